@@ -0,0 +1,391 @@
+// Command family-admin is the operator CLI for the admin API.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	var (
+		baseURL string
+		token   string
+		timeout time.Duration
+	)
+
+	root := &cobra.Command{
+		Use:          "family-admin",
+		Short:        "Operate a family-app-go deployment through its admin API",
+		SilenceUsage: true,
+	}
+	root.PersistentFlags().StringVar(&baseURL, "url", envOr("FAMILY_ADMIN_URL", "http://localhost:8080"), "server base URL (env FAMILY_ADMIN_URL)")
+	root.PersistentFlags().StringVar(&token, "token", os.Getenv("ADMIN_TOKEN"), "admin token (env ADMIN_TOKEN)")
+	root.PersistentFlags().DurationVar(&timeout, "timeout", 5*time.Minute, "request timeout")
+
+	api := func() *client {
+		return newClient(baseURL, token, timeout)
+	}
+
+	root.AddCommand(
+		newFamiliesCommand(api),
+		newSyncCommand(api),
+		newPurgeCommand(api),
+		newJobsCommand(api),
+		newBackupsCommand(api),
+		newFlagsCommand(api),
+		newUsageCommand(api),
+		newEncryptionCommand(api),
+	)
+	return root
+}
+
+func newFamiliesCommand(api func() *client) *cobra.Command {
+	families := &cobra.Command{Use: "families", Short: "Inspect families"}
+
+	var limit, offset int
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List families with member and data counts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(limit))
+			query.Set("offset", strconv.Itoa(offset))
+			return printResponse(cmd, api(), http.MethodGet, "/families", query, nil)
+		},
+	}
+	list.Flags().IntVar(&limit, "limit", 50, "page size (max 200)")
+	list.Flags().IntVar(&offset, "offset", 0, "page offset")
+
+	families.AddCommand(list)
+	return families
+}
+
+func newSyncCommand(api func() *client) *cobra.Command {
+	sync := &cobra.Command{Use: "sync", Short: "Inspect and unstick offline sync batches"}
+
+	var (
+		stuck    bool
+		status   string
+		familyID string
+		limit    int
+	)
+	batches := &cobra.Command{
+		Use:   "batches",
+		Short: "List sync batches",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(limit))
+			if stuck {
+				query.Set("stuck", "true")
+			}
+			if status != "" {
+				query.Set("status", status)
+			}
+			if familyID != "" {
+				query.Set("family_id", familyID)
+			}
+			return printResponse(cmd, api(), http.MethodGet, "/sync/batches", query, nil)
+		},
+	}
+	batches.Flags().BoolVar(&stuck, "stuck", false, "only batches stuck in processing")
+	batches.Flags().StringVar(&status, "status", "", "filter by status (processing, completed)")
+	batches.Flags().StringVar(&familyID, "family-id", "", "filter by family")
+	batches.Flags().IntVar(&limit, "limit", 50, "page size (max 200)")
+
+	show := &cobra.Command{
+		Use:   "show <batch-id>",
+		Short: "Show a sync batch and its user's pending operations",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printResponse(cmd, api(), http.MethodGet, "/sync/batches/"+url.PathEscape(args[0]), nil, nil)
+		},
+	}
+
+	var force bool
+	release := &cobra.Command{
+		Use:   "release <batch-id>",
+		Short: "Release a stuck batch so the client's retry runs again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			if force {
+				query.Set("force", "true")
+			}
+			return printResponse(cmd, api(), http.MethodPost, "/sync/batches/"+url.PathEscape(args[0])+"/release", query, nil)
+		},
+	}
+	release.Flags().BoolVar(&force, "force", false, "release even if the batch is not stuck yet")
+
+	sync.AddCommand(batches, show, release)
+	return sync
+}
+
+func newPurgeCommand(api func() *client) *cobra.Command {
+	var (
+		olderThanDays int
+		dryRun        bool
+	)
+	purge := &cobra.Command{
+		Use:   "purge",
+		Short: "Hard-delete soft-deleted rows",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			body := map[string]interface{}{"dry_run": dryRun}
+			if cmd.Flags().Changed("older-than-days") {
+				body["older_than_days"] = olderThanDays
+			}
+			return printResponse(cmd, api(), http.MethodPost, "/purge", nil, body)
+		},
+	}
+	purge.Flags().IntVar(&olderThanDays, "older-than-days", 0, "keep rows soft-deleted within the last N days")
+	purge.Flags().BoolVar(&dryRun, "dry-run", false, "only count the rows that would be deleted")
+	return purge
+}
+
+func newJobsCommand(api func() *client) *cobra.Command {
+	jobs := &cobra.Command{Use: "jobs", Short: "List and trigger background jobs"}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List jobs that can be triggered",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return printResponse(cmd, api(), http.MethodGet, "/jobs", nil, nil)
+		},
+	}
+	run := &cobra.Command{
+		Use:   "run <name>",
+		Short: "Run a job now and wait for it to finish, retries included",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printResponse(cmd, api(), http.MethodPost, "/jobs/"+url.PathEscape(args[0])+"/run", nil, nil)
+		},
+	}
+
+	var (
+		job    string
+		status string
+		limit  int
+	)
+	runs := &cobra.Command{
+		Use:   "runs",
+		Short: "Show recent job runs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(limit))
+			if job != "" {
+				query.Set("job", job)
+			}
+			if status != "" {
+				query.Set("status", status)
+			}
+			return printResponse(cmd, api(), http.MethodGet, "/jobs/runs", query, nil)
+		},
+	}
+	runs.Flags().StringVar(&job, "job", "", "filter by job name")
+	runs.Flags().StringVar(&status, "status", "", "filter by status (running, succeeded, failed)")
+	runs.Flags().IntVar(&limit, "limit", 50, "page size (max 200)")
+
+	jobs.AddCommand(list, run, runs)
+	return jobs
+}
+
+func newBackupsCommand(api func() *client) *cobra.Command {
+	backups := &cobra.Command{Use: "backups", Short: "List and restore database backups"}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List stored backups, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return printResponse(cmd, api(), http.MethodGet, "/backups", nil, nil)
+		},
+	}
+
+	var yes bool
+	restore := &cobra.Command{
+		Use:   "restore <key>",
+		Short: "Replace the database contents with a backup",
+		Long: "Replace the contents of every backed up table with the backup, in one transaction. " +
+			"Everything written since the backup is lost. The backup must match the current schema version. " +
+			"Run `family-admin jobs run backup` first to keep a copy of the current state.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("restore overwrites all data; pass --yes to confirm")
+			}
+			return printResponse(cmd, api(), http.MethodPost, "/backups/restore", nil, map[string]interface{}{"key": args[0]})
+		},
+	}
+	restore.Flags().BoolVar(&yes, "yes", false, "confirm that all current data is replaced")
+
+	backups.AddCommand(list, restore)
+	return backups
+}
+
+func newEncryptionCommand(api func() *client) *cobra.Command {
+	encryption := &cobra.Command{Use: "encryption", Short: "Manage encryption of sensitive columns"}
+
+	var dryRun bool
+	rotate := &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-encrypt encrypted columns with the primary key",
+		Long: "Re-encrypt every encrypted column with the first key of FIELD_ENCRYPTION_KEYS, " +
+			"including rows still in plaintext. Once it has run, older keys can be removed.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return printResponse(cmd, api(), http.MethodPost, "/encryption/rotate", nil, map[string]interface{}{"dry_run": dryRun})
+		},
+	}
+	rotate.Flags().BoolVar(&dryRun, "dry-run", false, "only count the values left to rotate")
+
+	encryption.AddCommand(rotate)
+	return encryption
+}
+
+func newFlagsCommand(api func() *client) *cobra.Command {
+	flags := &cobra.Command{Use: "flags", Short: "Inspect and flip feature flags"}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List feature flags with their global value and family overrides",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return printResponse(cmd, api(), http.MethodGet, "/feature-flags", nil, nil)
+		},
+	}
+
+	var familyID string
+	set := &cobra.Command{
+		Use:   "set <key> <on|off>",
+		Short: "Set a flag globally, or for one family with --family-id",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var enabled bool
+			switch args[1] {
+			case "on":
+				enabled = true
+			case "off":
+			default:
+				return fmt.Errorf("value must be on or off, got %q", args[1])
+			}
+			path := "/feature-flags/" + url.PathEscape(args[0])
+			if familyID != "" {
+				path += "/families/" + url.PathEscape(familyID)
+			}
+			return printResponse(cmd, api(), http.MethodPut, path, nil, map[string]interface{}{"enabled": enabled})
+		},
+	}
+	set.Flags().StringVar(&familyID, "family-id", "", "override the flag for this family only")
+
+	var clearFamilyID string
+	clearOverride := &cobra.Command{
+		Use:   "clear <key>",
+		Short: "Drop a family override so the family follows the global value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "/feature-flags/" + url.PathEscape(args[0]) + "/families/" + url.PathEscape(clearFamilyID)
+			return printResponse(cmd, api(), http.MethodDelete, path, nil, nil)
+		},
+	}
+	clearOverride.Flags().StringVar(&clearFamilyID, "family-id", "", "family whose override to drop")
+	_ = clearOverride.MarkFlagRequired("family-id")
+
+	flags.AddCommand(list, set, clearOverride)
+	return flags
+}
+
+func newUsageCommand(api func() *client) *cobra.Command {
+	usage := &cobra.Command{Use: "usage", Short: "Show API usage per module and family"}
+
+	var from, to string
+	modules := &cobra.Command{
+		Use:   "modules",
+		Short: "Requests and distinct families per module",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return printResponse(cmd, api(), http.MethodGet, "/usage/modules", usageRange(from, to), nil)
+		},
+	}
+	modules.Flags().StringVar(&from, "from", "", "first day, YYYY-MM-DD (default 30 days ago)")
+	modules.Flags().StringVar(&to, "to", "", "last day, YYYY-MM-DD (default today)")
+
+	var (
+		dailyFrom, dailyTo string
+		familyID, module   string
+		limit              int
+	)
+	daily := &cobra.Command{
+		Use:   "daily",
+		Short: "Daily request counts per family and endpoint",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query := usageRange(dailyFrom, dailyTo)
+			query.Set("limit", strconv.Itoa(limit))
+			if familyID != "" {
+				query.Set("family_id", familyID)
+			}
+			if module != "" {
+				query.Set("module", module)
+			}
+			return printResponse(cmd, api(), http.MethodGet, "/usage", query, nil)
+		},
+	}
+	daily.Flags().StringVar(&dailyFrom, "from", "", "first day, YYYY-MM-DD (default 30 days ago)")
+	daily.Flags().StringVar(&dailyTo, "to", "", "last day, YYYY-MM-DD (default today)")
+	daily.Flags().StringVar(&familyID, "family-id", "", "filter by family")
+	daily.Flags().StringVar(&module, "module", "", "filter by module, e.g. gym")
+	daily.Flags().IntVar(&limit, "limit", 100, "max rows (max 1000)")
+
+	usage.AddCommand(modules, daily)
+	return usage
+}
+
+func usageRange(from, to string) url.Values {
+	query := url.Values{}
+	if from != "" {
+		query.Set("from", from)
+	}
+	if to != "" {
+		query.Set("to", to)
+	}
+	return query
+}
+
+func printResponse(cmd *cobra.Command, c *client, method, path string, query url.Values, body interface{}) error {
+	data, err := c.do(cmd.Context(), method, path, query, body)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), out.String())
+	return err
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"family-app-go/internal/app"
 	"family-app-go/pkg/logger"
@@ -38,6 +37,18 @@ func main() {
 		close(serverErrCh)
 	}()
 
+	grpcSrv := application.GRPCServer()
+	grpcErrCh := make(chan error, 1)
+	if grpcSrv != nil {
+		log.Info("grpc: listening", "addr", grpcSrv.Addr())
+		go func() {
+			if err := grpcSrv.ListenAndServe(); err != nil {
+				grpcErrCh <- err
+			}
+			close(grpcErrCh)
+		}()
+	}
+
 	exitCode := 0
 	select {
 	case <-ctx.Done():
@@ -47,13 +58,19 @@ func main() {
 			log.Critical("http: server failed", "addr", srv.Addr, "err", err)
 			exitCode = 1
 		}
+	case err := <-grpcErrCh:
+		if err != nil {
+			log.Critical("grpc: server failed", "addr", grpcSrv.Addr(), "err", err)
+			exitCode = 1
+		}
 	}
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	log.Info("app: draining", "timeout", application.ShutdownTimeout().String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), application.ShutdownTimeout())
 	defer cancel()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Error("http: graceful shutdown failed", "err", err)
+	if err := application.Shutdown(shutdownCtx); err != nil {
+		log.Error("app: graceful shutdown failed", "err", err)
 		exitCode = 1
 	}
 
@@ -0,0 +1,107 @@
+//go:build e2e
+// +build e2e
+
+package e2e_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	analyticsrepo "family-app-go/internal/repository/postgres/analytics"
+)
+
+// benchExpenses is how many expenses a benchmark family holds, spread over
+// nine months and benchCategories categories.
+const (
+	benchExpenses   = 5000
+	benchCategories = 12
+)
+
+// BenchmarkListExpensesWithCategories pages through expenses filtered by two
+// categories, which exercises the expense_categories EXISTS filter and the
+// category lookup for the page.
+func BenchmarkListExpensesWithCategories(b *testing.B) {
+	env := newEnv(b)
+	owner := newUser(b)
+	env.createFamily(b, owner, "Bench Family")
+	categories := env.seedExpenses(b, owner)
+
+	url := env.url("/expenses?limit=50&category_ids=" + categories[0] + "," + categories[1])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, body := requestJSON(b, env.client, http.MethodGet, url, owner, nil)
+		if resp.StatusCode != http.StatusOK {
+			b.Fatalf("list expenses: expected 200, got %d: %s", resp.StatusCode, string(body))
+		}
+	}
+}
+
+// BenchmarkAnalyticsSummary totals a quarter of expenses for one category.
+// Most days are served from the daily rollups; a few are left dirty so the
+// fallback to expenses is measured too.
+func BenchmarkAnalyticsSummary(b *testing.B) {
+	env := newEnv(b)
+	owner := newUser(b)
+	env.createFamily(b, owner, "Bench Family")
+	categories := env.seedExpenses(b, owner)
+
+	ctx := context.Background()
+	rollups := analyticsrepo.NewPostgres(env.db)
+	if _, err := rollups.RefreshRollups(ctx, time.Now().UTC(), 500); err != nil {
+		b.Fatalf("refresh rollups: %v", err)
+	}
+	dirtyFrom := time.Date(2026, 3, 25, 0, 0, 0, 0, time.UTC)
+	if _, err := rollups.MarkRollupDaysDirty(ctx, dirtyFrom, dirtyFrom.AddDate(0, 0, 7)); err != nil {
+		b.Fatalf("mark rollup days dirty: %v", err)
+	}
+
+	url := env.url("/analytics/summary?from=2026-01-01&to=2026-03-31&category_ids=" + categories[0])
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, body := requestJSON(b, env.client, http.MethodGet, url, owner, nil)
+		if resp.StatusCode != http.StatusOK {
+			b.Fatalf("analytics summary: expected 200, got %d: %s", resp.StatusCode, string(body))
+		}
+	}
+}
+
+// seedExpenses creates benchCategories categories through the API and
+// inserts benchExpenses expenses for user's family directly, each with one
+// or two categories. It returns the category IDs.
+func (e *testEnv) seedExpenses(b *testing.B, user string) []string {
+	b.Helper()
+
+	categories := make([]string, 0, benchCategories)
+	for i := 0; i < benchCategories; i++ {
+		categories = append(categories, e.createCategory(b, user, fmt.Sprintf("Category %d", i+1)).ID)
+	}
+
+	if err := e.db.Exec(`
+		INSERT INTO expenses (id, family_id, user_id, date, amount, currency, base_currency, exchange_rate, amount_in_base, title)
+		SELECT gen_random_uuid(), m.family_id, m.user_id, DATE '2025-10-01' + (n % 270), 1 + n % 97, 'USD', 'USD', 1, 1 + n % 97, 'Expense ' || n
+		FROM family_members m, generate_series(1, ?) AS n
+		WHERE m.user_id = ?`, benchExpenses, user).Error; err != nil {
+		b.Fatalf("seed expenses: %v", err)
+	}
+	categoryArray := "{" + strings.Join(categories, ",") + "}"
+	if err := e.db.Exec(`
+		INSERT INTO expense_categories (expense_id, category_id)
+		SELECT e.id, (?::uuid[])[1 + (hashtext(e.id::text) & 2147483647) % ?]
+		FROM expenses e JOIN family_members m ON m.family_id = e.family_id
+		WHERE m.user_id = ?`, categoryArray, benchCategories, user).Error; err != nil {
+		b.Fatalf("seed expense categories: %v", err)
+	}
+	if err := e.db.Exec(`
+		INSERT INTO expense_categories (expense_id, category_id)
+		SELECT e.id, (?::uuid[])[1 + (hashtext(e.title) & 2147483647) % ?]
+		FROM expenses e JOIN family_members m ON m.family_id = e.family_id
+		WHERE m.user_id = ? AND (hashtext(e.id::text) & 2147483647) % 3 = 0
+		ON CONFLICT DO NOTHING`, categoryArray, benchCategories, user).Error; err != nil {
+		b.Fatalf("seed second categories: %v", err)
+	}
+	return categories
+}
@@ -5,187 +5,15 @@ package e2e_test
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"io"
-	"log/slog"
 	"net/http"
-	"net/http/httptest"
-	"os"
 	"strings"
 	"testing"
 	"time"
-
-	"family-app-go/internal/config"
-	"family-app-go/internal/db"
-	analyticsdomain "family-app-go/internal/domain/analytics"
-	expensesdomain "family-app-go/internal/domain/expenses"
-	familydomain "family-app-go/internal/domain/family"
-	ratesdomain "family-app-go/internal/domain/rates"
-	todosdomain "family-app-go/internal/domain/todos"
-	userdomain "family-app-go/internal/domain/user"
-	inmemoryrepo "family-app-go/internal/repository/inmemory"
-	analyticsrepo "family-app-go/internal/repository/postgres/analytics"
-	expensesrepo "family-app-go/internal/repository/postgres/expenses"
-	familyrepo "family-app-go/internal/repository/postgres/family"
-	todosrepo "family-app-go/internal/repository/postgres/todos"
-	userrepo "family-app-go/internal/repository/postgres/user"
-	"family-app-go/internal/transport/httpserver"
-	"family-app-go/internal/transport/httpserver/handler"
-	"family-app-go/pkg/logger"
-	"gorm.io/gorm"
 )
 
-type testEnv struct {
-	server     *httptest.Server
-	authServer *httptest.Server
-	db         *gorm.DB
-}
-
-type e2eRatesProvider struct{}
-
-func (e2eRatesProvider) ListCurrencies(_ context.Context) ([]ratesdomain.Currency, error) {
-	return []ratesdomain.Currency{
-		{Code: "BYN", Name: "Belarusian Ruble", Icon: "🇧🇾", Symbol: "ƃ"},
-		{Code: "USD", Name: "US Dollar", Icon: "🇺🇸"},
-		{Code: "EUR", Name: "Euro", Icon: "🇪🇺"},
-		{Code: "RUB", Name: "Russian Ruble", Icon: "🇷🇺"},
-	}, nil
-}
-
-func (e2eRatesProvider) GetBYNRate(_ context.Context, currency string, onDate time.Time) (ratesdomain.BYNRate, error) {
-	switch strings.ToUpper(strings.TrimSpace(currency)) {
-	case "USD":
-		return ratesdomain.BYNRate{Code: "USD", Date: onDate, Scale: 1, Rate: 3.2}, nil
-	case "EUR":
-		return ratesdomain.BYNRate{Code: "EUR", Date: onDate, Scale: 1, Rate: 3.5}, nil
-	case "RUB":
-		return ratesdomain.BYNRate{Code: "RUB", Date: onDate, Scale: 100, Rate: 3.6}, nil
-	default:
-		return ratesdomain.BYNRate{}, ratesdomain.ErrRateNotAvailable
-	}
-}
-
-func setupE2E(t *testing.T) *testEnv {
-	t.Helper()
-
-	dsn := os.Getenv("E2E_DB_DSN")
-	if dsn == "" {
-		t.Skip("E2E_DB_DSN not set; skipping e2e tests")
-	}
-
-	authServer := newAuthServer(t)
-
-	cfg := config.Config{
-		DB: config.DBConfig{DSN: dsn},
-		TopCategories: config.TopCategoriesConfig{
-			Enabled:       true,
-			LookbackDays:  30,
-			DBReadLimit:   1000,
-			MinRecords:    1,
-			ResponseCount: 5,
-			CacheTTL:      time.Minute,
-		},
-		Supabase: config.SupabaseConfig{
-			URL:            authServer.URL,
-			PublishableKey: "test-key",
-			AuthTimeout:    2 * time.Second,
-		},
-	}
-
-	log := logger.New(io.Discard, slog.LevelError, "text")
-
-	dbConn, err := db.NewPostgres(log, cfg.DB)
-	if err != nil {
-		t.Fatalf("db connect: %v", err)
-	}
-
-	if err := db.Migrate(dbConn); err != nil {
-		t.Fatalf("migrate: %v", err)
-	}
-
-	if err := cleanDB(dbConn); err != nil {
-		t.Fatalf("clean db: %v", err)
-	}
-
-	familyRepo := familyrepo.NewPostgres(dbConn)
-	familyService := familydomain.NewServiceWithCache(familyRepo, inmemoryrepo.NewInMemoryFamilyCache())
-	expensesRepo := expensesrepo.NewPostgres(dbConn)
-	ratesService := ratesdomain.NewService(e2eRatesProvider{}, ratesdomain.Config{
-		RateCacheTTL:       time.Minute,
-		CurrenciesCacheTTL: time.Minute,
-		FallbackDays:       0,
-	})
-	expensesService := expensesdomain.NewServiceWithDependencies(expensesRepo, inmemoryrepo.NewInMemoryCategoriesCache(), ratesService)
-	analyticsRepo := analyticsrepo.NewPostgres(dbConn)
-	analyticsService := analyticsdomain.NewServiceWithTopCategoriesConfig(analyticsRepo, analyticsdomain.TopCategoriesConfig{
-		Enabled:       cfg.TopCategories.Enabled,
-		LookbackDays:  cfg.TopCategories.LookbackDays,
-		DBReadLimit:   cfg.TopCategories.DBReadLimit,
-		MinRecords:    cfg.TopCategories.MinRecords,
-		ResponseCount: cfg.TopCategories.ResponseCount,
-		CacheTTL:      cfg.TopCategories.CacheTTL,
-	})
-	userRepo := userrepo.NewPostgres(dbConn)
-	userService := userdomain.NewService(userRepo)
-	todosRepo := todosrepo.NewPostgres(dbConn)
-	todosService := todosdomain.NewService(todosRepo)
-	handlers := handler.New(analyticsService, familyService, expensesService, ratesService, todosService, nil, nil, log)
-
-	router := httpserver.NewRouter(cfg, handlers, userService, log)
-	server := httptest.NewServer(router)
-
-	return &testEnv{server: server, authServer: authServer, db: dbConn}
-}
-
-func (e *testEnv) Close() {
-	e.server.Close()
-	e.authServer.Close()
-	sqlDB, err := e.db.DB()
-	if err == nil {
-		_ = sqlDB.Close()
-	}
-}
-
-func newAuthServer(t *testing.T) *httptest.Server {
-	t.Helper()
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("apikey") != "test-key" {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
-		}
-		auth := r.Header.Get("Authorization")
-		if !strings.HasPrefix(auth, "Bearer ") {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
-		}
-		token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
-		if token == "" {
-			w.WriteHeader(http.StatusUnauthorized)
-			return
-		}
-
-		payload := map[string]interface{}{
-			"id":    token,
-			"email": token + "@example.com",
-			"user_metadata": map[string]interface{}{
-				"name":       "User " + token,
-				"avatar_url": "https://example.com/avatar.png",
-			},
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(payload)
-	}))
-}
-
-func cleanDB(dbConn *gorm.DB) error {
-	return dbConn.WithContext(context.Background()).Exec(
-		"TRUNCATE TABLE expense_categories, expenses, categories, family_members, families, user_profiles RESTART IDENTITY CASCADE",
-	).Error
-}
-
-func requestJSON(t *testing.T, client *http.Client, method, url, token string, payload interface{}) (*http.Response, []byte) {
+func requestJSON(t testing.TB, client *http.Client, method, url, token string, payload interface{}) (*http.Response, []byte) {
 	t.Helper()
 
 	var body io.Reader
@@ -263,7 +91,6 @@ type authMeResponse struct {
 type familyResponse struct {
 	ID              string    `json:"id"`
 	Name            string    `json:"name"`
-	Code            string    `json:"code"`
 	OwnerID         string    `json:"owner_id"`
 	DefaultCurrency string    `json:"default_currency"`
 	CreatedAt       time.Time `json:"created_at"`
@@ -338,12 +165,11 @@ type exchangeRateResponse struct {
 }
 
 func TestE2EHealthAndAuth(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 
-	resp, body := requestRaw(t, client, http.MethodGet, env.server.URL+"/health")
+	resp, body := requestRaw(t, client, http.MethodGet, env.url("/health"))
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -351,7 +177,7 @@ func TestE2EHealthAndAuth(t *testing.T) {
 		t.Fatalf("expected ok, got %q", string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/auth/me", "", nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/auth/me"), "", nil)
 	if resp.StatusCode != http.StatusUnauthorized {
 		t.Fatalf("expected 401, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -364,7 +190,7 @@ func TestE2EHealthAndAuth(t *testing.T) {
 	}
 
 	userID := "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/auth/me", userID, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/auth/me"), userID, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -381,15 +207,14 @@ func TestE2EHealthAndAuth(t *testing.T) {
 }
 
 func TestE2EFamilyFlow(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 
 	user1 := "11111111-1111-1111-1111-111111111111"
 	user2 := "22222222-2222-2222-2222-222222222222"
 
-	resp, body := requestJSON(t, client, http.MethodPost, env.server.URL+"/families", user1, map[string]string{
+	resp, body := requestJSON(t, client, http.MethodPost, env.url("/families"), user1, map[string]string{
 		"name": "Ivanovs",
 	})
 	if resp.StatusCode != http.StatusCreated {
@@ -399,25 +224,38 @@ func TestE2EFamilyFlow(t *testing.T) {
 	if err := json.Unmarshal(body, &family); err != nil {
 		t.Fatalf("decode family: %v", err)
 	}
-	if family.ID == "" || family.Code == "" {
-		t.Fatalf("expected family id and code")
+	if family.ID == "" {
+		t.Fatalf("expected family id")
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPatch, env.server.URL+"/families/me", user1, map[string]string{
+	resp, body = requestJSON(t, client, http.MethodPatch, env.url("/families/me"), user1, map[string]string{
 		"name": "Ivanovs 2",
 	})
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/families/join", user2, map[string]string{
-		"code": family.Code,
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/families/me/invites"), user1, map[string]interface{}{
+		"max_uses": 1,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+	var invite struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &invite); err != nil {
+		t.Fatalf("decode invite: %v", err)
+	}
+
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/families/join"), user2, map[string]string{
+		"token": invite.Token,
 	})
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/families/me/members", user1, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/families/me/members"), user1, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -429,43 +267,42 @@ func TestE2EFamilyFlow(t *testing.T) {
 		t.Fatalf("expected 2 members, got %d", len(members))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/families/leave", user1, nil)
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/families/leave"), user1, nil)
 	if resp.StatusCode != http.StatusNoContent {
 		t.Fatalf("expected 204, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/families/leave", user2, nil)
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/families/leave"), user2, nil)
 	if resp.StatusCode != http.StatusNoContent {
 		t.Fatalf("expected 204, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/families/leave", user1, nil)
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/families/leave"), user1, nil)
 	if resp.StatusCode != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/families/me", user1, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/families/me"), user1, nil)
 	if resp.StatusCode != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d: %s", resp.StatusCode, string(body))
 	}
 }
 
 func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 
 	user1 := "11111111-1111-1111-1111-111111111111"
 
-	resp, body := requestJSON(t, client, http.MethodPost, env.server.URL+"/families", user1, map[string]string{
+	resp, body := requestJSON(t, client, http.MethodPost, env.url("/families"), user1, map[string]string{
 		"name": "Ivanovs",
 	})
 	if resp.StatusCode != http.StatusCreated {
 		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/expenses", user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/expenses"), user1, map[string]interface{}{
 		"date":         "2026-02-05",
 		"amount":       12.5,
 		"currency":     "BYN",
@@ -476,7 +313,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected 404, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/categories", user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/categories"), user1, map[string]interface{}{
 		"name":  "Food",
 		"color": "#AABBCC",
 		"emoji": "🙂",
@@ -495,7 +332,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected emoji, got %+v", category.Emoji)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPatch, env.server.URL+"/categories/"+category.ID, user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPatch, env.url("/categories/")+category.ID, user1, map[string]interface{}{
 		"name":  "Food Updated",
 		"color": "#00FF11",
 		"emoji": "❤️",
@@ -516,7 +353,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected emoji, got %+v", category.Emoji)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPatch, env.server.URL+"/categories/"+category.ID, user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPatch, env.url("/categories/")+category.ID, user1, map[string]interface{}{
 		"name":  "Food Updated",
 		"color": nil,
 		"emoji": nil,
@@ -534,7 +371,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected nil emoji, got %+v", category.Emoji)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/categories", user1, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/categories"), user1, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -549,7 +386,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected cleared color/emoji, got color=%+v emoji=%+v", categories[0].Color, categories[0].Emoji)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/categories", user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/categories"), user1, map[string]interface{}{
 		"name":  "Invalid Color",
 		"color": "#12GG34",
 	})
@@ -557,7 +394,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected 400 for invalid color, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/categories", user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/categories"), user1, map[string]interface{}{
 		"name":  "Invalid Emoji",
 		"emoji": "ab",
 	})
@@ -565,7 +402,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected 400 for invalid emoji, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/expenses", user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/expenses"), user1, map[string]interface{}{
 		"date":         "2026-02-05",
 		"amount":       12.5,
 		"currency":     "BYN",
@@ -580,7 +417,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("decode expense: %v", err)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/expenses?category_id="+category.ID, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/expenses?category_id=")+category.ID, user1, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -592,7 +429,7 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected total 1, got %d", list.Total)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPut, env.server.URL+"/expenses/"+expense.ID, user1, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPut, env.url("/expenses/")+expense.ID, user1, map[string]interface{}{
 		"date":         "2026-02-05",
 		"amount":       10.0,
 		"currency":     "USD",
@@ -603,35 +440,34 @@ func TestE2EExpensesAndCategoriesFlow(t *testing.T) {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/expenses/"+expense.ID, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/expenses/")+expense.ID, user1, nil)
 	if resp.StatusCode != http.StatusNoContent {
 		t.Fatalf("expected 204, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/expenses/"+expense.ID, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/expenses/")+expense.ID, user1, nil)
 	if resp.StatusCode != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/categories/"+category.ID, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/categories/")+category.ID, user1, nil)
 	if resp.StatusCode != http.StatusNoContent {
 		t.Fatalf("expected 204, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/categories/"+category.ID, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/categories/")+category.ID, user1, nil)
 	if resp.StatusCode != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d: %s", resp.StatusCode, string(body))
 	}
 }
 
 func TestE2ERatesEndpoints(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 	user := "77777777-7777-7777-7777-777777777777"
 
-	resp, body := requestJSON(t, client, http.MethodGet, env.server.URL+"/currencies", user, nil)
+	resp, body := requestJSON(t, client, http.MethodGet, env.url("/currencies"), user, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -666,7 +502,7 @@ func TestE2ERatesEndpoints(t *testing.T) {
 		t.Fatalf("expected BYN and USD in currencies, got %+v", currencies)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/exchange-rates?from=USD&to=BYN&date=2026-02-10", user, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/exchange-rates?from=USD&to=BYN&date=2026-02-10"), user, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -681,7 +517,7 @@ func TestE2ERatesEndpoints(t *testing.T) {
 		t.Fatalf("expected rate 3.2, got %v", rate.Rate)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/exchange-rates?from=GBP&to=BYN&date=2026-02-10", user, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/exchange-rates?from=GBP&to=BYN&date=2026-02-10"), user, nil)
 	if resp.StatusCode != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -695,13 +531,12 @@ func TestE2ERatesEndpoints(t *testing.T) {
 }
 
 func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 	user := "88888888-8888-8888-8888-888888888888"
 
-	resp, body := requestJSON(t, client, http.MethodPost, env.server.URL+"/families", user, map[string]string{
+	resp, body := requestJSON(t, client, http.MethodPost, env.url("/families"), user, map[string]string{
 		"name": "Conversion Family",
 	})
 	if resp.StatusCode != http.StatusCreated {
@@ -715,7 +550,7 @@ func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
 		t.Fatalf("expected default currency USD, got %q", family.DefaultCurrency)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/expenses", user, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/expenses"), user, map[string]interface{}{
 		"date":     "2026-02-10",
 		"amount":   32.0,
 		"currency": "BYN",
@@ -741,7 +576,7 @@ func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
 		t.Fatalf("expected rate source nbrb, got %+v", bynExpense.RateSource)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/expenses", user, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/expenses"), user, map[string]interface{}{
 		"date":     "2026-02-10",
 		"amount":   5.0,
 		"currency": "USD",
@@ -751,7 +586,7 @@ func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
 		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/expenses?currency=USD", user, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/expenses?currency=USD"), user, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -763,7 +598,7 @@ func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
 		t.Fatalf("expected single USD expense, got %+v", expensesList)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/analytics/summary?from=2026-02-10&to=2026-02-10", user, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/analytics/summary?from=2026-02-10&to=2026-02-10"), user, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -778,7 +613,7 @@ func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
 		t.Fatalf("expected total 15, got %v", summary.TotalAmount)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/analytics/summary?from=2026-02-10&to=2026-02-10&currency=BYN", user, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/analytics/summary?from=2026-02-10&to=2026-02-10&currency=BYN"), user, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -792,7 +627,7 @@ func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
 		t.Fatalf("expected total 32 for BYN filter, got %v", summary.TotalAmount)
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/expenses", user, map[string]interface{}{
+	resp, body = requestJSON(t, client, http.MethodPost, env.url("/expenses"), user, map[string]interface{}{
 		"date":     "2026-02-10",
 		"amount":   10.0,
 		"currency": "GBP",
@@ -804,20 +639,19 @@ func TestE2EExpenseConversionAndAnalyticsModes(t *testing.T) {
 }
 
 func TestE2EDefaultCurrencyLocked(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 	user := "99999999-9999-9999-9999-999999999999"
 
-	resp, body := requestJSON(t, client, http.MethodPost, env.server.URL+"/families", user, map[string]string{
+	resp, body := requestJSON(t, client, http.MethodPost, env.url("/families"), user, map[string]string{
 		"name": "Locked Currency Family",
 	})
 	if resp.StatusCode != http.StatusCreated {
 		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodPatch, env.server.URL+"/families/me", user, map[string]string{
+	resp, body = requestJSON(t, client, http.MethodPatch, env.url("/families/me"), user, map[string]string{
 		"default_currency": "BYN",
 	})
 	if resp.StatusCode != http.StatusConflict {
@@ -833,33 +667,16 @@ func TestE2EDefaultCurrencyLocked(t *testing.T) {
 }
 
 func TestE2EFamilyMembersManage(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 
-	user1 := "33333333-3333-3333-3333-333333333333"
-	user2 := "44444444-4444-4444-4444-444444444444"
+	user1 := newUser(t)
+	user2 := newUser(t)
+	env.createFamily(t, user1, "Ivanovs")
+	env.addMember(t, user1, user2)
 
-	resp, body := requestJSON(t, client, http.MethodPost, env.server.URL+"/families", user1, map[string]string{
-		"name": "Ivanovs",
-	})
-	if resp.StatusCode != http.StatusCreated {
-		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
-	}
-	var family familyResponse
-	if err := json.Unmarshal(body, &family); err != nil {
-		t.Fatalf("decode family: %v", err)
-	}
-
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/families/join", user2, map[string]string{
-		"code": family.Code,
-	})
-	if resp.StatusCode != http.StatusOK {
-		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
-	}
-
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/families/me/members", user1, nil)
+	resp, body := requestJSON(t, client, http.MethodGet, env.url("/families/me/members"), user1, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -879,27 +696,27 @@ func TestE2EFamilyMembersManage(t *testing.T) {
 		}
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/families/me/members/"+user1, user2, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/families/me/members/")+user1, user2, nil)
 	if resp.StatusCode != http.StatusForbidden {
 		t.Fatalf("expected 403, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/families/me/members/"+user1, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/families/me/members/")+user1, user1, nil)
 	if resp.StatusCode != http.StatusConflict {
 		t.Fatalf("expected 409, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/families/me/members/"+user2, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/families/me/members/")+user2, user1, nil)
 	if resp.StatusCode != http.StatusNoContent {
 		t.Fatalf("expected 204, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodDelete, env.server.URL+"/families/me/members/"+user2, user1, nil)
+	resp, body = requestJSON(t, client, http.MethodDelete, env.url("/families/me/members/")+user2, user1, nil)
 	if resp.StatusCode != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d: %s", resp.StatusCode, string(body))
 	}
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/families/me/members", user1, nil)
+	resp, body = requestJSON(t, client, http.MethodGet, env.url("/families/me/members"), user1, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -912,58 +729,20 @@ func TestE2EFamilyMembersManage(t *testing.T) {
 }
 
 func TestE2ETopCategoriesByFamily(t *testing.T) {
-	env := setupE2E(t)
-	defer env.Close()
-
-	client := &http.Client{Timeout: 5 * time.Second}
-
-	user1 := "55555555-5555-5555-5555-555555555555"
-	user2 := "66666666-6666-6666-6666-666666666666"
-
-	resp, body := requestJSON(t, client, http.MethodPost, env.server.URL+"/families", user1, map[string]string{
-		"name": "Analytics Family",
-	})
-	if resp.StatusCode != http.StatusCreated {
-		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
-	}
+	t.Parallel()
+	env := newEnv(t)
+	client := env.client
 
-	var family familyResponse
-	if err := json.Unmarshal(body, &family); err != nil {
-		t.Fatalf("decode family: %v", err)
-	}
-
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/families/join", user2, map[string]string{
-		"code": family.Code,
-	})
-	if resp.StatusCode != http.StatusOK {
-		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
-	}
-
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/categories", user1, map[string]interface{}{
-		"name": "Food",
-	})
-	if resp.StatusCode != http.StatusCreated {
-		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
-	}
-	var food categoryResponse
-	if err := json.Unmarshal(body, &food); err != nil {
-		t.Fatalf("decode food category: %v", err)
-	}
-
-	resp, body = requestJSON(t, client, http.MethodPost, env.server.URL+"/categories", user1, map[string]interface{}{
-		"name": "Transport",
-	})
-	if resp.StatusCode != http.StatusCreated {
-		t.Fatalf("expected 201, got %d: %s", resp.StatusCode, string(body))
-	}
-	var transport categoryResponse
-	if err := json.Unmarshal(body, &transport); err != nil {
-		t.Fatalf("decode transport category: %v", err)
-	}
+	user1 := newUser(t)
+	user2 := newUser(t)
+	env.createFamily(t, user1, "Analytics Family")
+	env.addMember(t, user1, user2)
+	food := env.createCategory(t, user1, "Food")
+	transport := env.createCategory(t, user1, "Transport")
 
 	createExpense := func(userID, title string, amount float64, categoryID string) {
 		t.Helper()
-		resp, body := requestJSON(t, client, http.MethodPost, env.server.URL+"/expenses", userID, map[string]interface{}{
+		resp, body := requestJSON(t, client, http.MethodPost, env.url("/expenses"), userID, map[string]interface{}{
 			"date":         "2026-02-10",
 			"amount":       amount,
 			"currency":     "USD",
@@ -982,7 +761,7 @@ func TestE2ETopCategoriesByFamily(t *testing.T) {
 	createExpense(user2, "Food shared #1", 100, food.ID)
 	createExpense(user2, "Food shared #2", 200, food.ID)
 
-	resp, body = requestJSON(t, client, http.MethodGet, env.server.URL+"/top_categories", user1, nil)
+	resp, body := requestJSON(t, client, http.MethodGet, env.url("/top_categories"), user1, nil)
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
 	}
@@ -0,0 +1,354 @@
+//go:build e2e
+// +build e2e
+
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"family-app-go/internal/config"
+	"family-app-go/internal/db"
+	activitydomain "family-app-go/internal/domain/activity"
+	analyticsdomain "family-app-go/internal/domain/analytics"
+	auditdomain "family-app-go/internal/domain/audit"
+	commentsdomain "family-app-go/internal/domain/comments"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	ratesdomain "family-app-go/internal/domain/rates"
+	todosdomain "family-app-go/internal/domain/todos"
+	userdomain "family-app-go/internal/domain/user"
+	inmemoryrepo "family-app-go/internal/repository/inmemory"
+	activityrepo "family-app-go/internal/repository/postgres/activity"
+	analyticsrepo "family-app-go/internal/repository/postgres/analytics"
+	auditrepo "family-app-go/internal/repository/postgres/audit"
+	commentsrepo "family-app-go/internal/repository/postgres/comments"
+	expensesrepo "family-app-go/internal/repository/postgres/expenses"
+	familyrepo "family-app-go/internal/repository/postgres/family"
+	featureflagsrepo "family-app-go/internal/repository/postgres/featureflags"
+	todosrepo "family-app-go/internal/repository/postgres/todos"
+	userrepo "family-app-go/internal/repository/postgres/user"
+	"family-app-go/internal/transport/httpserver"
+	"family-app-go/internal/transport/httpserver/handler"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/logger"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// postgresImage is the server the suite runs against when E2E_DB_DSN is not
+// set; keep it on the major version production uses.
+const postgresImage = "postgres:16-alpine"
+
+var (
+	// baseDSN reaches the suite database. Each test works in its own schema
+	// of it, so tests may run in parallel.
+	baseDSN   string
+	schemaSeq atomic.Int64
+)
+
+// TestMain starts a throwaway Postgres container for the whole suite. Set
+// E2E_DB_DSN to run against an existing database instead, e.g. a CI service
+// container; tests only ever touch the schemas they create.
+func TestMain(m *testing.M) {
+	os.Exit(runSuite(m))
+}
+
+func runSuite(m *testing.M) int {
+	if dsn := os.Getenv("E2E_DB_DSN"); dsn != "" {
+		baseDSN = dsn
+		return m.Run()
+	}
+
+	ctx := context.Background()
+	container, err := tcpostgres.Run(ctx, postgresImage,
+		tcpostgres.WithDatabase("family_app"),
+		tcpostgres.WithUsername("family_app"),
+		tcpostgres.WithPassword("family_app"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: start postgres container: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := testcontainers.TerminateContainer(container); err != nil {
+			fmt.Fprintf(os.Stderr, "e2e: terminate postgres container: %v\n", err)
+		}
+	}()
+
+	baseDSN, err = container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: postgres connection string: %v\n", err)
+		return 1
+	}
+	return m.Run()
+}
+
+type testEnv struct {
+	server *httptest.Server
+	db     *gorm.DB
+	client *http.Client
+}
+
+// url returns the address of an API path such as "/expenses".
+func (e *testEnv) url(path string) string {
+	return e.server.URL + "/api" + path
+}
+
+// newEnv migrates a fresh schema and serves the API on top of it. Everything
+// is torn down when the test ends.
+func newEnv(t testing.TB) *testEnv {
+	t.Helper()
+
+	schema := createSchema(t)
+	authServer := newAuthServer(t)
+
+	cfg := config.Config{
+		DB: config.DBConfig{DSN: withSearchPath(baseDSN, schema)},
+		TopCategories: config.TopCategoriesConfig{
+			Enabled:       true,
+			LookbackDays:  30,
+			DBReadLimit:   1000,
+			MinRecords:    1,
+			ResponseCount: 5,
+			CacheTTL:      time.Minute,
+		},
+		Supabase: config.SupabaseConfig{
+			URL:            authServer.URL,
+			PublishableKey: "test-key",
+			AuthTimeout:    2 * time.Second,
+		},
+	}
+
+	log := logger.New(io.Discard, slog.LevelError, "text")
+
+	dbConn, err := db.NewPostgres(log, cfg.DB)
+	if err != nil {
+		t.Fatalf("db connect: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := dbConn.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+	if err := db.Migrate(dbConn); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	familyService := familydomain.NewServiceWithCache(familyrepo.NewPostgres(dbConn), inmemoryrepo.NewInMemoryFamilyCache())
+	ratesService := ratesdomain.NewService(e2eRatesProvider{}, ratesdomain.Config{
+		RateCacheTTL:       time.Minute,
+		CurrenciesCacheTTL: time.Minute,
+		FallbackDays:       0,
+	})
+	expensesService := expensesdomain.NewServiceWithDependencies(expensesrepo.NewPostgres(dbConn), inmemoryrepo.NewInMemoryCategoriesCache(), ratesService)
+	analyticsService := analyticsdomain.NewServiceWithTopCategoriesConfig(analyticsrepo.NewPostgres(dbConn), analyticsdomain.TopCategoriesConfig{
+		Enabled:       cfg.TopCategories.Enabled,
+		LookbackDays:  cfg.TopCategories.LookbackDays,
+		DBReadLimit:   cfg.TopCategories.DBReadLimit,
+		MinRecords:    cfg.TopCategories.MinRecords,
+		ResponseCount: cfg.TopCategories.ResponseCount,
+		CacheTTL:      cfg.TopCategories.CacheTTL,
+	})
+	userService := userdomain.NewService(userrepo.NewPostgres(dbConn))
+	todosService := todosdomain.NewService(todosrepo.NewPostgres(dbConn))
+	activityService := activitydomain.NewService(activityrepo.NewPostgres(dbConn))
+	flagsService := featureflagsdomain.NewService(featureflagsrepo.NewPostgres(dbConn))
+	auditService := auditdomain.NewService(auditrepo.NewPostgres(dbConn))
+	commentsService := commentsdomain.NewService(commentsrepo.NewPostgres(dbConn))
+	handlers := handler.New(activityService, analyticsService, nil, nil, nil, familyService, userService, expensesService, ratesService, todosService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, commentsService, nil, nil, nil, nil, nil, nil, flagsService, auditService, nil, commonhandler.NewListLimits(cfg.ListLimits), log)
+
+	router := httpserver.NewRouter(cfg, handlers, nil, nil, nil, userService, familyService, flagsService, nil, nil, auditService, log)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return &testEnv{server: server, db: dbConn, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// createSchema creates a schema only the calling test uses and drops it when
+// the test ends.
+func createSchema(t testing.TB) string {
+	t.Helper()
+
+	admin, err := gorm.Open(postgres.Open(baseDSN), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("connect to suite database: %v", err)
+	}
+	sqlDB, err := admin.DB()
+	if err != nil {
+		t.Fatalf("suite database handle: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	schema := fmt.Sprintf("e2e_%d_%d", os.Getpid(), schemaSeq.Add(1))
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		if err := admin.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+			t.Logf("drop schema %s: %v", schema, err)
+		}
+	})
+	return schema
+}
+
+// withSearchPath points every connection of dsn at schema. Both URL and
+// key=value DSNs are accepted.
+func withSearchPath(dsn, schema string) string {
+	if strings.Contains(dsn, "://") {
+		parsed, err := url.Parse(dsn)
+		if err == nil {
+			query := parsed.Query()
+			query.Set("search_path", schema)
+			parsed.RawQuery = query.Encode()
+			return parsed.String()
+		}
+	}
+	return dsn + " search_path=" + schema
+}
+
+// newAuthServer fakes the Supabase user endpoint: the bearer token is the
+// user ID.
+func newAuthServer(t testing.TB) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("apikey") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+		if token == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		payload := map[string]interface{}{
+			"id":    token,
+			"email": token + "@example.com",
+			"user_metadata": map[string]interface{}{
+				"name":       "User " + token,
+				"avatar_url": "https://example.com/avatar.png",
+			},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(payload)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type e2eRatesProvider struct{}
+
+func (e2eRatesProvider) ListCurrencies(_ context.Context) ([]ratesdomain.Currency, error) {
+	return []ratesdomain.Currency{
+		{Code: "BYN", Name: "Belarusian Ruble", Icon: "🇧🇾", Symbol: "ƃ"},
+		{Code: "USD", Name: "US Dollar", Icon: "🇺🇸"},
+		{Code: "EUR", Name: "Euro", Icon: "🇪🇺"},
+		{Code: "RUB", Name: "Russian Ruble", Icon: "🇷🇺"},
+	}, nil
+}
+
+func (e2eRatesProvider) GetBYNRate(_ context.Context, currency string, onDate time.Time) (ratesdomain.BYNRate, error) {
+	switch strings.ToUpper(strings.TrimSpace(currency)) {
+	case "USD":
+		return ratesdomain.BYNRate{Code: "USD", Date: onDate, Scale: 1, Rate: 3.2}, nil
+	case "EUR":
+		return ratesdomain.BYNRate{Code: "EUR", Date: onDate, Scale: 1, Rate: 3.5}, nil
+	case "RUB":
+		return ratesdomain.BYNRate{Code: "RUB", Date: onDate, Scale: 100, Rate: 3.6}, nil
+	default:
+		return ratesdomain.BYNRate{}, ratesdomain.ErrRateNotAvailable
+	}
+}
+
+// newUser returns the token of a user no other test knows about. The fake
+// auth server accepts it as that user's ID.
+func newUser(t testing.TB) string {
+	t.Helper()
+	userID, err := id.New()
+	if err != nil {
+		t.Fatalf("new user id: %v", err)
+	}
+	return userID
+}
+
+// createFamily creates a family owned by owner.
+func (e *testEnv) createFamily(t testing.TB, owner, name string) familyResponse {
+	t.Helper()
+
+	resp, body := requestJSON(t, e.client, http.MethodPost, e.url("/families"), owner, map[string]string{
+		"name": name,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create family: expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+	var family familyResponse
+	if err := json.Unmarshal(body, &family); err != nil {
+		t.Fatalf("decode family: %v", err)
+	}
+	return family
+}
+
+// addMember brings user into owner's family through a single-use invite.
+func (e *testEnv) addMember(t testing.TB, owner, user string) {
+	t.Helper()
+
+	resp, body := requestJSON(t, e.client, http.MethodPost, e.url("/families/me/invites"), owner, map[string]interface{}{
+		"max_uses": 1,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create invite: expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+	var invite struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &invite); err != nil {
+		t.Fatalf("decode invite: %v", err)
+	}
+
+	resp, body = requestJSON(t, e.client, http.MethodPost, e.url("/families/join"), user, map[string]string{
+		"token": invite.Token,
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("join family: expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+}
+
+// createCategory adds a category to user's family.
+func (e *testEnv) createCategory(t testing.TB, user, name string) categoryResponse {
+	t.Helper()
+
+	resp, body := requestJSON(t, e.client, http.MethodPost, e.url("/categories"), user, map[string]interface{}{
+		"name": name,
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create category: expected 201, got %d: %s", resp.StatusCode, string(body))
+	}
+	var category categoryResponse
+	if err := json.Unmarshal(body, &category); err != nil {
+		t.Fatalf("decode category: %v", err)
+	}
+	return category
+}
@@ -1,43 +1,108 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"family-app-go/internal/config"
 	"family-app-go/internal/db"
 	"family-app-go/internal/devseed"
+	activitydomain "family-app-go/internal/domain/activity"
+	admindomain "family-app-go/internal/domain/admin"
 	analyticsdomain "family-app-go/internal/domain/analytics"
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	auditdomain "family-app-go/internal/domain/audit"
+	backupdomain "family-app-go/internal/domain/backup"
+	batchdomain "family-app-go/internal/domain/batch"
+	budgetsdomain "family-app-go/internal/domain/budgets"
+	calendardomain "family-app-go/internal/domain/calendar"
+	commentsdomain "family-app-go/internal/domain/comments"
+	dashboarddomain "family-app-go/internal/domain/dashboard"
+	erasuredomain "family-app-go/internal/domain/erasure"
 	expensesdomain "family-app-go/internal/domain/expenses"
+	exportsdomain "family-app-go/internal/domain/exports"
 	familydomain "family-app-go/internal/domain/family"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
 	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	notificationsdomain "family-app-go/internal/domain/notifications"
+	petsdomain "family-app-go/internal/domain/pets"
+	pollsdomain "family-app-go/internal/domain/polls"
 	ratesdomain "family-app-go/internal/domain/rates"
 	receiptsdomain "family-app-go/internal/domain/receipts"
+	retentiondomain "family-app-go/internal/domain/retention"
+	searchdomain "family-app-go/internal/domain/search"
+	sessionsdomain "family-app-go/internal/domain/sessions"
 	syncdomain "family-app-go/internal/domain/sync"
 	todosdomain "family-app-go/internal/domain/todos"
+	usagedomain "family-app-go/internal/domain/usage"
 	userdomain "family-app-go/internal/domain/user"
+	viewsdomain "family-app-go/internal/domain/views"
+	wishlistdomain "family-app-go/internal/domain/wishlist"
+	yearreviewdomain "family-app-go/internal/domain/yearreview"
+	"family-app-go/internal/jobs"
 	httpratesrepo "family-app-go/internal/repository/http/rates"
 	inmemoryrepo "family-app-go/internal/repository/inmemory"
+	activityrepo "family-app-go/internal/repository/postgres/activity"
+	adminrepo "family-app-go/internal/repository/postgres/admin"
 	analyticsrepo "family-app-go/internal/repository/postgres/analytics"
+	apikeysrepo "family-app-go/internal/repository/postgres/apikeys"
+	auditrepo "family-app-go/internal/repository/postgres/audit"
+	backuprepo "family-app-go/internal/repository/postgres/backup"
+	budgetsrepo "family-app-go/internal/repository/postgres/budgets"
+	calendarrepo "family-app-go/internal/repository/postgres/calendar"
+	commentsrepo "family-app-go/internal/repository/postgres/comments"
+	erasurerepo "family-app-go/internal/repository/postgres/erasure"
 	expensesrepo "family-app-go/internal/repository/postgres/expenses"
+	exportsrepo "family-app-go/internal/repository/postgres/exports"
 	familyrepo "family-app-go/internal/repository/postgres/family"
+	favoritesrepo "family-app-go/internal/repository/postgres/favorites"
+	featureflagsrepo "family-app-go/internal/repository/postgres/featureflags"
 	gymrepo "family-app-go/internal/repository/postgres/gym"
+	labelsrepo "family-app-go/internal/repository/postgres/labels"
+	milestonesrepo "family-app-go/internal/repository/postgres/milestones"
+	notificationsrepo "family-app-go/internal/repository/postgres/notifications"
+	petsrepo "family-app-go/internal/repository/postgres/pets"
+	pollsrepo "family-app-go/internal/repository/postgres/polls"
 	postgresratesrepo "family-app-go/internal/repository/postgres/rates"
 	receiptsrepo "family-app-go/internal/repository/postgres/receipts"
+	retentionrepo "family-app-go/internal/repository/postgres/retention"
+	sessionsrepo "family-app-go/internal/repository/postgres/sessions"
 	syncrepo "family-app-go/internal/repository/postgres/sync"
 	todosrepo "family-app-go/internal/repository/postgres/todos"
+	usagerepo "family-app-go/internal/repository/postgres/usage"
 	userrepo "family-app-go/internal/repository/postgres/user"
+	viewsrepo "family-app-go/internal/repository/postgres/views"
+	wishlistrepo "family-app-go/internal/repository/postgres/wishlist"
+	yearreviewrepo "family-app-go/internal/repository/postgres/yearreview"
+	"family-app-go/internal/transport/grpcserver"
 	"family-app-go/internal/transport/httpserver"
 	"family-app-go/internal/transport/httpserver/handler"
 	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/blobstore"
+	"family-app-go/pkg/errorreport"
+	"family-app-go/pkg/fieldcrypt"
 	"family-app-go/pkg/logger"
+	"family-app-go/pkg/tracing"
 	"gorm.io/gorm"
 )
 
 type App struct {
-	cfg        config.Config
-	httpServer *http.Server
-	db         *gorm.DB
+	cfg             config.Config
+	httpServer      *http.Server
+	grpcServer      *grpcserver.Server
+	jobRunner       *jobs.Runner
+	usage           *usagedomain.Service
+	db              *gorm.DB
+	shutdownTracing func(context.Context) error
+	errorReporter   *errorreport.Sentry
 }
 
 func New(log logger.Logger) (*App, error) {
@@ -47,11 +112,53 @@ func New(log logger.Logger) (*App, error) {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Enabled:     cfg.Tracing.Enabled,
+		ServiceName: cfg.Tracing.ServiceName,
+		Endpoint:    cfg.Tracing.Endpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+		Environment: cfg.Env,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initialize tracing: %w", err)
+	}
+	if cfg.Tracing.Enabled {
+		log.Info("app: tracing enabled", "service_name", cfg.Tracing.ServiceName, "sample_ratio", cfg.Tracing.SampleRatio)
+	}
+
+	errorReporter, err := errorreport.NewSentry(errorreport.Config{
+		DSN:         cfg.ErrorReporting.SentryDSN,
+		Environment: cfg.Env,
+		Release:     cfg.ErrorReporting.Release,
+		SampleRate:  cfg.ErrorReporting.SampleRate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("initialize error reporting: %w", err)
+	}
+	if errorReporter != nil {
+		log = logger.WithReporter(log, errorReporter)
+		log.Info("app: error reporting enabled", "sample_rate", cfg.ErrorReporting.SampleRate)
+	}
+
+	keyring, err := fieldcrypt.LoadKeyring(context.Background(), fieldcrypt.EnvProvider{Value: cfg.Encryption.Keys})
+	if err != nil {
+		return nil, fmt.Errorf("load field encryption keys: %w", err)
+	}
+	fieldcrypt.Use(keyring)
+	if keyring != nil {
+		log.Info("app: field encryption enabled", "key_id", keyring.PrimaryKeyID())
+	}
+
 	log.Info("app: initializing database")
 	dbConn, err := db.NewPostgres(log, cfg.DB)
 	if err != nil {
 		return nil, fmt.Errorf("initialize database: %w", err)
 	}
+	if cfg.Tracing.Enabled {
+		if err := tracing.InstrumentGORM(dbConn); err != nil {
+			return nil, fmt.Errorf("instrument database: %w", err)
+		}
+	}
 
 	log.Info("app: running migrations")
 	if err := db.Migrate(dbConn); err != nil {
@@ -69,13 +176,25 @@ func New(log logger.Logger) (*App, error) {
 		return nil, fmt.Errorf("initialize rates provider: %w", err)
 	}
 	ratesProvider := postgresratesrepo.NewPostgresProvider(dbConn, nbrbProvider)
-	ratesService := ratesdomain.NewService(ratesProvider, ratesdomain.Config{
+	referenceProviders, err := buildReferenceRateProviders(cfg.Rates, log)
+	if err != nil {
+		return nil, fmt.Errorf("initialize reference rate providers: %w", err)
+	}
+	ratesService := ratesdomain.NewServiceWithReferenceRates(ratesProvider, postgresratesrepo.NewPostgres(dbConn), referenceProviders, ratesdomain.Config{
 		RateCacheTTL:       cfg.Rates.RateCacheTTL,
 		CurrenciesCacheTTL: cfg.Rates.CurrenciesCacheTTL,
 		FallbackDays:       cfg.Rates.FallbackDays,
 	})
-	expensesService := expensesdomain.NewServiceWithDependencies(expensesRepo, categoriesCache, ratesService)
+	expensesService := expensesdomain.NewServiceWithOptions(expensesRepo, expensesdomain.ServiceOptions{
+		CategoriesCache: categoriesCache,
+		Rates:           ratesService,
+		SpendingLimits:  familyService,
+		ExpensesPerDay:  cfg.Quotas.ExpensesPerDay,
+	})
 	analyticsRepo := analyticsrepo.NewPostgres(dbConn)
+	if cfg.AnalyticsRollups.Enabled {
+		analyticsRepo = analyticsrepo.NewPostgresWithRollups(dbConn)
+	}
 	analyticsService := analyticsdomain.NewServiceWithTopCategoriesConfig(analyticsRepo, analyticsdomain.TopCategoriesConfig{
 		Enabled:       cfg.TopCategories.Enabled,
 		LookbackDays:  cfg.TopCategories.LookbackDays,
@@ -84,14 +203,45 @@ func New(log logger.Logger) (*App, error) {
 		ResponseCount: cfg.TopCategories.ResponseCount,
 		CacheTTL:      cfg.TopCategories.CacheTTL,
 	})
+	profileSource, err := buildProfileSource(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("initialize profile source: %w", err)
+	}
 	userRepo := userrepo.NewPostgres(dbConn)
-	userService := userdomain.NewService(userRepo)
+	userService := userdomain.NewServiceWithOptions(userRepo, userdomain.ServiceOptions{
+		Avatars:        blobstore.NewLocal(cfg.Avatar.StorageDir),
+		ProfileSource:  profileSource,
+		SyncBatchSize:  cfg.ProfileSync.BatchSize,
+		SyncStaleAfter: cfg.ProfileSync.StaleAfter,
+	})
 	todosRepo := todosrepo.NewPostgres(dbConn)
-	todosService := todosdomain.NewService(todosRepo)
+	todosService := todosdomain.NewServiceWithOptions(todosRepo, todosdomain.ServiceOptions{
+		TodoListsPerFamily: cfg.Quotas.TodoListsPerFamily,
+		TodoItemsPerList:   cfg.Quotas.TodoItemsPerList,
+	})
 	syncRepo := syncrepo.NewPostgres(dbConn)
-	syncService := syncdomain.NewService(syncRepo, expensesService, todosService)
+	featureFlagsService := featureflagsdomain.NewServiceWithOptions(featureflagsrepo.NewPostgres(dbConn), featureflagsdomain.ServiceOptions{
+		CacheTTL: cfg.FeatureFlags.CacheTTL,
+	})
+	syncService := syncdomain.NewServiceWithOptions(syncRepo, expensesService, todosService, syncdomain.ServiceOptions{
+		OperationsPerHour: cfg.Quotas.SyncOperationsPerHour,
+		RetentionDays:     cfg.SyncRetention.Days,
+		Flags:             featureFlagsService,
+	})
 	gymRepo := gymrepo.NewPostgres(dbConn)
-	gymService := gymdomain.NewService(gymRepo)
+	gymService := gymdomain.NewServiceWithOptions(gymRepo, familyService, gymdomain.ServiceOptions{
+		NudgeBefore: cfg.GymNudge.BeforeWeek,
+	})
+	labelsService := labelsdomain.NewService(labelsrepo.NewPostgres(dbConn))
+	favoritesService := favoritesdomain.NewService(favoritesrepo.NewPostgres(dbConn))
+	auditService := auditdomain.NewServiceWithOptions(auditrepo.NewPostgres(dbConn), auditdomain.ServiceOptions{
+		RetentionDays: cfg.Audit.RetentionDays,
+	})
+	viewsService := viewsdomain.NewService(viewsrepo.NewPostgres(dbConn))
+	searchService := searchdomain.NewService(expensesService, todosService, gymService)
+	batchService := batchdomain.NewService(expensesService, todosService, gymService, labelsService)
+	yearReviewService := yearreviewdomain.NewService(yearreviewrepo.NewPostgres(dbConn))
+	commentsService := commentsdomain.NewService(commentsrepo.NewPostgres(dbConn))
 	receiptRepo := receiptsrepo.NewPostgres(dbConn)
 	receiptParser, err := buildReceiptParser(cfg.ReceiptParser, log)
 	if err != nil {
@@ -106,6 +256,70 @@ func New(log logger.Logger) (*App, error) {
 		HintNormalizer: receiptHintNormalizer,
 		WorkerEnabled:  true,
 	})
+	retentionRepo := retentionrepo.NewPostgres(dbConn)
+	retentionService := retentiondomain.NewServiceWithOptions(retentionRepo, familyService, retentiondomain.ServiceOptions{
+		RunInterval: cfg.Retention.RunInterval,
+	})
+	exportsService := exportsdomain.NewServiceWithOptions(exportsrepo.NewPostgres(dbConn), familyService, blobstore.NewLocal(cfg.Exports.StorageDir), exportsdomain.ServiceOptions{
+		Secret: cfg.Exports.SigningSecret,
+		TTL:    cfg.Exports.TTL,
+	})
+	erasureService := erasuredomain.NewServiceWithOptions(erasurerepo.NewPostgres(dbConn), familyService, erasuredomain.ServiceOptions{
+		GracePeriod: cfg.Erasure.GracePeriod,
+		Avatars:     userService,
+		Archives:    exportsService,
+	})
+	backupService := backupdomain.NewServiceWithOptions(backuprepo.NewPostgres(dbConn), blobstore.NewLocal(cfg.Blob.StorageDir), backupdomain.ServiceOptions{
+		Retention: cfg.Backup.Retention,
+		KeepLast:  cfg.Backup.KeepLast,
+	})
+	authCache, err := buildAuthCache(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("initialize auth cache: %w", err)
+	}
+	sessionsRepo := sessionsrepo.NewPostgres(dbConn)
+	sessionsService := sessionsdomain.NewService(sessionsRepo, authCache)
+	usageService := usagedomain.NewServiceWithOptions(usagerepo.NewPostgres(dbConn), usagedomain.ServiceOptions{
+		RetentionDays: cfg.Usage.RetentionDays,
+	})
+	activityRepo := activityrepo.NewPostgres(dbConn)
+	activityService := activitydomain.NewService(activityRepo)
+	pollsService := pollsdomain.NewService(pollsrepo.NewPostgres(dbConn), activityService)
+	pushProviders, err := buildPushProviders(cfg.Notifications, log)
+	if err != nil {
+		return nil, fmt.Errorf("initialize push providers: %w", err)
+	}
+	notificationsService := notificationsdomain.NewServiceWithOptions(notificationsrepo.NewPostgres(dbConn), notificationsdomain.ServiceOptions{
+		Pushers: pushProviders,
+	})
+	budgetsService := budgetsdomain.NewService(budgetsrepo.NewPostgres(dbConn), analyticsService, notificationsService)
+	jobRunner, err := buildJobRunner(cfg, dbConn, log, analyticsService, retentionService, gymService, receiptService, exportsService, erasureService, backupService, ratesService, auditService, syncService, sessionsService, usageService, pollsService, userService, budgetsService)
+	if err != nil {
+		return nil, fmt.Errorf("initialize job runner: %w", err)
+	}
+	adminService := admindomain.NewServiceWithOptions(adminrepo.NewPostgres(dbConn), admindomain.ServiceOptions{
+		Jobs:                jobRunner,
+		Backups:             backupService,
+		Keyring:             keyring,
+		StuckSyncBatchAfter: cfg.Admin.StuckSyncBatchAfter,
+	})
+	calendarService := calendardomain.NewService(calendarrepo.NewPostgres(dbConn), familyService, todosService, cfg.Calendar.FeedSecret)
+	wishlistRepo := wishlistrepo.NewPostgres(dbConn)
+	wishlistService := wishlistdomain.NewService(wishlistRepo, familyService)
+	petsRepo := petsrepo.NewPostgres(dbConn)
+	petsService := petsdomain.NewService(petsRepo, expensesService)
+	milestonesService := milestonesdomain.NewService(milestonesrepo.NewPostgres(dbConn))
+	dashboardService := dashboarddomain.NewServiceWithOptions(expensesService, todosService, petsService, gymService, activityService, milestonesService, dashboarddomain.ServiceOptions{
+		SectionTimeout: cfg.Dashboard.SectionTimeout,
+	})
+	apiKeysRepo := apikeysrepo.NewPostgres(dbConn)
+	apiKeysService := apikeysdomain.NewService(apiKeysRepo)
+	authProvider, authService, err := buildAuthProvider(cfg, dbConn, log)
+	if err != nil {
+		return nil, fmt.Errorf("initialize auth provider: %w", err)
+	}
+
+	healthService := buildHealthService(cfg, dbConn)
 
 	var mockDataSeeder commonhandler.FamilySeeder
 	if cfg.MockDataSeed.Enabled {
@@ -119,18 +333,57 @@ func New(log logger.Logger) (*App, error) {
 			Currency:         cfg.MockDataSeed.Currency,
 		})
 	}
-	handlers := handler.New(analyticsService, familyService, expensesService, ratesService, todosService, syncService, gymService, receiptService, log, mockDataSeeder)
+	handlers := handler.New(activityService, analyticsService, authService, apiKeysService, sessionsService, familyService, userService, expensesService, ratesService, todosService, syncService, gymService, labelsService, receiptService, retentionService, calendarService, wishlistService, petsService, healthService, adminService, exportsService, erasureService, viewsService, searchService, dashboardService, yearReviewService, commentsService, pollsService, milestonesService, budgetsService, notificationsService, batchService, favoritesService, featureFlagsService, auditService, usageService, commonhandler.NewListLimits(cfg.ListLimits), log, mockDataSeeder)
+
+	// Counting stays off until USAGE_ANALYTICS_ENABLED; the admin API still
+	// reports what was collected.
+	var usageRecorder authmw.UsageRecorder
+	if cfg.Usage.Enabled {
+		usageRecorder = usageService
+	}
 
 	log.Info("app: initializing router")
-	router := httpserver.NewRouter(cfg, handlers, userService, log)
+	router := httpserver.NewRouter(cfg, handlers, authProvider, apiKeysService, sessionsService, userService, familyService, featureFlagsService, usageRecorder, authCache, auditService, log)
 
 	log.Info("app: initializing http server")
 	srv := httpserver.New(cfg, router)
 
+	var grpcSrv *grpcserver.Server
+	if cfg.GRPC.Enabled {
+		log.Info("app: initializing grpc server")
+		grpcAuth := authmw.NewAuth(cfg.Supabase, authProvider, apiKeysService, sessionsService, userService, authCache, auditService, log)
+		grpcSrv = grpcserver.New(cfg, grpcserver.Services{
+			Families: familyService,
+			Expenses: expensesService,
+			Todos:    todosService,
+			Sync:     syncService,
+			Activity: activityService,
+		}, grpcAuth, authmw.NewFamilyAccess(familyService, log), authmw.NewMaintenance(featureFlagsService, cfg.Maintenance.RetryAfter, log), log)
+	}
+
+	if cfg.Jobs.Enabled {
+		log.Info("app: starting job runner", "jobs", len(jobRunner.Jobs()))
+		if err := jobRunner.Start(); err != nil {
+			return nil, fmt.Errorf("start job runner: %w", err)
+		}
+	}
+
+	if cfg.Usage.Enabled {
+		log.Info("app: starting usage counters", "flush_interval", cfg.Usage.FlushInterval)
+		usageService.Start(cfg.Usage.FlushInterval, func(err error) {
+			log.Warn("usage: flush failed", "err", err)
+		})
+	}
+
 	return &App{
-		cfg:        cfg,
-		httpServer: srv,
-		db:         dbConn,
+		cfg:             cfg,
+		httpServer:      srv,
+		grpcServer:      grpcSrv,
+		jobRunner:       jobRunner,
+		usage:           usageService,
+		db:              dbConn,
+		shutdownTracing: shutdownTracing,
+		errorReporter:   errorReporter,
 	}, nil
 }
 
@@ -138,13 +391,80 @@ func (a *App) HTTPServer() *http.Server {
 	return a.httpServer
 }
 
+// GRPCServer returns nil unless GRPC_ENABLED is set.
+func (a *App) GRPCServer() *grpcserver.Server {
+	return a.grpcServer
+}
+
+// ShutdownTimeout is SHUTDOWN_TIMEOUT, the budget for Shutdown.
+func (a *App) ShutdownTimeout() time.Duration {
+	return a.cfg.ShutdownTimeout
+}
+
+// Shutdown stops accepting work and drains the HTTP server, the gRPC server
+// and the job runner side by side, so one slow part does not eat the others'
+// budget, then writes the remaining usage counters. Whatever still runs when
+// ctx is done is cut off.
+func (a *App) Shutdown(ctx context.Context) error {
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	drain := func(name string, stop func(context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := stop(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+
+	drain("shutdown http", a.httpServer.Shutdown)
+	if a.grpcServer != nil {
+		drain("shutdown grpc", a.grpcServer.Shutdown)
+	}
+	if a.jobRunner != nil {
+		drain("stop jobs", a.jobRunner.Stop)
+	}
+	wg.Wait()
+	// Requests are drained now, so the last counters are complete.
+	if a.usage != nil {
+		if err := a.usage.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("flush usage: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes reported errors and releases tracing and the database. Call
+// it after Shutdown.
 func (a *App) Close() error {
-	if a.db == nil {
-		return nil
+	var errs []error
+	if a.errorReporter != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.errorReporter.Flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	sqlDB, err := a.db.DB()
-	if err != nil {
-		return err
+	if a.shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.shutdownTracing(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown tracing: %w", err))
+		}
+	}
+	if a.db != nil {
+		sqlDB, err := a.db.DB()
+		if err != nil {
+			errs = append(errs, err)
+		} else if err := sqlDB.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return sqlDB.Close()
+	return errors.Join(errs...)
 }
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"family-app-go/internal/config"
+	inmemoryrepo "family-app-go/internal/repository/inmemory"
+	redisrepo "family-app-go/internal/repository/redis"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+)
+
+func buildAuthCache(cfg config.Config, log logger.Logger) (authmw.AuthCache, error) {
+	backend := strings.TrimSpace(cfg.Supabase.CacheBackend)
+	if cfg.Supabase.CacheTTL <= 0 || backend == "none" {
+		log.Info("app: auth cache disabled")
+		return nil, nil
+	}
+
+	switch backend {
+	case "", "memory":
+		log.Info("app: using in-memory auth cache", "ttl", cfg.Supabase.CacheTTL.String())
+		return inmemoryrepo.NewInMemoryAuthCache(), nil
+	case "redis":
+		if strings.TrimSpace(cfg.Redis.URL) == "" {
+			return nil, fmt.Errorf("REDIS_URL is required for redis auth cache")
+		}
+		client, err := redisrepo.NewClient(cfg.Redis.URL)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("app: using redis auth cache", "ttl", cfg.Supabase.CacheTTL.String())
+		return redisrepo.NewAuthCache(client), nil
+	default:
+		return nil, fmt.Errorf("unknown auth cache backend %q", backend)
+	}
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"family-app-go/internal/config"
+	authdomain "family-app-go/internal/domain/auth"
+	userdomain "family-app-go/internal/domain/user"
+	supabaserepo "family-app-go/internal/repository/http/supabase"
+	authrepo "family-app-go/internal/repository/postgres/auth"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const localAuthIssuer = "family-app"
+
+// minJWTSecretLength keeps HS256 secrets at the size of the hash output.
+const minJWTSecretLength = 32
+
+// buildAuthProvider returns the provider that verifies bearer tokens and, in
+// self-hosted mode, the service behind the register/login endpoints.
+func buildAuthProvider(cfg config.Config, db *gorm.DB, log logger.Logger) (authmw.AuthProvider, *authdomain.Service, error) {
+	provider := strings.TrimSpace(cfg.Auth.Provider)
+	switch provider {
+	case "", "supabase":
+		log.Info("app: using supabase auth provider")
+		return authmw.NewSupabaseProvider(cfg.Supabase, log), nil, nil
+	case "local":
+		if len(cfg.Auth.JWTSecret) < minJWTSecretLength {
+			return nil, nil, fmt.Errorf("AUTH_JWT_SECRET must be at least %d bytes for local auth", minJWTSecretLength)
+		}
+		service := authdomain.NewService(authrepo.NewPostgres(db), authdomain.Config{
+			Secret:          []byte(cfg.Auth.JWTSecret),
+			Issuer:          localAuthIssuer,
+			AccessTokenTTL:  cfg.Auth.AccessTokenTTL,
+			RefreshTokenTTL: cfg.Auth.RefreshTokenTTL,
+		})
+		log.Info("app: using local auth provider", "access_token_ttl", cfg.Auth.AccessTokenTTL.String())
+		return authmw.NewLocalProvider(service, log), service, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown auth provider %q", provider)
+	}
+}
+
+// buildProfileSource returns the Supabase admin client profile_sync refreshes
+// profiles from, or nil when users do not come from Supabase or no service
+// role key is configured.
+func buildProfileSource(cfg config.Config, log logger.Logger) (userdomain.ProfileSource, error) {
+	provider := strings.TrimSpace(cfg.Auth.Provider)
+	if (provider != "" && provider != "supabase") || cfg.Supabase.SkipAuth || cfg.Supabase.URL == "" {
+		return nil, nil
+	}
+	if strings.TrimSpace(cfg.Supabase.ServiceRoleKey) == "" {
+		log.Info("app: supabase service role key is empty, profiles are not refreshed from supabase")
+		return nil, nil
+	}
+	client, err := supabaserepo.NewAdminClient(cfg.Supabase.URL, cfg.Supabase.ServiceRoleKey, cfg.Supabase.AuthTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
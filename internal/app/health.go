@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"family-app-go/internal/config"
+	"family-app-go/internal/db"
+	healthdomain "family-app-go/internal/domain/health"
+	"gorm.io/gorm"
+)
+
+const supabaseHealthPath = "/auth/v1/health"
+
+// buildHealthService wires the readiness checks. The database and schema are
+// critical; the read replica and Supabase only degrade readiness because the
+// API keeps working without them.
+func buildHealthService(cfg config.Config, dbConn *gorm.DB) *healthdomain.Service {
+	checks := []healthdomain.Check{
+		{
+			Name:     "database",
+			Critical: true,
+			Run: func(ctx context.Context) (map[string]any, error) {
+				sqlDB, err := dbConn.DB()
+				if err != nil {
+					return nil, err
+				}
+				if err := sqlDB.PingContext(ctx); err != nil {
+					return nil, err
+				}
+				stats := sqlDB.Stats()
+				return map[string]any{
+					"open_connections": stats.OpenConnections,
+					"in_use":           stats.InUse,
+				}, nil
+			},
+		},
+		{
+			Name:     "migrations",
+			Critical: true,
+			Run: func(ctx context.Context) (map[string]any, error) {
+				pending, err := db.PendingMigrations(ctx, dbConn)
+				if err != nil {
+					return nil, err
+				}
+				if len(pending) > 0 {
+					return map[string]any{"pending": pending}, fmt.Errorf("%d pending migrations", len(pending))
+				}
+				return nil, nil
+			},
+		},
+	}
+
+	if cfg.DB.ReplicaDSN != "" {
+		// Not critical: a lagging or down replica only slows lists and
+		// analytics, it does not take writes down.
+		checks = append(checks, healthdomain.Check{
+			Name: "database_replica",
+			Run: func(ctx context.Context) (map[string]any, error) {
+				var applied int64
+				if err := dbConn.WithContext(ctx).Clauses(db.ReadReplica).Table("schema_migrations").Count(&applied).Error; err != nil {
+					return nil, err
+				}
+				return map[string]any{"applied_migrations": applied}, nil
+			},
+		})
+	}
+
+	provider := strings.TrimSpace(cfg.Auth.Provider)
+	if (provider == "" || provider == "supabase") && !cfg.Supabase.SkipAuth && cfg.Supabase.URL != "" {
+		checks = append(checks, healthdomain.Check{
+			Name: "supabase",
+			Run:  supabaseHealthCheck(cfg.Supabase),
+		})
+	}
+
+	return healthdomain.NewService(checks, cfg.Health.CheckTimeout)
+}
+
+func supabaseHealthCheck(cfg config.SupabaseConfig) func(ctx context.Context) (map[string]any, error) {
+	url := strings.TrimRight(cfg.URL, "/") + supabaseHealthPath
+	client := &http.Client{}
+	return func(ctx context.Context) (map[string]any, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.PublishableKey != "" {
+			req.Header.Set("apikey", cfg.PublishableKey)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		details := map[string]any{"status_code": resp.StatusCode}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return details, fmt.Errorf("supabase returned status %d", resp.StatusCode)
+		}
+		return details, nil
+	}
+}
@@ -0,0 +1,343 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"family-app-go/internal/config"
+	analyticsdomain "family-app-go/internal/domain/analytics"
+	auditdomain "family-app-go/internal/domain/audit"
+	backupdomain "family-app-go/internal/domain/backup"
+	budgetsdomain "family-app-go/internal/domain/budgets"
+	erasuredomain "family-app-go/internal/domain/erasure"
+	exportsdomain "family-app-go/internal/domain/exports"
+	gymdomain "family-app-go/internal/domain/gym"
+	pollsdomain "family-app-go/internal/domain/polls"
+	ratesdomain "family-app-go/internal/domain/rates"
+	receiptsdomain "family-app-go/internal/domain/receipts"
+	retentiondomain "family-app-go/internal/domain/retention"
+	sessionsdomain "family-app-go/internal/domain/sessions"
+	syncdomain "family-app-go/internal/domain/sync"
+	usagedomain "family-app-go/internal/domain/usage"
+	userdomain "family-app-go/internal/domain/user"
+	"family-app-go/internal/jobs"
+	jobsrepo "family-app-go/internal/repository/postgres/jobs"
+	"family-app-go/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// buildJobRunner registers the background jobs. Postgres advisory locks keep
+// each job to one instance at a time. Jobs whose worker is disabled stay
+// registered without a schedule so operators can still run them.
+func buildJobRunner(cfg config.Config, dbConn *gorm.DB, log logger.Logger, analytics *analyticsdomain.Service, retention *retentiondomain.Service, gym *gymdomain.Service, receipts *receiptsdomain.Service, exports *exportsdomain.Service, erasure *erasuredomain.Service, backups *backupdomain.Service, rates *ratesdomain.Service, audit *auditdomain.Service, syncs *syncdomain.Service, sessions *sessionsdomain.Service, usage *usagedomain.Service, polls *pollsdomain.Service, users *userdomain.Service, budgets *budgetsdomain.Service) (*jobs.Runner, error) {
+	repo := jobsrepo.NewPostgres(dbConn)
+	runner := jobs.NewRunner(jobs.Options{
+		Store:        repo,
+		Locker:       repo,
+		Logger:       log,
+		Timeout:      cfg.Jobs.Timeout,
+		MaxAttempts:  cfg.Jobs.MaxAttempts,
+		RetryBackoff: cfg.Jobs.RetryBackoff,
+	})
+
+	backupSchedule, err := jobs.ParseSchedule(cfg.Backup.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("backup schedule: %w", err)
+	}
+	ratesSchedule, err := jobs.ParseSchedule(cfg.Rates.RefreshSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("rates refresh schedule: %w", err)
+	}
+	rollupsSchedule, err := jobs.ParseSchedule(cfg.AnalyticsRollups.RebuildSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("analytics rollups rebuild schedule: %w", err)
+	}
+
+	registered := []jobs.Job{
+		{
+			Name:        "retention",
+			Description: "Apply retention policies that are due.",
+			Schedule:    scheduleIf(cfg.Retention.JobEnabled, jobs.Every(cfg.Retention.PollInterval)),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				results, err := retention.RunDue(ctx)
+				for _, result := range results {
+					log.Info(
+						"retention: policy applied",
+						"family_id", result.FamilyID,
+						"expenses_deleted", result.ExpensesDeleted,
+						"todos_archived", result.TodosArchived,
+					)
+				}
+				return results, err
+			},
+		},
+		{
+			Name:        "gym_nudges",
+			Description: "Nudge users who are behind their weekly gym goal.",
+			Schedule:    scheduleIf(cfg.GymNudge.JobEnabled, jobs.Every(cfg.GymNudge.PollInterval)),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				nudges, err := gym.RunNudges(ctx)
+				for _, nudge := range nudges {
+					log.Info(
+						"gym: goal nudge created",
+						"user_id", nudge.UserID,
+						"workouts_done", nudge.WorkoutsDone,
+						"target", nudge.Target,
+					)
+				}
+				return nudges, err
+			},
+		},
+		{
+			Name:        "budget_alerts",
+			Description: "Notify families whose spending reached 50, 80 or 100% of a monthly budget.",
+			Schedule:    scheduleIf(cfg.BudgetAlerts.JobEnabled, jobs.Every(cfg.BudgetAlerts.PollInterval)),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				alerts, err := budgets.EvaluateAlerts(ctx)
+				for _, alert := range alerts {
+					log.Info(
+						"budgets: threshold alert sent",
+						"budget_id", alert.BudgetID,
+						"family_id", alert.FamilyID,
+						"threshold", alert.Threshold,
+						"recipients", alert.Recipients,
+						"push_failures", alert.PushFailures,
+					)
+				}
+				return alerts, err
+			},
+		},
+		{
+			Name:        "family_exports",
+			Description: "Build pending family data exports and remove expired ones.",
+			Schedule:    scheduleIf(exports.Enabled(), jobs.Every(cfg.Exports.PollInterval)),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				results, err := exports.ProcessPending(ctx)
+				for _, result := range results {
+					log.Info(
+						"exports: export processed",
+						"export_id", result.ExportID,
+						"family_id", result.FamilyID,
+						"status", result.Status,
+						"size_bytes", result.SizeBytes,
+					)
+				}
+				return results, err
+			},
+		},
+		{
+			Name:        "erasure_purge",
+			Description: "Hard-delete accounts and families whose deletion grace period has ended.",
+			Schedule:    jobs.Every(cfg.Erasure.PollInterval),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				results, err := erasure.PurgeDue(ctx)
+				for _, result := range results {
+					log.Info(
+						"erasure: subject purged",
+						"request_id", result.RequestID,
+						"subject", result.Subject,
+						"subject_id", result.SubjectID,
+					)
+				}
+				return results, err
+			},
+		},
+		{
+			Name:        "audit_purge",
+			Description: "Delete security audit events older than the retention period.",
+			Schedule:    jobs.Every(cfg.Audit.PurgeInterval),
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := audit.Purge(ctx)
+				if result != nil {
+					log.Info(
+						"audit: events purged",
+						"deleted_before", result.DeletedBefore,
+						"deleted", result.Deleted,
+					)
+				}
+				return result, err
+			},
+		},
+		{
+			Name:        "sync_purge",
+			Description: "Delete sync operations, batches and change events older than the retention period.",
+			Schedule:    jobs.Every(cfg.SyncRetention.PurgeInterval),
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := syncs.Purge(ctx)
+				if result != nil {
+					log.Info(
+						"sync: records purged",
+						"deleted_before", result.DeletedBefore,
+						"operations_deleted", result.OperationsDeleted,
+						"batches_deleted", result.BatchesDeleted,
+						"events_deleted", result.EventsDeleted,
+						"operation_rows", result.Storage.OperationRows,
+						"operation_bytes", result.Storage.OperationBytes,
+						"batch_rows", result.Storage.BatchRows,
+						"batch_bytes", result.Storage.BatchBytes,
+					)
+				}
+				return result, err
+			},
+		},
+		{
+			Name:        "sessions_purge",
+			Description: "Delete sessions unseen for 30 days and revocations older than a year.",
+			Schedule:    jobs.Every(24 * time.Hour),
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := sessions.Purge(ctx)
+				if result != nil {
+					log.Info(
+						"sessions: stale sessions purged",
+						"seen_before", result.SeenBefore,
+						"revoked_before", result.RevokedBefore,
+						"deleted", result.Deleted,
+					)
+				}
+				return result, err
+			},
+		},
+		{
+			Name:        "usage_purge",
+			Description: "Delete API usage counts older than the retention period.",
+			Schedule:    jobs.Every(24 * time.Hour),
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := usage.Purge(ctx)
+				if result != nil {
+					log.Info(
+						"usage: counts purged",
+						"deleted_before", result.DeletedBefore,
+						"deleted", result.Deleted,
+					)
+				}
+				return result, err
+			},
+		},
+		{
+			Name:        "polls_close",
+			Description: "Close polls past their deadline and announce the outcome.",
+			Schedule:    jobs.Every(cfg.Polls.CloseInterval),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				closed, err := polls.CloseDue(ctx)
+				for _, summary := range closed {
+					log.Info(
+						"polls: poll closed",
+						"poll_id", summary.Poll.ID,
+						"family_id", summary.Poll.FamilyID,
+						"total_votes", summary.Results.TotalVotes,
+					)
+				}
+				return closed, err
+			},
+		},
+		{
+			Name:        "profile_sync",
+			Description: "Refresh user profiles from Supabase and patch the todo completion snapshots of changed profiles.",
+			Schedule:    jobs.Every(cfg.ProfileSync.Interval),
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := users.SyncProfiles(ctx)
+				if result != nil {
+					log.Info(
+						"user: profiles synced",
+						"checked", result.Checked,
+						"missing", result.Missing,
+						"failed", result.Failed,
+						"drained", result.Drained,
+						"snapshots_patched", result.SnapshotsPatched,
+					)
+				}
+				return result, err
+			},
+		},
+		{
+			Name:        "backup",
+			Description: "Snapshot the database to the blob store and prune old backups.",
+			Schedule:    scheduleIf(cfg.Backup.Enabled, backupSchedule),
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := backups.Run(ctx)
+				if result != nil {
+					log.Info(
+						"backup: snapshot stored",
+						"key", result.Key,
+						"size_bytes", result.SizeBytes,
+						"tables", len(result.Tables),
+						"removed", len(result.Removed),
+					)
+				}
+				return result, err
+			},
+		},
+		{
+			Name:        "fx_rates",
+			Description: "Fetch and store today's reference exchange rates.",
+			Schedule:    scheduleIf(cfg.Rates.RefreshEnabled && rates.HasReferenceProviders(), ratesSchedule),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := rates.RefreshReferenceRates(ctx, time.Now())
+				if result != nil {
+					log.Info(
+						"rates: reference rates stored",
+						"date", result.Date.Format("2006-01-02"),
+						"source", result.Source,
+						"currencies", result.Currencies,
+					)
+				}
+				return result, err
+			},
+		},
+		{
+			Name:        "analytics_rollups_refresh",
+			Description: "Roll up expense days changed since the last run.",
+			Schedule:    jobs.Every(cfg.AnalyticsRollups.RefreshInterval),
+			RunOnStart:  true,
+			Run: func(ctx context.Context) (interface{}, error) {
+				return analytics.RefreshRollups(ctx)
+			},
+		},
+		{
+			Name:        "analytics_rollups_rebuild",
+			Description: "Rebuild the expense rollups of the last days.",
+			Schedule:    rollupsSchedule,
+			Run: func(ctx context.Context) (interface{}, error) {
+				result, err := analytics.RebuildRollups(ctx, cfg.AnalyticsRollups.RebuildDays)
+				log.Info(
+					"analytics: rollups rebuilt",
+					"from", result.From,
+					"to", result.To,
+					"days", result.Days,
+				)
+				return result, err
+			},
+		},
+		{
+			Name:        "receipts_recover",
+			Description: "Requeue receipt parses stuck in processing.",
+			Run: func(ctx context.Context) (interface{}, error) {
+				if err := receipts.RecoverStaleProcessing(ctx); err != nil {
+					return nil, err
+				}
+				return nil, receipts.RecoverStaleCategoryCorrections(ctx)
+			},
+		},
+	}
+	for _, job := range registered {
+		if err := runner.Register(job); err != nil {
+			return nil, err
+		}
+	}
+	return runner, nil
+}
+
+func scheduleIf(enabled bool, schedule jobs.Schedule) jobs.Schedule {
+	if !enabled {
+		return nil
+	}
+	return schedule
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"family-app-go/internal/config"
+	notificationsdomain "family-app-go/internal/domain/notifications"
+	httppushrepo "family-app-go/internal/repository/http/push"
+	"family-app-go/pkg/logger"
+)
+
+func buildPushProviders(cfg config.NotificationsConfig, log logger.Logger) ([]notificationsdomain.Pusher, error) {
+	var providers []notificationsdomain.Pusher
+	for _, name := range strings.Split(cfg.PushProviders, ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "":
+		case "webhook":
+			if strings.TrimSpace(cfg.PushWebhookURL) == "" {
+				log.Warn("app: push webhook url is empty, skipping push provider")
+				continue
+			}
+			client, err := httppushrepo.NewWebhookClient(cfg.PushWebhookURL, cfg.PushTimeout)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, client)
+		default:
+			return nil, fmt.Errorf("unknown push provider %q", name)
+		}
+	}
+	return providers, nil
+}
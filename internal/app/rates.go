@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"family-app-go/internal/config"
+	ratesdomain "family-app-go/internal/domain/rates"
+	httpratesrepo "family-app-go/internal/repository/http/rates"
+	"family-app-go/pkg/logger"
+)
+
+func buildReferenceRateProviders(cfg config.RatesConfig, log logger.Logger) ([]ratesdomain.ReferenceProvider, error) {
+	var providers []ratesdomain.ReferenceProvider
+	for _, name := range strings.Split(cfg.ReferenceProviders, ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "":
+		case "ecb":
+			client, err := httpratesrepo.NewECBClient(cfg.ECBBaseURL, cfg.HTTPTimeout)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, client)
+		case "exchangerate_host":
+			if strings.TrimSpace(cfg.ExchangeRateHostAccessKey) == "" {
+				log.Warn("app: exchangerate.host access key is empty, skipping reference rate provider")
+				continue
+			}
+			client, err := httpratesrepo.NewExchangeRateHostClient(cfg.ExchangeRateHostBaseURL, cfg.ExchangeRateHostAccessKey, cfg.HTTPTimeout)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, client)
+		default:
+			return nil, fmt.Errorf("unknown reference rate provider %q", name)
+		}
+	}
+	return providers, nil
+}
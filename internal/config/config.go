@@ -14,14 +14,78 @@ type Config struct {
 	HTTPPort           string
 	Env                string
 	OfflineSyncEnabled bool
+	ShutdownTimeout    time.Duration
+	HTTP               HTTPConfig
+	ListLimits         ListLimitsConfig
 	TopCategories      TopCategoriesConfig
+	AnalyticsRollups   AnalyticsRollupsConfig
 	Rates              RatesConfig
 	MockDataSeed       MockDataSeedConfig
 	ReceiptParser      ReceiptParserConfig
+	Jobs               JobsConfig
+	Retention          RetentionConfig
+	GymNudge           GymNudgeConfig
+	BudgetAlerts       BudgetAlertsConfig
+	Notifications      NotificationsConfig
+	Calendar           CalendarConfig
+	Dashboard          DashboardConfig
+	Polls              PollsConfig
+	Exports            ExportsConfig
+	Erasure            ErasureConfig
+	Audit              AuditConfig
+	SyncRetention      SyncRetentionConfig
+	Blob               BlobConfig
+	Backup             BackupConfig
+	Avatar             AvatarConfig
+	ProfileSync        ProfileSyncConfig
+	Redis              RedisConfig
+	Tracing            TracingConfig
+	ErrorReporting     ErrorReportingConfig
+	Health             HealthConfig
+	FeatureFlags       FeatureFlagsConfig
+	Maintenance        MaintenanceConfig
+	Usage              UsageConfig
+	GRPC               GRPCConfig
+	Admin              AdminConfig
+	Quotas             QuotasConfig
+	Encryption         EncryptionConfig
 	DB                 DBConfig
+	Auth               AuthConfig
 	Supabase           SupabaseConfig
 }
 
+// HTTPConfig limits request bodies and sets response compression. Uploads
+// still enforce their own per-file limits below UploadMaxBodyBytes.
+type HTTPConfig struct {
+	MaxBodyBytes       int64
+	SyncMaxBodyBytes   int64
+	UploadMaxBodyBytes int64
+	// CompressionLevel is the gzip/deflate level; 0 disables compression.
+	CompressionLevel int
+	// DebugLogRoutes are path prefixes whose requests and responses are
+	// logged with sensitive fields redacted. Requests sending DebugLogToken
+	// in X-Debug-Log are logged on any route.
+	DebugLogRoutes       []string
+	DebugLogToken        string
+	DebugLogMaxBodyBytes int
+}
+
+// ListLimitsConfig sets the page size of list endpoints: the limit used when
+// a request sends none, and the most rows a page returns. Larger limits are
+// clamped to the max.
+type ListLimitsConfig struct {
+	Expenses     ListLimitConfig
+	TodoLists    ListLimitConfig
+	GymEntries   ListLimitConfig
+	Workouts     ListLimitConfig
+	AnalyticsTop ListLimitConfig
+}
+
+type ListLimitConfig struct {
+	Default int
+	Max     int
+}
+
 type ReceiptParserConfig struct {
 	FileStorageDir        string
 	Enabled               bool
@@ -34,6 +98,185 @@ type ReceiptParserConfig struct {
 	HintNormalizerModel   string
 }
 
+// JobsConfig tunes the background job runner. With Enabled false nothing
+// runs on a schedule, but operators can still trigger jobs.
+type JobsConfig struct {
+	Enabled      bool
+	Timeout      time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+type RetentionConfig struct {
+	JobEnabled   bool
+	RunInterval  time.Duration
+	PollInterval time.Duration
+}
+
+type GymNudgeConfig struct {
+	JobEnabled   bool
+	BeforeWeek   time.Duration
+	PollInterval time.Duration
+}
+
+type BudgetAlertsConfig struct {
+	JobEnabled   bool
+	PollInterval time.Duration
+}
+
+type NotificationsConfig struct {
+	// PushProviders lists the push providers, comma separated, that every
+	// notification is sent to besides the inbox.
+	PushProviders  string
+	PushWebhookURL string
+	PushTimeout    time.Duration
+}
+
+type CalendarConfig struct {
+	FeedSecret string
+}
+
+// DashboardConfig bounds how long each dashboard section may take before it
+// is left out of the response.
+type DashboardConfig struct {
+	SectionTimeout time.Duration
+}
+
+// PollsConfig sets how often polls past their deadline are closed and
+// announced.
+type PollsConfig struct {
+	CloseInterval time.Duration
+}
+
+// ExportsConfig drives family data exports. An empty SigningSecret disables
+// them.
+type ExportsConfig struct {
+	SigningSecret string
+	StorageDir    string
+	TTL           time.Duration
+	PollInterval  time.Duration
+}
+
+// ErasureConfig sets how long account and family deletions wait before the
+// purge job hard-deletes them.
+type ErasureConfig struct {
+	GracePeriod  time.Duration
+	PollInterval time.Duration
+}
+
+// AuditConfig sets how long security audit events are kept and how often the
+// purge job removes older ones.
+type AuditConfig struct {
+	RetentionDays int
+	PurgeInterval time.Duration
+}
+
+// SyncRetentionConfig sets how long sync operations and batches are kept
+// for deduplication and how often the purge job removes older ones.
+type SyncRetentionConfig struct {
+	Days          int
+	PurgeInterval time.Duration
+}
+
+// BlobConfig locates the blob store backups are written to.
+type BlobConfig struct {
+	StorageDir string
+}
+
+// BackupConfig schedules database backups. With Enabled false the backup job
+// only runs when an operator triggers it.
+type BackupConfig struct {
+	Enabled   bool
+	Schedule  string
+	Retention time.Duration
+	KeepLast  int
+}
+
+type TracingConfig struct {
+	Enabled     bool
+	ServiceName string
+	Endpoint    string
+	SampleRatio float64
+}
+
+// ErrorReportingConfig sends internal errors and panics to Sentry while
+// SentryDSN is set.
+type ErrorReportingConfig struct {
+	SentryDSN  string
+	Release    string
+	SampleRate float64
+}
+
+// FeatureFlagsConfig sets how long evaluated flags are cached per instance.
+// Admin changes apply at once on the instance that made them.
+type FeatureFlagsConfig struct {
+	CacheTTL time.Duration
+}
+
+// MaintenanceConfig sets the Retry-After sent while a route group is in
+// maintenance. Groups are put into maintenance with the maintenance_* flags.
+type MaintenanceConfig struct {
+	RetryAfter time.Duration
+}
+
+// UsageConfig turns on per-family API usage counters. Each instance flushes
+// its counters every FlushInterval; counts older than RetentionDays are
+// purged.
+type UsageConfig struct {
+	Enabled       bool
+	FlushInterval time.Duration
+	RetentionDays int
+}
+
+type HealthConfig struct {
+	CheckTimeout time.Duration
+}
+
+type GRPCConfig struct {
+	Enabled bool
+	Port    string
+}
+
+// AdminConfig guards the operator API under /api/admin. An empty Token
+// disables it.
+type AdminConfig struct {
+	Token               string
+	StuckSyncBatchAfter time.Duration
+}
+
+// EncryptionConfig holds the keys sensitive columns are encrypted with at
+// rest, as comma-separated id:base64 pairs with the primary key first. Empty
+// leaves them in plaintext.
+type EncryptionConfig struct {
+	Keys string
+}
+
+// QuotasConfig caps what one family may write, so a runaway client cannot
+// flood a shared instance. Zero leaves a quota unlimited.
+type QuotasConfig struct {
+	ExpensesPerDay        int
+	TodoItemsPerList      int
+	TodoListsPerFamily    int
+	SyncOperationsPerHour int
+}
+
+type RedisConfig struct {
+	URL string
+}
+
+type AvatarConfig struct {
+	StorageDir string
+}
+
+// ProfileSyncConfig drives the profile_sync job. Profiles are only refreshed
+// from Supabase when SupabaseConfig.ServiceRoleKey is set; queued snapshot
+// patches run either way.
+type ProfileSyncConfig struct {
+	Interval   time.Duration
+	BatchSize  int
+	StaleAfter time.Duration
+}
+
 type MockDataSeedConfig struct {
 	Enabled          bool
 	LookbackMonths   int
@@ -52,16 +295,38 @@ type TopCategoriesConfig struct {
 	CacheTTL      time.Duration
 }
 
+// AnalyticsRollupsConfig controls the daily expense rollups. The jobs keep
+// them current even while Enabled is off, so reads can be switched over at
+// any time.
+type AnalyticsRollupsConfig struct {
+	// Enabled makes analytics read the rollups instead of scanning expenses.
+	Enabled         bool
+	RefreshInterval time.Duration
+	RebuildSchedule string
+	RebuildDays     int
+}
+
 type RatesConfig struct {
 	NBRBBaseURL        string
 	HTTPTimeout        time.Duration
 	RateCacheTTL       time.Duration
 	CurrenciesCacheTTL time.Duration
 	FallbackDays       int
+	// ReferenceProviders lists the daily euro reference rate providers,
+	// comma separated, in the order they are tried.
+	ReferenceProviders        string
+	ECBBaseURL                string
+	ExchangeRateHostBaseURL   string
+	ExchangeRateHostAccessKey string
+	RefreshEnabled            bool
+	RefreshSchedule           string
 }
 
+// DBConfig describes the primary database. ReplicaDSN, when set, serves
+// read-only list and analytics queries.
 type DBConfig struct {
 	DSN             string
+	ReplicaDSN      string
 	Host            string
 	Port            string
 	User            string
@@ -72,12 +337,46 @@ type DBConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	// ConnMaxIdleTime closes connections idle this long, so a pool grown by
+	// a load spike shrinks back.
+	ConnMaxIdleTime time.Duration
+	// StatementTimeout is PostgreSQL's statement_timeout for every session.
+	// It backs up QueryTimeout for statements the client stops waiting on;
+	// zero leaves the server default. Migrations run without it.
+	StatementTimeout time.Duration
+	// QueryTimeout bounds each statement, so a stuck database fails requests
+	// with 503 instead of holding them; zero disables it.
+	QueryTimeout time.Duration
+	// SlowQueryThreshold logs statements that take at least this long; zero
+	// disables it.
+	SlowQueryThreshold time.Duration
+	// SlowQueryExplainRate is the share of slow statements, from 0 to 1,
+	// logged with their EXPLAIN plan.
+	SlowQueryExplainRate float64
+	// RequestBudget is the database time one HTTP request may use before its
+	// log line is flagged; zero disables the flag.
+	RequestBudget time.Duration
+}
+
+// AuthConfig selects who issues access tokens: "supabase" or "local" for
+// self-hosted email/password accounts.
+type AuthConfig struct {
+	Provider        string
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 }
 
 type SupabaseConfig struct {
 	URL            string
 	PublishableKey string
+	// ServiceRoleKey lets the server read users through the Auth admin API.
+	ServiceRoleKey string
 	AuthTimeout    time.Duration
+	JWKSEnabled    bool
+	JWKSCacheTTL   time.Duration
+	CacheBackend   string
+	CacheTTL       time.Duration
 	SkipAuth       bool
 	MockUserID     string
 	MockUserEmail  string
@@ -97,6 +396,23 @@ func Load(log logger.Logger) (Config, error) {
 		HTTPPort:           getEnv("HTTP_PORT", "8080"),
 		Env:                env,
 		OfflineSyncEnabled: getEnvBool("OFFLINE_SYNC_ENABLED", true),
+		ShutdownTimeout:    getEnvDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		HTTP: HTTPConfig{
+			MaxBodyBytes:         int64(getEnvInt("HTTP_MAX_BODY_BYTES", 1<<20)),
+			SyncMaxBodyBytes:     int64(getEnvInt("HTTP_SYNC_MAX_BODY_BYTES", 4<<20)),
+			UploadMaxBodyBytes:   int64(getEnvInt("HTTP_UPLOAD_MAX_BODY_BYTES", 50<<20)),
+			CompressionLevel:     getEnvInt("HTTP_COMPRESSION_LEVEL", 5),
+			DebugLogRoutes:       getEnvList("HTTP_DEBUG_LOG_ROUTES"),
+			DebugLogToken:        getEnv("HTTP_DEBUG_LOG_TOKEN", ""),
+			DebugLogMaxBodyBytes: getEnvInt("HTTP_DEBUG_LOG_MAX_BODY_BYTES", 16<<10),
+		},
+		ListLimits: ListLimitsConfig{
+			Expenses:     getEnvListLimit("LIST_LIMIT_EXPENSES", 50, 200),
+			TodoLists:    getEnvListLimit("LIST_LIMIT_TODO_LISTS", 50, 200),
+			GymEntries:   getEnvListLimit("LIST_LIMIT_GYM_ENTRIES", 100, 500),
+			Workouts:     getEnvListLimit("LIST_LIMIT_WORKOUTS", 100, 500),
+			AnalyticsTop: getEnvListLimit("LIST_LIMIT_ANALYTICS_TOP", 20, 100),
+		},
 		TopCategories: TopCategoriesConfig{
 			Enabled:       getEnvBool("TOP_CATEGORIES_ENABLED", true),
 			LookbackDays:  getEnvInt("TOP_CATEGORIES_LOOKBACK_DAYS", 30),
@@ -105,12 +421,24 @@ func Load(log logger.Logger) (Config, error) {
 			ResponseCount: getEnvInt("TOP_CATEGORIES_RESPONSE_COUNT", 5),
 			CacheTTL:      getEnvDuration("TOP_CATEGORIES_CACHE_TTL", time.Minute),
 		},
+		AnalyticsRollups: AnalyticsRollupsConfig{
+			Enabled:         getEnvBool("ANALYTICS_ROLLUPS_ENABLED", true),
+			RefreshInterval: getEnvDuration("ANALYTICS_ROLLUPS_REFRESH_INTERVAL", 5*time.Minute),
+			RebuildSchedule: getEnv("ANALYTICS_ROLLUPS_REBUILD_SCHEDULE", "15 0 * * *"),
+			RebuildDays:     getEnvInt("ANALYTICS_ROLLUPS_REBUILD_DAYS", 7),
+		},
 		Rates: RatesConfig{
-			NBRBBaseURL:        getEnv("RATES_NBRB_BASE_URL", "https://api.nbrb.by"),
-			HTTPTimeout:        getEnvDuration("RATES_HTTP_TIMEOUT", 5*time.Second),
-			RateCacheTTL:       getEnvDuration("RATES_CACHE_TTL", 12*time.Hour),
-			CurrenciesCacheTTL: getEnvDuration("RATES_CURRENCIES_CACHE_TTL", 24*time.Hour),
-			FallbackDays:       getEnvInt("RATES_FALLBACK_DAYS", 7),
+			NBRBBaseURL:               getEnv("RATES_NBRB_BASE_URL", "https://api.nbrb.by"),
+			HTTPTimeout:               getEnvDuration("RATES_HTTP_TIMEOUT", 5*time.Second),
+			RateCacheTTL:              getEnvDuration("RATES_CACHE_TTL", 12*time.Hour),
+			CurrenciesCacheTTL:        getEnvDuration("RATES_CURRENCIES_CACHE_TTL", 24*time.Hour),
+			FallbackDays:              getEnvInt("RATES_FALLBACK_DAYS", 7),
+			ReferenceProviders:        getEnv("RATES_REFERENCE_PROVIDERS", "ecb"),
+			ECBBaseURL:                getEnv("RATES_ECB_BASE_URL", "https://www.ecb.europa.eu"),
+			ExchangeRateHostBaseURL:   getEnv("RATES_EXCHANGERATE_HOST_BASE_URL", "https://api.exchangerate.host"),
+			ExchangeRateHostAccessKey: getEnv("RATES_EXCHANGERATE_HOST_ACCESS_KEY", ""),
+			RefreshEnabled:            getEnvBool("RATES_REFRESH_ENABLED", true),
+			RefreshSchedule:           getEnv("RATES_REFRESH_SCHEDULE", "30 16 * * *"),
 		},
 		MockDataSeed: MockDataSeedConfig{
 			Enabled:          getEnvBool("MOCK_DATA_SEED_ENABLED", strings.EqualFold(env, "development")),
@@ -131,23 +459,155 @@ func Load(log logger.Logger) (Config, error) {
 			HintNormalizerEnabled: getEnvBool("RECEIPT_HINT_NORMALIZER_ENABLED", getEnvBool("RECEIPT_PARSER_ENABLED", false)),
 			HintNormalizerModel:   getEnv("RECEIPT_HINT_NORMALIZER_MODEL", "gpt-5.4-nano"),
 		},
+		Jobs: JobsConfig{
+			Enabled:      getEnvBool("JOBS_ENABLED", true),
+			Timeout:      getEnvDuration("JOBS_TIMEOUT", 30*time.Minute),
+			MaxAttempts:  getEnvInt("JOBS_MAX_ATTEMPTS", 3),
+			RetryBackoff: getEnvDuration("JOBS_RETRY_BACKOFF", 30*time.Second),
+		},
+		Retention: RetentionConfig{
+			JobEnabled:   getEnvBool("RETENTION_JOB_ENABLED", true),
+			RunInterval:  getEnvDuration("RETENTION_RUN_INTERVAL", 24*time.Hour),
+			PollInterval: getEnvDuration("RETENTION_POLL_INTERVAL", time.Hour),
+		},
+		GymNudge: GymNudgeConfig{
+			JobEnabled:   getEnvBool("GYM_NUDGE_JOB_ENABLED", true),
+			BeforeWeek:   getEnvDuration("GYM_NUDGE_BEFORE_WEEK_END", 36*time.Hour),
+			PollInterval: getEnvDuration("GYM_NUDGE_POLL_INTERVAL", time.Hour),
+		},
+		BudgetAlerts: BudgetAlertsConfig{
+			JobEnabled:   getEnvBool("BUDGET_ALERTS_JOB_ENABLED", true),
+			PollInterval: getEnvDuration("BUDGET_ALERTS_POLL_INTERVAL", time.Hour),
+		},
+		Notifications: NotificationsConfig{
+			PushProviders:  getEnv("NOTIFICATIONS_PUSH_PROVIDERS", ""),
+			PushWebhookURL: getEnv("NOTIFICATIONS_PUSH_WEBHOOK_URL", ""),
+			PushTimeout:    getEnvDuration("NOTIFICATIONS_PUSH_TIMEOUT", 5*time.Second),
+		},
+		Calendar: CalendarConfig{
+			FeedSecret: getEnv("CALENDAR_FEED_SECRET", ""),
+		},
+		Dashboard: DashboardConfig{
+			SectionTimeout: getEnvDuration("DASHBOARD_SECTION_TIMEOUT", 2*time.Second),
+		},
+		Polls: PollsConfig{
+			CloseInterval: getEnvDuration("POLLS_CLOSE_INTERVAL", time.Minute),
+		},
+		Exports: ExportsConfig{
+			SigningSecret: getEnv("EXPORT_SIGNING_SECRET", ""),
+			StorageDir:    getEnv("EXPORT_STORAGE_DIR", "data/exports"),
+			TTL:           getEnvDuration("EXPORT_TTL", 7*24*time.Hour),
+			PollInterval:  getEnvDuration("EXPORT_POLL_INTERVAL", time.Minute),
+		},
+		Erasure: ErasureConfig{
+			GracePeriod:  getEnvDuration("ERASURE_GRACE_PERIOD", 30*24*time.Hour),
+			PollInterval: getEnvDuration("ERASURE_POLL_INTERVAL", time.Hour),
+		},
+		Audit: AuditConfig{
+			RetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 365),
+			PurgeInterval: getEnvDuration("AUDIT_PURGE_INTERVAL", 24*time.Hour),
+		},
+		SyncRetention: SyncRetentionConfig{
+			Days:          getEnvInt("SYNC_RETENTION_DAYS", 90),
+			PurgeInterval: getEnvDuration("SYNC_PURGE_INTERVAL", 24*time.Hour),
+		},
+		Blob: BlobConfig{
+			StorageDir: getEnv("BLOB_STORAGE_DIR", "data/blobs"),
+		},
+		Backup: BackupConfig{
+			Enabled:   getEnvBool("BACKUP_ENABLED", false),
+			Schedule:  getEnv("BACKUP_SCHEDULE", "@daily"),
+			Retention: getEnvDuration("BACKUP_RETENTION", 30*24*time.Hour),
+			KeepLast:  getEnvInt("BACKUP_KEEP_LAST", 3),
+		},
+		Avatar: AvatarConfig{
+			StorageDir: getEnv("AVATAR_STORAGE_DIR", "data/avatars"),
+		},
+		ProfileSync: ProfileSyncConfig{
+			Interval:   getEnvDuration("PROFILE_SYNC_INTERVAL", 15*time.Minute),
+			BatchSize:  getEnvInt("PROFILE_SYNC_BATCH_SIZE", 100),
+			StaleAfter: getEnvDuration("PROFILE_SYNC_STALE_AFTER", 24*time.Hour),
+		},
+		Tracing: TracingConfig{
+			Enabled:     getEnvBool("TRACING_ENABLED", false),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "family-app-go"),
+			Endpoint:    getEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")),
+			SampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		ErrorReporting: ErrorReportingConfig{
+			SentryDSN:  getEnv("SENTRY_DSN", ""),
+			Release:    getEnv("SENTRY_RELEASE", ""),
+			SampleRate: getEnvFloat("SENTRY_SAMPLE_RATE", 1),
+		},
+		Health: HealthConfig{
+			CheckTimeout: getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		},
+		FeatureFlags: FeatureFlagsConfig{
+			CacheTTL: getEnvDuration("FEATURE_FLAGS_CACHE_TTL", 30*time.Second),
+		},
+		Maintenance: MaintenanceConfig{
+			RetryAfter: getEnvDuration("MAINTENANCE_RETRY_AFTER", 5*time.Minute),
+		},
+		Usage: UsageConfig{
+			Enabled:       getEnvBool("USAGE_ANALYTICS_ENABLED", false),
+			FlushInterval: getEnvDuration("USAGE_FLUSH_INTERVAL", time.Minute),
+			RetentionDays: getEnvInt("USAGE_RETENTION_DAYS", 400),
+		},
+		GRPC: GRPCConfig{
+			Enabled: getEnvBool("GRPC_ENABLED", false),
+			Port:    getEnv("GRPC_PORT", "9090"),
+		},
+		Admin: AdminConfig{
+			Token:               getEnv("ADMIN_TOKEN", ""),
+			StuckSyncBatchAfter: getEnvDuration("ADMIN_STUCK_SYNC_BATCH_AFTER", 10*time.Minute),
+		},
+		Quotas: QuotasConfig{
+			ExpensesPerDay:        getEnvInt("QUOTA_EXPENSES_PER_DAY", 0),
+			TodoItemsPerList:      getEnvInt("QUOTA_TODO_ITEMS_PER_LIST", 0),
+			TodoListsPerFamily:    getEnvInt("QUOTA_TODO_LISTS_PER_FAMILY", 0),
+			SyncOperationsPerHour: getEnvInt("QUOTA_SYNC_OPERATIONS_PER_HOUR", 0),
+		},
+		Encryption: EncryptionConfig{
+			Keys: getEnv("FIELD_ENCRYPTION_KEYS", ""),
+		},
+		Redis: RedisConfig{
+			URL: getEnv("REDIS_URL", ""),
+		},
 		DB: DBConfig{
-			DSN:             getEnv("DB_DSN", ""),
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnv("DB_PORT", "5432"),
-			User:            getEnv("DB_USER", "postgres"),
-			Password:        getEnv("DB_PASSWORD", "postgres"),
-			Name:            getEnv("DB_NAME", "family_app"),
-			SSLMode:         getEnv("DB_SSLMODE", "disable"),
-			TimeZone:        getEnv("DB_TIMEZONE", "UTC"),
-			MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 10),
-			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
-			ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			DSN:                  getEnv("DB_DSN", ""),
+			ReplicaDSN:           getEnv("DB_REPLICA_DSN", ""),
+			Host:                 getEnv("DB_HOST", "localhost"),
+			Port:                 getEnv("DB_PORT", "5432"),
+			User:                 getEnv("DB_USER", "postgres"),
+			Password:             getEnv("DB_PASSWORD", "postgres"),
+			Name:                 getEnv("DB_NAME", "family_app"),
+			SSLMode:              getEnv("DB_SSLMODE", "disable"),
+			TimeZone:             getEnv("DB_TIMEZONE", "UTC"),
+			MaxOpenConns:         getEnvInt("DB_MAX_OPEN_CONNS", 10),
+			MaxIdleConns:         getEnvInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime:      getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime:      getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			StatementTimeout:     getEnvDuration("DB_STATEMENT_TIMEOUT", 5*time.Minute),
+			QueryTimeout:         getEnvDuration("DB_QUERY_TIMEOUT", 10*time.Second),
+			SlowQueryThreshold:   getEnvDuration("DB_SLOW_QUERY_THRESHOLD", time.Second),
+			SlowQueryExplainRate: getEnvFloat("DB_SLOW_QUERY_EXPLAIN_RATE", 0.1),
+			RequestBudget:        getEnvDuration("DB_REQUEST_BUDGET", 500*time.Millisecond),
+		},
+		Auth: AuthConfig{
+			Provider:        strings.ToLower(getEnv("AUTH_PROVIDER", "supabase")),
+			JWTSecret:       getEnv("AUTH_JWT_SECRET", ""),
+			AccessTokenTTL:  getEnvDuration("AUTH_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL: getEnvDuration("AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour),
 		},
 		Supabase: SupabaseConfig{
 			URL:            getEnv("SUPABASE_URL", ""),
 			PublishableKey: getEnv("SUPABASE_PUBLISHABLE_KEY", getEnv("VITE_SUPABASE_PUBLISHABLE_KEY", "")),
+			ServiceRoleKey: getEnv("SUPABASE_SERVICE_ROLE_KEY", ""),
 			AuthTimeout:    getEnvDuration("SUPABASE_AUTH_TIMEOUT", 5*time.Second),
+			JWKSEnabled:    getEnvBool("SUPABASE_JWKS_ENABLED", true),
+			JWKSCacheTTL:   getEnvDuration("SUPABASE_JWKS_CACHE_TTL", 10*time.Minute),
+			CacheBackend:   strings.ToLower(getEnv("AUTH_CACHE_BACKEND", "memory")),
+			CacheTTL:       getEnvDuration("AUTH_CACHE_TTL", time.Minute),
 			SkipAuth:       getEnvBool("AUTH_SKIP", false),
 			MockUserID:     getEnv("AUTH_MOCK_USER_ID", "00000000-0000-0000-0000-000000000001"),
 			MockUserEmail:  getEnv("AUTH_MOCK_USER_EMAIL", ""),
@@ -176,6 +636,18 @@ func getEnvInt(key string, fallback int) int {
 	return parsed
 }
 
+func getEnvFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
 func getEnvDuration(key string, fallback time.Duration) time.Duration {
 	value := os.Getenv(key)
 	if value == "" {
@@ -188,6 +660,17 @@ func getEnvDuration(key string, fallback time.Duration) time.Duration {
 	return parsed
 }
 
+// getEnvList splits a comma-separated value, dropping empty entries.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvBool(key string, fallback bool) bool {
 	value := os.Getenv(key)
 	if value == "" {
@@ -212,3 +695,22 @@ func (c DBConfig) GetDSN() string {
 		" sslmode=" + c.SSLMode +
 		" TimeZone=" + c.TimeZone
 }
+
+// getEnvListLimit reads <prefix>_DEFAULT and <prefix>_MAX. Non-positive
+// values fall back, and a default above the max is lowered to it.
+func getEnvListLimit(prefix string, fallbackDefault, fallbackMax int) ListLimitConfig {
+	limit := ListLimitConfig{
+		Default: getEnvInt(prefix+"_DEFAULT", fallbackDefault),
+		Max:     getEnvInt(prefix+"_MAX", fallbackMax),
+	}
+	if limit.Max <= 0 {
+		limit.Max = fallbackMax
+	}
+	if limit.Default <= 0 {
+		limit.Default = fallbackDefault
+	}
+	if limit.Default > limit.Max {
+		limit.Default = limit.Max
+	}
+	return limit
+}
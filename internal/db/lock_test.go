@@ -0,0 +1,48 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The keys are pinned: instances running different releases must derive
+// the same lock for the same resource.
+func TestLockKeys(t *testing.T) {
+	cases := []struct {
+		scope string
+		key   string
+		high  int32
+		low   int32
+	}{
+		{LockTodoListOrder, "11111111-1111-1111-1111-111111111111", -1506031679, -1507119583},
+		{LockTodoListOrder, "22222222-2222-2222-2222-222222222222", -74763442, 42377585},
+		{LockTodoListOrder, "", -1977667383, -1637946203},
+		{LockTodoListItems, "11111111-1111-1111-1111-111111111111", 1256451607, -1095150943},
+		{LockTodoListItems, "22222222-2222-2222-2222-222222222222", -1607247452, 454346225},
+		{LockShoppingList, "11111111-1111-1111-1111-111111111111", 552899143, 542295128},
+		{LockShoppingList, "22222222-2222-2222-2222-222222222222", -1229079524, -1483163704},
+		{"other", "11111111-1111-1111-1111-111111111111", 1357441041, -1275725019},
+	}
+	for _, tc := range cases {
+		t.Run(tc.scope+"/"+tc.key, func(t *testing.T) {
+			high, low := LockKeys(tc.scope, tc.key)
+			if high != tc.high || low != tc.low {
+				t.Fatalf("expected (%d, %d), got (%d, %d)", tc.high, tc.low, high, low)
+			}
+		})
+	}
+}
+
+func TestLockKeysDoNotCollide(t *testing.T) {
+	seen := make(map[[2]int32]string)
+	for _, scope := range []string{LockTodoListOrder, LockTodoListItems, LockShoppingList, "other"} {
+		for i := 0; i < 10000; i++ {
+			name := fmt.Sprintf("%s:family-%d", scope, i)
+			high, low := LockKeys(scope, fmt.Sprintf("family-%d", i))
+			if previous, ok := seen[[2]int32{high, low}]; ok {
+				t.Fatalf("%s and %s share lock keys (%d, %d)", previous, name, high, low)
+			}
+			seen[[2]int32{high, low}] = name
+		}
+	}
+}
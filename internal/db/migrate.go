@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -23,28 +24,28 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	// Migrations may rewrite or index large tables, so DB_STATEMENT_TIMEOUT
+	// does not apply to them. One connection runs them all, and the setting
+	// goes back to the session default before it returns to the pool.
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SET statement_timeout = 0").Error; err != nil {
+			return fmt.Errorf("disable statement timeout: %w", err)
+		}
+		defer conn.Exec("RESET statement_timeout")
+		return applyMigrations(conn, path)
+	})
+}
+
+func applyMigrations(db *gorm.DB, path string) error {
 	if err := ensureSchemaMigrations(db); err != nil {
 		return err
 	}
 
-	entries, err := os.ReadDir(path)
+	files, err := listMigrations(path)
 	if err != nil {
 		return err
 	}
 
-	var files []string
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if strings.HasSuffix(name, ".sql") {
-			files = append(files, name)
-		}
-	}
-
-	sort.Strings(files)
-
 	for _, name := range files {
 		applied, err := isMigrationApplied(db, name)
 		if err != nil {
@@ -76,6 +77,69 @@ func Migrate(db *gorm.DB) error {
 	return nil
 }
 
+// PendingMigrations lists migration files that are not recorded in
+// schema_migrations. Empty files are never applied and are not reported.
+func PendingMigrations(ctx context.Context, db *gorm.DB) ([]string, error) {
+	path, err := findMigrationsDir(migrationsDirName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	files, err := listMigrations(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []string
+	if err := db.WithContext(ctx).Raw("SELECT filename FROM schema_migrations").Scan(&applied).Error; err != nil {
+		return nil, err
+	}
+	appliedSet := make(map[string]struct{}, len(applied))
+	for _, name := range applied {
+		appliedSet[name] = struct{}{}
+	}
+
+	pending := []string{}
+	for _, name := range files {
+		if _, ok := appliedSet[name]; ok {
+			continue
+		}
+		contents, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(contents)) == "" {
+			continue
+		}
+		pending = append(pending, name)
+	}
+	return pending, nil
+}
+
+func listMigrations(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		if strings.HasSuffix(name, ".sql") {
+			files = append(files, name)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
 func ensureSchemaMigrations(db *gorm.DB) error {
 	return db.Exec(`
 		CREATE TABLE IF NOT EXISTS schema_migrations (
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"database/sql"
+	"expvar"
+	"sync/atomic"
+)
+
+// primaryPool is the connection pool reported as db_pool with the other
+// expvars at GET /api/admin/debug/vars.
+var primaryPool atomic.Pointer[sql.DB]
+
+func init() {
+	expvar.Publish("db_pool", expvar.Func(poolStats))
+}
+
+// registerPoolStats makes pool the one db_pool reports; the last pool
+// opened wins.
+func registerPoolStats(pool *sql.DB) {
+	primaryPool.Store(pool)
+}
+
+func poolStats() any {
+	pool := primaryPool.Load()
+	if pool == nil {
+		return nil
+	}
+	stats := pool.Stats()
+	return map[string]int64{
+		"max_open":             int64(stats.MaxOpenConnections),
+		"open":                 int64(stats.OpenConnections),
+		"in_use":               int64(stats.InUse),
+		"idle":                 int64(stats.Idle),
+		"wait_count":           stats.WaitCount,
+		"wait_ms":              stats.WaitDuration.Milliseconds(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	}
+}
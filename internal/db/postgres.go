@@ -3,7 +3,10 @@ package db
 import (
 	"fmt"
 	stdlog "log"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"family-app-go/internal/config"
@@ -17,6 +20,7 @@ const (
 	defaultMaxOpenConns    = 10
 	defaultMaxIdleConns    = 5
 	defaultConnMaxLifetime = 30 * time.Minute
+	defaultConnMaxIdleTime = 5 * time.Minute
 )
 
 func NewPostgres(log logger.Logger, cfg config.DBConfig) (*gorm.DB, error) {
@@ -36,10 +40,12 @@ func NewPostgres(log logger.Logger, cfg config.DBConfig) (*gorm.DB, error) {
 		)
 	}
 
-	dsn := cfg.GetDSN()
+	dsn := withStatementTimeout(cfg.GetDSN(), cfg.StatementTimeout)
 	gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: gormlogger.New(stdlog.New(os.Stdout, "\r\n", stdlog.LstdFlags), gormlogger.Config{
-			SlowThreshold:             time.Second,
+			// Slow statements are logged by the QueryLog plugin with the
+			// request's logger instead.
+			SlowThreshold:             0,
 			LogLevel:                  gormlogger.Warn,
 			IgnoreRecordNotFoundError: true,
 			Colorful:                  false,
@@ -66,15 +72,61 @@ func NewPostgres(log logger.Logger, cfg config.DBConfig) (*gorm.DB, error) {
 	if connMaxLifetime == 0 {
 		connMaxLifetime = defaultConnMaxLifetime
 	}
+	connMaxIdleTime := cfg.ConnMaxIdleTime
+	if connMaxIdleTime == 0 {
+		connMaxIdleTime = defaultConnMaxIdleTime
+	}
 
 	sqlDB.SetMaxOpenConns(maxOpen)
 	sqlDB.SetMaxIdleConns(maxIdle)
 	sqlDB.SetConnMaxLifetime(connMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(connMaxIdleTime)
+	registerPoolStats(sqlDB)
 
 	if err := sqlDB.Ping(); err != nil {
 		return nil, fmt.Errorf("db ping: %w", err)
 	}
 
+	if err := RegisterQueryTimeouts(gormDB, cfg.QueryTimeout); err != nil {
+		return nil, fmt.Errorf("register query timeouts: %w", err)
+	}
+	queryLog := NewQueryLog(QueryLogOptions{
+		SlowThreshold:     cfg.SlowQueryThreshold,
+		ExplainSampleRate: cfg.SlowQueryExplainRate,
+	}, log)
+	if err := gormDB.Use(queryLog); err != nil {
+		return nil, fmt.Errorf("register query log: %w", err)
+	}
+
+	if cfg.ReplicaDSN != "" {
+		log.Info("db: routing read-only queries to replica")
+		replicaDSN := withStatementTimeout(cfg.ReplicaDSN, cfg.StatementTimeout)
+		if err := registerReplica(gormDB, replicaDSN, maxOpen, maxIdle, connMaxLifetime, connMaxIdleTime); err != nil {
+			return nil, err
+		}
+	}
+
 	log.Info("db: connected")
 	return gormDB, nil
 }
+
+// withStatementTimeout adds statement_timeout to a key/value or URL DSN; pgx
+// sends unknown DSN settings to the server as session parameters. A DSN
+// that already sets it is left alone.
+func withStatementTimeout(dsn string, timeout time.Duration) string {
+	if timeout <= 0 || strings.Contains(dsn, "statement_timeout") {
+		return dsn
+	}
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		query := parsed.Query()
+		query.Set("statement_timeout", ms)
+		parsed.RawQuery = query.Encode()
+		return parsed.String()
+	}
+	return dsn + " statement_timeout=" + ms
+}
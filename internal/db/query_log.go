@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"family-app-go/pkg/logger"
+	"gorm.io/gorm"
+)
+
+const (
+	queryLogStartKey = "db:query_log_start"
+	// explainTimeout bounds the EXPLAIN of a slow statement; the plan is a
+	// diagnostic and must not hold the request much longer.
+	explainTimeout = 2 * time.Second
+)
+
+// QueryLogOptions configures the QueryLog plugin.
+type QueryLogOptions struct {
+	// SlowThreshold logs statements that take at least this long; zero
+	// disables it.
+	SlowThreshold time.Duration
+	// ExplainSampleRate is the share of slow statements, from 0 to 1, logged
+	// with their EXPLAIN plan.
+	ExplainSampleRate float64
+}
+
+// QueryLog is a GORM plugin that counts and times every statement for the
+// request it runs in (see TrackQueries) and logs slow ones, a sample of them
+// with the plan PostgreSQL chose.
+type QueryLog struct {
+	options QueryLogOptions
+	log     logger.Logger
+	sample  func() float64
+}
+
+func NewQueryLog(options QueryLogOptions, log logger.Logger) *QueryLog {
+	return &QueryLog{options: options, log: log, sample: rand.Float64}
+}
+
+func (p *QueryLog) Name() string {
+	return "db:query_log"
+}
+
+func (p *QueryLog) Initialize(gormDB *gorm.DB) error {
+	cb := gormDB.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("db:query_log_before_create", startQueryLog),
+		cb.Create().After("gorm:create").Register("db:query_log_after_create", p.endQueryLog),
+		cb.Query().Before("gorm:query").Register("db:query_log_before_query", startQueryLog),
+		cb.Query().After("gorm:query").Register("db:query_log_after_query", p.endQueryLog),
+		cb.Update().Before("gorm:update").Register("db:query_log_before_update", startQueryLog),
+		cb.Update().After("gorm:update").Register("db:query_log_after_update", p.endQueryLog),
+		cb.Delete().Before("gorm:delete").Register("db:query_log_before_delete", startQueryLog),
+		cb.Delete().After("gorm:delete").Register("db:query_log_after_delete", p.endQueryLog),
+		cb.Row().Before("gorm:row").Register("db:query_log_before_row", startQueryLog),
+		cb.Row().After("gorm:row").Register("db:query_log_after_row", p.endQueryLog),
+		cb.Raw().Before("gorm:raw").Register("db:query_log_before_raw", startQueryLog),
+		cb.Raw().After("gorm:raw").Register("db:query_log_after_raw", p.endQueryLog),
+	)
+}
+
+func startQueryLog(tx *gorm.DB) {
+	if tx.Statement == nil || tx.Statement.Context == nil {
+		return
+	}
+	tx.InstanceSet(queryLogStartKey, time.Now())
+}
+
+func (p *QueryLog) endQueryLog(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(queryLogStartKey)
+	if !ok {
+		return
+	}
+	start, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start)
+
+	if counter, ok := tx.Statement.Context.Value(queryStatsKey{}).(*queryCounter); ok {
+		counter.queries.Add(1)
+		counter.nanos.Add(int64(elapsed))
+	}
+
+	if p.options.SlowThreshold <= 0 || elapsed < p.options.SlowThreshold {
+		return
+	}
+	// The statement keeps placeholders; bound values are not logged.
+	query := tx.Statement.SQL.String()
+	fields := []any{
+		"elapsed_ms", elapsed.Milliseconds(),
+		"table", tx.Statement.Table,
+		"rows", tx.Statement.RowsAffected,
+		"sql", query,
+	}
+	if p.options.ExplainSampleRate > 0 && p.sample() < p.options.ExplainSampleRate {
+		plan, err := explain(tx, query)
+		if err != nil {
+			fields = append(fields, "explain_error", err.Error())
+		} else if plan != "" {
+			fields = append(fields, "plan", plan)
+		}
+	}
+	logger.FromContext(tx.Statement.Context, p.log).Warn("db: slow query", fields...)
+}
+
+// explain asks PostgreSQL for the plan of a statement that just ran, with
+// the same bound values, on the connection it ran on. Plain EXPLAIN plans
+// without executing, so it is safe for writes too. A failed statement
+// inside a transaction is skipped: the transaction accepts nothing more.
+func explain(tx *gorm.DB, query string) (string, error) {
+	if !explainable(query) || tx.Statement.ConnPool == nil {
+		return "", nil
+	}
+	if _, inTx := tx.Statement.ConnPool.(gorm.TxCommitter); inTx && tx.Error != nil {
+		return "", nil
+	}
+
+	// The statement's own deadline may be what made it slow.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(tx.Statement.Context), explainTimeout)
+	defer cancel()
+
+	rows, err := tx.Statement.ConnPool.QueryContext(ctx, "EXPLAIN "+query, tx.Statement.Vars...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func explainable(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH", "INSERT", "UPDATE", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+type queryStatsKey struct{}
+
+type queryCounter struct {
+	queries atomic.Int64
+	nanos   atomic.Int64
+}
+
+// QueryStats is the database work of one request so far.
+type QueryStats struct {
+	Queries int64
+	Elapsed time.Duration
+}
+
+// TrackQueries returns a context whose statements, and those of contexts
+// derived from it, are counted and timed by the QueryLog plugin.
+func TrackQueries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryStatsKey{}, &queryCounter{})
+}
+
+// QueryStatsFromContext reports the statements run with a context prepared
+// by TrackQueries; false when ctx is not tracked.
+func QueryStatsFromContext(ctx context.Context) (QueryStats, bool) {
+	counter, ok := ctx.Value(queryStatsKey{}).(*queryCounter)
+	if !ok {
+		return QueryStats{}, false
+	}
+	return QueryStats{Queries: counter.queries.Load(), Elapsed: time.Duration(counter.nanos.Load())}, true
+}
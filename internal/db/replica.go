@@ -0,0 +1,37 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const replicaResolver = "replica"
+
+// ReadReplica routes a read-only query to the replica set by DB_REPLICA_DSN:
+//
+//	r.db.WithContext(ctx).Clauses(db.ReadReplica).Find(&rows)
+//
+// Without a replica, and always inside a transaction, the query stays on the
+// primary. Use it only where a few seconds of replication lag are
+// acceptable; reads that must see the caller's own writes stay unmarked.
+var ReadReplica = dbresolver.Use(replicaResolver)
+
+func registerReplica(gormDB *gorm.DB, dsn string, maxOpen, maxIdle int, connMaxLifetime, connMaxIdleTime time.Duration) error {
+	// Registered by name rather than globally, so only queries marked with
+	// ReadReplica leave the primary.
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{postgres.Open(dsn)},
+	}, replicaResolver).
+		SetMaxOpenConns(maxOpen).
+		SetMaxIdleConns(maxIdle).
+		SetConnMaxLifetime(connMaxLifetime).
+		SetConnMaxIdleTime(connMaxIdleTime)
+	if err := gormDB.Use(resolver); err != nil {
+		return fmt.Errorf("register replica: %w", err)
+	}
+	return nil
+}
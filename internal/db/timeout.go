@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	queryCancelKey = "db:query_cancel"
+	queryParentKey = "db:query_parent"
+)
+
+type timeoutTrackerKey struct{}
+
+// TrackTimeouts returns a context that records when a statement run with it,
+// or with a context derived from it, hits the per-query timeout. The returned
+// func reports whether that happened, so an HTTP request can answer 503
+// instead of 500 when the database stopped responding.
+func TrackTimeouts(ctx context.Context) (context.Context, func() bool) {
+	hit := &atomic.Bool{}
+	return context.WithValue(ctx, timeoutTrackerKey{}, hit), hit.Load
+}
+
+// RegisterQueryTimeouts bounds every create, query, update, delete and exec
+// statement by timeout; zero disables it. Rows returned by Rows() and Row()
+// are read after the statement callbacks finish, so those keep the caller's
+// context only.
+func RegisterQueryTimeouts(gormDB *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	before := startQueryTimeout(timeout)
+	cb := gormDB.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("db:before_create", before),
+		cb.Create().After("gorm:create").Register("db:after_create", endQueryTimeout),
+		cb.Query().Before("gorm:query").Register("db:before_query", before),
+		cb.Query().After("gorm:query").Register("db:after_query", endQueryTimeout),
+		cb.Update().Before("gorm:update").Register("db:before_update", before),
+		cb.Update().After("gorm:update").Register("db:after_update", endQueryTimeout),
+		cb.Delete().Before("gorm:delete").Register("db:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("db:after_delete", endQueryTimeout),
+		cb.Raw().Before("gorm:raw").Register("db:before_raw", before),
+		cb.Raw().After("gorm:raw").Register("db:after_raw", endQueryTimeout),
+	)
+}
+
+func startQueryTimeout(timeout time.Duration) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Statement == nil || tx.Statement.Context == nil {
+			return
+		}
+		parent := tx.Statement.Context
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryParentKey, parent)
+		tx.InstanceSet(queryCancelKey, cancel)
+	}
+}
+
+func endQueryTimeout(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(queryCancelKey)
+	if !ok {
+		return
+	}
+	cancel, ok := value.(context.CancelFunc)
+	if !ok {
+		return
+	}
+	markTimeout(tx)
+	cancel()
+	// Chains such as Count followed by Find reuse the statement, so the next
+	// call must not inherit the spent deadline.
+	if value, ok := tx.InstanceGet(queryParentKey); ok {
+		if parent, ok := value.(context.Context); ok {
+			tx.Statement.Context = parent
+		}
+	}
+}
+
+// markTimeout records a statement that ran into its own deadline. A caller
+// that gave up first, such as a client that went away, is not a database
+// timeout.
+func markTimeout(tx *gorm.DB) {
+	if !errors.Is(tx.Statement.Context.Err(), context.DeadlineExceeded) {
+		return
+	}
+	if value, ok := tx.InstanceGet(queryParentKey); ok {
+		if parent, ok := value.(context.Context); ok && parent.Err() != nil {
+			return
+		}
+	}
+	if hit, ok := tx.Statement.Context.Value(timeoutTrackerKey{}).(*atomic.Bool); ok {
+		hit.Store(true)
+	}
+}
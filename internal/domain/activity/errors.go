@@ -0,0 +1,8 @@
+package activity
+
+import "errors"
+
+var (
+	ErrInvalidAction = errors.New("invalid activity action")
+	ErrActorRequired = errors.New("actor is required")
+)
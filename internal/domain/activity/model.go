@@ -0,0 +1,71 @@
+package activity
+
+import "time"
+
+const (
+	ActionExpenseCreated = "expense_created"
+	ActionTodoCompleted  = "todo_completed"
+	ActionMemberJoined   = "member_joined"
+	ActionPollClosed     = "poll_closed"
+
+	EntityExpense  = "expense"
+	EntityTodoItem = "todo_item"
+	EntityMember   = "member"
+	EntityPoll     = "poll"
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Actor is a snapshot of the user who performed an action, kept as it was at
+// the time so the feed does not change when profiles do.
+type Actor struct {
+	ID        string
+	Name      string
+	Email     string
+	AvatarURL string
+}
+
+// Event is one entry of a family's activity log.
+type Event struct {
+	ID             string    `gorm:"type:uuid;primaryKey"`
+	FamilyID       string    `gorm:"type:uuid;not null"`
+	ActorID        string    `gorm:"type:uuid;not null"`
+	ActorName      string    `gorm:"not null"`
+	ActorEmail     string    `gorm:"not null"`
+	ActorAvatarURL *string   `gorm:"column:actor_avatar_url"`
+	Action         string    `gorm:"not null"`
+	EntityType     string    `gorm:"not null"`
+	EntityID       string    `gorm:"not null"`
+	EntityParentID *string   `gorm:"column:entity_parent_id"`
+	Summary        string    `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func (Event) TableName() string {
+	return "family_activity_events"
+}
+
+// Link returns the client route the event's entity can be opened at.
+func (e Event) Link() string {
+	switch e.EntityType {
+	case EntityExpense:
+		return "/expenses/" + e.EntityID
+	case EntityTodoItem:
+		if e.EntityParentID != nil {
+			return "/todo-lists/" + *e.EntityParentID + "/items/" + e.EntityID
+		}
+		return "/todo-items/" + e.EntityID
+	case EntityMember:
+		return "/family/members/" + e.EntityID
+	case EntityPoll:
+		return "/polls/" + e.EntityID
+	default:
+		return ""
+	}
+}
+
+type ListFilter struct {
+	Limit  int
+	Offset int
+}
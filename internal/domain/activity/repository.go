@@ -0,0 +1,8 @@
+package activity
+
+import "context"
+
+type Repository interface {
+	CreateEvent(ctx context.Context, event *Event) error
+	ListEvents(ctx context.Context, familyID string, filter ListFilter) ([]Event, int64, error)
+}
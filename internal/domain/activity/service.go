@@ -0,0 +1,120 @@
+package activity
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *Service) ListEvents(ctx context.Context, familyID string, filter ListFilter) ([]Event, int64, error) {
+	ctx, span := tracing.Start(ctx, "activity.ListEvents")
+	defer span.End()
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListEvents(ctx, familyID, filter)
+}
+
+func (s *Service) RecordExpenseCreated(ctx context.Context, familyID string, actor Actor, expenseID, title string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "activity.RecordExpenseCreated")
+	defer span.End()
+
+	return s.record(ctx, familyID, actor, ActionExpenseCreated, EntityExpense, expenseID, nil, title)
+}
+
+func (s *Service) RecordTodoCompleted(ctx context.Context, familyID string, actor Actor, listID, itemID, title string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "activity.RecordTodoCompleted")
+	defer span.End()
+
+	return s.record(ctx, familyID, actor, ActionTodoCompleted, EntityTodoItem, itemID, &listID, title)
+}
+
+// RecordMemberJoined logs the joining user as both the actor and the entity.
+func (s *Service) RecordMemberJoined(ctx context.Context, familyID string, actor Actor) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "activity.RecordMemberJoined")
+	defer span.End()
+
+	return s.record(ctx, familyID, actor, ActionMemberJoined, EntityMember, actor.ID, nil, actor.Name)
+}
+
+// RecordPollClosed tells the family a poll has closed; summary carries the
+// question and the outcome.
+func (s *Service) RecordPollClosed(ctx context.Context, familyID string, actor Actor, pollID, summary string) (*Event, error) {
+	ctx, span := tracing.Start(ctx, "activity.RecordPollClosed")
+	defer span.End()
+
+	return s.record(ctx, familyID, actor, ActionPollClosed, EntityPoll, pollID, nil, summary)
+}
+
+func (s *Service) record(ctx context.Context, familyID string, actor Actor, action, entityType, entityID string, parentID *string, summary string) (*Event, error) {
+	actorID := strings.TrimSpace(actor.ID)
+	if actorID == "" {
+		return nil, ErrActorRequired
+	}
+	if !isKnownAction(action) {
+		return nil, ErrInvalidAction
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	event := Event{
+		ID:             newID,
+		FamilyID:       familyID,
+		ActorID:        actorID,
+		ActorName:      strings.TrimSpace(actor.Name),
+		ActorEmail:     strings.TrimSpace(actor.Email),
+		ActorAvatarURL: optionalString(actor.AvatarURL),
+		Action:         action,
+		EntityType:     entityType,
+		EntityID:       entityID,
+		EntityParentID: parentID,
+		Summary:        strings.TrimSpace(summary),
+		CreatedAt:      s.now().UTC(),
+	}
+	if err := s.repo.CreateEvent(ctx, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func isKnownAction(action string) bool {
+	switch action {
+	case ActionExpenseCreated, ActionTodoCompleted, ActionMemberJoined, ActionPollClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
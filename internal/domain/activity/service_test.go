@@ -0,0 +1,111 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testFamilyID = "11111111-1111-1111-1111-111111111111"
+
+type fakeActivityRepo struct {
+	events     []Event
+	lastFilter ListFilter
+}
+
+func (r *fakeActivityRepo) CreateEvent(_ context.Context, event *Event) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeActivityRepo) ListEvents(_ context.Context, familyID string, filter ListFilter) ([]Event, int64, error) {
+	r.lastFilter = filter
+	result := make([]Event, 0, len(r.events))
+	for _, event := range r.events {
+		if event.FamilyID == familyID {
+			result = append(result, event)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func newTestService(repo *fakeActivityRepo) *Service {
+	service := NewService(repo)
+	service.now = func() time.Time { return time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func TestRecordTodoCompletedSnapshotsActorAndLink(t *testing.T) {
+	repo := &fakeActivityRepo{}
+	service := newTestService(repo)
+
+	event, err := service.RecordTodoCompleted(context.Background(), testFamilyID, Actor{
+		ID:    "user-1",
+		Name:  " Anna ",
+		Email: "anna@example.com",
+	}, "list-1", "item-1", "Buy milk")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	if event.Action != ActionTodoCompleted || event.EntityType != EntityTodoItem {
+		t.Fatalf("unexpected event kind: %+v", event)
+	}
+	if event.ActorName != "Anna" || event.ActorAvatarURL != nil {
+		t.Fatalf("unexpected actor snapshot: %+v", event)
+	}
+	if link := event.Link(); link != "/todo-lists/list-1/items/item-1" {
+		t.Fatalf("unexpected link: %s", link)
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("expected one stored event, got %d", len(repo.events))
+	}
+}
+
+func TestRecordMemberJoinedUsesActorAsEntity(t *testing.T) {
+	service := newTestService(&fakeActivityRepo{})
+
+	event, err := service.RecordMemberJoined(context.Background(), testFamilyID, Actor{ID: "user-2", Name: "Ivan", AvatarURL: "https://example.com/a.png"})
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if event.EntityID != "user-2" || event.Link() != "/family/members/user-2" {
+		t.Fatalf("unexpected entity: %+v", event)
+	}
+	if event.ActorAvatarURL == nil || *event.ActorAvatarURL != "https://example.com/a.png" {
+		t.Fatalf("expected avatar snapshot, got %v", event.ActorAvatarURL)
+	}
+}
+
+func TestRecordRequiresActor(t *testing.T) {
+	repo := &fakeActivityRepo{}
+	service := newTestService(repo)
+
+	_, err := service.RecordExpenseCreated(context.Background(), testFamilyID, Actor{}, "expense-1", "Groceries")
+	if !errors.Is(err, ErrActorRequired) {
+		t.Fatalf("expected ErrActorRequired, got %v", err)
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("expected no stored events, got %d", len(repo.events))
+	}
+}
+
+func TestListEventsClampsPagination(t *testing.T) {
+	repo := &fakeActivityRepo{}
+	service := newTestService(repo)
+
+	if _, _, err := service.ListEvents(context.Background(), testFamilyID, ListFilter{Limit: 0, Offset: -5}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if repo.lastFilter.Limit != DefaultLimit || repo.lastFilter.Offset != 0 {
+		t.Fatalf("unexpected filter: %+v", repo.lastFilter)
+	}
+
+	if _, _, err := service.ListEvents(context.Background(), testFamilyID, ListFilter{Limit: 500}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if repo.lastFilter.Limit != MaxLimit {
+		t.Fatalf("expected limit clamped to %d, got %d", MaxLimit, repo.lastFilter.Limit)
+	}
+}
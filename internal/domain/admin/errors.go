@@ -0,0 +1,21 @@
+package admin
+
+import (
+	"errors"
+
+	backupdomain "family-app-go/internal/domain/backup"
+	"family-app-go/internal/jobs"
+)
+
+var (
+	ErrSyncBatchNotFound      = errors.New("sync batch not found")
+	ErrSyncBatchNotProcessing = errors.New("sync batch is not processing")
+	ErrSyncBatchNotStuck      = errors.New("sync batch is not stuck")
+	ErrEncryptionDisabled     = errors.New("field encryption is disabled")
+	ErrJobNotFound            = jobs.ErrJobNotFound
+	ErrJobRunning             = jobs.ErrJobRunning
+	ErrBackupNotFound         = backupdomain.ErrBackupNotFound
+	ErrInvalidBackup          = backupdomain.ErrInvalidBackup
+	ErrInvalidBackupKey       = backupdomain.ErrInvalidBackupKey
+	ErrBackupSchemaMismatch   = backupdomain.ErrSchemaMismatch
+)
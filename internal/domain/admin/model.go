@@ -0,0 +1,198 @@
+package admin
+
+import "time"
+
+const (
+	DefaultFamiliesLimit = 50
+	MaxFamiliesLimit     = 200
+	DefaultBatchesLimit  = 50
+	MaxBatchesLimit      = 200
+)
+
+type FamilySummary struct {
+	ID              string
+	Name            string
+	OwnerID         string
+	DefaultCurrency string
+	Members         int64
+	Expenses        int64
+	TodoLists       int64
+	TodoItems       int64
+	CreatedAt       time.Time
+}
+
+type ListFamiliesFilter struct {
+	Limit  int
+	Offset int
+}
+
+type SyncBatch struct {
+	ID             string
+	FamilyID       string
+	UserID         string
+	IdempotencyKey *string
+	Status         string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	// Stuck is set on processing batches untouched for longer than the
+	// configured threshold.
+	Stuck bool
+}
+
+type SyncOperation struct {
+	ID            string
+	OperationID   string
+	OperationType string
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// SyncBatchDetail is a batch with the pending operations of its user, which
+// are what keep retries answering batch_in_progress.
+type SyncBatchDetail struct {
+	SyncBatch
+	PendingOperations []SyncOperation
+}
+
+type SyncBatchFilter struct {
+	FamilyID string
+	Status   string
+	// StuckOnly limits the list to processing batches older than the stuck
+	// threshold.
+	StuckOnly     bool
+	UpdatedBefore *time.Time
+	Limit         int
+}
+
+type ReleaseResult struct {
+	BatchID            string
+	OperationsReleased int64
+}
+
+type PurgeInput struct {
+	// DeletedBefore keeps rows soft-deleted after it. Zero purges everything
+	// soft-deleted so far.
+	DeletedBefore time.Time
+	DryRun        bool
+}
+
+type PurgeCount struct {
+	Table string
+	Rows  int64
+}
+
+type PurgeResult struct {
+	DeletedBefore time.Time
+	DryRun        bool
+	Tables        []PurgeCount
+}
+
+// EncryptedColumn is a column written through the encrypted GORM
+// serializer.
+type EncryptedColumn struct {
+	Table  string
+	Column string
+}
+
+// EncryptedColumns lists every column encrypted at rest; a column gaining
+// the serializer tag must be added here so rotation reaches it.
+var EncryptedColumns = []EncryptedColumn{
+	{Table: "expenses", Column: "title"},
+	{Table: "pets", Column: "notes"},
+	{Table: "pet_vaccinations", Column: "notes"},
+	{Table: "pet_vet_visits", Column: "notes"},
+	{Table: "receipt_parse_files", Column: "file_name"},
+	{Table: "comments", Column: "body"},
+}
+
+// ExpectedIndex is an index a frequent query relies on. Any valid index on
+// Table whose key starts with Columns serves it, whatever its name.
+type ExpectedIndex struct {
+	// Name is the index the migrations create.
+	Name    string
+	Table   string
+	Columns []string
+	// Query is what goes slow without it.
+	Query string
+}
+
+// ExpectedIndexes lists the indexes the hottest queries need; a query
+// pattern gaining one in a migration should be added here so the index
+// report watches it.
+var ExpectedIndexes = []ExpectedIndex{
+	{Name: "idx_expenses_family_date", Table: "expenses", Columns: []string{"family_id", "date"}, Query: "expense lists and analytics by date range"},
+	{Name: "idx_expenses_family_created_at", Table: "expenses", Columns: []string{"family_id", "created_at"}, Query: "expense quotas and recent expenses"},
+	{Name: "idx_todo_items_list_archived_created_at", Table: "todo_items", Columns: []string{"list_id", "is_archived", "created_at"}, Query: "todo list items by archive state, newest first"},
+	{Name: "idx_todo_lists_family_deleted_at", Table: "todo_lists", Columns: []string{"family_id", "deleted_at"}, Query: "todo lists of a family"},
+	{Name: "idx_sync_operations_family_user_operation_unique", Table: "sync_operations", Columns: []string{"family_id", "user_id", "operation_id"}, Query: "sync operation deduplication"},
+	{Name: "idx_sync_operations_family_created_at", Table: "sync_operations", Columns: []string{"family_id", "created_at"}, Query: "sync quotas"},
+	{Name: "idx_family_activity_events_family_created", Table: "family_activity_events", Columns: []string{"family_id", "created_at"}, Query: "activity feed"},
+	{Name: "idx_comments_entity", Table: "comments", Columns: []string{"family_id", "entity_type", "entity_id", "created_at"}, Query: "comment threads"},
+	{Name: "change_events_pkey", Table: "change_events", Columns: []string{"family_id", "seq"}, Query: "sync change feed replay"},
+}
+
+// Index is an index present in the database. Columns are its key columns
+// in order; expression keys appear as their expression.
+type Index struct {
+	Name    string
+	Table   string
+	Columns []string
+	Unique  bool
+	// Valid is false for an index left behind by a failed concurrent build;
+	// the planner ignores it.
+	Valid   bool
+	Partial bool
+}
+
+type IndexStatus string
+
+const (
+	IndexPresent IndexStatus = "present"
+	IndexMissing IndexStatus = "missing"
+	// IndexInvalid means the only matching indexes are invalid.
+	IndexInvalid IndexStatus = "invalid"
+)
+
+type IndexCheck struct {
+	ExpectedIndex
+	Status IndexStatus
+	// MatchedBy names the index serving the query, which may differ from
+	// Name when it was created by hand.
+	MatchedBy string
+}
+
+// IndexReport compares ExpectedIndexes with the database.
+type IndexReport struct {
+	Checks  []IndexCheck
+	Missing int
+	// Invalid lists every invalid index of the schema, expected or not.
+	Invalid []Index
+}
+
+// EncryptedValue is the stored, possibly encrypted, value of one row's
+// column.
+type EncryptedValue struct {
+	ID    string
+	Value string
+}
+
+type RotateEncryptionInput struct {
+	DryRun bool
+}
+
+type RotationCount struct {
+	Table  string
+	Column string
+	Rows   int64
+}
+
+type RotateEncryptionResult struct {
+	// KeyID is the primary key the values were re-encrypted with.
+	KeyID   string
+	DryRun  bool
+	Columns []RotationCount
+}
+
+// Job is a background task operators may trigger on demand. Run returns a
+// JSON-friendly summary of what it did.
@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	ListFamilies(ctx context.Context, filter ListFamiliesFilter) ([]FamilySummary, int64, error)
+	ListSyncBatches(ctx context.Context, filter SyncBatchFilter) ([]SyncBatch, error)
+	GetSyncBatch(ctx context.Context, batchID string) (*SyncBatch, error)
+	ListPendingSyncOperations(ctx context.Context, familyID, userID string) ([]SyncOperation, error)
+	// ReleaseSyncBatch deletes a processing batch and its user's pending
+	// operations so the client can retry. It reports whether the batch was
+	// still processing.
+	ReleaseSyncBatch(ctx context.Context, batch SyncBatch) (bool, int64, error)
+	CountSoftDeleted(ctx context.Context, deletedBefore time.Time) ([]PurgeCount, error)
+	PurgeSoftDeleted(ctx context.Context, deletedBefore time.Time) ([]PurgeCount, error)
+	// ListEncryptedValues pages through the non-null values of column in ID
+	// order, starting after afterID.
+	ListEncryptedValues(ctx context.Context, column EncryptedColumn, afterID string, limit int) ([]EncryptedValue, error)
+	// ReplaceEncryptedValue sets a row's column to newValue if it still
+	// holds oldValue, and reports whether it did.
+	ReplaceEncryptedValue(ctx context.Context, column EncryptedColumn, id, oldValue, newValue string) (bool, error)
+	// ListIndexes returns the indexes of the current schema.
+	ListIndexes(ctx context.Context) ([]Index, error)
+}
@@ -0,0 +1,374 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	backupdomain "family-app-go/internal/domain/backup"
+	syncdomain "family-app-go/internal/domain/sync"
+	"family-app-go/internal/jobs"
+	"family-app-go/pkg/fieldcrypt"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	defaultStuckSyncBatchAfter = 10 * time.Minute
+	rotationBatchSize          = 500
+)
+
+// JobRunner runs background jobs and keeps their run history.
+type JobRunner interface {
+	Jobs() []jobs.Job
+	RunNow(ctx context.Context, name string) (*jobs.Run, error)
+	ListRuns(ctx context.Context, filter jobs.RunFilter) ([]jobs.Run, error)
+}
+
+// Backups lists and restores database backups.
+type Backups interface {
+	List(ctx context.Context) ([]backupdomain.Backup, error)
+	Restore(ctx context.Context, key string) (*backupdomain.RestoreResult, error)
+}
+
+// Service backs the operator-only admin API. It works across families, so
+// callers must authenticate operators before reaching it.
+type Service struct {
+	repo       Repository
+	jobs       JobRunner
+	backups    Backups
+	keyring    *fieldcrypt.Keyring
+	stuckAfter time.Duration
+	now        func() time.Time
+}
+
+type ServiceOptions struct {
+	// Jobs is the runner whose jobs operators may list and trigger.
+	Jobs JobRunner
+	// Backups is where operators list and restore database backups.
+	Backups Backups
+	// Keyring re-encrypts columns encrypted at rest; nil when encryption is
+	// disabled.
+	Keyring *fieldcrypt.Keyring
+	// StuckSyncBatchAfter is how long a batch may stay processing before it
+	// counts as stuck.
+	StuckSyncBatchAfter time.Duration
+}
+
+func NewService(repo Repository) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	stuckAfter := options.StuckSyncBatchAfter
+	if stuckAfter <= 0 {
+		stuckAfter = defaultStuckSyncBatchAfter
+	}
+	return &Service{
+		repo:       repo,
+		jobs:       options.Jobs,
+		backups:    options.Backups,
+		keyring:    options.Keyring,
+		stuckAfter: stuckAfter,
+		now:        time.Now,
+	}
+}
+
+func (s *Service) ListFamilies(ctx context.Context, filter ListFamiliesFilter) ([]FamilySummary, int64, error) {
+	ctx, span := tracing.Start(ctx, "admin.ListFamilies")
+	defer span.End()
+
+	filter.Limit = clampLimit(filter.Limit, DefaultFamiliesLimit, MaxFamiliesLimit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListFamilies(ctx, filter)
+}
+
+func (s *Service) ListSyncBatches(ctx context.Context, filter SyncBatchFilter) ([]SyncBatch, error) {
+	ctx, span := tracing.Start(ctx, "admin.ListSyncBatches")
+	defer span.End()
+
+	filter.FamilyID = strings.TrimSpace(filter.FamilyID)
+	filter.Status = strings.TrimSpace(filter.Status)
+	filter.Limit = clampLimit(filter.Limit, DefaultBatchesLimit, MaxBatchesLimit)
+	if filter.StuckOnly {
+		cutoff := s.stuckCutoff()
+		filter.Status = string(syncdomain.BatchStateProcessing)
+		filter.UpdatedBefore = &cutoff
+	}
+
+	batches, err := s.repo.ListSyncBatches(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range batches {
+		batches[i].Stuck = s.isStuck(batches[i])
+	}
+	return batches, nil
+}
+
+func (s *Service) GetSyncBatch(ctx context.Context, batchID string) (*SyncBatchDetail, error) {
+	ctx, span := tracing.Start(ctx, "admin.GetSyncBatch")
+	defer span.End()
+
+	batch, err := s.repo.GetSyncBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	batch.Stuck = s.isStuck(*batch)
+
+	operations, err := s.repo.ListPendingSyncOperations(ctx, batch.FamilyID, batch.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &SyncBatchDetail{SyncBatch: *batch, PendingOperations: operations}, nil
+}
+
+// ReleaseSyncBatch unsticks a batch left processing by a crashed request so
+// the client's retry with the same Idempotency-Key runs again. Batches that
+// are not stuck yet need force, since their request may still be running.
+func (s *Service) ReleaseSyncBatch(ctx context.Context, batchID string, force bool) (*ReleaseResult, error) {
+	ctx, span := tracing.Start(ctx, "admin.ReleaseSyncBatch")
+	defer span.End()
+
+	batch, err := s.repo.GetSyncBatch(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != string(syncdomain.BatchStateProcessing) {
+		return nil, ErrSyncBatchNotProcessing
+	}
+	if !force && !s.isStuck(*batch) {
+		return nil, ErrSyncBatchNotStuck
+	}
+
+	released, operations, err := s.repo.ReleaseSyncBatch(ctx, *batch)
+	if err != nil {
+		return nil, err
+	}
+	if !released {
+		return nil, ErrSyncBatchNotProcessing
+	}
+	return &ReleaseResult{BatchID: batch.ID, OperationsReleased: operations}, nil
+}
+
+// PurgeSoftDeleted hard-deletes soft-deleted rows, or only counts them on a
+// dry run.
+func (s *Service) PurgeSoftDeleted(ctx context.Context, input PurgeInput) (*PurgeResult, error) {
+	ctx, span := tracing.Start(ctx, "admin.PurgeSoftDeleted")
+	defer span.End()
+
+	deletedBefore := input.DeletedBefore
+	if deletedBefore.IsZero() {
+		deletedBefore = s.now()
+	}
+	deletedBefore = deletedBefore.UTC()
+
+	var (
+		tables []PurgeCount
+		err    error
+	)
+	if input.DryRun {
+		tables, err = s.repo.CountSoftDeleted(ctx, deletedBefore)
+	} else {
+		tables, err = s.repo.PurgeSoftDeleted(ctx, deletedBefore)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{DeletedBefore: deletedBefore, DryRun: input.DryRun, Tables: tables}, nil
+}
+
+// RotateEncryption re-encrypts every encrypted column with the primary key,
+// including plaintext written before encryption was enabled. Once it has run,
+// older keys can be dropped from the keyring. A value changed while it runs
+// is skipped, since the change was written with the primary key already. A
+// dry run only counts the values left to rotate.
+func (s *Service) RotateEncryption(ctx context.Context, input RotateEncryptionInput) (*RotateEncryptionResult, error) {
+	ctx, span := tracing.Start(ctx, "admin.RotateEncryption")
+	defer span.End()
+
+	if s.keyring == nil {
+		return nil, ErrEncryptionDisabled
+	}
+
+	result := &RotateEncryptionResult{
+		KeyID:   s.keyring.PrimaryKeyID(),
+		DryRun:  input.DryRun,
+		Columns: make([]RotationCount, 0, len(EncryptedColumns)),
+	}
+	for _, column := range EncryptedColumns {
+		rows, err := s.rotateColumn(ctx, column, input.DryRun)
+		if err != nil {
+			return nil, fmt.Errorf("rotate %s.%s: %w", column.Table, column.Column, err)
+		}
+		result.Columns = append(result.Columns, RotationCount{Table: column.Table, Column: column.Column, Rows: rows})
+	}
+	return result, nil
+}
+
+func (s *Service) rotateColumn(ctx context.Context, column EncryptedColumn, dryRun bool) (int64, error) {
+	var (
+		rows    int64
+		afterID string
+	)
+	for {
+		values, err := s.repo.ListEncryptedValues(ctx, column, afterID, rotationBatchSize)
+		if err != nil {
+			return rows, err
+		}
+
+		for _, value := range values {
+			afterID = value.ID
+			if !s.keyring.NeedsRotation(value.Value) {
+				continue
+			}
+			if dryRun {
+				rows++
+				continue
+			}
+
+			plaintext, err := s.keyring.Decrypt(value.Value)
+			if err != nil {
+				return rows, fmt.Errorf("row %s: %w", value.ID, err)
+			}
+			rotated, err := s.keyring.Encrypt(plaintext)
+			if err != nil {
+				return rows, err
+			}
+			replaced, err := s.repo.ReplaceEncryptedValue(ctx, column, value.ID, value.Value, rotated)
+			if err != nil {
+				return rows, err
+			}
+			if replaced {
+				rows++
+			}
+		}
+
+		if len(values) < rotationBatchSize {
+			return rows, nil
+		}
+	}
+}
+
+// IndexReport checks that the indexes of ExpectedIndexes exist and are
+// valid, so a migration that failed halfway or an index dropped by hand is
+// found before the queries it served slow down.
+func (s *Service) IndexReport(ctx context.Context) (*IndexReport, error) {
+	ctx, span := tracing.Start(ctx, "admin.IndexReport")
+	defer span.End()
+
+	indexes, err := s.repo.ListIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &IndexReport{Checks: make([]IndexCheck, 0, len(ExpectedIndexes))}
+	for _, expected := range ExpectedIndexes {
+		check := IndexCheck{ExpectedIndex: expected, Status: IndexMissing}
+		for _, index := range indexes {
+			if index.Table != expected.Table || !hasLeadingColumns(index.Columns, expected.Columns) {
+				continue
+			}
+			if index.Valid {
+				check.Status = IndexPresent
+				check.MatchedBy = index.Name
+				break
+			}
+			check.Status = IndexInvalid
+			check.MatchedBy = index.Name
+		}
+		if check.Status != IndexPresent {
+			report.Missing++
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	for _, index := range indexes {
+		if !index.Valid {
+			report.Invalid = append(report.Invalid, index)
+		}
+	}
+	return report, nil
+}
+
+func hasLeadingColumns(columns, leading []string) bool {
+	if len(columns) < len(leading) {
+		return false
+	}
+	for i, column := range leading {
+		if columns[i] != column {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) Jobs() []jobs.Job {
+	if s.jobs == nil {
+		return nil
+	}
+	return s.jobs.Jobs()
+}
+
+// RunJob runs the named job synchronously and returns its recorded run,
+// including failed ones.
+func (s *Service) RunJob(ctx context.Context, name string) (*jobs.Run, error) {
+	ctx, span := tracing.Start(ctx, "admin.RunJob")
+	defer span.End()
+
+	if s.jobs == nil {
+		return nil, ErrJobNotFound
+	}
+	return s.jobs.RunNow(ctx, name)
+}
+
+func (s *Service) ListJobRuns(ctx context.Context, filter jobs.RunFilter) ([]jobs.Run, error) {
+	ctx, span := tracing.Start(ctx, "admin.ListJobRuns")
+	defer span.End()
+
+	if s.jobs == nil {
+		return nil, nil
+	}
+	return s.jobs.ListRuns(ctx, filter)
+}
+
+func (s *Service) ListBackups(ctx context.Context) ([]backupdomain.Backup, error) {
+	ctx, span := tracing.Start(ctx, "admin.ListBackups")
+	defer span.End()
+
+	if s.backups == nil {
+		return nil, nil
+	}
+	return s.backups.List(ctx)
+}
+
+// RestoreBackup replaces all backed up tables with the contents of the
+// backup. Everything written since the backup is lost.
+func (s *Service) RestoreBackup(ctx context.Context, key string) (*backupdomain.RestoreResult, error) {
+	ctx, span := tracing.Start(ctx, "admin.RestoreBackup")
+	defer span.End()
+
+	if s.backups == nil {
+		return nil, ErrBackupNotFound
+	}
+	return s.backups.Restore(ctx, strings.TrimSpace(key))
+}
+
+func (s *Service) stuckCutoff() time.Time {
+	return s.now().UTC().Add(-s.stuckAfter)
+}
+
+func (s *Service) isStuck(batch SyncBatch) bool {
+	return batch.Status == string(syncdomain.BatchStateProcessing) && batch.UpdatedAt.Before(s.stuckCutoff())
+}
+
+func clampLimit(limit, fallback, max int) int {
+	if limit <= 0 {
+		return fallback
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
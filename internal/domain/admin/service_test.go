@@ -0,0 +1,273 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	syncdomain "family-app-go/internal/domain/sync"
+	"family-app-go/pkg/fieldcrypt"
+)
+
+type fakeAdminRepo struct {
+	batches       map[string]SyncBatch
+	listFilter    SyncBatchFilter
+	released      []string
+	purgedBefore  time.Time
+	countedBefore time.Time
+	encrypted     map[EncryptedColumn][]EncryptedValue
+	indexes       []Index
+}
+
+func newFakeAdminRepo(batches ...SyncBatch) *fakeAdminRepo {
+	repo := &fakeAdminRepo{batches: make(map[string]SyncBatch)}
+	for _, batch := range batches {
+		repo.batches[batch.ID] = batch
+	}
+	return repo
+}
+
+func (r *fakeAdminRepo) ListFamilies(context.Context, ListFamiliesFilter) ([]FamilySummary, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeAdminRepo) ListSyncBatches(_ context.Context, filter SyncBatchFilter) ([]SyncBatch, error) {
+	r.listFilter = filter
+	var batches []SyncBatch
+	for _, batch := range r.batches {
+		if filter.Status != "" && batch.Status != filter.Status {
+			continue
+		}
+		if filter.UpdatedBefore != nil && !batch.UpdatedAt.Before(*filter.UpdatedBefore) {
+			continue
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+func (r *fakeAdminRepo) GetSyncBatch(_ context.Context, batchID string) (*SyncBatch, error) {
+	batch, ok := r.batches[batchID]
+	if !ok {
+		return nil, ErrSyncBatchNotFound
+	}
+	return &batch, nil
+}
+
+func (r *fakeAdminRepo) ListPendingSyncOperations(context.Context, string, string) ([]SyncOperation, error) {
+	return nil, nil
+}
+
+func (r *fakeAdminRepo) ReleaseSyncBatch(_ context.Context, batch SyncBatch) (bool, int64, error) {
+	r.released = append(r.released, batch.ID)
+	delete(r.batches, batch.ID)
+	return true, 2, nil
+}
+
+func (r *fakeAdminRepo) CountSoftDeleted(_ context.Context, deletedBefore time.Time) ([]PurgeCount, error) {
+	r.countedBefore = deletedBefore
+	return []PurgeCount{{Table: "todo_items", Rows: 3}}, nil
+}
+
+func (r *fakeAdminRepo) PurgeSoftDeleted(_ context.Context, deletedBefore time.Time) ([]PurgeCount, error) {
+	r.purgedBefore = deletedBefore
+	return []PurgeCount{{Table: "todo_items", Rows: 3}}, nil
+}
+
+func (r *fakeAdminRepo) ListEncryptedValues(_ context.Context, column EncryptedColumn, afterID string, limit int) ([]EncryptedValue, error) {
+	values := make([]EncryptedValue, 0)
+	for _, value := range r.encrypted[column] {
+		if value.ID > afterID && len(values) < limit {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func (r *fakeAdminRepo) ListIndexes(context.Context) ([]Index, error) {
+	return r.indexes, nil
+}
+
+func (r *fakeAdminRepo) ReplaceEncryptedValue(_ context.Context, column EncryptedColumn, id, oldValue, newValue string) (bool, error) {
+	for i, value := range r.encrypted[column] {
+		if value.ID == id && value.Value == oldValue {
+			r.encrypted[column][i].Value = newValue
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestService(repo Repository, options ServiceOptions) (*Service, time.Time) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc := NewServiceWithOptions(repo, options)
+	svc.now = func() time.Time { return now }
+	return svc, now
+}
+
+func processingBatch(id string, updatedAt time.Time) SyncBatch {
+	return SyncBatch{ID: id, FamilyID: "family-1", UserID: "user-1", Status: string(syncdomain.BatchStateProcessing), UpdatedAt: updatedAt}
+}
+
+func TestListSyncBatchesStuckOnly(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := newFakeAdminRepo(
+		processingBatch("stuck", now.Add(-time.Hour)),
+		processingBatch("recent", now.Add(-time.Minute)),
+	)
+	svc, _ := newTestService(repo, ServiceOptions{})
+
+	batches, err := svc.ListSyncBatches(context.Background(), SyncBatchFilter{StuckOnly: true})
+	if err != nil {
+		t.Fatalf("list batches: %v", err)
+	}
+	if len(batches) != 1 || batches[0].ID != "stuck" || !batches[0].Stuck {
+		t.Fatalf("expected only the stuck batch, got %+v", batches)
+	}
+	if repo.listFilter.Limit != DefaultBatchesLimit {
+		t.Fatalf("expected default limit %d, got %d", DefaultBatchesLimit, repo.listFilter.Limit)
+	}
+}
+
+func TestReleaseSyncBatchRequiresForceWhenRecent(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := newFakeAdminRepo(processingBatch("recent", now.Add(-time.Minute)))
+	svc, _ := newTestService(repo, ServiceOptions{StuckSyncBatchAfter: 5 * time.Minute})
+
+	if _, err := svc.ReleaseSyncBatch(context.Background(), "recent", false); !errors.Is(err, ErrSyncBatchNotStuck) {
+		t.Fatalf("expected ErrSyncBatchNotStuck, got %v", err)
+	}
+
+	result, err := svc.ReleaseSyncBatch(context.Background(), "recent", true)
+	if err != nil {
+		t.Fatalf("forced release: %v", err)
+	}
+	if result.BatchID != "recent" || result.OperationsReleased != 2 {
+		t.Fatalf("unexpected release result %+v", result)
+	}
+}
+
+func TestReleaseSyncBatchRejectsCompleted(t *testing.T) {
+	batch := SyncBatch{ID: "done", Status: string(syncdomain.BatchStateCompleted)}
+	svc, _ := newTestService(newFakeAdminRepo(batch), ServiceOptions{})
+
+	if _, err := svc.ReleaseSyncBatch(context.Background(), "done", true); !errors.Is(err, ErrSyncBatchNotProcessing) {
+		t.Fatalf("expected ErrSyncBatchNotProcessing, got %v", err)
+	}
+}
+
+func TestPurgeSoftDeletedDryRunOnlyCounts(t *testing.T) {
+	repo := newFakeAdminRepo()
+	svc, now := newTestService(repo, ServiceOptions{})
+
+	result, err := svc.PurgeSoftDeleted(context.Background(), PurgeInput{DryRun: true})
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if !result.DryRun || !repo.countedBefore.Equal(now) || !repo.purgedBefore.IsZero() {
+		t.Fatalf("expected a dry run up to now, got %+v (purged before %v)", result, repo.purgedBefore)
+	}
+}
+
+func TestRotateEncryptionReencryptsWithPrimaryKey(t *testing.T) {
+	oldKeyring, err := fieldcrypt.NewKeyring([]fieldcrypt.Key{{ID: "old", Secret: bytes.Repeat([]byte{1}, 32)}})
+	if err != nil {
+		t.Fatalf("old keyring: %v", err)
+	}
+	keyring, err := fieldcrypt.NewKeyring([]fieldcrypt.Key{
+		{ID: "new", Secret: bytes.Repeat([]byte{2}, 32)},
+		{ID: "old", Secret: bytes.Repeat([]byte{1}, 32)},
+	})
+	if err != nil {
+		t.Fatalf("keyring: %v", err)
+	}
+	sealedOld, _ := oldKeyring.Encrypt("Vet bill")
+	sealedNew, _ := keyring.Encrypt("Groceries")
+
+	titles := EncryptedColumn{Table: "expenses", Column: "title"}
+	repo := newFakeAdminRepo()
+	repo.encrypted = map[EncryptedColumn][]EncryptedValue{
+		titles: {{ID: "a", Value: sealedOld}, {ID: "b", Value: sealedNew}, {ID: "c", Value: "Plain"}},
+	}
+	svc, _ := newTestService(repo, ServiceOptions{Keyring: keyring})
+
+	dryRun, err := svc.RotateEncryption(context.Background(), RotateEncryptionInput{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dryRun.Columns[0].Rows != 2 || repo.encrypted[titles][0].Value != sealedOld {
+		t.Fatalf("expected a dry run counting two values, got %+v", dryRun.Columns[0])
+	}
+
+	result, err := svc.RotateEncryption(context.Background(), RotateEncryptionInput{})
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if result.KeyID != "new" || result.Columns[0].Rows != 2 {
+		t.Fatalf("expected two values rotated to key new, got %+v", result)
+	}
+	for i, want := range []string{"Vet bill", "Groceries", "Plain"} {
+		value := repo.encrypted[titles][i].Value
+		if keyring.NeedsRotation(value) {
+			t.Fatalf("value %d is not sealed with the primary key: %q", i, value)
+		}
+		if got, err := keyring.Decrypt(value); err != nil || got != want {
+			t.Fatalf("value %d decrypts to %q, %v; want %q", i, got, err, want)
+		}
+	}
+
+	if _, err := NewService(repo).RotateEncryption(context.Background(), RotateEncryptionInput{}); !errors.Is(err, ErrEncryptionDisabled) {
+		t.Fatalf("expected encryption disabled, got %v", err)
+	}
+}
+
+func TestRunJobWithoutRunner(t *testing.T) {
+	svc, _ := newTestService(newFakeAdminRepo(), ServiceOptions{})
+
+	if _, err := svc.RunJob(context.Background(), "retention"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+	if jobs := svc.Jobs(); len(jobs) != 0 {
+		t.Fatalf("expected no jobs, got %d", len(jobs))
+	}
+}
+
+func TestIndexReportMatchesByLeadingColumns(t *testing.T) {
+	repo := newFakeAdminRepo()
+	for _, expected := range ExpectedIndexes {
+		repo.indexes = append(repo.indexes, Index{Name: expected.Name, Table: expected.Table, Columns: expected.Columns, Valid: true})
+	}
+	// The date index was rebuilt by hand with an extra column, and the todo
+	// items index only exists as a failed concurrent build.
+	repo.indexes[0] = Index{Name: "expenses_family_date_amount", Table: "expenses", Columns: []string{"family_id", "date", "amount"}, Valid: true}
+	repo.indexes[2].Valid = false
+	svc, _ := newTestService(repo, ServiceOptions{})
+
+	report, err := svc.IndexReport(context.Background())
+	if err != nil {
+		t.Fatalf("index report: %v", err)
+	}
+	if len(report.Checks) != len(ExpectedIndexes) {
+		t.Fatalf("expected %d checks, got %d", len(ExpectedIndexes), len(report.Checks))
+	}
+	if check := report.Checks[0]; check.Status != IndexPresent || check.MatchedBy != "expenses_family_date_amount" {
+		t.Fatalf("unexpected date index check: %+v", check)
+	}
+	if check := report.Checks[2]; check.Status != IndexInvalid {
+		t.Fatalf("expected invalid todo items index, got %+v", check)
+	}
+	if report.Missing != 1 || len(report.Invalid) != 1 || report.Invalid[0].Name != ExpectedIndexes[2].Name {
+		t.Fatalf("unexpected report totals: missing=%d invalid=%+v", report.Missing, report.Invalid)
+	}
+
+	repo.indexes = nil
+	report, err = svc.IndexReport(context.Background())
+	if err != nil {
+		t.Fatalf("index report: %v", err)
+	}
+	if report.Missing != len(ExpectedIndexes) || report.Checks[0].Status != IndexMissing {
+		t.Fatalf("expected every index missing, got %+v", report)
+	}
+}
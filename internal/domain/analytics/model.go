@@ -118,3 +118,51 @@ type DeltaResult struct {
 	Amount  float64 `json:"amount"`
 	Percent float64 `json:"percent"`
 }
+
+const (
+	DefaultCategoryTrendMonths = 6
+	MaxCategoryTrendMonths     = 24
+)
+
+// CategoryTrendsFilter selects Months calendar months ending with ToMonth;
+// the same number of months before them form the prior period.
+type CategoryTrendsFilter struct {
+	ToMonth       time.Time
+	Months        int
+	Currency      string
+	UseBaseAmount bool
+	CategoryIDs   []string
+}
+
+type CategoryMonthlyRow struct {
+	CategoryID   string  `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Month        string  `json:"month"`
+	Total        float64 `json:"total"`
+	Count        int64   `json:"count"`
+}
+
+type CategoryTrend struct {
+	CategoryID   string       `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Months       []MonthlyRow `json:"months"`
+	Total        float64      `json:"total"`
+	PriorTotal   float64      `json:"prior_total"`
+	// ChangePercent is nil when the category had no spending in the prior
+	// period, so there is nothing to compare against.
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+type CategoryTrendsResult struct {
+	FromMonth string          `json:"from_month"`
+	ToMonth   string          `json:"to_month"`
+	Items     []CategoryTrend `json:"items"`
+}
+
+// RollupResult reports one rollup maintenance run. Days counts the
+// family-days whose rollups were rewritten.
+type RollupResult struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+	Days int64  `json:"days"`
+}
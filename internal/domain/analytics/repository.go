@@ -1,6 +1,9 @@
 package analytics
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Summary(ctx context.Context, familyID string, filter SummaryFilter) (SummaryResult, error)
@@ -8,4 +11,7 @@ type Repository interface {
 	ByCategory(ctx context.Context, familyID string, filter ByCategoryFilter) ([]ByCategoryRow, error)
 	TopCategories(ctx context.Context, familyID string, filter TopCategoriesFilter) ([]ByCategoryRow, int64, error)
 	Monthly(ctx context.Context, familyID string, filter MonthlyFilter) ([]MonthlyRow, error)
+	CategoryMonthly(ctx context.Context, familyID string, filter MonthlyFilter) ([]CategoryMonthlyRow, error)
+	RefreshRollups(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	MarkRollupDaysDirty(ctx context.Context, from, before time.Time) (int64, error)
 }
@@ -2,8 +2,11 @@ package analytics
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
+
+	"family-app-go/pkg/tracing"
 )
 
 type Service struct {
@@ -38,6 +41,9 @@ func NewServiceWithTopCategoriesConfig(repo Repository, cfg TopCategoriesConfig)
 }
 
 func (s *Service) Summary(ctx context.Context, familyID string, filter SummaryFilter) (SummaryResult, error) {
+	ctx, span := tracing.Start(ctx, "analytics.Summary")
+	defer span.End()
+
 	result, err := s.repo.Summary(ctx, familyID, filter)
 	if err != nil {
 		return SummaryResult{}, err
@@ -52,19 +58,25 @@ func (s *Service) Summary(ctx context.Context, familyID string, filter SummaryFi
 }
 
 func (s *Service) Timeseries(ctx context.Context, familyID string, filter TimeseriesFilter) ([]TimeseriesPoint, error) {
+	ctx, span := tracing.Start(ctx, "analytics.Timeseries")
+	defer span.End()
+
 	return s.repo.Timeseries(ctx, familyID, filter)
 }
 
 func (s *Service) ByCategory(ctx context.Context, familyID string, filter ByCategoryFilter) ([]ByCategoryRow, error) {
+	ctx, span := tracing.Start(ctx, "analytics.ByCategory")
+	defer span.End()
+
 	return s.repo.ByCategory(ctx, familyID, filter)
 }
 
 func (s *Service) TopCategories(ctx context.Context, familyID string) (TopCategoriesResult, error) {
+	ctx, span := tracing.Start(ctx, "analytics.TopCategories")
+	defer span.End()
+
 	if !s.topCategoriesConfig.Enabled {
-		return TopCategoriesResult{
-			Status: TopCategoriesStatusDisabled,
-			Items:  []ByCategoryRow{},
-		}, nil
+		return DisabledTopCategories(), nil
 	}
 
 	filter := s.topCategoriesFilter()
@@ -92,11 +104,111 @@ func (s *Service) TopCategories(ctx context.Context, familyID string) (TopCatego
 	return result, nil
 }
 
+// DisabledTopCategories is the report answered while top categories are
+// turned off.
+func DisabledTopCategories() TopCategoriesResult {
+	return TopCategoriesResult{
+		Status: TopCategoriesStatusDisabled,
+		Items:  []ByCategoryRow{},
+	}
+}
+
 func (s *Service) Monthly(ctx context.Context, familyID string, filter MonthlyFilter) ([]MonthlyRow, error) {
+	ctx, span := tracing.Start(ctx, "analytics.Monthly")
+	defer span.End()
+
 	return s.repo.Monthly(ctx, familyID, filter)
 }
 
+func (s *Service) CategoryTrends(ctx context.Context, familyID string, filter CategoryTrendsFilter) (CategoryTrendsResult, error) {
+	ctx, span := tracing.Start(ctx, "analytics.CategoryTrends")
+	defer span.End()
+
+	months := filter.Months
+	if months <= 0 {
+		months = DefaultCategoryTrendMonths
+	}
+	if months > MaxCategoryTrendMonths {
+		months = MaxCategoryTrendMonths
+	}
+	toMonth := filter.ToMonth
+	if toMonth.IsZero() {
+		toMonth = s.now().UTC()
+	}
+	toMonth = time.Date(toMonth.Year(), toMonth.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := toMonth.AddDate(0, -(months - 1), 0)
+	priorFrom := from.AddDate(0, -months, 0)
+
+	rows, err := s.repo.CategoryMonthly(ctx, familyID, MonthlyFilter{
+		From:          priorFrom,
+		To:            toMonth.AddDate(0, 1, 0),
+		Currency:      filter.Currency,
+		UseBaseAmount: filter.UseBaseAmount,
+		CategoryIDs:   filter.CategoryIDs,
+	})
+	if err != nil {
+		return CategoryTrendsResult{}, err
+	}
+
+	periodStart := from.Format("2006-01")
+	trends := make(map[string]*CategoryTrend)
+	monthIndex := make(map[string]int, months)
+	order := make([]string, 0)
+	for i := 0; i < months; i++ {
+		monthIndex[from.AddDate(0, i, 0).Format("2006-01")] = i
+	}
+	for _, row := range rows {
+		trend, ok := trends[row.CategoryID]
+		if !ok {
+			trend = &CategoryTrend{
+				CategoryID:   row.CategoryID,
+				CategoryName: row.CategoryName,
+				Months:       make([]MonthlyRow, months),
+			}
+			for i := range trend.Months {
+				trend.Months[i].Month = from.AddDate(0, i, 0).Format("2006-01")
+			}
+			trends[row.CategoryID] = trend
+			order = append(order, row.CategoryID)
+		}
+		if row.Month < periodStart {
+			trend.PriorTotal += row.Total
+			continue
+		}
+		if i, ok := monthIndex[row.Month]; ok {
+			trend.Months[i].Total = row.Total
+			trend.Months[i].Count = row.Count
+			trend.Total += row.Total
+		}
+	}
+
+	items := make([]CategoryTrend, 0, len(order))
+	for _, id := range order {
+		trend := trends[id]
+		if trend.PriorTotal != 0 {
+			change := (trend.Total - trend.PriorTotal) / trend.PriorTotal * 100
+			trend.ChangePercent = &change
+		}
+		items = append(items, *trend)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Total != items[j].Total {
+			return items[i].Total > items[j].Total
+		}
+		return items[i].CategoryName < items[j].CategoryName
+	})
+
+	return CategoryTrendsResult{
+		FromMonth: periodStart,
+		ToMonth:   toMonth.Format("2006-01"),
+		Items:     items,
+	}, nil
+}
+
 func (s *Service) Compare(ctx context.Context, familyID string, filter CompareFilter) (CompareResult, error) {
+	ctx, span := tracing.Start(ctx, "analytics.Compare")
+	defer span.End()
+
 	resultA, err := s.repo.Summary(ctx, familyID, SummaryFilter{
 		From:          filter.FromA,
 		To:            filter.ToA,
@@ -145,6 +257,46 @@ func (s *Service) Compare(ctx context.Context, familyID string, filter CompareFi
 	}, nil
 }
 
+// RefreshRollups rolls up the days changed since the last run. Today is left
+// to the raw scans until it is over.
+func (s *Service) RefreshRollups(ctx context.Context) (RollupResult, error) {
+	ctx, span := tracing.Start(ctx, "analytics.RefreshRollups")
+	defer span.End()
+
+	today := s.today()
+	days, err := s.repo.RefreshRollups(ctx, today, rollupBatchSize)
+	return RollupResult{To: today.AddDate(0, 0, -1).Format("2006-01-02"), Days: days}, err
+}
+
+// RebuildRollups rewrites the rollups of the last given number of days, so
+// they heal even if a change slipped past the dirty-day tracking.
+func (s *Service) RebuildRollups(ctx context.Context, days int) (RollupResult, error) {
+	ctx, span := tracing.Start(ctx, "analytics.RebuildRollups")
+	defer span.End()
+
+	if days <= 0 {
+		days = defaultRollupRebuildDays
+	}
+	today := s.today()
+	from := today.AddDate(0, 0, -days)
+	result := RollupResult{
+		From: from.Format("2006-01-02"),
+		To:   today.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+	if _, err := s.repo.MarkRollupDaysDirty(ctx, from, today); err != nil {
+		return result, err
+	}
+
+	refreshed, err := s.repo.RefreshRollups(ctx, today, rollupBatchSize)
+	result.Days = refreshed
+	return result, err
+}
+
+func (s *Service) today() time.Time {
+	current := s.now().UTC()
+	return time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 func daysBetweenInclusive(from, to time.Time) int {
 	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
 	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
@@ -160,6 +312,9 @@ const (
 	defaultTopCategoriesMinRecords    = 10
 	defaultTopCategoriesResponseCount = 5
 	defaultTopCategoriesCacheTTL      = time.Minute
+
+	defaultRollupRebuildDays = 7
+	rollupBatchSize          = 500
 )
 
 func normalizeTopCategoriesConfig(cfg TopCategoriesConfig) TopCategoriesConfig {
@@ -11,6 +11,12 @@ type fakeAnalyticsRepo struct {
 	topCategoriesRows        []ByCategoryRow
 	topCategoriesRecordsRead int64
 	topCategoriesCalls       int
+	categoryMonthlyRows      []CategoryMonthlyRow
+	categoryMonthlyFilter    MonthlyFilter
+	dirtyFrom                time.Time
+	dirtyBefore              time.Time
+	refreshBefore            time.Time
+	refreshed                int64
 }
 
 func (f *fakeAnalyticsRepo) Summary(ctx context.Context, familyID string, filter SummaryFilter) (SummaryResult, error) {
@@ -40,6 +46,22 @@ func (f *fakeAnalyticsRepo) Monthly(ctx context.Context, familyID string, filter
 	return nil, nil
 }
 
+func (f *fakeAnalyticsRepo) CategoryMonthly(ctx context.Context, familyID string, filter MonthlyFilter) ([]CategoryMonthlyRow, error) {
+	f.categoryMonthlyFilter = filter
+	return f.categoryMonthlyRows, nil
+}
+
+func (f *fakeAnalyticsRepo) RefreshRollups(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	f.refreshBefore = before
+	return f.refreshed, nil
+}
+
+func (f *fakeAnalyticsRepo) MarkRollupDaysDirty(ctx context.Context, from, before time.Time) (int64, error) {
+	f.dirtyFrom = from
+	f.dirtyBefore = before
+	return 0, nil
+}
+
 func TestSummaryAvgPerDay(t *testing.T) {
 	repo := &fakeAnalyticsRepo{
 		summaries: map[string]SummaryResult{
@@ -91,6 +113,77 @@ func TestCompareDelta(t *testing.T) {
 	}
 }
 
+func TestCategoryTrendsComparesWithPriorPeriod(t *testing.T) {
+	repo := &fakeAnalyticsRepo{
+		categoryMonthlyRows: []CategoryMonthlyRow{
+			{CategoryID: "cat-1", CategoryName: "Food", Month: "2025-11", Total: 100, Count: 2},
+			{CategoryID: "cat-1", CategoryName: "Food", Month: "2025-12", Total: 100, Count: 3},
+			{CategoryID: "cat-2", CategoryName: "Taxi", Month: "2026-01", Total: 40, Count: 1},
+			{CategoryID: "cat-1", CategoryName: "Food", Month: "2026-01", Total: 50, Count: 1},
+			{CategoryID: "cat-1", CategoryName: "Food", Month: "2026-02", Total: 100, Count: 4},
+		},
+	}
+	svc := NewService(repo)
+	svc.now = func() time.Time { return time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC) }
+
+	result, err := svc.CategoryTrends(context.Background(), "fam-1", CategoryTrendsFilter{Months: 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !repo.categoryMonthlyFilter.From.Equal(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)) ||
+		!repo.categoryMonthlyFilter.To.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected range %v - %v", repo.categoryMonthlyFilter.From, repo.categoryMonthlyFilter.To)
+	}
+	if result.FromMonth != "2026-01" || result.ToMonth != "2026-02" {
+		t.Fatalf("unexpected period %s - %s", result.FromMonth, result.ToMonth)
+	}
+	if len(result.Items) != 2 {
+		t.Fatalf("expected 2 categories, got %d", len(result.Items))
+	}
+
+	food := result.Items[0]
+	if food.CategoryID != "cat-1" || food.Total != 150 || food.PriorTotal != 200 {
+		t.Fatalf("unexpected food trend %+v", food)
+	}
+	if food.ChangePercent == nil || *food.ChangePercent != -25 {
+		t.Fatalf("expected change -25, got %v", food.ChangePercent)
+	}
+	if len(food.Months) != 2 || food.Months[0].Month != "2026-01" || food.Months[1].Count != 4 {
+		t.Fatalf("unexpected food months %+v", food.Months)
+	}
+
+	taxi := result.Items[1]
+	if taxi.ChangePercent != nil {
+		t.Fatalf("expected no change without prior spending, got %v", *taxi.ChangePercent)
+	}
+	if taxi.Months[1].Month != "2026-02" || taxi.Months[1].Total != 0 {
+		t.Fatalf("expected empty month to be filled, got %+v", taxi.Months)
+	}
+}
+
+func TestRebuildRollupsCoversFinishedDays(t *testing.T) {
+	repo := &fakeAnalyticsRepo{refreshed: 12}
+	svc := NewService(repo)
+	svc.now = func() time.Time { return time.Date(2026, 3, 2, 0, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60)) }
+
+	result, err := svc.RebuildRollups(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	today := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !repo.dirtyFrom.Equal(today.AddDate(0, 0, -7)) || !repo.dirtyBefore.Equal(today) {
+		t.Fatalf("unexpected dirty range %v - %v", repo.dirtyFrom, repo.dirtyBefore)
+	}
+	if !repo.refreshBefore.Equal(today) {
+		t.Fatalf("expected refresh before %v, got %v", today, repo.refreshBefore)
+	}
+	if result.From != "2026-02-22" || result.To != "2026-02-28" || result.Days != 12 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
 func TestTopCategoriesUsesCacheWithinTTL(t *testing.T) {
 	repo := &fakeAnalyticsRepo{
 		topCategoriesRows: []ByCategoryRow{
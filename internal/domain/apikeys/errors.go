@@ -0,0 +1,11 @@
+package apikeys
+
+import "errors"
+
+var (
+	ErrKeyNotFound  = errors.New("api key not found")
+	ErrInvalidName  = errors.New("invalid api key name")
+	ErrInvalidScope = errors.New("invalid api key scope")
+	ErrTooManyKeys  = errors.New("too many api keys")
+	ErrInvalidKey   = errors.New("invalid api key")
+)
@@ -0,0 +1,44 @@
+package apikeys
+
+import "time"
+
+const (
+	// KeyPrefix marks API keys so the auth middleware can tell them apart
+	// from provider access tokens.
+	KeyPrefix = "fam_"
+
+	ScopeFull     = "full"
+	ScopeReadOnly = "read_only"
+
+	MaxNameLength   = 100
+	MaxKeysPerUser  = 20
+	lastUsedMinStep = time.Minute
+)
+
+// Key is a per-user API key. Only the sha256 of the secret is stored; Prefix
+// is the public part shown in listings so users can tell keys apart.
+type Key struct {
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	UserID     string     `gorm:"type:uuid;not null"`
+	Name       string     `gorm:"not null"`
+	Prefix     string     `gorm:"not null"`
+	KeyHash    string     `gorm:"not null"`
+	Scope      string     `gorm:"not null"`
+	LastUsedAt *time.Time `gorm:""`
+	RevokedAt  *time.Time `gorm:""`
+	CreatedAt  time.Time  `gorm:"not null"`
+}
+
+func (Key) TableName() string {
+	return "api_keys"
+}
+
+// ReadOnly reports whether the key may only be used for safe methods.
+func (k Key) ReadOnly() bool {
+	return k.Scope == ScopeReadOnly
+}
+
+type CreateInput struct {
+	Name  string
+	Scope string
+}
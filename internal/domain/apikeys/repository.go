@@ -0,0 +1,15 @@
+package apikeys
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateKey(ctx context.Context, key *Key) error
+	ListKeys(ctx context.Context, userID string) ([]Key, error)
+	CountActiveKeys(ctx context.Context, userID string) (int64, error)
+	GetKeyByHash(ctx context.Context, hash string) (*Key, error)
+	RevokeKey(ctx context.Context, userID, id string, at time.Time) error
+	TouchKey(ctx context.Context, id string, at time.Time) error
+}
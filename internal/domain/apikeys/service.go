@@ -0,0 +1,137 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	prefixBytes = 4
+	secretBytes = 24
+)
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// CreateKey stores a new key and returns it together with the plaintext
+// secret, which is never retrievable again.
+func (s *Service) CreateKey(ctx context.Context, userID string, input CreateInput) (*Key, string, error) {
+	ctx, span := tracing.Start(ctx, "apikeys.CreateKey")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return nil, "", ErrInvalidName
+	}
+	scope := strings.TrimSpace(input.Scope)
+	if scope == "" {
+		scope = ScopeFull
+	}
+	if scope != ScopeFull && scope != ScopeReadOnly {
+		return nil, "", ErrInvalidScope
+	}
+
+	count, err := s.repo.CountActiveKeys(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= MaxKeysPerUser {
+		return nil, "", ErrTooManyKeys
+	}
+
+	prefix, err := id.Hex(prefixBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	plaintext := KeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, "", err
+	}
+	key := Key{
+		ID:        newID,
+		UserID:    userID,
+		Name:      name,
+		Prefix:    KeyPrefix + prefix,
+		KeyHash:   hashKey(plaintext),
+		Scope:     scope,
+		CreatedAt: s.now().UTC(),
+	}
+	if err := s.repo.CreateKey(ctx, &key); err != nil {
+		return nil, "", err
+	}
+	return &key, plaintext, nil
+}
+
+func (s *Service) ListKeys(ctx context.Context, userID string) ([]Key, error) {
+	ctx, span := tracing.Start(ctx, "apikeys.ListKeys")
+	defer span.End()
+
+	return s.repo.ListKeys(ctx, userID)
+}
+
+func (s *Service) RevokeKey(ctx context.Context, userID, id string) error {
+	ctx, span := tracing.Start(ctx, "apikeys.RevokeKey")
+	defer span.End()
+
+	return s.repo.RevokeKey(ctx, userID, id, s.now().UTC())
+}
+
+// Authenticate resolves a plaintext key. last_used_at is only written when
+// it is more than a minute old to keep busy scripts from writing on every
+// request.
+func (s *Service) Authenticate(ctx context.Context, plaintext string) (*Key, error) {
+	ctx, span := tracing.Start(ctx, "apikeys.Authenticate")
+	defer span.End()
+
+	if !strings.HasPrefix(plaintext, KeyPrefix) {
+		return nil, ErrInvalidKey
+	}
+	key, err := s.repo.GetKeyByHash(ctx, hashKey(plaintext))
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrInvalidKey
+	}
+
+	now := s.now().UTC()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedMinStep {
+		if err := s.repo.TouchKey(ctx, key.ID, now); err == nil {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
+
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,172 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeKeysRepo struct {
+	keys    map[string]Key
+	touches int
+}
+
+func newFakeKeysRepo() *fakeKeysRepo {
+	return &fakeKeysRepo{keys: map[string]Key{}}
+}
+
+func (r *fakeKeysRepo) CreateKey(_ context.Context, key *Key) error {
+	r.keys[key.ID] = *key
+	return nil
+}
+
+func (r *fakeKeysRepo) ListKeys(_ context.Context, userID string) ([]Key, error) {
+	var result []Key
+	for _, key := range r.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeKeysRepo) CountActiveKeys(ctx context.Context, userID string) (int64, error) {
+	keys, _ := r.ListKeys(ctx, userID)
+	return int64(len(keys)), nil
+}
+
+func (r *fakeKeysRepo) GetKeyByHash(_ context.Context, hash string) (*Key, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			key := key
+			return &key, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (r *fakeKeysRepo) RevokeKey(_ context.Context, userID, id string, at time.Time) error {
+	key, ok := r.keys[id]
+	if !ok || key.UserID != userID || key.RevokedAt != nil {
+		return ErrKeyNotFound
+	}
+	key.RevokedAt = &at
+	r.keys[id] = key
+	return nil
+}
+
+func (r *fakeKeysRepo) TouchKey(_ context.Context, id string, at time.Time) error {
+	key := r.keys[id]
+	key.LastUsedAt = &at
+	r.keys[id] = key
+	r.touches++
+	return nil
+}
+
+func TestCreateKeyStoresOnlyHash(t *testing.T) {
+	repo := newFakeKeysRepo()
+	service := NewService(repo)
+
+	key, plaintext, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: " Home Assistant "})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.HasPrefix(plaintext, key.Prefix+"_") || !strings.HasPrefix(key.Prefix, KeyPrefix) {
+		t.Fatalf("unexpected key format: %s (prefix %s)", plaintext, key.Prefix)
+	}
+	if key.Name != "Home Assistant" || key.Scope != ScopeFull {
+		t.Fatalf("unexpected key: %+v", key)
+	}
+	if strings.Contains(repo.keys[key.ID].KeyHash, plaintext) {
+		t.Fatal("plaintext key stored")
+	}
+
+	resolved, err := service.Authenticate(context.Background(), plaintext)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if resolved.UserID != "user-1" || resolved.LastUsedAt == nil {
+		t.Fatalf("unexpected resolved key: %+v", resolved)
+	}
+}
+
+func TestCreateKeyValidatesInput(t *testing.T) {
+	service := NewService(newFakeKeysRepo())
+
+	if _, _, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: "  "}); !errors.Is(err, ErrInvalidName) {
+		t.Fatalf("expected ErrInvalidName, got %v", err)
+	}
+	if _, _, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: "script", Scope: "admin"}); !errors.Is(err, ErrInvalidScope) {
+		t.Fatalf("expected ErrInvalidScope, got %v", err)
+	}
+	key, _, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: "script", Scope: ScopeReadOnly})
+	if err != nil {
+		t.Fatalf("create read-only: %v", err)
+	}
+	if !key.ReadOnly() {
+		t.Fatalf("expected read-only key: %+v", key)
+	}
+}
+
+func TestCreateKeyEnforcesLimit(t *testing.T) {
+	service := NewService(newFakeKeysRepo())
+	for i := 0; i < MaxKeysPerUser; i++ {
+		if _, _, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: "key"}); err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+	}
+	if _, _, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: "key"}); !errors.Is(err, ErrTooManyKeys) {
+		t.Fatalf("expected ErrTooManyKeys, got %v", err)
+	}
+}
+
+func TestAuthenticateRejectsRevokedAndUnknownKeys(t *testing.T) {
+	service := NewService(newFakeKeysRepo())
+	key, plaintext, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: "script"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if _, err := service.Authenticate(context.Background(), KeyPrefix+"unknown"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey for unknown key, got %v", err)
+	}
+	if err := service.RevokeKey(context.Background(), "user-2", key.ID); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound for foreign key, got %v", err)
+	}
+	if err := service.RevokeKey(context.Background(), "user-1", key.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := service.Authenticate(context.Background(), plaintext); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey after revoke, got %v", err)
+	}
+}
+
+func TestAuthenticateThrottlesLastUsedWrites(t *testing.T) {
+	repo := newFakeKeysRepo()
+	service := NewService(repo)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	_, plaintext, err := service.CreateKey(context.Background(), "user-1", CreateInput{Name: "script"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := service.Authenticate(context.Background(), plaintext); err != nil {
+			t.Fatalf("authenticate: %v", err)
+		}
+	}
+	if repo.touches != 1 {
+		t.Fatalf("expected 1 touch, got %d", repo.touches)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := service.Authenticate(context.Background(), plaintext); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if repo.touches != 2 {
+		t.Fatalf("expected 2 touches, got %d", repo.touches)
+	}
+}
@@ -0,0 +1,5 @@
+package audit
+
+import "errors"
+
+var ErrInvalidEventType = errors.New("invalid security event type")
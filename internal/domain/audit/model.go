@@ -0,0 +1,96 @@
+package audit
+
+import "time"
+
+// Security event types. Unlike the family activity feed these are kept for
+// incident investigation and are only visible through the admin API.
+const (
+	EventLoginSucceeded       = "login_succeeded"
+	EventLoginFailed          = "login_failed"
+	EventTokenRejected        = "token_rejected"
+	EventMemberRemoved        = "member_removed"
+	EventOwnershipTransferred = "ownership_transferred"
+	EventAPIKeyCreated        = "api_key_created"
+	EventAPIKeyRevoked        = "api_key_revoked"
+	EventAPIKeyUsed           = "api_key_used"
+	EventExportRequested      = "export_requested"
+	EventExportDownloaded     = "export_downloaded"
+	EventSessionRevoked       = "session_revoked"
+
+	DefaultLimit = 50
+	MaxLimit     = 200
+
+	DefaultRetentionDays = 365
+
+	maxUserAgentLength = 512
+	// apiKeyUseStep and tokenRejectedStep throttle the noisy event types to
+	// one per key, or per client address, per step and instance.
+	apiKeyUseStep     = time.Hour
+	tokenRejectedStep = time.Minute
+	// maxThrottled bounds the throttle state; stale entries are dropped once
+	// it is reached.
+	maxThrottled = 10000
+)
+
+var eventTypes = []string{
+	EventLoginSucceeded,
+	EventLoginFailed,
+	EventTokenRejected,
+	EventMemberRemoved,
+	EventOwnershipTransferred,
+	EventAPIKeyCreated,
+	EventAPIKeyRevoked,
+	EventAPIKeyUsed,
+	EventExportRequested,
+	EventExportDownloaded,
+	EventSessionRevoked,
+}
+
+// EventTypes returns every security event type.
+func EventTypes() []string {
+	return append([]string(nil), eventTypes...)
+}
+
+// Event is one entry of the security audit trail.
+type Event struct {
+	ID       string  `gorm:"type:uuid;primaryKey"`
+	Type     string  `gorm:"not null"`
+	ActorID  *string `gorm:"type:uuid"`
+	FamilyID *string `gorm:"type:uuid"`
+	// TargetID is the member, API key, export or session the event is about.
+	TargetID  *string
+	APIKeyID  *string `gorm:"column:api_key_id;type:uuid"`
+	IP        string  `gorm:"column:ip;not null"`
+	UserAgent string  `gorm:"not null"`
+	RequestID string  `gorm:"not null"`
+	// Details holds a JSON object of event specific fields.
+	Details   []byte    `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (Event) TableName() string {
+	return "security_audit_events"
+}
+
+// Input describes an event to record. Empty IDs are stored as null.
+type Input struct {
+	Type      string
+	ActorID   string
+	FamilyID  string
+	TargetID  string
+	APIKeyID  string
+	IP        string
+	UserAgent string
+	RequestID string
+	Details   map[string]string
+}
+
+type ListFilter struct {
+	Type     string
+	ActorID  string
+	FamilyID string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,14 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateEvent(ctx context.Context, event *Event) error
+	// ListEvents returns matching events, newest first, and their total.
+	ListEvents(ctx context.Context, filter ListFilter) ([]Event, int64, error)
+	// DeleteBefore removes events created before cutoff.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
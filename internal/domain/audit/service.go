@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// Service records and queries the security audit trail.
+type Service struct {
+	repo          Repository
+	retentionDays int
+	now           func() time.Time
+
+	mu        sync.Mutex
+	throttled map[string]time.Time
+}
+
+type ServiceOptions struct {
+	// RetentionDays is how long events are kept before Purge removes them.
+	RetentionDays int
+}
+
+type PurgeResult struct {
+	DeletedBefore time.Time `json:"deleted_before"`
+	Deleted       int64     `json:"deleted"`
+}
+
+func NewService(repo Repository) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	retentionDays := options.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = DefaultRetentionDays
+	}
+	return &Service{
+		repo:          repo,
+		retentionDays: retentionDays,
+		now:           time.Now,
+		throttled:     make(map[string]time.Time),
+	}
+}
+
+// Record stores a security event. API key use and rejected tokens are
+// throttled, so a busy integration or a token spray does not flood the
+// trail; throttled events return nil without being stored.
+func (s *Service) Record(ctx context.Context, input Input) error {
+	ctx, span := tracing.Start(ctx, "audit.Record")
+	defer span.End()
+
+	if !isKnownEventType(input.Type) {
+		return ErrInvalidEventType
+	}
+	now := s.now().UTC()
+	if s.throttle(input, now) {
+		return nil
+	}
+
+	details := []byte("{}")
+	if len(input.Details) > 0 {
+		encoded, err := json.Marshal(input.Details)
+		if err != nil {
+			return err
+		}
+		details = encoded
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return err
+	}
+	userAgent := strings.TrimSpace(input.UserAgent)
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return s.repo.CreateEvent(ctx, &Event{
+		ID:        newID,
+		Type:      input.Type,
+		ActorID:   optionalString(input.ActorID),
+		FamilyID:  optionalString(input.FamilyID),
+		TargetID:  optionalString(input.TargetID),
+		APIKeyID:  optionalString(input.APIKeyID),
+		IP:        strings.TrimSpace(input.IP),
+		UserAgent: userAgent,
+		RequestID: strings.TrimSpace(input.RequestID),
+		Details:   details,
+		CreatedAt: now,
+	})
+}
+
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]Event, int64, error) {
+	ctx, span := tracing.Start(ctx, "audit.List")
+	defer span.End()
+
+	filter.Type = strings.TrimSpace(filter.Type)
+	if filter.Type != "" && !isKnownEventType(filter.Type) {
+		return nil, 0, ErrInvalidEventType
+	}
+	filter.ActorID = strings.TrimSpace(filter.ActorID)
+	filter.FamilyID = strings.TrimSpace(filter.FamilyID)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListEvents(ctx, filter)
+}
+
+// Purge removes events older than the retention period.
+func (s *Service) Purge(ctx context.Context) (*PurgeResult, error) {
+	ctx, span := tracing.Start(ctx, "audit.Purge")
+	defer span.End()
+
+	cutoff := s.now().UTC().AddDate(0, 0, -s.retentionDays)
+	deleted, err := s.repo.DeleteBefore(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{DeletedBefore: cutoff, Deleted: deleted}, nil
+}
+
+// throttle reports whether the event repeats one recorded within its step.
+func (s *Service) throttle(input Input, now time.Time) bool {
+	var key string
+	var step time.Duration
+	switch input.Type {
+	case EventAPIKeyUsed:
+		key, step = input.Type+":"+input.APIKeyID, apiKeyUseStep
+	case EventTokenRejected:
+		key, step = input.Type+":"+input.IP, tokenRejectedStep
+	default:
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.throttled[key]; ok && now.Sub(last) < step {
+		return true
+	}
+	if len(s.throttled) >= maxThrottled {
+		for existing, last := range s.throttled {
+			if now.Sub(last) >= apiKeyUseStep {
+				delete(s.throttled, existing)
+			}
+		}
+		if len(s.throttled) >= maxThrottled {
+			// Too many distinct clients to remember; start over rather
+			// than grow without bound.
+			s.throttled = make(map[string]time.Time)
+		}
+	}
+	s.throttled[key] = now
+	return false
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, known := range eventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
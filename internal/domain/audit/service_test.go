@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeAuditRepo struct {
+	Repository
+	events []Event
+	cutoff time.Time
+}
+
+func (r *fakeAuditRepo) CreateEvent(_ context.Context, event *Event) error {
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeAuditRepo) DeleteBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	r.cutoff = cutoff
+	return 3, nil
+}
+
+func TestRecordStoresEventWithDetails(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	service := NewService(repo)
+
+	err := service.Record(context.Background(), Input{
+		Type:     EventMemberRemoved,
+		ActorID:  "owner-1",
+		FamilyID: "family-1",
+		TargetID: "member-1",
+		IP:       "203.0.113.7",
+		Details:  map[string]string{"role": "member"},
+	})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if len(repo.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(repo.events))
+	}
+	event := repo.events[0]
+	if event.ActorID == nil || *event.ActorID != "owner-1" || event.APIKeyID != nil {
+		t.Fatalf("unexpected ids: %+v", event)
+	}
+	if string(event.Details) != `{"role":"member"}` {
+		t.Fatalf("details = %s", event.Details)
+	}
+}
+
+func TestRecordRejectsUnknownType(t *testing.T) {
+	service := NewService(&fakeAuditRepo{})
+
+	if err := service.Record(context.Background(), Input{Type: "password_changed"}); !errors.Is(err, ErrInvalidEventType) {
+		t.Fatalf("expected ErrInvalidEventType, got %v", err)
+	}
+}
+
+func TestRecordThrottlesAPIKeyUse(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	service := NewService(repo)
+	now := time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	use := Input{Type: EventAPIKeyUsed, ActorID: "user-1", APIKeyID: "key-1"}
+	for i := 0; i < 3; i++ {
+		if err := service.Record(ctx, use); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if err := service.Record(ctx, Input{Type: EventAPIKeyUsed, ActorID: "user-1", APIKeyID: "key-2"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	now = now.Add(apiKeyUseStep)
+	if err := service.Record(ctx, use); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	if len(repo.events) != 3 {
+		t.Fatalf("expected 3 stored uses, got %d", len(repo.events))
+	}
+}
+
+func TestPurgeUsesRetention(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	service := NewServiceWithOptions(repo, ServiceOptions{RetentionDays: 30})
+	now := time.Date(2026, time.March, 31, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	result, err := service.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	want := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	if !repo.cutoff.Equal(want) || result.Deleted != 3 {
+		t.Fatalf("cutoff = %v, deleted = %d", repo.cutoff, result.Deleted)
+	}
+}
@@ -0,0 +1,15 @@
+package auth
+
+import "errors"
+
+var (
+	ErrAccountNotFound      = errors.New("account not found")
+	ErrEmailTaken           = errors.New("email already registered")
+	ErrInvalidEmail         = errors.New("invalid email")
+	ErrInvalidPassword      = errors.New("invalid password")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrInvalidAccessToken   = errors.New("invalid access token")
+	ErrNotConfigured        = errors.New("auth secret not configured")
+)
@@ -0,0 +1,59 @@
+package auth
+
+import "time"
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength matches bcrypt's input limit.
+	MaxPasswordLength = 72
+
+	TokenTypeBearer = "bearer"
+)
+
+type Account struct {
+	ID           string    `gorm:"type:uuid;primaryKey"`
+	Email        string    `gorm:"not null"`
+	Name         string    `gorm:"not null"`
+	PasswordHash string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+	UpdatedAt    time.Time `gorm:"not null"`
+}
+
+func (Account) TableName() string {
+	return "auth_accounts"
+}
+
+// RefreshToken stores the sha256 of an opaque refresh token. Tokens are
+// single use: refreshing revokes the presented token and issues a new one.
+type RefreshToken struct {
+	ID        string     `gorm:"type:uuid;primaryKey"`
+	UserID    string     `gorm:"type:uuid;not null"`
+	TokenHash string     `gorm:"not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	RevokedAt *time.Time `gorm:""`
+	CreatedAt time.Time  `gorm:"not null"`
+}
+
+func (RefreshToken) TableName() string {
+	return "auth_refresh_tokens"
+}
+
+type RegisterInput struct {
+	Email    string
+	Password string
+	Name     string
+}
+
+// Identity is what a verified access token says about its bearer.
+type Identity struct {
+	UserID string
+	Email  string
+	Name   string
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	ExpiresAt    time.Time
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateAccount(ctx context.Context, account *Account) error
+	GetAccountByEmail(ctx context.Context, email string) (*Account, error)
+	GetAccountByID(ctx context.Context, id string) (*Account, error)
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	// RevokeRefreshToken reports false when the token was already revoked.
+	RevokeRefreshToken(ctx context.Context, id string, at time.Time) (bool, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error
+}
@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	refreshTokenBytes      = 32
+)
+
+type Config struct {
+	Secret          []byte
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+type Service struct {
+	repo       Repository
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+func NewService(repo Repository, cfg Config) *Service {
+	accessTTL := cfg.AccessTokenTTL
+	if accessTTL <= 0 {
+		accessTTL = defaultAccessTokenTTL
+	}
+	refreshTTL := cfg.RefreshTokenTTL
+	if refreshTTL <= 0 {
+		refreshTTL = defaultRefreshTokenTTL
+	}
+	return &Service{
+		repo:       repo,
+		secret:     cfg.Secret,
+		issuer:     cfg.Issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+// Configured reports whether the service can sign tokens. A nil service,
+// as wired when another provider verifies tokens, is not configured.
+func (s *Service) Configured() bool {
+	return s != nil && len(s.secret) > 0
+}
+
+func (s *Service) Register(ctx context.Context, input RegisterInput) (*Account, *TokenPair, error) {
+	ctx, span := tracing.Start(ctx, "auth.Register")
+	defer span.End()
+
+	if !s.Configured() {
+		return nil, nil, ErrNotConfigured
+	}
+	email, err := normalizeEmail(input.Email)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validatePassword(input.Password); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := s.repo.GetAccountByEmail(ctx, email); err == nil {
+		return nil, nil, ErrEmailTaken
+	} else if !errors.Is(err, ErrAccountNotFound) {
+		return nil, nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hash password: %w", err)
+	}
+	newID, err := id.New()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.now().UTC()
+	account := Account{
+		ID:           newID,
+		Email:        email,
+		Name:         strings.TrimSpace(input.Name),
+		PasswordHash: string(hash),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.CreateAccount(ctx, &account); err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.issue(ctx, &account)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &account, pair, nil
+}
+
+func (s *Service) Login(ctx context.Context, email, password string) (*Account, *TokenPair, error) {
+	ctx, span := tracing.Start(ctx, "auth.Login")
+	defer span.End()
+
+	if !s.Configured() {
+		return nil, nil, ErrNotConfigured
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	account, err := s.repo.GetAccountByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrAccountNotFound) {
+			return nil, nil, ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)); err != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	pair, err := s.issue(ctx, account)
+	if err != nil {
+		return nil, nil, err
+	}
+	return account, pair, nil
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already
+// rotated revokes every token of its owner, since it means the token leaked.
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*Account, *TokenPair, error) {
+	ctx, span := tracing.Start(ctx, "auth.Refresh")
+	defer span.End()
+
+	if !s.Configured() {
+		return nil, nil, ErrNotConfigured
+	}
+	stored, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	now := s.now().UTC()
+	if stored.RevokedAt != nil {
+		if err := s.repo.RevokeUserRefreshTokens(ctx, stored.UserID, now); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if !now.Before(stored.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	revoked, err := s.repo.RevokeRefreshToken(ctx, stored.ID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !revoked {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	account, err := s.repo.GetAccountByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, ErrAccountNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	pair, err := s.issue(ctx, account)
+	if err != nil {
+		return nil, nil, err
+	}
+	return account, pair, nil
+}
+
+// Revoke invalidates a refresh token. Unknown tokens are ignored so the
+// call is safe to repeat.
+func (s *Service) Revoke(ctx context.Context, refreshToken string) error {
+	ctx, span := tracing.Start(ctx, "auth.Revoke")
+	defer span.End()
+
+	stored, err := s.repo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return err
+	}
+	if stored.RevokedAt != nil {
+		return nil
+	}
+	_, err = s.repo.RevokeRefreshToken(ctx, stored.ID, s.now().UTC())
+	return err
+}
+
+func (s *Service) VerifyAccessToken(token string) (*Identity, error) {
+	if !s.Configured() {
+		return nil, ErrNotConfigured
+	}
+	claims, err := verifyJWT(s.secret, token)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	now := s.now()
+	if claims.Subject == "" || claims.ExpiresAt == 0 || !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrInvalidAccessToken
+	}
+	if s.issuer != "" && claims.Issuer != s.issuer {
+		return nil, ErrInvalidAccessToken
+	}
+	if claims.Audience != accessTokenAudience {
+		return nil, ErrInvalidAccessToken
+	}
+
+	return &Identity{
+		UserID: claims.Subject,
+		Email:  claims.Email,
+		Name:   claims.Name,
+	}, nil
+}
+
+func (s *Service) issue(ctx context.Context, account *Account) (*TokenPair, error) {
+	now := s.now().UTC()
+	expiresAt := now.Add(s.accessTTL)
+
+	access, err := signJWT(s.secret, accessClaims{
+		Subject:   account.ID,
+		Email:     account.Email,
+		Name:      account.Name,
+		Issuer:    s.issuer,
+		Audience:  accessTokenAudience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	refresh := base64.RawURLEncoding.EncodeToString(raw)
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRefreshToken(ctx, &RefreshToken{
+		ID:        newID,
+		UserID:    account.ID,
+		TokenHash: hashToken(refresh),
+		ExpiresAt: now.Add(s.refreshTTL),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    TokenTypeBearer,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+func normalizeEmail(value string) (string, error) {
+	email := strings.ToLower(strings.TrimSpace(value))
+	if email == "" {
+		return "", ErrInvalidEmail
+	}
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
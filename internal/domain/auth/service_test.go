@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeAuthRepo struct {
+	accounts map[string]Account
+	tokens   map[string]RefreshToken
+}
+
+func newFakeAuthRepo() *fakeAuthRepo {
+	return &fakeAuthRepo{
+		accounts: map[string]Account{},
+		tokens:   map[string]RefreshToken{},
+	}
+}
+
+func (r *fakeAuthRepo) CreateAccount(_ context.Context, account *Account) error {
+	for _, existing := range r.accounts {
+		if strings.EqualFold(existing.Email, account.Email) {
+			return ErrEmailTaken
+		}
+	}
+	r.accounts[account.ID] = *account
+	return nil
+}
+
+func (r *fakeAuthRepo) GetAccountByEmail(_ context.Context, email string) (*Account, error) {
+	for _, account := range r.accounts {
+		if strings.EqualFold(account.Email, email) {
+			account := account
+			return &account, nil
+		}
+	}
+	return nil, ErrAccountNotFound
+}
+
+func (r *fakeAuthRepo) GetAccountByID(_ context.Context, id string) (*Account, error) {
+	account, ok := r.accounts[id]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+	return &account, nil
+}
+
+func (r *fakeAuthRepo) CreateRefreshToken(_ context.Context, token *RefreshToken) error {
+	r.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *fakeAuthRepo) GetRefreshTokenByHash(_ context.Context, hash string) (*RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			token := token
+			return &token, nil
+		}
+	}
+	return nil, ErrRefreshTokenNotFound
+}
+
+func (r *fakeAuthRepo) RevokeRefreshToken(_ context.Context, id string, at time.Time) (bool, error) {
+	token, ok := r.tokens[id]
+	if !ok || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.RevokedAt = &at
+	r.tokens[id] = token
+	return true, nil
+}
+
+func (r *fakeAuthRepo) RevokeUserRefreshTokens(_ context.Context, userID string, at time.Time) error {
+	for id, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func newTestService(repo *fakeAuthRepo) *Service {
+	return NewService(repo, Config{
+		Secret: []byte("0123456789abcdef0123456789abcdef"),
+		Issuer: "family-app",
+	})
+}
+
+func TestRegisterIssuesVerifiableTokens(t *testing.T) {
+	repo := newFakeAuthRepo()
+	service := newTestService(repo)
+
+	account, pair, err := service.Register(context.Background(), RegisterInput{
+		Email:    " Anna@Example.com ",
+		Password: "correct horse",
+		Name:     " Anna ",
+	})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if account.Email != "anna@example.com" || account.Name != "Anna" {
+		t.Fatalf("unexpected account: %+v", account)
+	}
+	if account.PasswordHash == "correct horse" {
+		t.Fatal("password stored in plain text")
+	}
+
+	identity, err := service.VerifyAccessToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if identity.UserID != account.ID || identity.Email != account.Email {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+
+	if _, _, err := service.Register(context.Background(), RegisterInput{Email: "anna@example.com", Password: "another one"}); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("expected ErrEmailTaken, got %v", err)
+	}
+}
+
+func TestRegisterValidatesInput(t *testing.T) {
+	service := newTestService(newFakeAuthRepo())
+
+	if _, _, err := service.Register(context.Background(), RegisterInput{Email: "not-an-email", Password: "long enough"}); !errors.Is(err, ErrInvalidEmail) {
+		t.Fatalf("expected ErrInvalidEmail, got %v", err)
+	}
+	if _, _, err := service.Register(context.Background(), RegisterInput{Email: "a@example.com", Password: "short"}); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+}
+
+func TestLoginRejectsWrongPassword(t *testing.T) {
+	service := newTestService(newFakeAuthRepo())
+	if _, _, err := service.Register(context.Background(), RegisterInput{Email: "a@example.com", Password: "correct horse"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if _, _, err := service.Login(context.Background(), "a@example.com", "wrong horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if _, _, err := service.Login(context.Background(), "missing@example.com", "correct horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials for unknown email, got %v", err)
+	}
+	if _, _, err := service.Login(context.Background(), "A@example.com", "correct horse"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+}
+
+func TestRefreshRotatesAndDetectsReuse(t *testing.T) {
+	repo := newFakeAuthRepo()
+	service := newTestService(repo)
+	_, first, err := service.Register(context.Background(), RegisterInput{Email: "a@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	_, second, err := service.Refresh(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+
+	if _, _, err := service.Refresh(context.Background(), first.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken on reuse, got %v", err)
+	}
+	if _, _, err := service.Refresh(context.Background(), second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected reuse to revoke the whole family, got %v", err)
+	}
+}
+
+func TestRefreshRejectsExpiredAndRevokedTokens(t *testing.T) {
+	repo := newFakeAuthRepo()
+	service := newTestService(repo)
+	_, pair, err := service.Register(context.Background(), RegisterInput{Email: "a@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if err := service.Revoke(context.Background(), pair.RefreshToken); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, _, err := service.Refresh(context.Background(), pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken after revoke, got %v", err)
+	}
+
+	_, pair, err = service.Login(context.Background(), "a@example.com", "correct horse")
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	service.now = func() time.Time { return time.Now().Add(defaultRefreshTokenTTL + time.Hour) }
+	if _, _, err := service.Refresh(context.Background(), pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken when expired, got %v", err)
+	}
+}
+
+func TestVerifyAccessTokenRejectsTamperedAndExpired(t *testing.T) {
+	service := newTestService(newFakeAuthRepo())
+	_, pair, err := service.Register(context.Background(), RegisterInput{Email: "a@example.com", Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	other := NewService(newFakeAuthRepo(), Config{Secret: []byte("another-secret-another-secret-xx"), Issuer: "family-app"})
+	if _, err := other.VerifyAccessToken(pair.AccessToken); !errors.Is(err, ErrInvalidAccessToken) {
+		t.Fatalf("expected ErrInvalidAccessToken for foreign signature, got %v", err)
+	}
+
+	service.now = func() time.Time { return time.Now().Add(defaultAccessTokenTTL + time.Minute) }
+	if _, err := service.VerifyAccessToken(pair.AccessToken); !errors.Is(err, ErrInvalidAccessToken) {
+		t.Fatalf("expected ErrInvalidAccessToken when expired, got %v", err)
+	}
+}
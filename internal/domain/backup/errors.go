@@ -0,0 +1,10 @@
+package backup
+
+import "errors"
+
+var (
+	ErrBackupNotFound   = errors.New("backup not found")
+	ErrInvalidBackup    = errors.New("invalid backup")
+	ErrSchemaMismatch   = errors.New("backup schema version does not match the database")
+	ErrInvalidBackupKey = errors.New("invalid backup key")
+)
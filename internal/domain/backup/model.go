@@ -0,0 +1,50 @@
+package backup
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Format identifies the snapshot layout: a gzip-compressed stream of JSON
+// lines, a Header followed by one Record per row, tables in dependency order.
+const Format = "family-app-backup/v1"
+
+// KeyPrefix is where backups live in the blob store.
+const KeyPrefix = "backups/"
+
+type Header struct {
+	Format        string    `json:"format"`
+	CreatedAt     time.Time `json:"created_at"`
+	SchemaVersion string    `json:"schema_version"`
+	Tables        []string  `json:"tables"`
+}
+
+type Record struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+type Backup struct {
+	Key       string
+	SizeBytes int64
+	CreatedAt time.Time
+}
+
+type TableCount struct {
+	Table string
+	Rows  int64
+}
+
+type Result struct {
+	Key           string
+	SchemaVersion string
+	SizeBytes     int64
+	Tables        []TableCount
+	Removed       []string
+}
+
+type RestoreResult struct {
+	Key           string
+	SchemaVersion string
+	Tables        []TableCount
+}
@@ -0,0 +1,22 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RowInserter loads rows of one table during a restore.
+type RowInserter func(table string, rows []json.RawMessage) error
+
+type Repository interface {
+	// Tables lists the tables to back up, parents before the tables that
+	// reference them.
+	Tables(ctx context.Context) ([]string, error)
+	SchemaVersion(ctx context.Context) (string, error)
+	// Snapshot streams every row of the tables, as JSON objects, from one
+	// consistent read-only snapshot.
+	Snapshot(ctx context.Context, tables []string, fn func(table string, row json.RawMessage) error) error
+	// Restore empties the tables and lets fn load them again, all in one
+	// transaction.
+	Restore(ctx context.Context, tables []string, fn func(insert RowInserter) error) error
+}
@@ -0,0 +1,293 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/blobstore"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	defaultRetention   = 30 * 24 * time.Hour
+	defaultKeepLast    = 3
+	restoreBatchSize   = 500
+	keyTimestampLayout = "2006-01-02T15-04-05Z"
+	keySuffix          = ".jsonl.gz"
+)
+
+type Service struct {
+	repo      Repository
+	store     blobstore.Store
+	retention time.Duration
+	keepLast  int
+	now       func() time.Time
+}
+
+// ServiceOptions sets backup retention: backups older than Retention are
+// removed after each run, but the newest KeepLast always stay.
+type ServiceOptions struct {
+	Retention time.Duration
+	KeepLast  int
+}
+
+func NewService(repo Repository, store blobstore.Store) *Service {
+	return NewServiceWithOptions(repo, store, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, store blobstore.Store, options ServiceOptions) *Service {
+	retention := options.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	keepLast := options.KeepLast
+	if keepLast <= 0 {
+		keepLast = defaultKeepLast
+	}
+
+	return &Service{
+		repo:      repo,
+		store:     store,
+		retention: retention,
+		keepLast:  keepLast,
+		now:       time.Now,
+	}
+}
+
+// Run snapshots the database into the blob store and then applies the
+// retention policy. It is driven by the background job runner.
+func (s *Service) Run(ctx context.Context) (*Result, error) {
+	ctx, span := tracing.Start(ctx, "backup.Run")
+	defer span.End()
+
+	tables, err := s.repo.Tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.repo.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := s.now().UTC()
+	key := KeyPrefix + createdAt.Format(keyTimestampLayout) + keySuffix
+	header := Header{
+		Format:        Format,
+		CreatedAt:     createdAt,
+		SchemaVersion: version,
+		Tables:        tables,
+	}
+
+	// The snapshot is encoded on the fly and streamed into the store.
+	reader, writer := io.Pipe()
+	counts := make(map[string]int64, len(tables))
+	go func() {
+		writer.CloseWithError(s.writeSnapshot(ctx, writer, header, counts))
+	}()
+	size, err := s.store.Put(ctx, key, reader)
+	reader.CloseWithError(err)
+	if err != nil {
+		return nil, fmt.Errorf("store backup: %w", err)
+	}
+
+	result := &Result{
+		Key:           key,
+		SchemaVersion: version,
+		SizeBytes:     size,
+		Tables:        make([]TableCount, 0, len(tables)),
+	}
+	for _, table := range tables {
+		result.Tables = append(result.Tables, TableCount{Table: table, Rows: counts[table]})
+	}
+
+	removed, err := s.prune(ctx)
+	result.Removed = removed
+	if err != nil {
+		return result, fmt.Errorf("prune backups: %w", err)
+	}
+	return result, nil
+}
+
+// List returns the stored backups, newest first.
+func (s *Service) List(ctx context.Context) ([]Backup, error) {
+	ctx, span := tracing.Start(ctx, "backup.List")
+	defer span.End()
+
+	objects, err := s.store.List(ctx, KeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0, len(objects))
+	for _, object := range objects {
+		createdAt, ok := parseKeyTime(object.Key)
+		if !ok {
+			continue
+		}
+		backups = append(backups, Backup{Key: object.Key, SizeBytes: object.Size, CreatedAt: createdAt})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// Restore replaces the contents of every table in the backup with the backed
+// up rows, in a single transaction. The backup must come from the same schema
+// version as the database.
+func (s *Service) Restore(ctx context.Context, key string) (*RestoreResult, error) {
+	ctx, span := tracing.Start(ctx, "backup.Restore")
+	defer span.End()
+
+	if _, ok := parseKeyTime(key); !ok {
+		return nil, ErrInvalidBackupKey
+	}
+
+	file, err := s.store.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return nil, ErrBackupNotFound
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	defer gz.Close()
+	decoder := json.NewDecoder(gz)
+
+	var header Header
+	if err := decoder.Decode(&header); err != nil {
+		return nil, fmt.Errorf("%w: read header: %v", ErrInvalidBackup, err)
+	}
+	if header.Format != Format || len(header.Tables) == 0 {
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidBackup, header.Format)
+	}
+	version, err := s.repo.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if header.SchemaVersion != version {
+		return nil, fmt.Errorf("%w: backup %s, database %s", ErrSchemaMismatch, header.SchemaVersion, version)
+	}
+
+	known := make(map[string]bool, len(header.Tables))
+	for _, table := range header.Tables {
+		known[table] = true
+	}
+	counts := make(map[string]int64, len(header.Tables))
+	err = s.repo.Restore(ctx, header.Tables, func(insert RowInserter) error {
+		var (
+			table string
+			batch []json.RawMessage
+		)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := insert(table, batch); err != nil {
+				return fmt.Errorf("restore %s: %w", table, err)
+			}
+			counts[table] += int64(len(batch))
+			batch = batch[:0]
+			return nil
+		}
+
+		for {
+			var record Record
+			if err := decoder.Decode(&record); err != nil {
+				if errors.Is(err, io.EOF) {
+					return flush()
+				}
+				return fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+			}
+			if !known[record.Table] {
+				return fmt.Errorf("%w: unexpected table %q", ErrInvalidBackup, record.Table)
+			}
+			if record.Table != table || len(batch) >= restoreBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+				table = record.Table
+			}
+			batch = append(batch, record.Row)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RestoreResult{
+		Key:           key,
+		SchemaVersion: header.SchemaVersion,
+		Tables:        make([]TableCount, 0, len(header.Tables)),
+	}
+	for _, table := range header.Tables {
+		result.Tables = append(result.Tables, TableCount{Table: table, Rows: counts[table]})
+	}
+	return result, nil
+}
+
+func (s *Service) writeSnapshot(ctx context.Context, w io.Writer, header Header, counts map[string]int64) error {
+	gz := gzip.NewWriter(w)
+	buffered := bufio.NewWriter(gz)
+	encoder := json.NewEncoder(buffered)
+
+	if err := encoder.Encode(header); err != nil {
+		return err
+	}
+	err := s.repo.Snapshot(ctx, header.Tables, func(table string, row json.RawMessage) error {
+		counts[table]++
+		return encoder.Encode(Record{Table: table, Row: row})
+	})
+	if err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// prune removes backups past the retention period, always keeping the newest
+// keepLast.
+func (s *Service) prune(ctx context.Context) ([]string, error) {
+	backups, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := s.now().UTC().Add(-s.retention)
+	var removed []string
+	for i, backup := range backups {
+		if i < s.keepLast || !backup.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if err := s.store.Delete(ctx, backup.Key); err != nil {
+			return removed, err
+		}
+		removed = append(removed, backup.Key)
+	}
+	return removed, nil
+}
+
+func parseKeyTime(key string) (time.Time, bool) {
+	if !strings.HasPrefix(key, KeyPrefix) || !strings.HasSuffix(key, keySuffix) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(key, KeyPrefix), keySuffix)
+	createdAt, err := time.Parse(keyTimestampLayout, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return createdAt, true
+}
@@ -0,0 +1,179 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"family-app-go/pkg/blobstore"
+)
+
+type fakeBackupRepo struct {
+	tables  []string
+	version string
+	rows    map[string][]json.RawMessage
+}
+
+func newFakeBackupRepo() *fakeBackupRepo {
+	return &fakeBackupRepo{
+		tables:  []string{"families", "expenses"},
+		version: "0045_create_deletion_requests.sql",
+		rows: map[string][]json.RawMessage{
+			"families": {json.RawMessage(`{"id":"f1","name":"Smiths"}`)},
+			"expenses": {
+				json.RawMessage(`{"id":"e1","family_id":"f1","amount":12.5}`),
+				json.RawMessage(`{"id":"e2","family_id":"f1","amount":3}`),
+			},
+		},
+	}
+}
+
+func (r *fakeBackupRepo) Tables(context.Context) ([]string, error) {
+	return r.tables, nil
+}
+
+func (r *fakeBackupRepo) SchemaVersion(context.Context) (string, error) {
+	return r.version, nil
+}
+
+func (r *fakeBackupRepo) Snapshot(_ context.Context, tables []string, fn func(string, json.RawMessage) error) error {
+	for _, table := range tables {
+		for _, row := range r.rows[table] {
+			if err := fn(table, row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *fakeBackupRepo) Restore(_ context.Context, tables []string, fn func(RowInserter) error) error {
+	restored := make(map[string][]json.RawMessage, len(tables))
+	err := fn(func(table string, rows []json.RawMessage) error {
+		for _, row := range rows {
+			restored[table] = append(restored[table], append(json.RawMessage(nil), row...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	r.rows = restored
+	return nil
+}
+
+func newTestService(t *testing.T, repo *fakeBackupRepo, now time.Time) (*Service, *blobstore.Local) {
+	t.Helper()
+	store := blobstore.NewLocal(t.TempDir())
+	service := NewServiceWithOptions(repo, store, ServiceOptions{Retention: 7 * 24 * time.Hour, KeepLast: 2})
+	service.now = func() time.Time { return now }
+	return service, store
+}
+
+func TestRunAndRestoreRoundTrip(t *testing.T) {
+	repo := newFakeBackupRepo()
+	service, _ := newTestService(t, repo, time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC))
+
+	result, err := service.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if result.Key != "backups/2026-03-01T04-00-00Z.jsonl.gz" {
+		t.Fatalf("unexpected key %q", result.Key)
+	}
+	if result.SizeBytes == 0 || len(result.Tables) != 2 || result.Tables[1].Rows != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	original := repo.rows
+	repo.rows = map[string][]json.RawMessage{"families": {json.RawMessage(`{"id":"f2"}`)}}
+
+	restored, err := service.Restore(context.Background(), result.Key)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.Tables[0].Rows != 1 || restored.Tables[1].Rows != 2 {
+		t.Fatalf("unexpected restore counts %+v", restored.Tables)
+	}
+	for table, rows := range original {
+		if len(repo.rows[table]) != len(rows) {
+			t.Fatalf("table %s: expected %d rows, got %d", table, len(rows), len(repo.rows[table]))
+		}
+		for i := range rows {
+			if !bytes.Equal(repo.rows[table][i], rows[i]) {
+				t.Fatalf("table %s row %d: expected %s, got %s", table, i, rows[i], repo.rows[table][i])
+			}
+		}
+	}
+}
+
+func TestRestoreRejectsSchemaMismatch(t *testing.T) {
+	repo := newFakeBackupRepo()
+	service, _ := newTestService(t, repo, time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC))
+
+	result, err := service.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	repo.version = "0046_next.sql"
+
+	if _, err := service.Restore(context.Background(), result.Key); !errors.Is(err, ErrSchemaMismatch) {
+		t.Fatalf("expected ErrSchemaMismatch, got %v", err)
+	}
+	if len(repo.rows["expenses"]) != 2 {
+		t.Fatalf("expected data untouched, got %v", repo.rows)
+	}
+}
+
+func TestRestoreValidatesKey(t *testing.T) {
+	service, store := newTestService(t, newFakeBackupRepo(), time.Now())
+
+	if _, err := service.Restore(context.Background(), "../etc/passwd"); !errors.Is(err, ErrInvalidBackupKey) {
+		t.Fatalf("expected ErrInvalidBackupKey, got %v", err)
+	}
+	if _, err := service.Restore(context.Background(), "backups/2026-01-01T00-00-00Z.jsonl.gz"); !errors.Is(err, ErrBackupNotFound) {
+		t.Fatalf("expected ErrBackupNotFound, got %v", err)
+	}
+
+	key := "backups/2026-01-02T00-00-00Z.jsonl.gz"
+	if _, err := store.Put(context.Background(), key, bytes.NewReader([]byte("not gzip"))); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err := service.Restore(context.Background(), key); !errors.Is(err, ErrInvalidBackup) {
+		t.Fatalf("expected ErrInvalidBackup, got %v", err)
+	}
+}
+
+func TestRunPrunesExpiredBackupsKeepingNewest(t *testing.T) {
+	repo := newFakeBackupRepo()
+	start := time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC)
+	service, _ := newTestService(t, repo, start)
+
+	for day := 0; day < 4; day++ {
+		service.now = func() time.Time { return start.AddDate(0, 0, day) }
+		if _, err := service.Run(context.Background()); err != nil {
+			t.Fatalf("run day %d: %v", day, err)
+		}
+	}
+
+	// Thirty days later every backup is past retention, but the newest two stay.
+	service.now = func() time.Time { return start.AddDate(0, 0, 30) }
+	result, err := service.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if len(result.Removed) != 3 {
+		t.Fatalf("expected 3 removed backups, got %v", result.Removed)
+	}
+
+	backups, err := service.List(context.Background())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(backups) != 2 || backups[0].Key != result.Key || backups[1].Key != "backups/2026-03-04T04-00-00Z.jsonl.gz" {
+		t.Fatalf("unexpected backups %+v", backups)
+	}
+}
@@ -0,0 +1,8 @@
+package batch
+
+import "errors"
+
+var (
+	ErrInvalidRef  = errors.New("invalid batch reference")
+	ErrTooManyRefs = errors.New("too many batch references")
+)
@@ -0,0 +1,56 @@
+package batch
+
+import (
+	expensesdomain "family-app-go/internal/domain/expenses"
+	gymdomain "family-app-go/internal/domain/gym"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+type EntityType string
+
+const (
+	EntityExpense  EntityType = "expense"
+	EntityTodoItem EntityType = "todo_item"
+	EntityCategory EntityType = "category"
+	EntityWorkout  EntityType = "workout"
+)
+
+// MaxRefs caps the records read in one request.
+const MaxRefs = 100
+
+type Status string
+
+const (
+	StatusFound     Status = "found"
+	StatusNotFound  Status = "not_found"
+	StatusForbidden Status = "forbidden"
+)
+
+// Ref names one record by type and ID.
+type Ref struct {
+	Type EntityType
+	ID   string
+}
+
+type Query struct {
+	FamilyID string
+	UserID   string
+	// Child limits snapshots to what a child member may see: todo items
+	// assigned to them and their own workouts. Expenses and categories are
+	// forbidden.
+	Child bool
+	Refs  []Ref
+}
+
+// Snapshot is the current state of one requested record. Only the field
+// of its type is set, and only when Status is StatusFound.
+type Snapshot struct {
+	Ref      Ref
+	Status   Status
+	Expense  *expensesdomain.ExpenseWithCategories
+	Category *expensesdomain.Category
+	TodoItem *todosdomain.TodoItem
+	Workout  *gymdomain.WorkoutWithSets
+	// Labels are set for todo items and workouts.
+	Labels []string
+}
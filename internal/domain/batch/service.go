@@ -0,0 +1,186 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/pkg/tracing"
+)
+
+type ExpenseReader interface {
+	GetExpenseWithCategories(ctx context.Context, familyID, expenseID string) (*expensesdomain.ExpenseWithCategories, error)
+	ListCategories(ctx context.Context, familyID string) ([]expensesdomain.Category, error)
+}
+
+type TodoItemReader interface {
+	GetTodoItem(ctx context.Context, familyID, itemID, viewerID string) (*todosdomain.TodoItem, error)
+}
+
+type WorkoutReader interface {
+	GetWorkoutByID(ctx context.Context, userID, workoutID string) (*gymdomain.WorkoutWithSets, error)
+}
+
+type LabelReader interface {
+	ListByEntities(ctx context.Context, entityType labelsdomain.EntityType, entityIDs []string) (map[string][]string, error)
+}
+
+// Service reads the current state of records from several domains at once,
+// for clients reconciling after change events. Each record goes through its
+// domain's own read, so a snapshot shows exactly what a single read would.
+type Service struct {
+	expenses ExpenseReader
+	todos    TodoItemReader
+	workouts WorkoutReader
+	labels   LabelReader
+}
+
+func NewService(expenses ExpenseReader, todos TodoItemReader, workouts WorkoutReader, labels LabelReader) *Service {
+	return &Service{
+		expenses: expenses,
+		todos:    todos,
+		workouts: workouts,
+		labels:   labels,
+	}
+}
+
+// Get returns a snapshot per distinct reference, in request order. Records
+// that do not exist or the caller cannot see are reported by status rather
+// than failing the whole request.
+func (s *Service) Get(ctx context.Context, query Query) ([]Snapshot, error) {
+	ctx, span := tracing.Start(ctx, "batch.Get")
+	defer span.End()
+
+	refs, err := normalizeRefs(query.Refs)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories map[string]expensesdomain.Category
+	snapshots := make([]Snapshot, 0, len(refs))
+	for _, ref := range refs {
+		snapshot := Snapshot{Ref: ref, Status: StatusNotFound}
+		switch ref.Type {
+		case EntityExpense:
+			if query.Child {
+				snapshot.Status = StatusForbidden
+				break
+			}
+			expense, err := s.expenses.GetExpenseWithCategories(ctx, query.FamilyID, ref.ID)
+			if err != nil && !errors.Is(err, expensesdomain.ErrExpenseNotFound) {
+				return nil, err
+			}
+			if expense != nil {
+				snapshot.Status, snapshot.Expense = StatusFound, expense
+			}
+		case EntityCategory:
+			if query.Child {
+				snapshot.Status = StatusForbidden
+				break
+			}
+			if categories == nil {
+				list, err := s.expenses.ListCategories(ctx, query.FamilyID)
+				if err != nil {
+					return nil, err
+				}
+				categories = make(map[string]expensesdomain.Category, len(list))
+				for _, category := range list {
+					categories[category.ID] = category
+				}
+			}
+			if category, ok := categories[ref.ID]; ok {
+				snapshot.Status, snapshot.Category = StatusFound, &category
+			}
+		case EntityTodoItem:
+			item, err := s.todos.GetTodoItem(ctx, query.FamilyID, ref.ID, query.UserID)
+			if err != nil && !errors.Is(err, todosdomain.ErrTodoItemNotFound) {
+				return nil, err
+			}
+			// A child sees only the items assigned to them; others are
+			// reported as missing, as search leaves them out.
+			if item != nil && (!query.Child || (item.AssigneeID != nil && *item.AssigneeID == query.UserID)) {
+				snapshot.Status, snapshot.TodoItem = StatusFound, item
+			}
+		case EntityWorkout:
+			workout, err := s.workouts.GetWorkoutByID(ctx, query.UserID, ref.ID)
+			if err != nil && !errors.Is(err, gymdomain.ErrWorkoutNotFound) {
+				return nil, err
+			}
+			if workout != nil {
+				snapshot.Status, snapshot.Workout = StatusFound, workout
+			}
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := s.attachLabels(ctx, snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// attachLabels sets the labels of the found todo items and workouts, one
+// lookup per type.
+func (s *Service) attachLabels(ctx context.Context, snapshots []Snapshot) error {
+	for entityType, labelType := range map[EntityType]labelsdomain.EntityType{
+		EntityTodoItem: labelsdomain.EntityTodoItem,
+		EntityWorkout:  labelsdomain.EntityWorkout,
+	} {
+		var ids []string
+		for _, snapshot := range snapshots {
+			if snapshot.Ref.Type == entityType && snapshot.Status == StatusFound {
+				ids = append(ids, snapshot.Ref.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		labels, err := s.labels.ListByEntities(ctx, labelType, ids)
+		if err != nil {
+			return err
+		}
+		for i := range snapshots {
+			if snapshots[i].Ref.Type == entityType && snapshots[i].Status == StatusFound {
+				snapshots[i].Labels = labels[snapshots[i].Ref.ID]
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeRefs trims the references and drops repeated ones, keeping the
+// first occurrence.
+func normalizeRefs(refs []Ref) ([]Ref, error) {
+	if len(refs) == 0 {
+		return nil, ErrInvalidRef
+	}
+	seen := make(map[Ref]bool, len(refs))
+	normalized := make([]Ref, 0, len(refs))
+	for _, ref := range refs {
+		ref.ID = strings.TrimSpace(ref.ID)
+		if ref.ID == "" || !validEntityType(ref.Type) {
+			return nil, ErrInvalidRef
+		}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		normalized = append(normalized, ref)
+	}
+	if len(normalized) > MaxRefs {
+		return nil, ErrTooManyRefs
+	}
+	return normalized, nil
+}
+
+func validEntityType(entityType EntityType) bool {
+	switch entityType {
+	case EntityExpense, EntityTodoItem, EntityCategory, EntityWorkout:
+		return true
+	}
+	return false
+}
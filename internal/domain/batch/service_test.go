@@ -0,0 +1,168 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+type fakeExpenses struct {
+	expenses   map[string]expensesdomain.ExpenseWithCategories
+	categories []expensesdomain.Category
+	listCalls  int
+}
+
+func (f *fakeExpenses) GetExpenseWithCategories(_ context.Context, _ string, expenseID string) (*expensesdomain.ExpenseWithCategories, error) {
+	expense, ok := f.expenses[expenseID]
+	if !ok {
+		return nil, expensesdomain.ErrExpenseNotFound
+	}
+	return &expense, nil
+}
+
+func (f *fakeExpenses) ListCategories(_ context.Context, _ string) ([]expensesdomain.Category, error) {
+	f.listCalls++
+	return f.categories, nil
+}
+
+type fakeTodos struct {
+	items map[string]todosdomain.TodoItem
+}
+
+func (f *fakeTodos) GetTodoItem(_ context.Context, _ string, itemID, _ string) (*todosdomain.TodoItem, error) {
+	item, ok := f.items[itemID]
+	if !ok {
+		return nil, todosdomain.ErrTodoItemNotFound
+	}
+	return &item, nil
+}
+
+type fakeWorkouts struct {
+	err error
+}
+
+func (f *fakeWorkouts) GetWorkoutByID(_ context.Context, userID, workoutID string) (*gymdomain.WorkoutWithSets, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if workoutID != "workout-1" || userID != "user-1" {
+		return nil, gymdomain.ErrWorkoutNotFound
+	}
+	return &gymdomain.WorkoutWithSets{Workout: gymdomain.Workout{ID: workoutID, UserID: userID}}, nil
+}
+
+type fakeLabels struct{}
+
+func (fakeLabels) ListByEntities(_ context.Context, entityType labelsdomain.EntityType, entityIDs []string) (map[string][]string, error) {
+	labels := make(map[string][]string, len(entityIDs))
+	for _, id := range entityIDs {
+		labels[id] = []string{string(entityType)}
+	}
+	return labels, nil
+}
+
+func newTestService() (*Service, *fakeExpenses, *fakeWorkouts) {
+	kid := "user-2"
+	expenses := &fakeExpenses{
+		expenses:   map[string]expensesdomain.ExpenseWithCategories{"expense-1": {Expense: expensesdomain.Expense{ID: "expense-1"}, CategoryIDs: []string{"category-1"}}},
+		categories: []expensesdomain.Category{{ID: "category-1"}, {ID: "category-2"}},
+	}
+	todos := &fakeTodos{items: map[string]todosdomain.TodoItem{
+		"item-1": {ID: "item-1"},
+		"item-2": {ID: "item-2", AssigneeID: &kid},
+	}}
+	workouts := &fakeWorkouts{}
+	return NewService(expenses, todos, workouts, fakeLabels{}), expenses, workouts
+}
+
+func statuses(snapshots []Snapshot) []Status {
+	result := make([]Status, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		result = append(result, snapshot.Status)
+	}
+	return result
+}
+
+func TestGetReturnsSnapshotsInRequestOrder(t *testing.T) {
+	service, expenses, _ := newTestService()
+
+	snapshots, err := service.Get(context.Background(), Query{FamilyID: "family-1", UserID: "user-1", Refs: []Ref{
+		{Type: EntityWorkout, ID: "workout-1"},
+		{Type: EntityExpense, ID: "expense-1"},
+		{Type: EntityCategory, ID: "category-2"},
+		{Type: EntityTodoItem, ID: "item-1"},
+		{Type: EntityExpense, ID: " expense-1 "},
+		{Type: EntityCategory, ID: "category-3"},
+		{Type: EntityExpense, ID: "expense-2"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Status{StatusFound, StatusFound, StatusFound, StatusFound, StatusNotFound, StatusNotFound}
+	if got := statuses(snapshots); !reflect.DeepEqual(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+	if snapshots[1].Expense.CategoryIDs[0] != "category-1" || snapshots[2].Category.ID != "category-2" {
+		t.Fatalf("snapshots = %+v", snapshots)
+	}
+	if !reflect.DeepEqual(snapshots[0].Labels, []string{"workout"}) || !reflect.DeepEqual(snapshots[3].Labels, []string{"todo_item"}) {
+		t.Fatalf("labels = %v, %v", snapshots[0].Labels, snapshots[3].Labels)
+	}
+	if expenses.listCalls != 1 {
+		t.Fatalf("categories listed %d times, want once", expenses.listCalls)
+	}
+}
+
+func TestGetForChildHidesFinancesAndOthersItems(t *testing.T) {
+	service, _, _ := newTestService()
+
+	snapshots, err := service.Get(context.Background(), Query{FamilyID: "family-1", UserID: "user-2", Child: true, Refs: []Ref{
+		{Type: EntityExpense, ID: "expense-1"},
+		{Type: EntityCategory, ID: "category-1"},
+		{Type: EntityTodoItem, ID: "item-1"},
+		{Type: EntityTodoItem, ID: "item-2"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Status{StatusForbidden, StatusForbidden, StatusNotFound, StatusFound}
+	if got := statuses(snapshots); !reflect.DeepEqual(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+	if snapshots[0].Expense != nil || snapshots[2].TodoItem != nil {
+		t.Fatalf("hidden records leaked: %+v", snapshots)
+	}
+}
+
+func TestGetValidatesRefsAndFailsOnReadErrors(t *testing.T) {
+	service, _, workouts := newTestService()
+
+	for name, refs := range map[string][]Ref{
+		"empty":        nil,
+		"unknown type": {{Type: "pet", ID: "pet-1"}},
+		"blank id":     {{Type: EntityExpense, ID: " "}},
+	} {
+		if _, err := service.Get(context.Background(), Query{Refs: refs}); !errors.Is(err, ErrInvalidRef) {
+			t.Fatalf("%s: error = %v, want ErrInvalidRef", name, err)
+		}
+	}
+
+	refs := make([]Ref, 0, MaxRefs+1)
+	for i := 0; i <= MaxRefs; i++ {
+		refs = append(refs, Ref{Type: EntityWorkout, ID: string(rune('a'+i%26)) + string(rune('a'+i/26))})
+	}
+	if _, err := service.Get(context.Background(), Query{Refs: refs}); !errors.Is(err, ErrTooManyRefs) {
+		t.Fatalf("too many refs error = %v, want ErrTooManyRefs", err)
+	}
+
+	workouts.err = errors.New("database down")
+	if _, err := service.Get(context.Background(), Query{UserID: "user-1", Refs: []Ref{{Type: EntityWorkout, ID: "workout-1"}}}); !errors.Is(err, workouts.err) {
+		t.Fatalf("error = %v, want the read error", err)
+	}
+}
@@ -0,0 +1,11 @@
+package budgets
+
+import "errors"
+
+var (
+	ErrBudgetNotFound   = errors.New("budget not found")
+	ErrBudgetExists     = errors.New("category already has a budget")
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrInvalidAmount    = errors.New("invalid budget amount")
+	ErrInvalidCurrency  = errors.New("invalid budget currency")
+)
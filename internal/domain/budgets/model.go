@@ -0,0 +1,92 @@
+package budgets
+
+import (
+	"math"
+	"time"
+)
+
+// Thresholds are the shares of a monthly budget, in percent, that raise an
+// alert once spending reaches them.
+var Thresholds = []int{50, 80, 100}
+
+// Budget caps what the family means to spend on one category per calendar
+// month (UTC), in Currency. Expenses count at their amount in that currency,
+// or their own amount when they were recorded in it without conversion.
+type Budget struct {
+	ID         string  `gorm:"type:uuid;primaryKey"`
+	FamilyID   string  `gorm:"type:uuid;not null"`
+	CategoryID string  `gorm:"type:uuid;not null"`
+	Amount     float64 `gorm:"type:numeric(14,2);not null"`
+	Currency   string  `gorm:"size:3;not null"`
+	CreatedBy  string  `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	// CategoryName is read with the budget and never written.
+	CategoryName string `gorm:"->"`
+}
+
+// Status is a budget with what was spent in the current month.
+type Status struct {
+	Budget
+	PeriodStart time.Time
+	Spent       float64
+	// Share is Spent over Amount; it goes past 1 once the budget is
+	// exceeded.
+	Share float64
+}
+
+// Reached returns the highest threshold the spending has reached, or 0. It
+// compares the amounts, not the rounded Share.
+func (s Status) Reached() int {
+	reached := 0
+	for _, threshold := range Thresholds {
+		if s.Amount > 0 && s.Spent*100 >= s.Amount*float64(threshold) {
+			reached = threshold
+		}
+	}
+	return reached
+}
+
+// Alert records that a budget reached a threshold in a period, so each
+// threshold is announced at most once per month.
+type Alert struct {
+	ID         string    `gorm:"type:uuid;primaryKey"`
+	BudgetID   string    `gorm:"type:uuid;not null"`
+	FamilyID   string    `gorm:"type:uuid;not null"`
+	CategoryID string    `gorm:"type:uuid;not null"`
+	Period     time.Time `gorm:"type:date;not null"`
+	Threshold  int       `gorm:"not null"`
+	Spent      float64   `gorm:"type:numeric(14,2);not null"`
+	Amount     float64   `gorm:"type:numeric(14,2);not null"`
+	CreatedAt  time.Time
+}
+
+// AlertResult is an alert the evaluation job raised and who it went to.
+type AlertResult struct {
+	Alert
+	CategoryName string
+	Currency     string
+	Recipients   int
+	PushFailures int
+}
+
+// Input sets a budget. The category cannot change once the budget exists,
+// and an empty Currency keeps the current one on update.
+type Input struct {
+	CategoryID string
+	Amount     float64
+	Currency   string
+}
+
+func monthStartUTC(value time.Time) time.Time {
+	value = value.UTC()
+	return time.Date(value.Year(), value.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func share(spent, amount float64) float64 {
+	if amount <= 0 {
+		return 0
+	}
+	return math.Round(spent/amount*10000) / 10000
+}
@@ -0,0 +1,26 @@
+package budgets
+
+import "context"
+
+type Repository interface {
+	// ListBudgets returns the family's budgets with their category names,
+	// by category name.
+	ListBudgets(ctx context.Context, familyID string) ([]Budget, error)
+	GetBudget(ctx context.Context, familyID, budgetID string) (*Budget, error)
+	// CreateBudget returns ErrBudgetExists when the category already has a
+	// budget.
+	CreateBudget(ctx context.Context, budget *Budget) error
+	UpdateBudget(ctx context.Context, budget *Budget) error
+	DeleteBudget(ctx context.Context, familyID, budgetID string) (bool, error)
+	CategoryExists(ctx context.Context, familyID, categoryID string) (bool, error)
+
+	// ListBudgetFamilies returns the families that have at least one
+	// budget.
+	ListBudgetFamilies(ctx context.Context) ([]string, error)
+	// ListAlertRecipients returns the family members who see the family
+	// finances, which leaves children out.
+	ListAlertRecipients(ctx context.Context, familyID string) ([]string, error)
+	// CreateAlert records an alert unless the budget already has one for
+	// the same period and threshold, and reports whether it was recorded.
+	CreateAlert(ctx context.Context, alert *Alert) (bool, error)
+}
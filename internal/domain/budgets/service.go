@@ -0,0 +1,326 @@
+package budgets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	analyticsdomain "family-app-go/internal/domain/analytics"
+	notificationsdomain "family-app-go/internal/domain/notifications"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/money"
+	"family-app-go/pkg/tracing"
+)
+
+// Spending is implemented by *analyticsdomain.Service.
+type Spending interface {
+	ByCategory(ctx context.Context, familyID string, filter analyticsdomain.ByCategoryFilter) ([]analyticsdomain.ByCategoryRow, error)
+}
+
+// Notifier is implemented by *notificationsdomain.Service.
+type Notifier interface {
+	Deliver(ctx context.Context, notifications []notificationsdomain.Notification) (*notificationsdomain.Delivery, error)
+}
+
+type Service struct {
+	repo     Repository
+	spending Spending
+	notifier Notifier
+	now      func() time.Time
+}
+
+func NewService(repo Repository, spending Spending, notifier Notifier) *Service {
+	return &Service{
+		repo:     repo,
+		spending: spending,
+		notifier: notifier,
+		now:      time.Now,
+	}
+}
+
+// List returns the family's budgets with what was spent on each this month.
+func (s *Service) List(ctx context.Context, familyID string) ([]Status, error) {
+	ctx, span := tracing.Start(ctx, "budgets.List")
+	defer span.End()
+
+	budgets, err := s.repo.ListBudgets(ctx, familyID)
+	if err != nil {
+		return nil, err
+	}
+	return s.statuses(ctx, familyID, budgets)
+}
+
+func (s *Service) Get(ctx context.Context, familyID, budgetID string) (*Status, error) {
+	ctx, span := tracing.Start(ctx, "budgets.Get")
+	defer span.End()
+
+	return s.status(ctx, familyID, budgetID)
+}
+
+func (s *Service) Create(ctx context.Context, familyID, createdBy string, input Input) (*Status, error) {
+	ctx, span := tracing.Start(ctx, "budgets.Create")
+	defer span.End()
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	budget := Budget{ID: newID, FamilyID: familyID, CategoryID: strings.TrimSpace(input.CategoryID), CreatedBy: createdBy}
+	if err := applyInput(&budget, input); err != nil {
+		return nil, err
+	}
+	exists, err := s.repo.CategoryExists(ctx, familyID, budget.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCategoryNotFound
+	}
+	if err := s.repo.CreateBudget(ctx, &budget); err != nil {
+		return nil, err
+	}
+	return s.status(ctx, familyID, budget.ID)
+}
+
+// Update changes a budget's amount and, when given, its currency. Alerts
+// already raised this month stay raised, so a higher amount does not repeat
+// them.
+func (s *Service) Update(ctx context.Context, familyID, budgetID string, input Input) (*Status, error) {
+	ctx, span := tracing.Start(ctx, "budgets.Update")
+	defer span.End()
+
+	budget, err := s.repo.GetBudget(ctx, familyID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyInput(budget, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateBudget(ctx, budget); err != nil {
+		return nil, err
+	}
+	return s.status(ctx, familyID, budgetID)
+}
+
+func (s *Service) Delete(ctx context.Context, familyID, budgetID string) error {
+	ctx, span := tracing.Start(ctx, "budgets.Delete")
+	defer span.End()
+
+	deleted, err := s.repo.DeleteBudget(ctx, familyID, budgetID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// EvaluateAlerts checks every budget against this month's spending and
+// raises an alert for each threshold reached for the first time in the
+// month. Members who see the family finances get one notification per
+// budget, for the highest threshold it newly reached. A family that fails
+// does not stop the others.
+func (s *Service) EvaluateAlerts(ctx context.Context) ([]AlertResult, error) {
+	ctx, span := tracing.Start(ctx, "budgets.EvaluateAlerts")
+	defer span.End()
+
+	familyIDs, err := s.repo.ListBudgetFamilies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []AlertResult
+	var errs []error
+	for _, familyID := range familyIDs {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		familyResults, err := s.evaluateFamily(ctx, familyID)
+		results = append(results, familyResults...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("family %s: %w", familyID, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+func (s *Service) evaluateFamily(ctx context.Context, familyID string) ([]AlertResult, error) {
+	budgets, err := s.repo.ListBudgets(ctx, familyID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.statuses(ctx, familyID, budgets)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results    []AlertResult
+		recipients []string
+		loaded     bool
+	)
+	for _, status := range statuses {
+		alert, err := s.recordAlerts(ctx, status)
+		if err != nil {
+			return results, err
+		}
+		if alert == nil {
+			continue
+		}
+
+		if !loaded {
+			if recipients, err = s.repo.ListAlertRecipients(ctx, familyID); err != nil {
+				return results, err
+			}
+			loaded = true
+		}
+		result := AlertResult{Alert: *alert, CategoryName: status.CategoryName, Currency: status.Currency, Recipients: len(recipients)}
+		delivery, err := s.notifier.Deliver(ctx, alertNotifications(status, alert.Threshold, recipients))
+		if err != nil {
+			return results, err
+		}
+		result.PushFailures = len(delivery.PushErrors)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// recordAlerts records every threshold the budget reached and returns the
+// highest one not recorded before this run, or nil.
+func (s *Service) recordAlerts(ctx context.Context, status Status) (*Alert, error) {
+	var newest *Alert
+	for _, threshold := range Thresholds {
+		if threshold > status.Reached() {
+			break
+		}
+		alertID, err := id.New()
+		if err != nil {
+			return nil, err
+		}
+		alert := Alert{
+			ID:         alertID,
+			BudgetID:   status.ID,
+			FamilyID:   status.FamilyID,
+			CategoryID: status.CategoryID,
+			Period:     status.PeriodStart,
+			Threshold:  threshold,
+			Spent:      status.Spent,
+			Amount:     status.Amount,
+			CreatedAt:  s.now().UTC(),
+		}
+		created, err := s.repo.CreateAlert(ctx, &alert)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			newest = &alert
+		}
+	}
+	return newest, nil
+}
+
+func (s *Service) status(ctx context.Context, familyID, budgetID string) (*Status, error) {
+	budget, err := s.repo.GetBudget(ctx, familyID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.statuses(ctx, familyID, []Budget{*budget})
+	if err != nil {
+		return nil, err
+	}
+	return &statuses[0], nil
+}
+
+// statuses adds this month's spending to budgets, with one analytics query
+// per budget currency.
+func (s *Service) statuses(ctx context.Context, familyID string, budgets []Budget) ([]Status, error) {
+	periodStart := monthStartUTC(s.now())
+	periodEnd := periodStart.AddDate(0, 1, -1)
+
+	categoriesByCurrency := make(map[string][]string)
+	for _, budget := range budgets {
+		categoriesByCurrency[budget.Currency] = append(categoriesByCurrency[budget.Currency], budget.CategoryID)
+	}
+	spent := make(map[string]map[string]float64, len(categoriesByCurrency))
+	for currency, categoryIDs := range categoriesByCurrency {
+		rows, err := s.spending.ByCategory(ctx, familyID, analyticsdomain.ByCategoryFilter{
+			From:          periodStart,
+			To:            periodEnd,
+			Currency:      currency,
+			UseBaseAmount: true,
+			CategoryIDs:   categoryIDs,
+			Limit:         len(categoryIDs),
+		})
+		if err != nil {
+			return nil, err
+		}
+		totals := make(map[string]float64, len(rows))
+		for _, row := range rows {
+			totals[row.CategoryID] = row.Total
+		}
+		spent[currency] = totals
+	}
+
+	statuses := make([]Status, 0, len(budgets))
+	for _, budget := range budgets {
+		total := money.Round(spent[budget.Currency][budget.CategoryID], budget.Currency)
+		statuses = append(statuses, Status{
+			Budget:      budget,
+			PeriodStart: periodStart,
+			Spent:       total,
+			Share:       share(total, budget.Amount),
+		})
+	}
+	return statuses, nil
+}
+
+func alertNotifications(status Status, threshold int, recipients []string) []notificationsdomain.Notification {
+	title := fmt.Sprintf("%s budget at %d%%", status.CategoryName, threshold)
+	if threshold >= 100 {
+		title = fmt.Sprintf("%s budget used up", status.CategoryName)
+	}
+	body := fmt.Sprintf("Spent %s of %s %s in %s.",
+		formatAmount(status.Spent, status.Currency),
+		formatAmount(status.Amount, status.Currency),
+		status.Currency,
+		status.PeriodStart.Format("January 2006"),
+	)
+
+	notifications := make([]notificationsdomain.Notification, 0, len(recipients))
+	for _, userID := range recipients {
+		notifications = append(notifications, notificationsdomain.Notification{
+			UserID:   userID,
+			FamilyID: status.FamilyID,
+			Kind:     notificationsdomain.KindBudgetThreshold,
+			Title:    title,
+			Body:     body,
+			Link:     "/budgets/" + status.ID,
+		})
+	}
+	return notifications
+}
+
+func formatAmount(amount float64, currency string) string {
+	return strconv.FormatFloat(amount, 'f', money.Decimals(currency), 64)
+}
+
+func applyInput(budget *Budget, input Input) error {
+	currency := strings.ToUpper(strings.TrimSpace(input.Currency))
+	if currency == "" {
+		currency = budget.Currency
+	}
+	if !money.IsCurrency(currency) {
+		return ErrInvalidCurrency
+	}
+	if input.Amount <= 0 || money.CheckPrecision(input.Amount, currency) != nil {
+		return ErrInvalidAmount
+	}
+	budget.Amount = input.Amount
+	budget.Currency = currency
+	return nil
+}
@@ -0,0 +1,168 @@
+package budgets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	analyticsdomain "family-app-go/internal/domain/analytics"
+	notificationsdomain "family-app-go/internal/domain/notifications"
+)
+
+type fakeBudgetsRepo struct {
+	Repository
+	budgets    []Budget
+	recipients []string
+	alerts     map[string]Alert
+}
+
+func (r *fakeBudgetsRepo) ListBudgetFamilies(context.Context) ([]string, error) {
+	return []string{"family-1"}, nil
+}
+
+func (r *fakeBudgetsRepo) ListBudgets(context.Context, string) ([]Budget, error) {
+	return r.budgets, nil
+}
+
+func (r *fakeBudgetsRepo) ListAlertRecipients(context.Context, string) ([]string, error) {
+	return r.recipients, nil
+}
+
+func (r *fakeBudgetsRepo) CreateAlert(_ context.Context, alert *Alert) (bool, error) {
+	key := fmt.Sprintf("%s/%s/%d", alert.BudgetID, alert.Period.Format("2006-01-02"), alert.Threshold)
+	if _, ok := r.alerts[key]; ok {
+		return false, nil
+	}
+	r.alerts[key] = *alert
+	return true, nil
+}
+
+type fakeSpending struct {
+	totals  map[string]float64
+	filters []analyticsdomain.ByCategoryFilter
+}
+
+func (s *fakeSpending) ByCategory(_ context.Context, _ string, filter analyticsdomain.ByCategoryFilter) ([]analyticsdomain.ByCategoryRow, error) {
+	s.filters = append(s.filters, filter)
+	var rows []analyticsdomain.ByCategoryRow
+	for _, categoryID := range filter.CategoryIDs {
+		if total, ok := s.totals[categoryID]; ok {
+			rows = append(rows, analyticsdomain.ByCategoryRow{CategoryID: categoryID, Total: total})
+		}
+	}
+	return rows, nil
+}
+
+type fakeNotifier struct {
+	sent [][]notificationsdomain.Notification
+}
+
+func (n *fakeNotifier) Deliver(_ context.Context, notifications []notificationsdomain.Notification) (*notificationsdomain.Delivery, error) {
+	n.sent = append(n.sent, notifications)
+	return &notificationsdomain.Delivery{Notifications: notifications}, nil
+}
+
+func newAlertsService(spent float64) (*Service, *fakeSpending, *fakeNotifier) {
+	repo := &fakeBudgetsRepo{
+		budgets: []Budget{{
+			ID:           "budget-1",
+			FamilyID:     "family-1",
+			CategoryID:   "category-1",
+			CategoryName: "Groceries",
+			Amount:       500,
+			Currency:     "EUR",
+		}},
+		recipients: []string{"owner", "member"},
+		alerts:     map[string]Alert{},
+	}
+	spending := &fakeSpending{totals: map[string]float64{"category-1": spent}}
+	notifier := &fakeNotifier{}
+	service := NewService(repo, spending, notifier)
+	service.now = func() time.Time { return time.Date(2026, time.March, 18, 9, 30, 0, 0, time.UTC) }
+	return service, spending, notifier
+}
+
+func TestEvaluateAlertsSendsTheHighestNewThresholdOncePerMonth(t *testing.T) {
+	service, spending, notifier := newAlertsService(420)
+
+	results, err := service.EvaluateAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Threshold != 80 || results[0].Recipients != 2 {
+		t.Fatalf("results = %+v, want one 80%% alert for two members", results)
+	}
+	if len(notifier.sent) != 1 || len(notifier.sent[0]) != 2 {
+		t.Fatalf("sent %d deliveries, want one to both members", len(notifier.sent))
+	}
+	notification := notifier.sent[0][0]
+	if notification.UserID != "owner" || notification.Kind != notificationsdomain.KindBudgetThreshold ||
+		notification.Title != "Groceries budget at 80%" || notification.Body != "Spent 420.00 of 500.00 EUR in March 2026." ||
+		notification.Link != "/budgets/budget-1" {
+		t.Fatalf("notification = %+v", notification)
+	}
+
+	filter := spending.filters[0]
+	if !filter.From.Equal(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)) ||
+		!filter.To.Equal(time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)) ||
+		filter.Currency != "EUR" || !filter.UseBaseAmount {
+		t.Fatalf("filter = %+v, want March in EUR base amounts", filter)
+	}
+
+	// The next hourly run finds nothing new to announce.
+	results, err = service.EvaluateAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 || len(notifier.sent) != 1 {
+		t.Fatalf("second run sent %d alerts, want none", len(results))
+	}
+}
+
+func TestEvaluateAlertsAnnouncesTheNextThreshold(t *testing.T) {
+	service, spending, notifier := newAlertsService(260)
+
+	if _, err := service.EvaluateAlerts(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spending.totals["category-1"] = 510
+	results, err := service.EvaluateAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Threshold != 100 {
+		t.Fatalf("results = %+v, want the 100%% alert", results)
+	}
+	if got := notifier.sent[1][0].Title; got != "Groceries budget used up" {
+		t.Fatalf("title = %q", got)
+	}
+}
+
+func TestEvaluateAlertsSkipsBudgetsBelowHalf(t *testing.T) {
+	service, _, notifier := newAlertsService(249.99)
+
+	results, err := service.EvaluateAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 || len(notifier.sent) != 0 {
+		t.Fatalf("sent %+v, want nothing below 50%%", results)
+	}
+}
+
+func TestApplyInputChecksAmountAndCurrency(t *testing.T) {
+	budget := Budget{Currency: "EUR"}
+	if err := applyInput(&budget, Input{Amount: 250}); err != nil || budget.Currency != "EUR" {
+		t.Fatalf("kept currency %q, err %v", budget.Currency, err)
+	}
+	if err := applyInput(&budget, Input{Amount: 0}); err != ErrInvalidAmount {
+		t.Fatalf("zero amount err = %v", err)
+	}
+	if err := applyInput(&budget, Input{Amount: 10.5, Currency: "jpy"}); err != ErrInvalidAmount {
+		t.Fatalf("fractional yen err = %v", err)
+	}
+	if err := applyInput(&budget, Input{Amount: 10, Currency: "XXX1"}); err != ErrInvalidCurrency {
+		t.Fatalf("bad currency err = %v", err)
+	}
+}
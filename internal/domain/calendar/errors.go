@@ -0,0 +1,9 @@
+package calendar
+
+import "errors"
+
+var (
+	ErrFeedDisabled     = errors.New("calendar feed disabled")
+	ErrInvalidFeedToken = errors.New("invalid calendar feed token")
+	ErrFeedKeyNotFound  = errors.New("calendar feed key not found")
+)
@@ -0,0 +1,24 @@
+package calendar
+
+import "time"
+
+// Event is a single all-day entry of the calendar feed.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Date        time.Time
+	CreatedAt   time.Time
+}
+
+// FeedKey holds the nonce mixed into a user's feed token. Replacing it
+// revokes every token issued before.
+type FeedKey struct {
+	UserID    string    `gorm:"type:uuid;primaryKey"`
+	Nonce     string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (FeedKey) TableName() string {
+	return "calendar_feed_keys"
+}
@@ -0,0 +1,13 @@
+package calendar
+
+import "context"
+
+type Repository interface {
+	// GetFeedKey returns ErrFeedKeyNotFound until a token was issued.
+	GetFeedKey(ctx context.Context, userID string) (*FeedKey, error)
+	// EnsureFeedKey stores key unless the user has one and returns the
+	// stored key.
+	EnsureFeedKey(ctx context.Context, key *FeedKey) (*FeedKey, error)
+	// ReplaceFeedKey stores key over the user's current one.
+	ReplaceFeedKey(ctx context.Context, key *FeedKey) error
+}
@@ -0,0 +1,199 @@
+package calendar
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	feedTokenScope = "calendar-feed:"
+	feedNonceBytes = 16
+)
+
+type FamilyProvider interface {
+	GetFamilyByUser(ctx context.Context, userID string) (*familydomain.Family, error)
+}
+
+type TodoProvider interface {
+	ListDueTodoItems(ctx context.Context, familyID, viewerID string) ([]todosdomain.DueTodoItem, error)
+}
+
+type Service struct {
+	repo     Repository
+	families FamilyProvider
+	todos    TodoProvider
+	secret   []byte
+}
+
+// NewService creates the calendar feed service. An empty secret disables the
+// feed: tokens can be neither issued nor verified.
+func NewService(repo Repository, families FamilyProvider, todos TodoProvider, secret string) *Service {
+	return &Service{
+		repo:     repo,
+		families: families,
+		todos:    todos,
+		secret:   []byte(strings.TrimSpace(secret)),
+	}
+}
+
+func (s *Service) Enabled() bool {
+	return len(s.secret) > 0
+}
+
+// IssueFeedToken returns the subscription token for the user. The token stays
+// the same across requests, so calendar apps keep working, until the user
+// rotates it, the account is erased or the feed secret changes.
+func (s *Service) IssueFeedToken(ctx context.Context, userID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "calendar.IssueFeedToken")
+	defer span.End()
+
+	if !s.Enabled() {
+		return "", ErrFeedDisabled
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return "", ErrInvalidFeedToken
+	}
+
+	key, err := newFeedKey(userID)
+	if err != nil {
+		return "", err
+	}
+	key, err = s.repo.EnsureFeedKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return s.token(key), nil
+}
+
+// RotateFeedToken replaces the user's feed key and returns the new token;
+// tokens issued before stop verifying.
+func (s *Service) RotateFeedToken(ctx context.Context, userID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "calendar.RotateFeedToken")
+	defer span.End()
+
+	if !s.Enabled() {
+		return "", ErrFeedDisabled
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return "", ErrInvalidFeedToken
+	}
+
+	key, err := newFeedKey(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.repo.ReplaceFeedKey(ctx, key); err != nil {
+		return "", err
+	}
+	return s.token(key), nil
+}
+
+// VerifyFeedToken checks the token signature against the user's current feed
+// key and returns the user it was issued for.
+func (s *Service) VerifyFeedToken(ctx context.Context, token string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrFeedDisabled
+	}
+
+	encodedUser, encodedSignature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || encodedUser == "" || encodedSignature == "" {
+		return "", ErrInvalidFeedToken
+	}
+	rawUser, err := base64.RawURLEncoding.DecodeString(encodedUser)
+	if err != nil {
+		return "", ErrInvalidFeedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return "", ErrInvalidFeedToken
+	}
+	userID := string(rawUser)
+	if userID == "" {
+		return "", ErrInvalidFeedToken
+	}
+
+	key, err := s.repo.GetFeedKey(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrFeedKeyNotFound) {
+			return "", ErrInvalidFeedToken
+		}
+		return "", err
+	}
+	if !hmac.Equal(signature, s.sign(key)) {
+		return "", ErrInvalidFeedToken
+	}
+	return userID, nil
+}
+
+// FeedEvents resolves the token owner and returns the open todo items of their
+// family that have a due date.
+func (s *Service) FeedEvents(ctx context.Context, token string) ([]Event, error) {
+	ctx, span := tracing.Start(ctx, "calendar.FeedEvents")
+	defer span.End()
+
+	userID, err := s.VerifyFeedToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.todos.ListDueTodoItems(ctx, family.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(items))
+	for _, due := range items {
+		if due.Item.DueDate == nil {
+			continue
+		}
+		events = append(events, Event{
+			UID:         "todo-" + due.Item.ID,
+			Summary:     due.Item.Title,
+			Description: due.ListTitle,
+			Date:        *due.Item.DueDate,
+			CreatedAt:   due.Item.CreatedAt,
+		})
+	}
+	return events, nil
+}
+
+func (s *Service) token(key *FeedKey) string {
+	encodedUser := base64.RawURLEncoding.EncodeToString([]byte(key.UserID))
+	signature := base64.RawURLEncoding.EncodeToString(s.sign(key))
+	return encodedUser + "." + signature
+}
+
+func (s *Service) sign(key *FeedKey) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(feedTokenScope + key.UserID + ":" + key.Nonce))
+	return mac.Sum(nil)
+}
+
+func newFeedKey(userID string) (*FeedKey, error) {
+	nonce := make([]byte, feedNonceBytes)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &FeedKey{
+		UserID:    userID,
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
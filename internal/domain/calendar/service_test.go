@@ -0,0 +1,190 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testUserID   = "22222222-2222-2222-2222-222222222222"
+)
+
+type fakeFamilyProvider struct{}
+
+func (fakeFamilyProvider) GetFamilyByUser(_ context.Context, userID string) (*familydomain.Family, error) {
+	if userID == testUserID {
+		return &familydomain.Family{ID: testFamilyID}, nil
+	}
+	return nil, familydomain.ErrFamilyNotFound
+}
+
+type fakeTodoProvider struct {
+	items     []todosdomain.DueTodoItem
+	familyIDs []string
+}
+
+func (f *fakeTodoProvider) ListDueTodoItems(_ context.Context, familyID, _ string) ([]todosdomain.DueTodoItem, error) {
+	f.familyIDs = append(f.familyIDs, familyID)
+	return f.items, nil
+}
+
+type fakeFeedKeys struct {
+	keys map[string]FeedKey
+}
+
+func newFakeFeedKeys() *fakeFeedKeys {
+	return &fakeFeedKeys{keys: make(map[string]FeedKey)}
+}
+
+func (r *fakeFeedKeys) GetFeedKey(_ context.Context, userID string) (*FeedKey, error) {
+	key, ok := r.keys[userID]
+	if !ok {
+		return nil, ErrFeedKeyNotFound
+	}
+	return &key, nil
+}
+
+func (r *fakeFeedKeys) EnsureFeedKey(ctx context.Context, key *FeedKey) (*FeedKey, error) {
+	if _, ok := r.keys[key.UserID]; !ok {
+		r.keys[key.UserID] = *key
+	}
+	return r.GetFeedKey(ctx, key.UserID)
+}
+
+func (r *fakeFeedKeys) ReplaceFeedKey(_ context.Context, key *FeedKey) error {
+	r.keys[key.UserID] = *key
+	return nil
+}
+
+func TestFeedTokenRoundTrip(t *testing.T) {
+	service := NewService(newFakeFeedKeys(), fakeFamilyProvider{}, &fakeTodoProvider{}, "secret")
+
+	token, err := service.IssueFeedToken(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	userID, err := service.VerifyFeedToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("verify token: %v", err)
+	}
+	if userID != testUserID {
+		t.Fatalf("expected user %s, got %s", testUserID, userID)
+	}
+}
+
+func TestVerifyFeedTokenRejectsForeignSignature(t *testing.T) {
+	issuer := NewService(newFakeFeedKeys(), fakeFamilyProvider{}, &fakeTodoProvider{}, "other-secret")
+	token, err := issuer.IssueFeedToken(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	service := NewService(newFakeFeedKeys(), fakeFamilyProvider{}, &fakeTodoProvider{}, "secret")
+	for _, candidate := range []string{token, "", "garbage", testUserID + "."} {
+		if _, err := service.VerifyFeedToken(context.Background(), candidate); !errors.Is(err, ErrInvalidFeedToken) {
+			t.Fatalf("expected ErrInvalidFeedToken for %q, got %v", candidate, err)
+		}
+	}
+}
+
+func TestFeedDisabledWithoutSecret(t *testing.T) {
+	service := NewService(newFakeFeedKeys(), fakeFamilyProvider{}, &fakeTodoProvider{}, " ")
+
+	if _, err := service.IssueFeedToken(context.Background(), testUserID); !errors.Is(err, ErrFeedDisabled) {
+		t.Fatalf("expected ErrFeedDisabled, got %v", err)
+	}
+	if _, err := service.FeedEvents(context.Background(), "a.b"); !errors.Is(err, ErrFeedDisabled) {
+		t.Fatalf("expected ErrFeedDisabled, got %v", err)
+	}
+}
+
+func TestFeedEventsUsesTokenOwnerFamily(t *testing.T) {
+	dueDate := time.Date(2026, 5, 12, 0, 0, 0, 0, time.UTC)
+	todos := &fakeTodoProvider{items: []todosdomain.DueTodoItem{
+		{
+			Item:      todosdomain.TodoItem{ID: "item-1", Title: "Pay rent", DueDate: &dueDate},
+			ListTitle: "Home",
+		},
+		{
+			Item:      todosdomain.TodoItem{ID: "item-2", Title: "No date"},
+			ListTitle: "Home",
+		},
+	}}
+	service := NewService(newFakeFeedKeys(), fakeFamilyProvider{}, todos, "secret")
+
+	token, err := service.IssueFeedToken(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	events, err := service.FeedEvents(context.Background(), token)
+	if err != nil {
+		t.Fatalf("feed events: %v", err)
+	}
+
+	if len(todos.familyIDs) != 1 || todos.familyIDs[0] != testFamilyID {
+		t.Fatalf("expected items to be loaded for family %s, got %v", testFamilyID, todos.familyIDs)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %#v", events)
+	}
+	if events[0].UID != "todo-item-1" || events[0].Summary != "Pay rent" || !events[0].Date.Equal(dueDate) {
+		t.Fatalf("unexpected event: %#v", events[0])
+	}
+}
+
+func TestFeedTokenIsStableUntilRotated(t *testing.T) {
+	service := NewService(newFakeFeedKeys(), fakeFamilyProvider{}, &fakeTodoProvider{}, "secret")
+	ctx := context.Background()
+
+	first, err := service.IssueFeedToken(ctx, testUserID)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	again, err := service.IssueFeedToken(ctx, testUserID)
+	if err != nil {
+		t.Fatalf("issue token again: %v", err)
+	}
+	if again != first {
+		t.Fatalf("expected the same token, got %q and %q", first, again)
+	}
+
+	rotated, err := service.RotateFeedToken(ctx, testUserID)
+	if err != nil {
+		t.Fatalf("rotate token: %v", err)
+	}
+	if rotated == first {
+		t.Fatal("expected a new token after rotating")
+	}
+	if _, err := service.VerifyFeedToken(ctx, first); !errors.Is(err, ErrInvalidFeedToken) {
+		t.Fatalf("expected ErrInvalidFeedToken for the old token, got %v", err)
+	}
+	if userID, err := service.VerifyFeedToken(ctx, rotated); err != nil || userID != testUserID {
+		t.Fatalf("verify rotated token: user %q, err %v", userID, err)
+	}
+	if current, err := service.IssueFeedToken(ctx, testUserID); err != nil || current != rotated {
+		t.Fatalf("expected the rotated token to be issued, got %q, err %v", current, err)
+	}
+}
+
+// Erasing the account deletes the feed key, which revokes the token.
+func TestFeedTokenRejectedWithoutFeedKey(t *testing.T) {
+	keys := newFakeFeedKeys()
+	service := NewService(keys, fakeFamilyProvider{}, &fakeTodoProvider{}, "secret")
+
+	token, err := service.IssueFeedToken(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	delete(keys.keys, testUserID)
+
+	if _, err := service.VerifyFeedToken(context.Background(), token); !errors.Is(err, ErrInvalidFeedToken) {
+		t.Fatalf("expected ErrInvalidFeedToken, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package comments
+
+import "errors"
+
+var (
+	ErrCommentNotFound   = errors.New("comment not found")
+	ErrInvalidBody       = errors.New("invalid comment body")
+	ErrInvalidEntityType = errors.New("invalid comment entity type")
+	ErrNotAuthor         = errors.New("only the author can change a comment")
+)
@@ -0,0 +1,139 @@
+package comments
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	// DefaultMentionLimit and MaxMentionLimit page the mention inbox.
+	DefaultMentionLimit = 50
+	MaxMentionLimit     = 100
+)
+
+// mentionPattern matches @handle where the @ does not follow a letter or
+// digit, so email addresses in the text are not mentions. A handle is an
+// email local part, optionally followed by its domain.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@([\p{L}\p{N}_.%+-]+(?:@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+)?)`)
+
+// parseHandles returns the lowercased handles mentioned in body, each once,
+// in order of appearance.
+func parseHandles(body string) []string {
+	var handles []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if handle != "" && !slices.Contains(handles, handle) {
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+// resolveHandles returns the IDs of the members the handles name. A handle
+// without a domain names the member whose email starts with it, unless
+// several do.
+func resolveHandles(handles []string, members []Member) []string {
+	byEmail := make(map[string]string, len(members))
+	byLocal := make(map[string][]string, len(members))
+	for _, member := range members {
+		email := strings.ToLower(strings.TrimSpace(member.Email))
+		local, _, ok := strings.Cut(email, "@")
+		if !ok || local == "" {
+			continue
+		}
+		byEmail[email] = member.UserID
+		byLocal[local] = append(byLocal[local], member.UserID)
+	}
+
+	var userIDs []string
+	for _, handle := range handles {
+		userID, ok := byEmail[handle]
+		if !ok {
+			if matches := byLocal[handle]; len(matches) == 1 {
+				userID, ok = matches[0], true
+			}
+		}
+		if ok && !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// newMentions builds the inbox entries for the members comment mentions,
+// leaving out its author, members outside audience and children, who
+// cannot open comment threads.
+func (s *Service) newMentions(ctx context.Context, comment *Comment, audience []string) ([]Mention, error) {
+	handles := parseHandles(comment.Body)
+	if len(handles) == 0 {
+		return nil, nil
+	}
+	members, err := s.repo.ListMembers(ctx, comment.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string]bool, len(members))
+	for _, member := range members {
+		children[member.UserID] = member.Child
+	}
+
+	var mentions []Mention
+	for _, userID := range resolveHandles(handles, members) {
+		if userID == comment.AuthorID {
+			continue
+		}
+		if audience != nil && !slices.Contains(audience, userID) {
+			continue
+		}
+		if children[userID] {
+			continue
+		}
+		mentionID, err := id.New()
+		if err != nil {
+			return nil, err
+		}
+		mentions = append(mentions, Mention{
+			ID:         mentionID,
+			FamilyID:   comment.FamilyID,
+			UserID:     userID,
+			CommentID:  comment.ID,
+			EntityType: comment.EntityType,
+			EntityID:   comment.EntityID,
+			CreatedAt:  comment.UpdatedAt,
+		})
+	}
+	return mentions, nil
+}
+
+// Mentions returns a page of the comments that mentioned userID, newest
+// first.
+func (s *Service) Mentions(ctx context.Context, familyID, userID string, filter MentionFilter) (*MentionPage, error) {
+	ctx, span := tracing.Start(ctx, "comments.Mentions")
+	defer span.End()
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultMentionLimit
+	}
+	if filter.Limit > MaxMentionLimit {
+		filter.Limit = MaxMentionLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListMentions(ctx, familyID, userID, filter)
+}
+
+// MarkMentionsRead marks the given mentions of userID read, or all of them
+// when mentionIDs is empty. It returns how many were unread.
+func (s *Service) MarkMentionsRead(ctx context.Context, familyID, userID string, mentionIDs []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "comments.MarkMentionsRead")
+	defer span.End()
+
+	return s.repo.MarkMentionsRead(ctx, familyID, userID, mentionIDs, s.now().UTC())
+}
@@ -0,0 +1,137 @@
+package comments
+
+import "time"
+
+// EntityType names the kind of record a comment thread belongs to.
+type EntityType string
+
+const (
+	EntityExpense  EntityType = "expense"
+	EntityTodoItem EntityType = "todo_item"
+
+	// MaxBodyLength caps a comment, in characters.
+	MaxBodyLength = 2000
+	// DefaultLimit and MaxLimit page a thread.
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+// Comment is one message in the thread of a record. The author is a
+// snapshot taken when the comment was written, as in the activity log.
+type Comment struct {
+	ID              string     `gorm:"type:uuid;primaryKey"`
+	FamilyID        string     `gorm:"type:uuid;not null"`
+	EntityType      EntityType `gorm:"type:varchar(32);not null"`
+	EntityID        string     `gorm:"type:uuid;not null"`
+	AuthorID        string     `gorm:"type:uuid;not null"`
+	AuthorName      string     `gorm:"not null"`
+	AuthorEmail     string     `gorm:"not null"`
+	AuthorAvatarURL *string    `gorm:"column:author_avatar_url"`
+	Body            string     `gorm:"not null;serializer:encrypted"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// Read records how far a user has read a thread. Comments created after
+// LastReadAt by someone else are unread.
+type Read struct {
+	UserID     string     `gorm:"type:uuid;primaryKey"`
+	EntityType EntityType `gorm:"type:varchar(32);primaryKey"`
+	EntityID   string     `gorm:"type:uuid;primaryKey"`
+	FamilyID   string     `gorm:"type:uuid;not null"`
+	LastReadAt time.Time  `gorm:"not null"`
+}
+
+func (Read) TableName() string {
+	return "comment_reads"
+}
+
+// Mention is the inbox entry of a member named in a comment with @handle.
+// Comment is loaded with it for the inbox.
+type Mention struct {
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	FamilyID   string     `gorm:"type:uuid;not null"`
+	UserID     string     `gorm:"type:uuid;not null"`
+	CommentID  string     `gorm:"type:uuid;not null"`
+	EntityType EntityType `gorm:"type:varchar(32);not null"`
+	EntityID   string     `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time
+	ReadAt     *time.Time
+
+	Comment Comment `gorm:"foreignKey:CommentID"`
+}
+
+func (Mention) TableName() string {
+	return "comment_mentions"
+}
+
+// Member is someone a comment can mention. Their handle is their email or,
+// when no other member shares it, the part before the @.
+type Member struct {
+	UserID string
+	Email  string
+	Child  bool
+}
+
+type Author struct {
+	ID        string
+	Name      string
+	Email     string
+	AvatarURL string
+}
+
+// Counts sums up a thread for one reader. Their own comments are never
+// unread.
+type Counts struct {
+	Total  int64
+	Unread int64
+}
+
+// Thread identifies the comments of one record.
+type Thread struct {
+	FamilyID   string
+	EntityType EntityType
+	EntityID   string
+}
+
+type CreateInput struct {
+	FamilyID   string
+	EntityType EntityType
+	EntityID   string
+	Author     Author
+	Body       string
+	// Audience limits who can be mentioned to the members who see the
+	// record; nil leaves every member.
+	Audience []string
+}
+
+type UpdateInput struct {
+	Thread    Thread
+	CommentID string
+	AuthorID  string
+	Body      string
+	Audience  []string
+}
+
+type ListFilter struct {
+	Limit  int
+	Offset int
+}
+
+type MentionFilter struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// MentionPage is a page of a member's mentions, newest first. Total counts
+// the filtered mentions, Unread all unread ones.
+type MentionPage struct {
+	Items  []Mention
+	Total  int64
+	Unread int64
+}
@@ -0,0 +1,29 @@
+package comments
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// CreateComment stores a comment with the mentions it makes.
+	CreateComment(ctx context.Context, comment *Comment, mentions []Mention) error
+	GetComment(ctx context.Context, familyID, commentID string) (*Comment, error)
+	// UpdateComment saves an edited comment. Members it already mentioned
+	// keep their mention as it was.
+	UpdateComment(ctx context.Context, comment *Comment, mentions []Mention) error
+	DeleteComment(ctx context.Context, familyID, commentID string) (bool, error)
+	// ListComments returns a thread oldest first, with its total size.
+	ListComments(ctx context.Context, familyID string, entityType EntityType, entityID string, filter ListFilter) ([]Comment, int64, error)
+	// MarkRead moves the reader's position forward; it never moves it back.
+	MarkRead(ctx context.Context, read *Read) error
+	// CountComments returns the counts of each thread seen by userID, keyed
+	// by record ID. Records without comments are left out.
+	CountComments(ctx context.Context, familyID string, entityType EntityType, entityIDs []string, userID string) (map[string]Counts, error)
+	DeleteByEntity(ctx context.Context, entityType EntityType, entityID string) error
+	ListMembers(ctx context.Context, familyID string) ([]Member, error)
+	ListMentions(ctx context.Context, familyID, userID string, filter MentionFilter) (*MentionPage, error)
+	// MarkMentionsRead marks the user's unread mentions read, all of them
+	// when mentionIDs is empty, and returns how many changed.
+	MarkMentionsRead(ctx context.Context, familyID, userID string, mentionIDs []string, readAt time.Time) (int64, error)
+}
@@ -0,0 +1,195 @@
+package comments
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// Service keeps comment threads on records owned by other domains and the
+// inbox of members mentioned in them. Callers check that the user may see
+// the record before touching its thread.
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Comment, error) {
+	ctx, span := tracing.Start(ctx, "comments.Create")
+	defer span.End()
+
+	if !validEntityType(input.EntityType) {
+		return nil, ErrInvalidEntityType
+	}
+	body, err := normalizeBody(input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	comment := Comment{
+		ID:          newID,
+		FamilyID:    input.FamilyID,
+		EntityType:  input.EntityType,
+		EntityID:    input.EntityID,
+		AuthorID:    input.Author.ID,
+		AuthorName:  input.Author.Name,
+		AuthorEmail: input.Author.Email,
+		Body:        body,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if input.Author.AvatarURL != "" {
+		avatarURL := input.Author.AvatarURL
+		comment.AuthorAvatarURL = &avatarURL
+	}
+	mentions, err := s.newMentions(ctx, &comment, input.Audience)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateComment(ctx, &comment, mentions); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// List returns a page of the thread, oldest first, and marks it read for
+// the reader.
+func (s *Service) List(ctx context.Context, thread Thread, readerID string, filter ListFilter) ([]Comment, int64, error) {
+	ctx, span := tracing.Start(ctx, "comments.List")
+	defer span.End()
+
+	if !validEntityType(thread.EntityType) {
+		return nil, 0, ErrInvalidEntityType
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	items, total, err := s.repo.ListComments(ctx, thread.FamilyID, thread.EntityType, thread.EntityID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.repo.MarkRead(ctx, &Read{
+		UserID:     readerID,
+		EntityType: thread.EntityType,
+		EntityID:   thread.EntityID,
+		FamilyID:   thread.FamilyID,
+		LastReadAt: s.now().UTC(),
+	}); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Update replaces the body of the author's own comment in its thread.
+// Members the edit mentions for the first time are notified.
+func (s *Service) Update(ctx context.Context, input UpdateInput) (*Comment, error) {
+	ctx, span := tracing.Start(ctx, "comments.Update")
+	defer span.End()
+
+	normalized, err := normalizeBody(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.authored(ctx, input.Thread, input.CommentID, input.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	comment.Body = normalized
+	comment.UpdatedAt = s.now().UTC()
+	mentions, err := s.newMentions(ctx, comment, input.Audience)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateComment(ctx, comment, mentions); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Delete removes the author's own comment from thread.
+func (s *Service) Delete(ctx context.Context, thread Thread, commentID, authorID string) error {
+	ctx, span := tracing.Start(ctx, "comments.Delete")
+	defer span.End()
+
+	if _, err := s.authored(ctx, thread, commentID, authorID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteComment(ctx, thread.FamilyID, commentID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// Counts returns the size and unread count of each record's thread for
+// userID, keyed by record ID. Records without comments are left out.
+func (s *Service) Counts(ctx context.Context, familyID string, entityType EntityType, entityIDs []string, userID string) (map[string]Counts, error) {
+	ctx, span := tracing.Start(ctx, "comments.Counts")
+	defer span.End()
+
+	if len(entityIDs) == 0 {
+		return map[string]Counts{}, nil
+	}
+	return s.repo.CountComments(ctx, familyID, entityType, entityIDs, userID)
+}
+
+// Clear removes the thread of a deleted record.
+func (s *Service) Clear(ctx context.Context, entityType EntityType, entityID string) error {
+	ctx, span := tracing.Start(ctx, "comments.Clear")
+	defer span.End()
+
+	return s.repo.DeleteByEntity(ctx, entityType, entityID)
+}
+
+// authored loads a comment of thread, checking that authorID wrote it.
+func (s *Service) authored(ctx context.Context, thread Thread, commentID, authorID string) (*Comment, error) {
+	comment, err := s.repo.GetComment(ctx, thread.FamilyID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.EntityType != thread.EntityType || comment.EntityID != thread.EntityID {
+		return nil, ErrCommentNotFound
+	}
+	if comment.AuthorID != authorID {
+		return nil, ErrNotAuthor
+	}
+	return comment, nil
+}
+
+func normalizeBody(value string) (string, error) {
+	body := strings.TrimSpace(value)
+	if body == "" || utf8.RuneCountInString(body) > MaxBodyLength {
+		return "", ErrInvalidBody
+	}
+	return body, nil
+}
+
+func validEntityType(entityType EntityType) bool {
+	return entityType == EntityExpense || entityType == EntityTodoItem
+}
@@ -0,0 +1,188 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeCommentsRepo struct {
+	Repository
+	comments map[string]*Comment
+	reads    []Read
+	deleted  []string
+	members  []Member
+	mentions []Mention
+}
+
+func (r *fakeCommentsRepo) CreateComment(_ context.Context, comment *Comment, mentions []Mention) error {
+	if r.comments == nil {
+		r.comments = make(map[string]*Comment)
+	}
+	stored := *comment
+	r.comments[comment.ID] = &stored
+	r.mentions = append(r.mentions, mentions...)
+	return nil
+}
+
+func (r *fakeCommentsRepo) UpdateComment(_ context.Context, comment *Comment, mentions []Mention) error {
+	stored := *comment
+	r.comments[comment.ID] = &stored
+	r.mentions = append(r.mentions, mentions...)
+	return nil
+}
+
+func (r *fakeCommentsRepo) ListMembers(_ context.Context, _ string) ([]Member, error) {
+	return r.members, nil
+}
+
+func (r *fakeCommentsRepo) GetComment(_ context.Context, familyID, commentID string) (*Comment, error) {
+	comment, ok := r.comments[commentID]
+	if !ok || comment.FamilyID != familyID {
+		return nil, ErrCommentNotFound
+	}
+	copied := *comment
+	return &copied, nil
+}
+
+func (r *fakeCommentsRepo) DeleteComment(_ context.Context, _ string, commentID string) (bool, error) {
+	if _, ok := r.comments[commentID]; !ok {
+		return false, nil
+	}
+	delete(r.comments, commentID)
+	r.deleted = append(r.deleted, commentID)
+	return true, nil
+}
+
+func (r *fakeCommentsRepo) ListComments(_ context.Context, _ string, _ EntityType, _ string, _ ListFilter) ([]Comment, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeCommentsRepo) MarkRead(_ context.Context, read *Read) error {
+	r.reads = append(r.reads, *read)
+	return nil
+}
+
+func seedComment(t *testing.T, service *Service) *Comment {
+	t.Helper()
+	comment, err := service.Create(context.Background(), CreateInput{
+		FamilyID:   "family-1",
+		EntityType: EntityExpense,
+		EntityID:   "expense-1",
+		Author:     Author{ID: "user-1", Name: "Alice"},
+		Body:       "  who paid for this?  ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return comment
+}
+
+func TestCreateTrimsBodyAndRejectsInvalid(t *testing.T) {
+	service := NewService(&fakeCommentsRepo{})
+
+	comment := seedComment(t, service)
+	if comment.Body != "who paid for this?" || comment.AuthorName != "Alice" {
+		t.Fatalf("comment = %+v", comment)
+	}
+
+	for _, body := range []string{"   ", strings.Repeat("a", MaxBodyLength+1)} {
+		_, err := service.Create(context.Background(), CreateInput{
+			FamilyID:   "family-1",
+			EntityType: EntityExpense,
+			EntityID:   "expense-1",
+			Author:     Author{ID: "user-1"},
+			Body:       body,
+		})
+		if !errors.Is(err, ErrInvalidBody) {
+			t.Fatalf("Create(%d chars) error = %v, want ErrInvalidBody", len(body), err)
+		}
+	}
+}
+
+func TestOnlyAuthorChangesComment(t *testing.T) {
+	repo := &fakeCommentsRepo{}
+	service := NewService(repo)
+	comment := seedComment(t, service)
+	thread := Thread{FamilyID: "family-1", EntityType: EntityExpense, EntityID: "expense-1"}
+
+	_, err := service.Update(context.Background(), UpdateInput{Thread: thread, CommentID: comment.ID, AuthorID: "user-2", Body: "mine now"})
+	if !errors.Is(err, ErrNotAuthor) {
+		t.Fatalf("Update by another member error = %v, want ErrNotAuthor", err)
+	}
+	if err := service.Delete(context.Background(), thread, comment.ID, "user-2"); !errors.Is(err, ErrNotAuthor) {
+		t.Fatalf("Delete by another member error = %v, want ErrNotAuthor", err)
+	}
+
+	other := Thread{FamilyID: "family-1", EntityType: EntityExpense, EntityID: "expense-2"}
+	if err := service.Delete(context.Background(), other, comment.ID, "user-1"); !errors.Is(err, ErrCommentNotFound) {
+		t.Fatalf("Delete through another thread error = %v, want ErrCommentNotFound", err)
+	}
+	if err := service.Delete(context.Background(), thread, comment.ID, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.deleted) != 1 {
+		t.Fatalf("deleted = %v, want the comment", repo.deleted)
+	}
+}
+
+func TestListMarksThreadRead(t *testing.T) {
+	repo := &fakeCommentsRepo{}
+	service := NewService(repo)
+	thread := Thread{FamilyID: "family-1", EntityType: EntityExpense, EntityID: "expense-1"}
+
+	if _, _, err := service.List(context.Background(), thread, "user-2", ListFilter{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.reads) != 1 || repo.reads[0].UserID != "user-2" || repo.reads[0].EntityID != "expense-1" {
+		t.Fatalf("reads = %+v", repo.reads)
+	}
+}
+
+func TestParseHandlesSkipsEmailAddresses(t *testing.T) {
+	handles := parseHandles("@Bob can you check? cc @carol@example.com, mail dave@example.com. Thanks @bob.")
+	want := []string{"bob", "carol@example.com"}
+	if !reflect.DeepEqual(handles, want) {
+		t.Fatalf("handles = %v, want %v", handles, want)
+	}
+}
+
+func TestResolveHandlesSkipsAmbiguousLocalParts(t *testing.T) {
+	members := []Member{
+		{UserID: "user-2", Email: "bob@example.com"},
+		{UserID: "user-3", Email: "sam@example.com"},
+		{UserID: "user-4", Email: "sam@example.org"},
+	}
+	userIDs := resolveHandles([]string{"bob", "sam", "sam@example.org", "nobody"}, members)
+	want := []string{"user-2", "user-4"}
+	if !reflect.DeepEqual(userIDs, want) {
+		t.Fatalf("userIDs = %v, want %v", userIDs, want)
+	}
+}
+
+func TestCreateMentionsVisibleMembersOnly(t *testing.T) {
+	repo := &fakeCommentsRepo{members: []Member{
+		{UserID: "user-1", Email: "alice@example.com"},
+		{UserID: "user-2", Email: "bob@example.com"},
+		{UserID: "user-3", Email: "kid@example.com", Child: true},
+		{UserID: "user-4", Email: "dana@example.com"},
+	}}
+	service := NewService(repo)
+
+	_, err := service.Create(context.Background(), CreateInput{
+		FamilyID:   "family-1",
+		EntityType: EntityTodoItem,
+		EntityID:   "item-1",
+		Author:     Author{ID: "user-1"},
+		Body:       "@alice @bob @kid @dana who buys milk?",
+		Audience:   []string{"user-1", "user-2", "user-3"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.mentions) != 1 || repo.mentions[0].UserID != "user-2" || repo.mentions[0].EntityID != "item-1" {
+		t.Fatalf("mentions = %+v, want only bob", repo.mentions)
+	}
+}
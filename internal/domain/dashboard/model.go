@@ -0,0 +1,119 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	gymdomain "family-app-go/internal/domain/gym"
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	petsdomain "family-app-go/internal/domain/pets"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+const (
+	// MaxDueTodos and MaxNotifications cap the items listed in a section;
+	// the counts still cover all of them.
+	MaxDueTodos      = 10
+	MaxNotifications = 5
+	MaxMilestones    = 3
+	// EventWindowDays is how far ahead upcoming events are listed.
+	EventWindowDays = 7
+	// DefaultSectionTimeout bounds each section when ServiceOptions leave it
+	// unset.
+	DefaultSectionTimeout = 2 * time.Second
+)
+
+// Section names a part of the dashboard that is loaded, and may fail, on its
+// own.
+type Section string
+
+const (
+	SectionBudget        Section = "budget"
+	SectionDueTodos      Section = "due_todos"
+	SectionEvents        Section = "upcoming_events"
+	SectionStreak        Section = "gym_streak"
+	SectionNotifications Section = "notifications"
+	SectionMilestones    Section = "milestones"
+)
+
+// SectionError records a section left out of the dashboard.
+type SectionError struct {
+	Section Section
+	Err     error
+}
+
+func (e SectionError) Error() string {
+	return string(e.Section) + ": " + e.Err.Error()
+}
+
+func (e SectionError) Unwrap() error { return e.Err }
+
+// Timeout reports whether the section ran out of time rather than failed.
+func (e SectionError) Timeout() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// Query selects whose home screen to build.
+type Query struct {
+	FamilyID     string
+	UserID       string
+	BaseCurrency string
+	// Child leaves out the family's finances and activity, including
+	// milestone savings goals, and limits todo items to those assigned to
+	// the user.
+	Child bool
+}
+
+// Dashboard is the home screen of one user for the current week, which runs
+// from Monday to Sunday (UTC) as gym streaks do. Budget and Notifications
+// are nil for child members. A section that failed is left nil and listed
+// in Errors.
+type Dashboard struct {
+	WeekStart     time.Time
+	WeekEnd       time.Time
+	Budget        *Budget
+	DueTodos      *DueTodos
+	Events        []petsdomain.Reminder
+	Streak        *gymdomain.Streak
+	Notifications *Notifications
+	// Milestones are the next family milestones from today, soonest first.
+	Milestones []milestonesdomain.Milestone
+	Errors     []SectionError
+}
+
+// Failed reports whether section is missing because it failed.
+func (d *Dashboard) Failed(section Section) bool {
+	for _, err := range d.Errors {
+		if err.Section == section {
+			return true
+		}
+	}
+	return false
+}
+
+// Budget compares this week's spending with last week's, in the family
+// currency. The family keeps no budget amount, so spending is the status.
+type Budget struct {
+	BaseCurrency  string
+	SpentThisWeek float64
+	SpentLastWeek float64
+	// Unconverted counts this week's expenses left out of SpentThisWeek.
+	Unconverted int64
+}
+
+// DueTodos lists open items due by WeekEnd, overdue ones
+// included, soonest first.
+type DueTodos struct {
+	Items   []todosdomain.DueTodoItem
+	Total   int
+	Overdue int
+}
+
+// Notifications are the family activity of other members since the week
+// started; there is no read state, so all of it counts as unread.
+type Notifications struct {
+	Unread int
+	Latest []activitydomain.Event
+}
@@ -0,0 +1,275 @@
+package dashboard
+
+import (
+	"context"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	gymdomain "family-app-go/internal/domain/gym"
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	petsdomain "family-app-go/internal/domain/pets"
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/pkg/tracing"
+	"golang.org/x/sync/errgroup"
+)
+
+type ExpenseSummarizer interface {
+	SummarizeExpenses(ctx context.Context, familyID, baseCurrency string, filter expensesdomain.ListFilter) (expensesdomain.ExpenseTotals, error)
+}
+
+type DueTodoLister interface {
+	ListDueTodoItems(ctx context.Context, familyID, viewerID string) ([]todosdomain.DueTodoItem, error)
+}
+
+type ReminderLister interface {
+	ListReminders(ctx context.Context, familyID string, windowDays int) ([]petsdomain.Reminder, error)
+}
+
+type StreakProvider interface {
+	GetStreak(ctx context.Context, userID string) (*gymdomain.Streak, error)
+}
+
+type ActivityLister interface {
+	ListEvents(ctx context.Context, familyID string, filter activitydomain.ListFilter) ([]activitydomain.Event, int64, error)
+}
+
+type MilestoneLister interface {
+	ListUpcoming(ctx context.Context, familyID string, limit int) ([]milestonesdomain.Milestone, error)
+}
+
+// Service assembles the home screen from the domain services, querying them
+// in parallel so the page costs one round trip.
+type Service struct {
+	expenses       ExpenseSummarizer
+	todos          DueTodoLister
+	reminders      ReminderLister
+	streaks        StreakProvider
+	activity       ActivityLister
+	milestones     MilestoneLister
+	sectionTimeout time.Duration
+	now            func() time.Time
+}
+
+type ServiceOptions struct {
+	// SectionTimeout bounds each section; a section still running then is
+	// reported as failed.
+	SectionTimeout time.Duration
+}
+
+func NewService(expenses ExpenseSummarizer, todos DueTodoLister, reminders ReminderLister, streaks StreakProvider, activity ActivityLister, milestones MilestoneLister) *Service {
+	return NewServiceWithOptions(expenses, todos, reminders, streaks, activity, milestones, ServiceOptions{})
+}
+
+func NewServiceWithOptions(expenses ExpenseSummarizer, todos DueTodoLister, reminders ReminderLister, streaks StreakProvider, activity ActivityLister, milestones MilestoneLister, options ServiceOptions) *Service {
+	sectionTimeout := options.SectionTimeout
+	if sectionTimeout <= 0 {
+		sectionTimeout = DefaultSectionTimeout
+	}
+	return &Service{
+		expenses:       expenses,
+		todos:          todos,
+		reminders:      reminders,
+		streaks:        streaks,
+		activity:       activity,
+		milestones:     milestones,
+		sectionTimeout: sectionTimeout,
+		now:            time.Now,
+	}
+}
+
+// section loads one part of the dashboard into its field.
+type section struct {
+	name Section
+	load func(ctx context.Context) error
+}
+
+// Build returns the dashboard with every section that loaded in time. Failed
+// sections are listed in Dashboard.Errors instead of failing the call; only
+// a cancelled ctx does.
+func (s *Service) Build(ctx context.Context, query Query) (*Dashboard, error) {
+	ctx, span := tracing.Start(ctx, "dashboard.Build")
+	defer span.End()
+
+	now := s.now().UTC()
+	weekStart := startOfWeek(now)
+	dashboard := &Dashboard{WeekStart: weekStart, WeekEnd: weekStart.AddDate(0, 0, 6)}
+
+	sections := []section{
+		{SectionDueTodos, func(ctx context.Context) error {
+			due, err := s.dueTodos(ctx, query, now, dashboard.WeekEnd)
+			dashboard.DueTodos = due
+			return err
+		}},
+		{SectionEvents, func(ctx context.Context) error {
+			events, err := s.reminders.ListReminders(ctx, query.FamilyID, EventWindowDays)
+			dashboard.Events = events
+			return err
+		}},
+		{SectionStreak, func(ctx context.Context) error {
+			streak, err := s.streaks.GetStreak(ctx, query.UserID)
+			dashboard.Streak = streak
+			return err
+		}},
+		{SectionMilestones, func(ctx context.Context) error {
+			milestones, err := s.upcomingMilestones(ctx, query)
+			dashboard.Milestones = milestones
+			return err
+		}},
+	}
+	if !query.Child {
+		sections = append(sections,
+			section{SectionBudget, func(ctx context.Context) error {
+				budget, err := s.budget(ctx, query, weekStart)
+				dashboard.Budget = budget
+				return err
+			}},
+			section{SectionNotifications, func(ctx context.Context) error {
+				notifications, err := s.notifications(ctx, query, weekStart)
+				dashboard.Notifications = notifications
+				return err
+			}},
+		)
+	}
+
+	// Sections never return their error to the group, so one failing does
+	// not cancel the others. Each writes its own field and errs slot, so
+	// they need no lock.
+	errs := make([]error, len(sections))
+	var group errgroup.Group
+	for i, section := range sections {
+		group.Go(func() error {
+			sectionCtx, cancel := context.WithTimeout(ctx, s.sectionTimeout)
+			defer cancel()
+			errs[i] = section.load(sectionCtx)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		dashboard.Errors = append(dashboard.Errors, SectionError{Section: sections[i].name, Err: err})
+		dashboard.clear(sections[i].name)
+	}
+	return dashboard, nil
+}
+
+// clear drops what a failed section may have left half-filled.
+func (d *Dashboard) clear(section Section) {
+	switch section {
+	case SectionBudget:
+		d.Budget = nil
+	case SectionDueTodos:
+		d.DueTodos = nil
+	case SectionEvents:
+		d.Events = nil
+	case SectionStreak:
+		d.Streak = nil
+	case SectionNotifications:
+		d.Notifications = nil
+	case SectionMilestones:
+		d.Milestones = nil
+	}
+}
+
+func (s *Service) budget(ctx context.Context, query Query, weekStart time.Time) (*Budget, error) {
+	thisWeek, err := s.spending(ctx, query, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	lastWeek, err := s.spending(ctx, query, weekStart.AddDate(0, 0, -7))
+	if err != nil {
+		return nil, err
+	}
+	return &Budget{
+		BaseCurrency:  thisWeek.BaseCurrency,
+		SpentThisWeek: thisWeek.TotalInBase,
+		SpentLastWeek: lastWeek.TotalInBase,
+		Unconverted:   thisWeek.Unconverted,
+	}, nil
+}
+
+// spending totals the week starting at from, archived expenses included as
+// in analytics.
+func (s *Service) spending(ctx context.Context, query Query, from time.Time) (expensesdomain.ExpenseTotals, error) {
+	to := from.AddDate(0, 0, 6)
+	return s.expenses.SummarizeExpenses(ctx, query.FamilyID, query.BaseCurrency, expensesdomain.ListFilter{
+		From:     &from,
+		To:       &to,
+		Archived: expensesdomain.ArchivedAll,
+	})
+}
+
+func (s *Service) dueTodos(ctx context.Context, query Query, now, weekEnd time.Time) (*DueTodos, error) {
+	items, err := s.todos.ListDueTodoItems(ctx, query.FamilyID, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due := &DueTodos{Items: []todosdomain.DueTodoItem{}}
+	for _, item := range items {
+		if item.Item.DueDate == nil || item.Item.DueDate.After(weekEnd) {
+			continue
+		}
+		if query.Child && (item.Item.AssigneeID == nil || *item.Item.AssigneeID != query.UserID) {
+			continue
+		}
+		due.Total++
+		if item.Item.DueDate.Before(today) {
+			due.Overdue++
+		}
+		if len(due.Items) < MaxDueTodos {
+			due.Items = append(due.Items, item)
+		}
+	}
+	return due, nil
+}
+
+func (s *Service) notifications(ctx context.Context, query Query, weekStart time.Time) (*Notifications, error) {
+	// The feed is newest first, so the week's events are at its head.
+	events, _, err := s.activity.ListEvents(ctx, query.FamilyID, activitydomain.ListFilter{Limit: activitydomain.MaxLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := &Notifications{Latest: []activitydomain.Event{}}
+	for _, event := range events {
+		if event.CreatedAt.Before(weekStart) {
+			break
+		}
+		if event.ActorID == query.UserID {
+			continue
+		}
+		notifications.Unread++
+		if len(notifications.Latest) < MaxNotifications {
+			notifications.Latest = append(notifications.Latest, event)
+		}
+	}
+	return notifications, nil
+}
+
+func (s *Service) upcomingMilestones(ctx context.Context, query Query) ([]milestonesdomain.Milestone, error) {
+	milestones, err := s.milestones.ListUpcoming(ctx, query.FamilyID, MaxMilestones)
+	if err != nil {
+		return nil, err
+	}
+	if query.Child {
+		for i := range milestones {
+			milestones[i].GoalAmount, milestones[i].GoalCurrency, milestones[i].SavedAmount = nil, nil, nil
+		}
+	}
+	return milestones, nil
+}
+
+func startOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
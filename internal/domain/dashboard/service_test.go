@@ -0,0 +1,218 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	gymdomain "family-app-go/internal/domain/gym"
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	petsdomain "family-app-go/internal/domain/pets"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testUserID   = "22222222-2222-2222-2222-222222222222"
+	otherUserID  = "33333333-3333-3333-3333-333333333333"
+)
+
+// testNow is a Wednesday; its week runs from Monday May 11.
+var testNow = time.Date(2026, time.May, 13, 12, 0, 0, 0, time.UTC)
+
+type fakeExpenses struct {
+	totals map[time.Time]float64
+	called bool
+}
+
+func (f *fakeExpenses) SummarizeExpenses(_ context.Context, _, baseCurrency string, filter expensesdomain.ListFilter) (expensesdomain.ExpenseTotals, error) {
+	f.called = true
+	return expensesdomain.ExpenseTotals{BaseCurrency: baseCurrency, TotalInBase: f.totals[*filter.From]}, nil
+}
+
+type fakeTodos struct {
+	items []todosdomain.DueTodoItem
+}
+
+func (f *fakeTodos) ListDueTodoItems(_ context.Context, _, _ string) ([]todosdomain.DueTodoItem, error) {
+	return f.items, nil
+}
+
+type fakeReminders struct {
+	items []petsdomain.Reminder
+	err   error
+}
+
+func (f *fakeReminders) ListReminders(_ context.Context, _ string, _ int) ([]petsdomain.Reminder, error) {
+	return f.items, f.err
+}
+
+// fakeStreaks blocks until ctx is done when slow is set.
+type fakeStreaks struct {
+	slow bool
+}
+
+func (f fakeStreaks) GetStreak(ctx context.Context, _ string) (*gymdomain.Streak, error) {
+	if f.slow {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &gymdomain.Streak{CurrentStreakWeeks: 3}, nil
+}
+
+type fakeActivity struct {
+	events []activitydomain.Event
+	called bool
+}
+
+func (f *fakeActivity) ListEvents(_ context.Context, _ string, _ activitydomain.ListFilter) ([]activitydomain.Event, int64, error) {
+	f.called = true
+	return f.events, int64(len(f.events)), nil
+}
+
+// fakeMilestones returns a vacation with a savings goal.
+type fakeMilestones struct{}
+
+func (fakeMilestones) ListUpcoming(_ context.Context, _ string, _ int) ([]milestonesdomain.Milestone, error) {
+	goal, saved, currency := 2000.0, 500.0, "EUR"
+	return []milestonesdomain.Milestone{{
+		ID:           "vacation",
+		Date:         testNow.AddDate(0, 2, 0),
+		GoalAmount:   &goal,
+		GoalCurrency: &currency,
+		SavedAmount:  &saved,
+	}}, nil
+}
+
+func newTestService(expenses *fakeExpenses, todos *fakeTodos, reminders *fakeReminders, activity *fakeActivity) *Service {
+	return newTestServiceWithStreaks(expenses, todos, reminders, fakeStreaks{}, activity)
+}
+
+func newTestServiceWithStreaks(expenses *fakeExpenses, todos *fakeTodos, reminders *fakeReminders, streaks fakeStreaks, activity *fakeActivity) *Service {
+	service := NewServiceWithOptions(expenses, todos, reminders, streaks, activity, fakeMilestones{}, ServiceOptions{SectionTimeout: 50 * time.Millisecond})
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func dueItem(id string, due time.Time, assigneeID *string) todosdomain.DueTodoItem {
+	return todosdomain.DueTodoItem{Item: todosdomain.TodoItem{ID: id, DueDate: &due, AssigneeID: assigneeID}}
+}
+
+func TestBuildAssemblesTheWeek(t *testing.T) {
+	weekStart := time.Date(2026, time.May, 11, 0, 0, 0, 0, time.UTC)
+	expenses := &fakeExpenses{totals: map[time.Time]float64{weekStart: 42.5, weekStart.AddDate(0, 0, -7): 80}}
+	todos := &fakeTodos{items: []todosdomain.DueTodoItem{
+		dueItem("overdue", testNow.AddDate(0, 0, -3), nil),
+		dueItem("today", weekStart.AddDate(0, 0, 2), nil),
+		dueItem("sunday", weekStart.AddDate(0, 0, 6), nil),
+		dueItem("next-week", weekStart.AddDate(0, 0, 7), nil),
+	}}
+	activity := &fakeActivity{events: []activitydomain.Event{
+		{ID: "a1", ActorID: otherUserID, CreatedAt: testNow.Add(-time.Hour)},
+		{ID: "a2", ActorID: testUserID, CreatedAt: testNow.Add(-2 * time.Hour)},
+		{ID: "a3", ActorID: otherUserID, CreatedAt: weekStart},
+		{ID: "a4", ActorID: otherUserID, CreatedAt: weekStart.Add(-time.Minute)},
+	}}
+	service := newTestService(expenses, todos, &fakeReminders{items: []petsdomain.Reminder{{Title: "Rabies"}}}, activity)
+
+	dashboard, err := service.Build(context.Background(), Query{FamilyID: testFamilyID, UserID: testUserID, BaseCurrency: "EUR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !dashboard.WeekStart.Equal(weekStart) || !dashboard.WeekEnd.Equal(weekStart.AddDate(0, 0, 6)) {
+		t.Fatalf("week = %v..%v", dashboard.WeekStart, dashboard.WeekEnd)
+	}
+	if dashboard.Budget == nil || dashboard.Budget.SpentThisWeek != 42.5 || dashboard.Budget.SpentLastWeek != 80 || dashboard.Budget.BaseCurrency != "EUR" {
+		t.Fatalf("budget = %+v", dashboard.Budget)
+	}
+	if len(dashboard.Errors) != 0 {
+		t.Fatalf("unexpected section errors: %v", dashboard.Errors)
+	}
+	if dashboard.DueTodos == nil || dashboard.DueTodos.Total != 3 || dashboard.DueTodos.Overdue != 1 || len(dashboard.DueTodos.Items) != 3 {
+		t.Fatalf("due todos = %+v", dashboard.DueTodos)
+	}
+	if len(dashboard.Events) != 1 || dashboard.Streak == nil || dashboard.Streak.CurrentStreakWeeks != 3 {
+		t.Fatalf("events = %+v, streak = %+v", dashboard.Events, dashboard.Streak)
+	}
+	if dashboard.Notifications == nil || dashboard.Notifications.Unread != 2 || dashboard.Notifications.Latest[0].ID != "a1" {
+		t.Fatalf("notifications = %+v", dashboard.Notifications)
+	}
+	if len(dashboard.Milestones) != 1 || !dashboard.Milestones[0].HasGoal() {
+		t.Fatalf("milestones = %+v", dashboard.Milestones)
+	}
+}
+
+func TestBuildForChildSkipsFamilySections(t *testing.T) {
+	childID := testUserID
+	otherID := otherUserID
+	expenses := &fakeExpenses{}
+	activity := &fakeActivity{}
+	todos := &fakeTodos{items: []todosdomain.DueTodoItem{
+		dueItem("mine", testNow, &childID),
+		dueItem("theirs", testNow, &otherID),
+		dueItem("unassigned", testNow, nil),
+	}}
+	service := newTestService(expenses, todos, &fakeReminders{}, activity)
+
+	dashboard, err := service.Build(context.Background(), Query{FamilyID: testFamilyID, UserID: testUserID, BaseCurrency: "EUR", Child: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expenses.called || activity.called || dashboard.Budget != nil || dashboard.Notifications != nil {
+		t.Fatalf("child dashboard reached family sections: %+v", dashboard)
+	}
+	if dashboard.DueTodos == nil || dashboard.DueTodos.Total != 1 || dashboard.DueTodos.Items[0].Item.ID != "mine" {
+		t.Fatalf("due todos = %+v", dashboard.DueTodos)
+	}
+	if len(dashboard.Milestones) != 1 || dashboard.Milestones[0].HasGoal() {
+		t.Fatalf("child milestones kept savings: %+v", dashboard.Milestones)
+	}
+}
+
+func TestBuildKeepsOtherSectionsWhenOneFails(t *testing.T) {
+	failure := errors.New("pets down")
+	expenses := &fakeExpenses{}
+	service := newTestServiceWithStreaks(expenses, &fakeTodos{}, &fakeReminders{err: failure}, fakeStreaks{slow: true}, &fakeActivity{})
+
+	dashboard, err := service.Build(context.Background(), Query{FamilyID: testFamilyID, UserID: testUserID, BaseCurrency: "EUR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dashboard.Errors) != 2 {
+		t.Fatalf("section errors = %v, want events and streak", dashboard.Errors)
+	}
+	for _, sectionErr := range dashboard.Errors {
+		switch sectionErr.Section {
+		case SectionEvents:
+			if !errors.Is(sectionErr, failure) || sectionErr.Timeout() {
+				t.Fatalf("events error = %v", sectionErr)
+			}
+		case SectionStreak:
+			if !sectionErr.Timeout() {
+				t.Fatalf("streak error = %v, want a timeout", sectionErr)
+			}
+		default:
+			t.Fatalf("unexpected failed section %s", sectionErr.Section)
+		}
+	}
+	if dashboard.Events != nil || dashboard.Streak != nil || !dashboard.Failed(SectionStreak) {
+		t.Fatalf("failed sections kept data: %+v", dashboard)
+	}
+	if dashboard.Budget == nil || dashboard.DueTodos == nil || dashboard.Notifications == nil {
+		t.Fatalf("healthy sections missing: %+v", dashboard)
+	}
+}
+
+func TestBuildFailsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service := newTestService(&fakeExpenses{}, &fakeTodos{}, &fakeReminders{}, &fakeActivity{})
+
+	if _, err := service.Build(ctx, Query{FamilyID: testFamilyID, UserID: testUserID}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+package erasure
+
+import "errors"
+
+var (
+	ErrDeletionNotFound         = errors.New("deletion request not found")
+	ErrDeletionAlreadyScheduled = errors.New("deletion already scheduled")
+)
@@ -0,0 +1,43 @@
+package erasure
+
+import "time"
+
+const (
+	SubjectUser   = "user"
+	SubjectFamily = "family"
+
+	StatusScheduled = "scheduled"
+	StatusCancelled = "cancelled"
+	StatusCompleted = "completed"
+)
+
+// AnonymizedName replaces the name of an erased user in snapshots kept by the
+// rest of the family, such as completed todo items and activity entries.
+const AnonymizedName = "Deleted user"
+
+// DeletionRequest schedules the erasure of a user account or a family. The
+// subject is hard-deleted by the purge job once ScheduledFor has passed;
+// until then the request can be cancelled. Completed requests are kept as a
+// record of the erasure and hold no personal data besides the IDs.
+type DeletionRequest struct {
+	ID           string `gorm:"type:uuid;primaryKey"`
+	Subject      string
+	SubjectID    string `gorm:"type:uuid"`
+	RequestedBy  string `gorm:"type:uuid"`
+	Status       string
+	ScheduledFor time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	CancelledAt  *time.Time
+	CompletedAt  *time.Time
+}
+
+func (DeletionRequest) TableName() string {
+	return "deletion_requests"
+}
+
+type PurgeResult struct {
+	RequestID string
+	Subject   string
+	SubjectID string
+}
@@ -0,0 +1,17 @@
+package erasure
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateRequest(ctx context.Context, request *DeletionRequest) error
+	UpdateRequest(ctx context.Context, request *DeletionRequest) error
+	GetScheduledRequest(ctx context.Context, subject, subjectID string) (*DeletionRequest, error)
+	ListDueRequests(ctx context.Context, before time.Time, limit int) ([]DeletionRequest, error)
+	// EraseUser deletes the personal data of a user that is not tied to a
+	// family and anonymizes the snapshots other members still see. The
+	// family membership must already be gone.
+	EraseUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,286 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	defaultGracePeriod = 30 * 24 * time.Hour
+	defaultBatchSize   = 50
+)
+
+type FamilyProvider interface {
+	GetFamilyByUser(ctx context.Context, userID string) (*familydomain.Family, error)
+	ListMembers(ctx context.Context, userID string) ([]familydomain.FamilyMember, error)
+	LeaveFamily(ctx context.Context, userID string) (*familydomain.LeaveResult, error)
+	DeleteFamily(ctx context.Context, familyID string) error
+}
+
+// AvatarRemover deletes uploaded avatar files, which live outside the
+// database.
+type AvatarRemover interface {
+	DeleteUploadedAvatar(ctx context.Context, userID string) error
+}
+
+// ArchiveRemover deletes stored family export archives, which live outside
+// the database.
+type ArchiveRemover interface {
+	DeleteFamilyArchives(ctx context.Context, familyID string) error
+}
+
+type Service struct {
+	repo        Repository
+	families    FamilyProvider
+	avatars     AvatarRemover
+	archives    ArchiveRemover
+	gracePeriod time.Duration
+	batchSize   int
+	now         func() time.Time
+}
+
+// ServiceOptions sets the grace period between a deletion request and the
+// hard deletion done by PurgeDue, which is driven by the background job
+// runner.
+type ServiceOptions struct {
+	GracePeriod time.Duration
+	Avatars     AvatarRemover
+	Archives    ArchiveRemover
+	BatchSize   int
+}
+
+func NewService(repo Repository, families FamilyProvider) *Service {
+	return NewServiceWithOptions(repo, families, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, families FamilyProvider, options ServiceOptions) *Service {
+	gracePeriod := options.GracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = defaultGracePeriod
+	}
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Service{
+		repo:        repo,
+		families:    families,
+		avatars:     options.Avatars,
+		archives:    options.Archives,
+		gracePeriod: gracePeriod,
+		batchSize:   batchSize,
+		now:         time.Now,
+	}
+}
+
+// ScheduleAccountDeletion schedules the caller's account for erasure after
+// the grace period.
+func (s *Service) ScheduleAccountDeletion(ctx context.Context, userID string) (*DeletionRequest, error) {
+	ctx, span := tracing.Start(ctx, "erasure.ScheduleAccountDeletion")
+	defer span.End()
+
+	return s.schedule(ctx, SubjectUser, userID, userID)
+}
+
+func (s *Service) GetAccountDeletion(ctx context.Context, userID string) (*DeletionRequest, error) {
+	ctx, span := tracing.Start(ctx, "erasure.GetAccountDeletion")
+	defer span.End()
+
+	return s.repo.GetScheduledRequest(ctx, SubjectUser, userID)
+}
+
+func (s *Service) CancelAccountDeletion(ctx context.Context, userID string) (*DeletionRequest, error) {
+	ctx, span := tracing.Start(ctx, "erasure.CancelAccountDeletion")
+	defer span.End()
+
+	return s.cancel(ctx, SubjectUser, userID)
+}
+
+// ScheduleFamilyDeletion schedules the caller's family, with all of its
+// data, for erasure after the grace period. Owner only.
+func (s *Service) ScheduleFamilyDeletion(ctx context.Context, userID string) (*DeletionRequest, error) {
+	ctx, span := tracing.Start(ctx, "erasure.ScheduleFamilyDeletion")
+	defer span.End()
+
+	family, err := s.ownedFamily(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.schedule(ctx, SubjectFamily, family.ID, userID)
+}
+
+func (s *Service) GetFamilyDeletion(ctx context.Context, userID string) (*DeletionRequest, error) {
+	ctx, span := tracing.Start(ctx, "erasure.GetFamilyDeletion")
+	defer span.End()
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetScheduledRequest(ctx, SubjectFamily, family.ID)
+}
+
+func (s *Service) CancelFamilyDeletion(ctx context.Context, userID string) (*DeletionRequest, error) {
+	ctx, span := tracing.Start(ctx, "erasure.CancelFamilyDeletion")
+	defer span.End()
+
+	family, err := s.ownedFamily(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.cancel(ctx, SubjectFamily, family.ID)
+}
+
+// PurgeDue hard-deletes the subjects of requests whose grace period has
+// ended. A failing request does not stop the rest of the batch; it stays
+// scheduled and is retried on the next run.
+func (s *Service) PurgeDue(ctx context.Context) ([]PurgeResult, error) {
+	ctx, span := tracing.Start(ctx, "erasure.PurgeDue")
+	defer span.End()
+
+	requests, err := s.repo.ListDueRequests(ctx, s.now().UTC(), s.batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]PurgeResult, 0, len(requests))
+	var errs []error
+	for i := range requests {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		request := requests[i]
+
+		var purgeErr error
+		switch request.Subject {
+		case SubjectUser:
+			purgeErr = s.purgeUser(ctx, request.SubjectID)
+		case SubjectFamily:
+			purgeErr = s.purgeFamily(ctx, request.SubjectID)
+		default:
+			purgeErr = fmt.Errorf("unknown deletion subject %q", request.Subject)
+		}
+		if purgeErr != nil {
+			errs = append(errs, fmt.Errorf("deletion request %s: %w", request.ID, purgeErr))
+			continue
+		}
+
+		now := s.now().UTC()
+		request.Status = StatusCompleted
+		request.CompletedAt = &now
+		request.UpdatedAt = now
+		if err := s.repo.UpdateRequest(ctx, &request); err != nil {
+			errs = append(errs, fmt.Errorf("deletion request %s: %w", request.ID, err))
+			continue
+		}
+		results = append(results, PurgeResult{
+			RequestID: request.ID,
+			Subject:   request.Subject,
+			SubjectID: request.SubjectID,
+		})
+	}
+	return results, errors.Join(errs...)
+}
+
+// purgeUser leaves the family first, so ownership is handed over as on a
+// regular leave. A user who is the last member takes the family with them.
+func (s *Service) purgeUser(ctx context.Context, userID string) error {
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	switch {
+	case errors.Is(err, familydomain.ErrFamilyNotFound):
+	case err != nil:
+		return err
+	default:
+		members, err := s.families.ListMembers(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(members) <= 1 {
+			if err := s.purgeFamily(ctx, family.ID); err != nil {
+				return err
+			}
+		} else if _, err := s.families.LeaveFamily(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	if s.avatars != nil {
+		if err := s.avatars.DeleteUploadedAvatar(ctx, userID); err != nil {
+			return err
+		}
+	}
+	return s.repo.EraseUser(ctx, userID)
+}
+
+func (s *Service) purgeFamily(ctx context.Context, familyID string) error {
+	if s.archives != nil {
+		if err := s.archives.DeleteFamilyArchives(ctx, familyID); err != nil {
+			return err
+		}
+	}
+	return s.families.DeleteFamily(ctx, familyID)
+}
+
+func (s *Service) schedule(ctx context.Context, subject, subjectID, requestedBy string) (*DeletionRequest, error) {
+	existing, err := s.repo.GetScheduledRequest(ctx, subject, subjectID)
+	if err != nil && !errors.Is(err, ErrDeletionNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrDeletionAlreadyScheduled
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	request := &DeletionRequest{
+		ID:           newID,
+		Subject:      subject,
+		SubjectID:    subjectID,
+		RequestedBy:  requestedBy,
+		Status:       StatusScheduled,
+		ScheduledFor: now.Add(s.gracePeriod),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.CreateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (s *Service) cancel(ctx context.Context, subject, subjectID string) (*DeletionRequest, error) {
+	request, err := s.repo.GetScheduledRequest(ctx, subject, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	request.Status = StatusCancelled
+	request.CancelledAt = &now
+	request.UpdatedAt = now
+	if err := s.repo.UpdateRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (s *Service) ownedFamily(ctx context.Context, userID string) (*familydomain.Family, error) {
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if family.OwnerID != userID {
+		return nil, familydomain.ErrNotOwner
+	}
+	return family, nil
+}
@@ -0,0 +1,335 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testOwnerID  = "22222222-2222-2222-2222-222222222222"
+	testMemberID = "33333333-3333-3333-3333-333333333333"
+)
+
+type fakeFamilyProvider struct {
+	members  map[string][]string
+	owner    map[string]string
+	left     []string
+	deleted  []string
+	familyOf map[string]string
+}
+
+func newFakeFamilyProvider() *fakeFamilyProvider {
+	return &fakeFamilyProvider{
+		members:  map[string][]string{testFamilyID: {testOwnerID, testMemberID}},
+		owner:    map[string]string{testFamilyID: testOwnerID},
+		familyOf: map[string]string{testOwnerID: testFamilyID, testMemberID: testFamilyID},
+	}
+}
+
+func (p *fakeFamilyProvider) GetFamilyByUser(_ context.Context, userID string) (*familydomain.Family, error) {
+	familyID, ok := p.familyOf[userID]
+	if !ok {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	return &familydomain.Family{ID: familyID, OwnerID: p.owner[familyID]}, nil
+}
+
+func (p *fakeFamilyProvider) ListMembers(_ context.Context, userID string) ([]familydomain.FamilyMember, error) {
+	familyID, ok := p.familyOf[userID]
+	if !ok {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	members := make([]familydomain.FamilyMember, 0, len(p.members[familyID]))
+	for _, memberID := range p.members[familyID] {
+		members = append(members, familydomain.FamilyMember{FamilyID: familyID, UserID: memberID})
+	}
+	return members, nil
+}
+
+func (p *fakeFamilyProvider) LeaveFamily(_ context.Context, userID string) (*familydomain.LeaveResult, error) {
+	familyID := p.familyOf[userID]
+	p.left = append(p.left, userID)
+	delete(p.familyOf, userID)
+	remaining := p.members[familyID][:0]
+	for _, memberID := range p.members[familyID] {
+		if memberID != userID {
+			remaining = append(remaining, memberID)
+		}
+	}
+	p.members[familyID] = remaining
+	return &familydomain.LeaveResult{FamilyID: familyID}, nil
+}
+
+func (p *fakeFamilyProvider) DeleteFamily(_ context.Context, familyID string) error {
+	p.deleted = append(p.deleted, familyID)
+	for _, memberID := range p.members[familyID] {
+		delete(p.familyOf, memberID)
+	}
+	delete(p.members, familyID)
+	return nil
+}
+
+type fakeErasureRepo struct {
+	requests map[string]*DeletionRequest
+	erased   []string
+	eraseErr map[string]error
+}
+
+func newFakeErasureRepo() *fakeErasureRepo {
+	return &fakeErasureRepo{
+		requests: make(map[string]*DeletionRequest),
+		eraseErr: make(map[string]error),
+	}
+}
+
+func (r *fakeErasureRepo) CreateRequest(_ context.Context, request *DeletionRequest) error {
+	cloned := *request
+	r.requests[request.ID] = &cloned
+	return nil
+}
+
+func (r *fakeErasureRepo) UpdateRequest(_ context.Context, request *DeletionRequest) error {
+	cloned := *request
+	r.requests[request.ID] = &cloned
+	return nil
+}
+
+func (r *fakeErasureRepo) GetScheduledRequest(_ context.Context, subject, subjectID string) (*DeletionRequest, error) {
+	for _, request := range r.requests {
+		if request.Subject == subject && request.SubjectID == subjectID && request.Status == StatusScheduled {
+			cloned := *request
+			return &cloned, nil
+		}
+	}
+	return nil, ErrDeletionNotFound
+}
+
+func (r *fakeErasureRepo) ListDueRequests(_ context.Context, before time.Time, _ int) ([]DeletionRequest, error) {
+	var due []DeletionRequest
+	for _, request := range r.requests {
+		if request.Status == StatusScheduled && !request.ScheduledFor.After(before) {
+			due = append(due, *request)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeErasureRepo) EraseUser(_ context.Context, userID string) error {
+	if err := r.eraseErr[userID]; err != nil {
+		return err
+	}
+	r.erased = append(r.erased, userID)
+	return nil
+}
+
+type fakeAvatarRemover struct {
+	removed []string
+}
+
+func (r *fakeAvatarRemover) DeleteUploadedAvatar(_ context.Context, userID string) error {
+	r.removed = append(r.removed, userID)
+	return nil
+}
+
+type fakeArchiveRemover struct {
+	removed []string
+}
+
+func (r *fakeArchiveRemover) DeleteFamilyArchives(_ context.Context, familyID string) error {
+	r.removed = append(r.removed, familyID)
+	return nil
+}
+
+type testEnv struct {
+	service  *Service
+	repo     *fakeErasureRepo
+	families *fakeFamilyProvider
+	avatars  *fakeAvatarRemover
+	archives *fakeArchiveRemover
+	now      time.Time
+}
+
+func newTestEnv() *testEnv {
+	env := &testEnv{
+		repo:     newFakeErasureRepo(),
+		families: newFakeFamilyProvider(),
+		avatars:  &fakeAvatarRemover{},
+		archives: &fakeArchiveRemover{},
+		now:      time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	env.service = NewServiceWithOptions(env.repo, env.families, ServiceOptions{
+		GracePeriod: 48 * time.Hour,
+		Avatars:     env.avatars,
+		Archives:    env.archives,
+	})
+	env.service.now = func() time.Time { return env.now }
+	return env
+}
+
+func TestScheduleAccountDeletion(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+
+	request, err := env.service.ScheduleAccountDeletion(ctx, testMemberID)
+	if err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	if request.Subject != SubjectUser || request.SubjectID != testMemberID || request.Status != StatusScheduled {
+		t.Fatalf("unexpected request: %+v", request)
+	}
+	if !request.ScheduledFor.Equal(env.now.Add(48 * time.Hour)) {
+		t.Fatalf("unexpected scheduled_for %v", request.ScheduledFor)
+	}
+
+	if _, err := env.service.ScheduleAccountDeletion(ctx, testMemberID); !errors.Is(err, ErrDeletionAlreadyScheduled) {
+		t.Fatalf("expected ErrDeletionAlreadyScheduled, got %v", err)
+	}
+}
+
+func TestCancelAccountDeletion(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+
+	if _, err := env.service.CancelAccountDeletion(ctx, testMemberID); !errors.Is(err, ErrDeletionNotFound) {
+		t.Fatalf("expected ErrDeletionNotFound, got %v", err)
+	}
+	if _, err := env.service.ScheduleAccountDeletion(ctx, testMemberID); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	cancelled, err := env.service.CancelAccountDeletion(ctx, testMemberID)
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if cancelled.Status != StatusCancelled || cancelled.CancelledAt == nil {
+		t.Fatalf("unexpected cancelled request: %+v", cancelled)
+	}
+
+	env.now = env.now.Add(72 * time.Hour)
+	results, err := env.service.PurgeDue(ctx)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if len(results) != 0 || len(env.repo.erased) != 0 {
+		t.Fatalf("cancelled deletion must not purge, got %+v", results)
+	}
+}
+
+func TestScheduleFamilyDeletionRequiresOwner(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+
+	if _, err := env.service.ScheduleFamilyDeletion(ctx, testMemberID); !errors.Is(err, familydomain.ErrNotOwner) {
+		t.Fatalf("expected ErrNotOwner, got %v", err)
+	}
+	if _, err := env.service.ScheduleFamilyDeletion(ctx, testOwnerID); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	request, err := env.service.GetFamilyDeletion(ctx, testMemberID)
+	if err != nil {
+		t.Fatalf("get family deletion: %v", err)
+	}
+	if request.Subject != SubjectFamily || request.SubjectID != testFamilyID {
+		t.Fatalf("unexpected request: %+v", request)
+	}
+	if _, err := env.service.CancelFamilyDeletion(ctx, testMemberID); !errors.Is(err, familydomain.ErrNotOwner) {
+		t.Fatalf("expected ErrNotOwner on cancel, got %v", err)
+	}
+}
+
+func TestPurgeDueWaitsForGracePeriod(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+
+	if _, err := env.service.ScheduleAccountDeletion(ctx, testMemberID); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+
+	env.now = env.now.Add(47 * time.Hour)
+	results, err := env.service.PurgeDue(ctx)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected nothing purged before the grace period ends, got %+v", results)
+	}
+
+	env.now = env.now.Add(time.Hour)
+	results, err = env.service.PurgeDue(ctx)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if len(results) != 1 || results[0].SubjectID != testMemberID {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if len(env.families.left) != 1 || env.families.left[0] != testMemberID {
+		t.Fatalf("expected member to leave the family, got %v", env.families.left)
+	}
+	if len(env.families.deleted) != 0 {
+		t.Fatalf("family with other members must stay, deleted %v", env.families.deleted)
+	}
+	if len(env.repo.erased) != 1 || len(env.avatars.removed) != 1 {
+		t.Fatalf("expected user data and avatar erased, got erased=%v avatars=%v", env.repo.erased, env.avatars.removed)
+	}
+	if request := env.repo.requests[results[0].RequestID]; request.Status != StatusCompleted || request.CompletedAt == nil {
+		t.Fatalf("expected completed request, got %+v", request)
+	}
+}
+
+func TestPurgeLastMemberDeletesFamily(t *testing.T) {
+	env := newTestEnv()
+	env.families.members[testFamilyID] = []string{testOwnerID}
+	delete(env.families.familyOf, testMemberID)
+	ctx := context.Background()
+
+	if _, err := env.service.ScheduleAccountDeletion(ctx, testOwnerID); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	env.now = env.now.Add(48 * time.Hour)
+	if _, err := env.service.PurgeDue(ctx); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+
+	if len(env.families.deleted) != 1 || env.families.deleted[0] != testFamilyID {
+		t.Fatalf("expected family deleted, got %v", env.families.deleted)
+	}
+	if len(env.archives.removed) != 1 {
+		t.Fatalf("expected export archives removed, got %v", env.archives.removed)
+	}
+	if len(env.families.left) != 0 {
+		t.Fatalf("last member must not leave, got %v", env.families.left)
+	}
+	if len(env.repo.erased) != 1 || env.repo.erased[0] != testOwnerID {
+		t.Fatalf("expected owner erased, got %v", env.repo.erased)
+	}
+}
+
+func TestPurgeDueKeepsFailedRequestsScheduled(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+
+	failing, err := env.service.ScheduleAccountDeletion(ctx, testMemberID)
+	if err != nil {
+		t.Fatalf("schedule member: %v", err)
+	}
+	if _, err := env.service.ScheduleFamilyDeletion(ctx, testOwnerID); err != nil {
+		t.Fatalf("schedule family: %v", err)
+	}
+	env.repo.eraseErr[testMemberID] = errors.New("boom")
+
+	env.now = env.now.Add(48 * time.Hour)
+	results, err := env.service.PurgeDue(ctx)
+	if err == nil {
+		t.Fatalf("expected purge error")
+	}
+	if len(results) != 1 || results[0].Subject != SubjectFamily {
+		t.Fatalf("expected the family purge to go through, got %+v", results)
+	}
+	if request := env.repo.requests[failing.ID]; request.Status != StatusScheduled {
+		t.Fatalf("failed request must stay scheduled, got %s", request.Status)
+	}
+}
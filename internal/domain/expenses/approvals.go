@@ -0,0 +1,309 @@
+package expenses
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// ApprovalStatus tracks an expense that went over its author's spending limit
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+)
+
+// ExpenseApproval holds an expense above its author's spending limit until
+// the family owner decides on it. The conversion is kept from the request,
+// so an approved expense uses the rate the member saw.
+type ExpenseApproval struct {
+	ID           string     `gorm:"type:uuid;primaryKey"`
+	FamilyID     string     `gorm:"type:uuid;index;not null"`
+	UserID       string     `gorm:"type:uuid;not null"`
+	Date         time.Time  `gorm:"type:date;not null"`
+	Amount       float64    `gorm:"type:numeric(12,2);not null"`
+	Currency     string     `gorm:"size:3;not null"`
+	BaseCurrency *string    `gorm:"size:3"`
+	ExchangeRate *float64   `gorm:"type:numeric(18,8)"`
+	AmountInBase *float64   `gorm:"type:numeric(14,2)"`
+	RateDate     *time.Time `gorm:"type:date"`
+	RateSource   *string    `gorm:"type:text"`
+	Title        string     `gorm:"not null"`
+	CategoryIDs  []byte     `gorm:"type:jsonb;not null"`
+	// SpendingLimit is the author's limit when the expense was submitted.
+	SpendingLimit float64        `gorm:"type:numeric(12,2);not null"`
+	Status        ApprovalStatus `gorm:"type:text;not null"`
+	// ExpenseID is set once the approval created or updated the expense.
+	ExpenseID *string `gorm:"type:uuid"`
+	// UpdatesExpenseID is the stored expense an approved change applies to;
+	// nil means the approval creates a new expense.
+	UpdatesExpenseID *string `gorm:"type:uuid"`
+	DecidedBy        *string `gorm:"type:uuid"`
+	DecidedAt        *time.Time
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+
+	Latitude  *float64 `gorm:"type:double precision"`
+	Longitude *float64 `gorm:"type:double precision"`
+	PlaceName *string  `gorm:"serializer:encrypted"`
+}
+
+func (ExpenseApproval) TableName() string {
+	return "expense_approvals"
+}
+
+// ApprovalFilter narrows ListExpenseApprovals; empty fields match everything.
+type ApprovalFilter struct {
+	Status ApprovalStatus
+	UserID string
+}
+
+// spendingLimit returns userID's spending limit, or nil when their expenses
+// need no approval.
+func (s *Service) spendingLimit(ctx context.Context, userID string) (*float64, error) {
+	if s.spendingLimits == nil || userID == "" {
+		return nil, nil
+	}
+	return s.spendingLimits.GetMemberSpendingLimit(ctx, userID)
+}
+
+// spendingLimitsOf returns the spending limit of every author in expenses.
+func (s *Service) spendingLimitsOf(ctx context.Context, expenses []Expense) (map[string]*float64, error) {
+	limits := make(map[string]*float64)
+	for _, expense := range expenses {
+		if _, ok := limits[expense.UserID]; ok {
+			continue
+		}
+		limit, err := s.spendingLimit(ctx, expense.UserID)
+		if err != nil {
+			return nil, err
+		}
+		limits[expense.UserID] = limit
+	}
+	return limits, nil
+}
+
+// overLimit reports whether expense needs approval under limit. An amount
+// that could not be converted to the base currency is never within it.
+func overLimit(expense Expense, limit *float64) bool {
+	return limit != nil && (expense.AmountInBase == nil || *expense.AmountInBase > *limit)
+}
+
+// queueApproval stores a pending approval holding the prepared expense in
+// place of the expense itself. updatesExpenseID is set when the approval
+// holds a change to that stored expense.
+func queueApproval(ctx context.Context, repo Repository, expense Expense, categoryIDs []string, limit float64, updatesExpenseID *string) (*ExpenseApproval, error) {
+	if len(categoryIDs) > 0 {
+		count, err := repo.CountCategoriesByIDs(ctx, expense.FamilyID, categoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		if count != int64(len(categoryIDs)) {
+			return nil, ErrCategoryNotFound
+		}
+	}
+	if categoryIDs == nil {
+		categoryIDs = []string{}
+	}
+	encodedCategoryIDs, err := json.Marshal(categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	approvalID := expense.ID
+	if updatesExpenseID != nil {
+		if approvalID, err = id.New(); err != nil {
+			return nil, err
+		}
+	}
+	approval := ExpenseApproval{
+		ID:               approvalID,
+		FamilyID:         expense.FamilyID,
+		UserID:           expense.UserID,
+		Date:             expense.Date,
+		Amount:           expense.Amount,
+		Currency:         expense.Currency,
+		BaseCurrency:     expense.BaseCurrency,
+		ExchangeRate:     expense.ExchangeRate,
+		AmountInBase:     expense.AmountInBase,
+		RateDate:         expense.RateDate,
+		RateSource:       expense.RateSource,
+		Title:            expense.Title,
+		CategoryIDs:      encodedCategoryIDs,
+		SpendingLimit:    limit,
+		Status:           ApprovalPending,
+		UpdatesExpenseID: updatesExpenseID,
+		Latitude:         expense.Latitude,
+		Longitude:        expense.Longitude,
+		PlaceName:        expense.PlaceName,
+	}
+	if err := repo.CreateExpenseApproval(ctx, &approval); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (s *Service) ListExpenseApprovals(ctx context.Context, familyID string, filter ApprovalFilter) ([]ExpenseApproval, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ListExpenseApprovals")
+	defer span.End()
+
+	return s.repo.ListExpenseApprovals(ctx, familyID, filter)
+}
+
+// ApproveExpense creates the expense held by a pending approval, or applies
+// the held change to the expense it updates.
+func (s *Service) ApproveExpense(ctx context.Context, familyID, approvalID, deciderID string) (*ExpenseApproval, *ExpenseWithCategories, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ApproveExpense")
+	defer span.End()
+
+	var (
+		approval *ExpenseApproval
+		created  *ExpenseWithCategories
+	)
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		var err error
+		approval, err = lockPendingApproval(ctx, tx, familyID, approvalID)
+		if err != nil {
+			return err
+		}
+
+		var categoryIDs []string
+		if err := json.Unmarshal(approval.CategoryIDs, &categoryIDs); err != nil {
+			return err
+		}
+		if approval.UpdatesExpenseID != nil {
+			created, err = applyApprovedUpdate(ctx, tx, approval, categoryIDs)
+			if err != nil {
+				return err
+			}
+		} else {
+			expenseID, err := id.New()
+			if err != nil {
+				return err
+			}
+			expense := Expense{
+				ID:           expenseID,
+				FamilyID:     approval.FamilyID,
+				UserID:       approval.UserID,
+				Date:         approval.Date,
+				Amount:       approval.Amount,
+				Currency:     approval.Currency,
+				BaseCurrency: approval.BaseCurrency,
+				ExchangeRate: approval.ExchangeRate,
+				AmountInBase: approval.AmountInBase,
+				RateDate:     approval.RateDate,
+				RateSource:   approval.RateSource,
+				Title:        approval.Title,
+				Latitude:     approval.Latitude,
+				Longitude:    approval.Longitude,
+				PlaceName:    approval.PlaceName,
+			}
+			categoryIDs, err = createExpenseInTx(ctx, tx, &expense, categoryIDs)
+			if err != nil {
+				return err
+			}
+			created = &ExpenseWithCategories{Expense: expense, CategoryIDs: categoryIDs}
+		}
+
+		decide(approval, ApprovalApproved, deciderID)
+		approval.ExpenseID = &created.ID
+		return tx.UpdateExpenseApproval(ctx, approval)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return approval, created, nil
+}
+
+// RejectExpense closes a pending approval without creating the expense.
+func (s *Service) RejectExpense(ctx context.Context, familyID, approvalID, deciderID string) (*ExpenseApproval, error) {
+	ctx, span := tracing.Start(ctx, "expenses.RejectExpense")
+	defer span.End()
+
+	var approval *ExpenseApproval
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		var err error
+		approval, err = lockPendingApproval(ctx, tx, familyID, approvalID)
+		if err != nil {
+			return err
+		}
+		decide(approval, ApprovalRejected, deciderID)
+		return tx.UpdateExpenseApproval(ctx, approval)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// applyApprovedUpdate writes an approved change over the expense it updates.
+// The change wins over edits made while it waited, as the owner approved
+// exactly these values.
+func applyApprovedUpdate(ctx context.Context, tx Repository, approval *ExpenseApproval, categoryIDs []string) (*ExpenseWithCategories, error) {
+	expense, err := tx.GetExpenseByID(ctx, approval.FamilyID, *approval.UpdatesExpenseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(categoryIDs) > 0 {
+		count, err := tx.CountCategoriesByIDs(ctx, expense.FamilyID, categoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		if count != int64(len(categoryIDs)) {
+			return nil, ErrCategoryNotFound
+		}
+	}
+	if expense.AutoCategorized {
+		current, err := tx.GetCategoryIDsByExpenseIDs(ctx, []string{expense.ID})
+		if err != nil {
+			return nil, err
+		}
+		expense.AutoCategorized = sameCategoryIDs(current[expense.ID], categoryIDs)
+	}
+
+	expense.Date = approval.Date
+	expense.Amount = approval.Amount
+	expense.Currency = approval.Currency
+	expense.BaseCurrency = approval.BaseCurrency
+	expense.ExchangeRate = approval.ExchangeRate
+	expense.AmountInBase = approval.AmountInBase
+	expense.RateDate = approval.RateDate
+	expense.RateSource = approval.RateSource
+	expense.Title = approval.Title
+	expense.Latitude = approval.Latitude
+	expense.Longitude = approval.Longitude
+	expense.PlaceName = approval.PlaceName
+	expense.UpdatedAt = time.Now().UTC()
+	if err := tx.UpdateExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+	if err := tx.ReplaceExpenseCategories(ctx, expense.ID, categoryIDs); err != nil {
+		return nil, err
+	}
+	return &ExpenseWithCategories{Expense: *expense, CategoryIDs: categoryIDs}, nil
+}
+
+func lockPendingApproval(ctx context.Context, tx Repository, familyID, approvalID string) (*ExpenseApproval, error) {
+	if !id.IsUUID(approvalID) {
+		return nil, ErrApprovalNotFound
+	}
+	approval, err := tx.LockExpenseApproval(ctx, familyID, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Status != ApprovalPending {
+		return nil, ErrApprovalDecided
+	}
+	return approval, nil
+}
+
+func decide(approval *ExpenseApproval, status ApprovalStatus, deciderID string) {
+	now := time.Now().UTC()
+	approval.Status = status
+	approval.DecidedBy = &deciderID
+	approval.DecidedAt = &now
+}
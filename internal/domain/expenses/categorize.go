@@ -0,0 +1,319 @@
+package expenses
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	// categoryHistoryLookback and categoryHistoryLimit bound the past
+	// expenses learned suggestions are drawn from.
+	categoryHistoryLookback = 365 * 24 * time.Hour
+	categoryHistoryLimit    = 2000
+	maxCategoryRulePattern  = 200
+	// A merchant seen before under a different spelling is recognised from
+	// this similarity on.
+	merchantSimilarity = 0.6
+)
+
+// categorizer suggests a category for an expense title: the first of the
+// family's rules that matches, otherwise the category the family used most
+// often for the same merchant.
+type categorizer struct {
+	rules     []CategoryRule
+	merchants map[string]map[string]int
+}
+
+// newCategorizer loads the family's rules and the categorized expenses dated
+// up to a year before from.
+func newCategorizer(ctx context.Context, repo Repository, familyID string, from time.Time) (*categorizer, error) {
+	rules, err := repo.ListCategoryRules(ctx, familyID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := repo.ListCategorizedTitles(ctx, familyID, from.Add(-categoryHistoryLookback), categoryHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &categorizer{rules: rules, merchants: make(map[string]map[string]int)}
+	for _, entry := range history {
+		key := merchantKey(entry.Title)
+		if key == "" {
+			continue
+		}
+		if c.merchants[key] == nil {
+			c.merchants[key] = make(map[string]int)
+		}
+		c.merchants[key][entry.CategoryID]++
+	}
+	return c, nil
+}
+
+func (c *categorizer) suggest(title string) (CategorySuggestion, bool) {
+	normalized := normalizeRuleText(title)
+	for _, rule := range c.rules {
+		if rule.matches(normalized) {
+			return CategorySuggestion{CategoryID: rule.CategoryID, RuleID: rule.ID}, true
+		}
+	}
+
+	key := merchantKey(title)
+	if key == "" {
+		return CategorySuggestion{}, false
+	}
+	counts, ok := c.merchants[key]
+	if !ok {
+		best := 0.0
+		for known, knownCounts := range c.merchants {
+			if similarity := titleSimilarity(key, known); similarity >= merchantSimilarity && similarity > best {
+				best, counts = similarity, knownCounts
+			}
+		}
+	}
+	if categoryID := mostUsedCategory(counts); categoryID != "" {
+		return CategorySuggestion{CategoryID: categoryID}, true
+	}
+	return CategorySuggestion{}, false
+}
+
+func (r CategoryRule) matches(normalizedTitle string) bool {
+	pattern := normalizeRuleText(r.Pattern)
+	switch r.MatchType {
+	case RuleMatchExact:
+		return normalizedTitle == pattern
+	case RuleMatchPrefix:
+		return strings.HasPrefix(normalizedTitle, pattern)
+	default:
+		return strings.Contains(normalizedTitle, pattern)
+	}
+}
+
+func normalizeRuleText(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
+
+func (s *Service) ListCategoryRules(ctx context.Context, familyID string) ([]CategoryRule, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ListCategoryRules")
+	defer span.End()
+
+	return s.repo.ListCategoryRules(ctx, familyID)
+}
+
+func (s *Service) CreateCategoryRule(ctx context.Context, input CreateCategoryRuleInput) (*CategoryRule, error) {
+	ctx, span := tracing.Start(ctx, "expenses.CreateCategoryRule")
+	defer span.End()
+
+	pattern, matchType, err := validateCategoryRule(input.Pattern, input.MatchType)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetCategoryByID(ctx, input.FamilyID, strings.TrimSpace(input.CategoryID)); err != nil {
+		return nil, err
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	rule := CategoryRule{
+		ID:         newID,
+		FamilyID:   input.FamilyID,
+		Pattern:    pattern,
+		MatchType:  matchType,
+		CategoryID: strings.TrimSpace(input.CategoryID),
+		Priority:   input.Priority,
+	}
+	if err := s.repo.CreateCategoryRule(ctx, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *Service) UpdateCategoryRule(ctx context.Context, input UpdateCategoryRuleInput) (*CategoryRule, error) {
+	ctx, span := tracing.Start(ctx, "expenses.UpdateCategoryRule")
+	defer span.End()
+
+	rule, err := s.repo.GetCategoryRuleByID(ctx, input.FamilyID, input.RuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern, matchType := rule.Pattern, rule.MatchType
+	if input.Pattern != nil {
+		pattern = *input.Pattern
+	}
+	if input.MatchType != nil {
+		matchType = *input.MatchType
+	}
+	rule.Pattern, rule.MatchType, err = validateCategoryRule(pattern, matchType)
+	if err != nil {
+		return nil, err
+	}
+	if input.CategoryID != nil {
+		categoryID := strings.TrimSpace(*input.CategoryID)
+		if _, err := s.repo.GetCategoryByID(ctx, input.FamilyID, categoryID); err != nil {
+			return nil, err
+		}
+		rule.CategoryID = categoryID
+	}
+	if input.Priority != nil {
+		rule.Priority = *input.Priority
+	}
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpdateCategoryRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *Service) DeleteCategoryRule(ctx context.Context, familyID, ruleID string) error {
+	ctx, span := tracing.Start(ctx, "expenses.DeleteCategoryRule")
+	defer span.End()
+
+	deleted, err := s.repo.DeleteCategoryRule(ctx, familyID, ruleID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCategoryRuleNotFound
+	}
+	return nil
+}
+
+func validateCategoryRule(pattern string, matchType RuleMatchType) (string, RuleMatchType, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || len([]rune(pattern)) > maxCategoryRulePattern {
+		return "", "", ErrInvalidCategoryRule
+	}
+	switch matchType {
+	case "":
+		matchType = RuleMatchContains
+	case RuleMatchContains, RuleMatchPrefix, RuleMatchExact:
+	default:
+		return "", "", ErrInvalidCategoryRule
+	}
+	return pattern, matchType, nil
+}
+
+func mostUsedCategory(counts map[string]int) string {
+	best, bestCount := "", 0
+	for categoryID, count := range counts {
+		if count > bestCount || (count == bestCount && categoryID < best) {
+			best, bestCount = categoryID, count
+		}
+	}
+	return best
+}
+
+// merchantNoise are words banks wrap merchant names in.
+var merchantNoise = map[string]bool{
+	"card": true, "payment": true, "purchase": true, "pos": true, "debit": true, "credit": true,
+	"visa": true, "mastercard": true, "maestro": true, "contactless": true, "online": true,
+	"transaction": true, "ref": true, "sepa": true, "direct": true, "transfer": true,
+}
+
+// MerchantKey groups expense titles by merchant, the way categorization
+// does.
+func MerchantKey(title string) string {
+	return merchantKey(title)
+}
+
+// merchantKey reduces a statement description or expense title to the
+// words that name the merchant: no digits, punctuation or bank boilerplate.
+func merchantKey(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	kept := make([]string, 0, 2)
+	for _, word := range words {
+		if len([]rune(word)) < 2 || merchantNoise[word] {
+			continue
+		}
+		kept = append(kept, word)
+		if len(kept) == 2 {
+			break
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// titleSimilarity compares two titles from 0 for nothing in common to 1 for
+// the same merchant. It takes the better of the share of the shorter title's
+// words found in the other, which copes with bank boilerplate around a
+// merchant name, and the Sørensen–Dice coefficient of their letter bigrams,
+// which copes with spelling variants.
+func titleSimilarity(a, b string) float64 {
+	return math.Max(sharedWords(a, b), bigramSimilarity(a, b))
+}
+
+func sharedWords(a, b string) float64 {
+	first, second := merchantWords(a), merchantWords(b)
+	if len(first) == 0 || len(second) == 0 {
+		return 0
+	}
+	if len(first) > len(second) {
+		first, second = second, first
+	}
+
+	shared := 0
+	for word := range first {
+		if second[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(first))
+}
+
+func merchantWords(value string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(value), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if len([]rune(word)) >= 3 && !merchantNoise[word] {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+func bigramSimilarity(a, b string) float64 {
+	first, second := letterBigrams(a), letterBigrams(b)
+	if len(first) == 0 || len(second) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, count := range first {
+		total += count
+	}
+	for _, count := range second {
+		total += count
+	}
+
+	shared := 0
+	for bigram, count := range first {
+		if other := second[bigram]; other < count {
+			shared += other
+		} else {
+			shared += count
+		}
+	}
+	return 2 * float64(shared) / float64(total)
+}
+
+func letterBigrams(value string) map[string]int {
+	bigrams := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(value), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		runes := []rune(word)
+		for i := 0; i+1 < len(runes); i++ {
+			bigrams[string(runes[i:i+2])]++
+		}
+	}
+	return bigrams
+}
@@ -1,6 +1,10 @@
 package expenses
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	ErrExpenseNotFound      = errors.New("expense not found")
@@ -10,4 +14,63 @@ var (
 	ErrInvalidCategoryColor = errors.New("invalid category color")
 	ErrInvalidCategoryEmoji = errors.New("invalid category emoji")
 	ErrRateNotAvailable     = errors.New("rate not available")
+	ErrInvalidCurrency      = errors.New("invalid currency")
+	ErrInvalidLocation      = errors.New("invalid location")
+	ErrInvalidBoundingBox   = errors.New("invalid bounding box")
+	ErrVersionConflict      = errors.New("version conflict")
+	ErrCategoryRuleNotFound = errors.New("category rule not found")
+	ErrInvalidCategoryRule  = errors.New("invalid category rule")
+	ErrApprovalNotFound     = errors.New("expense approval not found")
+	ErrApprovalDecided      = errors.New("expense approval already decided")
+	ErrApprovalRequired     = errors.New("expense needs approval")
+
+	ErrInvalidStatementFile       = errors.New("invalid statement file")
+	ErrUnsupportedStatementFormat = errors.New("unsupported statement format")
+	ErrStatementTooLarge          = errors.New("statement has too many rows")
 )
+
+// ExpenseConflictError reports an update based on a stale version. Current is
+// the stored expense the client should reapply its change to.
+type ExpenseConflictError struct {
+	Current ExpenseWithCategories
+}
+
+func (e *ExpenseConflictError) Error() string { return ErrVersionConflict.Error() }
+
+func (e *ExpenseConflictError) Unwrap() error { return ErrVersionConflict }
+
+// ApprovalRequiredError reports an expense, or a change to one, above its
+// author's spending limit. Nothing was written to the expense; Approval waits
+// for the family owner instead.
+type ApprovalRequiredError struct {
+	Approval ExpenseApproval
+}
+
+func (e *ApprovalRequiredError) Error() string { return ErrApprovalRequired.Error() }
+
+func (e *ApprovalRequiredError) Unwrap() error { return ErrApprovalRequired }
+
+// CurrencyError rejects an expense currency that is not an ISO 4217 code or,
+// when Allowed is set, that the family does not accept.
+type CurrencyError struct {
+	Currency string
+	Allowed  []string
+}
+
+func (e *CurrencyError) Error() string {
+	if len(e.Allowed) == 0 {
+		return fmt.Sprintf("currency %s is not an ISO 4217 code", e.Currency)
+	}
+	return fmt.Sprintf("currency %s is not allowed, use one of %s", e.Currency, strings.Join(e.Allowed, ", "))
+}
+
+func (e *CurrencyError) Unwrap() error { return ErrInvalidCurrency }
+
+// CategoryConflictError reports an update based on a stale category version.
+type CategoryConflictError struct {
+	Current Category
+}
+
+func (e *CategoryConflictError) Error() string { return ErrVersionConflict.Error() }
+
+func (e *CategoryConflictError) Unwrap() error { return ErrVersionConflict }
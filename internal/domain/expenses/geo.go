@@ -0,0 +1,153 @@
+package expenses
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	// MaxPlaceNameLength caps the place name stored with an expense.
+	MaxPlaceNameLength = 200
+	// DefaultGeoGrid and MaxGeoGrid bound the cells per side of a geo query.
+	DefaultGeoGrid = 16
+	MaxGeoGrid     = 64
+)
+
+// Location places an expense on the map. Latitude and Longitude are set
+// together; PlaceName may come alone. An empty Location clears it.
+type Location struct {
+	Latitude  *float64
+	Longitude *float64
+	PlaceName *string
+}
+
+// GeoFilter selects located expenses inside a bounding box. The box is split
+// into Grid x Grid cells and the expenses of each cell form one cluster.
+// Boxes crossing the antimeridian are not supported.
+type GeoFilter struct {
+	South       float64
+	West        float64
+	North       float64
+	East        float64
+	From        *time.Time
+	To          *time.Time
+	CategoryIDs []string
+	Grid        int
+}
+
+// CellSize returns the height and width of a grid cell in degrees.
+func (f GeoFilter) CellSize() (float64, float64) {
+	return (f.North - f.South) / float64(f.Grid), (f.East - f.West) / float64(f.Grid)
+}
+
+// Cell returns the grid row and column of a point inside the box. Points on
+// the north or east edge belong to the last cell.
+func (f GeoFilter) Cell(latitude, longitude float64) (int, int) {
+	latStep, lngStep := f.CellSize()
+	row := min(int(math.Floor((latitude-f.South)/latStep)), f.Grid-1)
+	col := min(int(math.Floor((longitude-f.West)/lngStep)), f.Grid-1)
+	return row, col
+}
+
+// GeoCluster is the centroid of the located expenses in one grid cell.
+// ExpenseID is only set for a cluster of a single expense, so the client can
+// open it directly.
+type GeoCluster struct {
+	Latitude  float64
+	Longitude float64
+	Count     int64
+	// AmountInBase sums the cluster in the family currency.
+	AmountInBase float64
+	ExpenseID    string
+}
+
+// ClusterExpenseLocations groups the family's located expenses in the
+// filter's bounding box, archived ones included, largest clusters first.
+func (s *Service) ClusterExpenseLocations(ctx context.Context, familyID string, filter GeoFilter) ([]GeoCluster, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ClusterExpenseLocations")
+	defer span.End()
+
+	if filter.Grid == 0 {
+		filter.Grid = DefaultGeoGrid
+	}
+	if !validBoundingBox(filter) || filter.Grid < 1 || filter.Grid > MaxGeoGrid {
+		return nil, ErrInvalidBoundingBox
+	}
+	filter.CategoryIDs = normalizeCategoryIDs(filter.CategoryIDs)
+
+	clusters, err := s.repo.ClusterExpenseLocations(ctx, familyID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range clusters {
+		if clusters[i].Count != 1 {
+			clusters[i].ExpenseID = ""
+		}
+		clusters[i].AmountInBase = roundMoney(clusters[i].AmountInBase)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		if clusters[i].Latitude != clusters[j].Latitude {
+			return clusters[i].Latitude < clusters[j].Latitude
+		}
+		return clusters[i].Longitude < clusters[j].Longitude
+	})
+	return clusters, nil
+}
+
+func validBoundingBox(filter GeoFilter) bool {
+	return validLatitude(filter.South) && validLatitude(filter.North) &&
+		validLongitude(filter.West) && validLongitude(filter.East) &&
+		filter.South < filter.North && filter.West < filter.East
+}
+
+// normalizeLocation trims the place name and checks the coordinates. It
+// returns nil for a location without coordinates or place name.
+func normalizeLocation(location *Location) (*Location, error) {
+	if location == nil {
+		return nil, nil
+	}
+	if (location.Latitude == nil) != (location.Longitude == nil) {
+		return nil, ErrInvalidLocation
+	}
+	if location.Latitude != nil && (!validLatitude(*location.Latitude) || !validLongitude(*location.Longitude)) {
+		return nil, ErrInvalidLocation
+	}
+
+	normalized := Location{Latitude: location.Latitude, Longitude: location.Longitude}
+	if location.PlaceName != nil {
+		if name := strings.TrimSpace(*location.PlaceName); name != "" {
+			if utf8.RuneCountInString(name) > MaxPlaceNameLength {
+				return nil, ErrInvalidLocation
+			}
+			normalized.PlaceName = &name
+		}
+	}
+	if normalized.Latitude == nil && normalized.PlaceName == nil {
+		return nil, nil
+	}
+	return &normalized, nil
+}
+
+func setLocation(expense *Expense, location *Location) {
+	expense.Latitude, expense.Longitude, expense.PlaceName = nil, nil, nil
+	if location != nil {
+		expense.Latitude, expense.Longitude, expense.PlaceName = location.Latitude, location.Longitude, location.PlaceName
+	}
+}
+
+func validLatitude(value float64) bool {
+	return value >= -90 && value <= 90
+}
+
+func validLongitude(value float64) bool {
+	return value >= -180 && value <= 180
+}
@@ -14,9 +14,21 @@ type Expense struct {
 	AmountInBase *float64   `gorm:"type:numeric(14,2)"`
 	RateDate     *time.Time `gorm:"type:date"`
 	RateSource   *string    `gorm:"type:text"`
-	Title        string     `gorm:"not null"`
-	CreatedAt    time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+	Title        string     `gorm:"not null;serializer:encrypted"`
+	// AutoCategorized marks categories picked by a category rule or from the
+	// family's history rather than by a user.
+	AutoCategorized bool `gorm:"not null;default:false"`
+	// IsArchived hides an expense from default lists; analytics and reports
+	// still count it.
+	IsArchived bool      `gorm:"not null;default:false"`
+	Version    int64     `gorm:"not null;default:1"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+
+	// Latitude and Longitude place the expense on the map; see Location.
+	Latitude  *float64 `gorm:"type:double precision"`
+	Longitude *float64 `gorm:"type:double precision"`
+	PlaceName *string  `gorm:"serializer:encrypted"`
 }
 
 type Category struct {
@@ -25,6 +37,7 @@ type Category struct {
 	Name      string    `gorm:"not null"`
 	Color     *string   `gorm:"type:text"`
 	Emoji     *string   `gorm:"type:text"`
+	Version   int64     `gorm:"not null;default:1"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
 
@@ -43,10 +56,42 @@ type ListFilter struct {
 	To          *time.Time
 	Currency    string
 	CategoryIDs []string
-	Limit       int
-	Offset      int
+	// Query limits expenses to titles containing it, case-insensitively.
+	Query string
+	// Archived selects archived expenses; empty means all of them.
+	Archived ArchivedFilter
+	Limit    int
+	Offset   int
+}
+
+// CurrencyTotal sums the expenses in one currency. AmountInBase only covers
+// expenses converted to the family currency; Unconverted counts the rest.
+type CurrencyTotal struct {
+	Currency     string
+	Amount       float64
+	Count        int64
+	AmountInBase float64
+	Unconverted  int64
+}
+
+// ExpenseTotals sums the expenses matching a list filter across all pages.
+type ExpenseTotals struct {
+	ByCurrency   []CurrencyTotal
+	BaseCurrency string
+	// TotalInBase is the sum converted to BaseCurrency; expenses without a
+	// conversion are left out and counted in Unconverted.
+	TotalInBase float64
+	Unconverted int64
 }
 
+type ArchivedFilter string
+
+const (
+	ArchivedExclude ArchivedFilter = "exclude"
+	ArchivedOnly    ArchivedFilter = "only"
+	ArchivedAll     ArchivedFilter = "all"
+)
+
 type CreateExpenseInput struct {
 	FamilyID     string
 	UserID       string
@@ -56,17 +101,37 @@ type CreateExpenseInput struct {
 	BaseCurrency string
 	Title        string
 	CategoryIDs  []string
+	// AllowedCurrencies restricts Currency to these codes and BaseCurrency;
+	// empty accepts any ISO 4217 code.
+	AllowedCurrencies []string
+	Location          *Location
+	// CreatedAt backdates the expense, e.g. to when it was recorded offline;
+	// nil means now.
+	CreatedAt *time.Time
 }
 
 type UpdateExpenseInput struct {
-	ID           string
-	FamilyID     string
+	ID       string
+	FamilyID string
+	// UserID is the member making the change; raising the amount above
+	// their spending limit needs the owner's approval.
+	UserID       string
 	Date         time.Time
 	Amount       float64
 	Currency     string
 	BaseCurrency string
 	Title        string
 	CategoryIDs  []string
+	// AllowedCurrencies is checked only when the currency changes, so older
+	// expenses stay editable after the family narrows its list.
+	AllowedCurrencies []string
+	// Location replaces the stored location when set; nil keeps it.
+	Location *Location
+	// IsArchived, when set, archives or restores the expense.
+	IsArchived *bool
+	// ExpectedVersion rejects the update with an ExpenseConflictError when
+	// the stored version differs. Zero skips the check.
+	ExpectedVersion int64
 }
 
 type CreateCategoryInput struct {
@@ -87,4 +152,70 @@ type UpdateCategoryInput struct {
 	Name       string
 	Color      OptionalNullableString
 	Emoji      OptionalNullableString
+	// ExpectedVersion rejects the update with a CategoryConflictError when
+	// the stored version differs. Zero skips the check.
+	ExpectedVersion int64
+}
+
+// RuleMatchType says how a category rule's pattern is compared with an
+// expense title. Both are compared lowercased with whitespace collapsed.
+type RuleMatchType string
+
+const (
+	RuleMatchContains RuleMatchType = "contains"
+	RuleMatchPrefix   RuleMatchType = "prefix"
+	RuleMatchExact    RuleMatchType = "exact"
+)
+
+// CategoryRule assigns CategoryID to new expenses whose title matches
+// Pattern. Rules with a higher Priority are tried first.
+type CategoryRule struct {
+	ID         string        `gorm:"type:uuid;primaryKey"`
+	FamilyID   string        `gorm:"type:uuid;index;not null"`
+	Pattern    string        `gorm:"not null"`
+	MatchType  RuleMatchType `gorm:"type:text;not null"`
+	CategoryID string        `gorm:"type:uuid;not null"`
+	Priority   int           `gorm:"not null;default:0"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time     `gorm:"autoUpdateTime"`
+}
+
+// CategoryUsage counts the expenses filed under a category. LastUsedAt is
+// when the newest of them was created.
+type CategoryUsage struct {
+	CategoryID   string
+	ExpenseCount int64
+	LastUsedAt   *time.Time
+}
+
+// CategorizedTitle is a past expense title with one of its categories, the
+// history learned suggestions are drawn from.
+type CategorizedTitle struct {
+	Title      string `gorm:"serializer:encrypted"`
+	CategoryID string
+}
+
+type CreateCategoryRuleInput struct {
+	FamilyID   string
+	Pattern    string
+	MatchType  RuleMatchType
+	CategoryID string
+	Priority   int
+}
+
+// UpdateCategoryRuleInput changes the fields that are set.
+type UpdateCategoryRuleInput struct {
+	FamilyID   string
+	RuleID     string
+	Pattern    *string
+	MatchType  *RuleMatchType
+	CategoryID *string
+	Priority   *int
+}
+
+// CategorySuggestion explains where a suggested category comes from: the
+// rule that matched, or the family's history when RuleID is empty.
+type CategorySuggestion struct {
+	CategoryID string
+	RuleID     string
 }
@@ -1,22 +1,60 @@
 package expenses
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Transaction(ctx context.Context, fn func(Repository) error) error
 	ListExpenses(ctx context.Context, familyID string, filter ListFilter) ([]Expense, int64, error)
+	// SummarizeExpenses totals the expenses matching filter per currency,
+	// ignoring limit and offset.
+	SummarizeExpenses(ctx context.Context, familyID, baseCurrency string, filter ListFilter) ([]CurrencyTotal, error)
 	GetExpenseByID(ctx context.Context, familyID, expenseID string) (*Expense, error)
 	CreateExpense(ctx context.Context, expense *Expense) error
+	// CountExpensesCreatedSince counts the family's expenses created at or
+	// after since, whatever their date.
+	CountExpensesCreatedSince(ctx context.Context, familyID string, since time.Time) (int64, error)
+	// UpdateExpense only applies when the stored version still matches and bumps it;
+	// otherwise it returns ErrVersionConflict.
 	UpdateExpense(ctx context.Context, expense *Expense) error
 	DeleteExpense(ctx context.Context, familyID, expenseID string) (bool, error)
+	// ArchiveExpensesBefore archives the family's expenses dated before the
+	// given day and returns how many it changed.
+	ArchiveExpensesBefore(ctx context.Context, familyID string, before, at time.Time) (int64, error)
 	ReplaceExpenseCategories(ctx context.Context, expenseID string, categoryIDs []string) error
 	GetCategoryIDsByExpenseIDs(ctx context.Context, expenseIDs []string) (map[string][]string, error)
 	CountCategoriesByIDs(ctx context.Context, familyID string, categoryIDs []string) (int64, error)
 	ListCategories(ctx context.Context, familyID string) ([]Category, error)
 	CreateCategory(ctx context.Context, category *Category) error
 	GetCategoryByID(ctx context.Context, familyID, categoryID string) (*Category, error)
+	// UpdateCategory only applies when the stored version still matches and bumps it;
+	// otherwise it returns ErrVersionConflict.
 	UpdateCategory(ctx context.Context, category *Category) error
 	CountCategoriesByName(ctx context.Context, familyID, name, excludeID string) (int64, error)
 	DeleteCategory(ctx context.Context, familyID, categoryID string) (bool, error)
 	CountExpenseCategoriesByCategoryID(ctx context.Context, categoryID string) (int64, error)
+	// ListCategoryUsage returns usage for the family's categories that have
+	// expenses, archived ones included.
+	ListCategoryUsage(ctx context.Context, familyID string) ([]CategoryUsage, error)
+	// ListCategoryRules returns the family's rules, highest priority first.
+	ListCategoryRules(ctx context.Context, familyID string) ([]CategoryRule, error)
+	GetCategoryRuleByID(ctx context.Context, familyID, ruleID string) (*CategoryRule, error)
+	CreateCategoryRule(ctx context.Context, rule *CategoryRule) error
+	UpdateCategoryRule(ctx context.Context, rule *CategoryRule) error
+	DeleteCategoryRule(ctx context.Context, familyID, ruleID string) (bool, error)
+	// ListCategorizedTitles returns the titles and categories of the family's
+	// most recent expenses dated from since on, newest first.
+	ListCategorizedTitles(ctx context.Context, familyID string, since time.Time, limit int) ([]CategorizedTitle, error)
+	CreateExpenseApproval(ctx context.Context, approval *ExpenseApproval) error
+	// ListExpenseApprovals returns the family's approvals, newest first.
+	ListExpenseApprovals(ctx context.Context, familyID string, filter ApprovalFilter) ([]ExpenseApproval, error)
+	// LockExpenseApproval loads an approval for update; it returns
+	// ErrApprovalNotFound when the family has no such approval.
+	LockExpenseApproval(ctx context.Context, familyID, approvalID string) (*ExpenseApproval, error)
+	UpdateExpenseApproval(ctx context.Context, approval *ExpenseApproval) error
+	// ClusterExpenseLocations aggregates the located expenses matching
+	// filter per grid cell.
+	ClusterExpenseLocations(ctx context.Context, familyID string, filter GeoFilter) ([]GeoCluster, error)
 }
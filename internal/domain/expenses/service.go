@@ -2,21 +2,27 @@ package expenses
 
 import (
 	"context"
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"math"
-	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	quotadomain "family-app-go/internal/domain/quota"
 	ratesdomain "family-app-go/internal/domain/rates"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/money"
+	"family-app-go/pkg/tracing"
+	"family-app-go/pkg/validate"
 )
 
 type Service struct {
 	repo            Repository
 	categoriesCache CategoriesCache
 	rates           RateProvider
+	spendingLimits  SpendingLimits
+	expensesPerDay  int
 }
 
 type RateProvider interface {
@@ -34,17 +40,81 @@ func NewServiceWithCategoriesCache(repo Repository, categoriesCache CategoriesCa
 }
 
 func NewServiceWithDependencies(repo Repository, categoriesCache CategoriesCache, rates RateProvider) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{CategoriesCache: categoriesCache, Rates: rates})
+}
+
+// SpendingLimits looks up a member's spending limit; nil means their
+// expenses need no approval.
+type SpendingLimits interface {
+	GetMemberSpendingLimit(ctx context.Context, userID string) (*float64, error)
+}
+
+type ServiceOptions struct {
+	CategoriesCache CategoriesCache
+	Rates           RateProvider
+	// SpendingLimits, when set, queues expenses above their author's limit
+	// as approvals instead of creating them.
+	SpendingLimits SpendingLimits
+	// ExpensesPerDay caps the expenses a family creates in any 24 hours;
+	// zero leaves it unlimited.
+	ExpensesPerDay int
+}
+
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	categoriesCache := options.CategoriesCache
 	if categoriesCache == nil {
 		categoriesCache = noopCategoriesCache{}
 	}
 	return &Service{
 		repo:            repo,
 		categoriesCache: categoriesCache,
-		rates:           rates,
+		rates:           options.Rates,
+		spendingLimits:  options.SpendingLimits,
+		expensesPerDay:  options.ExpensesPerDay,
+	}
+}
+
+// SearchExpenses returns the family's expenses whose title contains query,
+// newest first.
+func (s *Service) SearchExpenses(ctx context.Context, familyID, query string, limit int) ([]Expense, error) {
+	ctx, span := tracing.Start(ctx, "expenses.SearchExpenses")
+	defer span.End()
+
+	expenses, _, err := s.repo.ListExpenses(ctx, familyID, ListFilter{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
 	}
+	return expenses, nil
+}
+
+// SummarizeExpenses totals the expenses matching filter over all pages, per
+// currency and converted to baseCurrency.
+func (s *Service) SummarizeExpenses(ctx context.Context, familyID, baseCurrency string, filter ListFilter) (ExpenseTotals, error) {
+	ctx, span := tracing.Start(ctx, "expenses.SummarizeExpenses")
+	defer span.End()
+
+	baseCurrency = strings.ToUpper(strings.TrimSpace(baseCurrency))
+	byCurrency, err := s.repo.SummarizeExpenses(ctx, familyID, baseCurrency, filter)
+	if err != nil {
+		return ExpenseTotals{}, err
+	}
+
+	totals := ExpenseTotals{ByCurrency: make([]CurrencyTotal, 0, len(byCurrency)), BaseCurrency: baseCurrency}
+	for _, total := range byCurrency {
+		total.Amount = roundMoney(total.Amount)
+		total.AmountInBase = roundMoney(total.AmountInBase)
+		totals.ByCurrency = append(totals.ByCurrency, total)
+		totals.TotalInBase += total.AmountInBase
+		totals.Unconverted += total.Unconverted
+	}
+	totals.TotalInBase = roundMoney(totals.TotalInBase)
+	return totals, nil
 }
 
 func (s *Service) ListExpenses(ctx context.Context, familyID string, filter ListFilter) ([]ExpenseWithCategories, int64, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ListExpenses")
+	defer span.End()
+
 	expenses, total, err := s.repo.ListExpenses(ctx, familyID, filter)
 	if err != nil {
 		return nil, 0, err
@@ -75,16 +145,105 @@ func (s *Service) ListExpenses(ctx context.Context, familyID string, filter List
 	return items, total, nil
 }
 
+// CreateExpense stores a new expense. An expense above its author's spending
+// limit is queued for approval instead, failing with an ApprovalRequiredError.
 func (s *Service) CreateExpense(ctx context.Context, input CreateExpenseInput) (*ExpenseWithCategories, error) {
-	currency, baseCurrency, err := s.validateInput(input.Currency, input.BaseCurrency, input.Title)
+	ctx, span := tracing.Start(ctx, "expenses.CreateExpense")
+	defer span.End()
+
+	expense, categoryIDs, err := s.prepareExpense(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := s.spendingLimit(ctx, expense.UserID)
 	if err != nil {
 		return nil, err
 	}
+	if overLimit(expense, limit) {
+		approval, err := queueApproval(ctx, s.repo, expense, categoryIDs, *limit, nil)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ApprovalRequiredError{Approval: *approval}
+	}
+	return s.createPreparedExpense(ctx, expense, categoryIDs)
+}
 
-	expenseID, err := newUUID()
+// CreateExpenseWithRepository is CreateExpense through repo, which must
+// already be bound to the caller's transaction. The approval of an expense
+// above the limit is stored through repo too, so the caller must commit
+// rather than roll back on an ApprovalRequiredError.
+func (s *Service) CreateExpenseWithRepository(ctx context.Context, repo Repository, input CreateExpenseInput) (*ExpenseWithCategories, error) {
+	ctx, span := tracing.Start(ctx, "expenses.CreateExpenseWithRepository")
+	defer span.End()
+
+	expense, categoryIDs, err := s.prepareExpense(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := s.spendingLimit(ctx, expense.UserID)
 	if err != nil {
 		return nil, err
 	}
+	if overLimit(expense, limit) {
+		approval, err := queueApproval(ctx, repo, expense, categoryIDs, *limit, nil)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &ApprovalRequiredError{Approval: *approval}
+	}
+	if err := s.checkExpenseQuota(ctx, repo, expense.FamilyID, 1); err != nil {
+		return nil, err
+	}
+	categoryIDs, err = createExpenseInTx(ctx, repo, &expense, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &ExpenseWithCategories{Expense: expense, CategoryIDs: categoryIDs}, nil
+}
+
+// ValidateExpense runs the checks CreateExpense does, currency conversion
+// included, without storing the expense. Categories are only checked when
+// given; an expense left without any is categorized when created.
+func (s *Service) ValidateExpense(ctx context.Context, input CreateExpenseInput) error {
+	ctx, span := tracing.Start(ctx, "expenses.ValidateExpense")
+	defer span.End()
+
+	expense, categoryIDs, err := s.prepareExpense(ctx, input)
+	if err != nil {
+		return err
+	}
+	if len(categoryIDs) > 0 {
+		count, err := s.repo.CountCategoriesByIDs(ctx, expense.FamilyID, categoryIDs)
+		if err != nil {
+			return err
+		}
+		if count != int64(len(categoryIDs)) {
+			return ErrCategoryNotFound
+		}
+	}
+	return s.checkExpenseQuota(ctx, s.repo, expense.FamilyID, 1)
+}
+
+// prepareExpense validates input and converts the amount to the base
+// currency without storing anything.
+func (s *Service) prepareExpense(ctx context.Context, input CreateExpenseInput) (Expense, []string, error) {
+	currency, baseCurrency, err := s.validateInput(input.Amount, input.Currency, input.BaseCurrency, input.Title)
+	if err != nil {
+		return Expense{}, nil, err
+	}
+	if err := checkAllowedCurrency(currency, baseCurrency, input.AllowedCurrencies); err != nil {
+		return Expense{}, nil, err
+	}
+	location, err := normalizeLocation(input.Location)
+	if err != nil {
+		return Expense{}, nil, err
+	}
+
+	expenseID, err := id.New()
+	if err != nil {
+		return Expense{}, nil, err
+	}
 
 	expense := Expense{
 		ID:       expenseID,
@@ -95,31 +254,29 @@ func (s *Service) CreateExpense(ctx context.Context, input CreateExpenseInput) (
 		Currency: currency,
 		Title:    strings.TrimSpace(input.Title),
 	}
+	if input.CreatedAt != nil {
+		expense.CreatedAt = input.CreatedAt.UTC()
+	}
+	setLocation(&expense, location)
 	if err := s.applyCurrencyConversion(ctx, &expense, baseCurrency); err != nil {
-		return nil, err
+		return Expense{}, nil, err
 	}
 
 	categoryIDs := normalizeCategoryIDs(input.CategoryIDs)
 	if err := validateCategoryIDs(categoryIDs); err != nil {
-		return nil, err
+		return Expense{}, nil, err
 	}
+	return expense, categoryIDs, nil
+}
 
-	err = s.repo.Transaction(ctx, func(tx Repository) error {
-		if len(categoryIDs) > 0 {
-			count, err := tx.CountCategoriesByIDs(ctx, input.FamilyID, categoryIDs)
-			if err != nil {
-				return err
-			}
-			if count != int64(len(categoryIDs)) {
-				return ErrCategoryNotFound
-			}
-		}
-
-		if err := tx.CreateExpense(ctx, &expense); err != nil {
+func (s *Service) createPreparedExpense(ctx context.Context, expense Expense, categoryIDs []string) (*ExpenseWithCategories, error) {
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		if err := s.checkExpenseQuota(ctx, tx, expense.FamilyID, 1); err != nil {
 			return err
 		}
-
-		return tx.ReplaceExpenseCategories(ctx, expense.ID, categoryIDs)
+		var err error
+		categoryIDs, err = createExpenseInTx(ctx, tx, &expense, categoryIDs)
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -128,31 +285,194 @@ func (s *Service) CreateExpense(ctx context.Context, input CreateExpenseInput) (
 	return &ExpenseWithCategories{Expense: expense, CategoryIDs: categoryIDs}, nil
 }
 
-func (s *Service) CreateExpensesBatch(ctx context.Context, inputs []CreateExpenseInput) ([]ExpenseWithCategories, error) {
+// checkExpenseQuota fails with a quota.ExceededError when adding more
+// expenses would take the family over its daily quota. Concurrent writers
+// can overshoot it slightly; the quota guards against runaway clients, not
+// exact counts.
+func (s *Service) checkExpenseQuota(ctx context.Context, repo Repository, familyID string, adding int) error {
+	if s.expensesPerDay <= 0 || adding == 0 {
+		return nil
+	}
+	created, err := repo.CountExpensesCreatedSince(ctx, familyID, time.Now().UTC().Add(-quotadomain.ExpensesWindow))
+	if err != nil {
+		return err
+	}
+	return quotadomain.Check(quotadomain.ExpensesPerDay, s.expensesPerDay, quotadomain.ExpensesWindow, created, adding)
+}
+
+// checkExpenseQuotas checks the daily quota of every family in expenses.
+func (s *Service) checkExpenseQuotas(ctx context.Context, repo Repository, expenses []Expense) error {
+	if s.expensesPerDay <= 0 {
+		return nil
+	}
+	adding := make(map[string]int)
+	families := make([]string, 0, 1)
+	for _, expense := range expenses {
+		if adding[expense.FamilyID] == 0 {
+			families = append(families, expense.FamilyID)
+		}
+		adding[expense.FamilyID]++
+	}
+	for _, familyID := range families {
+		if err := s.checkExpenseQuota(ctx, repo, familyID, adding[familyID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createExpenseInTx stores a prepared expense, picking a category from the
+// family's rules and history when none is given. It returns the categories
+// the expense ended up with.
+func createExpenseInTx(ctx context.Context, tx Repository, expense *Expense, categoryIDs []string) ([]string, error) {
+	if len(categoryIDs) == 0 {
+		c, err := newCategorizer(ctx, tx, expense.FamilyID, expense.Date)
+		if err != nil {
+			return nil, err
+		}
+		if suggestion, ok := c.suggest(expense.Title); ok {
+			categoryIDs = []string{suggestion.CategoryID}
+			expense.AutoCategorized = true
+		}
+	}
+	if len(categoryIDs) > 0 {
+		count, err := tx.CountCategoriesByIDs(ctx, expense.FamilyID, categoryIDs)
+		if err != nil {
+			return nil, err
+		}
+		if count != int64(len(categoryIDs)) {
+			return nil, ErrCategoryNotFound
+		}
+	}
+
+	if err := tx.CreateExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	return categoryIDs, tx.ReplaceExpenseCategories(ctx, expense.ID, categoryIDs)
+}
+
+// CreateExpensesBatch stores the expenses in one transaction. Expenses above
+// their author's spending limit are queued for approval instead and returned
+// as approvals; the rest are returned in input order.
+func (s *Service) CreateExpensesBatch(ctx context.Context, inputs []CreateExpenseInput) ([]ExpenseWithCategories, []ExpenseApproval, error) {
+	ctx, span := tracing.Start(ctx, "expenses.CreateExpensesBatch")
+	defer span.End()
+
+	outcomes, err := s.createExpensesBatch(ctx, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	created, approvals := splitBatchOutcomes(outcomes)
+	return created, approvals, nil
+}
+
+// CreateExpensesBatchWithRepository is CreateExpensesBatch through repo,
+// which must already be bound to the caller's transaction.
+func (s *Service) CreateExpensesBatchWithRepository(ctx context.Context, repo Repository, inputs []CreateExpenseInput) ([]ExpenseWithCategories, []ExpenseApproval, error) {
+	ctx, span := tracing.Start(ctx, "expenses.CreateExpensesBatchWithRepository")
+	defer span.End()
+
+	expenses, categoryIDsByExpenseID, err := s.prepareExpensesBatch(ctx, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	limits, err := s.spendingLimitsOf(ctx, expenses)
+	if err != nil {
+		return nil, nil, err
+	}
+	outcomes, err := s.storeExpensesBatch(ctx, repo, expenses, categoryIDsByExpenseID, limits)
+	if err != nil {
+		return nil, nil, err
+	}
+	created, approvals := splitBatchOutcomes(outcomes)
+	return created, approvals, nil
+}
+
+// batchOutcome is what became of one input of a batch: either the expense
+// was created or it waits for approval.
+type batchOutcome struct {
+	expense  *ExpenseWithCategories
+	approval *ExpenseApproval
+}
+
+// createExpensesBatch stores the expenses in one transaction and returns the
+// outcomes in input order.
+func (s *Service) createExpensesBatch(ctx context.Context, inputs []CreateExpenseInput) ([]batchOutcome, error) {
 	expenses, categoryIDsByExpenseID, err := s.prepareExpensesBatch(ctx, inputs)
 	if err != nil {
 		return nil, err
 	}
+	limits, err := s.spendingLimitsOf(ctx, expenses)
+	if err != nil {
+		return nil, err
+	}
 
+	var outcomes []batchOutcome
 	err = s.repo.Transaction(ctx, func(tx Repository) error {
-		return createPreparedExpensesBatch(ctx, tx, inputs, expenses, categoryIDsByExpenseID)
+		var err error
+		outcomes, err = s.storeExpensesBatch(ctx, tx, expenses, categoryIDsByExpenseID, limits)
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
-
-	return expensesBatchResult(expenses, categoryIDsByExpenseID), nil
+	return outcomes, nil
 }
 
-func (s *Service) CreateExpensesBatchWithRepository(ctx context.Context, repo Repository, inputs []CreateExpenseInput) ([]ExpenseWithCategories, error) {
-	expenses, categoryIDsByExpenseID, err := s.prepareExpensesBatch(ctx, inputs)
-	if err != nil {
+// storeExpensesBatch creates the expenses within their author's limit and
+// queues the rest for approval. Only the created expenses count against the
+// daily quota.
+func (s *Service) storeExpensesBatch(ctx context.Context, repo Repository, expenses []Expense, categoryIDsByExpenseID map[string][]string, limits map[string]*float64) ([]batchOutcome, error) {
+	within := make([]Expense, 0, len(expenses))
+	approvals := make(map[string]*ExpenseApproval)
+	for _, expense := range expenses {
+		limit := limits[expense.UserID]
+		if !overLimit(expense, limit) {
+			within = append(within, expense)
+			continue
+		}
+		approval, err := queueApproval(ctx, repo, expense, categoryIDsByExpenseID[expense.ID], *limit, nil)
+		if err != nil {
+			return nil, err
+		}
+		approvals[expense.ID] = approval
+	}
+
+	if err := s.checkExpenseQuotas(ctx, repo, within); err != nil {
 		return nil, err
 	}
-	if err := createPreparedExpensesBatch(ctx, repo, inputs, expenses, categoryIDsByExpenseID); err != nil {
+	if err := createPreparedExpensesBatch(ctx, repo, within, categoryIDsByExpenseID); err != nil {
 		return nil, err
 	}
-	return expensesBatchResult(expenses, categoryIDsByExpenseID), nil
+
+	outcomes := make([]batchOutcome, 0, len(expenses))
+	for _, expense := range expenses {
+		if approval, ok := approvals[expense.ID]; ok {
+			outcomes = append(outcomes, batchOutcome{approval: approval})
+			continue
+		}
+		created := within[0]
+		within = within[1:]
+		outcomes = append(outcomes, batchOutcome{expense: &ExpenseWithCategories{
+			Expense:     created,
+			CategoryIDs: categoryIDsByExpenseID[created.ID],
+		}})
+	}
+	return outcomes, nil
+}
+
+func splitBatchOutcomes(outcomes []batchOutcome) ([]ExpenseWithCategories, []ExpenseApproval) {
+	created := make([]ExpenseWithCategories, 0, len(outcomes))
+	approvals := make([]ExpenseApproval, 0)
+	for _, outcome := range outcomes {
+		if outcome.approval != nil {
+			approvals = append(approvals, *outcome.approval)
+			continue
+		}
+		created = append(created, *outcome.expense)
+	}
+	return created, approvals
 }
 
 func (s *Service) prepareExpensesBatch(ctx context.Context, inputs []CreateExpenseInput) ([]Expense, map[string][]string, error) {
@@ -163,7 +483,14 @@ func (s *Service) prepareExpensesBatch(ctx context.Context, inputs []CreateExpen
 	expenses := make([]Expense, 0, len(inputs))
 	categoryIDsByExpenseID := make(map[string][]string, len(inputs))
 	for _, input := range inputs {
-		currency, baseCurrency, err := s.validateInput(input.Currency, input.BaseCurrency, input.Title)
+		currency, baseCurrency, err := s.validateInput(input.Amount, input.Currency, input.BaseCurrency, input.Title)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := checkAllowedCurrency(currency, baseCurrency, input.AllowedCurrencies); err != nil {
+			return nil, nil, err
+		}
+		location, err := normalizeLocation(input.Location)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -171,7 +498,7 @@ func (s *Service) prepareExpensesBatch(ctx context.Context, inputs []CreateExpen
 			return nil, nil, fmt.Errorf("amount must be positive")
 		}
 
-		expenseID, err := newUUID()
+		expenseID, err := id.New()
 		if err != nil {
 			return nil, nil, err
 		}
@@ -184,6 +511,7 @@ func (s *Service) prepareExpensesBatch(ctx context.Context, inputs []CreateExpen
 			Currency: currency,
 			Title:    strings.TrimSpace(input.Title),
 		}
+		setLocation(&expense, location)
 		if err := s.applyCurrencyConversion(ctx, &expense, baseCurrency); err != nil {
 			return nil, nil, err
 		}
@@ -199,11 +527,41 @@ func (s *Service) prepareExpensesBatch(ctx context.Context, inputs []CreateExpen
 	return expenses, categoryIDsByExpenseID, nil
 }
 
-func createPreparedExpensesBatch(ctx context.Context, repo Repository, inputs []CreateExpenseInput, expenses []Expense, categoryIDsByExpenseID map[string][]string) error {
-	for index, expense := range expenses {
+// createPreparedExpensesBatch stores the expenses. Expenses without
+// categories are auto-categorized first, with one categorizer per family
+// built before anything is stored.
+func createPreparedExpensesBatch(ctx context.Context, repo Repository, expenses []Expense, categoryIDsByExpenseID map[string][]string) error {
+	categorizers := make(map[string]*categorizer)
+	for index := range expenses {
+		expense := &expenses[index]
+		if len(categoryIDsByExpenseID[expense.ID]) > 0 {
+			continue
+		}
+		c, ok := categorizers[expense.FamilyID]
+		if !ok {
+			from := expense.Date
+			for _, other := range expenses {
+				if other.FamilyID == expense.FamilyID && other.Date.Before(from) {
+					from = other.Date
+				}
+			}
+			var err error
+			c, err = newCategorizer(ctx, repo, expense.FamilyID, from)
+			if err != nil {
+				return err
+			}
+			categorizers[expense.FamilyID] = c
+		}
+		if suggestion, ok := c.suggest(expense.Title); ok {
+			categoryIDsByExpenseID[expense.ID] = []string{suggestion.CategoryID}
+			expense.AutoCategorized = true
+		}
+	}
+
+	for _, expense := range expenses {
 		categoryIDs := categoryIDsByExpenseID[expense.ID]
 		if len(categoryIDs) > 0 {
-			count, err := repo.CountCategoriesByIDs(ctx, inputs[index].FamilyID, categoryIDs)
+			count, err := repo.CountCategoriesByIDs(ctx, expense.FamilyID, categoryIDs)
 			if err != nil {
 				return err
 			}
@@ -223,19 +581,14 @@ func createPreparedExpensesBatch(ctx context.Context, repo Repository, inputs []
 	return nil
 }
 
-func expensesBatchResult(expenses []Expense, categoryIDsByExpenseID map[string][]string) []ExpenseWithCategories {
-	result := make([]ExpenseWithCategories, 0, len(expenses))
-	for _, expense := range expenses {
-		result = append(result, ExpenseWithCategories{
-			Expense:     expense,
-			CategoryIDs: categoryIDsByExpenseID[expense.ID],
-		})
-	}
-	return result
-}
-
+// UpdateExpense changes a stored expense. A change raising the amount above
+// the spending limit of input.UserID is queued for approval instead, leaving
+// the expense as it was and failing with an ApprovalRequiredError.
 func (s *Service) UpdateExpense(ctx context.Context, input UpdateExpenseInput) (*ExpenseWithCategories, error) {
-	currency, baseCurrency, err := s.validateInput(input.Currency, input.BaseCurrency, input.Title)
+	ctx, span := tracing.Start(ctx, "expenses.UpdateExpense")
+	defer span.End()
+
+	currency, baseCurrency, err := s.validateInput(input.Amount, input.Currency, input.BaseCurrency, input.Title)
 	if err != nil {
 		return nil, err
 	}
@@ -244,8 +597,20 @@ func (s *Service) UpdateExpense(ctx context.Context, input UpdateExpenseInput) (
 	if err := validateCategoryIDs(categoryIDs); err != nil {
 		return nil, err
 	}
+	location, err := normalizeLocation(input.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := s.spendingLimit(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
 
-	var updated Expense
+	var (
+		updated  Expense
+		approval *ExpenseApproval
+	)
 	err = s.repo.Transaction(ctx, func(tx Repository) error {
 		if len(categoryIDs) > 0 {
 			count, err := tx.CountCategoriesByIDs(ctx, input.FamilyID, categoryIDs)
@@ -261,17 +626,49 @@ func (s *Service) UpdateExpense(ctx context.Context, input UpdateExpenseInput) (
 		if err != nil {
 			return err
 		}
+		if input.ExpectedVersion > 0 && expense.Version != input.ExpectedVersion {
+			return expenseConflict(ctx, tx, input.FamilyID, input.ID)
+		}
+		if expense.Currency != currency {
+			if err := checkAllowedCurrency(currency, baseCurrency, input.AllowedCurrencies); err != nil {
+				return err
+			}
+		}
+		if expense.AutoCategorized {
+			// Auto-picked categories stay flagged until a user changes them.
+			current, err := tx.GetCategoryIDsByExpenseIDs(ctx, []string{expense.ID})
+			if err != nil {
+				return err
+			}
+			expense.AutoCategorized = sameCategoryIDs(current[expense.ID], categoryIDs)
+		}
 
+		before := *expense
 		expense.Date = input.Date
 		expense.Amount = input.Amount
 		expense.Currency = currency
 		expense.Title = strings.TrimSpace(input.Title)
+		if input.IsArchived != nil {
+			expense.IsArchived = *input.IsArchived
+		}
+		if input.Location != nil {
+			setLocation(expense, location)
+		}
 		expense.UpdatedAt = time.Now().UTC()
 		if err := s.applyCurrencyConversion(ctx, expense, baseCurrency); err != nil {
 			return err
 		}
+		if overLimit(*expense, limit) && raisesAmount(before, *expense) {
+			// The approval must be committed, so the change is reported
+			// once the transaction is done.
+			approval, err = queueApproval(ctx, tx, *expense, categoryIDs, *limit, &expense.ID)
+			return err
+		}
 
 		if err := tx.UpdateExpense(ctx, expense); err != nil {
+			if errors.Is(err, ErrVersionConflict) {
+				return expenseConflict(ctx, tx, input.FamilyID, input.ID)
+			}
 			return err
 		}
 
@@ -285,11 +682,64 @@ func (s *Service) UpdateExpense(ctx context.Context, input UpdateExpenseInput) (
 	if err != nil {
 		return nil, err
 	}
+	if approval != nil {
+		return nil, &ApprovalRequiredError{Approval: *approval}
+	}
 
 	return &ExpenseWithCategories{Expense: updated, CategoryIDs: categoryIDs}, nil
 }
 
+// raisesAmount reports whether an update makes the expense cost more. Edits
+// that keep or lower the amount need no new approval, even above the limit.
+func raisesAmount(before, after Expense) bool {
+	if before.AmountInBase != nil && after.AmountInBase != nil {
+		return *after.AmountInBase > *before.AmountInBase
+	}
+	return before.Currency != after.Currency || after.Amount > before.Amount
+}
+
+// expenseConflict loads the stored expense for an ExpenseConflictError.
+func expenseConflict(ctx context.Context, repo Repository, familyID, expenseID string) error {
+	current, err := repo.GetExpenseByID(ctx, familyID, expenseID)
+	if err != nil {
+		return err
+	}
+	categoryIDs, err := repo.GetCategoryIDsByExpenseIDs(ctx, []string{current.ID})
+	if err != nil {
+		return err
+	}
+	return &ExpenseConflictError{Current: ExpenseWithCategories{Expense: *current, CategoryIDs: categoryIDs[current.ID]}}
+}
+
+// GetExpense returns one of the family's expenses, or ErrExpenseNotFound.
+func (s *Service) GetExpense(ctx context.Context, familyID, expenseID string) (*Expense, error) {
+	ctx, span := tracing.Start(ctx, "expenses.GetExpense")
+	defer span.End()
+
+	return s.repo.GetExpenseByID(ctx, familyID, expenseID)
+}
+
+// GetExpenseWithCategories returns an expense, archived or not, with its
+// category IDs.
+func (s *Service) GetExpenseWithCategories(ctx context.Context, familyID, expenseID string) (*ExpenseWithCategories, error) {
+	ctx, span := tracing.Start(ctx, "expenses.GetExpenseWithCategories")
+	defer span.End()
+
+	expense, err := s.repo.GetExpenseByID(ctx, familyID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	categoryIDsByExpense, err := s.repo.GetCategoryIDsByExpenseIDs(ctx, []string{expense.ID})
+	if err != nil {
+		return nil, err
+	}
+	return &ExpenseWithCategories{Expense: *expense, CategoryIDs: categoryIDsByExpense[expense.ID]}, nil
+}
+
 func (s *Service) DeleteExpense(ctx context.Context, familyID, expenseID string) error {
+	ctx, span := tracing.Start(ctx, "expenses.DeleteExpense")
+	defer span.End()
+
 	deleted, err := s.repo.DeleteExpense(ctx, familyID, expenseID)
 	if err != nil {
 		return err
@@ -300,7 +750,19 @@ func (s *Service) DeleteExpense(ctx context.Context, familyID, expenseID string)
 	return nil
 }
 
+// ArchiveExpensesBefore archives the family's expenses dated before the given
+// day so default lists skip them, and returns how many were archived.
+func (s *Service) ArchiveExpensesBefore(ctx context.Context, familyID string, before time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ArchiveExpensesBefore")
+	defer span.End()
+
+	return s.repo.ArchiveExpensesBefore(ctx, familyID, before, time.Now().UTC())
+}
+
 func (s *Service) ListCategories(ctx context.Context, familyID string) ([]Category, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ListCategories")
+	defer span.End()
+
 	if cached, ok := s.categoriesCache.GetByFamilyID(familyID); ok {
 		return cloneCategories(cached), nil
 	}
@@ -314,44 +776,104 @@ func (s *Service) ListCategories(ctx context.Context, familyID string) ([]Catego
 	return cloneCategories(categories), nil
 }
 
-func (s *Service) CreateCategory(ctx context.Context, input CreateCategoryInput) (*Category, error) {
-	name, err := validateCategoryName(input.Name)
+// ListCategoryUsage returns the usage of the family's categories by category
+// ID. Categories without expenses are missing from the map.
+func (s *Service) ListCategoryUsage(ctx context.Context, familyID string) (map[string]CategoryUsage, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ListCategoryUsage")
+	defer span.End()
+
+	rows, err := s.repo.ListCategoryUsage(ctx, familyID)
 	if err != nil {
 		return nil, err
 	}
+	usage := make(map[string]CategoryUsage, len(rows))
+	for _, row := range rows {
+		usage[row.CategoryID] = row
+	}
+	return usage, nil
+}
 
-	color, err := normalizeCategoryColor(input.Color)
+func (s *Service) GetCategory(ctx context.Context, familyID, categoryID string) (*Category, error) {
+	ctx, span := tracing.Start(ctx, "expenses.GetCategory")
+	defer span.End()
+
+	return s.repo.GetCategoryByID(ctx, familyID, categoryID)
+}
+
+func (s *Service) CreateCategory(ctx context.Context, input CreateCategoryInput) (*Category, error) {
+	ctx, span := tracing.Start(ctx, "expenses.CreateCategory")
+	defer span.End()
+
+	return s.createCategory(ctx, s.repo, input)
+}
+
+// CreateCategoryWithRepository is CreateCategory through repo, which must
+// already be bound to the caller's transaction.
+func (s *Service) CreateCategoryWithRepository(ctx context.Context, repo Repository, input CreateCategoryInput) (*Category, error) {
+	ctx, span := tracing.Start(ctx, "expenses.CreateCategoryWithRepository")
+	defer span.End()
+
+	return s.createCategory(ctx, repo, input)
+}
+
+// ValidateCategory runs the checks CreateCategory does without storing the
+// category.
+func (s *Service) ValidateCategory(ctx context.Context, input CreateCategoryInput) error {
+	_, span := tracing.Start(ctx, "expenses.ValidateCategory")
+	defer span.End()
+
+	_, err := prepareCategory(input)
+	return err
+}
+
+func (s *Service) createCategory(ctx context.Context, repo Repository, input CreateCategoryInput) (*Category, error) {
+	category, err := prepareCategory(input)
 	if err != nil {
 		return nil, err
 	}
 
-	emoji, err := normalizeCategoryEmoji(input.Emoji)
+	newID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
+	category.ID = newID
 
-	id, err := newUUID()
-	if err != nil {
+	if err := repo.CreateCategory(ctx, &category); err != nil {
 		return nil, err
 	}
 
-	category := Category{
-		ID:       id,
-		FamilyID: input.FamilyID,
-		Name:     name,
-		Color:    color,
-		Emoji:    emoji,
+	s.categoriesCache.DeleteByFamilyID(input.FamilyID)
+	return &category, nil
+}
+
+func prepareCategory(input CreateCategoryInput) (Category, error) {
+	name, err := validateCategoryName(input.Name)
+	if err != nil {
+		return Category{}, err
 	}
 
-	if err := s.repo.CreateCategory(ctx, &category); err != nil {
-		return nil, err
+	color, err := normalizeCategoryColor(input.Color)
+	if err != nil {
+		return Category{}, err
 	}
 
-	s.categoriesCache.DeleteByFamilyID(input.FamilyID)
-	return &category, nil
+	emoji, err := normalizeCategoryEmoji(input.Emoji)
+	if err != nil {
+		return Category{}, err
+	}
+
+	return Category{
+		FamilyID: input.FamilyID,
+		Name:     name,
+		Color:    color,
+		Emoji:    emoji,
+	}, nil
 }
 
 func (s *Service) UpdateCategory(ctx context.Context, input UpdateCategoryInput) (*Category, error) {
+	ctx, span := tracing.Start(ctx, "expenses.UpdateCategory")
+	defer span.End()
+
 	name, err := validateCategoryName(input.Name)
 	if err != nil {
 		return nil, err
@@ -361,6 +883,9 @@ func (s *Service) UpdateCategory(ctx context.Context, input UpdateCategoryInput)
 	if err != nil {
 		return nil, err
 	}
+	if input.ExpectedVersion > 0 && category.Version != input.ExpectedVersion {
+		return nil, &CategoryConflictError{Current: *category}
+	}
 
 	count, err := s.repo.CountCategoriesByName(ctx, input.FamilyID, name, category.ID)
 	if err != nil {
@@ -387,6 +912,13 @@ func (s *Service) UpdateCategory(ctx context.Context, input UpdateCategoryInput)
 	}
 
 	if err := s.repo.UpdateCategory(ctx, category); err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			current, getErr := s.repo.GetCategoryByID(ctx, input.FamilyID, input.CategoryID)
+			if getErr != nil {
+				return nil, getErr
+			}
+			return nil, &CategoryConflictError{Current: *current}
+		}
 		return nil, err
 	}
 
@@ -395,6 +927,9 @@ func (s *Service) UpdateCategory(ctx context.Context, input UpdateCategoryInput)
 }
 
 func (s *Service) DeleteCategory(ctx context.Context, familyID, categoryID string) error {
+	ctx, span := tracing.Start(ctx, "expenses.DeleteCategory")
+	defer span.End()
+
 	inUse, err := s.repo.CountExpenseCategoriesByCategoryID(ctx, categoryID)
 	if err != nil {
 		return err
@@ -413,7 +948,10 @@ func (s *Service) DeleteCategory(ctx context.Context, familyID, categoryID strin
 	return nil
 }
 
-func (s *Service) validateInput(currency, baseCurrency, title string) (string, string, error) {
+// validateInput checks the title, the currencies and that the amount fits
+// the currency's minor units. Unknown currencies fail with a *CurrencyError,
+// too fine amounts with a *money.PrecisionError.
+func (s *Service) validateInput(amount float64, currency, baseCurrency, title string) (string, string, error) {
 	if strings.TrimSpace(title) == "" {
 		return "", "", fmt.Errorf("title is required")
 	}
@@ -421,6 +959,12 @@ func (s *Service) validateInput(currency, baseCurrency, title string) (string, s
 	if err != nil {
 		return "", "", fmt.Errorf("currency is required")
 	}
+	if !money.IsCurrency(normalizedCurrency) {
+		return "", "", &CurrencyError{Currency: normalizedCurrency}
+	}
+	if err := money.CheckPrecision(amount, normalizedCurrency); err != nil {
+		return "", "", err
+	}
 	normalizedBaseCurrency := normalizedCurrency
 	if strings.TrimSpace(baseCurrency) != "" {
 		normalizedBaseCurrency, err = normalizeCurrencyCode(baseCurrency)
@@ -438,7 +982,7 @@ func (s *Service) applyCurrencyConversion(ctx context.Context, expense *Expense,
 
 	if expense.Currency == baseCurrency {
 		expense.ExchangeRate = float64Ptr(1)
-		expense.AmountInBase = float64Ptr(roundMoney(expense.Amount))
+		expense.AmountInBase = float64Ptr(money.Round(expense.Amount, baseCurrency))
 		expense.RateSource = stringPtr("identity")
 		return nil
 	}
@@ -456,7 +1000,7 @@ func (s *Service) applyCurrencyConversion(ctx context.Context, expense *Expense,
 	}
 
 	expense.ExchangeRate = float64Ptr(quote.Rate)
-	expense.AmountInBase = float64Ptr(roundMoney(expense.Amount * quote.Rate))
+	expense.AmountInBase = float64Ptr(money.Round(expense.Amount*quote.Rate, baseCurrency))
 	expense.RateDate = timePtr(dateOnlyUTC(quote.Date))
 	source := strings.TrimSpace(quote.Source)
 	if source == "" {
@@ -467,6 +1011,27 @@ func (s *Service) applyCurrencyConversion(ctx context.Context, expense *Expense,
 	return nil
 }
 
+// checkAllowedCurrency accepts the base currency and, when the family keeps
+// a list, the currencies on it.
+func checkAllowedCurrency(currency, baseCurrency string, allowed []string) error {
+	if len(allowed) == 0 || currency == baseCurrency {
+		return nil
+	}
+	for _, code := range allowed {
+		if strings.EqualFold(code, currency) {
+			return nil
+		}
+	}
+	codes := []string{baseCurrency}
+	for _, code := range allowed {
+		if code != baseCurrency {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return &CurrencyError{Currency: currency, Allowed: codes}
+}
+
 func normalizeCurrencyCode(currency string) (string, error) {
 	currency = strings.ToUpper(strings.TrimSpace(currency))
 	if len(currency) != 3 {
@@ -503,6 +1068,22 @@ func roundMoney(value float64) float64 {
 	return math.Round(value*100) / 100
 }
 
+func sameCategoryIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
+
 func normalizeCategoryIDs(categoryIDs []string) []string {
 	if len(categoryIDs) == 0 {
 		return nil
@@ -527,7 +1108,7 @@ func normalizeCategoryIDs(categoryIDs []string) []string {
 
 func validateCategoryIDs(categoryIDs []string) error {
 	for _, categoryID := range categoryIDs {
-		if !isUUID(categoryID) {
+		if !id.IsUUID(categoryID) {
 			return ErrCategoryNotFound
 		}
 	}
@@ -565,15 +1146,13 @@ func validateCategoryName(name string) (string, error) {
 	return name, nil
 }
 
-var categoryColorRegex = regexp.MustCompile(`^#[0-9a-f]{6}$`)
-
 func normalizeCategoryColor(value *string) (*string, error) {
 	if value == nil {
 		return nil, nil
 	}
 
-	color := strings.ToLower(strings.TrimSpace(*value))
-	if !categoryColorRegex.MatchString(color) {
+	color, ok := validate.HexColor(*value)
+	if !ok {
 		return nil, ErrInvalidCategoryColor
 	}
 
@@ -585,145 +1164,10 @@ func normalizeCategoryEmoji(value *string) (*string, error) {
 		return nil, nil
 	}
 
-	emoji := strings.TrimSpace(*value)
-	if emoji == "" {
-		return nil, ErrInvalidCategoryEmoji
-	}
-	if !isSingleEmojiGrapheme(emoji) {
+	emoji, ok := validate.Emoji(*value)
+	if !ok {
 		return nil, ErrInvalidCategoryEmoji
 	}
 
 	return &emoji, nil
 }
-
-const (
-	variationSelector16    rune = 0xFE0F
-	zeroWidthJoiner        rune = 0x200D
-	combiningEnclosingMark rune = 0x20E3
-)
-
-func isSingleEmojiGrapheme(value string) bool {
-	runes := []rune(value)
-	if len(runes) == 0 {
-		return false
-	}
-
-	if isKeycapEmoji(runes) {
-		return true
-	}
-	if len(runes) == 2 && isRegionalIndicator(runes[0]) && isRegionalIndicator(runes[1]) {
-		return true
-	}
-
-	index, ok := consumeEmojiComponent(runes, 0)
-	if !ok {
-		return false
-	}
-	for index < len(runes) {
-		if runes[index] != zeroWidthJoiner {
-			return false
-		}
-		index++
-
-		next, ok := consumeEmojiComponent(runes, index)
-		if !ok {
-			return false
-		}
-		index = next
-	}
-
-	return true
-}
-
-func consumeEmojiComponent(runes []rune, index int) (int, bool) {
-	if index >= len(runes) {
-		return index, false
-	}
-	if !isEmojiBase(runes[index]) {
-		return index, false
-	}
-	index++
-
-	if index < len(runes) && runes[index] == variationSelector16 {
-		index++
-	}
-	if index < len(runes) && isEmojiModifier(runes[index]) {
-		index++
-	}
-
-	return index, true
-}
-
-func isEmojiBase(r rune) bool {
-	switch {
-	case r >= 0x1F000 && r <= 0x1FAFF:
-		return true
-	case r >= 0x2600 && r <= 0x27BF:
-		return true
-	case r >= 0x2300 && r <= 0x23FF:
-		return true
-	case r >= 0x2B00 && r <= 0x2BFF:
-		return true
-	case r == 0x00A9 || r == 0x00AE || r == 0x3030 || r == 0x303D || r == 0x3297 || r == 0x3299:
-		return true
-	}
-	return false
-}
-
-func isEmojiModifier(r rune) bool {
-	return r >= 0x1F3FB && r <= 0x1F3FF
-}
-
-func isRegionalIndicator(r rune) bool {
-	return r >= 0x1F1E6 && r <= 0x1F1FF
-}
-
-func isKeycapEmoji(runes []rune) bool {
-	if len(runes) == 2 && isKeycapBase(runes[0]) && runes[1] == combiningEnclosingMark {
-		return true
-	}
-	if len(runes) == 3 && isKeycapBase(runes[0]) && runes[1] == variationSelector16 && runes[2] == combiningEnclosingMark {
-		return true
-	}
-	return false
-}
-
-func isKeycapBase(r rune) bool {
-	return r == '#' || r == '*' || (r >= '0' && r <= '9')
-}
-
-func isUUID(value string) bool {
-	if len(value) != 36 {
-		return false
-	}
-	for i := 0; i < len(value); i++ {
-		ch := value[i]
-		switch i {
-		case 8, 13, 18, 23:
-			if ch != '-' {
-				return false
-			}
-			continue
-		}
-		if !isHex(ch) {
-			return false
-		}
-	}
-	return true
-}
-
-func isHex(ch byte) bool {
-	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
-}
-
-func newUUID() (string, error) {
-	var b [16]byte
-	if _, err := rand.Read(b[:]); err != nil {
-		return "", err
-	}
-
-	b[6] = (b[6] & 0x0f) | 0x40
-	b[8] = (b[8] & 0x3f) | 0x80
-
-	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
-}
@@ -8,16 +8,24 @@ import (
 	"testing"
 	"time"
 
+	quotadomain "family-app-go/internal/domain/quota"
 	ratesdomain "family-app-go/internal/domain/rates"
+	"family-app-go/pkg/money"
 )
 
-const categoryID1 = "11111111-1111-1111-1111-111111111111"
+const (
+	categoryID1 = "11111111-1111-1111-1111-111111111111"
+	categoryID2 = "22222222-2222-2222-2222-222222222222"
+)
 
 type fakeExpensesRepo struct {
 	expenses            map[string]*Expense
 	categories          map[string]*Category
 	expenseCategories   map[string][]string
+	rules               map[string]*CategoryRule
+	approvals           map[string]*ExpenseApproval
 	listCategoriesCalls int
+	geoFilter           GeoFilter
 }
 
 type fakeCategoriesCache struct {
@@ -77,6 +85,8 @@ func newFakeExpensesRepo() *fakeExpensesRepo {
 		expenses:          make(map[string]*Expense),
 		categories:        make(map[string]*Category),
 		expenseCategories: make(map[string][]string),
+		rules:             make(map[string]*CategoryRule),
+		approvals:         make(map[string]*ExpenseApproval),
 	}
 }
 
@@ -99,6 +109,9 @@ func (r *fakeExpensesRepo) ListExpenses(ctx context.Context, familyID string, fi
 		if filter.Currency != "" && !strings.EqualFold(expense.Currency, filter.Currency) {
 			continue
 		}
+		if (filter.Archived == ArchivedExclude && expense.IsArchived) || (filter.Archived == ArchivedOnly && !expense.IsArchived) {
+			continue
+		}
 		if len(filter.CategoryIDs) > 0 {
 			if !containsAny(r.expenseCategories[expense.ID], filter.CategoryIDs) {
 				continue
@@ -130,18 +143,33 @@ func (r *fakeExpensesRepo) GetExpenseByID(ctx context.Context, familyID, expense
 	if !ok || expense.FamilyID != familyID {
 		return nil, ErrExpenseNotFound
 	}
-	return expense, nil
+	copied := *expense
+	return &copied, nil
 }
 
 func (r *fakeExpensesRepo) CreateExpense(ctx context.Context, expense *Expense) error {
+	if expense.CreatedAt.IsZero() {
+		expense.CreatedAt = time.Now().UTC()
+	}
 	r.expenses[expense.ID] = expense
 	return nil
 }
 
+func (r *fakeExpensesRepo) CountExpensesCreatedSince(ctx context.Context, familyID string, since time.Time) (int64, error) {
+	var count int64
+	for _, expense := range r.expenses {
+		if expense.FamilyID == familyID && !expense.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (r *fakeExpensesRepo) UpdateExpense(ctx context.Context, expense *Expense) error {
 	if _, ok := r.expenses[expense.ID]; !ok {
 		return ErrExpenseNotFound
 	}
+	expense.Version++
 	r.expenses[expense.ID] = expense
 	return nil
 }
@@ -156,6 +184,51 @@ func (r *fakeExpensesRepo) DeleteExpense(ctx context.Context, familyID, expenseI
 	return true, nil
 }
 
+func (r *fakeExpensesRepo) SummarizeExpenses(ctx context.Context, familyID, baseCurrency string, filter ListFilter) ([]CurrencyTotal, error) {
+	filter.Limit, filter.Offset = 0, 0
+	items, _, err := r.ListExpenses(ctx, familyID, filter)
+	if err != nil {
+		return nil, err
+	}
+	byCurrency := map[string]*CurrencyTotal{}
+	var currencies []string
+	for _, expense := range items {
+		total, ok := byCurrency[expense.Currency]
+		if !ok {
+			total = &CurrencyTotal{Currency: expense.Currency}
+			byCurrency[expense.Currency] = total
+			currencies = append(currencies, expense.Currency)
+		}
+		total.Amount += expense.Amount
+		total.Count++
+		if expense.AmountInBase != nil && expense.BaseCurrency != nil && *expense.BaseCurrency == baseCurrency {
+			total.AmountInBase += *expense.AmountInBase
+		} else {
+			total.Unconverted++
+		}
+	}
+	sort.Strings(currencies)
+	totals := make([]CurrencyTotal, 0, len(currencies))
+	for _, currency := range currencies {
+		totals = append(totals, *byCurrency[currency])
+	}
+	return totals, nil
+}
+
+func (r *fakeExpensesRepo) ArchiveExpensesBefore(ctx context.Context, familyID string, before, at time.Time) (int64, error) {
+	var archived int64
+	for _, expense := range r.expenses {
+		if expense.FamilyID != familyID || expense.IsArchived || !expense.Date.Before(before) {
+			continue
+		}
+		expense.IsArchived = true
+		expense.UpdatedAt = at
+		expense.Version++
+		archived++
+	}
+	return archived, nil
+}
+
 func (r *fakeExpensesRepo) ReplaceExpenseCategories(ctx context.Context, expenseID string, categoryIDs []string) error {
 	r.expenseCategories[expenseID] = append([]string{}, categoryIDs...)
 	return nil
@@ -215,6 +288,7 @@ func (r *fakeExpensesRepo) UpdateCategory(ctx context.Context, category *Categor
 	if _, ok := r.categories[category.ID]; !ok {
 		return ErrCategoryNotFound
 	}
+	category.Version++
 	r.categories[category.ID] = category
 	return nil
 }
@@ -254,6 +328,171 @@ func (r *fakeExpensesRepo) CountExpenseCategoriesByCategoryID(ctx context.Contex
 	return count, nil
 }
 
+func (r *fakeExpensesRepo) ListCategoryRules(ctx context.Context, familyID string) ([]CategoryRule, error) {
+	result := make([]CategoryRule, 0)
+	for _, rule := range r.rules {
+		if rule.FamilyID == familyID {
+			result = append(result, *rule)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority > result[j].Priority
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *fakeExpensesRepo) GetCategoryRuleByID(ctx context.Context, familyID, ruleID string) (*CategoryRule, error) {
+	rule, ok := r.rules[ruleID]
+	if !ok || rule.FamilyID != familyID {
+		return nil, ErrCategoryRuleNotFound
+	}
+	cloned := *rule
+	return &cloned, nil
+}
+
+func (r *fakeExpensesRepo) CreateCategoryRule(ctx context.Context, rule *CategoryRule) error {
+	cloned := *rule
+	r.rules[rule.ID] = &cloned
+	return nil
+}
+
+func (r *fakeExpensesRepo) UpdateCategoryRule(ctx context.Context, rule *CategoryRule) error {
+	if _, ok := r.rules[rule.ID]; !ok {
+		return ErrCategoryRuleNotFound
+	}
+	cloned := *rule
+	r.rules[rule.ID] = &cloned
+	return nil
+}
+
+func (r *fakeExpensesRepo) DeleteCategoryRule(ctx context.Context, familyID, ruleID string) (bool, error) {
+	rule, ok := r.rules[ruleID]
+	if !ok || rule.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.rules, ruleID)
+	return true, nil
+}
+
+func (r *fakeExpensesRepo) ListCategoryUsage(ctx context.Context, familyID string) ([]CategoryUsage, error) {
+	usage := make(map[string]*CategoryUsage)
+	for _, expense := range r.expenses {
+		if expense.FamilyID != familyID {
+			continue
+		}
+		for _, categoryID := range r.expenseCategories[expense.ID] {
+			row, ok := usage[categoryID]
+			if !ok {
+				row = &CategoryUsage{CategoryID: categoryID}
+				usage[categoryID] = row
+			}
+			row.ExpenseCount++
+			createdAt := expense.CreatedAt
+			if row.LastUsedAt == nil || createdAt.After(*row.LastUsedAt) {
+				row.LastUsedAt = &createdAt
+			}
+		}
+	}
+
+	result := make([]CategoryUsage, 0, len(usage))
+	for _, row := range usage {
+		result = append(result, *row)
+	}
+	return result, nil
+}
+
+func (r *fakeExpensesRepo) ListCategorizedTitles(ctx context.Context, familyID string, since time.Time, limit int) ([]CategorizedTitle, error) {
+	expenses := make([]*Expense, 0)
+	for _, expense := range r.expenses {
+		if expense.FamilyID == familyID && !expense.Date.Before(since) {
+			expenses = append(expenses, expense)
+		}
+	}
+	sort.Slice(expenses, func(i, j int) bool { return expenses[i].Date.After(expenses[j].Date) })
+
+	result := make([]CategorizedTitle, 0)
+	for _, expense := range expenses {
+		for _, categoryID := range r.expenseCategories[expense.ID] {
+			if len(result) == limit {
+				return result, nil
+			}
+			result = append(result, CategorizedTitle{Title: expense.Title, CategoryID: categoryID})
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeExpensesRepo) CreateExpenseApproval(ctx context.Context, approval *ExpenseApproval) error {
+	r.approvals[approval.ID] = approval
+	return nil
+}
+
+func (r *fakeExpensesRepo) ListExpenseApprovals(ctx context.Context, familyID string, filter ApprovalFilter) ([]ExpenseApproval, error) {
+	items := make([]ExpenseApproval, 0)
+	for _, approval := range r.approvals {
+		if approval.FamilyID != familyID {
+			continue
+		}
+		if (filter.Status != "" && approval.Status != filter.Status) || (filter.UserID != "" && approval.UserID != filter.UserID) {
+			continue
+		}
+		items = append(items, *approval)
+	}
+	return items, nil
+}
+
+func (r *fakeExpensesRepo) LockExpenseApproval(ctx context.Context, familyID, approvalID string) (*ExpenseApproval, error) {
+	approval, ok := r.approvals[approvalID]
+	if !ok || approval.FamilyID != familyID {
+		return nil, ErrApprovalNotFound
+	}
+	copied := *approval
+	return &copied, nil
+}
+
+func (r *fakeExpensesRepo) UpdateExpenseApproval(ctx context.Context, approval *ExpenseApproval) error {
+	copied := *approval
+	r.approvals[approval.ID] = &copied
+	return nil
+}
+
+func (r *fakeExpensesRepo) ClusterExpenseLocations(ctx context.Context, familyID string, filter GeoFilter) ([]GeoCluster, error) {
+	r.geoFilter = filter
+	type cell struct{ row, col int }
+	byCell := make(map[cell]*GeoCluster)
+	for _, expense := range r.expenses {
+		if expense.FamilyID != familyID || expense.Latitude == nil {
+			continue
+		}
+		latitude, longitude := *expense.Latitude, *expense.Longitude
+		if latitude < filter.South || latitude > filter.North || longitude < filter.West || longitude > filter.East {
+			continue
+		}
+		row, col := filter.Cell(latitude, longitude)
+		cluster, ok := byCell[cell{row, col}]
+		if !ok {
+			cluster = &GeoCluster{ExpenseID: expense.ID}
+			byCell[cell{row, col}] = cluster
+		}
+		cluster.Latitude += latitude
+		cluster.Longitude += longitude
+		cluster.Count++
+		if expense.AmountInBase != nil {
+			cluster.AmountInBase += *expense.AmountInBase
+		}
+	}
+	clusters := make([]GeoCluster, 0, len(byCell))
+	for _, cluster := range byCell {
+		cluster.Latitude /= float64(cluster.Count)
+		cluster.Longitude /= float64(cluster.Count)
+		clusters = append(clusters, *cluster)
+	}
+	return clusters, nil
+}
+
 func TestCreateExpenseSuccess(t *testing.T) {
 	repo := newFakeExpensesRepo()
 	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "fam-1", Name: "Food"}
@@ -368,6 +607,51 @@ func TestCreateExpenseRateNotAvailable(t *testing.T) {
 	}
 }
 
+func TestCreateExpenseRejectsAmountFinerThanCurrency(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewService(repo)
+
+	_, err := svc.CreateExpense(context.Background(), CreateExpenseInput{
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:       10.5,
+		Currency:     "JPY",
+		BaseCurrency: "JPY",
+		Title:        "Onigiri",
+	})
+	var precisionErr *money.PrecisionError
+	if !errors.As(err, &precisionErr) || precisionErr.Decimals != 0 {
+		t.Fatalf("expected a JPY precision error, got %v", err)
+	}
+	if len(repo.expenses) != 0 {
+		t.Fatalf("expected nothing stored, got %d expenses", len(repo.expenses))
+	}
+}
+
+func TestCreateExpenseRoundsAmountInBaseToBaseCurrency(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewServiceWithDependencies(repo, newFakeCategoriesCache(), fakeRatesProvider{
+		quote: QuoteResult{Rate: 153.456, Date: time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), Source: "nbrb"},
+	})
+
+	created, err := svc.CreateExpense(context.Background(), CreateExpenseInput{
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:       10,
+		Currency:     "USD",
+		BaseCurrency: "JPY",
+		Title:        "Souvenir",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.AmountInBase == nil || *created.AmountInBase != 1535 {
+		t.Fatalf("expected amount_in_base 1535, got %+v", created.AmountInBase)
+	}
+}
+
 func TestCreateExpenseCategoryNotFound(t *testing.T) {
 	repo := newFakeExpensesRepo()
 	svc := NewService(repo)
@@ -388,6 +672,67 @@ func TestCreateExpenseCategoryNotFound(t *testing.T) {
 	}
 }
 
+func TestCreateExpenseDailyQuota(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.expenses["old"] = &Expense{ID: "old", FamilyID: "fam-1", CreatedAt: time.Now().UTC().Add(-25 * time.Hour)}
+	repo.expenses["other"] = &Expense{ID: "other", FamilyID: "fam-2", CreatedAt: time.Now().UTC()}
+	svc := NewServiceWithOptions(repo, ServiceOptions{ExpensesPerDay: 2})
+
+	input := CreateExpenseInput{
+		FamilyID: "fam-1",
+		UserID:   "user-1",
+		Date:     time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:   12.5,
+		Currency: "BYN",
+		Title:    "Coffee",
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.CreateExpense(context.Background(), input); err != nil {
+			t.Fatalf("create expense %d: %v", i+1, err)
+		}
+	}
+
+	_, err := svc.CreateExpense(context.Background(), input)
+	var exceeded *quotadomain.ExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if exceeded.Quota != quotadomain.ExpensesPerDay || exceeded.Limit != 2 || exceeded.Window != 24*time.Hour {
+		t.Fatalf("unexpected quota error: %+v", exceeded)
+	}
+	if len(repo.expenses) != 4 {
+		t.Fatalf("expected the third expense not stored, got %d expenses", len(repo.expenses))
+	}
+}
+
+func TestCreateExpensesBatchQuotaRejectsWholeBatch(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewServiceWithOptions(repo, ServiceOptions{ExpensesPerDay: 2})
+
+	inputs := make([]CreateExpenseInput, 0, 3)
+	for i := 0; i < 3; i++ {
+		inputs = append(inputs, CreateExpenseInput{
+			FamilyID: "fam-1",
+			UserID:   "user-1",
+			Date:     time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+			Amount:   float64(i + 1),
+			Currency: "BYN",
+			Title:    "Row",
+		})
+	}
+
+	_, _, err := svc.CreateExpensesBatch(context.Background(), inputs)
+	if !errors.Is(err, quotadomain.ErrExceeded) {
+		t.Fatalf("expected ErrExceeded, got %v", err)
+	}
+	if len(repo.expenses) != 0 {
+		t.Fatalf("expected nothing stored, got %d expenses", len(repo.expenses))
+	}
+	if _, _, err := svc.CreateExpensesBatch(context.Background(), inputs[:2]); err != nil {
+		t.Fatalf("expected a batch within the quota to pass, got %v", err)
+	}
+}
+
 func TestUpdateExpenseNotFound(t *testing.T) {
 	repo := newFakeExpensesRepo()
 	svc := NewService(repo)
@@ -444,6 +789,158 @@ func TestUpdateExpenseSuccess(t *testing.T) {
 	}
 }
 
+func TestUpdateExpenseKeepsOrClearsLocation(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	created, err := svc.CreateExpense(ctx, CreateExpenseInput{
+		FamilyID: "fam-1",
+		UserID:   "user-1",
+		Date:     time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:   4.5,
+		Currency: "EUR",
+		Title:    "Coffee",
+		Location: &Location{Latitude: float64Ptr(52.52), Longitude: float64Ptr(13.405), PlaceName: stringPtr("  Café Einstein ")},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.PlaceName == nil || *created.PlaceName != "Café Einstein" || created.Latitude == nil || *created.Latitude != 52.52 {
+		t.Fatalf("expected the location stored, got %+v", created.Expense)
+	}
+
+	input := UpdateExpenseInput{
+		ID:       created.ID,
+		FamilyID: "fam-1",
+		Date:     created.Date,
+		Amount:   5,
+		Currency: "EUR",
+		Title:    "Coffee",
+	}
+	updated, err := svc.UpdateExpense(ctx, input)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Latitude == nil || updated.PlaceName == nil {
+		t.Fatalf("expected the location kept without one in the update, got %+v", updated.Expense)
+	}
+
+	input.Location = &Location{}
+	updated, err = svc.UpdateExpense(ctx, input)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Latitude != nil || updated.Longitude != nil || updated.PlaceName != nil {
+		t.Fatalf("expected an empty location to clear it, got %+v", updated.Expense)
+	}
+
+	input.Location = &Location{Latitude: float64Ptr(91)}
+	if _, err := svc.UpdateExpense(ctx, input); !errors.Is(err, ErrInvalidLocation) {
+		t.Fatalf("expected ErrInvalidLocation, got %v", err)
+	}
+}
+
+func TestClusterExpenseLocationsGroupsByGridCell(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	locate := func(id string, latitude, longitude, amount float64) {
+		repo.expenses[id] = &Expense{ID: id, FamilyID: "fam-1", Latitude: &latitude, Longitude: &longitude, AmountInBase: &amount}
+	}
+	locate("exp-1", 52.51, 13.41, 10)
+	locate("exp-2", 52.53, 13.39, 5.5)
+	locate("exp-3", 52.1, 13.9, 7)
+	locate("exp-4", 48.85, 2.35, 100)
+	repo.expenses["exp-5"] = &Expense{ID: "exp-5", FamilyID: "fam-1"}
+	svc := NewService(repo)
+
+	clusters, err := svc.ClusterExpenseLocations(context.Background(), "fam-1", GeoFilter{South: 52, West: 13, North: 53, East: 14, Grid: 4})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", clusters)
+	}
+	if clusters[0].Count != 2 || clusters[0].AmountInBase != 15.5 || clusters[0].ExpenseID != "" {
+		t.Fatalf("unexpected city cluster: %+v", clusters[0])
+	}
+	if clusters[1].Count != 1 || clusters[1].ExpenseID != "exp-3" {
+		t.Fatalf("expected a single expense cluster with its ID, got %+v", clusters[1])
+	}
+
+	if _, err := svc.ClusterExpenseLocations(context.Background(), "fam-1", GeoFilter{South: 53, West: 13, North: 52, East: 14}); !errors.Is(err, ErrInvalidBoundingBox) {
+		t.Fatalf("expected ErrInvalidBoundingBox for a reversed box, got %v", err)
+	}
+	if _, err := svc.ClusterExpenseLocations(context.Background(), "fam-1", GeoFilter{South: 52, West: 13, North: 53, East: 14}); err != nil || repo.geoFilter.Grid != DefaultGeoGrid {
+		t.Fatalf("expected the default grid, got %d, %v", repo.geoFilter.Grid, err)
+	}
+}
+
+func TestUpdateExpenseVersionConflict(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.expenses["exp-1"] = &Expense{
+		ID:       "exp-1",
+		FamilyID: "fam-1",
+		Date:     time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Amount:   5,
+		Currency: "BYN",
+		Title:    "Old",
+		Version:  3,
+	}
+	repo.expenseCategories["exp-1"] = []string{categoryID1}
+
+	svc := NewService(repo)
+	_, err := svc.UpdateExpense(context.Background(), UpdateExpenseInput{
+		ID:              "exp-1",
+		FamilyID:        "fam-1",
+		Date:            time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:          10,
+		Currency:        "BYN",
+		Title:           "New",
+		ExpectedVersion: 2,
+	})
+
+	var conflict *ExpenseConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected ExpenseConflictError, got %v", err)
+	}
+	if conflict.Current.Version != 3 || conflict.Current.Title != "Old" {
+		t.Fatalf("expected stored expense in conflict, got %+v", conflict.Current.Expense)
+	}
+	if len(conflict.Current.CategoryIDs) != 1 || conflict.Current.CategoryIDs[0] != categoryID1 {
+		t.Fatalf("expected stored categories in conflict, got %v", conflict.Current.CategoryIDs)
+	}
+}
+
+func TestUpdateExpenseMatchingVersionBumpsVersion(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.expenses["exp-1"] = &Expense{
+		ID:       "exp-1",
+		FamilyID: "fam-1",
+		Date:     time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Amount:   5,
+		Currency: "BYN",
+		Title:    "Old",
+		Version:  3,
+	}
+
+	svc := NewService(repo)
+	result, err := svc.UpdateExpense(context.Background(), UpdateExpenseInput{
+		ID:              "exp-1",
+		FamilyID:        "fam-1",
+		Date:            time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:          10,
+		Currency:        "BYN",
+		Title:           "New",
+		ExpectedVersion: 3,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Version != 4 {
+		t.Fatalf("expected version 4, got %d", result.Version)
+	}
+}
+
 func TestUpdateExpenseRecalculatesConversion(t *testing.T) {
 	repo := newFakeExpensesRepo()
 	repo.expenses["exp-1"] = &Expense{
@@ -597,39 +1094,329 @@ func TestListExpensesFilterByCategoryIDsEmptyIgnored(t *testing.T) {
 	}
 }
 
-func TestDeleteExpenseNotFound(t *testing.T) {
+func TestArchiveExpensesBeforeHidesThemFromDefaultList(t *testing.T) {
 	repo := newFakeExpensesRepo()
-	svc := NewService(repo)
-	if err := svc.DeleteExpense(context.Background(), "fam-1", "exp-1"); !errors.Is(err, ErrExpenseNotFound) {
-		t.Fatalf("expected ErrExpenseNotFound, got %v", err)
-	}
-}
+	repo.expenses["exp-1"] = &Expense{ID: "exp-1", FamilyID: "fam-1", UserID: "user-1", Date: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), Version: 1}
+	repo.expenses["exp-2"] = &Expense{ID: "exp-2", FamilyID: "fam-1", UserID: "user-1", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Version: 1}
+	repo.expenses["exp-3"] = &Expense{ID: "exp-3", FamilyID: "fam-2", UserID: "user-2", Date: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), Version: 1}
 
-func TestCreateAndDeleteCategory(t *testing.T) {
-	repo := newFakeExpensesRepo()
 	svc := NewService(repo)
-
-	created, err := svc.CreateCategory(context.Background(), CreateCategoryInput{
-		FamilyID: "fam-1",
-		Name:     "Food",
-	})
+	archived, err := svc.ArchiveExpensesBefore(context.Background(), "fam-1", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if created.Name != "Food" {
-		t.Fatalf("expected category name, got %q", created.Name)
+	if archived != 1 || !repo.expenses["exp-1"].IsArchived || repo.expenses["exp-1"].Version != 2 {
+		t.Fatalf("expected exp-1 archived with a new version, got %d %+v", archived, repo.expenses["exp-1"])
+	}
+	if repo.expenses["exp-3"].IsArchived {
+		t.Fatalf("expected other families untouched")
 	}
 
-	if err := svc.DeleteCategory(context.Background(), "fam-1", created.ID); err != nil {
+	items, total, err := svc.ListExpenses(context.Background(), "fam-1", ListFilter{Archived: ArchivedExclude})
+	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if _, ok := repo.categories[created.ID]; ok {
-		t.Fatalf("expected category deleted")
+	if total != 1 || items[0].ID != "exp-2" {
+		t.Fatalf("expected only exp-2, got %+v", items)
 	}
-}
 
-func TestDeleteCategoryNotFound(t *testing.T) {
-	repo := newFakeExpensesRepo()
+	restore := false
+	if _, err := svc.UpdateExpense(context.Background(), UpdateExpenseInput{
+		ID:         "exp-1",
+		FamilyID:   "fam-1",
+		Date:       repo.expenses["exp-1"].Date,
+		Amount:     1,
+		Currency:   "USD",
+		Title:      "Restored",
+		IsArchived: &restore,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.expenses["exp-1"].IsArchived {
+		t.Fatalf("expected exp-1 restored")
+	}
+}
+
+func TestSummarizeExpensesTotalsPerCurrencyAndInBase(t *testing.T) {
+	base := "BYN"
+	rate := func(value float64) *float64 { return &value }
+	repo := newFakeExpensesRepo()
+	repo.expenses["exp-1"] = &Expense{ID: "exp-1", FamilyID: "fam-1", Date: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), Amount: 10.10, Currency: "USD", BaseCurrency: &base, AmountInBase: rate(33.33)}
+	repo.expenses["exp-2"] = &Expense{ID: "exp-2", FamilyID: "fam-1", Date: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC), Amount: 20.20, Currency: "USD", BaseCurrency: &base, AmountInBase: rate(66.67)}
+	repo.expenses["exp-3"] = &Expense{ID: "exp-3", FamilyID: "fam-1", Date: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), Amount: 5, Currency: "BYN", BaseCurrency: &base, AmountInBase: rate(5)}
+	repo.expenses["exp-4"] = &Expense{ID: "exp-4", FamilyID: "fam-1", Date: time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), Amount: 7, Currency: "PLN"}
+	repo.expenses["exp-5"] = &Expense{ID: "exp-5", FamilyID: "fam-1", Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 100, Currency: "USD", BaseCurrency: &base, AmountInBase: rate(300)}
+
+	svc := NewService(repo)
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	totals, err := svc.SummarizeExpenses(context.Background(), "fam-1", "byn", ListFilter{From: &from, Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if totals.BaseCurrency != "BYN" || totals.TotalInBase != 105 || totals.Unconverted != 1 {
+		t.Fatalf("unexpected totals %+v", totals)
+	}
+	if len(totals.ByCurrency) != 3 {
+		t.Fatalf("expected 3 currencies, got %+v", totals.ByCurrency)
+	}
+	usd := totals.ByCurrency[2]
+	if usd.Currency != "USD" || usd.Amount != 30.3 || usd.Count != 2 {
+		t.Fatalf("unexpected USD total %+v", usd)
+	}
+}
+
+// fakeSpendingLimits maps user IDs to their spending limit.
+type fakeSpendingLimits map[string]float64
+
+func (f fakeSpendingLimits) GetMemberSpendingLimit(_ context.Context, userID string) (*float64, error) {
+	limit, ok := f[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &limit, nil
+}
+
+func TestCreateExpenseQueuesExpenseAboveSpendingLimit(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "fam-1", Name: "Food"}
+	svc := NewServiceWithOptions(repo, ServiceOptions{
+		CategoriesCache: newFakeCategoriesCache(),
+		Rates:           fakeRatesProvider{quote: QuoteResult{Rate: 3, Date: time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC), Source: "nbrb"}},
+		SpendingLimits:  fakeSpendingLimits{"user-1": 50},
+	})
+	input := CreateExpenseInput{
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:       20,
+		Currency:     "USD",
+		BaseCurrency: "BYN",
+		Title:        "Headphones",
+		CategoryIDs:  []string{categoryID1},
+	}
+
+	created, err := svc.CreateExpense(context.Background(), input)
+	var required *ApprovalRequiredError
+	if !errors.As(err, &required) {
+		t.Fatalf("expected ApprovalRequiredError, got %v with %+v", err, created)
+	}
+	approval := required.Approval
+	if approval.Status != ApprovalPending || approval.AmountInBase == nil || *approval.AmountInBase != 60 || approval.SpendingLimit != 50 || len(repo.expenses) != 0 {
+		t.Fatalf("unexpected approval %+v", approval)
+	}
+
+	decided, expense, err := svc.ApproveExpense(context.Background(), "fam-1", approval.ID, "owner-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if decided.Status != ApprovalApproved || decided.ExpenseID == nil || *decided.ExpenseID != expense.ID {
+		t.Fatalf("unexpected decision %+v", decided)
+	}
+	stored := repo.expenses[expense.ID]
+	if stored == nil || stored.UserID != "user-1" || *stored.AmountInBase != 60 || len(expense.CategoryIDs) != 1 {
+		t.Fatalf("unexpected expense %+v", expense)
+	}
+
+	if _, err := svc.RejectExpense(context.Background(), "fam-1", approval.ID, "owner-1"); !errors.Is(err, ErrApprovalDecided) {
+		t.Fatalf("expected ErrApprovalDecided, got %v", err)
+	}
+}
+
+func TestCreateExpenseWithinSpendingLimit(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewServiceWithOptions(repo, ServiceOptions{
+		CategoriesCache: newFakeCategoriesCache(),
+		SpendingLimits:  fakeSpendingLimits{"user-1": 50},
+	})
+
+	created, err := svc.CreateExpense(context.Background(), CreateExpenseInput{
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:       50,
+		Currency:     "BYN",
+		BaseCurrency: "BYN",
+		Title:        "Groceries",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.expenses[created.ID] == nil || len(repo.approvals) != 0 {
+		t.Fatalf("expected the expense to be created, got %+v with %d approvals", created, len(repo.approvals))
+	}
+}
+
+func TestCreateExpenseWithRepositoryQueuesExpenseAboveSpendingLimit(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewServiceWithOptions(repo, ServiceOptions{SpendingLimits: fakeSpendingLimits{"user-1": 50}})
+
+	_, err := svc.CreateExpenseWithRepository(context.Background(), repo, CreateExpenseInput{
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+		Amount:       80,
+		Currency:     "BYN",
+		BaseCurrency: "BYN",
+		Title:        "Jacket",
+	})
+	if !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("expected ErrApprovalRequired, got %v", err)
+	}
+	if len(repo.expenses) != 0 || len(repo.approvals) != 1 {
+		t.Fatalf("expected only the approval stored, got %d expenses and %d approvals", len(repo.expenses), len(repo.approvals))
+	}
+}
+
+func TestCreateExpensesBatchQueuesExpensesAboveSpendingLimit(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewServiceWithOptions(repo, ServiceOptions{SpendingLimits: fakeSpendingLimits{"user-1": 50}})
+
+	inputs := make([]CreateExpenseInput, 0, 3)
+	for _, amount := range []float64{10, 80, 20} {
+		inputs = append(inputs, CreateExpenseInput{
+			FamilyID:     "fam-1",
+			UserID:       "user-1",
+			Date:         time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+			Amount:       amount,
+			Currency:     "BYN",
+			BaseCurrency: "BYN",
+			Title:        "Row",
+		})
+	}
+
+	created, approvals, err := svc.CreateExpensesBatch(context.Background(), inputs)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(created) != 2 || created[0].Amount != 10 || created[1].Amount != 20 {
+		t.Fatalf("expected the expenses within the limit created in order, got %+v", created)
+	}
+	if len(approvals) != 1 || approvals[0].Amount != 80 || repo.approvals[approvals[0].ID] == nil {
+		t.Fatalf("expected the expense above the limit queued, got %+v", approvals)
+	}
+	if len(repo.expenses) != 2 {
+		t.Fatalf("expected 2 expenses stored, got %d", len(repo.expenses))
+	}
+}
+
+func TestUpdateExpenseAboveSpendingLimitQueuesApproval(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.expenses["exp-1"] = &Expense{
+		ID:           "exp-1",
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Amount:       20,
+		Currency:     "BYN",
+		BaseCurrency: stringPtr("BYN"),
+		AmountInBase: float64Ptr(20),
+		Title:        "Shoes",
+		Version:      1,
+	}
+	svc := NewServiceWithOptions(repo, ServiceOptions{SpendingLimits: fakeSpendingLimits{"user-1": 50}})
+	input := UpdateExpenseInput{
+		ID:           "exp-1",
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Amount:       80,
+		Currency:     "BYN",
+		BaseCurrency: "BYN",
+		Title:        "Boots",
+	}
+
+	_, err := svc.UpdateExpense(context.Background(), input)
+	var required *ApprovalRequiredError
+	if !errors.As(err, &required) {
+		t.Fatalf("expected ApprovalRequiredError, got %v", err)
+	}
+	approval := required.Approval
+	if approval.UpdatesExpenseID == nil || *approval.UpdatesExpenseID != "exp-1" || approval.ID == "exp-1" || approval.Amount != 80 || repo.approvals[approval.ID] == nil {
+		t.Fatalf("unexpected approval %+v", approval)
+	}
+	if stored := repo.expenses["exp-1"]; stored.Amount != 20 || stored.Title != "Shoes" || stored.Version != 1 {
+		t.Fatalf("expected the expense untouched, got %+v", stored)
+	}
+
+	decided, updated, err := svc.ApproveExpense(context.Background(), "fam-1", approval.ID, "owner-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if decided.ExpenseID == nil || *decided.ExpenseID != "exp-1" || updated.ID != "exp-1" {
+		t.Fatalf("expected the approval to update exp-1, got %+v and %+v", decided, updated)
+	}
+	if stored := repo.expenses["exp-1"]; stored.Amount != 80 || stored.Title != "Boots" || len(repo.expenses) != 1 {
+		t.Fatalf("expected the approved change applied, got %+v", stored)
+	}
+}
+
+func TestUpdateExpenseLoweringAmountAboveSpendingLimitIsApplied(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.expenses["exp-1"] = &Expense{
+		ID:           "exp-1",
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Amount:       90,
+		Currency:     "BYN",
+		BaseCurrency: stringPtr("BYN"),
+		AmountInBase: float64Ptr(90),
+		Title:        "Boots",
+	}
+	svc := NewServiceWithOptions(repo, ServiceOptions{SpendingLimits: fakeSpendingLimits{"user-1": 50}})
+
+	updated, err := svc.UpdateExpense(context.Background(), UpdateExpenseInput{
+		ID:           "exp-1",
+		FamilyID:     "fam-1",
+		UserID:       "user-1",
+		Date:         time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Amount:       70,
+		Currency:     "BYN",
+		BaseCurrency: "BYN",
+		Title:        "Boots",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Amount != 70 || len(repo.approvals) != 0 {
+		t.Fatalf("expected the lower amount applied without approval, got %+v", updated)
+	}
+}
+
+func TestDeleteExpenseNotFound(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewService(repo)
+	if err := svc.DeleteExpense(context.Background(), "fam-1", "exp-1"); !errors.Is(err, ErrExpenseNotFound) {
+		t.Fatalf("expected ErrExpenseNotFound, got %v", err)
+	}
+}
+
+func TestCreateAndDeleteCategory(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	svc := NewService(repo)
+
+	created, err := svc.CreateCategory(context.Background(), CreateCategoryInput{
+		FamilyID: "fam-1",
+		Name:     "Food",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.Name != "Food" {
+		t.Fatalf("expected category name, got %q", created.Name)
+	}
+
+	if err := svc.DeleteCategory(context.Background(), "fam-1", created.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := repo.categories[created.ID]; ok {
+		t.Fatalf("expected category deleted")
+	}
+}
+
+func TestDeleteCategoryNotFound(t *testing.T) {
+	repo := newFakeExpensesRepo()
 	svc := NewService(repo)
 	if err := svc.DeleteCategory(context.Background(), "fam-1", categoryID1); !errors.Is(err, ErrCategoryNotFound) {
 		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
@@ -728,6 +1515,27 @@ func TestUpdateCategoryClearColorAndEmojiWithNull(t *testing.T) {
 	}
 }
 
+func TestUpdateCategoryVersionConflict(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "fam-1", Name: "Food", Version: 2}
+	svc := NewService(repo)
+
+	_, err := svc.UpdateCategory(context.Background(), UpdateCategoryInput{
+		FamilyID:        "fam-1",
+		CategoryID:      categoryID1,
+		Name:            "Groceries",
+		ExpectedVersion: 1,
+	})
+
+	var conflict *CategoryConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected CategoryConflictError, got %v", err)
+	}
+	if conflict.Current.Name != "Food" || conflict.Current.Version != 2 {
+		t.Fatalf("expected stored category in conflict, got %+v", conflict.Current)
+	}
+}
+
 func TestListCategoriesIncludesColorAndEmoji(t *testing.T) {
 	repo := newFakeExpensesRepo()
 	repo.categories[categoryID1] = &Category{
@@ -754,6 +1562,32 @@ func TestListCategoriesIncludesColorAndEmoji(t *testing.T) {
 	}
 }
 
+func TestListCategoryUsageCountsExpensesByCategory(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	older := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(48 * time.Hour)
+	repo.expenses["exp-1"] = &Expense{ID: "exp-1", FamilyID: "fam-1", CreatedAt: older}
+	repo.expenses["exp-2"] = &Expense{ID: "exp-2", FamilyID: "fam-1", CreatedAt: newer}
+	repo.expenses["exp-3"] = &Expense{ID: "exp-3", FamilyID: "fam-2", CreatedAt: newer}
+	repo.expenseCategories["exp-1"] = []string{categoryID1, categoryID2}
+	repo.expenseCategories["exp-2"] = []string{categoryID1}
+	repo.expenseCategories["exp-3"] = []string{categoryID2}
+	svc := NewService(repo)
+
+	usage, err := svc.ListCategoryUsage(context.Background(), "fam-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	food := usage[categoryID1]
+	if food.ExpenseCount != 2 || food.LastUsedAt == nil || !food.LastUsedAt.Equal(newer) {
+		t.Fatalf("unexpected usage for category 1: %+v", food)
+	}
+	other := usage[categoryID2]
+	if other.ExpenseCount != 1 || other.LastUsedAt == nil || !other.LastUsedAt.Equal(older) {
+		t.Fatalf("unexpected usage for category 2: %+v", other)
+	}
+}
+
 func TestCreateCategoryInvalidColor(t *testing.T) {
 	repo := newFakeExpensesRepo()
 	svc := NewService(repo)
@@ -916,3 +1750,392 @@ func containsAny(values []string, candidates []string) bool {
 func strPtr(value string) *string {
 	return &value
 }
+
+func seedExpense(repo *fakeExpensesRepo, id, title string, date time.Time, amount float64, categoryIDs ...string) {
+	repo.expenses[id] = &Expense{ID: id, FamilyID: "family-1", UserID: "user-1", Date: date, Amount: amount, Currency: "EUR", Title: title}
+	repo.expenseCategories[id] = categoryIDs
+}
+
+func TestImportStatementCSVMatchesDuplicatesAndSuggestsCategories(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "family-1", Name: "Groceries"}
+	// Already entered by hand, two days before the bank posted it.
+	seedExpense(repo, "existing-1", "Lidl weekly shop", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), 42.10)
+	seedExpense(repo, "history-1", "LIDL DIENSTLEISTUNG", time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), 18, categoryID1)
+	seedExpense(repo, "history-2", "Lidl", time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 22, categoryID1)
+
+	data := "Booking date;Description;Amount\n" +
+		"05.03.2026;CARD PAYMENT LIDL 1234 BERLIN;-42,10\n" +
+		"06.03.2026;Kartenzahlung LIDL 5678 BERLIN;-12,99\n" +
+		"06.03.2026;Salary;2.500,00\n" +
+		"07.03.2026;Bakery;oops\n"
+
+	service := NewService(repo)
+	result, err := service.ImportStatement(context.Background(), ImportStatementInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Currency: "eur",
+		Data:     strings.NewReader(data),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Format != StatementFormatCSV || result.Rows != 4 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Created != 1 || result.Duplicates != 1 || result.Incoming != 1 {
+		t.Fatalf("expected 1 created, 1 duplicate, 1 incoming, got %+v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 5 || result.Errors[0].Message != "invalid amount" {
+		t.Fatalf("unexpected errors: %+v", result.Errors)
+	}
+
+	duplicate, created := result.Items[0], result.Items[1]
+	if duplicate.Status != StatementItemDuplicate || duplicate.MatchedExpenseID != "existing-1" {
+		t.Fatalf("expected first row to match existing-1, got %+v", duplicate)
+	}
+	if created.Status != StatementItemCreated || created.ExpenseID == "" || created.SuggestedCategoryID != categoryID1 {
+		t.Fatalf("expected created row with suggested category, got %+v", created)
+	}
+	stored := repo.expenses[created.ExpenseID]
+	if stored == nil || stored.Amount != 12.99 || stored.Currency != "EUR" || stored.Title != "Kartenzahlung LIDL 5678 BERLIN" {
+		t.Fatalf("unexpected stored expense: %+v", stored)
+	}
+	if got := repo.expenseCategories[created.ExpenseID]; len(got) != 1 || got[0] != categoryID1 {
+		t.Fatalf("expected suggested category to be applied, got %v", got)
+	}
+
+	// Importing the same file again creates nothing.
+	again, err := service.ImportStatement(context.Background(), ImportStatementInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Currency: "EUR",
+		Data:     strings.NewReader(data),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Created != 0 || again.Duplicates != 2 {
+		t.Fatalf("expected re-import to only find duplicates, got %+v", again)
+	}
+}
+
+func TestImportStatementDoesNotMatchDistantUnrelatedExpense(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	seedExpense(repo, "existing-1", "Cinema", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 10)
+
+	data := "date,description,amount,currency\n2026-03-04,Coffee shop,-10.00,EUR\n"
+	result, err := NewService(repo).ImportStatement(context.Background(), ImportStatementInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		DryRun:   true,
+		Data:     strings.NewReader(data),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Created != 1 || result.Items[0].Status != StatementItemCreated {
+		t.Fatalf("expected the row to be new, got %+v", result.Items)
+	}
+	if result.Items[0].ExpenseID != "" || len(repo.expenses) != 1 {
+		t.Fatalf("dry run must not create expenses")
+	}
+}
+
+func TestImportStatementOFX(t *testing.T) {
+	data := `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>GBP
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20260302120000[0:GMT]
+<TRNAMT>-23.45
+<FITID>1001
+<NAME>TESCO STORES
+<MEMO>Contactless
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20260303
+<TRNAMT>100.00
+<FITID>1002
+<NAME>Refund &amp; more
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+	repo := newFakeExpensesRepo()
+	result, err := NewService(repo).ImportStatement(context.Background(), ImportStatementInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Data:     strings.NewReader(data),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Format != StatementFormatOFX || result.Created != 1 || result.Incoming != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	item := result.Items[0]
+	if item.Title != "TESCO STORES Contactless" || item.Amount != 23.45 || item.Currency != "GBP" || !item.Date.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+	if result.Items[1].Title != "Refund & more" {
+		t.Fatalf("expected entities to be decoded, got %q", result.Items[1].Title)
+	}
+}
+
+func TestImportStatementCAMT(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Acct><Ccy>CHF</Ccy></Acct>
+      <Ntry>
+        <Amt Ccy="CHF">54.20</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2026-03-05</Dt></BookgDt>
+        <NtryDtls><TxDtls>
+          <RltdPties><Cdtr><Nm>Migros Zuerich</Nm></Cdtr></RltdPties>
+          <RmtInf><Ustrd>Einkauf</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="CHF">10.00</Amt>
+        <CdtDbtInd>XXXX</CdtDbtInd>
+        <BookgDt><Dt>2026-03-06</Dt></BookgDt>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+	repo := newFakeExpensesRepo()
+	result, err := NewService(repo).ImportStatement(context.Background(), ImportStatementInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Data:     strings.NewReader(data),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Format != StatementFormatCAMT || result.Created != 1 || len(result.Errors) != 1 || result.Errors[0].Row != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if item := result.Items[0]; item.Title != "Migros Zuerich Einkauf" || item.Amount != 54.2 || item.Currency != "CHF" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
+
+func TestImportStatementRejectsUnknownFiles(t *testing.T) {
+	service := NewService(newFakeExpensesRepo())
+	_, err := service.ImportStatement(context.Background(), ImportStatementInput{
+		FamilyID: "family-1",
+		Data:     strings.NewReader("foo,bar\n1,2\n"),
+	})
+	if !errors.Is(err, ErrInvalidStatementFile) {
+		t.Fatalf("expected ErrInvalidStatementFile, got %v", err)
+	}
+	_, err = service.ImportStatement(context.Background(), ImportStatementInput{
+		FamilyID: "family-1",
+		Format:   "qif",
+		Data:     strings.NewReader(""),
+	})
+	if !errors.Is(err, ErrUnsupportedStatementFormat) {
+		t.Fatalf("expected ErrUnsupportedStatementFormat, got %v", err)
+	}
+}
+
+func TestParseStatementAmount(t *testing.T) {
+	cases := map[string]float64{
+		"-12.50":    -12.5,
+		"1.234,56":  1234.56,
+		"1,234.56":  1234.56,
+		"1 234,56":  1234.56,
+		"(12.50)":   -12.5,
+		"€ -3,5":    -3.5,
+		"1,234":     1234,
+		"−7.00 EUR": -7,
+	}
+	for input, want := range cases {
+		got, err := parseStatementAmount(input)
+		if err != nil || got != want {
+			t.Fatalf("parseStatementAmount(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+}
+
+func TestCreateExpenseAppliesCategoryRule(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "family-1", Name: "Transport"}
+	repo.categories[categoryID2] = &Category{ID: categoryID2, FamilyID: "family-1", Name: "Fun"}
+	svc := NewService(repo)
+
+	if _, err := svc.CreateCategoryRule(context.Background(), CreateCategoryRuleInput{
+		FamilyID: "family-1", Pattern: "  UBER ", CategoryID: categoryID1,
+	}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	if _, err := svc.CreateCategoryRule(context.Background(), CreateCategoryRuleInput{
+		FamilyID: "family-1", Pattern: "uber eats", MatchType: RuleMatchPrefix, CategoryID: categoryID2, Priority: 10,
+	}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+
+	input := CreateExpenseInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Date:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Amount:   9,
+		Currency: "EUR",
+		Title:    "Uber  trip to airport",
+	}
+	created, err := svc.CreateExpense(context.Background(), input)
+	if err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	if !created.AutoCategorized || len(created.CategoryIDs) != 1 || created.CategoryIDs[0] != categoryID1 {
+		t.Fatalf("expected rule category, got %+v", created)
+	}
+
+	input.Title = "Uber Eats dinner"
+	created, err = svc.CreateExpense(context.Background(), input)
+	if err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	if len(created.CategoryIDs) != 1 || created.CategoryIDs[0] != categoryID2 {
+		t.Fatalf("expected higher priority rule to win, got %+v", created.CategoryIDs)
+	}
+
+	input.Title = "Uber trip"
+	input.CategoryIDs = []string{categoryID2}
+	created, err = svc.CreateExpense(context.Background(), input)
+	if err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	if created.AutoCategorized || created.CategoryIDs[0] != categoryID2 {
+		t.Fatalf("expected explicit categories to be kept, got %+v", created)
+	}
+}
+
+func TestCreateExpenseLearnsCategoryFromHistory(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "family-1", Name: "Groceries"}
+	seedExpense(repo, "history-1", "REWE Markt", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 30, categoryID1)
+	seedExpense(repo, "old-1", "Cinema", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 12, categoryID1)
+	svc := NewService(repo)
+
+	created, err := svc.CreateExpense(context.Background(), CreateExpenseInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Date:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Amount:   14,
+		Currency: "EUR",
+		Title:    "rewe markt 0815",
+	})
+	if err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	if !created.AutoCategorized || len(created.CategoryIDs) != 1 || created.CategoryIDs[0] != categoryID1 {
+		t.Fatalf("expected learned category, got %+v", created)
+	}
+
+	// History older than a year is not used.
+	created, err = svc.CreateExpense(context.Background(), CreateExpenseInput{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Date:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Amount:   12,
+		Currency: "EUR",
+		Title:    "Cinema",
+	})
+	if err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	if created.AutoCategorized || len(created.CategoryIDs) != 0 {
+		t.Fatalf("expected no suggestion, got %+v", created)
+	}
+}
+
+func TestUpdateExpenseClearsAutoCategorizedWhenCategoriesChange(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "family-1", Name: "Transport"}
+	repo.categories[categoryID2] = &Category{ID: categoryID2, FamilyID: "family-1", Name: "Travel"}
+	repo.rules["rule-1"] = &CategoryRule{ID: "rule-1", FamilyID: "family-1", Pattern: "taxi", MatchType: RuleMatchContains, CategoryID: categoryID1}
+	svc := NewService(repo)
+
+	created, err := svc.CreateExpense(context.Background(), CreateExpenseInput{
+		FamilyID: "family-1", UserID: "user-1", Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Amount: 20, Currency: "EUR", Title: "Taxi",
+	})
+	if err != nil || !created.AutoCategorized {
+		t.Fatalf("expected auto-categorized expense, got %+v, %v", created, err)
+	}
+
+	update := UpdateExpenseInput{
+		ID: created.ID, FamilyID: "family-1", Date: created.Date,
+		Amount: 25, Currency: "EUR", Title: "Taxi", CategoryIDs: []string{categoryID1},
+	}
+	updated, err := svc.UpdateExpense(context.Background(), update)
+	if err != nil {
+		t.Fatalf("update expense: %v", err)
+	}
+	if !updated.AutoCategorized {
+		t.Fatalf("expected flag to stay while categories are unchanged")
+	}
+
+	update.CategoryIDs = []string{categoryID2}
+	updated, err = svc.UpdateExpense(context.Background(), update)
+	if err != nil {
+		t.Fatalf("update expense: %v", err)
+	}
+	if updated.AutoCategorized {
+		t.Fatalf("expected flag to clear once a user picked categories")
+	}
+}
+
+func TestCategoryRuleValidation(t *testing.T) {
+	repo := newFakeExpensesRepo()
+	repo.categories[categoryID1] = &Category{ID: categoryID1, FamilyID: "family-1", Name: "Transport"}
+	svc := NewService(repo)
+
+	if _, err := svc.CreateCategoryRule(context.Background(), CreateCategoryRuleInput{FamilyID: "family-1", Pattern: " ", CategoryID: categoryID1}); !errors.Is(err, ErrInvalidCategoryRule) {
+		t.Fatalf("expected ErrInvalidCategoryRule, got %v", err)
+	}
+	if _, err := svc.CreateCategoryRule(context.Background(), CreateCategoryRuleInput{FamilyID: "family-1", Pattern: "bus", MatchType: "regex", CategoryID: categoryID1}); !errors.Is(err, ErrInvalidCategoryRule) {
+		t.Fatalf("expected ErrInvalidCategoryRule, got %v", err)
+	}
+	if _, err := svc.CreateCategoryRule(context.Background(), CreateCategoryRuleInput{FamilyID: "family-2", Pattern: "bus", CategoryID: categoryID1}); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("expected ErrCategoryNotFound, got %v", err)
+	}
+
+	rule, err := svc.CreateCategoryRule(context.Background(), CreateCategoryRuleInput{FamilyID: "family-1", Pattern: "bus", CategoryID: categoryID1})
+	if err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	if rule.MatchType != RuleMatchContains {
+		t.Fatalf("expected contains by default, got %q", rule.MatchType)
+	}
+
+	exact := RuleMatchExact
+	priority := 5
+	updated, err := svc.UpdateCategoryRule(context.Background(), UpdateCategoryRuleInput{FamilyID: "family-1", RuleID: rule.ID, MatchType: &exact, Priority: &priority})
+	if err != nil {
+		t.Fatalf("update rule: %v", err)
+	}
+	if updated.MatchType != RuleMatchExact || updated.Priority != 5 || updated.Pattern != "bus" {
+		t.Fatalf("unexpected rule %+v", updated)
+	}
+
+	if err := svc.DeleteCategoryRule(context.Background(), "family-1", rule.ID); err != nil {
+		t.Fatalf("delete rule: %v", err)
+	}
+	if err := svc.DeleteCategoryRule(context.Background(), "family-1", rule.ID); !errors.Is(err, ErrCategoryRuleNotFound) {
+		t.Fatalf("expected ErrCategoryRuleNotFound, got %v", err)
+	}
+}
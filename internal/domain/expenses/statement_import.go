@@ -0,0 +1,344 @@
+package expenses
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/money"
+	"family-app-go/pkg/tracing"
+)
+
+// StatementFormat names a supported bank export layout
+type StatementFormat string
+
+const (
+	StatementFormatAuto StatementFormat = "auto"
+	StatementFormatOFX  StatementFormat = "ofx"
+	StatementFormatCAMT StatementFormat = "camt"
+	StatementFormatCSV  StatementFormat = "csv"
+)
+
+// StatementItemStatus tells what an import did with a statement row
+type StatementItemStatus string
+
+const (
+	StatementItemCreated   StatementItemStatus = "created"
+	StatementItemDuplicate StatementItemStatus = "duplicate"
+	StatementItemIncoming  StatementItemStatus = "incoming"
+	// StatementItemPendingApproval marks a row above the importer's spending
+	// limit; it waits for the owner's approval instead of being created.
+	StatementItemPendingApproval StatementItemStatus = "pending_approval"
+)
+
+const (
+	// MaxStatementRows bounds a single import; longer statements can be split.
+	MaxStatementRows = 5000
+	// StatementMatchWindow is how far apart a statement row and an expense
+	// may be dated and still match: card payments often post days later.
+	StatementMatchWindow = 3 * 24 * time.Hour
+
+	statementTitleMaxLength = 200
+	// A row dated more than a day away from an expense only matches when the
+	// titles are at least this similar.
+	statementMatchSimilarity = 0.5
+)
+
+// ImportStatementInput represents a bank statement import request
+type ImportStatementInput struct {
+	FamilyID string
+	UserID   string
+	Format   StatementFormat
+	// Currency is used for rows that carry none, which is common in CSV.
+	Currency     string
+	BaseCurrency string
+	DryRun       bool
+	Data         io.Reader
+
+	// AllowedCurrencies turns rows in other currencies into row errors.
+	AllowedCurrencies []string
+}
+
+// StatementRowError describes a row that could not be imported. For CSV Row
+// is the 1-based line number, for OFX and CAMT the transaction's position.
+type StatementRowError struct {
+	Row     int
+	Message string
+}
+
+// StatementItem is the outcome for one readable statement row. Duplicate rows
+// carry the expense they matched, created rows the new expense and the
+// category suggested from the family's history, if any. Rows pending approval
+// carry the approval instead of an expense.
+type StatementItem struct {
+	Row                 int
+	Date                time.Time
+	Amount              float64
+	Currency            string
+	Title               string
+	Status              StatementItemStatus
+	ExpenseID           string
+	ApprovalID          string
+	MatchedExpenseID    string
+	SuggestedCategoryID string
+}
+
+// StatementImportResult summarizes an import. In dry-run mode created items
+// describe what would be created and nothing is stored.
+type StatementImportResult struct {
+	DryRun          bool
+	Format          StatementFormat
+	Rows            int
+	Created         int
+	Duplicates      int
+	Incoming        int
+	PendingApproval int
+	Items           []StatementItem
+	Errors          []StatementRowError
+}
+
+// ImportStatement reads a bank export and creates an expense for each
+// outgoing payment that is not already recorded. A row is a duplicate of an
+// existing expense with the same amount and currency dated within
+// StatementMatchWindow, and, unless the dates are at most a day apart, a
+// similar title; each expense matches at most one row, so importing the same
+// statement twice creates nothing the second time. New expenses are
+// auto-categorized like any other expense created without categories, and
+// those above the importer's spending limit are queued for approval.
+// Incoming payments are reported and skipped.
+func (s *Service) ImportStatement(ctx context.Context, input ImportStatementInput) (*StatementImportResult, error) {
+	ctx, span := tracing.Start(ctx, "expenses.ImportStatement")
+	defer span.End()
+
+	parsed, err := parseStatement(input.Data, input.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StatementImportResult{
+		DryRun: input.DryRun,
+		Format: parsed.Format,
+		Rows:   len(parsed.Transactions) + len(parsed.Errors),
+		Items:  make([]StatementItem, 0, len(parsed.Transactions)),
+		Errors: parsed.Errors,
+	}
+	if len(parsed.Transactions) == 0 {
+		return result, nil
+	}
+
+	defaultCurrency, _ := normalizeCurrencyCode(input.Currency)
+	for _, transaction := range parsed.Transactions {
+		item := StatementItem{
+			Row:    transaction.Row,
+			Date:   transaction.Date,
+			Amount: roundMoney(math.Abs(transaction.Amount)),
+			Title:  statementTitle(transaction.Description),
+		}
+		if transaction.Amount >= 0 {
+			item.Status = StatementItemIncoming
+		} else {
+			item.Status = StatementItemCreated
+		}
+
+		currency, err := normalizeCurrencyCode(transaction.Currency)
+		if strings.TrimSpace(transaction.Currency) == "" && defaultCurrency != "" {
+			currency, err = defaultCurrency, nil
+		}
+		if err == nil && !money.IsCurrency(currency) {
+			err = &CurrencyError{Currency: currency}
+		}
+		if err == nil {
+			err = checkAllowedCurrency(currency, input.BaseCurrency, input.AllowedCurrencies)
+		}
+		if err == nil {
+			item.Amount = money.Round(item.Amount, currency)
+		}
+		switch {
+		case err != nil && strings.TrimSpace(transaction.Currency) == "":
+			result.Errors = append(result.Errors, StatementRowError{Row: transaction.Row, Message: "currency is required"})
+			continue
+		case err != nil:
+			message := "invalid currency"
+			var currencyErr *CurrencyError
+			if errors.As(err, &currencyErr) {
+				message = currencyErr.Error()
+			}
+			result.Errors = append(result.Errors, StatementRowError{Row: transaction.Row, Message: message})
+			continue
+		case item.Title == "":
+			result.Errors = append(result.Errors, StatementRowError{Row: transaction.Row, Message: "description is required"})
+			continue
+		case item.Amount == 0:
+			result.Errors = append(result.Errors, StatementRowError{Row: transaction.Row, Message: "amount is zero"})
+			continue
+		}
+		item.Currency = currency
+		result.Items = append(result.Items, item)
+	}
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Row < result.Errors[j].Row })
+
+	if err := s.matchStatementItems(ctx, input.FamilyID, result.Items); err != nil {
+		return nil, err
+	}
+	if err := s.suggestStatementCategories(ctx, input.FamilyID, result.Items); err != nil {
+		return nil, err
+	}
+
+	inputs := make([]CreateExpenseInput, 0, len(result.Items))
+	created := make([]int, 0, len(result.Items))
+	for i, item := range result.Items {
+		switch item.Status {
+		case StatementItemDuplicate:
+			result.Duplicates++
+			continue
+		case StatementItemIncoming:
+			result.Incoming++
+			continue
+		}
+		result.Created++
+		inputs = append(inputs, CreateExpenseInput{
+			FamilyID:     input.FamilyID,
+			UserID:       input.UserID,
+			Date:         item.Date,
+			Amount:       item.Amount,
+			Currency:     item.Currency,
+			BaseCurrency: input.BaseCurrency,
+			Title:        item.Title,
+		})
+		created = append(created, i)
+	}
+
+	if input.DryRun || len(inputs) == 0 {
+		return result, nil
+	}
+
+	outcomes, err := s.createExpensesBatch(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for i, outcome := range outcomes {
+		item := &result.Items[created[i]]
+		if outcome.approval != nil {
+			item.Status = StatementItemPendingApproval
+			item.ApprovalID = outcome.approval.ID
+			result.Created--
+			result.PendingApproval++
+			continue
+		}
+		item.ExpenseID = outcome.expense.ID
+	}
+	return result, nil
+}
+
+// matchStatementItems marks outgoing items that are already recorded as
+// duplicates. Each existing expense is claimed by the closest row.
+func (s *Service) matchStatementItems(ctx context.Context, familyID string, items []StatementItem) error {
+	from, to, ok := statementItemsRange(items, StatementItemCreated)
+	if !ok {
+		return nil
+	}
+	from = from.Add(-StatementMatchWindow)
+	to = to.Add(StatementMatchWindow)
+
+	existing, _, err := s.repo.ListExpenses(ctx, familyID, ListFilter{From: &from, To: &to})
+	if err != nil {
+		return err
+	}
+
+	type candidate struct {
+		item       int
+		expense    int
+		days       int
+		similarity float64
+	}
+	var candidates []candidate
+	for i, item := range items {
+		if item.Status != StatementItemCreated {
+			continue
+		}
+		for j, expense := range existing {
+			if expense.Currency != item.Currency || math.Abs(expense.Amount-item.Amount) >= 0.005 {
+				continue
+			}
+			days := int(math.Abs(dateOnlyUTC(expense.Date).Sub(dateOnlyUTC(item.Date)).Hours()) / 24)
+			if time.Duration(days)*24*time.Hour > StatementMatchWindow {
+				continue
+			}
+			similarity := titleSimilarity(item.Title, expense.Title)
+			if days > 1 && similarity < statementMatchSimilarity {
+				continue
+			}
+			candidates = append(candidates, candidate{item: i, expense: j, days: days, similarity: similarity})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].days != candidates[j].days {
+			return candidates[i].days < candidates[j].days
+		}
+		return candidates[i].similarity > candidates[j].similarity
+	})
+	claimed := make(map[int]bool, len(candidates))
+	for _, match := range candidates {
+		item := &items[match.item]
+		if claimed[match.expense] || item.Status != StatementItemCreated {
+			continue
+		}
+		claimed[match.expense] = true
+		item.Status = StatementItemDuplicate
+		item.MatchedExpenseID = existing[match.expense].ID
+	}
+	return nil
+}
+
+// suggestStatementCategories previews the category each new expense will get
+// when it is created.
+func (s *Service) suggestStatementCategories(ctx context.Context, familyID string, items []StatementItem) error {
+	from, _, ok := statementItemsRange(items, StatementItemCreated)
+	if !ok {
+		return nil
+	}
+	c, err := newCategorizer(ctx, s.repo, familyID, from)
+	if err != nil {
+		return err
+	}
+	for i := range items {
+		if items[i].Status != StatementItemCreated {
+			continue
+		}
+		if suggestion, ok := c.suggest(items[i].Title); ok {
+			items[i].SuggestedCategoryID = suggestion.CategoryID
+		}
+	}
+	return nil
+}
+
+func statementItemsRange(items []StatementItem, status StatementItemStatus) (time.Time, time.Time, bool) {
+	var from, to time.Time
+	found := false
+	for _, item := range items {
+		if item.Status != status {
+			continue
+		}
+		if !found || item.Date.Before(from) {
+			from = item.Date
+		}
+		if !found || item.Date.After(to) {
+			to = item.Date
+		}
+		found = true
+	}
+	return from, to, found
+}
+
+func statementTitle(description string) string {
+	title := strings.Join(strings.Fields(description), " ")
+	if runes := []rune(title); len(runes) > statementTitleMaxLength {
+		title = strings.TrimSpace(string(runes[:statementTitleMaxLength]))
+	}
+	return title
+}
@@ -0,0 +1,553 @@
+package expenses
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// statementTransaction is a single booking read from any supported layout.
+// Amount is signed: negative amounts leave the account.
+type statementTransaction struct {
+	Row         int
+	Date        time.Time
+	Amount      float64
+	Currency    string
+	Description string
+}
+
+type parsedStatement struct {
+	Format       StatementFormat
+	Transactions []statementTransaction
+	Errors       []StatementRowError
+}
+
+func parseStatement(data io.Reader, format StatementFormat) (*parsedStatement, error) {
+	switch format {
+	case "", StatementFormatAuto, StatementFormatOFX, StatementFormatCAMT, StatementFormatCSV:
+	default:
+		return nil, ErrUnsupportedStatementFormat
+	}
+
+	buffered := bufio.NewReader(data)
+	if format == "" || format == StatementFormatAuto {
+		head, err := buffered.Peek(4096)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, ErrInvalidStatementFile
+		}
+		format = detectStatementFormat(head)
+	}
+
+	var (
+		parsed *parsedStatement
+		err    error
+	)
+	switch format {
+	case StatementFormatOFX:
+		parsed, err = parseOFX(buffered)
+	case StatementFormatCAMT:
+		parsed, err = parseCAMT(buffered)
+	default:
+		parsed, err = parseStatementCSV(buffered)
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed.Format = format
+	return parsed, nil
+}
+
+func detectStatementFormat(head []byte) StatementFormat {
+	upper := bytes.ToUpper(head)
+	switch {
+	case bytes.Contains(upper, []byte("OFXHEADER")) || bytes.Contains(upper, []byte("<OFX>")):
+		return StatementFormatOFX
+	case bytes.Contains(head, []byte("camt.05")) || bytes.Contains(head, []byte("<BkToCstmr")):
+		return StatementFormatCAMT
+	}
+	return StatementFormatCSV
+}
+
+// OFX
+
+var ofxEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ")
+
+// parseOFX reads both OFX 1.x (SGML, leaf elements without closing tags) and
+// OFX 2.x (XML) by scanning tags and the text that follows them.
+func parseOFX(data io.Reader) (*parsedStatement, error) {
+	raw, err := io.ReadAll(data)
+	if err != nil {
+		return nil, ErrInvalidStatementFile
+	}
+	content := string(raw)
+	start := strings.Index(strings.ToUpper(content), "<OFX>")
+	if start < 0 {
+		return nil, ErrInvalidStatementFile
+	}
+	content = content[start:]
+
+	parsed := &parsedStatement{}
+	var (
+		defaultCurrency string
+		current         map[string]string
+		index           int
+	)
+	for len(content) > 0 {
+		open := strings.IndexByte(content, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(content[open:], '>')
+		if end < 0 {
+			break
+		}
+		tag := strings.ToUpper(strings.TrimSpace(content[open+1 : open+end]))
+		content = content[open+end+1:]
+
+		next := strings.IndexByte(content, '<')
+		if next < 0 {
+			next = len(content)
+		}
+		value := strings.TrimSpace(ofxEntities.Replace(content[:next]))
+
+		switch {
+		case tag == "STMTTRN":
+			current = make(map[string]string)
+		case tag == "/STMTTRN":
+			if current == nil {
+				continue
+			}
+			index++
+			transaction, err := ofxTransaction(current, defaultCurrency)
+			if err != nil {
+				parsed.Errors = append(parsed.Errors, StatementRowError{Row: index, Message: err.Error()})
+			} else {
+				transaction.Row = index
+				parsed.Transactions = append(parsed.Transactions, transaction)
+			}
+			current = nil
+			if len(parsed.Transactions)+len(parsed.Errors) > MaxStatementRows {
+				return nil, ErrStatementTooLarge
+			}
+		case tag == "CURDEF":
+			defaultCurrency = value
+		case current != nil && !strings.HasPrefix(tag, "/") && value != "":
+			current[tag] = value
+		}
+	}
+
+	if index == 0 && defaultCurrency == "" {
+		return nil, ErrInvalidStatementFile
+	}
+	return parsed, nil
+}
+
+func ofxTransaction(fields map[string]string, defaultCurrency string) (statementTransaction, error) {
+	rawDate := fields["DTPOSTED"]
+	if rawDate == "" {
+		rawDate = fields["DTUSER"]
+	}
+	if len(rawDate) < 8 {
+		return statementTransaction{}, errors.New("invalid date")
+	}
+	date, err := time.Parse("20060102", rawDate[:8])
+	if err != nil {
+		return statementTransaction{}, errors.New("invalid date")
+	}
+
+	amount, err := parseStatementAmount(fields["TRNAMT"])
+	if err != nil {
+		return statementTransaction{}, errors.New("invalid amount")
+	}
+
+	currency := fields["CURSYM"]
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	description := fields["NAME"]
+	if description == "" {
+		description = fields["PAYEE"]
+	}
+	if memo := fields["MEMO"]; memo != "" && !strings.Contains(description, memo) {
+		description = strings.TrimSpace(description + " " + memo)
+	}
+
+	return statementTransaction{
+		Date:        date,
+		Amount:      amount,
+		Currency:    currency,
+		Description: description,
+	}, nil
+}
+
+// CAMT.053 / CAMT.054
+
+type camtDocument struct {
+	Statements    []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+	Notifications []camtStatement `xml:"BkToCstmrDbtCdtNtfctn>Ntfctn"`
+	Reports       []camtStatement `xml:"BkToCstmrAcctRpt>Rpt"`
+}
+
+type camtStatement struct {
+	Currency string      `xml:"Acct>Ccy"`
+	Entries  []camtEntry `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtEntry struct {
+	Amount          camtAmount `xml:"Amt"`
+	CreditDebit     string     `xml:"CdtDbtInd"`
+	BookingDate     camtDate   `xml:"BookgDt"`
+	ValueDate       camtDate   `xml:"ValDt"`
+	AdditionalInfo  string     `xml:"AddtlNtryInf"`
+	CreditorNames   []string   `xml:"NtryDtls>TxDtls>RltdPties>Cdtr>Nm"`
+	CreditorParties []string   `xml:"NtryDtls>TxDtls>RltdPties>Cdtr>Pty>Nm"`
+	DebtorNames     []string   `xml:"NtryDtls>TxDtls>RltdPties>Dbtr>Nm"`
+	DebtorParties   []string   `xml:"NtryDtls>TxDtls>RltdPties>Dbtr>Pty>Nm"`
+	Remittance      []string   `xml:"NtryDtls>TxDtls>RmtInf>Ustrd"`
+}
+
+func parseCAMT(data io.Reader) (*parsedStatement, error) {
+	var document camtDocument
+	if err := xml.NewDecoder(data).Decode(&document); err != nil {
+		return nil, ErrInvalidStatementFile
+	}
+
+	statements := append(append(document.Statements, document.Notifications...), document.Reports...)
+	if len(statements) == 0 {
+		return nil, ErrInvalidStatementFile
+	}
+
+	parsed := &parsedStatement{}
+	index := 0
+	for _, statement := range statements {
+		for _, entry := range statement.Entries {
+			index++
+			if index > MaxStatementRows {
+				return nil, ErrStatementTooLarge
+			}
+			transaction, err := camtTransaction(entry, statement.Currency)
+			if err != nil {
+				parsed.Errors = append(parsed.Errors, StatementRowError{Row: index, Message: err.Error()})
+				continue
+			}
+			transaction.Row = index
+			parsed.Transactions = append(parsed.Transactions, transaction)
+		}
+	}
+	return parsed, nil
+}
+
+func camtTransaction(entry camtEntry, accountCurrency string) (statementTransaction, error) {
+	date, ok := entry.BookingDate.parse()
+	if !ok {
+		date, ok = entry.ValueDate.parse()
+	}
+	if !ok {
+		return statementTransaction{}, errors.New("invalid date")
+	}
+
+	amount, err := parseStatementAmount(entry.Amount.Value)
+	if err != nil {
+		return statementTransaction{}, errors.New("invalid amount")
+	}
+	amount = math.Abs(amount)
+	switch strings.ToUpper(strings.TrimSpace(entry.CreditDebit)) {
+	case "DBIT":
+		amount = -amount
+	case "CRDT":
+	default:
+		return statementTransaction{}, errors.New("invalid credit/debit indicator")
+	}
+
+	currency := entry.Amount.Currency
+	if currency == "" {
+		currency = accountCurrency
+	}
+
+	// The counterparty of a debit is the creditor, and the other way round.
+	names := append(entry.CreditorNames, entry.CreditorParties...)
+	if amount > 0 {
+		names = append(entry.DebtorNames, entry.DebtorParties...)
+	}
+	parts := make([]string, 0, 3)
+	if len(names) > 0 {
+		parts = append(parts, names[0])
+	}
+	if len(entry.Remittance) > 0 {
+		parts = append(parts, strings.Join(entry.Remittance, " "))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, entry.AdditionalInfo)
+	}
+
+	return statementTransaction{
+		Date:        date,
+		Amount:      amount,
+		Currency:    currency,
+		Description: strings.Join(strings.Fields(strings.Join(parts, " ")), " "),
+	}, nil
+}
+
+func (d camtDate) parse() (time.Time, bool) {
+	if value := strings.TrimSpace(d.Date); len(value) >= 10 {
+		if date, err := time.Parse("2006-01-02", value[:10]); err == nil {
+			return date, true
+		}
+	}
+	if value := strings.TrimSpace(d.DateTime); len(value) >= 10 {
+		if date, err := time.Parse("2006-01-02", value[:10]); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CSV
+
+type statementColumns map[string]int
+
+func (c statementColumns) index(names ...string) int {
+	for _, name := range names {
+		if index, ok := c[name]; ok {
+			return index
+		}
+	}
+	return -1
+}
+
+var (
+	csvDateColumns        = []string{"date", "booking date", "transaction date", "posted date", "posting date", "value date"}
+	csvAmountColumns      = []string{"amount", "transaction amount", "sum"}
+	csvDebitColumns       = []string{"debit", "debit amount", "withdrawal", "withdrawals", "paid out", "money out"}
+	csvCreditColumns      = []string{"credit", "credit amount", "deposit", "deposits", "paid in", "money in"}
+	csvDirectionColumns   = []string{"debit/credit", "credit/debit", "type", "transaction type"}
+	csvDescriptionColumns = []string{"description", "payee", "merchant", "name", "counterparty", "details", "narrative", "memo", "reference"}
+	csvCurrencyColumns    = []string{"currency", "ccy"}
+)
+
+// statementDateLayouts are tried in order; slash dates are read day first.
+var statementDateLayouts = []string{"2006-01-02", "02.01.2006", "02/01/2006", "01/02/2006", "2006/01/02", "02-01-2006", "2.1.2006", "2/1/2006"}
+
+func parseStatementCSV(data io.Reader) (*parsedStatement, error) {
+	buffered := bufio.NewReader(data)
+	firstLine, err := buffered.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, ErrInvalidStatementFile
+	}
+	if index := bytes.IndexByte(firstLine, '\n'); index >= 0 {
+		firstLine = firstLine[:index]
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+	switch {
+	case bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")):
+		reader.Comma = ';'
+	case bytes.Count(firstLine, []byte("\t")) > bytes.Count(firstLine, []byte(",")):
+		reader.Comma = '\t'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidStatementFile
+	}
+	columns := make(statementColumns, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	dateColumn := columns.index(csvDateColumns...)
+	amountColumn := columns.index(csvAmountColumns...)
+	debitColumn := columns.index(csvDebitColumns...)
+	creditColumn := columns.index(csvCreditColumns...)
+	descriptionColumn := columns.index(csvDescriptionColumns...)
+	if dateColumn < 0 || descriptionColumn < 0 || (amountColumn < 0 && debitColumn < 0) {
+		return nil, ErrInvalidStatementFile
+	}
+	directionColumn := columns.index(csvDirectionColumns...)
+	currencyColumn := columns.index(csvCurrencyColumns...)
+
+	parsed := &parsedStatement{}
+	rows := 0
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, ErrInvalidStatementFile
+			}
+			parsed.Errors = append(parsed.Errors, StatementRowError{Row: parseErr.StartLine, Message: "malformed csv row"})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankStatementRecord(record) {
+			continue
+		}
+
+		rows++
+		if rows > MaxStatementRows {
+			return nil, ErrStatementTooLarge
+		}
+
+		value := func(index int) string {
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		date, ok := parseStatementDate(value(dateColumn))
+		if !ok {
+			parsed.Errors = append(parsed.Errors, StatementRowError{Row: line, Message: "invalid date"})
+			continue
+		}
+
+		var amount float64
+		if amountColumn >= 0 {
+			amount, err = parseStatementAmount(value(amountColumn))
+			if err != nil {
+				parsed.Errors = append(parsed.Errors, StatementRowError{Row: line, Message: "invalid amount"})
+				continue
+			}
+			switch strings.ToLower(value(directionColumn)) {
+			case "d", "db", "dr", "dbit", "debit":
+				amount = -math.Abs(amount)
+			case "c", "cr", "crdt", "credit":
+				amount = math.Abs(amount)
+			}
+		} else {
+			debit, credit := value(debitColumn), value(creditColumn)
+			switch {
+			case debit != "":
+				amount, err = parseStatementAmount(debit)
+				amount = -math.Abs(amount)
+			case credit != "":
+				amount, err = parseStatementAmount(credit)
+				amount = math.Abs(amount)
+			default:
+				err = errors.New("empty amount")
+			}
+			if err != nil {
+				parsed.Errors = append(parsed.Errors, StatementRowError{Row: line, Message: "invalid amount"})
+				continue
+			}
+		}
+
+		parsed.Transactions = append(parsed.Transactions, statementTransaction{
+			Row:         line,
+			Date:        date,
+			Amount:      amount,
+			Currency:    value(currencyColumn),
+			Description: value(descriptionColumn),
+		})
+	}
+
+	return parsed, nil
+}
+
+func parseStatementDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) > 10 {
+		// Drop a trailing time of day.
+		if index := strings.IndexAny(value, " T"); index >= 8 {
+			value = value[:index]
+		}
+	}
+	for _, layout := range statementDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseStatementAmount accepts the decimal styles banks export: "-1234.56",
+// "1.234,56", "1 234,56", "(12.50)" and amounts with a currency sign.
+func parseStatementAmount(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	var cleaned strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9', r == '.', r == ',':
+			cleaned.WriteRune(r)
+		case r == '-' || r == '−':
+			negative = !negative
+		case r == '+', unicode.IsSpace(r), r == '\'', unicode.IsLetter(r), unicode.IsSymbol(r):
+		default:
+			return 0, errors.New("invalid amount")
+		}
+	}
+	number := cleaned.String()
+	if number == "" {
+		return 0, errors.New("invalid amount")
+	}
+
+	lastDot := strings.LastIndexByte(number, '.')
+	lastComma := strings.LastIndexByte(number, ',')
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			number = strings.ReplaceAll(number, ".", "")
+			number = strings.Replace(number, ",", ".", 1)
+		} else {
+			number = strings.ReplaceAll(number, ",", "")
+		}
+	case lastComma >= 0:
+		if strings.Count(number, ",") == 1 && len(number)-lastComma-1 <= 2 {
+			number = strings.Replace(number, ",", ".", 1)
+		} else {
+			number = strings.ReplaceAll(number, ",", "")
+		}
+	case strings.Count(number, ".") > 1:
+		number = strings.ReplaceAll(number, ".", "")
+	}
+
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func isBlankStatementRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type manifest struct {
+	ExportID    string          `json:"export_id"`
+	FamilyID    string          `json:"family_id"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Datasets    []manifestEntry `json:"datasets"`
+}
+
+type manifestEntry struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// buildArchive writes a zip with manifest.json plus a JSON and a CSV file per
+// dataset. JSON files hold an array of objects keyed by column name.
+func buildArchive(export Export, datasets []Dataset, generatedAt time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	summary := manifest{
+		ExportID:    export.ID,
+		FamilyID:    export.FamilyID,
+		GeneratedAt: generatedAt,
+		Datasets:    make([]manifestEntry, 0, len(datasets)),
+	}
+	for _, dataset := range datasets {
+		if err := writeJSONFile(archive, dataset.Name+".json", datasetObjects(dataset)); err != nil {
+			return nil, err
+		}
+		if err := writeCSVFile(archive, dataset); err != nil {
+			return nil, err
+		}
+		summary.Datasets = append(summary.Datasets, manifestEntry{Name: dataset.Name, Rows: len(dataset.Rows)})
+	}
+	if err := writeJSONFile(archive, "manifest.json", summary); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("close export archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func datasetObjects(dataset Dataset) []map[string]interface{} {
+	objects := make([]map[string]interface{}, 0, len(dataset.Rows))
+	for _, row := range dataset.Rows {
+		object := make(map[string]interface{}, len(dataset.Columns))
+		for i, column := range dataset.Columns {
+			if i < len(row) {
+				object[column] = row[i]
+			}
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+func writeJSONFile(archive *zip.Writer, name string, payload interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(payload); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeCSVFile(archive *zip.Writer, dataset Dataset) error {
+	name := dataset.Name + ".csv"
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.Write(dataset.Columns); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	record := make([]string, len(dataset.Columns))
+	for _, row := range dataset.Rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = csvValue(row[i])
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
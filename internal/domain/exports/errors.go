@@ -0,0 +1,12 @@
+package exports
+
+import "errors"
+
+var (
+	ErrExportsDisabled      = errors.New("family exports disabled")
+	ErrExportNotFound       = errors.New("export not found")
+	ErrExportInProgress     = errors.New("export in progress")
+	ErrExportNotReady       = errors.New("export not ready")
+	ErrExportExpired        = errors.New("export expired")
+	ErrInvalidDownloadToken = errors.New("invalid export download token")
+)
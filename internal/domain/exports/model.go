@@ -0,0 +1,64 @@
+package exports
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusReady      = "ready"
+	StatusFailed     = "failed"
+	StatusExpired    = "expired"
+)
+
+// Export is a requested family data archive. The archive itself lives in the
+// file store under StorageKey until ExpiresAt.
+type Export struct {
+	ID          string `gorm:"type:uuid;primaryKey"`
+	FamilyID    string `gorm:"type:uuid"`
+	RequestedBy string `gorm:"type:uuid"`
+	Status      string
+	StorageKey  *string
+	SizeBytes   int64
+	Error       *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
+
+func (Export) TableName() string {
+	return "family_exports"
+}
+
+func (e Export) Active() bool {
+	return e.Status == StatusPending || e.Status == StatusProcessing
+}
+
+// Dataset is one exported table: it becomes <Name>.json and <Name>.csv in the
+// archive. Values are already JSON-friendly (strings, numbers, booleans or
+// nil).
+type Dataset struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Download is a ready archive resolved from a download token.
+type Download struct {
+	ExportID string
+	FamilyID string
+	FileName string
+	// Body streams the archive from the file store; the caller closes it.
+	Body io.ReadCloser
+	Size int64
+}
+
+type ProcessResult struct {
+	ExportID  string
+	FamilyID  string
+	Status    string
+	SizeBytes int64
+}
@@ -0,0 +1,17 @@
+package exports
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateExport(ctx context.Context, export *Export) error
+	UpdateExport(ctx context.Context, export *Export) error
+	GetExport(ctx context.Context, id string) (*Export, error)
+	GetActiveExport(ctx context.Context, familyID string) (*Export, error)
+	ListPendingExports(ctx context.Context, limit int) ([]Export, error)
+	ListFamilyExports(ctx context.Context, familyID string) ([]Export, error)
+	ListExpiredExports(ctx context.Context, before time.Time, limit int) ([]Export, error)
+	LoadDatasets(ctx context.Context, familyID string) ([]Dataset, error)
+}
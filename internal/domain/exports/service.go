@@ -0,0 +1,353 @@
+package exports
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/pkg/blobstore"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	downloadTokenScope = "family-export:"
+	defaultTTL         = 7 * 24 * time.Hour
+	defaultBatchSize   = 10
+)
+
+type FamilyProvider interface {
+	GetFamilyByUser(ctx context.Context, userID string) (*familydomain.Family, error)
+}
+
+type Service struct {
+	repo      Repository
+	families  FamilyProvider
+	files     blobstore.Store
+	secret    []byte
+	ttl       time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+// ServiceOptions configures export generation. An empty Secret disables
+// exports: download links can be neither issued nor verified. TTL is how long
+// a ready archive stays downloadable.
+type ServiceOptions struct {
+	Secret    string
+	TTL       time.Duration
+	BatchSize int
+}
+
+func NewService(repo Repository, families FamilyProvider, files blobstore.Store, secret string) *Service {
+	return NewServiceWithOptions(repo, families, files, ServiceOptions{Secret: secret})
+}
+
+func NewServiceWithOptions(repo Repository, families FamilyProvider, files blobstore.Store, options ServiceOptions) *Service {
+	ttl := options.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Service{
+		repo:      repo,
+		families:  families,
+		files:     files,
+		secret:    []byte(strings.TrimSpace(options.Secret)),
+		ttl:       ttl,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+func (s *Service) Enabled() bool {
+	return len(s.secret) > 0
+}
+
+// Request queues an export of the caller's family. Only one export per family
+// may be pending or processing at a time.
+func (s *Service) Request(ctx context.Context, userID string) (*Export, error) {
+	ctx, span := tracing.Start(ctx, "exports.Request")
+	defer span.End()
+
+	if !s.Enabled() {
+		return nil, ErrExportsDisabled
+	}
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.repo.GetActiveExport(ctx, family.ID)
+	if err != nil && !errors.Is(err, ErrExportNotFound) {
+		return nil, err
+	}
+	if active != nil {
+		return nil, ErrExportInProgress
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	export := &Export{
+		ID:          newID,
+		FamilyID:    family.ID,
+		RequestedBy: userID,
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.CreateExport(ctx, export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// Get returns an export of the caller's family.
+func (s *Service) Get(ctx context.Context, userID, exportID string) (*Export, error) {
+	ctx, span := tracing.Start(ctx, "exports.Get")
+	defer span.End()
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.repo.GetExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.FamilyID != family.ID {
+		return nil, ErrExportNotFound
+	}
+	return export, nil
+}
+
+// IssueDownloadToken signs a download link for a ready export. The token
+// expires together with the archive.
+func (s *Service) IssueDownloadToken(export Export) (string, error) {
+	if !s.Enabled() {
+		return "", ErrExportsDisabled
+	}
+	if export.Status != StatusReady || export.ExpiresAt == nil {
+		return "", ErrExportNotReady
+	}
+
+	payload := export.ID + ":" + strconv.FormatInt(export.ExpiresAt.Unix(), 10)
+	encodedPayload := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	signature := base64.RawURLEncoding.EncodeToString(s.sign(payload))
+	return encodedPayload + "." + signature, nil
+}
+
+// VerifyDownloadToken checks the token signature and expiry and returns the
+// export it was issued for.
+func (s *Service) VerifyDownloadToken(token string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrExportsDisabled
+	}
+
+	encodedPayload, encodedSignature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok || encodedPayload == "" || encodedSignature == "" {
+		return "", ErrInvalidDownloadToken
+	}
+	rawPayload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", ErrInvalidDownloadToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return "", ErrInvalidDownloadToken
+	}
+
+	payload := string(rawPayload)
+	if !hmac.Equal(signature, s.sign(payload)) {
+		return "", ErrInvalidDownloadToken
+	}
+	exportID, rawExpiry, ok := strings.Cut(payload, ":")
+	if !ok || exportID == "" {
+		return "", ErrInvalidDownloadToken
+	}
+	expiry, err := strconv.ParseInt(rawExpiry, 10, 64)
+	if err != nil {
+		return "", ErrInvalidDownloadToken
+	}
+	if !s.now().Before(time.Unix(expiry, 0)) {
+		return "", ErrExportExpired
+	}
+	return exportID, nil
+}
+
+// Open resolves a download token to the archive, opened for streaming so a
+// large family's export is never held in memory.
+func (s *Service) Open(ctx context.Context, token string) (*Download, error) {
+	ctx, span := tracing.Start(ctx, "exports.Open")
+	defer span.End()
+
+	exportID, err := s.VerifyDownloadToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.repo.GetExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case export.Status == StatusExpired:
+		return nil, ErrExportExpired
+	case export.Status != StatusReady || export.StorageKey == nil:
+		return nil, ErrExportNotReady
+	case export.ExpiresAt != nil && !s.now().Before(*export.ExpiresAt):
+		return nil, ErrExportExpired
+	}
+
+	body, err := s.files.Open(ctx, *export.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Download{
+		ExportID: export.ID,
+		FamilyID: export.FamilyID,
+		FileName: fmt.Sprintf("family-export-%s.zip", export.CreatedAt.UTC().Format("2006-01-02")),
+		Body:     body,
+		Size:     export.SizeBytes,
+	}, nil
+}
+
+// ProcessPending builds the archives of pending exports and removes expired
+// ones. It is driven by the background job runner, which keeps it to one
+// instance at a time. A failed export is marked failed; the rest of the batch
+// still runs.
+func (s *Service) ProcessPending(ctx context.Context) ([]ProcessResult, error) {
+	ctx, span := tracing.Start(ctx, "exports.ProcessPending")
+	defer span.End()
+
+	if err := s.removeExpired(ctx); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.ListPendingExports(ctx, s.batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ProcessResult, 0, len(pending))
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		export := pending[i]
+		if err := s.process(ctx, &export); err != nil {
+			message := err.Error()
+			export.Status = StatusFailed
+			export.Error = &message
+			export.UpdatedAt = s.now().UTC()
+			if updateErr := s.repo.UpdateExport(ctx, &export); updateErr != nil {
+				return results, updateErr
+			}
+		}
+		results = append(results, ProcessResult{
+			ExportID:  export.ID,
+			FamilyID:  export.FamilyID,
+			Status:    export.Status,
+			SizeBytes: export.SizeBytes,
+		})
+	}
+	return results, nil
+}
+
+// DeleteFamilyArchives removes the stored archives of a family. Export rows
+// are deleted with the family itself.
+func (s *Service) DeleteFamilyArchives(ctx context.Context, familyID string) error {
+	ctx, span := tracing.Start(ctx, "exports.DeleteFamilyArchives")
+	defer span.End()
+
+	exports, err := s.repo.ListFamilyExports(ctx, familyID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.StorageKey == nil {
+			continue
+		}
+		if err := s.files.Delete(ctx, *export.StorageKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) process(ctx context.Context, export *Export) error {
+	export.Status = StatusProcessing
+	export.UpdatedAt = s.now().UTC()
+	if err := s.repo.UpdateExport(ctx, export); err != nil {
+		return err
+	}
+
+	datasets, err := s.repo.LoadDatasets(ctx, export.FamilyID)
+	if err != nil {
+		return fmt.Errorf("load export data: %w", err)
+	}
+	now := s.now().UTC()
+	data, err := buildArchive(*export, datasets, now)
+	if err != nil {
+		return err
+	}
+	key := path.Join(export.FamilyID, export.ID+".zip")
+	if _, err := s.files.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("store export archive: %w", err)
+	}
+
+	expiresAt := now.Add(s.ttl)
+	export.Status = StatusReady
+	export.StorageKey = &key
+	export.SizeBytes = int64(len(data))
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	export.UpdatedAt = now
+	return s.repo.UpdateExport(ctx, export)
+}
+
+func (s *Service) removeExpired(ctx context.Context) error {
+	expired, err := s.repo.ListExpiredExports(ctx, s.now().UTC(), s.batchSize)
+	if err != nil {
+		return err
+	}
+	for i := range expired {
+		export := expired[i]
+		if export.StorageKey != nil {
+			if err := s.files.Delete(ctx, *export.StorageKey); err != nil {
+				return err
+			}
+		}
+		export.Status = StatusExpired
+		export.StorageKey = nil
+		export.UpdatedAt = s.now().UTC()
+		if err := s.repo.UpdateExport(ctx, &export); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(downloadTokenScope + payload))
+	return mac.Sum(nil)
+}
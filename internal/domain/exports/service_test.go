@@ -0,0 +1,369 @@
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/pkg/blobstore"
+)
+
+const (
+	testFamilyID  = "11111111-1111-1111-1111-111111111111"
+	testOwnerID   = "22222222-2222-2222-2222-222222222222"
+	testOtherUser = "33333333-3333-3333-3333-333333333333"
+	testSecret    = "export-secret"
+)
+
+type fakeFamilyProvider struct{}
+
+func (fakeFamilyProvider) GetFamilyByUser(_ context.Context, userID string) (*familydomain.Family, error) {
+	switch userID {
+	case testOwnerID:
+		return &familydomain.Family{ID: testFamilyID, OwnerID: testOwnerID}, nil
+	case testOtherUser:
+		return &familydomain.Family{ID: "44444444-4444-4444-4444-444444444444", OwnerID: testOtherUser}, nil
+	}
+	return nil, familydomain.ErrFamilyNotFound
+}
+
+type fakeExportsRepo struct {
+	exports  map[string]*Export
+	datasets []Dataset
+	loadErr  error
+}
+
+func newFakeExportsRepo() *fakeExportsRepo {
+	return &fakeExportsRepo{
+		exports: make(map[string]*Export),
+		datasets: []Dataset{
+			{Name: "family", Columns: []string{"id", "name"}, Rows: [][]interface{}{{testFamilyID, "Smiths"}}},
+			{Name: "expenses", Columns: []string{"id", "amount", "title"}, Rows: [][]interface{}{
+				{"e1", "12.50", "Milk, bread"},
+				{"e2", nil, "Rent"},
+			}},
+		},
+	}
+}
+
+func (r *fakeExportsRepo) CreateExport(_ context.Context, export *Export) error {
+	cloned := *export
+	r.exports[export.ID] = &cloned
+	return nil
+}
+
+func (r *fakeExportsRepo) UpdateExport(_ context.Context, export *Export) error {
+	cloned := *export
+	r.exports[export.ID] = &cloned
+	return nil
+}
+
+func (r *fakeExportsRepo) GetExport(_ context.Context, id string) (*Export, error) {
+	export, ok := r.exports[id]
+	if !ok {
+		return nil, ErrExportNotFound
+	}
+	cloned := *export
+	return &cloned, nil
+}
+
+func (r *fakeExportsRepo) GetActiveExport(_ context.Context, familyID string) (*Export, error) {
+	for _, export := range r.exports {
+		if export.FamilyID == familyID && export.Active() {
+			cloned := *export
+			return &cloned, nil
+		}
+	}
+	return nil, ErrExportNotFound
+}
+
+func (r *fakeExportsRepo) ListPendingExports(_ context.Context, _ int) ([]Export, error) {
+	var result []Export
+	for _, export := range r.exports {
+		if export.Active() {
+			result = append(result, *export)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeExportsRepo) ListFamilyExports(_ context.Context, familyID string) ([]Export, error) {
+	var result []Export
+	for _, export := range r.exports {
+		if export.FamilyID == familyID {
+			result = append(result, *export)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeExportsRepo) ListExpiredExports(_ context.Context, before time.Time, _ int) ([]Export, error) {
+	var result []Export
+	for _, export := range r.exports {
+		if export.Status == StatusReady && export.ExpiresAt != nil && export.ExpiresAt.Before(before) {
+			result = append(result, *export)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeExportsRepo) LoadDatasets(_ context.Context, _ string) ([]Dataset, error) {
+	return r.datasets, r.loadErr
+}
+
+type fakeFileStore struct {
+	blobstore.Store
+	files map[string][]byte
+}
+
+func newFakeFileStore() *fakeFileStore {
+	return &fakeFileStore{files: make(map[string][]byte)}
+}
+
+func (s *fakeFileStore) Put(_ context.Context, key string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s.files[key] = data
+	return int64(len(data)), nil
+}
+
+func (s *fakeFileStore) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, blobstore.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *fakeFileStore) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func newTestService(repo *fakeExportsRepo, files *fakeFileStore, now time.Time) *Service {
+	service := NewServiceWithOptions(repo, fakeFamilyProvider{}, files, ServiceOptions{
+		Secret: testSecret,
+		TTL:    24 * time.Hour,
+	})
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestRequestDisabledWithoutSecret(t *testing.T) {
+	service := NewService(newFakeExportsRepo(), fakeFamilyProvider{}, newFakeFileStore(), "")
+
+	if _, err := service.Request(context.Background(), testOwnerID); !errors.Is(err, ErrExportsDisabled) {
+		t.Fatalf("expected ErrExportsDisabled, got %v", err)
+	}
+}
+
+func TestRequestRejectsSecondActiveExport(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := newTestService(newFakeExportsRepo(), newFakeFileStore(), now)
+
+	export, err := service.Request(context.Background(), testOwnerID)
+	if err != nil {
+		t.Fatalf("request export: %v", err)
+	}
+	if export.Status != StatusPending || export.FamilyID != testFamilyID {
+		t.Fatalf("unexpected export: %+v", export)
+	}
+
+	if _, err := service.Request(context.Background(), testOwnerID); !errors.Is(err, ErrExportInProgress) {
+		t.Fatalf("expected ErrExportInProgress, got %v", err)
+	}
+}
+
+func TestProcessPendingBuildsArchive(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeExportsRepo()
+	files := newFakeFileStore()
+	service := newTestService(repo, files, now)
+	ctx := context.Background()
+
+	export, err := service.Request(ctx, testOwnerID)
+	if err != nil {
+		t.Fatalf("request export: %v", err)
+	}
+
+	results, err := service.ProcessPending(ctx)
+	if err != nil {
+		t.Fatalf("process pending: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != StatusReady {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+
+	ready, err := service.Get(ctx, testOwnerID, export.ID)
+	if err != nil {
+		t.Fatalf("get export: %v", err)
+	}
+	if ready.Status != StatusReady || ready.ExpiresAt == nil || !ready.ExpiresAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("unexpected ready export: %+v", ready)
+	}
+
+	token, err := service.IssueDownloadToken(*ready)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	download, err := service.Open(ctx, token)
+	if err != nil {
+		t.Fatalf("open export: %v", err)
+	}
+	if download.FileName != "family-export-2026-03-01.zip" {
+		t.Fatalf("unexpected file name %q", download.FileName)
+	}
+
+	defer download.Body.Close()
+	data, err := io.ReadAll(download.Body)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	if int64(len(data)) != download.Size {
+		t.Fatalf("archive size %d, reported %d", len(data), download.Size)
+	}
+	contents := readArchive(t, data)
+	for _, name := range []string{"manifest.json", "family.json", "family.csv", "expenses.json", "expenses.csv"} {
+		if _, ok := contents[name]; !ok {
+			t.Fatalf("archive is missing %s", name)
+		}
+	}
+	if csv := contents["expenses.csv"]; csv != "id,amount,title\ne1,12.50,\"Milk, bread\"\ne2,,Rent\n" {
+		t.Fatalf("unexpected expenses.csv:\n%s", csv)
+	}
+	if !strings.Contains(contents["expenses.json"], `"title": "Milk, bread"`) {
+		t.Fatalf("unexpected expenses.json:\n%s", contents["expenses.json"])
+	}
+}
+
+func TestProcessPendingMarksFailedExport(t *testing.T) {
+	repo := newFakeExportsRepo()
+	repo.loadErr = errors.New("boom")
+	service := newTestService(repo, newFakeFileStore(), time.Now())
+	ctx := context.Background()
+
+	export, err := service.Request(ctx, testOwnerID)
+	if err != nil {
+		t.Fatalf("request export: %v", err)
+	}
+	if _, err := service.ProcessPending(ctx); err != nil {
+		t.Fatalf("process pending: %v", err)
+	}
+
+	failed := repo.exports[export.ID]
+	if failed.Status != StatusFailed || failed.Error == nil {
+		t.Fatalf("expected failed export, got %+v", failed)
+	}
+	if _, err := service.Request(ctx, testOwnerID); err != nil {
+		t.Fatalf("expected a new export after failure, got %v", err)
+	}
+}
+
+func TestGetHidesOtherFamilyExports(t *testing.T) {
+	service := newTestService(newFakeExportsRepo(), newFakeFileStore(), time.Now())
+	ctx := context.Background()
+
+	export, err := service.Request(ctx, testOwnerID)
+	if err != nil {
+		t.Fatalf("request export: %v", err)
+	}
+	if _, err := service.Get(ctx, testOtherUser, export.ID); !errors.Is(err, ErrExportNotFound) {
+		t.Fatalf("expected ErrExportNotFound, got %v", err)
+	}
+}
+
+func TestDownloadTokenValidation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := newTestService(newFakeExportsRepo(), newFakeFileStore(), now)
+	expiresAt := now.Add(time.Hour)
+	export := Export{ID: "export-1", Status: StatusReady, ExpiresAt: &expiresAt}
+
+	if _, err := service.IssueDownloadToken(Export{ID: "export-1", Status: StatusPending}); !errors.Is(err, ErrExportNotReady) {
+		t.Fatalf("expected ErrExportNotReady, got %v", err)
+	}
+
+	token, err := service.IssueDownloadToken(export)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	exportID, err := service.VerifyDownloadToken(token)
+	if err != nil || exportID != "export-1" {
+		t.Fatalf("verify token: id=%q err=%v", exportID, err)
+	}
+
+	if _, err := service.VerifyDownloadToken(token + "x"); !errors.Is(err, ErrInvalidDownloadToken) {
+		t.Fatalf("expected ErrInvalidDownloadToken for tampered token, got %v", err)
+	}
+
+	other := newTestService(newFakeExportsRepo(), newFakeFileStore(), now)
+	other.secret = []byte("rotated")
+	if _, err := other.VerifyDownloadToken(token); !errors.Is(err, ErrInvalidDownloadToken) {
+		t.Fatalf("expected ErrInvalidDownloadToken after rotation, got %v", err)
+	}
+
+	service.now = func() time.Time { return expiresAt }
+	if _, err := service.VerifyDownloadToken(token); !errors.Is(err, ErrExportExpired) {
+		t.Fatalf("expected ErrExportExpired, got %v", err)
+	}
+}
+
+func TestProcessPendingRemovesExpiredArchives(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeExportsRepo()
+	files := newFakeFileStore()
+	service := newTestService(repo, files, now)
+	ctx := context.Background()
+
+	export, err := service.Request(ctx, testOwnerID)
+	if err != nil {
+		t.Fatalf("request export: %v", err)
+	}
+	if _, err := service.ProcessPending(ctx); err != nil {
+		t.Fatalf("process pending: %v", err)
+	}
+	if len(files.files) != 1 {
+		t.Fatalf("expected stored archive, got %d files", len(files.files))
+	}
+
+	service.now = func() time.Time { return now.Add(25 * time.Hour) }
+	if _, err := service.ProcessPending(ctx); err != nil {
+		t.Fatalf("process pending: %v", err)
+	}
+	if len(files.files) != 0 {
+		t.Fatalf("expected archive to be deleted, got %d files", len(files.files))
+	}
+	if expired := repo.exports[export.ID]; expired.Status != StatusExpired || expired.StorageKey != nil {
+		t.Fatalf("unexpected expired export: %+v", expired)
+	}
+}
+
+func readArchive(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+	contents := make(map[string]string, len(reader.File))
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", file.Name, err)
+		}
+		contents[file.Name] = string(body)
+	}
+	return contents
+}
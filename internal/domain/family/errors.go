@@ -4,14 +4,26 @@ import "errors"
 
 var (
 	ErrFamilyNotFound        = errors.New("family not found")
-	ErrFamilyCodeNotFound    = errors.New("family code not found")
 	ErrAlreadyInFamily       = errors.New("already in family")
 	ErrMemberNotFound        = errors.New("member not found")
 	ErrNotOwner              = errors.New("not owner")
 	ErrCannotRemoveOwner     = errors.New("cannot remove owner")
-	ErrCodeGenerationFailed  = errors.New("family code generation failed")
 	ErrInvalidFamilyName     = errors.New("invalid family name")
 	ErrInvalidCurrency       = errors.New("invalid currency")
 	ErrDefaultCurrencyLocked = errors.New("default currency is locked")
 	ErrNoFieldsToUpdate      = errors.New("no fields to update")
+	ErrInviteNotFound        = errors.New("invite not found")
+	ErrInviteExpired         = errors.New("invite expired")
+	ErrInviteUsedUp          = errors.New("invite has no uses left")
+	ErrInviteRevoked         = errors.New("invite revoked")
+	ErrInvalidInviteTTL      = errors.New("invalid invite expiry")
+	ErrInvalidInviteMaxUses  = errors.New("invalid invite max uses")
+	ErrInvalidRole           = errors.New("invalid member role")
+	ErrCannotChangeOwnerRole = errors.New("cannot change owner role")
+	ErrInvalidSpendingLimit  = errors.New("invalid spending limit")
+	ErrCannotLimitOwner      = errors.New("cannot limit owner spending")
 )
+
+// ErrInvalidAllowedCurrencies rejects allowed currency lists with unknown
+// codes or more than MaxAllowedCurrencies entries.
+var ErrInvalidAllowedCurrencies = errors.New("invalid allowed currencies")
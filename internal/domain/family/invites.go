@@ -0,0 +1,216 @@
+package family
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	DefaultInviteTTL = 72 * time.Hour
+	MinInviteTTL     = time.Hour
+	MaxInviteTTL     = 30 * 24 * time.Hour
+	MaxInviteUses    = 50
+
+	inviteTokenBytes = 32
+)
+
+type CreateInviteInput struct {
+	TTL     time.Duration
+	MaxUses *int
+	Role    string
+}
+
+// CreatedInvite carries the plain token, which is not stored and cannot be
+// read back later.
+type CreatedInvite struct {
+	Invite Invite
+	Token  string
+}
+
+func (s *Service) CreateInvite(ctx context.Context, actorID string, input CreateInviteInput) (*CreatedInvite, error) {
+	ctx, span := tracing.Start(ctx, "family.CreateInvite")
+	defer span.End()
+
+	ttl := input.TTL
+	if ttl == 0 {
+		ttl = DefaultInviteTTL
+	}
+	if ttl < MinInviteTTL || ttl > MaxInviteTTL {
+		return nil, ErrInvalidInviteTTL
+	}
+	if input.MaxUses != nil && (*input.MaxUses < 1 || *input.MaxUses > MaxInviteUses) {
+		return nil, ErrInvalidInviteMaxUses
+	}
+	role, err := normalizeAssignableRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := s.ownerMember(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	token, err := newInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	invite := Invite{
+		ID:        newID,
+		FamilyID:  actor.FamilyID,
+		TokenHash: hashInviteToken(token),
+		Role:      role,
+		MaxUses:   input.MaxUses,
+		ExpiresAt: now.Add(ttl),
+		CreatedBy: actorID,
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateInvite(ctx, &invite); err != nil {
+		return nil, err
+	}
+
+	return &CreatedInvite{Invite: invite, Token: token}, nil
+}
+
+func (s *Service) ListInvites(ctx context.Context, actorID string) ([]Invite, error) {
+	ctx, span := tracing.Start(ctx, "family.ListInvites")
+	defer span.End()
+
+	actor, err := s.ownerMember(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvites(ctx, actor.FamilyID)
+}
+
+func (s *Service) RevokeInvite(ctx context.Context, actorID, inviteID string) error {
+	ctx, span := tracing.Start(ctx, "family.RevokeInvite")
+	defer span.End()
+
+	actor, err := s.ownerMember(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.RevokeInvite(ctx, actor.FamilyID, inviteID, s.now().UTC())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInviteNotFound
+	}
+	return nil
+}
+
+// JoinFamily redeems an invite token. The invite row is locked so concurrent
+// joins cannot exceed its max uses.
+func (s *Service) JoinFamily(ctx context.Context, userID, token string) (*Family, error) {
+	ctx, span := tracing.Start(ctx, "family.JoinFamily")
+	defer span.End()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrInviteNotFound
+	}
+
+	var result Family
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		inFamily, err := tx.IsUserInFamily(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if inFamily {
+			return ErrAlreadyInFamily
+		}
+
+		invite, err := tx.LockInviteByTokenHash(ctx, hashInviteToken(token))
+		if err != nil {
+			return err
+		}
+		switch invite.Status(s.now()) {
+		case InviteStatusRevoked:
+			return ErrInviteRevoked
+		case InviteStatusExpired:
+			return ErrInviteExpired
+		case InviteStatusUsedUp:
+			return ErrInviteUsedUp
+		}
+
+		member := FamilyMember{
+			FamilyID: invite.FamilyID,
+			UserID:   userID,
+			Role:     invite.Role,
+		}
+		if err := tx.AddMember(ctx, &member); err != nil {
+			return err
+		}
+		if err := tx.IncrementInviteUses(ctx, invite.ID); err != nil {
+			return err
+		}
+
+		family, err := tx.GetFamilyByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		result = *family
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Clear()
+	return &result, nil
+}
+
+func (s *Service) ownerMember(ctx context.Context, actorID string) (*FamilyMember, error) {
+	actor, err := s.repo.GetMemberByUser(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role != RoleOwner {
+		return nil, ErrNotOwner
+	}
+	return actor, nil
+}
+
+// normalizeAssignableRole validates roles that can be granted to a member.
+// Ownership only moves when the owner leaves.
+func normalizeAssignableRole(role string) (string, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	switch role {
+	case "":
+		return RoleMember, nil
+	case RoleMember, RoleChild:
+		return role, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+func newInviteToken() (string, error) {
+	var b [inviteTokenBytes]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,35 +1,111 @@
 package family
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 const (
 	RoleOwner  = "owner"
 	RoleMember = "member"
+	// RoleChild members only see their own data and todo items assigned to
+	// them; family finances are hidden.
+	RoleChild = "child"
 )
 
 type Family struct {
 	ID              string    `gorm:"type:uuid;primaryKey"`
 	Name            string    `gorm:"not null"`
-	Code            string    `gorm:"size:6;not null;uniqueIndex"`
 	OwnerID         string    `gorm:"not null;index"`
 	DefaultCurrency string    `gorm:"size:3;not null;default:USD"`
 	CreatedAt       time.Time `gorm:"autoCreateTime"`
 	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+
+	// AllowedCurrencies is a JSON array of the codes expenses may use besides
+	// the default currency; see Currencies.
+	AllowedCurrencies []byte `gorm:"type:jsonb;not null;default:'[]'"`
+}
+
+// Currencies returns the currencies the family accepts on expenses besides
+// DefaultCurrency. An empty list accepts any ISO 4217 currency.
+func (f Family) Currencies() []string {
+	var currencies []string
+	if len(f.AllowedCurrencies) == 0 || json.Unmarshal(f.AllowedCurrencies, &currencies) != nil {
+		return nil
+	}
+	return currencies
 }
 
 type FamilyMember struct {
-	FamilyID string    `gorm:"type:uuid;primaryKey"`
-	UserID   string    `gorm:"primaryKey;uniqueIndex"`
-	Role     string    `gorm:"type:varchar(16);not null"`
-	JoinedAt time.Time `gorm:"autoCreateTime"`
+	FamilyID string `gorm:"type:uuid;primaryKey"`
+	UserID   string `gorm:"primaryKey;uniqueIndex"`
+	Role     string `gorm:"type:varchar(16);not null"`
+	// SpendingLimit caps a single expense in the family default currency.
+	// Larger expenses wait for the owner's approval; nil means no limit.
+	SpendingLimit *float64  `gorm:"type:numeric(12,2)"`
+	JoinedAt      time.Time `gorm:"autoCreateTime"`
 
 	Family Family `gorm:"foreignKey:FamilyID;references:ID;constraint:OnDelete:CASCADE"`
 }
 
+// LeaveResult tells who took over the family when its owner left.
+type LeaveResult struct {
+	FamilyID string
+	// NewOwnerID is empty unless ownership was handed over.
+	NewOwnerID string
+}
+
 type FamilyMemberProfile struct {
-	UserID    string
-	Role      string
-	JoinedAt  time.Time
-	Email     *string
-	AvatarURL *string
+	UserID        string
+	Role          string
+	SpendingLimit *float64
+	JoinedAt      time.Time
+	Email         *string
+	AvatarURL     *string
+}
+
+// MaxAllowedCurrencies caps the allowed currency list of a family.
+const MaxAllowedCurrencies = 20
+
+// MaxSpendingLimit matches the largest amount an expense can hold.
+const MaxSpendingLimit = 9999999999.99
+
+const (
+	InviteStatusActive  = "active"
+	InviteStatusExpired = "expired"
+	InviteStatusUsedUp  = "used_up"
+	InviteStatusRevoked = "revoked"
+)
+
+// Invite is a tokenized link for joining a family. Only the SHA-256 hash of
+// the token is stored; the token itself is returned once on creation.
+type Invite struct {
+	ID        string `gorm:"type:uuid;primaryKey"`
+	FamilyID  string `gorm:"type:uuid;not null;index"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex"`
+	Role      string `gorm:"type:varchar(16);not null"`
+	MaxUses   *int
+	Uses      int       `gorm:"not null;default:0"`
+	ExpiresAt time.Time `gorm:"not null"`
+	CreatedBy string    `gorm:"type:uuid;not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (Invite) TableName() string {
+	return "family_invites"
+}
+
+// Status reports whether the invite can still be used at the given time.
+func (i Invite) Status(now time.Time) string {
+	switch {
+	case i.RevokedAt != nil:
+		return InviteStatusRevoked
+	case !now.Before(i.ExpiresAt):
+		return InviteStatusExpired
+	case i.MaxUses != nil && i.Uses >= *i.MaxUses:
+		return InviteStatusUsedUp
+	default:
+		return InviteStatusActive
+	}
 }
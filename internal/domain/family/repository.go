@@ -1,12 +1,17 @@
 package family
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Transaction(ctx context.Context, fn func(Repository) error) error
 	GetFamilyByUser(ctx context.Context, userID string) (*Family, error)
-	GetFamilyByCode(ctx context.Context, code string) (*Family, error)
 	GetMemberByUser(ctx context.Context, userID string) (*FamilyMember, error)
+	// GetMemberWithFamilyByUser is GetMemberByUser with the member's Family
+	// loaded in the same query.
+	GetMemberWithFamilyByUser(ctx context.Context, userID string) (*FamilyMember, error)
 	GetMember(ctx context.Context, familyID, userID string) (*FamilyMember, error)
 	ListMembers(ctx context.Context, familyID string) ([]FamilyMember, error)
 	ListMembersWithProfiles(ctx context.Context, familyID string) ([]FamilyMemberProfile, error)
@@ -14,12 +19,18 @@ type Repository interface {
 	AddMember(ctx context.Context, member *FamilyMember) error
 	UpdateFamilyName(ctx context.Context, familyID, name string) error
 	UpdateFamilyDefaultCurrency(ctx context.Context, familyID, currency string) error
+	UpdateFamilyAllowedCurrencies(ctx context.Context, familyID string, currencies []byte) error
 	UpdateFamilyOwner(ctx context.Context, familyID, ownerID string) error
 	UpdateMemberRole(ctx context.Context, familyID, userID, role string) error
+	UpdateMemberSpendingLimit(ctx context.Context, familyID, userID string, limit *float64) error
 	DeleteFamily(ctx context.Context, familyID string) error
 	DeleteMember(ctx context.Context, familyID, userID string) error
 	DeleteMembersByFamily(ctx context.Context, familyID string) error
 	CountMembers(ctx context.Context, familyID string) (int64, error)
 	IsUserInFamily(ctx context.Context, userID string) (bool, error)
-	IsCodeTaken(ctx context.Context, code string) (bool, error)
+	CreateInvite(ctx context.Context, invite *Invite) error
+	ListInvites(ctx context.Context, familyID string) ([]Invite, error)
+	LockInviteByTokenHash(ctx context.Context, tokenHash string) (*Invite, error)
+	IncrementInviteUses(ctx context.Context, inviteID string) error
+	RevokeInvite(ctx context.Context, familyID, inviteID string, revokedAt time.Time) (bool, error)
 }
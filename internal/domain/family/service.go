@@ -2,16 +2,19 @@ package family
 
 import (
 	"context"
-	"crypto/rand"
+	"encoding/json"
 	"fmt"
-	"math/big"
+	"math"
+	"sort"
 	"strings"
 	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/money"
+	"family-app-go/pkg/tracing"
 )
 
 const (
-	familyCodeLength      = 6
-	familyCodeAttempts    = 10
 	familyCacheTTL        = 60 * time.Second
 	defaultFamilyCurrency = "USD"
 )
@@ -19,11 +22,15 @@ const (
 type Service struct {
 	repo  Repository
 	cache Cache
+	now   func() time.Time
 }
 
 type UpdateFamilyInput struct {
 	Name            *string
 	DefaultCurrency *string
+	// AllowedCurrencies replaces the allowed currency list; an empty list
+	// accepts any currency again.
+	AllowedCurrencies *[]string
 }
 
 func NewService(repo Repository) *Service {
@@ -37,10 +44,14 @@ func NewServiceWithCache(repo Repository, cache Cache) *Service {
 	return &Service{
 		repo:  repo,
 		cache: cache,
+		now:   time.Now,
 	}
 }
 
 func (s *Service) GetFamilyByUser(ctx context.Context, userID string) (*Family, error) {
+	ctx, span := tracing.Start(ctx, "family.GetFamilyByUser")
+	defer span.End()
+
 	if cached, ok := s.cache.GetByUserID(userID); ok {
 		return cloneFamily(cached), nil
 	}
@@ -54,6 +65,9 @@ func (s *Service) GetFamilyByUser(ctx context.Context, userID string) (*Family,
 }
 
 func (s *Service) CreateFamily(ctx context.Context, userID, name string) (*Family, error) {
+	ctx, span := tracing.Start(ctx, "family.CreateFamily")
+	defer span.End()
+
 	normalizedName, err := normalizeFamilyName(name)
 	if err != nil {
 		return nil, err
@@ -69,22 +83,17 @@ func (s *Service) CreateFamily(ctx context.Context, userID, name string) (*Famil
 			return ErrAlreadyInFamily
 		}
 
-		id, err := newUUID()
-		if err != nil {
-			return err
-		}
-
-		code, err := generateUniqueCode(ctx, tx)
+		newID, err := id.New()
 		if err != nil {
 			return err
 		}
 
 		family := Family{
-			ID:              id,
-			Name:            normalizedName,
-			Code:            code,
-			OwnerID:         userID,
-			DefaultCurrency: defaultFamilyCurrency,
+			ID:                newID,
+			Name:              normalizedName,
+			OwnerID:           userID,
+			DefaultCurrency:   defaultFamilyCurrency,
+			AllowedCurrencies: []byte("[]"),
 		}
 		if err := tx.CreateFamily(ctx, &family); err != nil {
 			return err
@@ -110,53 +119,19 @@ func (s *Service) CreateFamily(ctx context.Context, userID, name string) (*Famil
 	return &result, nil
 }
 
-func (s *Service) JoinFamily(ctx context.Context, userID, code string) (*Family, error) {
-	code = strings.ToUpper(strings.TrimSpace(code))
-	if code == "" {
-		return nil, fmt.Errorf("code is required")
-	}
-
-	var result Family
-	err := s.repo.Transaction(ctx, func(tx Repository) error {
-		inFamily, err := tx.IsUserInFamily(ctx, userID)
-		if err != nil {
-			return err
-		}
-		if inFamily {
-			return ErrAlreadyInFamily
-		}
-
-		family, err := tx.GetFamilyByCode(ctx, code)
-		if err != nil {
-			return err
-		}
-
-		member := FamilyMember{
-			FamilyID: family.ID,
-			UserID:   userID,
-			Role:     RoleMember,
-		}
-		if err := tx.AddMember(ctx, &member); err != nil {
-			return err
-		}
+// LeaveFamily removes the user from their family. An owner leaving hands the
+// family to another member, adults before children.
+func (s *Service) LeaveFamily(ctx context.Context, userID string) (*LeaveResult, error) {
+	ctx, span := tracing.Start(ctx, "family.LeaveFamily")
+	defer span.End()
 
-		result = *family
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	s.cache.Clear()
-	return &result, nil
-}
-
-func (s *Service) LeaveFamily(ctx context.Context, userID string) error {
+	var result LeaveResult
 	err := s.repo.Transaction(ctx, func(tx Repository) error {
 		member, err := tx.GetMemberByUser(ctx, userID)
 		if err != nil {
 			return err
 		}
+		result.FamilyID = member.FamilyID
 
 		if member.Role == RoleOwner {
 			count, err := tx.CountMembers(ctx, member.FamilyID)
@@ -168,11 +143,14 @@ func (s *Service) LeaveFamily(ctx context.Context, userID string) error {
 				if err != nil {
 					return err
 				}
+				// Adults take over before children.
 				var newOwner *FamilyMember
 				for i := range members {
-					if members[i].UserID != userID {
+					if members[i].UserID == userID {
+						continue
+					}
+					if newOwner == nil || (newOwner.Role == RoleChild && members[i].Role != RoleChild) {
 						newOwner = &members[i]
-						break
 					}
 				}
 				if newOwner == nil {
@@ -184,6 +162,7 @@ func (s *Service) LeaveFamily(ctx context.Context, userID string) error {
 				if err := tx.UpdateMemberRole(ctx, member.FamilyID, newOwner.UserID, RoleOwner); err != nil {
 					return err
 				}
+				result.NewOwnerID = newOwner.UserID
 				return tx.DeleteMember(ctx, member.FamilyID, userID)
 			}
 			return tx.DeleteMember(ctx, member.FamilyID, userID)
@@ -192,20 +171,24 @@ func (s *Service) LeaveFamily(ctx context.Context, userID string) error {
 		return tx.DeleteMember(ctx, member.FamilyID, userID)
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	s.cache.Clear()
-	return nil
+	return &result, nil
 }
 
 func (s *Service) UpdateFamily(ctx context.Context, userID string, input UpdateFamilyInput) (*Family, error) {
-	if input.Name == nil && input.DefaultCurrency == nil {
+	ctx, span := tracing.Start(ctx, "family.UpdateFamily")
+	defer span.End()
+
+	if input.Name == nil && input.DefaultCurrency == nil && input.AllowedCurrencies == nil {
 		return nil, ErrNoFieldsToUpdate
 	}
 
 	var (
-		name            *string
-		defaultCurrency *string
+		name              *string
+		defaultCurrency   *string
+		allowedCurrencies []byte
 	)
 	if input.Name != nil {
 		normalizedName, err := normalizeFamilyName(*input.Name)
@@ -221,6 +204,15 @@ func (s *Service) UpdateFamily(ctx context.Context, userID string, input UpdateF
 		}
 		defaultCurrency = &normalizedCurrency
 	}
+	if input.AllowedCurrencies != nil {
+		normalized, err := normalizeAllowedCurrencies(*input.AllowedCurrencies)
+		if err != nil {
+			return nil, err
+		}
+		if allowedCurrencies, err = json.Marshal(normalized); err != nil {
+			return nil, err
+		}
+	}
 
 	var result Family
 	err := s.repo.Transaction(ctx, func(tx Repository) error {
@@ -246,6 +238,13 @@ func (s *Service) UpdateFamily(ctx context.Context, userID string, input UpdateF
 			family.DefaultCurrency = *defaultCurrency
 		}
 
+		if allowedCurrencies != nil {
+			if err := tx.UpdateFamilyAllowedCurrencies(ctx, family.ID, allowedCurrencies); err != nil {
+				return err
+			}
+			family.AllowedCurrencies = allowedCurrencies
+		}
+
 		result = *family
 		return nil
 	})
@@ -257,6 +256,9 @@ func (s *Service) UpdateFamily(ctx context.Context, userID string, input UpdateF
 }
 
 func (s *Service) ListMembers(ctx context.Context, userID string) ([]FamilyMember, error) {
+	ctx, span := tracing.Start(ctx, "family.ListMembers")
+	defer span.End()
+
 	family, err := s.GetFamilyByUser(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -266,6 +268,9 @@ func (s *Service) ListMembers(ctx context.Context, userID string) ([]FamilyMembe
 }
 
 func (s *Service) ListMembersWithProfiles(ctx context.Context, userID string) ([]FamilyMemberProfile, error) {
+	ctx, span := tracing.Start(ctx, "family.ListMembersWithProfiles")
+	defer span.End()
+
 	family, err := s.GetFamilyByUser(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -274,12 +279,44 @@ func (s *Service) ListMembersWithProfiles(ctx context.Context, userID string) ([
 	return s.repo.ListMembersWithProfiles(ctx, family.ID)
 }
 
-func (s *Service) RemoveMember(ctx context.Context, actorID, memberID string) error {
+// GetMembership returns the caller's member record with their family
+// loaded, in one query.
+func (s *Service) GetMembership(ctx context.Context, userID string) (*FamilyMember, error) {
+	ctx, span := tracing.Start(ctx, "family.GetMembership")
+	defer span.End()
+
+	return s.repo.GetMemberWithFamilyByUser(ctx, userID)
+}
+
+// GetMemberRole returns the caller's role in their family.
+func (s *Service) GetMemberRole(ctx context.Context, userID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "family.GetMemberRole")
+	defer span.End()
+
+	member, err := s.repo.GetMemberByUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+func (s *Service) UpdateMemberRole(ctx context.Context, actorID, memberID, role string) (*FamilyMember, error) {
+	ctx, span := tracing.Start(ctx, "family.UpdateMemberRole")
+	defer span.End()
+
 	if strings.TrimSpace(memberID) == "" {
-		return fmt.Errorf("member id is required")
+		return nil, fmt.Errorf("member id is required")
+	}
+	if strings.TrimSpace(role) == "" {
+		return nil, ErrInvalidRole
+	}
+	role, err := normalizeAssignableRole(role)
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.repo.Transaction(ctx, func(tx Repository) error {
+	var result FamilyMember
+	err = s.repo.Transaction(ctx, func(tx Repository) error {
 		actor, err := tx.GetMemberByUser(ctx, actorID)
 		if err != nil {
 			return err
@@ -293,71 +330,137 @@ func (s *Service) RemoveMember(ctx context.Context, actorID, memberID string) er
 			return err
 		}
 		if member.Role == RoleOwner {
-			return ErrCannotRemoveOwner
+			return ErrCannotChangeOwnerRole
 		}
 
-		return tx.DeleteMember(ctx, actor.FamilyID, memberID)
+		if err := tx.UpdateMemberRole(ctx, actor.FamilyID, memberID, role); err != nil {
+			return err
+		}
+		member.Role = role
+		result = *member
+		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s.cache.Clear()
-	return nil
+	return &result, nil
 }
 
-func cloneFamily(family *Family) *Family {
-	if family == nil {
-		return nil
+// GetMemberSpendingLimit returns the caller's spending limit, or nil when
+// their expenses need no approval.
+func (s *Service) GetMemberSpendingLimit(ctx context.Context, userID string) (*float64, error) {
+	ctx, span := tracing.Start(ctx, "family.GetMemberSpendingLimit")
+	defer span.End()
+
+	member, err := s.repo.GetMemberByUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
-	cloned := *family
-	return &cloned
+	return member.SpendingLimit, nil
 }
 
-func generateUniqueCode(ctx context.Context, repo Repository) (string, error) {
-	for i := 0; i < familyCodeAttempts; i++ {
-		code, err := generateCode(familyCodeLength)
+// SetMemberSpendingLimit sets or, with a nil limit, clears a member's
+// spending limit. Only the owner may do it, and the owner has no limit.
+func (s *Service) SetMemberSpendingLimit(ctx context.Context, actorID, memberID string, limit *float64) (*FamilyMember, error) {
+	ctx, span := tracing.Start(ctx, "family.SetMemberSpendingLimit")
+	defer span.End()
+
+	if strings.TrimSpace(memberID) == "" {
+		return nil, fmt.Errorf("member id is required")
+	}
+	if limit != nil {
+		if *limit < 0 || *limit > MaxSpendingLimit {
+			return nil, ErrInvalidSpendingLimit
+		}
+		rounded := math.Round(*limit*100) / 100
+		limit = &rounded
+	}
+
+	var result FamilyMember
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		actor, err := tx.GetMemberByUser(ctx, actorID)
 		if err != nil {
-			return "", err
+			return err
 		}
-		taken, err := repo.IsCodeTaken(ctx, code)
+		if actor.Role != RoleOwner {
+			return ErrNotOwner
+		}
+
+		member, err := tx.GetMember(ctx, actor.FamilyID, memberID)
 		if err != nil {
-			return "", err
+			return err
+		}
+		if member.Role == RoleOwner {
+			return ErrCannotLimitOwner
 		}
-		if !taken {
-			return code, nil
+
+		if err := tx.UpdateMemberSpendingLimit(ctx, actor.FamilyID, memberID, limit); err != nil {
+			return err
 		}
+		member.SpendingLimit = limit
+		result = *member
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return "", ErrCodeGenerationFailed
+	return &result, nil
 }
 
-func generateCode(length int) (string, error) {
-	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
-	max := big.NewInt(int64(len(alphabet)))
+func (s *Service) RemoveMember(ctx context.Context, actorID, memberID string) error {
+	ctx, span := tracing.Start(ctx, "family.RemoveMember")
+	defer span.End()
+
+	if strings.TrimSpace(memberID) == "" {
+		return fmt.Errorf("member id is required")
+	}
 
-	var builder strings.Builder
-	builder.Grow(length)
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		actor, err := tx.GetMemberByUser(ctx, actorID)
+		if err != nil {
+			return err
+		}
+		if actor.Role != RoleOwner {
+			return ErrNotOwner
+		}
 
-	for i := 0; i < length; i++ {
-		n, err := rand.Int(rand.Reader, max)
+		member, err := tx.GetMember(ctx, actor.FamilyID, memberID)
 		if err != nil {
-			return "", err
+			return err
+		}
+		if member.Role == RoleOwner {
+			return ErrCannotRemoveOwner
 		}
-		builder.WriteByte(alphabet[n.Int64()])
-	}
 
-	return builder.String(), nil
+		return tx.DeleteMember(ctx, actor.FamilyID, memberID)
+	})
+	if err != nil {
+		return err
+	}
+	s.cache.Clear()
+	return nil
 }
 
-func newUUID() (string, error) {
-	var b [16]byte
-	if _, err := rand.Read(b[:]); err != nil {
-		return "", err
-	}
+// DeleteFamily hard-deletes a family. Members, invites and all family data go
+// with it through ON DELETE CASCADE. It is used by scheduled erasure once the
+// grace period ends, so there is no ownership check here.
+func (s *Service) DeleteFamily(ctx context.Context, familyID string) error {
+	ctx, span := tracing.Start(ctx, "family.DeleteFamily")
+	defer span.End()
 
-	b[6] = (b[6] & 0x0f) | 0x40
-	b[8] = (b[8] & 0x3f) | 0x80
+	if err := s.repo.DeleteFamily(ctx, familyID); err != nil {
+		return err
+	}
+	s.cache.Clear()
+	return nil
+}
 
-	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+func cloneFamily(family *Family) *Family {
+	if family == nil {
+		return nil
+	}
+	cloned := *family
+	return &cloned
 }
 
 func normalizeFamilyName(name string) (string, error) {
@@ -370,13 +473,30 @@ func normalizeFamilyName(name string) (string, error) {
 
 func normalizeCurrency(currency string) (string, error) {
 	currency = strings.ToUpper(strings.TrimSpace(currency))
-	if len(currency) != 3 {
+	if !money.IsCurrency(currency) {
 		return "", ErrInvalidCurrency
 	}
-	for i := 0; i < len(currency); i++ {
-		if currency[i] < 'A' || currency[i] > 'Z' {
-			return "", ErrInvalidCurrency
+	return currency, nil
+}
+
+// normalizeAllowedCurrencies uppercases, dedupes and sorts the codes.
+func normalizeAllowedCurrencies(currencies []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(currencies))
+	normalized := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		code, err := normalizeCurrency(currency)
+		if err != nil {
+			return nil, ErrInvalidAllowedCurrencies
+		}
+		if _, ok := seen[code]; ok {
+			continue
 		}
+		seen[code] = struct{}{}
+		normalized = append(normalized, code)
 	}
-	return currency, nil
+	if len(normalized) > MaxAllowedCurrencies {
+		return nil, ErrInvalidAllowedCurrencies
+	}
+	sort.Strings(normalized)
+	return normalized, nil
 }
@@ -3,6 +3,7 @@ package family
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -10,7 +11,7 @@ import (
 type fakeFamilyRepo struct {
 	families             map[string]*Family
 	members              map[string]*FamilyMember
-	codes                map[string]string
+	invites              map[string]*Invite
 	getFamilyByUserCalls int
 }
 
@@ -52,7 +53,7 @@ func newFakeFamilyRepo() *fakeFamilyRepo {
 	return &fakeFamilyRepo{
 		families: make(map[string]*Family),
 		members:  make(map[string]*FamilyMember),
-		codes:    make(map[string]string),
+		invites:  make(map[string]*Invite),
 	}
 }
 
@@ -73,24 +74,26 @@ func (r *fakeFamilyRepo) GetFamilyByUser(ctx context.Context, userID string) (*F
 	return family, nil
 }
 
-func (r *fakeFamilyRepo) GetFamilyByCode(ctx context.Context, code string) (*Family, error) {
-	id, ok := r.codes[code]
-	if !ok {
-		return nil, ErrFamilyCodeNotFound
-	}
-	family, ok := r.families[id]
+func (r *fakeFamilyRepo) GetMemberByUser(ctx context.Context, userID string) (*FamilyMember, error) {
+	member, ok := r.members[userID]
 	if !ok {
-		return nil, ErrFamilyCodeNotFound
+		return nil, ErrFamilyNotFound
 	}
-	return family, nil
+	return member, nil
 }
 
-func (r *fakeFamilyRepo) GetMemberByUser(ctx context.Context, userID string) (*FamilyMember, error) {
+func (r *fakeFamilyRepo) GetMemberWithFamilyByUser(ctx context.Context, userID string) (*FamilyMember, error) {
 	member, ok := r.members[userID]
 	if !ok {
 		return nil, ErrFamilyNotFound
 	}
-	return member, nil
+	family, ok := r.families[member.FamilyID]
+	if !ok {
+		return nil, ErrFamilyNotFound
+	}
+	withFamily := *member
+	withFamily.Family = *family
+	return &withFamily, nil
 }
 
 func (r *fakeFamilyRepo) GetMember(ctx context.Context, familyID, userID string) (*FamilyMember, error) {
@@ -126,7 +129,6 @@ func (r *fakeFamilyRepo) ListMembersWithProfiles(ctx context.Context, familyID s
 
 func (r *fakeFamilyRepo) CreateFamily(ctx context.Context, family *Family) error {
 	r.families[family.ID] = family
-	r.codes[family.Code] = family.ID
 	return nil
 }
 
@@ -156,6 +158,15 @@ func (r *fakeFamilyRepo) UpdateFamilyDefaultCurrency(ctx context.Context, family
 	return nil
 }
 
+func (r *fakeFamilyRepo) UpdateFamilyAllowedCurrencies(ctx context.Context, familyID string, currencies []byte) error {
+	family, ok := r.families[familyID]
+	if !ok {
+		return ErrFamilyNotFound
+	}
+	family.AllowedCurrencies = currencies
+	return nil
+}
+
 func (r *fakeFamilyRepo) UpdateFamilyOwner(ctx context.Context, familyID, ownerID string) error {
 	family, ok := r.families[familyID]
 	if !ok {
@@ -174,11 +185,16 @@ func (r *fakeFamilyRepo) UpdateMemberRole(ctx context.Context, familyID, userID,
 	return nil
 }
 
-func (r *fakeFamilyRepo) DeleteFamily(ctx context.Context, familyID string) error {
-	family, ok := r.families[familyID]
-	if ok {
-		delete(r.codes, family.Code)
+func (r *fakeFamilyRepo) UpdateMemberSpendingLimit(ctx context.Context, familyID, userID string, limit *float64) error {
+	member, ok := r.members[userID]
+	if !ok || member.FamilyID != familyID {
+		return ErrFamilyNotFound
 	}
+	member.SpendingLimit = limit
+	return nil
+}
+
+func (r *fakeFamilyRepo) DeleteFamily(ctx context.Context, familyID string) error {
 	delete(r.families, familyID)
 	return nil
 }
@@ -215,9 +231,46 @@ func (r *fakeFamilyRepo) IsUserInFamily(ctx context.Context, userID string) (boo
 	return ok, nil
 }
 
-func (r *fakeFamilyRepo) IsCodeTaken(ctx context.Context, code string) (bool, error) {
-	_, ok := r.codes[code]
-	return ok, nil
+func (r *fakeFamilyRepo) CreateInvite(ctx context.Context, invite *Invite) error {
+	cloned := *invite
+	r.invites[invite.ID] = &cloned
+	return nil
+}
+
+func (r *fakeFamilyRepo) ListInvites(ctx context.Context, familyID string) ([]Invite, error) {
+	result := make([]Invite, 0)
+	for _, invite := range r.invites {
+		if invite.FamilyID == familyID {
+			result = append(result, *invite)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeFamilyRepo) LockInviteByTokenHash(ctx context.Context, tokenHash string) (*Invite, error) {
+	for _, invite := range r.invites {
+		if invite.TokenHash == tokenHash {
+			cloned := *invite
+			return &cloned, nil
+		}
+	}
+	return nil, ErrInviteNotFound
+}
+
+func (r *fakeFamilyRepo) IncrementInviteUses(ctx context.Context, inviteID string) error {
+	if invite, ok := r.invites[inviteID]; ok {
+		invite.Uses++
+	}
+	return nil
+}
+
+func (r *fakeFamilyRepo) RevokeInvite(ctx context.Context, familyID, inviteID string, revokedAt time.Time) (bool, error) {
+	invite, ok := r.invites[inviteID]
+	if !ok || invite.FamilyID != familyID || invite.RevokedAt != nil {
+		return false, nil
+	}
+	invite.RevokedAt = &revokedAt
+	return true, nil
 }
 
 func TestCreateFamilySuccess(t *testing.T) {
@@ -234,9 +287,6 @@ func TestCreateFamilySuccess(t *testing.T) {
 	if result.OwnerID != "user-1" {
 		t.Fatalf("expected owner user-1, got %q", result.OwnerID)
 	}
-	if len(result.Code) != 6 {
-		t.Fatalf("expected code length 6, got %q", result.Code)
-	}
 	if result.DefaultCurrency != "USD" {
 		t.Fatalf("expected default currency USD, got %q", result.DefaultCurrency)
 	}
@@ -254,8 +304,7 @@ func TestCreateFamilySuccess(t *testing.T) {
 
 func TestCreateFamilyAlreadyInFamily(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "AAAAAA", OwnerID: "owner"}
-	repo.codes["AAAAAA"] = "fam-1"
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
 
 	svc := NewService(repo)
@@ -265,13 +314,25 @@ func TestCreateFamilyAlreadyInFamily(t *testing.T) {
 	}
 }
 
-func TestJoinFamilySuccess(t *testing.T) {
+func TestJoinFamilyWithInvite(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "owner"}
-	repo.codes["ZXCVBN"] = "fam-1"
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
+	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
 
 	svc := NewService(repo)
-	result, err := svc.JoinFamily(context.Background(), "user-1", "zxcvbn")
+	maxUses := 1
+	created, err := svc.CreateInvite(context.Background(), "owner", CreateInviteInput{MaxUses: &maxUses})
+	if err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+	if created.Token == "" || created.Invite.TokenHash == created.Token {
+		t.Fatalf("expected plain token to be returned and only its hash stored")
+	}
+	if created.Invite.Role != RoleMember {
+		t.Fatalf("expected default member role, got %q", created.Invite.Role)
+	}
+
+	result, err := svc.JoinFamily(context.Background(), "user-1", created.Token)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -282,27 +343,82 @@ func TestJoinFamilySuccess(t *testing.T) {
 	if member == nil || member.Role != RoleMember {
 		t.Fatalf("expected member role, got %+v", member)
 	}
+
+	_, err = svc.JoinFamily(context.Background(), "user-2", created.Token)
+	if !errors.Is(err, ErrInviteUsedUp) {
+		t.Fatalf("expected ErrInviteUsedUp, got %v", err)
+	}
 }
 
-func TestJoinFamilyCodeNotFound(t *testing.T) {
+func TestJoinFamilyRejectsExpiredAndRevokedInvites(t *testing.T) {
 	repo := newFakeFamilyRepo()
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
+	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
 	svc := NewService(repo)
-	_, err := svc.JoinFamily(context.Background(), "user-1", "missing")
-	if !errors.Is(err, ErrFamilyCodeNotFound) {
-		t.Fatalf("expected ErrFamilyCodeNotFound, got %v", err)
+	svc.now = func() time.Time { return now }
+
+	expiring, err := svc.CreateInvite(context.Background(), "owner", CreateInviteInput{TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+	revoked, err := svc.CreateInvite(context.Background(), "owner", CreateInviteInput{})
+	if err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+	if err := svc.RevokeInvite(context.Background(), "owner", revoked.Invite.ID); err != nil {
+		t.Fatalf("revoke invite: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := svc.JoinFamily(context.Background(), "user-1", expiring.Token); !errors.Is(err, ErrInviteExpired) {
+		t.Fatalf("expected ErrInviteExpired, got %v", err)
+	}
+	if _, err := svc.JoinFamily(context.Background(), "user-1", revoked.Token); !errors.Is(err, ErrInviteRevoked) {
+		t.Fatalf("expected ErrInviteRevoked, got %v", err)
+	}
+	if _, err := svc.JoinFamily(context.Background(), "user-1", "missing"); !errors.Is(err, ErrInviteNotFound) {
+		t.Fatalf("expected ErrInviteNotFound, got %v", err)
+	}
+}
+
+func TestCreateInviteRequiresOwnerAndValidLimits(t *testing.T) {
+	repo := newFakeFamilyRepo()
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
+	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
+	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
+
+	svc := NewService(repo)
+	if _, err := svc.CreateInvite(context.Background(), "user-1", CreateInviteInput{}); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("expected ErrNotOwner, got %v", err)
+	}
+	if _, err := svc.CreateInvite(context.Background(), "owner", CreateInviteInput{TTL: MaxInviteTTL + time.Hour}); !errors.Is(err, ErrInvalidInviteTTL) {
+		t.Fatalf("expected ErrInvalidInviteTTL, got %v", err)
+	}
+	zero := 0
+	if _, err := svc.CreateInvite(context.Background(), "owner", CreateInviteInput{MaxUses: &zero}); !errors.Is(err, ErrInvalidInviteMaxUses) {
+		t.Fatalf("expected ErrInvalidInviteMaxUses, got %v", err)
+	}
+	if _, err := svc.CreateInvite(context.Background(), "owner", CreateInviteInput{Role: RoleOwner}); !errors.Is(err, ErrInvalidRole) {
+		t.Fatalf("expected ErrInvalidRole, got %v", err)
 	}
 }
 
 func TestLeaveFamilyOwnerTransfers(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "owner"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
 	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
 	repo.members["user-2"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-2", Role: RoleMember}
 
 	svc := NewService(repo)
-	if err := svc.LeaveFamily(context.Background(), "owner"); err != nil {
+	result, err := svc.LeaveFamily(context.Background(), "owner")
+	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+	if result.FamilyID != "fam-1" || result.NewOwnerID != "user-2" {
+		t.Fatalf("unexpected leave result: %+v", result)
+	}
 	if repo.families["fam-1"] == nil {
 		t.Fatalf("family should not be deleted")
 	}
@@ -320,11 +436,11 @@ func TestLeaveFamilyOwnerTransfers(t *testing.T) {
 
 func TestLeaveFamilyOwnerSolo(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "owner"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
 	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
 
 	svc := NewService(repo)
-	if err := svc.LeaveFamily(context.Background(), "owner"); err != nil {
+	if _, err := svc.LeaveFamily(context.Background(), "owner"); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 	if _, ok := repo.families["fam-1"]; !ok {
@@ -337,7 +453,7 @@ func TestLeaveFamilyOwnerSolo(t *testing.T) {
 
 func TestUpdateFamily(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "user-1", DefaultCurrency: "USD"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "user-1", DefaultCurrency: "USD"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleOwner}
 
 	svc := NewService(repo)
@@ -352,7 +468,7 @@ func TestUpdateFamily(t *testing.T) {
 
 func TestUpdateFamilyCurrencyLocked(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "user-1", DefaultCurrency: "USD"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "user-1", DefaultCurrency: "USD"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleOwner}
 
 	svc := NewService(repo)
@@ -364,7 +480,7 @@ func TestUpdateFamilyCurrencyLocked(t *testing.T) {
 
 func TestUpdateFamilySameCurrencyAllowed(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "user-1", DefaultCurrency: "USD"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "user-1", DefaultCurrency: "USD"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleOwner}
 
 	svc := NewService(repo)
@@ -379,7 +495,7 @@ func TestUpdateFamilySameCurrencyAllowed(t *testing.T) {
 
 func TestUpdateFamilyRejectsInvalidCurrency(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "user-1", DefaultCurrency: "USD"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "user-1", DefaultCurrency: "USD"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleOwner}
 
 	svc := NewService(repo)
@@ -389,9 +505,32 @@ func TestUpdateFamilyRejectsInvalidCurrency(t *testing.T) {
 	}
 }
 
+func TestUpdateFamilyAllowedCurrencies(t *testing.T) {
+	repo := newFakeFamilyRepo()
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "user-1", DefaultCurrency: "USD"}
+	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleOwner}
+
+	svc := NewService(repo)
+	result, err := svc.UpdateFamily(context.Background(), "user-1", UpdateFamilyInput{AllowedCurrencies: &[]string{"eur", "BYN", "EUR"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := strings.Join(result.Currencies(), ","); got != "BYN,EUR" {
+		t.Fatalf("expected BYN,EUR, got %q", got)
+	}
+
+	_, err = svc.UpdateFamily(context.Background(), "user-1", UpdateFamilyInput{AllowedCurrencies: &[]string{"EUR", "XYZ"}})
+	if !errors.Is(err, ErrInvalidAllowedCurrencies) {
+		t.Fatalf("expected ErrInvalidAllowedCurrencies, got %v", err)
+	}
+	if got := strings.Join(repo.families["fam-1"].Currencies(), ","); got != "BYN,EUR" {
+		t.Fatalf("expected the list unchanged, got %q", got)
+	}
+}
+
 func TestListMembers(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "user-1"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "user-1"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleOwner}
 	repo.members["user-2"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-2", Role: RoleMember}
 
@@ -407,7 +546,7 @@ func TestListMembers(t *testing.T) {
 
 func TestRemoveMemberNotOwner(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "owner"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
 	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
 	repo.members["user-2"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-2", Role: RoleMember}
@@ -421,7 +560,7 @@ func TestRemoveMemberNotOwner(t *testing.T) {
 
 func TestRemoveMemberCannotRemoveOwner(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "owner"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
 	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
 
@@ -434,7 +573,7 @@ func TestRemoveMemberCannotRemoveOwner(t *testing.T) {
 
 func TestRemoveMemberSuccess(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "owner"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
 	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
 
@@ -449,7 +588,7 @@ func TestRemoveMemberSuccess(t *testing.T) {
 
 func TestGetFamilyByUserUsesCache(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", Code: "ZXCVBN", OwnerID: "owner"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
 
 	svc := NewServiceWithCache(repo, newFakeFamilyCache())
@@ -473,7 +612,7 @@ func TestGetFamilyByUserUsesCache(t *testing.T) {
 
 func TestUpdateFamilyInvalidatesCache(t *testing.T) {
 	repo := newFakeFamilyRepo()
-	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Old", Code: "ZXCVBN", OwnerID: "user-1", DefaultCurrency: "USD"}
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Old", OwnerID: "user-1", DefaultCurrency: "USD"}
 	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleOwner}
 
 	svc := NewServiceWithCache(repo, newFakeFamilyCache())
@@ -504,3 +643,108 @@ func TestUpdateFamilyInvalidatesCache(t *testing.T) {
 func stringPtr(value string) *string {
 	return &value
 }
+
+func TestUpdateMemberRoleMakesChild(t *testing.T) {
+	repo := newFakeFamilyRepo()
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
+	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
+	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
+
+	svc := NewService(repo)
+	member, err := svc.UpdateMemberRole(context.Background(), "owner", "user-1", "child")
+	if err != nil {
+		t.Fatalf("update member role: %v", err)
+	}
+	if member.Role != RoleChild || repo.members["user-1"].Role != RoleChild {
+		t.Fatalf("expected child role, got %+v", member)
+	}
+
+	role, err := svc.GetMemberRole(context.Background(), "user-1")
+	if err != nil || role != RoleChild {
+		t.Fatalf("expected child role lookup, got %q, %v", role, err)
+	}
+
+	if _, err := svc.UpdateMemberRole(context.Background(), "user-1", "owner", RoleMember); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("expected ErrNotOwner, got %v", err)
+	}
+	if _, err := svc.UpdateMemberRole(context.Background(), "owner", "owner", RoleChild); !errors.Is(err, ErrCannotChangeOwnerRole) {
+		t.Fatalf("expected ErrCannotChangeOwnerRole, got %v", err)
+	}
+	if _, err := svc.UpdateMemberRole(context.Background(), "owner", "user-1", RoleOwner); !errors.Is(err, ErrInvalidRole) {
+		t.Fatalf("expected ErrInvalidRole, got %v", err)
+	}
+}
+
+func TestSetMemberSpendingLimit(t *testing.T) {
+	repo := newFakeFamilyRepo()
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
+	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
+	repo.members["user-1"] = &FamilyMember{FamilyID: "fam-1", UserID: "user-1", Role: RoleMember}
+
+	svc := NewService(repo)
+	limit := 25.555
+	member, err := svc.SetMemberSpendingLimit(context.Background(), "owner", "user-1", &limit)
+	if err != nil {
+		t.Fatalf("set spending limit: %v", err)
+	}
+	if member.SpendingLimit == nil || *member.SpendingLimit != 25.56 {
+		t.Fatalf("expected rounded limit, got %+v", member.SpendingLimit)
+	}
+
+	stored, err := svc.GetMemberSpendingLimit(context.Background(), "user-1")
+	if err != nil || stored == nil || *stored != 25.56 {
+		t.Fatalf("expected stored limit, got %v, %v", stored, err)
+	}
+
+	if _, err := svc.SetMemberSpendingLimit(context.Background(), "user-1", "user-1", nil); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("expected ErrNotOwner, got %v", err)
+	}
+	if _, err := svc.SetMemberSpendingLimit(context.Background(), "owner", "owner", &limit); !errors.Is(err, ErrCannotLimitOwner) {
+		t.Fatalf("expected ErrCannotLimitOwner, got %v", err)
+	}
+	negative := -1.0
+	if _, err := svc.SetMemberSpendingLimit(context.Background(), "owner", "user-1", &negative); !errors.Is(err, ErrInvalidSpendingLimit) {
+		t.Fatalf("expected ErrInvalidSpendingLimit, got %v", err)
+	}
+
+	if _, err := svc.SetMemberSpendingLimit(context.Background(), "owner", "user-1", nil); err != nil {
+		t.Fatalf("clear spending limit: %v", err)
+	}
+	if repo.members["user-1"].SpendingLimit != nil {
+		t.Fatalf("expected limit to be cleared")
+	}
+}
+
+func TestLeaveFamilyOwnerPrefersAdultSuccessor(t *testing.T) {
+	repo := newFakeFamilyRepo()
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
+	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
+	repo.members["kid"] = &FamilyMember{FamilyID: "fam-1", UserID: "kid", Role: RoleChild}
+	repo.members["adult"] = &FamilyMember{FamilyID: "fam-1", UserID: "adult", Role: RoleMember}
+
+	svc := NewService(repo)
+	if _, err := svc.LeaveFamily(context.Background(), "owner"); err != nil {
+		t.Fatalf("leave family: %v", err)
+	}
+	if repo.families["fam-1"].OwnerID != "adult" || repo.members["kid"].Role != RoleChild {
+		t.Fatalf("expected adult to take ownership, got owner %q", repo.families["fam-1"].OwnerID)
+	}
+}
+
+func TestJoinFamilyWithChildInvite(t *testing.T) {
+	repo := newFakeFamilyRepo()
+	repo.families["fam-1"] = &Family{ID: "fam-1", Name: "Fam", OwnerID: "owner"}
+	repo.members["owner"] = &FamilyMember{FamilyID: "fam-1", UserID: "owner", Role: RoleOwner}
+
+	svc := NewService(repo)
+	created, err := svc.CreateInvite(context.Background(), "owner", CreateInviteInput{Role: "child"})
+	if err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+	if _, err := svc.JoinFamily(context.Background(), "kid", created.Token); err != nil {
+		t.Fatalf("join family: %v", err)
+	}
+	if repo.members["kid"].Role != RoleChild {
+		t.Fatalf("expected child role, got %q", repo.members["kid"].Role)
+	}
+}
@@ -0,0 +1,9 @@
+package favorites
+
+import "errors"
+
+var (
+	ErrInvalidEntityType = errors.New("invalid favorite entity type")
+	ErrInvalidEntityID   = errors.New("invalid favorite entity id")
+	ErrTooManyFavorites  = errors.New("too many favorites")
+)
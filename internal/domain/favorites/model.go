@@ -0,0 +1,30 @@
+package favorites
+
+import "time"
+
+// EntityType names the kind of record a user can pin.
+type EntityType string
+
+const (
+	EntityTodoList EntityType = "todo_list"
+	EntityCategory EntityType = "category"
+	// EntityExercise favorites are keyed by exercise name; exercises have no
+	// records of their own.
+	EntityExercise EntityType = "exercise"
+
+	MaxFavoritesPerType = 100
+	MaxEntityIDLength   = 200
+)
+
+// Favorite pins one record for one user. Favorites are personal: family
+// members pin lists and categories independently.
+type Favorite struct {
+	UserID     string     `gorm:"type:uuid;primaryKey"`
+	EntityType EntityType `gorm:"type:varchar(32);primaryKey"`
+	EntityID   string     `gorm:"primaryKey"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+func (Favorite) TableName() string {
+	return "user_favorites"
+}
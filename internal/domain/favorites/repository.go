@@ -0,0 +1,14 @@
+package favorites
+
+import "context"
+
+type Repository interface {
+	// AddFavorite stores the favorite; adding it twice is not an error.
+	AddFavorite(ctx context.Context, favorite *Favorite) error
+	RemoveFavorite(ctx context.Context, userID string, entityType EntityType, entityID string) error
+	// ListFavorites returns the user's favorites of a type, newest first.
+	ListFavorites(ctx context.Context, userID string, entityType EntityType) ([]Favorite, error)
+	CountFavorites(ctx context.Context, userID string, entityType EntityType) (int64, error)
+	// DeleteByEntity removes every user's favorite of a deleted record.
+	DeleteByEntity(ctx context.Context, entityType EntityType, entityID string) error
+}
@@ -0,0 +1,111 @@
+package favorites
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// Service keeps per-user favorites of records owned by other domains. The
+// records are not looked up: a favorite of a record the user cannot see
+// never matches anything they list.
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Add pins a record for the user.
+func (s *Service) Add(ctx context.Context, userID string, entityType EntityType, entityID string) error {
+	ctx, span := tracing.Start(ctx, "favorites.Add")
+	defer span.End()
+
+	entityID, err := normalizeEntityID(entityType, entityID)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountFavorites(ctx, userID, entityType)
+	if err != nil {
+		return err
+	}
+	if count >= MaxFavoritesPerType {
+		return ErrTooManyFavorites
+	}
+	return s.repo.AddFavorite(ctx, &Favorite{UserID: userID, EntityType: entityType, EntityID: entityID})
+}
+
+// Remove unpins a record; removing a record that is not pinned is not an
+// error.
+func (s *Service) Remove(ctx context.Context, userID string, entityType EntityType, entityID string) error {
+	ctx, span := tracing.Start(ctx, "favorites.Remove")
+	defer span.End()
+
+	entityID, err := normalizeEntityID(entityType, entityID)
+	if err != nil {
+		return err
+	}
+	return s.repo.RemoveFavorite(ctx, userID, entityType, entityID)
+}
+
+func (s *Service) List(ctx context.Context, userID string, entityType EntityType) ([]Favorite, error) {
+	ctx, span := tracing.Start(ctx, "favorites.List")
+	defer span.End()
+
+	if !validEntityType(entityType) {
+		return nil, ErrInvalidEntityType
+	}
+	return s.repo.ListFavorites(ctx, userID, entityType)
+}
+
+// IDs returns the IDs the user pinned of a type as a set.
+func (s *Service) IDs(ctx context.Context, userID string, entityType EntityType) (map[string]bool, error) {
+	favorites, err := s.List(ctx, userID, entityType)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(favorites))
+	for _, favorite := range favorites {
+		ids[favorite.EntityID] = true
+	}
+	return ids, nil
+}
+
+// Clear removes all favorites of a deleted record.
+func (s *Service) Clear(ctx context.Context, entityType EntityType, entityID string) error {
+	ctx, span := tracing.Start(ctx, "favorites.Clear")
+	defer span.End()
+
+	return s.repo.DeleteByEntity(ctx, entityType, entityID)
+}
+
+// SortFirst moves the items whose ID is in ids to the front, keeping the
+// order within both groups.
+func SortFirst[T any](items []T, ids map[string]bool, id func(T) string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return ids[id(items[i])] && !ids[id(items[j])]
+	})
+}
+
+func normalizeEntityID(entityType EntityType, entityID string) (string, error) {
+	if !validEntityType(entityType) {
+		return "", ErrInvalidEntityType
+	}
+	entityID = strings.TrimSpace(entityID)
+	if entityID == "" || len([]rune(entityID)) > MaxEntityIDLength {
+		return "", ErrInvalidEntityID
+	}
+	if entityType != EntityExercise && !id.IsUUID(entityID) {
+		return "", ErrInvalidEntityID
+	}
+	return entityID, nil
+}
+
+func validEntityType(entityType EntityType) bool {
+	return entityType == EntityTodoList || entityType == EntityCategory || entityType == EntityExercise
+}
@@ -0,0 +1,74 @@
+package favorites
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakeFavoritesRepo struct {
+	Repository
+	added []Favorite
+	count int64
+}
+
+func (r *fakeFavoritesRepo) AddFavorite(_ context.Context, favorite *Favorite) error {
+	r.added = append(r.added, *favorite)
+	return nil
+}
+
+func (r *fakeFavoritesRepo) CountFavorites(context.Context, string, EntityType) (int64, error) {
+	return r.count, nil
+}
+
+func TestAddStoresTrimmedExerciseName(t *testing.T) {
+	repo := &fakeFavoritesRepo{}
+	service := NewService(repo)
+
+	if err := service.Add(context.Background(), "user-1", EntityExercise, "  Bench press "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Favorite{{UserID: "user-1", EntityType: EntityExercise, EntityID: "Bench press"}}
+	if !reflect.DeepEqual(repo.added, want) {
+		t.Fatalf("added = %+v, want %+v", repo.added, want)
+	}
+}
+
+func TestAddRejectsInvalidInput(t *testing.T) {
+	service := NewService(&fakeFavoritesRepo{})
+
+	cases := []struct {
+		entityType EntityType
+		entityID   string
+		want       error
+	}{
+		{entityType: "workout", entityID: "b3c1e7a2-4f0d-4c53-9a39-3f1f0d7f2a10", want: ErrInvalidEntityType},
+		{entityType: EntityTodoList, entityID: "not-a-uuid", want: ErrInvalidEntityID},
+		{entityType: EntityExercise, entityID: "   ", want: ErrInvalidEntityID},
+	}
+	for _, tc := range cases {
+		if err := service.Add(context.Background(), "user-1", tc.entityType, tc.entityID); !errors.Is(err, tc.want) {
+			t.Fatalf("Add(%q, %q) error = %v, want %v", tc.entityType, tc.entityID, err, tc.want)
+		}
+	}
+}
+
+func TestAddEnforcesLimit(t *testing.T) {
+	service := NewService(&fakeFavoritesRepo{count: MaxFavoritesPerType})
+
+	err := service.Add(context.Background(), "user-1", EntityExercise, "Squat")
+	if !errors.Is(err, ErrTooManyFavorites) {
+		t.Fatalf("error = %v, want ErrTooManyFavorites", err)
+	}
+}
+
+func TestSortFirstKeepsOrderWithinGroups(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	SortFirst(items, map[string]bool{"c": true, "d": true}, func(item string) string { return item })
+
+	want := []string{"c", "d", "a", "b"}
+	if !reflect.DeepEqual(items, want) {
+		t.Fatalf("items = %v, want %v", items, want)
+	}
+}
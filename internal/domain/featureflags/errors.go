@@ -0,0 +1,9 @@
+package featureflags
+
+import "errors"
+
+var (
+	ErrUnknownFlag     = errors.New("unknown feature flag")
+	ErrFamilyNotFound  = errors.New("family not found")
+	ErrFeatureDisabled = errors.New("feature is disabled")
+)
@@ -0,0 +1,91 @@
+package featureflags
+
+import "time"
+
+// Flag keys. Every flag is declared in definitions; unknown keys are
+// rejected so a typo in an admin call cannot create a flag nothing reads.
+const (
+	// TopCategories gates GET /api/top_categories. TOP_CATEGORIES_ENABLED
+	// still turns the report off for everyone.
+	TopCategories = "top_categories"
+	// OfflineSync gates POST /api/sync. OFFLINE_SYNC_ENABLED still removes
+	// the endpoint for everyone.
+	OfflineSync = "offline_sync"
+
+	// Maintenance flags stop writes to a domain over HTTP, gRPC and sync,
+	// e.g. while a migration rewrites its tables. Reads stay available.
+	// They are off by default.
+	MaintenanceSync     = "maintenance_sync"
+	MaintenanceExpenses = "maintenance_expenses"
+	MaintenanceTodos    = "maintenance_todos"
+	MaintenanceGym      = "maintenance_gym"
+	MaintenancePets     = "maintenance_pets"
+	MaintenanceWishlist = "maintenance_wishlist"
+)
+
+// Definition describes a flag and the value it has until an operator sets
+// it.
+type Definition struct {
+	Key         string
+	Description string
+	Default     bool
+}
+
+var definitions = []Definition{
+	{Key: MaintenanceExpenses, Description: "Maintenance mode for expenses, categories, category rules and receipt parses."},
+	{Key: MaintenanceGym, Description: "Maintenance mode for gym entries, workouts, templates and sessions."},
+	{Key: MaintenancePets, Description: "Maintenance mode for pets."},
+	{Key: MaintenanceSync, Description: "Maintenance mode for offline sync."},
+	{Key: MaintenanceTodos, Description: "Maintenance mode for todo lists and items."},
+	{Key: MaintenanceWishlist, Description: "Maintenance mode for wishlists."},
+	{Key: OfflineSync, Description: "Offline sync batches over POST /api/sync.", Default: true},
+	{Key: TopCategories, Description: "Top categories report.", Default: true},
+}
+
+// Definitions returns every known flag, sorted by key.
+func Definitions() []Definition {
+	return append([]Definition(nil), definitions...)
+}
+
+func lookupDefinition(key string) (Definition, bool) {
+	for _, definition := range definitions {
+		if definition.Key == key {
+			return definition, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Flag is the global value of a flag set by an operator. Flags without a
+// row use their definition's default.
+type Flag struct {
+	Key       string    `gorm:"primaryKey"`
+	Enabled   bool      `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Flag) TableName() string {
+	return "feature_flags"
+}
+
+// FamilyOverride sets a flag for one family, ahead of the global value.
+type FamilyOverride struct {
+	FlagKey   string    `gorm:"primaryKey"`
+	FamilyID  string    `gorm:"type:uuid;primaryKey"`
+	Enabled   bool      `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (FamilyOverride) TableName() string {
+	return "feature_flag_overrides"
+}
+
+// State is a flag as operators see it: its definition, the global value and
+// every family override.
+type State struct {
+	Definition
+	// Enabled is the global value, the default when no operator set it.
+	Enabled   bool
+	UpdatedAt *time.Time
+	Overrides []FamilyOverride
+}
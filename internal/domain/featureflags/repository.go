@@ -0,0 +1,16 @@
+package featureflags
+
+import "context"
+
+type Repository interface {
+	ListFlags(ctx context.Context) ([]Flag, error)
+	// ListOverrides returns every family override, ordered by flag and
+	// family.
+	ListOverrides(ctx context.Context) ([]FamilyOverride, error)
+	// UpsertFlag sets the global value of a flag.
+	UpsertFlag(ctx context.Context, flag *Flag) error
+	// UpsertOverride sets a family override. It returns ErrFamilyNotFound
+	// when the family does not exist.
+	UpsertOverride(ctx context.Context, override *FamilyOverride) error
+	DeleteOverride(ctx context.Context, key, familyID string) error
+}
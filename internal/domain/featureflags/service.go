@@ -0,0 +1,224 @@
+package featureflags
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"family-app-go/pkg/tracing"
+)
+
+const defaultCacheTTL = 30 * time.Second
+
+// Service evaluates feature flags. Evaluation reads a snapshot of every flag
+// and override, cached for CacheTTL: changes made on this instance apply at
+// once, changes made on other instances within CacheTTL.
+type Service struct {
+	repo     Repository
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	snapshot *snapshot
+}
+
+type ServiceOptions struct {
+	// CacheTTL is how long a snapshot of the flags is reused. Zero reads
+	// the flags on every evaluation.
+	CacheTTL time.Duration
+}
+
+// snapshot holds the stored values; flags missing here use their default.
+type snapshot struct {
+	flags     map[string]bool
+	overrides map[string]map[string]bool
+	expiresAt time.Time
+}
+
+func NewService(repo Repository) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{CacheTTL: defaultCacheTTL})
+}
+
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	cacheTTL := options.CacheTTL
+	if cacheTTL < 0 {
+		cacheTTL = 0
+	}
+	return &Service{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+	}
+}
+
+// Enabled reports whether a flag is on for the family: the family override
+// wins over the global value, which wins over the default.
+func (s *Service) Enabled(ctx context.Context, key, familyID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "featureflags.Enabled")
+	defer span.End()
+
+	definition, ok := lookupDefinition(key)
+	if !ok {
+		return false, ErrUnknownFlag
+	}
+	current, err := s.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return current.evaluate(definition, familyID), nil
+}
+
+// Evaluate returns every flag for the family, keyed by flag key.
+func (s *Service) Evaluate(ctx context.Context, familyID string) (map[string]bool, error) {
+	ctx, span := tracing.Start(ctx, "featureflags.Evaluate")
+	defer span.End()
+
+	current, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		result[definition.Key] = current.evaluate(definition, familyID)
+	}
+	return result, nil
+}
+
+// List returns every flag with its stored values, read past the cache.
+func (s *Service) List(ctx context.Context) ([]State, error) {
+	ctx, span := tracing.Start(ctx, "featureflags.List")
+	defer span.End()
+
+	flags, err := s.repo.ListFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.repo.ListOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]State, 0, len(definitions))
+	for _, definition := range definitions {
+		state := State{Definition: definition, Enabled: definition.Default, Overrides: []FamilyOverride{}}
+		for _, flag := range flags {
+			if flag.Key == definition.Key {
+				updatedAt := flag.UpdatedAt
+				state.Enabled = flag.Enabled
+				state.UpdatedAt = &updatedAt
+			}
+		}
+		for _, override := range overrides {
+			if override.FlagKey == definition.Key {
+				state.Overrides = append(state.Overrides, override)
+			}
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// SetGlobal sets the value of a flag for families without an override.
+func (s *Service) SetGlobal(ctx context.Context, key string, enabled bool) error {
+	ctx, span := tracing.Start(ctx, "featureflags.SetGlobal")
+	defer span.End()
+
+	if _, ok := lookupDefinition(key); !ok {
+		return ErrUnknownFlag
+	}
+	if err := s.repo.UpsertFlag(ctx, &Flag{Key: key, Enabled: enabled}); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// SetFamily overrides a flag for one family.
+func (s *Service) SetFamily(ctx context.Context, key, familyID string, enabled bool) error {
+	ctx, span := tracing.Start(ctx, "featureflags.SetFamily")
+	defer span.End()
+
+	if _, ok := lookupDefinition(key); !ok {
+		return ErrUnknownFlag
+	}
+	override := &FamilyOverride{FlagKey: key, FamilyID: strings.TrimSpace(familyID), Enabled: enabled}
+	if err := s.repo.UpsertOverride(ctx, override); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// ClearFamily removes a family override, so the family follows the global
+// value again.
+func (s *Service) ClearFamily(ctx context.Context, key, familyID string) error {
+	ctx, span := tracing.Start(ctx, "featureflags.ClearFamily")
+	defer span.End()
+
+	if _, ok := lookupDefinition(key); !ok {
+		return ErrUnknownFlag
+	}
+	if err := s.repo.DeleteOverride(ctx, key, strings.TrimSpace(familyID)); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *Service) load(ctx context.Context) (*snapshot, error) {
+	now := s.now()
+	s.mu.Lock()
+	current := s.snapshot
+	s.mu.Unlock()
+	if current != nil && now.Before(current.expiresAt) {
+		return current, nil
+	}
+
+	flags, err := s.repo.ListFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.repo.ListOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current = &snapshot{
+		flags:     make(map[string]bool, len(flags)),
+		overrides: make(map[string]map[string]bool),
+		expiresAt: now.Add(s.cacheTTL),
+	}
+	for _, flag := range flags {
+		current.flags[flag.Key] = flag.Enabled
+	}
+	for _, override := range overrides {
+		if current.overrides[override.FlagKey] == nil {
+			current.overrides[override.FlagKey] = make(map[string]bool)
+		}
+		current.overrides[override.FlagKey][override.FamilyID] = override.Enabled
+	}
+
+	if s.cacheTTL > 0 {
+		s.mu.Lock()
+		s.snapshot = current
+		s.mu.Unlock()
+	}
+	return current, nil
+}
+
+func (s *Service) invalidate() {
+	s.mu.Lock()
+	s.snapshot = nil
+	s.mu.Unlock()
+}
+
+func (c *snapshot) evaluate(definition Definition, familyID string) bool {
+	if enabled, ok := c.overrides[definition.Key][familyID]; ok {
+		return enabled
+	}
+	if enabled, ok := c.flags[definition.Key]; ok {
+		return enabled
+	}
+	return definition.Default
+}
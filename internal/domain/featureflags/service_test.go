@@ -0,0 +1,101 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeFlagsRepo struct {
+	flags     []Flag
+	overrides []FamilyOverride
+	loads     int
+}
+
+func (r *fakeFlagsRepo) ListFlags(context.Context) ([]Flag, error) {
+	r.loads++
+	return r.flags, nil
+}
+
+func (r *fakeFlagsRepo) ListOverrides(context.Context) ([]FamilyOverride, error) {
+	return r.overrides, nil
+}
+
+func (r *fakeFlagsRepo) UpsertFlag(_ context.Context, flag *Flag) error {
+	for i := range r.flags {
+		if r.flags[i].Key == flag.Key {
+			r.flags[i] = *flag
+			return nil
+		}
+	}
+	r.flags = append(r.flags, *flag)
+	return nil
+}
+
+func (r *fakeFlagsRepo) UpsertOverride(_ context.Context, override *FamilyOverride) error {
+	r.overrides = append(r.overrides, *override)
+	return nil
+}
+
+func (r *fakeFlagsRepo) DeleteOverride(context.Context, string, string) error {
+	return nil
+}
+
+func TestEnabledPrefersOverrideThenGlobalThenDefault(t *testing.T) {
+	repo := &fakeFlagsRepo{
+		flags:     []Flag{{Key: TopCategories, Enabled: false}},
+		overrides: []FamilyOverride{{FlagKey: TopCategories, FamilyID: "family-1", Enabled: true}},
+	}
+	service := NewService(repo)
+	ctx := context.Background()
+
+	cases := []struct {
+		key      string
+		familyID string
+		want     bool
+	}{
+		{key: TopCategories, familyID: "family-1", want: true},
+		{key: TopCategories, familyID: "family-2", want: false},
+		{key: OfflineSync, familyID: "family-2", want: true},
+	}
+	for _, tc := range cases {
+		got, err := service.Enabled(ctx, tc.key, tc.familyID)
+		if err != nil {
+			t.Fatalf("Enabled(%q, %q): %v", tc.key, tc.familyID, err)
+		}
+		if got != tc.want {
+			t.Fatalf("Enabled(%q, %q) = %v, want %v", tc.key, tc.familyID, got, tc.want)
+		}
+	}
+	if repo.loads != 1 {
+		t.Fatalf("expected one load for cached evaluations, got %d", repo.loads)
+	}
+}
+
+func TestEnabledRejectsUnknownFlag(t *testing.T) {
+	service := NewService(&fakeFlagsRepo{})
+
+	if _, err := service.Enabled(context.Background(), "sync_v3", "family-1"); !errors.Is(err, ErrUnknownFlag) {
+		t.Fatalf("expected ErrUnknownFlag, got %v", err)
+	}
+	if err := service.SetGlobal(context.Background(), "sync_v3", true); !errors.Is(err, ErrUnknownFlag) {
+		t.Fatalf("expected ErrUnknownFlag, got %v", err)
+	}
+}
+
+func TestSetGlobalInvalidatesCache(t *testing.T) {
+	repo := &fakeFlagsRepo{}
+	service := NewServiceWithOptions(repo, ServiceOptions{CacheTTL: time.Hour})
+	ctx := context.Background()
+
+	if enabled, _ := service.Enabled(ctx, TopCategories, "family-1"); !enabled {
+		t.Fatal("expected default to be enabled")
+	}
+	if err := service.SetGlobal(ctx, TopCategories, false); err != nil {
+		t.Fatalf("SetGlobal: %v", err)
+	}
+	if enabled, _ := service.Enabled(ctx, TopCategories, "family-1"); enabled {
+		t.Fatal("expected flag to be disabled right after SetGlobal")
+	}
+}
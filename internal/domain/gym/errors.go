@@ -3,7 +3,24 @@ package gym
 import "errors"
 
 var (
-	ErrGymEntryNotFound = errors.New("gym entry not found")
-	ErrWorkoutNotFound  = errors.New("workout not found")
-	ErrTemplateNotFound = errors.New("workout template not found")
+	ErrGymEntryNotFound  = errors.New("gym entry not found")
+	ErrWorkoutNotFound   = errors.New("workout not found")
+	ErrTemplateNotFound  = errors.New("workout template not found")
+	ErrFamilyRequired    = errors.New("family is required for family scope")
+	ErrInvalidScope      = errors.New("invalid scope")
+	ErrInvalidVisibility = errors.New("invalid visibility")
+
+	ErrSessionNotFound      = errors.New("workout session not found")
+	ErrSessionAlreadyActive = errors.New("workout session already active")
+	ErrSessionNotActive     = errors.New("workout session is not active")
+	ErrSessionEmpty         = errors.New("workout session has no sets")
+	ErrInvalidRestSeconds   = errors.New("invalid rest seconds")
+
+	ErrInvalidImportFile       = errors.New("invalid import file")
+	ErrUnsupportedImportFormat = errors.New("unsupported import format")
+	ErrInvalidWeightUnit       = errors.New("invalid weight unit")
+	ErrImportTooLarge          = errors.New("import has too many rows")
+
+	ErrGoalNotFound = errors.New("gym goal not found")
+	ErrInvalidGoal  = errors.New("invalid gym goal")
 )
@@ -0,0 +1,261 @@
+package gym
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	// MaxWorkoutsPerWeek bounds the weekly goal to two sessions a day.
+	MaxWorkoutsPerWeek = 14
+
+	// streakLookbackWeeks limits how far back streaks are computed.
+	streakLookbackWeeks = 104
+
+	defaultNudgeBefore = 36 * time.Hour
+	nudgeBatchSize     = 100
+)
+
+// Goal is a user's weekly training target
+type Goal struct {
+	UserID          string    `gorm:"type:uuid;primaryKey"`
+	WorkoutsPerWeek int       `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Goal) TableName() string {
+	return "gym_goals"
+}
+
+// GoalNudge is emitted once per week when the week is about to end below the
+// user's goal. Clients poll it through the streak endpoint.
+type GoalNudge struct {
+	ID           string    `gorm:"type:uuid;primaryKey"`
+	UserID       string    `gorm:"type:uuid;not null"`
+	WeekStart    time.Time `gorm:"type:date;not null"`
+	WorkoutsDone int       `gorm:"not null"`
+	Target       int       `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (GoalNudge) TableName() string {
+	return "gym_goal_nudges"
+}
+
+// ActivityDay aggregates a user's training on one date
+type ActivityDay struct {
+	Date     time.Time
+	Workouts int
+	Entries  int
+}
+
+// Streak describes progress towards the weekly goal. Weeks start on Monday
+// (UTC). Without a goal only the current week's count is filled.
+type Streak struct {
+	Goal               *Goal
+	WeekStart          time.Time
+	WeekEnd            time.Time
+	WorkoutsThisWeek   int
+	CurrentStreakWeeks int
+	LongestStreakWeeks int
+	Nudge              *GoalNudge
+}
+
+// Remaining returns how many workouts are still needed this week.
+func (s Streak) Remaining() int {
+	if s.Goal == nil || s.WorkoutsThisWeek >= s.Goal.WorkoutsPerWeek {
+		return 0
+	}
+	return s.Goal.WorkoutsPerWeek - s.WorkoutsThisWeek
+}
+
+func (s *Service) GetGoal(ctx context.Context, userID string) (*Goal, error) {
+	ctx, span := tracing.Start(ctx, "gym.GetGoal")
+	defer span.End()
+
+	return s.repo.GetGoal(ctx, userID)
+}
+
+func (s *Service) SetGoal(ctx context.Context, userID string, workoutsPerWeek int) (*Goal, error) {
+	ctx, span := tracing.Start(ctx, "gym.SetGoal")
+	defer span.End()
+
+	if workoutsPerWeek < 1 || workoutsPerWeek > MaxWorkoutsPerWeek {
+		return nil, ErrInvalidGoal
+	}
+
+	goal := &Goal{
+		UserID:          userID,
+		WorkoutsPerWeek: workoutsPerWeek,
+		UpdatedAt:       s.now().UTC(),
+	}
+	if err := s.repo.UpsertGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+func (s *Service) DeleteGoal(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "gym.DeleteGoal")
+	defer span.End()
+
+	deleted, err := s.repo.DeleteGoal(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// GetStreak counts workouts per week and returns the current and longest run
+// of weeks meeting the goal. The current week only extends the streak once
+// its goal is met, so an unfinished week does not break it.
+func (s *Service) GetStreak(ctx context.Context, userID string) (*Streak, error) {
+	ctx, span := tracing.Start(ctx, "gym.GetStreak")
+	defer span.End()
+
+	now := s.now().UTC()
+	weekStart := startOfWeek(now)
+	streak := &Streak{
+		WeekStart: weekStart,
+		WeekEnd:   weekStart.AddDate(0, 0, 6),
+	}
+
+	goal, err := s.repo.GetGoal(ctx, userID)
+	if err != nil && !errors.Is(err, ErrGoalNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		streak.Goal = goal
+	}
+
+	from := weekStart
+	if streak.Goal != nil {
+		from = weekStart.AddDate(0, 0, -7*streakLookbackWeeks)
+	}
+	days, err := s.repo.ListActivityDays(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	weekly := workoutsByWeek(days)
+	streak.WorkoutsThisWeek = weekly[weekStart]
+
+	if streak.Goal == nil {
+		return streak, nil
+	}
+
+	target := streak.Goal.WorkoutsPerWeek
+	current := 0
+	if weekly[weekStart] >= target {
+		current = 1
+	}
+	for week := weekStart.AddDate(0, 0, -7); !week.Before(from); week = week.AddDate(0, 0, -7) {
+		if weekly[week] < target {
+			break
+		}
+		current++
+	}
+	streak.CurrentStreakWeeks = current
+
+	run := 0
+	for week := from; !week.After(weekStart); week = week.AddDate(0, 0, 7) {
+		if weekly[week] >= target {
+			run++
+			if run > streak.LongestStreakWeeks {
+				streak.LongestStreakWeeks = run
+			}
+			continue
+		}
+		if !week.Equal(weekStart) {
+			run = 0
+		}
+	}
+
+	nudge, found, err := s.repo.GetGoalNudge(ctx, userID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		streak.Nudge = nudge
+	}
+
+	return streak, nil
+}
+
+// RunNudges records a nudge for every user whose week ends within the nudge
+// window while still below their goal. Each user is nudged at most once per
+// week.
+func (s *Service) RunNudges(ctx context.Context) ([]GoalNudge, error) {
+	ctx, span := tracing.Start(ctx, "gym.RunNudges")
+	defer span.End()
+
+	now := s.now().UTC()
+	weekStart := startOfWeek(now)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	if now.Before(weekEnd.Add(-s.nudgeBefore)) {
+		return nil, nil
+	}
+
+	goals, err := s.repo.ListGoalsWithoutNudge(ctx, weekStart, nudgeBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	nudges := make([]GoalNudge, 0, len(goals))
+	for _, goal := range goals {
+		days, err := s.repo.ListActivityDays(ctx, goal.UserID, weekStart)
+		if err != nil {
+			return nudges, err
+		}
+		done := workoutsByWeek(days)[weekStart]
+		if done >= goal.WorkoutsPerWeek {
+			continue
+		}
+
+		nudgeID, err := id.New()
+		if err != nil {
+			return nudges, err
+		}
+		nudge := GoalNudge{
+			ID:           nudgeID,
+			UserID:       goal.UserID,
+			WeekStart:    weekStart,
+			WorkoutsDone: done,
+			Target:       goal.WorkoutsPerWeek,
+			CreatedAt:    now,
+		}
+		if err := s.repo.CreateGoalNudge(ctx, &nudge); err != nil {
+			return nudges, err
+		}
+		nudges = append(nudges, nudge)
+	}
+	return nudges, nil
+}
+
+// workoutsByWeek counts workouts per week start. A day with gym entries but
+// no workout counts as one workout.
+func workoutsByWeek(days []ActivityDay) map[time.Time]int {
+	weekly := make(map[time.Time]int)
+	for _, day := range days {
+		count := day.Workouts
+		if count == 0 && day.Entries > 0 {
+			count = 1
+		}
+		weekly[startOfWeek(day.Date)] += count
+	}
+	return weekly
+}
+
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,549 @@
+package gym
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// ImportFormat names a supported CSV export layout
+type ImportFormat string
+
+const (
+	ImportFormatAuto    ImportFormat = "auto"
+	ImportFormatStrong  ImportFormat = "strong"
+	ImportFormatHevy    ImportFormat = "hevy"
+	ImportFormatGeneric ImportFormat = "generic"
+)
+
+// WeightUnit is the unit weights are given in by an export
+type WeightUnit string
+
+const (
+	WeightUnitKg  WeightUnit = "kg"
+	WeightUnitLbs WeightUnit = "lbs"
+)
+
+const (
+	// MaxImportRows bounds a single import; bigger histories can be split.
+	MaxImportRows = 50000
+	kgPerLb       = 0.45359237
+)
+
+// ImportInput represents a CSV import request
+type ImportInput struct {
+	UserID     string
+	Format     ImportFormat
+	WeightUnit WeightUnit
+	DryRun     bool
+	Data       io.Reader
+}
+
+// ImportRowError describes a row that could not be imported. Row is the
+// 1-based line number in the file, the header being row 1.
+type ImportRowError struct {
+	Row     int
+	Message string
+}
+
+// ImportResult summarizes an import. In dry-run mode the counts describe what
+// would be imported and nothing is stored.
+type ImportResult struct {
+	DryRun            bool
+	Format            ImportFormat
+	Rows              int
+	SkippedRows       int
+	Workouts          int
+	WorkoutSets       int
+	Entries           int
+	DuplicateWorkouts int
+	Errors            []ImportRowError
+}
+
+type importedWorkout struct {
+	Date time.Time
+	Name string
+	Sets []CreateWorkoutSetInput
+}
+
+type parsedImport struct {
+	Format   ImportFormat
+	Rows     int
+	Skipped  int
+	Workouts []importedWorkout
+	Entries  []CreateGymEntryInput
+	Errors   []ImportRowError
+}
+
+// Import reads a CSV export and stores its workouts and standalone entries.
+// Invalid rows are reported and skipped. Workouts that already exist with the
+// same date and name are skipped so re-running an import is safe. Imported
+// history does not emit personal record events.
+func (s *Service) Import(ctx context.Context, input ImportInput) (*ImportResult, error) {
+	ctx, span := tracing.Start(ctx, "gym.Import")
+	defer span.End()
+
+	parsed, err := parseImport(input.Data, input.Format, input.WeightUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{
+		DryRun:      input.DryRun,
+		Format:      parsed.Format,
+		Rows:        parsed.Rows,
+		SkippedRows: parsed.Skipped,
+		Errors:      parsed.Errors,
+	}
+
+	workouts, err := s.withoutExistingWorkouts(ctx, input.UserID, parsed.Workouts)
+	if err != nil {
+		return nil, err
+	}
+	result.DuplicateWorkouts = len(parsed.Workouts) - len(workouts)
+	result.Workouts = len(workouts)
+	for _, workout := range workouts {
+		result.WorkoutSets += len(workout.Sets)
+	}
+	result.Entries = len(parsed.Entries)
+
+	if input.DryRun {
+		return result, nil
+	}
+
+	err = s.repo.Transaction(ctx, func(tx Repository) error {
+		for _, imported := range workouts {
+			workoutID, err := id.New()
+			if err != nil {
+				return err
+			}
+			workout := Workout{
+				ID:         workoutID,
+				UserID:     input.UserID,
+				Date:       imported.Date,
+				Name:       imported.Name,
+				Visibility: VisibilityPrivate,
+			}
+			sets := make([]WorkoutSet, 0, len(imported.Sets))
+			for i, setInput := range imported.Sets {
+				setID, err := id.New()
+				if err != nil {
+					return err
+				}
+				sets = append(sets, WorkoutSet{
+					ID:        setID,
+					WorkoutID: workoutID,
+					Exercise:  setInput.Exercise,
+					WeightKg:  setInput.WeightKg,
+					Reps:      setInput.Reps,
+					SetOrder:  i,
+				})
+			}
+
+			if err := tx.CreateWorkout(ctx, &workout); err != nil {
+				return err
+			}
+			if len(sets) > 0 {
+				if err := tx.ReplaceWorkoutSets(ctx, workout.ID, sets); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, entryInput := range parsed.Entries {
+			entryID, err := id.New()
+			if err != nil {
+				return err
+			}
+			entry := GymEntry{
+				ID:         entryID,
+				UserID:     input.UserID,
+				Date:       entryInput.Date,
+				Exercise:   entryInput.Exercise,
+				WeightKg:   entryInput.WeightKg,
+				Reps:       entryInput.Reps,
+				Visibility: VisibilityPrivate,
+			}
+			if err := tx.CreateGymEntry(ctx, &entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) withoutExistingWorkouts(ctx context.Context, userID string, workouts []importedWorkout) ([]importedWorkout, error) {
+	if len(workouts) == 0 {
+		return workouts, nil
+	}
+
+	from, to := workouts[0].Date, workouts[0].Date
+	for _, workout := range workouts {
+		if workout.Date.Before(from) {
+			from = workout.Date
+		}
+		if workout.Date.After(to) {
+			to = workout.Date
+		}
+	}
+
+	existing, _, err := s.repo.ListWorkouts(ctx, []string{userID}, ListFilter{From: &from, To: &to})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(existing))
+	for _, workout := range existing {
+		seen[workoutKey(workout.Date, workout.Name)] = struct{}{}
+	}
+
+	result := make([]importedWorkout, 0, len(workouts))
+	for _, workout := range workouts {
+		key := workoutKey(workout.Date, workout.Name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, workout)
+	}
+	return result, nil
+}
+
+func workoutKey(date time.Time, name string) string {
+	return date.Format("2006-01-02") + "|" + strings.ToLower(strings.TrimSpace(name))
+}
+
+// CSV parsing
+
+type importColumns map[string]int
+
+func (c importColumns) has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := c[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (c importColumns) value(record []string, names ...string) string {
+	for _, name := range names {
+		if index, ok := c[name]; ok && index < len(record) {
+			return strings.TrimSpace(record[index])
+		}
+	}
+	return ""
+}
+
+// importRow is a single set read from any supported layout
+type importRow struct {
+	WorkoutKey  string
+	WorkoutName string
+	Date        time.Time
+	Exercise    string
+	WeightKg    float64
+	Reps        int
+}
+
+// errSkipRow marks rows that are not sets, such as Strong rest timer rows or
+// cardio sets without weight and reps.
+var errSkipRow = errors.New("not a strength set")
+
+func parseImport(data io.Reader, format ImportFormat, unit WeightUnit) (*parsedImport, error) {
+	switch format {
+	case "", ImportFormatAuto, ImportFormatStrong, ImportFormatHevy, ImportFormatGeneric:
+	default:
+		return nil, ErrUnsupportedImportFormat
+	}
+	switch unit {
+	case "", WeightUnitKg, WeightUnitLbs:
+	default:
+		return nil, ErrInvalidWeightUnit
+	}
+
+	reader, err := newImportReader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidImportFile
+	}
+	columns := make(importColumns, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	format, err = detectImportFormat(columns, format)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &parsedImport{Format: format}
+	workoutIndex := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, ErrInvalidImportFile
+			}
+			parsed.Errors = append(parsed.Errors, ImportRowError{Row: parseErr.StartLine, Message: "malformed csv row"})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		parsed.Rows++
+		if parsed.Rows > MaxImportRows {
+			return nil, ErrImportTooLarge
+		}
+
+		row, err := parseImportRow(format, columns, record, unit)
+		if err != nil {
+			if errors.Is(err, errSkipRow) {
+				parsed.Skipped++
+				continue
+			}
+			parsed.Errors = append(parsed.Errors, ImportRowError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		if row.WorkoutKey == "" {
+			parsed.Entries = append(parsed.Entries, CreateGymEntryInput{
+				Date:     row.Date,
+				Exercise: row.Exercise,
+				WeightKg: row.WeightKg,
+				Reps:     row.Reps,
+			})
+			continue
+		}
+
+		index, ok := workoutIndex[row.WorkoutKey]
+		if !ok {
+			index = len(parsed.Workouts)
+			workoutIndex[row.WorkoutKey] = index
+			parsed.Workouts = append(parsed.Workouts, importedWorkout{Date: row.Date, Name: row.WorkoutName})
+		}
+		parsed.Workouts[index].Sets = append(parsed.Workouts[index].Sets, CreateWorkoutSetInput{
+			Exercise: row.Exercise,
+			WeightKg: row.WeightKg,
+			Reps:     row.Reps,
+		})
+	}
+
+	return parsed, nil
+}
+
+// newImportReader sniffs the delimiter from the header line, since some
+// locales export semicolon-separated files.
+func newImportReader(data io.Reader) (*csv.Reader, error) {
+	buffered := bufio.NewReader(data)
+	firstLine, err := buffered.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, ErrInvalidImportFile
+	}
+	if index := bytes.IndexByte(firstLine, '\n'); index >= 0 {
+		firstLine = firstLine[:index]
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	return reader, nil
+}
+
+func detectImportFormat(columns importColumns, requested ImportFormat) (ImportFormat, error) {
+	strong := columns.has("date", "workout name", "exercise name", "reps")
+	hevy := columns.has("title", "start_time", "exercise_title", "reps")
+	generic := columns.has("date", "exercise", "reps")
+
+	switch requested {
+	case ImportFormatStrong:
+		if strong {
+			return ImportFormatStrong, nil
+		}
+	case ImportFormatHevy:
+		if hevy {
+			return ImportFormatHevy, nil
+		}
+	case ImportFormatGeneric:
+		if generic {
+			return ImportFormatGeneric, nil
+		}
+	default:
+		switch {
+		case strong:
+			return ImportFormatStrong, nil
+		case hevy:
+			return ImportFormatHevy, nil
+		case generic:
+			return ImportFormatGeneric, nil
+		}
+	}
+	return "", ErrInvalidImportFile
+}
+
+func parseImportRow(format ImportFormat, columns importColumns, record []string, unit WeightUnit) (importRow, error) {
+	var row importRow
+	var rawDate, weight, reps string
+	rowUnit := unit
+
+	switch format {
+	case ImportFormatStrong:
+		// Strong interleaves rest timer and note rows with a non-numeric set order.
+		if setOrder := columns.value(record, "set order"); setOrder != "" {
+			if _, err := strconv.Atoi(setOrder); err != nil {
+				return row, errSkipRow
+			}
+		}
+		rawDate = columns.value(record, "date")
+		row.WorkoutName = columns.value(record, "workout name")
+		row.WorkoutKey = rawDate + "|" + row.WorkoutName
+		row.Exercise = columns.value(record, "exercise name")
+		weight = columns.value(record, "weight")
+		reps = columns.value(record, "reps")
+		switch value := strings.ToLower(columns.value(record, "weight unit")); value {
+		case "":
+		case "kg", "kgs":
+			rowUnit = WeightUnitKg
+		case "lb", "lbs":
+			rowUnit = WeightUnitLbs
+		default:
+			rowUnit = WeightUnit(value)
+		}
+	case ImportFormatHevy:
+		rawDate = columns.value(record, "start_time")
+		row.WorkoutName = columns.value(record, "title")
+		row.WorkoutKey = rawDate + "|" + row.WorkoutName
+		row.Exercise = columns.value(record, "exercise_title")
+		reps = columns.value(record, "reps")
+		if columns.has("weight_lbs") {
+			weight = columns.value(record, "weight_lbs")
+			rowUnit = WeightUnitLbs
+		} else {
+			weight = columns.value(record, "weight_kg")
+			rowUnit = WeightUnitKg
+		}
+	default:
+		rawDate = columns.value(record, "date")
+		row.WorkoutName = columns.value(record, "workout")
+		if row.WorkoutName != "" {
+			row.WorkoutKey = rawDate + "|" + row.WorkoutName
+		}
+		row.Exercise = columns.value(record, "exercise")
+		reps = columns.value(record, "reps")
+		switch {
+		case columns.has("weight_lbs"):
+			weight = columns.value(record, "weight_lbs")
+			rowUnit = WeightUnitLbs
+		case columns.has("weight_kg"):
+			weight = columns.value(record, "weight_kg")
+			rowUnit = WeightUnitKg
+		default:
+			weight = columns.value(record, "weight")
+		}
+	}
+
+	date, err := parseImportDate(rawDate)
+	if err != nil {
+		return row, fmt.Errorf("invalid date %q", rawDate)
+	}
+	row.Date = date
+
+	if row.Exercise == "" {
+		return row, fmt.Errorf("exercise is required")
+	}
+
+	weightValue, err := parseImportNumber(weight)
+	if err != nil || weightValue < 0 {
+		return row, fmt.Errorf("invalid weight %q", weight)
+	}
+	repsValue, err := parseImportNumber(reps)
+	if err != nil || repsValue < 0 || repsValue != math.Trunc(repsValue) {
+		return row, fmt.Errorf("invalid reps %q", reps)
+	}
+	if weightValue == 0 && repsValue == 0 {
+		return row, errSkipRow
+	}
+
+	switch rowUnit {
+	case WeightUnitLbs:
+		weightValue = roundKg(weightValue * kgPerLb)
+	case "", WeightUnitKg:
+	default:
+		return row, fmt.Errorf("invalid weight unit %q", rowUnit)
+	}
+
+	row.WeightKg = weightValue
+	row.Reps = int(repsValue)
+	return row, nil
+}
+
+var importDateLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC3339,
+	"2 Jan 2006, 15:04",
+	"2 Jan 2006 15:04",
+	"Jan 2, 2006, 15:04",
+	"02.01.2006",
+	"02.01.2006 15:04",
+}
+
+// parseImportDate keeps the calendar date as written in the export; the
+// clock time only distinguishes workouts of the same day.
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range importDateLayouts {
+		parsed, err := time.Parse(layout, value)
+		if err == nil {
+			return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date format")
+}
+
+func parseImportNumber(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
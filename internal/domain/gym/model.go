@@ -1,27 +1,41 @@
 package gym
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // GymEntry represents a single set in a workout
 type GymEntry struct {
-	ID        string    `gorm:"type:uuid;primaryKey"`
-	UserID    string    `gorm:"type:uuid;index;not null"`
-	Date      time.Time `gorm:"type:date;not null"`
-	Exercise  string    `gorm:"not null"`
-	WeightKg  float64   `gorm:"type:numeric(8,2);not null"`
-	Reps      int       `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	UserID     string     `gorm:"type:uuid;index;not null"`
+	Date       time.Time  `gorm:"type:date;not null"`
+	Exercise   string     `gorm:"not null"`
+	WeightKg   float64    `gorm:"type:numeric(8,2);not null"`
+	Reps       int        `gorm:"not null"`
+	Visibility Visibility `gorm:"type:varchar(16);not null;default:private"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
 }
 
+// Visibility controls whether a gym entry, workout or template is shared
+// with the family
+type Visibility string
+
+const (
+	VisibilityPrivate Visibility = "private"
+	VisibilityFamily  Visibility = "family"
+)
+
 // Workout represents a collection of sets grouped together
 type Workout struct {
-	ID        string    `gorm:"type:uuid;primaryKey"`
-	UserID    string    `gorm:"type:uuid;index;not null"`
-	Date      time.Time `gorm:"type:date;not null"`
-	Name      string    `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	UserID     string     `gorm:"type:uuid;index;not null"`
+	Date       time.Time  `gorm:"type:date;not null"`
+	Name       string     `gorm:"not null"`
+	Visibility Visibility `gorm:"type:varchar(16);not null;default:private"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
 }
 
 // WorkoutSet represents a single set within a workout
@@ -38,11 +52,12 @@ type WorkoutSet struct {
 
 // WorkoutTemplate represents a reusable workout template
 type WorkoutTemplate struct {
-	ID        string    `gorm:"type:uuid;primaryKey"`
-	UserID    string    `gorm:"type:uuid;index;not null"`
-	Name      string    `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	UserID     string     `gorm:"type:uuid;index;not null"`
+	Name       string     `gorm:"not null"`
+	Visibility Visibility `gorm:"type:varchar(16);not null;default:private"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
 }
 
 // TemplateSet represents a single set within a workout template (similar to WorkoutSet)
@@ -68,31 +83,73 @@ type TemplateWithSets struct {
 	Sets []TemplateSet
 }
 
+// ScopeKind selects whose records a read returns
+type ScopeKind string
+
+const (
+	ScopeMe     ScopeKind = "me"
+	ScopeFamily ScopeKind = "family"
+)
+
+// ParseScopeKind reads a scope query value, ignoring case and surrounding
+// space. An empty value is ScopeMe.
+func ParseScopeKind(value string) (ScopeKind, bool) {
+	switch kind := ScopeKind(strings.ToLower(strings.TrimSpace(value))); kind {
+	case "", ScopeMe:
+		return ScopeMe, true
+	case ScopeFamily:
+		return ScopeFamily, true
+	default:
+		return "", false
+	}
+}
+
+// Scope identifies the caller of a read operation. With ScopeFamily the read
+// covers every member of FamilyID; writes are always limited to UserID's own
+// records.
+type Scope struct {
+	UserID   string
+	FamilyID string
+	Kind     ScopeKind
+}
+
 // ListFilter defines filtering options for listing gym entries/workouts
 type ListFilter struct {
 	From   *time.Time
 	To     *time.Time
 	Limit  int
 	Offset int
+	// ViewerID, when set, limits other users' gym entries and workouts to
+	// ones shared with the family; the viewer's own are always included.
+	ViewerID string
+	// SharedOnly limits workouts to ones shared with the family.
+	SharedOnly bool
+	// Labels limits workouts to ones carrying at least one of the labels.
+	Labels []string
+	// Query limits workouts to ones whose name or an exercise contains it,
+	// case-insensitively.
+	Query string
 }
 
 // CreateGymEntryInput represents input for creating a gym entry
 type CreateGymEntryInput struct {
-	UserID   string
-	Date     time.Time
-	Exercise string
-	WeightKg float64
-	Reps     int
+	UserID     string
+	Date       time.Time
+	Exercise   string
+	WeightKg   float64
+	Reps       int
+	Visibility Visibility // Optional: defaults to private
 }
 
 // UpdateGymEntryInput represents input for updating a gym entry
 type UpdateGymEntryInput struct {
-	ID       string
-	UserID   string
-	Date     time.Time
-	Exercise string
-	WeightKg float64
-	Reps     int
+	ID         string
+	UserID     string
+	Date       time.Time
+	Exercise   string
+	WeightKg   float64
+	Reps       int
+	Visibility Visibility // Optional: keeps the current visibility when empty
 }
 
 // CreateWorkoutInput represents input for creating a workout
@@ -101,7 +158,8 @@ type CreateWorkoutInput struct {
 	Date       time.Time
 	Name       string
 	Sets       []CreateWorkoutSetInput
-	TemplateID string // Optional: if provided, copy sets from template
+	TemplateID string     // Optional: if provided, copy sets from template
+	Visibility Visibility // Optional: defaults to private
 }
 
 // CreateWorkoutSetInput represents input for creating a workout set
@@ -113,18 +171,20 @@ type CreateWorkoutSetInput struct {
 
 // UpdateWorkoutInput represents input for updating a workout
 type UpdateWorkoutInput struct {
-	ID     string
-	UserID string
-	Date   time.Time
-	Name   string
-	Sets   []CreateWorkoutSetInput
+	ID         string
+	UserID     string
+	Date       time.Time
+	Name       string
+	Sets       []CreateWorkoutSetInput
+	Visibility Visibility // Optional: keeps the current visibility when empty
 }
 
 // CreateTemplateInput represents input for creating a workout template
 type CreateTemplateInput struct {
-	UserID string
-	Name   string
-	Sets   []CreateTemplateSetInput
+	UserID     string
+	Name       string
+	Sets       []CreateTemplateSetInput
+	Visibility Visibility // Optional: defaults to private
 }
 
 // CreateTemplateSetInput represents input for creating a template set
@@ -136,8 +196,161 @@ type CreateTemplateSetInput struct {
 
 // UpdateTemplateInput represents input for updating a workout template
 type UpdateTemplateInput struct {
-	ID     string
-	UserID string
-	Name   string
-	Sets   []CreateTemplateSetInput
+	ID         string
+	UserID     string
+	Name       string
+	Sets       []CreateTemplateSetInput
+	Visibility Visibility // Optional: keeps the current visibility when empty
+}
+
+// ExerciseSet is a single performed set of an exercise, either a standalone
+// gym entry or a set of a workout
+type ExerciseSet struct {
+	Exercise string
+	WeightKg float64
+	Reps     int
+	Date     time.Time
+}
+
+// PersonalRecord holds the best results of a user for one exercise.
+// Estimated one-rep maxes are nil until the exercise has a weighted set;
+// OneRepMaxDate is the date of the best Epley estimate.
+type PersonalRecord struct {
+	Exercise         string
+	MaxWeightKg      float64
+	MaxWeightReps    int
+	MaxWeightDate    time.Time
+	MaxReps          int
+	MaxRepsDate      time.Time
+	EpleyOneRepMax   *float64
+	BrzyckiOneRepMax *float64
+	OneRepMaxDate    *time.Time
+}
+
+// RecordKind identifies which personal record was beaten
+type RecordKind string
+
+const (
+	RecordMaxWeight RecordKind = "max_weight"
+	RecordMaxReps   RecordKind = "max_reps"
+	RecordOneRepMax RecordKind = "estimated_1rm"
+)
+
+// Record event sources
+const (
+	RecordSourceGymEntry = "gym_entry"
+	RecordSourceWorkout  = "workout"
+)
+
+// PersonalRecordEvent is emitted when a new set beats a previous personal
+// record. Clients poll these to show "new PR" notifications.
+type PersonalRecordEvent struct {
+	ID            string    `gorm:"type:uuid;primaryKey"`
+	UserID        string    `gorm:"type:uuid;index;not null"`
+	Exercise      string    `gorm:"not null"`
+	Kind          string    `gorm:"type:varchar(32);not null"`
+	Value         float64   `gorm:"type:numeric(10,2);not null"`
+	PreviousValue float64   `gorm:"type:numeric(10,2);not null"`
+	AchievedOn    time.Time `gorm:"type:date;not null"`
+	SourceType    string    `gorm:"type:varchar(32);not null"`
+	SourceID      string    `gorm:"type:uuid;not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (PersonalRecordEvent) TableName() string {
+	return "gym_personal_record_events"
+}
+
+// SessionStatus is the state of a live workout session
+type SessionStatus string
+
+const (
+	SessionStatusActive    SessionStatus = "active"
+	SessionStatusFinished  SessionStatus = "finished"
+	SessionStatusCancelled SessionStatus = "cancelled"
+)
+
+// Session is a workout in progress. Sets are appended while training and the
+// session is converted into a Workout when finished. A user has at most one
+// active session.
+type Session struct {
+	ID            string        `gorm:"type:uuid;primaryKey"`
+	UserID        string        `gorm:"type:uuid;index;not null"`
+	Name          string        `gorm:"not null"`
+	Date          time.Time     `gorm:"type:date;not null"`
+	Status        SessionStatus `gorm:"type:varchar(16);not null"`
+	RestSeconds   int           `gorm:"not null;default:0"`
+	RestStartedAt *time.Time
+	RestEndsAt    *time.Time
+	StartedAt     time.Time `gorm:"not null"`
+	FinishedAt    *time.Time
+	WorkoutID     *string   `gorm:"type:uuid"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Session) TableName() string {
+	return "gym_sessions"
+}
+
+// ElapsedSeconds returns the training time of the session, frozen once the
+// session is no longer active.
+func (s Session) ElapsedSeconds(now time.Time) int64 {
+	end := now
+	if s.FinishedAt != nil {
+		end = *s.FinishedAt
+	}
+	if end.Before(s.StartedAt) {
+		return 0
+	}
+	return int64(end.Sub(s.StartedAt) / time.Second)
+}
+
+// RestRemainingSeconds returns how much of the current rest timer is left,
+// or zero when no rest is running.
+func (s Session) RestRemainingSeconds(now time.Time) int64 {
+	if s.Status != SessionStatusActive || s.RestEndsAt == nil || !now.Before(*s.RestEndsAt) {
+		return 0
+	}
+	return int64((s.RestEndsAt.Sub(now) + time.Second - 1) / time.Second)
+}
+
+// SessionSet is a set performed during a session
+type SessionSet struct {
+	ID          string    `gorm:"type:uuid;primaryKey"`
+	SessionID   string    `gorm:"type:uuid;index;not null"`
+	Exercise    string    `gorm:"not null"`
+	WeightKg    float64   `gorm:"type:numeric(8,2);not null"`
+	Reps        int       `gorm:"not null"`
+	SetOrder    int       `gorm:"not null;default:0"`
+	CompletedAt time.Time `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (SessionSet) TableName() string {
+	return "gym_session_sets"
+}
+
+// SessionWithSets combines Session with its sets
+type SessionWithSets struct {
+	Session
+	Sets []SessionSet
+}
+
+// StartSessionInput represents input for starting a workout session
+type StartSessionInput struct {
+	UserID      string
+	Name        string
+	Date        *time.Time // Optional: defaults to the start date
+	RestSeconds int
+}
+
+// UpdateSessionInput appends sets to an active session. Nil fields are left
+// unchanged. Appending sets starts the rest timer when RestSeconds is set.
+type UpdateSessionInput struct {
+	ID          string
+	UserID      string
+	Name        *string
+	RestSeconds *int
+	Sets        []CreateWorkoutSetInput
 }
@@ -1,19 +1,22 @@
 package gym
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Transaction(ctx context.Context, fn func(Repository) error) error
 
 	// GymEntry operations
-	ListGymEntries(ctx context.Context, userID string, filter ListFilter) ([]GymEntry, int64, error)
+	ListGymEntries(ctx context.Context, userIDs []string, filter ListFilter) ([]GymEntry, int64, error)
 	GetGymEntryByID(ctx context.Context, userID, entryID string) (*GymEntry, error)
 	CreateGymEntry(ctx context.Context, entry *GymEntry) error
 	UpdateGymEntry(ctx context.Context, entry *GymEntry) error
 	DeleteGymEntry(ctx context.Context, userID, entryID string) (bool, error)
 
 	// Workout operations
-	ListWorkouts(ctx context.Context, userID string, filter ListFilter) ([]Workout, int64, error)
+	ListWorkouts(ctx context.Context, userIDs []string, filter ListFilter) ([]Workout, int64, error)
 	GetWorkoutByID(ctx context.Context, userID, workoutID string) (*Workout, error)
 	CreateWorkout(ctx context.Context, workout *Workout) error
 	UpdateWorkout(ctx context.Context, workout *Workout) error
@@ -24,8 +27,9 @@ type Repository interface {
 	ReplaceWorkoutSets(ctx context.Context, workoutID string, sets []WorkoutSet) error
 
 	// WorkoutTemplate operations
-	ListTemplates(ctx context.Context, userID string) ([]WorkoutTemplate, error)
+	ListTemplates(ctx context.Context, userID string, sharedFrom []string) ([]WorkoutTemplate, error)
 	GetTemplateByID(ctx context.Context, userID, templateID string) (*WorkoutTemplate, error)
+	GetSharedTemplateByID(ctx context.Context, userIDs []string, templateID string) (*WorkoutTemplate, error)
 	CreateTemplate(ctx context.Context, template *WorkoutTemplate) error
 	UpdateTemplate(ctx context.Context, template *WorkoutTemplate) error
 	DeleteTemplate(ctx context.Context, userID, templateID string) (bool, error)
@@ -34,6 +38,30 @@ type Repository interface {
 	GetSetsByTemplateIDs(ctx context.Context, templateIDs []string) (map[string][]TemplateSet, error)
 	ReplaceTemplateSets(ctx context.Context, templateID string, sets []TemplateSet) error
 
-	// Exercise list
-	ListExercises(ctx context.Context, userID string) ([]string, error)
+	// Session operations
+	GetActiveSession(ctx context.Context, userID string) (*Session, error)
+	GetSessionByID(ctx context.Context, userID, sessionID string) (*Session, error)
+	LockSession(ctx context.Context, userID, sessionID string) (*Session, error)
+	CreateSession(ctx context.Context, session *Session) error
+	UpdateSession(ctx context.Context, session *Session) error
+	ListSessionSets(ctx context.Context, sessionID string) ([]SessionSet, error)
+	AppendSessionSets(ctx context.Context, sets []SessionSet) error
+
+	// Exercise list. viewerID, when set, leaves out other users' entries and
+	// workouts not shared with the family.
+	ListExercises(ctx context.Context, userIDs []string, viewerID string) ([]string, error)
+
+	// Personal records
+	ListExerciseSets(ctx context.Context, userID string, exercises []string) ([]ExerciseSet, error)
+	CreatePersonalRecordEvents(ctx context.Context, events []PersonalRecordEvent) error
+	ListPersonalRecordEvents(ctx context.Context, userID string, limit int) ([]PersonalRecordEvent, error)
+
+	// Goals and streaks
+	GetGoal(ctx context.Context, userID string) (*Goal, error)
+	UpsertGoal(ctx context.Context, goal *Goal) error
+	DeleteGoal(ctx context.Context, userID string) (bool, error)
+	ListActivityDays(ctx context.Context, userID string, from time.Time) ([]ActivityDay, error)
+	ListGoalsWithoutNudge(ctx context.Context, weekStart time.Time, limit int) ([]Goal, error)
+	GetGoalNudge(ctx context.Context, userID string, weekStart time.Time) (*GoalNudge, bool, error)
+	CreateGoalNudge(ctx context.Context, nudge *GoalNudge) error
 }
@@ -2,46 +2,123 @@ package gym
 
 import (
 	"context"
-	"crypto/rand"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
 )
 
+// MemberProvider lists the members of the caller's family for family-scoped
+// reads.
+type MemberProvider interface {
+	ListMembers(ctx context.Context, userID string) ([]familydomain.FamilyMember, error)
+}
+
 type Service struct {
-	repo Repository
+	repo        Repository
+	members     MemberProvider
+	nudgeBefore time.Duration
+	now         func() time.Time
+}
+
+// ServiceOptions configures goal nudges. NudgeBefore is how long before the
+// end of the week users below their goal are nudged; RunNudges is driven by
+// the background job runner.
+type ServiceOptions struct {
+	NudgeBefore time.Duration
 }
 
 func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+	return NewServiceWithMembers(repo, nil)
+}
+
+func NewServiceWithMembers(repo Repository, members MemberProvider) *Service {
+	return NewServiceWithOptions(repo, members, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, members MemberProvider, options ServiceOptions) *Service {
+	nudgeBefore := options.NudgeBefore
+	if nudgeBefore <= 0 {
+		nudgeBefore = defaultNudgeBefore
+	}
+
+	return &Service{
+		repo:        repo,
+		members:     members,
+		nudgeBefore: nudgeBefore,
+		now:         time.Now,
+	}
 }
 
 // GymEntry operations
 
-func (s *Service) ListGymEntries(ctx context.Context, userID string, filter ListFilter) ([]GymEntry, int64, error) {
-	return s.repo.ListGymEntries(ctx, userID, filter)
+// ListGymEntries returns the caller's gym entries, or with family scope also
+// the entries other members shared with the family.
+func (s *Service) ListGymEntries(ctx context.Context, scope Scope, filter ListFilter) ([]GymEntry, int64, error) {
+	ctx, span := tracing.Start(ctx, "gym.ListGymEntries")
+	defer span.End()
+
+	userIDs, err := s.scopeUserIDs(ctx, scope)
+	if err != nil {
+		return nil, 0, err
+	}
+	if scope.Kind == ScopeFamily {
+		filter.ViewerID = scope.UserID
+	}
+	return s.repo.ListGymEntries(ctx, userIDs, filter)
 }
 
 func (s *Service) CreateGymEntry(ctx context.Context, input CreateGymEntryInput) (*GymEntry, error) {
+	ctx, span := tracing.Start(ctx, "gym.CreateGymEntry")
+	defer span.End()
+
 	if err := s.validateGymEntryInput(input.Exercise); err != nil {
 		return nil, err
 	}
+	visibility, err := normalizeVisibility(input.Visibility, VisibilityPrivate)
+	if err != nil {
+		return nil, err
+	}
 
-	entryID, err := newUUID()
+	entryID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
 
 	entry := GymEntry{
-		ID:       entryID,
-		UserID:   input.UserID,
-		Date:     input.Date,
-		Exercise: strings.TrimSpace(input.Exercise),
-		WeightKg: input.WeightKg,
-		Reps:     input.Reps,
+		ID:         entryID,
+		UserID:     input.UserID,
+		Date:       input.Date,
+		Exercise:   strings.TrimSpace(input.Exercise),
+		WeightKg:   input.WeightKg,
+		Reps:       input.Reps,
+		Visibility: visibility,
 	}
 
-	if err := s.repo.CreateGymEntry(ctx, &entry); err != nil {
+	err = s.repo.Transaction(ctx, func(tx Repository) error {
+		events, err := detectRecordEvents(ctx, tx, entry.UserID, RecordSourceGymEntry, entry.ID, []ExerciseSet{{
+			Exercise: entry.Exercise,
+			WeightKg: entry.WeightKg,
+			Reps:     entry.Reps,
+			Date:     entry.Date,
+		}})
+		if err != nil {
+			return err
+		}
+
+		if err := tx.CreateGymEntry(ctx, &entry); err != nil {
+			return err
+		}
+
+		return createRecordEvents(ctx, tx, events)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -49,9 +126,15 @@ func (s *Service) CreateGymEntry(ctx context.Context, input CreateGymEntryInput)
 }
 
 func (s *Service) UpdateGymEntry(ctx context.Context, input UpdateGymEntryInput) (*GymEntry, error) {
+	ctx, span := tracing.Start(ctx, "gym.UpdateGymEntry")
+	defer span.End()
+
 	if err := s.validateGymEntryInput(input.Exercise); err != nil {
 		return nil, err
 	}
+	if _, err := normalizeVisibility(input.Visibility, VisibilityPrivate); err != nil {
+		return nil, err
+	}
 
 	entry, err := s.repo.GetGymEntryByID(ctx, input.UserID, input.ID)
 	if err != nil {
@@ -62,6 +145,9 @@ func (s *Service) UpdateGymEntry(ctx context.Context, input UpdateGymEntryInput)
 	entry.Exercise = strings.TrimSpace(input.Exercise)
 	entry.WeightKg = input.WeightKg
 	entry.Reps = input.Reps
+	if input.Visibility != "" {
+		entry.Visibility = input.Visibility
+	}
 	entry.UpdatedAt = time.Now().UTC()
 
 	if err := s.repo.UpdateGymEntry(ctx, entry); err != nil {
@@ -72,6 +158,9 @@ func (s *Service) UpdateGymEntry(ctx context.Context, input UpdateGymEntryInput)
 }
 
 func (s *Service) DeleteGymEntry(ctx context.Context, userID, entryID string) error {
+	ctx, span := tracing.Start(ctx, "gym.DeleteGymEntry")
+	defer span.End()
+
 	deleted, err := s.repo.DeleteGymEntry(ctx, userID, entryID)
 	if err != nil {
 		return err
@@ -84,8 +173,56 @@ func (s *Service) DeleteGymEntry(ctx context.Context, userID, entryID string) er
 
 // Workout operations
 
-func (s *Service) ListWorkouts(ctx context.Context, userID string, filter ListFilter) ([]WorkoutWithSets, int64, error) {
-	workouts, total, err := s.repo.ListWorkouts(ctx, userID, filter)
+// ListWorkouts returns the caller's workouts, or with family scope also the
+// workouts other members shared with the family.
+func (s *Service) ListWorkouts(ctx context.Context, scope Scope, filter ListFilter) ([]WorkoutWithSets, int64, error) {
+	ctx, span := tracing.Start(ctx, "gym.ListWorkouts")
+	defer span.End()
+
+	userIDs, err := s.scopeUserIDs(ctx, scope)
+	if err != nil {
+		return nil, 0, err
+	}
+	if scope.Kind == ScopeFamily {
+		filter.ViewerID = scope.UserID
+	}
+
+	return s.listWorkouts(ctx, userIDs, filter)
+}
+
+// SearchWorkouts returns workouts in scope whose name or an exercise contains
+// query, newest first.
+func (s *Service) SearchWorkouts(ctx context.Context, scope Scope, query string, limit int) ([]WorkoutWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.SearchWorkouts")
+	defer span.End()
+
+	workouts, _, err := s.ListWorkouts(ctx, scope, ListFilter{Query: query, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	return workouts, nil
+}
+
+// ListFamilyFeed returns recent workouts shared with the family by any member,
+// including the caller.
+func (s *Service) ListFamilyFeed(ctx context.Context, scope Scope, filter ListFilter) ([]WorkoutWithSets, int64, error) {
+	ctx, span := tracing.Start(ctx, "gym.ListFamilyFeed")
+	defer span.End()
+
+	if scope.Kind != ScopeFamily {
+		return nil, 0, ErrInvalidScope
+	}
+	userIDs, err := s.scopeUserIDs(ctx, scope)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.SharedOnly = true
+
+	return s.listWorkouts(ctx, userIDs, filter)
+}
+
+func (s *Service) listWorkouts(ctx context.Context, userIDs []string, filter ListFilter) ([]WorkoutWithSets, int64, error) {
+	workouts, total, err := s.repo.ListWorkouts(ctx, userIDs, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -116,6 +253,9 @@ func (s *Service) ListWorkouts(ctx context.Context, userID string, filter ListFi
 }
 
 func (s *Service) GetWorkoutByID(ctx context.Context, userID, workoutID string) (*WorkoutWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.GetWorkoutByID")
+	defer span.End()
+
 	workout, err := s.repo.GetWorkoutByID(ctx, userID, workoutID)
 	if err != nil {
 		return nil, err
@@ -133,27 +273,35 @@ func (s *Service) GetWorkoutByID(ctx context.Context, userID, workoutID string)
 }
 
 func (s *Service) CreateWorkout(ctx context.Context, input CreateWorkoutInput) (*WorkoutWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.CreateWorkout")
+	defer span.End()
+
 	if err := s.validateWorkoutInput(input.Name); err != nil {
 		return nil, err
 	}
+	visibility, err := normalizeVisibility(input.Visibility, VisibilityPrivate)
+	if err != nil {
+		return nil, err
+	}
 
-	workoutID, err := newUUID()
+	workoutID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
 
 	workout := Workout{
-		ID:     workoutID,
-		UserID: input.UserID,
-		Date:   input.Date,
-		Name:   strings.TrimSpace(input.Name),
+		ID:         workoutID,
+		UserID:     input.UserID,
+		Date:       input.Date,
+		Name:       strings.TrimSpace(input.Name),
+		Visibility: visibility,
 	}
 
 	// If template_id is provided, load template sets
 	setsInput := input.Sets
 	if input.TemplateID != "" {
-		// Verify template exists and belongs to user
-		_, err := s.repo.GetTemplateByID(ctx, input.UserID, input.TemplateID)
+		// Verify template belongs to user or is shared with the user's family
+		_, err := s.templateForUser(ctx, input.UserID, input.TemplateID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load template: %w", err)
 		}
@@ -180,7 +328,7 @@ func (s *Service) CreateWorkout(ctx context.Context, input CreateWorkoutInput) (
 			return nil, err
 		}
 
-		setID, err := newUUID()
+		setID, err := id.New()
 		if err != nil {
 			return nil, err
 		}
@@ -196,17 +344,7 @@ func (s *Service) CreateWorkout(ctx context.Context, input CreateWorkoutInput) (
 	}
 
 	err = s.repo.Transaction(ctx, func(tx Repository) error {
-		if err := tx.CreateWorkout(ctx, &workout); err != nil {
-			return err
-		}
-
-		if len(sets) > 0 {
-			if err := tx.ReplaceWorkoutSets(ctx, workout.ID, sets); err != nil {
-				return err
-			}
-		}
-
-		return nil
+		return storeWorkout(ctx, tx, &workout, sets)
 	})
 	if err != nil {
 		return nil, err
@@ -215,10 +353,47 @@ func (s *Service) CreateWorkout(ctx context.Context, input CreateWorkoutInput) (
 	return &WorkoutWithSets{Workout: workout, Sets: sets}, nil
 }
 
+// storeWorkout persists a new workout with its sets and records any personal
+// records the sets beat.
+func storeWorkout(ctx context.Context, tx Repository, workout *Workout, sets []WorkoutSet) error {
+	performed := make([]ExerciseSet, 0, len(sets))
+	for _, set := range sets {
+		performed = append(performed, ExerciseSet{
+			Exercise: set.Exercise,
+			WeightKg: set.WeightKg,
+			Reps:     set.Reps,
+			Date:     workout.Date,
+		})
+	}
+
+	events, err := detectRecordEvents(ctx, tx, workout.UserID, RecordSourceWorkout, workout.ID, performed)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.CreateWorkout(ctx, workout); err != nil {
+		return err
+	}
+
+	if len(sets) > 0 {
+		if err := tx.ReplaceWorkoutSets(ctx, workout.ID, sets); err != nil {
+			return err
+		}
+	}
+
+	return createRecordEvents(ctx, tx, events)
+}
+
 func (s *Service) UpdateWorkout(ctx context.Context, input UpdateWorkoutInput) (*WorkoutWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.UpdateWorkout")
+	defer span.End()
+
 	if err := s.validateWorkoutInput(input.Name); err != nil {
 		return nil, err
 	}
+	if _, err := normalizeVisibility(input.Visibility, VisibilityPrivate); err != nil {
+		return nil, err
+	}
 
 	var updated Workout
 	var updatedSets []WorkoutSet
@@ -231,6 +406,9 @@ func (s *Service) UpdateWorkout(ctx context.Context, input UpdateWorkoutInput) (
 
 		workout.Date = input.Date
 		workout.Name = strings.TrimSpace(input.Name)
+		if input.Visibility != "" {
+			workout.Visibility = input.Visibility
+		}
 		workout.UpdatedAt = time.Now().UTC()
 
 		if err := tx.UpdateWorkout(ctx, workout); err != nil {
@@ -243,7 +421,7 @@ func (s *Service) UpdateWorkout(ctx context.Context, input UpdateWorkoutInput) (
 				return err
 			}
 
-			setID, err := newUUID()
+			setID, err := id.New()
 			if err != nil {
 				return err
 			}
@@ -274,6 +452,9 @@ func (s *Service) UpdateWorkout(ctx context.Context, input UpdateWorkoutInput) (
 }
 
 func (s *Service) DeleteWorkout(ctx context.Context, userID, workoutID string) error {
+	ctx, span := tracing.Start(ctx, "gym.DeleteWorkout")
+	defer span.End()
+
 	deleted, err := s.repo.DeleteWorkout(ctx, userID, workoutID)
 	if err != nil {
 		return err
@@ -286,8 +467,26 @@ func (s *Service) DeleteWorkout(ctx context.Context, userID, workoutID string) e
 
 // WorkoutTemplate operations
 
-func (s *Service) ListTemplates(ctx context.Context, userID string) ([]TemplateWithSets, error) {
-	templates, err := s.repo.ListTemplates(ctx, userID)
+// ListTemplates returns the caller's templates, or with family scope also the
+// templates other members shared with the family.
+func (s *Service) ListTemplates(ctx context.Context, scope Scope) ([]TemplateWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.ListTemplates")
+	defer span.End()
+
+	var sharedFrom []string
+	if scope.Kind != "" && scope.Kind != ScopeMe {
+		userIDs, err := s.scopeUserIDs(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range userIDs {
+			if userID != scope.UserID {
+				sharedFrom = append(sharedFrom, userID)
+			}
+		}
+	}
+
+	templates, err := s.repo.ListTemplates(ctx, scope.UserID, sharedFrom)
 	if err != nil {
 		return nil, err
 	}
@@ -318,6 +517,9 @@ func (s *Service) ListTemplates(ctx context.Context, userID string) ([]TemplateW
 }
 
 func (s *Service) GetTemplateByID(ctx context.Context, userID, templateID string) (*TemplateWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.GetTemplateByID")
+	defer span.End()
+
 	template, err := s.repo.GetTemplateByID(ctx, userID, templateID)
 	if err != nil {
 		return nil, err
@@ -335,19 +537,27 @@ func (s *Service) GetTemplateByID(ctx context.Context, userID, templateID string
 }
 
 func (s *Service) CreateTemplate(ctx context.Context, input CreateTemplateInput) (*TemplateWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.CreateTemplate")
+	defer span.End()
+
 	if err := s.validateTemplateName(input.Name); err != nil {
 		return nil, err
 	}
+	visibility, err := normalizeVisibility(input.Visibility, VisibilityPrivate)
+	if err != nil {
+		return nil, err
+	}
 
-	templateID, err := newUUID()
+	templateID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
 
 	template := WorkoutTemplate{
-		ID:     templateID,
-		UserID: input.UserID,
-		Name:   strings.TrimSpace(input.Name),
+		ID:         templateID,
+		UserID:     input.UserID,
+		Name:       strings.TrimSpace(input.Name),
+		Visibility: visibility,
 	}
 
 	sets := make([]TemplateSet, 0, len(input.Sets))
@@ -356,7 +566,7 @@ func (s *Service) CreateTemplate(ctx context.Context, input CreateTemplateInput)
 			return nil, err
 		}
 
-		setID, err := newUUID()
+		setID, err := id.New()
 		if err != nil {
 			return nil, err
 		}
@@ -392,9 +602,15 @@ func (s *Service) CreateTemplate(ctx context.Context, input CreateTemplateInput)
 }
 
 func (s *Service) UpdateTemplate(ctx context.Context, input UpdateTemplateInput) (*TemplateWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.UpdateTemplate")
+	defer span.End()
+
 	if err := s.validateTemplateName(input.Name); err != nil {
 		return nil, err
 	}
+	if _, err := normalizeVisibility(input.Visibility, VisibilityPrivate); err != nil {
+		return nil, err
+	}
 
 	var updated WorkoutTemplate
 	var updatedSets []TemplateSet
@@ -406,6 +622,9 @@ func (s *Service) UpdateTemplate(ctx context.Context, input UpdateTemplateInput)
 		}
 
 		template.Name = strings.TrimSpace(input.Name)
+		if input.Visibility != "" {
+			template.Visibility = input.Visibility
+		}
 		template.UpdatedAt = time.Now().UTC()
 
 		if err := tx.UpdateTemplate(ctx, template); err != nil {
@@ -418,7 +637,7 @@ func (s *Service) UpdateTemplate(ctx context.Context, input UpdateTemplateInput)
 				return err
 			}
 
-			setID, err := newUUID()
+			setID, err := id.New()
 			if err != nil {
 				return err
 			}
@@ -449,6 +668,9 @@ func (s *Service) UpdateTemplate(ctx context.Context, input UpdateTemplateInput)
 }
 
 func (s *Service) DeleteTemplate(ctx context.Context, userID, templateID string) error {
+	ctx, span := tracing.Start(ctx, "gym.DeleteTemplate")
+	defer span.End()
+
 	deleted, err := s.repo.DeleteTemplate(ctx, userID, templateID)
 	if err != nil {
 		return err
@@ -461,12 +683,557 @@ func (s *Service) DeleteTemplate(ctx context.Context, userID, templateID string)
 
 // Exercise list
 
-func (s *Service) ListExercises(ctx context.Context, userID string) ([]string, error) {
-	return s.repo.ListExercises(ctx, userID)
+// ListExercises returns the exercises of the caller's gym entries and
+// workouts, or with family scope also of those other members shared with the
+// family.
+func (s *Service) ListExercises(ctx context.Context, scope Scope) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "gym.ListExercises")
+	defer span.End()
+
+	userIDs, err := s.scopeUserIDs(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	viewerID := ""
+	if scope.Kind == ScopeFamily {
+		viewerID = scope.UserID
+	}
+	return s.repo.ListExercises(ctx, userIDs, viewerID)
+}
+
+// Session operations
+
+// MaxRestSeconds caps the rest timer at one hour.
+const MaxRestSeconds = 60 * 60
+
+func (s *Service) StartSession(ctx context.Context, input StartSessionInput) (*SessionWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.StartSession")
+	defer span.End()
+
+	if err := s.validateWorkoutInput(input.Name); err != nil {
+		return nil, err
+	}
+	if err := validateRestSeconds(input.RestSeconds); err != nil {
+		return nil, err
+	}
+
+	sessionID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if input.Date != nil {
+		date = *input.Date
+	}
+
+	session := Session{
+		ID:          sessionID,
+		UserID:      input.UserID,
+		Name:        strings.TrimSpace(input.Name),
+		Date:        date,
+		Status:      SessionStatusActive,
+		RestSeconds: input.RestSeconds,
+		StartedAt:   now,
+	}
+
+	err = s.repo.Transaction(ctx, func(tx Repository) error {
+		if _, err := tx.GetActiveSession(ctx, input.UserID); err == nil {
+			return ErrSessionAlreadyActive
+		} else if !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+		return tx.CreateSession(ctx, &session)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionWithSets{Session: session, Sets: []SessionSet{}}, nil
+}
+
+func (s *Service) GetActiveSession(ctx context.Context, userID string) (*SessionWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.GetActiveSession")
+	defer span.End()
+
+	session, err := s.repo.GetActiveSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withSessionSets(ctx, session)
+}
+
+func (s *Service) GetSession(ctx context.Context, userID, sessionID string) (*SessionWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.GetSession")
+	defer span.End()
+
+	session, err := s.repo.GetSessionByID(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withSessionSets(ctx, session)
+}
+
+// UpdateSession appends sets to an active session and restarts the rest
+// timer from the time the sets were received.
+func (s *Service) UpdateSession(ctx context.Context, input UpdateSessionInput) (*SessionWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.UpdateSession")
+	defer span.End()
+
+	if input.Name != nil {
+		if err := s.validateWorkoutInput(*input.Name); err != nil {
+			return nil, err
+		}
+	}
+	if input.RestSeconds != nil {
+		if err := validateRestSeconds(*input.RestSeconds); err != nil {
+			return nil, err
+		}
+	}
+	for _, set := range input.Sets {
+		if err := s.validateGymEntryInput(set.Exercise); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *SessionWithSets
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		session, err := tx.LockSession(ctx, input.UserID, input.ID)
+		if err != nil {
+			return err
+		}
+		if session.Status != SessionStatusActive {
+			return ErrSessionNotActive
+		}
+
+		existing, err := tx.ListSessionSets(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		now := s.now().UTC()
+		if input.Name != nil {
+			session.Name = strings.TrimSpace(*input.Name)
+		}
+		if input.RestSeconds != nil {
+			session.RestSeconds = *input.RestSeconds
+		}
+
+		appended := make([]SessionSet, 0, len(input.Sets))
+		for i, setInput := range input.Sets {
+			setID, err := id.New()
+			if err != nil {
+				return err
+			}
+			appended = append(appended, SessionSet{
+				ID:          setID,
+				SessionID:   session.ID,
+				Exercise:    strings.TrimSpace(setInput.Exercise),
+				WeightKg:    setInput.WeightKg,
+				Reps:        setInput.Reps,
+				SetOrder:    len(existing) + i,
+				CompletedAt: now,
+			})
+		}
+		if len(appended) > 0 {
+			if err := tx.AppendSessionSets(ctx, appended); err != nil {
+				return err
+			}
+			session.RestStartedAt = nil
+			session.RestEndsAt = nil
+			if session.RestSeconds > 0 {
+				endsAt := now.Add(time.Duration(session.RestSeconds) * time.Second)
+				session.RestStartedAt = &now
+				session.RestEndsAt = &endsAt
+			}
+		}
+
+		session.UpdatedAt = now
+		if err := tx.UpdateSession(ctx, session); err != nil {
+			return err
+		}
+
+		result = &SessionWithSets{Session: *session, Sets: append(existing, appended...)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FinishSession converts an active session into a Workout with the session's
+// sets. The session keeps a reference to the created workout.
+func (s *Service) FinishSession(ctx context.Context, userID, sessionID string) (*SessionWithSets, *WorkoutWithSets, error) {
+	ctx, span := tracing.Start(ctx, "gym.FinishSession")
+	defer span.End()
+
+	var finished *SessionWithSets
+	var created *WorkoutWithSets
+
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		session, err := tx.LockSession(ctx, userID, sessionID)
+		if err != nil {
+			return err
+		}
+		if session.Status != SessionStatusActive {
+			return ErrSessionNotActive
+		}
+
+		sessionSets, err := tx.ListSessionSets(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+		if len(sessionSets) == 0 {
+			return ErrSessionEmpty
+		}
+
+		workoutID, err := id.New()
+		if err != nil {
+			return err
+		}
+		workout := Workout{
+			ID:         workoutID,
+			UserID:     session.UserID,
+			Date:       session.Date,
+			Name:       session.Name,
+			Visibility: VisibilityPrivate,
+		}
+
+		sets := make([]WorkoutSet, 0, len(sessionSets))
+		for i, sessionSet := range sessionSets {
+			setID, err := id.New()
+			if err != nil {
+				return err
+			}
+			sets = append(sets, WorkoutSet{
+				ID:        setID,
+				WorkoutID: workoutID,
+				Exercise:  sessionSet.Exercise,
+				WeightKg:  sessionSet.WeightKg,
+				Reps:      sessionSet.Reps,
+				SetOrder:  i,
+			})
+		}
+
+		if err := storeWorkout(ctx, tx, &workout, sets); err != nil {
+			return err
+		}
+
+		now := s.now().UTC()
+		session.Status = SessionStatusFinished
+		session.FinishedAt = &now
+		session.WorkoutID = &workout.ID
+		session.RestStartedAt = nil
+		session.RestEndsAt = nil
+		session.UpdatedAt = now
+		if err := tx.UpdateSession(ctx, session); err != nil {
+			return err
+		}
+
+		finished = &SessionWithSets{Session: *session, Sets: sessionSets}
+		created = &WorkoutWithSets{Workout: workout, Sets: sets}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return finished, created, nil
+}
+
+// CancelSession abandons an active session without creating a workout.
+func (s *Service) CancelSession(ctx context.Context, userID, sessionID string) error {
+	ctx, span := tracing.Start(ctx, "gym.CancelSession")
+	defer span.End()
+
+	return s.repo.Transaction(ctx, func(tx Repository) error {
+		session, err := tx.LockSession(ctx, userID, sessionID)
+		if err != nil {
+			return err
+		}
+		if session.Status != SessionStatusActive {
+			return ErrSessionNotActive
+		}
+
+		now := s.now().UTC()
+		session.Status = SessionStatusCancelled
+		session.FinishedAt = &now
+		session.RestStartedAt = nil
+		session.RestEndsAt = nil
+		session.UpdatedAt = now
+		return tx.UpdateSession(ctx, session)
+	})
+}
+
+func (s *Service) withSessionSets(ctx context.Context, session *Session) (*SessionWithSets, error) {
+	sets, err := s.repo.ListSessionSets(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionWithSets{Session: *session, Sets: sets}, nil
+}
+
+func validateRestSeconds(value int) error {
+	if value < 0 || value > MaxRestSeconds {
+		return ErrInvalidRestSeconds
+	}
+	return nil
+}
+
+// Personal records
+
+const (
+	defaultRecordEventsLimit = 50
+	maxRecordEventsLimit     = 200
+	// brzyckiMaxReps is the rep count at which the Brzycki formula breaks down.
+	brzyckiMaxReps = 37
+)
+
+// ListRecords returns the caller's personal records per exercise, computed
+// from both standalone gym entries and workout sets.
+func (s *Service) ListRecords(ctx context.Context, userID string) ([]PersonalRecord, error) {
+	ctx, span := tracing.Start(ctx, "gym.ListRecords")
+	defer span.End()
+
+	sets, err := s.repo.ListExerciseSets(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	records := computeRecords(sets)
+	result := make([]PersonalRecord, 0, len(records))
+	for _, record := range records {
+		result = append(result, *record)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Exercise < result[j].Exercise
+	})
+	return result, nil
+}
+
+func (s *Service) ListRecordEvents(ctx context.Context, userID string, limit int) ([]PersonalRecordEvent, error) {
+	ctx, span := tracing.Start(ctx, "gym.ListRecordEvents")
+	defer span.End()
+
+	if limit <= 0 {
+		limit = defaultRecordEventsLimit
+	}
+	if limit > maxRecordEventsLimit {
+		limit = maxRecordEventsLimit
+	}
+	return s.repo.ListPersonalRecordEvents(ctx, userID, limit)
+}
+
+// detectRecordEvents compares newly performed sets with the records built from
+// the user's earlier sets. It must run before the new sets are stored. The
+// first set of an exercise establishes a baseline and is not reported as a PR.
+func detectRecordEvents(ctx context.Context, repo Repository, userID, sourceType, sourceID string, performed []ExerciseSet) ([]PersonalRecordEvent, error) {
+	if len(performed) == 0 {
+		return nil, nil
+	}
+
+	exercises := make([]string, 0, len(performed))
+	seen := make(map[string]struct{}, len(performed))
+	for _, set := range performed {
+		if _, ok := seen[set.Exercise]; ok {
+			continue
+		}
+		seen[set.Exercise] = struct{}{}
+		exercises = append(exercises, set.Exercise)
+	}
+
+	previous, err := repo.ListExerciseSets(ctx, userID, exercises)
+	if err != nil {
+		return nil, err
+	}
+
+	before := computeRecords(previous)
+	after := computeRecords(append(append([]ExerciseSet{}, previous...), performed...))
+
+	var events []PersonalRecordEvent
+	add := func(exercise string, kind RecordKind, value, previousValue float64, achievedOn time.Time) error {
+		eventID, err := id.New()
+		if err != nil {
+			return err
+		}
+		events = append(events, PersonalRecordEvent{
+			ID:            eventID,
+			UserID:        userID,
+			Exercise:      exercise,
+			Kind:          string(kind),
+			Value:         value,
+			PreviousValue: previousValue,
+			AchievedOn:    achievedOn,
+			SourceType:    sourceType,
+			SourceID:      sourceID,
+		})
+		return nil
+	}
+
+	for _, exercise := range exercises {
+		old, ok := before[exercise]
+		if !ok {
+			continue
+		}
+		current := after[exercise]
+
+		if current.MaxWeightKg > old.MaxWeightKg {
+			if err := add(exercise, RecordMaxWeight, current.MaxWeightKg, old.MaxWeightKg, current.MaxWeightDate); err != nil {
+				return nil, err
+			}
+		}
+		if current.MaxReps > old.MaxReps {
+			if err := add(exercise, RecordMaxReps, float64(current.MaxReps), float64(old.MaxReps), current.MaxRepsDate); err != nil {
+				return nil, err
+			}
+		}
+		if old.EpleyOneRepMax != nil && *current.EpleyOneRepMax > *old.EpleyOneRepMax {
+			if err := add(exercise, RecordOneRepMax, *current.EpleyOneRepMax, *old.EpleyOneRepMax, *current.OneRepMaxDate); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return events, nil
+}
+
+func createRecordEvents(ctx context.Context, repo Repository, events []PersonalRecordEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return repo.CreatePersonalRecordEvents(ctx, events)
+}
+
+func computeRecords(sets []ExerciseSet) map[string]*PersonalRecord {
+	records := make(map[string]*PersonalRecord)
+	for _, set := range sets {
+		record, ok := records[set.Exercise]
+		if !ok {
+			record = &PersonalRecord{Exercise: set.Exercise}
+			records[set.Exercise] = record
+		}
+
+		if set.WeightKg > record.MaxWeightKg || (set.WeightKg == record.MaxWeightKg && set.Reps > record.MaxWeightReps) {
+			record.MaxWeightKg = set.WeightKg
+			record.MaxWeightReps = set.Reps
+			record.MaxWeightDate = set.Date
+		}
+		if set.Reps > record.MaxReps {
+			record.MaxReps = set.Reps
+			record.MaxRepsDate = set.Date
+		}
+
+		if set.WeightKg <= 0 || set.Reps <= 0 {
+			continue
+		}
+		epley := epleyOneRepMax(set.WeightKg, set.Reps)
+		if record.EpleyOneRepMax == nil || epley > *record.EpleyOneRepMax {
+			date := set.Date
+			record.EpleyOneRepMax = &epley
+			record.OneRepMaxDate = &date
+		}
+		if set.Reps < brzyckiMaxReps {
+			brzycki := brzyckiOneRepMax(set.WeightKg, set.Reps)
+			if record.BrzyckiOneRepMax == nil || brzycki > *record.BrzyckiOneRepMax {
+				record.BrzyckiOneRepMax = &brzycki
+			}
+		}
+	}
+	return records
+}
+
+// epleyOneRepMax estimates 1RM as weight * (1 + reps/30). A single rep is
+// already a 1RM.
+func epleyOneRepMax(weightKg float64, reps int) float64 {
+	if reps == 1 {
+		return roundKg(weightKg)
+	}
+	return roundKg(weightKg * (1 + float64(reps)/30))
+}
+
+// brzyckiOneRepMax estimates 1RM as weight * 36 / (37 - reps).
+func brzyckiOneRepMax(weightKg float64, reps int) float64 {
+	return roundKg(weightKg * 36 / float64(brzyckiMaxReps-reps))
+}
+
+func roundKg(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// scopeUserIDs resolves the users whose records a read may return. Members
+// are re-checked against scope.FamilyID so a stale family lookup cannot widen
+// the result.
+func (s *Service) scopeUserIDs(ctx context.Context, scope Scope) ([]string, error) {
+	switch scope.Kind {
+	case "", ScopeMe:
+		return []string{scope.UserID}, nil
+	case ScopeFamily:
+	default:
+		return nil, ErrInvalidScope
+	}
+
+	if strings.TrimSpace(scope.FamilyID) == "" || s.members == nil {
+		return nil, ErrFamilyRequired
+	}
+
+	members, err := s.members.ListMembers(ctx, scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.FamilyID == scope.FamilyID {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil, ErrFamilyRequired
+	}
+	return userIDs, nil
+}
+
+// templateForUser returns a template the user owns or one another member of
+// the user's family shared with the family.
+func (s *Service) templateForUser(ctx context.Context, userID, templateID string) (*WorkoutTemplate, error) {
+	template, err := s.repo.GetTemplateByID(ctx, userID, templateID)
+	if err == nil || !errors.Is(err, ErrTemplateNotFound) || s.members == nil {
+		return template, err
+	}
+
+	members, err := s.members.ListMembers(ctx, userID)
+	if err != nil {
+		if errors.Is(err, familydomain.ErrFamilyNotFound) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	if len(userIDs) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+	return s.repo.GetSharedTemplateByID(ctx, userIDs, templateID)
 }
 
 // Validation helpers
 
+func normalizeVisibility(value, fallback Visibility) (Visibility, error) {
+	switch value {
+	case "":
+		return fallback, nil
+	case VisibilityPrivate, VisibilityFamily:
+		return value, nil
+	default:
+		return "", ErrInvalidVisibility
+	}
+}
+
 func (s *Service) validateGymEntryInput(exercise string) error {
 	if strings.TrimSpace(exercise) == "" {
 		return fmt.Errorf("exercise is required")
@@ -494,15 +1261,3 @@ func (s *Service) validateTemplateName(name string) error {
 }
 
 // UUID generation
-
-func newUUID() (string, error) {
-	var b [16]byte
-	if _, err := rand.Read(b[:]); err != nil {
-		return "", err
-	}
-
-	b[6] = (b[6] & 0x0f) | 0x40
-	b[8] = (b[8] & 0x3f) | 0x80
-
-	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
-}
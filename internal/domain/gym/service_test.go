@@ -0,0 +1,721 @@
+package gym
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testUserID   = "22222222-2222-2222-2222-222222222222"
+	testMemberID = "33333333-3333-3333-3333-333333333333"
+)
+
+type fakeMemberProvider struct {
+	members []familydomain.FamilyMember
+}
+
+func (p fakeMemberProvider) ListMembers(_ context.Context, _ string) ([]familydomain.FamilyMember, error) {
+	return p.members, nil
+}
+
+type fakeGymRepo struct {
+	Repository
+	listedUserIDs []string
+	listedViewer  string
+	entries       []GymEntry
+	sets          []ExerciseSet
+	events        []PersonalRecordEvent
+	sessions      map[string]*Session
+	sessionSets   map[string][]SessionSet
+	workouts      []Workout
+	lastFilter    ListFilter
+	templates     map[string]WorkoutTemplate
+	templateSets  map[string][]TemplateSet
+	goals         map[string]Goal
+	activity      []ActivityDay
+	nudges        []GoalNudge
+}
+
+func newFakeGymRepo() *fakeGymRepo {
+	return &fakeGymRepo{
+		sessions:    make(map[string]*Session),
+		sessionSets: make(map[string][]SessionSet),
+		goals:       make(map[string]Goal),
+	}
+}
+
+func (r *fakeGymRepo) Transaction(_ context.Context, fn func(Repository) error) error {
+	return fn(r)
+}
+
+func (r *fakeGymRepo) CreateGymEntry(_ context.Context, entry *GymEntry) error {
+	r.sets = append(r.sets, ExerciseSet{
+		Exercise: entry.Exercise,
+		WeightKg: entry.WeightKg,
+		Reps:     entry.Reps,
+		Date:     entry.Date,
+	})
+	return nil
+}
+
+func (r *fakeGymRepo) ListExerciseSets(_ context.Context, _ string, exercises []string) ([]ExerciseSet, error) {
+	if exercises == nil {
+		return append([]ExerciseSet{}, r.sets...), nil
+	}
+	var result []ExerciseSet
+	for _, set := range r.sets {
+		for _, exercise := range exercises {
+			if set.Exercise == exercise {
+				result = append(result, set)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeGymRepo) CreatePersonalRecordEvents(_ context.Context, events []PersonalRecordEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *fakeGymRepo) ListGymEntries(_ context.Context, userIDs []string, filter ListFilter) ([]GymEntry, int64, error) {
+	r.listedUserIDs = userIDs
+	var entries []GymEntry
+	for _, entry := range r.entries {
+		if !slices.Contains(userIDs, entry.UserID) {
+			continue
+		}
+		if filter.ViewerID != "" && entry.UserID != filter.ViewerID && entry.Visibility != VisibilityFamily {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, int64(len(entries)), nil
+}
+
+func (r *fakeGymRepo) ListExercises(_ context.Context, userIDs []string, viewerID string) ([]string, error) {
+	r.listedUserIDs = userIDs
+	r.listedViewer = viewerID
+	return nil, nil
+}
+
+func newFamilyMembers() fakeMemberProvider {
+	return fakeMemberProvider{members: []familydomain.FamilyMember{
+		{FamilyID: testFamilyID, UserID: testUserID},
+		{FamilyID: testFamilyID, UserID: testMemberID},
+	}}
+}
+
+func TestListGymEntriesDefaultsToOwnRecords(t *testing.T) {
+	repo := &fakeGymRepo{}
+	service := NewServiceWithMembers(repo, newFamilyMembers())
+
+	if _, _, err := service.ListGymEntries(context.Background(), Scope{UserID: testUserID}, ListFilter{}); err != nil {
+		t.Fatalf("list gym entries: %v", err)
+	}
+	if !reflect.DeepEqual(repo.listedUserIDs, []string{testUserID}) {
+		t.Fatalf("expected only own records, got %v", repo.listedUserIDs)
+	}
+}
+
+func TestListExercisesFamilyScopeCoversMembers(t *testing.T) {
+	repo := &fakeGymRepo{}
+	service := NewServiceWithMembers(repo, newFamilyMembers())
+
+	_, err := service.ListExercises(context.Background(), Scope{
+		UserID:   testUserID,
+		FamilyID: testFamilyID,
+		Kind:     ScopeFamily,
+	})
+	if err != nil {
+		t.Fatalf("list exercises: %v", err)
+	}
+	if !reflect.DeepEqual(repo.listedUserIDs, []string{testUserID, testMemberID}) {
+		t.Fatalf("expected family members, got %v", repo.listedUserIDs)
+	}
+	if repo.listedViewer != testUserID {
+		t.Fatalf("expected exercises limited to shared records, got viewer %q", repo.listedViewer)
+	}
+}
+
+func TestListGymEntriesFamilyScopeLeavesOutUnsharedEntries(t *testing.T) {
+	const otherMemberID = "55555555-5555-5555-5555-555555555555"
+	repo := &fakeGymRepo{entries: []GymEntry{
+		{ID: "own", UserID: testUserID, Visibility: VisibilityPrivate},
+		{ID: "shared", UserID: testMemberID, Visibility: VisibilityFamily},
+		{ID: "not-shared", UserID: otherMemberID, Visibility: VisibilityPrivate},
+	}}
+	members := newFamilyMembers()
+	members.members = append(members.members, familydomain.FamilyMember{FamilyID: testFamilyID, UserID: otherMemberID})
+	service := NewServiceWithMembers(repo, members)
+
+	entries, total, err := service.ListGymEntries(context.Background(), Scope{
+		UserID:   testUserID,
+		FamilyID: testFamilyID,
+		Kind:     ScopeFamily,
+	}, ListFilter{})
+	if err != nil {
+		t.Fatalf("list gym entries: %v", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	if total != 2 || !reflect.DeepEqual(ids, []string{"own", "shared"}) {
+		t.Fatalf("expected own and shared entries, got %v (total %d)", ids, total)
+	}
+}
+
+func TestFamilyScopeRequiresFamily(t *testing.T) {
+	service := NewServiceWithMembers(&fakeGymRepo{}, newFamilyMembers())
+
+	_, err := service.ListExercises(context.Background(), Scope{UserID: testUserID, Kind: ScopeFamily})
+	if !errors.Is(err, ErrFamilyRequired) {
+		t.Fatalf("expected ErrFamilyRequired, got %v", err)
+	}
+
+	_, err = service.ListExercises(context.Background(), Scope{
+		UserID:   testUserID,
+		FamilyID: "44444444-4444-4444-4444-444444444444",
+		Kind:     ScopeFamily,
+	})
+	if !errors.Is(err, ErrFamilyRequired) {
+		t.Fatalf("expected ErrFamilyRequired for foreign family, got %v", err)
+	}
+}
+
+func TestInvalidScopeRejected(t *testing.T) {
+	service := NewServiceWithMembers(&fakeGymRepo{}, newFamilyMembers())
+
+	_, err := service.ListExercises(context.Background(), Scope{UserID: testUserID, Kind: "everyone"})
+	if !errors.Is(err, ErrInvalidScope) {
+		t.Fatalf("expected ErrInvalidScope, got %v", err)
+	}
+}
+
+func TestListRecordsEstimatesOneRepMax(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeGymRepo{sets: []ExerciseSet{
+		{Exercise: "Bench", WeightKg: 100, Reps: 1, Date: day},
+		{Exercise: "Bench", WeightKg: 90, Reps: 6, Date: day.AddDate(0, 0, 7)},
+		{Exercise: "Pull-up", WeightKg: 0, Reps: 15, Date: day},
+	}}
+	service := NewService(repo)
+
+	records, err := service.ListRecords(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("list records: %v", err)
+	}
+	if len(records) != 2 || records[0].Exercise != "Bench" || records[1].Exercise != "Pull-up" {
+		t.Fatalf("unexpected records: %#v", records)
+	}
+
+	bench := records[0]
+	if bench.MaxWeightKg != 100 || bench.MaxReps != 6 {
+		t.Fatalf("unexpected bench maxima: %#v", bench)
+	}
+	// Epley: 90 * (1 + 6/30) = 108; Brzycki: 90 * 36 / 31 = 104.52
+	if bench.EpleyOneRepMax == nil || *bench.EpleyOneRepMax != 108 {
+		t.Fatalf("unexpected epley estimate: %v", bench.EpleyOneRepMax)
+	}
+	if bench.BrzyckiOneRepMax == nil || *bench.BrzyckiOneRepMax != 104.52 {
+		t.Fatalf("unexpected brzycki estimate: %v", bench.BrzyckiOneRepMax)
+	}
+	if !bench.OneRepMaxDate.Equal(day.AddDate(0, 0, 7)) {
+		t.Fatalf("unexpected 1rm date: %v", bench.OneRepMaxDate)
+	}
+
+	if records[1].EpleyOneRepMax != nil {
+		t.Fatalf("expected no 1rm estimate for bodyweight sets, got %v", *records[1].EpleyOneRepMax)
+	}
+}
+
+func TestCreateGymEntryEmitsRecordEvents(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeGymRepo{}
+	service := NewService(repo)
+
+	if _, err := service.CreateGymEntry(context.Background(), CreateGymEntryInput{
+		UserID: testUserID, Date: day, Exercise: "Squat", WeightKg: 100, Reps: 5,
+	}); err != nil {
+		t.Fatalf("create first entry: %v", err)
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("first set must not be reported as a record, got %#v", repo.events)
+	}
+
+	entry, err := service.CreateGymEntry(context.Background(), CreateGymEntryInput{
+		UserID: testUserID, Date: day.AddDate(0, 0, 3), Exercise: "Squat", WeightKg: 110, Reps: 3,
+	})
+	if err != nil {
+		t.Fatalf("create second entry: %v", err)
+	}
+
+	kinds := make(map[string]PersonalRecordEvent)
+	for _, event := range repo.events {
+		kinds[event.Kind] = event
+	}
+	if len(kinds) != 2 {
+		t.Fatalf("expected max weight and 1rm events, got %#v", repo.events)
+	}
+	weight, ok := kinds[string(RecordMaxWeight)]
+	if !ok || weight.Value != 110 || weight.PreviousValue != 100 || weight.SourceID != entry.ID {
+		t.Fatalf("unexpected max weight event: %#v", weight)
+	}
+	if _, ok := kinds[string(RecordOneRepMax)]; !ok {
+		t.Fatalf("expected estimated 1rm event, got %#v", repo.events)
+	}
+}
+
+func (r *fakeGymRepo) GetActiveSession(_ context.Context, userID string) (*Session, error) {
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.Status == SessionStatusActive {
+			cloned := *session
+			return &cloned, nil
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+func (r *fakeGymRepo) GetSessionByID(_ context.Context, userID, sessionID string) (*Session, error) {
+	session, ok := r.sessions[sessionID]
+	if !ok || session.UserID != userID {
+		return nil, ErrSessionNotFound
+	}
+	cloned := *session
+	return &cloned, nil
+}
+
+func (r *fakeGymRepo) LockSession(ctx context.Context, userID, sessionID string) (*Session, error) {
+	return r.GetSessionByID(ctx, userID, sessionID)
+}
+
+func (r *fakeGymRepo) CreateSession(_ context.Context, session *Session) error {
+	cloned := *session
+	r.sessions[session.ID] = &cloned
+	return nil
+}
+
+func (r *fakeGymRepo) UpdateSession(_ context.Context, session *Session) error {
+	cloned := *session
+	r.sessions[session.ID] = &cloned
+	return nil
+}
+
+func (r *fakeGymRepo) ListSessionSets(_ context.Context, sessionID string) ([]SessionSet, error) {
+	return append([]SessionSet{}, r.sessionSets[sessionID]...), nil
+}
+
+func (r *fakeGymRepo) AppendSessionSets(_ context.Context, sets []SessionSet) error {
+	for _, set := range sets {
+		r.sessionSets[set.SessionID] = append(r.sessionSets[set.SessionID], set)
+	}
+	return nil
+}
+
+func (r *fakeGymRepo) CreateWorkout(_ context.Context, workout *Workout) error {
+	r.workouts = append(r.workouts, *workout)
+	return nil
+}
+
+func (r *fakeGymRepo) ReplaceWorkoutSets(_ context.Context, _ string, sets []WorkoutSet) error {
+	for _, set := range sets {
+		r.sets = append(r.sets, ExerciseSet{Exercise: set.Exercise, WeightKg: set.WeightKg, Reps: set.Reps})
+	}
+	return nil
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	now := start
+	repo := newFakeGymRepo()
+	service := NewService(repo)
+	service.now = func() time.Time { return now }
+
+	session, err := service.StartSession(context.Background(), StartSessionInput{
+		UserID:      testUserID,
+		Name:        "Push day",
+		RestSeconds: 90,
+	})
+	if err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	if _, err := service.StartSession(context.Background(), StartSessionInput{UserID: testUserID, Name: "Again"}); !errors.Is(err, ErrSessionAlreadyActive) {
+		t.Fatalf("expected ErrSessionAlreadyActive, got %v", err)
+	}
+
+	if _, _, err := service.FinishSession(context.Background(), testUserID, session.ID); !errors.Is(err, ErrSessionEmpty) {
+		t.Fatalf("expected ErrSessionEmpty, got %v", err)
+	}
+
+	now = start.Add(10 * time.Minute)
+	if _, err := service.UpdateSession(context.Background(), UpdateSessionInput{
+		ID:     session.ID,
+		UserID: testUserID,
+		Sets:   []CreateWorkoutSetInput{{Exercise: "Bench", WeightKg: 80, Reps: 8}},
+	}); err != nil {
+		t.Fatalf("update session: %v", err)
+	}
+	now = start.Add(11 * time.Minute)
+	updated, err := service.UpdateSession(context.Background(), UpdateSessionInput{
+		ID:     session.ID,
+		UserID: testUserID,
+		Sets:   []CreateWorkoutSetInput{{Exercise: "Bench", WeightKg: 80, Reps: 7}},
+	})
+	if err != nil {
+		t.Fatalf("update session: %v", err)
+	}
+	if len(updated.Sets) != 2 || updated.Sets[1].SetOrder != 1 {
+		t.Fatalf("expected sets to be appended in order, got %#v", updated.Sets)
+	}
+	if remaining := updated.RestRemainingSeconds(now.Add(30 * time.Second)); remaining != 60 {
+		t.Fatalf("expected 60s of rest left, got %d", remaining)
+	}
+
+	now = start.Add(45 * time.Minute)
+	finished, workout, err := service.FinishSession(context.Background(), testUserID, session.ID)
+	if err != nil {
+		t.Fatalf("finish session: %v", err)
+	}
+	if finished.Status != SessionStatusFinished || finished.WorkoutID == nil || *finished.WorkoutID != workout.ID {
+		t.Fatalf("unexpected finished session: %#v", finished.Session)
+	}
+	if finished.ElapsedSeconds(now.Add(time.Hour)) != 45*60 {
+		t.Fatalf("expected elapsed time to freeze at finish, got %d", finished.ElapsedSeconds(now.Add(time.Hour)))
+	}
+	if workout.Name != "Push day" || len(workout.Sets) != 2 || len(repo.workouts) != 1 {
+		t.Fatalf("unexpected workout: %#v", workout)
+	}
+
+	if _, err := service.UpdateSession(context.Background(), UpdateSessionInput{ID: session.ID, UserID: testUserID}); !errors.Is(err, ErrSessionNotActive) {
+		t.Fatalf("expected ErrSessionNotActive, got %v", err)
+	}
+}
+
+func (r *fakeGymRepo) ListWorkouts(_ context.Context, userIDs []string, filter ListFilter) ([]Workout, int64, error) {
+	r.listedUserIDs = userIDs
+	r.lastFilter = filter
+	return r.workouts, int64(len(r.workouts)), nil
+}
+
+func (r *fakeGymRepo) GetSetsByWorkoutIDs(_ context.Context, _ []string) (map[string][]WorkoutSet, error) {
+	return map[string][]WorkoutSet{}, nil
+}
+
+func (r *fakeGymRepo) GetTemplateByID(_ context.Context, userID, templateID string) (*WorkoutTemplate, error) {
+	template, ok := r.templates[templateID]
+	if !ok || template.UserID != userID {
+		return nil, ErrTemplateNotFound
+	}
+	return &template, nil
+}
+
+func (r *fakeGymRepo) GetSharedTemplateByID(_ context.Context, userIDs []string, templateID string) (*WorkoutTemplate, error) {
+	template, ok := r.templates[templateID]
+	if !ok || template.Visibility != VisibilityFamily {
+		return nil, ErrTemplateNotFound
+	}
+	for _, userID := range userIDs {
+		if template.UserID == userID {
+			return &template, nil
+		}
+	}
+	return nil, ErrTemplateNotFound
+}
+
+func (r *fakeGymRepo) GetSetsByTemplateIDs(_ context.Context, templateIDs []string) (map[string][]TemplateSet, error) {
+	result := make(map[string][]TemplateSet)
+	for _, templateID := range templateIDs {
+		result[templateID] = r.templateSets[templateID]
+	}
+	return result, nil
+}
+
+const strongExport = `Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Reps;Distance;Seconds;Notes;Workout Notes;RPE
+2024-01-15 18:30:00;Push;1h;Bench Press (Barbell);1;135;8;0;0;;;
+2024-01-15 18:30:00;Push;1h;Bench Press (Barbell);Rest Timer;0;0;0;90;;;
+2024-01-15 18:30:00;Push;1h;Bench Press (Barbell);2;145,5;6;0;0;;;
+2024-01-16 07:00:00;Run;30m;Running;1;0;0;5;1800;;;
+2024-01-17 18:00:00;Pull;1h;Deadlift;1;225;x;0;0;;;
+`
+
+func TestImportStrongDryRunReportsRowsWithoutWriting(t *testing.T) {
+	repo := newFakeGymRepo()
+	service := NewService(repo)
+
+	result, err := service.Import(context.Background(), ImportInput{
+		UserID:     testUserID,
+		WeightUnit: WeightUnitLbs,
+		DryRun:     true,
+		Data:       strings.NewReader(strongExport),
+	})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Format != ImportFormatStrong || result.Rows != 5 || result.SkippedRows != 2 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if result.Workouts != 1 || result.WorkoutSets != 2 {
+		t.Fatalf("expected one workout with two sets, got %#v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 6 {
+		t.Fatalf("expected invalid reps on row 6, got %#v", result.Errors)
+	}
+	if len(repo.workouts) != 0 {
+		t.Fatalf("dry run must not store workouts")
+	}
+}
+
+func TestImportHevySkipsExistingWorkouts(t *testing.T) {
+	const hevyExport = "title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,set_index,set_type,weight_kg,reps,distance_km,duration_seconds,rpe\n" +
+		"Legs,\"15 Jan 2024, 18:30\",\"15 Jan 2024, 19:30\",,Squat (Barbell),,,0,normal,100,5,,,\n" +
+		"Legs,\"15 Jan 2024, 18:30\",\"15 Jan 2024, 19:30\",,Squat (Barbell),,,1,normal,102.5,5,,,\n" +
+		"Arms,\"17 Jan 2024, 18:30\",\"17 Jan 2024, 19:00\",,Bicep Curl,,,0,normal,15,12,,,\n"
+
+	repo := newFakeGymRepo()
+	repo.workouts = []Workout{{ID: "existing", UserID: testUserID, Date: time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC), Name: "arms"}}
+	service := NewService(repo)
+
+	result, err := service.Import(context.Background(), ImportInput{
+		UserID: testUserID,
+		Data:   strings.NewReader(hevyExport),
+	})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.Format != ImportFormatHevy || result.Workouts != 1 || result.DuplicateWorkouts != 1 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if len(repo.workouts) != 2 || repo.workouts[1].Name != "Legs" {
+		t.Fatalf("expected legs workout to be stored, got %#v", repo.workouts)
+	}
+	if len(repo.events) != 0 {
+		t.Fatalf("imported history must not emit record events")
+	}
+}
+
+func TestImportRejectsUnknownLayout(t *testing.T) {
+	service := NewService(newFakeGymRepo())
+
+	_, err := service.Import(context.Background(), ImportInput{
+		UserID: testUserID,
+		Data:   strings.NewReader("foo,bar\n1,2\n"),
+	})
+	if !errors.Is(err, ErrInvalidImportFile) {
+		t.Fatalf("expected ErrInvalidImportFile, got %v", err)
+	}
+}
+
+func TestFamilyWorkoutReadsRespectSharing(t *testing.T) {
+	repo := newFakeGymRepo()
+	service := NewServiceWithMembers(repo, newFamilyMembers())
+	scope := Scope{UserID: testUserID, FamilyID: testFamilyID, Kind: ScopeFamily}
+
+	if _, _, err := service.ListWorkouts(context.Background(), scope, ListFilter{}); err != nil {
+		t.Fatalf("list workouts: %v", err)
+	}
+	if repo.lastFilter.ViewerID != testUserID || repo.lastFilter.SharedOnly {
+		t.Fatalf("expected family scope to include only shared workouts of others, got %#v", repo.lastFilter)
+	}
+
+	if _, _, err := service.ListFamilyFeed(context.Background(), scope, ListFilter{Limit: 20}); err != nil {
+		t.Fatalf("list family feed: %v", err)
+	}
+	if !repo.lastFilter.SharedOnly || len(repo.listedUserIDs) != 2 {
+		t.Fatalf("expected shared workouts of all members, got %#v for %v", repo.lastFilter, repo.listedUserIDs)
+	}
+
+	_, _, err := service.ListFamilyFeed(context.Background(), Scope{UserID: testUserID}, ListFilter{})
+	if !errors.Is(err, ErrInvalidScope) {
+		t.Fatalf("expected ErrInvalidScope for personal feed, got %v", err)
+	}
+}
+
+func TestCreateWorkoutFromSharedTemplate(t *testing.T) {
+	repo := newFakeGymRepo()
+	repo.templates = map[string]WorkoutTemplate{
+		"shared":  {ID: "shared", UserID: testMemberID, Name: "Legs", Visibility: VisibilityFamily},
+		"private": {ID: "private", UserID: testMemberID, Name: "Arms", Visibility: VisibilityPrivate},
+	}
+	repo.templateSets = map[string][]TemplateSet{
+		"shared": {{TemplateID: "shared", Exercise: "Squat", WeightKg: 80, Reps: 5}},
+	}
+	service := NewServiceWithMembers(repo, newFamilyMembers())
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	workout, err := service.CreateWorkout(context.Background(), CreateWorkoutInput{
+		UserID: testUserID, Date: date, Name: "Legs", TemplateID: "shared",
+	})
+	if err != nil {
+		t.Fatalf("create workout from shared template: %v", err)
+	}
+	if len(workout.Sets) != 1 || workout.Visibility != VisibilityPrivate {
+		t.Fatalf("unexpected workout: %#v", workout)
+	}
+
+	_, err = service.CreateWorkout(context.Background(), CreateWorkoutInput{
+		UserID: testUserID, Date: date, Name: "Arms", TemplateID: "private",
+	})
+	if !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("expected ErrTemplateNotFound for private template, got %v", err)
+	}
+
+	_, err = service.CreateWorkout(context.Background(), CreateWorkoutInput{
+		UserID: testUserID, Date: date, Name: "Arms", Visibility: "public",
+	})
+	if !errors.Is(err, ErrInvalidVisibility) {
+		t.Fatalf("expected ErrInvalidVisibility, got %v", err)
+	}
+}
+
+func (r *fakeGymRepo) GetGoal(_ context.Context, userID string) (*Goal, error) {
+	goal, ok := r.goals[userID]
+	if !ok {
+		return nil, ErrGoalNotFound
+	}
+	return &goal, nil
+}
+
+func (r *fakeGymRepo) UpsertGoal(_ context.Context, goal *Goal) error {
+	r.goals[goal.UserID] = *goal
+	return nil
+}
+
+func (r *fakeGymRepo) ListActivityDays(_ context.Context, _ string, from time.Time) ([]ActivityDay, error) {
+	days := make([]ActivityDay, 0, len(r.activity))
+	for _, day := range r.activity {
+		if !day.Date.Before(from) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func (r *fakeGymRepo) ListGoalsWithoutNudge(_ context.Context, weekStart time.Time, _ int) ([]Goal, error) {
+	goals := make([]Goal, 0, len(r.goals))
+	for _, goal := range r.goals {
+		if _, found, _ := r.GetGoalNudge(context.Background(), goal.UserID, weekStart); !found {
+			goals = append(goals, goal)
+		}
+	}
+	return goals, nil
+}
+
+func (r *fakeGymRepo) GetGoalNudge(_ context.Context, userID string, weekStart time.Time) (*GoalNudge, bool, error) {
+	for _, nudge := range r.nudges {
+		if nudge.UserID == userID && nudge.WeekStart.Equal(weekStart) {
+			return &nudge, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (r *fakeGymRepo) CreateGoalNudge(_ context.Context, nudge *GoalNudge) error {
+	r.nudges = append(r.nudges, *nudge)
+	return nil
+}
+
+func activityDay(year int, month time.Month, day, workouts, entries int) ActivityDay {
+	return ActivityDay{
+		Date:     time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		Workouts: workouts,
+		Entries:  entries,
+	}
+}
+
+func TestSetGoalValidatesBounds(t *testing.T) {
+	service := NewService(newFakeGymRepo())
+
+	for _, value := range []int{0, MaxWorkoutsPerWeek + 1} {
+		if _, err := service.SetGoal(context.Background(), testUserID, value); !errors.Is(err, ErrInvalidGoal) {
+			t.Fatalf("expected ErrInvalidGoal for %d, got %v", value, err)
+		}
+	}
+}
+
+func TestGetStreakCountsConsecutiveWeeks(t *testing.T) {
+	repo := newFakeGymRepo()
+	repo.goals[testUserID] = Goal{UserID: testUserID, WorkoutsPerWeek: 2}
+	repo.activity = []ActivityDay{
+		// Week of 2026-04-13: met, then a gap week breaks the run.
+		activityDay(2026, time.April, 13, 1, 0),
+		activityDay(2026, time.April, 15, 1, 0),
+		// Weeks of 2026-04-27, 2026-05-04 and 2026-05-11 are met; entries
+		// alone count as one workout per day.
+		activityDay(2026, time.April, 27, 2, 0),
+		activityDay(2026, time.May, 4, 1, 0),
+		activityDay(2026, time.May, 6, 0, 3),
+		activityDay(2026, time.May, 11, 1, 0),
+		activityDay(2026, time.May, 13, 1, 2),
+		// Current week has one of two workouts so far.
+		activityDay(2026, time.May, 18, 1, 0),
+	}
+	service := NewService(repo)
+	service.now = func() time.Time { return time.Date(2026, time.May, 20, 9, 0, 0, 0, time.UTC) }
+
+	streak, err := service.GetStreak(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("get streak: %v", err)
+	}
+	if !streak.WeekStart.Equal(time.Date(2026, time.May, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected week start: %v", streak.WeekStart)
+	}
+	if streak.WorkoutsThisWeek != 1 || streak.Remaining() != 1 {
+		t.Fatalf("unexpected current week: %#v", streak)
+	}
+	if streak.CurrentStreakWeeks != 3 || streak.LongestStreakWeeks != 3 {
+		t.Fatalf("expected current and longest streak of 3, got %d and %d", streak.CurrentStreakWeeks, streak.LongestStreakWeeks)
+	}
+}
+
+func TestRunNudgesOncePerWeekBelowGoal(t *testing.T) {
+	repo := newFakeGymRepo()
+	repo.goals[testUserID] = Goal{UserID: testUserID, WorkoutsPerWeek: 3}
+	repo.goals[testMemberID] = Goal{UserID: testMemberID, WorkoutsPerWeek: 1}
+	repo.activity = []ActivityDay{activityDay(2026, time.May, 19, 1, 0)}
+	service := NewService(repo)
+
+	service.now = func() time.Time { return time.Date(2026, time.May, 20, 9, 0, 0, 0, time.UTC) }
+	nudges, err := service.RunNudges(context.Background())
+	if err != nil {
+		t.Fatalf("run nudges mid-week: %v", err)
+	}
+	if len(nudges) != 0 {
+		t.Fatalf("expected no nudges mid-week, got %#v", nudges)
+	}
+
+	service.now = func() time.Time { return time.Date(2026, time.May, 23, 13, 0, 0, 0, time.UTC) }
+	nudges, err = service.RunNudges(context.Background())
+	if err != nil {
+		t.Fatalf("run nudges: %v", err)
+	}
+	// The fake repo returns the same activity for every user, so the member
+	// with a goal of one has already met it.
+	if len(nudges) != 1 || nudges[0].UserID != testUserID || nudges[0].WorkoutsDone != 1 || nudges[0].Target != 3 {
+		t.Fatalf("unexpected nudges: %#v", nudges)
+	}
+
+	nudges, err = service.RunNudges(context.Background())
+	if err != nil {
+		t.Fatalf("second run nudges: %v", err)
+	}
+	if len(nudges) != 0 {
+		t.Fatalf("expected no repeated nudges, got %#v", nudges)
+	}
+
+	streak, err := service.GetStreak(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("get streak: %v", err)
+	}
+	if streak.Nudge == nil || streak.Nudge.Target != 3 {
+		t.Fatalf("expected streak to carry the nudge, got %#v", streak.Nudge)
+	}
+}
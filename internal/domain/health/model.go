@@ -0,0 +1,39 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// Check probes one dependency. Failing critical checks make the service
+// unready; failing optional checks only degrade it.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) (details map[string]any, err error)
+}
+
+type ComponentStatus struct {
+	Status    string
+	Critical  bool
+	LatencyMS int64
+	Error     string
+	Details   map[string]any
+}
+
+type Report struct {
+	Status     string
+	CheckedAt  time.Time
+	Components map[string]ComponentStatus
+}
+
+// Ready reports whether every critical component is healthy.
+func (r Report) Ready() bool {
+	return r.Status != StatusFail
+}
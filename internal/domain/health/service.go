@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultCheckTimeout = 2 * time.Second
+
+type Service struct {
+	checks  []Check
+	timeout time.Duration
+	now     func() time.Time
+}
+
+func NewService(checks []Check, timeout time.Duration) *Service {
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	return &Service{
+		checks:  checks,
+		timeout: timeout,
+		now:     time.Now,
+	}
+}
+
+// Readiness runs all checks in parallel, each bounded by the check timeout.
+func (s *Service) Readiness(ctx context.Context) Report {
+	report := Report{
+		Status:     StatusOK,
+		CheckedAt:  s.now().UTC(),
+		Components: make(map[string]ComponentStatus, len(s.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			component := s.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[check.Name] = component
+		}(check)
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status == StatusOK {
+			continue
+		}
+		if component.Critical {
+			report.Status = StatusFail
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (s *Service) run(ctx context.Context, check Check) (component ComponentStatus) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	started := time.Now()
+	component = ComponentStatus{Status: StatusOK, Critical: check.Critical}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			component.Status = StatusFail
+			component.Error = fmt.Sprintf("check panicked: %v", recovered)
+		}
+		component.LatencyMS = time.Since(started).Milliseconds()
+	}()
+
+	details, err := check.Run(ctx)
+	component.Details = details
+	if err != nil {
+		component.Status = StatusFail
+		component.Error = err.Error()
+	}
+	return component
+}
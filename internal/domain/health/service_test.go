@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func okCheck(name string, critical bool) Check {
+	return Check{Name: name, Critical: critical, Run: func(context.Context) (map[string]any, error) {
+		return nil, nil
+	}}
+}
+
+func failingCheck(name string, critical bool) Check {
+	return Check{Name: name, Critical: critical, Run: func(context.Context) (map[string]any, error) {
+		return nil, errors.New("down")
+	}}
+}
+
+func TestReadinessAllHealthy(t *testing.T) {
+	report := NewService([]Check{okCheck("database", true), okCheck("supabase", false)}, 0).Readiness(context.Background())
+	if report.Status != StatusOK || !report.Ready() {
+		t.Fatalf("expected ok report, got %+v", report)
+	}
+	if len(report.Components) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(report.Components))
+	}
+}
+
+func TestReadinessOptionalFailureDegrades(t *testing.T) {
+	report := NewService([]Check{okCheck("database", true), failingCheck("supabase", false)}, 0).Readiness(context.Background())
+	if report.Status != StatusDegraded || !report.Ready() {
+		t.Fatalf("expected degraded but ready report, got %+v", report)
+	}
+	if component := report.Components["supabase"]; component.Status != StatusFail || component.Error != "down" {
+		t.Fatalf("unexpected supabase component: %+v", component)
+	}
+}
+
+func TestReadinessCriticalFailureFails(t *testing.T) {
+	report := NewService([]Check{failingCheck("database", true), failingCheck("supabase", false)}, 0).Readiness(context.Background())
+	if report.Status != StatusFail || report.Ready() {
+		t.Fatalf("expected failed report, got %+v", report)
+	}
+}
+
+func TestReadinessTimesOutSlowChecks(t *testing.T) {
+	slow := Check{Name: "database", Critical: true, Run: func(ctx context.Context) (map[string]any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	started := time.Now()
+	report := NewService([]Check{slow}, 20*time.Millisecond).Readiness(context.Background())
+	if time.Since(started) > time.Second {
+		t.Fatal("check was not bounded by the timeout")
+	}
+	if report.Ready() {
+		t.Fatalf("expected timed out check to fail readiness, got %+v", report)
+	}
+}
+
+func TestReadinessRecoversPanickingCheck(t *testing.T) {
+	panicking := Check{Name: "migrations", Critical: true, Run: func(context.Context) (map[string]any, error) {
+		panic("boom")
+	}}
+	report := NewService([]Check{panicking}, 0).Readiness(context.Background())
+	if report.Components["migrations"].Status != StatusFail {
+		t.Fatalf("expected panicking check to fail, got %+v", report.Components["migrations"])
+	}
+}
@@ -0,0 +1,9 @@
+package labels
+
+import "errors"
+
+var (
+	ErrInvalidLabel      = errors.New("invalid label")
+	ErrTooManyLabels     = errors.New("too many labels")
+	ErrInvalidEntityType = errors.New("invalid label entity type")
+)
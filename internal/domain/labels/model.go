@@ -0,0 +1,44 @@
+package labels
+
+import "time"
+
+// EntityType names the kind of record a label is attached to.
+type EntityType string
+
+const (
+	EntityTodoItem EntityType = "todo_item"
+	EntityWorkout  EntityType = "workout"
+
+	// MaxLabelsPerEntity and MaxLabelLength bound what a record can carry.
+	MaxLabelsPerEntity = 10
+	MaxLabelLength     = 32
+)
+
+// Label attaches one free-form label, such as "weekend" or "legs day", to a
+// record. OwnerID is whoever the labelled record belongs to: the family for
+// todo items, the user for workouts.
+type Label struct {
+	EntityType EntityType `gorm:"type:varchar(32);primaryKey"`
+	EntityID   string     `gorm:"type:uuid;primaryKey"`
+	Label      string     `gorm:"primaryKey"`
+	OwnerID    string     `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+func (Label) TableName() string {
+	return "entity_labels"
+}
+
+// Usage is a label in use by an owner, with how many records carry it.
+type Usage struct {
+	Label string
+	Count int64
+}
+
+// SetInput replaces the labels of one record.
+type SetInput struct {
+	EntityType EntityType
+	EntityID   string
+	OwnerID    string
+	Labels     []string
+}
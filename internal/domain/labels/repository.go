@@ -0,0 +1,13 @@
+package labels
+
+import "context"
+
+type Repository interface {
+	// ReplaceLabels swaps the labels of a record for labels.
+	ReplaceLabels(ctx context.Context, entityType EntityType, entityID, ownerID string, labels []string) error
+	// ListByEntities returns the labels of each record, sorted, keyed by
+	// record ID. Records without labels are left out.
+	ListByEntities(ctx context.Context, entityType EntityType, entityIDs []string) (map[string][]string, error)
+	ListUsage(ctx context.Context, entityType EntityType, ownerID string) ([]Usage, error)
+	DeleteByEntity(ctx context.Context, entityType EntityType, entityID string) error
+}
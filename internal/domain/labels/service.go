@@ -0,0 +1,97 @@
+package labels
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"family-app-go/pkg/tracing"
+)
+
+// Service keeps the labels of records owned by other domains. Callers check
+// that the user may change a record before labelling it.
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Set replaces the labels of a record and returns them normalized.
+func (s *Service) Set(ctx context.Context, input SetInput) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "labels.Set")
+	defer span.End()
+
+	if !validEntityType(input.EntityType) {
+		return nil, ErrInvalidEntityType
+	}
+	labels, err := Normalize(input.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) > MaxLabelsPerEntity {
+		return nil, ErrTooManyLabels
+	}
+
+	if err := s.repo.ReplaceLabels(ctx, input.EntityType, input.EntityID, input.OwnerID, labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// ListByEntities returns the labels of each record keyed by record ID.
+func (s *Service) ListByEntities(ctx context.Context, entityType EntityType, entityIDs []string) (map[string][]string, error) {
+	ctx, span := tracing.Start(ctx, "labels.ListByEntities")
+	defer span.End()
+
+	if len(entityIDs) == 0 {
+		return map[string][]string{}, nil
+	}
+	return s.repo.ListByEntities(ctx, entityType, entityIDs)
+}
+
+// ListUsage returns the labels an owner uses on a kind of record, most used
+// first, for suggestions and filters.
+func (s *Service) ListUsage(ctx context.Context, entityType EntityType, ownerID string) ([]Usage, error) {
+	ctx, span := tracing.Start(ctx, "labels.ListUsage")
+	defer span.End()
+
+	if !validEntityType(entityType) {
+		return nil, ErrInvalidEntityType
+	}
+	return s.repo.ListUsage(ctx, entityType, ownerID)
+}
+
+// Clear removes the labels of a deleted record.
+func (s *Service) Clear(ctx context.Context, entityType EntityType, entityID string) error {
+	ctx, span := tracing.Start(ctx, "labels.Clear")
+	defer span.End()
+
+	return s.repo.DeleteByEntity(ctx, entityType, entityID)
+}
+
+// Normalize lowercases labels, collapses their whitespace and drops
+// duplicates, so "Legs  Day" and "legs day" are one label. The result is
+// sorted. Empty or overlong labels are rejected.
+func Normalize(values []string) ([]string, error) {
+	seen := make(map[string]bool, len(values))
+	labels := make([]string, 0, len(values))
+	for _, value := range values {
+		label := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+		if label == "" || len([]rune(label)) > MaxLabelLength {
+			return nil, ErrInvalidLabel
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+func validEntityType(entityType EntityType) bool {
+	return entityType == EntityTodoItem || entityType == EntityWorkout
+}
@@ -0,0 +1,87 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeLabelsRepo struct {
+	Repository
+	replaced map[string][]string
+}
+
+func (r *fakeLabelsRepo) ReplaceLabels(_ context.Context, _ EntityType, entityID, _ string, labels []string) error {
+	if r.replaced == nil {
+		r.replaced = make(map[string][]string)
+	}
+	r.replaced[entityID] = labels
+	return nil
+}
+
+func TestNormalizeLowercasesAndDedupes(t *testing.T) {
+	labels, err := Normalize([]string{" Legs  Day", "legs day", "Cardio"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"cardio", "legs day"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("labels = %v, want %v", labels, want)
+	}
+}
+
+func TestNormalizeRejectsEmptyAndOverlongLabels(t *testing.T) {
+	for _, value := range []string{"  ", strings.Repeat("a", MaxLabelLength+1)} {
+		if _, err := Normalize([]string{value}); !errors.Is(err, ErrInvalidLabel) {
+			t.Fatalf("Normalize(%q) error = %v, want ErrInvalidLabel", value, err)
+		}
+	}
+}
+
+func TestSetStoresNormalizedLabels(t *testing.T) {
+	repo := &fakeLabelsRepo{}
+	service := NewService(repo)
+
+	labels, err := service.Set(context.Background(), SetInput{
+		EntityType: EntityWorkout,
+		EntityID:   "workout-1",
+		OwnerID:    "user-1",
+		Labels:     []string{"Push", "push"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(labels, []string{"push"}) || !reflect.DeepEqual(repo.replaced["workout-1"], []string{"push"}) {
+		t.Fatalf("labels = %v, stored = %v", labels, repo.replaced["workout-1"])
+	}
+}
+
+func TestSetRejectsTooManyLabels(t *testing.T) {
+	values := make([]string, 0, MaxLabelsPerEntity+1)
+	for i := 0; i <= MaxLabelsPerEntity; i++ {
+		values = append(values, strings.Repeat("x", i+1))
+	}
+
+	_, err := NewService(&fakeLabelsRepo{}).Set(context.Background(), SetInput{
+		EntityType: EntityTodoItem,
+		EntityID:   "item-1",
+		OwnerID:    "family-1",
+		Labels:     values,
+	})
+	if !errors.Is(err, ErrTooManyLabels) {
+		t.Fatalf("error = %v, want ErrTooManyLabels", err)
+	}
+}
+
+func TestSetRejectsUnknownEntityType(t *testing.T) {
+	_, err := NewService(&fakeLabelsRepo{}).Set(context.Background(), SetInput{
+		EntityType: "expense",
+		EntityID:   "expense-1",
+		Labels:     []string{"food"},
+	})
+	if !errors.Is(err, ErrInvalidEntityType) {
+		t.Fatalf("error = %v, want ErrInvalidEntityType", err)
+	}
+}
@@ -0,0 +1,12 @@
+package milestones
+
+import "errors"
+
+var (
+	ErrMilestoneNotFound = errors.New("milestone not found")
+	ErrInvalidTitle      = errors.New("invalid milestone title")
+	ErrInvalidKind       = errors.New("invalid milestone kind")
+	ErrInvalidGoal       = errors.New("invalid savings goal")
+	ErrInvalidAmount     = errors.New("invalid savings amount")
+	ErrNoSavingsGoal     = errors.New("milestone has no savings goal")
+)
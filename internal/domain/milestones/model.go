@@ -0,0 +1,143 @@
+package milestones
+
+import (
+	"math"
+	"time"
+
+	"family-app-go/pkg/money"
+)
+
+const (
+	KindVacation = "vacation"
+	KindBirth    = "birth"
+	KindPayoff   = "payoff"
+	KindOther    = "other"
+
+	MaxTitleLength = 120
+)
+
+// Kinds lists the accepted milestone kinds; clients pick an icon by kind.
+var Kinds = []string{KindVacation, KindBirth, KindPayoff, KindOther}
+
+// Milestone is a date the family counts down to, such as a vacation, a
+// baby's due date or the last mortgage payment. A milestone may carry a
+// savings goal: GoalAmount, GoalCurrency and SavedAmount are all set or all
+// nil.
+type Milestone struct {
+	ID           string    `gorm:"type:uuid;primaryKey"`
+	FamilyID     string    `gorm:"type:uuid;not null"`
+	CreatedBy    string    `gorm:"type:uuid;not null"`
+	Title        string    `gorm:"not null"`
+	Kind         string    `gorm:"type:varchar(16);not null"`
+	Date         time.Time `gorm:"type:date;not null"`
+	Note         *string
+	GoalAmount   *float64 `gorm:"type:numeric(14,2)"`
+	GoalCurrency *string  `gorm:"size:3"`
+	SavedAmount  *float64 `gorm:"type:numeric(14,2)"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// HasGoal reports whether the milestone carries a savings goal.
+func (m Milestone) HasGoal() bool {
+	return m.GoalAmount != nil && m.GoalCurrency != nil && m.SavedAmount != nil
+}
+
+// Countdown is the time left until a milestone, counted in calendar days
+// (UTC) from today.
+type Countdown struct {
+	// Days is negative once the date has passed and 0 on the day itself.
+	Days int
+	// Weeks and Months are the whole weeks and calendar months left; both
+	// are 0 once the date has passed.
+	Weeks  int
+	Months int
+	Passed bool
+}
+
+// Countdown returns the time left from today until the milestone.
+func (m Milestone) Countdown(today time.Time) Countdown {
+	from := dateOnlyUTC(today)
+	to := dateOnlyUTC(m.Date)
+	days := int(math.Round(to.Sub(from).Hours() / 24))
+	if days < 0 {
+		return Countdown{Days: days, Passed: true}
+	}
+
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return Countdown{Days: days, Weeks: days / 7, Months: months}
+}
+
+// Progress is how far a savings goal is.
+type Progress struct {
+	Goal      float64
+	Saved     float64
+	Currency  string
+	Remaining float64
+	// Share is Saved over Goal, capped at 1.
+	Share float64
+	// PerMonth is what is left to save each month, started months
+	// included, to reach the goal on the date. It is nil once the goal is
+	// reached or the date has passed.
+	PerMonth *float64
+}
+
+// Progress returns the state of the savings goal, or nil without one.
+func (m Milestone) Progress(today time.Time) *Progress {
+	if !m.HasGoal() {
+		return nil
+	}
+
+	currency := *m.GoalCurrency
+	progress := &Progress{
+		Goal:      *m.GoalAmount,
+		Saved:     *m.SavedAmount,
+		Currency:  currency,
+		Remaining: money.Round(math.Max(*m.GoalAmount-*m.SavedAmount, 0), currency),
+		Share:     math.Min(*m.SavedAmount / *m.GoalAmount, 1),
+	}
+
+	countdown := m.Countdown(today)
+	if progress.Remaining == 0 || countdown.Passed {
+		return progress
+	}
+	months := countdown.Months
+	if dateOnlyUTC(today).AddDate(0, months, 0).Before(dateOnlyUTC(m.Date)) || months == 0 {
+		months++
+	}
+	perMonth := money.Round(progress.Remaining/float64(months), currency)
+	progress.PerMonth = &perMonth
+	return progress
+}
+
+// Input describes the editable fields of a milestone. Updates replace all of
+// them; a nil Goal removes the savings goal.
+type Input struct {
+	Title string
+	Kind  string
+	Date  time.Time
+	Note  *string
+	Goal  *GoalInput
+}
+
+// GoalInput sets a savings goal. Saved is what the family has put aside so
+// far.
+type GoalInput struct {
+	Amount   float64
+	Currency string
+	Saved    float64
+}
+
+// ListFilter selects milestones; past ones are left out unless IncludePast
+// is set.
+type ListFilter struct {
+	IncludePast bool
+}
+
+func dateOnlyUTC(value time.Time) time.Time {
+	value = value.UTC()
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,19 @@
+package milestones
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// ListMilestones returns the family's milestones dated from onwards,
+	// or all of them when from is nil, soonest first.
+	ListMilestones(ctx context.Context, familyID string, from *time.Time, limit int) ([]Milestone, error)
+	GetMilestone(ctx context.Context, familyID, milestoneID string) (*Milestone, error)
+	CreateMilestone(ctx context.Context, milestone *Milestone) error
+	UpdateMilestone(ctx context.Context, milestone *Milestone) error
+	DeleteMilestone(ctx context.Context, familyID, milestoneID string) (bool, error)
+	// AddSavings adds amount, which may be negative, to the saved amount of
+	// a milestone with a savings goal without letting it drop below zero.
+	AddSavings(ctx context.Context, familyID, milestoneID string, amount float64) (bool, error)
+}
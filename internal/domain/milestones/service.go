@@ -0,0 +1,178 @@
+package milestones
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/money"
+	"family-app-go/pkg/tracing"
+)
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// List returns the family's milestones, soonest first. Milestones whose date
+// has passed are only included on request.
+func (s *Service) List(ctx context.Context, familyID string, filter ListFilter) ([]Milestone, error) {
+	ctx, span := tracing.Start(ctx, "milestones.List")
+	defer span.End()
+
+	if filter.IncludePast {
+		return s.repo.ListMilestones(ctx, familyID, nil, 0)
+	}
+	today := dateOnlyUTC(s.now())
+	return s.repo.ListMilestones(ctx, familyID, &today, 0)
+}
+
+// ListUpcoming returns at most limit milestones from today on, soonest
+// first.
+func (s *Service) ListUpcoming(ctx context.Context, familyID string, limit int) ([]Milestone, error) {
+	ctx, span := tracing.Start(ctx, "milestones.ListUpcoming")
+	defer span.End()
+
+	today := dateOnlyUTC(s.now())
+	return s.repo.ListMilestones(ctx, familyID, &today, limit)
+}
+
+func (s *Service) Get(ctx context.Context, familyID, milestoneID string) (*Milestone, error) {
+	ctx, span := tracing.Start(ctx, "milestones.Get")
+	defer span.End()
+
+	return s.repo.GetMilestone(ctx, familyID, milestoneID)
+}
+
+func (s *Service) Create(ctx context.Context, familyID, createdBy string, input Input) (*Milestone, error) {
+	ctx, span := tracing.Start(ctx, "milestones.Create")
+	defer span.End()
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	milestone := Milestone{ID: newID, FamilyID: familyID, CreatedBy: createdBy}
+	if err := applyInput(&milestone, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateMilestone(ctx, &milestone); err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (s *Service) Update(ctx context.Context, familyID, milestoneID string, input Input) (*Milestone, error) {
+	ctx, span := tracing.Start(ctx, "milestones.Update")
+	defer span.End()
+
+	milestone, err := s.repo.GetMilestone(ctx, familyID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyInput(milestone, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateMilestone(ctx, milestone); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+func (s *Service) Delete(ctx context.Context, familyID, milestoneID string) error {
+	ctx, span := tracing.Start(ctx, "milestones.Delete")
+	defer span.End()
+
+	deleted, err := s.repo.DeleteMilestone(ctx, familyID, milestoneID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrMilestoneNotFound
+	}
+	return nil
+}
+
+// AddSavings records money put aside for, or taken back from, a milestone's
+// savings goal. The saved amount never drops below zero. It is a single
+// update, so members saving at the same time don't overwrite each other.
+func (s *Service) AddSavings(ctx context.Context, familyID, milestoneID string, amount float64) (*Milestone, error) {
+	ctx, span := tracing.Start(ctx, "milestones.AddSavings")
+	defer span.End()
+
+	milestone, err := s.repo.GetMilestone(ctx, familyID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	if !milestone.HasGoal() {
+		return nil, ErrNoSavingsGoal
+	}
+	if amount == 0 || money.CheckPrecision(amount, *milestone.GoalCurrency) != nil {
+		return nil, ErrInvalidAmount
+	}
+
+	updated, err := s.repo.AddSavings(ctx, familyID, milestoneID, amount)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrMilestoneNotFound
+	}
+	return s.repo.GetMilestone(ctx, familyID, milestoneID)
+}
+
+func applyInput(milestone *Milestone, input Input) error {
+	title := strings.TrimSpace(input.Title)
+	if title == "" || utf8.RuneCountInString(title) > MaxTitleLength {
+		return ErrInvalidTitle
+	}
+	kind := strings.ToLower(strings.TrimSpace(input.Kind))
+	if kind == "" {
+		kind = KindOther
+	}
+	if !slices.Contains(Kinds, kind) {
+		return ErrInvalidKind
+	}
+
+	milestone.Title = title
+	milestone.Kind = kind
+	milestone.Date = dateOnlyUTC(input.Date)
+	milestone.Note = trimOptional(input.Note)
+	milestone.GoalAmount, milestone.GoalCurrency, milestone.SavedAmount = nil, nil, nil
+	if input.Goal == nil {
+		return nil
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(input.Goal.Currency))
+	goal, saved := input.Goal.Amount, input.Goal.Saved
+	if !money.IsCurrency(currency) || goal <= 0 || saved < 0 ||
+		money.CheckPrecision(goal, currency) != nil || money.CheckPrecision(saved, currency) != nil {
+		return ErrInvalidGoal
+	}
+	milestone.GoalAmount = &goal
+	milestone.GoalCurrency = &currency
+	milestone.SavedAmount = &saved
+	return nil
+}
+
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
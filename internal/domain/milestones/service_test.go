@@ -0,0 +1,143 @@
+package milestones
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeMilestonesRepo struct {
+	Repository
+	milestones map[string]*Milestone
+}
+
+func (r *fakeMilestonesRepo) CreateMilestone(_ context.Context, milestone *Milestone) error {
+	if r.milestones == nil {
+		r.milestones = make(map[string]*Milestone)
+	}
+	stored := *milestone
+	r.milestones[milestone.ID] = &stored
+	return nil
+}
+
+func (r *fakeMilestonesRepo) GetMilestone(_ context.Context, familyID, milestoneID string) (*Milestone, error) {
+	milestone, ok := r.milestones[milestoneID]
+	if !ok || milestone.FamilyID != familyID {
+		return nil, ErrMilestoneNotFound
+	}
+	copied := *milestone
+	return &copied, nil
+}
+
+func (r *fakeMilestonesRepo) AddSavings(_ context.Context, _, milestoneID string, amount float64) (bool, error) {
+	milestone := r.milestones[milestoneID]
+	saved := max(*milestone.SavedAmount+amount, 0)
+	milestone.SavedAmount = &saved
+	return true, nil
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCountdown(t *testing.T) {
+	today := time.Date(2026, time.October, 18, 21, 30, 0, 0, time.UTC)
+	cases := []struct {
+		date time.Time
+		want Countdown
+	}{
+		{date(2026, time.October, 18), Countdown{}},
+		{date(2026, time.October, 31), Countdown{Days: 13, Weeks: 1}},
+		{date(2026, time.December, 17), Countdown{Days: 60, Weeks: 8, Months: 1}},
+		{date(2027, time.October, 18), Countdown{Days: 365, Weeks: 52, Months: 12}},
+		{date(2026, time.October, 10), Countdown{Days: -8, Passed: true}},
+	}
+	for _, tc := range cases {
+		if got := (Milestone{Date: tc.date}).Countdown(today); got != tc.want {
+			t.Fatalf("Countdown(%s) = %+v, want %+v", tc.date.Format("2006-01-02"), got, tc.want)
+		}
+	}
+}
+
+func TestProgressSpreadsRemainingOverStartedMonths(t *testing.T) {
+	goal, saved, currency := 3000.0, 1200.0, "EUR"
+	milestone := Milestone{Date: date(2027, time.January, 10), GoalAmount: &goal, GoalCurrency: &currency, SavedAmount: &saved}
+
+	progress := milestone.Progress(date(2026, time.October, 18))
+	if progress == nil || progress.Remaining != 1800 || progress.Share != 0.4 {
+		t.Fatalf("progress = %+v", progress)
+	}
+	// Two whole months and a started third are left.
+	if progress.PerMonth == nil || *progress.PerMonth != 600 {
+		t.Fatalf("per month = %v, want 600", progress.PerMonth)
+	}
+
+	saved = 3500
+	if progress := milestone.Progress(date(2026, time.October, 18)); progress.Share != 1 || progress.Remaining != 0 || progress.PerMonth != nil {
+		t.Fatalf("reached progress = %+v", progress)
+	}
+	if (Milestone{Date: milestone.Date}).Progress(date(2026, time.October, 18)) != nil {
+		t.Fatal("expected no progress without a goal")
+	}
+}
+
+func TestCreateValidatesInput(t *testing.T) {
+	service := NewService(&fakeMilestonesRepo{})
+
+	milestone, err := service.Create(context.Background(), "family-1", "user-1", Input{
+		Title: "  Sea trip ",
+		Date:  time.Date(2027, time.July, 1, 15, 0, 0, 0, time.UTC),
+		Goal:  &GoalInput{Amount: 2500, Currency: "eur"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if milestone.Title != "Sea trip" || milestone.Kind != KindOther || !milestone.Date.Equal(date(2027, time.July, 1)) || *milestone.GoalCurrency != "EUR" {
+		t.Fatalf("milestone = %+v", milestone)
+	}
+
+	for name, tc := range map[string]struct {
+		input Input
+		want  error
+	}{
+		"blank title":   {Input{Title: " "}, ErrInvalidTitle},
+		"unknown kind":  {Input{Title: "Trip", Kind: "party"}, ErrInvalidKind},
+		"zero goal":     {Input{Title: "Trip", Goal: &GoalInput{Currency: "EUR"}}, ErrInvalidGoal},
+		"bad currency":  {Input{Title: "Trip", Goal: &GoalInput{Amount: 10, Currency: "XXQ"}}, ErrInvalidGoal},
+		"negative save": {Input{Title: "Trip", Goal: &GoalInput{Amount: 10, Currency: "EUR", Saved: -1}}, ErrInvalidGoal},
+	} {
+		if _, err := service.Create(context.Background(), "family-1", "user-1", tc.input); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: error = %v, want %v", name, err, tc.want)
+		}
+	}
+}
+
+func TestAddSavingsNeedsGoal(t *testing.T) {
+	repo := &fakeMilestonesRepo{}
+	service := NewService(repo)
+	ctx := context.Background()
+
+	plain, err := service.Create(ctx, "family-1", "user-1", Input{Title: "Birthday", Date: date(2027, time.March, 3)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.AddSavings(ctx, "family-1", plain.ID, 10); !errors.Is(err, ErrNoSavingsGoal) {
+		t.Fatalf("error = %v, want ErrNoSavingsGoal", err)
+	}
+
+	trip, err := service.Create(ctx, "family-1", "user-1", Input{Title: "Trip", Date: date(2027, time.July, 1), Goal: &GoalInput{Amount: 100, Currency: "EUR", Saved: 20}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.AddSavings(ctx, "family-1", trip.ID, 0.001); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("error = %v, want ErrInvalidAmount", err)
+	}
+	updated, err := service.AddSavings(ctx, "family-1", trip.ID, -50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *updated.SavedAmount != 0 {
+		t.Fatalf("saved = %v, want 0", *updated.SavedAmount)
+	}
+}
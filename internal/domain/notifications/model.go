@@ -0,0 +1,46 @@
+package notifications
+
+import "time"
+
+const (
+	KindBudgetThreshold = "budget_threshold"
+
+	// DefaultLimit and MaxLimit page the notification inbox.
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+// Notification is an entry in a member's inbox. The inbox is the record of
+// what was sent; push providers only echo it to devices.
+type Notification struct {
+	ID        string `gorm:"type:uuid;primaryKey"`
+	FamilyID  string `gorm:"type:uuid;not null"`
+	UserID    string `gorm:"type:uuid;not null"`
+	Kind      string `gorm:"type:varchar(32);not null"`
+	Title     string `gorm:"not null"`
+	Body      string `gorm:"not null"`
+	Link      string `gorm:"not null"`
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
+
+type ListFilter struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+// Page is a page of a member's notifications, newest first. Total counts
+// the filtered notifications, Unread all unread ones.
+type Page struct {
+	Items  []Notification
+	Total  int64
+	Unread int64
+}
+
+// Delivery is the outcome of sending notifications. They are stored even
+// when a push provider fails; PushErrors lists those failures.
+type Delivery struct {
+	Notifications []Notification
+	PushErrors    []error
+}
@@ -0,0 +1,21 @@
+package notifications
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	CreateNotifications(ctx context.Context, notifications []Notification) error
+	ListNotifications(ctx context.Context, familyID, userID string, filter ListFilter) (*Page, error)
+	// MarkNotificationsRead marks the given unread notifications of userID
+	// read, or all of them when notificationIDs is empty, and returns how
+	// many it changed.
+	MarkNotificationsRead(ctx context.Context, familyID, userID string, notificationIDs []string, readAt time.Time) (int64, error)
+}
+
+// Pusher sends a stored notification to the member's devices.
+type Pusher interface {
+	Name() string
+	Push(ctx context.Context, notification Notification) error
+}
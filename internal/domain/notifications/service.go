@@ -0,0 +1,99 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+type Service struct {
+	repo    Repository
+	pushers []Pusher
+	now     func() time.Time
+}
+
+type ServiceOptions struct {
+	// Pushers are tried in order for every notification; none means the
+	// inbox is the only channel.
+	Pushers []Pusher
+}
+
+func NewService(repo Repository) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, opts ServiceOptions) *Service {
+	return &Service{
+		repo:    repo,
+		pushers: opts.Pushers,
+		now:     time.Now,
+	}
+}
+
+// Deliver stores the notifications in their users' inboxes, then hands each
+// one to the push providers. A failed push does not undo the inbox entry;
+// it is reported in the delivery.
+func (s *Service) Deliver(ctx context.Context, notifications []Notification) (*Delivery, error) {
+	ctx, span := tracing.Start(ctx, "notifications.Deliver")
+	defer span.End()
+
+	if len(notifications) == 0 {
+		return &Delivery{}, nil
+	}
+
+	now := s.now().UTC()
+	stored := make([]Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		newID, err := id.New()
+		if err != nil {
+			return nil, err
+		}
+		notification.ID = newID
+		notification.ReadAt = nil
+		notification.CreatedAt = now
+		stored = append(stored, notification)
+	}
+	if err := s.repo.CreateNotifications(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	delivery := &Delivery{Notifications: stored}
+	for _, notification := range stored {
+		for _, pusher := range s.pushers {
+			if err := pusher.Push(ctx, notification); err != nil {
+				delivery.PushErrors = append(delivery.PushErrors, fmt.Errorf("push %s to %s: %w", notification.ID, pusher.Name(), err))
+			}
+		}
+	}
+	return delivery, nil
+}
+
+// List returns a page of userID's notifications in the family, newest
+// first.
+func (s *Service) List(ctx context.Context, familyID, userID string, filter ListFilter) (*Page, error) {
+	ctx, span := tracing.Start(ctx, "notifications.List")
+	defer span.End()
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListNotifications(ctx, familyID, userID, filter)
+}
+
+// MarkRead marks the given notifications of userID read, or all of them
+// when notificationIDs is empty. It returns how many were unread.
+func (s *Service) MarkRead(ctx context.Context, familyID, userID string, notificationIDs []string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "notifications.MarkRead")
+	defer span.End()
+
+	return s.repo.MarkNotificationsRead(ctx, familyID, userID, notificationIDs, s.now().UTC())
+}
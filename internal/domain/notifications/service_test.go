@@ -0,0 +1,55 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeNotificationsRepo struct {
+	Repository
+	created []Notification
+}
+
+func (r *fakeNotificationsRepo) CreateNotifications(_ context.Context, notifications []Notification) error {
+	r.created = append(r.created, notifications...)
+	return nil
+}
+
+type fakePusher struct {
+	err    error
+	pushed []string
+}
+
+func (p *fakePusher) Name() string {
+	return "fake"
+}
+
+func (p *fakePusher) Push(_ context.Context, notification Notification) error {
+	p.pushed = append(p.pushed, notification.ID)
+	return p.err
+}
+
+func TestDeliverKeepsTheInboxWhenAPushFails(t *testing.T) {
+	repo := &fakeNotificationsRepo{}
+	failing := &fakePusher{err: errors.New("gateway down")}
+	working := &fakePusher{}
+	service := NewServiceWithOptions(repo, ServiceOptions{Pushers: []Pusher{failing, working}})
+
+	delivery, err := service.Deliver(context.Background(), []Notification{
+		{UserID: "owner", FamilyID: "family-1", Kind: KindBudgetThreshold, Title: "Groceries budget at 80%"},
+		{UserID: "member", FamilyID: "family-1", Kind: KindBudgetThreshold, Title: "Groceries budget at 80%"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.created) != 2 || repo.created[0].ID == "" || repo.created[0].CreatedAt.IsZero() {
+		t.Fatalf("stored %+v, want both notifications with ids", repo.created)
+	}
+	if len(working.pushed) != 2 {
+		t.Fatalf("working provider got %d pushes, want 2", len(working.pushed))
+	}
+	if len(delivery.PushErrors) != 2 {
+		t.Fatalf("push errors = %v, want one per failed push", delivery.PushErrors)
+	}
+}
@@ -0,0 +1,17 @@
+package pets
+
+import "errors"
+
+var (
+	ErrPetNotFound         = errors.New("pet not found")
+	ErrVaccinationNotFound = errors.New("vaccination not found")
+	ErrVetVisitNotFound    = errors.New("vet visit not found")
+	ErrScheduleNotFound    = errors.New("care schedule not found")
+	ErrNameRequired        = errors.New("name is required")
+	ErrReasonRequired      = errors.New("reason is required")
+	ErrTitleRequired       = errors.New("title is required")
+	ErrInvalidScheduleKind = errors.New("invalid schedule kind")
+	ErrInvalidInterval     = errors.New("invalid schedule interval")
+	ErrInvalidCost         = errors.New("invalid vet visit cost")
+	ErrInvalidNextDue      = errors.New("next due date is before administration date")
+)
@@ -0,0 +1,131 @@
+package pets
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ScheduleKindFeeding    = "feeding"
+	ScheduleKindMedication = "medication"
+
+	ReminderKindVaccination = "vaccination"
+
+	// ExpenseCategoryName is the expense category vet bills are filed under.
+	// It is created for the family on first use.
+	ExpenseCategoryName  = "Pets"
+	expenseCategoryEmoji = "🐾"
+
+	MaxIntervalHours      = 24 * 365
+	DefaultReminderDays   = 14
+	MaxReminderWindowDays = 365
+)
+
+type Pet struct {
+	ID        string `gorm:"type:uuid;primaryKey"`
+	FamilyID  string `gorm:"type:uuid;index;not null"`
+	Name      string `gorm:"not null"`
+	Species   *string
+	Breed     *string
+	BirthDate *time.Time     `gorm:"type:date"`
+	Notes     *string        `gorm:"serializer:encrypted"`
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+type Vaccination struct {
+	ID             string     `gorm:"type:uuid;primaryKey"`
+	PetID          string     `gorm:"type:uuid;index;not null"`
+	Name           string     `gorm:"not null"`
+	AdministeredOn time.Time  `gorm:"type:date;not null"`
+	NextDueOn      *time.Time `gorm:"type:date"`
+	Notes          *string    `gorm:"serializer:encrypted"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+}
+
+func (Vaccination) TableName() string {
+	return "pet_vaccinations"
+}
+
+type VetVisit struct {
+	ID        string    `gorm:"type:uuid;primaryKey"`
+	PetID     string    `gorm:"type:uuid;index;not null"`
+	VisitDate time.Time `gorm:"type:date;not null"`
+	Reason    string    `gorm:"not null"`
+	Clinic    *string
+	Notes     *string   `gorm:"serializer:encrypted"`
+	Cost      *float64  `gorm:"type:numeric(12,2)"`
+	Currency  *string   `gorm:"size:3"`
+	ExpenseID *string   `gorm:"type:uuid"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (VetVisit) TableName() string {
+	return "pet_vet_visits"
+}
+
+type CareSchedule struct {
+	ID            string `gorm:"type:uuid;primaryKey"`
+	PetID         string `gorm:"type:uuid;index;not null"`
+	Kind          string `gorm:"type:varchar(16);not null"`
+	Title         string `gorm:"not null"`
+	Dosage        *string
+	IntervalHours int       `gorm:"not null"`
+	NextDueAt     time.Time `gorm:"not null"`
+	LastDoneAt    *time.Time
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+func (CareSchedule) TableName() string {
+	return "pet_care_schedules"
+}
+
+// Reminder is an upcoming vaccination or care task of one of the family's pets.
+type Reminder struct {
+	Kind     string
+	PetID    string
+	PetName  string
+	SourceID string
+	Title    string
+	DueAt    time.Time
+}
+
+type PetInput struct {
+	Name      string
+	Species   *string
+	Breed     *string
+	BirthDate *time.Time
+	Notes     *string
+}
+
+type CreateVaccinationInput struct {
+	Name           string
+	AdministeredOn time.Time
+	NextDueOn      *time.Time
+	Notes          *string
+}
+
+type CreateVetVisitInput struct {
+	UserID       string
+	BaseCurrency string
+	VisitDate    time.Time
+	Reason       string
+	Clinic       *string
+	Notes        *string
+	Cost         *float64
+	Currency     *string
+
+	// AllowedCurrencies is the family's allowed currency list.
+	AllowedCurrencies []string
+}
+
+type CreateScheduleInput struct {
+	Kind          string
+	Title         string
+	Dosage        *string
+	IntervalHours int
+	StartsAt      *time.Time
+}
@@ -0,0 +1,31 @@
+package pets
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	ListPets(ctx context.Context, familyID string) ([]Pet, error)
+	GetPet(ctx context.Context, familyID, petID string) (*Pet, error)
+	CreatePet(ctx context.Context, pet *Pet) error
+	UpdatePet(ctx context.Context, pet *Pet) error
+	SoftDeletePet(ctx context.Context, familyID, petID string) (bool, error)
+
+	ListVaccinations(ctx context.Context, petID string) ([]Vaccination, error)
+	CreateVaccination(ctx context.Context, vaccination *Vaccination) error
+	DeleteVaccination(ctx context.Context, petID, vaccinationID string) (bool, error)
+
+	ListVetVisits(ctx context.Context, petID string) ([]VetVisit, error)
+	CreateVetVisit(ctx context.Context, visit *VetVisit) error
+	DeleteVetVisit(ctx context.Context, petID, visitID string) (bool, error)
+
+	ListSchedules(ctx context.Context, petID string) ([]CareSchedule, error)
+	GetSchedule(ctx context.Context, petID, scheduleID string) (*CareSchedule, error)
+	CreateSchedule(ctx context.Context, schedule *CareSchedule) error
+	MarkScheduleDone(ctx context.Context, schedule *CareSchedule) error
+	DeleteSchedule(ctx context.Context, petID, scheduleID string) (bool, error)
+
+	ListDueVaccinations(ctx context.Context, familyID string, dueBefore time.Time) ([]Reminder, error)
+	ListDueSchedules(ctx context.Context, familyID string, dueBefore time.Time) ([]Reminder, error)
+}
@@ -0,0 +1,452 @@
+package pets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// ExpenseRecorder files vet bills as family expenses so they show up in
+// expense analytics.
+type ExpenseRecorder interface {
+	ListCategories(ctx context.Context, familyID string) ([]expensesdomain.Category, error)
+	CreateCategory(ctx context.Context, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error)
+	CreateExpense(ctx context.Context, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error)
+	DeleteExpense(ctx context.Context, familyID, expenseID string) error
+}
+
+type Service struct {
+	repo     Repository
+	expenses ExpenseRecorder
+	now      func() time.Time
+}
+
+func NewService(repo Repository, expenses ExpenseRecorder) *Service {
+	return &Service{
+		repo:     repo,
+		expenses: expenses,
+		now:      time.Now,
+	}
+}
+
+func (s *Service) ListPets(ctx context.Context, familyID string) ([]Pet, error) {
+	ctx, span := tracing.Start(ctx, "pets.ListPets")
+	defer span.End()
+
+	return s.repo.ListPets(ctx, familyID)
+}
+
+func (s *Service) GetPet(ctx context.Context, familyID, petID string) (*Pet, error) {
+	ctx, span := tracing.Start(ctx, "pets.GetPet")
+	defer span.End()
+
+	return s.repo.GetPet(ctx, familyID, petID)
+}
+
+func (s *Service) CreatePet(ctx context.Context, familyID string, input PetInput) (*Pet, error) {
+	ctx, span := tracing.Start(ctx, "pets.CreatePet")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	pet := Pet{ID: newID, FamilyID: familyID}
+	applyPetInput(&pet, name, input)
+	if err := s.repo.CreatePet(ctx, &pet); err != nil {
+		return nil, err
+	}
+	return &pet, nil
+}
+
+func (s *Service) UpdatePet(ctx context.Context, familyID, petID string, input PetInput) (*Pet, error) {
+	ctx, span := tracing.Start(ctx, "pets.UpdatePet")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+
+	pet, err := s.repo.GetPet(ctx, familyID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyPetInput(pet, name, input)
+	if err := s.repo.UpdatePet(ctx, pet); err != nil {
+		return nil, err
+	}
+	return pet, nil
+}
+
+func (s *Service) DeletePet(ctx context.Context, familyID, petID string) error {
+	ctx, span := tracing.Start(ctx, "pets.DeletePet")
+	defer span.End()
+
+	deleted, err := s.repo.SoftDeletePet(ctx, familyID, petID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPetNotFound
+	}
+	return nil
+}
+
+func (s *Service) ListVaccinations(ctx context.Context, familyID, petID string) ([]Vaccination, error) {
+	ctx, span := tracing.Start(ctx, "pets.ListVaccinations")
+	defer span.End()
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVaccinations(ctx, petID)
+}
+
+func (s *Service) CreateVaccination(ctx context.Context, familyID, petID string, input CreateVaccinationInput) (*Vaccination, error) {
+	ctx, span := tracing.Start(ctx, "pets.CreateVaccination")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrNameRequired
+	}
+	if input.NextDueOn != nil && input.NextDueOn.Before(input.AdministeredOn) {
+		return nil, ErrInvalidNextDue
+	}
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return nil, err
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	vaccination := Vaccination{
+		ID:             newID,
+		PetID:          petID,
+		Name:           name,
+		AdministeredOn: input.AdministeredOn,
+		NextDueOn:      input.NextDueOn,
+		Notes:          trimmedOrNil(input.Notes),
+	}
+	if err := s.repo.CreateVaccination(ctx, &vaccination); err != nil {
+		return nil, err
+	}
+	return &vaccination, nil
+}
+
+func (s *Service) DeleteVaccination(ctx context.Context, familyID, petID, vaccinationID string) error {
+	ctx, span := tracing.Start(ctx, "pets.DeleteVaccination")
+	defer span.End()
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteVaccination(ctx, petID, vaccinationID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrVaccinationNotFound
+	}
+	return nil
+}
+
+func (s *Service) ListVetVisits(ctx context.Context, familyID, petID string) ([]VetVisit, error) {
+	ctx, span := tracing.Start(ctx, "pets.ListVetVisits")
+	defer span.End()
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVetVisits(ctx, petID)
+}
+
+// CreateVetVisit records a visit. When the visit has a cost, an expense is
+// created in the family's Pets category and linked to the visit. A cost above
+// the member's spending limit is queued for approval like any other expense,
+// and the visit is recorded without a linked expense.
+func (s *Service) CreateVetVisit(ctx context.Context, familyID, petID string, input CreateVetVisitInput) (*VetVisit, error) {
+	ctx, span := tracing.Start(ctx, "pets.CreateVetVisit")
+	defer span.End()
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+	currency := trimmedOrNil(input.Currency)
+	if input.Cost != nil && (*input.Cost <= 0 || currency == nil) {
+		return nil, ErrInvalidCost
+	}
+
+	pet, err := s.repo.GetPet(ctx, familyID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	visit := VetVisit{
+		ID:        newID,
+		PetID:     petID,
+		VisitDate: input.VisitDate,
+		Reason:    reason,
+		Clinic:    trimmedOrNil(input.Clinic),
+		Notes:     trimmedOrNil(input.Notes),
+	}
+
+	if input.Cost != nil {
+		upper := strings.ToUpper(*currency)
+		visit.Cost = input.Cost
+		visit.Currency = &upper
+
+		expenseID, err := s.recordVetExpense(ctx, familyID, *pet, visit, input)
+		if err != nil {
+			return nil, err
+		}
+		if expenseID != "" {
+			visit.ExpenseID = &expenseID
+		}
+	}
+
+	if err := s.repo.CreateVetVisit(ctx, &visit); err != nil {
+		if visit.ExpenseID != nil {
+			_ = s.expenses.DeleteExpense(ctx, familyID, *visit.ExpenseID)
+		}
+		return nil, err
+	}
+	return &visit, nil
+}
+
+// DeleteVetVisit removes the visit record. A linked expense is kept: it is a
+// real payment and remains editable from the expenses screen.
+func (s *Service) DeleteVetVisit(ctx context.Context, familyID, petID, visitID string) error {
+	ctx, span := tracing.Start(ctx, "pets.DeleteVetVisit")
+	defer span.End()
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteVetVisit(ctx, petID, visitID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrVetVisitNotFound
+	}
+	return nil
+}
+
+func (s *Service) ListSchedules(ctx context.Context, familyID, petID string) ([]CareSchedule, error) {
+	ctx, span := tracing.Start(ctx, "pets.ListSchedules")
+	defer span.End()
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSchedules(ctx, petID)
+}
+
+func (s *Service) CreateSchedule(ctx context.Context, familyID, petID string, input CreateScheduleInput) (*CareSchedule, error) {
+	ctx, span := tracing.Start(ctx, "pets.CreateSchedule")
+	defer span.End()
+
+	kind := strings.TrimSpace(strings.ToLower(input.Kind))
+	if kind != ScheduleKindFeeding && kind != ScheduleKindMedication {
+		return nil, ErrInvalidScheduleKind
+	}
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, ErrTitleRequired
+	}
+	if input.IntervalHours <= 0 || input.IntervalHours > MaxIntervalHours {
+		return nil, ErrInvalidInterval
+	}
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return nil, err
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	nextDueAt := s.now().UTC()
+	if input.StartsAt != nil {
+		nextDueAt = input.StartsAt.UTC()
+	}
+
+	schedule := CareSchedule{
+		ID:            newID,
+		PetID:         petID,
+		Kind:          kind,
+		Title:         title,
+		Dosage:        trimmedOrNil(input.Dosage),
+		IntervalHours: input.IntervalHours,
+		NextDueAt:     nextDueAt,
+	}
+	if err := s.repo.CreateSchedule(ctx, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// MarkScheduleDone records that the feeding or dose was given and moves the
+// next due time one interval after now.
+func (s *Service) MarkScheduleDone(ctx context.Context, familyID, petID, scheduleID string) (*CareSchedule, error) {
+	ctx, span := tracing.Start(ctx, "pets.MarkScheduleDone")
+	defer span.End()
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return nil, err
+	}
+	schedule, err := s.repo.GetSchedule(ctx, petID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	schedule.LastDoneAt = &now
+	schedule.NextDueAt = now.Add(time.Duration(schedule.IntervalHours) * time.Hour)
+	if err := s.repo.MarkScheduleDone(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *Service) DeleteSchedule(ctx context.Context, familyID, petID, scheduleID string) error {
+	ctx, span := tracing.Start(ctx, "pets.DeleteSchedule")
+	defer span.End()
+
+	if _, err := s.repo.GetPet(ctx, familyID, petID); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteSchedule(ctx, petID, scheduleID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// ListReminders returns vaccinations and care tasks due within the next
+// windowDays days, including overdue ones, ordered by due time.
+func (s *Service) ListReminders(ctx context.Context, familyID string, windowDays int) ([]Reminder, error) {
+	ctx, span := tracing.Start(ctx, "pets.ListReminders")
+	defer span.End()
+
+	if windowDays <= 0 {
+		windowDays = DefaultReminderDays
+	}
+	if windowDays > MaxReminderWindowDays {
+		windowDays = MaxReminderWindowDays
+	}
+	dueBefore := s.now().UTC().Add(time.Duration(windowDays) * 24 * time.Hour)
+
+	vaccinations, err := s.repo.ListDueVaccinations(ctx, familyID, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.repo.ListDueSchedules(ctx, familyID, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := append(vaccinations, schedules...)
+	sort.SliceStable(reminders, func(i, j int) bool {
+		return reminders[i].DueAt.Before(reminders[j].DueAt)
+	})
+	return reminders, nil
+}
+
+func (s *Service) recordVetExpense(ctx context.Context, familyID string, pet Pet, visit VetVisit, input CreateVetVisitInput) (string, error) {
+	categoryID, err := s.petsCategoryID(ctx, familyID)
+	if err != nil {
+		return "", err
+	}
+
+	expense, err := s.expenses.CreateExpense(ctx, expensesdomain.CreateExpenseInput{
+		FamilyID:     familyID,
+		UserID:       input.UserID,
+		Date:         visit.VisitDate,
+		Amount:       *visit.Cost,
+		Currency:     *visit.Currency,
+		BaseCurrency: input.BaseCurrency,
+		Title:        fmt.Sprintf("Vet: %s — %s", pet.Name, visit.Reason),
+		CategoryIDs:  []string{categoryID},
+
+		AllowedCurrencies: input.AllowedCurrencies,
+	})
+	if errors.Is(err, expensesdomain.ErrApprovalRequired) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return expense.ID, nil
+}
+
+func (s *Service) petsCategoryID(ctx context.Context, familyID string) (string, error) {
+	categories, err := s.expenses.ListCategories(ctx, familyID)
+	if err != nil {
+		return "", err
+	}
+	for _, category := range categories {
+		if strings.EqualFold(strings.TrimSpace(category.Name), ExpenseCategoryName) {
+			return category.ID, nil
+		}
+	}
+
+	emoji := expenseCategoryEmoji
+	category, err := s.expenses.CreateCategory(ctx, expensesdomain.CreateCategoryInput{
+		FamilyID: familyID,
+		Name:     ExpenseCategoryName,
+		Emoji:    &emoji,
+	})
+	if err != nil {
+		return "", err
+	}
+	return category.ID, nil
+}
+
+func applyPetInput(pet *Pet, name string, input PetInput) {
+	pet.Name = name
+	pet.Species = trimmedOrNil(input.Species)
+	pet.Breed = trimmedOrNil(input.Breed)
+	pet.BirthDate = input.BirthDate
+	pet.Notes = trimmedOrNil(input.Notes)
+}
+
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,309 @@
+package pets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testUserID   = "22222222-2222-2222-2222-222222222222"
+)
+
+type fakePetsRepo struct {
+	pets         map[string]*Pet
+	vaccinations []Vaccination
+	visits       []VetVisit
+	schedules    map[string]*CareSchedule
+	dueBefore    []time.Time
+}
+
+func newFakePetsRepo() *fakePetsRepo {
+	return &fakePetsRepo{
+		pets:      make(map[string]*Pet),
+		schedules: make(map[string]*CareSchedule),
+	}
+}
+
+func (r *fakePetsRepo) ListPets(_ context.Context, familyID string) ([]Pet, error) {
+	result := make([]Pet, 0, len(r.pets))
+	for _, pet := range r.pets {
+		if pet.FamilyID == familyID {
+			result = append(result, *pet)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakePetsRepo) GetPet(_ context.Context, familyID, petID string) (*Pet, error) {
+	pet, ok := r.pets[petID]
+	if !ok || pet.FamilyID != familyID {
+		return nil, ErrPetNotFound
+	}
+	cloned := *pet
+	return &cloned, nil
+}
+
+func (r *fakePetsRepo) CreatePet(_ context.Context, pet *Pet) error {
+	cloned := *pet
+	r.pets[pet.ID] = &cloned
+	return nil
+}
+
+func (r *fakePetsRepo) UpdatePet(_ context.Context, pet *Pet) error {
+	cloned := *pet
+	r.pets[pet.ID] = &cloned
+	return nil
+}
+
+func (r *fakePetsRepo) SoftDeletePet(_ context.Context, familyID, petID string) (bool, error) {
+	pet, ok := r.pets[petID]
+	if !ok || pet.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.pets, petID)
+	return true, nil
+}
+
+func (r *fakePetsRepo) ListVaccinations(_ context.Context, _ string) ([]Vaccination, error) {
+	return r.vaccinations, nil
+}
+
+func (r *fakePetsRepo) CreateVaccination(_ context.Context, vaccination *Vaccination) error {
+	r.vaccinations = append(r.vaccinations, *vaccination)
+	return nil
+}
+
+func (r *fakePetsRepo) DeleteVaccination(_ context.Context, _, _ string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakePetsRepo) ListVetVisits(_ context.Context, _ string) ([]VetVisit, error) {
+	return r.visits, nil
+}
+
+func (r *fakePetsRepo) CreateVetVisit(_ context.Context, visit *VetVisit) error {
+	r.visits = append(r.visits, *visit)
+	return nil
+}
+
+func (r *fakePetsRepo) DeleteVetVisit(_ context.Context, _, _ string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakePetsRepo) ListSchedules(_ context.Context, _ string) ([]CareSchedule, error) {
+	return nil, nil
+}
+
+func (r *fakePetsRepo) GetSchedule(_ context.Context, petID, scheduleID string) (*CareSchedule, error) {
+	schedule, ok := r.schedules[scheduleID]
+	if !ok || schedule.PetID != petID {
+		return nil, ErrScheduleNotFound
+	}
+	cloned := *schedule
+	return &cloned, nil
+}
+
+func (r *fakePetsRepo) CreateSchedule(_ context.Context, schedule *CareSchedule) error {
+	cloned := *schedule
+	r.schedules[schedule.ID] = &cloned
+	return nil
+}
+
+func (r *fakePetsRepo) MarkScheduleDone(_ context.Context, schedule *CareSchedule) error {
+	cloned := *schedule
+	r.schedules[schedule.ID] = &cloned
+	return nil
+}
+
+func (r *fakePetsRepo) DeleteSchedule(_ context.Context, _, _ string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakePetsRepo) ListDueVaccinations(_ context.Context, _ string, dueBefore time.Time) ([]Reminder, error) {
+	r.dueBefore = append(r.dueBefore, dueBefore)
+	return []Reminder{{Kind: ReminderKindVaccination, Title: "Rabies", DueAt: dueBefore.Add(-48 * time.Hour)}}, nil
+}
+
+func (r *fakePetsRepo) ListDueSchedules(_ context.Context, _ string, dueBefore time.Time) ([]Reminder, error) {
+	return []Reminder{{Kind: ScheduleKindMedication, Title: "Pill", DueAt: dueBefore.Add(-72 * time.Hour)}}, nil
+}
+
+type fakeExpenseRecorder struct {
+	categories []expensesdomain.Category
+	created    []expensesdomain.CreateExpenseInput
+}
+
+func (f *fakeExpenseRecorder) ListCategories(_ context.Context, _ string) ([]expensesdomain.Category, error) {
+	return f.categories, nil
+}
+
+func (f *fakeExpenseRecorder) CreateCategory(_ context.Context, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error) {
+	category := expensesdomain.Category{ID: "category-pets", FamilyID: input.FamilyID, Name: input.Name}
+	f.categories = append(f.categories, category)
+	return &category, nil
+}
+
+func (f *fakeExpenseRecorder) CreateExpense(_ context.Context, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error) {
+	f.created = append(f.created, input)
+	return &expensesdomain.ExpenseWithCategories{
+		Expense:     expensesdomain.Expense{ID: "expense-1"},
+		CategoryIDs: input.CategoryIDs,
+	}, nil
+}
+
+func (f *fakeExpenseRecorder) DeleteExpense(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func createTestPet(t *testing.T, service *Service) *Pet {
+	t.Helper()
+	pet, err := service.CreatePet(context.Background(), testFamilyID, PetInput{Name: " Rex "})
+	if err != nil {
+		t.Fatalf("create pet: %v", err)
+	}
+	return pet
+}
+
+func TestCreateVetVisitWithCostCreatesPetsExpense(t *testing.T) {
+	expenses := &fakeExpenseRecorder{}
+	service := NewService(newFakePetsRepo(), expenses)
+	pet := createTestPet(t, service)
+
+	cost := 45.5
+	currency := "byn"
+	visit, err := service.CreateVetVisit(context.Background(), testFamilyID, pet.ID, CreateVetVisitInput{
+		UserID:       testUserID,
+		BaseCurrency: "USD",
+		VisitDate:    time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC),
+		Reason:       "Checkup",
+		Cost:         &cost,
+		Currency:     &currency,
+	})
+	if err != nil {
+		t.Fatalf("create vet visit: %v", err)
+	}
+
+	if visit.ExpenseID == nil || *visit.ExpenseID != "expense-1" {
+		t.Fatalf("expected visit to be linked to expense, got %v", visit.ExpenseID)
+	}
+	if len(expenses.created) != 1 {
+		t.Fatalf("expected one expense, got %d", len(expenses.created))
+	}
+	created := expenses.created[0]
+	if created.Amount != cost || created.Currency != "BYN" || created.UserID != testUserID {
+		t.Fatalf("unexpected expense input: %#v", created)
+	}
+	if len(created.CategoryIDs) != 1 || created.CategoryIDs[0] != "category-pets" {
+		t.Fatalf("expected Pets category, got %v", created.CategoryIDs)
+	}
+
+	if _, err := service.CreateVetVisit(context.Background(), testFamilyID, pet.ID, CreateVetVisitInput{
+		UserID:    testUserID,
+		VisitDate: time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC),
+		Reason:    "Vaccine",
+		Cost:      &cost,
+		Currency:  &currency,
+	}); err != nil {
+		t.Fatalf("create second vet visit: %v", err)
+	}
+	if len(expenses.categories) != 1 {
+		t.Fatalf("expected Pets category to be reused, got %d categories", len(expenses.categories))
+	}
+}
+
+func TestCreateVetVisitWithoutCostSkipsExpense(t *testing.T) {
+	expenses := &fakeExpenseRecorder{}
+	service := NewService(newFakePetsRepo(), expenses)
+	pet := createTestPet(t, service)
+
+	visit, err := service.CreateVetVisit(context.Background(), testFamilyID, pet.ID, CreateVetVisitInput{
+		UserID:    testUserID,
+		VisitDate: time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC),
+		Reason:    "Checkup",
+	})
+	if err != nil {
+		t.Fatalf("create vet visit: %v", err)
+	}
+	if visit.ExpenseID != nil || len(expenses.created) != 0 {
+		t.Fatalf("expected no expense for visit without cost")
+	}
+
+	cost := 10.0
+	if _, err := service.CreateVetVisit(context.Background(), testFamilyID, pet.ID, CreateVetVisitInput{
+		VisitDate: time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC),
+		Reason:    "Checkup",
+		Cost:      &cost,
+	}); !errors.Is(err, ErrInvalidCost) {
+		t.Fatalf("expected ErrInvalidCost without currency, got %v", err)
+	}
+}
+
+func TestMarkScheduleDoneAdvancesNextDue(t *testing.T) {
+	now := time.Date(2026, 5, 10, 8, 0, 0, 0, time.UTC)
+	repo := newFakePetsRepo()
+	service := NewService(repo, &fakeExpenseRecorder{})
+	service.now = func() time.Time { return now }
+	pet := createTestPet(t, service)
+
+	schedule, err := service.CreateSchedule(context.Background(), testFamilyID, pet.ID, CreateScheduleInput{
+		Kind:          "Medication",
+		Title:         "Antibiotic",
+		IntervalHours: 12,
+	})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	if schedule.Kind != ScheduleKindMedication || !schedule.NextDueAt.Equal(now) {
+		t.Fatalf("unexpected schedule: %#v", schedule)
+	}
+
+	done, err := service.MarkScheduleDone(context.Background(), testFamilyID, pet.ID, schedule.ID)
+	if err != nil {
+		t.Fatalf("mark done: %v", err)
+	}
+	if done.LastDoneAt == nil || !done.LastDoneAt.Equal(now) || !done.NextDueAt.Equal(now.Add(12*time.Hour)) {
+		t.Fatalf("unexpected schedule after done: %#v", done)
+	}
+
+	if _, err := service.CreateSchedule(context.Background(), testFamilyID, pet.ID, CreateScheduleInput{
+		Kind:          "walk",
+		Title:         "Walk",
+		IntervalHours: 12,
+	}); !errors.Is(err, ErrInvalidScheduleKind) {
+		t.Fatalf("expected ErrInvalidScheduleKind, got %v", err)
+	}
+}
+
+func TestListRemindersMergesAndSorts(t *testing.T) {
+	now := time.Date(2026, 5, 10, 8, 0, 0, 0, time.UTC)
+	repo := newFakePetsRepo()
+	service := NewService(repo, &fakeExpenseRecorder{})
+	service.now = func() time.Time { return now }
+
+	reminders, err := service.ListReminders(context.Background(), testFamilyID, 0)
+	if err != nil {
+		t.Fatalf("list reminders: %v", err)
+	}
+
+	wantDueBefore := now.Add(DefaultReminderDays * 24 * time.Hour)
+	if len(repo.dueBefore) != 1 || !repo.dueBefore[0].Equal(wantDueBefore) {
+		t.Fatalf("expected default window, got %v", repo.dueBefore)
+	}
+	if len(reminders) != 2 || reminders[0].Title != "Pill" || reminders[1].Title != "Rabies" {
+		t.Fatalf("expected reminders sorted by due time, got %#v", reminders)
+	}
+}
+
+func TestPetAccessIsFamilyScoped(t *testing.T) {
+	service := NewService(newFakePetsRepo(), &fakeExpenseRecorder{})
+	pet := createTestPet(t, service)
+
+	if _, err := service.ListVaccinations(context.Background(), "99999999-9999-9999-9999-999999999999", pet.ID); !errors.Is(err, ErrPetNotFound) {
+		t.Fatalf("expected ErrPetNotFound for other family, got %v", err)
+	}
+}
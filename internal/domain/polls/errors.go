@@ -0,0 +1,13 @@
+package polls
+
+import "errors"
+
+var (
+	ErrPollNotFound    = errors.New("poll not found")
+	ErrOptionNotFound  = errors.New("poll option not found")
+	ErrInvalidQuestion = errors.New("invalid poll question")
+	ErrInvalidOptions  = errors.New("invalid poll options")
+	ErrInvalidDeadline = errors.New("poll deadline must be in the future")
+	ErrPollClosed      = errors.New("poll is closed")
+	ErrNotAllowed      = errors.New("only the poll creator or the family owner can do this")
+)
@@ -0,0 +1,124 @@
+package polls
+
+import "time"
+
+const (
+	// MaxQuestionLength and MaxOptionLength cap the poll texts, in
+	// characters.
+	MaxQuestionLength = 200
+	MaxOptionLength   = 100
+	// MinOptions and MaxOptions bound the choices of a poll.
+	MinOptions = 2
+	MaxOptions = 10
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Status filters polls by whether they still take votes.
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+	StatusAll    Status = "all"
+)
+
+// Poll is a family decision. Members vote for one option each and may change
+// their vote until the poll is closed by hand or its deadline passes. The
+// creator's name is a snapshot for the activity feed.
+type Poll struct {
+	ID          string `gorm:"type:uuid;primaryKey"`
+	FamilyID    string `gorm:"type:uuid;not null"`
+	CreatedBy   string `gorm:"type:uuid;not null"`
+	CreatorName string `gorm:"not null"`
+	Question    string `gorm:"not null"`
+	Deadline    *time.Time
+	ClosedAt    *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Options []Option `gorm:"foreignKey:PollID"`
+}
+
+func (Poll) TableName() string {
+	return "polls"
+}
+
+// Open reports whether the poll takes votes at now. A poll past its deadline
+// is closed even before the close job records it.
+func (p Poll) Open(now time.Time) bool {
+	return p.ClosedAt == nil && (p.Deadline == nil || now.Before(*p.Deadline))
+}
+
+type Option struct {
+	ID       string `gorm:"type:uuid;primaryKey"`
+	PollID   string `gorm:"type:uuid;not null"`
+	Text     string `gorm:"not null"`
+	Position int    `gorm:"not null"`
+}
+
+func (Option) TableName() string {
+	return "poll_options"
+}
+
+type Vote struct {
+	PollID   string    `gorm:"type:uuid;primaryKey"`
+	UserID   string    `gorm:"type:uuid;primaryKey"`
+	OptionID string    `gorm:"type:uuid;not null"`
+	VotedAt  time.Time `gorm:"not null"`
+}
+
+func (Vote) TableName() string {
+	return "poll_votes"
+}
+
+// OptionResult counts the votes of one option, in option order.
+type OptionResult struct {
+	Option   Option
+	Votes    int64
+	VoterIDs []string
+}
+
+// Results tallies a poll. Winners holds the options with the most votes,
+// several on a tie and none before the first vote.
+type Results struct {
+	Options    []OptionResult
+	TotalVotes int64
+	Winners    []string
+}
+
+// Summary is a poll with its results and the viewer's vote, empty if they
+// have not voted.
+type Summary struct {
+	Poll    Poll
+	Results Results
+	MyVote  string
+}
+
+type Creator struct {
+	ID   string
+	Name string
+}
+
+type CreateInput struct {
+	FamilyID string
+	Creator  Creator
+	Question string
+	Options  []string
+	Deadline *time.Time
+}
+
+type ListFilter struct {
+	Status Status
+	Limit  int
+	Offset int
+}
+
+// Closer is who closes a poll by hand. The creator and the family owner may.
+type Closer struct {
+	ID      string
+	Name    string
+	Email   string
+	IsOwner bool
+}
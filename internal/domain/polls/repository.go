@@ -0,0 +1,28 @@
+package polls
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// CreatePoll stores a poll with its options.
+	CreatePoll(ctx context.Context, poll *Poll) error
+	// GetPoll returns a poll of the family with its options in order.
+	GetPoll(ctx context.Context, familyID, pollID string) (*Poll, error)
+	// ListPolls returns a page of the family's polls, newest first. Open and
+	// closed are judged at now.
+	ListPolls(ctx context.Context, familyID string, filter ListFilter, now time.Time) ([]Poll, int64, error)
+	DeletePoll(ctx context.Context, familyID, pollID string) (bool, error)
+	// ClosePoll records closedAt unless the poll is closed already, and
+	// reports whether it did.
+	ClosePoll(ctx context.Context, pollID string, closedAt time.Time) (bool, error)
+	// ListDuePolls returns up to limit polls past their deadline that are
+	// not closed yet, with their options.
+	ListDuePolls(ctx context.Context, now time.Time, limit int) ([]Poll, error)
+
+	// SaveVote replaces the member's vote on the poll.
+	SaveVote(ctx context.Context, vote *Vote) error
+	DeleteVote(ctx context.Context, pollID, userID string) (bool, error)
+	ListVotes(ctx context.Context, pollIDs []string) ([]Vote, error)
+}
@@ -0,0 +1,408 @@
+package polls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// closeBatchSize is how many due polls CloseDue closes per query.
+const closeBatchSize = 100
+
+// ActivityRecorder announces closed polls in the family activity feed,
+// which is where members are told about family events.
+type ActivityRecorder interface {
+	RecordPollClosed(ctx context.Context, familyID string, actor activitydomain.Actor, pollID, summary string) (*activitydomain.Event, error)
+}
+
+type Service struct {
+	repo     Repository
+	activity ActivityRecorder
+	now      func() time.Time
+}
+
+func NewService(repo Repository, activity ActivityRecorder) *Service {
+	return &Service{
+		repo:     repo,
+		activity: activity,
+		now:      time.Now,
+	}
+}
+
+func (s *Service) Create(ctx context.Context, input CreateInput) (*Summary, error) {
+	ctx, span := tracing.Start(ctx, "polls.Create")
+	defer span.End()
+
+	question := strings.TrimSpace(input.Question)
+	if question == "" || utf8.RuneCountInString(question) > MaxQuestionLength {
+		return nil, ErrInvalidQuestion
+	}
+	texts, err := normalizeOptions(input.Options)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	var deadline *time.Time
+	if input.Deadline != nil {
+		if !input.Deadline.After(now) {
+			return nil, ErrInvalidDeadline
+		}
+		value := input.Deadline.UTC()
+		deadline = &value
+	}
+
+	pollID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	poll := Poll{
+		ID:          pollID,
+		FamilyID:    input.FamilyID,
+		CreatedBy:   input.Creator.ID,
+		CreatorName: strings.TrimSpace(input.Creator.Name),
+		Question:    question,
+		Deadline:    deadline,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for i, text := range texts {
+		optionID, err := id.New()
+		if err != nil {
+			return nil, err
+		}
+		poll.Options = append(poll.Options, Option{ID: optionID, PollID: pollID, Text: text, Position: i})
+	}
+	if err := s.repo.CreatePoll(ctx, &poll); err != nil {
+		return nil, err
+	}
+	return &Summary{Poll: poll, Results: tally(poll, nil)}, nil
+}
+
+// List returns a page of the family's polls, newest first, each with its
+// results and the viewer's vote.
+func (s *Service) List(ctx context.Context, familyID, viewerID string, filter ListFilter) ([]Summary, int64, error) {
+	ctx, span := tracing.Start(ctx, "polls.List")
+	defer span.End()
+
+	switch filter.Status {
+	case "":
+		filter.Status = StatusAll
+	case StatusOpen, StatusClosed, StatusAll:
+	default:
+		return nil, 0, fmt.Errorf("unknown poll status %q", filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	polls, total, err := s.repo.ListPolls(ctx, familyID, filter, s.now().UTC())
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(polls) == 0 {
+		return []Summary{}, total, nil
+	}
+	pollIDs := make([]string, 0, len(polls))
+	for _, poll := range polls {
+		pollIDs = append(pollIDs, poll.ID)
+	}
+	votes, err := s.repo.ListVotes(ctx, pollIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	byPoll := make(map[string][]Vote, len(polls))
+	for _, vote := range votes {
+		byPoll[vote.PollID] = append(byPoll[vote.PollID], vote)
+	}
+
+	summaries := make([]Summary, 0, len(polls))
+	for _, poll := range polls {
+		summaries = append(summaries, summarize(poll, byPoll[poll.ID], viewerID))
+	}
+	return summaries, total, nil
+}
+
+// Get returns a poll with its results and the viewer's vote.
+func (s *Service) Get(ctx context.Context, familyID, pollID, viewerID string) (*Summary, error) {
+	ctx, span := tracing.Start(ctx, "polls.Get")
+	defer span.End()
+
+	poll, err := s.repo.GetPoll(ctx, familyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, *poll, viewerID)
+}
+
+// Vote records the member's choice, replacing an earlier one.
+func (s *Service) Vote(ctx context.Context, familyID, pollID, userID, optionID string) (*Summary, error) {
+	ctx, span := tracing.Start(ctx, "polls.Vote")
+	defer span.End()
+
+	poll, err := s.openPoll(ctx, familyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasOption(*poll, optionID) {
+		return nil, ErrOptionNotFound
+	}
+	if err := s.repo.SaveVote(ctx, &Vote{PollID: poll.ID, UserID: userID, OptionID: optionID, VotedAt: s.now().UTC()}); err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, *poll, userID)
+}
+
+// Unvote withdraws the member's vote while the poll is open.
+func (s *Service) Unvote(ctx context.Context, familyID, pollID, userID string) (*Summary, error) {
+	ctx, span := tracing.Start(ctx, "polls.Unvote")
+	defer span.End()
+
+	poll, err := s.openPoll(ctx, familyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.DeleteVote(ctx, poll.ID, userID); err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, *poll, userID)
+}
+
+// Close ends the voting before the deadline and announces the outcome.
+func (s *Service) Close(ctx context.Context, familyID, pollID string, closer Closer) (*Summary, error) {
+	ctx, span := tracing.Start(ctx, "polls.Close")
+	defer span.End()
+
+	poll, err := s.repo.GetPoll(ctx, familyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if poll.CreatedBy != closer.ID && !closer.IsOwner {
+		return nil, ErrNotAllowed
+	}
+	if poll.ClosedAt != nil {
+		return nil, ErrPollClosed
+	}
+
+	closedAt := s.now().UTC()
+	// A poll past its deadline stopped taking votes then.
+	if poll.Deadline != nil && poll.Deadline.Before(closedAt) {
+		closedAt = *poll.Deadline
+	}
+	closed, err := s.repo.ClosePoll(ctx, poll.ID, closedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrPollClosed
+	}
+	poll.ClosedAt = &closedAt
+
+	summary, err := s.summary(ctx, *poll, closer.ID)
+	if err != nil {
+		return nil, err
+	}
+	actor := activitydomain.Actor{ID: closer.ID, Name: closer.Name, Email: closer.Email}
+	if err := s.announce(ctx, summary, actor); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Delete removes a poll with its votes.
+func (s *Service) Delete(ctx context.Context, familyID, pollID string, closer Closer) error {
+	ctx, span := tracing.Start(ctx, "polls.Delete")
+	defer span.End()
+
+	poll, err := s.repo.GetPoll(ctx, familyID, pollID)
+	if err != nil {
+		return err
+	}
+	if poll.CreatedBy != closer.ID && !closer.IsOwner {
+		return ErrNotAllowed
+	}
+	deleted, err := s.repo.DeletePoll(ctx, familyID, poll.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPollNotFound
+	}
+	return nil
+}
+
+// CloseDue closes the polls whose deadline has passed and announces each
+// outcome on behalf of the poll's creator. A failed announcement does not
+// stop the others.
+func (s *Service) CloseDue(ctx context.Context) ([]Summary, error) {
+	ctx, span := tracing.Start(ctx, "polls.CloseDue")
+	defer span.End()
+
+	var (
+		closed []Summary
+		errs   []error
+	)
+	for {
+		due, err := s.repo.ListDuePolls(ctx, s.now().UTC(), closeBatchSize)
+		if err != nil {
+			return closed, err
+		}
+		for _, poll := range due {
+			ok, err := s.repo.ClosePoll(ctx, poll.ID, *poll.Deadline)
+			if err != nil {
+				return closed, err
+			}
+			if !ok {
+				continue
+			}
+			poll.ClosedAt = poll.Deadline
+
+			summary, err := s.summary(ctx, poll, "")
+			if err != nil {
+				return closed, err
+			}
+			closed = append(closed, *summary)
+			actor := activitydomain.Actor{ID: poll.CreatedBy, Name: poll.CreatorName}
+			if err := s.announce(ctx, summary, actor); err != nil {
+				errs = append(errs, fmt.Errorf("announce poll %s: %w", poll.ID, err))
+			}
+		}
+		if len(due) < closeBatchSize {
+			return closed, errors.Join(errs...)
+		}
+	}
+}
+
+func (s *Service) openPoll(ctx context.Context, familyID, pollID string) (*Poll, error) {
+	poll, err := s.repo.GetPoll(ctx, familyID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !poll.Open(s.now()) {
+		return nil, ErrPollClosed
+	}
+	return poll, nil
+}
+
+func (s *Service) summary(ctx context.Context, poll Poll, viewerID string) (*Summary, error) {
+	votes, err := s.repo.ListVotes(ctx, []string{poll.ID})
+	if err != nil {
+		return nil, err
+	}
+	summary := summarize(poll, votes, viewerID)
+	return &summary, nil
+}
+
+func (s *Service) announce(ctx context.Context, summary *Summary, actor activitydomain.Actor) error {
+	_, err := s.activity.RecordPollClosed(ctx, summary.Poll.FamilyID, actor, summary.Poll.ID, summary.Poll.Question+": "+outcome(summary.Results))
+	return err
+}
+
+func summarize(poll Poll, votes []Vote, viewerID string) Summary {
+	summary := Summary{Poll: poll, Results: tally(poll, votes)}
+	for _, vote := range votes {
+		if viewerID != "" && vote.UserID == viewerID {
+			summary.MyVote = vote.OptionID
+		}
+	}
+	return summary
+}
+
+// tally counts votes per option. Votes for options no longer on the poll
+// are ignored.
+func tally(poll Poll, votes []Vote) Results {
+	results := Results{Options: make([]OptionResult, 0, len(poll.Options))}
+	index := make(map[string]int, len(poll.Options))
+	for i, option := range poll.Options {
+		index[option.ID] = i
+		results.Options = append(results.Options, OptionResult{Option: option, VoterIDs: []string{}})
+	}
+	for _, vote := range votes {
+		i, ok := index[vote.OptionID]
+		if !ok {
+			continue
+		}
+		results.Options[i].Votes++
+		results.Options[i].VoterIDs = append(results.Options[i].VoterIDs, vote.UserID)
+		results.TotalVotes++
+	}
+
+	var most int64
+	for _, option := range results.Options {
+		most = max(most, option.Votes)
+	}
+	results.Winners = []string{}
+	if most > 0 {
+		for _, option := range results.Options {
+			if option.Votes == most {
+				results.Winners = append(results.Winners, option.Option.ID)
+			}
+		}
+	}
+	return results
+}
+
+// outcome describes the result for the activity feed.
+func outcome(results Results) string {
+	if len(results.Winners) == 0 {
+		return "no votes"
+	}
+	var names []string
+	var votes int64
+	for _, option := range results.Options {
+		for _, winner := range results.Winners {
+			if option.Option.ID == winner {
+				names = append(names, option.Option.Text)
+				votes = option.Votes
+			}
+		}
+	}
+	if len(names) > 1 {
+		return "tie between " + strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s (%d of %d votes)", names[0], votes, results.TotalVotes)
+}
+
+func hasOption(poll Poll, optionID string) bool {
+	for _, option := range poll.Options {
+		if option.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeOptions trims the option texts and rejects blank, overlong and
+// duplicate ones, ignoring case.
+func normalizeOptions(values []string) ([]string, error) {
+	if len(values) < MinOptions || len(values) > MaxOptions {
+		return nil, ErrInvalidOptions
+	}
+	seen := make(map[string]struct{}, len(values))
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		text := strings.TrimSpace(value)
+		if text == "" || utf8.RuneCountInString(text) > MaxOptionLength {
+			return nil, ErrInvalidOptions
+		}
+		key := strings.ToLower(text)
+		if _, ok := seen[key]; ok {
+			return nil, ErrInvalidOptions
+		}
+		seen[key] = struct{}{}
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
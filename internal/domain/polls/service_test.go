@@ -0,0 +1,204 @@
+package polls
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+)
+
+type fakePollsRepo struct {
+	Repository
+	polls map[string]*Poll
+	votes map[string]Vote
+}
+
+func newFakePollsRepo() *fakePollsRepo {
+	return &fakePollsRepo{polls: make(map[string]*Poll), votes: make(map[string]Vote)}
+}
+
+func (r *fakePollsRepo) CreatePoll(_ context.Context, poll *Poll) error {
+	stored := *poll
+	r.polls[poll.ID] = &stored
+	return nil
+}
+
+func (r *fakePollsRepo) GetPoll(_ context.Context, familyID, pollID string) (*Poll, error) {
+	poll, ok := r.polls[pollID]
+	if !ok || poll.FamilyID != familyID {
+		return nil, ErrPollNotFound
+	}
+	copied := *poll
+	return &copied, nil
+}
+
+func (r *fakePollsRepo) ClosePoll(_ context.Context, pollID string, closedAt time.Time) (bool, error) {
+	poll := r.polls[pollID]
+	if poll.ClosedAt != nil {
+		return false, nil
+	}
+	poll.ClosedAt = &closedAt
+	return true, nil
+}
+
+func (r *fakePollsRepo) ListDuePolls(_ context.Context, now time.Time, _ int) ([]Poll, error) {
+	var due []Poll
+	for _, poll := range r.polls {
+		if poll.ClosedAt == nil && poll.Deadline != nil && !poll.Deadline.After(now) {
+			due = append(due, *poll)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakePollsRepo) SaveVote(_ context.Context, vote *Vote) error {
+	r.votes[vote.PollID+"/"+vote.UserID] = *vote
+	return nil
+}
+
+func (r *fakePollsRepo) ListVotes(_ context.Context, pollIDs []string) ([]Vote, error) {
+	var votes []Vote
+	for _, vote := range r.votes {
+		for _, pollID := range pollIDs {
+			if vote.PollID == pollID {
+				votes = append(votes, vote)
+			}
+		}
+	}
+	return votes, nil
+}
+
+type fakeActivity struct {
+	summaries []string
+	actors    []string
+}
+
+func (a *fakeActivity) RecordPollClosed(_ context.Context, _ string, actor activitydomain.Actor, _, summary string) (*activitydomain.Event, error) {
+	a.summaries = append(a.summaries, summary)
+	a.actors = append(a.actors, actor.ID)
+	return &activitydomain.Event{}, nil
+}
+
+func newTestService(now time.Time) (*Service, *fakePollsRepo, *fakeActivity) {
+	repo := newFakePollsRepo()
+	activity := &fakeActivity{}
+	service := NewService(repo, activity)
+	service.now = func() time.Time { return now }
+	return service, repo, activity
+}
+
+func createDinnerPoll(t *testing.T, service *Service, deadline *time.Time) *Summary {
+	t.Helper()
+	summary, err := service.Create(context.Background(), CreateInput{
+		FamilyID: "family-1",
+		Creator:  Creator{ID: "user-1", Name: "Alice"},
+		Question: " Where do we order dinner? ",
+		Options:  []string{"Pizza", " Sushi "},
+		Deadline: deadline,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return summary
+}
+
+func TestCreateValidatesPoll(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	service, _, _ := newTestService(now)
+
+	summary := createDinnerPoll(t, service, nil)
+	if summary.Poll.Question != "Where do we order dinner?" || summary.Poll.Options[1].Text != "Sushi" || summary.Poll.Options[1].Position != 1 {
+		t.Fatalf("poll = %+v", summary.Poll)
+	}
+
+	past := now.Add(-time.Minute)
+	for name, input := range map[string]CreateInput{
+		"one option":     {Question: "Dinner?", Options: []string{"Pizza"}},
+		"duplicate":      {Question: "Dinner?", Options: []string{"Pizza", "pizza"}},
+		"blank question": {Question: " ", Options: []string{"Pizza", "Sushi"}},
+		"past deadline":  {Question: "Dinner?", Options: []string{"Pizza", "Sushi"}, Deadline: &past},
+	} {
+		if _, err := service.Create(context.Background(), input); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestVoteReplacesEarlierVoteUntilDeadline(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(time.Hour)
+	service, _, _ := newTestService(now)
+	poll := createDinnerPoll(t, service, &deadline).Poll
+	pizza, sushi := poll.Options[0].ID, poll.Options[1].ID
+
+	if _, err := service.Vote(context.Background(), "family-1", poll.ID, "user-2", pizza); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	summary, err := service.Vote(context.Background(), "family-1", poll.ID, "user-2", sushi)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.MyVote != sushi || summary.Results.TotalVotes != 1 || !reflect.DeepEqual(summary.Results.Winners, []string{sushi}) {
+		t.Fatalf("summary = %+v", summary)
+	}
+	if _, err := service.Vote(context.Background(), "family-1", poll.ID, "user-2", "other"); !errors.Is(err, ErrOptionNotFound) {
+		t.Fatalf("unknown option error = %v, want ErrOptionNotFound", err)
+	}
+
+	service.now = func() time.Time { return deadline }
+	if _, err := service.Vote(context.Background(), "family-1", poll.ID, "user-3", pizza); !errors.Is(err, ErrPollClosed) {
+		t.Fatalf("vote at deadline error = %v, want ErrPollClosed", err)
+	}
+}
+
+func TestCloseDueAnnouncesOutcome(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(time.Hour)
+	service, repo, activity := newTestService(now)
+	poll := createDinnerPoll(t, service, &deadline).Poll
+	for _, userID := range []string{"user-1", "user-2"} {
+		if _, err := service.Vote(context.Background(), "family-1", poll.ID, userID, poll.Options[1].ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	service.now = func() time.Time { return deadline.Add(time.Minute) }
+	closed, err := service.CloseDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(closed) != 1 || !repo.polls[poll.ID].ClosedAt.Equal(deadline) {
+		t.Fatalf("closed = %+v", closed)
+	}
+	want := []string{"Where do we order dinner?: Sushi (2 of 2 votes)"}
+	if !reflect.DeepEqual(activity.summaries, want) || activity.actors[0] != "user-1" {
+		t.Fatalf("announced %v by %v, want %v", activity.summaries, activity.actors, want)
+	}
+
+	if closed, _ := service.CloseDue(context.Background()); len(closed) != 0 {
+		t.Fatalf("closed again: %+v", closed)
+	}
+}
+
+func TestCloseIsLimitedToCreatorAndOwner(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	service, _, activity := newTestService(now)
+	poll := createDinnerPoll(t, service, nil).Poll
+
+	if _, err := service.Close(context.Background(), "family-1", poll.ID, Closer{ID: "user-2"}); !errors.Is(err, ErrNotAllowed) {
+		t.Fatalf("close by member error = %v, want ErrNotAllowed", err)
+	}
+	summary, err := service.Close(context.Background(), "family-1", poll.ID, Closer{ID: "user-3", IsOwner: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Poll.ClosedAt == nil || !reflect.DeepEqual(activity.summaries, []string{"Where do we order dinner?: no votes"}) {
+		t.Fatalf("summary = %+v, announced %v", summary.Poll, activity.summaries)
+	}
+	if _, err := service.Close(context.Background(), "family-1", poll.ID, Closer{ID: "user-1"}); !errors.Is(err, ErrPollClosed) {
+		t.Fatalf("second close error = %v, want ErrPollClosed", err)
+	}
+}
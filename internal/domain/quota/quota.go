@@ -0,0 +1,57 @@
+// Package quota holds the per-family write limits shared instances enforce
+// against runaway clients, and the error services return when one is hit.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Names identify a quota in errors and API responses.
+const (
+	ExpensesPerDay        = "expenses_per_day"
+	TodoItemsPerList      = "todo_items_per_list"
+	TodoListsPerFamily    = "todo_lists_per_family"
+	SyncOperationsPerHour = "sync_operations_per_hour"
+)
+
+// Windows of the rolling quotas.
+const (
+	ExpensesWindow       = 24 * time.Hour
+	SyncOperationsWindow = time.Hour
+)
+
+// Limits are the per-family quotas. Zero leaves a quota unlimited.
+type Limits struct {
+	ExpensesPerDay        int
+	TodoItemsPerList      int
+	TodoListsPerFamily    int
+	SyncOperationsPerHour int
+}
+
+var ErrExceeded = errors.New("quota exceeded")
+
+// ExceededError names the quota a write would exceed. Window is set for
+// rolling quotas, which free up over time; capacity quotas such as items per
+// list only free up when something is deleted.
+type ExceededError struct {
+	Quota  string
+	Limit  int
+	Window time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s: %s limit is %d", ErrExceeded, e.Quota, e.Limit)
+}
+
+func (e *ExceededError) Unwrap() error { return ErrExceeded }
+
+// Check returns an ExceededError when adding more writes to used would exceed
+// limit. A limit of zero or less never fails.
+func Check(name string, limit int, window time.Duration, used int64, adding int) error {
+	if limit <= 0 || used+int64(adding) <= int64(limit) {
+		return nil
+	}
+	return &ExceededError{Quota: name, Limit: limit, Window: window}
+}
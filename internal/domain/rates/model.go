@@ -9,6 +9,9 @@ import (
 var (
 	ErrInvalidCurrency  = errors.New("invalid currency")
 	ErrRateNotAvailable = errors.New("rate not available")
+	// ErrNoReferenceProviders is returned by a refresh when no reference
+	// rate provider is configured.
+	ErrNoReferenceProviders = errors.New("no reference rate providers configured")
 )
 
 type Currency struct {
@@ -37,3 +40,47 @@ type Provider interface {
 	ListCurrencies(ctx context.Context) ([]Currency, error)
 	GetBYNRate(ctx context.Context, currency string, onDate time.Time) (BYNRate, error)
 }
+
+// ReferenceRates are one day's rates against the euro: Rates holds how many
+// units of each currency one euro buys.
+type ReferenceRates struct {
+	Date   time.Time
+	Source string
+	Rates  map[string]float64
+}
+
+// ReferenceProvider publishes daily euro reference rates for many currencies
+// at once, such as the ECB.
+type ReferenceProvider interface {
+	Name() string
+	// GetEURRates returns the latest rates published on or before onDate,
+	// or ErrRateNotAvailable.
+	GetEURRates(ctx context.Context, onDate time.Time) (ReferenceRates, error)
+}
+
+// Repository persists reference rates so conversions keep working when the
+// providers are unreachable.
+type Repository interface {
+	// SaveReferenceRates stores the rates of currencies the app knows and
+	// returns how many were stored.
+	SaveReferenceRates(ctx context.Context, rates ReferenceRates) (int, error)
+	// GetReferenceRates returns the newest rates dated between from and to,
+	// or ErrRateNotAvailable.
+	GetReferenceRates(ctx context.Context, from, to time.Time) (ReferenceRates, error)
+}
+
+// RateTable converts from Base: Rates holds how many units of each currency
+// one unit of Base buys.
+type RateTable struct {
+	Base   string
+	Date   time.Time
+	Source string
+	Rates  map[string]float64
+}
+
+// RefreshResult reports the reference rates a refresh stored.
+type RefreshResult struct {
+	Date       time.Time
+	Source     string
+	Currencies int
+}
@@ -2,10 +2,13 @@ package rates
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
+
+	"family-app-go/pkg/tracing"
 )
 
 type Config struct {
@@ -15,7 +18,9 @@ type Config struct {
 }
 
 type Service struct {
-	provider Provider
+	provider           Provider
+	repo               Repository
+	referenceProviders []ReferenceProvider
 
 	rateCacheTTL       time.Duration
 	currenciesCacheTTL time.Duration
@@ -27,6 +32,9 @@ type Service struct {
 	currenciesMu       sync.RWMutex
 	currenciesCache    []Currency
 	currenciesExpireAt time.Time
+
+	referenceMu    sync.RWMutex
+	referenceCache map[string]cacheItem[ReferenceRates]
 }
 
 type cacheItem[T any] struct {
@@ -35,6 +43,13 @@ type cacheItem[T any] struct {
 }
 
 func NewService(provider Provider, cfg Config) *Service {
+	return NewServiceWithReferenceRates(provider, nil, nil, cfg)
+}
+
+// NewServiceWithReferenceRates also serves daily euro reference rates: they
+// are stored in repo, fetched from the first of referenceProviders that has
+// them, and used for conversions the primary provider cannot make.
+func NewServiceWithReferenceRates(provider Provider, repo Repository, referenceProviders []ReferenceProvider, cfg Config) *Service {
 	if cfg.RateCacheTTL <= 0 {
 		cfg.RateCacheTTL = 12 * time.Hour
 	}
@@ -47,14 +62,20 @@ func NewService(provider Provider, cfg Config) *Service {
 
 	return &Service{
 		provider:           provider,
+		repo:               repo,
+		referenceProviders: referenceProviders,
 		rateCacheTTL:       cfg.RateCacheTTL,
 		currenciesCacheTTL: cfg.CurrenciesCacheTTL,
 		fallbackDays:       cfg.FallbackDays,
 		rateCache:          make(map[string]cacheItem[Quote]),
+		referenceCache:     make(map[string]cacheItem[ReferenceRates]),
 	}
 }
 
 func (s *Service) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	ctx, span := tracing.Start(ctx, "rates.ListCurrencies")
+	defer span.End()
+
 	now := time.Now()
 
 	s.currenciesMu.RLock()
@@ -84,6 +105,9 @@ func (s *Service) ListCurrencies(ctx context.Context) ([]Currency, error) {
 }
 
 func (s *Service) GetRate(ctx context.Context, from, to string, onDate time.Time) (Quote, error) {
+	ctx, span := tracing.Start(ctx, "rates.GetRate")
+	defer span.End()
+
 	fromCode, err := normalizeCurrency(from)
 	if err != nil {
 		return Quote{}, err
@@ -113,6 +137,22 @@ func (s *Service) GetRate(ctx context.Context, from, to string, onDate time.Time
 		return cached, nil
 	}
 
+	quote, err := s.nbrbQuote(ctx, fromCode, toCode, onDate)
+	if err != nil {
+		// Currencies the NBRB does not quote, or an NBRB outage, fall back
+		// to the stored reference rates.
+		reference, referenceErr := s.referenceQuote(ctx, fromCode, toCode, onDate)
+		if referenceErr != nil {
+			return Quote{}, err
+		}
+		quote = reference
+	}
+
+	s.setQuoteCache(cacheKey, quote, time.Now().Add(s.rateCacheTTL))
+	return quote, nil
+}
+
+func (s *Service) nbrbQuote(ctx context.Context, fromCode, toCode string, onDate time.Time) (Quote, error) {
 	var lastErr error
 	for offset := 0; offset <= s.fallbackDays; offset++ {
 		rateDate := onDate.AddDate(0, 0, -offset)
@@ -135,16 +175,13 @@ func (s *Service) GetRate(ctx context.Context, from, to string, onDate time.Time
 			return Quote{}, err
 		}
 
-		quote := Quote{
+		return Quote{
 			From:   fromCode,
 			To:     toCode,
 			Rate:   fromBYN / toBYN,
 			Date:   rateDate,
 			Source: "nbrb",
-		}
-
-		s.setQuoteCache(cacheKey, quote, time.Now().Add(s.rateCacheTTL))
-		return quote, nil
+		}, nil
 	}
 
 	if lastErr != nil {
@@ -153,6 +190,147 @@ func (s *Service) GetRate(ctx context.Context, from, to string, onDate time.Time
 	return Quote{}, ErrRateNotAvailable
 }
 
+func (s *Service) referenceQuote(ctx context.Context, fromCode, toCode string, onDate time.Time) (Quote, error) {
+	table, err := s.GetRates(ctx, fromCode, onDate)
+	if err != nil {
+		return Quote{}, err
+	}
+	rate, ok := table.Rates[toCode]
+	if !ok {
+		return Quote{}, ErrRateNotAvailable
+	}
+	return Quote{
+		From:   fromCode,
+		To:     toCode,
+		Rate:   rate,
+		Date:   table.Date,
+		Source: table.Source,
+	}, nil
+}
+
+// GetRates returns the reference rates of every known currency against base
+// on onDate, or on the closest earlier day within the fallback window.
+func (s *Service) GetRates(ctx context.Context, base string, onDate time.Time) (RateTable, error) {
+	ctx, span := tracing.Start(ctx, "rates.GetRates")
+	defer span.End()
+
+	baseCode, err := normalizeCurrency(base)
+	if err != nil {
+		return RateTable{}, err
+	}
+	if onDate.IsZero() {
+		return RateTable{}, fmt.Errorf("date is required")
+	}
+
+	reference, err := s.referenceRates(ctx, dateOnlyUTC(onDate))
+	if err != nil {
+		return RateTable{}, err
+	}
+
+	baseRate := 1.0
+	if baseCode != referenceBase {
+		baseRate = reference.Rates[baseCode]
+		if baseRate <= 0 {
+			return RateTable{}, ErrRateNotAvailable
+		}
+	}
+
+	table := RateTable{
+		Base:   baseCode,
+		Date:   reference.Date,
+		Source: reference.Source,
+		Rates:  make(map[string]float64, len(reference.Rates)+1),
+	}
+	table.Rates[referenceBase] = 1 / baseRate
+	for code, rate := range reference.Rates {
+		table.Rates[code] = rate / baseRate
+	}
+	table.Rates[baseCode] = 1
+	return table, nil
+}
+
+// HasReferenceProviders reports whether reference rates can be refreshed.
+func (s *Service) HasReferenceProviders() bool {
+	return s.repo != nil && len(s.referenceProviders) > 0
+}
+
+// RefreshReferenceRates fetches the reference rates for onDate from the first
+// provider that has them and stores them.
+func (s *Service) RefreshReferenceRates(ctx context.Context, onDate time.Time) (*RefreshResult, error) {
+	ctx, span := tracing.Start(ctx, "rates.RefreshReferenceRates")
+	defer span.End()
+
+	if !s.HasReferenceProviders() {
+		return nil, ErrNoReferenceProviders
+	}
+
+	fetched, stored, err := s.fetchReferenceRates(ctx, dateOnlyUTC(onDate))
+	if err != nil {
+		return nil, err
+	}
+	return &RefreshResult{Date: fetched.Date, Source: fetched.Source, Currencies: stored}, nil
+}
+
+// referenceRates prefers stored rates and only asks the providers when none
+// are stored for the fallback window.
+func (s *Service) referenceRates(ctx context.Context, onDate time.Time) (ReferenceRates, error) {
+	if s.repo == nil {
+		return ReferenceRates{}, ErrRateNotAvailable
+	}
+
+	key := onDate.Format("2006-01-02")
+	now := time.Now()
+	s.referenceMu.RLock()
+	item, ok := s.referenceCache[key]
+	s.referenceMu.RUnlock()
+	if ok && item.expiresAt.After(now) {
+		return item.value, nil
+	}
+
+	from := onDate.AddDate(0, 0, -s.fallbackDays)
+	reference, err := s.repo.GetReferenceRates(ctx, from, onDate)
+	if errors.Is(err, ErrRateNotAvailable) && len(s.referenceProviders) > 0 && !onDate.After(dateOnlyUTC(now)) {
+		if _, _, err = s.fetchReferenceRates(ctx, onDate); err == nil {
+			reference, err = s.repo.GetReferenceRates(ctx, from, onDate)
+		}
+	}
+	if err != nil {
+		return ReferenceRates{}, err
+	}
+
+	s.referenceMu.Lock()
+	s.referenceCache[key] = cacheItem[ReferenceRates]{value: reference, expiresAt: now.Add(s.rateCacheTTL)}
+	s.referenceMu.Unlock()
+	return reference, nil
+}
+
+func (s *Service) fetchReferenceRates(ctx context.Context, onDate time.Time) (ReferenceRates, int, error) {
+	var errs []error
+	for _, provider := range s.referenceProviders {
+		fetched, err := provider.GetEURRates(ctx, onDate)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		if fetched.Source == "" {
+			fetched.Source = provider.Name()
+		}
+		fetched.Date = dateOnlyUTC(fetched.Date)
+
+		stored, err := s.repo.SaveReferenceRates(ctx, fetched)
+		if err != nil {
+			return ReferenceRates{}, 0, err
+		}
+
+		// Cached lookups may have fallen back to an earlier day.
+		s.referenceMu.Lock()
+		clear(s.referenceCache)
+		s.referenceMu.Unlock()
+		return fetched, stored, nil
+	}
+	return ReferenceRates{}, 0, errors.Join(errs...)
+}
+
 func (s *Service) getBYNPerUnitOnDate(ctx context.Context, currency string, onDate time.Time) (float64, error) {
 	if currency == "BYN" {
 		return 1, nil
@@ -195,6 +373,9 @@ func (s *Service) setQuoteCache(key string, quote Quote, expiresAt time.Time) {
 	s.rateMu.Unlock()
 }
 
+// referenceBase is the currency reference rates are quoted against.
+const referenceBase = "EUR"
+
 func normalizeCurrency(value string) (string, error) {
 	value = strings.ToUpper(strings.TrimSpace(value))
 	if len(value) != 3 {
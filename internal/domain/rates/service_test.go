@@ -3,6 +3,7 @@ package rates
 import (
 	"context"
 	"errors"
+	"math"
 	"testing"
 	"time"
 )
@@ -94,3 +95,127 @@ func TestListCurrenciesAddsBYN(t *testing.T) {
 		}
 	}
 }
+
+type fakeRatesRepo struct {
+	stored []ReferenceRates
+}
+
+func (f *fakeRatesRepo) SaveReferenceRates(_ context.Context, rates ReferenceRates) (int, error) {
+	f.stored = append(f.stored, rates)
+	return len(rates.Rates), nil
+}
+
+func (f *fakeRatesRepo) GetReferenceRates(_ context.Context, from, to time.Time) (ReferenceRates, error) {
+	var found *ReferenceRates
+	for i := range f.stored {
+		rates := &f.stored[i]
+		if rates.Date.Before(from) || rates.Date.After(to) {
+			continue
+		}
+		if found == nil || !rates.Date.Before(found.Date) {
+			found = rates
+		}
+	}
+	if found == nil {
+		return ReferenceRates{}, ErrRateNotAvailable
+	}
+	return *found, nil
+}
+
+type fakeReferenceProvider struct {
+	name  string
+	rates map[string]ReferenceRates
+	err   error
+	calls int
+}
+
+func (f *fakeReferenceProvider) Name() string {
+	return f.name
+}
+
+func (f *fakeReferenceProvider) GetEURRates(_ context.Context, onDate time.Time) (ReferenceRates, error) {
+	f.calls++
+	if f.err != nil {
+		return ReferenceRates{}, f.err
+	}
+	rates, ok := f.rates[onDate.Format("2006-01-02")]
+	if !ok {
+		return ReferenceRates{}, ErrRateNotAvailable
+	}
+	return rates, nil
+}
+
+func TestGetRatesRebasesStoredReferenceRates(t *testing.T) {
+	date := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRatesRepo{stored: []ReferenceRates{{
+		Date:   date.AddDate(0, 0, -1),
+		Source: "ecb",
+		Rates:  map[string]float64{"USD": 1.1, "PLN": 4.4},
+	}}}
+	svc := NewServiceWithReferenceRates(&fakeProvider{}, repo, nil, Config{FallbackDays: 3})
+
+	table, err := svc.GetRates(context.Background(), "usd", date)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if table.Base != "USD" || table.Source != "ecb" || table.Date.Format("2006-01-02") != "2026-03-04" {
+		t.Fatalf("unexpected table %+v", table)
+	}
+	if table.Rates["USD"] != 1 || math.Abs(table.Rates["PLN"]-4) > 1e-9 || math.Abs(table.Rates["EUR"]-1/1.1) > 1e-9 {
+		t.Fatalf("unexpected rates %+v", table.Rates)
+	}
+}
+
+func TestGetRatesFetchesAndStoresMissingDay(t *testing.T) {
+	date := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRatesRepo{}
+	failing := &fakeReferenceProvider{name: "ecb", err: errors.New("unavailable")}
+	backup := &fakeReferenceProvider{name: "exchangerate_host", rates: map[string]ReferenceRates{
+		"2026-03-05": {Date: date, Rates: map[string]float64{"USD": 1.2}},
+	}}
+	svc := NewServiceWithReferenceRates(&fakeProvider{}, repo, []ReferenceProvider{failing, backup}, Config{})
+
+	table, err := svc.GetRates(context.Background(), "EUR", date)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if table.Source != "exchangerate_host" || table.Rates["USD"] != 1.2 {
+		t.Fatalf("unexpected table %+v", table)
+	}
+	if len(repo.stored) != 1 {
+		t.Fatalf("expected fetched rates to be stored, got %d", len(repo.stored))
+	}
+
+	if _, err := svc.GetRates(context.Background(), "EUR", date); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if failing.calls != 1 || backup.calls != 1 {
+		t.Fatalf("expected stored rates to be reused, got %d and %d provider calls", failing.calls, backup.calls)
+	}
+}
+
+func TestGetRateFallsBackToReferenceRates(t *testing.T) {
+	date := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRatesRepo{stored: []ReferenceRates{{
+		Date:   date,
+		Source: "ecb",
+		Rates:  map[string]float64{"USD": 1.25, "ISK": 150},
+	}}}
+	svc := NewServiceWithReferenceRates(&fakeProvider{}, repo, nil, Config{})
+
+	quote, err := svc.GetRate(context.Background(), "ISK", "USD", date)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if quote.Source != "ecb" || math.Abs(quote.Rate-1.25/150) > 1e-12 {
+		t.Fatalf("unexpected quote %+v", quote)
+	}
+}
+
+func TestRefreshReferenceRatesRequiresProviders(t *testing.T) {
+	svc := NewServiceWithReferenceRates(&fakeProvider{}, &fakeRatesRepo{}, nil, Config{})
+
+	if _, err := svc.RefreshReferenceRates(context.Background(), time.Now()); !errors.Is(err, ErrNoReferenceProviders) {
+		t.Fatalf("expected ErrNoReferenceProviders, got %v", err)
+	}
+}
@@ -59,7 +59,7 @@ type File struct {
 	ID          string    `gorm:"type:uuid;primaryKey"`
 	JobID       string    `gorm:"type:uuid;index;not null"`
 	Ordinal     int       `gorm:"not null"`
-	FileName    string    `gorm:"not null"`
+	FileName    string    `gorm:"not null;serializer:encrypted"`
 	ContentType string    `gorm:"not null"`
 	SizeBytes   int64     `gorm:"not null"`
 	StorageKey  *string   `gorm:"type:text"`
@@ -250,6 +250,9 @@ type ApproveInput struct {
 	BaseCurrency string
 	JobID        string
 	Expenses     []ApproveExpenseInput
+
+	// AllowedCurrencies is the family's allowed currency list.
+	AllowedCurrencies []string
 }
 
 type ReviewItemInput struct {
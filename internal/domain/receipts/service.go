@@ -2,17 +2,16 @@ package receipts
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"math"
 	"sort"
 	"strings"
 	"time"
 
 	expensesdomain "family-app-go/internal/domain/expenses"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
 )
 
 const (
@@ -41,8 +40,8 @@ type CategoryProvider interface {
 }
 
 type ExpenseBatchCreator interface {
-	CreateExpensesBatch(ctx context.Context, inputs []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, error)
-	CreateExpensesBatchWithRepository(ctx context.Context, repo expensesdomain.Repository, inputs []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, error)
+	CreateExpensesBatch(ctx context.Context, inputs []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error)
+	CreateExpensesBatchWithRepository(ctx context.Context, repo expensesdomain.Repository, inputs []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error)
 }
 
 type Service struct {
@@ -121,6 +120,9 @@ func NewServiceWithOptions(repo Repository, parser Parser, categories CategoryPr
 }
 
 func (s *Service) CreateParse(ctx context.Context, input CreateParseInput) (*Job, error) {
+	ctx, span := tracing.Start(ctx, "receipts.CreateParse")
+	defer span.End()
+
 	if s.parser == nil {
 		return nil, ErrReceiptParserDisabled
 	}
@@ -145,7 +147,7 @@ func (s *Service) CreateParse(ctx context.Context, input CreateParseInput) (*Job
 		return nil, ErrActiveReceiptParseExists
 	}
 
-	jobID, err := newUUID()
+	jobID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +185,7 @@ func (s *Service) CreateParse(ctx context.Context, input CreateParseInput) (*Job
 		}
 	}()
 	for ordinal, uploadedFile := range uploadedFiles {
-		fileID, err := newUUID()
+		fileID, err := id.New()
 		if err != nil {
 			return nil, err
 		}
@@ -226,10 +228,16 @@ func (s *Service) CreateParse(ctx context.Context, input CreateParseInput) (*Job
 }
 
 func (s *Service) GetActiveParse(ctx context.Context, familyID string) (*Job, error) {
+	ctx, span := tracing.Start(ctx, "receipts.GetActiveParse")
+	defer span.End()
+
 	return s.repo.GetActiveJob(ctx, familyID)
 }
 
 func (s *Service) GetParse(ctx context.Context, familyID, jobID string) (*JobWithDrafts, error) {
+	ctx, span := tracing.Start(ctx, "receipts.GetParse")
+	defer span.End()
+
 	job, err := s.repo.GetJobByID(ctx, familyID, jobID)
 	if err != nil {
 		return nil, err
@@ -246,6 +254,9 @@ func (s *Service) GetParse(ctx context.Context, familyID, jobID string) (*JobWit
 }
 
 func (s *Service) CancelParse(ctx context.Context, familyID, jobID string) (*Job, error) {
+	ctx, span := tracing.Start(ctx, "receipts.CancelParse")
+	defer span.End()
+
 	job, err := s.repo.GetJobByID(ctx, familyID, jobID)
 	if err != nil {
 		return nil, err
@@ -264,28 +275,34 @@ func (s *Service) CancelParse(ctx context.Context, familyID, jobID string) (*Job
 	return job, nil
 }
 
-func (s *Service) ApproveParse(ctx context.Context, input ApproveInput) ([]expensesdomain.ExpenseWithCategories, error) {
+// ApproveParse creates the reviewed expenses of a ready receipt. Expenses
+// above the member's spending limit are queued for the owner's approval and
+// returned as approvals.
+func (s *Service) ApproveParse(ctx context.Context, input ApproveInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error) {
+	ctx, span := tracing.Start(ctx, "receipts.ApproveParse")
+	defer span.End()
+
 	job, err := s.repo.GetJobByID(ctx, input.FamilyID, input.JobID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if job.Status != StatusReady {
-		return nil, ErrReceiptParseInvalidStatus
+		return nil, nil, ErrReceiptParseInvalidStatus
 	}
 	if len(input.Expenses) == 0 {
-		return nil, ErrReceiptParseEmpty
+		return nil, nil, ErrReceiptParseEmpty
 	}
 
 	drafts, err := s.repo.ListDraftExpenses(ctx, job.ID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	items, err := s.repo.ListItemsByJobID(ctx, job.ID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if hasUnresolvedItems(items) {
-		return nil, ErrReceiptParseUnresolvedItems
+		return nil, nil, ErrReceiptParseUnresolvedItems
 	}
 	draftByID := make(map[string]DraftExpense, len(drafts))
 	for _, draft := range drafts {
@@ -297,12 +314,12 @@ func (s *Service) ApproveParse(ctx context.Context, input ApproveInput) ([]expen
 	for _, item := range input.Expenses {
 		draft, ok := draftByID[item.DraftID]
 		if !ok {
-			return nil, ErrReceiptParseInvalidStatus
+			return nil, nil, ErrReceiptParseInvalidStatus
 		}
 		title := strings.TrimSpace(item.Title)
 		currency := strings.ToUpper(strings.TrimSpace(item.Currency))
 		if title == "" || item.Amount <= 0 || currency == "" || len(item.CategoryIDs) == 0 {
-			return nil, ErrReceiptParseInvalidStatus
+			return nil, nil, ErrReceiptParseInvalidStatus
 		}
 
 		finalCategoryID := item.CategoryIDs[0]
@@ -322,10 +339,15 @@ func (s *Service) ApproveParse(ctx context.Context, input ApproveInput) ([]expen
 			BaseCurrency: input.BaseCurrency,
 			Title:        title,
 			CategoryIDs:  item.CategoryIDs,
+
+			AllowedCurrencies: input.AllowedCurrencies,
 		})
 	}
 
-	var created []expensesdomain.ExpenseWithCategories
+	var (
+		created   []expensesdomain.ExpenseWithCategories
+		approvals []expensesdomain.ExpenseApproval
+	)
 	err = s.repo.Transaction(ctx, func(receiptTx Repository, expenseTx expensesdomain.Repository) error {
 		currentJob, err := receiptTx.GetJobByID(ctx, input.FamilyID, input.JobID)
 		if err != nil {
@@ -335,7 +357,7 @@ func (s *Service) ApproveParse(ctx context.Context, input ApproveInput) ([]expen
 			return ErrReceiptParseInvalidStatus
 		}
 
-		created, err = s.expenses.CreateExpensesBatchWithRepository(ctx, expenseTx, expenseInputs)
+		created, approvals, err = s.expenses.CreateExpensesBatchWithRepository(ctx, expenseTx, expenseInputs)
 		if err != nil {
 			if errors.Is(err, expensesdomain.ErrCategoryNotFound) {
 				return ErrCategoryNotFound
@@ -360,16 +382,19 @@ func (s *Service) ApproveParse(ctx context.Context, input ApproveInput) ([]expen
 		return receiptTx.UpdateJob(ctx, currentJob)
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	s.wakeHintMaterializer()
 	s.cleanupStoredFiles(ctx, job.ID)
 
-	return created, nil
+	return created, approvals, nil
 }
 
 func (s *Service) UpdateItems(ctx context.Context, input UpdateItemsInput) (*JobWithDrafts, error) {
+	ctx, span := tracing.Start(ctx, "receipts.UpdateItems")
+	defer span.End()
+
 	job, err := s.repo.GetJobByID(ctx, input.FamilyID, input.JobID)
 	if err != nil {
 		return nil, err
@@ -757,7 +782,7 @@ func (s *Service) normalizeParsed(jobID string, parsed *ParsedReceipt, categorie
 			continue
 		}
 
-		itemID, err := newUUID()
+		itemID, err := id.New()
 		if err != nil {
 			return nil, nil, err
 		}
@@ -791,7 +816,7 @@ func (s *Service) normalizeParsed(jobID string, parsed *ParsedReceipt, categorie
 		categoryName := categoryNames[*categoryID]
 		aggregate := aggregates[*categoryID]
 		if aggregate == nil {
-			draftID, err := newUUID()
+			draftID, err := id.New()
 			if err != nil {
 				return nil, nil, err
 			}
@@ -844,7 +869,7 @@ func buildDraftsFromItems(jobID string, items []Item, categoryNames map[string]s
 
 		aggregate := aggregates[categoryID]
 		if aggregate == nil {
-			draftID, err := newUUID()
+			draftID, err := id.New()
 			if err != nil {
 				return nil, err
 			}
@@ -881,7 +906,7 @@ func (s *Service) persistCategoryCorrections(ctx context.Context, repo Repositor
 			continue
 		}
 
-		eventID, err := newUUID()
+		eventID, err := id.New()
 		if err != nil {
 			return err
 		}
@@ -1010,11 +1035,11 @@ func (s *Service) persistMaterializedHint(ctx context.Context, event *CategoryCo
 	if canonicalName == "" {
 		canonicalName = deterministicCanonicalName(*event)
 	}
-	hintID, err := newUUID()
+	hintID, err := id.New()
 	if err != nil {
 		return err
 	}
-	exampleID, err := newUUID()
+	exampleID, err := id.New()
 	if err != nil {
 		return err
 	}
@@ -1217,14 +1242,3 @@ func stringValue(value *string) string {
 	}
 	return *value
 }
-
-func newUUID() (string, error) {
-	var b [16]byte
-	if _, err := rand.Read(b[:]); err != nil {
-		return "", fmt.Errorf("generate uuid: %w", err)
-	}
-	b[6] = (b[6] & 0x0f) | 0x40
-	b[8] = (b[8] & 0x3f) | 0x80
-	encoded := hex.EncodeToString(b[:])
-	return encoded[0:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:32], nil
-}
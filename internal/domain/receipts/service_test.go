@@ -225,7 +225,7 @@ func TestApproveParseRollsBackExpensesWhenReceiptUpdateFails(t *testing.T) {
 	service := NewServiceWithOptions(receiptRepo, nil, nil, fakeExpenseBatchCreator{}, ServiceOptions{WorkerEnabled: false})
 	date := time.Date(2026, 4, 25, 0, 0, 0, 0, time.UTC)
 
-	_, err := service.ApproveParse(ctx, ApproveInput{
+	_, _, err := service.ApproveParse(ctx, ApproveInput{
 		FamilyID:     testFamilyID,
 		UserID:       testUserID,
 		BaseCurrency: "BYN",
@@ -306,7 +306,7 @@ func TestApproveParseDeletesStoredFilesAfterSuccess(t *testing.T) {
 	})
 	date := time.Date(2026, 4, 25, 0, 0, 0, 0, time.UTC)
 
-	created, err := service.ApproveParse(ctx, ApproveInput{
+	created, _, err := service.ApproveParse(ctx, ApproveInput{
 		FamilyID:     testFamilyID,
 		UserID:       testUserID,
 		BaseCurrency: "BYN",
@@ -346,7 +346,7 @@ func TestApproveParseCreatesCorrectionEventForChangedCategory(t *testing.T) {
 	})
 	date := time.Date(2026, 4, 25, 0, 0, 0, 0, time.UTC)
 
-	_, err := service.ApproveParse(ctx, ApproveInput{
+	_, _, err := service.ApproveParse(ctx, ApproveInput{
 		FamilyID:     testFamilyID,
 		UserID:       testUserID,
 		BaseCurrency: "BYN",
@@ -393,7 +393,7 @@ func TestApproveParseCreatesCorrectionEventForManuallyCategorizedUnresolvedItem(
 	})
 	date := time.Date(2026, 4, 25, 0, 0, 0, 0, time.UTC)
 
-	_, err := service.ApproveParse(ctx, ApproveInput{
+	_, _, err := service.ApproveParse(ctx, ApproveInput{
 		FamilyID:     testFamilyID,
 		UserID:       testUserID,
 		BaseCurrency: "BYN",
@@ -434,7 +434,7 @@ func TestApproveParseSkipsCorrectionEventWhenCategoryUnchanged(t *testing.T) {
 	})
 	date := time.Date(2026, 4, 25, 0, 0, 0, 0, time.UTC)
 
-	_, err := service.ApproveParse(ctx, ApproveInput{
+	_, _, err := service.ApproveParse(ctx, ApproveInput{
 		FamilyID:     testFamilyID,
 		UserID:       testUserID,
 		BaseCurrency: "BYN",
@@ -903,11 +903,11 @@ func TestUpdateItemsRebuildsDraftsAndResolvesUncategorizedItem(t *testing.T) {
 
 type fakeExpenseBatchCreator struct{}
 
-func (fakeExpenseBatchCreator) CreateExpensesBatch(context.Context, []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, error) {
-	return nil, errors.New("unexpected CreateExpensesBatch call")
+func (fakeExpenseBatchCreator) CreateExpensesBatch(context.Context, []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error) {
+	return nil, nil, errors.New("unexpected CreateExpensesBatch call")
 }
 
-func (fakeExpenseBatchCreator) CreateExpensesBatchWithRepository(ctx context.Context, repo expensesdomain.Repository, inputs []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, error) {
+func (fakeExpenseBatchCreator) CreateExpensesBatchWithRepository(ctx context.Context, repo expensesdomain.Repository, inputs []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error) {
 	result := make([]expensesdomain.ExpenseWithCategories, 0, len(inputs))
 	for index, input := range inputs {
 		expense := expensesdomain.Expense{
@@ -920,17 +920,17 @@ func (fakeExpenseBatchCreator) CreateExpensesBatchWithRepository(ctx context.Con
 			Title:    input.Title,
 		}
 		if err := repo.CreateExpense(ctx, &expense); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if err := repo.ReplaceExpenseCategories(ctx, expense.ID, input.CategoryIDs); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		result = append(result, expensesdomain.ExpenseWithCategories{
 			Expense:     expense,
 			CategoryIDs: append([]string{}, input.CategoryIDs...),
 		})
 	}
-	return result, nil
+	return result, nil, nil
 }
 
 type fakeParser struct{}
@@ -1491,6 +1491,10 @@ func (r *fakeReceiptExpenseRepo) CreateExpense(_ context.Context, expense *expen
 	return nil
 }
 
+func (r *fakeReceiptExpenseRepo) CountExpensesCreatedSince(context.Context, string, time.Time) (int64, error) {
+	return int64(len(r.expenses)), nil
+}
+
 func (r *fakeReceiptExpenseRepo) UpdateExpense(context.Context, *expensesdomain.Expense) error {
 	return nil
 }
@@ -1499,6 +1503,34 @@ func (r *fakeReceiptExpenseRepo) DeleteExpense(context.Context, string, string)
 	return false, nil
 }
 
+func (r *fakeReceiptExpenseRepo) SummarizeExpenses(context.Context, string, string, expensesdomain.ListFilter) ([]expensesdomain.CurrencyTotal, error) {
+	return nil, nil
+}
+
+func (r *fakeReceiptExpenseRepo) CreateExpenseApproval(context.Context, *expensesdomain.ExpenseApproval) error {
+	return nil
+}
+
+func (r *fakeReceiptExpenseRepo) ListExpenseApprovals(context.Context, string, expensesdomain.ApprovalFilter) ([]expensesdomain.ExpenseApproval, error) {
+	return nil, nil
+}
+
+func (r *fakeReceiptExpenseRepo) LockExpenseApproval(context.Context, string, string) (*expensesdomain.ExpenseApproval, error) {
+	return nil, expensesdomain.ErrApprovalNotFound
+}
+
+func (r *fakeReceiptExpenseRepo) UpdateExpenseApproval(context.Context, *expensesdomain.ExpenseApproval) error {
+	return nil
+}
+
+func (r *fakeReceiptExpenseRepo) ClusterExpenseLocations(context.Context, string, expensesdomain.GeoFilter) ([]expensesdomain.GeoCluster, error) {
+	return nil, nil
+}
+
+func (r *fakeReceiptExpenseRepo) ArchiveExpensesBefore(context.Context, string, time.Time, time.Time) (int64, error) {
+	return 0, nil
+}
+
 func (r *fakeReceiptExpenseRepo) ReplaceExpenseCategories(_ context.Context, expenseID string, categoryIDs []string) error {
 	r.expenseCategories[expenseID] = append([]string{}, categoryIDs...)
 	return nil
@@ -1539,3 +1571,31 @@ func (r *fakeReceiptExpenseRepo) DeleteCategory(context.Context, string, string)
 func (r *fakeReceiptExpenseRepo) CountExpenseCategoriesByCategoryID(context.Context, string) (int64, error) {
 	return 0, nil
 }
+
+func (r *fakeReceiptExpenseRepo) ListCategoryUsage(context.Context, string) ([]expensesdomain.CategoryUsage, error) {
+	return nil, nil
+}
+
+func (r *fakeReceiptExpenseRepo) ListCategoryRules(context.Context, string) ([]expensesdomain.CategoryRule, error) {
+	return nil, nil
+}
+
+func (r *fakeReceiptExpenseRepo) GetCategoryRuleByID(context.Context, string, string) (*expensesdomain.CategoryRule, error) {
+	return nil, expensesdomain.ErrCategoryRuleNotFound
+}
+
+func (r *fakeReceiptExpenseRepo) CreateCategoryRule(context.Context, *expensesdomain.CategoryRule) error {
+	return nil
+}
+
+func (r *fakeReceiptExpenseRepo) UpdateCategoryRule(context.Context, *expensesdomain.CategoryRule) error {
+	return nil
+}
+
+func (r *fakeReceiptExpenseRepo) DeleteCategoryRule(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeReceiptExpenseRepo) ListCategorizedTitles(context.Context, string, time.Time, int) ([]expensesdomain.CategorizedTitle, error) {
+	return nil, nil
+}
@@ -0,0 +1,10 @@
+package retention
+
+import "errors"
+
+var (
+	ErrPolicyNotFound           = errors.New("retention policy not found")
+	ErrNotOwner                 = errors.New("not owner")
+	ErrInvalidExpensesMaxAge    = errors.New("invalid expenses max age")
+	ErrInvalidTodosArchiveAfter = errors.New("invalid todos archive after")
+)
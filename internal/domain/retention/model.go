@@ -0,0 +1,46 @@
+package retention
+
+import "time"
+
+const (
+	MinExpensesMaxAgeDays    = 30
+	MaxExpensesMaxAgeDays    = 36500
+	MinTodosArchiveAfterDays = 1
+	MaxTodosArchiveAfterDays = 3650
+)
+
+type Policy struct {
+	FamilyID              string `gorm:"type:uuid;primaryKey"`
+	ExpensesMaxAgeDays    *int
+	TodosArchiveAfterDays *int
+	LastRunAt             *time.Time
+	CreatedAt             time.Time `gorm:"autoCreateTime"`
+	UpdatedAt             time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Policy) TableName() string {
+	return "family_retention_policies"
+}
+
+func (p Policy) Enabled() bool {
+	return p.ExpensesMaxAgeDays != nil || p.TodosArchiveAfterDays != nil
+}
+
+type UpdatePolicyInput struct {
+	ExpensesMaxAgeDays    *int
+	TodosArchiveAfterDays *int
+}
+
+type Preview struct {
+	ExpensesCutoff   *time.Time
+	ExpensesToDelete int64
+	TodosCutoff      *time.Time
+	TodosToArchive   int64
+}
+
+type RunResult struct {
+	FamilyID        string
+	ExpensesDeleted int64
+	TodosArchived   int64
+	RanAt           time.Time
+}
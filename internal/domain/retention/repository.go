@@ -0,0 +1,18 @@
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Transaction(ctx context.Context, fn func(Repository) error) error
+	GetPolicy(ctx context.Context, familyID string) (*Policy, error)
+	UpsertPolicy(ctx context.Context, policy *Policy) error
+	ListDuePolicies(ctx context.Context, lastRunBefore time.Time, limit int) ([]Policy, error)
+	MarkPolicyRun(ctx context.Context, familyID string, ranAt time.Time) error
+	CountExpensesBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error)
+	DeleteExpensesBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error)
+	CountCompletedTodosBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error)
+	ArchiveCompletedTodosBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,238 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	defaultRunInterval  = 24 * time.Hour
+	defaultRunBatchSize = 50
+)
+
+type FamilyProvider interface {
+	GetFamilyByUser(ctx context.Context, userID string) (*familydomain.Family, error)
+}
+
+type Service struct {
+	repo        Repository
+	families    FamilyProvider
+	runInterval time.Duration
+	now         func() time.Time
+}
+
+// ServiceOptions sets how often each family's policy is applied. RunDue is
+// driven by the background job runner.
+type ServiceOptions struct {
+	RunInterval time.Duration
+}
+
+func NewService(repo Repository, families FamilyProvider) *Service {
+	return NewServiceWithOptions(repo, families, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, families FamilyProvider, options ServiceOptions) *Service {
+	runInterval := options.RunInterval
+	if runInterval <= 0 {
+		runInterval = defaultRunInterval
+	}
+
+	return &Service{
+		repo:        repo,
+		families:    families,
+		runInterval: runInterval,
+		now:         time.Now,
+	}
+}
+
+func (s *Service) GetPolicy(ctx context.Context, userID string) (*Policy, error) {
+	ctx, span := tracing.Start(ctx, "retention.GetPolicy")
+	defer span.End()
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.policyForFamily(ctx, family.ID)
+}
+
+func (s *Service) UpdatePolicy(ctx context.Context, userID string, input UpdatePolicyInput) (*Policy, error) {
+	ctx, span := tracing.Start(ctx, "retention.UpdatePolicy")
+	defer span.End()
+
+	if err := validatePolicyInput(input); err != nil {
+		return nil, err
+	}
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if family.OwnerID != userID {
+		return nil, ErrNotOwner
+	}
+
+	var result Policy
+	err = s.repo.Transaction(ctx, func(tx Repository) error {
+		policy, err := tx.GetPolicy(ctx, family.ID)
+		if err != nil {
+			if !errors.Is(err, ErrPolicyNotFound) {
+				return err
+			}
+			policy = &Policy{FamilyID: family.ID}
+		}
+
+		policy.ExpensesMaxAgeDays = cloneInt(input.ExpensesMaxAgeDays)
+		policy.TodosArchiveAfterDays = cloneInt(input.TodosArchiveAfterDays)
+		policy.UpdatedAt = s.now().UTC()
+		if err := tx.UpsertPolicy(ctx, policy); err != nil {
+			return err
+		}
+
+		result = *policy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Preview reports what the retention job would change for the caller's family
+// if it ran now, without modifying any data.
+func (s *Service) Preview(ctx context.Context, userID string) (*Preview, error) {
+	ctx, span := tracing.Start(ctx, "retention.Preview")
+	defer span.End()
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyForFamily(ctx, family.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	preview := &Preview{}
+	if cutoff, ok := expensesCutoff(*policy, now); ok {
+		count, err := s.repo.CountExpensesBefore(ctx, family.ID, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		preview.ExpensesCutoff = &cutoff
+		preview.ExpensesToDelete = count
+	}
+	if cutoff, ok := todosCutoff(*policy, now); ok {
+		count, err := s.repo.CountCompletedTodosBefore(ctx, family.ID, cutoff)
+		if err != nil {
+			return nil, err
+		}
+		preview.TodosCutoff = &cutoff
+		preview.TodosToArchive = count
+	}
+
+	return preview, nil
+}
+
+// RunDue applies every enabled policy that has not run within the configured
+// run interval and returns one result per processed family.
+func (s *Service) RunDue(ctx context.Context) ([]RunResult, error) {
+	ctx, span := tracing.Start(ctx, "retention.RunDue")
+	defer span.End()
+
+	now := s.now().UTC()
+	policies, err := s.repo.ListDuePolicies(ctx, now.Add(-s.runInterval), defaultRunBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]RunResult, 0, len(policies))
+	for _, policy := range policies {
+		result, err := s.applyPolicy(ctx, policy, now)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *Service) applyPolicy(ctx context.Context, policy Policy, now time.Time) (RunResult, error) {
+	result := RunResult{FamilyID: policy.FamilyID, RanAt: now}
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		if cutoff, ok := expensesCutoff(policy, now); ok {
+			deleted, err := tx.DeleteExpensesBefore(ctx, policy.FamilyID, cutoff)
+			if err != nil {
+				return err
+			}
+			result.ExpensesDeleted = deleted
+		}
+		if cutoff, ok := todosCutoff(policy, now); ok {
+			archived, err := tx.ArchiveCompletedTodosBefore(ctx, policy.FamilyID, cutoff)
+			if err != nil {
+				return err
+			}
+			result.TodosArchived = archived
+		}
+		return tx.MarkPolicyRun(ctx, policy.FamilyID, now)
+	})
+	if err != nil {
+		return RunResult{}, err
+	}
+	return result, nil
+}
+
+func (s *Service) policyForFamily(ctx context.Context, familyID string) (*Policy, error) {
+	policy, err := s.repo.GetPolicy(ctx, familyID)
+	if err != nil {
+		if errors.Is(err, ErrPolicyNotFound) {
+			return &Policy{FamilyID: familyID}, nil
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func validatePolicyInput(input UpdatePolicyInput) error {
+	if value := input.ExpensesMaxAgeDays; value != nil {
+		if *value < MinExpensesMaxAgeDays || *value > MaxExpensesMaxAgeDays {
+			return ErrInvalidExpensesMaxAge
+		}
+	}
+	if value := input.TodosArchiveAfterDays; value != nil {
+		if *value < MinTodosArchiveAfterDays || *value > MaxTodosArchiveAfterDays {
+			return ErrInvalidTodosArchiveAfter
+		}
+	}
+	return nil
+}
+
+func expensesCutoff(policy Policy, now time.Time) (time.Time, bool) {
+	if policy.ExpensesMaxAgeDays == nil {
+		return time.Time{}, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.AddDate(0, 0, -*policy.ExpensesMaxAgeDays), true
+}
+
+func todosCutoff(policy Policy, now time.Time) (time.Time, bool) {
+	if policy.TodosArchiveAfterDays == nil {
+		return time.Time{}, false
+	}
+	return now.Add(-time.Duration(*policy.TodosArchiveAfterDays) * 24 * time.Hour), true
+}
+
+func cloneInt(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
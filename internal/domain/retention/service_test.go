@@ -0,0 +1,222 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testOwnerID  = "22222222-2222-2222-2222-222222222222"
+	testMemberID = "33333333-3333-3333-3333-333333333333"
+)
+
+type fakeFamilyProvider struct{}
+
+func (fakeFamilyProvider) GetFamilyByUser(_ context.Context, userID string) (*familydomain.Family, error) {
+	switch userID {
+	case testOwnerID, testMemberID:
+		return &familydomain.Family{ID: testFamilyID, OwnerID: testOwnerID}, nil
+	}
+	return nil, familydomain.ErrFamilyNotFound
+}
+
+type fakeRetentionRepo struct {
+	policies       map[string]*Policy
+	expenseCutoffs []time.Time
+	todoCutoffs    []time.Time
+	expensesBefore int64
+	todosBefore    int64
+	markedRuns     map[string]time.Time
+}
+
+func newFakeRetentionRepo() *fakeRetentionRepo {
+	return &fakeRetentionRepo{
+		policies:   make(map[string]*Policy),
+		markedRuns: make(map[string]time.Time),
+	}
+}
+
+func (r *fakeRetentionRepo) Transaction(_ context.Context, fn func(Repository) error) error {
+	return fn(r)
+}
+
+func (r *fakeRetentionRepo) GetPolicy(_ context.Context, familyID string) (*Policy, error) {
+	policy, ok := r.policies[familyID]
+	if !ok {
+		return nil, ErrPolicyNotFound
+	}
+	cloned := *policy
+	return &cloned, nil
+}
+
+func (r *fakeRetentionRepo) UpsertPolicy(_ context.Context, policy *Policy) error {
+	cloned := *policy
+	r.policies[policy.FamilyID] = &cloned
+	return nil
+}
+
+func (r *fakeRetentionRepo) ListDuePolicies(_ context.Context, lastRunBefore time.Time, _ int) ([]Policy, error) {
+	result := make([]Policy, 0, len(r.policies))
+	for _, policy := range r.policies {
+		if !policy.Enabled() {
+			continue
+		}
+		if policy.LastRunAt != nil && !policy.LastRunAt.Before(lastRunBefore) {
+			continue
+		}
+		result = append(result, *policy)
+	}
+	return result, nil
+}
+
+func (r *fakeRetentionRepo) MarkPolicyRun(_ context.Context, familyID string, ranAt time.Time) error {
+	r.markedRuns[familyID] = ranAt
+	if policy, ok := r.policies[familyID]; ok {
+		policy.LastRunAt = &ranAt
+	}
+	return nil
+}
+
+func (r *fakeRetentionRepo) CountExpensesBefore(_ context.Context, _ string, cutoff time.Time) (int64, error) {
+	r.expenseCutoffs = append(r.expenseCutoffs, cutoff)
+	return r.expensesBefore, nil
+}
+
+func (r *fakeRetentionRepo) DeleteExpensesBefore(_ context.Context, _ string, cutoff time.Time) (int64, error) {
+	r.expenseCutoffs = append(r.expenseCutoffs, cutoff)
+	return r.expensesBefore, nil
+}
+
+func (r *fakeRetentionRepo) CountCompletedTodosBefore(_ context.Context, _ string, cutoff time.Time) (int64, error) {
+	r.todoCutoffs = append(r.todoCutoffs, cutoff)
+	return r.todosBefore, nil
+}
+
+func (r *fakeRetentionRepo) ArchiveCompletedTodosBefore(_ context.Context, _ string, cutoff time.Time) (int64, error) {
+	r.todoCutoffs = append(r.todoCutoffs, cutoff)
+	return r.todosBefore, nil
+}
+
+func newTestService(repo Repository, now time.Time) *Service {
+	service := NewService(repo, fakeFamilyProvider{})
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestUpdatePolicyRejectsNonOwner(t *testing.T) {
+	service := newTestService(newFakeRetentionRepo(), time.Now())
+
+	_, err := service.UpdatePolicy(context.Background(), testMemberID, UpdatePolicyInput{
+		ExpensesMaxAgeDays: intPtr(365),
+	})
+	if !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("expected ErrNotOwner, got %v", err)
+	}
+}
+
+func TestUpdatePolicyValidatesBounds(t *testing.T) {
+	service := newTestService(newFakeRetentionRepo(), time.Now())
+
+	_, err := service.UpdatePolicy(context.Background(), testOwnerID, UpdatePolicyInput{
+		ExpensesMaxAgeDays: intPtr(MinExpensesMaxAgeDays - 1),
+	})
+	if !errors.Is(err, ErrInvalidExpensesMaxAge) {
+		t.Fatalf("expected ErrInvalidExpensesMaxAge, got %v", err)
+	}
+
+	_, err = service.UpdatePolicy(context.Background(), testOwnerID, UpdatePolicyInput{
+		TodosArchiveAfterDays: intPtr(0),
+	})
+	if !errors.Is(err, ErrInvalidTodosArchiveAfter) {
+		t.Fatalf("expected ErrInvalidTodosArchiveAfter, got %v", err)
+	}
+}
+
+func TestGetPolicyReturnsDisabledDefault(t *testing.T) {
+	service := newTestService(newFakeRetentionRepo(), time.Now())
+
+	policy, err := service.GetPolicy(context.Background(), testMemberID)
+	if err != nil {
+		t.Fatalf("get policy: %v", err)
+	}
+	if policy.FamilyID != testFamilyID || policy.Enabled() {
+		t.Fatalf("expected disabled default policy, got %#v", policy)
+	}
+}
+
+func TestPreviewUsesPolicyCutoffs(t *testing.T) {
+	now := time.Date(2026, 5, 10, 15, 30, 0, 0, time.UTC)
+	repo := newFakeRetentionRepo()
+	repo.expensesBefore = 7
+	repo.todosBefore = 3
+	service := newTestService(repo, now)
+
+	if _, err := service.UpdatePolicy(context.Background(), testOwnerID, UpdatePolicyInput{
+		ExpensesMaxAgeDays:    intPtr(365),
+		TodosArchiveAfterDays: intPtr(90),
+	}); err != nil {
+		t.Fatalf("update policy: %v", err)
+	}
+
+	preview, err := service.Preview(context.Background(), testMemberID)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+
+	wantExpensesCutoff := time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+	if preview.ExpensesCutoff == nil || !preview.ExpensesCutoff.Equal(wantExpensesCutoff) {
+		t.Fatalf("unexpected expenses cutoff: %v", preview.ExpensesCutoff)
+	}
+	wantTodosCutoff := now.Add(-90 * 24 * time.Hour)
+	if preview.TodosCutoff == nil || !preview.TodosCutoff.Equal(wantTodosCutoff) {
+		t.Fatalf("unexpected todos cutoff: %v", preview.TodosCutoff)
+	}
+	if preview.ExpensesToDelete != 7 || preview.TodosToArchive != 3 {
+		t.Fatalf("unexpected preview counts: %#v", preview)
+	}
+	if len(repo.markedRuns) != 0 {
+		t.Fatalf("preview must not mark policy as run")
+	}
+}
+
+func TestRunDueAppliesEnabledPoliciesOnce(t *testing.T) {
+	now := time.Date(2026, 5, 10, 15, 30, 0, 0, time.UTC)
+	repo := newFakeRetentionRepo()
+	repo.todosBefore = 4
+	repo.policies[testFamilyID] = &Policy{
+		FamilyID:              testFamilyID,
+		TodosArchiveAfterDays: intPtr(30),
+	}
+	service := newTestService(repo, now)
+
+	results, err := service.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("run due: %v", err)
+	}
+	if len(results) != 1 || results[0].TodosArchived != 4 || results[0].ExpensesDeleted != 0 {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+	if len(repo.expenseCutoffs) != 0 {
+		t.Fatalf("expected expenses rule to be skipped, got cutoffs %v", repo.expenseCutoffs)
+	}
+	if ranAt, ok := repo.markedRuns[testFamilyID]; !ok || !ranAt.Equal(now) {
+		t.Fatalf("expected policy run to be recorded at %v, got %v", now, ranAt)
+	}
+
+	results, err = service.RunDue(context.Background())
+	if err != nil {
+		t.Fatalf("second run due: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected policy not to be due again, got %#v", results)
+	}
+}
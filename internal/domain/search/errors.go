@@ -0,0 +1,8 @@
+package search
+
+import "errors"
+
+var (
+	ErrInvalidQuery = errors.New("invalid search query")
+	ErrInvalidType  = errors.New("invalid search result type")
+)
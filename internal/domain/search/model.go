@@ -0,0 +1,54 @@
+package search
+
+import "time"
+
+type ResultType string
+
+const (
+	TypeExpense  ResultType = "expense"
+	TypeTodoList ResultType = "todo_list"
+	TypeTodoItem ResultType = "todo_item"
+	TypeWorkout  ResultType = "workout"
+)
+
+const (
+	MinQueryLength = 2
+	MaxQueryLength = 100
+	DefaultLimit   = 20
+	MaxLimit       = 50
+)
+
+// AllTypes lists the result types in the order ties are broken.
+var AllTypes = []ResultType{TypeTodoItem, TypeTodoList, TypeExpense, TypeWorkout}
+
+type Query struct {
+	Text     string
+	UserID   string
+	FamilyID string
+	// Child limits results to what a child member may see: todo items and
+	// lists assigned to them and their own workouts, no expenses.
+	Child bool
+	// Types limits the result types; all of them when empty.
+	Types []ResultType
+	Limit int
+}
+
+// Result is one match. Score orders results across types; higher is better.
+type Result struct {
+	Type       ResultType
+	ID         string
+	Title      string
+	Date       *time.Time
+	ListID     string
+	Score      float64
+	Highlights []Highlight
+}
+
+// Highlight marks where the query matched in a field of the result. Start and
+// Length count characters, not bytes.
+type Highlight struct {
+	Field  string
+	Text   string
+	Start  int
+	Length int
+}
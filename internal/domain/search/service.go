@@ -0,0 +1,255 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	gymdomain "family-app-go/internal/domain/gym"
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/pkg/tracing"
+)
+
+// Match weights by where the query is found in a field, and how much a
+// match outside the title counts.
+const (
+	scoreExact      = 1.0
+	scorePrefix     = 0.8
+	scoreWordPrefix = 0.6
+	scoreContains   = 0.4
+	secondaryWeight = 0.5
+	// recencyBoost is added in full for today's records and fades out over
+	// recencyWindow, so recent matches win ties.
+	recencyBoost  = 0.1
+	recencyWindow = 365 * 24 * time.Hour
+)
+
+type ExpenseSearcher interface {
+	SearchExpenses(ctx context.Context, familyID, query string, limit int) ([]expensesdomain.Expense, error)
+}
+
+type TodoSearcher interface {
+	SearchTodoLists(ctx context.Context, familyID, query, assigneeID, viewerID string, limit int) ([]todosdomain.TodoList, error)
+	SearchTodoItems(ctx context.Context, familyID, query, assigneeID, viewerID string, limit int) ([]todosdomain.TodoItem, error)
+}
+
+type WorkoutSearcher interface {
+	SearchWorkouts(ctx context.Context, scope gymdomain.Scope, query string, limit int) ([]gymdomain.WorkoutWithSets, error)
+}
+
+// Service searches the family's records across domains. Each domain finds
+// its matches; the service ranks them together and marks what matched.
+type Service struct {
+	expenses ExpenseSearcher
+	todos    TodoSearcher
+	workouts WorkoutSearcher
+	now      func() time.Time
+}
+
+func NewService(expenses ExpenseSearcher, todos TodoSearcher, workouts WorkoutSearcher) *Service {
+	return &Service{
+		expenses: expenses,
+		todos:    todos,
+		workouts: workouts,
+		now:      time.Now,
+	}
+}
+
+func (s *Service) Search(ctx context.Context, query Query) ([]Result, error) {
+	ctx, span := tracing.Start(ctx, "search.Search")
+	defer span.End()
+
+	text := strings.Join(strings.Fields(query.Text), " ")
+	if length := utf8.RuneCountInString(text); length < MinQueryLength || length > MaxQueryLength {
+		return nil, ErrInvalidQuery
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	types, err := selectedTypes(query.Types)
+	if err != nil {
+		return nil, err
+	}
+	assigneeID := ""
+	if query.Child {
+		assigneeID = query.UserID
+	}
+
+	// Each domain returns up to limit matches ordered its own way, so the
+	// best ones overall are among them.
+	var results []Result
+	if types[TypeExpense] && !query.Child {
+		expenses, err := s.expenses.SearchExpenses(ctx, query.FamilyID, text, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, expense := range expenses {
+			date := expense.Date
+			results = s.appendMatch(results, text, Result{Type: TypeExpense, ID: expense.ID, Title: expense.Title, Date: &date}, nil)
+		}
+	}
+	if types[TypeTodoList] {
+		lists, err := s.todos.SearchTodoLists(ctx, query.FamilyID, text, assigneeID, query.UserID, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, list := range lists {
+			created := list.CreatedAt
+			results = s.appendMatch(results, text, Result{Type: TypeTodoList, ID: list.ID, Title: list.Title, Date: &created}, nil)
+		}
+	}
+	if types[TypeTodoItem] {
+		items, err := s.todos.SearchTodoItems(ctx, query.FamilyID, text, assigneeID, query.UserID, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			date := item.CreatedAt
+			if item.DueDate != nil {
+				date = *item.DueDate
+			}
+			results = s.appendMatch(results, text, Result{Type: TypeTodoItem, ID: item.ID, Title: item.Title, Date: &date, ListID: item.ListID}, nil)
+		}
+	}
+	if types[TypeWorkout] {
+		scope := gymdomain.Scope{UserID: query.UserID, FamilyID: query.FamilyID, Kind: gymdomain.ScopeFamily}
+		if query.Child {
+			scope = gymdomain.Scope{UserID: query.UserID, Kind: gymdomain.ScopeMe}
+		}
+		workouts, err := s.workouts.SearchWorkouts(ctx, scope, text, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, workout := range workouts {
+			date := workout.Date
+			exercises := make([]string, 0, len(workout.Sets))
+			for _, set := range workout.Sets {
+				exercises = append(exercises, set.Exercise)
+			}
+			results = s.appendMatch(results, text, Result{Type: TypeWorkout, ID: workout.ID, Title: workout.Name, Date: &date}, map[string][]string{"exercise": exercises})
+		}
+	}
+
+	order := make(map[ResultType]int, len(AllTypes))
+	for i, resultType := range AllTypes {
+		order[resultType] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return order[results[i].Type] < order[results[j].Type]
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// appendMatch scores a record and adds it with its highlights. The title
+// counts in full; other fields, such as workout exercises, count less and
+// only their best match is kept. Records the query does not match after all
+// are dropped.
+func (s *Service) appendMatch(results []Result, query string, result Result, secondary map[string][]string) []Result {
+	if score, start, ok := matchScore(result.Title, query); ok {
+		result.Score = score
+		result.Highlights = append(result.Highlights, Highlight{Field: "title", Text: result.Title, Start: start, Length: utf8.RuneCountInString(query)})
+	}
+
+	var best *Highlight
+	bestScore := 0.0
+	for field, values := range secondary {
+		seen := map[string]bool{}
+		for _, value := range values {
+			if seen[value] {
+				continue
+			}
+			seen[value] = true
+			score, start, ok := matchScore(value, query)
+			if !ok || score*secondaryWeight <= bestScore {
+				continue
+			}
+			bestScore = score * secondaryWeight
+			best = &Highlight{Field: field, Text: value, Start: start, Length: utf8.RuneCountInString(query)}
+		}
+	}
+	if best != nil {
+		if result.Score < bestScore {
+			result.Score = bestScore
+		}
+		result.Highlights = append(result.Highlights, *best)
+	}
+	if len(result.Highlights) == 0 {
+		return results
+	}
+
+	if result.Date != nil {
+		age := s.now().Sub(*result.Date)
+		if age < 0 {
+			age = 0
+		}
+		if age < recencyWindow {
+			result.Score += recencyBoost * (1 - float64(age)/float64(recencyWindow))
+		}
+	}
+	return append(results, result)
+}
+
+// matchScore rates how well value matches query, case-insensitively, and
+// returns the character offset of the match.
+func matchScore(value, query string) (float64, int, bool) {
+	lowerValue := strings.ToLower(value)
+	lowerQuery := strings.ToLower(query)
+
+	index := strings.Index(lowerValue, lowerQuery)
+	if index < 0 {
+		return 0, 0, false
+	}
+	switch {
+	case lowerValue == lowerQuery:
+		return scoreExact, 0, true
+	case index == 0:
+		return scorePrefix, 0, true
+	}
+
+	// Prefer a match at the start of a word over the first one anywhere.
+	for offset := index; offset >= 0; {
+		previous, _ := utf8.DecodeLastRuneInString(lowerValue[:offset])
+		if !unicode.IsLetter(previous) && !unicode.IsDigit(previous) {
+			return scoreWordPrefix, utf8.RuneCountInString(lowerValue[:offset]), true
+		}
+		next := strings.Index(lowerValue[offset+1:], lowerQuery)
+		if next < 0 {
+			break
+		}
+		offset += 1 + next
+	}
+	return scoreContains, utf8.RuneCountInString(lowerValue[:index]), true
+}
+
+func selectedTypes(types []ResultType) (map[ResultType]bool, error) {
+	selected := make(map[ResultType]bool, len(AllTypes))
+	if len(types) == 0 {
+		for _, resultType := range AllTypes {
+			selected[resultType] = true
+		}
+		return selected, nil
+	}
+	for _, resultType := range types {
+		switch resultType {
+		case TypeExpense, TypeTodoList, TypeTodoItem, TypeWorkout:
+			selected[resultType] = true
+		default:
+			return nil, ErrInvalidType
+		}
+	}
+	return selected, nil
+}
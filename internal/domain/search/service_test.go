@@ -0,0 +1,154 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	gymdomain "family-app-go/internal/domain/gym"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testUserID   = "22222222-2222-2222-2222-222222222222"
+)
+
+var testNow = time.Date(2026, time.May, 10, 12, 0, 0, 0, time.UTC)
+
+type fakeExpenses struct {
+	items  []expensesdomain.Expense
+	called bool
+}
+
+func (f *fakeExpenses) SearchExpenses(_ context.Context, _, _ string, _ int) ([]expensesdomain.Expense, error) {
+	f.called = true
+	return f.items, nil
+}
+
+type fakeTodos struct {
+	lists      []todosdomain.TodoList
+	items      []todosdomain.TodoItem
+	assigneeID string
+}
+
+func (f *fakeTodos) SearchTodoLists(_ context.Context, _, _, assigneeID, _ string, _ int) ([]todosdomain.TodoList, error) {
+	f.assigneeID = assigneeID
+	return f.lists, nil
+}
+
+func (f *fakeTodos) SearchTodoItems(_ context.Context, _, _, assigneeID, _ string, _ int) ([]todosdomain.TodoItem, error) {
+	f.assigneeID = assigneeID
+	return f.items, nil
+}
+
+type fakeWorkouts struct {
+	items []gymdomain.WorkoutWithSets
+	scope gymdomain.Scope
+}
+
+func (f *fakeWorkouts) SearchWorkouts(_ context.Context, scope gymdomain.Scope, _ string, _ int) ([]gymdomain.WorkoutWithSets, error) {
+	f.scope = scope
+	return f.items, nil
+}
+
+func newTestService(expenses *fakeExpenses, todos *fakeTodos, workouts *fakeWorkouts) *Service {
+	service := NewService(expenses, todos, workouts)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func TestSearchRanksAcrossTypes(t *testing.T) {
+	expenses := &fakeExpenses{items: []expensesdomain.Expense{
+		{ID: "e1", Title: "Bread and milk", Date: testNow.AddDate(0, 0, -2)},
+		{ID: "e2", Title: "Milk", Date: testNow.AddDate(-2, 0, 0)},
+	}}
+	todos := &fakeTodos{items: []todosdomain.TodoItem{
+		{ID: "i1", ListID: "l1", Title: "Buy milk", CreatedAt: testNow.AddDate(0, -1, 0)},
+		{ID: "i2", ListID: "l1", Title: "Buttermilk pancakes", CreatedAt: testNow},
+	}}
+	service := newTestService(expenses, todos, &fakeWorkouts{})
+
+	results, err := service.Search(context.Background(), Query{Text: " MILK ", UserID: testUserID, FamilyID: testFamilyID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"e2", "e1", "i1", "i2"}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, id := range want {
+		if results[i].ID != id {
+			t.Fatalf("result %d = %s, want %s", i, results[i].ID, id)
+		}
+	}
+
+	highlight := results[2].Highlights[0]
+	if highlight.Field != "title" || highlight.Start != 4 || highlight.Length != 4 {
+		t.Fatalf("highlight = %+v", highlight)
+	}
+	if results[2].ListID != "l1" || results[2].Type != TypeTodoItem {
+		t.Fatalf("todo item result = %+v", results[2])
+	}
+}
+
+func TestSearchHighlightsWorkoutExercises(t *testing.T) {
+	workouts := &fakeWorkouts{items: []gymdomain.WorkoutWithSets{{
+		Workout: gymdomain.Workout{ID: "w1", Name: "Leg day", Date: testNow},
+		Sets: []gymdomain.WorkoutSet{
+			{Exercise: "Back squat"},
+			{Exercise: "Back squat"},
+			{Exercise: "Squat jumps"},
+		},
+	}}}
+	service := newTestService(&fakeExpenses{}, &fakeTodos{}, workouts)
+
+	results, err := service.Search(context.Background(), Query{Text: "squat", UserID: testUserID, FamilyID: testFamilyID, Types: []ResultType{TypeWorkout}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	highlights := results[0].Highlights
+	if len(highlights) != 1 || highlights[0].Field != "exercise" || highlights[0].Text != "Squat jumps" || highlights[0].Start != 0 {
+		t.Fatalf("highlights = %+v", highlights)
+	}
+	if workouts.scope.Kind != gymdomain.ScopeFamily {
+		t.Fatalf("scope = %+v, want family", workouts.scope)
+	}
+}
+
+func TestSearchForChildSkipsExpenses(t *testing.T) {
+	expenses := &fakeExpenses{items: []expensesdomain.Expense{{ID: "e1", Title: "Milk"}}}
+	todos := &fakeTodos{}
+	workouts := &fakeWorkouts{}
+	service := newTestService(expenses, todos, workouts)
+
+	if _, err := service.Search(context.Background(), Query{Text: "milk", UserID: testUserID, FamilyID: testFamilyID, Child: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expenses.called {
+		t.Fatalf("expenses were searched for a child")
+	}
+	if todos.assigneeID != testUserID {
+		t.Fatalf("assignee = %q, want %q", todos.assigneeID, testUserID)
+	}
+	if workouts.scope.Kind != gymdomain.ScopeMe {
+		t.Fatalf("scope = %+v, want me", workouts.scope)
+	}
+}
+
+func TestSearchRejectsInvalidInput(t *testing.T) {
+	service := newTestService(&fakeExpenses{}, &fakeTodos{}, &fakeWorkouts{})
+
+	if _, err := service.Search(context.Background(), Query{Text: " a "}); !errors.Is(err, ErrInvalidQuery) {
+		t.Fatalf("error = %v, want ErrInvalidQuery", err)
+	}
+	if _, err := service.Search(context.Background(), Query{Text: "milk", Types: []ResultType{"pets"}}); !errors.Is(err, ErrInvalidType) {
+		t.Fatalf("error = %v, want ErrInvalidType", err)
+	}
+}
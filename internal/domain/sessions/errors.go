@@ -0,0 +1,8 @@
+package sessions
+
+import "errors"
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session revoked")
+)
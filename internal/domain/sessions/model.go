@@ -0,0 +1,58 @@
+package sessions
+
+import "time"
+
+const (
+	MaxDeviceNameLength = 100
+	maxUserAgentLength  = 512
+	// ActiveWindow bounds listings to sessions seen recently; Purge deletes
+	// the others.
+	ActiveWindow = 30 * 24 * time.Hour
+	MaxListed    = 50
+	// RevokedRetention keeps revoked sessions long enough that a device
+	// still refreshing the provider session stays locked out.
+	RevokedRetention = 365 * 24 * time.Hour
+	// lastSeenMinStep keeps busy clients from writing on every cache miss.
+	lastSeenMinStep = time.Minute
+)
+
+// Session is a device signed in to the account. Key is the provider's
+// session ID when the access token carries one, so refreshed tokens of the
+// same sign-in share a row, and the token hash otherwise. TokenHash is the
+// hash of the last token seen, which is also its auth cache key. ExpiresAt
+// is only set for sessions keyed by a token, which end with it.
+type Session struct {
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	UserID     string     `gorm:"type:uuid;not null"`
+	Key        string     `gorm:"column:session_key;not null"`
+	TokenHash  string     `gorm:"not null"`
+	DeviceName *string    `gorm:""`
+	UserAgent  string     `gorm:"not null"`
+	IP         string     `gorm:"column:ip;not null"`
+	CreatedAt  time.Time  `gorm:"not null"`
+	LastSeenAt time.Time  `gorm:"not null"`
+	ExpiresAt  *time.Time `gorm:""`
+	RevokedAt  *time.Time `gorm:""`
+}
+
+func (Session) TableName() string {
+	return "user_sessions"
+}
+
+// TrackInput describes a request authenticated with an access token. Key
+// and ExpiresAt follow the Session fields.
+type TrackInput struct {
+	UserID     string
+	Key        string
+	TokenHash  string
+	DeviceName string
+	UserAgent  string
+	IP         string
+	ExpiresAt  *time.Time
+}
+
+type PurgeResult struct {
+	SeenBefore    time.Time `json:"seen_before"`
+	RevokedBefore time.Time `json:"revoked_before"`
+	Deleted       int64     `json:"deleted"`
+}
@@ -0,0 +1,25 @@
+package sessions
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// CreateSession reports false when a session with the same key already
+	// exists, e.g. because a concurrent request created it.
+	CreateSession(ctx context.Context, session *Session) (bool, error)
+	GetSessionByKey(ctx context.Context, key string) (*Session, error)
+	// ListSessions returns live sessions seen since seenSince whose token
+	// has not expired at now, most recently seen first.
+	ListSessions(ctx context.Context, userID string, seenSince, now time.Time, limit int) ([]Session, error)
+	TouchSession(ctx context.Context, id, tokenHash string, expiresAt *time.Time, at time.Time) error
+	// RevokeSession returns the revoked session so its cached token can be
+	// dropped.
+	RevokeSession(ctx context.Context, userID, id string, at time.Time) (*Session, error)
+	// RevokeOtherSessions revokes every session when keepID is empty.
+	RevokeOtherSessions(ctx context.Context, userID, keepID string, at time.Time) ([]Session, error)
+	// DeleteStale removes live sessions last seen before seenBefore and
+	// revoked ones revoked before revokedBefore.
+	DeleteStale(ctx context.Context, seenBefore, revokedBefore time.Time) (int64, error)
+}
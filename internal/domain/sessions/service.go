@@ -0,0 +1,170 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+// TokenCache is the auth cache, keyed by token hash. Revoking a session
+// drops its last token from it so the next request is checked again.
+type TokenCache interface {
+	Delete(ctx context.Context, key string) error
+}
+
+type Service struct {
+	repo  Repository
+	cache TokenCache
+	now   func() time.Time
+}
+
+// NewService builds the service. cache may be nil when the auth cache is
+// disabled.
+func NewService(repo Repository, cache TokenCache) *Service {
+	return &Service{
+		repo:  repo,
+		cache: cache,
+		now:   time.Now,
+	}
+}
+
+// Track records the device behind an authenticated access token and
+// returns its session, or ErrSessionRevoked when the session was logged
+// out. last_seen_at is only written when it is more than a minute old or
+// the token changed.
+func (s *Service) Track(ctx context.Context, input TrackInput) (*Session, error) {
+	ctx, span := tracing.Start(ctx, "sessions.Track")
+	defer span.End()
+
+	now := s.now().UTC()
+	session, err := s.repo.GetSessionByKey(ctx, input.Key)
+	if errors.Is(err, ErrSessionNotFound) {
+		session, err = s.create(ctx, input, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil || session.UserID != input.UserID {
+		return nil, ErrSessionRevoked
+	}
+
+	if session.TokenHash != input.TokenHash || now.Sub(session.LastSeenAt) >= lastSeenMinStep {
+		if err := s.repo.TouchSession(ctx, session.ID, input.TokenHash, input.ExpiresAt, now); err == nil {
+			session.TokenHash = input.TokenHash
+			session.ExpiresAt = input.ExpiresAt
+			session.LastSeenAt = now
+		}
+	}
+	return session, nil
+}
+
+func (s *Service) create(ctx context.Context, input TrackInput, now time.Time) (*Session, error) {
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	session := Session{
+		ID:         newID,
+		UserID:     input.UserID,
+		Key:        input.Key,
+		TokenHash:  input.TokenHash,
+		UserAgent:  truncate(strings.TrimSpace(input.UserAgent), maxUserAgentLength),
+		IP:         input.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  input.ExpiresAt,
+	}
+	if name := strings.TrimSpace(input.DeviceName); name != "" {
+		name = truncate(name, MaxDeviceNameLength)
+		session.DeviceName = &name
+	}
+
+	created, err := s.repo.CreateSession(ctx, &session)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return s.repo.GetSessionByKey(ctx, input.Key)
+	}
+	return &session, nil
+}
+
+// ListSessions returns the devices that used the account recently, most
+// recently seen first.
+func (s *Service) ListSessions(ctx context.Context, userID string) ([]Session, error) {
+	ctx, span := tracing.Start(ctx, "sessions.ListSessions")
+	defer span.End()
+
+	now := s.now().UTC()
+	return s.repo.ListSessions(ctx, userID, now.Add(-ActiveWindow), now, MaxListed)
+}
+
+// RevokeSession logs a device out: its next request is rejected once the
+// token is no longer cached.
+func (s *Service) RevokeSession(ctx context.Context, userID, id string) error {
+	ctx, span := tracing.Start(ctx, "sessions.RevokeSession")
+	defer span.End()
+
+	session, err := s.repo.RevokeSession(ctx, userID, id, s.now().UTC())
+	if err != nil {
+		return err
+	}
+	s.forget(ctx, *session)
+	return nil
+}
+
+// RevokeOtherSessions logs out every device but the one with keepID, or
+// every device when keepID is empty, and returns how many were revoked.
+func (s *Service) RevokeOtherSessions(ctx context.Context, userID, keepID string) (int, error) {
+	ctx, span := tracing.Start(ctx, "sessions.RevokeOtherSessions")
+	defer span.End()
+
+	revoked, err := s.repo.RevokeOtherSessions(ctx, userID, keepID, s.now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	for _, session := range revoked {
+		s.forget(ctx, session)
+	}
+	return len(revoked), nil
+}
+
+// Purge removes sessions unseen for longer than ActiveWindow and revoked
+// ones older than RevokedRetention.
+func (s *Service) Purge(ctx context.Context) (*PurgeResult, error) {
+	ctx, span := tracing.Start(ctx, "sessions.Purge")
+	defer span.End()
+
+	now := s.now().UTC()
+	result := PurgeResult{
+		SeenBefore:    now.Add(-ActiveWindow),
+		RevokedBefore: now.Add(-RevokedRetention),
+	}
+	deleted, err := s.repo.DeleteStale(ctx, result.SeenBefore, result.RevokedBefore)
+	if err != nil {
+		return nil, err
+	}
+	result.Deleted = deleted
+	return &result, nil
+}
+
+// forget drops the session's last token from the auth cache. A failure only
+// delays the revocation until the cache entry expires.
+func (s *Service) forget(ctx context.Context, session Session) {
+	if s.cache == nil {
+		return
+	}
+	_ = s.cache.Delete(ctx, session.TokenHash)
+}
+
+func truncate(value string, maxRunes int) string {
+	if utf8.RuneCountInString(value) <= maxRunes {
+		return value
+	}
+	return string([]rune(value)[:maxRunes])
+}
@@ -0,0 +1,208 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeSessionsRepo struct {
+	sessions map[string]Session
+	touches  int
+}
+
+func newFakeSessionsRepo() *fakeSessionsRepo {
+	return &fakeSessionsRepo{sessions: map[string]Session{}}
+}
+
+func (r *fakeSessionsRepo) CreateSession(_ context.Context, session *Session) (bool, error) {
+	for _, existing := range r.sessions {
+		if existing.Key == session.Key {
+			return false, nil
+		}
+	}
+	r.sessions[session.ID] = *session
+	return true, nil
+}
+
+func (r *fakeSessionsRepo) GetSessionByKey(_ context.Context, key string) (*Session, error) {
+	for _, session := range r.sessions {
+		if session.Key == key {
+			session := session
+			return &session, nil
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+func (r *fakeSessionsRepo) ListSessions(_ context.Context, userID string, seenSince, now time.Time, limit int) ([]Session, error) {
+	var result []Session
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && !session.LastSeenAt.Before(seenSince) &&
+			(session.ExpiresAt == nil || session.ExpiresAt.After(now)) && len(result) < limit {
+			result = append(result, session)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeSessionsRepo) TouchSession(_ context.Context, id, tokenHash string, expiresAt *time.Time, at time.Time) error {
+	session := r.sessions[id]
+	session.TokenHash = tokenHash
+	session.ExpiresAt = expiresAt
+	session.LastSeenAt = at
+	r.sessions[id] = session
+	r.touches++
+	return nil
+}
+
+func (r *fakeSessionsRepo) RevokeSession(_ context.Context, userID, id string, at time.Time) (*Session, error) {
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return nil, ErrSessionNotFound
+	}
+	session.RevokedAt = &at
+	r.sessions[id] = session
+	return &session, nil
+}
+
+func (r *fakeSessionsRepo) RevokeOtherSessions(ctx context.Context, userID, keepID string, at time.Time) ([]Session, error) {
+	var revoked []Session
+	for id, session := range r.sessions {
+		if session.UserID != userID || session.RevokedAt != nil || id == keepID {
+			continue
+		}
+		session.RevokedAt = &at
+		r.sessions[id] = session
+		revoked = append(revoked, session)
+	}
+	return revoked, nil
+}
+
+func (r *fakeSessionsRepo) DeleteStale(_ context.Context, seenBefore, revokedBefore time.Time) (int64, error) {
+	var deleted int64
+	for id, session := range r.sessions {
+		if (session.RevokedAt == nil && session.LastSeenAt.Before(seenBefore)) || (session.RevokedAt != nil && session.RevokedAt.Before(revokedBefore)) {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type fakeTokenCache struct {
+	deleted []string
+}
+
+func (c *fakeTokenCache) Delete(_ context.Context, key string) error {
+	c.deleted = append(c.deleted, key)
+	return nil
+}
+
+func newTestService(repo Repository, cache TokenCache, now *time.Time) *Service {
+	service := NewService(repo, cache)
+	service.now = func() time.Time { return *now }
+	return service
+}
+
+func TestTrackReusesProviderSessionAcrossTokens(t *testing.T) {
+	repo := newFakeSessionsRepo()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	service := newTestService(repo, nil, &now)
+	ctx := context.Background()
+
+	first, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "supabase:sid-1", TokenHash: "hash-1", DeviceName: " Anna's iPhone ", UserAgent: "FamilyApp/2.3 iOS"})
+	if err != nil {
+		t.Fatalf("track: %v", err)
+	}
+	if first.DeviceName == nil || *first.DeviceName != "Anna's iPhone" {
+		t.Fatalf("unexpected device name: %+v", first.DeviceName)
+	}
+
+	now = now.Add(10 * time.Second)
+	again, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "supabase:sid-1", TokenHash: "hash-1"})
+	if err != nil {
+		t.Fatalf("track again: %v", err)
+	}
+	if again.ID != first.ID || repo.touches != 0 {
+		t.Fatalf("expected the same session without a write, got %s (touches %d)", again.ID, repo.touches)
+	}
+
+	refreshed, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "supabase:sid-1", TokenHash: "hash-2"})
+	if err != nil {
+		t.Fatalf("track refreshed token: %v", err)
+	}
+	if refreshed.ID != first.ID || repo.sessions[first.ID].TokenHash != "hash-2" || len(repo.sessions) != 1 {
+		t.Fatalf("expected the refreshed token to update the session: %+v", repo.sessions)
+	}
+}
+
+func TestRevokedSessionsAreRejectedAndForgotten(t *testing.T) {
+	repo := newFakeSessionsRepo()
+	cache := &fakeTokenCache{}
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	service := newTestService(repo, cache, &now)
+	ctx := context.Background()
+
+	phone, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "supabase:phone", TokenHash: "phone-hash"})
+	if err != nil {
+		t.Fatalf("track phone: %v", err)
+	}
+	laptop, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "supabase:laptop", TokenHash: "laptop-hash"})
+	if err != nil {
+		t.Fatalf("track laptop: %v", err)
+	}
+	tablet, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "supabase:tablet", TokenHash: "tablet-hash"})
+	if err != nil {
+		t.Fatalf("track tablet: %v", err)
+	}
+
+	if err := service.RevokeSession(ctx, "user-2", tablet.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound for a foreign session, got %v", err)
+	}
+	if err := service.RevokeSession(ctx, "user-1", tablet.ID); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	revoked, err := service.RevokeOtherSessions(ctx, "user-1", phone.ID)
+	if err != nil {
+		t.Fatalf("revoke others: %v", err)
+	}
+	if revoked != 1 || repo.sessions[laptop.ID].RevokedAt == nil || len(cache.deleted) != 2 || cache.deleted[0] != "tablet-hash" || cache.deleted[1] != "laptop-hash" {
+		t.Fatalf("expected the laptop to be revoked and both tokens forgotten, got %d, %v", revoked, cache.deleted)
+	}
+
+	if _, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "supabase:laptop", TokenHash: "laptop-hash-2"}); !errors.Is(err, ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked for a refreshed token of the laptop, got %v", err)
+	}
+	listed, err := service.ListSessions(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != phone.ID {
+		t.Fatalf("expected only the phone to be listed, got %+v", listed)
+	}
+}
+
+func TestPurgeDeletesStaleSessions(t *testing.T) {
+	repo := newFakeSessionsRepo()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	service := newTestService(repo, nil, &now)
+	ctx := context.Background()
+
+	if _, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "token:old", TokenHash: "old"}); err != nil {
+		t.Fatalf("track: %v", err)
+	}
+	now = now.Add(ActiveWindow + time.Hour)
+	if _, err := service.Track(ctx, TrackInput{UserID: "user-1", Key: "token:new", TokenHash: "new"}); err != nil {
+		t.Fatalf("track: %v", err)
+	}
+
+	result, err := service.Purge(ctx)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.Deleted != 1 || len(repo.sessions) != 1 {
+		t.Fatalf("expected the stale session to be deleted, got %+v", result)
+	}
+}
@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+// errBatchAborted rolls back the transaction of an atomic batch once one of
+// its operations failed.
+var errBatchAborted = errors.New("sync batch aborted")
+
+// batchTx holds the repositories bound to an atomic batch's transaction.
+type batchTx struct {
+	sync     Repository
+	expenses expensesdomain.Repository
+	todos    todosdomain.Repository
+}
+
+// processAtomicBatch applies the batch in one transaction, including its
+// idempotency record. When an operation fails nothing is kept: the response
+// reports that failure, and every other operation that was not a duplicate
+// fails with batch_aborted. An aborted batch is not stored, so the client can
+// retry it with the same Idempotency-Key.
+func (s *Service) processAtomicBatch(ctx context.Context, input BatchInput, syncID, requestHash string) (*BatchResponse, error) {
+	idempotencyKey := strings.TrimSpace(input.IdempotencyKey)
+
+	var response *BatchResponse
+	err := s.repo.Transaction(ctx, func(syncTx Repository, expensesTx expensesdomain.Repository, todosTx todosdomain.Repository) error {
+		if idempotencyKey != "" {
+			cached, err := beginBatch(ctx, syncTx, input, syncID, requestHash)
+			if err != nil {
+				return err
+			}
+			if cached != nil {
+				response = cached
+				return nil
+			}
+		}
+
+		response = s.applyOperations(ctx, input, syncID, &batchTx{sync: syncTx, expenses: expensesTx, todos: todosTx})
+		if response.Summary.Failed > 0 {
+			abortBatch(response, input.Operations)
+			return errBatchAborted
+		}
+
+		if idempotencyKey != "" {
+			encoded, err := json.Marshal(response)
+			if err != nil {
+				return err
+			}
+			return syncTx.CompleteBatch(ctx, syncID, BatchStateCompleted, encoded)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		return nil, err
+	}
+	return response, nil
+}
+
+// abortBatch rewrites the response of an atomic batch that stopped at a
+// failed operation. Operations applied before it were rolled back and those
+// after it never ran; duplicates keep their result, since an earlier batch
+// applied them.
+func abortBatch(response *BatchResponse, operations []OperationInput) {
+	response.Results = append(response.Results, make([]OperationResult, len(operations)-len(response.Results))...)
+	response.Mappings = make([]EntityMapping, 0)
+	response.Summary = BatchSummary{Total: len(operations)}
+
+	for i, operation := range operations {
+		result := response.Results[i]
+		switch result.Status {
+		case ResultStatusDuplicate:
+			response.Summary.Duplicate++
+			if result.LocalID != nil && result.ServerID != nil && result.Entity != nil {
+				response.Mappings = append(response.Mappings, EntityMapping{
+					Entity:   *result.Entity,
+					LocalID:  *result.LocalID,
+					ServerID: *result.ServerID,
+				})
+			}
+			continue
+		case ResultStatusFailed:
+		default:
+			result = failResult(OperationResult{
+				OperationID: operation.OperationID,
+				Type:        operation.Type,
+			}, ErrorCodeBatchAborted, "batch rolled back after another operation failed", true)
+		}
+		response.Results[i] = result
+		response.Summary.Failed++
+	}
+
+	response.Status = BatchStatusFailed
+}
+
+func (s *Service) createExpense(ctx context.Context, tx *batchTx, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error) {
+	if tx != nil {
+		return s.expenses.CreateExpenseWithRepository(ctx, tx.expenses, input)
+	}
+	return s.expenses.CreateExpense(ctx, input)
+}
+
+func (s *Service) createCategory(ctx context.Context, tx *batchTx, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error) {
+	if tx != nil {
+		return s.expenses.CreateCategoryWithRepository(ctx, tx.expenses, input)
+	}
+	return s.expenses.CreateCategory(ctx, input)
+}
+
+func (s *Service) createTodoItem(ctx context.Context, tx *batchTx, familyID string, input todosdomain.CreateTodoItemInput) (*todosdomain.TodoItem, error) {
+	if tx != nil {
+		return s.todos.CreateTodoItemWithRepository(ctx, tx.todos, familyID, input)
+	}
+	return s.todos.CreateTodoItem(ctx, familyID, input)
+}
+
+func (s *Service) updateTodoItem(ctx context.Context, tx *batchTx, input todosdomain.UpdateTodoItemInput) (*todosdomain.TodoItem, error) {
+	if tx != nil {
+		return s.todos.UpdateTodoItemWithRepository(ctx, tx.todos, input)
+	}
+	return s.todos.UpdateTodoItem(ctx, input)
+}
@@ -0,0 +1,116 @@
+package sync_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	syncdomain "family-app-go/internal/domain/sync"
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/pkg/familytest"
+	"family-app-go/pkg/id"
+)
+
+// BenchmarkProcessBatch applies a full batch of mixed operations, the shape
+// a client sends after a day offline. Repositories are in memory and start
+// empty for every batch, so this measures the service: validation, hashing,
+// local ID mapping and the per-operation bookkeeping.
+func BenchmarkProcessBatch(b *testing.B) {
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		syncs, input := newBenchBatch(b)
+		b.StartTimer()
+
+		response, err := syncs.ProcessBatch(ctx, input)
+		if err != nil {
+			b.Fatalf("process batch: %v", err)
+		}
+		if response.Summary.Applied != len(input.Operations) {
+			b.Fatalf("expected every operation applied, got %+v", response.Summary)
+		}
+	}
+}
+
+// newBenchBatch sets up a family with a category and a todo list in a fresh
+// store and returns a sync service over it with a batch to apply.
+func newBenchBatch(b *testing.B) (*syncdomain.Service, syncdomain.BatchInput) {
+	b.Helper()
+
+	ctx := context.Background()
+	store := familytest.NewStore()
+	expenses := expensesdomain.NewService(store.Expenses)
+	todos := todosdomain.NewService(store.Todos)
+
+	family, err := familydomain.NewService(store.Family).CreateFamily(ctx, "user-1", "Bench Family")
+	if err != nil {
+		b.Fatalf("create family: %v", err)
+	}
+	category, err := expenses.CreateCategory(ctx, expensesdomain.CreateCategoryInput{FamilyID: family.ID, Name: "Food"})
+	if err != nil {
+		b.Fatalf("create category: %v", err)
+	}
+	list, err := todos.CreateTodoList(ctx, todosdomain.CreateTodoListInput{FamilyID: family.ID, Title: "Groceries"})
+	if err != nil {
+		b.Fatalf("create list: %v", err)
+	}
+	return syncdomain.NewService(store.Sync, expenses, todos), benchBatch(b, family, category.ID, list.List.ID)
+}
+
+// benchBatch builds MaxBatchOperations operations: expenses, and todos that
+// are created and then completed by local ID.
+func benchBatch(b *testing.B, family *familydomain.Family, categoryID, listID string) syncdomain.BatchInput {
+	b.Helper()
+
+	input := syncdomain.BatchInput{
+		FamilyID:       family.ID,
+		BaseCurrency:   family.DefaultCurrency,
+		User:           syncdomain.UserSnapshot{ID: "user-1", Name: "Owner"},
+		IdempotencyKey: "bench-batch-key-1",
+		Operations:     make([]syncdomain.OperationInput, 0, syncdomain.MaxBatchOperations),
+	}
+	for len(input.Operations) < syncdomain.MaxBatchOperations {
+		n := len(input.Operations)
+		var op syncdomain.OperationInput
+		switch n % 3 {
+		case 0:
+			op = syncdomain.OperationInput{
+				Type:    syncdomain.OperationTypeCreateExpense,
+				LocalID: fmt.Sprintf("expense-%d", n),
+				CreateExpense: &syncdomain.CreateExpensePayload{
+					Date:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+					Amount:      float64(n) + 0.5,
+					Currency:    family.DefaultCurrency,
+					Title:       "Expense",
+					CategoryIDs: []string{categoryID},
+				},
+			}
+		case 1:
+			op = syncdomain.OperationInput{
+				Type:       syncdomain.OperationTypeCreateTodo,
+				LocalID:    fmt.Sprintf("todo-%d", n),
+				CreateTodo: &syncdomain.CreateTodoPayload{ListID: listID, Title: "Todo"},
+			}
+		default:
+			op = syncdomain.OperationInput{
+				Type: syncdomain.OperationTypeSetTodoCompleted,
+				SetTodoCompleted: &syncdomain.SetTodoCompletedPayload{
+					TodoLocalID: fmt.Sprintf("todo-%d", n-1),
+					IsCompleted: true,
+				},
+			}
+		}
+		operationID, err := id.New()
+		if err != nil {
+			b.Fatalf("operation id: %v", err)
+		}
+		op.OperationID = operationID
+		input.Operations = append(input.Operations, op)
+	}
+	return input
+}
@@ -0,0 +1,33 @@
+package sync
+
+import "time"
+
+// clientClock maps timestamps taken by a client's clock onto server time.
+type clientClock struct {
+	skew time.Duration
+	now  time.Time
+}
+
+// newClientClock measures the skew between clientTime, the client's clock
+// when it sent the batch, and serverTime. Without clientTime client
+// timestamps are taken as they are.
+func newClientClock(clientTime *time.Time, serverTime time.Time) clientClock {
+	clock := clientClock{now: serverTime}
+	if clientTime != nil {
+		clock.skew = serverTime.Sub(*clientTime)
+	}
+	return clock
+}
+
+// correct shifts value by the skew and caps it at server time, so a client
+// clock running ahead never dates anything in the future. nil stays nil.
+func (c clientClock) correct(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	corrected := value.Add(c.skew).UTC()
+	if corrected.After(c.now) {
+		corrected = c.now
+	}
+	return &corrected
+}
@@ -6,4 +6,8 @@ var (
 	ErrBatchTooLarge                 = errors.New("sync batch too large")
 	ErrIdempotencyKeyPayloadMismatch = errors.New("idempotency key payload mismatch")
 	ErrBatchInProgress               = errors.New("sync batch in progress")
+	ErrUnknownEntity                 = errors.New("unknown sync entity")
+	ErrTooManyLocalIDs               = errors.New("too many local ids")
+	ErrInvalidCursor                 = errors.New("invalid change feed cursor")
+	ErrCursorExpired                 = errors.New("change feed cursor expired")
 )
@@ -0,0 +1,138 @@
+package sync
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	DefaultEventLimit = 100
+	MaxEventLimit     = 500
+)
+
+type ChangeOp string
+
+const (
+	// ChangeOpUpsert means the record was created or changed; clients read
+	// it again, e.g. with POST /batch/get.
+	ChangeOpUpsert ChangeOp = "upsert"
+	ChangeOpDelete ChangeOp = "delete"
+)
+
+// ChangeEvent is one entry of a family's change log. Database triggers write
+// it in the transaction that changed the record, numbering each family's
+// events from 1 without gaps.
+type ChangeEvent struct {
+	FamilyID   string    `json:"-" gorm:"type:uuid;primaryKey"`
+	Seq        int64     `json:"seq" gorm:"primaryKey"`
+	Entity     Entity    `json:"entity" gorm:"column:entity_type"`
+	EntityID   string    `json:"entity_id" gorm:"type:uuid"`
+	Op         ChangeOp  `json:"op"`
+	OccurredAt time.Time `json:"occurred_at" gorm:"column:created_at"`
+}
+
+func (ChangeEvent) TableName() string {
+	return "change_events"
+}
+
+type ChangeFeedBounds struct {
+	LastSeq   int64
+	OldestSeq int64
+}
+
+// EventsPage is a slice of the change log after a cursor.
+type EventsPage struct {
+	Events []ChangeEvent `json:"events"`
+	// NextCursor resumes after the last event of the page, or repeats the
+	// requested cursor when there was none.
+	NextCursor string          `json:"next_cursor"`
+	HasMore    bool            `json:"has_more"`
+	Retention  EventsRetention `json:"retention"`
+}
+
+// EventsRetention tells clients how far back the log reaches. A cursor older
+// than OldestCursor has lost events and needs a full resync.
+type EventsRetention struct {
+	Days         int    `json:"days"`
+	OldestCursor string `json:"oldest_cursor"`
+	LatestCursor string `json:"latest_cursor"`
+}
+
+// Events returns the family's changes after cursor, oldest first. Cursor "0"
+// starts at the beginning of the log. Events are kept for the sync
+// retention period; a cursor whose next events were purged fails with
+// ErrCursorExpired.
+func (s *Service) Events(ctx context.Context, familyID, cursor string, limit int) (*EventsPage, error) {
+	ctx, span := tracing.Start(ctx, "sync.Events")
+	defer span.End()
+
+	afterSeq, err := parseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultEventLimit
+	}
+	if limit > MaxEventLimit {
+		limit = MaxEventLimit
+	}
+
+	bounds, err := s.repo.ChangeFeedBounds(ctx, familyID)
+	if err != nil {
+		return nil, err
+	}
+	// Without stored events, everything up to the latest one was purged.
+	oldestCursor := bounds.LastSeq
+	if bounds.OldestSeq > 0 {
+		oldestCursor = bounds.OldestSeq - 1
+	}
+	if afterSeq > bounds.LastSeq {
+		return nil, ErrInvalidCursor
+	}
+	if afterSeq < oldestCursor {
+		return nil, ErrCursorExpired
+	}
+
+	events, err := s.repo.ListChangeEvents(ctx, familyID, afterSeq, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	// Seqs have no gaps, so a missing next event was purged meanwhile.
+	if len(events) > 0 && events[0].Seq != afterSeq+1 {
+		return nil, ErrCursorExpired
+	}
+	page := &EventsPage{
+		Events:     events,
+		NextCursor: formatCursor(afterSeq),
+		Retention: EventsRetention{
+			Days:         s.retentionDays,
+			OldestCursor: formatCursor(oldestCursor),
+			LatestCursor: formatCursor(bounds.LastSeq),
+		},
+	}
+	if len(events) > limit {
+		page.Events, page.HasMore = events[:limit], true
+	}
+	if len(page.Events) > 0 {
+		page.NextCursor = formatCursor(page.Events[len(page.Events)-1].Seq)
+	}
+	return page, nil
+}
+
+// Cursors are the seq of the last event a client has seen. They are opaque
+// to clients.
+func parseCursor(cursor string) (int64, error) {
+	seq, err := strconv.ParseInt(strings.TrimSpace(cursor), 10, 64)
+	if err != nil || seq < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return seq, nil
+}
+
+func formatCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
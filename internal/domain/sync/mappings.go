@@ -0,0 +1,45 @@
+package sync
+
+import (
+	"context"
+
+	"family-app-go/pkg/tracing"
+)
+
+// ResolveMappings looks up the server IDs of entities the user created
+// through sync, so a client that lost its responses can re-link its local
+// database without uploading again. Mappings come from the operations
+// table: they outlive deleted entities but not the retention period.
+func (s *Service) ResolveMappings(ctx context.Context, familyID, userID string, entity Entity, localIDs []string) (*MappingsResult, error) {
+	ctx, span := tracing.Start(ctx, "sync.ResolveMappings")
+	defer span.End()
+
+	switch entity {
+	case EntityExpense, EntityTodoItem, EntityCategory:
+	default:
+		return nil, ErrUnknownEntity
+	}
+	if len(localIDs) > MaxMappingLocalIDs {
+		return nil, ErrTooManyLocalIDs
+	}
+
+	serverIDs, err := s.repo.FindServerIDsByLocalIDs(ctx, familyID, userID, entity, localIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MappingsResult{
+		Entity:   entity,
+		Mappings: make([]EntityMapping, 0, len(serverIDs)),
+		Missing:  make([]string, 0),
+	}
+	for _, localID := range localIDs {
+		serverID, ok := serverIDs[localID]
+		if !ok {
+			result.Missing = append(result.Missing, localID)
+			continue
+		}
+		result.Mappings = append(result.Mappings, EntityMapping{Entity: entity, LocalID: localID, ServerID: serverID})
+	}
+	return result, nil
+}
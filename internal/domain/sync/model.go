@@ -4,12 +4,16 @@ import "time"
 
 const MaxBatchOperations = 100
 
+// MaxMappingLocalIDs caps the local IDs one mappings lookup resolves.
+const MaxMappingLocalIDs = 500
+
 type OperationType string
 
 const (
 	OperationTypeCreateExpense    OperationType = "create_expense"
 	OperationTypeCreateTodo       OperationType = "create_todo"
 	OperationTypeSetTodoCompleted OperationType = "set_todo_completed"
+	OperationTypeCreateCategory   OperationType = "create_category"
 )
 
 type ResultStatus string
@@ -18,6 +22,9 @@ const (
 	ResultStatusApplied   ResultStatus = "applied"
 	ResultStatusDuplicate ResultStatus = "duplicate"
 	ResultStatusFailed    ResultStatus = "failed"
+	// ResultStatusValid is the verdict of ValidateBatch for an operation
+	// that would be applied.
+	ResultStatusValid ResultStatus = "valid"
 )
 
 type BatchStatus string
@@ -43,6 +50,9 @@ const (
 	ErrorCodeSyncBatchTooLarge             ErrorCode = "sync_batch_too_large"
 	ErrorCodeIdempotencyKeyPayloadMismatch ErrorCode = "idempotency_key_payload_mismatch"
 	ErrorCodeBatchInProgress               ErrorCode = "batch_in_progress"
+	ErrorCodeQuotaExceeded                 ErrorCode = "quota_exceeded"
+	ErrorCodeBatchAborted                  ErrorCode = "batch_aborted"
+	ErrorCodeMaintenance                   ErrorCode = "maintenance"
 	ErrorCodeInternalError                 ErrorCode = "internal_error"
 )
 
@@ -51,6 +61,14 @@ type Entity string
 const (
 	EntityExpense  Entity = "expense"
 	EntityTodoItem Entity = "todo_item"
+	EntityCategory Entity = "category"
+	// EntityExpenseApproval is what a create_expense above the member's
+	// spending limit becomes; the expense exists once the owner approves.
+	EntityExpenseApproval Entity = "expense_approval"
+	// Todo lists and workouts are only change feed and snapshot entities;
+	// sync operations do not create them.
+	EntityTodoList Entity = "todo_list"
+	EntityWorkout  Entity = "workout"
 )
 
 type BatchState string
@@ -80,16 +98,30 @@ type BatchInput struct {
 	BaseCurrency   string
 	User           UserSnapshot
 	IdempotencyKey string
-	Operations     []OperationInput
+	// ClientTime is the client's clock when it sent the batch. The
+	// difference to server time corrects every OccurredAt in the batch.
+	ClientTime *time.Time
+	// Atomic applies the operations in one transaction: the first failure
+	// rolls back the whole batch.
+	Atomic     bool
+	Operations []OperationInput
+
+	// AllowedCurrencies is the family's allowed currency list.
+	AllowedCurrencies []string
 }
 
 type OperationInput struct {
-	OperationID      string
-	Type             OperationType
-	LocalID          string
+	OperationID string
+	Type        OperationType
+	LocalID     string
+	// OccurredAt is when the client recorded the operation, by its own
+	// clock. Created expenses and todos keep it as their creation time and
+	// completed todos as their completion time; nil means when applied.
+	OccurredAt       *time.Time
 	CreateExpense    *CreateExpensePayload
 	CreateTodo       *CreateTodoPayload
 	SetTodoCompleted *SetTodoCompletedPayload
+	CreateCategory   *CreateCategoryPayload
 }
 
 type CreateExpensePayload struct {
@@ -98,6 +130,16 @@ type CreateExpensePayload struct {
 	Currency    string
 	Title       string
 	CategoryIDs []string
+	// CategoryLocalIDs refer to categories created by create_category
+	// operations, earlier in the batch or synced before. The tag keeps the
+	// payload hash of operations without them unchanged.
+	CategoryLocalIDs []string `json:",omitempty"`
+}
+
+type CreateCategoryPayload struct {
+	Name  string
+	Color *string
+	Emoji *string
 }
 
 type CreateTodoPayload struct {
@@ -112,7 +154,8 @@ type SetTodoCompletedPayload struct {
 }
 
 type BatchResponse struct {
-	SyncID     string            `json:"sync_id"`
+	SyncID     string            `json:"sync_id,omitempty"`
+	DryRun     bool              `json:"dry_run,omitempty"`
 	Status     BatchStatus       `json:"status"`
 	Summary    BatchSummary      `json:"summary"`
 	Results    []OperationResult `json:"results"`
@@ -123,6 +166,7 @@ type BatchResponse struct {
 type BatchSummary struct {
 	Total     int `json:"total"`
 	Applied   int `json:"applied"`
+	Valid     int `json:"valid,omitempty"`
 	Duplicate int `json:"duplicate"`
 	Failed    int `json:"failed"`
 }
@@ -149,6 +193,15 @@ type EntityMapping struct {
 	ServerID string `json:"server_id"`
 }
 
+// MappingsResult resolves local IDs the user uploaded to server IDs.
+// Missing lists the local IDs no applied operation created, in the order
+// asked.
+type MappingsResult struct {
+	Entity   Entity          `json:"entity"`
+	Mappings []EntityMapping `json:"mappings"`
+	Missing  []string        `json:"missing"`
+}
+
 type BatchRecord struct {
 	ID             string     `gorm:"type:uuid;primaryKey"`
 	FamilyID       string     `gorm:"type:uuid;not null;index"`
@@ -186,3 +239,21 @@ type OperationRecord struct {
 func (OperationRecord) TableName() string {
 	return "sync_operations"
 }
+
+// StorageStats estimates the sync tables' size. Rows come from the planner
+// statistics, so they lag until the next analyze.
+type StorageStats struct {
+	OperationRows  int64 `json:"operation_rows"`
+	OperationBytes int64 `json:"operation_bytes"`
+	BatchRows      int64 `json:"batch_rows"`
+	BatchBytes     int64 `json:"batch_bytes"`
+}
+
+// PurgeResult reports a retention run.
+type PurgeResult struct {
+	DeletedBefore     time.Time    `json:"deleted_before"`
+	OperationsDeleted int64        `json:"operations_deleted"`
+	BatchesDeleted    int64        `json:"batches_deleted"`
+	EventsDeleted     int64        `json:"events_deleted"`
+	Storage           StorageStats `json:"storage"`
+}
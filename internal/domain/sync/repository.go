@@ -1,11 +1,50 @@
 package sync
 
-import "context"
+import (
+	"context"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	todosdomain "family-app-go/internal/domain/todos"
+)
 
 type Repository interface {
+	// Transaction runs fn in one transaction spanning the sync, expenses
+	// and todos tables, rolling all of it back when fn fails.
+	Transaction(ctx context.Context, fn func(Repository, expensesdomain.Repository, todosdomain.Repository) error) error
 	BeginBatch(ctx context.Context, batch *BatchRecord) (bool, *BatchRecord, error)
 	CompleteBatch(ctx context.Context, batchID string, status BatchState, responseJSON []byte) error
 	ReserveOperation(ctx context.Context, operation *OperationRecord) (bool, *OperationRecord, error)
 	UpdateOperation(ctx context.Context, operation *OperationRecord) error
+	// FindOperation returns the user's operation with operationID, or nil
+	// when there is none.
+	FindOperation(ctx context.Context, familyID, userID, operationID string) (*OperationRecord, error)
+	// CountOperationsSince counts the operations reserved for the family at or
+	// after since, whatever their outcome.
+	CountOperationsSince(ctx context.Context, familyID string, since time.Time) (int64, error)
 	FindServerIDByLocalID(ctx context.Context, familyID, userID string, entity Entity, localID string) (string, bool, error)
+	// FindServerIDsByLocalIDs maps each of localIDs with an applied
+	// operation to the server ID its latest one created; others are left out.
+	FindServerIDsByLocalIDs(ctx context.Context, familyID, userID string, entity Entity, localIDs []string) (map[string]string, error)
+	// DeleteOperationsBefore removes up to limit operations created before
+	// cutoff and returns how many it removed.
+	DeleteOperationsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	// DeleteBatchesBefore removes up to limit batches created before cutoff
+	// and returns how many it removed.
+	DeleteBatchesBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	// ChangeFeedBounds returns the family's latest event seq and the oldest
+	// one still stored, both 0 when there are none.
+	ChangeFeedBounds(ctx context.Context, familyID string) (ChangeFeedBounds, error)
+	// ListChangeEvents returns up to limit of the family's events after seq,
+	// in seq order.
+	ListChangeEvents(ctx context.Context, familyID string, afterSeq int64, limit int) ([]ChangeEvent, error)
+	// DeleteChangeEventsBefore removes up to limit events created before
+	// cutoff and returns how many it removed.
+	DeleteChangeEventsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	// StreamSnapshot reads the records a member sees in one consistent
+	// snapshot. It calls begin with the seq of the last event the snapshot
+	// includes, then emit for every record.
+	StreamSnapshot(ctx context.Context, familyID, userID string, begin func(lastSeq int64) error, emit func(SnapshotRecord) error) error
+	// StorageStats estimates the size of the sync tables.
+	StorageStats(ctx context.Context) (StorageStats, error)
 }
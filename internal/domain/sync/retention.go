@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	DefaultRetentionDays = 90
+
+	// purgeChunkSize bounds each delete statement, so a purge of a large
+	// backlog never holds row locks or bloats WAL in one transaction.
+	purgeChunkSize = 5000
+)
+
+// storageMetrics publishes the sync tables' size and purge totals with the
+// other expvars at GET /api/admin/debug/vars.
+var storageMetrics = expvar.NewMap("sync_storage")
+
+// Purge removes operations, batches and change events older than the
+// retention period. Clients replaying an operation after that are no longer
+// deduplicated, and clients behind on events need a full resync, so the
+// period must outlast the longest time a client stays offline.
+func (s *Service) Purge(ctx context.Context) (*PurgeResult, error) {
+	ctx, span := tracing.Start(ctx, "sync.Purge")
+	defer span.End()
+
+	result := &PurgeResult{DeletedBefore: time.Now().UTC().AddDate(0, 0, -s.retentionDays)}
+	var err error
+	result.OperationsDeleted, err = purgeInChunks(ctx, result.DeletedBefore, s.repo.DeleteOperationsBefore)
+	storageMetrics.Add("operations_purged", result.OperationsDeleted)
+	if err != nil {
+		return result, err
+	}
+	result.BatchesDeleted, err = purgeInChunks(ctx, result.DeletedBefore, s.repo.DeleteBatchesBefore)
+	storageMetrics.Add("batches_purged", result.BatchesDeleted)
+	if err != nil {
+		return result, err
+	}
+	result.EventsDeleted, err = purgeInChunks(ctx, result.DeletedBefore, s.repo.DeleteChangeEventsBefore)
+	storageMetrics.Add("events_purged", result.EventsDeleted)
+	if err != nil {
+		return result, err
+	}
+
+	result.Storage, err = s.repo.StorageStats(ctx)
+	if err != nil {
+		return result, err
+	}
+	publishStorageStats(result.Storage)
+	return result, nil
+}
+
+func purgeInChunks(ctx context.Context, cutoff time.Time, deleteBefore func(context.Context, time.Time, int) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		deleted, err := deleteBefore(ctx, cutoff, purgeChunkSize)
+		total += deleted
+		if err != nil || deleted < purgeChunkSize {
+			return total, err
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+func publishStorageStats(stats StorageStats) {
+	set := func(key string, value int64) {
+		gauge := new(expvar.Int)
+		gauge.Set(value)
+		storageMetrics.Set(key, gauge)
+	}
+	set("operation_rows", stats.OperationRows)
+	set("operation_bytes", stats.OperationBytes)
+	set("batch_rows", stats.BatchRows)
+	set("batch_bytes", stats.BatchBytes)
+}
@@ -2,7 +2,6 @@ package sync
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -12,41 +11,92 @@ import (
 	"time"
 
 	expensesdomain "family-app-go/internal/domain/expenses"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	quotadomain "family-app-go/internal/domain/quota"
 	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/money"
+	"family-app-go/pkg/tracing"
 )
 
 type ExpensesService interface {
 	CreateExpense(ctx context.Context, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error)
+	CreateExpenseWithRepository(ctx context.Context, repo expensesdomain.Repository, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error)
+	ValidateExpense(ctx context.Context, input expensesdomain.CreateExpenseInput) error
+	CreateCategory(ctx context.Context, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error)
+	CreateCategoryWithRepository(ctx context.Context, repo expensesdomain.Repository, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error)
+	ValidateCategory(ctx context.Context, input expensesdomain.CreateCategoryInput) error
 }
 
 type TodosService interface {
 	CreateTodoItem(ctx context.Context, familyID string, input todosdomain.CreateTodoItemInput) (*todosdomain.TodoItem, error)
+	CreateTodoItemWithRepository(ctx context.Context, repo todosdomain.Repository, familyID string, input todosdomain.CreateTodoItemInput) (*todosdomain.TodoItem, error)
+	ValidateTodoItem(ctx context.Context, familyID string, input todosdomain.CreateTodoItemInput) error
 	UpdateTodoItem(ctx context.Context, input todosdomain.UpdateTodoItemInput) (*todosdomain.TodoItem, error)
+	UpdateTodoItemWithRepository(ctx context.Context, repo todosdomain.Repository, input todosdomain.UpdateTodoItemInput) (*todosdomain.TodoItem, error)
+	ValidateTodoItemUpdate(ctx context.Context, input todosdomain.UpdateTodoItemInput) error
+}
+
+// FeatureFlags resolves a feature flag for a family.
+type FeatureFlags interface {
+	Enabled(ctx context.Context, key, familyID string) (bool, error)
 }
 
 type Service struct {
-	repo     Repository
-	expenses ExpensesService
-	todos    TodosService
+	repo              Repository
+	expenses          ExpensesService
+	todos             TodosService
+	flags             FeatureFlags
+	operationsPerHour int
+	retentionDays     int
+}
+
+type ServiceOptions struct {
+	// OperationsPerHour caps the operations a family syncs in any hour,
+	// counting every submitted operation; zero leaves it unlimited.
+	OperationsPerHour int
+	// RetentionDays is how long operations and batches are kept before
+	// Purge removes them.
+	RetentionDays int
+	// Flags turns on maintenance for the domains operations write; nil
+	// never does.
+	Flags FeatureFlags
 }
 
 func NewService(repo Repository, expenses ExpensesService, todos TodosService) *Service {
+	return NewServiceWithOptions(repo, expenses, todos, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, expenses ExpensesService, todos TodosService, options ServiceOptions) *Service {
+	retentionDays := options.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = DefaultRetentionDays
+	}
 	return &Service{
-		repo:     repo,
-		expenses: expenses,
-		todos:    todos,
+		repo:              repo,
+		expenses:          expenses,
+		todos:             todos,
+		flags:             options.Flags,
+		operationsPerHour: options.OperationsPerHour,
+		retentionDays:     retentionDays,
 	}
 }
 
 func (s *Service) ProcessBatch(ctx context.Context, input BatchInput) (*BatchResponse, error) {
+	ctx, span := tracing.Start(ctx, "sync.ProcessBatch")
+	defer span.End()
+
 	if len(input.Operations) == 0 {
 		return nil, fmt.Errorf("operations are required")
 	}
 	if len(input.Operations) > MaxBatchOperations {
 		return nil, ErrBatchTooLarge
 	}
+	if err := s.checkOperationsQuota(ctx, input.FamilyID, len(input.Operations)); err != nil {
+		return nil, err
+	}
 
-	syncID, err := newUUID()
+	syncID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
@@ -56,43 +106,73 @@ func (s *Service) ProcessBatch(ctx context.Context, input BatchInput) (*BatchRes
 		return nil, err
 	}
 
-	idempotencyKey := strings.TrimSpace(input.IdempotencyKey)
-	batchCreated := false
+	if input.Atomic {
+		return s.processAtomicBatch(ctx, input, syncID, requestHash)
+	}
 
+	idempotencyKey := strings.TrimSpace(input.IdempotencyKey)
 	if idempotencyKey != "" {
-		batch := &BatchRecord{
-			ID:             syncID,
-			FamilyID:       input.FamilyID,
-			UserID:         input.User.ID,
-			IdempotencyKey: &idempotencyKey,
-			RequestHash:    requestHash,
-			Status:         BatchStateProcessing,
-		}
-
-		created, existing, err := s.repo.BeginBatch(ctx, batch)
+		cached, err := beginBatch(ctx, s.repo, input, syncID, requestHash)
 		if err != nil {
 			return nil, err
 		}
-		if !created {
-			if existing == nil {
-				return nil, ErrBatchInProgress
-			}
-			if existing.RequestHash != requestHash {
-				return nil, ErrIdempotencyKeyPayloadMismatch
-			}
-			if existing.Status == BatchStateCompleted && len(existing.ResponseJSON) > 0 {
-				var cached BatchResponse
-				if err := json.Unmarshal(existing.ResponseJSON, &cached); err == nil {
-					return &cached, nil
-				}
-			}
-			return nil, ErrBatchInProgress
+		if cached != nil {
+			return cached, nil
 		}
+	}
 
-		batchCreated = true
+	response := s.applyOperations(ctx, input, syncID, nil)
+
+	if idempotencyKey != "" {
+		if encoded, err := json.Marshal(response); err == nil {
+			_ = s.repo.CompleteBatch(ctx, syncID, BatchStateCompleted, encoded)
+		}
+	}
+
+	return response, nil
+}
+
+// beginBatch records a batch sent with an Idempotency-Key. It returns the
+// stored response when the key was already used for the same operations
+// and that batch completed.
+func beginBatch(ctx context.Context, repo Repository, input BatchInput, syncID, requestHash string) (*BatchResponse, error) {
+	idempotencyKey := strings.TrimSpace(input.IdempotencyKey)
+	batch := &BatchRecord{
+		ID:             syncID,
+		FamilyID:       input.FamilyID,
+		UserID:         input.User.ID,
+		IdempotencyKey: &idempotencyKey,
+		RequestHash:    requestHash,
+		Status:         BatchStateProcessing,
 	}
 
-	response := BatchResponse{
+	created, existing, err := repo.BeginBatch(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return nil, nil
+	}
+	if existing == nil {
+		return nil, ErrBatchInProgress
+	}
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyPayloadMismatch
+	}
+	if existing.Status == BatchStateCompleted && len(existing.ResponseJSON) > 0 {
+		var cached BatchResponse
+		if err := json.Unmarshal(existing.ResponseJSON, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+	return nil, ErrBatchInProgress
+}
+
+// applyOperations applies the batch's operations in order. Inside an atomic
+// batch, tx holds the transaction's repositories and it stops at the first
+// failed operation.
+func (s *Service) applyOperations(ctx context.Context, input BatchInput, syncID string, tx *batchTx) *BatchResponse {
+	response := &BatchResponse{
 		SyncID:   syncID,
 		Results:  make([]OperationResult, 0, len(input.Operations)),
 		Mappings: make([]EntityMapping, 0),
@@ -102,16 +182,15 @@ func (s *Service) ProcessBatch(ctx context.Context, input BatchInput) (*BatchRes
 		ServerTime: time.Now().UTC(),
 	}
 
-	localTodoIDs := make(map[string]string)
+	localIDs := make(map[localIDKey]string)
+	clock := newClientClock(input.ClientTime, response.ServerTime)
 
 	for _, operation := range input.Operations {
-		result, mapping := s.processOperation(ctx, input, operation, localTodoIDs)
+		result, mapping := s.processOperation(ctx, input, operation, tx, clock, localIDs)
 		response.Results = append(response.Results, result)
 		if mapping != nil {
 			response.Mappings = append(response.Mappings, *mapping)
-			if mapping.Entity == EntityTodoItem {
-				localTodoIDs[mapping.LocalID] = mapping.ServerID
-			}
+			localIDs[localIDKey{entity: mapping.Entity, localID: mapping.LocalID}] = mapping.ServerID
 		}
 
 		switch result.Status {
@@ -122,31 +201,52 @@ func (s *Service) ProcessBatch(ctx context.Context, input BatchInput) (*BatchRes
 		default:
 			response.Summary.Failed++
 		}
+		if tx != nil && result.Status == ResultStatusFailed {
+			break
+		}
 	}
 
 	response.Status = deriveBatchStatus(response.Summary)
+	return response
+}
 
-	if batchCreated {
-		if encoded, err := json.Marshal(response); err == nil {
-			_ = s.repo.CompleteBatch(ctx, syncID, BatchStateCompleted, encoded)
-		}
+// checkOperationsQuota rejects a whole batch that would take the family over
+// its hourly quota, before anything is recorded, so the client can resend
+// it unchanged later. Replays of completed batches count as well.
+func (s *Service) checkOperationsQuota(ctx context.Context, familyID string, adding int) error {
+	if s.operationsPerHour <= 0 {
+		return nil
 	}
-
-	return &response, nil
+	used, err := s.repo.CountOperationsSince(ctx, familyID, time.Now().UTC().Add(-quotadomain.SyncOperationsWindow))
+	if err != nil {
+		return err
+	}
+	return quotadomain.Check(quotadomain.SyncOperationsPerHour, s.operationsPerHour, quotadomain.SyncOperationsWindow, used, adding)
 }
 
-func (s *Service) processOperation(ctx context.Context, input BatchInput, operation OperationInput, localTodoIDs map[string]string) (OperationResult, *EntityMapping) {
+func (s *Service) processOperation(ctx context.Context, input BatchInput, operation OperationInput, tx *batchTx, clock clientClock, localIDs map[localIDKey]string) (OperationResult, *EntityMapping) {
+	repo := s.repo
+	if tx != nil {
+		repo = tx.sync
+	}
+
 	base := OperationResult{
 		OperationID: operation.OperationID,
 		Type:        operation.Type,
 	}
 
+	// Checked before the operation is recorded, so that resending it once
+	// maintenance ends applies it.
+	if s.inMaintenance(ctx, input.FamilyID, operation.Type) {
+		return maintenanceResult(base), nil
+	}
+
 	payloadHash, err := hashOperation(operation)
 	if err != nil {
 		return failResult(base, ErrorCodeInternalError, "internal error", true), nil
 	}
 
-	recordID, err := newUUID()
+	recordID, err := id.New()
 	if err != nil {
 		return failResult(base, ErrorCodeInternalError, "internal error", true), nil
 	}
@@ -165,7 +265,7 @@ func (s *Service) processOperation(ctx context.Context, input BatchInput, operat
 		reserved.LocalID = &localID
 	}
 
-	created, existing, err := s.repo.ReserveOperation(ctx, reserved)
+	created, existing, err := repo.ReserveOperation(ctx, reserved)
 	if err != nil {
 		return failResult(base, ErrorCodeInternalError, "internal error", true), nil
 	}
@@ -175,6 +275,7 @@ func (s *Service) processOperation(ctx context.Context, input BatchInput, operat
 
 	result := base
 	var mapping *EntityMapping
+	occurredAt := clock.correct(operation.OccurredAt)
 
 	switch operation.Type {
 	case OperationTypeCreateExpense:
@@ -183,34 +284,30 @@ func (s *Service) processOperation(ctx context.Context, input BatchInput, operat
 			break
 		}
 
-		createdExpense, err := s.expenses.CreateExpense(ctx, expensesdomain.CreateExpenseInput{
-			FamilyID:     input.FamilyID,
-			UserID:       input.User.ID,
-			Date:         operation.CreateExpense.Date,
-			Amount:       operation.CreateExpense.Amount,
-			Currency:     operation.CreateExpense.Currency,
-			BaseCurrency: input.BaseCurrency,
-			Title:        operation.CreateExpense.Title,
-			CategoryIDs:  operation.CreateExpense.CategoryIDs,
-		})
+		categoryIDs, err := resolveCategoryIDs(ctx, repo, input, operation.CreateExpense, localIDs)
 		if err != nil {
-			if errors.Is(err, expensesdomain.ErrCategoryNotFound) {
-				result = failResult(result, ErrorCodeCategoryNotFound, "category not found", false)
-				break
-			}
-			if errors.Is(err, expensesdomain.ErrRateNotAvailable) {
-				result = failResult(result, ErrorCodeInvalidRequest, "rate is not available for selected date", false)
-				break
-			}
-			result = failResult(result, ErrorCodeInternalError, "internal error", true)
+			result = failResult(result, ErrorCodeDependencyNotResolved, "category id dependency is not resolved", false)
 			break
 		}
 
+		createdExpense, err := s.createExpense(ctx, tx, createExpenseInput(input, operation.CreateExpense, categoryIDs, occurredAt))
+		entity, serverID := EntityExpense, ""
+		var approvalErr *expensesdomain.ApprovalRequiredError
+		if errors.As(err, &approvalErr) {
+			// Queued for the owner's approval, which is stored with the
+			// batch; retrying would only queue it again.
+			entity, serverID = EntityExpenseApproval, approvalErr.Approval.ID
+		} else if err != nil {
+			result = createExpenseFailure(result, err)
+			break
+		} else {
+			serverID = createdExpense.ID
+		}
+
 		result.Status = ResultStatusApplied
 		result.LocalID = nonEmptyStringPtr(operation.LocalID)
-		entity := EntityExpense
 		result.Entity = &entity
-		result.ServerID = nonEmptyStringPtr(createdExpense.ID)
+		result.ServerID = nonEmptyStringPtr(serverID)
 
 		if result.LocalID != nil && result.ServerID != nil {
 			mapping = &EntityMapping{
@@ -226,16 +323,9 @@ func (s *Service) processOperation(ctx context.Context, input BatchInput, operat
 			break
 		}
 
-		createdTodo, err := s.todos.CreateTodoItem(ctx, input.FamilyID, todosdomain.CreateTodoItemInput{
-			ListID: operation.CreateTodo.ListID,
-			Title:  operation.CreateTodo.Title,
-		})
+		createdTodo, err := s.createTodoItem(ctx, tx, input.FamilyID, createTodoInput(input, operation.CreateTodo, occurredAt))
 		if err != nil {
-			if errors.Is(err, todosdomain.ErrTodoListNotFound) {
-				result = failResult(result, ErrorCodeTodoListNotFound, "todo list not found", false)
-				break
-			}
-			result = failResult(result, ErrorCodeInternalError, "internal error", true)
+			result = createTodoFailure(result, err)
 			break
 		}
 
@@ -253,41 +343,47 @@ func (s *Service) processOperation(ctx context.Context, input BatchInput, operat
 			}
 		}
 
+	case OperationTypeCreateCategory:
+		if operation.CreateCategory == nil {
+			result = failResult(result, ErrorCodeInvalidRequest, "payload is required", false)
+			break
+		}
+
+		createdCategory, err := s.createCategory(ctx, tx, createCategoryInput(input, operation.CreateCategory))
+		if err != nil {
+			result = createCategoryFailure(result, err)
+			break
+		}
+
+		result.Status = ResultStatusApplied
+		result.LocalID = nonEmptyStringPtr(operation.LocalID)
+		entity := EntityCategory
+		result.Entity = &entity
+		result.ServerID = nonEmptyStringPtr(createdCategory.ID)
+
+		if result.LocalID != nil && result.ServerID != nil {
+			mapping = &EntityMapping{
+				Entity:   entity,
+				LocalID:  *result.LocalID,
+				ServerID: *result.ServerID,
+			}
+		}
+
 	case OperationTypeSetTodoCompleted:
 		if operation.SetTodoCompleted == nil {
 			result = failResult(result, ErrorCodeInvalidRequest, "payload is required", false)
 			break
 		}
 
-		targetTodoID, resolveErr := s.resolveTodoID(ctx, input.FamilyID, input.User.ID, operation, localTodoIDs)
+		targetTodoID, resolveErr := resolveTodoID(ctx, repo, input.FamilyID, input.User.ID, operation, localIDs)
 		if resolveErr != nil {
 			result = failResult(result, ErrorCodeDependencyNotResolved, "todo id dependency is not resolved", false)
 			break
 		}
 
-		var completedBy *todosdomain.UserSnapshot
-		if operation.SetTodoCompleted.IsCompleted {
-			completedBy = &todosdomain.UserSnapshot{
-				ID:        input.User.ID,
-				Name:      input.User.Name,
-				Email:     input.User.Email,
-				AvatarURL: input.User.AvatarURL,
-			}
-		}
-
-		isCompleted := operation.SetTodoCompleted.IsCompleted
-		_, err := s.todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{
-			ID:          targetTodoID,
-			FamilyID:    input.FamilyID,
-			IsCompleted: &isCompleted,
-			CompletedBy: completedBy,
-		})
+		_, err := s.updateTodoItem(ctx, tx, setTodoCompletedInput(input, targetTodoID, operation.SetTodoCompleted, occurredAt))
 		if err != nil {
-			if errors.Is(err, todosdomain.ErrTodoItemNotFound) {
-				result = failResult(result, ErrorCodeTodoItemNotFound, "todo item not found", false)
-				break
-			}
-			result = failResult(result, ErrorCodeInternalError, "internal error", true)
+			result = setTodoCompletedFailure(result, err)
 			break
 		}
 
@@ -297,6 +393,12 @@ func (s *Service) processOperation(ctx context.Context, input BatchInput, operat
 		result = failResult(result, ErrorCodeUnsupportedOperationType, "unsupported operation type", false)
 	}
 
+	// A failure rolls back an atomic batch, and the failed statement may
+	// have aborted its transaction already.
+	if tx != nil && result.Status != ResultStatusApplied {
+		return result, mapping
+	}
+
 	updateRecord := *reserved
 	if result.Status == ResultStatusApplied {
 		updateRecord.Status = OperationStateApplied
@@ -321,14 +423,120 @@ func (s *Service) processOperation(ctx context.Context, input BatchInput, operat
 		updateRecord.LocalID = result.LocalID
 	}
 
-	if err := s.repo.UpdateOperation(ctx, &updateRecord); err != nil {
+	if err := repo.UpdateOperation(ctx, &updateRecord); err != nil {
 		return failResult(base, ErrorCodeInternalError, "internal error", true), nil
 	}
 
 	return result, mapping
 }
 
-func (s *Service) resolveTodoID(ctx context.Context, familyID, userID string, operation OperationInput, localTodoIDs map[string]string) (string, error) {
+func createExpenseInput(input BatchInput, payload *CreateExpensePayload, categoryIDs []string, occurredAt *time.Time) expensesdomain.CreateExpenseInput {
+	return expensesdomain.CreateExpenseInput{
+		CreatedAt:    occurredAt,
+		FamilyID:     input.FamilyID,
+		UserID:       input.User.ID,
+		Date:         payload.Date,
+		Amount:       payload.Amount,
+		Currency:     payload.Currency,
+		BaseCurrency: input.BaseCurrency,
+		Title:        payload.Title,
+		CategoryIDs:  categoryIDs,
+
+		AllowedCurrencies: input.AllowedCurrencies,
+	}
+}
+
+func createCategoryInput(input BatchInput, payload *CreateCategoryPayload) expensesdomain.CreateCategoryInput {
+	return expensesdomain.CreateCategoryInput{
+		FamilyID: input.FamilyID,
+		Name:     payload.Name,
+		Color:    payload.Color,
+		Emoji:    payload.Emoji,
+	}
+}
+
+func createTodoInput(input BatchInput, payload *CreateTodoPayload, occurredAt *time.Time) todosdomain.CreateTodoItemInput {
+	return todosdomain.CreateTodoItemInput{
+		ListID:    payload.ListID,
+		Title:     payload.Title,
+		ViewerID:  input.User.ID,
+		CreatedAt: occurredAt,
+	}
+}
+
+func setTodoCompletedInput(input BatchInput, todoID string, payload *SetTodoCompletedPayload, occurredAt *time.Time) todosdomain.UpdateTodoItemInput {
+	var completedBy *todosdomain.UserSnapshot
+	if payload.IsCompleted {
+		completedBy = &todosdomain.UserSnapshot{
+			ID:        input.User.ID,
+			Name:      input.User.Name,
+			Email:     input.User.Email,
+			AvatarURL: input.User.AvatarURL,
+		}
+	}
+
+	isCompleted := payload.IsCompleted
+	return todosdomain.UpdateTodoItemInput{
+		ID:          todoID,
+		FamilyID:    input.FamilyID,
+		IsCompleted: &isCompleted,
+		CompletedBy: completedBy,
+		CompletedAt: occurredAt,
+		ViewerID:    input.User.ID,
+	}
+}
+
+func createExpenseFailure(result OperationResult, err error) OperationResult {
+	switch {
+	case errors.Is(err, expensesdomain.ErrCategoryNotFound):
+		return failResult(result, ErrorCodeCategoryNotFound, "category not found", false)
+	case errors.Is(err, expensesdomain.ErrRateNotAvailable):
+		return failResult(result, ErrorCodeInvalidRequest, "rate is not available for selected date", false)
+	case errors.Is(err, money.ErrInvalidPrecision), errors.Is(err, expensesdomain.ErrInvalidCurrency):
+		return failResult(result, ErrorCodeInvalidRequest, err.Error(), false)
+	case errors.Is(err, quotadomain.ErrExceeded):
+		return failResult(result, ErrorCodeQuotaExceeded, err.Error(), true)
+	default:
+		return failResult(result, ErrorCodeInternalError, "internal error", true)
+	}
+}
+
+func createCategoryFailure(result OperationResult, err error) OperationResult {
+	switch {
+	case errors.Is(err, expensesdomain.ErrInvalidCategoryColor):
+		return failResult(result, ErrorCodeInvalidRequest, "invalid category color", false)
+	case errors.Is(err, expensesdomain.ErrInvalidCategoryEmoji):
+		return failResult(result, ErrorCodeInvalidRequest, "invalid category emoji", false)
+	default:
+		return failResult(result, ErrorCodeInternalError, "internal error", true)
+	}
+}
+
+func createTodoFailure(result OperationResult, err error) OperationResult {
+	switch {
+	case errors.Is(err, todosdomain.ErrTodoListNotFound):
+		return failResult(result, ErrorCodeTodoListNotFound, "todo list not found", false)
+	case errors.Is(err, quotadomain.ErrExceeded):
+		return failResult(result, ErrorCodeQuotaExceeded, err.Error(), false)
+	default:
+		return failResult(result, ErrorCodeInternalError, "internal error", true)
+	}
+}
+
+func setTodoCompletedFailure(result OperationResult, err error) OperationResult {
+	if errors.Is(err, todosdomain.ErrTodoItemNotFound) {
+		return failResult(result, ErrorCodeTodoItemNotFound, "todo item not found", false)
+	}
+	return failResult(result, ErrorCodeInternalError, "internal error", true)
+}
+
+// localIDKey names an entity the client refers to by its local ID.
+type localIDKey struct {
+	entity  Entity
+	localID string
+}
+
+func resolveTodoID(ctx context.Context, repo Repository, familyID, userID string, operation OperationInput, localIDs map[localIDKey]string) (string, error) {
 	if operation.SetTodoCompleted == nil {
 		return "", fmt.Errorf("set_todo_completed payload is required")
 	}
@@ -342,19 +550,47 @@ func (s *Service) resolveTodoID(ctx context.Context, familyID, userID string, op
 		return "", fmt.Errorf("todo id is required")
 	}
 
-	if todoID := strings.TrimSpace(localTodoIDs[localID]); todoID != "" {
-		return todoID, nil
+	return resolveLocalID(ctx, repo, familyID, userID, EntityTodoItem, localID, localIDs)
+}
+
+// resolveCategoryIDs returns the expense's category IDs followed by those
+// its category local IDs resolve to.
+func resolveCategoryIDs(ctx context.Context, repo Repository, input BatchInput, payload *CreateExpensePayload, localIDs map[localIDKey]string) ([]string, error) {
+	if len(payload.CategoryLocalIDs) == 0 {
+		return payload.CategoryIDs, nil
+	}
+
+	categoryIDs := append(make([]string, 0, len(payload.CategoryIDs)+len(payload.CategoryLocalIDs)), payload.CategoryIDs...)
+	for _, localID := range payload.CategoryLocalIDs {
+		localID = strings.TrimSpace(localID)
+		if localID == "" {
+			continue
+		}
+		categoryID, err := resolveLocalID(ctx, repo, input.FamilyID, input.User.ID, EntityCategory, localID, localIDs)
+		if err != nil {
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	return categoryIDs, nil
+}
+
+// resolveLocalID finds the server ID of an entity created earlier in the
+// batch or, failing that, by an operation the user synced before.
+func resolveLocalID(ctx context.Context, repo Repository, familyID, userID string, entity Entity, localID string, localIDs map[localIDKey]string) (string, error) {
+	if serverID := strings.TrimSpace(localIDs[localIDKey{entity: entity, localID: localID}]); serverID != "" {
+		return serverID, nil
 	}
 
-	todoID, found, err := s.repo.FindServerIDByLocalID(ctx, familyID, userID, EntityTodoItem, localID)
+	serverID, found, err := repo.FindServerIDByLocalID(ctx, familyID, userID, entity, localID)
 	if err != nil {
 		return "", err
 	}
-	if !found || strings.TrimSpace(todoID) == "" {
-		return "", fmt.Errorf("todo id dependency is not resolved")
+	if !found || strings.TrimSpace(serverID) == "" {
+		return "", fmt.Errorf("%s id dependency is not resolved", entity)
 	}
 
-	return todoID, nil
+	return serverID, nil
 }
 
 func resultFromExisting(base OperationResult, operation OperationInput, existing *OperationRecord, payloadHash string) (OperationResult, *EntityMapping) {
@@ -412,6 +648,31 @@ func resultFromExisting(base OperationResult, operation OperationInput, existing
 	return result, nil
 }
 
+// maintenanceFlags names the maintenance flag of the domain each operation
+// type writes.
+var maintenanceFlags = map[OperationType]string{
+	OperationTypeCreateExpense:    featureflagsdomain.MaintenanceExpenses,
+	OperationTypeCreateCategory:   featureflagsdomain.MaintenanceExpenses,
+	OperationTypeCreateTodo:       featureflagsdomain.MaintenanceTodos,
+	OperationTypeSetTodoCompleted: featureflagsdomain.MaintenanceTodos,
+}
+
+// inMaintenance reports whether the domain the operation writes is in
+// maintenance for the family. A flag lookup failure counts as off, as it
+// does for HTTP writes.
+func (s *Service) inMaintenance(ctx context.Context, familyID string, operationType OperationType) bool {
+	flag, ok := maintenanceFlags[operationType]
+	if !ok || s.flags == nil {
+		return false
+	}
+	enabled, err := s.flags.Enabled(ctx, flag, familyID)
+	return err == nil && enabled
+}
+
+func maintenanceResult(base OperationResult) OperationResult {
+	return failResult(base, ErrorCodeMaintenance, "this feature is under maintenance; retry later", true)
+}
+
 func failResult(base OperationResult, code ErrorCode, message string, retryable bool) OperationResult {
 	base.Status = ResultStatusFailed
 	base.Error = &OperationError{
@@ -426,7 +687,7 @@ func deriveBatchStatus(summary BatchSummary) BatchStatus {
 	if summary.Failed == 0 {
 		return BatchStatusSuccess
 	}
-	if summary.Applied > 0 || summary.Duplicate > 0 {
+	if summary.Applied > 0 || summary.Valid > 0 || summary.Duplicate > 0 {
 		return BatchStatusPartialSuccess
 	}
 	return BatchStatusFailed
@@ -453,18 +714,22 @@ func hashOperation(operation OperationInput) (string, error) {
 		payload = operation.CreateTodo
 	case OperationTypeSetTodoCompleted:
 		payload = operation.SetTodoCompleted
+	case OperationTypeCreateCategory:
+		payload = operation.CreateCategory
 	default:
 		payload = map[string]string{"type": string(operation.Type)}
 	}
 
 	value := struct {
-		Type    OperationType `json:"type"`
-		LocalID string        `json:"local_id,omitempty"`
-		Payload interface{}   `json:"payload"`
+		Type       OperationType `json:"type"`
+		LocalID    string        `json:"local_id,omitempty"`
+		OccurredAt *time.Time    `json:"occurred_at,omitempty"`
+		Payload    interface{}   `json:"payload"`
 	}{
-		Type:    operation.Type,
-		LocalID: operation.LocalID,
-		Payload: payload,
+		Type:       operation.Type,
+		LocalID:    operation.LocalID,
+		OccurredAt: operation.OccurredAt,
+		Payload:    payload,
 	}
 
 	return hashValue(value)
@@ -480,18 +745,6 @@ func hashValue(value interface{}) (string, error) {
 	return hex.EncodeToString(sum[:]), nil
 }
 
-func newUUID() (string, error) {
-	var b [16]byte
-	if _, err := rand.Read(b[:]); err != nil {
-		return "", err
-	}
-
-	b[6] = (b[6] & 0x0f) | 0x40
-	b[8] = (b[8] & 0x3f) | 0x80
-
-	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
-}
-
 func cloneString(value *string) *string {
 	if value == nil {
 		return nil
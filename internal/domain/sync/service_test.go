@@ -2,12 +2,16 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	stdsync "sync"
 	"testing"
 	"time"
 
 	expensesdomain "family-app-go/internal/domain/expenses"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	quotadomain "family-app-go/internal/domain/quota"
 	todosdomain "family-app-go/internal/domain/todos"
 )
 
@@ -261,6 +265,645 @@ func TestProcessBatchCreateExpenseRateNotAvailable(t *testing.T) {
 	}
 }
 
+func TestProcessBatchOperationsQuotaRejectsWholeBatch(t *testing.T) {
+	repo := newFakeSyncRepo()
+	todosSvc := newFakeTodosService()
+	svc := NewServiceWithOptions(repo, newFakeExpensesService(), todosSvc, ServiceOptions{OperationsPerHour: 2})
+
+	todo := func(operationID string) OperationInput {
+		return OperationInput{
+			OperationID: operationID,
+			Type:        OperationTypeCreateTodo,
+			CreateTodo:  &CreateTodoPayload{ListID: "list-1", Title: "Buy milk"},
+		}
+	}
+	first := BatchInput{
+		FamilyID:   "fam-1",
+		User:       UserSnapshot{ID: "user-1", Name: "Test"},
+		Operations: []OperationInput{todo("88888888-8888-4888-8888-888888888881")},
+	}
+	if _, err := svc.ProcessBatch(context.Background(), first); err != nil {
+		t.Fatalf("process first batch: %v", err)
+	}
+
+	second := first
+	second.IdempotencyKey = "quota-batch-key-1"
+	second.Operations = []OperationInput{
+		todo("88888888-8888-4888-8888-888888888882"),
+		todo("88888888-8888-4888-8888-888888888883"),
+	}
+	_, err := svc.ProcessBatch(context.Background(), second)
+	var exceeded *quotadomain.ExceededError
+	if !errors.As(err, &exceeded) || exceeded.Quota != quotadomain.SyncOperationsPerHour {
+		t.Fatalf("expected sync operations quota error, got %v", err)
+	}
+	if todosSvc.createCalls != 1 || len(repo.batchesByKey) != 0 {
+		t.Fatalf("expected nothing recorded for the rejected batch, got %d creates and %d batches", todosSvc.createCalls, len(repo.batchesByKey))
+	}
+}
+
+func TestProcessBatchMapsCreateExpenseQuota(t *testing.T) {
+	repo := newFakeSyncRepo()
+	expensesSvc := newFakeExpensesService()
+	expensesSvc.createErr = &quotadomain.ExceededError{Quota: quotadomain.ExpensesPerDay, Limit: 10, Window: quotadomain.ExpensesWindow}
+	svc := NewService(repo, expensesSvc, newFakeTodosService())
+
+	response, err := svc.ProcessBatch(context.Background(), BatchInput{
+		FamilyID:     "fam-1",
+		BaseCurrency: "USD",
+		User:         UserSnapshot{ID: "user-1", Name: "Test"},
+		Operations: []OperationInput{{
+			OperationID: "99999999-9999-4999-8999-999999999999",
+			Type:        OperationTypeCreateExpense,
+			CreateExpense: &CreateExpensePayload{
+				Date:     time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+				Amount:   10,
+				Currency: "USD",
+				Title:    "Coffee",
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	result := response.Results[0]
+	if result.Error == nil || result.Error.Code != ErrorCodeQuotaExceeded || !result.Error.Retryable {
+		t.Fatalf("expected retryable quota_exceeded error, got %+v", result.Error)
+	}
+}
+
+func TestProcessBatchQueuesExpenseAboveSpendingLimit(t *testing.T) {
+	for _, atomic := range []bool{false, true} {
+		t.Run(fmt.Sprintf("atomic=%t", atomic), func(t *testing.T) {
+			expensesSvc := newFakeExpensesService()
+			limit := 50.0
+			expensesSvc.spendingLimit = &limit
+			svc := NewService(newFakeSyncRepo(), expensesSvc, newFakeTodosService())
+			expense := func(operationID, localID string, amount float64) OperationInput {
+				return OperationInput{
+					OperationID: operationID,
+					Type:        OperationTypeCreateExpense,
+					LocalID:     localID,
+					CreateExpense: &CreateExpensePayload{
+						Date:     time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+						Amount:   amount,
+						Currency: "USD",
+						Title:    "Coffee",
+					},
+				}
+			}
+
+			response, err := svc.ProcessBatch(context.Background(), BatchInput{
+				FamilyID:     "fam-1",
+				BaseCurrency: "USD",
+				User:         UserSnapshot{ID: "user-1", Name: "Test"},
+				Atomic:       atomic,
+				Operations: []OperationInput{
+					expense("dddddddd-dddd-4ddd-8ddd-ddddddddddd1", "expense-local-1", 10),
+					expense("dddddddd-dddd-4ddd-8ddd-ddddddddddd2", "expense-local-2", 80),
+				},
+			})
+			if err != nil {
+				t.Fatalf("process failed: %v", err)
+			}
+			if response.Status != BatchStatusSuccess || response.Summary.Applied != 2 {
+				t.Fatalf("expected both operations applied, got %+v", response)
+			}
+			within, above := response.Results[0], response.Results[1]
+			if within.Entity == nil || *within.Entity != EntityExpense || within.ServerID == nil || *within.ServerID != "expense-1" {
+				t.Fatalf("expected the expense within the limit created, got %+v", within)
+			}
+			if above.Entity == nil || *above.Entity != EntityExpenseApproval || above.ServerID == nil || *above.ServerID != "approval-2" {
+				t.Fatalf("expected the expense above the limit queued for approval, got %+v", above)
+			}
+			if len(response.Mappings) != 2 || response.Mappings[1] != (EntityMapping{Entity: EntityExpenseApproval, LocalID: "expense-local-2", ServerID: "approval-2"}) {
+				t.Fatalf("unexpected mappings %+v", response.Mappings)
+			}
+		})
+	}
+}
+
+func TestProcessBatchFailsOperationsInMaintenance(t *testing.T) {
+	flags := fakeFlags{featureflagsdomain.MaintenanceTodos: true}
+	svc := NewServiceWithOptions(newFakeSyncRepo(), newFakeExpensesService(), newFakeTodosService(), ServiceOptions{Flags: flags})
+	todo := OperationInput{
+		OperationID: "eeeeeeee-eeee-4eee-8eee-eeeeeeeeeee1",
+		Type:        OperationTypeCreateTodo,
+		LocalID:     "todo-local-1",
+		CreateTodo:  &CreateTodoPayload{ListID: "list-1", Title: "Buy apples"},
+	}
+	input := BatchInput{
+		FamilyID:     "fam-1",
+		BaseCurrency: "USD",
+		User:         UserSnapshot{ID: "user-1", Name: "Test"},
+		Operations: []OperationInput{
+			todo,
+			{
+				OperationID: "eeeeeeee-eeee-4eee-8eee-eeeeeeeeeee2",
+				Type:        OperationTypeCreateExpense,
+				CreateExpense: &CreateExpensePayload{
+					Date:     time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+					Amount:   10,
+					Currency: "USD",
+					Title:    "Coffee",
+				},
+			},
+		},
+	}
+
+	response, err := svc.ProcessBatch(context.Background(), input)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	failed := response.Results[0]
+	if failed.Status != ResultStatusFailed || failed.Error == nil || failed.Error.Code != ErrorCodeMaintenance || !failed.Error.Retryable {
+		t.Fatalf("expected a retryable maintenance failure for the todo, got %+v", failed)
+	}
+	if response.Results[1].Status != ResultStatusApplied {
+		t.Fatalf("expected the expense applied, got %+v", response.Results[1])
+	}
+
+	// The failure is not recorded, so resending the operation once
+	// maintenance ends applies it.
+	delete(flags, featureflagsdomain.MaintenanceTodos)
+	input.Operations = []OperationInput{todo}
+	response, err = svc.ProcessBatch(context.Background(), input)
+	if err != nil {
+		t.Fatalf("process failed: %v", err)
+	}
+	if response.Results[0].Status != ResultStatusApplied {
+		t.Fatalf("expected the resent todo applied, got %+v", response.Results[0])
+	}
+}
+
+func TestProcessBatchCorrectsClientClockSkew(t *testing.T) {
+	expensesSvc := newFakeExpensesService()
+	svc := NewService(newFakeSyncRepo(), expensesSvc, newFakeTodosService())
+
+	// The client's clock runs two hours behind the server's.
+	clientTime := time.Now().UTC().Add(-2 * time.Hour)
+	past := clientTime.Add(-30 * time.Minute)
+	future := clientTime.Add(3 * time.Hour)
+	expense := func(operationID string, occurredAt time.Time) OperationInput {
+		return OperationInput{
+			OperationID: operationID,
+			Type:        OperationTypeCreateExpense,
+			LocalID:     "expense-" + operationID,
+			OccurredAt:  &occurredAt,
+			CreateExpense: &CreateExpensePayload{
+				Date:     time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+				Amount:   10,
+				Currency: "USD",
+				Title:    "Coffee",
+			},
+		}
+	}
+
+	response, err := svc.ProcessBatch(context.Background(), BatchInput{
+		FamilyID:     "fam-1",
+		BaseCurrency: "USD",
+		User:         UserSnapshot{ID: "user-1", Name: "Test"},
+		ClientTime:   &clientTime,
+		Operations: []OperationInput{
+			expense("aaaaaaaa-aaaa-4aaa-8aaa-aaaaaaaaaaa1", past),
+			expense("aaaaaaaa-aaaa-4aaa-8aaa-aaaaaaaaaaa2", future),
+		},
+	})
+	if err != nil {
+		t.Fatalf("process batch: %v", err)
+	}
+	if len(expensesSvc.created) != 2 {
+		t.Fatalf("expected two expenses, got %d", len(expensesSvc.created))
+	}
+
+	want := response.ServerTime.Add(-30 * time.Minute)
+	if got := expensesSvc.created[0].CreatedAt; got == nil || got.Sub(want).Abs() > time.Second {
+		t.Fatalf("expected created at about %s, got %v", want, got)
+	}
+	if got := expensesSvc.created[1].CreatedAt; got == nil || !got.Equal(response.ServerTime) {
+		t.Fatalf("expected a future time capped at %s, got %v", response.ServerTime, got)
+	}
+}
+
+func TestProcessBatchResolvesCategoryLocalIDs(t *testing.T) {
+	expensesSvc := newFakeExpensesService()
+	svc := NewService(newFakeSyncRepo(), expensesSvc, newFakeTodosService())
+
+	response, err := svc.ProcessBatch(context.Background(), BatchInput{
+		FamilyID:     "fam-1",
+		BaseCurrency: "USD",
+		User:         UserSnapshot{ID: "user-1", Name: "Test"},
+		Operations: []OperationInput{
+			{
+				OperationID:    "aaaaaaaa-aaaa-4aaa-8aaa-aaaaaaaaaaa1",
+				Type:           OperationTypeCreateCategory,
+				LocalID:        "category-local-1",
+				CreateCategory: &CreateCategoryPayload{Name: "Groceries"},
+			},
+			{
+				OperationID: "aaaaaaaa-aaaa-4aaa-8aaa-aaaaaaaaaaa2",
+				Type:        OperationTypeCreateExpense,
+				LocalID:     "expense-local-1",
+				CreateExpense: &CreateExpensePayload{
+					Date:             time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+					Amount:           10,
+					Currency:         "USD",
+					Title:            "Milk",
+					CategoryIDs:      []string{"category-existing"},
+					CategoryLocalIDs: []string{"category-local-1"},
+				},
+			},
+			{
+				OperationID: "aaaaaaaa-aaaa-4aaa-8aaa-aaaaaaaaaaa3",
+				Type:        OperationTypeCreateExpense,
+				CreateExpense: &CreateExpensePayload{
+					Date:             time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC),
+					Amount:           5,
+					Currency:         "USD",
+					Title:            "Bread",
+					CategoryLocalIDs: []string{"category-local-unknown"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("process batch: %v", err)
+	}
+
+	if response.Results[0].Status != ResultStatusApplied || response.Results[1].Status != ResultStatusApplied {
+		t.Fatalf("expected category and expense applied, got %#v", response.Results)
+	}
+	if response.Results[2].Status != ResultStatusFailed || response.Results[2].Error.Code != ErrorCodeDependencyNotResolved {
+		t.Fatalf("expected unresolved category to fail, got %#v", response.Results[2])
+	}
+	if len(expensesSvc.created) != 1 {
+		t.Fatalf("expected one expense, got %d", len(expensesSvc.created))
+	}
+	if got := expensesSvc.created[0].CategoryIDs; len(got) != 2 || got[0] != "category-existing" || got[1] != "category-1" {
+		t.Fatalf("expected categories [category-existing category-1], got %v", got)
+	}
+}
+
+func TestResolveMappingsReportsMissingLocalIDs(t *testing.T) {
+	svc := NewService(newFakeSyncRepo(), newFakeExpensesService(), newFakeTodosService())
+	ctx := context.Background()
+
+	response, err := svc.ProcessBatch(ctx, BatchInput{
+		FamilyID: "fam-1",
+		User:     UserSnapshot{ID: "user-1", Name: "Test"},
+		Operations: []OperationInput{
+			{
+				OperationID: "bbbbbbbb-bbbb-4bbb-8bbb-bbbbbbbbbbb1",
+				Type:        OperationTypeCreateTodo,
+				LocalID:     "todo-local-1",
+				CreateTodo:  &CreateTodoPayload{ListID: "list-1", Title: "Buy milk"},
+			},
+			{
+				OperationID: "bbbbbbbb-bbbb-4bbb-8bbb-bbbbbbbbbbb2",
+				Type:        OperationTypeCreateTodo,
+				LocalID:     "todo-local-failed",
+				CreateTodo:  &CreateTodoPayload{ListID: "list-missing", Title: "Lost"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("process batch: %v", err)
+	}
+
+	result, err := svc.ResolveMappings(ctx, "fam-1", "user-1", EntityTodoItem, []string{"todo-local-failed", "todo-local-1", "todo-local-unknown"})
+	if err != nil {
+		t.Fatalf("resolve mappings: %v", err)
+	}
+	if len(result.Mappings) != 1 || result.Mappings[0] != response.Mappings[0] {
+		t.Fatalf("expected the mapping from the batch, got %+v", result.Mappings)
+	}
+	if len(result.Missing) != 2 || result.Missing[0] != "todo-local-failed" || result.Missing[1] != "todo-local-unknown" {
+		t.Fatalf("unexpected missing local ids: %v", result.Missing)
+	}
+
+	other, err := svc.ResolveMappings(ctx, "fam-1", "user-2", EntityTodoItem, []string{"todo-local-1"})
+	if err != nil {
+		t.Fatalf("resolve mappings for another user: %v", err)
+	}
+	if len(other.Mappings) != 0 {
+		t.Fatalf("expected no mappings for another user, got %+v", other.Mappings)
+	}
+	if _, err := svc.ResolveMappings(ctx, "fam-1", "user-1", Entity("pet"), []string{"todo-local-1"}); !errors.Is(err, ErrUnknownEntity) {
+		t.Fatalf("expected ErrUnknownEntity, got %v", err)
+	}
+}
+
+func TestProcessBatchAtomicAbortsOnFailure(t *testing.T) {
+	repo := newFakeSyncRepo()
+	svc := NewService(repo, newFakeExpensesService(), newFakeTodosService())
+
+	input := BatchInput{
+		FamilyID:       "fam-1",
+		User:           UserSnapshot{ID: "user-1", Name: "Test"},
+		IdempotencyKey: "atomic-batch-key-1",
+		Atomic:         true,
+		Operations: []OperationInput{
+			{
+				OperationID: "cccccccc-cccc-4ccc-8ccc-ccccccccccc1",
+				Type:        OperationTypeCreateTodo,
+				LocalID:     "todo-local-1",
+				CreateTodo:  &CreateTodoPayload{ListID: "list-1", Title: "Buy milk"},
+			},
+			{
+				OperationID: "cccccccc-cccc-4ccc-8ccc-ccccccccccc2",
+				Type:        OperationTypeCreateTodo,
+				LocalID:     "todo-local-2",
+				CreateTodo:  &CreateTodoPayload{ListID: "list-missing", Title: "Lost"},
+			},
+			{
+				OperationID:      "cccccccc-cccc-4ccc-8ccc-ccccccccccc3",
+				Type:             OperationTypeSetTodoCompleted,
+				SetTodoCompleted: &SetTodoCompletedPayload{TodoLocalID: "todo-local-1", IsCompleted: true},
+			},
+		},
+	}
+
+	response, err := svc.ProcessBatch(context.Background(), input)
+	if err != nil {
+		t.Fatalf("process batch: %v", err)
+	}
+	wantCodes := []ErrorCode{ErrorCodeBatchAborted, ErrorCodeTodoListNotFound, ErrorCodeBatchAborted}
+	for i, result := range response.Results {
+		if result.Status != ResultStatusFailed || result.Error == nil || result.Error.Code != wantCodes[i] {
+			t.Fatalf("result %d: expected %s, got %+v", i, wantCodes[i], result)
+		}
+	}
+	if response.Status != BatchStatusFailed || response.Summary.Failed != 3 || len(response.Mappings) != 0 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+	if len(repo.operationsByID) != 0 || len(repo.batchesByKey) != 0 {
+		t.Fatalf("expected nothing recorded, got %d operations and %d batches", len(repo.operationsByID), len(repo.batchesByKey))
+	}
+
+	input.Operations[1].CreateTodo.ListID = "list-1"
+	retried, err := svc.ProcessBatch(context.Background(), input)
+	if err != nil {
+		t.Fatalf("retry batch: %v", err)
+	}
+	if retried.Status != BatchStatusSuccess || retried.Summary.Applied != 3 {
+		t.Fatalf("expected the fixed batch applied with the same key, got %+v", retried)
+	}
+}
+
+func TestValidateBatchAppliesNothing(t *testing.T) {
+	repo := newFakeSyncRepo()
+	todosSvc := newFakeTodosService()
+	svc := NewService(repo, newFakeExpensesService(), todosSvc)
+	ctx := context.Background()
+
+	applied := OperationInput{
+		OperationID: "99999999-9999-4999-8999-999999999991",
+		Type:        OperationTypeCreateTodo,
+		LocalID:     "todo-local-applied",
+		CreateTodo:  &CreateTodoPayload{ListID: "list-1", Title: "Buy milk"},
+	}
+	input := BatchInput{
+		FamilyID:   "fam-1",
+		User:       UserSnapshot{ID: "user-1", Name: "Test"},
+		Operations: []OperationInput{applied},
+	}
+	if _, err := svc.ProcessBatch(ctx, input); err != nil {
+		t.Fatalf("process batch: %v", err)
+	}
+
+	input.Operations = []OperationInput{
+		applied,
+		{
+			OperationID: "99999999-9999-4999-8999-999999999992",
+			Type:        OperationTypeCreateTodo,
+			LocalID:     "todo-local-new",
+			CreateTodo:  &CreateTodoPayload{ListID: "list-1", Title: "Buy bread"},
+		},
+		{
+			OperationID:      "99999999-9999-4999-8999-999999999993",
+			Type:             OperationTypeSetTodoCompleted,
+			SetTodoCompleted: &SetTodoCompletedPayload{TodoLocalID: "todo-local-new", IsCompleted: true},
+		},
+		{
+			OperationID:      "99999999-9999-4999-8999-999999999994",
+			Type:             OperationTypeSetTodoCompleted,
+			SetTodoCompleted: &SetTodoCompletedPayload{TodoLocalID: "todo-local-applied", IsCompleted: true},
+		},
+		{
+			OperationID:      "99999999-9999-4999-8999-999999999995",
+			Type:             OperationTypeSetTodoCompleted,
+			SetTodoCompleted: &SetTodoCompletedPayload{TodoLocalID: "todo-local-unknown", IsCompleted: true},
+		},
+		{
+			OperationID: "99999999-9999-4999-8999-999999999996",
+			Type:        OperationTypeCreateTodo,
+			CreateTodo:  &CreateTodoPayload{ListID: "list-missing", Title: "Lost"},
+		},
+	}
+
+	response, err := svc.ValidateBatch(ctx, input)
+	if err != nil {
+		t.Fatalf("validate batch: %v", err)
+	}
+
+	want := []ResultStatus{ResultStatusDuplicate, ResultStatusValid, ResultStatusValid, ResultStatusValid, ResultStatusFailed, ResultStatusFailed}
+	for i, result := range response.Results {
+		if result.Status != want[i] {
+			t.Fatalf("result %d: expected %s, got %+v", i, want[i], result)
+		}
+	}
+	if code := response.Results[4].Error.Code; code != ErrorCodeDependencyNotResolved {
+		t.Fatalf("expected dependency_not_resolved, got %s", code)
+	}
+	if code := response.Results[5].Error.Code; code != ErrorCodeTodoListNotFound {
+		t.Fatalf("expected todo_list_not_found, got %s", code)
+	}
+	if !response.DryRun || response.SyncID != "" || response.Status != BatchStatusPartialSuccess {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+	if response.Summary != (BatchSummary{Total: 6, Valid: 3, Duplicate: 1, Failed: 2}) {
+		t.Fatalf("unexpected summary: %+v", response.Summary)
+	}
+	if todosSvc.createCalls != 1 || todosSvc.updateCalls != 0 || len(repo.operationsByID) != 1 {
+		t.Fatalf("expected nothing applied or recorded, got %d creates, %d updates and %d operations", todosSvc.createCalls, todosSvc.updateCalls, len(repo.operationsByID))
+	}
+}
+
+func TestPurgeRemovesRecordsOlderThanRetention(t *testing.T) {
+	repo := newFakeSyncRepo()
+	svc := NewServiceWithOptions(repo, newFakeExpensesService(), newFakeTodosService(), ServiceOptions{RetentionDays: 30})
+
+	old := time.Now().UTC().AddDate(0, 0, -31)
+	recent := time.Now().UTC().AddDate(0, 0, -29)
+	for i := 0; i < purgeChunkSize+1; i++ {
+		id := fmt.Sprintf("old-%d", i)
+		repo.operationsByID[id] = OperationRecord{ID: id, FamilyID: "fam-1", OperationID: id, CreatedAt: old}
+	}
+	repo.operationsByID["recent"] = OperationRecord{ID: "recent", FamilyID: "fam-1", OperationID: "recent", CreatedAt: recent}
+	repo.batchesByID["old"] = BatchRecord{ID: "old", CreatedAt: old}
+	repo.batchesByID["recent"] = BatchRecord{ID: "recent", CreatedAt: recent}
+
+	result, err := svc.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.OperationsDeleted != purgeChunkSize+1 || result.BatchesDeleted != 1 {
+		t.Fatalf("unexpected purge result: %+v", result)
+	}
+	if result.Storage.OperationRows != 1 || result.Storage.BatchRows != 1 {
+		t.Fatalf("unexpected storage stats: %+v", result.Storage)
+	}
+	if _, ok := repo.operationsByID["recent"]; !ok {
+		t.Fatalf("expected the recent operation kept")
+	}
+}
+
+func TestEventsPagesInOrderFromCursor(t *testing.T) {
+	repo := newFakeSyncRepo()
+	svc := NewServiceWithOptions(repo, newFakeExpensesService(), newFakeTodosService(), ServiceOptions{RetentionDays: 30})
+	for _, id := range []string{"exp-1", "exp-2", "exp-3"} {
+		repo.appendEvent(id, time.Now().UTC())
+	}
+
+	page, err := svc.Events(context.Background(), "fam-1", "0", 2)
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if len(page.Events) != 2 || page.Events[0].EntityID != "exp-1" || !page.HasMore || page.NextCursor != "2" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	if page.Retention != (EventsRetention{Days: 30, OldestCursor: "0", LatestCursor: "3"}) {
+		t.Fatalf("unexpected retention: %+v", page.Retention)
+	}
+
+	page, err = svc.Events(context.Background(), "fam-1", page.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if len(page.Events) != 1 || page.Events[0].Seq != 3 || page.HasMore || page.NextCursor != "3" {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+
+	page, err = svc.Events(context.Background(), "fam-1", "3", 2)
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if len(page.Events) != 0 || page.NextCursor != "3" {
+		t.Fatalf("unexpected caught-up page: %+v", page)
+	}
+
+	for _, cursor := range []string{"", "abc", "-1", "4"} {
+		if _, err := svc.Events(context.Background(), "fam-1", cursor, 2); !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("cursor %q: expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+}
+
+func TestEventsRequireResyncAfterPurge(t *testing.T) {
+	repo := newFakeSyncRepo()
+	svc := NewServiceWithOptions(repo, newFakeExpensesService(), newFakeTodosService(), ServiceOptions{RetentionDays: 30})
+	repo.appendEvent("exp-1", time.Now().UTC().AddDate(0, 0, -31))
+	repo.appendEvent("exp-2", time.Now().UTC().AddDate(0, 0, -31))
+	repo.appendEvent("exp-3", time.Now().UTC())
+
+	result, err := svc.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if result.EventsDeleted != 2 {
+		t.Fatalf("expected 2 events purged, got %+v", result)
+	}
+
+	if _, err := svc.Events(context.Background(), "fam-1", "1", 10); !errors.Is(err, ErrCursorExpired) {
+		t.Fatalf("expected ErrCursorExpired, got %v", err)
+	}
+	page, err := svc.Events(context.Background(), "fam-1", "2", 10)
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if len(page.Events) != 1 || page.Retention.OldestCursor != "2" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	repo.events = nil
+	if _, err := svc.Events(context.Background(), "fam-1", "2", 10); !errors.Is(err, ErrCursorExpired) {
+		t.Fatalf("expected ErrCursorExpired with every event purged, got %v", err)
+	}
+	if _, err := svc.Events(context.Background(), "fam-1", "3", 10); err != nil {
+		t.Fatalf("caught-up cursor: %v", err)
+	}
+}
+
+type recordingSnapshotWriter struct {
+	cursor  string
+	records []SnapshotRecord
+}
+
+func (w *recordingSnapshotWriter) Begin(cursor string) error {
+	w.cursor = cursor
+	return nil
+}
+
+func (w *recordingSnapshotWriter) Write(record SnapshotRecord) error {
+	w.records = append(w.records, record)
+	return nil
+}
+
+func TestSnapshotCursorHandsOffToEvents(t *testing.T) {
+	repo := newFakeSyncRepo()
+	svc := NewServiceWithOptions(repo, newFakeExpensesService(), newFakeTodosService(), ServiceOptions{RetentionDays: 30})
+	repo.appendEvent("exp-1", time.Now().UTC())
+	repo.appendEvent("exp-2", time.Now().UTC())
+
+	var snapshot recordingSnapshotWriter
+	if err := svc.Snapshot(context.Background(), "fam-1", "user-1", &snapshot); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if snapshot.cursor != "2" || len(snapshot.records) != 2 {
+		t.Fatalf("unexpected snapshot: cursor %q, %d records", snapshot.cursor, len(snapshot.records))
+	}
+
+	repo.appendEvent("exp-3", time.Now().UTC())
+	page, err := svc.Events(context.Background(), "fam-1", snapshot.cursor, 10)
+	if err != nil {
+		t.Fatalf("events: %v", err)
+	}
+	if len(page.Events) != 1 || page.Events[0].EntityID != "exp-3" || page.NextCursor != "3" {
+		t.Fatalf("expected only the change after the snapshot, got %+v", page)
+	}
+}
+
+func TestSnapshotCursorSurvivesPurgedEvents(t *testing.T) {
+	repo := newFakeSyncRepo()
+	svc := NewServiceWithOptions(repo, newFakeExpensesService(), newFakeTodosService(), ServiceOptions{RetentionDays: 30})
+	repo.appendEvent("exp-1", time.Now().UTC().AddDate(0, 0, -31))
+	repo.appendEvent("exp-2", time.Now().UTC().AddDate(0, 0, -31))
+	if _, err := svc.Purge(context.Background()); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if _, err := svc.Events(context.Background(), "fam-1", "0", 10); !errors.Is(err, ErrCursorExpired) {
+		t.Fatalf("expected ErrCursorExpired from the start of the log, got %v", err)
+	}
+
+	var snapshot recordingSnapshotWriter
+	if err := svc.Snapshot(context.Background(), "fam-1", "user-1", &snapshot); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	page, err := svc.Events(context.Background(), "fam-1", snapshot.cursor, 10)
+	if err != nil {
+		t.Fatalf("events from snapshot cursor %q: %v", snapshot.cursor, err)
+	}
+	if len(page.Events) != 0 || page.NextCursor != "2" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
+
+type fakeFlags map[string]bool
+
+func (f fakeFlags) Enabled(_ context.Context, key, _ string) (bool, error) {
+	return f[key], nil
+}
+
 type fakeSyncRepo struct {
 	mu stdsync.Mutex
 
@@ -269,6 +912,9 @@ type fakeSyncRepo struct {
 
 	operationsByID  map[string]OperationRecord
 	operationsByKey map[string]string
+
+	events  []ChangeEvent
+	lastSeq int64
 }
 
 func newFakeSyncRepo() *fakeSyncRepo {
@@ -280,6 +926,22 @@ func newFakeSyncRepo() *fakeSyncRepo {
 	}
 }
 
+func (r *fakeSyncRepo) Transaction(_ context.Context, fn func(Repository, expensesdomain.Repository, todosdomain.Repository) error) error {
+	r.mu.Lock()
+	batchesByID, batchesByKey := maps.Clone(r.batchesByID), maps.Clone(r.batchesByKey)
+	operationsByID, operationsByKey := maps.Clone(r.operationsByID), maps.Clone(r.operationsByKey)
+	r.mu.Unlock()
+
+	if err := fn(r, nil, nil); err != nil {
+		r.mu.Lock()
+		r.batchesByID, r.batchesByKey = batchesByID, batchesByKey
+		r.operationsByID, r.operationsByKey = operationsByID, operationsByKey
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
 func (r *fakeSyncRepo) BeginBatch(_ context.Context, batch *BatchRecord) (bool, *BatchRecord, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -329,6 +991,9 @@ func (r *fakeSyncRepo) ReserveOperation(_ context.Context, operation *OperationR
 	}
 
 	copied := *operation
+	if copied.CreatedAt.IsZero() {
+		copied.CreatedAt = time.Now().UTC()
+	}
 	r.operationsByID[copied.ID] = copied
 	r.operationsByKey[key] = copied.ID
 	return true, nil, nil
@@ -338,15 +1003,41 @@ func (r *fakeSyncRepo) UpdateOperation(_ context.Context, operation *OperationRe
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	_, ok := r.operationsByID[operation.ID]
+	stored, ok := r.operationsByID[operation.ID]
 	if !ok {
 		return nil
 	}
 	copied := *operation
+	copied.CreatedAt = stored.CreatedAt
 	r.operationsByID[copied.ID] = copied
 	return nil
 }
 
+func (r *fakeSyncRepo) FindOperation(_ context.Context, familyID, userID, operationID string) (*OperationRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, ok := r.operationsByKey[operationKey(familyID, userID, operationID)]
+	if !ok {
+		return nil, nil
+	}
+	copied := r.operationsByID[id]
+	return &copied, nil
+}
+
+func (r *fakeSyncRepo) CountOperationsSince(_ context.Context, familyID string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, operation := range r.operationsByID {
+		if operation.FamilyID == familyID && !operation.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (r *fakeSyncRepo) FindServerIDByLocalID(_ context.Context, familyID, userID string, entity Entity, localID string) (string, bool, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -370,6 +1061,111 @@ func (r *fakeSyncRepo) FindServerIDByLocalID(_ context.Context, familyID, userID
 	return "", false, nil
 }
 
+func (r *fakeSyncRepo) FindServerIDsByLocalIDs(ctx context.Context, familyID, userID string, entity Entity, localIDs []string) (map[string]string, error) {
+	result := make(map[string]string, len(localIDs))
+	for _, localID := range localIDs {
+		serverID, found, err := r.FindServerIDByLocalID(ctx, familyID, userID, entity, localID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result[localID] = serverID
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeSyncRepo) DeleteOperationsBefore(_ context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, operation := range r.operationsByID {
+		if deleted >= int64(limit) {
+			break
+		}
+		if operation.CreatedAt.Before(cutoff) {
+			delete(r.operationsByID, id)
+			delete(r.operationsByKey, operationKey(operation.FamilyID, operation.UserID, operation.OperationID))
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *fakeSyncRepo) DeleteBatchesBefore(_ context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, batch := range r.batchesByID {
+		if deleted >= int64(limit) {
+			break
+		}
+		if batch.CreatedAt.Before(cutoff) {
+			delete(r.batchesByID, id)
+			if batch.IdempotencyKey != nil {
+				delete(r.batchesByKey, batchKey(batch.FamilyID, batch.UserID, *batch.IdempotencyKey))
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *fakeSyncRepo) StorageStats(context.Context) (StorageStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return StorageStats{OperationRows: int64(len(r.operationsByID)), BatchRows: int64(len(r.batchesByID))}, nil
+}
+
+// appendEvent records the next event of fam-1 as the database triggers do.
+func (r *fakeSyncRepo) appendEvent(entityID string, occurredAt time.Time) {
+	r.lastSeq++
+	r.events = append(r.events, ChangeEvent{FamilyID: "fam-1", Seq: r.lastSeq, Entity: EntityExpense, EntityID: entityID, Op: ChangeOpUpsert, OccurredAt: occurredAt})
+}
+
+func (r *fakeSyncRepo) ChangeFeedBounds(_ context.Context, _ string) (ChangeFeedBounds, error) {
+	bounds := ChangeFeedBounds{LastSeq: r.lastSeq}
+	if len(r.events) > 0 {
+		bounds.OldestSeq = r.events[0].Seq
+	}
+	return bounds, nil
+}
+
+func (r *fakeSyncRepo) ListChangeEvents(_ context.Context, _ string, afterSeq int64, limit int) ([]ChangeEvent, error) {
+	var events []ChangeEvent
+	for _, event := range r.events {
+		if event.Seq > afterSeq && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeSyncRepo) DeleteChangeEventsBefore(_ context.Context, cutoff time.Time, limit int) (int64, error) {
+	var deleted int64
+	for len(r.events) > 0 && deleted < int64(limit) && r.events[0].OccurredAt.Before(cutoff) {
+		r.events = r.events[1:]
+		deleted++
+	}
+	return deleted, nil
+}
+
+// StreamSnapshot sees the records of every event so far as one expense each.
+func (r *fakeSyncRepo) StreamSnapshot(_ context.Context, _, _ string, begin func(int64) error, emit func(SnapshotRecord) error) error {
+	if err := begin(r.lastSeq); err != nil {
+		return err
+	}
+	for _, event := range r.events {
+		if err := emit(SnapshotRecord{Entity: event.Entity, Data: map[string]interface{}{"id": event.EntityID}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func batchKey(familyID, userID, idempotencyKey string) string {
 	return fmt.Sprintf("%s|%s|%s", familyID, userID, idempotencyKey)
 }
@@ -383,27 +1179,72 @@ type fakeExpensesService struct {
 	createCalls int
 	seq         int
 	createErr   error
+	created     []expensesdomain.CreateExpenseInput
+	categorySeq int
+	// spendingLimit queues expenses above it for approval, like the
+	// expenses service does for a member with a limit.
+	spendingLimit *float64
 }
 
 func newFakeExpensesService() *fakeExpensesService {
 	return &fakeExpensesService{}
 }
 
-func (f *fakeExpensesService) CreateExpense(_ context.Context, _ expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error) {
+func (f *fakeExpensesService) CreateExpense(_ context.Context, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	f.createCalls++
+	f.created = append(f.created, input)
 	if f.createErr != nil {
 		return nil, f.createErr
 	}
 	f.seq++
+	if f.spendingLimit != nil && input.Amount > *f.spendingLimit {
+		return nil, &expensesdomain.ApprovalRequiredError{Approval: expensesdomain.ExpenseApproval{
+			ID:            fmt.Sprintf("approval-%d", f.seq),
+			Amount:        input.Amount,
+			SpendingLimit: *f.spendingLimit,
+			Status:        expensesdomain.ApprovalPending,
+		}}
+	}
 	id := fmt.Sprintf("expense-%d", f.seq)
 	return &expensesdomain.ExpenseWithCategories{
 		Expense: expensesdomain.Expense{ID: id},
 	}, nil
 }
 
+func (f *fakeExpensesService) CreateExpenseWithRepository(ctx context.Context, _ expensesdomain.Repository, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error) {
+	return f.CreateExpense(ctx, input)
+}
+
+func (f *fakeExpensesService) ValidateExpense(_ context.Context, _ expensesdomain.CreateExpenseInput) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.createErr
+}
+
+func (f *fakeExpensesService) CreateCategory(_ context.Context, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.categorySeq++
+	return &expensesdomain.Category{
+		ID:       fmt.Sprintf("category-%d", f.categorySeq),
+		FamilyID: input.FamilyID,
+		Name:     input.Name,
+	}, nil
+}
+
+func (f *fakeExpensesService) CreateCategoryWithRepository(ctx context.Context, _ expensesdomain.Repository, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error) {
+	return f.CreateCategory(ctx, input)
+}
+
+func (f *fakeExpensesService) ValidateCategory(_ context.Context, _ expensesdomain.CreateCategoryInput) error {
+	return nil
+}
+
 type fakeTodosService struct {
 	mu stdsync.Mutex
 
@@ -467,3 +1308,31 @@ func (f *fakeTodosService) UpdateTodoItem(_ context.Context, input todosdomain.U
 	copied := item
 	return &copied, nil
 }
+
+func (f *fakeTodosService) ValidateTodoItem(_ context.Context, _ string, input todosdomain.CreateTodoItemInput) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.lists[input.ListID]; !ok {
+		return todosdomain.ErrTodoListNotFound
+	}
+	return nil
+}
+
+func (f *fakeTodosService) ValidateTodoItemUpdate(_ context.Context, input todosdomain.UpdateTodoItemInput) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.items[input.ID]; !ok {
+		return todosdomain.ErrTodoItemNotFound
+	}
+	return nil
+}
+
+func (f *fakeTodosService) CreateTodoItemWithRepository(ctx context.Context, _ todosdomain.Repository, familyID string, input todosdomain.CreateTodoItemInput) (*todosdomain.TodoItem, error) {
+	return f.CreateTodoItem(ctx, familyID, input)
+}
+
+func (f *fakeTodosService) UpdateTodoItemWithRepository(ctx context.Context, _ todosdomain.Repository, input todosdomain.UpdateTodoItemInput) (*todosdomain.TodoItem, error) {
+	return f.UpdateTodoItem(ctx, input)
+}
@@ -0,0 +1,34 @@
+package sync
+
+import (
+	"context"
+
+	"family-app-go/pkg/tracing"
+)
+
+// SnapshotRecord is one record of a snapshot with its fields by JSON name.
+type SnapshotRecord struct {
+	Entity Entity
+	Data   map[string]interface{}
+}
+
+// SnapshotWriter receives a snapshot while it is read, so it never has to
+// fit in memory. Begin is called once, before the first record.
+type SnapshotWriter interface {
+	Begin(cursor string) error
+	Write(record SnapshotRecord) error
+}
+
+// Snapshot reads everything a member syncs in one consistent view:
+// categories, expenses, the todo lists they see with their items, and their
+// own workouts with those shared with the family. The cursor passed to Begin
+// covers exactly the changes in the snapshot, so replaying events from it
+// misses nothing.
+func (s *Service) Snapshot(ctx context.Context, familyID, userID string, writer SnapshotWriter) error {
+	ctx, span := tracing.Start(ctx, "sync.Snapshot")
+	defer span.End()
+
+	return s.repo.StreamSnapshot(ctx, familyID, userID, func(lastSeq int64) error {
+		return writer.Begin(formatCursor(lastSeq))
+	}, writer.Write)
+}
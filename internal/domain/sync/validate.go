@@ -0,0 +1,211 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/tracing"
+)
+
+// ValidateBatch pre-flights a batch without applying or recording anything.
+// It runs the checks ProcessBatch would: payloads, quotas, operation IDs the
+// user already sent and todo and category dependencies, including those
+// created earlier in the same batch. Operations that would be applied are reported as
+// ResultStatusValid.
+//
+// The verdicts reflect the data at the time of the call. Quotas are checked
+// per operation against the current counts, so a batch just under one can
+// still partly fail when sent, and the Idempotency-Key is not looked at.
+func (s *Service) ValidateBatch(ctx context.Context, input BatchInput) (*BatchResponse, error) {
+	ctx, span := tracing.Start(ctx, "sync.ValidateBatch")
+	defer span.End()
+
+	if len(input.Operations) == 0 {
+		return nil, fmt.Errorf("operations are required")
+	}
+	if len(input.Operations) > MaxBatchOperations {
+		return nil, ErrBatchTooLarge
+	}
+	if err := s.checkOperationsQuota(ctx, input.FamilyID, len(input.Operations)); err != nil {
+		return nil, err
+	}
+
+	response := BatchResponse{
+		DryRun:   true,
+		Results:  make([]OperationResult, 0, len(input.Operations)),
+		Mappings: make([]EntityMapping, 0),
+		Summary: BatchSummary{
+			Total: len(input.Operations),
+		},
+		ServerTime: time.Now().UTC(),
+	}
+
+	// localIDs holds the entities the batch creates: the server ID for
+	// duplicates of applied operations, empty for those that would be created.
+	localIDs := make(map[localIDKey]string)
+	seen := make(map[string]seenOperation, len(input.Operations))
+
+	for _, operation := range input.Operations {
+		result, mapping := s.validateOperation(ctx, input, operation, localIDs, seen)
+		response.Results = append(response.Results, result)
+		if mapping != nil {
+			response.Mappings = append(response.Mappings, *mapping)
+		}
+		if result.Status != ResultStatusFailed && result.Entity != nil && result.LocalID != nil {
+			localIDs[localIDKey{entity: *result.Entity, localID: *result.LocalID}] = valueOr(result.ServerID, "")
+		}
+
+		switch result.Status {
+		case ResultStatusValid:
+			response.Summary.Valid++
+		case ResultStatusDuplicate:
+			response.Summary.Duplicate++
+		default:
+			response.Summary.Failed++
+		}
+	}
+
+	response.Status = deriveBatchStatus(response.Summary)
+	return &response, nil
+}
+
+// seenOperation is an operation met earlier in the batch being validated.
+type seenOperation struct {
+	payloadHash string
+	result      OperationResult
+}
+
+func (s *Service) validateOperation(ctx context.Context, input BatchInput, operation OperationInput, localIDs map[localIDKey]string, seen map[string]seenOperation) (OperationResult, *EntityMapping) {
+	base := OperationResult{
+		OperationID: operation.OperationID,
+		Type:        operation.Type,
+	}
+
+	payloadHash, err := hashOperation(operation)
+	if err != nil {
+		return failResult(base, ErrorCodeInternalError, "internal error", true), nil
+	}
+
+	if previous, ok := seen[operation.OperationID]; ok {
+		if previous.payloadHash != payloadHash {
+			return failResult(base, ErrorCodeOperationPayloadMismatch, "operation_id already used with different payload", false), nil
+		}
+		result := previous.result
+		if result.Status == ResultStatusValid {
+			result.Status = ResultStatusDuplicate
+		}
+		return result, nil
+	}
+
+	existing, err := s.repo.FindOperation(ctx, input.FamilyID, input.User.ID, operation.OperationID)
+	if err != nil {
+		return failResult(base, ErrorCodeInternalError, "internal error", true), nil
+	}
+	if existing != nil {
+		result, mapping := resultFromExisting(base, operation, existing, payloadHash)
+		seen[operation.OperationID] = seenOperation{payloadHash: payloadHash, result: result}
+		return result, mapping
+	}
+
+	result := s.validateNewOperation(ctx, input, operation, localIDs)
+	seen[operation.OperationID] = seenOperation{payloadHash: payloadHash, result: result}
+	return result, nil
+}
+
+func (s *Service) validateNewOperation(ctx context.Context, input BatchInput, operation OperationInput, localIDs map[localIDKey]string) OperationResult {
+	result := OperationResult{
+		OperationID: operation.OperationID,
+		Type:        operation.Type,
+	}
+	if s.inMaintenance(ctx, input.FamilyID, operation.Type) {
+		return maintenanceResult(result)
+	}
+
+	switch operation.Type {
+	case OperationTypeCreateExpense:
+		if operation.CreateExpense == nil {
+			return failResult(result, ErrorCodeInvalidRequest, "payload is required", false)
+		}
+		categoryIDs, err := s.validateCategoryIDs(ctx, input, operation.CreateExpense, localIDs)
+		if err != nil {
+			return failResult(result, ErrorCodeDependencyNotResolved, "category id dependency is not resolved", false)
+		}
+		if err := s.expenses.ValidateExpense(ctx, createExpenseInput(input, operation.CreateExpense, categoryIDs, nil)); err != nil {
+			return createExpenseFailure(result, err)
+		}
+		entity := EntityExpense
+		result.Entity = &entity
+
+	case OperationTypeCreateCategory:
+		if operation.CreateCategory == nil {
+			return failResult(result, ErrorCodeInvalidRequest, "payload is required", false)
+		}
+		if err := s.expenses.ValidateCategory(ctx, createCategoryInput(input, operation.CreateCategory)); err != nil {
+			return createCategoryFailure(result, err)
+		}
+		entity := EntityCategory
+		result.Entity = &entity
+
+	case OperationTypeCreateTodo:
+		if operation.CreateTodo == nil {
+			return failResult(result, ErrorCodeInvalidRequest, "payload is required", false)
+		}
+		if err := s.todos.ValidateTodoItem(ctx, input.FamilyID, createTodoInput(input, operation.CreateTodo, nil)); err != nil {
+			return createTodoFailure(result, err)
+		}
+		entity := EntityTodoItem
+		result.Entity = &entity
+
+	case OperationTypeSetTodoCompleted:
+		if operation.SetTodoCompleted == nil {
+			return failResult(result, ErrorCodeInvalidRequest, "payload is required", false)
+		}
+
+		localID := strings.TrimSpace(operation.SetTodoCompleted.TodoLocalID)
+		serverID, inBatch := localIDs[localIDKey{entity: EntityTodoItem, localID: localID}]
+		if operation.SetTodoCompleted.TodoID == "" && inBatch && serverID == "" {
+			// The todo does not exist yet; the batch creates it first.
+			break
+		}
+
+		targetTodoID, err := resolveTodoID(ctx, s.repo, input.FamilyID, input.User.ID, operation, localIDs)
+		if err != nil {
+			return failResult(result, ErrorCodeDependencyNotResolved, "todo id dependency is not resolved", false)
+		}
+		if err := s.todos.ValidateTodoItemUpdate(ctx, setTodoCompletedInput(input, targetTodoID, operation.SetTodoCompleted, nil)); err != nil {
+			return setTodoCompletedFailure(result, err)
+		}
+
+	default:
+		return failResult(result, ErrorCodeUnsupportedOperationType, "unsupported operation type", false)
+	}
+
+	result.Status = ResultStatusValid
+	if result.Entity != nil {
+		result.LocalID = nonEmptyStringPtr(operation.LocalID)
+	}
+	return result
+}
+
+// validateCategoryIDs resolves the expense's categories like
+// resolveCategoryIDs, leaving out those the batch has yet to create.
+func (s *Service) validateCategoryIDs(ctx context.Context, input BatchInput, payload *CreateExpensePayload, localIDs map[localIDKey]string) ([]string, error) {
+	categoryIDs := append([]string(nil), payload.CategoryIDs...)
+	for _, localID := range payload.CategoryLocalIDs {
+		localID = strings.TrimSpace(localID)
+		if localID == "" {
+			continue
+		}
+		if serverID, inBatch := localIDs[localIDKey{entity: EntityCategory, localID: localID}]; inBatch && serverID == "" {
+			continue
+		}
+		categoryID, err := resolveLocalID(ctx, s.repo, input.FamilyID, input.User.ID, EntityCategory, localID, localIDs)
+		if err != nil {
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	return categoryIDs, nil
+}
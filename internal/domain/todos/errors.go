@@ -3,6 +3,41 @@ package todos
 import "errors"
 
 var (
-	ErrTodoListNotFound = errors.New("todo list not found")
-	ErrTodoItemNotFound = errors.New("todo item not found")
+	ErrTodoListNotFound   = errors.New("todo list not found")
+	ErrTodoItemNotFound   = errors.New("todo item not found")
+	ErrAssigneeLocked     = errors.New("todo item assignee cannot be changed")
+	ErrVersionConflict    = errors.New("version conflict")
+	ErrShareNotFound      = errors.New("todo list share not found")
+	ErrInvalidShareExpiry = errors.New("share expiry must be in the future")
+	ErrInvalidListViewers = errors.New("list viewers must be family members")
+	ErrTooManyShares      = errors.New("too many todo list shares")
 )
+
+var (
+	ErrInvalidEstimatedPrice = errors.New("estimated price must not be negative")
+	ErrExpenseNotFound       = errors.New("expense not found")
+	ErrTodoItemNotCompleted  = errors.New("only completed todo items can be linked to an expense")
+	ErrTodoItemLinked        = errors.New("todo item is already linked to an expense")
+	// ErrPurchaseLocked is returned when a child member sets the price or
+	// expense of an item.
+	ErrPurchaseLocked = errors.New("todo item purchase cannot be changed")
+)
+
+// TodoListConflictError reports an update based on a stale list version.
+// Current is the stored list the client should reapply its change to.
+type TodoListConflictError struct {
+	Current TodoList
+}
+
+func (e *TodoListConflictError) Error() string { return ErrVersionConflict.Error() }
+
+func (e *TodoListConflictError) Unwrap() error { return ErrVersionConflict }
+
+// TodoItemConflictError reports an update based on a stale item version.
+type TodoItemConflictError struct {
+	Current TodoItem
+}
+
+func (e *TodoItemConflictError) Error() string { return ErrVersionConflict.Error() }
+
+func (e *TodoItemConflictError) Unwrap() error { return ErrVersionConflict }
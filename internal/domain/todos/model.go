@@ -7,14 +7,17 @@ import (
 )
 
 type TodoList struct {
-	ID               string         `gorm:"type:uuid;primaryKey"`
-	FamilyID         string         `gorm:"type:uuid;index;not null"`
-	Title            string         `gorm:"not null"`
-	ArchiveCompleted bool           `gorm:"not null;default:false;column:archive_completed"`
-	IsCollapsed      bool           `gorm:"not null;default:false;column:is_collapsed"`
-	Order            int            `gorm:"not null;column:order_index"`
-	CreatedAt        time.Time      `gorm:"autoCreateTime"`
-	DeletedAt        gorm.DeletedAt `gorm:"index"`
+	ID               string `gorm:"type:uuid;primaryKey"`
+	FamilyID         string `gorm:"type:uuid;index;not null"`
+	Title            string `gorm:"not null"`
+	ArchiveCompleted bool   `gorm:"not null;default:false;column:archive_completed"`
+	IsCollapsed      bool   `gorm:"not null;default:false;column:is_collapsed"`
+	// IsPrivate lists are only visible to their viewers, see ListViewer.
+	IsPrivate bool           `gorm:"not null;default:false;column:is_private"`
+	Order     int            `gorm:"not null;column:order_index"`
+	Version   int64          `gorm:"not null;default:1"`
+	CreatedAt time.Time      `gorm:"autoCreateTime"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 type TodoItem struct {
@@ -25,11 +28,19 @@ type TodoItem struct {
 	IsArchived           bool      `gorm:"not null;default:false"`
 	CreatedAt            time.Time `gorm:"autoCreateTime"`
 	CompletedAt          *time.Time
+	DueDate              *time.Time     `gorm:"type:date"`
+	AssigneeID           *string        `gorm:"type:uuid;column:assignee_id"`
 	CompletedByID        *string        `gorm:"column:completed_by_id"`
 	CompletedByName      *string        `gorm:"column:completed_by_name"`
 	CompletedByEmail     *string        `gorm:"column:completed_by_email"`
 	CompletedByAvatarURL *string        `gorm:"column:completed_by_avatar_url"`
+	Version              int64          `gorm:"not null;default:1"`
 	DeletedAt            gorm.DeletedAt `gorm:"index"`
+
+	// EstimatedPrice is the planned cost in the family currency.
+	EstimatedPrice *float64 `gorm:"type:numeric(12,2)"`
+	// ExpenseID links a completed purchase to the expense that paid for it.
+	ExpenseID *string `gorm:"type:uuid;column:expense_id"`
 }
 
 type UserSnapshot struct {
@@ -43,6 +54,13 @@ type ListFilter struct {
 	Query  string
 	Limit  int
 	Offset int
+	// AssigneeID limits lists and items to those assigned to the user. It is
+	// set for child members.
+	AssigneeID string
+	// ViewerID hides private lists the user is not a viewer of.
+	ViewerID string
+	// FavoritesOf orders the lists this user pinned first.
+	FavoritesOf string
 }
 
 type ArchivedFilter string
@@ -57,12 +75,24 @@ type ListItemCounts struct {
 	ItemsTotal     int64
 	ItemsCompleted int64
 	ItemsArchived  int64
+
+	// PlannedTotal sums the estimated prices of the items and
+	// PlannedPurchased those of the items linked to an expense.
+	PlannedTotal     float64
+	PlannedPurchased float64
+	ItemsLinked      int64
+	// ActualTotal sums the distinct expenses linked to the items in the
+	// family currency, so one receipt for several items counts once.
+	// Unconverted expenses count as zero.
+	ActualTotal float64
 }
 
 type ListWithItems struct {
 	List   TodoList
 	Counts ListItemCounts
 	Items  []TodoItem
+	// ViewerIDs are the members who see a private list.
+	ViewerIDs []string
 }
 
 type CreateTodoListInput struct {
@@ -79,11 +109,23 @@ type UpdateTodoListInput struct {
 	ArchiveCompleted *bool
 	IsCollapsed      *bool
 	Order            *int
+	ViewerID         string
+	// ExpectedVersion rejects the update with a TodoListConflictError when
+	// the stored version differs. Zero skips the check.
+	ExpectedVersion int64
 }
 
 type CreateTodoItemInput struct {
-	ListID string
-	Title  string
+	ListID     string
+	Title      string
+	DueDate    *time.Time
+	AssigneeID *string
+	ViewerID   string
+	// EstimatedPrice is the planned cost in the family currency.
+	EstimatedPrice *float64
+	// CreatedAt backdates the item, e.g. to when it was added offline; nil
+	// means now.
+	CreatedAt *time.Time
 }
 
 type UpdateTodoItemInput struct {
@@ -91,5 +133,44 @@ type UpdateTodoItemInput struct {
 	FamilyID    string
 	Title       *string
 	IsCompleted *bool
+	DueDate     OptionalNullableDate
+	AssigneeID  OptionalNullableString
 	CompletedBy *UserSnapshot
+	// CompletedAt is when the item was completed, for completions recorded
+	// offline; nil means now.
+	CompletedAt *time.Time
+	// EstimatedPrice sets or clears the planned cost.
+	EstimatedPrice OptionalNullableFloat
+	// ExpenseID links the item to an expense of the family or unlinks it.
+	// Only completed items can be linked; reopening an item unlinks it.
+	ExpenseID OptionalNullableString
+	// RestrictToAssignee only allows updating items assigned to this user,
+	// and only their title, due date and completion.
+	RestrictToAssignee string
+	ViewerID           string
+	// ExpectedVersion rejects the update with a TodoItemConflictError when
+	// the stored version differs. Zero skips the check.
+	ExpectedVersion int64
+}
+
+type OptionalNullableDate struct {
+	Set   bool
+	Value *time.Time
+}
+
+type OptionalNullableString struct {
+	Set   bool
+	Value *string
+}
+
+type OptionalNullableFloat struct {
+	Set   bool
+	Value *float64
+}
+
+// DueTodoItem is an open todo item with a due date, together with the title of
+// the list it belongs to.
+type DueTodoItem struct {
+	Item      TodoItem
+	ListTitle string
 }
@@ -1,24 +1,57 @@
 package todos
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Transaction(ctx context.Context, fn func(Repository) error) error
+	// LockFamilyOrders serializes changes to the order of the family's lists
+	// until the transaction ends. Other families are never blocked.
 	LockFamilyOrders(ctx context.Context, familyID string) error
+	// LockListItems serializes adding items to the list until the
+	// transaction ends, so the per-list quota holds under concurrent creates.
+	LockListItems(ctx context.Context, listID string) error
+	// LockShoppingList serializes linking the list's items to expenses until
+	// the transaction ends.
+	LockShoppingList(ctx context.Context, listID string) error
 	ListTodoLists(ctx context.Context, familyID string, filter ListFilter) ([]TodoList, int64, error)
 	GetTodoListByID(ctx context.Context, familyID, listID string) (*TodoList, error)
 	CreateTodoList(ctx context.Context, list *TodoList) error
+	// CountTodoLists counts the family's lists that are not deleted,
+	// archived ones included.
+	CountTodoLists(ctx context.Context, familyID string) (int64, error)
+	// UpdateTodoList only applies when the stored version still matches and bumps it;
+	// otherwise it returns ErrVersionConflict.
 	UpdateTodoList(ctx context.Context, list *TodoList) error
 	SoftDeleteTodoList(ctx context.Context, familyID, listID string) (bool, error)
 	GetMaxOrder(ctx context.Context, familyID string) (int, error)
 	ShiftOrderRange(ctx context.Context, familyID string, from, to, delta int) error
 	SetCompletedItemsArchived(ctx context.Context, listID string, archived bool) error
 	SoftDeleteItemsByList(ctx context.Context, listID string) error
-	CountItemsByListIDs(ctx context.Context, listIDs []string) (map[string]ListItemCounts, error)
-	ListItemsByListIDs(ctx context.Context, listIDs []string, archived ArchivedFilter) ([]TodoItem, error)
-	ListTodoItems(ctx context.Context, listID string, archived ArchivedFilter) ([]TodoItem, int64, error)
+	CountItemsByListIDs(ctx context.Context, listIDs []string, assigneeID string) (map[string]ListItemCounts, error)
+	ListItemsByListIDs(ctx context.Context, listIDs []string, archived ArchivedFilter, assigneeID string) ([]TodoItem, error)
+	SearchTodoItems(ctx context.Context, familyID, query, assigneeID, viewerID string, limit int) ([]TodoItem, error)
+	// ListTodoItems with labels only returns items carrying at least one of them.
+	ListTodoItems(ctx context.Context, listID string, archived ArchivedFilter, assigneeID string, labels []string) ([]TodoItem, int64, error)
 	CreateTodoItem(ctx context.Context, item *TodoItem) error
 	GetTodoItemWithListArchive(ctx context.Context, familyID, itemID string) (*TodoItem, bool, error)
+	// UpdateTodoItem only applies when the stored version still matches and bumps it;
+	// otherwise it returns ErrVersionConflict.
 	UpdateTodoItem(ctx context.Context, item *TodoItem) error
 	SoftDeleteTodoItem(ctx context.Context, itemID string) (bool, error)
+	ListDueTodoItems(ctx context.Context, familyID, viewerID string) ([]DueTodoItem, error)
+	// ListListViewers returns the viewers of the given lists keyed by list ID.
+	ListListViewers(ctx context.Context, listIDs []string) (map[string][]string, error)
+	ReplaceListViewers(ctx context.Context, listID string, userIDs []string) error
+	CountFamilyMembers(ctx context.Context, familyID string, userIDs []string) (int64, error)
+	// ExpenseExists reports whether the family has an expense with this ID.
+	ExpenseExists(ctx context.Context, familyID, expenseID string) (bool, error)
+	CreateListShare(ctx context.Context, share *ListShare) error
+	ListListShares(ctx context.Context, familyID, listID string) ([]ListShare, error)
+	CountActiveListShares(ctx context.Context, listID string, now time.Time) (int64, error)
+	// GetListShareByHash returns ErrShareNotFound for unknown tokens.
+	GetListShareByHash(ctx context.Context, hash string) (*ListShare, error)
+	RevokeListShare(ctx context.Context, familyID, listID, shareID string, at time.Time) error
 }
@@ -2,21 +2,48 @@ package todos
 
 import (
 	"context"
-	"crypto/rand"
+	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
+
+	quotadomain "family-app-go/internal/domain/quota"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
 )
 
 type Service struct {
-	repo Repository
+	repo           Repository
+	listsPerFamily int
+	itemsPerList   int
+}
+
+type ServiceOptions struct {
+	// TodoListsPerFamily caps the lists a family keeps; zero leaves it
+	// unlimited.
+	TodoListsPerFamily int
+	// TodoItemsPerList caps the items in a list, archived ones included;
+	// zero leaves it unlimited.
+	TodoItemsPerList int
 }
 
 func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+	return NewServiceWithOptions(repo, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	return &Service{
+		repo:           repo,
+		listsPerFamily: options.TodoListsPerFamily,
+		itemsPerList:   options.TodoItemsPerList,
+	}
 }
 
 func (s *Service) ListTodoLists(ctx context.Context, familyID string, filter ListFilter, includeItems bool, itemsArchived ArchivedFilter) ([]ListWithItems, int64, error) {
+	ctx, span := tracing.Start(ctx, "todos.ListTodoLists")
+	defer span.End()
+
 	lists, total, err := s.repo.ListTodoLists(ctx, familyID, filter)
 	if err != nil {
 		return nil, 0, err
@@ -30,14 +57,28 @@ func (s *Service) ListTodoLists(ctx context.Context, familyID string, filter Lis
 		listIDs = append(listIDs, list.ID)
 	}
 
-	counts, err := s.repo.CountItemsByListIDs(ctx, listIDs)
+	counts, err := s.repo.CountItemsByListIDs(ctx, listIDs, filter.AssigneeID)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	privateIDs := make([]string, 0)
+	for _, list := range lists {
+		if list.IsPrivate {
+			privateIDs = append(privateIDs, list.ID)
+		}
+	}
+	viewers := map[string][]string{}
+	if len(privateIDs) > 0 {
+		viewers, err = s.repo.ListListViewers(ctx, privateIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
 	itemsByList := map[string][]TodoItem{}
 	if includeItems {
-		items, err := s.repo.ListItemsByListIDs(ctx, listIDs, itemsArchived)
+		items, err := s.repo.ListItemsByListIDs(ctx, listIDs, itemsArchived, filter.AssigneeID)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -49,14 +90,19 @@ func (s *Service) ListTodoLists(ctx context.Context, familyID string, filter Lis
 	result := make([]ListWithItems, 0, len(lists))
 	for _, list := range lists {
 		listCounts := counts[list.ID]
+		if filter.AssigneeID != "" {
+			// Child members do not see the family's expenses.
+			listCounts.ActualTotal = 0
+		}
 		items := itemsByList[list.ID]
 		if includeItems && items == nil {
 			items = []TodoItem{}
 		}
 		result = append(result, ListWithItems{
-			List:   list,
-			Counts: listCounts,
-			Items:  items,
+			List:      list,
+			Counts:    listCounts,
+			Items:     items,
+			ViewerIDs: viewers[list.ID],
 		})
 	}
 
@@ -64,26 +110,34 @@ func (s *Service) ListTodoLists(ctx context.Context, familyID string, filter Lis
 }
 
 func (s *Service) CountItemsByListID(ctx context.Context, listID string) (ListItemCounts, error) {
-	counts, err := s.repo.CountItemsByListIDs(ctx, []string{listID})
+	ctx, span := tracing.Start(ctx, "todos.CountItemsByListID")
+	defer span.End()
+
+	counts, err := s.repo.CountItemsByListIDs(ctx, []string{listID}, "")
 	if err != nil {
 		return ListItemCounts{}, err
 	}
 	return counts[listID], nil
 }
 
-func (s *Service) CreateTodoList(ctx context.Context, input CreateTodoListInput) (*TodoList, error) {
+// CreateTodoList returns the new list with its counts, which are zero: the
+// caller needs no second query to render it.
+func (s *Service) CreateTodoList(ctx context.Context, input CreateTodoListInput) (*ListWithItems, error) {
+	ctx, span := tracing.Start(ctx, "todos.CreateTodoList")
+	defer span.End()
+
 	title := strings.TrimSpace(input.Title)
 	if title == "" {
 		return nil, fmt.Errorf("title is required")
 	}
 
-	id, err := newUUID()
+	newID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
 
 	list := TodoList{
-		ID:               id,
+		ID:               newID,
 		FamilyID:         input.FamilyID,
 		Title:            title,
 		ArchiveCompleted: input.ArchiveCompleted,
@@ -93,6 +147,15 @@ func (s *Service) CreateTodoList(ctx context.Context, input CreateTodoListInput)
 		if err := tx.LockFamilyOrders(ctx, input.FamilyID); err != nil {
 			return err
 		}
+		if s.listsPerFamily > 0 {
+			count, err := tx.CountTodoLists(ctx, input.FamilyID)
+			if err != nil {
+				return err
+			}
+			if err := quotadomain.Check(quotadomain.TodoListsPerFamily, s.listsPerFamily, 0, count, 1); err != nil {
+				return err
+			}
+		}
 		maxOrder, err := tx.GetMaxOrder(ctx, input.FamilyID)
 		if err != nil {
 			return err
@@ -120,57 +183,61 @@ func (s *Service) CreateTodoList(ctx context.Context, input CreateTodoListInput)
 		return nil, err
 	}
 
-	return &list, nil
+	return &ListWithItems{List: list}, nil
 }
 
-func (s *Service) UpdateTodoList(ctx context.Context, input UpdateTodoListInput) (*TodoList, error) {
+// UpdateTodoList returns the updated list with its item counts, read in the
+// same transaction as the update.
+func (s *Service) UpdateTodoList(ctx context.Context, input UpdateTodoListInput) (*ListWithItems, error) {
+	ctx, span := tracing.Start(ctx, "todos.UpdateTodoList")
+	defer span.End()
+
 	if input.Title == nil && input.ArchiveCompleted == nil && input.IsCollapsed == nil && input.Order == nil {
 		return nil, fmt.Errorf("no fields to update")
 	}
 
-	list, err := s.repo.GetTodoListByID(ctx, input.FamilyID, input.ID)
-	if err != nil {
-		return nil, err
-	}
-
-	archiveChanged := false
-	var desiredOrder *int
+	var title string
 	if input.Title != nil {
-		trimmed := strings.TrimSpace(*input.Title)
-		if trimmed == "" {
+		title = strings.TrimSpace(*input.Title)
+		if title == "" {
 			return nil, fmt.Errorf("title is required")
 		}
-		list.Title = trimmed
-	}
-	if input.ArchiveCompleted != nil {
-		archiveChanged = list.ArchiveCompleted != *input.ArchiveCompleted
-		list.ArchiveCompleted = *input.ArchiveCompleted
 	}
-	if input.IsCollapsed != nil {
-		list.IsCollapsed = *input.IsCollapsed
-	}
-	if input.Order != nil {
-		if *input.Order < 0 {
-			return nil, fmt.Errorf("order must be non-negative")
-		}
-		desiredOrder = input.Order
+	if input.Order != nil && *input.Order < 0 {
+		return nil, fmt.Errorf("order must be non-negative")
 	}
 
-	err = s.repo.Transaction(ctx, func(tx Repository) error {
-		if desiredOrder != nil {
+	var result *ListWithItems
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		if input.Order != nil {
 			if err := tx.LockFamilyOrders(ctx, input.FamilyID); err != nil {
 				return err
 			}
 		}
-		// Ensure we work with the latest order inside the transaction.
-		current, err := tx.GetTodoListByID(ctx, input.FamilyID, input.ID)
+		// Read the list inside the transaction so the order is the latest.
+		list, err := getVisibleList(ctx, tx, input.FamilyID, input.ID, input.ViewerID)
 		if err != nil {
 			return err
 		}
-		list.Order = current.Order
+		if input.ExpectedVersion > 0 && list.Version != input.ExpectedVersion {
+			return &TodoListConflictError{Current: *list}
+		}
+		currentOrder := list.Order
 
-		if desiredOrder != nil {
-			newOrder := *desiredOrder
+		archiveChanged := false
+		if input.Title != nil {
+			list.Title = title
+		}
+		if input.ArchiveCompleted != nil {
+			archiveChanged = list.ArchiveCompleted != *input.ArchiveCompleted
+			list.ArchiveCompleted = *input.ArchiveCompleted
+		}
+		if input.IsCollapsed != nil {
+			list.IsCollapsed = *input.IsCollapsed
+		}
+
+		if input.Order != nil {
+			newOrder := *input.Order
 			maxOrder, err := tx.GetMaxOrder(ctx, input.FamilyID)
 			if err != nil {
 				return err
@@ -180,19 +247,19 @@ func (s *Service) UpdateTodoList(ctx context.Context, input UpdateTodoListInput)
 				newOrder = maxOrder
 			}
 
-			if newOrder != list.Order {
+			if newOrder != currentOrder {
 				tempOrder := maxOrder + 1
 				list.Order = tempOrder
 				if err := tx.UpdateTodoList(ctx, list); err != nil {
-					return err
+					return todoListConflict(ctx, tx, input.FamilyID, input.ID, err)
 				}
 
-				if newOrder > current.Order {
-					if err := tx.ShiftOrderRange(ctx, input.FamilyID, current.Order+1, newOrder, -1); err != nil {
+				if newOrder > currentOrder {
+					if err := tx.ShiftOrderRange(ctx, input.FamilyID, currentOrder+1, newOrder, -1); err != nil {
 						return err
 					}
 				} else {
-					if err := tx.ShiftOrderRange(ctx, input.FamilyID, newOrder, current.Order-1, 1); err != nil {
+					if err := tx.ShiftOrderRange(ctx, input.FamilyID, newOrder, currentOrder-1, 1); err != nil {
 						return err
 					}
 				}
@@ -202,24 +269,55 @@ func (s *Service) UpdateTodoList(ctx context.Context, input UpdateTodoListInput)
 		}
 
 		if err := tx.UpdateTodoList(ctx, list); err != nil {
-			return err
+			return todoListConflict(ctx, tx, input.FamilyID, input.ID, err)
 		}
 		if archiveChanged {
 			if err := tx.SetCompletedItemsArchived(ctx, list.ID, list.ArchiveCompleted); err != nil {
 				return err
 			}
 		}
+
+		counts, err := tx.CountItemsByListIDs(ctx, []string{list.ID}, "")
+		if err != nil {
+			return err
+		}
+		result = &ListWithItems{List: *list, Counts: counts[list.ID]}
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return list, nil
+	return result, nil
+}
+
+// todoListConflict turns ErrVersionConflict from an update into a
+// TodoListConflictError carrying the stored list; other errors pass through.
+func todoListConflict(ctx context.Context, repo Repository, familyID, listID string, err error) error {
+	if !errors.Is(err, ErrVersionConflict) {
+		return err
+	}
+	current, getErr := repo.GetTodoListByID(ctx, familyID, listID)
+	if getErr != nil {
+		return getErr
+	}
+	return &TodoListConflictError{Current: *current}
+}
+
+// GetTodoList returns the list unless it is private and viewerID is not one
+// of its viewers.
+func (s *Service) GetTodoList(ctx context.Context, familyID, listID, viewerID string) (*TodoList, error) {
+	ctx, span := tracing.Start(ctx, "todos.GetTodoList")
+	defer span.End()
+
+	return getVisibleList(ctx, s.repo, familyID, listID, viewerID)
 }
 
-func (s *Service) DeleteTodoList(ctx context.Context, familyID, listID string) error {
-	list, err := s.repo.GetTodoListByID(ctx, familyID, listID)
+func (s *Service) DeleteTodoList(ctx context.Context, familyID, listID, viewerID string) error {
+	ctx, span := tracing.Start(ctx, "todos.DeleteTodoList")
+	defer span.End()
+
+	list, err := getVisibleList(ctx, s.repo, familyID, listID, viewerID)
 	if err != nil {
 		return err
 	}
@@ -239,12 +337,42 @@ func (s *Service) DeleteTodoList(ctx context.Context, familyID, listID string) e
 	})
 }
 
-func (s *Service) ListTodoItems(ctx context.Context, familyID, listID string, archived ArchivedFilter) ([]TodoItem, int64, error) {
-	if _, err := s.repo.GetTodoListByID(ctx, familyID, listID); err != nil {
+// SearchTodoLists returns the family's lists whose title contains query. A
+// non-empty assigneeID limits them to lists with items assigned to that user.
+// Private lists are left out unless viewerID is one of their viewers.
+func (s *Service) SearchTodoLists(ctx context.Context, familyID, query, assigneeID, viewerID string, limit int) ([]TodoList, error) {
+	ctx, span := tracing.Start(ctx, "todos.SearchTodoLists")
+	defer span.End()
+
+	lists, _, err := s.repo.ListTodoLists(ctx, familyID, ListFilter{Query: query, Limit: limit, AssigneeID: assigneeID, ViewerID: viewerID})
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// SearchTodoItems returns items of the family's lists whose title contains
+// query, open items first. A non-empty assigneeID limits them to items
+// assigned to that user. Items of private lists are left out unless
+// viewerID is one of their viewers.
+func (s *Service) SearchTodoItems(ctx context.Context, familyID, query, assigneeID, viewerID string, limit int) ([]TodoItem, error) {
+	ctx, span := tracing.Start(ctx, "todos.SearchTodoItems")
+	defer span.End()
+
+	return s.repo.SearchTodoItems(ctx, familyID, query, assigneeID, viewerID, limit)
+}
+
+// ListTodoItems lists a list's items. A non-empty assigneeID limits them to
+// items assigned to that user.
+func (s *Service) ListTodoItems(ctx context.Context, familyID, listID, viewerID string, archived ArchivedFilter, assigneeID string, labels []string) ([]TodoItem, int64, error) {
+	ctx, span := tracing.Start(ctx, "todos.ListTodoItems")
+	defer span.End()
+
+	if _, err := getVisibleList(ctx, s.repo, familyID, listID, viewerID); err != nil {
 		return nil, 0, err
 	}
 
-	items, total, err := s.repo.ListTodoItems(ctx, listID, archived)
+	items, total, err := s.repo.ListTodoItems(ctx, listID, archived, assigneeID, labels)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -253,39 +381,131 @@ func (s *Service) ListTodoItems(ctx context.Context, familyID, listID string, ar
 }
 
 func (s *Service) CreateTodoItem(ctx context.Context, familyID string, input CreateTodoItemInput) (*TodoItem, error) {
-	title := strings.TrimSpace(input.Title)
-	if title == "" {
-		return nil, fmt.Errorf("title is required")
-	}
+	ctx, span := tracing.Start(ctx, "todos.CreateTodoItem")
+	defer span.End()
 
-	if _, err := s.repo.GetTodoListByID(ctx, familyID, input.ListID); err != nil {
-		return nil, err
-	}
+	return s.createTodoItem(ctx, s.repo, familyID, input)
+}
+
+// CreateTodoItemWithRepository is CreateTodoItem through repo, e.g. one
+// bound to a caller's transaction.
+func (s *Service) CreateTodoItemWithRepository(ctx context.Context, repo Repository, familyID string, input CreateTodoItemInput) (*TodoItem, error) {
+	ctx, span := tracing.Start(ctx, "todos.CreateTodoItemWithRepository")
+	defer span.End()
+
+	return s.createTodoItem(ctx, repo, familyID, input)
+}
 
-	id, err := newUUID()
+func (s *Service) createTodoItem(ctx context.Context, repo Repository, familyID string, input CreateTodoItemInput) (*TodoItem, error) {
+	newID, err := id.New()
 	if err != nil {
 		return nil, err
 	}
 
-	item := TodoItem{
-		ID:     id,
-		ListID: input.ListID,
-		Title:  title,
-	}
+	var item TodoItem
+	err = repo.Transaction(ctx, func(tx Repository) error {
+		if err := tx.LockListItems(ctx, input.ListID); err != nil {
+			return err
+		}
+		title, err := s.checkNewTodoItem(ctx, tx, familyID, input)
+		if err != nil {
+			return err
+		}
+
+		item = TodoItem{
+			ID:         newID,
+			ListID:     input.ListID,
+			Title:      title,
+			DueDate:    normalizeDueDate(input.DueDate),
+			AssigneeID: normalizeAssignee(input.AssigneeID),
 
-	if err := s.repo.CreateTodoItem(ctx, &item); err != nil {
+			EstimatedPrice: normalizeEstimatedPrice(input.EstimatedPrice),
+		}
+		if input.CreatedAt != nil {
+			item.CreatedAt = input.CreatedAt.UTC()
+		}
+		return tx.CreateTodoItem(ctx, &item)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &item, nil
 }
 
+// ValidateTodoItem runs the checks CreateTodoItem does without creating the
+// item.
+func (s *Service) ValidateTodoItem(ctx context.Context, familyID string, input CreateTodoItemInput) error {
+	ctx, span := tracing.Start(ctx, "todos.ValidateTodoItem")
+	defer span.End()
+
+	_, err := s.checkNewTodoItem(ctx, s.repo, familyID, input)
+	return err
+}
+
+// checkNewTodoItem returns the trimmed title once the list is visible to the
+// viewer and has room for another item.
+func (s *Service) checkNewTodoItem(ctx context.Context, repo Repository, familyID string, input CreateTodoItemInput) (string, error) {
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return "", fmt.Errorf("title is required")
+	}
+	if !validEstimatedPrice(input.EstimatedPrice) {
+		return "", ErrInvalidEstimatedPrice
+	}
+
+	if _, err := getVisibleList(ctx, repo, familyID, input.ListID, input.ViewerID); err != nil {
+		return "", err
+	}
+	if s.itemsPerList > 0 {
+		counts, err := repo.CountItemsByListIDs(ctx, []string{input.ListID}, "")
+		if err != nil {
+			return "", err
+		}
+		if err := quotadomain.Check(quotadomain.TodoItemsPerList, s.itemsPerList, 0, counts[input.ListID].ItemsTotal, 1); err != nil {
+			return "", err
+		}
+	}
+	return title, nil
+}
+
 func (s *Service) UpdateTodoItem(ctx context.Context, input UpdateTodoItemInput) (*TodoItem, error) {
-	if input.Title == nil && input.IsCompleted == nil {
-		return nil, fmt.Errorf("no fields to update")
+	ctx, span := tracing.Start(ctx, "todos.UpdateTodoItem")
+	defer span.End()
+
+	return s.updateTodoItem(ctx, s.repo, input)
+}
+
+// UpdateTodoItemWithRepository is UpdateTodoItem through repo, e.g. one
+// bound to a caller's transaction.
+func (s *Service) UpdateTodoItemWithRepository(ctx context.Context, repo Repository, input UpdateTodoItemInput) (*TodoItem, error) {
+	ctx, span := tracing.Start(ctx, "todos.UpdateTodoItemWithRepository")
+	defer span.End()
+
+	return s.updateTodoItem(ctx, repo, input)
+}
+
+func (s *Service) updateTodoItem(ctx context.Context, repo Repository, input UpdateTodoItemInput) (*TodoItem, error) {
+	if !input.ExpenseID.Set {
+		return s.applyTodoItemUpdate(ctx, repo, input)
+	}
+
+	var item *TodoItem
+	err := repo.Transaction(ctx, func(tx Repository) error {
+		var err error
+		item, err = s.applyTodoItemUpdate(ctx, tx, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	return item, nil
+}
 
-	item, archiveCompleted, err := s.repo.GetTodoItemWithListArchive(ctx, input.FamilyID, input.ID)
+// applyTodoItemUpdate takes the shopping list lock before linking an
+// expense, so repo must be bound to a transaction when input sets one.
+func (s *Service) applyTodoItemUpdate(ctx context.Context, repo Repository, input UpdateTodoItemInput) (*TodoItem, error) {
+	item, archiveCompleted, err := loadTodoItemForUpdate(ctx, repo, input)
 	if err != nil {
 		return nil, err
 	}
@@ -298,14 +518,25 @@ func (s *Service) UpdateTodoItem(ctx context.Context, input UpdateTodoItemInput)
 		item.Title = trimmed
 	}
 
+	if input.DueDate.Set {
+		item.DueDate = normalizeDueDate(input.DueDate.Value)
+	}
+
+	if input.AssigneeID.Set {
+		item.AssigneeID = normalizeAssignee(input.AssigneeID.Value)
+	}
+
 	if input.IsCompleted != nil {
 		if *input.IsCompleted {
 			if input.CompletedBy == nil || strings.TrimSpace(input.CompletedBy.ID) == "" {
 				return nil, fmt.Errorf("completed_by is required")
 			}
-			now := time.Now().UTC()
+			completedAt := time.Now().UTC()
+			if input.CompletedAt != nil {
+				completedAt = input.CompletedAt.UTC()
+			}
 			item.IsCompleted = true
-			item.CompletedAt = &now
+			item.CompletedAt = &completedAt
 			item.IsArchived = archiveCompleted
 
 			completedByID := strings.TrimSpace(input.CompletedBy.ID)
@@ -329,21 +560,146 @@ func (s *Service) UpdateTodoItem(ctx context.Context, input UpdateTodoItemInput)
 			item.CompletedByName = nil
 			item.CompletedByEmail = nil
 			item.CompletedByAvatarURL = nil
+			item.ExpenseID = nil
+		}
+	}
+
+	if input.EstimatedPrice.Set {
+		item.EstimatedPrice = normalizeEstimatedPrice(input.EstimatedPrice.Value)
+	}
+
+	if input.ExpenseID.Set {
+		if err := repo.LockShoppingList(ctx, item.ListID); err != nil {
+			return nil, err
+		}
+		expenseID, err := checkExpenseLink(ctx, repo, input.FamilyID, item, input.ExpenseID.Value)
+		if err != nil {
+			return nil, err
+		}
+		item.ExpenseID = expenseID
+	}
+
+	if err := repo.UpdateTodoItem(ctx, item); err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			current, _, getErr := repo.GetTodoItemWithListArchive(ctx, input.FamilyID, input.ID)
+			if getErr != nil {
+				return nil, getErr
+			}
+			return nil, &TodoItemConflictError{Current: *current}
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ValidateTodoItemUpdate runs the checks UpdateTodoItem does against the
+// stored item without changing it.
+func (s *Service) ValidateTodoItemUpdate(ctx context.Context, input UpdateTodoItemInput) error {
+	ctx, span := tracing.Start(ctx, "todos.ValidateTodoItemUpdate")
+	defer span.End()
+
+	item, _, err := loadTodoItemForUpdate(ctx, s.repo, input)
+	if err != nil {
+		return err
+	}
+	if input.Title != nil && strings.TrimSpace(*input.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if input.IsCompleted != nil && *input.IsCompleted && (input.CompletedBy == nil || strings.TrimSpace(input.CompletedBy.ID) == "") {
+		return fmt.Errorf("completed_by is required")
+	}
+	if input.ExpenseID.Set {
+		if input.IsCompleted != nil {
+			item.IsCompleted = *input.IsCompleted
+		}
+		if _, err := checkExpenseLink(ctx, s.repo, input.FamilyID, item, input.ExpenseID.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTodoItemForUpdate returns the item input targets, and whether its list
+// archives completed items, once the viewer may update it.
+func loadTodoItemForUpdate(ctx context.Context, repo Repository, input UpdateTodoItemInput) (*TodoItem, bool, error) {
+	if input.Title == nil && input.IsCompleted == nil && !input.DueDate.Set && !input.AssigneeID.Set &&
+		!input.EstimatedPrice.Set && !input.ExpenseID.Set {
+		return nil, false, fmt.Errorf("no fields to update")
+	}
+	if input.EstimatedPrice.Set && !validEstimatedPrice(input.EstimatedPrice.Value) {
+		return nil, false, ErrInvalidEstimatedPrice
+	}
+
+	item, archiveCompleted, err := repo.GetTodoItemWithListArchive(ctx, input.FamilyID, input.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := checkItemVisible(ctx, repo, input.FamilyID, item, input.ViewerID); err != nil {
+		return nil, false, err
+	}
+	if input.RestrictToAssignee != "" {
+		if item.AssigneeID == nil || *item.AssigneeID != input.RestrictToAssignee {
+			return nil, false, ErrTodoItemNotFound
+		}
+		if input.AssigneeID.Set {
+			return nil, false, ErrAssigneeLocked
+		}
+		if input.EstimatedPrice.Set || input.ExpenseID.Set {
+			return nil, false, ErrPurchaseLocked
 		}
 	}
+	if input.ExpectedVersion > 0 && item.Version != input.ExpectedVersion {
+		return nil, false, &TodoItemConflictError{Current: *item}
+	}
+	return item, archiveCompleted, nil
+}
+
+// GetTodoItem returns an item of one of the family's lists.
+func (s *Service) GetTodoItem(ctx context.Context, familyID, itemID, viewerID string) (*TodoItem, error) {
+	ctx, span := tracing.Start(ctx, "todos.GetTodoItem")
+	defer span.End()
 
-	if err := s.repo.UpdateTodoItem(ctx, item); err != nil {
+	item, _, err := s.repo.GetTodoItemWithListArchive(ctx, familyID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkItemVisible(ctx, s.repo, familyID, item, viewerID); err != nil {
 		return nil, err
 	}
+	return item, nil
+}
+
+// ItemForExpense returns an item the viewer can file as an expense: it must
+// be completed and not linked to an expense yet.
+func (s *Service) ItemForExpense(ctx context.Context, familyID, itemID, viewerID string) (*TodoItem, error) {
+	ctx, span := tracing.Start(ctx, "todos.ItemForExpense")
+	defer span.End()
 
+	item, err := s.GetTodoItem(ctx, familyID, itemID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsCompleted {
+		return nil, ErrTodoItemNotCompleted
+	}
+	if item.ExpenseID != nil {
+		return nil, ErrTodoItemLinked
+	}
 	return item, nil
 }
 
-func (s *Service) DeleteTodoItem(ctx context.Context, familyID, itemID string) error {
+func (s *Service) DeleteTodoItem(ctx context.Context, familyID, itemID, viewerID string) error {
+	ctx, span := tracing.Start(ctx, "todos.DeleteTodoItem")
+	defer span.End()
+
 	item, _, err := s.repo.GetTodoItemWithListArchive(ctx, familyID, itemID)
 	if err != nil {
 		return err
 	}
+	if err := checkItemVisible(ctx, s.repo, familyID, item, viewerID); err != nil {
+		return err
+	}
 
 	deleted, err := s.repo.SoftDeleteTodoItem(ctx, item.ID)
 	if err != nil {
@@ -355,14 +711,63 @@ func (s *Service) DeleteTodoItem(ctx context.Context, familyID, itemID string) e
 	return nil
 }
 
-func newUUID() (string, error) {
-	var b [16]byte
-	if _, err := rand.Read(b[:]); err != nil {
-		return "", err
+// ListDueTodoItems returns open, non-archived items with a due date across all
+// of the family's lists, ordered by due date. Items of private lists are left
+// out unless viewerID is one of their viewers.
+func (s *Service) ListDueTodoItems(ctx context.Context, familyID, viewerID string) ([]DueTodoItem, error) {
+	ctx, span := tracing.Start(ctx, "todos.ListDueTodoItems")
+	defer span.End()
+
+	return s.repo.ListDueTodoItems(ctx, familyID, viewerID)
+}
+
+func normalizeDueDate(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
 	}
+	date := time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+	return &date
+}
 
-	b[6] = (b[6] & 0x0f) | 0x40
-	b[8] = (b[8] & 0x3f) | 0x80
+// checkExpenseLink returns the expense ID to store on item, nil to unlink it.
+func checkExpenseLink(ctx context.Context, repo Repository, familyID string, item *TodoItem, value *string) (*string, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil, nil
+	}
+	if !item.IsCompleted {
+		return nil, ErrTodoItemNotCompleted
+	}
+	expenseID := strings.TrimSpace(*value)
+	exists, err := repo.ExpenseExists(ctx, familyID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrExpenseNotFound
+	}
+	return &expenseID, nil
+}
+
+func validEstimatedPrice(value *float64) bool {
+	return value == nil || (*value >= 0 && !math.IsInf(*value, 0))
+}
+
+// normalizeEstimatedPrice rounds to cents, the scale of the column.
+func normalizeEstimatedPrice(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	rounded := math.Round(*value*100) / 100
+	return &rounded
+}
 
-	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+func normalizeAssignee(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
 }
@@ -0,0 +1,125 @@
+package todos
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type fakeTodosRepo struct {
+	Repository
+	list     *TodoList
+	created  []TodoList
+	counts   map[string]ListItemCounts
+	assignee *string
+	items    []TodoItem
+	// calls records the lock and quota calls in order.
+	calls []string
+}
+
+func (r *fakeTodosRepo) Transaction(_ context.Context, fn func(Repository) error) error {
+	return fn(r)
+}
+
+func (r *fakeTodosRepo) LockFamilyOrders(context.Context, string) error {
+	return nil
+}
+
+func (r *fakeTodosRepo) LockListItems(_ context.Context, listID string) error {
+	r.calls = append(r.calls, "lock "+listID)
+	return nil
+}
+
+func (r *fakeTodosRepo) CreateTodoItem(_ context.Context, item *TodoItem) error {
+	r.items = append(r.items, *item)
+	return nil
+}
+
+func (r *fakeTodosRepo) GetMaxOrder(context.Context, string) (int, error) {
+	return 2, nil
+}
+
+func (r *fakeTodosRepo) CreateTodoList(_ context.Context, list *TodoList) error {
+	list.Version = 1
+	r.created = append(r.created, *list)
+	return nil
+}
+
+func (r *fakeTodosRepo) GetTodoListByID(context.Context, string, string) (*TodoList, error) {
+	list := *r.list
+	return &list, nil
+}
+
+func (r *fakeTodosRepo) UpdateTodoList(_ context.Context, list *TodoList) error {
+	list.Version++
+	r.list = list
+	return nil
+}
+
+func (r *fakeTodosRepo) CountItemsByListIDs(_ context.Context, _ []string, assigneeID string) (map[string]ListItemCounts, error) {
+	r.assignee = &assigneeID
+	r.calls = append(r.calls, "count")
+	return r.counts, nil
+}
+
+func TestCreateTodoListReturnsEmptyCounts(t *testing.T) {
+	repo := &fakeTodosRepo{}
+	service := NewService(repo)
+
+	got, err := service.CreateTodoList(context.Background(), CreateTodoListInput{FamilyID: "family-1", Title: "  Groceries "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("created %d lists, want 1", len(repo.created))
+	}
+	if got.List.Title != "Groceries" || got.List.Order != 3 || got.List.Version != 1 {
+		t.Fatalf("list = %+v, want the trimmed title at order 3, version 1", got.List)
+	}
+	if got.Counts != (ListItemCounts{}) {
+		t.Fatalf("counts = %+v, want zero for a new list", got.Counts)
+	}
+}
+
+func TestUpdateTodoListReturnsItemCounts(t *testing.T) {
+	counts := ListItemCounts{ItemsTotal: 4, ItemsCompleted: 2, ItemsArchived: 1, PlannedTotal: 12.5, PlannedPurchased: 5, ActualTotal: 4.8, ItemsLinked: 1}
+	repo := &fakeTodosRepo{
+		list:   &TodoList{ID: "list-1", FamilyID: "family-1", Title: "Groceries", Order: 1, Version: 3},
+		counts: map[string]ListItemCounts{"list-1": counts, "list-2": {ItemsTotal: 9}},
+	}
+	service := NewService(repo)
+
+	title := "Weekend groceries"
+	got, err := service.UpdateTodoList(context.Background(), UpdateTodoListInput{ID: "list-1", FamilyID: "family-1", Title: &title, ExpectedVersion: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.List.Title != title || got.List.Version != 4 {
+		t.Fatalf("list = %+v, want the new title at version 4", got.List)
+	}
+	if !reflect.DeepEqual(got.Counts, counts) {
+		t.Fatalf("counts = %+v, want %+v", got.Counts, counts)
+	}
+	// The counts describe the whole list, whoever the items are assigned to.
+	if repo.assignee == nil || *repo.assignee != "" {
+		t.Fatalf("counted with assignee %v, want every item", repo.assignee)
+	}
+}
+
+func TestCreateTodoItemCountsUnderTheListLock(t *testing.T) {
+	repo := &fakeTodosRepo{
+		list:   &TodoList{ID: "list-1", FamilyID: "family-1", Title: "Groceries"},
+		counts: map[string]ListItemCounts{"list-1": {ItemsTotal: 1}},
+	}
+	service := NewServiceWithOptions(repo, ServiceOptions{TodoItemsPerList: 5})
+
+	if _, err := service.CreateTodoItem(context.Background(), "family-1", CreateTodoItemInput{ListID: "list-1", Title: "Milk"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"lock list-1", "count"}; !reflect.DeepEqual(repo.calls, want) {
+		t.Fatalf("calls = %v, want %v", repo.calls, want)
+	}
+	if len(repo.items) != 1 || repo.items[0].Title != "Milk" {
+		t.Fatalf("created %+v, want one Milk item", repo.items)
+	}
+}
@@ -0,0 +1,172 @@
+package todos
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	MaxSharesPerList = 20
+	shareTokenBytes  = 24
+)
+
+// ListShare is a read-only link to a todo list for people outside the
+// family. Only the sha256 of the token is stored, so the link is shown once
+// when it is created.
+type ListShare struct {
+	ID        string     `gorm:"type:uuid;primaryKey"`
+	FamilyID  string     `gorm:"type:uuid;not null"`
+	ListID    string     `gorm:"type:uuid;not null"`
+	TokenHash string     `gorm:"not null"`
+	CreatedBy string     `gorm:"type:uuid;not null"`
+	ExpiresAt *time.Time `gorm:""`
+	RevokedAt *time.Time `gorm:""`
+	CreatedAt time.Time  `gorm:"not null"`
+}
+
+func (ListShare) TableName() string {
+	return "todo_list_shares"
+}
+
+// Active reports whether the link still opens the list at now.
+func (s ListShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || now.Before(*s.ExpiresAt))
+}
+
+type CreateListShareInput struct {
+	FamilyID  string
+	ListID    string
+	CreatedBy string
+	ExpiresAt *time.Time
+}
+
+// SharedList is what a share link shows: the list and its items that are
+// not archived.
+type SharedList struct {
+	List      TodoList
+	Items     []TodoItem
+	ExpiresAt *time.Time
+}
+
+// CreateListShare stores a new share link and returns it together with the
+// plaintext token.
+func (s *Service) CreateListShare(ctx context.Context, input CreateListShareInput) (*ListShare, string, error) {
+	ctx, span := tracing.Start(ctx, "todos.CreateListShare")
+	defer span.End()
+
+	now := time.Now().UTC()
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return nil, "", ErrInvalidShareExpiry
+	}
+	if _, err := getVisibleList(ctx, s.repo, input.FamilyID, input.ListID, input.CreatedBy); err != nil {
+		return nil, "", err
+	}
+
+	count, err := s.repo.CountActiveListShares(ctx, input.ListID, now)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= MaxSharesPerList {
+		return nil, "", ErrTooManyShares
+	}
+
+	secret := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, "", err
+	}
+	var expiresAt *time.Time
+	if input.ExpiresAt != nil {
+		value := input.ExpiresAt.UTC()
+		expiresAt = &value
+	}
+	share := ListShare{
+		ID:        newID,
+		FamilyID:  input.FamilyID,
+		ListID:    input.ListID,
+		TokenHash: hashShareToken(token),
+		CreatedBy: input.CreatedBy,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	if err := s.repo.CreateListShare(ctx, &share); err != nil {
+		return nil, "", err
+	}
+	return &share, token, nil
+}
+
+// ListListShares returns all links of a list, revoked and expired included.
+func (s *Service) ListListShares(ctx context.Context, familyID, listID, viewerID string) ([]ListShare, error) {
+	ctx, span := tracing.Start(ctx, "todos.ListListShares")
+	defer span.End()
+
+	if _, err := getVisibleList(ctx, s.repo, familyID, listID, viewerID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListListShares(ctx, familyID, listID)
+}
+
+func (s *Service) RevokeListShare(ctx context.Context, familyID, listID, shareID, viewerID string) error {
+	ctx, span := tracing.Start(ctx, "todos.RevokeListShare")
+	defer span.End()
+
+	if _, err := getVisibleList(ctx, s.repo, familyID, listID, viewerID); err != nil {
+		if errors.Is(err, ErrTodoListNotFound) {
+			return ErrShareNotFound
+		}
+		return err
+	}
+	return s.repo.RevokeListShare(ctx, familyID, listID, shareID, time.Now().UTC())
+}
+
+// GetSharedList resolves a share token. Unknown, revoked and expired tokens
+// and links to deleted lists all return ErrShareNotFound, so the response
+// does not tell which tokens ever existed.
+func (s *Service) GetSharedList(ctx context.Context, token string) (*SharedList, error) {
+	ctx, span := tracing.Start(ctx, "todos.GetSharedList")
+	defer span.End()
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, ErrShareNotFound
+	}
+	share, err := s.repo.GetListShareByHash(ctx, hashShareToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !share.Active(time.Now().UTC()) {
+		return nil, ErrShareNotFound
+	}
+
+	list, err := s.repo.GetTodoListByID(ctx, share.FamilyID, share.ListID)
+	if err != nil {
+		if errors.Is(err, ErrTodoListNotFound) {
+			return nil, ErrShareNotFound
+		}
+		return nil, err
+	}
+	items, _, err := s.repo.ListTodoItems(ctx, list.ID, ArchivedExclude, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SharedList{List: *list, Items: items, ExpiresAt: share.ExpiresAt}, nil
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
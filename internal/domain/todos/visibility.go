@@ -0,0 +1,151 @@
+package todos
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"family-app-go/pkg/tracing"
+)
+
+// ListViewer grants a member access to a private list.
+type ListViewer struct {
+	ListID string `gorm:"type:uuid;primaryKey"`
+	UserID string `gorm:"type:uuid;primaryKey"`
+}
+
+func (ListViewer) TableName() string {
+	return "todo_list_viewers"
+}
+
+type SetListVisibilityInput struct {
+	FamilyID string
+	ListID   string
+	// ActorID must see the list and always stays a viewer, so nobody can
+	// hide a list from themselves.
+	ActorID string
+	// ViewerIDs makes the list private to these members; empty makes it
+	// visible to the whole family again.
+	ViewerIDs []string
+}
+
+// SetListVisibility makes a list private to the given members or, without
+// members, visible to the family. It returns the list and its viewers.
+func (s *Service) SetListVisibility(ctx context.Context, input SetListVisibilityInput) (*TodoList, []string, error) {
+	ctx, span := tracing.Start(ctx, "todos.SetListVisibility")
+	defer span.End()
+
+	var viewerIDs []string
+	if len(input.ViewerIDs) > 0 {
+		viewerIDs = uniqueIDs(append([]string{input.ActorID}, input.ViewerIDs...))
+		count, err := s.repo.CountFamilyMembers(ctx, input.FamilyID, viewerIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		if count != int64(len(viewerIDs)) {
+			return nil, nil, ErrInvalidListViewers
+		}
+	}
+
+	var list *TodoList
+	err := s.repo.Transaction(ctx, func(tx Repository) error {
+		var err error
+		list, err = getVisibleList(ctx, tx, input.FamilyID, input.ListID, input.ActorID)
+		if err != nil {
+			return err
+		}
+		if err := tx.ReplaceListViewers(ctx, list.ID, viewerIDs); err != nil {
+			return err
+		}
+		list.IsPrivate = len(viewerIDs) > 0
+		if err := tx.UpdateTodoList(ctx, list); err != nil {
+			return todoListConflict(ctx, tx, input.FamilyID, input.ListID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return list, viewerIDs, nil
+}
+
+// ItemAudience returns an item the viewer can see with the members who see
+// it too: the viewers of a private list, or nil when the whole family does.
+func (s *Service) ItemAudience(ctx context.Context, familyID, itemID, viewerID string) (*TodoItem, []string, error) {
+	ctx, span := tracing.Start(ctx, "todos.ItemAudience")
+	defer span.End()
+
+	item, _, err := s.repo.GetTodoItemWithListArchive(ctx, familyID, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	list, err := getVisibleList(ctx, s.repo, familyID, item.ListID, viewerID)
+	if err != nil {
+		if errors.Is(err, ErrTodoListNotFound) {
+			return nil, nil, ErrTodoItemNotFound
+		}
+		return nil, nil, err
+	}
+	if !list.IsPrivate {
+		return item, nil, nil
+	}
+	viewers, err := s.repo.ListListViewers(ctx, []string{list.ID})
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, viewers[list.ID], nil
+}
+
+// getVisibleList returns the list unless it is private and viewerID is not
+// one of its viewers, in which case the list does not exist for the caller.
+// An empty viewerID skips the check.
+func getVisibleList(ctx context.Context, repo Repository, familyID, listID, viewerID string) (*TodoList, error) {
+	list, err := repo.GetTodoListByID(ctx, familyID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if viewerID == "" || !list.IsPrivate {
+		return list, nil
+	}
+
+	viewers, err := repo.ListListViewers(ctx, []string{list.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range viewers[list.ID] {
+		if id == viewerID {
+			return list, nil
+		}
+	}
+	return nil, ErrTodoListNotFound
+}
+
+func checkItemVisible(ctx context.Context, repo Repository, familyID string, item *TodoItem, viewerID string) error {
+	if viewerID == "" {
+		return nil
+	}
+	if _, err := getVisibleList(ctx, repo, familyID, item.ListID, viewerID); err != nil {
+		if errors.Is(err, ErrTodoListNotFound) {
+			return ErrTodoItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,5 @@
+package usage
+
+import "errors"
+
+var ErrInvalidRange = errors.New("invalid usage date range")
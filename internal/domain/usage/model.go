@@ -0,0 +1,110 @@
+package usage
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetentionDays = 400
+	// DefaultWindowDays is the range reports cover when none is given;
+	// MaxWindowDays bounds it.
+	DefaultWindowDays = 30
+	MaxWindowDays     = 366
+
+	DefaultLimit = 100
+	MaxLimit     = 1000
+
+	DefaultFlushInterval = time.Minute
+	// maxPending bounds the counters kept between flushes. New endpoint and
+	// family pairs past it are not counted until the next flush.
+	maxPending = 50000
+	// flushBatchSize keeps each upsert statement small.
+	flushBatchSize = 500
+)
+
+// DailyCount is how many requests a family made to one endpoint on one UTC
+// day. Route is the route pattern, so IDs in the path do not split counts.
+type DailyCount struct {
+	FamilyID string    `gorm:"type:uuid;primaryKey" json:"family_id"`
+	Day      time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Method   string    `gorm:"primaryKey" json:"method"`
+	Route    string    `gorm:"primaryKey" json:"route"`
+	Module   string    `gorm:"not null" json:"module"`
+	Count    int64     `gorm:"not null" json:"count"`
+}
+
+func (DailyCount) TableName() string {
+	return "api_usage_daily"
+}
+
+// ModuleUsage sums a module's requests over a range. Families is how many
+// distinct families used it.
+type ModuleUsage struct {
+	Module   string    `json:"module"`
+	Requests int64     `json:"requests"`
+	Families int64     `json:"families"`
+	LastDay  time.Time `json:"last_day"`
+}
+
+// ModuleReport is the per-module usage of a range of UTC days, both
+// inclusive.
+type ModuleReport struct {
+	From    time.Time
+	To      time.Time
+	Modules []ModuleUsage
+}
+
+// ListFilter selects daily counts. From and To are UTC days, both
+// inclusive.
+type ListFilter struct {
+	From     time.Time
+	To       time.Time
+	FamilyID string
+	Module   string
+	Limit    int
+}
+
+type PurgeResult struct {
+	DeletedBefore time.Time `json:"deleted_before"`
+	Deleted       int64     `json:"deleted"`
+}
+
+// moduleAliases folds route segments that belong to one product module.
+var moduleAliases = map[string]string{
+	"auth":           "account",
+	"me":             "account",
+	"families":       "family",
+	"feature-flags":  "family",
+	"category-rules": "categories",
+	"receipt-parses": "receipts",
+	"top_categories": "analytics",
+	"reports":        "analytics",
+	"todo-lists":     "todos",
+	"todo-items":     "todos",
+	"wishlists":      "wishlist",
+	"wishlist-items": "wishlist",
+}
+
+// ModuleOf returns the module a route pattern belongs to: its first segment
+// after /api, with related segments folded together.
+func ModuleOf(route string) string {
+	segment := strings.TrimPrefix(route, "/api")
+	segment = strings.TrimPrefix(segment, "/")
+	if index := strings.IndexByte(segment, '/'); index >= 0 {
+		segment = segment[:index]
+	}
+	if segment == "" {
+		return "other"
+	}
+	if module, ok := moduleAliases[segment]; ok {
+		return module
+	}
+	return segment
+}
+
+// Day truncates t to its UTC day.
+func Day(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
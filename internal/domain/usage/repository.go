@@ -0,0 +1,19 @@
+package usage
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// AddCounts adds each count to the stored one for its key, creating
+	// missing rows.
+	AddCounts(ctx context.Context, counts []DailyCount) error
+	// ListModules sums requests per module between from and to, both
+	// inclusive, busiest first.
+	ListModules(ctx context.Context, from, to time.Time) ([]ModuleUsage, error)
+	// ListDaily returns matching counts, newest day first.
+	ListDaily(ctx context.Context, filter ListFilter) ([]DailyCount, error)
+	// DeleteBefore removes counts of days before day.
+	DeleteBefore(ctx context.Context, day time.Time) (int64, error)
+}
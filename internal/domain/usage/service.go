@@ -0,0 +1,259 @@
+package usage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"family-app-go/pkg/tracing"
+)
+
+// Service counts API requests per family, endpoint and day. Record only
+// bumps an in-memory counter; Flush adds the counters to the database, so
+// a busy endpoint costs one upsert per family and flush interval.
+type Service struct {
+	repo          Repository
+	retentionDays int
+	now           func() time.Time
+
+	mu      sync.Mutex
+	pending map[countKey]int64
+
+	loopMu sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type ServiceOptions struct {
+	// RetentionDays is how long daily counts are kept before Purge removes
+	// them.
+	RetentionDays int
+}
+
+type countKey struct {
+	familyID string
+	day      time.Time
+	method   string
+	route    string
+}
+
+func NewService(repo Repository) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	retentionDays := options.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = DefaultRetentionDays
+	}
+	return &Service{
+		repo:          repo,
+		retentionDays: retentionDays,
+		now:           time.Now,
+		pending:       make(map[countKey]int64),
+	}
+}
+
+// Record counts one request of a family to a route pattern. Requests
+// without a family or a matched route are not counted.
+func (s *Service) Record(familyID, method, route string) {
+	if familyID == "" || route == "" {
+		return
+	}
+	key := countKey{familyID: familyID, day: Day(s.now()), method: method, route: route}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pending[key]; !ok && len(s.pending) >= maxPending {
+		return
+	}
+	s.pending[key]++
+}
+
+// Flush writes the counters recorded since the last flush and returns how
+// many rows it touched. On failure the counters are kept for the next
+// flush.
+func (s *Service) Flush(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "usage.Flush")
+	defer span.End()
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[countKey]int64)
+	s.mu.Unlock()
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	counts := make([]DailyCount, 0, len(pending))
+	for key, count := range pending {
+		counts = append(counts, DailyCount{
+			FamilyID: key.familyID,
+			Day:      key.day,
+			Method:   key.method,
+			Route:    key.route,
+			Module:   ModuleOf(key.route),
+			Count:    count,
+		})
+	}
+	// A stable order keeps instances flushing at once from deadlocking on
+	// each other's rows.
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if a.FamilyID != b.FamilyID {
+			return a.FamilyID < b.FamilyID
+		}
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		return a.Method < b.Method
+	})
+
+	for start := 0; start < len(counts); start += flushBatchSize {
+		end := start + flushBatchSize
+		if end > len(counts) {
+			end = len(counts)
+		}
+		if err := s.repo.AddCounts(ctx, counts[start:end]); err != nil {
+			s.restore(counts[start:])
+			return start, err
+		}
+	}
+	return len(counts), nil
+}
+
+// Start flushes every interval until Stop. Every instance runs its own
+// loop, since each one holds its own counters.
+func (s *Service) Start(interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	s.loopMu.Lock()
+	defer s.loopMu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Flush(ctx); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the flush loop and writes what is left, or gives up when ctx
+// expires.
+func (s *Service) Stop(ctx context.Context) error {
+	s.loopMu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.loopMu.Unlock()
+	if cancel != nil {
+		cancel()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	_, err := s.Flush(ctx)
+	return err
+}
+
+// ListModules sums usage per module between two UTC days, both inclusive.
+// Zero days default to the last DefaultWindowDays.
+func (s *Service) ListModules(ctx context.Context, from, to time.Time) (*ModuleReport, error) {
+	ctx, span := tracing.Start(ctx, "usage.ListModules")
+	defer span.End()
+
+	from, to, err := s.window(from, to)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := s.repo.ListModules(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &ModuleReport{From: from, To: to, Modules: modules}, nil
+}
+
+// ListDaily returns daily counts per family and endpoint, newest first.
+func (s *Service) ListDaily(ctx context.Context, filter ListFilter) ([]DailyCount, error) {
+	ctx, span := tracing.Start(ctx, "usage.ListDaily")
+	defer span.End()
+
+	from, to, err := s.window(filter.From, filter.To)
+	if err != nil {
+		return nil, err
+	}
+	filter.From, filter.To = from, to
+	filter.FamilyID = strings.TrimSpace(filter.FamilyID)
+	filter.Module = strings.TrimSpace(filter.Module)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	return s.repo.ListDaily(ctx, filter)
+}
+
+// Purge removes daily counts older than the retention period.
+func (s *Service) Purge(ctx context.Context) (*PurgeResult, error) {
+	ctx, span := tracing.Start(ctx, "usage.Purge")
+	defer span.End()
+
+	cutoff := Day(s.now()).AddDate(0, 0, -s.retentionDays)
+	deleted, err := s.repo.DeleteBefore(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResult{DeletedBefore: cutoff, Deleted: deleted}, nil
+}
+
+func (s *Service) window(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = s.now()
+	}
+	to = Day(to)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -(DefaultWindowDays - 1))
+	}
+	from = Day(from)
+	if from.After(to) || to.Sub(from) >= MaxWindowDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrInvalidRange
+	}
+	return from, to, nil
+}
+
+// restore puts counts that failed to flush back, unless new ones already
+// filled the pending set.
+func (s *Service) restore(counts []DailyCount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, count := range counts {
+		key := countKey{familyID: count.FamilyID, day: count.Day, method: count.Method, route: count.Route}
+		if _, ok := s.pending[key]; !ok && len(s.pending) >= maxPending {
+			continue
+		}
+		s.pending[key] += count.Count
+	}
+}
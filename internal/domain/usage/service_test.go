@@ -0,0 +1,131 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeUsageRepo struct {
+	rows    map[countKey]DailyCount
+	failing bool
+	cutoff  time.Time
+	filter  ListFilter
+}
+
+func newFakeUsageRepo() *fakeUsageRepo {
+	return &fakeUsageRepo{rows: map[countKey]DailyCount{}}
+}
+
+func (r *fakeUsageRepo) AddCounts(_ context.Context, counts []DailyCount) error {
+	if r.failing {
+		return errors.New("database unavailable")
+	}
+	for _, count := range counts {
+		key := countKey{familyID: count.FamilyID, day: count.Day, method: count.Method, route: count.Route}
+		row := r.rows[key]
+		count.Count += row.Count
+		r.rows[key] = count
+	}
+	return nil
+}
+
+func (r *fakeUsageRepo) ListModules(_ context.Context, from, to time.Time) ([]ModuleUsage, error) {
+	return nil, nil
+}
+
+func (r *fakeUsageRepo) ListDaily(_ context.Context, filter ListFilter) ([]DailyCount, error) {
+	r.filter = filter
+	return nil, nil
+}
+
+func (r *fakeUsageRepo) DeleteBefore(_ context.Context, day time.Time) (int64, error) {
+	r.cutoff = day
+	return 0, nil
+}
+
+func TestFlushAddsCountsPerFamilyRouteAndDay(t *testing.T) {
+	repo := newFakeUsageRepo()
+	service := NewService(repo)
+	now := time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	service.Record("family-1", "GET", "/api/gym/entries")
+	service.Record("family-1", "GET", "/api/gym/entries")
+	service.Record("family-2", "POST", "/api/todo-lists/{list_id}/items")
+	service.Record("", "GET", "/api/gym/entries")
+	if rows, err := service.Flush(ctx); err != nil || rows != 2 {
+		t.Fatalf("expected 2 rows flushed, got %d, %v", rows, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	service.Record("family-1", "GET", "/api/gym/entries")
+	if _, err := service.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	gym := repo.rows[countKey{familyID: "family-1", day: day, method: "GET", route: "/api/gym/entries"}]
+	if gym.Count != 2 || gym.Module != "gym" {
+		t.Fatalf("unexpected gym count: %+v", gym)
+	}
+	todos := repo.rows[countKey{familyID: "family-2", day: day, method: "POST", route: "/api/todo-lists/{list_id}/items"}]
+	if todos.Count != 1 || todos.Module != "todos" {
+		t.Fatalf("unexpected todos count: %+v", todos)
+	}
+	if next := repo.rows[countKey{familyID: "family-1", day: day.AddDate(0, 0, 1), method: "GET", route: "/api/gym/entries"}]; next.Count != 1 {
+		t.Fatalf("expected the next day to be counted apart, got %+v", next)
+	}
+}
+
+func TestFailedFlushKeepsCounts(t *testing.T) {
+	repo := newFakeUsageRepo()
+	service := NewService(repo)
+	ctx := context.Background()
+
+	service.Record("family-1", "POST", "/api/expenses")
+	repo.failing = true
+	if _, err := service.Flush(ctx); err == nil {
+		t.Fatal("expected the flush to fail")
+	}
+	service.Record("family-1", "POST", "/api/expenses")
+	repo.failing = false
+	if err := service.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	for _, row := range repo.rows {
+		if row.Count != 2 || row.Module != "expenses" {
+			t.Fatalf("expected both requests to be kept, got %+v", row)
+		}
+	}
+	if len(repo.rows) != 1 {
+		t.Fatalf("expected one row, got %d", len(repo.rows))
+	}
+}
+
+func TestListDailyValidatesRange(t *testing.T) {
+	repo := newFakeUsageRepo()
+	service := NewService(repo)
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := service.ListDaily(ctx, ListFilter{}); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !repo.filter.From.Equal(time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)) || !repo.filter.To.Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)) || repo.filter.Limit != DefaultLimit {
+		t.Fatalf("unexpected default filter: %+v", repo.filter)
+	}
+
+	_, err := service.ListDaily(ctx, ListFilter{From: now, To: now.AddDate(0, 0, -1)})
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected ErrInvalidRange for a reversed range, got %v", err)
+	}
+	_, err = service.ListModules(ctx, now.AddDate(-2, 0, 0), now)
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("expected ErrInvalidRange for a range over a year, got %v", err)
+	}
+}
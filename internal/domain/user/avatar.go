@@ -0,0 +1,171 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"path"
+	"strings"
+
+	"family-app-go/pkg/blobstore"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	// MaxAvatarBytes limits the uploaded file before decoding.
+	MaxAvatarBytes = 5 << 20
+	// AvatarSize is the side of the square avatar stored after resizing.
+	AvatarSize = 256
+
+	maxAvatarPixels     = 40_000_000
+	avatarJPEGQuality   = 85
+	avatarFileExtension = ".jpg"
+)
+
+// UploadAvatar crops the image to a centered square, scales it down to
+// AvatarSize and stores it as JPEG. baseURL is where the avatar store is
+// served from; the resulting URL replaces the provider avatar for the user.
+func (s *Service) UploadAvatar(ctx context.Context, userID string, data []byte, baseURL string) (*Profile, error) {
+	ctx, span := tracing.Start(ctx, "user.UploadAvatar")
+	defer span.End()
+
+	if len(data) == 0 {
+		return nil, ErrInvalidAvatar
+	}
+	if len(data) > MaxAvatarBytes {
+		return nil, ErrAvatarTooLarge
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidAvatar
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxAvatarPixels {
+		return nil, ErrAvatarTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidAvatar
+	}
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, resizeSquare(src, AvatarSize), &jpeg.Options{Quality: avatarJPEGQuality}); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.GetProfile(ctx, userID)
+	if err != nil && !errors.Is(err, ErrProfileNotFound) {
+		return nil, err
+	}
+
+	// A fresh name per upload keeps clients and proxies from serving a stale
+	// cached image.
+	name, err := id.Hex(8)
+	if err != nil {
+		return nil, err
+	}
+	key := path.Join(userID, name+avatarFileExtension)
+	url := strings.TrimRight(baseURL, "/") + "/" + key
+
+	if _, err := s.avatars.Put(ctx, key, &encoded); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetUploadedAvatar(ctx, userID, key, url); err != nil {
+		_ = s.avatars.Delete(ctx, key)
+		return nil, err
+	}
+	if previous != nil && previous.UploadedAvatarKey != nil && *previous.UploadedAvatarKey != key {
+		_ = s.avatars.Delete(ctx, *previous.UploadedAvatarKey)
+	}
+
+	return s.repo.GetProfile(ctx, userID)
+}
+
+// DeleteUploadedAvatar removes the uploaded avatar file of the user, if any.
+// The profile row itself is left to the caller.
+func (s *Service) DeleteUploadedAvatar(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "user.DeleteUploadedAvatar")
+	defer span.End()
+
+	profile, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrProfileNotFound) {
+			return nil
+		}
+		return err
+	}
+	if profile.UploadedAvatarKey == nil {
+		return nil
+	}
+	return s.avatars.Delete(ctx, *profile.UploadedAvatarKey)
+}
+
+// LoadAvatar returns a stored avatar. Keys are always "<user id>/<file>".
+func (s *Service) LoadAvatar(ctx context.Context, userID, file string) ([]byte, error) {
+	ctx, span := tracing.Start(ctx, "user.LoadAvatar")
+	defer span.End()
+
+	if userID == "" || file == "" || strings.ContainsAny(userID+file, `/\`) || strings.HasPrefix(file, ".") {
+		return nil, ErrAvatarNotFound
+	}
+	body, err := s.avatars.Open(ctx, path.Join(userID, file))
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return nil, ErrAvatarNotFound
+		}
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// resizeSquare crops src to its centered square and box-filters it down to
+// size, flattening transparency onto white. Smaller images keep their size.
+func resizeSquare(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+	if side < size {
+		size = side
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		sy0 := y0 + y*side/size
+		sy1 := y0 + (y+1)*side/size
+		for x := 0; x < size; x++ {
+			sx0 := x0 + x*side/size
+			sx1 := x0 + (x+1)*side/size
+
+			var r, g, b, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					white := uint64(0xffff - ca)
+					r += uint64(cr) + white
+					g += uint64(cg) + white
+					b += uint64(cb) + white
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r / n) >> 8),
+				G: uint8((g / n) >> 8),
+				B: uint8((b / n) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
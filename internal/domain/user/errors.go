@@ -0,0 +1,16 @@
+package user
+
+import "errors"
+
+var (
+	ErrProfileNotFound   = errors.New("profile not found")
+	ErrNoFieldsToUpdate  = errors.New("no fields to update")
+	ErrInvalidTheme      = errors.New("invalid theme")
+	ErrInvalidLanguage   = errors.New("invalid language")
+	ErrInvalidWeightUnit = errors.New("invalid weight unit")
+	ErrInvalidAvatar     = errors.New("invalid avatar image")
+	ErrAvatarTooLarge    = errors.New("avatar image too large")
+	ErrAvatarNotFound    = errors.New("avatar not found")
+
+	ErrRemoteProfileNotFound = errors.New("remote profile not found")
+)
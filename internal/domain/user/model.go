@@ -2,14 +2,73 @@ package user
 
 import "time"
 
+// Profile preference columns are nullable; unset values resolve to the
+// defaults in Preferences. AvatarURL mirrors the identity provider while
+// UploadedAvatarURL, when set, is the avatar the user uploaded and wins.
+// Name and SyncedAt are filled in by profile sync.
 type Profile struct {
-	UserID    string     `gorm:"type:uuid;primaryKey"`
-	Email     *string    `gorm:"type:text"`
-	AvatarURL *string    `gorm:"type:text"`
-	CreatedAt time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt time.Time  `gorm:"autoUpdateTime"`
+	UserID               string  `gorm:"type:uuid;primaryKey"`
+	Name                 *string `gorm:"type:text"`
+	Email                *string `gorm:"type:text"`
+	AvatarURL            *string `gorm:"type:text"`
+	Theme                *string `gorm:"type:text"`
+	Language             *string `gorm:"type:text"`
+	WeightUnit           *string `gorm:"type:text"`
+	NotifyTodoReminders  *bool
+	NotifyGymNudges      *bool
+	NotifyFamilyActivity *bool
+	UploadedAvatarKey    *string `gorm:"type:text"`
+	UploadedAvatarURL    *string `gorm:"type:text"`
+	SyncedAt             *time.Time
+	CreatedAt            time.Time `gorm:"autoCreateTime"`
+	UpdatedAt            time.Time `gorm:"autoUpdateTime"`
 }
 
 func (Profile) TableName() string {
 	return "user_profiles"
 }
+
+// EffectiveAvatarURL returns the uploaded avatar if there is one, otherwise
+// the provider's.
+func (p Profile) EffectiveAvatarURL() string {
+	if p.UploadedAvatarURL != nil && *p.UploadedAvatarURL != "" {
+		return *p.UploadedAvatarURL
+	}
+	if p.AvatarURL != nil {
+		return *p.AvatarURL
+	}
+	return ""
+}
+
+// RemoteProfile is the identity provider's current view of a user. Empty
+// fields are unknown and leave the stored profile alone.
+type RemoteProfile struct {
+	Name      string
+	Email     string
+	AvatarURL string
+}
+
+// SyncOutboxEntry queues a user whose name, email or avatar changed, so the
+// snapshots taken of them can be patched. Entries are written by a database
+// trigger in the transaction that changed the profile.
+type SyncOutboxEntry struct {
+	UserID   string    `gorm:"type:uuid;primaryKey"`
+	QueuedAt time.Time `gorm:"column:queued_at"`
+}
+
+func (SyncOutboxEntry) TableName() string {
+	return "profile_sync_outbox"
+}
+
+// SyncResult sums up one profile sync run.
+type SyncResult struct {
+	// Checked profiles were looked up at the identity provider; Missing ones
+	// no longer exist there and Failed ones could not be fetched.
+	Checked int `json:"checked"`
+	Missing int `json:"missing"`
+	Failed  int `json:"failed"`
+	// Drained outbox entries had their snapshots patched; SnapshotsPatched
+	// counts the rewritten rows.
+	Drained          int   `json:"drained"`
+	SnapshotsPatched int64 `json:"snapshots_patched"`
+}
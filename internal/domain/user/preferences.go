@@ -0,0 +1,185 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+
+	WeightUnitKg = "kg"
+	WeightUnitLb = "lb"
+
+	DefaultLanguage = "en"
+)
+
+type NotificationSettings struct {
+	TodoReminders  bool
+	GymNudges      bool
+	FamilyActivity bool
+}
+
+// Preferences are the resolved per-user settings clients sync across devices.
+type Preferences struct {
+	Theme         string
+	Language      string
+	WeightUnit    string
+	Notifications NotificationSettings
+}
+
+// PreferencesUpdate holds the fields a PATCH changes; nil leaves a field as is.
+type PreferencesUpdate struct {
+	Theme                *string
+	Language             *string
+	WeightUnit           *string
+	NotifyTodoReminders  *bool
+	NotifyGymNudges      *bool
+	NotifyFamilyActivity *bool
+}
+
+func (u PreferencesUpdate) empty() bool {
+	return u.Theme == nil && u.Language == nil && u.WeightUnit == nil &&
+		u.NotifyTodoReminders == nil && u.NotifyGymNudges == nil && u.NotifyFamilyActivity == nil
+}
+
+func DefaultPreferences() Preferences {
+	return Preferences{
+		Theme:      ThemeSystem,
+		Language:   DefaultLanguage,
+		WeightUnit: WeightUnitKg,
+		Notifications: NotificationSettings{
+			TodoReminders:  true,
+			GymNudges:      true,
+			FamilyActivity: true,
+		},
+	}
+}
+
+func (s *Service) GetPreferences(ctx context.Context, userID string) (Preferences, error) {
+	ctx, span := tracing.Start(ctx, "user.GetPreferences")
+	defer span.End()
+
+	profile, err := s.repo.GetProfile(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrProfileNotFound) {
+			return DefaultPreferences(), nil
+		}
+		return Preferences{}, err
+	}
+	return preferencesFromProfile(profile), nil
+}
+
+func (s *Service) UpdatePreferences(ctx context.Context, userID string, update PreferencesUpdate) (Preferences, error) {
+	ctx, span := tracing.Start(ctx, "user.UpdatePreferences")
+	defer span.End()
+
+	if update.empty() {
+		return Preferences{}, ErrNoFieldsToUpdate
+	}
+	if update.Theme != nil {
+		theme, err := normalizeTheme(*update.Theme)
+		if err != nil {
+			return Preferences{}, err
+		}
+		update.Theme = &theme
+	}
+	if update.Language != nil {
+		language, err := normalizeLanguage(*update.Language)
+		if err != nil {
+			return Preferences{}, err
+		}
+		update.Language = &language
+	}
+	if update.WeightUnit != nil {
+		unit, err := normalizeWeightUnit(*update.WeightUnit)
+		if err != nil {
+			return Preferences{}, err
+		}
+		update.WeightUnit = &unit
+	}
+
+	if err := s.repo.UpdatePreferences(ctx, userID, update); err != nil {
+		return Preferences{}, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+func preferencesFromProfile(profile *Profile) Preferences {
+	prefs := DefaultPreferences()
+	if profile.Theme != nil {
+		prefs.Theme = *profile.Theme
+	}
+	if profile.Language != nil {
+		prefs.Language = *profile.Language
+	}
+	if profile.WeightUnit != nil {
+		prefs.WeightUnit = *profile.WeightUnit
+	}
+	if profile.NotifyTodoReminders != nil {
+		prefs.Notifications.TodoReminders = *profile.NotifyTodoReminders
+	}
+	if profile.NotifyGymNudges != nil {
+		prefs.Notifications.GymNudges = *profile.NotifyGymNudges
+	}
+	if profile.NotifyFamilyActivity != nil {
+		prefs.Notifications.FamilyActivity = *profile.NotifyFamilyActivity
+	}
+	return prefs
+}
+
+func normalizeTheme(theme string) (string, error) {
+	theme = strings.ToLower(strings.TrimSpace(theme))
+	switch theme {
+	case ThemeSystem, ThemeLight, ThemeDark:
+		return theme, nil
+	default:
+		return "", ErrInvalidTheme
+	}
+}
+
+func normalizeWeightUnit(unit string) (string, error) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	switch unit {
+	case WeightUnitKg, WeightUnitLb:
+		return unit, nil
+	default:
+		return "", ErrInvalidWeightUnit
+	}
+}
+
+// normalizeLanguage accepts a two-letter language code with an optional
+// two-letter region, e.g. "en" or "pt-BR".
+func normalizeLanguage(language string) (string, error) {
+	language = strings.ReplaceAll(strings.TrimSpace(language), "_", "-")
+	parts := strings.Split(language, "-")
+	if len(parts) > 2 || !isLetters(parts[0], 2) {
+		return "", ErrInvalidLanguage
+	}
+	normalized := strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		if !isLetters(parts[1], 2) {
+			return "", ErrInvalidLanguage
+		}
+		normalized += "-" + strings.ToUpper(parts[1])
+	}
+	return normalized, nil
+}
+
+func isLetters(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
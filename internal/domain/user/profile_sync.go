@@ -0,0 +1,74 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"family-app-go/pkg/tracing"
+)
+
+// ProfileSource looks users up at the identity provider. FetchProfile
+// returns ErrRemoteProfileNotFound for users the provider no longer has.
+type ProfileSource interface {
+	FetchProfile(ctx context.Context, userID string) (*RemoteProfile, error)
+}
+
+// SyncProfiles refreshes the profiles that are due from the identity
+// provider, then patches the completed_by snapshots of every user queued in
+// the outbox, including those the refresh just changed. Profiles missing at
+// the provider are marked synced and kept as they are. A profile that cannot
+// be fetched is retried next run and reported in the error.
+func (s *Service) SyncProfiles(ctx context.Context) (*SyncResult, error) {
+	ctx, span := tracing.Start(ctx, "user.SyncProfiles")
+	defer span.End()
+
+	result := &SyncResult{}
+	var fetchErr error
+	if s.profileSource != nil {
+		now := s.now().UTC()
+		profiles, err := s.repo.ListProfilesToSync(ctx, now.Add(-s.syncStaleAfter), s.syncBatchSize)
+		if err != nil {
+			return result, err
+		}
+		for _, profile := range profiles {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			result.Checked++
+			remote, err := s.profileSource.FetchProfile(ctx, profile.UserID)
+			switch {
+			case errors.Is(err, ErrRemoteProfileNotFound):
+				result.Missing++
+				err = s.repo.MarkProfileSynced(ctx, profile.UserID, now)
+			case err != nil:
+				result.Failed++
+				fetchErr = err
+				continue
+			default:
+				err = s.repo.ApplyRemoteProfile(ctx, profile.UserID, *remote, now)
+			}
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	entries, err := s.repo.ListSyncOutbox(ctx, s.syncBatchSize)
+	if err != nil {
+		return result, err
+	}
+	for _, entry := range entries {
+		patched, err := s.repo.PatchCompletedBySnapshots(ctx, entry)
+		if err != nil {
+			return result, err
+		}
+		result.Drained++
+		result.SnapshotsPatched += patched
+	}
+
+	if fetchErr != nil {
+		return result, fmt.Errorf("fetch %d of %d profiles: %w", result.Failed, result.Checked, fetchErr)
+	}
+	return result, nil
+}
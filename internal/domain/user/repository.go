@@ -1,7 +1,27 @@
 package user
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	UpsertProfile(ctx context.Context, profile *Profile) error
+	GetProfile(ctx context.Context, userID string) (*Profile, error)
+	UpdatePreferences(ctx context.Context, userID string, update PreferencesUpdate) error
+	SetUploadedAvatar(ctx context.Context, userID, key, url string) error
+
+	// ListProfilesToSync returns up to limit profiles never synced or last
+	// synced before syncedBefore, least recently synced first.
+	ListProfilesToSync(ctx context.Context, syncedBefore time.Time, limit int) ([]Profile, error)
+	// ApplyRemoteProfile stores the non-empty fields of remote and marks the
+	// profile synced at syncedAt.
+	ApplyRemoteProfile(ctx context.Context, userID string, remote RemoteProfile, syncedAt time.Time) error
+	MarkProfileSynced(ctx context.Context, userID string, syncedAt time.Time) error
+	// ListSyncOutbox returns up to limit queued users, oldest first.
+	ListSyncOutbox(ctx context.Context, limit int) ([]SyncOutboxEntry, error)
+	// PatchCompletedBySnapshots rewrites the completed_by snapshots of the
+	// entry's user from their profile and removes the entry unless it was
+	// queued again meanwhile. It returns the rows rewritten.
+	PatchCompletedBySnapshots(ctx context.Context, entry SyncOutboxEntry) (int64, error)
 }
@@ -3,19 +3,81 @@ package user
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"family-app-go/pkg/blobstore"
+	"family-app-go/pkg/tracing"
+)
+
+const defaultAvatarStoreRoot = "data/avatars"
+
+const (
+	defaultSyncBatchSize  = 100
+	defaultSyncStaleAfter = 24 * time.Hour
 )
 
 type Service struct {
-	repo Repository
+	repo           Repository
+	avatars        blobstore.Store
+	profileSource  ProfileSource
+	syncBatchSize  int
+	syncStaleAfter time.Duration
+	now            func() time.Time
+}
+
+// ServiceOptions configures the avatar store and profile sync. A nil
+// ProfileSource skips refreshing profiles from the identity provider; queued
+// snapshot patches still run.
+type ServiceOptions struct {
+	Avatars       blobstore.Store
+	ProfileSource ProfileSource
+	// SyncBatchSize caps the profiles refreshed and the outbox entries
+	// drained per run.
+	SyncBatchSize int
+	// SyncStaleAfter is how long a synced profile is trusted before it is
+	// refreshed again.
+	SyncStaleAfter time.Duration
 }
 
 func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+	return NewServiceWithOptions(repo, ServiceOptions{})
+}
+
+func NewServiceWithAvatarStore(repo Repository, avatars blobstore.Store) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{Avatars: avatars})
 }
 
-func (s *Service) UpsertProfile(ctx context.Context, userID, email, avatarURL string) error {
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	avatars := options.Avatars
+	if avatars == nil {
+		avatars = blobstore.NewLocal(defaultAvatarStoreRoot)
+	}
+	batchSize := options.SyncBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSyncBatchSize
+	}
+	staleAfter := options.SyncStaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultSyncStaleAfter
+	}
+	return &Service{
+		repo:           repo,
+		avatars:        avatars,
+		profileSource:  options.ProfileSource,
+		syncBatchSize:  batchSize,
+		syncStaleAfter: staleAfter,
+		now:            time.Now,
+	}
+}
+
+// UpsertProfile stores the identity provider's email and avatar and returns
+// the stored profile, including any uploaded avatar.
+func (s *Service) UpsertProfile(ctx context.Context, userID, email, avatarURL string) (*Profile, error) {
+	ctx, span := tracing.Start(ctx, "user.UpsertProfile")
+	defer span.End()
+
 	if userID == "" {
-		return fmt.Errorf("user id is required")
+		return nil, fmt.Errorf("user id is required")
 	}
 
 	profile := Profile{UserID: userID}
@@ -26,5 +88,8 @@ func (s *Service) UpsertProfile(ctx context.Context, userID, email, avatarURL st
 		profile.AvatarURL = &avatarURL
 	}
 
-	return s.repo.UpsertProfile(ctx, &profile)
+	if err := s.repo.UpsertProfile(ctx, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
 }
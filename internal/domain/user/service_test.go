@@ -0,0 +1,431 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"family-app-go/pkg/blobstore"
+)
+
+const testUserID = "22222222-2222-2222-2222-222222222222"
+
+type fakeUserRepo struct {
+	profiles map[string]*Profile
+	outbox   []SyncOutboxEntry
+	// snapshots is the number of completed_by snapshots each user has.
+	snapshots map[string]int64
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{profiles: make(map[string]*Profile), snapshots: make(map[string]int64)}
+}
+
+func (r *fakeUserRepo) UpsertProfile(_ context.Context, profile *Profile) error {
+	cloned := *profile
+	r.profiles[profile.UserID] = &cloned
+	return nil
+}
+
+func (r *fakeUserRepo) GetProfile(_ context.Context, userID string) (*Profile, error) {
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil, ErrProfileNotFound
+	}
+	cloned := *profile
+	return &cloned, nil
+}
+
+func (r *fakeUserRepo) UpdatePreferences(_ context.Context, userID string, update PreferencesUpdate) error {
+	profile, ok := r.profiles[userID]
+	if !ok {
+		profile = &Profile{UserID: userID}
+		r.profiles[userID] = profile
+	}
+	if update.Theme != nil {
+		profile.Theme = update.Theme
+	}
+	if update.Language != nil {
+		profile.Language = update.Language
+	}
+	if update.WeightUnit != nil {
+		profile.WeightUnit = update.WeightUnit
+	}
+	if update.NotifyTodoReminders != nil {
+		profile.NotifyTodoReminders = update.NotifyTodoReminders
+	}
+	if update.NotifyGymNudges != nil {
+		profile.NotifyGymNudges = update.NotifyGymNudges
+	}
+	if update.NotifyFamilyActivity != nil {
+		profile.NotifyFamilyActivity = update.NotifyFamilyActivity
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) SetUploadedAvatar(_ context.Context, userID, key, url string) error {
+	profile, ok := r.profiles[userID]
+	if !ok {
+		profile = &Profile{UserID: userID}
+		r.profiles[userID] = profile
+	}
+	profile.UploadedAvatarKey = &key
+	profile.UploadedAvatarURL = &url
+	return nil
+}
+
+func (r *fakeUserRepo) ListProfilesToSync(_ context.Context, syncedBefore time.Time, limit int) ([]Profile, error) {
+	var due []Profile
+	for _, profile := range r.profiles {
+		if profile.SyncedAt == nil || profile.SyncedAt.Before(syncedBefore) {
+			due = append(due, *profile)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].UserID < due[j].UserID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (r *fakeUserRepo) ApplyRemoteProfile(_ context.Context, userID string, remote RemoteProfile, syncedAt time.Time) error {
+	profile := r.profiles[userID]
+	before := fmt.Sprint(deref(profile.Name), deref(profile.Email), profile.EffectiveAvatarURL())
+	if remote.Name != "" {
+		profile.Name = &remote.Name
+	}
+	if remote.Email != "" {
+		profile.Email = &remote.Email
+	}
+	if remote.AvatarURL != "" {
+		profile.AvatarURL = &remote.AvatarURL
+	}
+	profile.SyncedAt = &syncedAt
+	if fmt.Sprint(deref(profile.Name), deref(profile.Email), profile.EffectiveAvatarURL()) != before {
+		r.outbox = append(r.outbox, SyncOutboxEntry{UserID: userID, QueuedAt: syncedAt})
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) MarkProfileSynced(_ context.Context, userID string, syncedAt time.Time) error {
+	r.profiles[userID].SyncedAt = &syncedAt
+	return nil
+}
+
+func (r *fakeUserRepo) ListSyncOutbox(_ context.Context, limit int) ([]SyncOutboxEntry, error) {
+	if len(r.outbox) > limit {
+		return r.outbox[:limit], nil
+	}
+	return r.outbox, nil
+}
+
+func (r *fakeUserRepo) PatchCompletedBySnapshots(_ context.Context, entry SyncOutboxEntry) (int64, error) {
+	kept := r.outbox[:0]
+	for _, queued := range r.outbox {
+		if queued != entry {
+			kept = append(kept, queued)
+		}
+	}
+	r.outbox = kept
+	return r.snapshots[entry.UserID], nil
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+type fakeProfileSource struct {
+	profiles map[string]RemoteProfile
+	err      error
+}
+
+func (s *fakeProfileSource) FetchProfile(_ context.Context, userID string) (*RemoteProfile, error) {
+	if userID == "failing" {
+		return nil, s.err
+	}
+	profile, ok := s.profiles[userID]
+	if !ok {
+		return nil, ErrRemoteProfileNotFound
+	}
+	return &profile, nil
+}
+
+type fakeAvatarStore struct {
+	blobstore.Store
+	files map[string][]byte
+}
+
+func newFakeAvatarStore() *fakeAvatarStore {
+	return &fakeAvatarStore{files: make(map[string][]byte)}
+}
+
+func (s *fakeAvatarStore) Put(_ context.Context, key string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s.files[key] = data
+	return int64(len(data)), nil
+}
+
+func (s *fakeAvatarStore) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.files[key]
+	if !ok {
+		return nil, blobstore.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *fakeAvatarStore) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 40, B: 40, A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadAvatarResizesAndReplacesPrevious(t *testing.T) {
+	repo := newFakeUserRepo()
+	store := newFakeAvatarStore()
+	service := NewServiceWithAvatarStore(repo, store)
+	ctx := context.Background()
+
+	first, err := service.UploadAvatar(ctx, testUserID, testPNG(t, 600, 400), "https://api.example.com/api/avatars/")
+	if err != nil {
+		t.Fatalf("upload avatar: %v", err)
+	}
+	if first.UploadedAvatarKey == nil || !strings.HasPrefix(*first.UploadedAvatarKey, testUserID+"/") {
+		t.Fatalf("unexpected key: %v", first.UploadedAvatarKey)
+	}
+	if url := first.EffectiveAvatarURL(); url != "https://api.example.com/api/avatars/"+*first.UploadedAvatarKey {
+		t.Fatalf("unexpected url: %s", url)
+	}
+
+	stored, ok := store.files[*first.UploadedAvatarKey]
+	if !ok {
+		t.Fatalf("expected stored avatar under %s", *first.UploadedAvatarKey)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(stored))
+	if err != nil {
+		t.Fatalf("decode stored avatar: %v", err)
+	}
+	if size := decoded.Bounds().Size(); size.X != AvatarSize || size.Y != AvatarSize {
+		t.Fatalf("expected %dx%d avatar, got %v", AvatarSize, AvatarSize, size)
+	}
+
+	firstKey := *first.UploadedAvatarKey
+	second, err := service.UploadAvatar(ctx, testUserID, testPNG(t, 64, 64), "https://api.example.com/api/avatars")
+	if err != nil {
+		t.Fatalf("upload second avatar: %v", err)
+	}
+	if _, ok := store.files[firstKey]; ok {
+		t.Fatalf("expected previous avatar to be deleted")
+	}
+	if len(store.files) != 1 {
+		t.Fatalf("expected one stored avatar, got %d", len(store.files))
+	}
+
+	file := strings.TrimPrefix(*second.UploadedAvatarKey, testUserID+"/")
+	if _, err := service.LoadAvatar(ctx, testUserID, file); err != nil {
+		t.Fatalf("load avatar: %v", err)
+	}
+	if _, err := service.LoadAvatar(ctx, testUserID, "../"+file); !errors.Is(err, ErrAvatarNotFound) {
+		t.Fatalf("expected ErrAvatarNotFound for traversal, got %v", err)
+	}
+}
+
+func TestUploadAvatarRejectsNonImages(t *testing.T) {
+	service := NewServiceWithAvatarStore(newFakeUserRepo(), newFakeAvatarStore())
+
+	_, err := service.UploadAvatar(context.Background(), testUserID, []byte("not an image"), "https://api.example.com/api/avatars")
+	if !errors.Is(err, ErrInvalidAvatar) {
+		t.Fatalf("expected ErrInvalidAvatar, got %v", err)
+	}
+}
+
+func TestEffectiveAvatarURLPrefersUpload(t *testing.T) {
+	provider := "https://provider.example.com/a.png"
+	uploaded := "https://api.example.com/api/avatars/u/1.jpg"
+
+	profile := Profile{AvatarURL: &provider}
+	if got := profile.EffectiveAvatarURL(); got != provider {
+		t.Fatalf("expected provider avatar, got %s", got)
+	}
+	profile.UploadedAvatarURL = &uploaded
+	if got := profile.EffectiveAvatarURL(); got != uploaded {
+		t.Fatalf("expected uploaded avatar, got %s", got)
+	}
+}
+
+func TestGetPreferencesDefaultsWithoutProfile(t *testing.T) {
+	service := NewServiceWithAvatarStore(newFakeUserRepo(), newFakeAvatarStore())
+
+	prefs, err := service.GetPreferences(context.Background(), testUserID)
+	if err != nil {
+		t.Fatalf("get preferences: %v", err)
+	}
+	if prefs != DefaultPreferences() {
+		t.Fatalf("expected defaults, got %+v", prefs)
+	}
+}
+
+func TestUpdatePreferencesNormalizesAndKeepsUnsetFields(t *testing.T) {
+	repo := newFakeUserRepo()
+	service := NewServiceWithAvatarStore(repo, newFakeAvatarStore())
+
+	theme := " Dark "
+	unit := "LB"
+	language := "pt_br"
+	off := false
+	prefs, err := service.UpdatePreferences(context.Background(), testUserID, PreferencesUpdate{
+		Theme:           &theme,
+		WeightUnit:      &unit,
+		Language:        &language,
+		NotifyGymNudges: &off,
+	})
+	if err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+	if prefs.Theme != ThemeDark || prefs.WeightUnit != WeightUnitLb || prefs.Language != "pt-BR" {
+		t.Fatalf("unexpected preferences: %+v", prefs)
+	}
+	if prefs.Notifications.GymNudges || !prefs.Notifications.TodoReminders || !prefs.Notifications.FamilyActivity {
+		t.Fatalf("unexpected notifications: %+v", prefs.Notifications)
+	}
+
+	light := "light"
+	prefs, err = service.UpdatePreferences(context.Background(), testUserID, PreferencesUpdate{Theme: &light})
+	if err != nil {
+		t.Fatalf("update preferences: %v", err)
+	}
+	if prefs.Theme != ThemeLight || prefs.WeightUnit != WeightUnitLb || prefs.Notifications.GymNudges {
+		t.Fatalf("expected other fields unchanged, got %+v", prefs)
+	}
+}
+
+func TestUpdatePreferencesValidation(t *testing.T) {
+	service := NewServiceWithAvatarStore(newFakeUserRepo(), newFakeAvatarStore())
+	ctx := context.Background()
+
+	if _, err := service.UpdatePreferences(ctx, testUserID, PreferencesUpdate{}); !errors.Is(err, ErrNoFieldsToUpdate) {
+		t.Fatalf("expected ErrNoFieldsToUpdate, got %v", err)
+	}
+
+	theme := "sepia"
+	if _, err := service.UpdatePreferences(ctx, testUserID, PreferencesUpdate{Theme: &theme}); !errors.Is(err, ErrInvalidTheme) {
+		t.Fatalf("expected ErrInvalidTheme, got %v", err)
+	}
+
+	unit := "stone"
+	if _, err := service.UpdatePreferences(ctx, testUserID, PreferencesUpdate{WeightUnit: &unit}); !errors.Is(err, ErrInvalidWeightUnit) {
+		t.Fatalf("expected ErrInvalidWeightUnit, got %v", err)
+	}
+
+	for _, language := range []string{"english", "e", "en-", "en-1A", "en-US-x"} {
+		value := language
+		if _, err := service.UpdatePreferences(ctx, testUserID, PreferencesUpdate{Language: &value}); !errors.Is(err, ErrInvalidLanguage) {
+			t.Fatalf("expected ErrInvalidLanguage for %q, got %v", language, err)
+		}
+	}
+}
+
+func TestSyncProfilesRefreshesDueProfilesAndPatchesSnapshots(t *testing.T) {
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+	stale := now.Add(-48 * time.Hour)
+	oldAvatar := "https://provider.example.com/old.png"
+	uploaded := "https://api.example.com/api/avatars/uploader/1.jpg"
+
+	repo := newFakeUserRepo()
+	repo.profiles["changed"] = &Profile{UserID: "changed", AvatarURL: &oldAvatar, SyncedAt: &stale}
+	repo.profiles["uploader"] = &Profile{UserID: "uploader", AvatarURL: &oldAvatar, UploadedAvatarURL: &uploaded}
+	repo.profiles["gone"] = &Profile{UserID: "gone"}
+	repo.profiles["fresh"] = &Profile{UserID: "fresh", AvatarURL: &oldAvatar, SyncedAt: &recent}
+	repo.profiles["failing"] = &Profile{UserID: "failing"}
+	repo.outbox = []SyncOutboxEntry{{UserID: "queued", QueuedAt: stale}}
+	repo.snapshots = map[string]int64{"changed": 3, "queued": 2}
+
+	source := &fakeProfileSource{
+		profiles: map[string]RemoteProfile{
+			"changed":  {Name: "Ann", AvatarURL: "https://provider.example.com/new.png"},
+			"uploader": {AvatarURL: "https://provider.example.com/new.png"},
+			"fresh":    {Name: "Not fetched"},
+		},
+		err: errors.New("supabase unavailable"),
+	}
+	service := NewServiceWithOptions(repo, ServiceOptions{Avatars: newFakeAvatarStore(), ProfileSource: source})
+	service.now = func() time.Time { return now }
+
+	result, err := service.SyncProfiles(context.Background())
+	if !errors.Is(err, source.err) {
+		t.Fatalf("expected the fetch error, got %v", err)
+	}
+	if result.Checked != 4 || result.Missing != 1 || result.Failed != 1 {
+		t.Fatalf("unexpected refresh counts: %+v", result)
+	}
+	// The uploaded avatar still wins, so only the changed user is queued.
+	if result.Drained != 2 || result.SnapshotsPatched != 5 || len(repo.outbox) != 0 {
+		t.Fatalf("unexpected outbox counts: %+v, left %v", result, repo.outbox)
+	}
+
+	changed := repo.profiles["changed"]
+	if changed.Name == nil || *changed.Name != "Ann" || changed.EffectiveAvatarURL() != "https://provider.example.com/new.png" {
+		t.Fatalf("expected the remote profile applied, got %+v", changed)
+	}
+	if changed.SyncedAt == nil || !changed.SyncedAt.Equal(now) {
+		t.Fatalf("expected synced at %v, got %v", now, changed.SyncedAt)
+	}
+	if gone := repo.profiles["gone"]; gone.SyncedAt == nil || gone.Name != nil {
+		t.Fatalf("expected missing profile marked synced and kept, got %+v", gone)
+	}
+	if fresh := repo.profiles["fresh"]; fresh.Name != nil || !fresh.SyncedAt.Equal(recent) {
+		t.Fatalf("expected fresh profile skipped, got %+v", fresh)
+	}
+	if failing := repo.profiles["failing"]; failing.SyncedAt != nil {
+		t.Fatalf("expected failed profile left due, got %+v", failing)
+	}
+}
+
+func TestSyncProfilesWithoutSourceOnlyDrainsOutbox(t *testing.T) {
+	repo := newFakeUserRepo()
+	repo.profiles[testUserID] = &Profile{UserID: testUserID}
+	repo.outbox = []SyncOutboxEntry{{UserID: testUserID, QueuedAt: time.Now()}}
+	repo.snapshots[testUserID] = 4
+	service := NewServiceWithAvatarStore(repo, newFakeAvatarStore())
+
+	result, err := service.SyncProfiles(context.Background())
+	if err != nil {
+		t.Fatalf("sync profiles: %v", err)
+	}
+	if result.Checked != 0 || result.Drained != 1 || result.SnapshotsPatched != 4 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if repo.profiles[testUserID].SyncedAt != nil {
+		t.Fatalf("expected profile left unsynced without a source")
+	}
+}
@@ -0,0 +1,11 @@
+package views
+
+import "errors"
+
+var (
+	ErrViewNotFound      = errors.New("saved view not found")
+	ErrInvalidName       = errors.New("invalid saved view name")
+	ErrInvalidEntityType = errors.New("invalid saved view entity type")
+	ErrInvalidFilters    = errors.New("invalid saved view filters")
+	ErrTooManyViews      = errors.New("too many saved views")
+)
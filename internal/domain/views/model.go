@@ -0,0 +1,57 @@
+package views
+
+import "time"
+
+// EntityType names the list endpoint a saved view runs against.
+type EntityType string
+
+const (
+	EntityExpenses  EntityType = "expenses"
+	EntityTodoLists EntityType = "todo_lists"
+	EntityTodoItems EntityType = "todo_items"
+	EntityWorkouts  EntityType = "workouts"
+)
+
+const (
+	MaxNameLength   = 100
+	MaxViewsPerUser = 50
+	maxFilterValue  = 500
+)
+
+// filterKeys lists the query parameters each list endpoint accepts. Saved
+// filters are limited to these so a view cannot smuggle anything else into
+// the request it replays.
+var filterKeys = map[EntityType][]string{
+	EntityExpenses:  {"from", "to", "currency", "category_id", "category_ids", "archived", "limit", "offset"},
+	EntityTodoLists: {"q", "include_items", "items_archived", "limit", "offset"},
+	EntityTodoItems: {"list_id", "archived", "labels"},
+	EntityWorkouts:  {"from", "to", "scope", "labels", "limit", "offset"},
+}
+
+// SavedView is a named set of list filters a user keeps for reuse. Filters
+// hold query parameter values; from and to may also be relative, like -30d,
+// and are resolved when the view is run.
+type SavedView struct {
+	ID         string     `gorm:"type:uuid;primaryKey"`
+	UserID     string     `gorm:"type:uuid;not null"`
+	Name       string     `gorm:"not null"`
+	EntityType EntityType `gorm:"not null"`
+	Filters    []byte     `gorm:"type:jsonb;not null"`
+	CreatedAt  time.Time  `gorm:"not null"`
+	UpdatedAt  time.Time  `gorm:"not null"`
+}
+
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+type CreateInput struct {
+	Name       string
+	EntityType EntityType
+	Filters    map[string]string
+}
+
+type UpdateInput struct {
+	Name    *string
+	Filters map[string]string
+}
@@ -0,0 +1,14 @@
+package views
+
+import "context"
+
+type Repository interface {
+	CreateView(ctx context.Context, view *SavedView) error
+	UpdateView(ctx context.Context, view *SavedView) error
+	GetView(ctx context.Context, userID, id string) (*SavedView, error)
+	// ListViews returns the user's views, all of them when entityType is
+	// empty.
+	ListViews(ctx context.Context, userID string, entityType EntityType) ([]SavedView, error)
+	CountViews(ctx context.Context, userID string) (int64, error)
+	DeleteView(ctx context.Context, userID, id string) error
+}
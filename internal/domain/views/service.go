@@ -0,0 +1,222 @@
+package views
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+var relativeDateRegex = regexp.MustCompile(`^[+-][0-9]{1,4}d$`)
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *Service) CreateView(ctx context.Context, userID string, input CreateInput) (*SavedView, error) {
+	ctx, span := tracing.Start(ctx, "views.CreateView")
+	defer span.End()
+
+	name, err := normalizeName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := filterKeys[input.EntityType]; !ok {
+		return nil, ErrInvalidEntityType
+	}
+	filters, err := encodeFilters(input.EntityType, input.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountViews(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxViewsPerUser {
+		return nil, ErrTooManyViews
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	view := SavedView{
+		ID:         newID,
+		UserID:     userID,
+		Name:       name,
+		EntityType: input.EntityType,
+		Filters:    filters,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repo.CreateView(ctx, &view); err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// UpdateView renames a view or replaces its filters; the entity type is
+// fixed once created.
+func (s *Service) UpdateView(ctx context.Context, userID, id string, input UpdateInput) (*SavedView, error) {
+	ctx, span := tracing.Start(ctx, "views.UpdateView")
+	defer span.End()
+
+	view, err := s.repo.GetView(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		name, err := normalizeName(*input.Name)
+		if err != nil {
+			return nil, err
+		}
+		view.Name = name
+	}
+	if input.Filters != nil {
+		filters, err := encodeFilters(view.EntityType, input.Filters)
+		if err != nil {
+			return nil, err
+		}
+		view.Filters = filters
+	}
+	view.UpdatedAt = s.now().UTC()
+
+	if err := s.repo.UpdateView(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+func (s *Service) GetView(ctx context.Context, userID, id string) (*SavedView, error) {
+	ctx, span := tracing.Start(ctx, "views.GetView")
+	defer span.End()
+
+	return s.repo.GetView(ctx, userID, id)
+}
+
+func (s *Service) ListViews(ctx context.Context, userID string, entityType EntityType) ([]SavedView, error) {
+	ctx, span := tracing.Start(ctx, "views.ListViews")
+	defer span.End()
+
+	if entityType != "" {
+		if _, ok := filterKeys[entityType]; !ok {
+			return nil, ErrInvalidEntityType
+		}
+	}
+	return s.repo.ListViews(ctx, userID, entityType)
+}
+
+func (s *Service) DeleteView(ctx context.Context, userID, id string) error {
+	ctx, span := tracing.Start(ctx, "views.DeleteView")
+	defer span.End()
+
+	return s.repo.DeleteView(ctx, userID, id)
+}
+
+// Filters returns the stored filters of a view as saved.
+func Filters(view SavedView) (map[string]string, error) {
+	filters := map[string]string{}
+	if len(view.Filters) == 0 {
+		return filters, nil
+	}
+	if err := json.Unmarshal(view.Filters, &filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// ResolveFilters returns the query parameters to run a view with, relative
+// from and to dates turned into dates as of today (UTC).
+func (s *Service) ResolveFilters(view SavedView) (map[string]string, error) {
+	filters, err := Filters(view)
+	if err != nil {
+		return nil, err
+	}
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	for _, key := range []string{"from", "to"} {
+		value, ok := filters[key]
+		if !ok {
+			continue
+		}
+		if date, relative := resolveDate(value, today); relative {
+			filters[key] = date.Format("2006-01-02")
+		}
+	}
+	return filters, nil
+}
+
+func normalizeName(value string) (string, error) {
+	name := strings.TrimSpace(value)
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+// encodeFilters checks filters against what the entity's list endpoint
+// accepts and returns them as stored. Empty values are dropped.
+func encodeFilters(entityType EntityType, filters map[string]string) ([]byte, error) {
+	allowed := make(map[string]bool, len(filterKeys[entityType]))
+	for _, key := range filterKeys[entityType] {
+		allowed[key] = true
+	}
+
+	cleaned := make(map[string]string, len(filters))
+	for key, value := range filters {
+		if !allowed[key] {
+			return nil, fmt.Errorf("%w: unknown filter %q", ErrInvalidFilters, key)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if utf8.RuneCountInString(value) > maxFilterValue {
+			return nil, fmt.Errorf("%w: %s is too long", ErrInvalidFilters, key)
+		}
+		if key == "from" || key == "to" {
+			if _, relative := resolveDate(value, time.Time{}); !relative {
+				if _, err := time.Parse("2006-01-02", value); err != nil {
+					return nil, fmt.Errorf("%w: %s must be a date or a relative day offset", ErrInvalidFilters, key)
+				}
+			}
+		}
+		cleaned[key] = value
+	}
+	if entityType == EntityTodoItems && cleaned["list_id"] == "" {
+		return nil, fmt.Errorf("%w: list_id is required", ErrInvalidFilters)
+	}
+	return json.Marshal(cleaned)
+}
+
+// resolveDate reads "today" or a day offset like -30d relative to today.
+func resolveDate(value string, today time.Time) (time.Time, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "today" {
+		return today, true
+	}
+	if !relativeDateRegex.MatchString(value) {
+		return time.Time{}, false
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return today.AddDate(0, 0, days), true
+}
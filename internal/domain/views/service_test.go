@@ -0,0 +1,141 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testUserID = "22222222-2222-2222-2222-222222222222"
+
+type fakeViewsRepo struct {
+	Repository
+	views map[string]SavedView
+	count int64
+}
+
+func newFakeViewsRepo() *fakeViewsRepo {
+	return &fakeViewsRepo{views: make(map[string]SavedView)}
+}
+
+func (r *fakeViewsRepo) CreateView(_ context.Context, view *SavedView) error {
+	r.views[view.ID] = *view
+	return nil
+}
+
+func (r *fakeViewsRepo) UpdateView(_ context.Context, view *SavedView) error {
+	r.views[view.ID] = *view
+	return nil
+}
+
+func (r *fakeViewsRepo) GetView(_ context.Context, userID, id string) (*SavedView, error) {
+	view, ok := r.views[id]
+	if !ok || view.UserID != userID {
+		return nil, ErrViewNotFound
+	}
+	return &view, nil
+}
+
+func (r *fakeViewsRepo) CountViews(_ context.Context, _ string) (int64, error) {
+	return r.count + int64(len(r.views)), nil
+}
+
+func newTestService(repo Repository) *Service {
+	service := NewService(repo)
+	service.now = func() time.Time {
+		return time.Date(2026, time.March, 31, 18, 0, 0, 0, time.UTC)
+	}
+	return service
+}
+
+func TestResolveFiltersTurnsRelativeDatesIntoDates(t *testing.T) {
+	service := newTestService(newFakeViewsRepo())
+
+	view, err := service.CreateView(context.Background(), testUserID, CreateInput{
+		Name:       "Groceries last 30 days",
+		EntityType: EntityExpenses,
+		Filters:    map[string]string{"from": "-30d", "to": "today", "category_id": "c1", "currency": " "},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	filters, err := service.ResolveFilters(*view)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filters["from"] != "2026-03-01" || filters["to"] != "2026-03-31" || filters["category_id"] != "c1" {
+		t.Fatalf("filters = %v", filters)
+	}
+	if _, ok := filters["currency"]; ok {
+		t.Fatalf("empty filter was kept: %v", filters)
+	}
+
+	saved, err := Filters(*view)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved["from"] != "-30d" {
+		t.Fatalf("stored from = %q, want the relative value", saved["from"])
+	}
+}
+
+func TestCreateViewRejectsFiltersOfOtherEndpoints(t *testing.T) {
+	service := newTestService(newFakeViewsRepo())
+
+	cases := []CreateInput{
+		{Name: "Bad key", EntityType: EntityWorkouts, Filters: map[string]string{"category_id": "c1"}},
+		{Name: "Bad date", EntityType: EntityExpenses, Filters: map[string]string{"from": "last month"}},
+		{Name: "No list", EntityType: EntityTodoItems, Filters: map[string]string{"archived": "all"}},
+	}
+	for _, input := range cases {
+		if _, err := service.CreateView(context.Background(), testUserID, input); !errors.Is(err, ErrInvalidFilters) {
+			t.Fatalf("%s: error = %v, want ErrInvalidFilters", input.Name, err)
+		}
+	}
+}
+
+func TestCreateViewChecksNameTypeAndLimit(t *testing.T) {
+	repo := newFakeViewsRepo()
+	service := newTestService(repo)
+
+	if _, err := service.CreateView(context.Background(), testUserID, CreateInput{Name: " ", EntityType: EntityExpenses}); !errors.Is(err, ErrInvalidName) {
+		t.Fatalf("error = %v, want ErrInvalidName", err)
+	}
+	if _, err := service.CreateView(context.Background(), testUserID, CreateInput{Name: "Pets", EntityType: "pets"}); !errors.Is(err, ErrInvalidEntityType) {
+		t.Fatalf("error = %v, want ErrInvalidEntityType", err)
+	}
+
+	repo.count = MaxViewsPerUser
+	if _, err := service.CreateView(context.Background(), testUserID, CreateInput{Name: "One more", EntityType: EntityTodoLists}); !errors.Is(err, ErrTooManyViews) {
+		t.Fatalf("error = %v, want ErrTooManyViews", err)
+	}
+}
+
+func TestUpdateViewKeepsFiltersWhenOnlyRenamed(t *testing.T) {
+	service := newTestService(newFakeViewsRepo())
+
+	view, err := service.CreateView(context.Background(), testUserID, CreateInput{
+		Name:       "Open todos",
+		EntityType: EntityTodoLists,
+		Filters:    map[string]string{"include_items": "true"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name := "My open todos"
+	updated, err := service.UpdateView(context.Background(), testUserID, view.ID, UpdateInput{Name: &name})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	filters, _ := Filters(*updated)
+	if updated.Name != name || filters["include_items"] != "true" {
+		t.Fatalf("updated = %+v, filters = %v", updated, filters)
+	}
+
+	if _, err := service.UpdateView(context.Background(), "someone-else", view.ID, UpdateInput{Name: &name}); !errors.Is(err, ErrViewNotFound) {
+		t.Fatalf("error = %v, want ErrViewNotFound", err)
+	}
+}
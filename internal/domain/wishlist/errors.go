@@ -0,0 +1,13 @@
+package wishlist
+
+import "errors"
+
+var (
+	ErrItemNotFound       = errors.New("wishlist item not found")
+	ErrMemberNotFound     = errors.New("wishlist member not found")
+	ErrTitleRequired      = errors.New("title is required")
+	ErrNotItemOwner       = errors.New("not wishlist item owner")
+	ErrCannotClaimOwnItem = errors.New("cannot claim own wishlist item")
+	ErrItemAlreadyClaimed = errors.New("wishlist item already claimed")
+	ErrNotClaimer         = errors.New("wishlist item claimed by another member")
+)
@@ -0,0 +1,37 @@
+package wishlist
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Item struct {
+	ID          string `gorm:"type:uuid;primaryKey"`
+	FamilyID    string `gorm:"type:uuid;index;not null"`
+	OwnerID     string `gorm:"type:uuid;not null"`
+	Title       string `gorm:"not null"`
+	URL         *string
+	Price       *float64
+	Currency    *string `gorm:"size:3"`
+	Note        *string
+	ClaimedByID *string `gorm:"type:uuid"`
+	ClaimedAt   *time.Time
+	CreatedAt   time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (Item) TableName() string {
+	return "wishlist_items"
+}
+
+// ItemInput describes the editable fields of a wishlist item. Updates replace
+// all of them.
+type ItemInput struct {
+	Title    string
+	URL      *string
+	Price    *float64
+	Currency *string
+	Note     *string
+}
@@ -0,0 +1,16 @@
+package wishlist
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	ListItemsByOwner(ctx context.Context, familyID, ownerID string) ([]Item, error)
+	GetItem(ctx context.Context, familyID, itemID string) (*Item, error)
+	CreateItem(ctx context.Context, item *Item) error
+	UpdateItem(ctx context.Context, item *Item) error
+	DeleteItem(ctx context.Context, familyID, itemID string) (bool, error)
+	ClaimItem(ctx context.Context, itemID, userID string, claimedAt time.Time) (bool, error)
+	UnclaimItem(ctx context.Context, itemID, userID string) (bool, error)
+}
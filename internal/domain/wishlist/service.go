@@ -0,0 +1,260 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/tracing"
+)
+
+type FamilyProvider interface {
+	GetFamilyByUser(ctx context.Context, userID string) (*familydomain.Family, error)
+}
+
+// Service manages member wishlists. Claims are a surprise for the wishlist
+// owner: every read made by the owner has claim details stripped, and the
+// owner can never claim their own items.
+type Service struct {
+	repo     Repository
+	families FamilyProvider
+	now      func() time.Time
+}
+
+func NewService(repo Repository, families FamilyProvider) *Service {
+	return &Service{
+		repo:     repo,
+		families: families,
+		now:      time.Now,
+	}
+}
+
+// ListItems returns the wishlist of ownerID as seen by viewerID. Both users
+// must belong to the same family.
+func (s *Service) ListItems(ctx context.Context, viewerID, ownerID string) ([]Item, error) {
+	ctx, span := tracing.Start(ctx, "wishlist.ListItems")
+	defer span.End()
+
+	family, err := s.families.GetFamilyByUser(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != viewerID {
+		if err := s.ensureMember(ctx, family.ID, ownerID); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := s.repo.ListItemsByOwner(ctx, family.ID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i] = visibleTo(items[i], viewerID)
+	}
+	return items, nil
+}
+
+func (s *Service) CreateItem(ctx context.Context, userID string, input ItemInput) (*Item, error) {
+	ctx, span := tracing.Start(ctx, "wishlist.CreateItem")
+	defer span.End()
+
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, ErrTitleRequired
+	}
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+
+	item := Item{
+		ID:       newID,
+		FamilyID: family.ID,
+		OwnerID:  userID,
+	}
+	applyInput(&item, title, input)
+	if err := s.repo.CreateItem(ctx, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s *Service) UpdateItem(ctx context.Context, userID, itemID string, input ItemInput) (*Item, error) {
+	ctx, span := tracing.Start(ctx, "wishlist.UpdateItem")
+	defer span.End()
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, ErrTitleRequired
+	}
+
+	item, err := s.ownedItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyInput(item, title, input)
+	if err := s.repo.UpdateItem(ctx, item); err != nil {
+		return nil, err
+	}
+
+	result := visibleTo(*item, userID)
+	return &result, nil
+}
+
+func (s *Service) DeleteItem(ctx context.Context, userID, itemID string) error {
+	ctx, span := tracing.Start(ctx, "wishlist.DeleteItem")
+	defer span.End()
+
+	item, err := s.ownedItem(ctx, userID, itemID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteItem(ctx, item.FamilyID, item.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+func (s *Service) ClaimItem(ctx context.Context, userID, itemID string) (*Item, error) {
+	ctx, span := tracing.Start(ctx, "wishlist.ClaimItem")
+	defer span.End()
+
+	item, err := s.familyItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OwnerID == userID {
+		return nil, ErrCannotClaimOwnItem
+	}
+	if item.ClaimedByID != nil {
+		if *item.ClaimedByID == userID {
+			return item, nil
+		}
+		return nil, ErrItemAlreadyClaimed
+	}
+
+	claimedAt := s.now().UTC()
+	claimed, err := s.repo.ClaimItem(ctx, item.ID, userID, claimedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrItemAlreadyClaimed
+	}
+
+	item.ClaimedByID = &userID
+	item.ClaimedAt = &claimedAt
+	return item, nil
+}
+
+func (s *Service) UnclaimItem(ctx context.Context, userID, itemID string) (*Item, error) {
+	ctx, span := tracing.Start(ctx, "wishlist.UnclaimItem")
+	defer span.End()
+
+	item, err := s.familyItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OwnerID == userID {
+		return nil, ErrCannotClaimOwnItem
+	}
+	if item.ClaimedByID == nil {
+		return item, nil
+	}
+	if *item.ClaimedByID != userID {
+		return nil, ErrNotClaimer
+	}
+
+	released, err := s.repo.UnclaimItem(ctx, item.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !released {
+		return nil, ErrNotClaimer
+	}
+
+	item.ClaimedByID = nil
+	item.ClaimedAt = nil
+	return item, nil
+}
+
+func (s *Service) familyItem(ctx context.Context, userID, itemID string) (*Item, error) {
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetItem(ctx, family.ID, itemID)
+}
+
+func (s *Service) ownedItem(ctx context.Context, userID, itemID string) (*Item, error) {
+	item, err := s.familyItem(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OwnerID != userID {
+		return nil, ErrNotItemOwner
+	}
+	return item, nil
+}
+
+func (s *Service) ensureMember(ctx context.Context, familyID, userID string) error {
+	family, err := s.families.GetFamilyByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, familydomain.ErrFamilyNotFound) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	if family.ID != familyID {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// visibleTo hides claim details from the owner of the item.
+func visibleTo(item Item, viewerID string) Item {
+	if item.OwnerID == viewerID {
+		item.ClaimedByID = nil
+		item.ClaimedAt = nil
+	}
+	return item
+}
+
+func applyInput(item *Item, title string, input ItemInput) {
+	item.Title = title
+	item.URL = trimmedOrNil(input.URL)
+	item.Price = input.Price
+	item.Currency = trimmedOrNil(input.Currency)
+	if item.Currency != nil {
+		upper := strings.ToUpper(*item.Currency)
+		item.Currency = &upper
+	}
+	item.Note = trimmedOrNil(input.Note)
+}
+
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
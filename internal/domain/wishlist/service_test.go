@@ -0,0 +1,202 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+)
+
+const (
+	testFamilyID   = "11111111-1111-1111-1111-111111111111"
+	testOwnerID    = "22222222-2222-2222-2222-222222222222"
+	testMemberID   = "33333333-3333-3333-3333-333333333333"
+	testOtherID    = "44444444-4444-4444-4444-444444444444"
+	testOutsiderID = "55555555-5555-5555-5555-555555555555"
+)
+
+type fakeFamilyProvider struct{}
+
+func (fakeFamilyProvider) GetFamilyByUser(_ context.Context, userID string) (*familydomain.Family, error) {
+	switch userID {
+	case testOwnerID, testMemberID, testOtherID:
+		return &familydomain.Family{ID: testFamilyID, OwnerID: testOwnerID}, nil
+	case testOutsiderID:
+		return &familydomain.Family{ID: "99999999-9999-9999-9999-999999999999", OwnerID: testOutsiderID}, nil
+	}
+	return nil, familydomain.ErrFamilyNotFound
+}
+
+type fakeWishlistRepo struct {
+	items map[string]*Item
+}
+
+func newFakeWishlistRepo() *fakeWishlistRepo {
+	return &fakeWishlistRepo{items: make(map[string]*Item)}
+}
+
+func (r *fakeWishlistRepo) ListItemsByOwner(_ context.Context, familyID, ownerID string) ([]Item, error) {
+	result := make([]Item, 0)
+	for _, item := range r.items {
+		if item.FamilyID == familyID && item.OwnerID == ownerID {
+			result = append(result, *item)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeWishlistRepo) GetItem(_ context.Context, familyID, itemID string) (*Item, error) {
+	item, ok := r.items[itemID]
+	if !ok || item.FamilyID != familyID {
+		return nil, ErrItemNotFound
+	}
+	cloned := *item
+	return &cloned, nil
+}
+
+func (r *fakeWishlistRepo) CreateItem(_ context.Context, item *Item) error {
+	cloned := *item
+	r.items[item.ID] = &cloned
+	return nil
+}
+
+func (r *fakeWishlistRepo) UpdateItem(_ context.Context, item *Item) error {
+	stored, ok := r.items[item.ID]
+	if !ok {
+		return ErrItemNotFound
+	}
+	stored.Title = item.Title
+	stored.URL = item.URL
+	stored.Price = item.Price
+	stored.Currency = item.Currency
+	stored.Note = item.Note
+	return nil
+}
+
+func (r *fakeWishlistRepo) DeleteItem(_ context.Context, familyID, itemID string) (bool, error) {
+	item, ok := r.items[itemID]
+	if !ok || item.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.items, itemID)
+	return true, nil
+}
+
+func (r *fakeWishlistRepo) ClaimItem(_ context.Context, itemID, userID string, claimedAt time.Time) (bool, error) {
+	item, ok := r.items[itemID]
+	if !ok || item.ClaimedByID != nil {
+		return false, nil
+	}
+	item.ClaimedByID = &userID
+	item.ClaimedAt = &claimedAt
+	return true, nil
+}
+
+func (r *fakeWishlistRepo) UnclaimItem(_ context.Context, itemID, userID string) (bool, error) {
+	item, ok := r.items[itemID]
+	if !ok || item.ClaimedByID == nil || *item.ClaimedByID != userID {
+		return false, nil
+	}
+	item.ClaimedByID = nil
+	item.ClaimedAt = nil
+	return true, nil
+}
+
+func createTestItem(t *testing.T, service *Service) *Item {
+	t.Helper()
+	item, err := service.CreateItem(context.Background(), testOwnerID, ItemInput{Title: " Headphones "})
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	return item
+}
+
+func TestClaimIsHiddenFromOwner(t *testing.T) {
+	service := NewService(newFakeWishlistRepo(), fakeFamilyProvider{})
+	item := createTestItem(t, service)
+
+	if _, err := service.ClaimItem(context.Background(), testMemberID, item.ID); err != nil {
+		t.Fatalf("claim item: %v", err)
+	}
+
+	ownerView, err := service.ListItems(context.Background(), testOwnerID, testOwnerID)
+	if err != nil {
+		t.Fatalf("list as owner: %v", err)
+	}
+	if len(ownerView) != 1 || ownerView[0].ClaimedByID != nil || ownerView[0].ClaimedAt != nil {
+		t.Fatalf("expected claim to be hidden from owner, got %#v", ownerView)
+	}
+
+	memberView, err := service.ListItems(context.Background(), testOtherID, testOwnerID)
+	if err != nil {
+		t.Fatalf("list as member: %v", err)
+	}
+	if len(memberView) != 1 || memberView[0].ClaimedByID == nil || *memberView[0].ClaimedByID != testMemberID {
+		t.Fatalf("expected claim to be visible to other members, got %#v", memberView)
+	}
+
+	updated, err := service.UpdateItem(context.Background(), testOwnerID, item.ID, ItemInput{Title: "Wireless headphones"})
+	if err != nil {
+		t.Fatalf("update item: %v", err)
+	}
+	if updated.ClaimedByID != nil {
+		t.Fatalf("expected update response to hide claim from owner")
+	}
+}
+
+func TestOwnerCannotClaimOwnItem(t *testing.T) {
+	service := NewService(newFakeWishlistRepo(), fakeFamilyProvider{})
+	item := createTestItem(t, service)
+
+	if _, err := service.ClaimItem(context.Background(), testOwnerID, item.ID); !errors.Is(err, ErrCannotClaimOwnItem) {
+		t.Fatalf("expected ErrCannotClaimOwnItem, got %v", err)
+	}
+}
+
+func TestClaimConflictsAndRelease(t *testing.T) {
+	service := NewService(newFakeWishlistRepo(), fakeFamilyProvider{})
+	item := createTestItem(t, service)
+
+	if _, err := service.ClaimItem(context.Background(), testMemberID, item.ID); err != nil {
+		t.Fatalf("claim item: %v", err)
+	}
+	if _, err := service.ClaimItem(context.Background(), testOtherID, item.ID); !errors.Is(err, ErrItemAlreadyClaimed) {
+		t.Fatalf("expected ErrItemAlreadyClaimed, got %v", err)
+	}
+	if _, err := service.UnclaimItem(context.Background(), testOtherID, item.ID); !errors.Is(err, ErrNotClaimer) {
+		t.Fatalf("expected ErrNotClaimer, got %v", err)
+	}
+
+	released, err := service.UnclaimItem(context.Background(), testMemberID, item.ID)
+	if err != nil {
+		t.Fatalf("unclaim item: %v", err)
+	}
+	if released.ClaimedByID != nil {
+		t.Fatalf("expected claim to be released")
+	}
+	if _, err := service.ClaimItem(context.Background(), testOtherID, item.ID); err != nil {
+		t.Fatalf("claim released item: %v", err)
+	}
+}
+
+func TestOnlyOwnerCanEditItem(t *testing.T) {
+	service := NewService(newFakeWishlistRepo(), fakeFamilyProvider{})
+	item := createTestItem(t, service)
+
+	if _, err := service.UpdateItem(context.Background(), testMemberID, item.ID, ItemInput{Title: "Other"}); !errors.Is(err, ErrNotItemOwner) {
+		t.Fatalf("expected ErrNotItemOwner on update, got %v", err)
+	}
+	if err := service.DeleteItem(context.Background(), testMemberID, item.ID); !errors.Is(err, ErrNotItemOwner) {
+		t.Fatalf("expected ErrNotItemOwner on delete, got %v", err)
+	}
+}
+
+func TestListItemsRejectsOtherFamilies(t *testing.T) {
+	service := NewService(newFakeWishlistRepo(), fakeFamilyProvider{})
+
+	if _, err := service.ListItems(context.Background(), testMemberID, testOutsiderID); !errors.Is(err, ErrMemberNotFound) {
+		t.Fatalf("expected ErrMemberNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+package yearreview
+
+import "errors"
+
+var ErrInvalidYear = errors.New("invalid year")
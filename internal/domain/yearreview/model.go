@@ -0,0 +1,74 @@
+package yearreview
+
+import "time"
+
+// Review is a family's wrap-up of one calendar year (UTC). Each highlight is
+// nil when the year has nothing to show for it.
+type Review struct {
+	Year         int
+	BaseCurrency string
+	// TotalSpent sums the year's expenses in BaseCurrency; expenses without
+	// a conversion are left out and counted in Unconverted.
+	TotalSpent    float64
+	Expenses      int64
+	Unconverted   int64
+	TopMerchant   *Merchant
+	TopTodoMember *TodoMember
+	HeaviestLift  *Lift
+	LongestStreak *Streak
+	GeneratedAt   time.Time
+}
+
+// Merchant is where the family spent the most in the base currency. Titles
+// are grouped as expense categorization groups them, ignoring case, digits
+// and card boilerplate; Name is the first title seen.
+type Merchant struct {
+	Name         string
+	Expenses     int64
+	AmountInBase float64
+}
+
+// TodoMember is the member who completed the most todo items.
+type TodoMember struct {
+	UserID         string
+	Name           *string
+	ItemsCompleted int64
+}
+
+// Lift is the heaviest set of a workout shared with the family.
+type Lift struct {
+	UserID   string
+	Exercise string
+	WeightKg float64
+	Reps     int
+	Date     time.Time
+}
+
+// Streak is the longest run of consecutive days one member logged a shared
+// workout.
+type Streak struct {
+	UserID string
+	Days   int
+	From   time.Time
+	To     time.Time
+}
+
+// SpendingTotals sums a year of expenses.
+type SpendingTotals struct {
+	TotalInBase float64
+	Count       int64
+	Unconverted int64
+}
+
+// ExpenseTitle is one expense as merchants are counted from. AmountInBase
+// is nil without a conversion to the base currency.
+type ExpenseTitle struct {
+	Title        string
+	AmountInBase *float64
+}
+
+// WorkoutDay is a day a member logged at least one shared workout.
+type WorkoutDay struct {
+	UserID string
+	Date   time.Time
+}
@@ -0,0 +1,21 @@
+package yearreview
+
+import (
+	"context"
+	"time"
+)
+
+// Repository aggregates a date range, from and to inclusive, across the
+// expenses, todos and gym tables.
+type Repository interface {
+	SpendingTotals(ctx context.Context, familyID, baseCurrency string, from, to time.Time) (SpendingTotals, error)
+	// ListExpenseTitles returns every expense's title with its amount in
+	// baseCurrency. Titles may be encrypted at rest, so merchants are grouped
+	// by the service rather than in SQL.
+	ListExpenseTitles(ctx context.Context, familyID, baseCurrency string, from, to time.Time) ([]ExpenseTitle, error)
+	TopTodoMember(ctx context.Context, familyID string, from, to time.Time) (*TodoMember, error)
+	HeaviestLift(ctx context.Context, familyID string, from, to time.Time) (*Lift, error)
+	// ListWorkoutDays returns the distinct days with a shared workout, ordered
+	// by member and date.
+	ListWorkoutDays(ctx context.Context, familyID string, from, to time.Time) ([]WorkoutDay, error)
+}
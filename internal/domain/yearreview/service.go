@@ -0,0 +1,215 @@
+package yearreview
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	"family-app-go/pkg/money"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	// MinYear is the first year a review can be asked for.
+	MinYear = 2000
+	// DefaultPastYearTTL and DefaultCurrentYearTTL apply when ServiceOptions
+	// leave them unset. A finished year only changes when old records are
+	// edited, so it is kept far longer than the running one.
+	DefaultPastYearTTL    = 24 * time.Hour
+	DefaultCurrentYearTTL = time.Hour
+)
+
+// Service builds the yearly wrap-up and caches it per family, year and
+// currency.
+type Service struct {
+	repo           Repository
+	pastYearTTL    time.Duration
+	currentYearTTL time.Duration
+	cache          reviewCache
+	now            func() time.Time
+}
+
+type ServiceOptions struct {
+	PastYearTTL    time.Duration
+	CurrentYearTTL time.Duration
+}
+
+func NewService(repo Repository) *Service {
+	return NewServiceWithOptions(repo, ServiceOptions{})
+}
+
+func NewServiceWithOptions(repo Repository, options ServiceOptions) *Service {
+	pastYearTTL := options.PastYearTTL
+	if pastYearTTL <= 0 {
+		pastYearTTL = DefaultPastYearTTL
+	}
+	currentYearTTL := options.CurrentYearTTL
+	if currentYearTTL <= 0 {
+		currentYearTTL = DefaultCurrentYearTTL
+	}
+	return &Service{
+		repo:           repo,
+		pastYearTTL:    pastYearTTL,
+		currentYearTTL: currentYearTTL,
+		cache:          reviewCache{items: make(map[string]cachedReview)},
+		now:            time.Now,
+	}
+}
+
+// YearInReview returns the family's review of year and when it stops being
+// served from cache. The running year is reviewed up to today; later years
+// are ErrInvalidYear.
+func (s *Service) YearInReview(ctx context.Context, familyID string, year int, baseCurrency string) (Review, time.Time, error) {
+	ctx, span := tracing.Start(ctx, "yearreview.YearInReview")
+	defer span.End()
+
+	now := s.now().UTC()
+	if year < MinYear || year > now.Year() {
+		return Review{}, time.Time{}, ErrInvalidYear
+	}
+
+	key := familyID + ":" + strconv.Itoa(year) + ":" + baseCurrency
+	if cached, ok := s.cache.get(key, now); ok {
+		return cached.review, cached.expiresAt, nil
+	}
+
+	review, err := s.build(ctx, familyID, year, baseCurrency, now)
+	if err != nil {
+		return Review{}, time.Time{}, err
+	}
+	ttl := s.pastYearTTL
+	if year == now.Year() {
+		ttl = s.currentYearTTL
+	}
+	expiresAt := now.Add(ttl)
+	s.cache.set(key, cachedReview{review: review, expiresAt: expiresAt})
+	return review, expiresAt, nil
+}
+
+func (s *Service) build(ctx context.Context, familyID string, year int, baseCurrency string, now time.Time) (Review, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	totals, err := s.repo.SpendingTotals(ctx, familyID, baseCurrency, from, to)
+	if err != nil {
+		return Review{}, err
+	}
+	titles, err := s.repo.ListExpenseTitles(ctx, familyID, baseCurrency, from, to)
+	if err != nil {
+		return Review{}, err
+	}
+	member, err := s.repo.TopTodoMember(ctx, familyID, from, to)
+	if err != nil {
+		return Review{}, err
+	}
+	lift, err := s.repo.HeaviestLift(ctx, familyID, from, to)
+	if err != nil {
+		return Review{}, err
+	}
+	days, err := s.repo.ListWorkoutDays(ctx, familyID, from, to)
+	if err != nil {
+		return Review{}, err
+	}
+
+	return Review{
+		Year:          year,
+		BaseCurrency:  baseCurrency,
+		TotalSpent:    totals.TotalInBase,
+		Expenses:      totals.Count,
+		Unconverted:   totals.Unconverted,
+		TopMerchant:   topMerchant(titles, baseCurrency),
+		TopTodoMember: member,
+		HeaviestLift:  lift,
+		LongestStreak: longestStreak(days),
+		GeneratedAt:   now,
+	}, nil
+}
+
+// topMerchant sums titles per merchant and returns the one with the highest
+// amount, then the most expenses, then the first seen.
+func topMerchant(titles []ExpenseTitle, baseCurrency string) *Merchant {
+	merchants := make(map[string]*Merchant)
+	var order []string
+	for _, title := range titles {
+		key := expensesdomain.MerchantKey(title.Title)
+		if key == "" {
+			continue
+		}
+		merchant, ok := merchants[key]
+		if !ok {
+			merchant = &Merchant{Name: strings.TrimSpace(title.Title)}
+			merchants[key] = merchant
+			order = append(order, key)
+		}
+		merchant.Expenses++
+		if title.AmountInBase != nil {
+			merchant.AmountInBase += *title.AmountInBase
+		}
+	}
+
+	var best *Merchant
+	for _, key := range order {
+		merchant := merchants[key]
+		if best == nil || merchant.AmountInBase > best.AmountInBase ||
+			(merchant.AmountInBase == best.AmountInBase && merchant.Expenses > best.Expenses) {
+			best = merchant
+		}
+	}
+	if best != nil {
+		best.AmountInBase = money.Round(best.AmountInBase, baseCurrency)
+	}
+	return best
+}
+
+// longestStreak finds the longest run of consecutive days in days, which
+// are ordered by member and date. Ties go to the earlier run.
+func longestStreak(days []WorkoutDay) *Streak {
+	var best, current *Streak
+	for _, day := range days {
+		if current != nil && current.UserID == day.UserID && day.Date.Equal(current.To.AddDate(0, 0, 1)) {
+			current.Days++
+			current.To = day.Date
+		} else {
+			current = &Streak{UserID: day.UserID, Days: 1, From: day.Date, To: day.Date}
+		}
+		if best == nil || current.Days > best.Days || (current.Days == best.Days && current.From.Before(best.From)) {
+			streak := *current
+			best = &streak
+		}
+	}
+	return best
+}
+
+type cachedReview struct {
+	review    Review
+	expiresAt time.Time
+}
+
+type reviewCache struct {
+	mu    sync.Mutex
+	items map[string]cachedReview
+}
+
+func (c *reviewCache) get(key string, now time.Time) (cachedReview, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[key]
+	if !ok {
+		return cachedReview{}, false
+	}
+	if !item.expiresAt.After(now) {
+		delete(c.items, key)
+		return cachedReview{}, false
+	}
+	return item, true
+}
+
+func (c *reviewCache) set(key string, item cachedReview) {
+	c.mu.Lock()
+	c.items[key] = item
+	c.mu.Unlock()
+}
@@ -0,0 +1,133 @@
+package yearreview
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testFamilyID = "11111111-1111-1111-1111-111111111111"
+
+var testNow = time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+type fakeRepo struct {
+	titles []ExpenseTitle
+	days   []WorkoutDay
+	calls  int
+}
+
+func (f *fakeRepo) SpendingTotals(_ context.Context, _, _ string, _, _ time.Time) (SpendingTotals, error) {
+	f.calls++
+	return SpendingTotals{TotalInBase: 120.5, Count: 4, Unconverted: 1}, nil
+}
+
+func (f *fakeRepo) ListExpenseTitles(_ context.Context, _, _ string, _, _ time.Time) ([]ExpenseTitle, error) {
+	return f.titles, nil
+}
+
+func (f *fakeRepo) TopTodoMember(_ context.Context, _ string, _, _ time.Time) (*TodoMember, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) HeaviestLift(_ context.Context, _ string, _, _ time.Time) (*Lift, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) ListWorkoutDays(_ context.Context, _ string, _, _ time.Time) ([]WorkoutDay, error) {
+	return f.days, nil
+}
+
+func newTestService(repo *fakeRepo) *Service {
+	service := NewService(repo)
+	service.now = func() time.Time { return testNow }
+	return service
+}
+
+func amount(value float64) *float64 {
+	return &value
+}
+
+func day(userID string, month time.Month, d int) WorkoutDay {
+	return WorkoutDay{UserID: userID, Date: time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestYearInReviewPicksHighlights(t *testing.T) {
+	repo := &fakeRepo{
+		titles: []ExpenseTitle{
+			{Title: "Bakery", AmountInBase: amount(30)},
+			{Title: " LIDL 1234 ", AmountInBase: amount(20)},
+			{Title: "Card payment Lidl", AmountInBase: amount(15.5)},
+			{Title: "lidl", AmountInBase: nil},
+			{Title: "1234", AmountInBase: amount(99)},
+		},
+		days: []WorkoutDay{
+			day("a", time.March, 1), day("a", time.March, 2),
+			day("a", time.March, 4), day("a", time.March, 5), day("a", time.March, 6),
+			day("b", time.March, 6), day("b", time.March, 7), day("b", time.March, 8),
+		},
+	}
+	service := newTestService(repo)
+
+	review, _, err := service.YearInReview(context.Background(), testFamilyID, 2025, "EUR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if review.TotalSpent != 120.5 || review.Expenses != 4 || review.Unconverted != 1 {
+		t.Fatalf("totals = %+v", review)
+	}
+	if merchant := review.TopMerchant; merchant == nil || merchant.Name != "LIDL 1234" || merchant.Expenses != 3 || merchant.AmountInBase != 35.5 {
+		t.Fatalf("top merchant = %+v", merchant)
+	}
+	if streak := review.LongestStreak; streak == nil || streak.UserID != "a" || streak.Days != 3 || streak.From.Day() != 4 {
+		t.Fatalf("longest streak = %+v", streak)
+	}
+	if review.TopTodoMember != nil || review.HeaviestLift != nil {
+		t.Fatalf("empty highlights should stay nil: %+v", review)
+	}
+}
+
+func TestYearInReviewIsCachedPerYear(t *testing.T) {
+	repo := &fakeRepo{}
+	service := newTestService(repo)
+
+	_, pastExpiry, err := service.YearInReview(context.Background(), testFamilyID, 2025, "EUR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := service.YearInReview(context.Background(), testFamilyID, 2025, "EUR"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 1 {
+		t.Fatalf("repository queried %d times, want the cached review", repo.calls)
+	}
+	if !pastExpiry.Equal(testNow.Add(DefaultPastYearTTL)) {
+		t.Fatalf("past year cached until %v", pastExpiry)
+	}
+
+	_, currentExpiry, err := service.YearInReview(context.Background(), testFamilyID, 2026, "EUR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 2 || !currentExpiry.Equal(testNow.Add(DefaultCurrentYearTTL)) {
+		t.Fatalf("current year: %d calls, cached until %v", repo.calls, currentExpiry)
+	}
+
+	service.now = func() time.Time { return testNow.Add(DefaultPastYearTTL) }
+	if _, _, err := service.YearInReview(context.Background(), testFamilyID, 2025, "EUR"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.calls != 3 {
+		t.Fatalf("expired review was not rebuilt")
+	}
+}
+
+func TestYearInReviewRejectsFutureYears(t *testing.T) {
+	service := newTestService(&fakeRepo{})
+
+	for _, year := range []int{1999, 2027} {
+		if _, _, err := service.YearInReview(context.Background(), testFamilyID, year, "EUR"); !errors.Is(err, ErrInvalidYear) {
+			t.Fatalf("year %d: expected ErrInvalidYear, got %v", year, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobExists        = errors.New("job already registered")
+	ErrJobRunning       = errors.New("job is already running")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+	ErrRunnerStarted    = errors.New("runner already started")
+	errMissingJobRunner = errors.New("job has no run function")
+)
+
+// Job is a named unit of background work. Jobs without a Schedule only run
+// when triggered through RunNow.
+type Job struct {
+	Name        string
+	Description string
+	Schedule    Schedule
+	// Timeout bounds a single attempt. Zero uses the runner default.
+	Timeout time.Duration
+	// MaxAttempts is how many times a failing run is tried before it is
+	// recorded as failed. Zero uses the runner default.
+	MaxAttempts int
+	// RunOnStart runs a scheduled job once when the runner starts, before
+	// waiting for the first scheduled time.
+	RunOnStart bool
+	// Run does the work. Its result is stored as JSON in the run history.
+	Run func(ctx context.Context) (interface{}, error)
+}
+
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule"
+	TriggerManual   Trigger = "manual"
+)
+
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusSucceeded RunStatus = "succeeded"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// Run is one execution of a job, across all of its attempts.
+type Run struct {
+	ID         string    `gorm:"type:uuid;primaryKey"`
+	JobName    string    `gorm:"not null"`
+	Trigger    Trigger   `gorm:"not null"`
+	Status     RunStatus `gorm:"not null"`
+	Attempts   int       `gorm:"not null"`
+	Instance   string    `gorm:"not null"`
+	Error      *string   `gorm:"type:text"`
+	Result     []byte    `gorm:"type:jsonb"`
+	StartedAt  time.Time `gorm:"not null"`
+	FinishedAt *time.Time
+}
+
+func (Run) TableName() string {
+	return "job_runs"
+}
+
+type RunFilter struct {
+	JobName string
+	Status  RunStatus
+	Limit   int
+}
+
+const (
+	DefaultRunsLimit = 50
+	MaxRunsLimit     = 200
+)
+
+// Store persists run history.
+type Store interface {
+	CreateRun(ctx context.Context, run *Run) error
+	FinishRun(ctx context.Context, run *Run) error
+	ListRuns(ctx context.Context, filter RunFilter) ([]Run, error)
+}
+
+// Locker serializes a job across instances. TryLock reports false without
+// waiting when another holder has the lock; release must be called once the
+// job is done.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (release func(), acquired bool, err error)
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// localLocker only serializes jobs within this process.
+type localLocker struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func newLocalLocker() *localLocker {
+	return &localLocker{running: make(map[string]bool)}
+}
+
+func (l *localLocker) TryLock(_ context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running[name] {
+		return nil, false, nil
+	}
+	l.running[name] = true
+	return func() {
+		l.mu.Lock()
+		delete(l.running, name)
+		l.mu.Unlock()
+	}, true, nil
+}
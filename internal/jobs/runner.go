@@ -0,0 +1,354 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/logger"
+	"family-app-go/pkg/tracing"
+)
+
+const (
+	defaultTimeout      = 30 * time.Minute
+	defaultMaxAttempts  = 3
+	defaultRetryBackoff = 30 * time.Second
+	defaultMaxBackoff   = 10 * time.Minute
+)
+
+// Runner schedules registered jobs, retries failed attempts with exponential
+// backoff and records every run. Each run holds the job's lock, so with a
+// shared Locker only one instance runs a job at a time.
+type Runner struct {
+	store        Store
+	locker       Locker
+	log          logger.Logger
+	instance     string
+	timeout      time.Duration
+	maxAttempts  int
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	now          func() time.Time
+	sleep        func(ctx context.Context, d time.Duration) bool
+
+	mu      sync.Mutex
+	jobs    []Job
+	started bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type Options struct {
+	Store Store
+	// Locker defaults to an in-process lock, which only protects a single
+	// instance.
+	Locker Locker
+	Logger logger.Logger
+	// Instance names this process in the run history. Defaults to the
+	// hostname.
+	Instance     string
+	Timeout      time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+func NewRunner(options Options) *Runner {
+	runner := &Runner{
+		store:        options.Store,
+		locker:       options.Locker,
+		log:          options.Logger,
+		instance:     options.Instance,
+		timeout:      options.Timeout,
+		maxAttempts:  options.MaxAttempts,
+		retryBackoff: options.RetryBackoff,
+		maxBackoff:   options.MaxBackoff,
+		now:          time.Now,
+		sleep:        sleepContext,
+	}
+	if runner.locker == nil {
+		runner.locker = newLocalLocker()
+	}
+	if runner.log == nil {
+		runner.log = logger.New(io.Discard, slog.LevelError, "text")
+	}
+	if runner.instance == "" {
+		runner.instance, _ = os.Hostname()
+	}
+	if runner.timeout <= 0 {
+		runner.timeout = defaultTimeout
+	}
+	if runner.maxAttempts <= 0 {
+		runner.maxAttempts = defaultMaxAttempts
+	}
+	if runner.retryBackoff <= 0 {
+		runner.retryBackoff = defaultRetryBackoff
+	}
+	if runner.maxBackoff <= 0 {
+		runner.maxBackoff = defaultMaxBackoff
+	}
+	return runner
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (r *Runner) Register(job Job) error {
+	job.Name = strings.TrimSpace(job.Name)
+	if job.Name == "" {
+		return errors.New("job name is required")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("%s: %w", job.Name, errMissingJobRunner)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.started {
+		return ErrRunnerStarted
+	}
+	for _, existing := range r.jobs {
+		if existing.Name == job.Name {
+			return fmt.Errorf("%s: %w", job.Name, ErrJobExists)
+		}
+	}
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+// Jobs lists registered jobs in registration order.
+func (r *Runner) Jobs() []Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Job(nil), r.jobs...)
+}
+
+// Start launches a scheduling loop per scheduled job.
+func (r *Runner) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.started {
+		return ErrRunnerStarted
+	}
+	r.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	for _, job := range r.jobs {
+		if job.Schedule == nil {
+			continue
+		}
+		r.wg.Add(1)
+		go r.loop(ctx, job)
+	}
+	return nil
+}
+
+// Stop cancels running jobs and waits for their loops to exit or for ctx to
+// expire.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RunNow runs a job synchronously, outside its schedule. It returns
+// ErrJobRunning when the job is already running anywhere. A failed job still
+// returns its run; the error is recorded on it.
+func (r *Runner) RunNow(ctx context.Context, name string) (*Run, error) {
+	job, ok := r.job(strings.TrimSpace(name))
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return r.execute(ctx, job, TriggerManual)
+}
+
+func (r *Runner) ListRuns(ctx context.Context, filter RunFilter) ([]Run, error) {
+	ctx, span := tracing.Start(ctx, "jobs.ListRuns")
+	defer span.End()
+
+	filter.JobName = strings.TrimSpace(filter.JobName)
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultRunsLimit
+	}
+	if filter.Limit > MaxRunsLimit {
+		filter.Limit = MaxRunsLimit
+	}
+	return r.store.ListRuns(ctx, filter)
+}
+
+func (r *Runner) job(name string) (Job, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+	defer r.wg.Done()
+
+	if job.RunOnStart {
+		r.runScheduled(ctx, job)
+	}
+	for {
+		next := job.Schedule.Next(r.now())
+		if next.IsZero() {
+			r.log.Warn("jobs: schedule never fires again", "job", job.Name)
+			return
+		}
+		if !r.sleep(ctx, next.Sub(r.now())) {
+			return
+		}
+		r.runScheduled(ctx, job)
+	}
+}
+
+func (r *Runner) runScheduled(ctx context.Context, job Job) {
+	run, err := r.execute(ctx, job, TriggerSchedule)
+	switch {
+	case errors.Is(err, ErrJobRunning):
+		r.log.Debug("jobs: skipped, running elsewhere", "job", job.Name)
+	case err != nil:
+		r.log.InternalError("jobs: run failed to start", err, "job", job.Name)
+	case run.Status == RunStatusFailed:
+		r.log.Error("jobs: run failed", "job", job.Name, "run_id", run.ID, "attempts", run.Attempts, "err", *run.Error)
+	default:
+		r.log.Info("jobs: run succeeded", "job", job.Name, "run_id", run.ID, "attempts", run.Attempts)
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, job Job, trigger Trigger) (*Run, error) {
+	ctx, span := tracing.Start(ctx, "jobs.Run")
+	defer span.End()
+
+	release, acquired, err := r.locker.TryLock(ctx, job.Name)
+	if err != nil {
+		return nil, fmt.Errorf("lock job %s: %w", job.Name, err)
+	}
+	if !acquired {
+		return nil, ErrJobRunning
+	}
+	defer release()
+
+	newID, err := id.New()
+	if err != nil {
+		return nil, err
+	}
+	run := &Run{
+		ID:        newID,
+		JobName:   job.Name,
+		Trigger:   trigger,
+		Status:    RunStatusRunning,
+		Instance:  r.instance,
+		StartedAt: r.now().UTC(),
+	}
+	if err := r.store.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	result, attempts, runErr := r.attempt(ctx, job)
+	finishedAt := r.now().UTC()
+	run.Attempts = attempts
+	run.FinishedAt = &finishedAt
+	if runErr != nil {
+		message := runErr.Error()
+		run.Status = RunStatusFailed
+		run.Error = &message
+	} else {
+		run.Status = RunStatusSucceeded
+		if result != nil {
+			data, err := json.Marshal(result)
+			if err != nil {
+				r.log.InternalError("jobs: encode result failed", err, "job", job.Name)
+			} else {
+				run.Result = data
+			}
+		}
+	}
+
+	// Record the outcome even when the run was cancelled by shutdown.
+	if err := r.store.FinishRun(context.WithoutCancel(ctx), run); err != nil {
+		r.log.InternalError("jobs: record run failed", err, "job", job.Name, "run_id", run.ID)
+	}
+	return run, nil
+}
+
+func (r *Runner) attempt(ctx context.Context, job Job) (interface{}, int, error) {
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = r.timeout
+	}
+	maxAttempts := job.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = r.maxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		result, err := job.Run(attemptCtx)
+		cancel()
+		if err == nil {
+			return result, attempt, nil
+		}
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			return nil, attempt, err
+		}
+
+		backoff := r.backoff(attempt)
+		r.log.Warn("jobs: attempt failed, retrying", "job", job.Name, "attempt", attempt, "retry_in", backoff.String(), "err", err)
+		if !r.sleep(ctx, backoff) {
+			return nil, attempt, err
+		}
+	}
+}
+
+func (r *Runner) backoff(attempt int) time.Duration {
+	backoff := r.retryBackoff
+	for i := 1; i < attempt && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.maxBackoff {
+		backoff = r.maxBackoff
+	}
+	return backoff
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,183 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeStore struct {
+	mu   sync.Mutex
+	runs map[string]Run
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{runs: make(map[string]Run)}
+}
+
+func (s *fakeStore) CreateRun(_ context.Context, run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[run.ID] = *run
+	return nil
+}
+
+func (s *fakeStore) FinishRun(_ context.Context, run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[run.ID] = *run
+	return nil
+}
+
+func (s *fakeStore) ListRuns(_ context.Context, filter RunFilter) ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var runs []Run
+	for _, run := range s.runs {
+		if filter.JobName == "" || run.JobName == filter.JobName {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func newTestRunner(store Store) (*Runner, *[]time.Duration) {
+	var slept []time.Duration
+	runner := NewRunner(Options{Store: store, Instance: "test", MaxAttempts: 3, RetryBackoff: time.Second})
+	runner.sleep = func(ctx context.Context, d time.Duration) bool {
+		slept = append(slept, d)
+		return ctx.Err() == nil
+	}
+	return runner, &slept
+}
+
+func TestRunNowRetriesWithBackoff(t *testing.T) {
+	store := newFakeStore()
+	runner, slept := newTestRunner(store)
+
+	calls := 0
+	if err := runner.Register(Job{Name: "flaky", Run: func(context.Context) (interface{}, error) {
+		calls++
+		if calls < 3 {
+			return nil, errors.New("temporary")
+		}
+		return map[string]int{"processed": 2}, nil
+	}}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	run, err := runner.RunNow(context.Background(), "flaky")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if run.Status != RunStatusSucceeded || run.Attempts != 3 || run.Trigger != TriggerManual {
+		t.Fatalf("unexpected run %+v", run)
+	}
+	if string(run.Result) != `{"processed":2}` {
+		t.Fatalf("unexpected result %s", run.Result)
+	}
+	if len(*slept) != 2 || (*slept)[0] != time.Second || (*slept)[1] != 2*time.Second {
+		t.Fatalf("expected exponential backoff, got %v", *slept)
+	}
+	if stored := store.runs[run.ID]; stored.Status != RunStatusSucceeded || stored.FinishedAt == nil {
+		t.Fatalf("expected finished run in history, got %+v", stored)
+	}
+}
+
+func TestRunNowRecordsFailure(t *testing.T) {
+	store := newFakeStore()
+	runner, _ := newTestRunner(store)
+	if err := runner.Register(Job{Name: "broken", MaxAttempts: 2, Run: func(context.Context) (interface{}, error) {
+		return nil, errors.New("boom")
+	}}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	run, err := runner.RunNow(context.Background(), "broken")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if run.Status != RunStatusFailed || run.Attempts != 2 || run.Error == nil || *run.Error != "boom" {
+		t.Fatalf("unexpected run %+v", run)
+	}
+}
+
+func TestRunNowSkipsLockedJob(t *testing.T) {
+	runner, _ := newTestRunner(newFakeStore())
+	if err := runner.Register(Job{Name: "exclusive", Run: func(context.Context) (interface{}, error) {
+		return nil, nil
+	}}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	release, acquired, err := runner.locker.TryLock(context.Background(), "exclusive")
+	if err != nil || !acquired {
+		t.Fatalf("expected to take the lock, got %v, %v", acquired, err)
+	}
+	if _, err := runner.RunNow(context.Background(), "exclusive"); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("expected ErrJobRunning, got %v", err)
+	}
+	release()
+	if _, err := runner.RunNow(context.Background(), "exclusive"); err != nil {
+		t.Fatalf("expected run after release, got %v", err)
+	}
+}
+
+func TestRunNowUnknownJob(t *testing.T) {
+	runner, _ := newTestRunner(newFakeStore())
+	if _, err := runner.RunNow(context.Background(), "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestRegisterRejectsDuplicates(t *testing.T) {
+	runner, _ := newTestRunner(newFakeStore())
+	job := Job{Name: "once", Run: func(context.Context) (interface{}, error) { return nil, nil }}
+	if err := runner.Register(job); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := runner.Register(job); !errors.Is(err, ErrJobExists) {
+		t.Fatalf("expected ErrJobExists, got %v", err)
+	}
+}
+
+func TestStartRunsScheduledJobs(t *testing.T) {
+	store := newFakeStore()
+	runner := NewRunner(Options{Store: store})
+	ran := make(chan struct{}, 1)
+	if err := runner.Register(Job{
+		Name:       "tick",
+		Schedule:   Every(time.Hour),
+		RunOnStart: true,
+		Run: func(context.Context) (interface{}, error) {
+			select {
+			case ran <- struct{}{}:
+			default:
+			}
+			return nil, nil
+		},
+	}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := runner.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("expected the job to run on start")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := runner.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	runs, _ := store.ListRuns(context.Background(), RunFilter{JobName: "tick"})
+	if len(runs) != 1 || runs[0].Trigger != TriggerSchedule {
+		t.Fatalf("expected one scheduled run, got %+v", runs)
+	}
+}
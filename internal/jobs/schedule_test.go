@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseScheduleNext(t *testing.T) {
+	// A Tuesday.
+	base := time.Date(2026, 3, 10, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "*/15 * * * *", want: time.Date(2026, 3, 10, 12, 45, 0, 0, time.UTC)},
+		{spec: "0 3 * * *", want: time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", want: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", want: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "30 9 * * 1-5", want: time.Date(2026, 3, 11, 9, 30, 0, 0, time.UTC)},
+		{spec: "0 0 1 * 5", want: time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 12 * * 7", want: time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{spec: "@every 90m", want: base.Add(90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: parse: %v", tt.spec, err)
+		}
+		if got := schedule.Next(base); !got.Equal(tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.spec, tt.want, got)
+		}
+	}
+}
+
+func TestParseScheduleRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "@every -1m", "@yearly"} {
+		if _, err := ParseSchedule(spec); !errors.Is(err, ErrInvalidSchedule) {
+			t.Fatalf("%q: expected ErrInvalidSchedule, got %v", spec, err)
+		}
+	}
+}
+
+func TestCronScheduleThatNeverMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("expected no next run, got %v", next)
+	}
+}
@@ -0,0 +1,90 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	notificationsdomain "family-app-go/internal/domain/notifications"
+)
+
+// WebhookClient posts every notification as JSON to one URL, where a push
+// gateway forwards it to the user's devices.
+type WebhookClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+type webhookPayload struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	FamilyID  string    `json:"family_id"`
+	Kind      string    `json:"kind"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Link      string    `json:"link"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewWebhookClient(rawURL string, timeout time.Duration) (*WebhookClient, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, fmt.Errorf("push webhook: url is required")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("push webhook: unsupported url scheme %q", parsed.Scheme)
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &WebhookClient{
+		url:        parsed.String(),
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (c *WebhookClient) Name() string {
+	return "webhook"
+}
+
+func (c *WebhookClient) Push(ctx context.Context, notification notificationsdomain.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		ID:        notification.ID,
+		UserID:    notification.UserID,
+		FamilyID:  notification.FamilyID,
+		Kind:      notification.Kind,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Link:      notification.Link,
+		CreatedAt: notification.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("push webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	notificationsdomain "family-app-go/internal/domain/notifications"
+)
+
+func TestWebhookPushPostsTheNotification(t *testing.T) {
+	var got webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client, err := NewWebhookClient(server.URL, 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	notification := notificationsdomain.Notification{
+		ID:       "notification-1",
+		UserID:   "user-1",
+		FamilyID: "family-1",
+		Kind:     notificationsdomain.KindBudgetThreshold,
+		Title:    "Groceries budget at 80%",
+		Body:     "Spent 400.00 of 500.00 EUR in March 2026.",
+		Link:     "/budgets/budget-1",
+	}
+	if err := client.Push(context.Background(), notification); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	if got.ID != "notification-1" || got.UserID != "user-1" || got.Title != notification.Title || got.Link != notification.Link {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+}
+
+func TestWebhookPushFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := NewWebhookClient(server.URL, 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if err := client.Push(context.Background(), notificationsdomain.Notification{ID: "notification-1"}); err == nil {
+		t.Fatal("expected an error for a 502 response")
+	}
+}
+
+func TestNewWebhookClientRequiresHTTPURL(t *testing.T) {
+	for _, rawURL := range []string{"", "ftp://push.example.com"} {
+		if _, err := NewWebhookClient(rawURL, time.Second); err == nil {
+			t.Fatalf("expected an error for %q", rawURL)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package rates
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	ratesdomain "family-app-go/internal/domain/rates"
+)
+
+const (
+	ecbDailyPath   = "/stats/eurofxref/eurofxref-daily.xml"
+	ecbRecentPath  = "/stats/eurofxref/eurofxref-hist-90d.xml"
+	ecbHistoryPath = "/stats/eurofxref/eurofxref-hist.xml"
+	// ecbRecentDays is how far back the 90-day history file reaches, with a
+	// margin for its publication lag.
+	ecbRecentDays = 85
+)
+
+// ECBClient reads the euro foreign exchange reference rates the European
+// Central Bank publishes every working day.
+type ECBClient struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+func NewECBClient(baseURL string, timeout time.Duration) (*ECBClient, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = "https://www.ecb.europa.eu"
+	}
+	parsed, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &ECBClient{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (c *ECBClient) Name() string {
+	return "ecb"
+}
+
+func (c *ECBClient) GetEURRates(ctx context.Context, onDate time.Time) (ratesdomain.ReferenceRates, error) {
+	onDate = dateOnlyUTC(onDate)
+	today := dateOnlyUTC(time.Now())
+
+	// The daily file only holds the latest day; older dates need one of the
+	// history files, the full one being several megabytes.
+	path := ecbDailyPath
+	switch {
+	case onDate.Before(today.AddDate(0, 0, -ecbRecentDays)):
+		path = ecbHistoryPath
+	case onDate.Before(today):
+		path = ecbRecentPath
+	}
+
+	endpoint, err := c.resolveURL(path)
+	if err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ratesdomain.ReferenceRates{}, fmt.Errorf("ecb rates: unexpected status %d", resp.StatusCode)
+	}
+
+	var envelope struct {
+		Cube struct {
+			Days []struct {
+				Time  string `xml:"time,attr"`
+				Rates []struct {
+					Currency string `xml:"currency,attr"`
+					Rate     string `xml:"rate,attr"`
+				} `xml:"Cube"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return ratesdomain.ReferenceRates{}, fmt.Errorf("ecb rates: %w", err)
+	}
+
+	// Days are listed newest first, but do not rely on it.
+	found := false
+	result := ratesdomain.ReferenceRates{Source: c.Name()}
+	for _, day := range envelope.Cube.Days {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(day.Time))
+		if err != nil || date.After(onDate) || (found && !date.After(result.Date)) {
+			continue
+		}
+
+		rates := make(map[string]float64, len(day.Rates))
+		for _, item := range day.Rates {
+			code := strings.ToUpper(strings.TrimSpace(item.Currency))
+			rate, err := strconv.ParseFloat(strings.TrimSpace(item.Rate), 64)
+			if len(code) != 3 || err != nil || rate <= 0 {
+				continue
+			}
+			rates[code] = rate
+		}
+		if len(rates) == 0 {
+			continue
+		}
+
+		found = true
+		result.Date = date
+		result.Rates = rates
+	}
+	if !found {
+		return ratesdomain.ReferenceRates{}, ratesdomain.ErrRateNotAvailable
+	}
+	return result, nil
+}
+
+func (c *ECBClient) resolveURL(path string) (string, error) {
+	relative, err := url.Parse(path)
+	if err != nil {
+		return "", err
+	}
+	return c.baseURL.ResolveReference(relative).String(), nil
+}
@@ -0,0 +1,54 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ratesdomain "family-app-go/internal/domain/rates"
+)
+
+func TestECBGetEURRatesPicksLatestDayOnOrBeforeDate(t *testing.T) {
+	onDate := dateOnlyUTC(time.Now()).AddDate(0, 0, -10)
+	day := func(offset int) string { return onDate.AddDate(0, 0, offset).Format("2006-01-02") }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ecbRecentPath {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="` + day(1) + `"><Cube currency="USD" rate="1.0900"/></Cube>
+		<Cube time="` + day(-1) + `"><Cube currency="USD" rate="1.0850"/><Cube currency="PLN" rate="4.3125"/></Cube>
+		<Cube time="` + day(-2) + `"><Cube currency="USD" rate="1.0800"/></Cube>
+	</Cube>
+</gesmes:Envelope>`))
+	}))
+	defer server.Close()
+
+	client, err := NewECBClient(server.URL, 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	rates, err := client.GetEURRates(context.Background(), onDate)
+	if err != nil {
+		t.Fatalf("get rates: %v", err)
+	}
+	if rates.Date.Format("2006-01-02") != day(-1) || rates.Source != "ecb" {
+		t.Fatalf("unexpected rates %+v", rates)
+	}
+	if rates.Rates["USD"] != 1.085 || rates.Rates["PLN"] != 4.3125 {
+		t.Fatalf("unexpected rates %+v", rates.Rates)
+	}
+
+	if _, err := client.GetEURRates(context.Background(), onDate.AddDate(0, 0, -5)); !errors.Is(err, ratesdomain.ErrRateNotAvailable) {
+		t.Fatalf("expected ErrRateNotAvailable, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ratesdomain "family-app-go/internal/domain/rates"
+)
+
+// ExchangeRateHostClient reads historical rates from exchangerate.host. The
+// API quotes against the account's source currency, USD on the free plan,
+// so rates are converted to euro-based ones.
+type ExchangeRateHostClient struct {
+	baseURL    *url.URL
+	accessKey  string
+	httpClient *http.Client
+}
+
+func NewExchangeRateHostClient(baseURL, accessKey string, timeout time.Duration) (*ExchangeRateHostClient, error) {
+	if strings.TrimSpace(accessKey) == "" {
+		return nil, fmt.Errorf("exchangerate.host access key is required")
+	}
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = "https://api.exchangerate.host"
+	}
+	parsed, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &ExchangeRateHostClient{
+		baseURL:    parsed,
+		accessKey:  strings.TrimSpace(accessKey),
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (c *ExchangeRateHostClient) Name() string {
+	return "exchangerate_host"
+}
+
+func (c *ExchangeRateHostClient) GetEURRates(ctx context.Context, onDate time.Time) (ratesdomain.ReferenceRates, error) {
+	relative, err := url.Parse("/historical")
+	if err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+	endpoint := c.baseURL.ResolveReference(relative)
+	query := endpoint.Query()
+	query.Set("access_key", c.accessKey)
+	query.Set("date", onDate.Format("2006-01-02"))
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ratesdomain.ReferenceRates{}, fmt.Errorf("exchangerate.host rates: unexpected status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Success bool               `json:"success"`
+		Date    string             `json:"date"`
+		Source  string             `json:"source"`
+		Quotes  map[string]float64 `json:"quotes"`
+		Error   *struct {
+			Code int    `json:"code"`
+			Info string `json:"info"`
+		} `json:"error"`
+	}
+	if err := decodeJSON(resp.Body, &payload); err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+	if !payload.Success {
+		if payload.Error != nil {
+			return ratesdomain.ReferenceRates{}, fmt.Errorf("exchangerate.host rates: error %d: %s", payload.Error.Code, payload.Error.Info)
+		}
+		return ratesdomain.ReferenceRates{}, fmt.Errorf("exchangerate.host rates: request failed")
+	}
+
+	// Quotes are keyed by the source and target codes run together, such as
+	// USDEUR.
+	source := strings.ToUpper(strings.TrimSpace(payload.Source))
+	perSource := map[string]float64{source: 1}
+	for pair, rate := range payload.Quotes {
+		pair = strings.ToUpper(pair)
+		if len(pair) != 6 || !strings.HasPrefix(pair, source) || rate <= 0 {
+			continue
+		}
+		perSource[pair[3:]] = rate
+	}
+	eurPerSource := perSource["EUR"]
+	if len(source) != 3 || eurPerSource <= 0 {
+		return ratesdomain.ReferenceRates{}, ratesdomain.ErrRateNotAvailable
+	}
+
+	date := onDate
+	if parsed, err := time.Parse("2006-01-02", payload.Date); err == nil {
+		date = parsed
+	}
+	result := ratesdomain.ReferenceRates{
+		Date:   dateOnlyUTC(date),
+		Source: c.Name(),
+		Rates:  make(map[string]float64, len(perSource)),
+	}
+	for code, rate := range perSource {
+		if code != "EUR" {
+			result.Rates[code] = rate / eurPerSource
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,63 @@
+package rates
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExchangeRateHostGetEURRatesRebasesQuotes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/historical" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("access_key") != "secret" || query.Get("date") != "2026-03-05" {
+			t.Fatalf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"historical":true,"date":"2026-03-05","source":"USD","quotes":{"USDEUR":0.8,"USDPLN":3.2,"USDBYN":3.4}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewExchangeRateHostClient(server.URL, "secret", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	rates, err := client.GetEURRates(context.Background(), time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("get rates: %v", err)
+	}
+	if rates.Source != "exchangerate_host" || rates.Date.Format("2006-01-02") != "2026-03-05" {
+		t.Fatalf("unexpected rates %+v", rates)
+	}
+	if _, ok := rates.Rates["EUR"]; ok {
+		t.Fatalf("did not expect EUR in euro-based rates: %+v", rates.Rates)
+	}
+	if math.Abs(rates.Rates["USD"]-1.25) > 1e-9 || math.Abs(rates.Rates["PLN"]-4) > 1e-9 || math.Abs(rates.Rates["BYN"]-4.25) > 1e-9 {
+		t.Fatalf("unexpected rates %+v", rates.Rates)
+	}
+}
+
+func TestExchangeRateHostReportsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":false,"error":{"code":101,"type":"invalid_access_key","info":"You have not supplied a valid API Access Key."}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewExchangeRateHostClient(server.URL, "secret", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	_, err = client.GetEURRates(context.Background(), time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC))
+	if err == nil || !strings.Contains(err.Error(), "101") {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	userdomain "family-app-go/internal/domain/user"
+)
+
+// AdminClient reads users through the Supabase Auth admin API. It needs the
+// project's service role key and must only run server side.
+type AdminClient struct {
+	baseURL        *url.URL
+	serviceRoleKey string
+	httpClient     *http.Client
+}
+
+func NewAdminClient(baseURL, serviceRoleKey string, timeout time.Duration) (*AdminClient, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		return nil, errors.New("supabase url is required")
+	}
+	if strings.TrimSpace(serviceRoleKey) == "" {
+		return nil, errors.New("supabase service role key is required")
+	}
+	parsed, err := url.Parse(strings.TrimRight(strings.TrimSpace(baseURL), "/"))
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &AdminClient{
+		baseURL:        parsed,
+		serviceRoleKey: strings.TrimSpace(serviceRoleKey),
+		httpClient:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// FetchProfile returns the user's email and the name and avatar from their
+// metadata, read the way the auth middleware reads tokens.
+func (c *AdminClient) FetchProfile(ctx context.Context, userID string) (*userdomain.RemoteProfile, error) {
+	endpoint := c.baseURL.JoinPath("auth", "v1", "admin", "users", userID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.serviceRoleKey)
+	req.Header.Set("apikey", c.serviceRoleKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, userdomain.ErrRemoteProfileNotFound
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("supabase admin get user: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Email        string                 `json:"email"`
+		UserMetadata map[string]interface{} `json:"user_metadata"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("supabase admin get user: decode response: %w", err)
+	}
+
+	return &userdomain.RemoteProfile{
+		Name:      firstNonEmpty(metadataString(payload.UserMetadata, "name"), metadataString(payload.UserMetadata, "full_name")),
+		Email:     strings.TrimSpace(payload.Email),
+		AvatarURL: metadataString(payload.UserMetadata, "avatar_url"),
+	}, nil
+}
+
+func metadataString(metadata map[string]interface{}, key string) string {
+	value, ok := metadata[key].(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	userdomain "family-app-go/internal/domain/user"
+)
+
+func TestFetchProfileReadsEmailAndMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/v1/admin/users/user-1" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer service-role" || r.Header.Get("apikey") != "service-role" {
+			t.Fatalf("expected the service role key, got %q / %q", r.Header.Get("Authorization"), r.Header.Get("apikey"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id":"user-1",
+			"email":"ann@example.com",
+			"user_metadata":{"full_name":" Ann Smith ","avatar_url":"https://cdn.example.com/ann.png","plan":3}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAdminClient(server.URL+"/", "service-role", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	profile, err := client.FetchProfile(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("fetch profile: %v", err)
+	}
+	want := userdomain.RemoteProfile{Name: "Ann Smith", Email: "ann@example.com", AvatarURL: "https://cdn.example.com/ann.png"}
+	if *profile != want {
+		t.Fatalf("expected %+v, got %+v", want, *profile)
+	}
+}
+
+func TestFetchProfileStatusErrors(t *testing.T) {
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"msg":"nope"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewAdminClient(server.URL, "service-role", 2*time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	if _, err := client.FetchProfile(context.Background(), "user-1"); !errors.Is(err, userdomain.ErrRemoteProfileNotFound) {
+		t.Fatalf("expected ErrRemoteProfileNotFound, got %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	_, err = client.FetchProfile(context.Background(), "user-1")
+	if err == nil || errors.Is(err, userdomain.ErrRemoteProfileNotFound) {
+		t.Fatalf("expected a status error, got %v", err)
+	}
+}
+
+func TestNewAdminClientRequiresServiceRoleKey(t *testing.T) {
+	if _, err := NewAdminClient("https://project.supabase.co", " ", time.Second); err == nil {
+		t.Fatalf("expected an error without a service role key")
+	}
+}
@@ -0,0 +1,89 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// authCacheSweepEvery controls how often Set drops expired entries so tokens
+// that are never seen again do not pile up.
+const authCacheSweepEvery = 256
+
+type InMemoryAuthCache struct {
+	mu    sync.RWMutex
+	items map[string]authItem
+	sets  int
+}
+
+type authItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewInMemoryAuthCache() *InMemoryAuthCache {
+	return &InMemoryAuthCache{
+		items: make(map[string]authItem),
+	}
+}
+
+func (c *InMemoryAuthCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	now := time.Now()
+
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+
+	if !item.expiresAt.After(now) {
+		c.mu.Lock()
+		item, ok = c.items[key]
+		if ok && !item.expiresAt.After(now) {
+			delete(c.items, key)
+		}
+		c.mu.Unlock()
+		return nil, false, nil
+	}
+
+	value := make([]byte, len(item.value))
+	copy(value, item.value)
+	return value, true, nil
+}
+
+func (c *InMemoryAuthCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		c.mu.Lock()
+		delete(c.items, key)
+		c.mu.Unlock()
+		return nil
+	}
+
+	stored := make([]byte, len(value))
+	copy(stored, value)
+	now := time.Now()
+
+	c.mu.Lock()
+	c.items[key] = authItem{
+		value:     stored,
+		expiresAt: now.Add(ttl),
+	}
+	c.sets++
+	if c.sets%authCacheSweepEvery == 0 {
+		for k, item := range c.items {
+			if !item.expiresAt.After(now) {
+				delete(c.items, k)
+			}
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *InMemoryAuthCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,43 @@
+package activity
+
+import (
+	"context"
+
+	"family-app-go/internal/db"
+	activitydomain "family-app-go/internal/domain/activity"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateEvent(ctx context.Context, event *activitydomain.Event) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *PostgresRepository) ListEvents(ctx context.Context, familyID string, filter activitydomain.ListFilter) ([]activitydomain.Event, int64, error) {
+	query := r.db.WithContext(ctx).
+		Clauses(db.ReadReplica).
+		Model(&activitydomain.Event{}).
+		Where("family_id = ?", familyID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []activitydomain.Event
+	if err := query.
+		Order("created_at desc, id desc").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
@@ -0,0 +1,262 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"family-app-go/internal/db"
+	admindomain "family-app-go/internal/domain/admin"
+	syncdomain "family-app-go/internal/domain/sync"
+	"gorm.io/gorm"
+)
+
+// softDeleteTables are purged children first so no soft-deleted row outlives
+// its parent.
+var softDeleteTables = []string{"todo_items", "todo_lists", "wishlist_items", "pets"}
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListFamilies(ctx context.Context, filter admindomain.ListFamiliesFilter) ([]admindomain.FamilySummary, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Table("families").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []admindomain.FamilySummary
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(`
+		SELECT
+			f.id,
+			f.name,
+			f.owner_id,
+			f.default_currency,
+			f.created_at,
+			(SELECT COUNT(*) FROM family_members m WHERE m.family_id = f.id) AS members,
+			(SELECT COUNT(*) FROM expenses e WHERE e.family_id = f.id) AS expenses,
+			(SELECT COUNT(*) FROM todo_lists l WHERE l.family_id = f.id AND l.deleted_at IS NULL) AS todo_lists,
+			(SELECT COUNT(*) FROM todo_items i
+				JOIN todo_lists l ON l.id = i.list_id
+				WHERE l.family_id = f.id AND l.deleted_at IS NULL AND i.deleted_at IS NULL) AS todo_items
+		FROM families f
+		ORDER BY f.created_at DESC, f.id
+		LIMIT ? OFFSET ?
+	`, filter.Limit, filter.Offset).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+func (r *PostgresRepository) ListSyncBatches(ctx context.Context, filter admindomain.SyncBatchFilter) ([]admindomain.SyncBatch, error) {
+	query := r.db.WithContext(ctx).Model(&syncdomain.BatchRecord{})
+	if filter.FamilyID != "" {
+		query = query.Where("family_id = ?", filter.FamilyID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UpdatedBefore != nil {
+		query = query.Where("updated_at < ?", *filter.UpdatedBefore)
+	}
+
+	var records []syncdomain.BatchRecord
+	if err := query.Order("updated_at ASC").Limit(filter.Limit).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	batches := make([]admindomain.SyncBatch, 0, len(records))
+	for _, record := range records {
+		batches = append(batches, toSyncBatch(record))
+	}
+	return batches, nil
+}
+
+func (r *PostgresRepository) GetSyncBatch(ctx context.Context, batchID string) (*admindomain.SyncBatch, error) {
+	var record syncdomain.BatchRecord
+	if err := r.db.WithContext(ctx).Where("id = ?", batchID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, admindomain.ErrSyncBatchNotFound
+		}
+		return nil, err
+	}
+	batch := toSyncBatch(record)
+	return &batch, nil
+}
+
+func (r *PostgresRepository) ListPendingSyncOperations(ctx context.Context, familyID, userID string) ([]admindomain.SyncOperation, error) {
+	var records []syncdomain.OperationRecord
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND user_id = ? AND status = ?", familyID, userID, syncdomain.OperationStatePending).
+		Order("created_at ASC").
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	operations := make([]admindomain.SyncOperation, 0, len(records))
+	for _, record := range records {
+		operations = append(operations, admindomain.SyncOperation{
+			ID:            record.ID,
+			OperationID:   record.OperationID,
+			OperationType: string(record.OperationType),
+			Status:        string(record.Status),
+			CreatedAt:     record.CreatedAt,
+			UpdatedAt:     record.UpdatedAt,
+		})
+	}
+	return operations, nil
+}
+
+func (r *PostgresRepository) ReleaseSyncBatch(ctx context.Context, batch admindomain.SyncBatch) (bool, int64, error) {
+	var (
+		released   bool
+		operations int64
+	)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND status = ?", batch.ID, syncdomain.BatchStateProcessing).Delete(&syncdomain.BatchRecord{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		released = true
+
+		result = tx.Where("family_id = ? AND user_id = ? AND status = ?", batch.FamilyID, batch.UserID, syncdomain.OperationStatePending).
+			Delete(&syncdomain.OperationRecord{})
+		if result.Error != nil {
+			return result.Error
+		}
+		operations = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	return released, operations, nil
+}
+
+func (r *PostgresRepository) CountSoftDeleted(ctx context.Context, deletedBefore time.Time) ([]admindomain.PurgeCount, error) {
+	counts := make([]admindomain.PurgeCount, 0, len(softDeleteTables))
+	for _, table := range softDeleteTables {
+		var rows int64
+		if err := r.db.WithContext(ctx).
+			Table(table).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Count(&rows).Error; err != nil {
+			return nil, err
+		}
+		counts = append(counts, admindomain.PurgeCount{Table: table, Rows: rows})
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) PurgeSoftDeleted(ctx context.Context, deletedBefore time.Time) ([]admindomain.PurgeCount, error) {
+	counts := make([]admindomain.PurgeCount, 0, len(softDeleteTables))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range softDeleteTables {
+			result := tx.Exec("DELETE FROM "+table+" WHERE deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore)
+			if result.Error != nil {
+				return result.Error
+			}
+			counts = append(counts, admindomain.PurgeCount{Table: table, Rows: result.RowsAffected})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) ListEncryptedValues(ctx context.Context, column admindomain.EncryptedColumn, afterID string, limit int) ([]admindomain.EncryptedValue, error) {
+	query := r.db.WithContext(ctx).
+		Table(column.Table).
+		Select("id, " + column.Column + " AS value").
+		Where(column.Column + " IS NOT NULL")
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+
+	var values []admindomain.EncryptedValue
+	if err := query.Order("id").Limit(limit).Scan(&values).Error; err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (r *PostgresRepository) ReplaceEncryptedValue(ctx context.Context, column admindomain.EncryptedColumn, id, oldValue, newValue string) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(
+		"UPDATE "+column.Table+" SET "+column.Column+" = ? WHERE id = ? AND "+column.Column+" = ?",
+		newValue, id, oldValue,
+	)
+	return result.RowsAffected > 0, result.Error
+}
+
+type indexColumnRow struct {
+	IndexName string
+	TableName string
+	IsUnique  bool
+	IsValid   bool
+	IsPartial bool
+	Position  int
+	Column    string
+}
+
+// ListIndexes reads the catalog with one row per key column, so no array
+// type has to be scanned. It runs on the primary: a replica may not have
+// replayed a fresh index build yet.
+func (r *PostgresRepository) ListIndexes(ctx context.Context) ([]admindomain.Index, error) {
+	var rows []indexColumnRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			c.relname AS index_name,
+			t.relname AS table_name,
+			i.indisunique AS is_unique,
+			i.indisvalid AS is_valid,
+			i.indpred IS NOT NULL AS is_partial,
+			k.position,
+			pg_get_indexdef(i.indexrelid, k.position, true) AS column
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		CROSS JOIN LATERAL generate_series(1, i.indnkeyatts) AS k(position)
+		WHERE n.nspname = current_schema()
+		ORDER BY t.relname, c.relname, k.position
+	`).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var indexes []admindomain.Index
+	for _, row := range rows {
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != row.IndexName || indexes[len(indexes)-1].Table != row.TableName {
+			indexes = append(indexes, admindomain.Index{
+				Name:    row.IndexName,
+				Table:   row.TableName,
+				Unique:  row.IsUnique,
+				Valid:   row.IsValid,
+				Partial: row.IsPartial,
+			})
+		}
+		last := &indexes[len(indexes)-1]
+		last.Columns = append(last.Columns, row.Column)
+	}
+	return indexes, nil
+}
+
+func toSyncBatch(record syncdomain.BatchRecord) admindomain.SyncBatch {
+	return admindomain.SyncBatch{
+		ID:             record.ID,
+		FamilyID:       record.FamilyID,
+		UserID:         record.UserID,
+		IdempotencyKey: record.IdempotencyKey,
+		Status:         string(record.Status),
+		CreatedAt:      record.CreatedAt,
+		UpdatedAt:      record.UpdatedAt,
+	}
+}
@@ -6,28 +6,44 @@ import (
 	"strings"
 	"time"
 
+	"family-app-go/internal/db"
 	analyticsdomain "family-app-go/internal/domain/analytics"
 	"gorm.io/gorm"
 )
 
 type PostgresRepository struct {
 	db *gorm.DB
+	// useRollups makes reports read the daily rollups for finished days and
+	// scan expenses only for today, later days and days not rolled up yet.
+	useRollups bool
+	now        func() time.Time
 }
 
 func NewPostgres(db *gorm.DB) *PostgresRepository {
-	return &PostgresRepository{db: db}
+	return &PostgresRepository{db: db, now: time.Now}
+}
+
+func NewPostgresWithRollups(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db, useRollups: true, now: time.Now}
 }
 
 func (r *PostgresRepository) Summary(ctx context.Context, familyID string, filter analyticsdomain.SummaryFilter) (analyticsdomain.SummaryResult, error) {
-	where, args, amountExpr := buildExpenseWhere(familyID, filter.From, filter.To, filter.Currency, filter.UseBaseAmount, filter.CategoryIDs)
-	query := "SELECT COALESCE(SUM(" + amountExpr + "), 0) AS total_amount, COUNT(*) AS count FROM expenses e WHERE " + where
+	source, args := r.expenseRows(expenseRowsQuery{
+		FamilyID:      familyID,
+		From:          filter.From,
+		To:            filter.To,
+		Currency:      filter.Currency,
+		UseBaseAmount: filter.UseBaseAmount,
+		CategoryIDs:   filter.CategoryIDs,
+	})
+	query := "SELECT COALESCE(SUM(s.amount), 0) AS total_amount, COALESCE(SUM(s.count), 0) AS count FROM (" + source + ") s"
 
 	var row struct {
 		TotalAmount float64 `gorm:"column:total_amount"`
 		Count       int64   `gorm:"column:count"`
 	}
 
-	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&row).Error; err != nil {
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(query, args...).Scan(&row).Error; err != nil {
 		return analyticsdomain.SummaryResult{}, err
 	}
 
@@ -35,21 +51,28 @@ func (r *PostgresRepository) Summary(ctx context.Context, familyID string, filte
 }
 
 func (r *PostgresRepository) Timeseries(ctx context.Context, familyID string, filter analyticsdomain.TimeseriesFilter) ([]analyticsdomain.TimeseriesPoint, error) {
-	where, args, amountExpr := buildExpenseWhere(familyID, filter.From, filter.To, filter.Currency, filter.UseBaseAmount, filter.CategoryIDs)
-
 	groupBy := strings.ToLower(strings.TrimSpace(filter.GroupBy))
 	if groupBy != "day" && groupBy != "week" {
 		return nil, fmt.Errorf("invalid group_by")
 	}
 
-	// e.date is a DATE (calendar day). Applying timezone conversion here shifts
+	source, args := r.expenseRows(expenseRowsQuery{
+		FamilyID:      familyID,
+		From:          filter.From,
+		To:            filter.To,
+		Currency:      filter.Currency,
+		UseBaseAmount: filter.UseBaseAmount,
+		CategoryIDs:   filter.CategoryIDs,
+	})
+
+	// s.day is a DATE (calendar day). Applying timezone conversion here shifts
 	// bucket boundaries and may move expenses to neighbor days.
-	periodExpr := fmt.Sprintf("date_trunc('%s', e.date::timestamp)", groupBy)
+	periodExpr := fmt.Sprintf("date_trunc('%s', s.day::timestamp)", groupBy)
 	selectExpr := fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", periodExpr)
-	query := fmt.Sprintf("SELECT %s AS period, COALESCE(SUM(%s), 0) AS total, COUNT(*) AS count FROM expenses e WHERE %s GROUP BY 1 ORDER BY 1", selectExpr, amountExpr, where)
+	query := fmt.Sprintf("SELECT %s AS period, COALESCE(SUM(s.amount), 0) AS total, COALESCE(SUM(s.count), 0) AS count FROM (%s) s GROUP BY 1 ORDER BY 1", selectExpr, source)
 
 	var rows []analyticsdomain.TimeseriesPoint
-	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(query, args...).Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
@@ -57,24 +80,26 @@ func (r *PostgresRepository) Timeseries(ctx context.Context, familyID string, fi
 }
 
 func (r *PostgresRepository) ByCategory(ctx context.Context, familyID string, filter analyticsdomain.ByCategoryFilter) ([]analyticsdomain.ByCategoryRow, error) {
-	where, args, amountExpr := buildExpenseWhere(familyID, filter.From, filter.To, filter.Currency, filter.UseBaseAmount, nil)
-	where = "t.family_id = ? AND " + where
-	args = append([]interface{}{familyID}, args...)
-	if len(filter.CategoryIDs) > 0 {
-		where += " AND t.id IN (?)"
-		args = append(args, filter.CategoryIDs)
-	}
+	source, args := r.expenseRows(expenseRowsQuery{
+		FamilyID:      familyID,
+		From:          filter.From,
+		To:            filter.To,
+		Currency:      filter.Currency,
+		UseBaseAmount: filter.UseBaseAmount,
+		CategoryIDs:   filter.CategoryIDs,
+		PerCategory:   true,
+	})
 
 	limit := filter.Limit
 	if limit <= 0 {
 		limit = 20
 	}
 
-	query := fmt.Sprintf("SELECT t.id AS category_id, t.name AS category_name, COALESCE(SUM(%s), 0) AS total, COUNT(e.id) AS count FROM categories t JOIN expense_categories et ON et.category_id = t.id JOIN expenses e ON e.id = et.expense_id WHERE %s GROUP BY t.id, t.name ORDER BY total DESC LIMIT ?", amountExpr, where)
-	args = append(args, limit)
+	query := fmt.Sprintf("SELECT t.id AS category_id, t.name AS category_name, COALESCE(SUM(s.amount), 0) AS total, COALESCE(SUM(s.count), 0) AS count FROM (%s) s JOIN categories t ON t.id = s.category_id AND t.family_id = ? GROUP BY t.id, t.name ORDER BY total DESC LIMIT ?", source)
+	args = append(args, familyID, limit)
 
 	var rows []analyticsdomain.ByCategoryRow
-	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(query, args...).Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
@@ -95,7 +120,7 @@ func (r *PostgresRepository) TopCategories(ctx context.Context, familyID string,
 	var countRow struct {
 		RecordsRead int64 `gorm:"column:records_read"`
 	}
-	if err := r.db.WithContext(ctx).Raw(countQuery, familyID, filter.From, filter.To, readLimit).Scan(&countRow).Error; err != nil {
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(countQuery, familyID, filter.From, filter.To, readLimit).Scan(&countRow).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -110,7 +135,7 @@ func (r *PostgresRepository) TopCategories(ctx context.Context, familyID string,
 		"LIMIT ?"
 
 	var rows []analyticsdomain.ByCategoryRow
-	if err := r.db.WithContext(ctx).Raw(query, familyID, filter.From, filter.To, readLimit, familyID, responseCount).Scan(&rows).Error; err != nil {
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(query, familyID, filter.From, filter.To, readLimit, familyID, responseCount).Scan(&rows).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -118,19 +143,223 @@ func (r *PostgresRepository) TopCategories(ctx context.Context, familyID string,
 }
 
 func (r *PostgresRepository) Monthly(ctx context.Context, familyID string, filter analyticsdomain.MonthlyFilter) ([]analyticsdomain.MonthlyRow, error) {
-	where, args, amountExpr := buildExpenseWhereRange(familyID, filter.From, filter.To, filter.Currency, filter.UseBaseAmount, filter.CategoryIDs)
-	periodExpr := "date_trunc('month', e.date::timestamp)"
+	source, args := r.expenseRows(expenseRowsQuery{
+		FamilyID:      familyID,
+		From:          filter.From,
+		To:            filter.To,
+		ToExclusive:   true,
+		Currency:      filter.Currency,
+		UseBaseAmount: filter.UseBaseAmount,
+		CategoryIDs:   filter.CategoryIDs,
+	})
+	periodExpr := "date_trunc('month', s.day::timestamp)"
 	selectExpr := "to_char(" + periodExpr + ", 'YYYY-MM')"
-	query := fmt.Sprintf("SELECT %s AS month, COALESCE(SUM(%s), 0) AS total, COUNT(*) AS count FROM expenses e WHERE %s GROUP BY %s ORDER BY %s", selectExpr, amountExpr, where, periodExpr, periodExpr)
+	query := fmt.Sprintf("SELECT %s AS month, COALESCE(SUM(s.amount), 0) AS total, COALESCE(SUM(s.count), 0) AS count FROM (%s) s GROUP BY %s ORDER BY %s", selectExpr, source, periodExpr, periodExpr)
 
 	var rows []analyticsdomain.MonthlyRow
-	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(query, args...).Scan(&rows).Error; err != nil {
 		return nil, err
 	}
 
 	return rows, nil
 }
 
+func (r *PostgresRepository) CategoryMonthly(ctx context.Context, familyID string, filter analyticsdomain.MonthlyFilter) ([]analyticsdomain.CategoryMonthlyRow, error) {
+	source, args := r.expenseRows(expenseRowsQuery{
+		FamilyID:      familyID,
+		From:          filter.From,
+		To:            filter.To,
+		ToExclusive:   true,
+		Currency:      filter.Currency,
+		UseBaseAmount: filter.UseBaseAmount,
+		CategoryIDs:   filter.CategoryIDs,
+		PerCategory:   true,
+	})
+
+	periodExpr := "date_trunc('month', s.day::timestamp)"
+	query := fmt.Sprintf("SELECT t.id AS category_id, t.name AS category_name, to_char(%s, 'YYYY-MM') AS month, COALESCE(SUM(s.amount), 0) AS total, COALESCE(SUM(s.count), 0) AS count FROM (%s) s JOIN categories t ON t.id = s.category_id AND t.family_id = ? GROUP BY t.id, t.name, %s ORDER BY %s, t.name", periodExpr, source, periodExpr, periodExpr)
+	args = append(args, familyID)
+
+	var rows []analyticsdomain.CategoryMonthlyRow
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// RefreshRollups rebuilds the rollups of dirty days before the given day in
+// batches. Each batch claims its days and rewrites their rollups in one
+// transaction, so a change committed meanwhile marks the day dirty again.
+func (r *PostgresRepository) RefreshRollups(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	var refreshed int64
+	for {
+		var days []struct {
+			FamilyID string    `gorm:"column:family_id"`
+			Day      time.Time `gorm:"column:day"`
+		}
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			claim := "DELETE FROM expense_rollup_dirty_days WHERE (family_id, day) IN (" +
+				"SELECT family_id, day FROM expense_rollup_dirty_days WHERE day < ? ORDER BY day LIMIT ? FOR UPDATE SKIP LOCKED" +
+				") RETURNING family_id, day"
+			if err := tx.Raw(claim, before, batchSize).Scan(&days).Error; err != nil {
+				return err
+			}
+			for _, day := range days {
+				if err := writeRollups(tx, day.FamilyID, day.Day); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return refreshed, err
+		}
+		refreshed += int64(len(days))
+		if len(days) < batchSize {
+			return refreshed, nil
+		}
+	}
+}
+
+// MarkRollupDaysDirty queues every day in [from, before) that has expenses or
+// rollups for a rebuild.
+func (r *PostgresRepository) MarkRollupDaysDirty(ctx context.Context, from, before time.Time) (int64, error) {
+	query := "INSERT INTO expense_rollup_dirty_days (family_id, day) " +
+		"SELECT family_id, date FROM expenses WHERE date >= ? AND date < ? " +
+		"UNION SELECT family_id, day FROM expense_daily_totals WHERE day >= ? AND day < ? " +
+		"UNION SELECT family_id, day FROM expense_daily_category_totals WHERE day >= ? AND day < ? " +
+		"ON CONFLICT DO NOTHING"
+	result := r.db.WithContext(ctx).Exec(query, from, before, from, before, from, before)
+	return result.RowsAffected, result.Error
+}
+
+func writeRollups(tx *gorm.DB, familyID string, day time.Time) error {
+	statements := []string{
+		"DELETE FROM expense_daily_totals WHERE family_id = ? AND day = ?",
+		"DELETE FROM expense_daily_category_totals WHERE family_id = ? AND day = ?",
+		"INSERT INTO expense_daily_totals (family_id, day, currency, base_currency, has_base, amount, base_amount, count) " +
+			"SELECT e.family_id, e.date, e.currency, COALESCE(e.base_currency, ''), e.amount_in_base IS NOT NULL, " +
+			"SUM(e.amount), SUM(COALESCE(e.amount_in_base, e.amount)), COUNT(*) " +
+			"FROM expenses e WHERE e.family_id = ? AND e.date = ? GROUP BY 1, 2, 3, 4, 5",
+		"INSERT INTO expense_daily_category_totals (family_id, category_id, day, currency, base_currency, has_base, amount, base_amount, count) " +
+			"SELECT e.family_id, ec.category_id, e.date, e.currency, COALESCE(e.base_currency, ''), e.amount_in_base IS NOT NULL, " +
+			"SUM(e.amount), SUM(COALESCE(e.amount_in_base, e.amount)), COUNT(*) " +
+			"FROM expenses e JOIN expense_categories ec ON ec.expense_id = e.id WHERE e.family_id = ? AND e.date = ? GROUP BY 1, 2, 3, 4, 5, 6",
+	}
+	for _, statement := range statements {
+		if err := tx.Exec(statement, familyID, day).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expenseRowsQuery describes the expenses a report aggregates. PerCategory
+// yields one row per expense category, limited to CategoryIDs when set;
+// otherwise CategoryIDs keeps expenses in any of the categories.
+type expenseRowsQuery struct {
+	FamilyID      string
+	From          time.Time
+	To            time.Time
+	ToExclusive   bool
+	Currency      string
+	UseBaseAmount bool
+	CategoryIDs   []string
+	PerCategory   bool
+}
+
+// expenseRows returns a subquery of (category_id,) day, amount and count rows
+// for the reports to aggregate. With rollups enabled, finished days come from
+// the rollup tables; today, later days and dirty days are scanned from
+// expenses. Filtering by several categories without grouping by category
+// cannot use the rollups, since an expense would be counted once per
+// matching category.
+func (r *PostgresRepository) expenseRows(q expenseRowsQuery) (string, []interface{}) {
+	if !r.useRollups || (!q.PerCategory && len(q.CategoryIDs) > 1) {
+		return rawExpenseRows(q, "", nil)
+	}
+
+	current := r.now().UTC()
+	today := time.Date(current.Year(), current.Month(), current.Day(), 0, 0, 0, 0, time.UTC)
+
+	table := "expense_daily_totals"
+	selectExpr := "r.day"
+	if q.PerCategory || len(q.CategoryIDs) > 0 {
+		table = "expense_daily_category_totals"
+	}
+	if q.PerCategory {
+		selectExpr = "r.category_id, r.day"
+	}
+
+	toOp := "<="
+	if q.ToExclusive {
+		toOp = "<"
+	}
+	conditions := []string{
+		"r.family_id = ?",
+		"r.day >= ?",
+		"r.day " + toOp + " ?",
+		"r.day < ?",
+		"NOT EXISTS (SELECT 1 FROM expense_rollup_dirty_days d WHERE d.family_id = r.family_id AND d.day = r.day)",
+	}
+	args := []interface{}{q.FamilyID, q.From, q.To, today}
+	amountExpr := "r.amount"
+	if q.Currency != "" {
+		if q.UseBaseAmount {
+			conditions = append(conditions, "((r.base_currency = ? AND r.has_base) OR (r.currency = ? AND NOT r.has_base))")
+			args = append(args, q.Currency, q.Currency)
+			amountExpr = "r.base_amount"
+		} else {
+			conditions = append(conditions, "r.currency = ?")
+			args = append(args, q.Currency)
+		}
+	}
+	if len(q.CategoryIDs) > 0 {
+		conditions = append(conditions, "r.category_id IN (?)")
+		args = append(args, q.CategoryIDs)
+	}
+	rolled := fmt.Sprintf("SELECT %s, %s AS amount, r.count AS count FROM %s r WHERE %s", selectExpr, amountExpr, table, strings.Join(conditions, " AND "))
+
+	recent, recentArgs := rawExpenseRows(q, "e.date >= ?", []interface{}{today})
+	dirty, dirtyArgs := rawExpenseRows(q, "e.date IN (SELECT d.day FROM expense_rollup_dirty_days d WHERE d.family_id = ? AND d.day < ?)", []interface{}{q.FamilyID, today})
+
+	args = append(args, recentArgs...)
+	args = append(args, dirtyArgs...)
+	return rolled + " UNION ALL " + recent + " UNION ALL " + dirty, args
+}
+
+func rawExpenseRows(q expenseRowsQuery, extra string, extraArgs []interface{}) (string, []interface{}) {
+	categoryIDs := q.CategoryIDs
+	if q.PerCategory {
+		categoryIDs = nil
+	}
+
+	var (
+		where      string
+		args       []interface{}
+		amountExpr string
+	)
+	if q.ToExclusive {
+		where, args, amountExpr = buildExpenseWhereRange(q.FamilyID, q.From, q.To, q.Currency, q.UseBaseAmount, categoryIDs)
+	} else {
+		where, args, amountExpr = buildExpenseWhere(q.FamilyID, q.From, q.To, q.Currency, q.UseBaseAmount, categoryIDs)
+	}
+	if extra != "" {
+		where += " AND " + extra
+		args = append(args, extraArgs...)
+	}
+
+	if !q.PerCategory {
+		return fmt.Sprintf("SELECT e.date AS day, %s AS amount, 1 AS count FROM expenses e WHERE %s", amountExpr, where), args
+	}
+	if len(q.CategoryIDs) > 0 {
+		where += " AND ec.category_id IN (?)"
+		args = append(args, q.CategoryIDs)
+	}
+	return fmt.Sprintf("SELECT ec.category_id, e.date AS day, %s AS amount, 1 AS count FROM expenses e JOIN expense_categories ec ON ec.expense_id = e.id WHERE %s", amountExpr, where), args
+}
+
 func buildExpenseWhere(familyID string, from, to time.Time, currency string, useBaseAmount bool, categoryIDs []string) (string, []interface{}, string) {
 	conditions := []string{"e.family_id = ?", "e.date >= ?", "e.date <= ?"}
 	args := []interface{}{familyID, from, to}
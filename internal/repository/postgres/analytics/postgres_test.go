@@ -62,3 +62,41 @@ func TestBuildExpenseWhereRangeUsesBaseAmountExpression(t *testing.T) {
 		t.Fatalf("expected 5 args, got %d", len(args))
 	}
 }
+
+func TestExpenseRowsReadsRollupsForFinishedDays(t *testing.T) {
+	repo := NewPostgresWithRollups(nil)
+	repo.now = func() time.Time { return time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC) }
+
+	query, args := repo.expenseRows(expenseRowsQuery{
+		FamilyID:    "fam-1",
+		From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		CategoryIDs: []string{"cat-1"},
+		PerCategory: true,
+	})
+
+	if !strings.Contains(query, "FROM expense_daily_category_totals r") {
+		t.Fatalf("expected category rollups, got %q", query)
+	}
+	if strings.Count(query, "UNION ALL") != 2 || !strings.Contains(query, "expense_rollup_dirty_days") {
+		t.Fatalf("expected raw scans for today and dirty days, got %q", query)
+	}
+	if len(args) != 16 {
+		t.Fatalf("expected 16 args, got %d", len(args))
+	}
+}
+
+func TestExpenseRowsScansExpensesForSeveralCategories(t *testing.T) {
+	repo := NewPostgresWithRollups(nil)
+
+	query, _ := repo.expenseRows(expenseRowsQuery{
+		FamilyID:    "fam-1",
+		From:        time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
+		CategoryIDs: []string{"cat-1", "cat-2"},
+	})
+
+	if strings.Contains(query, "expense_daily") || !strings.Contains(query, "FROM expenses e") {
+		t.Fatalf("expected a raw scan, got %q", query)
+	}
+}
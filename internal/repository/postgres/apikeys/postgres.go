@@ -0,0 +1,76 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateKey(ctx context.Context, key *apikeysdomain.Key) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *PostgresRepository) ListKeys(ctx context.Context, userID string) ([]apikeysdomain.Key, error) {
+	var keys []apikeysdomain.Key
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at desc, id desc").
+		Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *PostgresRepository) CountActiveKeys(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&apikeysdomain.Key{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresRepository) GetKeyByHash(ctx context.Context, hash string) (*apikeysdomain.Key, error) {
+	var key apikeysdomain.Key
+	if err := r.db.WithContext(ctx).
+		Where("key_hash = ?", hash).
+		First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apikeysdomain.ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *PostgresRepository) RevokeKey(ctx context.Context, userID, id string, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&apikeysdomain.Key{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apikeysdomain.ErrKeyNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) TouchKey(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&apikeysdomain.Key{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
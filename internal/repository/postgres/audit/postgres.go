@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"family-app-go/internal/db"
+	auditdomain "family-app-go/internal/domain/audit"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateEvent(ctx context.Context, event *auditdomain.Event) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *PostgresRepository) ListEvents(ctx context.Context, filter auditdomain.ListFilter) ([]auditdomain.Event, int64, error) {
+	query := r.db.WithContext(ctx).
+		Clauses(db.ReadReplica).
+		Model(&auditdomain.Event{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.FamilyID != "" {
+		query = query.Where("family_id = ?", filter.FamilyID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []auditdomain.Event
+	if err := query.
+		Order("created_at desc, id desc").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *PostgresRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("created_at < ?", cutoff).
+		Delete(&auditdomain.Event{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	authdomain "family-app-go/internal/domain/auth"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateAccount(ctx context.Context, account *authdomain.Account) error {
+	if err := r.db.WithContext(ctx).Create(account).Error; err != nil {
+		if isUniqueViolation(err) {
+			return authdomain.ErrEmailTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetAccountByEmail(ctx context.Context, email string) (*authdomain.Account, error) {
+	var account authdomain.Account
+	if err := r.db.WithContext(ctx).
+		Where("lower(email) = lower(?)", email).
+		First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, authdomain.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *PostgresRepository) GetAccountByID(ctx context.Context, id string) (*authdomain.Account, error) {
+	var account authdomain.Account
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, authdomain.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *PostgresRepository) CreateRefreshToken(ctx context.Context, token *authdomain.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *PostgresRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*authdomain.RefreshToken, error) {
+	var token authdomain.RefreshToken
+	if err := r.db.WithContext(ctx).
+		Where("token_hash = ?", hash).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, authdomain.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *PostgresRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&authdomain.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *PostgresRepository) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&authdomain.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
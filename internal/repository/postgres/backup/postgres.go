@@ -0,0 +1,204 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	backupdomain "family-app-go/internal/domain/backup"
+	"gorm.io/gorm"
+)
+
+// excludedTables are not part of backups: the migration log describes the
+// schema rather than data, and job history belongs to the running deployment.
+var excludedTables = map[string]bool{
+	"schema_migrations": true,
+	"job_runs":          true,
+}
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) Tables(ctx context.Context) ([]string, error) {
+	var names []string
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'
+		ORDER BY table_name`).Scan(&names).Error; err != nil {
+		return nil, err
+	}
+
+	var edges []struct {
+		Child  string
+		Parent string
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT child.relname AS child, parent.relname AS parent
+		FROM pg_constraint con
+		JOIN pg_class child ON child.oid = con.conrelid
+		JOIN pg_class parent ON parent.oid = con.confrelid
+		JOIN pg_namespace n ON n.oid = con.connamespace
+		WHERE con.contype = 'f' AND n.nspname = current_schema()`).Scan(&edges).Error; err != nil {
+		return nil, err
+	}
+
+	tables := make([]string, 0, len(names))
+	for _, name := range names {
+		if !excludedTables[name] {
+			tables = append(tables, name)
+		}
+	}
+	parents := make(map[string][]string, len(tables))
+	for _, edge := range edges {
+		if edge.Child != edge.Parent {
+			parents[edge.Child] = append(parents[edge.Child], edge.Parent)
+		}
+	}
+	return dependencyOrder(tables, parents), nil
+}
+
+func (r *PostgresRepository) SchemaVersion(ctx context.Context) (string, error) {
+	var version sql.NullString
+	if err := r.db.WithContext(ctx).
+		Raw("SELECT MAX(filename) FROM schema_migrations").
+		Scan(&version).Error; err != nil {
+		return "", err
+	}
+	return version.String, nil
+}
+
+func (r *PostgresRepository) Snapshot(ctx context.Context, tables []string, fn func(table string, row json.RawMessage) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			if err := snapshotTable(tx, table, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+func (r *PostgresRepository) Restore(ctx context.Context, tables []string, fn func(insert backupdomain.RowInserter) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		quoted := make([]string, 0, len(tables))
+		for _, table := range tables {
+			quoted = append(quoted, quoteIdent(table))
+		}
+		if err := tx.Exec("TRUNCATE " + strings.Join(quoted, ", ")).Error; err != nil {
+			return err
+		}
+
+		err := fn(func(table string, rows []json.RawMessage) error {
+			payload := make([]byte, 0, 2)
+			payload = append(payload, '[')
+			for i, row := range rows {
+				if i > 0 {
+					payload = append(payload, ',')
+				}
+				payload = append(payload, row...)
+			}
+			payload = append(payload, ']')
+
+			name := quoteIdent(table)
+			return tx.Exec("INSERT INTO "+name+" SELECT * FROM json_populate_recordset(NULL::"+name+", ?::json)", string(payload)).Error
+		})
+		if err != nil {
+			return err
+		}
+		return resetSequences(tx, tables)
+	})
+}
+
+func snapshotTable(tx *gorm.DB, table string, fn func(table string, row json.RawMessage) error) error {
+	rows, err := tx.Raw("SELECT row_to_json(t)::text FROM " + quoteIdent(table) + " t").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return err
+		}
+		if err := fn(table, json.RawMessage(row)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// resetSequences moves serial sequences past the restored IDs.
+func resetSequences(tx *gorm.DB, tables []string) error {
+	var columns []struct {
+		TableName  string
+		ColumnName string
+	}
+	if err := tx.Raw(`
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name IN ? AND column_default LIKE 'nextval(%'`, tables).
+		Scan(&columns).Error; err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		table, name := quoteIdent(column.TableName), quoteIdent(column.ColumnName)
+		if err := tx.Exec(
+			"SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX("+name+"), 1), MAX("+name+") IS NOT NULL) FROM "+table,
+			column.TableName, column.ColumnName,
+		).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dependencyOrder sorts tables so every table comes after the tables it
+// references. Ties keep alphabetical order; cycles are appended as is.
+func dependencyOrder(tables []string, parents map[string][]string) []string {
+	pending := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		pending[table] = true
+	}
+
+	ordered := make([]string, 0, len(tables))
+	for len(pending) > 0 {
+		var ready []string
+		for table := range pending {
+			blocked := false
+			for _, parent := range parents[table] {
+				if pending[parent] {
+					blocked = true
+					break
+				}
+			}
+			if !blocked {
+				ready = append(ready, table)
+			}
+		}
+		if len(ready) == 0 {
+			for table := range pending {
+				ready = append(ready, table)
+			}
+		}
+		sort.Strings(ready)
+		for _, table := range ready {
+			delete(pending, table)
+		}
+		ordered = append(ordered, ready...)
+	}
+	return ordered
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,130 @@
+package budgets
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	budgetsdomain "family-app-go/internal/domain/budgets"
+	familydomain "family-app-go/internal/domain/family"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const budgetColumns = "budgets.*, categories.name AS category_name"
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListBudgets(ctx context.Context, familyID string) ([]budgetsdomain.Budget, error) {
+	var budgets []budgetsdomain.Budget
+	if err := r.withCategory(ctx).
+		Where("budgets.family_id = ?", familyID).
+		Order("categories.name asc, budgets.created_at asc").
+		Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *PostgresRepository) GetBudget(ctx context.Context, familyID, budgetID string) (*budgetsdomain.Budget, error) {
+	var budget budgetsdomain.Budget
+	if err := r.withCategory(ctx).
+		Where("budgets.family_id = ? AND budgets.id = ?", familyID, budgetID).
+		First(&budget).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, budgetsdomain.ErrBudgetNotFound
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (r *PostgresRepository) CreateBudget(ctx context.Context, budget *budgetsdomain.Budget) error {
+	if err := r.db.WithContext(ctx).Create(budget).Error; err != nil {
+		if isUniqueViolation(err) {
+			return budgetsdomain.ErrBudgetExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UpdateBudget(ctx context.Context, budget *budgetsdomain.Budget) error {
+	return r.db.WithContext(ctx).
+		Model(&budgetsdomain.Budget{}).
+		Where("id = ? AND family_id = ?", budget.ID, budget.FamilyID).
+		Updates(map[string]interface{}{
+			"amount":     budget.Amount,
+			"currency":   budget.Currency,
+			"updated_at": time.Now().UTC(),
+		}).Error
+}
+
+func (r *PostgresRepository) DeleteBudget(ctx context.Context, familyID, budgetID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&budgetsdomain.Budget{}, "id = ? AND family_id = ?", budgetID, familyID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) CategoryExists(ctx context.Context, familyID, categoryID string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Table("categories").
+		Where("family_id = ? AND id = ?", familyID, categoryID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *PostgresRepository) ListBudgetFamilies(ctx context.Context) ([]string, error) {
+	var familyIDs []string
+	if err := r.db.WithContext(ctx).
+		Model(&budgetsdomain.Budget{}).
+		Distinct("family_id").
+		Order("family_id").
+		Pluck("family_id", &familyIDs).Error; err != nil {
+		return nil, err
+	}
+	return familyIDs, nil
+}
+
+func (r *PostgresRepository) ListAlertRecipients(ctx context.Context, familyID string) ([]string, error) {
+	var userIDs []string
+	if err := r.db.WithContext(ctx).
+		Model(&familydomain.FamilyMember{}).
+		Where("family_id = ? AND role <> ?", familyID, familydomain.RoleChild).
+		Order("joined_at asc").
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (r *PostgresRepository) CreateAlert(ctx context.Context, alert *budgetsdomain.Alert) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "budget_id"}, {Name: "period"}, {Name: "threshold"}},
+			DoNothing: true,
+		}).
+		Create(alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) withCategory(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&budgetsdomain.Budget{}).
+		Select(budgetColumns).
+		Joins("JOIN categories ON categories.id = budgets.category_id AND categories.family_id = budgets.family_id")
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
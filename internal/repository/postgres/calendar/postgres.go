@@ -0,0 +1,51 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+
+	calendardomain "family-app-go/internal/domain/calendar"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) GetFeedKey(ctx context.Context, userID string) (*calendardomain.FeedKey, error) {
+	var key calendardomain.FeedKey
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, calendardomain.ErrFeedKeyNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// EnsureFeedKey reads the key back after the insert, so concurrent first
+// requests agree on one nonce.
+func (r *PostgresRepository) EnsureFeedKey(ctx context.Context, key *calendardomain.FeedKey) (*calendardomain.FeedKey, error) {
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(key).Error; err != nil {
+		return nil, err
+	}
+	return r.GetFeedKey(ctx, key.UserID)
+}
+
+func (r *PostgresRepository) ReplaceFeedKey(ctx context.Context, key *calendardomain.FeedKey) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"nonce", "created_at"}),
+		}).
+		Create(key).Error
+}
@@ -0,0 +1,202 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	commentsdomain "family-app-go/internal/domain/comments"
+	familydomain "family-app-go/internal/domain/family"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateComment(ctx context.Context, comment *commentsdomain.Comment, mentions []commentsdomain.Mention) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return createMentions(tx, mentions)
+	})
+}
+
+func (r *PostgresRepository) GetComment(ctx context.Context, familyID, commentID string) (*commentsdomain.Comment, error) {
+	var comment commentsdomain.Comment
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND id = ?", familyID, commentID).
+		First(&comment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commentsdomain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *PostgresRepository) UpdateComment(ctx context.Context, comment *commentsdomain.Comment, mentions []commentsdomain.Mention) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Select writes through the model so the body is encrypted.
+		result := tx.Model(comment).
+			Select("body", "updated_at").
+			Where("family_id = ?", comment.FamilyID).
+			Updates(comment)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return commentsdomain.ErrCommentNotFound
+		}
+		return createMentions(tx, mentions)
+	})
+}
+
+// createMentions skips members the comment already mentions.
+func createMentions(tx *gorm.DB, mentions []commentsdomain.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	return tx.Omit("Comment").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "comment_id"}, {Name: "user_id"}},
+			DoNothing: true,
+		}).
+		Create(&mentions).Error
+}
+
+func (r *PostgresRepository) DeleteComment(ctx context.Context, familyID, commentID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&commentsdomain.Comment{}, "family_id = ? AND id = ?", familyID, commentID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListComments(ctx context.Context, familyID string, entityType commentsdomain.EntityType, entityID string, filter commentsdomain.ListFilter) ([]commentsdomain.Comment, int64, error) {
+	query := r.db.WithContext(ctx).Model(&commentsdomain.Comment{}).
+		Where("family_id = ? AND entity_type = ? AND entity_id = ?", familyID, entityType, entityID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []commentsdomain.Comment
+	if err := query.Order("created_at, id").Limit(filter.Limit).Offset(filter.Offset).Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *PostgresRepository) MarkRead(ctx context.Context, read *commentsdomain.Read) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "entity_type"}, {Name: "entity_id"}},
+			DoUpdates: clause.Set{{
+				Column: clause.Column{Name: "last_read_at"},
+				Value:  gorm.Expr("GREATEST(comment_reads.last_read_at, excluded.last_read_at)"),
+			}},
+		}).
+		Create(read).Error
+}
+
+func (r *PostgresRepository) CountComments(ctx context.Context, familyID string, entityType commentsdomain.EntityType, entityIDs []string, userID string) (map[string]commentsdomain.Counts, error) {
+	var rows []struct {
+		EntityID string
+		Total    int64
+		Unread   int64
+	}
+	err := r.db.WithContext(ctx).Raw(`SELECT c.entity_id,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE c.author_id <> ? AND (rd.last_read_at IS NULL OR c.created_at > rd.last_read_at)) AS unread
+		FROM comments c
+		LEFT JOIN comment_reads rd ON rd.user_id = ? AND rd.entity_type = c.entity_type AND rd.entity_id = c.entity_id
+		WHERE c.family_id = ? AND c.entity_type = ? AND c.entity_id IN ?
+		GROUP BY c.entity_id`,
+		userID, userID, familyID, entityType, entityIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]commentsdomain.Counts, len(rows))
+	for _, row := range rows {
+		counts[row.EntityID] = commentsdomain.Counts{Total: row.Total, Unread: row.Unread}
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) DeleteByEntity(ctx context.Context, entityType commentsdomain.EntityType, entityID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&commentsdomain.Comment{}, "entity_type = ? AND entity_id = ?", entityType, entityID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&commentsdomain.Read{}, "entity_type = ? AND entity_id = ?", entityType, entityID).Error
+	})
+}
+
+func (r *PostgresRepository) ListMembers(ctx context.Context, familyID string) ([]commentsdomain.Member, error) {
+	var rows []struct {
+		UserID string
+		Email  *string
+		Role   string
+	}
+	if err := r.db.WithContext(ctx).
+		Table("family_members").
+		Select("family_members.user_id, user_profiles.email, family_members.role").
+		Joins("left join user_profiles on user_profiles.user_id = family_members.user_id").
+		Where("family_members.family_id = ?", familyID).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	members := make([]commentsdomain.Member, 0, len(rows))
+	for _, row := range rows {
+		member := commentsdomain.Member{UserID: row.UserID, Child: row.Role == familydomain.RoleChild}
+		if row.Email != nil {
+			member.Email = *row.Email
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (r *PostgresRepository) ListMentions(ctx context.Context, familyID, userID string, filter commentsdomain.MentionFilter) (*commentsdomain.MentionPage, error) {
+	base := r.db.WithContext(ctx).Model(&commentsdomain.Mention{}).
+		Where("family_id = ? AND user_id = ?", familyID, userID)
+
+	page := &commentsdomain.MentionPage{}
+	if err := base.Session(&gorm.Session{}).Where("read_at IS NULL").Count(&page.Unread).Error; err != nil {
+		return nil, err
+	}
+
+	query := base.Session(&gorm.Session{})
+	if filter.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+	if err := query.Preload("Comment").
+		Order("created_at DESC, id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&page.Items).Error; err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (r *PostgresRepository) MarkMentionsRead(ctx context.Context, familyID, userID string, mentionIDs []string, readAt time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&commentsdomain.Mention{}).
+		Where("family_id = ? AND user_id = ? AND read_at IS NULL", familyID, userID)
+	if len(mentionIDs) > 0 {
+		query = query.Where("id IN ?", mentionIDs)
+	}
+	result := query.Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,121 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	erasuredomain "family-app-go/internal/domain/erasure"
+	"gorm.io/gorm"
+)
+
+// userDataDeletes remove rows owned by a single user. Each statement takes the
+// user ID as its only parameter. Sets of workouts and gym sessions go with
+// their parents through ON DELETE CASCADE, refresh tokens with the account.
+var userDataDeletes = []string{
+	"DELETE FROM wishlist_items WHERE owner_id = ?",
+	"DELETE FROM gym_entries WHERE user_id = ?",
+	"DELETE FROM workouts WHERE user_id = ?",
+	"DELETE FROM entity_labels WHERE owner_id = ?",
+	"DELETE FROM workout_templates WHERE user_id = ?",
+	"DELETE FROM gym_sessions WHERE user_id = ?",
+	"DELETE FROM gym_goals WHERE user_id = ?",
+	"DELETE FROM gym_goal_nudges WHERE user_id = ?",
+	"DELETE FROM gym_personal_record_events WHERE user_id = ?",
+	"DELETE FROM sync_operations WHERE user_id = ?",
+	"DELETE FROM sync_batches WHERE user_id = ?",
+	"DELETE FROM api_keys WHERE user_id = ?",
+	"DELETE FROM calendar_feed_keys WHERE user_id = ?",
+	"DELETE FROM notifications WHERE user_id = ?",
+	"DELETE FROM user_sessions WHERE user_id = ?",
+	"DELETE FROM saved_views WHERE user_id = ?",
+	"DELETE FROM comments WHERE author_id = ?",
+	"DELETE FROM comment_reads WHERE user_id = ?",
+	"DELETE FROM comment_mentions WHERE user_id = ?",
+	"DELETE FROM poll_votes WHERE user_id = ?",
+	"DELETE FROM auth_accounts WHERE id = ?",
+	"DELETE FROM user_profiles WHERE user_id = ?",
+	"DELETE FROM profile_sync_outbox WHERE user_id = ?",
+}
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateRequest(ctx context.Context, request *erasuredomain.DeletionRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+func (r *PostgresRepository) UpdateRequest(ctx context.Context, request *erasuredomain.DeletionRequest) error {
+	return r.db.WithContext(ctx).
+		Model(&erasuredomain.DeletionRequest{}).
+		Where("id = ?", request.ID).
+		Updates(map[string]interface{}{
+			"status":       request.Status,
+			"cancelled_at": request.CancelledAt,
+			"completed_at": request.CompletedAt,
+			"updated_at":   request.UpdatedAt,
+		}).Error
+}
+
+func (r *PostgresRepository) GetScheduledRequest(ctx context.Context, subject, subjectID string) (*erasuredomain.DeletionRequest, error) {
+	var request erasuredomain.DeletionRequest
+	if err := r.db.WithContext(ctx).
+		Where("subject = ? AND subject_id = ? AND status = ?", subject, subjectID, erasuredomain.StatusScheduled).
+		First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, erasuredomain.ErrDeletionNotFound
+		}
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *PostgresRepository) ListDueRequests(ctx context.Context, before time.Time, limit int) ([]erasuredomain.DeletionRequest, error) {
+	query := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_for <= ?", erasuredomain.StatusScheduled, before).
+		Order("scheduled_for ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var requests []erasuredomain.DeletionRequest
+	if err := query.Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *PostgresRepository) EraseUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Family data the user created stays with the family; only the
+		// snapshots of who they were are anonymized.
+		if err := tx.Exec(`UPDATE todo_items
+			SET completed_by_id = NULL, completed_by_name = ?, completed_by_email = NULL, completed_by_avatar_url = NULL
+			WHERE completed_by_id = ?`, erasuredomain.AnonymizedName, userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE todo_items SET assignee_id = NULL WHERE assignee_id = ?", userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE family_activity_events
+			SET actor_name = ?, actor_email = '', actor_avatar_url = NULL
+			WHERE actor_id = ?`, erasuredomain.AnonymizedName, userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE wishlist_items SET claimed_by_id = NULL, claimed_at = NULL WHERE claimed_by_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		for _, statement := range userDataDeletes {
+			if err := tx.Exec(statement, userID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
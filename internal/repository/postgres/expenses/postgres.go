@@ -3,9 +3,14 @@ package expenses
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
+	"family-app-go/internal/db"
 	expensesdomain "family-app-go/internal/domain/expenses"
+	"family-app-go/pkg/fieldcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostgresRepository struct {
@@ -23,34 +28,13 @@ func (r *PostgresRepository) Transaction(ctx context.Context, fn func(expensesdo
 }
 
 func (r *PostgresRepository) ListExpenses(ctx context.Context, familyID string, filter expensesdomain.ListFilter) ([]expensesdomain.Expense, int64, error) {
-	query := r.db.WithContext(ctx).Model(&expensesdomain.Expense{}).Where("family_id = ?", familyID)
-	if filter.From != nil {
-		query = query.Where("date >= ?", *filter.From)
-	}
-	if filter.To != nil {
-		query = query.Where("date <= ?", *filter.To)
-	}
-	if filter.Currency != "" {
-		query = query.Where("currency = ?", filter.Currency)
-	}
-	if len(filter.CategoryIDs) > 0 {
-		query = query.Joins("join expense_categories on expense_categories.expense_id = expenses.id").Where("expense_categories.category_id IN ?", filter.CategoryIDs)
-	}
-
-	countQuery := query.Session(&gorm.Session{})
-	if len(filter.CategoryIDs) > 0 {
-		countQuery = countQuery.Distinct("expenses.id")
-	}
+	query := r.filteredExpenses(ctx, familyID, filter)
 
 	var total int64
-	if err := countQuery.Count(&total).Error; err != nil {
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if len(filter.CategoryIDs) > 0 {
-		query = query.Distinct()
-	}
-
 	query = query.Order("date desc, created_at desc")
 	if filter.Limit > 0 {
 		query = query.Limit(filter.Limit)
@@ -67,6 +51,101 @@ func (r *PostgresRepository) ListExpenses(ctx context.Context, familyID string,
 	return items, total, nil
 }
 
+// SummarizeExpenses totals the expenses matching filter per currency in one
+// grouped query. Limit and offset are ignored.
+func (r *PostgresRepository) SummarizeExpenses(ctx context.Context, familyID, baseCurrency string, filter expensesdomain.ListFilter) ([]expensesdomain.CurrencyTotal, error) {
+	var totals []expensesdomain.CurrencyTotal
+	err := r.filteredExpenses(ctx, familyID, filter).
+		Select(`currency,
+			COALESCE(SUM(amount), 0) AS amount,
+			COUNT(*) AS count,
+			COALESCE(SUM(amount_in_base) FILTER (WHERE base_currency = ?), 0) AS amount_in_base,
+			COUNT(*) FILTER (WHERE amount_in_base IS NULL OR base_currency IS DISTINCT FROM ?) AS unconverted`, baseCurrency, baseCurrency).
+		Group("currency").
+		Order("currency").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// filteredExpenses applies the list filter, without paging or ordering.
+func (r *PostgresRepository) filteredExpenses(ctx context.Context, familyID string, filter expensesdomain.ListFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Clauses(db.ReadReplica).Model(&expensesdomain.Expense{}).Where("family_id = ?", familyID)
+	if filter.From != nil {
+		query = query.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("date <= ?", *filter.To)
+	}
+	if filter.Currency != "" {
+		query = query.Where("currency = ?", filter.Currency)
+	}
+	switch filter.Archived {
+	case expensesdomain.ArchivedOnly:
+		query = query.Where("expenses.is_archived = ?", true)
+	case expensesdomain.ArchivedExclude:
+		query = query.Where("expenses.is_archived = ?", false)
+	}
+	if search := strings.TrimSpace(filter.Query); search != "" {
+		matches, err := r.encryptedTitleMatches(ctx, familyID, search)
+		switch {
+		case err != nil:
+			_ = query.AddError(err)
+		case len(matches) > 0:
+			query = query.Where("(expenses.title ILIKE ? OR expenses.id IN ?)", "%"+search+"%", matches)
+		default:
+			query = query.Where("expenses.title ILIKE ?", "%"+search+"%")
+		}
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM expense_categories WHERE expense_categories.expense_id = expenses.id AND expense_categories.category_id IN ?)",
+			filter.CategoryIDs,
+		)
+	}
+	return query
+}
+
+type storedTitle struct {
+	ID    string
+	Title string
+}
+
+// encryptedTitleMatches returns the IDs of the family's expenses with an
+// encrypted title containing search. SQL only sees the ciphertext of those
+// titles, so they are decrypted and matched here; plaintext titles are left
+// to ILIKE. Without a keyring nothing is encrypted.
+func (r *PostgresRepository) encryptedTitleMatches(ctx context.Context, familyID, search string) ([]string, error) {
+	if fieldcrypt.Active() == nil {
+		return nil, nil
+	}
+	var titles []storedTitle
+	if err := r.db.WithContext(ctx).Clauses(db.ReadReplica).
+		Table("expenses").
+		Select("id, title").
+		Where("family_id = ? AND title LIKE ?", familyID, fieldcrypt.EncryptedLike).
+		Scan(&titles).Error; err != nil {
+		return nil, err
+	}
+	return matchTitles(titles, search)
+}
+
+// matchTitles decrypts titles and returns the IDs of those containing
+// search, ignoring case as ILIKE does.
+func matchTitles(titles []storedTitle, search string) ([]string, error) {
+	needle := strings.ToLower(search)
+	var ids []string
+	for _, stored := range titles {
+		title, err := fieldcrypt.Decrypt(stored.Title)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(strings.ToLower(title), needle) {
+			ids = append(ids, stored.ID)
+		}
+	}
+	return ids, nil
+}
+
 func (r *PostgresRepository) GetExpenseByID(ctx context.Context, familyID, expenseID string) (*expensesdomain.Expense, error) {
 	var expense expensesdomain.Expense
 	if err := r.db.WithContext(ctx).
@@ -85,21 +164,56 @@ func (r *PostgresRepository) CreateExpense(ctx context.Context, expense *expense
 }
 
 func (r *PostgresRepository) UpdateExpense(ctx context.Context, expense *expensesdomain.Expense) error {
-	return r.db.WithContext(ctx).
+	result := r.db.WithContext(ctx).
 		Model(&expensesdomain.Expense{}).
-		Where("id = ? AND family_id = ?", expense.ID, expense.FamilyID).
+		Where("id = ? AND family_id = ? AND version = ?", expense.ID, expense.FamilyID, expense.Version).
 		Updates(map[string]interface{}{
-			"date":           expense.Date,
-			"amount":         expense.Amount,
-			"currency":       expense.Currency,
-			"base_currency":  expense.BaseCurrency,
-			"exchange_rate":  expense.ExchangeRate,
-			"amount_in_base": expense.AmountInBase,
-			"rate_date":      expense.RateDate,
-			"rate_source":    expense.RateSource,
-			"title":          expense.Title,
-			"updated_at":     expense.UpdatedAt,
-		}).Error
+			"date":             expense.Date,
+			"amount":           expense.Amount,
+			"currency":         expense.Currency,
+			"base_currency":    expense.BaseCurrency,
+			"exchange_rate":    expense.ExchangeRate,
+			"amount_in_base":   expense.AmountInBase,
+			"rate_date":        expense.RateDate,
+			"rate_source":      expense.RateSource,
+			"title":            fieldcrypt.Value(expense.Title),
+			"auto_categorized": expense.AutoCategorized,
+			"is_archived":      expense.IsArchived,
+			"latitude":         expense.Latitude,
+			"longitude":        expense.Longitude,
+			"place_name":       fieldcrypt.Value(expense.PlaceName),
+			"updated_at":       expense.UpdatedAt,
+			"version":          gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return expensesdomain.ErrVersionConflict
+	}
+	expense.Version++
+	return nil
+}
+
+func (r *PostgresRepository) ArchiveExpensesBefore(ctx context.Context, familyID string, before, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&expensesdomain.Expense{}).
+		Where("family_id = ? AND date < ? AND is_archived = ?", familyID, before, false).
+		Updates(map[string]interface{}{
+			"is_archived": true,
+			"updated_at":  at,
+			"version":     gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *PostgresRepository) CountExpensesCreatedSince(ctx context.Context, familyID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&expensesdomain.Expense{}).
+		Where("family_id = ? AND created_at >= ?", familyID, since).
+		Count(&count).Error
+	return count, err
 }
 
 func (r *PostgresRepository) DeleteExpense(ctx context.Context, familyID, expenseID string) (bool, error) {
@@ -191,14 +305,23 @@ func (r *PostgresRepository) GetCategoryByID(ctx context.Context, familyID, cate
 }
 
 func (r *PostgresRepository) UpdateCategory(ctx context.Context, category *expensesdomain.Category) error {
-	return r.db.WithContext(ctx).
+	result := r.db.WithContext(ctx).
 		Model(&expensesdomain.Category{}).
-		Where("id = ? AND family_id = ?", category.ID, category.FamilyID).
+		Where("id = ? AND family_id = ? AND version = ?", category.ID, category.FamilyID, category.Version).
 		Updates(map[string]interface{}{
-			"name":  category.Name,
-			"color": category.Color,
-			"emoji": category.Emoji,
-		}).Error
+			"name":    category.Name,
+			"color":   category.Color,
+			"emoji":   category.Emoji,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return expensesdomain.ErrVersionConflict
+	}
+	category.Version++
+	return nil
 }
 
 func (r *PostgresRepository) CountCategoriesByName(ctx context.Context, familyID, name, excludeID string) (int64, error) {
@@ -230,3 +353,150 @@ func (r *PostgresRepository) CountExpenseCategoriesByCategoryID(ctx context.Cont
 	}
 	return count, nil
 }
+
+func (r *PostgresRepository) ListCategoryUsage(ctx context.Context, familyID string) ([]expensesdomain.CategoryUsage, error) {
+	var usage []expensesdomain.CategoryUsage
+	if err := r.db.WithContext(ctx).
+		Clauses(db.ReadReplica).
+		Table("expense_categories").
+		Select("expense_categories.category_id, COUNT(*) AS expense_count, MAX(expenses.created_at) AS last_used_at").
+		Joins("JOIN expenses ON expenses.id = expense_categories.expense_id").
+		Where("expenses.family_id = ?", familyID).
+		Group("expense_categories.category_id").
+		Scan(&usage).Error; err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (r *PostgresRepository) ListCategoryRules(ctx context.Context, familyID string) ([]expensesdomain.CategoryRule, error) {
+	var rules []expensesdomain.CategoryRule
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ?", familyID).
+		Order("priority desc, created_at asc, id asc").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *PostgresRepository) GetCategoryRuleByID(ctx context.Context, familyID, ruleID string) (*expensesdomain.CategoryRule, error) {
+	var rule expensesdomain.CategoryRule
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND id = ?", familyID, ruleID).
+		First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, expensesdomain.ErrCategoryRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *PostgresRepository) CreateCategoryRule(ctx context.Context, rule *expensesdomain.CategoryRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+func (r *PostgresRepository) UpdateCategoryRule(ctx context.Context, rule *expensesdomain.CategoryRule) error {
+	result := r.db.WithContext(ctx).
+		Model(&expensesdomain.CategoryRule{}).
+		Where("id = ? AND family_id = ?", rule.ID, rule.FamilyID).
+		Updates(map[string]interface{}{
+			"pattern":     rule.Pattern,
+			"match_type":  rule.MatchType,
+			"category_id": rule.CategoryID,
+			"priority":    rule.Priority,
+			"updated_at":  rule.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return expensesdomain.ErrCategoryRuleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteCategoryRule(ctx context.Context, familyID, ruleID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&expensesdomain.CategoryRule{}, "family_id = ? AND id = ?", familyID, ruleID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListCategorizedTitles(ctx context.Context, familyID string, since time.Time, limit int) ([]expensesdomain.CategorizedTitle, error) {
+	var rows []expensesdomain.CategorizedTitle
+	if err := r.db.WithContext(ctx).
+		Table("expenses").
+		Select("expenses.title, expense_categories.category_id").
+		Joins("JOIN expense_categories ON expense_categories.expense_id = expenses.id").
+		Where("expenses.family_id = ? AND expenses.date >= ?", familyID, since).
+		Order("expenses.date desc, expenses.created_at desc").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *PostgresRepository) CreateExpenseApproval(ctx context.Context, approval *expensesdomain.ExpenseApproval) error {
+	return r.db.WithContext(ctx).Create(approval).Error
+}
+
+func (r *PostgresRepository) ListExpenseApprovals(ctx context.Context, familyID string, filter expensesdomain.ApprovalFilter) ([]expensesdomain.ExpenseApproval, error) {
+	query := r.db.WithContext(ctx).Where("family_id = ?", familyID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	var approvals []expensesdomain.ExpenseApproval
+	if err := query.Order("created_at DESC").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+func (r *PostgresRepository) LockExpenseApproval(ctx context.Context, familyID, approvalID string) (*expensesdomain.ExpenseApproval, error) {
+	var approval expensesdomain.ExpenseApproval
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("family_id = ? AND id = ?", familyID, approvalID).
+		First(&approval).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, expensesdomain.ErrApprovalNotFound
+		}
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (r *PostgresRepository) ClusterExpenseLocations(ctx context.Context, familyID string, filter expensesdomain.GeoFilter) ([]expensesdomain.GeoCluster, error) {
+	latStep, lngStep := filter.CellSize()
+	lastCell := filter.Grid - 1
+	var clusters []expensesdomain.GeoCluster
+	err := r.filteredExpenses(ctx, familyID, expensesdomain.ListFilter{From: filter.From, To: filter.To, CategoryIDs: filter.CategoryIDs}).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", filter.South, filter.North, filter.West, filter.East).
+		Select(`LEAST(FLOOR((latitude - ?) / ?), ?) AS cell_row,
+			LEAST(FLOOR((longitude - ?) / ?), ?) AS cell_col,
+			AVG(latitude) AS latitude,
+			AVG(longitude) AS longitude,
+			COUNT(*) AS count,
+			COALESCE(SUM(amount_in_base), 0) AS amount_in_base,
+			MIN(id::text) AS expense_id`,
+			filter.South, latStep, lastCell, filter.West, lngStep, lastCell).
+		Group("cell_row, cell_col").
+		Scan(&clusters).Error
+	return clusters, err
+}
+
+func (r *PostgresRepository) UpdateExpenseApproval(ctx context.Context, approval *expensesdomain.ExpenseApproval) error {
+	return r.db.WithContext(ctx).Model(&expensesdomain.ExpenseApproval{}).
+		Where("family_id = ? AND id = ?", approval.FamilyID, approval.ID).
+		Updates(map[string]interface{}{
+			"status":     approval.Status,
+			"expense_id": approval.ExpenseID,
+			"decided_by": approval.DecidedBy,
+			"decided_at": approval.DecidedAt,
+		}).Error
+}
@@ -0,0 +1,53 @@
+package expenses
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"family-app-go/pkg/fieldcrypt"
+)
+
+func TestMatchTitlesFindsEncryptedTitles(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring([]fieldcrypt.Key{{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)}})
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	fieldcrypt.Use(keyring)
+	t.Cleanup(func() { fieldcrypt.Use(nil) })
+
+	var titles []storedTitle
+	for id, title := range map[string]string{"exp-1": "Groceries at Lidl", "exp-2": "Rent"} {
+		sealed, err := fieldcrypt.Encrypt(title)
+		if err != nil {
+			t.Fatalf("encrypt: %v", err)
+		}
+		titles = append(titles, storedTitle{ID: id, Title: sealed})
+	}
+
+	ids, err := matchTitles(titles, "LIDL")
+	if err != nil {
+		t.Fatalf("match: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"exp-1"}) {
+		t.Fatalf("ids = %v, want [exp-1]", ids)
+	}
+	if ids, err := matchTitles(titles, "coffee"); err != nil || len(ids) != 0 {
+		t.Fatalf("expected no match, got %v, %v", ids, err)
+	}
+}
+
+func TestMatchTitlesFailsWithoutTheKey(t *testing.T) {
+	keyring, err := fieldcrypt.NewKeyring([]fieldcrypt.Key{{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)}})
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	sealed, err := keyring.Encrypt("Groceries")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	if _, err := matchTitles([]storedTitle{{ID: "exp-1", Title: sealed}}, "groceries"); err == nil {
+		t.Fatal("expected an error for a title no key opens")
+	}
+}
@@ -0,0 +1,223 @@
+package exports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	exportsdomain "family-app-go/internal/domain/exports"
+	"family-app-go/pkg/fieldcrypt"
+	"gorm.io/gorm"
+)
+
+type datasetQuery struct {
+	name  string
+	query string
+}
+
+// datasetQueries list everything exported for a family. Each query takes the
+// family ID as its only parameter. Soft-deleted todo rows are left out.
+var datasetQueries = []datasetQuery{
+	{"family", `SELECT id, name, owner_id, default_currency, created_at, updated_at
+		FROM families WHERE id = ?`},
+	{"members", `SELECT m.user_id, m.role, m.joined_at, p.email, p.avatar_url
+		FROM family_members m
+		LEFT JOIN user_profiles p ON p.user_id = m.user_id
+		WHERE m.family_id = ?
+		ORDER BY m.joined_at`},
+	{"categories", `SELECT * FROM categories WHERE family_id = ? ORDER BY created_at, id`},
+	{"expenses", `SELECT * FROM expenses WHERE family_id = ? ORDER BY date, created_at, id`},
+	{"expense_categories", `SELECT ec.expense_id, ec.category_id
+		FROM expense_categories ec
+		JOIN expenses e ON e.id = ec.expense_id
+		WHERE e.family_id = ?
+		ORDER BY ec.expense_id, ec.category_id`},
+	{"todo_lists", `SELECT * FROM todo_lists WHERE family_id = ? AND deleted_at IS NULL ORDER BY created_at, id`},
+	{"todo_items", `SELECT i.*
+		FROM todo_items i
+		JOIN todo_lists l ON l.id = i.list_id
+		WHERE l.family_id = ? AND l.deleted_at IS NULL AND i.deleted_at IS NULL
+		ORDER BY i.list_id, i.created_at, i.id`},
+	{"gym_entries", `SELECT * FROM gym_entries WHERE family_id = ? ORDER BY date, created_at, id`},
+	{"workouts", `SELECT * FROM workouts WHERE family_id = ? ORDER BY date, created_at, id`},
+	{"workout_sets", `SELECT s.*
+		FROM workout_sets s
+		JOIN workouts w ON w.id = s.workout_id
+		WHERE w.family_id = ?
+		ORDER BY s.workout_id, s.set_order, s.id`},
+	{"gym_sessions", `SELECT s.*
+		FROM gym_sessions s
+		JOIN family_members m ON m.user_id = s.user_id
+		WHERE m.family_id = ?
+		ORDER BY s.started_at, s.id`},
+	{"gym_session_sets", `SELECT ss.*
+		FROM gym_session_sets ss
+		JOIN gym_sessions s ON s.id = ss.session_id
+		JOIN family_members m ON m.user_id = s.user_id
+		WHERE m.family_id = ?
+		ORDER BY ss.session_id, ss.set_order, ss.id`},
+	{"gym_goals", `SELECT g.*
+		FROM gym_goals g
+		JOIN family_members m ON m.user_id = g.user_id
+		WHERE m.family_id = ?
+		ORDER BY g.user_id`},
+}
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateExport(ctx context.Context, export *exportsdomain.Export) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *PostgresRepository) UpdateExport(ctx context.Context, export *exportsdomain.Export) error {
+	return r.db.WithContext(ctx).
+		Model(&exportsdomain.Export{}).
+		Where("id = ?", export.ID).
+		Updates(map[string]interface{}{
+			"status":       export.Status,
+			"storage_key":  export.StorageKey,
+			"size_bytes":   export.SizeBytes,
+			"error":        export.Error,
+			"completed_at": export.CompletedAt,
+			"expires_at":   export.ExpiresAt,
+			"updated_at":   export.UpdatedAt,
+		}).Error
+}
+
+func (r *PostgresRepository) GetExport(ctx context.Context, id string) (*exportsdomain.Export, error) {
+	var export exportsdomain.Export
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exportsdomain.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *PostgresRepository) GetActiveExport(ctx context.Context, familyID string) (*exportsdomain.Export, error) {
+	var export exportsdomain.Export
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND status IN ?", familyID, []string{exportsdomain.StatusPending, exportsdomain.StatusProcessing}).
+		Order("created_at DESC").
+		First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exportsdomain.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ListPendingExports also picks up exports left in processing by an
+// interrupted run; the job lock guarantees no other run still owns them.
+func (r *PostgresRepository) ListPendingExports(ctx context.Context, limit int) ([]exportsdomain.Export, error) {
+	query := r.db.WithContext(ctx).
+		Where("status IN ?", []string{exportsdomain.StatusPending, exportsdomain.StatusProcessing}).
+		Order("created_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var exports []exportsdomain.Export
+	if err := query.Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *PostgresRepository) ListFamilyExports(ctx context.Context, familyID string) ([]exportsdomain.Export, error) {
+	var exports []exportsdomain.Export
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ?", familyID).
+		Order("created_at ASC").
+		Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *PostgresRepository) ListExpiredExports(ctx context.Context, before time.Time, limit int) ([]exportsdomain.Export, error) {
+	query := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", exportsdomain.StatusReady, before).
+		Order("expires_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var exports []exportsdomain.Export
+	if err := query.Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *PostgresRepository) LoadDatasets(ctx context.Context, familyID string) ([]exportsdomain.Dataset, error) {
+	datasets := make([]exportsdomain.Dataset, 0, len(datasetQueries))
+	for _, dq := range datasetQueries {
+		dataset, err := r.loadDataset(ctx, dq, familyID)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", dq.name, err)
+		}
+		datasets = append(datasets, dataset)
+	}
+	return datasets, nil
+}
+
+func (r *PostgresRepository) loadDataset(ctx context.Context, dq datasetQuery, familyID string) (exportsdomain.Dataset, error) {
+	rows, err := r.db.WithContext(ctx).Raw(dq.query, familyID).Rows()
+	if err != nil {
+		return exportsdomain.Dataset{}, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return exportsdomain.Dataset{}, err
+	}
+
+	dataset := exportsdomain.Dataset{Name: dq.name, Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		targets := make([]interface{}, len(columns))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return exportsdomain.Dataset{}, err
+		}
+		for i, value := range values {
+			values[i] = exportValue(value)
+			// Encrypted columns are exported in clear text.
+			if text, ok := values[i].(string); ok && fieldcrypt.IsEncrypted(text) {
+				if values[i], err = fieldcrypt.Decrypt(text); err != nil {
+					return exportsdomain.Dataset{}, fmt.Errorf("column %s: %w", columns[i], err)
+				}
+			}
+		}
+		dataset.Rows = append(dataset.Rows, values)
+	}
+	return dataset, rows.Err()
+}
+
+// exportValue turns driver values into JSON-friendly ones: timestamps become
+// RFC 3339 strings and raw bytes (numerics, uuids, jsonb) become text.
+func exportValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case []byte:
+		return string(v)
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+	default:
+		return v
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	familydomain "family-app-go/internal/domain/family"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostgresRepository struct {
@@ -40,20 +41,23 @@ func (r *PostgresRepository) GetFamilyByUser(ctx context.Context, userID string)
 	return &family, nil
 }
 
-func (r *PostgresRepository) GetFamilyByCode(ctx context.Context, code string) (*familydomain.Family, error) {
-	var family familydomain.Family
-	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&family).Error; err != nil {
+func (r *PostgresRepository) GetMemberByUser(ctx context.Context, userID string) (*familydomain.FamilyMember, error) {
+	var member familydomain.FamilyMember
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&member).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, familydomain.ErrFamilyCodeNotFound
+			return nil, familydomain.ErrFamilyNotFound
 		}
 		return nil, err
 	}
-	return &family, nil
+	return &member, nil
 }
 
-func (r *PostgresRepository) GetMemberByUser(ctx context.Context, userID string) (*familydomain.FamilyMember, error) {
+func (r *PostgresRepository) GetMemberWithFamilyByUser(ctx context.Context, userID string) (*familydomain.FamilyMember, error) {
 	var member familydomain.FamilyMember
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&member).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		InnerJoins("Family").
+		Where("family_members.user_id = ?", userID).
+		First(&member).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, familydomain.ErrFamilyNotFound
 		}
@@ -86,17 +90,18 @@ func (r *PostgresRepository) ListMembers(ctx context.Context, familyID string) (
 
 func (r *PostgresRepository) ListMembersWithProfiles(ctx context.Context, familyID string) ([]familydomain.FamilyMemberProfile, error) {
 	type memberRow struct {
-		UserID    string    `gorm:"column:user_id"`
-		Role      string    `gorm:"column:role"`
-		JoinedAt  time.Time `gorm:"column:joined_at"`
-		Email     *string   `gorm:"column:email"`
-		AvatarURL *string   `gorm:"column:avatar_url"`
+		UserID        string    `gorm:"column:user_id"`
+		Role          string    `gorm:"column:role"`
+		SpendingLimit *float64  `gorm:"column:spending_limit"`
+		JoinedAt      time.Time `gorm:"column:joined_at"`
+		Email         *string   `gorm:"column:email"`
+		AvatarURL     *string   `gorm:"column:avatar_url"`
 	}
 
 	var rows []memberRow
 	if err := r.db.WithContext(ctx).
 		Table("family_members").
-		Select("family_members.user_id, family_members.role, family_members.joined_at, user_profiles.email, user_profiles.avatar_url").
+		Select("family_members.user_id, family_members.role, family_members.spending_limit, family_members.joined_at, user_profiles.email, user_profiles.avatar_url").
 		Joins("left join user_profiles on user_profiles.user_id = family_members.user_id").
 		Where("family_members.family_id = ?", familyID).
 		Order("family_members.joined_at asc").
@@ -107,11 +112,12 @@ func (r *PostgresRepository) ListMembersWithProfiles(ctx context.Context, family
 	members := make([]familydomain.FamilyMemberProfile, 0, len(rows))
 	for _, row := range rows {
 		members = append(members, familydomain.FamilyMemberProfile{
-			UserID:    row.UserID,
-			Role:      row.Role,
-			JoinedAt:  row.JoinedAt,
-			Email:     row.Email,
-			AvatarURL: row.AvatarURL,
+			UserID:        row.UserID,
+			Role:          row.Role,
+			SpendingLimit: row.SpendingLimit,
+			JoinedAt:      row.JoinedAt,
+			Email:         row.Email,
+			AvatarURL:     row.AvatarURL,
 		})
 	}
 	return members, nil
@@ -133,6 +139,10 @@ func (r *PostgresRepository) UpdateFamilyDefaultCurrency(ctx context.Context, fa
 	return r.db.WithContext(ctx).Model(&familydomain.Family{}).Where("id = ?", familyID).Update("default_currency", currency).Error
 }
 
+func (r *PostgresRepository) UpdateFamilyAllowedCurrencies(ctx context.Context, familyID string, currencies []byte) error {
+	return r.db.WithContext(ctx).Model(&familydomain.Family{}).Where("id = ?", familyID).Update("allowed_currencies", currencies).Error
+}
+
 func (r *PostgresRepository) UpdateFamilyOwner(ctx context.Context, familyID, ownerID string) error {
 	return r.db.WithContext(ctx).Model(&familydomain.Family{}).Where("id = ?", familyID).Update("owner_id", ownerID).Error
 }
@@ -143,8 +153,21 @@ func (r *PostgresRepository) UpdateMemberRole(ctx context.Context, familyID, use
 		Update("role", role).Error
 }
 
+func (r *PostgresRepository) UpdateMemberSpendingLimit(ctx context.Context, familyID, userID string, limit *float64) error {
+	return r.db.WithContext(ctx).Model(&familydomain.FamilyMember{}).
+		Where("family_id = ? AND user_id = ?", familyID, userID).
+		Update("spending_limit", limit).Error
+}
+
 func (r *PostgresRepository) DeleteFamily(ctx context.Context, familyID string) error {
-	return r.db.WithContext(ctx).Delete(&familydomain.Family{}, "id = ?", familyID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Labels point at their owner without a foreign key, so they do
+		// not go with the family's other data through ON DELETE CASCADE.
+		if err := tx.Exec("DELETE FROM entity_labels WHERE owner_id = ?", familyID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&familydomain.Family{}, "id = ?", familyID).Error
+	})
 }
 
 func (r *PostgresRepository) DeleteMember(ctx context.Context, familyID, userID string) error {
@@ -171,10 +194,46 @@ func (r *PostgresRepository) IsUserInFamily(ctx context.Context, userID string)
 	return count > 0, nil
 }
 
-func (r *PostgresRepository) IsCodeTaken(ctx context.Context, code string) (bool, error) {
-	var count int64
-	if err := r.db.WithContext(ctx).Model(&familydomain.Family{}).Where("code = ?", code).Count(&count).Error; err != nil {
-		return false, err
+func (r *PostgresRepository) CreateInvite(ctx context.Context, invite *familydomain.Invite) error {
+	return r.db.WithContext(ctx).Create(invite).Error
+}
+
+func (r *PostgresRepository) ListInvites(ctx context.Context, familyID string) ([]familydomain.Invite, error) {
+	var invites []familydomain.Invite
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ?", familyID).
+		Order("created_at desc").
+		Find(&invites).Error; err != nil {
+		return nil, err
 	}
-	return count > 0, nil
+	return invites, nil
+}
+
+func (r *PostgresRepository) LockInviteByTokenHash(ctx context.Context, tokenHash string) (*familydomain.Invite, error) {
+	var invite familydomain.Invite
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&invite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, familydomain.ErrInviteNotFound
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *PostgresRepository) IncrementInviteUses(ctx context.Context, inviteID string) error {
+	return r.db.WithContext(ctx).
+		Model(&familydomain.Invite{}).
+		Where("id = ?", inviteID).
+		Update("uses", gorm.Expr("uses + 1")).Error
+}
+
+func (r *PostgresRepository) RevokeInvite(ctx context.Context, familyID, inviteID string, revokedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&familydomain.Invite{}).
+		Where("family_id = ? AND id = ? AND revoked_at IS NULL", familyID, inviteID).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected > 0, result.Error
 }
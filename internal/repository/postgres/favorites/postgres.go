@@ -0,0 +1,57 @@
+package favorites
+
+import (
+	"context"
+
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) AddFavorite(ctx context.Context, favorite *favoritesdomain.Favorite) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(favorite).Error
+}
+
+func (r *PostgresRepository) RemoveFavorite(ctx context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
+		Delete(&favoritesdomain.Favorite{}).Error
+}
+
+func (r *PostgresRepository) ListFavorites(ctx context.Context, userID string, entityType favoritesdomain.EntityType) ([]favoritesdomain.Favorite, error) {
+	var favorites []favoritesdomain.Favorite
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND entity_type = ?", userID, entityType).
+		Order("created_at desc, entity_id asc").
+		Find(&favorites).Error; err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+func (r *PostgresRepository) CountFavorites(ctx context.Context, userID string, entityType favoritesdomain.EntityType) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&favoritesdomain.Favorite{}).
+		Where("user_id = ? AND entity_type = ?", userID, entityType).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *PostgresRepository) DeleteByEntity(ctx context.Context, entityType favoritesdomain.EntityType, entityID string) error {
+	return r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Delete(&favoritesdomain.Favorite{}).Error
+}
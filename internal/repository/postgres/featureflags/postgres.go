@@ -0,0 +1,76 @@
+package featureflags
+
+import (
+	"context"
+	"time"
+
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListFlags(ctx context.Context) ([]featureflagsdomain.Flag, error) {
+	var flags []featureflagsdomain.Flag
+	if err := r.db.WithContext(ctx).Order("key asc").Find(&flags).Error; err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+func (r *PostgresRepository) ListOverrides(ctx context.Context) ([]featureflagsdomain.FamilyOverride, error) {
+	var overrides []featureflagsdomain.FamilyOverride
+	if err := r.db.WithContext(ctx).
+		Order("flag_key asc, family_id asc").
+		Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func (r *PostgresRepository) UpsertFlag(ctx context.Context, flag *featureflagsdomain.Flag) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"enabled":    flag.Enabled,
+				"updated_at": time.Now().UTC(),
+			}),
+		}).
+		Create(flag).Error
+}
+
+func (r *PostgresRepository) UpsertOverride(ctx context.Context, override *featureflagsdomain.FamilyOverride) error {
+	var exists bool
+	if err := r.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM families WHERE id = ?)", override.FamilyID).
+		Scan(&exists).Error; err != nil {
+		return err
+	}
+	if !exists {
+		return featureflagsdomain.ErrFamilyNotFound
+	}
+
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "flag_key"}, {Name: "family_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"enabled":    override.Enabled,
+				"updated_at": time.Now().UTC(),
+			}),
+		}).
+		Create(override).Error
+}
+
+func (r *PostgresRepository) DeleteOverride(ctx context.Context, key, familyID string) error {
+	return r.db.WithContext(ctx).
+		Where("flag_key = ? AND family_id = ?", key, familyID).
+		Delete(&featureflagsdomain.FamilyOverride{}).Error
+}
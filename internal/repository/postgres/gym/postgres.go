@@ -3,9 +3,14 @@ package gym
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostgresRepository struct {
@@ -24,8 +29,8 @@ func (r *PostgresRepository) Transaction(ctx context.Context, fn func(gymdomain.
 
 // GymEntry operations
 
-func (r *PostgresRepository) ListGymEntries(ctx context.Context, userID string, filter gymdomain.ListFilter) ([]gymdomain.GymEntry, int64, error) {
-	query := r.db.WithContext(ctx).Model(&gymdomain.GymEntry{}).Where("user_id = ?", userID)
+func (r *PostgresRepository) ListGymEntries(ctx context.Context, userIDs []string, filter gymdomain.ListFilter) ([]gymdomain.GymEntry, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gymdomain.GymEntry{}).Where("user_id IN ?", userIDs)
 
 	if filter.From != nil {
 		query = query.Where("date >= ?", *filter.From)
@@ -33,6 +38,9 @@ func (r *PostgresRepository) ListGymEntries(ctx context.Context, userID string,
 	if filter.To != nil {
 		query = query.Where("date <= ?", *filter.To)
 	}
+	if filter.ViewerID != "" {
+		query = query.Where("(user_id = ? OR visibility = ?)", filter.ViewerID, gymdomain.VisibilityFamily)
+	}
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -81,6 +89,7 @@ func (r *PostgresRepository) UpdateGymEntry(ctx context.Context, entry *gymdomai
 			"exercise":   entry.Exercise,
 			"weight_kg":  entry.WeightKg,
 			"reps":       entry.Reps,
+			"visibility": entry.Visibility,
 			"updated_at": entry.UpdatedAt,
 		}).Error
 }
@@ -92,8 +101,8 @@ func (r *PostgresRepository) DeleteGymEntry(ctx context.Context, userID, entryID
 
 // Workout operations
 
-func (r *PostgresRepository) ListWorkouts(ctx context.Context, userID string, filter gymdomain.ListFilter) ([]gymdomain.Workout, int64, error) {
-	query := r.db.WithContext(ctx).Model(&gymdomain.Workout{}).Where("user_id = ?", userID)
+func (r *PostgresRepository) ListWorkouts(ctx context.Context, userIDs []string, filter gymdomain.ListFilter) ([]gymdomain.Workout, int64, error) {
+	query := r.db.WithContext(ctx).Model(&gymdomain.Workout{}).Where("user_id IN ?", userIDs)
 
 	if filter.From != nil {
 		query = query.Where("date >= ?", *filter.From)
@@ -101,6 +110,25 @@ func (r *PostgresRepository) ListWorkouts(ctx context.Context, userID string, fi
 	if filter.To != nil {
 		query = query.Where("date <= ?", *filter.To)
 	}
+	if filter.SharedOnly {
+		query = query.Where("visibility = ?", gymdomain.VisibilityFamily)
+	}
+	if filter.ViewerID != "" {
+		query = query.Where("(user_id = ? OR visibility = ?)", filter.ViewerID, gymdomain.VisibilityFamily)
+	}
+	if len(filter.Labels) > 0 {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM entity_labels l WHERE l.entity_type = ? AND l.entity_id = workouts.id AND l.label IN ?)",
+			labelsdomain.EntityWorkout, filter.Labels,
+		)
+	}
+	if search := strings.TrimSpace(filter.Query); search != "" {
+		pattern := "%" + search + "%"
+		query = query.Where(
+			"(workouts.name ILIKE ? OR EXISTS (SELECT 1 FROM workout_sets s WHERE s.workout_id = workouts.id AND s.exercise ILIKE ?))",
+			pattern, pattern,
+		)
+	}
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -147,6 +175,7 @@ func (r *PostgresRepository) UpdateWorkout(ctx context.Context, workout *gymdoma
 		Updates(map[string]interface{}{
 			"date":       workout.Date,
 			"name":       workout.Name,
+			"visibility": workout.Visibility,
 			"updated_at": workout.UpdatedAt,
 		}).Error
 }
@@ -193,10 +222,14 @@ func (r *PostgresRepository) ReplaceWorkoutSets(ctx context.Context, workoutID s
 
 // WorkoutTemplate operations
 
-func (r *PostgresRepository) ListTemplates(ctx context.Context, userID string) ([]gymdomain.WorkoutTemplate, error) {
+func (r *PostgresRepository) ListTemplates(ctx context.Context, userID string, sharedFrom []string) ([]gymdomain.WorkoutTemplate, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(sharedFrom) > 0 {
+		query = query.Or("(user_id IN ? AND visibility = ?)", sharedFrom, gymdomain.VisibilityFamily)
+	}
+
 	var templates []gymdomain.WorkoutTemplate
-	if err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+	if err := query.
 		Order("created_at desc").
 		Find(&templates).Error; err != nil {
 		return nil, err
@@ -217,6 +250,19 @@ func (r *PostgresRepository) GetTemplateByID(ctx context.Context, userID, templa
 	return &template, nil
 }
 
+func (r *PostgresRepository) GetSharedTemplateByID(ctx context.Context, userIDs []string, templateID string) (*gymdomain.WorkoutTemplate, error) {
+	var template gymdomain.WorkoutTemplate
+	if err := r.db.WithContext(ctx).
+		Where("user_id IN ? AND id = ? AND visibility = ?", userIDs, templateID, gymdomain.VisibilityFamily).
+		First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gymdomain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
 func (r *PostgresRepository) CreateTemplate(ctx context.Context, template *gymdomain.WorkoutTemplate) error {
 	return r.db.WithContext(ctx).Create(template).Error
 }
@@ -227,6 +273,7 @@ func (r *PostgresRepository) UpdateTemplate(ctx context.Context, template *gymdo
 		Where("id = ? AND user_id = ?", template.ID, template.UserID).
 		Updates(map[string]interface{}{
 			"name":       template.Name,
+			"visibility": template.Visibility,
 			"updated_at": template.UpdatedAt,
 		}).Error
 }
@@ -273,27 +320,32 @@ func (r *PostgresRepository) ReplaceTemplateSets(ctx context.Context, templateID
 
 // Exercise list
 
-func (r *PostgresRepository) ListExercises(ctx context.Context, userID string) ([]string, error) {
+func (r *PostgresRepository) ListExercises(ctx context.Context, userIDs []string, viewerID string) ([]string, error) {
 	var exercises []string
 
 	// Get unique exercises from gym_entries
 	var entryExercises []string
-	if err := r.db.WithContext(ctx).
+	entries := r.db.WithContext(ctx).
 		Model(&gymdomain.GymEntry{}).
-		Where("user_id = ?", userID).
-		Distinct("exercise").
-		Pluck("exercise", &entryExercises).Error; err != nil {
+		Where("user_id IN ?", userIDs)
+	if viewerID != "" {
+		entries = entries.Where("(user_id = ? OR visibility = ?)", viewerID, gymdomain.VisibilityFamily)
+	}
+	if err := entries.Distinct("exercise").Pluck("exercise", &entryExercises).Error; err != nil {
 		return nil, err
 	}
 
 	// Get unique exercises from workout_sets via workouts
 	var setExercises []string
-	if err := r.db.WithContext(ctx).
+	sets := r.db.WithContext(ctx).
 		Model(&gymdomain.WorkoutSet{}).
 		Select("DISTINCT workout_sets.exercise").
 		Joins("JOIN workouts ON workouts.id = workout_sets.workout_id").
-		Where("workouts.user_id = ?", userID).
-		Pluck("exercise", &setExercises).Error; err != nil {
+		Where("workouts.user_id IN ?", userIDs)
+	if viewerID != "" {
+		sets = sets.Where("(workouts.user_id = ? OR workouts.visibility = ?)", viewerID, gymdomain.VisibilityFamily)
+	}
+	if err := sets.Pluck("exercise", &setExercises).Error; err != nil {
 		return nil, err
 	}
 
@@ -312,3 +364,212 @@ func (r *PostgresRepository) ListExercises(ctx context.Context, userID string) (
 
 	return exercises, nil
 }
+
+// Personal records
+
+func (r *PostgresRepository) ListExerciseSets(ctx context.Context, userID string, exercises []string) ([]gymdomain.ExerciseSet, error) {
+	entriesQuery := r.db.WithContext(ctx).
+		Model(&gymdomain.GymEntry{}).
+		Select("exercise, weight_kg, reps, date").
+		Where("user_id = ?", userID)
+	setsQuery := r.db.WithContext(ctx).
+		Model(&gymdomain.WorkoutSet{}).
+		Select("workout_sets.exercise, workout_sets.weight_kg, workout_sets.reps, workouts.date").
+		Joins("JOIN workouts ON workouts.id = workout_sets.workout_id").
+		Where("workouts.user_id = ?", userID)
+	if exercises != nil {
+		entriesQuery = entriesQuery.Where("exercise IN ?", exercises)
+		setsQuery = setsQuery.Where("workout_sets.exercise IN ?", exercises)
+	}
+
+	var sets []gymdomain.ExerciseSet
+	if err := r.db.WithContext(ctx).
+		Raw("? UNION ALL ? ORDER BY date", entriesQuery, setsQuery).
+		Scan(&sets).Error; err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+func (r *PostgresRepository) CreatePersonalRecordEvents(ctx context.Context, events []gymdomain.PersonalRecordEvent) error {
+	return r.db.WithContext(ctx).Create(&events).Error
+}
+
+func (r *PostgresRepository) ListPersonalRecordEvents(ctx context.Context, userID string, limit int) ([]gymdomain.PersonalRecordEvent, error) {
+	var events []gymdomain.PersonalRecordEvent
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Session operations
+
+func (r *PostgresRepository) GetActiveSession(ctx context.Context, userID string) (*gymdomain.Session, error) {
+	var session gymdomain.Session
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, gymdomain.SessionStatusActive).
+		First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gymdomain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *PostgresRepository) GetSessionByID(ctx context.Context, userID, sessionID string) (*gymdomain.Session, error) {
+	return r.findSession(r.db.WithContext(ctx), userID, sessionID)
+}
+
+func (r *PostgresRepository) LockSession(ctx context.Context, userID, sessionID string) (*gymdomain.Session, error) {
+	return r.findSession(r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), userID, sessionID)
+}
+
+func (r *PostgresRepository) findSession(query *gorm.DB, userID, sessionID string) (*gymdomain.Session, error) {
+	var session gymdomain.Session
+	if err := query.
+		Where("user_id = ? AND id = ?", userID, sessionID).
+		First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gymdomain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *PostgresRepository) CreateSession(ctx context.Context, session *gymdomain.Session) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		if isUniqueViolation(err) {
+			return gymdomain.ErrSessionAlreadyActive
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UpdateSession(ctx context.Context, session *gymdomain.Session) error {
+	return r.db.WithContext(ctx).
+		Model(&gymdomain.Session{}).
+		Where("id = ? AND user_id = ?", session.ID, session.UserID).
+		Updates(map[string]interface{}{
+			"name":            session.Name,
+			"status":          session.Status,
+			"rest_seconds":    session.RestSeconds,
+			"rest_started_at": session.RestStartedAt,
+			"rest_ends_at":    session.RestEndsAt,
+			"finished_at":     session.FinishedAt,
+			"workout_id":      session.WorkoutID,
+			"updated_at":      session.UpdatedAt,
+		}).Error
+}
+
+func (r *PostgresRepository) ListSessionSets(ctx context.Context, sessionID string) ([]gymdomain.SessionSet, error) {
+	var sets []gymdomain.SessionSet
+	if err := r.db.WithContext(ctx).
+		Where("session_id = ?", sessionID).
+		Order("set_order asc").
+		Find(&sets).Error; err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+func (r *PostgresRepository) AppendSessionSets(ctx context.Context, sets []gymdomain.SessionSet) error {
+	return r.db.WithContext(ctx).Create(&sets).Error
+}
+
+// Goals and streaks
+
+func (r *PostgresRepository) GetGoal(ctx context.Context, userID string) (*gymdomain.Goal, error) {
+	var goal gymdomain.Goal
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&goal).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gymdomain.ErrGoalNotFound
+		}
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r *PostgresRepository) UpsertGoal(ctx context.Context, goal *gymdomain.Goal) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"workouts_per_week": goal.WorkoutsPerWeek,
+				"updated_at":        goal.UpdatedAt,
+			}),
+		}).
+		Create(goal).Error
+}
+
+func (r *PostgresRepository) DeleteGoal(ctx context.Context, userID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&gymdomain.Goal{}, "user_id = ?", userID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListActivityDays(ctx context.Context, userID string, from time.Time) ([]gymdomain.ActivityDay, error) {
+	workoutsQuery := r.db.WithContext(ctx).
+		Model(&gymdomain.Workout{}).
+		Select("date, COUNT(*) AS workouts, 0 AS entries").
+		Where("user_id = ? AND date >= ?", userID, from).
+		Group("date")
+	entriesQuery := r.db.WithContext(ctx).
+		Model(&gymdomain.GymEntry{}).
+		Select("date, 0 AS workouts, COUNT(*) AS entries").
+		Where("user_id = ? AND date >= ?", userID, from).
+		Group("date")
+
+	var days []gymdomain.ActivityDay
+	if err := r.db.WithContext(ctx).
+		Raw("SELECT date, SUM(workouts) AS workouts, SUM(entries) AS entries FROM (? UNION ALL ?) activity GROUP BY date ORDER BY date", workoutsQuery, entriesQuery).
+		Scan(&days).Error; err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func (r *PostgresRepository) ListGoalsWithoutNudge(ctx context.Context, weekStart time.Time, limit int) ([]gymdomain.Goal, error) {
+	var goals []gymdomain.Goal
+	if err := r.db.WithContext(ctx).
+		Where("NOT EXISTS (SELECT 1 FROM gym_goal_nudges n WHERE n.user_id = gym_goals.user_id AND n.week_start = ?)", weekStart).
+		Order("user_id asc").
+		Limit(limit).
+		Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func (r *PostgresRepository) GetGoalNudge(ctx context.Context, userID string, weekStart time.Time) (*gymdomain.GoalNudge, bool, error) {
+	var nudge gymdomain.GoalNudge
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND week_start = ?", userID, weekStart).
+		First(&nudge).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return &nudge, true, nil
+}
+
+func (r *PostgresRepository) CreateGoalNudge(ctx context.Context, nudge *gymdomain.GoalNudge) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "week_start"}},
+			DoNothing: true,
+		}).
+		Create(nudge).Error
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"hash/fnv"
+
+	"family-app-go/internal/jobs"
+	"gorm.io/gorm"
+)
+
+// lockNamespace keeps job advisory locks apart from other pg_advisory_lock
+// users; it is mixed into every key.
+const lockNamespace = "family-app-go/jobs:"
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateRun(ctx context.Context, run *jobs.Run) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *PostgresRepository) FinishRun(ctx context.Context, run *jobs.Run) error {
+	return r.db.WithContext(ctx).
+		Model(&jobs.Run{}).
+		Where("id = ?", run.ID).
+		Updates(map[string]interface{}{
+			"status":      run.Status,
+			"attempts":    run.Attempts,
+			"error":       run.Error,
+			"result":      run.Result,
+			"finished_at": run.FinishedAt,
+		}).Error
+}
+
+func (r *PostgresRepository) ListRuns(ctx context.Context, filter jobs.RunFilter) ([]jobs.Run, error) {
+	query := r.db.WithContext(ctx).Model(&jobs.Run{})
+	if filter.JobName != "" {
+		query = query.Where("job_name = ?", filter.JobName)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var runs []jobs.Run
+	if err := query.Order("started_at DESC").Limit(filter.Limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// TryLock takes a session-level advisory lock on a dedicated connection, so
+// the lock lives exactly as long as the job holds the connection. A crashed
+// instance drops its connection and with it the lock.
+func (r *PostgresRepository) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := lockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		// Unlock with a fresh context: the job's context may be cancelled.
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		conn.Close()
+	}
+	return release, true, nil
+}
+
+func lockKey(name string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(lockNamespace + name))
+	return int64(hash.Sum64())
+}
@@ -0,0 +1,78 @@
+package labels
+
+import (
+	"context"
+
+	"family-app-go/internal/db"
+	labelsdomain "family-app-go/internal/domain/labels"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ReplaceLabels(ctx context.Context, entityType labelsdomain.EntityType, entityID, ownerID string, labels []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+			Delete(&labelsdomain.Label{}).Error; err != nil {
+			return err
+		}
+		if len(labels) == 0 {
+			return nil
+		}
+
+		rows := make([]labelsdomain.Label, 0, len(labels))
+		for _, label := range labels {
+			rows = append(rows, labelsdomain.Label{
+				EntityType: entityType,
+				EntityID:   entityID,
+				Label:      label,
+				OwnerID:    ownerID,
+			})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *PostgresRepository) ListByEntities(ctx context.Context, entityType labelsdomain.EntityType, entityIDs []string) (map[string][]string, error) {
+	var rows []labelsdomain.Label
+	if err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Order("entity_id asc, label asc").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string)
+	for _, row := range rows {
+		result[row.EntityID] = append(result[row.EntityID], row.Label)
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) ListUsage(ctx context.Context, entityType labelsdomain.EntityType, ownerID string) ([]labelsdomain.Usage, error) {
+	var usage []labelsdomain.Usage
+	if err := r.db.WithContext(ctx).
+		Clauses(db.ReadReplica).
+		Model(&labelsdomain.Label{}).
+		Select("label, count(*) AS count").
+		Where("entity_type = ? AND owner_id = ?", entityType, ownerID).
+		Group("label").
+		Order("count desc, label asc").
+		Scan(&usage).Error; err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (r *PostgresRepository) DeleteByEntity(ctx context.Context, entityType labelsdomain.EntityType, entityID string) error {
+	return r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Delete(&labelsdomain.Label{}).Error
+}
@@ -0,0 +1,85 @@
+package milestones
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListMilestones(ctx context.Context, familyID string, from *time.Time, limit int) ([]milestonesdomain.Milestone, error) {
+	query := r.db.WithContext(ctx).
+		Where("family_id = ?", familyID).
+		Order("date asc, created_at asc")
+	if from != nil {
+		query = query.Where("date >= ?", *from)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var milestones []milestonesdomain.Milestone
+	if err := query.Find(&milestones).Error; err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+func (r *PostgresRepository) GetMilestone(ctx context.Context, familyID, milestoneID string) (*milestonesdomain.Milestone, error) {
+	var milestone milestonesdomain.Milestone
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND id = ?", familyID, milestoneID).
+		First(&milestone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, milestonesdomain.ErrMilestoneNotFound
+		}
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (r *PostgresRepository) CreateMilestone(ctx context.Context, milestone *milestonesdomain.Milestone) error {
+	return r.db.WithContext(ctx).Create(milestone).Error
+}
+
+func (r *PostgresRepository) UpdateMilestone(ctx context.Context, milestone *milestonesdomain.Milestone) error {
+	return r.db.WithContext(ctx).
+		Model(&milestonesdomain.Milestone{}).
+		Where("id = ? AND family_id = ?", milestone.ID, milestone.FamilyID).
+		Updates(map[string]interface{}{
+			"title":         milestone.Title,
+			"kind":          milestone.Kind,
+			"date":          milestone.Date,
+			"note":          milestone.Note,
+			"goal_amount":   milestone.GoalAmount,
+			"goal_currency": milestone.GoalCurrency,
+			"saved_amount":  milestone.SavedAmount,
+			"updated_at":    time.Now().UTC(),
+		}).Error
+}
+
+func (r *PostgresRepository) DeleteMilestone(ctx context.Context, familyID, milestoneID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&milestonesdomain.Milestone{}, "id = ? AND family_id = ?", milestoneID, familyID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) AddSavings(ctx context.Context, familyID, milestoneID string, amount float64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&milestonesdomain.Milestone{}).
+		Where("id = ? AND family_id = ? AND saved_amount IS NOT NULL", milestoneID, familyID).
+		Updates(map[string]interface{}{
+			"saved_amount": gorm.Expr("GREATEST(saved_amount + ?, 0)", amount),
+			"updated_at":   time.Now().UTC(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,60 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	notificationsdomain "family-app-go/internal/domain/notifications"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateNotifications(ctx context.Context, notifications []notificationsdomain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&notifications).Error
+}
+
+func (r *PostgresRepository) ListNotifications(ctx context.Context, familyID, userID string, filter notificationsdomain.ListFilter) (*notificationsdomain.Page, error) {
+	base := r.db.WithContext(ctx).Model(&notificationsdomain.Notification{}).
+		Where("family_id = ? AND user_id = ?", familyID, userID)
+
+	page := &notificationsdomain.Page{}
+	if err := base.Session(&gorm.Session{}).Where("read_at IS NULL").Count(&page.Unread).Error; err != nil {
+		return nil, err
+	}
+
+	query := base.Session(&gorm.Session{})
+	if filter.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+	if err := query.
+		Order("created_at DESC, id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&page.Items).Error; err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (r *PostgresRepository) MarkNotificationsRead(ctx context.Context, familyID, userID string, notificationIDs []string, readAt time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&notificationsdomain.Notification{}).
+		Where("family_id = ? AND user_id = ? AND read_at IS NULL", familyID, userID)
+	if len(notificationIDs) > 0 {
+		query = query.Where("id IN ?", notificationIDs)
+	}
+	result := query.Update("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
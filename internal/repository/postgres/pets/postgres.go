@@ -0,0 +1,224 @@
+package pets
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	petsdomain "family-app-go/internal/domain/pets"
+	"family-app-go/pkg/fieldcrypt"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListPets(ctx context.Context, familyID string) ([]petsdomain.Pet, error) {
+	var pets []petsdomain.Pet
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ?", familyID).
+		Order("created_at asc").
+		Find(&pets).Error; err != nil {
+		return nil, err
+	}
+	return pets, nil
+}
+
+func (r *PostgresRepository) GetPet(ctx context.Context, familyID, petID string) (*petsdomain.Pet, error) {
+	var pet petsdomain.Pet
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND id = ?", familyID, petID).
+		First(&pet).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, petsdomain.ErrPetNotFound
+		}
+		return nil, err
+	}
+	return &pet, nil
+}
+
+func (r *PostgresRepository) CreatePet(ctx context.Context, pet *petsdomain.Pet) error {
+	return r.db.WithContext(ctx).Create(pet).Error
+}
+
+func (r *PostgresRepository) UpdatePet(ctx context.Context, pet *petsdomain.Pet) error {
+	return r.db.WithContext(ctx).
+		Model(&petsdomain.Pet{}).
+		Where("id = ? AND family_id = ?", pet.ID, pet.FamilyID).
+		Updates(map[string]interface{}{
+			"name":       pet.Name,
+			"species":    pet.Species,
+			"breed":      pet.Breed,
+			"birth_date": pet.BirthDate,
+			"notes":      fieldcrypt.Value(pet.Notes),
+			"updated_at": time.Now().UTC(),
+		}).Error
+}
+
+func (r *PostgresRepository) SoftDeletePet(ctx context.Context, familyID, petID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&petsdomain.Pet{}, "id = ? AND family_id = ?", petID, familyID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListVaccinations(ctx context.Context, petID string) ([]petsdomain.Vaccination, error) {
+	var vaccinations []petsdomain.Vaccination
+	if err := r.db.WithContext(ctx).
+		Where("pet_id = ?", petID).
+		Order("administered_on desc, created_at desc").
+		Find(&vaccinations).Error; err != nil {
+		return nil, err
+	}
+	return vaccinations, nil
+}
+
+func (r *PostgresRepository) CreateVaccination(ctx context.Context, vaccination *petsdomain.Vaccination) error {
+	return r.db.WithContext(ctx).Create(vaccination).Error
+}
+
+func (r *PostgresRepository) DeleteVaccination(ctx context.Context, petID, vaccinationID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&petsdomain.Vaccination{}, "id = ? AND pet_id = ?", vaccinationID, petID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListVetVisits(ctx context.Context, petID string) ([]petsdomain.VetVisit, error) {
+	var visits []petsdomain.VetVisit
+	if err := r.db.WithContext(ctx).
+		Where("pet_id = ?", petID).
+		Order("visit_date desc, created_at desc").
+		Find(&visits).Error; err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+func (r *PostgresRepository) CreateVetVisit(ctx context.Context, visit *petsdomain.VetVisit) error {
+	return r.db.WithContext(ctx).Create(visit).Error
+}
+
+func (r *PostgresRepository) DeleteVetVisit(ctx context.Context, petID, visitID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&petsdomain.VetVisit{}, "id = ? AND pet_id = ?", visitID, petID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListSchedules(ctx context.Context, petID string) ([]petsdomain.CareSchedule, error) {
+	var schedules []petsdomain.CareSchedule
+	if err := r.db.WithContext(ctx).
+		Where("pet_id = ?", petID).
+		Order("next_due_at asc").
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *PostgresRepository) GetSchedule(ctx context.Context, petID, scheduleID string) (*petsdomain.CareSchedule, error) {
+	var schedule petsdomain.CareSchedule
+	if err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND id = ?", petID, scheduleID).
+		First(&schedule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, petsdomain.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *PostgresRepository) CreateSchedule(ctx context.Context, schedule *petsdomain.CareSchedule) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *PostgresRepository) MarkScheduleDone(ctx context.Context, schedule *petsdomain.CareSchedule) error {
+	return r.db.WithContext(ctx).
+		Model(&petsdomain.CareSchedule{}).
+		Where("id = ? AND pet_id = ?", schedule.ID, schedule.PetID).
+		Updates(map[string]interface{}{
+			"last_done_at": schedule.LastDoneAt,
+			"next_due_at":  schedule.NextDueAt,
+			"updated_at":   time.Now().UTC(),
+		}).Error
+}
+
+func (r *PostgresRepository) DeleteSchedule(ctx context.Context, petID, scheduleID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&petsdomain.CareSchedule{}, "id = ? AND pet_id = ?", scheduleID, petID)
+	return result.RowsAffected > 0, result.Error
+}
+
+type reminderRow struct {
+	SourceID string    `gorm:"column:source_id"`
+	PetID    string    `gorm:"column:pet_id"`
+	PetName  string    `gorm:"column:pet_name"`
+	Kind     string    `gorm:"column:kind"`
+	Title    string    `gorm:"column:title"`
+	DueAt    time.Time `gorm:"column:due_at"`
+}
+
+// ListDueVaccinations returns the latest vaccination of each name per pet whose
+// next dose is due before the given time. Older records of the same vaccine
+// are superseded by the most recent administration.
+func (r *PostgresRepository) ListDueVaccinations(ctx context.Context, familyID string, dueBefore time.Time) ([]petsdomain.Reminder, error) {
+	var rows []reminderRow
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT source_id, pet_id, pet_name, kind, title, due_at
+		FROM (
+			SELECT DISTINCT ON (v.pet_id, lower(v.name))
+				v.id AS source_id,
+				v.pet_id,
+				p.name AS pet_name,
+				? AS kind,
+				v.name AS title,
+				v.next_due_on::timestamptz AS due_at
+			FROM pet_vaccinations v
+			JOIN pets p ON p.id = v.pet_id
+			WHERE p.family_id = ? AND p.deleted_at IS NULL
+			ORDER BY v.pet_id, lower(v.name), v.administered_on DESC, v.created_at DESC
+		) latest
+		WHERE due_at IS NOT NULL AND due_at < ?
+		ORDER BY due_at ASC`,
+		petsdomain.ReminderKindVaccination, familyID, dueBefore,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]petsdomain.Reminder, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toReminder(row))
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) ListDueSchedules(ctx context.Context, familyID string, dueBefore time.Time) ([]petsdomain.Reminder, error) {
+	var rows []reminderRow
+	if err := r.db.WithContext(ctx).
+		Table("pet_care_schedules AS s").
+		Select("s.id AS source_id, s.pet_id, p.name AS pet_name, s.kind, s.title, s.next_due_at AS due_at").
+		Joins("JOIN pets p ON p.id = s.pet_id").
+		Where("p.family_id = ? AND p.deleted_at IS NULL", familyID).
+		Where("s.next_due_at < ?", dueBefore).
+		Order("s.next_due_at asc").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]petsdomain.Reminder, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toReminder(row))
+	}
+	return result, nil
+}
+
+func toReminder(row reminderRow) petsdomain.Reminder {
+	return petsdomain.Reminder{
+		Kind:     row.Kind,
+		PetID:    row.PetID,
+		PetName:  row.PetName,
+		SourceID: row.SourceID,
+		Title:    row.Title,
+		DueAt:    row.DueAt,
+	}
+}
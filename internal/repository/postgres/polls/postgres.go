@@ -0,0 +1,114 @@
+package polls
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pollsdomain "family-app-go/internal/domain/polls"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreatePoll(ctx context.Context, poll *pollsdomain.Poll) error {
+	// GORM inserts the options with the poll in one transaction.
+	return r.db.WithContext(ctx).Create(poll).Error
+}
+
+func (r *PostgresRepository) GetPoll(ctx context.Context, familyID, pollID string) (*pollsdomain.Poll, error) {
+	var poll pollsdomain.Poll
+	if err := r.db.WithContext(ctx).
+		Preload("Options", orderOptions).
+		Where("family_id = ? AND id = ?", familyID, pollID).
+		First(&poll).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pollsdomain.ErrPollNotFound
+		}
+		return nil, err
+	}
+	return &poll, nil
+}
+
+func (r *PostgresRepository) ListPolls(ctx context.Context, familyID string, filter pollsdomain.ListFilter, now time.Time) ([]pollsdomain.Poll, int64, error) {
+	query := r.db.WithContext(ctx).Model(&pollsdomain.Poll{}).Where("family_id = ?", familyID)
+	switch filter.Status {
+	case pollsdomain.StatusOpen:
+		query = query.Where("closed_at IS NULL AND (deadline IS NULL OR deadline > ?)", now)
+	case pollsdomain.StatusClosed:
+		query = query.Where("closed_at IS NOT NULL OR deadline <= ?", now)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var polls []pollsdomain.Poll
+	if err := query.Preload("Options", orderOptions).
+		Order("created_at DESC, id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&polls).Error; err != nil {
+		return nil, 0, err
+	}
+	return polls, total, nil
+}
+
+func (r *PostgresRepository) DeletePoll(ctx context.Context, familyID, pollID string) (bool, error) {
+	// Options and votes go with the poll through ON DELETE CASCADE.
+	result := r.db.WithContext(ctx).Delete(&pollsdomain.Poll{}, "family_id = ? AND id = ?", familyID, pollID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ClosePoll(ctx context.Context, pollID string, closedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&pollsdomain.Poll{}).
+		Where("id = ? AND closed_at IS NULL", pollID).
+		Updates(map[string]interface{}{"closed_at": closedAt, "updated_at": gorm.Expr("now()")})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListDuePolls(ctx context.Context, now time.Time, limit int) ([]pollsdomain.Poll, error) {
+	var polls []pollsdomain.Poll
+	err := r.db.WithContext(ctx).
+		Preload("Options", orderOptions).
+		Where("closed_at IS NULL AND deadline <= ?", now).
+		Order("deadline, id").
+		Limit(limit).
+		Find(&polls).Error
+	return polls, err
+}
+
+func (r *PostgresRepository) SaveVote(ctx context.Context, vote *pollsdomain.Vote) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "poll_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"option_id", "voted_at"}),
+		}).
+		Create(vote).Error
+}
+
+func (r *PostgresRepository) DeleteVote(ctx context.Context, pollID, userID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&pollsdomain.Vote{}, "poll_id = ? AND user_id = ?", pollID, userID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) ListVotes(ctx context.Context, pollIDs []string) ([]pollsdomain.Vote, error) {
+	var votes []pollsdomain.Vote
+	err := r.db.WithContext(ctx).
+		Where("poll_id IN ?", pollIDs).
+		Order("voted_at, user_id").
+		Find(&votes).Error
+	return votes, err
+}
+
+func orderOptions(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
@@ -2,6 +2,8 @@ package rates
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 
 	ratesdomain "family-app-go/internal/domain/rates"
@@ -57,3 +59,88 @@ func (p *PostgresProvider) ListCurrencies(ctx context.Context) ([]ratesdomain.Cu
 func (p *PostgresProvider) GetBYNRate(ctx context.Context, currency string, onDate time.Time) (ratesdomain.BYNRate, error) {
 	return p.rateProvider.GetBYNRate(ctx, currency, onDate)
 }
+
+// referenceBase is the from_currency of reference rate rows.
+const referenceBase = "EUR"
+
+// Postgres stores reference rates in fx_rates as euro-based pairs.
+type Postgres struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (r *Postgres) SaveReferenceRates(ctx context.Context, rates ratesdomain.ReferenceRates) (int, error) {
+	codes := make([]string, 0, len(rates.Rates))
+	for code, rate := range rates.Rates {
+		if rate > 0 && code != referenceBase {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return 0, nil
+	}
+	sort.Strings(codes)
+
+	values := make([]string, 0, len(codes))
+	args := make([]interface{}, 0, 3+2*len(codes))
+	args = append(args, rates.Date.Format("2006-01-02"), referenceBase, rates.Source)
+	for _, code := range codes {
+		values = append(values, "(?, ?::numeric)")
+		args = append(args, code, rates.Rates[code])
+	}
+
+	// The currencies foreign key limits the table to currencies the app
+	// knows; the rest of a provider's list is dropped.
+	result := r.db.WithContext(ctx).Exec(
+		"INSERT INTO fx_rates (rate_date, from_currency, to_currency, scale, rate, source, fetched_at) "+
+			"SELECT ?::date, ?, v.code, 1, v.rate, ?, now() "+
+			"FROM (VALUES "+strings.Join(values, ", ")+") AS v(code, rate) "+
+			"JOIN currencies c ON c.code = v.code "+
+			"ON CONFLICT (rate_date, from_currency, to_currency, source) DO UPDATE SET rate = EXCLUDED.rate, fetched_at = EXCLUDED.fetched_at",
+		args...,
+	)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (r *Postgres) GetReferenceRates(ctx context.Context, from, to time.Time) (ratesdomain.ReferenceRates, error) {
+	var rows []struct {
+		RateDate   time.Time `gorm:"column:rate_date"`
+		ToCurrency string    `gorm:"column:to_currency"`
+		Rate       float64   `gorm:"column:rate"`
+		Scale      int       `gorm:"column:scale"`
+		Source     string    `gorm:"column:source"`
+	}
+	// Only the newest day counts; when several sources stored it, the most
+	// recently fetched one wins.
+	if err := r.db.WithContext(ctx).Raw(
+		"SELECT rate_date, to_currency, rate, scale, source FROM fx_rates "+
+			"WHERE from_currency = ? AND rate_date = ("+
+			"SELECT max(rate_date) FROM fx_rates WHERE from_currency = ? AND rate_date >= ? AND rate_date <= ?"+
+			") ORDER BY fetched_at DESC, to_currency ASC",
+		referenceBase, referenceBase, from.Format("2006-01-02"), to.Format("2006-01-02"),
+	).Scan(&rows).Error; err != nil {
+		return ratesdomain.ReferenceRates{}, err
+	}
+	if len(rows) == 0 {
+		return ratesdomain.ReferenceRates{}, ratesdomain.ErrRateNotAvailable
+	}
+
+	result := ratesdomain.ReferenceRates{
+		Date:   rows[0].RateDate,
+		Source: rows[0].Source,
+		Rates:  make(map[string]float64, len(rows)),
+	}
+	for _, row := range rows {
+		if row.Source != result.Source || row.Scale <= 0 {
+			continue
+		}
+		result.Rates[row.ToCurrency] = row.Rate / float64(row.Scale)
+	}
+	return result, nil
+}
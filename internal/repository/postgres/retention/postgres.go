@@ -0,0 +1,117 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	retentiondomain "family-app-go/internal/domain/retention"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) Transaction(ctx context.Context, fn func(retentiondomain.Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&PostgresRepository{db: tx})
+	})
+}
+
+func (r *PostgresRepository) GetPolicy(ctx context.Context, familyID string) (*retentiondomain.Policy, error) {
+	var policy retentiondomain.Policy
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ?", familyID).
+		First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, retentiondomain.ErrPolicyNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *PostgresRepository) UpsertPolicy(ctx context.Context, policy *retentiondomain.Policy) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "family_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"expenses_max_age_days":    policy.ExpensesMaxAgeDays,
+				"todos_archive_after_days": policy.TodosArchiveAfterDays,
+				"updated_at":               policy.UpdatedAt,
+			}),
+		}).
+		Create(policy).Error
+}
+
+func (r *PostgresRepository) ListDuePolicies(ctx context.Context, lastRunBefore time.Time, limit int) ([]retentiondomain.Policy, error) {
+	query := r.db.WithContext(ctx).
+		Where("expenses_max_age_days IS NOT NULL OR todos_archive_after_days IS NOT NULL").
+		Where("last_run_at IS NULL OR last_run_at < ?", lastRunBefore).
+		Order("last_run_at ASC NULLS FIRST")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var policies []retentiondomain.Policy
+	if err := query.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *PostgresRepository) MarkPolicyRun(ctx context.Context, familyID string, ranAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&retentiondomain.Policy{}).
+		Where("family_id = ?", familyID).
+		UpdateColumn("last_run_at", ranAt).Error
+}
+
+func (r *PostgresRepository) CountExpensesBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("expenses").
+		Where("family_id = ? AND date < ?", familyID, cutoff).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresRepository) DeleteExpensesBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Exec("DELETE FROM expenses WHERE family_id = ? AND date < ?", familyID, cutoff)
+	return result.RowsAffected, result.Error
+}
+
+func (r *PostgresRepository) CountCompletedTodosBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("todo_items").
+		Joins("JOIN todo_lists ON todo_lists.id = todo_items.list_id").
+		Where("todo_lists.family_id = ? AND todo_lists.deleted_at IS NULL", familyID).
+		Where("todo_items.deleted_at IS NULL AND todo_items.is_completed = true AND todo_items.is_archived = false").
+		Where("todo_items.completed_at < ?", cutoff).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresRepository) ArchiveCompletedTodosBefore(ctx context.Context, familyID string, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE todo_items
+		SET is_archived = true, version = todo_items.version + 1
+		FROM todo_lists
+		WHERE todo_lists.id = todo_items.list_id
+		  AND todo_lists.family_id = ?
+		  AND todo_lists.deleted_at IS NULL
+		  AND todo_items.deleted_at IS NULL
+		  AND todo_items.is_completed = true
+		  AND todo_items.is_archived = false
+		  AND todo_items.completed_at < ?
+	`, familyID, cutoff)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,103 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sessionsdomain "family-app-go/internal/domain/sessions"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateSession(ctx context.Context, session *sessionsdomain.Session) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "session_key"}}, DoNothing: true}).
+		Create(session)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *PostgresRepository) GetSessionByKey(ctx context.Context, key string) (*sessionsdomain.Session, error) {
+	var session sessionsdomain.Session
+	if err := r.db.WithContext(ctx).
+		Where("session_key = ?", key).
+		First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, sessionsdomain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *PostgresRepository) ListSessions(ctx context.Context, userID string, seenSince, now time.Time, limit int) ([]sessionsdomain.Session, error) {
+	var sessions []sessionsdomain.Session
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND last_seen_at >= ?", userID, seenSince).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("last_seen_at desc, id desc").
+		Limit(limit).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *PostgresRepository) TouchSession(ctx context.Context, id, tokenHash string, expiresAt *time.Time, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&sessionsdomain.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"token_hash":   tokenHash,
+			"expires_at":   expiresAt,
+			"last_seen_at": at,
+		}).Error
+}
+
+func (r *PostgresRepository) RevokeSession(ctx context.Context, userID, id string, at time.Time) (*sessionsdomain.Session, error) {
+	var sessions []sessionsdomain.Session
+	if err := r.db.WithContext(ctx).
+		Model(&sessions).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", at).Error; err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, sessionsdomain.ErrSessionNotFound
+	}
+	return &sessions[0], nil
+}
+
+func (r *PostgresRepository) RevokeOtherSessions(ctx context.Context, userID, keepID string, at time.Time) ([]sessionsdomain.Session, error) {
+	var sessions []sessionsdomain.Session
+	query := r.db.WithContext(ctx).
+		Model(&sessions).
+		Clauses(clause.Returning{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if keepID != "" {
+		query = query.Where("id <> ?", keepID)
+	}
+	if err := query.Update("revoked_at", at).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *PostgresRepository) DeleteStale(ctx context.Context, seenBefore, revokedBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("(revoked_at IS NULL AND last_seen_at < ?) OR revoked_at < ?", seenBefore, revokedBefore).
+		Delete(&sessionsdomain.Session{})
+	return result.RowsAffected, result.Error
+}
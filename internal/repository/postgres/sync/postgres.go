@@ -3,8 +3,13 @@ package sync
 import (
 	"context"
 	"errors"
+	"time"
 
+	expensesdomain "family-app-go/internal/domain/expenses"
 	syncdomain "family-app-go/internal/domain/sync"
+	todosdomain "family-app-go/internal/domain/todos"
+	expensesrepo "family-app-go/internal/repository/postgres/expenses"
+	todosrepo "family-app-go/internal/repository/postgres/todos"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -18,6 +23,12 @@ func NewPostgres(db *gorm.DB) *PostgresRepository {
 	return &PostgresRepository{db: db}
 }
 
+func (r *PostgresRepository) Transaction(ctx context.Context, fn func(syncdomain.Repository, expensesdomain.Repository, todosdomain.Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&PostgresRepository{db: tx}, expensesrepo.NewPostgres(tx), todosrepo.NewPostgres(tx))
+	})
+}
+
 func (r *PostgresRepository) BeginBatch(ctx context.Context, batch *syncdomain.BatchRecord) (bool, *syncdomain.BatchRecord, error) {
 	err := r.db.WithContext(ctx).Create(batch).Error
 	if err == nil {
@@ -99,6 +110,28 @@ func (r *PostgresRepository) UpdateOperation(ctx context.Context, operation *syn
 		}).Error
 }
 
+func (r *PostgresRepository) FindOperation(ctx context.Context, familyID, userID, operationID string) (*syncdomain.OperationRecord, error) {
+	var operation syncdomain.OperationRecord
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND user_id = ? AND operation_id = ?", familyID, userID, operationID).
+		First(&operation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &operation, nil
+}
+
+func (r *PostgresRepository) CountOperationsSince(ctx context.Context, familyID string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&syncdomain.OperationRecord{}).
+		Where("family_id = ? AND created_at >= ?", familyID, since).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *PostgresRepository) FindServerIDByLocalID(ctx context.Context, familyID, userID string, entity syncdomain.Entity, localID string) (string, bool, error) {
 	type row struct {
 		ServerID string `gorm:"column:server_id"`
@@ -124,6 +157,109 @@ func (r *PostgresRepository) FindServerIDByLocalID(ctx context.Context, familyID
 	return result.ServerID, true, nil
 }
 
+func (r *PostgresRepository) FindServerIDsByLocalIDs(ctx context.Context, familyID, userID string, entity syncdomain.Entity, localIDs []string) (map[string]string, error) {
+	if len(localIDs) == 0 {
+		return map[string]string{}, nil
+	}
+
+	type row struct {
+		LocalID  string `gorm:"column:local_id"`
+		ServerID string `gorm:"column:server_id"`
+	}
+
+	var rows []row
+	err := r.db.WithContext(ctx).
+		Table("sync_operations").
+		Select("DISTINCT ON (local_id) local_id, server_id").
+		Where("family_id = ? AND user_id = ? AND entity = ? AND local_id IN ?", familyID, userID, entity, localIDs).
+		Where("status = ?", syncdomain.OperationStateApplied).
+		Where("server_id IS NOT NULL").
+		Order("local_id, created_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(rows))
+	for _, row := range rows {
+		result[row.LocalID] = row.ServerID
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) DeleteOperationsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM sync_operations
+		WHERE id IN (SELECT id FROM sync_operations WHERE created_at < ? ORDER BY created_at LIMIT ?)`, cutoff, limit)
+	return result.RowsAffected, result.Error
+}
+
+func (r *PostgresRepository) DeleteBatchesBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM sync_batches
+		WHERE id IN (SELECT id FROM sync_batches WHERE created_at < ? ORDER BY created_at LIMIT ?)`, cutoff, limit)
+	return result.RowsAffected, result.Error
+}
+
+func (r *PostgresRepository) ChangeFeedBounds(ctx context.Context, familyID string) (syncdomain.ChangeFeedBounds, error) {
+	var bounds syncdomain.ChangeFeedBounds
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COALESCE((SELECT last_seq FROM change_feed_heads WHERE family_id = ?), 0) AS last_seq,
+			COALESCE((SELECT MIN(seq) FROM change_events WHERE family_id = ?), 0) AS oldest_seq`, familyID, familyID).
+		Row().Scan(&bounds.LastSeq, &bounds.OldestSeq)
+	return bounds, err
+}
+
+func (r *PostgresRepository) ListChangeEvents(ctx context.Context, familyID string, afterSeq int64, limit int) ([]syncdomain.ChangeEvent, error) {
+	var events []syncdomain.ChangeEvent
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND seq > ?", familyID, afterSeq).
+		Order("seq").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *PostgresRepository) DeleteChangeEventsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM change_events
+		WHERE (family_id, seq) IN (SELECT family_id, seq FROM change_events WHERE created_at < ? ORDER BY created_at LIMIT ?)`, cutoff, limit)
+	return result.RowsAffected, result.Error
+}
+
+// StorageStats reads the planner's row estimates, which never scan the
+// tables, and their size including indexes and TOAST.
+func (r *PostgresRepository) StorageStats(ctx context.Context) (syncdomain.StorageStats, error) {
+	type row struct {
+		Name  string `gorm:"column:name"`
+		Rows  int64  `gorm:"column:rows"`
+		Bytes int64  `gorm:"column:bytes"`
+	}
+
+	var rows []row
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT relname AS name, GREATEST(reltuples, 0)::bigint AS rows, pg_total_relation_size(oid) AS bytes
+		FROM pg_class
+		WHERE oid IN ('sync_operations'::regclass, 'sync_batches'::regclass)`).
+		Scan(&rows).Error; err != nil {
+		return syncdomain.StorageStats{}, err
+	}
+
+	var stats syncdomain.StorageStats
+	for _, table := range rows {
+		switch table.Name {
+		case "sync_operations":
+			stats.OperationRows, stats.OperationBytes = table.Rows, table.Bytes
+		case "sync_batches":
+			stats.BatchRows, stats.BatchBytes = table.Rows, table.Bytes
+		}
+	}
+	return stats, nil
+}
+
 func isUniqueViolation(err error) bool {
 	var pgErr *pgconn.PgError
 	return errors.As(err, &pgErr) && pgErr.Code == "23505"
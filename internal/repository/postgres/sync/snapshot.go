@@ -0,0 +1,137 @@
+package sync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	syncdomain "family-app-go/internal/domain/sync"
+	"family-app-go/pkg/fieldcrypt"
+	"gorm.io/gorm"
+)
+
+// snapshotQueries read a member's records in the order they are streamed.
+// Each query takes the family ID, and then the user ID when withUser is set.
+// Columns ending in _json hold JSON built by the query and are
+// passed through under the name without the suffix.
+var snapshotQueries = []struct {
+	entity   syncdomain.Entity
+	withUser bool
+	query    string
+}{
+	{syncdomain.EntityCategory, false, `SELECT id, name, color, emoji, version, created_at
+		FROM categories WHERE family_id = ? ORDER BY created_at, id`},
+	{syncdomain.EntityExpense, false, `SELECT e.id, e.user_id, e.date, e.amount, e.currency, e.base_currency, e.amount_in_base,
+			e.title, e.is_archived, e.version, e.created_at, e.updated_at,
+			COALESCE((SELECT json_agg(ec.category_id ORDER BY ec.category_id) FROM expense_categories ec WHERE ec.expense_id = e.id), '[]')::text AS category_ids_json
+		FROM expenses e WHERE e.family_id = ? ORDER BY e.date, e.created_at, e.id`},
+	{syncdomain.EntityTodoList, true, `SELECT l.id, l.title, l.archive_completed, l.is_private, l.order_index, l.version, l.created_at
+		FROM todo_lists l
+		WHERE l.family_id = ? AND l.deleted_at IS NULL
+			AND (NOT l.is_private OR EXISTS (SELECT 1 FROM todo_list_viewers v WHERE v.list_id = l.id AND v.user_id = ?))
+		ORDER BY l.order_index, l.created_at, l.id`},
+	{syncdomain.EntityTodoItem, true, `SELECT i.id, i.list_id, i.title, i.is_completed, i.is_archived, i.created_at, i.completed_at,
+			i.due_date, i.assignee_id, i.version, i.estimated_price, i.expense_id
+		FROM todo_items i
+		JOIN todo_lists l ON l.id = i.list_id
+		WHERE l.family_id = ? AND l.deleted_at IS NULL AND i.deleted_at IS NULL
+			AND (NOT l.is_private OR EXISTS (SELECT 1 FROM todo_list_viewers v WHERE v.list_id = l.id AND v.user_id = ?))
+		ORDER BY i.list_id, i.created_at, i.id`},
+	{syncdomain.EntityWorkout, true, `SELECT w.id, w.user_id, w.date, w.name, w.visibility, w.created_at, w.updated_at,
+			COALESCE((SELECT json_agg(json_build_object('id', s.id, 'exercise', s.exercise, 'weight_kg', s.weight_kg, 'reps', s.reps) ORDER BY s.set_order, s.id)
+				FROM workout_sets s WHERE s.workout_id = w.id), '[]')::text AS sets_json
+		FROM workouts w
+		WHERE w.family_id = ? AND (w.user_id = ? OR w.visibility = 'family')
+		ORDER BY w.date, w.created_at, w.id`},
+}
+
+const jsonColumnSuffix = "_json"
+
+// StreamSnapshot runs every query in one read-only repeatable read
+// transaction, so the records and the change feed head come from the same
+// point in time.
+func (r *PostgresRepository) StreamSnapshot(ctx context.Context, familyID, userID string, begin func(lastSeq int64) error, emit func(syncdomain.SnapshotRecord) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var lastSeq int64
+		if err := tx.Raw("SELECT COALESCE((SELECT last_seq FROM change_feed_heads WHERE family_id = ?), 0)", familyID).
+			Row().Scan(&lastSeq); err != nil {
+			return err
+		}
+		if err := begin(lastSeq); err != nil {
+			return err
+		}
+
+		for _, sq := range snapshotQueries {
+			args := []interface{}{familyID}
+			if sq.withUser {
+				args = append(args, userID)
+			}
+			if err := streamRecords(tx, sq.entity, sq.query, args, emit); err != nil {
+				return fmt.Errorf("snapshot %s: %w", sq.entity, err)
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+func streamRecords(tx *gorm.DB, entity syncdomain.Entity, query string, args []interface{}, emit func(syncdomain.SnapshotRecord) error) error {
+	rows, err := tx.Raw(query, args...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+		data := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			value, err := snapshotValue(values[i])
+			if err != nil {
+				return fmt.Errorf("column %s: %w", column, err)
+			}
+			if name, ok := strings.CutSuffix(column, jsonColumnSuffix); ok {
+				if text, isText := value.(string); isText {
+					value = json.RawMessage(text)
+				}
+				column = name
+			}
+			data[column] = value
+		}
+		if err := emit(syncdomain.SnapshotRecord{Entity: entity, Data: data}); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// snapshotValue turns driver values into JSON-friendly ones as exports do,
+// and decrypts encrypted columns.
+func snapshotValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339), nil
+	case []byte:
+		value = string(v)
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16]), nil
+	}
+	if text, ok := value.(string); ok && fieldcrypt.IsEncrypted(text) {
+		return fieldcrypt.Decrypt(text)
+	}
+	return value, nil
+}
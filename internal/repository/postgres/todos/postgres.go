@@ -5,9 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
+	"family-app-go/internal/db"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	labelsdomain "family-app-go/internal/domain/labels"
 	todosdomain "family-app-go/internal/domain/todos"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostgresRepository struct {
@@ -25,9 +30,15 @@ func (r *PostgresRepository) Transaction(ctx context.Context, fn func(todosdomai
 }
 
 func (r *PostgresRepository) LockFamilyOrders(ctx context.Context, familyID string) error {
-	return r.db.WithContext(ctx).
-		Exec("SELECT pg_advisory_xact_lock(hashtext(?))", familyID).
-		Error
+	return db.LockXact(ctx, r.db, db.LockTodoListOrder, familyID)
+}
+
+func (r *PostgresRepository) LockListItems(ctx context.Context, listID string) error {
+	return db.LockXact(ctx, r.db, db.LockTodoListItems, listID)
+}
+
+func (r *PostgresRepository) LockShoppingList(ctx context.Context, listID string) error {
+	return db.LockXact(ctx, r.db, db.LockShoppingList, listID)
 }
 
 func (r *PostgresRepository) ListTodoLists(ctx context.Context, familyID string, filter todosdomain.ListFilter) ([]todosdomain.TodoList, int64, error) {
@@ -36,6 +47,15 @@ func (r *PostgresRepository) ListTodoLists(ctx context.Context, familyID string,
 	if search != "" {
 		query = query.Where("title ILIKE ?", "%"+search+"%")
 	}
+	if filter.AssigneeID != "" {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM todo_items WHERE todo_items.list_id = todo_lists.id AND todo_items.assignee_id = ? AND todo_items.deleted_at IS NULL)",
+			filter.AssigneeID,
+		)
+	}
+	if filter.ViewerID != "" {
+		query = query.Where(visibleListCondition("todo_lists"), filter.ViewerID)
+	}
 
 	countQuery := query.Session(&gorm.Session{})
 	var total int64
@@ -43,6 +63,12 @@ func (r *PostgresRepository) ListTodoLists(ctx context.Context, familyID string,
 		return nil, 0, err
 	}
 
+	if filter.FavoritesOf != "" {
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "EXISTS (SELECT 1 FROM user_favorites WHERE user_favorites.user_id = ? AND user_favorites.entity_type = ? AND user_favorites.entity_id = todo_lists.id::text) DESC",
+			Vars: []interface{}{filter.FavoritesOf, favoritesdomain.EntityTodoList},
+		}})
+	}
 	query = query.Order("order_index asc, created_at asc")
 	if filter.Limit > 0 {
 		query = query.Limit(filter.Limit)
@@ -77,15 +103,25 @@ func (r *PostgresRepository) CreateTodoList(ctx context.Context, list *todosdoma
 }
 
 func (r *PostgresRepository) UpdateTodoList(ctx context.Context, list *todosdomain.TodoList) error {
-	return r.db.WithContext(ctx).
+	result := r.db.WithContext(ctx).
 		Model(&todosdomain.TodoList{}).
-		Where("id = ? AND family_id = ?", list.ID, list.FamilyID).
+		Where("id = ? AND family_id = ? AND version = ?", list.ID, list.FamilyID, list.Version).
 		Updates(map[string]interface{}{
 			"title":             list.Title,
 			"archive_completed": list.ArchiveCompleted,
 			"is_collapsed":      list.IsCollapsed,
 			"order_index":       list.Order,
-		}).Error
+			"is_private":        list.IsPrivate,
+			"version":           gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return todosdomain.ErrVersionConflict
+	}
+	list.Version++
+	return nil
 }
 
 func (r *PostgresRepository) SoftDeleteTodoList(ctx context.Context, familyID, listID string) (bool, error) {
@@ -93,6 +129,15 @@ func (r *PostgresRepository) SoftDeleteTodoList(ctx context.Context, familyID, l
 	return result.RowsAffected > 0, result.Error
 }
 
+func (r *PostgresRepository) CountTodoLists(ctx context.Context, familyID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&todosdomain.TodoList{}).
+		Where("family_id = ?", familyID).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *PostgresRepository) GetMaxOrder(ctx context.Context, familyID string) (int, error) {
 	var max sql.NullInt64
 	if err := r.db.WithContext(ctx).
@@ -139,9 +184,10 @@ func (r *PostgresRepository) ShiftOrderRange(ctx context.Context, familyID strin
 func (r *PostgresRepository) SetCompletedItemsArchived(ctx context.Context, listID string, archived bool) error {
 	return r.db.WithContext(ctx).
 		Model(&todosdomain.TodoItem{}).
-		Where("list_id = ? AND is_completed = ?", listID, true).
+		Where("list_id = ? AND is_completed = ? AND is_archived <> ?", listID, true, archived).
 		Updates(map[string]interface{}{
 			"is_archived": archived,
+			"version":     gorm.Expr("version + 1"),
 		}).Error
 }
 
@@ -149,7 +195,7 @@ func (r *PostgresRepository) SoftDeleteItemsByList(ctx context.Context, listID s
 	return r.db.WithContext(ctx).Delete(&todosdomain.TodoItem{}, "list_id = ?", listID).Error
 }
 
-func (r *PostgresRepository) CountItemsByListIDs(ctx context.Context, listIDs []string) (map[string]todosdomain.ListItemCounts, error) {
+func (r *PostgresRepository) CountItemsByListIDs(ctx context.Context, listIDs []string, assigneeID string) (map[string]todosdomain.ListItemCounts, error) {
 	result := make(map[string]todosdomain.ListItemCounts, len(listIDs))
 	if len(listIDs) == 0 {
 		return result, nil
@@ -160,39 +206,91 @@ func (r *PostgresRepository) CountItemsByListIDs(ctx context.Context, listIDs []
 		ItemsTotal     int64  `gorm:"column:items_total"`
 		ItemsCompleted int64  `gorm:"column:items_completed"`
 		ItemsArchived  int64  `gorm:"column:items_archived"`
+
+		PlannedTotal     float64 `gorm:"column:planned_total"`
+		PlannedPurchased float64 `gorm:"column:planned_purchased"`
+		ItemsLinked      int64   `gorm:"column:items_linked"`
 	}
 
-	var rows []row
-	if err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Model(&todosdomain.TodoItem{}).
 		Select(`
 			list_id,
 			COUNT(*) as items_total,
 			SUM(CASE WHEN is_completed THEN 1 ELSE 0 END) as items_completed,
-			SUM(CASE WHEN is_archived THEN 1 ELSE 0 END) as items_archived`).
-		Where("list_id IN ?", listIDs).
-		Group("list_id").
-		Find(&rows).Error; err != nil {
+			SUM(CASE WHEN is_archived THEN 1 ELSE 0 END) as items_archived,
+			COALESCE(SUM(estimated_price), 0) as planned_total,
+			COALESCE(SUM(CASE WHEN expense_id IS NOT NULL THEN estimated_price END), 0) as planned_purchased,
+			COUNT(expense_id) as items_linked`).
+		Where("list_id IN ?", listIDs)
+	if assigneeID != "" {
+		query = query.Where("assignee_id = ?", assigneeID)
+	}
+
+	var rows []row
+	if err := query.Group("list_id").Find(&rows).Error; err != nil {
 		return nil, err
 	}
 
+	linked := make([]string, 0, len(rows))
 	for _, item := range rows {
 		result[item.ListID] = todosdomain.ListItemCounts{
 			ItemsTotal:     item.ItemsTotal,
 			ItemsCompleted: item.ItemsCompleted,
 			ItemsArchived:  item.ItemsArchived,
+
+			PlannedTotal:     item.PlannedTotal,
+			PlannedPurchased: item.PlannedPurchased,
+			ItemsLinked:      item.ItemsLinked,
 		}
+		if item.ItemsLinked > 0 {
+			linked = append(linked, item.ListID)
+		}
+	}
+	if len(linked) == 0 {
+		return result, nil
+	}
+
+	// Several items bought in one trip share an expense, which counts once.
+	type actualRow struct {
+		ListID      string  `gorm:"column:list_id"`
+		ActualTotal float64 `gorm:"column:actual_total"`
+	}
+	purchases := r.db.WithContext(ctx).
+		Model(&todosdomain.TodoItem{}).
+		Distinct("list_id", "expense_id").
+		Where("list_id IN ? AND expense_id IS NOT NULL", linked)
+	if assigneeID != "" {
+		purchases = purchases.Where("assignee_id = ?", assigneeID)
+	}
+	var actuals []actualRow
+	err := r.db.WithContext(ctx).
+		Table("(?) AS p", purchases).
+		Select("p.list_id, COALESCE(SUM(e.amount_in_base), 0) AS actual_total").
+		Joins("JOIN expenses e ON e.id = p.expense_id").
+		Group("p.list_id").
+		Find(&actuals).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, actual := range actuals {
+		counts := result[actual.ListID]
+		counts.ActualTotal = actual.ActualTotal
+		result[actual.ListID] = counts
 	}
 
 	return result, nil
 }
 
-func (r *PostgresRepository) ListItemsByListIDs(ctx context.Context, listIDs []string, archived todosdomain.ArchivedFilter) ([]todosdomain.TodoItem, error) {
+func (r *PostgresRepository) ListItemsByListIDs(ctx context.Context, listIDs []string, archived todosdomain.ArchivedFilter, assigneeID string) ([]todosdomain.TodoItem, error) {
 	if len(listIDs) == 0 {
 		return []todosdomain.TodoItem{}, nil
 	}
 
 	query := r.db.WithContext(ctx).Model(&todosdomain.TodoItem{}).Where("list_id IN ?", listIDs)
+	if assigneeID != "" {
+		query = query.Where("assignee_id = ?", assigneeID)
+	}
 	switch archived {
 	case todosdomain.ArchivedOnly:
 		query = query.Where("is_archived = ?", true)
@@ -209,8 +307,17 @@ func (r *PostgresRepository) ListItemsByListIDs(ctx context.Context, listIDs []s
 	return items, nil
 }
 
-func (r *PostgresRepository) ListTodoItems(ctx context.Context, listID string, archived todosdomain.ArchivedFilter) ([]todosdomain.TodoItem, int64, error) {
+func (r *PostgresRepository) ListTodoItems(ctx context.Context, listID string, archived todosdomain.ArchivedFilter, assigneeID string, labels []string) ([]todosdomain.TodoItem, int64, error) {
 	query := r.db.WithContext(ctx).Model(&todosdomain.TodoItem{}).Where("list_id = ?", listID)
+	if assigneeID != "" {
+		query = query.Where("assignee_id = ?", assigneeID)
+	}
+	if len(labels) > 0 {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM entity_labels l WHERE l.entity_type = ? AND l.entity_id = todo_items.id AND l.label IN ?)",
+			labelsdomain.EntityTodoItem, labels,
+		)
+	}
 	switch archived {
 	case todosdomain.ArchivedOnly:
 		query = query.Where("is_archived = ?", true)
@@ -233,6 +340,30 @@ func (r *PostgresRepository) ListTodoItems(ctx context.Context, listID string, a
 	return items, total, nil
 }
 
+func (r *PostgresRepository) SearchTodoItems(ctx context.Context, familyID, search, assigneeID, viewerID string, limit int) ([]todosdomain.TodoItem, error) {
+	query := r.db.WithContext(ctx).
+		Model(&todosdomain.TodoItem{}).
+		Joins("JOIN todo_lists ON todo_lists.id = todo_items.list_id AND todo_lists.deleted_at IS NULL").
+		Where("todo_lists.family_id = ? AND todo_items.title ILIKE ?", familyID, "%"+strings.TrimSpace(search)+"%")
+	if assigneeID != "" {
+		query = query.Where("todo_items.assignee_id = ?", assigneeID)
+	}
+	if viewerID != "" {
+		query = query.Where(visibleListCondition("todo_lists"), viewerID)
+	}
+
+	query = query.Order("todo_items.is_completed asc, todo_items.created_at desc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var items []todosdomain.TodoItem
+	if err := query.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (r *PostgresRepository) CreateTodoItem(ctx context.Context, item *todosdomain.TodoItem) error {
 	return r.db.WithContext(ctx).Create(item).Error
 }
@@ -263,22 +394,180 @@ func (r *PostgresRepository) GetTodoItemWithListArchive(ctx context.Context, fam
 }
 
 func (r *PostgresRepository) UpdateTodoItem(ctx context.Context, item *todosdomain.TodoItem) error {
-	return r.db.WithContext(ctx).
+	result := r.db.WithContext(ctx).
 		Model(&todosdomain.TodoItem{}).
-		Where("id = ? AND list_id = ?", item.ID, item.ListID).
+		Where("id = ? AND list_id = ? AND version = ?", item.ID, item.ListID, item.Version).
 		Updates(map[string]interface{}{
 			"title":                   item.Title,
 			"is_completed":            item.IsCompleted,
 			"is_archived":             item.IsArchived,
 			"completed_at":            item.CompletedAt,
+			"due_date":                item.DueDate,
+			"assignee_id":             item.AssigneeID,
 			"completed_by_id":         item.CompletedByID,
 			"completed_by_name":       item.CompletedByName,
 			"completed_by_email":      item.CompletedByEmail,
 			"completed_by_avatar_url": item.CompletedByAvatarURL,
-		}).Error
+			"estimated_price":         item.EstimatedPrice,
+			"expense_id":              item.ExpenseID,
+			"version":                 gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return todosdomain.ErrVersionConflict
+	}
+	item.Version++
+	return nil
 }
 
 func (r *PostgresRepository) SoftDeleteTodoItem(ctx context.Context, itemID string) (bool, error) {
 	result := r.db.WithContext(ctx).Delete(&todosdomain.TodoItem{}, "id = ?", itemID)
 	return result.RowsAffected > 0, result.Error
 }
+
+func (r *PostgresRepository) ListDueTodoItems(ctx context.Context, familyID, viewerID string) ([]todosdomain.DueTodoItem, error) {
+	type row struct {
+		todosdomain.TodoItem
+		ListTitle string `gorm:"column:list_title"`
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&todosdomain.TodoItem{}).
+		Select("todo_items.*, todo_lists.title as list_title").
+		Joins("join todo_lists on todo_lists.id = todo_items.list_id").
+		Where("todo_lists.family_id = ?", familyID).
+		Where("todo_lists.deleted_at IS NULL").
+		Where("todo_items.due_date IS NOT NULL").
+		Where("todo_items.is_completed = ? AND todo_items.is_archived = ?", false, false)
+	if viewerID != "" {
+		query = query.Where(visibleListCondition("todo_lists"), viewerID)
+	}
+
+	var rows []row
+	if err := query.
+		Order("todo_items.due_date asc, todo_items.created_at asc").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]todosdomain.DueTodoItem, 0, len(rows))
+	for _, item := range rows {
+		result = append(result, todosdomain.DueTodoItem{
+			Item:      item.TodoItem,
+			ListTitle: item.ListTitle,
+		})
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) CreateListShare(ctx context.Context, share *todosdomain.ListShare) error {
+	return r.db.WithContext(ctx).Create(share).Error
+}
+
+func (r *PostgresRepository) ListListShares(ctx context.Context, familyID, listID string) ([]todosdomain.ListShare, error) {
+	var shares []todosdomain.ListShare
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND list_id = ?", familyID, listID).
+		Order("created_at desc").
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (r *PostgresRepository) CountActiveListShares(ctx context.Context, listID string, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&todosdomain.ListShare{}).
+		Where("list_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", listID, now).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresRepository) GetListShareByHash(ctx context.Context, hash string) (*todosdomain.ListShare, error) {
+	var share todosdomain.ListShare
+	if err := r.db.WithContext(ctx).
+		Where("token_hash = ?", hash).
+		First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, todosdomain.ErrShareNotFound
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *PostgresRepository) RevokeListShare(ctx context.Context, familyID, listID, shareID string, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&todosdomain.ListShare{}).
+		Where("id = ? AND family_id = ? AND list_id = ? AND revoked_at IS NULL", shareID, familyID, listID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return todosdomain.ErrShareNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListListViewers(ctx context.Context, listIDs []string) (map[string][]string, error) {
+	var viewers []todosdomain.ListViewer
+	if err := r.db.WithContext(ctx).
+		Where("list_id IN ?", listIDs).
+		Order("user_id asc").
+		Find(&viewers).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(listIDs))
+	for _, viewer := range viewers {
+		result[viewer.ListID] = append(result[viewer.ListID], viewer.UserID)
+	}
+	return result, nil
+}
+
+func (r *PostgresRepository) ReplaceListViewers(ctx context.Context, listID string, userIDs []string) error {
+	if err := r.db.WithContext(ctx).
+		Where("list_id = ?", listID).
+		Delete(&todosdomain.ListViewer{}).Error; err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	viewers := make([]todosdomain.ListViewer, 0, len(userIDs))
+	for _, userID := range userIDs {
+		viewers = append(viewers, todosdomain.ListViewer{ListID: listID, UserID: userID})
+	}
+	return r.db.WithContext(ctx).Create(&viewers).Error
+}
+
+func (r *PostgresRepository) CountFamilyMembers(ctx context.Context, familyID string, userIDs []string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("family_members").
+		// Compared as text so ids that are not UUIDs simply don't match.
+		Where("family_id = ? AND user_id::text IN ?", familyID, userIDs).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresRepository) ExpenseExists(ctx context.Context, familyID, expenseID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("expenses").
+		// Compared as text so ids that are not UUIDs simply don't match.
+		Where("family_id = ? AND id::text = ?", familyID, expenseID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// visibleListCondition matches lists that are not private or have the bound
+// user among their viewers.
+func visibleListCondition(table string) string {
+	return "(" + table + ".is_private = false OR EXISTS (SELECT 1 FROM todo_list_viewers v WHERE v.list_id = " + table + ".id AND v.user_id = ?))"
+}
@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"family-app-go/internal/db"
+	usagedomain "family-app-go/internal/domain/usage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) AddCounts(ctx context.Context, counts []usagedomain.DailyCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "family_id"}, {Name: "day"}, {Name: "method"}, {Name: "route"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count": gorm.Expr("api_usage_daily.count + excluded.count"),
+			}),
+		}).
+		Create(&counts).Error
+}
+
+func (r *PostgresRepository) ListModules(ctx context.Context, from, to time.Time) ([]usagedomain.ModuleUsage, error) {
+	var modules []usagedomain.ModuleUsage
+	if err := r.db.WithContext(ctx).
+		Clauses(db.ReadReplica).
+		Model(&usagedomain.DailyCount{}).
+		Select("module, SUM(count) AS requests, COUNT(DISTINCT family_id) AS families, MAX(day) AS last_day").
+		Where("day >= ? AND day <= ?", from, to).
+		Group("module").
+		Order("requests desc, module").
+		Scan(&modules).Error; err != nil {
+		return nil, err
+	}
+	return modules, nil
+}
+
+func (r *PostgresRepository) ListDaily(ctx context.Context, filter usagedomain.ListFilter) ([]usagedomain.DailyCount, error) {
+	query := r.db.WithContext(ctx).
+		Clauses(db.ReadReplica).
+		Where("day >= ? AND day <= ?", filter.From, filter.To)
+	if filter.FamilyID != "" {
+		query = query.Where("family_id = ?", filter.FamilyID)
+	}
+	if filter.Module != "" {
+		query = query.Where("module = ?", filter.Module)
+	}
+
+	var counts []usagedomain.DailyCount
+	if err := query.
+		Order("day desc, count desc, route, method").
+		Limit(filter.Limit).
+		Find(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("day < ?", day).
+		Delete(&usagedomain.DailyCount{})
+	return result.RowsAffected, result.Error
+}
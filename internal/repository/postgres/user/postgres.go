@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	domain "family-app-go/internal/domain/user"
@@ -33,5 +34,174 @@ func (r *PostgresRepository) UpsertProfile(ctx context.Context, profile *domain.
 			Columns:   []clause.Column{{Name: "user_id"}},
 			DoUpdates: clause.Assignments(updates),
 		}).
+		Clauses(clause.Returning{}).
 		Create(profile).Error
 }
+
+func (r *PostgresRepository) GetProfile(ctx context.Context, userID string) (*domain.Profile, error) {
+	var profile domain.Profile
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrProfileNotFound
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *PostgresRepository) UpdatePreferences(ctx context.Context, userID string, update domain.PreferencesUpdate) error {
+	now := time.Now().UTC()
+	updates := map[string]interface{}{
+		"updated_at": now,
+	}
+	if update.Theme != nil {
+		updates["theme"] = *update.Theme
+	}
+	if update.Language != nil {
+		updates["language"] = *update.Language
+	}
+	if update.WeightUnit != nil {
+		updates["weight_unit"] = *update.WeightUnit
+	}
+	if update.NotifyTodoReminders != nil {
+		updates["notify_todo_reminders"] = *update.NotifyTodoReminders
+	}
+	if update.NotifyGymNudges != nil {
+		updates["notify_gym_nudges"] = *update.NotifyGymNudges
+	}
+	if update.NotifyFamilyActivity != nil {
+		updates["notify_family_activity"] = *update.NotifyFamilyActivity
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.Profile{UserID: userID}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Profile{}).
+			Where("user_id = ?", userID).
+			Updates(updates).Error
+	})
+}
+
+func (r *PostgresRepository) SetUploadedAvatar(ctx context.Context, userID, key, url string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.Profile{UserID: userID}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Profile{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{
+				"uploaded_avatar_key": key,
+				"uploaded_avatar_url": url,
+				"updated_at":          time.Now().UTC(),
+			}).Error
+	})
+}
+
+func (r *PostgresRepository) ListProfilesToSync(ctx context.Context, syncedBefore time.Time, limit int) ([]domain.Profile, error) {
+	var profiles []domain.Profile
+	if err := r.db.WithContext(ctx).
+		Where("synced_at IS NULL OR synced_at < ?", syncedBefore).
+		Order("synced_at ASC NULLS FIRST").
+		Order("user_id ASC").
+		Limit(limit).
+		Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func (r *PostgresRepository) ApplyRemoteProfile(ctx context.Context, userID string, remote domain.RemoteProfile, syncedAt time.Time) error {
+	updates := map[string]interface{}{
+		"synced_at":  syncedAt,
+		"updated_at": time.Now().UTC(),
+	}
+	if remote.Name != "" {
+		updates["name"] = remote.Name
+	}
+	if remote.Email != "" {
+		updates["email"] = remote.Email
+	}
+	if remote.AvatarURL != "" {
+		updates["avatar_url"] = remote.AvatarURL
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&domain.Profile{}).
+		Where("user_id = ?", userID).
+		Updates(updates).Error
+}
+
+func (r *PostgresRepository) MarkProfileSynced(ctx context.Context, userID string, syncedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Profile{}).
+		Where("user_id = ?", userID).
+		Update("synced_at", syncedAt).Error
+}
+
+func (r *PostgresRepository) ListSyncOutbox(ctx context.Context, limit int) ([]domain.SyncOutboxEntry, error) {
+	var entries []domain.SyncOutboxEntry
+	if err := r.db.WithContext(ctx).
+		Order("queued_at ASC").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *PostgresRepository) PatchCompletedBySnapshots(ctx context.Context, entry domain.SyncOutboxEntry) (int64, error) {
+	var patched int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var profile domain.Profile
+		err := tx.Where("user_id = ?", entry.UserID).First(&profile).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// Erased since it was queued; its snapshots are anonymized.
+		case err != nil:
+			return err
+		default:
+			// Unknown names and emails keep the snapshot's; the avatar
+			// always follows the profile, uploaded or not.
+			result := tx.Exec(`UPDATE todo_items t SET
+				completed_by_name = COALESCE(v.name, t.completed_by_name),
+				completed_by_email = COALESCE(v.email, t.completed_by_email),
+				completed_by_avatar_url = v.avatar_url
+				FROM (SELECT ?::text AS name, ?::text AS email, ?::text AS avatar_url) v
+				WHERE t.completed_by_id = ?
+				AND (t.completed_by_name IS DISTINCT FROM COALESCE(v.name, t.completed_by_name)
+					OR t.completed_by_email IS DISTINCT FROM COALESCE(v.email, t.completed_by_email)
+					OR t.completed_by_avatar_url IS DISTINCT FROM v.avatar_url)`,
+				nonEmpty(profile.Name), nonEmpty(profile.Email), nonEmptyString(profile.EffectiveAvatarURL()), entry.UserID)
+			if result.Error != nil {
+				return result.Error
+			}
+			patched = result.RowsAffected
+		}
+		// A change committed after the entry was read re-queued it with a
+		// later queued_at; that entry stays for the next run.
+		return tx.Exec("DELETE FROM profile_sync_outbox WHERE user_id = ? AND queued_at = ?", entry.UserID, entry.QueuedAt).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return patched, nil
+}
+
+func nonEmpty(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	return nonEmptyString(*value)
+}
+
+func nonEmptyString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
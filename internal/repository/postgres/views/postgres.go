@@ -0,0 +1,87 @@
+package views
+
+import (
+	"context"
+	"errors"
+
+	viewsdomain "family-app-go/internal/domain/views"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) CreateView(ctx context.Context, view *viewsdomain.SavedView) error {
+	return r.db.WithContext(ctx).Create(view).Error
+}
+
+func (r *PostgresRepository) UpdateView(ctx context.Context, view *viewsdomain.SavedView) error {
+	result := r.db.WithContext(ctx).
+		Model(&viewsdomain.SavedView{}).
+		Where("id = ? AND user_id = ?", view.ID, view.UserID).
+		Updates(map[string]interface{}{
+			"name":       view.Name,
+			"filters":    view.Filters,
+			"updated_at": view.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return viewsdomain.ErrViewNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetView(ctx context.Context, userID, id string) (*viewsdomain.SavedView, error) {
+	var view viewsdomain.SavedView
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&view).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, viewsdomain.ErrViewNotFound
+		}
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (r *PostgresRepository) ListViews(ctx context.Context, userID string, entityType viewsdomain.EntityType) ([]viewsdomain.SavedView, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+
+	var views []viewsdomain.SavedView
+	if err := query.Order("name asc, id asc").Find(&views).Error; err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (r *PostgresRepository) CountViews(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&viewsdomain.SavedView{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresRepository) DeleteView(ctx context.Context, userID, id string) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&viewsdomain.SavedView{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return viewsdomain.ErrViewNotFound
+	}
+	return nil
+}
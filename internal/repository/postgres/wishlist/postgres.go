@@ -0,0 +1,89 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	wishlistdomain "family-app-go/internal/domain/wishlist"
+	"gorm.io/gorm"
+)
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) ListItemsByOwner(ctx context.Context, familyID, ownerID string) ([]wishlistdomain.Item, error) {
+	var items []wishlistdomain.Item
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND owner_id = ?", familyID, ownerID).
+		Order("created_at asc").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *PostgresRepository) GetItem(ctx context.Context, familyID, itemID string) (*wishlistdomain.Item, error) {
+	var item wishlistdomain.Item
+	if err := r.db.WithContext(ctx).
+		Where("family_id = ? AND id = ?", familyID, itemID).
+		First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, wishlistdomain.ErrItemNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *PostgresRepository) CreateItem(ctx context.Context, item *wishlistdomain.Item) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+func (r *PostgresRepository) UpdateItem(ctx context.Context, item *wishlistdomain.Item) error {
+	return r.db.WithContext(ctx).
+		Model(&wishlistdomain.Item{}).
+		Where("id = ? AND family_id = ?", item.ID, item.FamilyID).
+		Updates(map[string]interface{}{
+			"title":      item.Title,
+			"url":        item.URL,
+			"price":      item.Price,
+			"currency":   item.Currency,
+			"note":       item.Note,
+			"updated_at": time.Now().UTC(),
+		}).Error
+}
+
+func (r *PostgresRepository) DeleteItem(ctx context.Context, familyID, itemID string) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&wishlistdomain.Item{}, "id = ? AND family_id = ?", itemID, familyID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClaimItem sets the claimer only if the item is still unclaimed, so two
+// members racing for the same gift cannot both win.
+func (r *PostgresRepository) ClaimItem(ctx context.Context, itemID, userID string, claimedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&wishlistdomain.Item{}).
+		Where("id = ? AND claimed_by_id IS NULL", itemID).
+		Updates(map[string]interface{}{
+			"claimed_by_id": userID,
+			"claimed_at":    claimedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *PostgresRepository) UnclaimItem(ctx context.Context, itemID, userID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&wishlistdomain.Item{}).
+		Where("id = ? AND claimed_by_id = ?", itemID, userID).
+		Updates(map[string]interface{}{
+			"claimed_by_id": nil,
+			"claimed_at":    nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,111 @@
+package yearreview
+
+import (
+	"context"
+	"time"
+
+	"family-app-go/internal/db"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	yearreviewdomain "family-app-go/internal/domain/yearreview"
+	"gorm.io/gorm"
+)
+
+// sharedWorkouts selects the workouts family members shared with the
+// family; private ones stay out of the review.
+const sharedWorkouts = `FROM workouts w
+	JOIN family_members m ON m.user_id = w.user_id AND m.family_id = ?`
+
+const sharedWorkoutsWhere = `w.visibility = 'family' AND w.date BETWEEN ? AND ?`
+
+type PostgresRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgres(db *gorm.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+func (r *PostgresRepository) SpendingTotals(ctx context.Context, familyID, baseCurrency string, from, to time.Time) (yearreviewdomain.SpendingTotals, error) {
+	var totals yearreviewdomain.SpendingTotals
+	err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(`SELECT
+			COALESCE(SUM(amount_in_base) FILTER (WHERE base_currency = ?), 0) AS total_in_base,
+			COUNT(*) AS count,
+			COUNT(*) FILTER (WHERE amount_in_base IS NULL OR base_currency IS DISTINCT FROM ?) AS unconverted
+		FROM expenses
+		WHERE family_id = ? AND date BETWEEN ? AND ?`,
+		baseCurrency, baseCurrency, familyID, from, to).
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *PostgresRepository) ListExpenseTitles(ctx context.Context, familyID, baseCurrency string, from, to time.Time) ([]yearreviewdomain.ExpenseTitle, error) {
+	// Read through the expense model so encrypted titles are decrypted.
+	var expenses []expensesdomain.Expense
+	err := r.db.WithContext(ctx).Clauses(db.ReadReplica).
+		Select("id", "title", "amount_in_base", "base_currency").
+		Where("family_id = ? AND date BETWEEN ? AND ?", familyID, from, to).
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make([]yearreviewdomain.ExpenseTitle, 0, len(expenses))
+	for _, expense := range expenses {
+		title := yearreviewdomain.ExpenseTitle{Title: expense.Title}
+		if expense.BaseCurrency != nil && *expense.BaseCurrency == baseCurrency {
+			title.AmountInBase = expense.AmountInBase
+		}
+		titles = append(titles, title)
+	}
+	return titles, nil
+}
+
+func (r *PostgresRepository) TopTodoMember(ctx context.Context, familyID string, from, to time.Time) (*yearreviewdomain.TodoMember, error) {
+	var rows []yearreviewdomain.TodoMember
+	err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(`SELECT
+			i.completed_by_id AS user_id,
+			MAX(i.completed_by_name) AS name,
+			COUNT(*) AS items_completed
+		FROM todo_items i
+		JOIN todo_lists l ON l.id = i.list_id
+		WHERE l.family_id = ? AND l.deleted_at IS NULL AND i.deleted_at IS NULL
+			AND i.is_completed AND i.completed_by_id IS NOT NULL
+			AND i.completed_at >= ? AND i.completed_at < ?
+		GROUP BY i.completed_by_id
+		ORDER BY items_completed DESC, MIN(i.completed_at)
+		LIMIT 1`,
+		familyID, from, to.AddDate(0, 0, 1)).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+func (r *PostgresRepository) HeaviestLift(ctx context.Context, familyID string, from, to time.Time) (*yearreviewdomain.Lift, error) {
+	var rows []yearreviewdomain.Lift
+	err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(`SELECT
+			w.user_id, s.exercise, s.weight_kg, s.reps, w.date
+		`+sharedWorkouts+`
+		JOIN workout_sets s ON s.workout_id = w.id
+		WHERE `+sharedWorkoutsWhere+`
+		ORDER BY s.weight_kg DESC, s.reps DESC, w.date
+		LIMIT 1`,
+		familyID, from, to).
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+func (r *PostgresRepository) ListWorkoutDays(ctx context.Context, familyID string, from, to time.Time) ([]yearreviewdomain.WorkoutDay, error) {
+	var days []yearreviewdomain.WorkoutDay
+	err := r.db.WithContext(ctx).Clauses(db.ReadReplica).Raw(`SELECT DISTINCT w.user_id, w.date
+		`+sharedWorkouts+`
+		WHERE `+sharedWorkoutsWhere+`
+		ORDER BY w.user_id, w.date`,
+		familyID, from, to).
+		Scan(&days).Error
+	return days, err
+}
@@ -0,0 +1,33 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+const authKeyPrefix = "family-app:auth:"
+
+// AuthCache stores resolved auth users in Redis so every app instance shares
+// them and logout invalidation applies everywhere.
+type AuthCache struct {
+	client *Client
+}
+
+func NewAuthCache(client *Client) *AuthCache {
+	return &AuthCache{client: client}
+}
+
+func (c *AuthCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	return c.client.Get(authKeyPrefix + key)
+}
+
+func (c *AuthCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return c.client.Del(authKeyPrefix + key)
+	}
+	return c.client.SetPX(authKeyPrefix+key, value, ttl)
+}
+
+func (c *AuthCache) Delete(_ context.Context, key string) error {
+	return c.client.Del(authKeyPrefix + key)
+}
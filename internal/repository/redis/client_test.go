@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer speaks enough RESP to cover GET, SET, DEL, AUTH and SELECT.
+type fakeServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &fakeServer{listener: listener, data: make(map[string]string)}
+	go server.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "AUTH", "SELECT":
+			reply = "+OK\r\n"
+		case "SET":
+			s.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case "GET":
+			value, ok := s.data[args[1]]
+			if !ok {
+				reply = "$-1\r\n"
+			} else {
+				reply = "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+			}
+		case "DEL":
+			delete(s.data, args[1])
+			reply = ":1\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+func TestClientSetGetDel(t *testing.T) {
+	server := newFakeServer(t)
+	client, err := NewClient("redis://:secret@" + server.listener.Addr().String() + "/2")
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	if err := client.SetPX("key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	value, ok, err := client.Get("key")
+	if err != nil || !ok || string(value) != "value" {
+		t.Fatalf("unexpected get: %q %v %v", value, ok, err)
+	}
+	if err := client.Del("key"); err != nil {
+		t.Fatalf("del: %v", err)
+	}
+	if _, ok, err := client.Get("key"); err != nil || ok {
+		t.Fatalf("expected miss after del, got %v %v", ok, err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	want := []string{"AUTH secret", "SELECT 2", "SET key value PX 60000", "GET key", "DEL key", "GET key"}
+	if strings.Join(server.commands, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected commands: %v", server.commands)
+	}
+}
+
+func TestNewClientRejectsBadURLs(t *testing.T) {
+	for _, raw := range []string{"http://localhost:6379", "redis://localhost:6379/x"} {
+		if _, err := NewClient(raw); err == nil {
+			t.Fatalf("expected error for %s", raw)
+		}
+	}
+}
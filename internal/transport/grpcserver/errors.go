@@ -0,0 +1,124 @@
+package grpcserver
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	quotadomain "family-app-go/internal/domain/quota"
+	"family-app-go/internal/transport/httpserver/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain scopes the ErrorInfo reasons, which are the error codes of the
+// HTTP API.
+const errorDomain = "family-app"
+
+func statusError(code codes.Code, reason, message string) error {
+	st := status.New(code, message)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func internalError() error {
+	return statusError(codes.Internal, "internal_error", "internal error")
+}
+
+func notFound(reason, message string) error {
+	return statusError(codes.NotFound, reason, message)
+}
+
+// versionConflict is Aborted, the code gRPC reserves for read-modify-write
+// races; clients re-read the entity and retry.
+func versionConflict() error {
+	return statusError(codes.Aborted, "version_conflict", "resource was modified by someone else")
+}
+
+// approvalRequired is FailedPrecondition with the queued approval's ID in the
+// ErrorInfo metadata; the expense is created once the owner approves it.
+func approvalRequired(approval expensesdomain.ExpenseApproval) error {
+	st := status.New(codes.FailedPrecondition, "expense is above the spending limit and waits for approval")
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "approval_required",
+		Domain:   errorDomain,
+		Metadata: map[string]string{"approval_id": approval.ID},
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// underMaintenance is Unavailable, which clients retry, with the delay the
+// HTTP API sends as Retry-After in a RetryInfo.
+func underMaintenance(retryAfterSeconds int) error {
+	st := status.New(codes.Unavailable, "this feature is under maintenance; retry later")
+	if detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: "maintenance", Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Duration(retryAfterSeconds) * time.Second)},
+	); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// invalidArgument reports validation failures as InvalidArgument with one
+// BadRequest field violation per rejected field.
+func invalidArgument(err error) error {
+	var fieldErrs validation.Errors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) == 0 {
+		return statusError(codes.InvalidArgument, "invalid_request", err.Error())
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Message,
+			Reason:      fieldErr.Code,
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, fieldErrs[0].Message)
+	if detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: "invalid_request", Domain: errorDomain},
+		&errdetails.BadRequest{FieldViolations: violations},
+	); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// quotaExceeded is ResourceExhausted with the quota's name, limit and, for
+// rolling quotas, window in the ErrorInfo metadata and a QuotaFailure.
+func quotaExceeded(err error) error {
+	const message = "family quota exceeded"
+	var exceeded *quotadomain.ExceededError
+	if !errors.As(err, &exceeded) {
+		return statusError(codes.ResourceExhausted, "quota_exceeded", message)
+	}
+
+	metadata := map[string]string{
+		"quota": exceeded.Quota,
+		"limit": strconv.Itoa(exceeded.Limit),
+	}
+	if exceeded.Window > 0 {
+		metadata["window_seconds"] = strconv.FormatInt(int64(exceeded.Window.Seconds()), 10)
+	}
+	st := status.New(codes.ResourceExhausted, message)
+	if detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: "quota_exceeded", Domain: errorDomain, Metadata: metadata},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     "family",
+			Description: exceeded.Error(),
+		}}},
+	); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
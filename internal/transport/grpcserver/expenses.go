@@ -0,0 +1,309 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	quotadomain "family-app-go/internal/domain/quota"
+	"family-app-go/internal/transport/grpcserver/familyv1"
+	"family-app-go/internal/transport/httpserver/validation"
+	"family-app-go/pkg/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultExpensesLimit = 50
+	// streamExpensesPageSize is how many expenses StreamExpenses reads per
+	// query.
+	streamExpensesPageSize = 200
+)
+
+type expensesServer struct {
+	familyv1.UnimplementedExpensesServiceServer
+	service
+	expenses *expensesdomain.Service
+	activity *activitydomain.Service
+}
+
+func (s *expensesServer) ListExpenses(ctx context.Context, req *familyv1.ListExpensesRequest) (*familyv1.ListExpensesResponse, error) {
+	v := validation.New()
+	validateExpenseFilter(v, req.GetFilter())
+	limit := int(req.GetLimit())
+	offset := int(req.GetOffset())
+	v.NonNegative("limit", &limit)
+	v.NonNegative("offset", &offset)
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if limit == 0 {
+		limit = defaultExpensesLimit
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.expenses.list")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := toExpenseFilter(req.GetFilter())
+	filter.Limit = limit
+	filter.Offset = offset
+	items, total, err := s.expenses.ListExpenses(ctx, family.ID, filter)
+	if err != nil {
+		s.requestLog(ctx).InternalError("grpc.expenses.list: list expenses failed", err, "user_id", user.ID, "family_id", family.ID)
+		return nil, internalError()
+	}
+
+	response := &familyv1.ListExpensesResponse{
+		Items: make([]*familyv1.Expense, 0, len(items)),
+		Total: total,
+	}
+	for _, item := range items {
+		response.Items = append(response.Items, toExpenseMessage(item))
+	}
+	return response, nil
+}
+
+func (s *expensesServer) StreamExpenses(req *familyv1.StreamExpensesRequest, stream grpc.ServerStreamingServer[familyv1.Expense]) error {
+	ctx := stream.Context()
+
+	v := validation.New()
+	validateExpenseFilter(v, req.GetFilter())
+	if err := v.Err(); err != nil {
+		return invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.expenses.stream")
+	if err != nil {
+		return err
+	}
+
+	filter := toExpenseFilter(req.GetFilter())
+	filter.Limit = streamExpensesPageSize
+	for {
+		items, _, err := s.expenses.ListExpenses(ctx, family.ID, filter)
+		if err != nil {
+			if ctx.Err() != nil {
+				return statusError(codes.Canceled, "canceled", "stream canceled")
+			}
+			s.requestLog(ctx).InternalError("grpc.expenses.stream: list expenses failed", err, "user_id", user.ID, "family_id", family.ID, "offset", filter.Offset)
+			return internalError()
+		}
+		for _, item := range items {
+			if err := stream.Send(toExpenseMessage(item)); err != nil {
+				return err
+			}
+		}
+		if len(items) < filter.Limit {
+			return nil
+		}
+		filter.Offset += len(items)
+	}
+}
+
+func (s *expensesServer) CreateExpense(ctx context.Context, req *familyv1.CreateExpenseRequest) (*familyv1.Expense, error) {
+	v := validation.New()
+	validateExpense(v, req.GetDate(), req.GetAmount(), req.GetTitle(), req.GetCurrency())
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.expenses.create")
+	if err != nil {
+		return nil, err
+	}
+
+	date, _ := parseDate(req.GetDate())
+	created, err := s.expenses.CreateExpense(ctx, expensesdomain.CreateExpenseInput{
+		FamilyID:     family.ID,
+		UserID:       user.ID,
+		Date:         date,
+		Amount:       req.GetAmount(),
+		Currency:     req.GetCurrency(),
+		BaseCurrency: family.DefaultCurrency,
+		Title:        req.GetTitle(),
+		CategoryIDs:  req.GetCategoryIds(),
+
+		AllowedCurrencies: family.Currencies(),
+	})
+	if err != nil {
+		return nil, s.expenseError(ctx, "grpc.expenses.create", err, "user_id", user.ID, "family_id", family.ID)
+	}
+
+	if _, err := s.activity.RecordExpenseCreated(ctx, family.ID, actorFromUser(user), created.ID, created.Title); err != nil {
+		s.requestLog(ctx).InternalError("grpc.expenses.create: record activity failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", created.ID)
+	}
+	return toExpenseMessage(*created), nil
+}
+
+func (s *expensesServer) UpdateExpense(ctx context.Context, req *familyv1.UpdateExpenseRequest) (*familyv1.Expense, error) {
+	v := validation.New()
+	v.Required("id", req.GetId())
+	validateExpense(v, req.GetDate(), req.GetAmount(), req.GetTitle(), req.GetCurrency())
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.expenses.update")
+	if err != nil {
+		return nil, err
+	}
+
+	expenseID := strings.TrimSpace(req.GetId())
+	date, _ := parseDate(req.GetDate())
+	updated, err := s.expenses.UpdateExpense(ctx, expensesdomain.UpdateExpenseInput{
+		ID:              expenseID,
+		FamilyID:        family.ID,
+		UserID:          user.ID,
+		Date:            date,
+		Amount:          req.GetAmount(),
+		Currency:        req.GetCurrency(),
+		BaseCurrency:    family.DefaultCurrency,
+		Title:           req.GetTitle(),
+		CategoryIDs:     req.GetCategoryIds(),
+		ExpectedVersion: req.GetExpectedVersion(),
+
+		AllowedCurrencies: family.Currencies(),
+	})
+	if err != nil {
+		return nil, s.expenseError(ctx, "grpc.expenses.update", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+	}
+	return toExpenseMessage(*updated), nil
+}
+
+func (s *expensesServer) DeleteExpense(ctx context.Context, req *familyv1.DeleteExpenseRequest) (*familyv1.DeleteExpenseResponse, error) {
+	v := validation.New()
+	v.Required("id", req.GetId())
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.expenses.delete")
+	if err != nil {
+		return nil, err
+	}
+
+	expenseID := strings.TrimSpace(req.GetId())
+	if err := s.expenses.DeleteExpense(ctx, family.ID, expenseID); err != nil {
+		return nil, s.expenseError(ctx, "grpc.expenses.delete", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+	}
+	return &familyv1.DeleteExpenseResponse{}, nil
+}
+
+func (s *expensesServer) ListCategories(ctx context.Context, _ *familyv1.ListCategoriesRequest) (*familyv1.ListCategoriesResponse, error) {
+	user, family, err := s.callerFamily(ctx, "grpc.categories.list")
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.expenses.ListCategories(ctx, family.ID)
+	if err != nil {
+		s.requestLog(ctx).InternalError("grpc.categories.list: list categories failed", err, "user_id", user.ID, "family_id", family.ID)
+		return nil, internalError()
+	}
+
+	response := &familyv1.ListCategoriesResponse{Items: make([]*familyv1.Category, 0, len(categories))}
+	for _, category := range categories {
+		response.Items = append(response.Items, &familyv1.Category{
+			Id:        category.ID,
+			FamilyId:  category.FamilyID,
+			Name:      category.Name,
+			Color:     category.Color,
+			Emoji:     category.Emoji,
+			CreatedAt: timestamppb.New(category.CreatedAt),
+			Version:   category.Version,
+		})
+	}
+	return response, nil
+}
+
+func (s *expensesServer) expenseError(ctx context.Context, operation string, err error, attrs ...any) error {
+	var precisionErr *money.PrecisionError
+	var approvalErr *expensesdomain.ApprovalRequiredError
+	switch {
+	case errors.As(err, &approvalErr):
+		s.requestLog(ctx).BusinessError(operation+": approval required", err, attrs...)
+		return approvalRequired(approvalErr.Approval)
+	case errors.Is(err, expensesdomain.ErrExpenseNotFound):
+		s.requestLog(ctx).BusinessError(operation+": expense not found", err, attrs...)
+		return notFound("expense_not_found", "expense not found")
+	case errors.Is(err, expensesdomain.ErrCategoryNotFound):
+		s.requestLog(ctx).BusinessError(operation+": category not found", err, attrs...)
+		return notFound("category_not_found", "category not found")
+	case errors.Is(err, expensesdomain.ErrRateNotAvailable):
+		s.requestLog(ctx).BusinessError(operation+": rate not available", err, attrs...)
+		return statusError(codes.FailedPrecondition, "rate_not_available", "rate is not available for selected date")
+	case errors.Is(err, expensesdomain.ErrVersionConflict):
+		s.requestLog(ctx).BusinessError(operation+": version conflict", err, attrs...)
+		return versionConflict()
+	case errors.Is(err, quotadomain.ErrExceeded):
+		s.requestLog(ctx).BusinessError(operation+": quota exceeded", err, attrs...)
+		return quotaExceeded(err)
+	case errors.As(err, &precisionErr):
+		s.requestLog(ctx).BusinessError(operation+": invalid precision", err, attrs...)
+		return invalidArgument(validation.PrecisionErr("amount", precisionErr))
+	case errors.Is(err, expensesdomain.ErrInvalidCurrency):
+		s.requestLog(ctx).BusinessError(operation+": invalid currency", err, attrs...)
+		return invalidArgument(validation.FieldErr("currency", validation.CodeCurrency, err.Error()))
+	default:
+		s.requestLog(ctx).InternalError(operation+": failed", err, attrs...)
+		return internalError()
+	}
+}
+
+func validateExpense(v *validation.Validator, date string, amount float64, title, currency string) {
+	v.Required("date", date)
+	v.Date("date", &date)
+	v.Positive("amount", amount)
+	v.Precision("amount", amount, currency)
+	v.Required("title", title)
+	v.Required("currency", currency)
+	v.Currency("currency", currency)
+}
+
+func validateExpenseFilter(v *validation.Validator, filter *familyv1.ExpenseFilter) {
+	from := filter.GetFrom()
+	to := filter.GetTo()
+	v.Date("filter.from", &from)
+	v.Date("filter.to", &to)
+}
+
+// toExpenseFilter maps a validated filter.
+func toExpenseFilter(filter *familyv1.ExpenseFilter) expensesdomain.ListFilter {
+	result := expensesdomain.ListFilter{
+		From:     parseOptionalDate(filter.GetFrom()),
+		To:       parseOptionalDate(filter.GetTo()),
+		Currency: strings.ToUpper(strings.TrimSpace(filter.GetCurrency())),
+	}
+	for _, categoryID := range filter.GetCategoryIds() {
+		if categoryID = strings.TrimSpace(categoryID); categoryID != "" {
+			result.CategoryIDs = append(result.CategoryIDs, categoryID)
+		}
+	}
+	return result
+}
+
+func toExpenseMessage(expense expensesdomain.ExpenseWithCategories) *familyv1.Expense {
+	return &familyv1.Expense{
+		Id:           expense.ID,
+		FamilyId:     expense.FamilyID,
+		UserId:       expense.UserID,
+		Date:         expense.Date.Format(dateLayout),
+		Amount:       expense.Amount,
+		Currency:     expense.Currency,
+		BaseCurrency: expense.BaseCurrency,
+		ExchangeRate: expense.ExchangeRate,
+		AmountInBase: expense.AmountInBase,
+		RateDate:     formatOptionalDate(expense.RateDate),
+		RateSource:   expense.RateSource,
+		Title:        expense.Title,
+		CategoryIds:  expense.CategoryIDs,
+		CreatedAt:    timestamppb.New(expense.CreatedAt),
+		UpdatedAt:    timestamppb.New(expense.UpdatedAt),
+		Version:      expense.Version,
+	}
+}
@@ -0,0 +1,889 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: family/v1/sync.proto
+
+package familyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SyncBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Same rules as the Idempotency-Key header: 8 to 128 characters.
+	IdempotencyKey string           `protobuf:"bytes,1,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Operations     []*SyncOperation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncBatchRequest) Reset() {
+	*x = SyncBatchRequest{}
+	mi := &file_family_v1_sync_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBatchRequest) ProtoMessage() {}
+
+func (x *SyncBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBatchRequest.ProtoReflect.Descriptor instead.
+func (*SyncBatchRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SyncBatchRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *SyncBatchRequest) GetOperations() []*SyncOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+type SyncOperation struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OperationId string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	LocalId     string                 `protobuf:"bytes,2,opt,name=local_id,json=localId,proto3" json:"local_id,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*SyncOperation_CreateExpense
+	//	*SyncOperation_CreateTodo
+	//	*SyncOperation_SetTodoCompleted
+	Payload       isSyncOperation_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncOperation) Reset() {
+	*x = SyncOperation{}
+	mi := &file_family_v1_sync_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOperation) ProtoMessage() {}
+
+func (x *SyncOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOperation.ProtoReflect.Descriptor instead.
+func (*SyncOperation) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SyncOperation) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *SyncOperation) GetLocalId() string {
+	if x != nil {
+		return x.LocalId
+	}
+	return ""
+}
+
+func (x *SyncOperation) GetPayload() isSyncOperation_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SyncOperation) GetCreateExpense() *SyncCreateExpense {
+	if x != nil {
+		if x, ok := x.Payload.(*SyncOperation_CreateExpense); ok {
+			return x.CreateExpense
+		}
+	}
+	return nil
+}
+
+func (x *SyncOperation) GetCreateTodo() *SyncCreateTodo {
+	if x != nil {
+		if x, ok := x.Payload.(*SyncOperation_CreateTodo); ok {
+			return x.CreateTodo
+		}
+	}
+	return nil
+}
+
+func (x *SyncOperation) GetSetTodoCompleted() *SyncSetTodoCompleted {
+	if x != nil {
+		if x, ok := x.Payload.(*SyncOperation_SetTodoCompleted); ok {
+			return x.SetTodoCompleted
+		}
+	}
+	return nil
+}
+
+type isSyncOperation_Payload interface {
+	isSyncOperation_Payload()
+}
+
+type SyncOperation_CreateExpense struct {
+	CreateExpense *SyncCreateExpense `protobuf:"bytes,3,opt,name=create_expense,json=createExpense,proto3,oneof"`
+}
+
+type SyncOperation_CreateTodo struct {
+	CreateTodo *SyncCreateTodo `protobuf:"bytes,4,opt,name=create_todo,json=createTodo,proto3,oneof"`
+}
+
+type SyncOperation_SetTodoCompleted struct {
+	SetTodoCompleted *SyncSetTodoCompleted `protobuf:"bytes,5,opt,name=set_todo_completed,json=setTodoCompleted,proto3,oneof"`
+}
+
+func (*SyncOperation_CreateExpense) isSyncOperation_Payload() {}
+
+func (*SyncOperation_CreateTodo) isSyncOperation_Payload() {}
+
+func (*SyncOperation_SetTodoCompleted) isSyncOperation_Payload() {}
+
+type SyncCreateExpense struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	CategoryIds   []string               `protobuf:"bytes,5,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncCreateExpense) Reset() {
+	*x = SyncCreateExpense{}
+	mi := &file_family_v1_sync_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncCreateExpense) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncCreateExpense) ProtoMessage() {}
+
+func (x *SyncCreateExpense) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncCreateExpense.ProtoReflect.Descriptor instead.
+func (*SyncCreateExpense) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SyncCreateExpense) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *SyncCreateExpense) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SyncCreateExpense) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SyncCreateExpense) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SyncCreateExpense) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+type SyncCreateTodo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncCreateTodo) Reset() {
+	*x = SyncCreateTodo{}
+	mi := &file_family_v1_sync_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncCreateTodo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncCreateTodo) ProtoMessage() {}
+
+func (x *SyncCreateTodo) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncCreateTodo.ProtoReflect.Descriptor instead.
+func (*SyncCreateTodo) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SyncCreateTodo) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *SyncCreateTodo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+type SyncSetTodoCompleted struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of todo_id or todo_local_id is required.
+	TodoId        string `protobuf:"bytes,1,opt,name=todo_id,json=todoId,proto3" json:"todo_id,omitempty"`
+	TodoLocalId   string `protobuf:"bytes,2,opt,name=todo_local_id,json=todoLocalId,proto3" json:"todo_local_id,omitempty"`
+	IsCompleted   bool   `protobuf:"varint,3,opt,name=is_completed,json=isCompleted,proto3" json:"is_completed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncSetTodoCompleted) Reset() {
+	*x = SyncSetTodoCompleted{}
+	mi := &file_family_v1_sync_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncSetTodoCompleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncSetTodoCompleted) ProtoMessage() {}
+
+func (x *SyncSetTodoCompleted) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncSetTodoCompleted.ProtoReflect.Descriptor instead.
+func (*SyncSetTodoCompleted) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SyncSetTodoCompleted) GetTodoId() string {
+	if x != nil {
+		return x.TodoId
+	}
+	return ""
+}
+
+func (x *SyncSetTodoCompleted) GetTodoLocalId() string {
+	if x != nil {
+		return x.TodoLocalId
+	}
+	return ""
+}
+
+func (x *SyncSetTodoCompleted) GetIsCompleted() bool {
+	if x != nil {
+		return x.IsCompleted
+	}
+	return false
+}
+
+type SyncBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SyncId        string                 `protobuf:"bytes,1,opt,name=sync_id,json=syncId,proto3" json:"sync_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Summary       *SyncSummary           `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	Results       []*SyncOperationResult `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	Mappings      []*SyncEntityMapping   `protobuf:"bytes,5,rep,name=mappings,proto3" json:"mappings,omitempty"`
+	ServerTime    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncBatchResponse) Reset() {
+	*x = SyncBatchResponse{}
+	mi := &file_family_v1_sync_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncBatchResponse) ProtoMessage() {}
+
+func (x *SyncBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncBatchResponse.ProtoReflect.Descriptor instead.
+func (*SyncBatchResponse) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SyncBatchResponse) GetSyncId() string {
+	if x != nil {
+		return x.SyncId
+	}
+	return ""
+}
+
+func (x *SyncBatchResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SyncBatchResponse) GetSummary() *SyncSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *SyncBatchResponse) GetResults() []*SyncOperationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SyncBatchResponse) GetMappings() []*SyncEntityMapping {
+	if x != nil {
+		return x.Mappings
+	}
+	return nil
+}
+
+func (x *SyncBatchResponse) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+type SyncSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Applied       int32                  `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	Duplicate     int32                  `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	Failed        int32                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncSummary) Reset() {
+	*x = SyncSummary{}
+	mi := &file_family_v1_sync_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncSummary) ProtoMessage() {}
+
+func (x *SyncSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncSummary.ProtoReflect.Descriptor instead.
+func (*SyncSummary) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SyncSummary) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SyncSummary) GetApplied() int32 {
+	if x != nil {
+		return x.Applied
+	}
+	return 0
+}
+
+func (x *SyncSummary) GetDuplicate() int32 {
+	if x != nil {
+		return x.Duplicate
+	}
+	return 0
+}
+
+func (x *SyncSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type SyncOperationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	LocalId       *string                `protobuf:"bytes,4,opt,name=local_id,json=localId,proto3,oneof" json:"local_id,omitempty"`
+	Entity        *string                `protobuf:"bytes,5,opt,name=entity,proto3,oneof" json:"entity,omitempty"`
+	ServerId      *string                `protobuf:"bytes,6,opt,name=server_id,json=serverId,proto3,oneof" json:"server_id,omitempty"`
+	Error         *SyncOperationError    `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncOperationResult) Reset() {
+	*x = SyncOperationResult{}
+	mi := &file_family_v1_sync_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncOperationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOperationResult) ProtoMessage() {}
+
+func (x *SyncOperationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOperationResult.ProtoReflect.Descriptor instead.
+func (*SyncOperationResult) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SyncOperationResult) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *SyncOperationResult) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SyncOperationResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SyncOperationResult) GetLocalId() string {
+	if x != nil && x.LocalId != nil {
+		return *x.LocalId
+	}
+	return ""
+}
+
+func (x *SyncOperationResult) GetEntity() string {
+	if x != nil && x.Entity != nil {
+		return *x.Entity
+	}
+	return ""
+}
+
+func (x *SyncOperationResult) GetServerId() string {
+	if x != nil && x.ServerId != nil {
+		return *x.ServerId
+	}
+	return ""
+}
+
+func (x *SyncOperationResult) GetError() *SyncOperationError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type SyncOperationError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Retryable     bool                   `protobuf:"varint,3,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncOperationError) Reset() {
+	*x = SyncOperationError{}
+	mi := &file_family_v1_sync_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncOperationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOperationError) ProtoMessage() {}
+
+func (x *SyncOperationError) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOperationError.ProtoReflect.Descriptor instead.
+func (*SyncOperationError) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SyncOperationError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *SyncOperationError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SyncOperationError) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+type SyncEntityMapping struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entity        string                 `protobuf:"bytes,1,opt,name=entity,proto3" json:"entity,omitempty"`
+	LocalId       string                 `protobuf:"bytes,2,opt,name=local_id,json=localId,proto3" json:"local_id,omitempty"`
+	ServerId      string                 `protobuf:"bytes,3,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncEntityMapping) Reset() {
+	*x = SyncEntityMapping{}
+	mi := &file_family_v1_sync_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncEntityMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncEntityMapping) ProtoMessage() {}
+
+func (x *SyncEntityMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_sync_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncEntityMapping.ProtoReflect.Descriptor instead.
+func (*SyncEntityMapping) Descriptor() ([]byte, []int) {
+	return file_family_v1_sync_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SyncEntityMapping) GetEntity() string {
+	if x != nil {
+		return x.Entity
+	}
+	return ""
+}
+
+func (x *SyncEntityMapping) GetLocalId() string {
+	if x != nil {
+		return x.LocalId
+	}
+	return ""
+}
+
+func (x *SyncEntityMapping) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+var File_family_v1_sync_proto protoreflect.FileDescriptor
+
+const file_family_v1_sync_proto_rawDesc = "" +
+	"\n" +
+	"\x14family/v1/sync.proto\x12\tfamily.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"u\n" +
+	"\x10SyncBatchRequest\x12'\n" +
+	"\x0fidempotency_key\x18\x01 \x01(\tR\x0eidempotencyKey\x128\n" +
+	"\n" +
+	"operations\x18\x02 \x03(\v2\x18.family.v1.SyncOperationR\n" +
+	"operations\"\xae\x02\n" +
+	"\rSyncOperation\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12\x19\n" +
+	"\blocal_id\x18\x02 \x01(\tR\alocalId\x12E\n" +
+	"\x0ecreate_expense\x18\x03 \x01(\v2\x1c.family.v1.SyncCreateExpenseH\x00R\rcreateExpense\x12<\n" +
+	"\vcreate_todo\x18\x04 \x01(\v2\x19.family.v1.SyncCreateTodoH\x00R\n" +
+	"createTodo\x12O\n" +
+	"\x12set_todo_completed\x18\x05 \x01(\v2\x1f.family.v1.SyncSetTodoCompletedH\x00R\x10setTodoCompletedB\t\n" +
+	"\apayload\"\x94\x01\n" +
+	"\x11SyncCreateExpense\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12!\n" +
+	"\fcategory_ids\x18\x05 \x03(\tR\vcategoryIds\"?\n" +
+	"\x0eSyncCreateTodo\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\"v\n" +
+	"\x14SyncSetTodoCompleted\x12\x17\n" +
+	"\atodo_id\x18\x01 \x01(\tR\x06todoId\x12\"\n" +
+	"\rtodo_local_id\x18\x02 \x01(\tR\vtodoLocalId\x12!\n" +
+	"\fis_completed\x18\x03 \x01(\bR\visCompleted\"\xa7\x02\n" +
+	"\x11SyncBatchResponse\x12\x17\n" +
+	"\async_id\x18\x01 \x01(\tR\x06syncId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x120\n" +
+	"\asummary\x18\x03 \x01(\v2\x16.family.v1.SyncSummaryR\asummary\x128\n" +
+	"\aresults\x18\x04 \x03(\v2\x1e.family.v1.SyncOperationResultR\aresults\x128\n" +
+	"\bmappings\x18\x05 \x03(\v2\x1c.family.v1.SyncEntityMappingR\bmappings\x12;\n" +
+	"\vserver_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"serverTime\"s\n" +
+	"\vSyncSummary\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\x05R\aapplied\x12\x1c\n" +
+	"\tduplicate\x18\x03 \x01(\x05R\tduplicate\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\"\x9e\x02\n" +
+	"\x13SyncOperationResult\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1e\n" +
+	"\blocal_id\x18\x04 \x01(\tH\x00R\alocalId\x88\x01\x01\x12\x1b\n" +
+	"\x06entity\x18\x05 \x01(\tH\x01R\x06entity\x88\x01\x01\x12 \n" +
+	"\tserver_id\x18\x06 \x01(\tH\x02R\bserverId\x88\x01\x01\x123\n" +
+	"\x05error\x18\a \x01(\v2\x1d.family.v1.SyncOperationErrorR\x05errorB\v\n" +
+	"\t_local_idB\t\n" +
+	"\a_entityB\f\n" +
+	"\n" +
+	"_server_id\"`\n" +
+	"\x12SyncOperationError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\tretryable\x18\x03 \x01(\bR\tretryable\"c\n" +
+	"\x11SyncEntityMapping\x12\x16\n" +
+	"\x06entity\x18\x01 \x01(\tR\x06entity\x12\x19\n" +
+	"\blocal_id\x18\x02 \x01(\tR\alocalId\x12\x1b\n" +
+	"\tserver_id\x18\x03 \x01(\tR\bserverId2\xa2\x01\n" +
+	"\vSyncService\x12F\n" +
+	"\tSyncBatch\x12\x1b.family.v1.SyncBatchRequest\x1a\x1c.family.v1.SyncBatchResponse\x12K\n" +
+	"\n" +
+	"SyncStream\x12\x1b.family.v1.SyncBatchRequest\x1a\x1c.family.v1.SyncBatchResponse(\x010\x01B?Z=family-app-go/internal/transport/grpcserver/familyv1;familyv1b\x06proto3"
+
+var (
+	file_family_v1_sync_proto_rawDescOnce sync.Once
+	file_family_v1_sync_proto_rawDescData []byte
+)
+
+func file_family_v1_sync_proto_rawDescGZIP() []byte {
+	file_family_v1_sync_proto_rawDescOnce.Do(func() {
+		file_family_v1_sync_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_family_v1_sync_proto_rawDesc), len(file_family_v1_sync_proto_rawDesc)))
+	})
+	return file_family_v1_sync_proto_rawDescData
+}
+
+var file_family_v1_sync_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_family_v1_sync_proto_goTypes = []any{
+	(*SyncBatchRequest)(nil),      // 0: family.v1.SyncBatchRequest
+	(*SyncOperation)(nil),         // 1: family.v1.SyncOperation
+	(*SyncCreateExpense)(nil),     // 2: family.v1.SyncCreateExpense
+	(*SyncCreateTodo)(nil),        // 3: family.v1.SyncCreateTodo
+	(*SyncSetTodoCompleted)(nil),  // 4: family.v1.SyncSetTodoCompleted
+	(*SyncBatchResponse)(nil),     // 5: family.v1.SyncBatchResponse
+	(*SyncSummary)(nil),           // 6: family.v1.SyncSummary
+	(*SyncOperationResult)(nil),   // 7: family.v1.SyncOperationResult
+	(*SyncOperationError)(nil),    // 8: family.v1.SyncOperationError
+	(*SyncEntityMapping)(nil),     // 9: family.v1.SyncEntityMapping
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_family_v1_sync_proto_depIdxs = []int32{
+	1,  // 0: family.v1.SyncBatchRequest.operations:type_name -> family.v1.SyncOperation
+	2,  // 1: family.v1.SyncOperation.create_expense:type_name -> family.v1.SyncCreateExpense
+	3,  // 2: family.v1.SyncOperation.create_todo:type_name -> family.v1.SyncCreateTodo
+	4,  // 3: family.v1.SyncOperation.set_todo_completed:type_name -> family.v1.SyncSetTodoCompleted
+	6,  // 4: family.v1.SyncBatchResponse.summary:type_name -> family.v1.SyncSummary
+	7,  // 5: family.v1.SyncBatchResponse.results:type_name -> family.v1.SyncOperationResult
+	9,  // 6: family.v1.SyncBatchResponse.mappings:type_name -> family.v1.SyncEntityMapping
+	10, // 7: family.v1.SyncBatchResponse.server_time:type_name -> google.protobuf.Timestamp
+	8,  // 8: family.v1.SyncOperationResult.error:type_name -> family.v1.SyncOperationError
+	0,  // 9: family.v1.SyncService.SyncBatch:input_type -> family.v1.SyncBatchRequest
+	0,  // 10: family.v1.SyncService.SyncStream:input_type -> family.v1.SyncBatchRequest
+	5,  // 11: family.v1.SyncService.SyncBatch:output_type -> family.v1.SyncBatchResponse
+	5,  // 12: family.v1.SyncService.SyncStream:output_type -> family.v1.SyncBatchResponse
+	11, // [11:13] is the sub-list for method output_type
+	9,  // [9:11] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_family_v1_sync_proto_init() }
+func file_family_v1_sync_proto_init() {
+	if File_family_v1_sync_proto != nil {
+		return
+	}
+	file_family_v1_sync_proto_msgTypes[1].OneofWrappers = []any{
+		(*SyncOperation_CreateExpense)(nil),
+		(*SyncOperation_CreateTodo)(nil),
+		(*SyncOperation_SetTodoCompleted)(nil),
+	}
+	file_family_v1_sync_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_family_v1_sync_proto_rawDesc), len(file_family_v1_sync_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_family_v1_sync_proto_goTypes,
+		DependencyIndexes: file_family_v1_sync_proto_depIdxs,
+		MessageInfos:      file_family_v1_sync_proto_msgTypes,
+	}.Build()
+	File_family_v1_sync_proto = out.File
+	file_family_v1_sync_proto_goTypes = nil
+	file_family_v1_sync_proto_depIdxs = nil
+}
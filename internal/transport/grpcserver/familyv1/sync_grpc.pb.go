@@ -0,0 +1,164 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: family/v1/sync.proto
+
+package familyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SyncService_SyncBatch_FullMethodName  = "/family.v1.SyncService/SyncBatch"
+	SyncService_SyncStream_FullMethodName = "/family.v1.SyncService/SyncStream"
+)
+
+// SyncServiceClient is the client API for SyncService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SyncService mirrors POST /api/sync for offline clients.
+type SyncServiceClient interface {
+	SyncBatch(ctx context.Context, in *SyncBatchRequest, opts ...grpc.CallOption) (*SyncBatchResponse, error)
+	// SyncStream applies batches in the order they arrive and answers each one,
+	// so a client can drain its outbox over a single connection. A rejected
+	// batch ends the stream with its error.
+	SyncStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SyncBatchRequest, SyncBatchResponse], error)
+}
+
+type syncServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSyncServiceClient(cc grpc.ClientConnInterface) SyncServiceClient {
+	return &syncServiceClient{cc}
+}
+
+func (c *syncServiceClient) SyncBatch(ctx context.Context, in *SyncBatchRequest, opts ...grpc.CallOption) (*SyncBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncBatchResponse)
+	err := c.cc.Invoke(ctx, SyncService_SyncBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *syncServiceClient) SyncStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SyncBatchRequest, SyncBatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SyncService_ServiceDesc.Streams[0], SyncService_SyncStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SyncBatchRequest, SyncBatchResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SyncService_SyncStreamClient = grpc.BidiStreamingClient[SyncBatchRequest, SyncBatchResponse]
+
+// SyncServiceServer is the server API for SyncService service.
+// All implementations must embed UnimplementedSyncServiceServer
+// for forward compatibility.
+//
+// SyncService mirrors POST /api/sync for offline clients.
+type SyncServiceServer interface {
+	SyncBatch(context.Context, *SyncBatchRequest) (*SyncBatchResponse, error)
+	// SyncStream applies batches in the order they arrive and answers each one,
+	// so a client can drain its outbox over a single connection. A rejected
+	// batch ends the stream with its error.
+	SyncStream(grpc.BidiStreamingServer[SyncBatchRequest, SyncBatchResponse]) error
+	mustEmbedUnimplementedSyncServiceServer()
+}
+
+// UnimplementedSyncServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSyncServiceServer struct{}
+
+func (UnimplementedSyncServiceServer) SyncBatch(context.Context, *SyncBatchRequest) (*SyncBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncBatch not implemented")
+}
+func (UnimplementedSyncServiceServer) SyncStream(grpc.BidiStreamingServer[SyncBatchRequest, SyncBatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SyncStream not implemented")
+}
+func (UnimplementedSyncServiceServer) mustEmbedUnimplementedSyncServiceServer() {}
+func (UnimplementedSyncServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeSyncServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SyncServiceServer will
+// result in compilation errors.
+type UnsafeSyncServiceServer interface {
+	mustEmbedUnimplementedSyncServiceServer()
+}
+
+func RegisterSyncServiceServer(s grpc.ServiceRegistrar, srv SyncServiceServer) {
+	// If the following call pancis, it indicates UnimplementedSyncServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SyncService_ServiceDesc, srv)
+}
+
+func _SyncService_SyncBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncServiceServer).SyncBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SyncService_SyncBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncServiceServer).SyncBatch(ctx, req.(*SyncBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SyncService_SyncStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SyncServiceServer).SyncStream(&grpc.GenericServerStream[SyncBatchRequest, SyncBatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SyncService_SyncStreamServer = grpc.BidiStreamingServer[SyncBatchRequest, SyncBatchResponse]
+
+// SyncService_ServiceDesc is the grpc.ServiceDesc for SyncService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SyncService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "family.v1.SyncService",
+	HandlerType: (*SyncServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SyncBatch",
+			Handler:    _SyncService_SyncBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SyncStream",
+			Handler:       _SyncService_SyncStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "family/v1/sync.proto",
+}
@@ -0,0 +1,1208 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: family/v1/todos.proto
+
+package familyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ArchivedFilter int32
+
+const (
+	// Treated as ARCHIVED_FILTER_EXCLUDE.
+	ArchivedFilter_ARCHIVED_FILTER_UNSPECIFIED ArchivedFilter = 0
+	ArchivedFilter_ARCHIVED_FILTER_EXCLUDE     ArchivedFilter = 1
+	ArchivedFilter_ARCHIVED_FILTER_ONLY        ArchivedFilter = 2
+	ArchivedFilter_ARCHIVED_FILTER_ALL         ArchivedFilter = 3
+)
+
+// Enum value maps for ArchivedFilter.
+var (
+	ArchivedFilter_name = map[int32]string{
+		0: "ARCHIVED_FILTER_UNSPECIFIED",
+		1: "ARCHIVED_FILTER_EXCLUDE",
+		2: "ARCHIVED_FILTER_ONLY",
+		3: "ARCHIVED_FILTER_ALL",
+	}
+	ArchivedFilter_value = map[string]int32{
+		"ARCHIVED_FILTER_UNSPECIFIED": 0,
+		"ARCHIVED_FILTER_EXCLUDE":     1,
+		"ARCHIVED_FILTER_ONLY":        2,
+		"ARCHIVED_FILTER_ALL":         3,
+	}
+)
+
+func (x ArchivedFilter) Enum() *ArchivedFilter {
+	p := new(ArchivedFilter)
+	*p = x
+	return p
+}
+
+func (x ArchivedFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ArchivedFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_family_v1_todos_proto_enumTypes[0].Descriptor()
+}
+
+func (ArchivedFilter) Type() protoreflect.EnumType {
+	return &file_family_v1_todos_proto_enumTypes[0]
+}
+
+func (x ArchivedFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ArchivedFilter.Descriptor instead.
+func (ArchivedFilter) EnumDescriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{0}
+}
+
+type TodoList struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FamilyId         string                 `protobuf:"bytes,2,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`
+	Title            string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	IsCollapsed      bool                   `protobuf:"varint,4,opt,name=is_collapsed,json=isCollapsed,proto3" json:"is_collapsed,omitempty"`
+	Order            int32                  `protobuf:"varint,5,opt,name=order,proto3" json:"order,omitempty"`
+	ArchiveCompleted bool                   `protobuf:"varint,6,opt,name=archive_completed,json=archiveCompleted,proto3" json:"archive_completed,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ItemsTotal       int64                  `protobuf:"varint,8,opt,name=items_total,json=itemsTotal,proto3" json:"items_total,omitempty"`
+	ItemsCompleted   int64                  `protobuf:"varint,9,opt,name=items_completed,json=itemsCompleted,proto3" json:"items_completed,omitempty"`
+	ItemsArchived    int64                  `protobuf:"varint,10,opt,name=items_archived,json=itemsArchived,proto3" json:"items_archived,omitempty"`
+	// Only filled when include_items was requested.
+	Items         []*TodoItem `protobuf:"bytes,11,rep,name=items,proto3" json:"items,omitempty"`
+	Version       int64       `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoList) Reset() {
+	*x = TodoList{}
+	mi := &file_family_v1_todos_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoList) ProtoMessage() {}
+
+func (x *TodoList) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoList.ProtoReflect.Descriptor instead.
+func (*TodoList) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TodoList) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TodoList) GetFamilyId() string {
+	if x != nil {
+		return x.FamilyId
+	}
+	return ""
+}
+
+func (x *TodoList) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TodoList) GetIsCollapsed() bool {
+	if x != nil {
+		return x.IsCollapsed
+	}
+	return false
+}
+
+func (x *TodoList) GetOrder() int32 {
+	if x != nil {
+		return x.Order
+	}
+	return 0
+}
+
+func (x *TodoList) GetArchiveCompleted() bool {
+	if x != nil {
+		return x.ArchiveCompleted
+	}
+	return false
+}
+
+func (x *TodoList) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TodoList) GetItemsTotal() int64 {
+	if x != nil {
+		return x.ItemsTotal
+	}
+	return 0
+}
+
+func (x *TodoList) GetItemsCompleted() int64 {
+	if x != nil {
+		return x.ItemsCompleted
+	}
+	return 0
+}
+
+func (x *TodoList) GetItemsArchived() int64 {
+	if x != nil {
+		return x.ItemsArchived
+	}
+	return 0
+}
+
+func (x *TodoList) GetItems() []*TodoItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *TodoList) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type TodoItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Title         string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	IsCompleted   bool                   `protobuf:"varint,4,opt,name=is_completed,json=isCompleted,proto3" json:"is_completed,omitempty"`
+	IsArchived    bool                   `protobuf:"varint,5,opt,name=is_archived,json=isArchived,proto3" json:"is_archived,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	CompletedBy   *TodoCompletedBy       `protobuf:"bytes,8,opt,name=completed_by,json=completedBy,proto3" json:"completed_by,omitempty"`
+	DueDate       *string                `protobuf:"bytes,9,opt,name=due_date,json=dueDate,proto3,oneof" json:"due_date,omitempty"`
+	AssigneeId    *string                `protobuf:"bytes,10,opt,name=assignee_id,json=assigneeId,proto3,oneof" json:"assignee_id,omitempty"`
+	Version       int64                  `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoItem) Reset() {
+	*x = TodoItem{}
+	mi := &file_family_v1_todos_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoItem) ProtoMessage() {}
+
+func (x *TodoItem) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoItem.ProtoReflect.Descriptor instead.
+func (*TodoItem) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TodoItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TodoItem) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *TodoItem) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TodoItem) GetIsCompleted() bool {
+	if x != nil {
+		return x.IsCompleted
+	}
+	return false
+}
+
+func (x *TodoItem) GetIsArchived() bool {
+	if x != nil {
+		return x.IsArchived
+	}
+	return false
+}
+
+func (x *TodoItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetCompletedBy() *TodoCompletedBy {
+	if x != nil {
+		return x.CompletedBy
+	}
+	return nil
+}
+
+func (x *TodoItem) GetDueDate() string {
+	if x != nil && x.DueDate != nil {
+		return *x.DueDate
+	}
+	return ""
+}
+
+func (x *TodoItem) GetAssigneeId() string {
+	if x != nil && x.AssigneeId != nil {
+		return *x.AssigneeId
+	}
+	return ""
+}
+
+func (x *TodoItem) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type TodoCompletedBy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	AvatarUrl     *string                `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3,oneof" json:"avatar_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoCompletedBy) Reset() {
+	*x = TodoCompletedBy{}
+	mi := &file_family_v1_todos_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoCompletedBy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoCompletedBy) ProtoMessage() {}
+
+func (x *TodoCompletedBy) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoCompletedBy.ProtoReflect.Descriptor instead.
+func (*TodoCompletedBy) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TodoCompletedBy) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TodoCompletedBy) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TodoCompletedBy) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *TodoCompletedBy) GetAvatarUrl() string {
+	if x != nil && x.AvatarUrl != nil {
+		return *x.AvatarUrl
+	}
+	return ""
+}
+
+type ListTodoListsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Defaults to 50.
+	Limit         int32          `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32          `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	IncludeItems  bool           `protobuf:"varint,4,opt,name=include_items,json=includeItems,proto3" json:"include_items,omitempty"`
+	ItemsArchived ArchivedFilter `protobuf:"varint,5,opt,name=items_archived,json=itemsArchived,proto3,enum=family.v1.ArchivedFilter" json:"items_archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoListsRequest) Reset() {
+	*x = ListTodoListsRequest{}
+	mi := &file_family_v1_todos_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoListsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoListsRequest) ProtoMessage() {}
+
+func (x *ListTodoListsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoListsRequest.ProtoReflect.Descriptor instead.
+func (*ListTodoListsRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTodoListsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListTodoListsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTodoListsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListTodoListsRequest) GetIncludeItems() bool {
+	if x != nil {
+		return x.IncludeItems
+	}
+	return false
+}
+
+func (x *ListTodoListsRequest) GetItemsArchived() ArchivedFilter {
+	if x != nil {
+		return x.ItemsArchived
+	}
+	return ArchivedFilter_ARCHIVED_FILTER_UNSPECIFIED
+}
+
+type ListTodoListsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TodoList            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoListsResponse) Reset() {
+	*x = ListTodoListsResponse{}
+	mi := &file_family_v1_todos_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoListsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoListsResponse) ProtoMessage() {}
+
+func (x *ListTodoListsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoListsResponse.ProtoReflect.Descriptor instead.
+func (*ListTodoListsResponse) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTodoListsResponse) GetItems() []*TodoList {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListTodoListsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type CreateTodoListRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Title            string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	ArchiveCompleted bool                   `protobuf:"varint,2,opt,name=archive_completed,json=archiveCompleted,proto3" json:"archive_completed,omitempty"`
+	Order            *int32                 `protobuf:"varint,3,opt,name=order,proto3,oneof" json:"order,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateTodoListRequest) Reset() {
+	*x = CreateTodoListRequest{}
+	mi := &file_family_v1_todos_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTodoListRequest) ProtoMessage() {}
+
+func (x *CreateTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTodoListRequest.ProtoReflect.Descriptor instead.
+func (*CreateTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateTodoListRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTodoListRequest) GetArchiveCompleted() bool {
+	if x != nil {
+		return x.ArchiveCompleted
+	}
+	return false
+}
+
+func (x *CreateTodoListRequest) GetOrder() int32 {
+	if x != nil && x.Order != nil {
+		return *x.Order
+	}
+	return 0
+}
+
+type DeleteTodoListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoListRequest) Reset() {
+	*x = DeleteTodoListRequest{}
+	mi := &file_family_v1_todos_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoListRequest) ProtoMessage() {}
+
+func (x *DeleteTodoListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoListRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoListRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteTodoListRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+type DeleteTodoListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoListResponse) Reset() {
+	*x = DeleteTodoListResponse{}
+	mi := &file_family_v1_todos_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoListResponse) ProtoMessage() {}
+
+func (x *DeleteTodoListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoListResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTodoListResponse) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{7}
+}
+
+type ListTodoItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Archived      ArchivedFilter         `protobuf:"varint,2,opt,name=archived,proto3,enum=family.v1.ArchivedFilter" json:"archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoItemsRequest) Reset() {
+	*x = ListTodoItemsRequest{}
+	mi := &file_family_v1_todos_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoItemsRequest) ProtoMessage() {}
+
+func (x *ListTodoItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoItemsRequest.ProtoReflect.Descriptor instead.
+func (*ListTodoItemsRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTodoItemsRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *ListTodoItemsRequest) GetArchived() ArchivedFilter {
+	if x != nil {
+		return x.Archived
+	}
+	return ArchivedFilter_ARCHIVED_FILTER_UNSPECIFIED
+}
+
+type ListTodoItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TodoItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoItemsResponse) Reset() {
+	*x = ListTodoItemsResponse{}
+	mi := &file_family_v1_todos_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoItemsResponse) ProtoMessage() {}
+
+func (x *ListTodoItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoItemsResponse.ProtoReflect.Descriptor instead.
+func (*ListTodoItemsResponse) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListTodoItemsResponse) GetItems() []*TodoItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListTodoItemsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type CreateTodoItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	DueDate       *string                `protobuf:"bytes,3,opt,name=due_date,json=dueDate,proto3,oneof" json:"due_date,omitempty"`
+	AssigneeId    *string                `protobuf:"bytes,4,opt,name=assignee_id,json=assigneeId,proto3,oneof" json:"assignee_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTodoItemRequest) Reset() {
+	*x = CreateTodoItemRequest{}
+	mi := &file_family_v1_todos_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTodoItemRequest) ProtoMessage() {}
+
+func (x *CreateTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*CreateTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateTodoItemRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *CreateTodoItemRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTodoItemRequest) GetDueDate() string {
+	if x != nil && x.DueDate != nil {
+		return *x.DueDate
+	}
+	return ""
+}
+
+func (x *CreateTodoItemRequest) GetAssigneeId() string {
+	if x != nil && x.AssigneeId != nil {
+		return *x.AssigneeId
+	}
+	return ""
+}
+
+// Unset fields are left unchanged. An empty due_date or assignee_id clears it.
+type UpdateTodoItemRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ItemId      string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Title       *string                `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	IsCompleted *bool                  `protobuf:"varint,3,opt,name=is_completed,json=isCompleted,proto3,oneof" json:"is_completed,omitempty"`
+	DueDate     *string                `protobuf:"bytes,4,opt,name=due_date,json=dueDate,proto3,oneof" json:"due_date,omitempty"`
+	AssigneeId  *string                `protobuf:"bytes,5,opt,name=assignee_id,json=assigneeId,proto3,oneof" json:"assignee_id,omitempty"`
+	// Fails with ABORTED when the stored version differs.
+	ExpectedVersion *int64 `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateTodoItemRequest) Reset() {
+	*x = UpdateTodoItemRequest{}
+	mi := &file_family_v1_todos_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTodoItemRequest) ProtoMessage() {}
+
+func (x *UpdateTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateTodoItemRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetIsCompleted() bool {
+	if x != nil && x.IsCompleted != nil {
+		return *x.IsCompleted
+	}
+	return false
+}
+
+func (x *UpdateTodoItemRequest) GetDueDate() string {
+	if x != nil && x.DueDate != nil {
+		return *x.DueDate
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetAssigneeId() string {
+	if x != nil && x.AssigneeId != nil {
+		return *x.AssigneeId
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteTodoItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoItemRequest) Reset() {
+	*x = DeleteTodoItemRequest{}
+	mi := &file_family_v1_todos_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoItemRequest) ProtoMessage() {}
+
+func (x *DeleteTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteTodoItemRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+type DeleteTodoItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoItemResponse) Reset() {
+	*x = DeleteTodoItemResponse{}
+	mi := &file_family_v1_todos_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoItemResponse) ProtoMessage() {}
+
+func (x *DeleteTodoItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_family_v1_todos_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoItemResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTodoItemResponse) Descriptor() ([]byte, []int) {
+	return file_family_v1_todos_proto_rawDescGZIP(), []int{13}
+}
+
+var File_family_v1_todos_proto protoreflect.FileDescriptor
+
+const file_family_v1_todos_proto_rawDesc = "" +
+	"\n" +
+	"\x15family/v1/todos.proto\x12\tfamily.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa4\x03\n" +
+	"\bTodoList\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tfamily_id\x18\x02 \x01(\tR\bfamilyId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12!\n" +
+	"\fis_collapsed\x18\x04 \x01(\bR\visCollapsed\x12\x14\n" +
+	"\x05order\x18\x05 \x01(\x05R\x05order\x12+\n" +
+	"\x11archive_completed\x18\x06 \x01(\bR\x10archiveCompleted\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1f\n" +
+	"\vitems_total\x18\b \x01(\x03R\n" +
+	"itemsTotal\x12'\n" +
+	"\x0fitems_completed\x18\t \x01(\x03R\x0eitemsCompleted\x12%\n" +
+	"\x0eitems_archived\x18\n" +
+	" \x01(\x03R\ritemsArchived\x12)\n" +
+	"\x05items\x18\v \x03(\v2\x13.family.v1.TodoItemR\x05items\x12\x18\n" +
+	"\aversion\x18\f \x01(\x03R\aversion\"\xc3\x03\n" +
+	"\bTodoItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\alist_id\x18\x02 \x01(\tR\x06listId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12!\n" +
+	"\fis_completed\x18\x04 \x01(\bR\visCompleted\x12\x1f\n" +
+	"\vis_archived\x18\x05 \x01(\bR\n" +
+	"isArchived\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fcompleted_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12=\n" +
+	"\fcompleted_by\x18\b \x01(\v2\x1a.family.v1.TodoCompletedByR\vcompletedBy\x12\x1e\n" +
+	"\bdue_date\x18\t \x01(\tH\x00R\adueDate\x88\x01\x01\x12$\n" +
+	"\vassignee_id\x18\n" +
+	" \x01(\tH\x01R\n" +
+	"assigneeId\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\v \x01(\x03R\aversionB\v\n" +
+	"\t_due_dateB\x0e\n" +
+	"\f_assignee_id\"~\n" +
+	"\x0fTodoCompletedBy\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\"\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tH\x00R\tavatarUrl\x88\x01\x01B\r\n" +
+	"\v_avatar_url\"\xc1\x01\n" +
+	"\x14ListTodoListsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12#\n" +
+	"\rinclude_items\x18\x04 \x01(\bR\fincludeItems\x12@\n" +
+	"\x0eitems_archived\x18\x05 \x01(\x0e2\x19.family.v1.ArchivedFilterR\ritemsArchived\"X\n" +
+	"\x15ListTodoListsResponse\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.family.v1.TodoListR\x05items\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\x7f\n" +
+	"\x15CreateTodoListRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12+\n" +
+	"\x11archive_completed\x18\x02 \x01(\bR\x10archiveCompleted\x12\x19\n" +
+	"\x05order\x18\x03 \x01(\x05H\x00R\x05order\x88\x01\x01B\b\n" +
+	"\x06_order\"0\n" +
+	"\x15DeleteTodoListRequest\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\"\x18\n" +
+	"\x16DeleteTodoListResponse\"f\n" +
+	"\x14ListTodoItemsRequest\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\x125\n" +
+	"\barchived\x18\x02 \x01(\x0e2\x19.family.v1.ArchivedFilterR\barchived\"X\n" +
+	"\x15ListTodoItemsResponse\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.family.v1.TodoItemR\x05items\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\xa9\x01\n" +
+	"\x15CreateTodoItemRequest\x12\x17\n" +
+	"\alist_id\x18\x01 \x01(\tR\x06listId\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1e\n" +
+	"\bdue_date\x18\x03 \x01(\tH\x00R\adueDate\x88\x01\x01\x12$\n" +
+	"\vassignee_id\x18\x04 \x01(\tH\x01R\n" +
+	"assigneeId\x88\x01\x01B\v\n" +
+	"\t_due_dateB\x0e\n" +
+	"\f_assignee_id\"\xb6\x02\n" +
+	"\x15UpdateTodoItemRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\x12\x19\n" +
+	"\x05title\x18\x02 \x01(\tH\x00R\x05title\x88\x01\x01\x12&\n" +
+	"\fis_completed\x18\x03 \x01(\bH\x01R\visCompleted\x88\x01\x01\x12\x1e\n" +
+	"\bdue_date\x18\x04 \x01(\tH\x02R\adueDate\x88\x01\x01\x12$\n" +
+	"\vassignee_id\x18\x05 \x01(\tH\x03R\n" +
+	"assigneeId\x88\x01\x01\x12.\n" +
+	"\x10expected_version\x18\x06 \x01(\x03H\x04R\x0fexpectedVersion\x88\x01\x01B\b\n" +
+	"\x06_titleB\x0f\n" +
+	"\r_is_completedB\v\n" +
+	"\t_due_dateB\x0e\n" +
+	"\f_assignee_idB\x13\n" +
+	"\x11_expected_version\"0\n" +
+	"\x15DeleteTodoItemRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\"\x18\n" +
+	"\x16DeleteTodoItemResponse*\x81\x01\n" +
+	"\x0eArchivedFilter\x12\x1f\n" +
+	"\x1bARCHIVED_FILTER_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17ARCHIVED_FILTER_EXCLUDE\x10\x01\x12\x18\n" +
+	"\x14ARCHIVED_FILTER_ONLY\x10\x02\x12\x17\n" +
+	"\x13ARCHIVED_FILTER_ALL\x10\x032\xbf\x04\n" +
+	"\fTodosService\x12R\n" +
+	"\rListTodoLists\x12\x1f.family.v1.ListTodoListsRequest\x1a .family.v1.ListTodoListsResponse\x12G\n" +
+	"\x0eCreateTodoList\x12 .family.v1.CreateTodoListRequest\x1a\x13.family.v1.TodoList\x12U\n" +
+	"\x0eDeleteTodoList\x12 .family.v1.DeleteTodoListRequest\x1a!.family.v1.DeleteTodoListResponse\x12R\n" +
+	"\rListTodoItems\x12\x1f.family.v1.ListTodoItemsRequest\x1a .family.v1.ListTodoItemsResponse\x12G\n" +
+	"\x0eCreateTodoItem\x12 .family.v1.CreateTodoItemRequest\x1a\x13.family.v1.TodoItem\x12G\n" +
+	"\x0eUpdateTodoItem\x12 .family.v1.UpdateTodoItemRequest\x1a\x13.family.v1.TodoItem\x12U\n" +
+	"\x0eDeleteTodoItem\x12 .family.v1.DeleteTodoItemRequest\x1a!.family.v1.DeleteTodoItemResponseB?Z=family-app-go/internal/transport/grpcserver/familyv1;familyv1b\x06proto3"
+
+var (
+	file_family_v1_todos_proto_rawDescOnce sync.Once
+	file_family_v1_todos_proto_rawDescData []byte
+)
+
+func file_family_v1_todos_proto_rawDescGZIP() []byte {
+	file_family_v1_todos_proto_rawDescOnce.Do(func() {
+		file_family_v1_todos_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_family_v1_todos_proto_rawDesc), len(file_family_v1_todos_proto_rawDesc)))
+	})
+	return file_family_v1_todos_proto_rawDescData
+}
+
+var file_family_v1_todos_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_family_v1_todos_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_family_v1_todos_proto_goTypes = []any{
+	(ArchivedFilter)(0),            // 0: family.v1.ArchivedFilter
+	(*TodoList)(nil),               // 1: family.v1.TodoList
+	(*TodoItem)(nil),               // 2: family.v1.TodoItem
+	(*TodoCompletedBy)(nil),        // 3: family.v1.TodoCompletedBy
+	(*ListTodoListsRequest)(nil),   // 4: family.v1.ListTodoListsRequest
+	(*ListTodoListsResponse)(nil),  // 5: family.v1.ListTodoListsResponse
+	(*CreateTodoListRequest)(nil),  // 6: family.v1.CreateTodoListRequest
+	(*DeleteTodoListRequest)(nil),  // 7: family.v1.DeleteTodoListRequest
+	(*DeleteTodoListResponse)(nil), // 8: family.v1.DeleteTodoListResponse
+	(*ListTodoItemsRequest)(nil),   // 9: family.v1.ListTodoItemsRequest
+	(*ListTodoItemsResponse)(nil),  // 10: family.v1.ListTodoItemsResponse
+	(*CreateTodoItemRequest)(nil),  // 11: family.v1.CreateTodoItemRequest
+	(*UpdateTodoItemRequest)(nil),  // 12: family.v1.UpdateTodoItemRequest
+	(*DeleteTodoItemRequest)(nil),  // 13: family.v1.DeleteTodoItemRequest
+	(*DeleteTodoItemResponse)(nil), // 14: family.v1.DeleteTodoItemResponse
+	(*timestamppb.Timestamp)(nil),  // 15: google.protobuf.Timestamp
+}
+var file_family_v1_todos_proto_depIdxs = []int32{
+	15, // 0: family.v1.TodoList.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 1: family.v1.TodoList.items:type_name -> family.v1.TodoItem
+	15, // 2: family.v1.TodoItem.created_at:type_name -> google.protobuf.Timestamp
+	15, // 3: family.v1.TodoItem.completed_at:type_name -> google.protobuf.Timestamp
+	3,  // 4: family.v1.TodoItem.completed_by:type_name -> family.v1.TodoCompletedBy
+	0,  // 5: family.v1.ListTodoListsRequest.items_archived:type_name -> family.v1.ArchivedFilter
+	1,  // 6: family.v1.ListTodoListsResponse.items:type_name -> family.v1.TodoList
+	0,  // 7: family.v1.ListTodoItemsRequest.archived:type_name -> family.v1.ArchivedFilter
+	2,  // 8: family.v1.ListTodoItemsResponse.items:type_name -> family.v1.TodoItem
+	4,  // 9: family.v1.TodosService.ListTodoLists:input_type -> family.v1.ListTodoListsRequest
+	6,  // 10: family.v1.TodosService.CreateTodoList:input_type -> family.v1.CreateTodoListRequest
+	7,  // 11: family.v1.TodosService.DeleteTodoList:input_type -> family.v1.DeleteTodoListRequest
+	9,  // 12: family.v1.TodosService.ListTodoItems:input_type -> family.v1.ListTodoItemsRequest
+	11, // 13: family.v1.TodosService.CreateTodoItem:input_type -> family.v1.CreateTodoItemRequest
+	12, // 14: family.v1.TodosService.UpdateTodoItem:input_type -> family.v1.UpdateTodoItemRequest
+	13, // 15: family.v1.TodosService.DeleteTodoItem:input_type -> family.v1.DeleteTodoItemRequest
+	5,  // 16: family.v1.TodosService.ListTodoLists:output_type -> family.v1.ListTodoListsResponse
+	1,  // 17: family.v1.TodosService.CreateTodoList:output_type -> family.v1.TodoList
+	8,  // 18: family.v1.TodosService.DeleteTodoList:output_type -> family.v1.DeleteTodoListResponse
+	10, // 19: family.v1.TodosService.ListTodoItems:output_type -> family.v1.ListTodoItemsResponse
+	2,  // 20: family.v1.TodosService.CreateTodoItem:output_type -> family.v1.TodoItem
+	2,  // 21: family.v1.TodosService.UpdateTodoItem:output_type -> family.v1.TodoItem
+	14, // 22: family.v1.TodosService.DeleteTodoItem:output_type -> family.v1.DeleteTodoItemResponse
+	16, // [16:23] is the sub-list for method output_type
+	9,  // [9:16] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_family_v1_todos_proto_init() }
+func file_family_v1_todos_proto_init() {
+	if File_family_v1_todos_proto != nil {
+		return
+	}
+	file_family_v1_todos_proto_msgTypes[1].OneofWrappers = []any{}
+	file_family_v1_todos_proto_msgTypes[2].OneofWrappers = []any{}
+	file_family_v1_todos_proto_msgTypes[5].OneofWrappers = []any{}
+	file_family_v1_todos_proto_msgTypes[10].OneofWrappers = []any{}
+	file_family_v1_todos_proto_msgTypes[11].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_family_v1_todos_proto_rawDesc), len(file_family_v1_todos_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_family_v1_todos_proto_goTypes,
+		DependencyIndexes: file_family_v1_todos_proto_depIdxs,
+		EnumInfos:         file_family_v1_todos_proto_enumTypes,
+		MessageInfos:      file_family_v1_todos_proto_msgTypes,
+	}.Build()
+	File_family_v1_todos_proto = out.File
+	file_family_v1_todos_proto_goTypes = nil
+	file_family_v1_todos_proto_depIdxs = nil
+}
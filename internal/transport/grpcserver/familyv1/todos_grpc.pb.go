@@ -0,0 +1,355 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: family/v1/todos.proto
+
+package familyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TodosService_ListTodoLists_FullMethodName  = "/family.v1.TodosService/ListTodoLists"
+	TodosService_CreateTodoList_FullMethodName = "/family.v1.TodosService/CreateTodoList"
+	TodosService_DeleteTodoList_FullMethodName = "/family.v1.TodosService/DeleteTodoList"
+	TodosService_ListTodoItems_FullMethodName  = "/family.v1.TodosService/ListTodoItems"
+	TodosService_CreateTodoItem_FullMethodName = "/family.v1.TodosService/CreateTodoItem"
+	TodosService_UpdateTodoItem_FullMethodName = "/family.v1.TodosService/UpdateTodoItem"
+	TodosService_DeleteTodoItem_FullMethodName = "/family.v1.TodosService/DeleteTodoItem"
+)
+
+// TodosServiceClient is the client API for TodosService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TodosService mirrors /api/todo-lists and /api/todo-items. Child members
+// only see and update items assigned to them.
+type TodosServiceClient interface {
+	ListTodoLists(ctx context.Context, in *ListTodoListsRequest, opts ...grpc.CallOption) (*ListTodoListsResponse, error)
+	CreateTodoList(ctx context.Context, in *CreateTodoListRequest, opts ...grpc.CallOption) (*TodoList, error)
+	DeleteTodoList(ctx context.Context, in *DeleteTodoListRequest, opts ...grpc.CallOption) (*DeleteTodoListResponse, error)
+	ListTodoItems(ctx context.Context, in *ListTodoItemsRequest, opts ...grpc.CallOption) (*ListTodoItemsResponse, error)
+	CreateTodoItem(ctx context.Context, in *CreateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	UpdateTodoItem(ctx context.Context, in *UpdateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	DeleteTodoItem(ctx context.Context, in *DeleteTodoItemRequest, opts ...grpc.CallOption) (*DeleteTodoItemResponse, error)
+}
+
+type todosServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTodosServiceClient(cc grpc.ClientConnInterface) TodosServiceClient {
+	return &todosServiceClient{cc}
+}
+
+func (c *todosServiceClient) ListTodoLists(ctx context.Context, in *ListTodoListsRequest, opts ...grpc.CallOption) (*ListTodoListsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTodoListsResponse)
+	err := c.cc.Invoke(ctx, TodosService_ListTodoLists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todosServiceClient) CreateTodoList(ctx context.Context, in *CreateTodoListRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, TodosService_CreateTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todosServiceClient) DeleteTodoList(ctx context.Context, in *DeleteTodoListRequest, opts ...grpc.CallOption) (*DeleteTodoListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTodoListResponse)
+	err := c.cc.Invoke(ctx, TodosService_DeleteTodoList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todosServiceClient) ListTodoItems(ctx context.Context, in *ListTodoItemsRequest, opts ...grpc.CallOption) (*ListTodoItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTodoItemsResponse)
+	err := c.cc.Invoke(ctx, TodosService_ListTodoItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todosServiceClient) CreateTodoItem(ctx context.Context, in *CreateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodosService_CreateTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todosServiceClient) UpdateTodoItem(ctx context.Context, in *UpdateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodosService_UpdateTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todosServiceClient) DeleteTodoItem(ctx context.Context, in *DeleteTodoItemRequest, opts ...grpc.CallOption) (*DeleteTodoItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTodoItemResponse)
+	err := c.cc.Invoke(ctx, TodosService_DeleteTodoItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TodosServiceServer is the server API for TodosService service.
+// All implementations must embed UnimplementedTodosServiceServer
+// for forward compatibility.
+//
+// TodosService mirrors /api/todo-lists and /api/todo-items. Child members
+// only see and update items assigned to them.
+type TodosServiceServer interface {
+	ListTodoLists(context.Context, *ListTodoListsRequest) (*ListTodoListsResponse, error)
+	CreateTodoList(context.Context, *CreateTodoListRequest) (*TodoList, error)
+	DeleteTodoList(context.Context, *DeleteTodoListRequest) (*DeleteTodoListResponse, error)
+	ListTodoItems(context.Context, *ListTodoItemsRequest) (*ListTodoItemsResponse, error)
+	CreateTodoItem(context.Context, *CreateTodoItemRequest) (*TodoItem, error)
+	UpdateTodoItem(context.Context, *UpdateTodoItemRequest) (*TodoItem, error)
+	DeleteTodoItem(context.Context, *DeleteTodoItemRequest) (*DeleteTodoItemResponse, error)
+	mustEmbedUnimplementedTodosServiceServer()
+}
+
+// UnimplementedTodosServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTodosServiceServer struct{}
+
+func (UnimplementedTodosServiceServer) ListTodoLists(context.Context, *ListTodoListsRequest) (*ListTodoListsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoLists not implemented")
+}
+func (UnimplementedTodosServiceServer) CreateTodoList(context.Context, *CreateTodoListRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTodoList not implemented")
+}
+func (UnimplementedTodosServiceServer) DeleteTodoList(context.Context, *DeleteTodoListRequest) (*DeleteTodoListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodoList not implemented")
+}
+func (UnimplementedTodosServiceServer) ListTodoItems(context.Context, *ListTodoItemsRequest) (*ListTodoItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoItems not implemented")
+}
+func (UnimplementedTodosServiceServer) CreateTodoItem(context.Context, *CreateTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTodoItem not implemented")
+}
+func (UnimplementedTodosServiceServer) UpdateTodoItem(context.Context, *UpdateTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodoItem not implemented")
+}
+func (UnimplementedTodosServiceServer) DeleteTodoItem(context.Context, *DeleteTodoItemRequest) (*DeleteTodoItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodoItem not implemented")
+}
+func (UnimplementedTodosServiceServer) mustEmbedUnimplementedTodosServiceServer() {}
+func (UnimplementedTodosServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeTodosServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TodosServiceServer will
+// result in compilation errors.
+type UnsafeTodosServiceServer interface {
+	mustEmbedUnimplementedTodosServiceServer()
+}
+
+func RegisterTodosServiceServer(s grpc.ServiceRegistrar, srv TodosServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTodosServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TodosService_ServiceDesc, srv)
+}
+
+func _TodosService_ListTodoLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoListsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodosServiceServer).ListTodoLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodosService_ListTodoLists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodosServiceServer).ListTodoLists(ctx, req.(*ListTodoListsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodosService_CreateTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodosServiceServer).CreateTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodosService_CreateTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodosServiceServer).CreateTodoList(ctx, req.(*CreateTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodosService_DeleteTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodosServiceServer).DeleteTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodosService_DeleteTodoList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodosServiceServer).DeleteTodoList(ctx, req.(*DeleteTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodosService_ListTodoItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodosServiceServer).ListTodoItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodosService_ListTodoItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodosServiceServer).ListTodoItems(ctx, req.(*ListTodoItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodosService_CreateTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodosServiceServer).CreateTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodosService_CreateTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodosServiceServer).CreateTodoItem(ctx, req.(*CreateTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodosService_UpdateTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodosServiceServer).UpdateTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodosService_UpdateTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodosServiceServer).UpdateTodoItem(ctx, req.(*UpdateTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodosService_DeleteTodoItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodosServiceServer).DeleteTodoItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodosService_DeleteTodoItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodosServiceServer).DeleteTodoItem(ctx, req.(*DeleteTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TodosService_ServiceDesc is the grpc.ServiceDesc for TodosService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TodosService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "family.v1.TodosService",
+	HandlerType: (*TodosServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListTodoLists",
+			Handler:    _TodosService_ListTodoLists_Handler,
+		},
+		{
+			MethodName: "CreateTodoList",
+			Handler:    _TodosService_CreateTodoList_Handler,
+		},
+		{
+			MethodName: "DeleteTodoList",
+			Handler:    _TodosService_DeleteTodoList_Handler,
+		},
+		{
+			MethodName: "ListTodoItems",
+			Handler:    _TodosService_ListTodoItems_Handler,
+		},
+		{
+			MethodName: "CreateTodoItem",
+			Handler:    _TodosService_CreateTodoItem_Handler,
+		},
+		{
+			MethodName: "UpdateTodoItem",
+			Handler:    _TodosService_UpdateTodoItem_Handler,
+		},
+		{
+			MethodName: "DeleteTodoItem",
+			Handler:    _TodosService_DeleteTodoItem_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "family/v1/todos.proto",
+}
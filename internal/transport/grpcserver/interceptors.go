@@ -0,0 +1,261 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	"family-app-go/internal/transport/grpcserver/familyv1"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"family-app-go/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDMetadataKey = "x-request-id"
+
+// methodPolicy says who may call an RPC beyond being authenticated. The zero
+// value is the strictest: no read-only API keys and no child members.
+type methodPolicy struct {
+	readOnly   bool
+	allowChild bool
+}
+
+// Child members get the same todo access as over HTTP: their assigned items
+// only, and nothing touching family finances.
+var methodPolicies = map[string]methodPolicy{
+	familyv1.ExpensesService_ListExpenses_FullMethodName:   {readOnly: true},
+	familyv1.ExpensesService_StreamExpenses_FullMethodName: {readOnly: true},
+	familyv1.ExpensesService_ListCategories_FullMethodName: {readOnly: true},
+	familyv1.TodosService_ListTodoLists_FullMethodName:     {readOnly: true, allowChild: true},
+	familyv1.TodosService_ListTodoItems_FullMethodName:     {readOnly: true, allowChild: true},
+	familyv1.TodosService_UpdateTodoItem_FullMethodName:    {allowChild: true},
+}
+
+// methodMaintenance names the maintenance flags of the domains each RPC
+// writes, like Guard does for HTTP routes. Sync batches are also checked
+// per operation by the sync service.
+var methodMaintenance = map[string][]string{
+	familyv1.ExpensesService_CreateExpense_FullMethodName: {featureflagsdomain.MaintenanceExpenses},
+	familyv1.ExpensesService_UpdateExpense_FullMethodName: {featureflagsdomain.MaintenanceExpenses},
+	familyv1.ExpensesService_DeleteExpense_FullMethodName: {featureflagsdomain.MaintenanceExpenses},
+	familyv1.TodosService_CreateTodoList_FullMethodName:   {featureflagsdomain.MaintenanceTodos},
+	familyv1.TodosService_DeleteTodoList_FullMethodName:   {featureflagsdomain.MaintenanceTodos},
+	familyv1.TodosService_CreateTodoItem_FullMethodName:   {featureflagsdomain.MaintenanceTodos},
+	familyv1.TodosService_UpdateTodoItem_FullMethodName:   {featureflagsdomain.MaintenanceTodos},
+	familyv1.TodosService_DeleteTodoItem_FullMethodName:   {featureflagsdomain.MaintenanceTodos},
+	familyv1.SyncService_SyncBatch_FullMethodName:         {featureflagsdomain.MaintenanceSync},
+	familyv1.SyncService_SyncStream_FullMethodName:        {featureflagsdomain.MaintenanceSync},
+}
+
+type interceptors struct {
+	auth        *authmw.Auth
+	access      *authmw.FamilyAccess
+	maintenance *authmw.Maintenance
+	log         logger.Logger
+}
+
+func (i *interceptors) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	ctx, finish := i.observe(ctx, info.FullMethod, grpc.SetHeader)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			authmw.RecordPanic("grpc")
+			logger.FromContext(ctx, i.log).Critical("grpc: handler panicked", "method", info.FullMethod, "panic", recovered, "stack", string(debug.Stack()))
+			err = internalError()
+		}
+		finish(err)
+	}()
+
+	ctx, err = i.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.checkMaintenance(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (i *interceptors) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, finish := i.observe(ss.Context(), info.FullMethod, func(_ context.Context, md metadata.MD) error {
+		return ss.SetHeader(md)
+	})
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			authmw.RecordPanic("grpc")
+			logger.FromContext(ctx, i.log).Critical("grpc: handler panicked", "method", info.FullMethod, "panic", recovered, "stack", string(debug.Stack()))
+			err = internalError()
+		}
+		finish(err)
+	}()
+
+	ctx, err = i.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if err := i.checkMaintenance(ctx, info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// observe does for RPCs what RequestID and NewTracing do for HTTP requests:
+// it assigns the request ID, echoes it in the response header, opens a
+// server span and stores a request-scoped logger. finish logs the outcome.
+func (i *interceptors) observe(ctx context.Context, method string, setHeader func(context.Context, metadata.MD) error) (context.Context, func(error)) {
+	startedAt := time.Now()
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := authmw.ResolveRequestID(firstMetadata(md, requestIDMetadataKey))
+	ctx = authmw.WithRequestID(ctx, requestID)
+	_ = setHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := tracing.Start(ctx, strings.TrimPrefix(method, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+			attribute.String("request.id", requestID),
+		),
+	)
+
+	requestLog := i.log
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		requestLog = requestLog.With("trace_id", traceID)
+	}
+	ctx = logger.WithContext(logger.WithRequestID(ctx, requestID), requestLog)
+	requestLog = logger.FromContext(ctx, requestLog)
+
+	return ctx, func(err error) {
+		code := status.Code(err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+		if isServerError(code) {
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+		span.End()
+
+		requestLog.Info("grpc: request", "method", method, "code", code.String(), "duration_ms", time.Since(startedAt).Milliseconds())
+	}
+}
+
+// authorize authenticates the call with the same token rules as the HTTP
+// API, read from the authorization or x-api-key metadata, then applies the
+// method policy.
+func (i *interceptors) authorize(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authorization := firstMetadata(md, "authorization")
+	token, ok := bearerToken(authorization)
+	if !ok {
+		token = strings.TrimSpace(firstMetadata(md, "x-api-key"))
+	}
+
+	// Auth providers take an HTTP request for logging; give them one that
+	// names the RPC.
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, method, nil)
+	if err != nil {
+		return nil, internalError()
+	}
+	r.Header.Set("Authorization", authorization)
+
+	ctx, err = i.auth.Authenticate(r, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, authmw.ErrUnauthenticated):
+			return nil, statusError(codes.Unauthenticated, "invalid_token", "invalid token")
+		case errors.Is(err, authmw.ErrAuthNotConfigured):
+			return nil, statusError(codes.Internal, "auth_not_configured", "auth not configured")
+		default:
+			return nil, internalError()
+		}
+	}
+
+	policy := methodPolicies[method]
+	if key, ok := authmw.APIKeyFromContext(ctx); ok && key.ReadOnly() && !policy.readOnly {
+		return nil, statusError(codes.PermissionDenied, "insufficient_scope", "api key is read-only")
+	}
+
+	ctx, err = i.access.WithRole(ctx)
+	if err != nil {
+		return nil, internalError()
+	}
+	if authmw.IsChild(ctx) && !policy.allowChild {
+		return nil, statusError(codes.PermissionDenied, "child_restricted", "not available for child members")
+	}
+	return ctx, nil
+}
+
+// checkMaintenance answers Unavailable while a domain the method writes is
+// in maintenance for the caller's family.
+func (i *interceptors) checkMaintenance(ctx context.Context, method string) error {
+	flags, ok := methodMaintenance[method]
+	if !ok || !i.maintenance.Active(ctx, flags...) {
+		return nil
+	}
+	return underMaintenance(i.maintenance.RetryAfterSeconds())
+}
+
+// contextStream hands the authenticated context to streaming handlers.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier lets the OpenTelemetry propagator read traceparent from
+// incoming metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	return firstMetadata(metadata.MD(c), key)
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func bearerToken(value string) (string, bool) {
+	parts := strings.Fields(value)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded, codes.Unimplemented:
+		return true
+	}
+	return false
+}
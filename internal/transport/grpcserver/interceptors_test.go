@@ -0,0 +1,128 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"family-app-go/internal/config"
+	familydomain "family-app-go/internal/domain/family"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	"family-app-go/internal/transport/grpcserver/familyv1"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"family-app-go/pkg/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRoles struct {
+	role string
+}
+
+func (f fakeRoles) GetMembership(_ context.Context, userID string) (*familydomain.FamilyMember, error) {
+	if f.role == "" {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	return &familydomain.FamilyMember{FamilyID: "family-1", UserID: userID, Role: f.role, Family: familydomain.Family{ID: "family-1"}}, nil
+}
+
+func newTestInterceptors(role string) *interceptors {
+	log := logger.New(io.Discard, slog.LevelError, "text")
+	auth := authmw.NewAuth(config.SupabaseConfig{SkipAuth: true, MockUserID: "user-1"}, nil, nil, nil, nil, nil, nil, log)
+	return &interceptors{auth: auth, access: authmw.NewFamilyAccess(fakeRoles{role: role}, log), log: log}
+}
+
+func TestAuthorizeAppliesChildPolicy(t *testing.T) {
+	i := newTestInterceptors(familydomain.RoleChild)
+
+	_, err := i.authorize(context.Background(), familyv1.ExpensesService_CreateExpense_FullMethodName)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for child creating an expense, got %v", err)
+	}
+
+	ctx, err := i.authorize(context.Background(), familyv1.TodosService_ListTodoItems_FullMethodName)
+	if err != nil {
+		t.Fatalf("expected child to list todo items, got %v", err)
+	}
+	if got := authmw.AssigneeRestriction(ctx); got != "user-1" {
+		t.Fatalf("expected assignee restriction for child, got %q", got)
+	}
+}
+
+func TestAuthorizeAllowsAdults(t *testing.T) {
+	i := newTestInterceptors(familydomain.RoleMember)
+
+	ctx, err := i.authorize(context.Background(), familyv1.SyncService_SyncBatch_FullMethodName)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	if user, ok := authmw.UserFromContext(ctx); !ok || user.ID != "user-1" {
+		t.Fatalf("expected mock user in context, got %+v", user)
+	}
+}
+
+type fakeFlags map[string]bool
+
+func (f fakeFlags) Enabled(_ context.Context, key, familyID string) (bool, error) {
+	return familyID == "family-1" && f[key], nil
+}
+
+func TestCheckMaintenanceStopsWritesToTheDomain(t *testing.T) {
+	i := newTestInterceptors(familydomain.RoleMember)
+	i.maintenance = authmw.NewMaintenance(fakeFlags{featureflagsdomain.MaintenanceExpenses: true}, time.Minute, i.log)
+
+	ctx, err := i.authorize(context.Background(), familyv1.ExpensesService_CreateExpense_FullMethodName)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	err = i.checkMaintenance(ctx, familyv1.ExpensesService_CreateExpense_FullMethodName)
+	st := status.Convert(err)
+	if st.Code() != codes.Unavailable {
+		t.Fatalf("expected Unavailable for a write in maintenance, got %v", err)
+	}
+	var delay time.Duration
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			delay = info.GetRetryDelay().AsDuration()
+		}
+	}
+	if delay != time.Minute {
+		t.Fatalf("expected a one minute retry delay, got %s", delay)
+	}
+
+	for _, method := range []string{familyv1.ExpensesService_ListExpenses_FullMethodName, familyv1.TodosService_CreateTodoItem_FullMethodName} {
+		if err := i.checkMaintenance(ctx, method); err != nil {
+			t.Fatalf("expected %s to pass, got %v", method, err)
+		}
+	}
+}
+
+func TestInvalidArgumentCarriesFieldViolations(t *testing.T) {
+	err := validation.Run(syncBatch{&familyv1.SyncBatchRequest{
+		Operations: []*familyv1.SyncOperation{{OperationId: "not-a-uuid"}},
+	}})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	st := status.Convert(invalidArgument(err))
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %s", st.Code())
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	want := []string{"operations[0].operation_id", "operations[0].payload"}
+	if len(fields) != len(want) || fields[0] != want[0] || fields[1] != want[1] {
+		t.Fatalf("expected violations %v, got %v", want, fields)
+	}
+}
@@ -0,0 +1,98 @@
+// Package grpcserver serves the expenses, todos and sync APIs over gRPC for
+// native clients. It shares authentication, family access rules and the
+// domain services with the HTTP server.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"family-app-go/internal/config"
+	activitydomain "family-app-go/internal/domain/activity"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	syncdomain "family-app-go/internal/domain/sync"
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/internal/transport/grpcserver/familyv1"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+type Services struct {
+	Families *familydomain.Service
+	Expenses *expensesdomain.Service
+	Todos    *todosdomain.Service
+	Sync     *syncdomain.Service
+	Activity *activitydomain.Service
+}
+
+type Server struct {
+	addr      string
+	server    *grpc.Server
+	log       logger.Logger
+	draining  chan struct{}
+	drainOnce sync.Once
+}
+
+func New(cfg config.Config, services Services, auth *authmw.Auth, access *authmw.FamilyAccess, maintenance *authmw.Maintenance, log logger.Logger) *Server {
+	interceptors := &interceptors{auth: auth, access: access, maintenance: maintenance, log: log}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.unary),
+		grpc.ChainStreamInterceptor(interceptors.stream),
+	)
+
+	draining := make(chan struct{})
+	base := service{families: services.Families, log: log}
+	familyv1.RegisterExpensesServiceServer(server, &expensesServer{service: base, expenses: services.Expenses, activity: services.Activity})
+	familyv1.RegisterTodosServiceServer(server, &todosServer{service: base, todos: services.Todos, activity: services.Activity})
+	if cfg.OfflineSyncEnabled {
+		familyv1.RegisterSyncServiceServer(server, &syncServer{service: base, sync: services.Sync, draining: draining})
+	}
+
+	return &Server{
+		addr:     ":" + cfg.GRPC.Port,
+		server:   server,
+		log:      log,
+		draining: draining,
+	}
+}
+
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// ListenAndServe blocks until the server stops. It returns nil after Shutdown.
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if err := s.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown waits for in-flight calls and cuts open streams once ctx is done.
+// Sync streams end with Unavailable after their current batch, so clients
+// reconnect to another instance instead of holding the shutdown.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.drainOnce.Do(func() { close(s.draining) })
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
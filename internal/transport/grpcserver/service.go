@@ -0,0 +1,88 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	familydomain "family-app-go/internal/domain/family"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const dateLayout = "2006-01-02"
+
+// service holds what every RPC needs to find the caller's family.
+type service struct {
+	families *familydomain.Service
+	log      logger.Logger
+}
+
+func (s service) requestLog(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, s.log)
+}
+
+// callerFamily returns the authenticated user and their family, mapping
+// failures to status errors the way the HTTP handlers do.
+func (s service) callerFamily(ctx context.Context, operation string) (authmw.User, *familydomain.Family, error) {
+	user, ok := authmw.UserFromContext(ctx)
+	if !ok {
+		return authmw.User{}, nil, statusError(codes.Unauthenticated, "invalid_token", "invalid token")
+	}
+
+	family, err := s.families.GetFamilyByUser(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, familydomain.ErrFamilyNotFound) {
+			s.requestLog(ctx).BusinessError(operation+": family not found", err, "user_id", user.ID)
+			return user, nil, notFound("family_not_found", "family not found")
+		}
+		s.requestLog(ctx).InternalError(operation+": get family failed", err, "user_id", user.ID)
+		return user, nil, internalError()
+	}
+	return user, family, nil
+}
+
+func actorFromUser(user authmw.User) activitydomain.Actor {
+	return activitydomain.Actor{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
+func parseDate(value string) (time.Time, error) {
+	return time.Parse(dateLayout, strings.TrimSpace(value))
+}
+
+// parseOptionalDate parses a date that already passed validation; empty
+// means none.
+func parseOptionalDate(value string) *time.Time {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	date, err := parseDate(value)
+	if err != nil {
+		return nil
+	}
+	return &date
+}
+
+func formatOptionalDate(value *time.Time) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.Format(dateLayout)
+	return &formatted
+}
+
+func optionalTimestamp(value *time.Time) *timestamppb.Timestamp {
+	if value == nil {
+		return nil
+	}
+	return timestamppb.New(*value)
+}
@@ -0,0 +1,282 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	quotadomain "family-app-go/internal/domain/quota"
+	syncdomain "family-app-go/internal/domain/sync"
+	"family-app-go/internal/transport/grpcserver/familyv1"
+	"family-app-go/internal/transport/httpserver/validation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	minIdempotencyKeyLength = 8
+	maxIdempotencyKeyLength = 128
+)
+
+type syncServer struct {
+	familyv1.UnimplementedSyncServiceServer
+	service
+	sync *syncdomain.Service
+	// draining is closed when the server starts shutting down.
+	draining <-chan struct{}
+}
+
+func (s *syncServer) SyncBatch(ctx context.Context, req *familyv1.SyncBatchRequest) (*familyv1.SyncBatchResponse, error) {
+	return s.processBatch(ctx, "grpc.sync.batch", req)
+}
+
+func (s *syncServer) SyncStream(stream grpc.BidiStreamingServer[familyv1.SyncBatchRequest, familyv1.SyncBatchResponse]) error {
+	ctx := stream.Context()
+	requests := make(chan *familyv1.SyncBatchRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		var req *familyv1.SyncBatchRequest
+		select {
+		case req = <-requests:
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case <-s.draining:
+			// A batch in flight is answered first; idle streams are told to
+			// reconnect so GracefulStop does not wait for them.
+			return statusError(codes.Unavailable, "server_shutting_down", "server is shutting down, reconnect")
+		}
+
+		response, err := s.processBatch(ctx, "grpc.sync.stream", req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *syncServer) processBatch(ctx context.Context, operation string, req *familyv1.SyncBatchRequest) (*familyv1.SyncBatchResponse, error) {
+	startedAt := time.Now()
+
+	if len(req.GetOperations()) > syncdomain.MaxBatchOperations {
+		return nil, statusError(codes.ResourceExhausted, "sync_batch_too_large", "too many operations in one batch")
+	}
+	if err := validation.Run(syncBatch{req}); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]syncdomain.OperationInput, 0, len(req.GetOperations()))
+	for _, op := range req.GetOperations() {
+		operations = append(operations, toSyncOperation(op))
+	}
+
+	idempotencyKey := strings.TrimSpace(req.GetIdempotencyKey())
+	response, err := s.sync.ProcessBatch(ctx, syncdomain.BatchInput{
+		FamilyID:       family.ID,
+		BaseCurrency:   family.DefaultCurrency,
+		User:           syncdomain.UserSnapshot{ID: user.ID, Name: user.Name, Email: user.Email, AvatarURL: user.AvatarURL},
+		IdempotencyKey: idempotencyKey,
+		Operations:     operations,
+
+		AllowedCurrencies: family.Currencies(),
+	})
+	logAttrs := []any{
+		"user_id", user.ID,
+		"family_id", family.ID,
+		"operations", len(operations),
+		"has_idempotency_key", idempotencyKey != "",
+		"duration_ms", time.Since(startedAt).Milliseconds(),
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, syncdomain.ErrBatchTooLarge):
+			s.requestLog(ctx).BusinessError(operation+": batch too large", err, logAttrs...)
+			return nil, statusError(codes.ResourceExhausted, "sync_batch_too_large", "too many operations in one batch")
+		case errors.Is(err, syncdomain.ErrIdempotencyKeyPayloadMismatch):
+			s.requestLog(ctx).BusinessError(operation+": idempotency key payload mismatch", err, logAttrs...)
+			return nil, statusError(codes.AlreadyExists, "idempotency_key_payload_mismatch", "idempotency key was already used with different payload")
+		case errors.Is(err, syncdomain.ErrBatchInProgress):
+			s.requestLog(ctx).BusinessError(operation+": batch in progress", err, logAttrs...)
+			return nil, statusError(codes.Aborted, "batch_in_progress", "sync batch is already in progress")
+		case errors.Is(err, quotadomain.ErrExceeded):
+			s.requestLog(ctx).BusinessError(operation+": quota exceeded", err, logAttrs...)
+			return nil, quotaExceeded(err)
+		default:
+			s.requestLog(ctx).InternalError(operation+": process batch failed", err, logAttrs...)
+			return nil, internalError()
+		}
+	}
+
+	s.requestLog(ctx).Info("sync: completed", append(logAttrs,
+		"sync_id", response.SyncID,
+		"status", response.Status,
+		"applied", response.Summary.Applied,
+		"duplicate", response.Summary.Duplicate,
+		"failed", response.Summary.Failed,
+	)...)
+	return toSyncBatchMessage(response), nil
+}
+
+// syncBatch and syncOperation carry the same rules as the JSON sync API.
+type syncBatch struct {
+	*familyv1.SyncBatchRequest
+}
+
+func (req syncBatch) Validate(v *validation.Validator) {
+	key := strings.TrimSpace(req.GetIdempotencyKey())
+	if key != "" {
+		v.MinLength("idempotency_key", key, minIdempotencyKeyLength)
+		v.MaxLength("idempotency_key", key, maxIdempotencyKeyLength)
+	}
+	if len(req.GetOperations()) == 0 {
+		v.Add("operations", validation.CodeRequired, "operations are required")
+		return
+	}
+	for i, op := range req.GetOperations() {
+		v.Nested(fmt.Sprintf("operations[%d]", i), syncOperation{op})
+	}
+}
+
+type syncOperation struct {
+	*familyv1.SyncOperation
+}
+
+func (op syncOperation) Validate(v *validation.Validator) {
+	v.Required("operation_id", op.GetOperationId())
+	v.UUID("operation_id", op.GetOperationId())
+
+	switch payload := op.GetPayload().(type) {
+	case *familyv1.SyncOperation_CreateExpense:
+		v.Required("local_id", op.GetLocalId())
+		v.Nested("create_expense", syncCreateExpense{payload.CreateExpense})
+	case *familyv1.SyncOperation_CreateTodo:
+		v.Required("local_id", op.GetLocalId())
+		v.Required("create_todo.list_id", payload.CreateTodo.GetListId())
+		v.Required("create_todo.title", payload.CreateTodo.GetTitle())
+	case *familyv1.SyncOperation_SetTodoCompleted:
+		todo := payload.SetTodoCompleted
+		if strings.TrimSpace(todo.GetTodoId()) == "" && strings.TrimSpace(todo.GetTodoLocalId()) == "" {
+			v.Add("set_todo_completed.todo_id", validation.CodeRequired, "todo_id or todo_local_id is required")
+		}
+	default:
+		v.Add("payload", validation.CodeRequired, "payload is required")
+	}
+}
+
+type syncCreateExpense struct {
+	*familyv1.SyncCreateExpense
+}
+
+func (payload syncCreateExpense) Validate(v *validation.Validator) {
+	validateExpense(v, payload.GetDate(), payload.GetAmount(), payload.GetTitle(), payload.GetCurrency())
+}
+
+// toSyncOperation maps an operation that already passed validation.
+func toSyncOperation(op *familyv1.SyncOperation) syncdomain.OperationInput {
+	result := syncdomain.OperationInput{
+		OperationID: strings.TrimSpace(op.GetOperationId()),
+		LocalID:     strings.TrimSpace(op.GetLocalId()),
+	}
+
+	switch payload := op.GetPayload().(type) {
+	case *familyv1.SyncOperation_CreateExpense:
+		expense := payload.CreateExpense
+		date, _ := parseDate(expense.GetDate())
+		result.Type = syncdomain.OperationTypeCreateExpense
+		result.CreateExpense = &syncdomain.CreateExpensePayload{
+			Date:        date,
+			Amount:      expense.GetAmount(),
+			Currency:    expense.GetCurrency(),
+			Title:       expense.GetTitle(),
+			CategoryIDs: expense.GetCategoryIds(),
+		}
+	case *familyv1.SyncOperation_CreateTodo:
+		result.Type = syncdomain.OperationTypeCreateTodo
+		result.CreateTodo = &syncdomain.CreateTodoPayload{
+			ListID: payload.CreateTodo.GetListId(),
+			Title:  payload.CreateTodo.GetTitle(),
+		}
+	case *familyv1.SyncOperation_SetTodoCompleted:
+		result.Type = syncdomain.OperationTypeSetTodoCompleted
+		result.SetTodoCompleted = &syncdomain.SetTodoCompletedPayload{
+			TodoID:      strings.TrimSpace(payload.SetTodoCompleted.GetTodoId()),
+			TodoLocalID: strings.TrimSpace(payload.SetTodoCompleted.GetTodoLocalId()),
+			IsCompleted: payload.SetTodoCompleted.GetIsCompleted(),
+		}
+	}
+	return result
+}
+
+func toSyncBatchMessage(response *syncdomain.BatchResponse) *familyv1.SyncBatchResponse {
+	message := &familyv1.SyncBatchResponse{
+		SyncId: response.SyncID,
+		Status: string(response.Status),
+		Summary: &familyv1.SyncSummary{
+			Total:     int32(response.Summary.Total),
+			Applied:   int32(response.Summary.Applied),
+			Duplicate: int32(response.Summary.Duplicate),
+			Failed:    int32(response.Summary.Failed),
+		},
+		Results:    make([]*familyv1.SyncOperationResult, 0, len(response.Results)),
+		Mappings:   make([]*familyv1.SyncEntityMapping, 0, len(response.Mappings)),
+		ServerTime: timestamppb.New(response.ServerTime),
+	}
+
+	for _, result := range response.Results {
+		item := &familyv1.SyncOperationResult{
+			OperationId: result.OperationID,
+			Type:        string(result.Type),
+			Status:      string(result.Status),
+			LocalId:     result.LocalID,
+			ServerId:    result.ServerID,
+		}
+		if result.Entity != nil {
+			entity := string(*result.Entity)
+			item.Entity = &entity
+		}
+		if result.Error != nil {
+			item.Error = &familyv1.SyncOperationError{
+				Code:      string(result.Error.Code),
+				Message:   result.Error.Message,
+				Retryable: result.Error.Retryable,
+			}
+		}
+		message.Results = append(message.Results, item)
+	}
+	for _, mapping := range response.Mappings {
+		message.Mappings = append(message.Mappings, &familyv1.SyncEntityMapping{
+			Entity:   string(mapping.Entity),
+			LocalId:  mapping.LocalID,
+			ServerId: mapping.ServerID,
+		})
+	}
+	return message
+}
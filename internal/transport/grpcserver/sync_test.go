@@ -0,0 +1,53 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"family-app-go/internal/transport/grpcserver/familyv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// idleSyncStream never receives a batch until its context is done.
+type idleSyncStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s idleSyncStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s idleSyncStream) Recv() (*familyv1.SyncBatchRequest, error) {
+	<-s.ctx.Done()
+	return nil, s.ctx.Err()
+}
+
+func (s idleSyncStream) Send(*familyv1.SyncBatchResponse) error {
+	return nil
+}
+
+func TestSyncStreamEndsWhenDraining(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	draining := make(chan struct{})
+	server := &syncServer{draining: draining}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.SyncStream(idleSyncStream{ctx: ctx})
+	}()
+
+	close(draining)
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("expected Unavailable, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end after draining started")
+	}
+}
@@ -0,0 +1,373 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	quotadomain "family-app-go/internal/domain/quota"
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/internal/transport/grpcserver/familyv1"
+	authmw "family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultTodoListsLimit = 50
+
+type todosServer struct {
+	familyv1.UnimplementedTodosServiceServer
+	service
+	todos    *todosdomain.Service
+	activity *activitydomain.Service
+}
+
+func (s *todosServer) ListTodoLists(ctx context.Context, req *familyv1.ListTodoListsRequest) (*familyv1.ListTodoListsResponse, error) {
+	v := validation.New()
+	limit := int(req.GetLimit())
+	offset := int(req.GetOffset())
+	v.NonNegative("limit", &limit)
+	v.NonNegative("offset", &offset)
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if limit == 0 {
+		limit = defaultTodoListsLimit
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.todos.list_lists")
+	if err != nil {
+		return nil, err
+	}
+
+	lists, total, err := s.todos.ListTodoLists(ctx, family.ID, todosdomain.ListFilter{
+		Query:      strings.TrimSpace(req.GetQuery()),
+		Limit:      limit,
+		Offset:     offset,
+		AssigneeID: authmw.AssigneeRestriction(ctx),
+		ViewerID:   user.ID,
+	}, req.GetIncludeItems(), toArchivedFilter(req.GetItemsArchived()))
+	if err != nil {
+		s.requestLog(ctx).InternalError("grpc.todos.list_lists: list todo lists failed", err, "user_id", user.ID, "family_id", family.ID)
+		return nil, internalError()
+	}
+
+	response := &familyv1.ListTodoListsResponse{
+		Items: make([]*familyv1.TodoList, 0, len(lists)),
+		Total: total,
+	}
+	for _, list := range lists {
+		message := toTodoListMessage(list.List, list.Counts)
+		for _, item := range list.Items {
+			message.Items = append(message.Items, toTodoItemMessage(item))
+		}
+		response.Items = append(response.Items, message)
+	}
+	return response, nil
+}
+
+func (s *todosServer) CreateTodoList(ctx context.Context, req *familyv1.CreateTodoListRequest) (*familyv1.TodoList, error) {
+	v := validation.New()
+	v.Required("title", req.GetTitle())
+	var order *int
+	if req.Order != nil {
+		value := int(req.GetOrder())
+		order = &value
+	}
+	v.NonNegative("order", order)
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.todos.create_list")
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.todos.CreateTodoList(ctx, todosdomain.CreateTodoListInput{
+		FamilyID:         family.ID,
+		Title:            req.GetTitle(),
+		ArchiveCompleted: req.GetArchiveCompleted(),
+		Order:            order,
+	})
+	if err != nil {
+		return nil, s.todoError(ctx, "grpc.todos.create_list", err, "user_id", user.ID, "family_id", family.ID)
+	}
+
+	return toTodoListMessage(list.List, list.Counts), nil
+}
+
+func (s *todosServer) DeleteTodoList(ctx context.Context, req *familyv1.DeleteTodoListRequest) (*familyv1.DeleteTodoListResponse, error) {
+	v := validation.New()
+	v.Required("list_id", req.GetListId())
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.todos.delete_list")
+	if err != nil {
+		return nil, err
+	}
+
+	listID := strings.TrimSpace(req.GetListId())
+	if err := s.todos.DeleteTodoList(ctx, family.ID, listID, user.ID); err != nil {
+		return nil, s.todoError(ctx, "grpc.todos.delete_list", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+	}
+	return &familyv1.DeleteTodoListResponse{}, nil
+}
+
+func (s *todosServer) ListTodoItems(ctx context.Context, req *familyv1.ListTodoItemsRequest) (*familyv1.ListTodoItemsResponse, error) {
+	v := validation.New()
+	v.Required("list_id", req.GetListId())
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.todos.list_items")
+	if err != nil {
+		return nil, err
+	}
+
+	listID := strings.TrimSpace(req.GetListId())
+	items, total, err := s.todos.ListTodoItems(ctx, family.ID, listID, user.ID, toArchivedFilter(req.GetArchived()), authmw.AssigneeRestriction(ctx), nil)
+	if err != nil {
+		return nil, s.todoError(ctx, "grpc.todos.list_items", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+	}
+
+	response := &familyv1.ListTodoItemsResponse{
+		Items: make([]*familyv1.TodoItem, 0, len(items)),
+		Total: total,
+	}
+	for _, item := range items {
+		response.Items = append(response.Items, toTodoItemMessage(item))
+	}
+	return response, nil
+}
+
+func (s *todosServer) CreateTodoItem(ctx context.Context, req *familyv1.CreateTodoItemRequest) (*familyv1.TodoItem, error) {
+	v := validation.New()
+	v.Required("list_id", req.GetListId())
+	v.Required("title", req.GetTitle())
+	v.Date("due_date", req.DueDate)
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.todos.create_item")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAssignee(ctx, "grpc.todos.create_item", user.ID, req.AssigneeId); err != nil {
+		return nil, err
+	}
+
+	listID := strings.TrimSpace(req.GetListId())
+	item, err := s.todos.CreateTodoItem(ctx, family.ID, todosdomain.CreateTodoItemInput{
+		ListID:     listID,
+		Title:      req.GetTitle(),
+		DueDate:    parseOptionalDate(req.GetDueDate()),
+		AssigneeID: nonEmpty(req.AssigneeId),
+		ViewerID:   user.ID,
+	})
+	if err != nil {
+		return nil, s.todoError(ctx, "grpc.todos.create_item", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+	}
+	return toTodoItemMessage(*item), nil
+}
+
+func (s *todosServer) UpdateTodoItem(ctx context.Context, req *familyv1.UpdateTodoItemRequest) (*familyv1.TodoItem, error) {
+	v := validation.New()
+	v.Required("item_id", req.GetItemId())
+	if req.Title == nil && req.IsCompleted == nil && req.DueDate == nil && req.AssigneeId == nil {
+		v.Add("", validation.CodeEmpty, "no fields to update")
+	}
+	v.NotBlank("title", req.Title)
+	v.Date("due_date", req.DueDate)
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.todos.update_item")
+	if err != nil {
+		return nil, err
+	}
+	if req.AssigneeId != nil {
+		if err := s.checkAssignee(ctx, "grpc.todos.update_item", user.ID, req.AssigneeId); err != nil {
+			return nil, err
+		}
+	}
+
+	var completedBy *todosdomain.UserSnapshot
+	if req.GetIsCompleted() {
+		completedBy = &todosdomain.UserSnapshot{
+			ID:        user.ID,
+			Name:      user.Name,
+			Email:     user.Email,
+			AvatarURL: user.AvatarURL,
+		}
+	}
+
+	itemID := strings.TrimSpace(req.GetItemId())
+	item, err := s.todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{
+		ID:          itemID,
+		FamilyID:    family.ID,
+		Title:       req.Title,
+		IsCompleted: req.IsCompleted,
+		DueDate: todosdomain.OptionalNullableDate{
+			Set:   req.DueDate != nil,
+			Value: parseOptionalDate(req.GetDueDate()),
+		},
+		AssigneeID: todosdomain.OptionalNullableString{
+			Set:   req.AssigneeId != nil,
+			Value: nonEmpty(req.AssigneeId),
+		},
+		CompletedBy:        completedBy,
+		RestrictToAssignee: authmw.AssigneeRestriction(ctx),
+		ViewerID:           user.ID,
+		ExpectedVersion:    req.GetExpectedVersion(),
+	})
+	if err != nil {
+		return nil, s.todoError(ctx, "grpc.todos.update_item", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+	}
+
+	if completedBy != nil {
+		if _, err := s.activity.RecordTodoCompleted(ctx, family.ID, actorFromUser(user), item.ListID, item.ID, item.Title); err != nil {
+			s.requestLog(ctx).InternalError("grpc.todos.update_item: record activity failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", item.ID)
+		}
+	}
+	return toTodoItemMessage(*item), nil
+}
+
+func (s *todosServer) DeleteTodoItem(ctx context.Context, req *familyv1.DeleteTodoItemRequest) (*familyv1.DeleteTodoItemResponse, error) {
+	v := validation.New()
+	v.Required("item_id", req.GetItemId())
+	if err := v.Err(); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	user, family, err := s.callerFamily(ctx, "grpc.todos.delete_item")
+	if err != nil {
+		return nil, err
+	}
+
+	itemID := strings.TrimSpace(req.GetItemId())
+	if err := s.todos.DeleteTodoItem(ctx, family.ID, itemID, user.ID); err != nil {
+		return nil, s.todoError(ctx, "grpc.todos.delete_item", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+	}
+	return &familyv1.DeleteTodoItemResponse{}, nil
+}
+
+// checkAssignee rejects assignees outside the caller's family.
+func (s *todosServer) checkAssignee(ctx context.Context, operation, userID string, assigneeID *string) error {
+	assignee := nonEmpty(assigneeID)
+	if assignee == nil {
+		return nil
+	}
+
+	members, err := s.families.ListMembers(ctx, userID)
+	if err != nil {
+		s.requestLog(ctx).InternalError(operation+": list members failed", err, "user_id", userID)
+		return internalError()
+	}
+	for _, member := range members {
+		if member.UserID == *assignee {
+			return nil
+		}
+	}
+	return invalidArgument(validation.FieldErr("assignee_id", validation.CodeInvalid, "assignee_id must be a family member"))
+}
+
+func (s *todosServer) todoError(ctx context.Context, operation string, err error, attrs ...any) error {
+	switch {
+	case errors.Is(err, todosdomain.ErrTodoListNotFound):
+		s.requestLog(ctx).BusinessError(operation+": todo list not found", err, attrs...)
+		return notFound("todo_list_not_found", "todo list not found")
+	case errors.Is(err, todosdomain.ErrTodoItemNotFound):
+		s.requestLog(ctx).BusinessError(operation+": todo item not found", err, attrs...)
+		return notFound("todo_item_not_found", "todo item not found")
+	case errors.Is(err, todosdomain.ErrAssigneeLocked):
+		s.requestLog(ctx).BusinessError(operation+": assignee change denied", err, attrs...)
+		return statusError(codes.PermissionDenied, "child_restricted", "not available for child members")
+	case errors.Is(err, todosdomain.ErrVersionConflict):
+		s.requestLog(ctx).BusinessError(operation+": version conflict", err, attrs...)
+		return versionConflict()
+	case errors.Is(err, quotadomain.ErrExceeded):
+		s.requestLog(ctx).BusinessError(operation+": quota exceeded", err, attrs...)
+		return quotaExceeded(err)
+	default:
+		s.requestLog(ctx).InternalError(operation+": failed", err, attrs...)
+		return internalError()
+	}
+}
+
+func toArchivedFilter(filter familyv1.ArchivedFilter) todosdomain.ArchivedFilter {
+	switch filter {
+	case familyv1.ArchivedFilter_ARCHIVED_FILTER_ONLY:
+		return todosdomain.ArchivedOnly
+	case familyv1.ArchivedFilter_ARCHIVED_FILTER_ALL:
+		return todosdomain.ArchivedAll
+	default:
+		return todosdomain.ArchivedExclude
+	}
+}
+
+func toTodoListMessage(list todosdomain.TodoList, counts todosdomain.ListItemCounts) *familyv1.TodoList {
+	return &familyv1.TodoList{
+		Id:               list.ID,
+		FamilyId:         list.FamilyID,
+		Title:            list.Title,
+		IsCollapsed:      list.IsCollapsed,
+		Order:            int32(list.Order),
+		ArchiveCompleted: list.ArchiveCompleted,
+		CreatedAt:        timestamppb.New(list.CreatedAt),
+		ItemsTotal:       counts.ItemsTotal,
+		ItemsCompleted:   counts.ItemsCompleted,
+		ItemsArchived:    counts.ItemsArchived,
+		Version:          list.Version,
+	}
+}
+
+func toTodoItemMessage(item todosdomain.TodoItem) *familyv1.TodoItem {
+	message := &familyv1.TodoItem{
+		Id:          item.ID,
+		ListId:      item.ListID,
+		Title:       item.Title,
+		IsCompleted: item.IsCompleted,
+		IsArchived:  item.IsArchived,
+		CreatedAt:   timestamppb.New(item.CreatedAt),
+		CompletedAt: optionalTimestamp(item.CompletedAt),
+		DueDate:     formatOptionalDate(item.DueDate),
+		AssigneeId:  item.AssigneeID,
+		Version:     item.Version,
+	}
+	if item.CompletedByID != nil && strings.TrimSpace(*item.CompletedByID) != "" {
+		message.CompletedBy = &familyv1.TodoCompletedBy{
+			Id:        *item.CompletedByID,
+			Name:      valueOrEmpty(item.CompletedByName),
+			Email:     valueOrEmpty(item.CompletedByEmail),
+			AvatarUrl: item.CompletedByAvatarURL,
+		}
+	}
+	return message
+}
+
+// nonEmpty trims an optional string and drops it when blank.
+func nonEmpty(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,669 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	admindomain "family-app-go/internal/domain/admin"
+	auditdomain "family-app-go/internal/domain/audit"
+	"family-app-go/internal/jobs"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/pkg/id"
+	"github.com/go-chi/chi/v5"
+)
+
+type familySummaryResponse struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	OwnerID         string    `json:"owner_id"`
+	DefaultCurrency string    `json:"default_currency"`
+	Members         int64     `json:"members"`
+	Expenses        int64     `json:"expenses"`
+	TodoLists       int64     `json:"todo_lists"`
+	TodoItems       int64     `json:"todo_items"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type familyListResponse struct {
+	Items []familySummaryResponse `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+type syncBatchResponse struct {
+	ID             string    `json:"id"`
+	FamilyID       string    `json:"family_id"`
+	UserID         string    `json:"user_id"`
+	IdempotencyKey *string   `json:"idempotency_key"`
+	Status         string    `json:"status"`
+	Stuck          bool      `json:"stuck"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type syncBatchListResponse struct {
+	Items []syncBatchResponse `json:"items"`
+}
+
+type syncOperationResponse struct {
+	ID            string    `json:"id"`
+	OperationID   string    `json:"operation_id"`
+	OperationType string    `json:"operation_type"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type syncBatchDetailResponse struct {
+	syncBatchResponse
+	PendingOperations []syncOperationResponse `json:"pending_operations"`
+}
+
+type releaseResponse struct {
+	BatchID            string `json:"batch_id"`
+	OperationsReleased int64  `json:"operations_released"`
+}
+
+type purgeRequest struct {
+	// OlderThanDays keeps rows soft-deleted within the last N days.
+	OlderThanDays *int `json:"older_than_days"`
+	DryRun        bool `json:"dry_run"`
+}
+
+type purgeTableResponse struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+type purgeResponse struct {
+	DeletedBefore time.Time            `json:"deleted_before"`
+	DryRun        bool                 `json:"dry_run"`
+	Tables        []purgeTableResponse `json:"tables"`
+}
+
+type rotateEncryptionRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+type rotationColumnResponse struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Rows   int64  `json:"rows"`
+}
+
+type rotateEncryptionResponse struct {
+	KeyID   string                   `json:"key_id"`
+	DryRun  bool                     `json:"dry_run"`
+	Columns []rotationColumnResponse `json:"columns"`
+}
+
+type indexCheckResponse struct {
+	Name      string   `json:"name"`
+	Table     string   `json:"table"`
+	Columns   []string `json:"columns"`
+	Query     string   `json:"query"`
+	Status    string   `json:"status"`
+	MatchedBy *string  `json:"matched_by"`
+}
+
+type indexResponse struct {
+	Name    string   `json:"name"`
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Partial bool     `json:"partial"`
+}
+
+type indexReportResponse struct {
+	Missing int                  `json:"missing"`
+	Checks  []indexCheckResponse `json:"checks"`
+	Invalid []indexResponse      `json:"invalid"`
+}
+
+type jobResponse struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	NextRunAt   *time.Time `json:"next_run_at"`
+}
+
+type jobListResponse struct {
+	Items []jobResponse `json:"items"`
+}
+
+type jobRunResponse struct {
+	ID         string          `json:"id"`
+	Job        string          `json:"job"`
+	Trigger    string          `json:"trigger"`
+	Status     string          `json:"status"`
+	Attempts   int             `json:"attempts"`
+	Instance   string          `json:"instance"`
+	Error      *string         `json:"error"`
+	Result     json.RawMessage `json:"result"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+}
+
+type jobRunListResponse struct {
+	Items []jobRunResponse `json:"items"`
+}
+
+type backupResponse struct {
+	Key       string    `json:"key"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type backupListResponse struct {
+	Items []backupResponse `json:"items"`
+}
+
+type restoreBackupRequest struct {
+	Key string `json:"key"`
+}
+
+type restoreBackupResponse struct {
+	Key           string               `json:"key"`
+	SchemaVersion string               `json:"schema_version"`
+	Tables        []purgeTableResponse `json:"tables"`
+}
+
+type securityEventResponse struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	ActorID   *string         `json:"actor_id,omitempty"`
+	FamilyID  *string         `json:"family_id,omitempty"`
+	TargetID  *string         `json:"target_id,omitempty"`
+	APIKeyID  *string         `json:"api_key_id,omitempty"`
+	IP        string          `json:"ip"`
+	UserAgent string          `json:"user_agent"`
+	RequestID string          `json:"request_id"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type securityEventListResponse struct {
+	Items []securityEventResponse `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+func (h *Handlers) ListFamilies(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), admindomain.DefaultFamiliesLimit)
+	if err != nil || limit <= 0 || limit > admindomain.MaxFamiliesLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "limit must be between 1 and 200")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+
+	families, total, err := h.Admin.ListFamilies(r.Context(), admindomain.ListFamiliesFilter{Limit: limit, Offset: offset})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("admin.families: list families failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := familyListResponse{Items: make([]familySummaryResponse, 0, len(families)), Total: total}
+	for _, family := range families {
+		response.Items = append(response.Items, familySummaryResponse{
+			ID:              family.ID,
+			Name:            family.Name,
+			OwnerID:         family.OwnerID,
+			DefaultCurrency: family.DefaultCurrency,
+			Members:         family.Members,
+			Expenses:        family.Expenses,
+			TodoLists:       family.TodoLists,
+			TodoItems:       family.TodoItems,
+			CreatedAt:       family.CreatedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) ListSyncBatches(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), admindomain.DefaultBatchesLimit)
+	if err != nil || limit <= 0 || limit > admindomain.MaxBatchesLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "limit must be between 1 and 200")
+		return
+	}
+	stuck, err := parseBoolParam(query.Get("stuck"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "stuck must be true or false")
+		return
+	}
+
+	batches, err := h.Admin.ListSyncBatches(r.Context(), admindomain.SyncBatchFilter{
+		FamilyID:  query.Get("family_id"),
+		Status:    query.Get("status"),
+		StuckOnly: stuck,
+		Limit:     limit,
+	})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("admin.sync_batches: list batches failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := syncBatchListResponse{Items: make([]syncBatchResponse, 0, len(batches))}
+	for _, batch := range batches {
+		response.Items = append(response.Items, toSyncBatchResponse(batch))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) GetSyncBatch(w http.ResponseWriter, r *http.Request) {
+	batchID := strings.TrimSpace(chi.URLParam(r, "id"))
+
+	detail, err := h.Admin.GetSyncBatch(r.Context(), batchID)
+	if err != nil {
+		if errors.Is(err, admindomain.ErrSyncBatchNotFound) {
+			writeError(w, http.StatusNotFound, "sync_batch_not_found", "sync batch not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("admin.sync_batch: get batch failed", err, "batch_id", batchID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := syncBatchDetailResponse{
+		syncBatchResponse: toSyncBatchResponse(detail.SyncBatch),
+		PendingOperations: make([]syncOperationResponse, 0, len(detail.PendingOperations)),
+	}
+	for _, operation := range detail.PendingOperations {
+		response.PendingOperations = append(response.PendingOperations, syncOperationResponse{
+			ID:            operation.ID,
+			OperationID:   operation.OperationID,
+			OperationType: operation.OperationType,
+			Status:        operation.Status,
+			CreatedAt:     operation.CreatedAt,
+			UpdatedAt:     operation.UpdatedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) ReleaseSyncBatch(w http.ResponseWriter, r *http.Request) {
+	batchID := strings.TrimSpace(chi.URLParam(r, "id"))
+	force, err := parseBoolParam(r.URL.Query().Get("force"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "force must be true or false")
+		return
+	}
+
+	result, err := h.Admin.ReleaseSyncBatch(r.Context(), batchID, force)
+	if err != nil {
+		switch {
+		case errors.Is(err, admindomain.ErrSyncBatchNotFound):
+			writeError(w, http.StatusNotFound, "sync_batch_not_found", "sync batch not found")
+		case errors.Is(err, admindomain.ErrSyncBatchNotProcessing):
+			writeError(w, http.StatusConflict, "sync_batch_not_processing", "sync batch is not processing")
+		case errors.Is(err, admindomain.ErrSyncBatchNotStuck):
+			writeError(w, http.StatusConflict, "sync_batch_not_stuck", "sync batch is still recent; pass force=true to release it anyway")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("admin.sync_release: release batch failed", err, "batch_id", batchID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	commonhandler.RequestLog(r, h.log).Info("admin: sync batch released", "batch_id", result.BatchID, "operations_released", result.OperationsReleased, "force", force)
+	writeJSON(w, http.StatusOK, releaseResponse{BatchID: result.BatchID, OperationsReleased: result.OperationsReleased})
+}
+
+func (h *Handlers) PurgeSoftDeleted(w http.ResponseWriter, r *http.Request) {
+	var req purgeRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	input := admindomain.PurgeInput{DryRun: req.DryRun}
+	if req.OlderThanDays != nil {
+		input.DeletedBefore = time.Now().UTC().AddDate(0, 0, -*req.OlderThanDays)
+	}
+
+	result, err := h.Admin.PurgeSoftDeleted(r.Context(), input)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("admin.purge: purge failed", err, "dry_run", req.DryRun)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := purgeResponse{
+		DeletedBefore: result.DeletedBefore,
+		DryRun:        result.DryRun,
+		Tables:        make([]purgeTableResponse, 0, len(result.Tables)),
+	}
+	for _, table := range result.Tables {
+		response.Tables = append(response.Tables, purgeTableResponse{Table: table.Table, Rows: table.Rows})
+		if !result.DryRun {
+			commonhandler.RequestLog(r, h.log).Info("admin: soft-deleted rows purged", "table", table.Table, "rows", table.Rows, "deleted_before", result.DeletedBefore)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) RotateEncryption(w http.ResponseWriter, r *http.Request) {
+	var req rotateEncryptionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	result, err := h.Admin.RotateEncryption(r.Context(), admindomain.RotateEncryptionInput{DryRun: req.DryRun})
+	if err != nil {
+		if errors.Is(err, admindomain.ErrEncryptionDisabled) {
+			writeError(w, http.StatusConflict, "encryption_disabled", "field encryption is disabled; set FIELD_ENCRYPTION_KEYS")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("admin.encryption_rotate: rotate failed", err, "dry_run", req.DryRun)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := rotateEncryptionResponse{
+		KeyID:   result.KeyID,
+		DryRun:  result.DryRun,
+		Columns: make([]rotationColumnResponse, 0, len(result.Columns)),
+	}
+	for _, column := range result.Columns {
+		response.Columns = append(response.Columns, rotationColumnResponse{Table: column.Table, Column: column.Column, Rows: column.Rows})
+		if !result.DryRun {
+			commonhandler.RequestLog(r, h.log).Info("admin: encrypted column rotated", "table", column.Table, "column", column.Column, "rows", column.Rows, "key_id", result.KeyID)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) IndexReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.Admin.IndexReport(r.Context())
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("admin.indexes: index report failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := indexReportResponse{
+		Missing: report.Missing,
+		Checks:  make([]indexCheckResponse, 0, len(report.Checks)),
+		Invalid: make([]indexResponse, 0, len(report.Invalid)),
+	}
+	for _, check := range report.Checks {
+		item := indexCheckResponse{
+			Name:    check.Name,
+			Table:   check.Table,
+			Columns: check.Columns,
+			Query:   check.Query,
+			Status:  string(check.Status),
+		}
+		if check.MatchedBy != "" {
+			matchedBy := check.MatchedBy
+			item.MatchedBy = &matchedBy
+		}
+		response.Checks = append(response.Checks, item)
+	}
+	for _, index := range report.Invalid {
+		response.Invalid = append(response.Invalid, indexResponse{
+			Name:    index.Name,
+			Table:   index.Table,
+			Columns: index.Columns,
+			Unique:  index.Unique,
+			Partial: index.Partial,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) ListJobs(w http.ResponseWriter, r *http.Request) {
+	registered := h.Admin.Jobs()
+	now := time.Now().UTC()
+	response := jobListResponse{Items: make([]jobResponse, 0, len(registered))}
+	for _, job := range registered {
+		item := jobResponse{Name: job.Name, Description: job.Description}
+		if job.Schedule != nil {
+			if next := job.Schedule.Next(now); !next.IsZero() {
+				item.NextRunAt = &next
+			}
+		}
+		response.Items = append(response.Items, item)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) RunJob(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(chi.URLParam(r, "name"))
+
+	run, err := h.Admin.RunJob(r.Context(), name)
+	if err != nil {
+		switch {
+		case errors.Is(err, admindomain.ErrJobNotFound):
+			writeError(w, http.StatusNotFound, "job_not_found", "job not found")
+		case errors.Is(err, admindomain.ErrJobRunning):
+			writeError(w, http.StatusConflict, "job_running", "job is already running")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("admin.jobs: run job failed", err, "job", name)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	commonhandler.RequestLog(r, h.log).Info("admin: job run", "job", run.JobName, "run_id", run.ID, "status", run.Status, "attempts", run.Attempts)
+	writeJSON(w, http.StatusOK, toJobRunResponse(*run))
+}
+
+func (h *Handlers) ListJobRuns(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), jobs.DefaultRunsLimit)
+	if err != nil || limit <= 0 || limit > jobs.MaxRunsLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "limit must be between 1 and 200")
+		return
+	}
+	status := jobs.RunStatus(strings.TrimSpace(query.Get("status")))
+	switch status {
+	case "", jobs.RunStatusRunning, jobs.RunStatusSucceeded, jobs.RunStatusFailed:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_request", "status must be running, succeeded or failed")
+		return
+	}
+
+	runs, err := h.Admin.ListJobRuns(r.Context(), jobs.RunFilter{
+		JobName: query.Get("job"),
+		Status:  status,
+		Limit:   limit,
+	})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("admin.job_runs: list runs failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := jobRunListResponse{Items: make([]jobRunResponse, 0, len(runs))}
+	for _, run := range runs {
+		response.Items = append(response.Items, toJobRunResponse(run))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func toJobRunResponse(run jobs.Run) jobRunResponse {
+	response := jobRunResponse{
+		ID:         run.ID,
+		Job:        run.JobName,
+		Trigger:    string(run.Trigger),
+		Status:     string(run.Status),
+		Attempts:   run.Attempts,
+		Instance:   run.Instance,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+	if len(run.Result) > 0 {
+		response.Result = json.RawMessage(run.Result)
+	}
+	return response
+}
+
+func (h *Handlers) ListBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := h.Admin.ListBackups(r.Context())
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("admin.backups: list backups failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := backupListResponse{Items: make([]backupResponse, 0, len(backups))}
+	for _, backup := range backups {
+		response.Items = append(response.Items, backupResponse{
+			Key:       backup.Key,
+			SizeBytes: backup.SizeBytes,
+			CreatedAt: backup.CreatedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) RestoreBackup(w http.ResponseWriter, r *http.Request) {
+	var req restoreBackupRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	result, err := h.Admin.RestoreBackup(r.Context(), req.Key)
+	if err != nil {
+		switch {
+		case errors.Is(err, admindomain.ErrInvalidBackupKey):
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid backup key")
+		case errors.Is(err, admindomain.ErrBackupNotFound):
+			writeError(w, http.StatusNotFound, "backup_not_found", "backup not found")
+		case errors.Is(err, admindomain.ErrBackupSchemaMismatch):
+			writeError(w, http.StatusConflict, "backup_schema_mismatch", err.Error())
+		case errors.Is(err, admindomain.ErrInvalidBackup):
+			writeError(w, http.StatusUnprocessableEntity, "invalid_backup", err.Error())
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("admin.backups: restore failed", err, "key", req.Key)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	response := restoreBackupResponse{
+		Key:           result.Key,
+		SchemaVersion: result.SchemaVersion,
+		Tables:        make([]purgeTableResponse, 0, len(result.Tables)),
+	}
+	for _, table := range result.Tables {
+		response.Tables = append(response.Tables, purgeTableResponse{Table: table.Table, Rows: table.Rows})
+	}
+	commonhandler.RequestLog(r, h.log).Warn("admin: database restored from backup", "key", result.Key, "tables", len(result.Tables))
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) ListSecurityEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), auditdomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > auditdomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "limit must be between 1 and 200")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+	filter := auditdomain.ListFilter{
+		Type:     strings.TrimSpace(query.Get("type")),
+		ActorID:  strings.TrimSpace(query.Get("actor_id")),
+		FamilyID: strings.TrimSpace(query.Get("family_id")),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if filter.ActorID != "" && !id.IsUUID(filter.ActorID) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "actor_id must be a UUID")
+		return
+	}
+	if filter.FamilyID != "" && !id.IsUUID(filter.FamilyID) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "family_id must be a UUID")
+		return
+	}
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "from must be an RFC 3339 timestamp")
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "to must be an RFC 3339 timestamp")
+		return
+	}
+
+	events, total, err := h.Audit.List(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, auditdomain.ErrInvalidEventType) {
+			writeError(w, http.StatusBadRequest, "invalid_request", "unknown event type")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("admin.security_events: list events failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := securityEventListResponse{Items: make([]securityEventResponse, 0, len(events)), Total: total}
+	for _, event := range events {
+		response.Items = append(response.Items, toSecurityEventResponse(event))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func toSecurityEventResponse(event auditdomain.Event) securityEventResponse {
+	response := securityEventResponse{
+		ID:        event.ID,
+		Type:      event.Type,
+		ActorID:   event.ActorID,
+		FamilyID:  event.FamilyID,
+		TargetID:  event.TargetID,
+		APIKeyID:  event.APIKeyID,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		RequestID: event.RequestID,
+		CreatedAt: event.CreatedAt,
+	}
+	if len(event.Details) > 0 {
+		response.Details = json.RawMessage(event.Details)
+	}
+	return response
+}
+
+func toSyncBatchResponse(batch admindomain.SyncBatch) syncBatchResponse {
+	return syncBatchResponse{
+		ID:             batch.ID,
+		FamilyID:       batch.FamilyID,
+		UserID:         batch.UserID,
+		IdempotencyKey: batch.IdempotencyKey,
+		Status:         batch.Status,
+		Stuck:          batch.Stuck,
+		CreatedAt:      batch.CreatedAt,
+		UpdatedAt:      batch.UpdatedAt,
+	}
+}
+
+func parseTimeParam(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseBoolParam(value string) (bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,21 @@
+package admin
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Admin AdminService
+	Audit AuditService
+	Usage UsageService
+	log   logger.Logger
+}
+
+func New(admin AdminService, audit AuditService, usage UsageService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Admin: admin,
+		Audit: audit,
+		Usage: usage,
+		log:   log,
+	}
+}
@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"net/http"
+
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+)
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	commonhandler.WriteError(w, status, code, message)
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	commonhandler.WriteJSON(w, status, payload)
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return commonhandler.DecodeRequest(w, r, dst)
+}
+
+func parseIntParam(value string, fallback int) (int, error) {
+	return commonhandler.ParseIntParam(value, fallback)
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	admindomain "family-app-go/internal/domain/admin"
+	auditdomain "family-app-go/internal/domain/audit"
+	backupdomain "family-app-go/internal/domain/backup"
+	usagedomain "family-app-go/internal/domain/usage"
+	"family-app-go/internal/jobs"
+)
+
+// AdminService is implemented by *admindomain.Service.
+type AdminService interface {
+	GetSyncBatch(ctx context.Context, batchID string) (*admindomain.SyncBatchDetail, error)
+	IndexReport(ctx context.Context) (*admindomain.IndexReport, error)
+	Jobs() []jobs.Job
+	ListBackups(ctx context.Context) ([]backupdomain.Backup, error)
+	ListFamilies(ctx context.Context, filter admindomain.ListFamiliesFilter) ([]admindomain.FamilySummary, int64, error)
+	ListJobRuns(ctx context.Context, filter jobs.RunFilter) ([]jobs.Run, error)
+	ListSyncBatches(ctx context.Context, filter admindomain.SyncBatchFilter) ([]admindomain.SyncBatch, error)
+	PurgeSoftDeleted(ctx context.Context, input admindomain.PurgeInput) (*admindomain.PurgeResult, error)
+	ReleaseSyncBatch(ctx context.Context, batchID string, force bool) (*admindomain.ReleaseResult, error)
+	RestoreBackup(ctx context.Context, key string) (*backupdomain.RestoreResult, error)
+	RotateEncryption(ctx context.Context, input admindomain.RotateEncryptionInput) (*admindomain.RotateEncryptionResult, error)
+	RunJob(ctx context.Context, name string) (*jobs.Run, error)
+}
+
+// AuditService is implemented by *auditdomain.Service.
+type AuditService interface {
+	List(ctx context.Context, filter auditdomain.ListFilter) ([]auditdomain.Event, int64, error)
+}
+
+// UsageService is implemented by *usagedomain.Service.
+type UsageService interface {
+	ListDaily(ctx context.Context, filter usagedomain.ListFilter) ([]usagedomain.DailyCount, error)
+	ListModules(ctx context.Context, from, to time.Time) (*usagedomain.ModuleReport, error)
+}
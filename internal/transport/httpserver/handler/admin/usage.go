@@ -0,0 +1,148 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	usagedomain "family-app-go/internal/domain/usage"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/pkg/id"
+)
+
+const usageDayLayout = "2006-01-02"
+
+type moduleUsageResponse struct {
+	Module   string `json:"module"`
+	Requests int64  `json:"requests"`
+	Families int64  `json:"families"`
+	LastDay  string `json:"last_day"`
+}
+
+type moduleUsageListResponse struct {
+	From  string                `json:"from"`
+	To    string                `json:"to"`
+	Items []moduleUsageResponse `json:"items"`
+}
+
+type dailyUsageResponse struct {
+	FamilyID string `json:"family_id"`
+	Day      string `json:"day"`
+	Method   string `json:"method"`
+	Route    string `json:"route"`
+	Module   string `json:"module"`
+	Count    int64  `json:"count"`
+}
+
+type dailyUsageListResponse struct {
+	Items []dailyUsageResponse `json:"items"`
+}
+
+// ListModuleUsage shows which modules families use: requests and distinct
+// families per module over a range of days.
+func (h *Handlers) ListModuleUsage(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseUsageRange(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.Usage.ListModules(r.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, usagedomain.ErrInvalidRange) {
+			writeError(w, http.StatusBadRequest, "invalid_request", "from must not be after to, and the range must not exceed 366 days")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("admin.usage: list modules failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := moduleUsageListResponse{
+		From:  report.From.Format(usageDayLayout),
+		To:    report.To.Format(usageDayLayout),
+		Items: make([]moduleUsageResponse, 0, len(report.Modules)),
+	}
+	for _, module := range report.Modules {
+		response.Items = append(response.Items, moduleUsageResponse{
+			Module:   module.Module,
+			Requests: module.Requests,
+			Families: module.Families,
+			LastDay:  module.LastDay.Format(usageDayLayout),
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// ListUsage returns daily request counts per family and endpoint.
+func (h *Handlers) ListUsage(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseUsageRange(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), usagedomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > usagedomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "limit must be between 1 and 1000")
+		return
+	}
+	filter := usagedomain.ListFilter{
+		From:     from,
+		To:       to,
+		FamilyID: strings.TrimSpace(query.Get("family_id")),
+		Module:   strings.TrimSpace(query.Get("module")),
+		Limit:    limit,
+	}
+	if filter.FamilyID != "" && !id.IsUUID(filter.FamilyID) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "family_id must be a UUID")
+		return
+	}
+
+	counts, err := h.Usage.ListDaily(r.Context(), filter)
+	if err != nil {
+		if errors.Is(err, usagedomain.ErrInvalidRange) {
+			writeError(w, http.StatusBadRequest, "invalid_request", "from must not be after to, and the range must not exceed 366 days")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("admin.usage: list usage failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := dailyUsageListResponse{Items: make([]dailyUsageResponse, 0, len(counts))}
+	for _, count := range counts {
+		response.Items = append(response.Items, dailyUsageResponse{
+			FamilyID: count.FamilyID,
+			Day:      count.Day.Format(usageDayLayout),
+			Method:   count.Method,
+			Route:    count.Route,
+			Module:   count.Module,
+			Count:    count.Count,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// parseUsageRange reads the from and to days. Missing days are left zero
+// for the service to default.
+func parseUsageRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	var from, to time.Time
+	query := r.URL.Query()
+	if value := strings.TrimSpace(query.Get("from")); value != "" {
+		parsed, err := time.Parse(usageDayLayout, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "from must be a date (YYYY-MM-DD)")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	if value := strings.TrimSpace(query.Get("to")); value != "" {
+		parsed, err := time.Parse(usageDayLayout, value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "to must be a date (YYYY-MM-DD)")
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+	return from, to, true
+}
@@ -0,0 +1,11 @@
+package admin
+
+import "family-app-go/internal/transport/httpserver/validation"
+
+func (req purgeRequest) Validate(v *validation.Validator) {
+	v.NonNegative("older_than_days", req.OlderThanDays)
+}
+
+func (req restoreBackupRequest) Validate(v *validation.Validator) {
+	v.Required("key", req.Key)
+}
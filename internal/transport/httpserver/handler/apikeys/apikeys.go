@@ -0,0 +1,156 @@
+package apikeys
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	auditdomain "family-app-go/internal/domain/audit"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"family-app-go/pkg/id"
+	"github.com/go-chi/chi/v5"
+)
+
+type createAPIKeyRequest struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+type apiKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type createdAPIKeyResponse struct {
+	apiKeyResponse
+	Key string `json:"key"`
+}
+
+type apiKeysListResponse struct {
+	Items []apiKeyResponse `json:"items"`
+}
+
+func (h *Handlers) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.APIKeys.ListKeys(r.Context(), user.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("api_keys.list: list keys failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	items := make([]apiKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, toAPIKeyResponse(key))
+	}
+	writeJSON(w, http.StatusOK, apiKeysListResponse{Items: items})
+}
+
+func (h *Handlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req createAPIKeyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	key, plaintext, err := h.APIKeys.CreateKey(r.Context(), user.ID, apikeysdomain.CreateInput{
+		Name:  req.Name,
+		Scope: req.Scope,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, apikeysdomain.ErrInvalidName):
+			writeValidationError(w, validation.FieldErr("name", validation.CodeInvalid, fmt.Sprintf("name is required and must be at most %d characters", apikeysdomain.MaxNameLength)))
+		case errors.Is(err, apikeysdomain.ErrInvalidScope):
+			writeValidationError(w, validation.FieldErr("scope", validation.CodeEnum, "scope must be one of full, read_only"))
+		case errors.Is(err, apikeysdomain.ErrTooManyKeys):
+			commonhandler.RequestLog(r, h.log).BusinessError("api_keys.create: too many keys", err, "user_id", user.ID)
+			writeError(w, http.StatusConflict, "too_many_api_keys", fmt.Sprintf("at most %d active api keys are allowed", apikeysdomain.MaxKeysPerUser))
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("api_keys.create: create key failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventAPIKeyCreated, func(input *auditdomain.Input) {
+		input.TargetID = key.ID
+		input.Details = map[string]string{"scope": key.Scope, "prefix": key.Prefix}
+	})
+
+	writeJSON(w, http.StatusCreated, createdAPIKeyResponse{
+		apiKeyResponse: toAPIKeyResponse(*key),
+		Key:            plaintext,
+	})
+}
+
+func (h *Handlers) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	keyID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if !id.IsUUID(keyID) {
+		writeError(w, http.StatusNotFound, "api_key_not_found", "api key not found")
+		return
+	}
+
+	if err := h.APIKeys.RevokeKey(r.Context(), user.ID, keyID); err != nil {
+		if errors.Is(err, apikeysdomain.ErrKeyNotFound) {
+			writeError(w, http.StatusNotFound, "api_key_not_found", "api key not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("api_keys.revoke: revoke key failed", err, "user_id", user.ID, "key_id", keyID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventAPIKeyRevoked, func(input *auditdomain.Input) {
+		input.TargetID = keyID
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionUser rejects requests made with an API key: keys must not be able
+// to mint or revoke other keys.
+func (h *Handlers) sessionUser(w http.ResponseWriter, r *http.Request) (middleware.User, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, false
+	}
+	if _, viaKey := middleware.APIKeyFromContext(r.Context()); viaKey {
+		writeError(w, http.StatusForbidden, "api_key_not_allowed", "api keys cannot manage api keys")
+		return middleware.User{}, false
+	}
+	return user, true
+}
+
+func toAPIKeyResponse(key apikeysdomain.Key) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scope:      key.Scope,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,223 @@
+package apikeys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	auditdomain "family-app-go/internal/domain/audit"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	testUserID = "22222222-2222-2222-2222-222222222222"
+	testKeyID  = "66666666-6666-6666-6666-666666666666"
+)
+
+var errDatabase = errors.New("database is down")
+
+type mockAPIKeys struct {
+	keys    []apikeysdomain.Key
+	err     error
+	created apikeysdomain.CreateInput
+	revoked string
+}
+
+func (m *mockAPIKeys) CreateKey(_ context.Context, userID string, input apikeysdomain.CreateInput) (*apikeysdomain.Key, string, error) {
+	m.created = input
+	if m.err != nil {
+		return nil, "", m.err
+	}
+	return &apikeysdomain.Key{ID: testKeyID, UserID: userID, Name: input.Name, Prefix: "fam_abc", Scope: input.Scope, CreatedAt: time.Now()}, "fam_abc_secret", nil
+}
+
+func (m *mockAPIKeys) ListKeys(context.Context, string) ([]apikeysdomain.Key, error) {
+	return m.keys, m.err
+}
+
+func (m *mockAPIKeys) RevokeKey(_ context.Context, _ string, id string) error {
+	m.revoked = id
+	return m.err
+}
+
+type mockAudit struct {
+	events []auditdomain.Input
+}
+
+func (m *mockAudit) Record(_ context.Context, input auditdomain.Input) error {
+	m.events = append(m.events, input)
+	return nil
+}
+
+func newTestHandlers(keys *mockAPIKeys, audit *mockAudit) *Handlers {
+	return New(keys, audit, logger.New(io.Discard, slog.LevelError, "text"))
+}
+
+func userRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(middleware.WithUser(req.Context(), middleware.User{ID: testUserID}))
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var payload struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error response %q: %v", rec.Body.String(), err)
+	}
+	return payload.Error.Code
+}
+
+func TestAPIKeyHandlersRejectMissingUserAndAPIKeys(t *testing.T) {
+	h := newTestHandlers(&mockAPIKeys{}, &mockAudit{})
+
+	rec := httptest.NewRecorder()
+	h.ListAPIKeys(rec, httptest.NewRequest(http.MethodGet, "/api/me/api-keys", nil))
+	if rec.Code != http.StatusUnauthorized || errorCode(t, rec) != "invalid_token" {
+		t.Fatalf("expected 401 invalid_token, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req := userRequest(http.MethodGet, "/api/me/api-keys", "")
+	req = req.WithContext(middleware.WithAPIKey(req.Context(), middleware.APIKey{ID: testKeyID, Scope: apikeysdomain.ScopeFull}))
+	rec = httptest.NewRecorder()
+	h.ListAPIKeys(rec, req)
+	if rec.Code != http.StatusForbidden || errorCode(t, rec) != "api_key_not_allowed" {
+		t.Fatalf("expected 403 api_key_not_allowed, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestListAPIKeys(t *testing.T) {
+	keys := &mockAPIKeys{keys: []apikeysdomain.Key{{ID: testKeyID, Name: "CI", Prefix: "fam_abc", Scope: apikeysdomain.ScopeReadOnly}}}
+	rec := httptest.NewRecorder()
+	newTestHandlers(keys, &mockAudit{}).ListAPIKeys(rec, userRequest(http.MethodGet, "/api/me/api-keys", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body apiKeysListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Items) != 1 || body.Items[0].ID != testKeyID || body.Items[0].Scope != apikeysdomain.ScopeReadOnly {
+		t.Fatalf("unexpected items: %+v", body.Items)
+	}
+
+	keys.err = errDatabase
+	rec = httptest.NewRecorder()
+	newTestHandlers(keys, &mockAudit{}).ListAPIKeys(rec, userRequest(http.MethodGet, "/api/me/api-keys", ""))
+	if rec.Code != http.StatusInternalServerError || errorCode(t, rec) != "internal_error" {
+		t.Fatalf("expected 500 internal_error, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateAPIKeyErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "invalid json", body: `{"name":`, status: http.StatusBadRequest, code: "invalid_json"},
+		{name: "unknown field", body: `{"name":"CI","scope":"full","extra":1}`, status: http.StatusBadRequest, code: "invalid_json"},
+		{name: "validation", body: `{"name":"","scope":"admin"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "invalid name", body: `{"name":"CI","scope":"full"}`, err: apikeysdomain.ErrInvalidName, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "invalid scope", body: `{"name":"CI","scope":"full"}`, err: apikeysdomain.ErrInvalidScope, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "too many keys", body: `{"name":"CI","scope":"full"}`, err: apikeysdomain.ErrTooManyKeys, status: http.StatusConflict, code: "too_many_api_keys"},
+		{name: "internal", body: `{"name":"CI","scope":"full"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			audit := &mockAudit{}
+			rec := httptest.NewRecorder()
+			newTestHandlers(&mockAPIKeys{err: tc.err}, audit).CreateAPIKey(rec, userRequest(http.MethodPost, "/api/me/api-keys", tc.body))
+
+			if rec.Code != tc.status || errorCode(t, rec) != tc.code {
+				t.Fatalf("expected %d %s, got %d: %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+			if len(audit.events) != 0 {
+				t.Fatalf("expected no audit event, got %+v", audit.events)
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyReturnsPlaintextOnceAndAudits(t *testing.T) {
+	keys := &mockAPIKeys{}
+	audit := &mockAudit{}
+	rec := httptest.NewRecorder()
+	newTestHandlers(keys, audit).CreateAPIKey(rec, userRequest(http.MethodPost, "/api/me/api-keys", `{"name":"CI","scope":"read_only"}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body createdAPIKeyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Key != "fam_abc_secret" || body.ID != testKeyID || keys.created.Scope != apikeysdomain.ScopeReadOnly {
+		t.Fatalf("unexpected response %+v for input %+v", body, keys.created)
+	}
+	if len(audit.events) != 1 || audit.events[0].Type != auditdomain.EventAPIKeyCreated || audit.events[0].TargetID != testKeyID {
+		t.Fatalf("unexpected audit events: %+v", audit.events)
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	cases := []struct {
+		name   string
+		keyID  string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "malformed id", keyID: "not-a-uuid", status: http.StatusNotFound, code: "api_key_not_found"},
+		{name: "not found", keyID: testKeyID, err: apikeysdomain.ErrKeyNotFound, status: http.StatusNotFound, code: "api_key_not_found"},
+		{name: "internal", keyID: testKeyID, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "revoked", keyID: testKeyID, status: http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys := &mockAPIKeys{err: tc.err}
+			audit := &mockAudit{}
+			req := withURLParam(userRequest(http.MethodDelete, "/api/me/api-keys/"+tc.keyID, ""), "id", tc.keyID)
+			rec := httptest.NewRecorder()
+			newTestHandlers(keys, audit).RevokeAPIKey(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				if len(audit.events) != 0 {
+					t.Fatalf("expected no audit event, got %+v", audit.events)
+				}
+				return
+			}
+			if keys.revoked != testKeyID || len(audit.events) != 1 || audit.events[0].TargetID != testKeyID {
+				t.Fatalf("expected key revoked and audited, got %q %+v", keys.revoked, audit.events)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package apikeys
+
+import (
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	APIKeys APIKeysService
+	Audit   commonhandler.AuditRecorder
+	log     logger.Logger
+}
+
+func New(apiKeys APIKeysService, audit commonhandler.AuditRecorder, log logger.Logger) *Handlers {
+	return &Handlers{
+		APIKeys: apiKeys,
+		Audit:   audit,
+		log:     log,
+	}
+}
@@ -0,0 +1,14 @@
+package apikeys
+
+import (
+	"context"
+
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+)
+
+// APIKeysService is implemented by *apikeysdomain.Service.
+type APIKeysService interface {
+	CreateKey(ctx context.Context, userID string, input apikeysdomain.CreateInput) (*apikeysdomain.Key, string, error)
+	ListKeys(ctx context.Context, userID string) ([]apikeysdomain.Key, error)
+	RevokeKey(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,12 @@
+package apikeys
+
+import (
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req createAPIKeyRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, apikeysdomain.MaxNameLength)
+	v.OneOf("scope", req.Scope, apikeysdomain.ScopeFull, apikeysdomain.ScopeReadOnly)
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	auditdomain "family-app-go/internal/domain/audit"
+	authdomain "family-app-go/internal/domain/auth"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+type registerRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type sessionResponse struct {
+	AccessToken  string              `json:"access_token"`
+	RefreshToken string              `json:"refresh_token"`
+	TokenType    string              `json:"token_type"`
+	ExpiresAt    time.Time           `json:"expires_at"`
+	ExpiresIn    int64               `json:"expires_in"`
+	User         sessionUserResponse `json:"user"`
+}
+
+type sessionUserResponse struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+func (h *Handlers) Register(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var req registerRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	account, pair, err := h.Auth.Register(r.Context(), authdomain.RegisterInput{
+		Email:    req.Email,
+		Password: req.Password,
+		Name:     req.Name,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, authdomain.ErrInvalidEmail):
+			writeValidationError(w, validation.FieldErr("email", validation.CodeFormat, "invalid email"))
+		case errors.Is(err, authdomain.ErrInvalidPassword):
+			writeValidationError(w, validation.FieldErr("password", validation.CodeInvalid, "password must be 8 to 72 bytes long"))
+		case errors.Is(err, authdomain.ErrEmailTaken):
+			writeError(w, http.StatusConflict, "email_taken", "email already registered")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("auth.register: register failed", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toSessionResponse(account, pair))
+}
+
+func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var req loginRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	account, pair, err := h.Auth.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrInvalidCredentials) {
+			commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventLoginFailed, func(input *auditdomain.Input) {
+				input.Details = map[string]string{"email": strings.ToLower(strings.TrimSpace(req.Email))}
+			})
+			writeError(w, http.StatusUnauthorized, "invalid_credentials", "invalid email or password")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("auth.login: login failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventLoginSucceeded, func(input *auditdomain.Input) {
+		input.ActorID = account.ID
+	})
+
+	writeJSON(w, http.StatusOK, toSessionResponse(account, pair))
+}
+
+func (h *Handlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var req refreshRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	token := strings.TrimSpace(req.RefreshToken)
+
+	account, pair, err := h.Auth.Refresh(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrInvalidRefreshToken) {
+			writeError(w, http.StatusUnauthorized, "invalid_refresh_token", "invalid refresh token")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("auth.refresh: refresh failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSessionResponse(account, pair))
+}
+
+func (h *Handlers) Revoke(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+
+	var req refreshRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	token := strings.TrimSpace(req.RefreshToken)
+
+	if err := h.Auth.Revoke(r.Context(), token); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("auth.revoke: revoke failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) enabled(w http.ResponseWriter) bool {
+	if h.Auth == nil || !h.Auth.Configured() {
+		writeError(w, http.StatusNotFound, "auth_provider_disabled", "self-hosted auth is disabled")
+		return false
+	}
+	return true
+}
+
+func toSessionResponse(account *authdomain.Account, pair *authdomain.TokenPair) sessionResponse {
+	expiresIn := int64(time.Until(pair.ExpiresAt).Seconds())
+	if expiresIn < 0 {
+		expiresIn = 0
+	}
+	return sessionResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    pair.TokenType,
+		ExpiresAt:    pair.ExpiresAt,
+		ExpiresIn:    expiresIn,
+		User: sessionUserResponse{
+			ID:    account.ID,
+			Email: account.Email,
+			Name:  account.Name,
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/pkg/logger"
+)
+
+// Handlers serve the self-hosted auth endpoints. Auth is nil when another
+// provider issues tokens.
+type Handlers struct {
+	Auth  AuthService
+	Audit commonhandler.AuditRecorder
+	log   logger.Logger
+}
+
+func New(auth AuthService, audit commonhandler.AuditRecorder, log logger.Logger) *Handlers {
+	return &Handlers{
+		Auth:  auth,
+		Audit: audit,
+		log:   log,
+	}
+}
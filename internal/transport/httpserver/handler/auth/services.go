@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"context"
+
+	authdomain "family-app-go/internal/domain/auth"
+)
+
+// AuthService is implemented by *authdomain.Service.
+type AuthService interface {
+	Configured() bool
+	Login(ctx context.Context, email, password string) (*authdomain.Account, *authdomain.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*authdomain.Account, *authdomain.TokenPair, error)
+	Register(ctx context.Context, input authdomain.RegisterInput) (*authdomain.Account, *authdomain.TokenPair, error)
+	Revoke(ctx context.Context, refreshToken string) error
+}
@@ -0,0 +1,17 @@
+package auth
+
+import "family-app-go/internal/transport/httpserver/validation"
+
+func (req registerRequest) Validate(v *validation.Validator) {
+	v.Required("email", req.Email)
+	v.Required("password", req.Password)
+}
+
+func (req loginRequest) Validate(v *validation.Validator) {
+	v.Required("email", req.Email)
+	v.Required("password", req.Password)
+}
+
+func (req refreshRequest) Validate(v *validation.Validator) {
+	v.Required("refresh_token", req.RefreshToken)
+}
@@ -0,0 +1,251 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	batchdomain "family-app-go/internal/domain/batch"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+type refRequest struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+func (req refRequest) Validate(v *validation.Validator) {
+	v.Required("type", req.Type)
+	v.OneOf("type", req.Type, string(batchdomain.EntityExpense), string(batchdomain.EntityTodoItem), string(batchdomain.EntityCategory), string(batchdomain.EntityWorkout))
+	v.Required("id", req.ID)
+	v.UUID("id", req.ID)
+}
+
+type getRequest struct {
+	Items []refRequest `json:"items"`
+}
+
+func (req getRequest) Validate(v *validation.Validator) {
+	v.Check(len(req.Items) > 0, "items", validation.CodeRequired, "items is required")
+	v.Check(len(req.Items) <= batchdomain.MaxRefs, "items", validation.CodeInvalid, fmt.Sprintf("at most %d items", batchdomain.MaxRefs))
+	for i, item := range req.Items {
+		v.Nested(fmt.Sprintf("items[%d]", i), item)
+	}
+}
+
+type expenseSnapshotResponse struct {
+	ID           string    `json:"id"`
+	FamilyID     string    `json:"family_id"`
+	UserID       string    `json:"user_id"`
+	Date         string    `json:"date"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	AmountInBase *float64  `json:"amount_in_base,omitempty"`
+	Title        string    `json:"title"`
+	CategoryIDs  []string  `json:"category_ids"`
+	IsArchived   bool      `json:"is_archived"`
+	Version      int64     `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type categorySnapshotResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     *string   `json:"color"`
+	Emoji     *string   `json:"emoji"`
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type todoItemSnapshotResponse struct {
+	ID             string     `json:"id"`
+	ListID         string     `json:"list_id"`
+	Title          string     `json:"title"`
+	IsCompleted    bool       `json:"is_completed"`
+	IsArchived     bool       `json:"is_archived"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	DueDate        *string    `json:"due_date"`
+	AssigneeID     *string    `json:"assignee_id"`
+	Labels         []string   `json:"labels"`
+	Version        int64      `json:"version"`
+	EstimatedPrice *float64   `json:"estimated_price"`
+	ExpenseID      *string    `json:"expense_id"`
+}
+
+type workoutSetSnapshotResponse struct {
+	ID       string  `json:"id"`
+	Exercise string  `json:"exercise"`
+	WeightKg float64 `json:"weight_kg"`
+	Reps     int     `json:"reps"`
+}
+
+type workoutSnapshotResponse struct {
+	ID         string                       `json:"id"`
+	UserID     string                       `json:"user_id"`
+	Date       string                       `json:"date"`
+	Name       string                       `json:"name"`
+	Visibility string                       `json:"visibility"`
+	Sets       []workoutSetSnapshotResponse `json:"sets"`
+	Labels     []string                     `json:"labels"`
+	CreatedAt  time.Time                    `json:"created_at"`
+	UpdatedAt  time.Time                    `json:"updated_at"`
+}
+
+type snapshotResponse struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// Data holds the record in the shape of its type, only when found.
+	Data interface{} `json:"data"`
+}
+
+type getResponse struct {
+	Items []snapshotResponse `json:"items"`
+}
+
+// GetSnapshots returns the current state of the requested records in one
+// response, for clients reconciling after change events. Each item reports
+// whether the record was found; missing or hidden ones do not fail the rest.
+func (h *Handlers) GetSnapshots(w http.ResponseWriter, r *http.Request) {
+	var req getRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	refs := make([]batchdomain.Ref, 0, len(req.Items))
+	for _, item := range req.Items {
+		refs = append(refs, batchdomain.Ref{Type: batchdomain.EntityType(item.Type), ID: item.ID})
+	}
+
+	snapshots, err := h.Batch.Get(r.Context(), batchdomain.Query{
+		FamilyID: family.ID,
+		UserID:   user.ID,
+		Child:    middleware.IsChild(r.Context()),
+		Refs:     refs,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, batchdomain.ErrInvalidRef):
+			writeValidationError(w, validation.FieldErr("items", validation.CodeInvalid, "items must name a type and an id"))
+		case errors.Is(err, batchdomain.ErrTooManyRefs):
+			writeValidationError(w, validation.FieldErr("items", validation.CodeInvalid, fmt.Sprintf("at most %d items", batchdomain.MaxRefs)))
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("batch.get: get snapshots failed", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	response := getResponse{Items: make([]snapshotResponse, 0, len(snapshots))}
+	for _, snapshot := range snapshots {
+		response.Items = append(response.Items, toSnapshotResponse(snapshot))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func toSnapshotResponse(snapshot batchdomain.Snapshot) snapshotResponse {
+	response := snapshotResponse{
+		Type:   string(snapshot.Ref.Type),
+		ID:     snapshot.Ref.ID,
+		Status: string(snapshot.Status),
+	}
+	labels := snapshot.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	switch {
+	case snapshot.Expense != nil:
+		expense := snapshot.Expense
+		categoryIDs := expense.CategoryIDs
+		if categoryIDs == nil {
+			categoryIDs = []string{}
+		}
+		response.Data = expenseSnapshotResponse{
+			ID:           expense.ID,
+			FamilyID:     expense.FamilyID,
+			UserID:       expense.UserID,
+			Date:         expense.Date.Format("2006-01-02"),
+			Amount:       expense.Amount,
+			Currency:     expense.Currency,
+			AmountInBase: expense.AmountInBase,
+			Title:        expense.Title,
+			CategoryIDs:  categoryIDs,
+			IsArchived:   expense.IsArchived,
+			Version:      expense.Version,
+			CreatedAt:    expense.CreatedAt,
+			UpdatedAt:    expense.UpdatedAt,
+		}
+	case snapshot.Category != nil:
+		category := snapshot.Category
+		response.Data = categorySnapshotResponse{
+			ID:        category.ID,
+			Name:      category.Name,
+			Color:     category.Color,
+			Emoji:     category.Emoji,
+			Version:   category.Version,
+			CreatedAt: category.CreatedAt,
+		}
+	case snapshot.TodoItem != nil:
+		item := snapshot.TodoItem
+		var dueDate *string
+		if item.DueDate != nil {
+			value := item.DueDate.Format("2006-01-02")
+			dueDate = &value
+		}
+		response.Data = todoItemSnapshotResponse{
+			ID:             item.ID,
+			ListID:         item.ListID,
+			Title:          item.Title,
+			IsCompleted:    item.IsCompleted,
+			IsArchived:     item.IsArchived,
+			CreatedAt:      item.CreatedAt,
+			CompletedAt:    item.CompletedAt,
+			DueDate:        dueDate,
+			AssigneeID:     item.AssigneeID,
+			Labels:         labels,
+			Version:        item.Version,
+			EstimatedPrice: item.EstimatedPrice,
+			ExpenseID:      item.ExpenseID,
+		}
+	case snapshot.Workout != nil:
+		workout := snapshot.Workout
+		sets := make([]workoutSetSnapshotResponse, 0, len(workout.Sets))
+		for _, set := range workout.Sets {
+			sets = append(sets, workoutSetSnapshotResponse{
+				ID:       set.ID,
+				Exercise: set.Exercise,
+				WeightKg: set.WeightKg,
+				Reps:     set.Reps,
+			})
+		}
+		response.Data = workoutSnapshotResponse{
+			ID:         workout.ID,
+			UserID:     workout.UserID,
+			Date:       workout.Date.Format("2006-01-02"),
+			Name:       workout.Name,
+			Visibility: string(workout.Visibility),
+			Sets:       sets,
+			Labels:     labels,
+			CreatedAt:  workout.CreatedAt,
+			UpdatedAt:  workout.UpdatedAt,
+		}
+	}
+	return response
+}
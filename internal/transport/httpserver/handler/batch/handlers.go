@@ -0,0 +1,17 @@
+package batch
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Batch BatchService
+	log   logger.Logger
+}
+
+func New(batch BatchService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Batch: batch,
+		log:   log,
+	}
+}
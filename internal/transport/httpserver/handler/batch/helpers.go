@@ -0,0 +1,28 @@
+package batch
+
+import (
+	"net/http"
+
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+)
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	commonhandler.WriteError(w, status, code, message)
+}
+
+func requireFamily(w http.ResponseWriter, r *http.Request) (*familydomain.Family, bool) {
+	return commonhandler.RequireFamily(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	commonhandler.WriteJSON(w, status, payload)
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return commonhandler.DecodeRequest(w, r, dst)
+}
+
+func writeValidationError(w http.ResponseWriter, err error) {
+	commonhandler.WriteValidationError(w, err)
+}
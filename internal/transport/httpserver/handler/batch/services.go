@@ -0,0 +1,12 @@
+package batch
+
+import (
+	"context"
+
+	batchdomain "family-app-go/internal/domain/batch"
+)
+
+// BatchService is implemented by *batchdomain.Service.
+type BatchService interface {
+	Get(ctx context.Context, query batchdomain.Query) ([]batchdomain.Snapshot, error)
+}
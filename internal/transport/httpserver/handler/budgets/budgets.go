@@ -0,0 +1,217 @@
+package budgets
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	budgetsdomain "family-app-go/internal/domain/budgets"
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type createBudgetRequest struct {
+	CategoryID string  `json:"category_id"`
+	Amount     float64 `json:"amount"`
+	Currency   *string `json:"currency"`
+}
+
+type updateBudgetRequest struct {
+	Amount   float64 `json:"amount"`
+	Currency *string `json:"currency"`
+}
+
+type budgetResponse struct {
+	ID           string    `json:"id"`
+	CategoryID   string    `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	Month        string    `json:"month"`
+	Spent        float64   `json:"spent"`
+	Share        float64   `json:"share"`
+	Reached      int       `json:"reached"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type budgetListResponse struct {
+	Items []budgetResponse `json:"items"`
+}
+
+// ListBudgets returns the family's monthly budgets with what was spent on
+// each this month.
+func (h *Handlers) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	user, family, ok := h.currentUserFamily(w, r)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.Budgets.List(r.Context(), family.ID)
+	if err != nil {
+		h.writeBudgetError(w, r, "budgets.list", err, "user_id", user.ID, "family_id", family.ID)
+		return
+	}
+
+	response := budgetListResponse{Items: make([]budgetResponse, 0, len(statuses))}
+	for _, status := range statuses {
+		response.Items = append(response.Items, toBudgetResponse(status))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) GetBudget(w http.ResponseWriter, r *http.Request) {
+	user, family, budgetID, ok := h.budgetFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.Budgets.Get(r.Context(), family.ID, budgetID)
+	if err != nil {
+		h.writeBudgetError(w, r, "budgets.get", err, "user_id", user.ID, "family_id", family.ID, "budget_id", budgetID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toBudgetResponse(*status))
+}
+
+// CreateBudget sets a monthly budget for a category, in the family currency
+// unless currency is given. A category has at most one budget.
+func (h *Handlers) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	var req createBudgetRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r)
+	if !ok {
+		return
+	}
+
+	currency := family.DefaultCurrency
+	if req.Currency != nil && strings.TrimSpace(*req.Currency) != "" {
+		currency = *req.Currency
+	}
+	status, err := h.Budgets.Create(r.Context(), family.ID, user.ID, budgetsdomain.Input{
+		CategoryID: req.CategoryID,
+		Amount:     req.Amount,
+		Currency:   currency,
+	})
+	if err != nil {
+		h.writeBudgetError(w, r, "budgets.create", err, "user_id", user.ID, "family_id", family.ID, "category_id", req.CategoryID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toBudgetResponse(*status))
+}
+
+// UpdateBudget changes a budget's amount; leaving out currency keeps the
+// current one.
+func (h *Handlers) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	var req updateBudgetRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, budgetID, ok := h.budgetFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	input := budgetsdomain.Input{Amount: req.Amount}
+	if req.Currency != nil {
+		input.Currency = *req.Currency
+	}
+	status, err := h.Budgets.Update(r.Context(), family.ID, budgetID, input)
+	if err != nil {
+		h.writeBudgetError(w, r, "budgets.update", err, "user_id", user.ID, "family_id", family.ID, "budget_id", budgetID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toBudgetResponse(*status))
+}
+
+func (h *Handlers) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	user, family, budgetID, ok := h.budgetFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Budgets.Delete(r.Context(), family.ID, budgetID); err != nil {
+		h.writeBudgetError(w, r, "budgets.delete", err, "user_id", user.ID, "family_id", family.ID, "budget_id", budgetID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) currentUserFamily(w http.ResponseWriter, r *http.Request) (middleware.User, *familydomain.Family, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, false
+	}
+	return user, family, true
+}
+
+func (h *Handlers) budgetFromPath(w http.ResponseWriter, r *http.Request) (middleware.User, *familydomain.Family, string, bool) {
+	budgetID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if budgetID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return middleware.User{}, nil, "", false
+	}
+
+	user, family, ok := h.currentUserFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, "", false
+	}
+	return user, family, budgetID, true
+}
+
+func (h *Handlers) writeBudgetError(w http.ResponseWriter, r *http.Request, operation string, err error, args ...any) {
+	switch {
+	case errors.Is(err, budgetsdomain.ErrBudgetNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": budget not found", err, args...)
+		writeError(w, http.StatusNotFound, "budget_not_found", "budget not found")
+	case errors.Is(err, budgetsdomain.ErrCategoryNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": category not found", err, args...)
+		writeError(w, http.StatusNotFound, "category_not_found", "category not found")
+	case errors.Is(err, budgetsdomain.ErrBudgetExists):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": budget exists", err, args...)
+		writeError(w, http.StatusConflict, "budget_exists", "category already has a budget")
+	case errors.Is(err, budgetsdomain.ErrInvalidAmount):
+		writeValidationError(w, validation.FieldErr("amount", validation.CodeInvalid, "amount must be positive and fit the budget's currency"))
+	case errors.Is(err, budgetsdomain.ErrInvalidCurrency):
+		writeValidationError(w, validation.FieldErr("currency", validation.CodeCurrency, "currency must be an ISO 4217 currency code"))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": failed", err, args...)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+func toBudgetResponse(status budgetsdomain.Status) budgetResponse {
+	return budgetResponse{
+		ID:           status.ID,
+		CategoryID:   status.CategoryID,
+		CategoryName: status.CategoryName,
+		Amount:       status.Amount,
+		Currency:     status.Currency,
+		Month:        status.PeriodStart.Format("2006-01"),
+		Spent:        status.Spent,
+		Share:        status.Share,
+		Reached:      status.Reached(),
+		CreatedBy:    status.CreatedBy,
+		CreatedAt:    status.CreatedAt,
+		UpdatedAt:    status.UpdatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package budgets
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Budgets BudgetsService
+	log     logger.Logger
+}
+
+func New(budgets BudgetsService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Budgets: budgets,
+		log:     log,
+	}
+}
@@ -0,0 +1,16 @@
+package budgets
+
+import (
+	"context"
+
+	budgetsdomain "family-app-go/internal/domain/budgets"
+)
+
+// BudgetsService is implemented by *budgetsdomain.Service.
+type BudgetsService interface {
+	Create(ctx context.Context, familyID, createdBy string, input budgetsdomain.Input) (*budgetsdomain.Status, error)
+	Delete(ctx context.Context, familyID, budgetID string) error
+	Get(ctx context.Context, familyID, budgetID string) (*budgetsdomain.Status, error)
+	List(ctx context.Context, familyID string) ([]budgetsdomain.Status, error)
+	Update(ctx context.Context, familyID, budgetID string, input budgetsdomain.Input) (*budgetsdomain.Status, error)
+}
@@ -0,0 +1,25 @@
+package budgets
+
+import (
+	"strings"
+
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req createBudgetRequest) Validate(v *validation.Validator) {
+	v.Required("category_id", req.CategoryID)
+	v.UUID("category_id", req.CategoryID)
+	validateAmount(v, req.Amount, req.Currency)
+}
+
+func (req updateBudgetRequest) Validate(v *validation.Validator) {
+	validateAmount(v, req.Amount, req.Currency)
+}
+
+func validateAmount(v *validation.Validator, amount float64, currency *string) {
+	v.Positive("amount", amount)
+	if currency != nil && strings.TrimSpace(*currency) != "" {
+		v.Currency("currency", *currency)
+		v.Precision("amount", amount, *currency)
+	}
+}
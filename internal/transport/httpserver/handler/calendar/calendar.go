@@ -0,0 +1,107 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	calendardomain "family-app-go/internal/domain/calendar"
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+const feedPath = "/api/calendar/feed.ics"
+
+type feedURLResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+func (h *Handlers) GetFeedURL(w http.ResponseWriter, r *http.Request) {
+	h.writeFeedURL(w, r, "calendar.feed_url", h.Calendar.IssueFeedToken)
+}
+
+// RotateFeedURL replaces the caller's feed token; subscriptions made with
+// the old URL stop updating.
+func (h *Handlers) RotateFeedURL(w http.ResponseWriter, r *http.Request) {
+	h.writeFeedURL(w, r, "calendar.rotate_feed_url", h.Calendar.RotateFeedToken)
+}
+
+func (h *Handlers) writeFeedURL(w http.ResponseWriter, r *http.Request, op string, issue func(ctx context.Context, userID string) (string, error)) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	token, err := issue(r.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, calendardomain.ErrFeedDisabled) {
+			writeError(w, http.StatusNotFound, "calendar_feed_disabled", "calendar feed is disabled")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError(op+": issue token failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, feedURLResponse{
+		Token: token,
+		URL:   feedURL(r, token),
+	})
+}
+
+// Feed serves the iCalendar feed. It is mounted outside of the auth group:
+// calendar apps cannot send bearer tokens, so the signed token in the query
+// string is the only credential.
+func (h *Handlers) Feed(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSpace(r.URL.Query().Get("token"))
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "token is required")
+		return
+	}
+
+	events, err := h.Calendar.FeedEvents(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, calendardomain.ErrFeedDisabled):
+			writeError(w, http.StatusNotFound, "calendar_feed_disabled", "calendar feed is disabled")
+		case errors.Is(err, calendardomain.ErrInvalidFeedToken):
+			writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("calendar.feed: load events failed", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="family.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(renderICS(events, time.Now().UTC())))
+}
+
+func feedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")); forwarded != "" {
+		scheme = forwarded
+	}
+
+	target := url.URL{
+		Scheme:   scheme,
+		Host:     r.Host,
+		Path:     feedPath,
+		RawQuery: url.Values{"token": []string{token}}.Encode(),
+	}
+	return target.String()
+}
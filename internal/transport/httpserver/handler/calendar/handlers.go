@@ -0,0 +1,17 @@
+package calendar
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Calendar CalendarService
+	log      logger.Logger
+}
+
+func New(calendar CalendarService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Calendar: calendar,
+		log:      log,
+	}
+}
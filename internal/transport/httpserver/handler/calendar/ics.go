@@ -0,0 +1,80 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+
+	calendardomain "family-app-go/internal/domain/calendar"
+)
+
+const (
+	icsDateFormat     = "20060102"
+	icsDateTimeFormat = "20060102T150405Z"
+	icsMaxLineOctets  = 75
+)
+
+func renderICS(events []calendardomain.Event, now time.Time) string {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//family-app//calendar feed//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText("Family"))
+
+	stamp := now.UTC().Format(icsDateTimeFormat)
+	for _, event := range events {
+		start := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 0, 0, 0, 0, time.UTC)
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(event.UID)+"@family-app")
+		writeLine(&b, "DTSTAMP:"+stamp)
+		if !event.CreatedAt.IsZero() {
+			writeLine(&b, "CREATED:"+event.CreatedAt.UTC().Format(icsDateTimeFormat))
+		}
+		writeLine(&b, "DTSTART;VALUE=DATE:"+start.Format(icsDateFormat))
+		writeLine(&b, "DTEND;VALUE=DATE:"+start.AddDate(0, 0, 1).Format(icsDateFormat))
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// writeLine folds content lines longer than 75 octets as required by RFC 5545,
+// never splitting a multi-byte rune.
+func writeLine(b *strings.Builder, line string) {
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(value byte) bool {
+	return value&0xC0 != 0x80
+}
+
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
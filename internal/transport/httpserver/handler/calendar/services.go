@@ -0,0 +1,14 @@
+package calendar
+
+import (
+	"context"
+
+	calendardomain "family-app-go/internal/domain/calendar"
+)
+
+// CalendarService is implemented by *calendardomain.Service.
+type CalendarService interface {
+	FeedEvents(ctx context.Context, token string) ([]calendardomain.Event, error)
+	IssueFeedToken(ctx context.Context, userID string) (string, error)
+	RotateFeedToken(ctx context.Context, userID string) (string, error)
+}
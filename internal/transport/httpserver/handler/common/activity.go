@@ -0,0 +1,114 @@
+package common
+
+import (
+	"net/http"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+type activityActorResponse struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Email     string  `json:"email"`
+	AvatarURL *string `json:"avatar_url"`
+}
+
+type activityEntityResponse struct {
+	Type     string  `json:"type"`
+	ID       string  `json:"id"`
+	ParentID *string `json:"parent_id"`
+	Link     string  `json:"link"`
+}
+
+type activityEventResponse struct {
+	ID        string                 `json:"id"`
+	Action    string                 `json:"action"`
+	Summary   string                 `json:"summary"`
+	Actor     activityActorResponse  `json:"actor"`
+	Entity    activityEntityResponse `json:"entity"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type activityListResponse struct {
+	Items []activityEventResponse `json:"items"`
+	Total int64                   `json:"total"`
+}
+
+// ActorFromUser snapshots the authenticated user for the activity feed.
+func ActorFromUser(user middleware.User) activitydomain.Actor {
+	return activitydomain.Actor{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
+// ListFamilyActivity returns the family's recent actions, newest first.
+func (h *Handlers) ListFamilyActivity(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), activitydomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > activitydomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "limit must be between 1 and 100")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	events, total, err := h.Activity.ListEvents(r.Context(), family.ID, activitydomain.ListFilter{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		RequestLog(r, h.log).InternalError("families.activity: list events failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	items := make([]activityEventResponse, 0, len(events))
+	for _, event := range events {
+		items = append(items, toActivityEventResponse(event))
+	}
+
+	writeJSON(w, http.StatusOK, activityListResponse{
+		Items: items,
+		Total: total,
+	})
+}
+
+func toActivityEventResponse(event activitydomain.Event) activityEventResponse {
+	return activityEventResponse{
+		ID:      event.ID,
+		Action:  event.Action,
+		Summary: event.Summary,
+		Actor: activityActorResponse{
+			ID:        event.ActorID,
+			Name:      event.ActorName,
+			Email:     event.ActorEmail,
+			AvatarURL: event.ActorAvatarURL,
+		},
+		Entity: activityEntityResponse{
+			Type:     event.EntityType,
+			ID:       event.EntityID,
+			ParentID: event.EntityParentID,
+			Link:     event.Link(),
+		},
+		CreatedAt: event.CreatedAt,
+	}
+}
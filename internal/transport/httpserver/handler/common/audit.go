@@ -0,0 +1,31 @@
+package common
+
+import (
+	"context"
+	"net/http"
+
+	auditdomain "family-app-go/internal/domain/audit"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+)
+
+// AuditRecorder is the part of *auditdomain.Service that records security
+// events.
+type AuditRecorder interface {
+	Record(ctx context.Context, input auditdomain.Input) error
+}
+
+// RecordSecurityEvent stores a security audit event for the request. A
+// failure is logged and never fails the request.
+func RecordSecurityEvent(r *http.Request, audit AuditRecorder, log logger.Logger, eventType string, fill func(*auditdomain.Input)) {
+	if audit == nil {
+		return
+	}
+	input := middleware.AuditInput(r, eventType)
+	if fill != nil {
+		fill(&input)
+	}
+	if err := audit.Record(r.Context(), input); err != nil {
+		logger.FromContext(r.Context(), log).InternalError("audit: record security event failed", err, "type", eventType)
+	}
+}
@@ -1,16 +1,46 @@
 package common
 
 import (
+	"errors"
 	"net/http"
 
+	userdomain "family-app-go/internal/domain/user"
 	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
 )
 
 type authMeResponse struct {
-	ID        string `json:"id"`
-	Email     string `json:"email"`
-	Name      string `json:"name"`
-	AvatarURL string `json:"avatar_url"`
+	ID          string              `json:"id"`
+	Email       string              `json:"email"`
+	Name        string              `json:"name"`
+	AvatarURL   string              `json:"avatar_url"`
+	Preferences preferencesResponse `json:"preferences"`
+}
+
+type notificationSettingsResponse struct {
+	TodoReminders  bool `json:"todo_reminders"`
+	GymNudges      bool `json:"gym_nudges"`
+	FamilyActivity bool `json:"family_activity"`
+}
+
+type preferencesResponse struct {
+	Theme         string                       `json:"theme"`
+	Language      string                       `json:"language"`
+	WeightUnit    string                       `json:"weight_unit"`
+	Notifications notificationSettingsResponse `json:"notifications"`
+}
+
+type updateNotificationSettingsRequest struct {
+	TodoReminders  *bool `json:"todo_reminders"`
+	GymNudges      *bool `json:"gym_nudges"`
+	FamilyActivity *bool `json:"family_activity"`
+}
+
+type updatePreferencesRequest struct {
+	Theme         *string                            `json:"theme"`
+	Language      *string                            `json:"language"`
+	WeightUnit    *string                            `json:"weight_unit"`
+	Notifications *updateNotificationSettingsRequest `json:"notifications"`
 }
 
 func (h *Handlers) AuthMe(w http.ResponseWriter, r *http.Request) {
@@ -20,10 +50,92 @@ func (h *Handlers) AuthMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	prefs, err := h.Users.GetPreferences(r.Context(), user.ID)
+	if err != nil {
+		RequestLog(r, h.log).InternalError("auth.me: get preferences failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
 	writeJSON(w, http.StatusOK, authMeResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		AvatarURL: user.AvatarURL,
+		ID:          user.ID,
+		Email:       user.Email,
+		Name:        user.Name,
+		AvatarURL:   user.AvatarURL,
+		Preferences: toPreferencesResponse(prefs),
 	})
 }
+
+func (h *Handlers) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	prefs, err := h.Users.GetPreferences(r.Context(), user.ID)
+	if err != nil {
+		RequestLog(r, h.log).InternalError("preferences.get: get preferences failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPreferencesResponse(prefs))
+}
+
+func (h *Handlers) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req updatePreferencesRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	update := userdomain.PreferencesUpdate{
+		Theme:      req.Theme,
+		Language:   req.Language,
+		WeightUnit: req.WeightUnit,
+	}
+	if req.Notifications != nil {
+		update.NotifyTodoReminders = req.Notifications.TodoReminders
+		update.NotifyGymNudges = req.Notifications.GymNudges
+		update.NotifyFamilyActivity = req.Notifications.FamilyActivity
+	}
+
+	prefs, err := h.Users.UpdatePreferences(r.Context(), user.ID, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, userdomain.ErrNoFieldsToUpdate):
+			writeValidationError(w, validation.FieldErr("", validation.CodeEmpty, "no fields to update"))
+		case errors.Is(err, userdomain.ErrInvalidTheme):
+			writeValidationError(w, validation.FieldErr("theme", validation.CodeEnum, "theme must be system, light or dark"))
+		case errors.Is(err, userdomain.ErrInvalidLanguage):
+			writeValidationError(w, validation.FieldErr("language", validation.CodeFormat, "language must be a code like en or pt-BR"))
+		case errors.Is(err, userdomain.ErrInvalidWeightUnit):
+			writeValidationError(w, validation.FieldErr("weight_unit", validation.CodeEnum, "weight_unit must be kg or lb"))
+		default:
+			RequestLog(r, h.log).InternalError("preferences.update: update preferences failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPreferencesResponse(prefs))
+}
+
+func toPreferencesResponse(prefs userdomain.Preferences) preferencesResponse {
+	return preferencesResponse{
+		Theme:      prefs.Theme,
+		Language:   prefs.Language,
+		WeightUnit: prefs.WeightUnit,
+		Notifications: notificationSettingsResponse{
+			TodoReminders:  prefs.Notifications.TodoReminders,
+			GymNudges:      prefs.Notifications.GymNudges,
+			FamilyActivity: prefs.Notifications.FamilyActivity,
+		},
+	}
+}
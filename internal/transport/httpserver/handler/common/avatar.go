@@ -0,0 +1,113 @@
+package common
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	userdomain "family-app-go/internal/domain/user"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+const avatarsPath = "/api/avatars"
+
+type avatarResponse struct {
+	AvatarURL string `json:"avatar_url"`
+}
+
+// UploadAvatar accepts a multipart "avatar" image. The stored avatar replaces
+// the provider one for /auth/me and for snapshots taken from now on.
+func (h *Handlers) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, userdomain.MaxAvatarBytes+1024*1024)
+	if err := r.ParseMultipartForm(userdomain.MaxAvatarBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "avatar_too_large", "avatar must be at most 5 MB")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_avatar", "avatar image is required")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("avatar")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_avatar", "avatar image is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, userdomain.MaxAvatarBytes+1))
+	if err != nil {
+		RequestLog(r, h.log).InternalError("avatar.upload: read file failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	profile, err := h.Users.UploadAvatar(r.Context(), user.ID, data, avatarsBaseURL(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, userdomain.ErrInvalidAvatar):
+			RequestLog(r, h.log).BusinessError("avatar.upload: invalid image", err, "user_id", user.ID)
+			writeError(w, http.StatusBadRequest, "invalid_avatar", "avatar must be a JPEG, PNG or GIF image")
+		case errors.Is(err, userdomain.ErrAvatarTooLarge):
+			RequestLog(r, h.log).BusinessError("avatar.upload: image too large", err, "user_id", user.ID)
+			writeError(w, http.StatusRequestEntityTooLarge, "avatar_too_large", "avatar must be at most 5 MB")
+		default:
+			RequestLog(r, h.log).InternalError("avatar.upload: upload avatar failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, avatarResponse{AvatarURL: profile.EffectiveAvatarURL()})
+}
+
+// GetAvatar serves uploaded avatars. It is mounted outside of the auth group
+// so avatar URLs work in plain image tags; file names are random.
+func (h *Handlers) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	userID := strings.TrimSpace(chi.URLParam(r, "user_id"))
+	file := strings.TrimSpace(chi.URLParam(r, "file"))
+
+	data, err := h.Users.LoadAvatar(r.Context(), userID, file)
+	if err != nil {
+		if errors.Is(err, userdomain.ErrAvatarNotFound) {
+			writeError(w, http.StatusNotFound, "avatar_not_found", "avatar not found")
+			return
+		}
+		RequestLog(r, h.log).InternalError("avatar.get: load avatar failed", err, "user_id", userID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func avatarsBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")); forwarded != "" {
+		scheme = forwarded
+	}
+
+	target := url.URL{
+		Scheme: scheme,
+		Host:   r.Host,
+		Path:   avatarsPath,
+	}
+	return target.String()
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	syncdomain "family-app-go/internal/domain/sync"
+	"family-app-go/pkg/familytest"
+)
+
+// The contract tests snapshot the JSON the mobile clients read. The
+// fixtures set every field, so that omitempty fields are in the golden
+// files too.
+
+func TestSyncBatchContract(t *testing.T) {
+	entity := syncdomain.EntityTodoItem
+	localID, serverID := "todo-1", testTodoID
+	failedLocalID := "todo-2"
+	serverTime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		target string
+		batch  syncdomain.BatchResponse
+	}{
+		{
+			name:   "sync_batch",
+			target: "/api/sync/batch",
+			batch: syncdomain.BatchResponse{
+				SyncID:  "sync-1",
+				Status:  syncdomain.BatchStatusPartialSuccess,
+				Summary: syncdomain.BatchSummary{Total: 2, Applied: 1, Failed: 1},
+				Results: []syncdomain.OperationResult{
+					{OperationID: testOperationID, Type: syncdomain.OperationTypeCreateTodo, Status: syncdomain.ResultStatusApplied, LocalID: &localID, Entity: &entity, ServerID: &serverID},
+					{
+						OperationID: "66666666-6666-6666-6666-666666666666",
+						Type:        syncdomain.OperationTypeCreateTodo,
+						Status:      syncdomain.ResultStatusFailed,
+						LocalID:     &failedLocalID,
+						Error:       &syncdomain.OperationError{Code: syncdomain.ErrorCodeTodoListNotFound, Message: "todo list not found"},
+					},
+				},
+				Mappings:   []syncdomain.EntityMapping{{Entity: entity, LocalID: localID, ServerID: serverID}},
+				ServerTime: serverTime,
+			},
+		},
+		{
+			name:   "sync_batch_validate",
+			target: "/api/sync/batch?validate=true",
+			batch: syncdomain.BatchResponse{
+				DryRun:     true,
+				Status:     syncdomain.BatchStatusSuccess,
+				Summary:    syncdomain.BatchSummary{Total: 1, Valid: 1},
+				Results:    []syncdomain.OperationResult{{OperationID: testOperationID, Type: syncdomain.OperationTypeCreateTodo, Status: syncdomain.ResultStatusValid, LocalID: &localID, Entity: &entity}},
+				Mappings:   []syncdomain.EntityMapping{},
+				ServerTime: serverTime,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.sync.batch = &tc.batch
+			body := syncBody(syncOperation(testOperationID, "create_todo", `{"list_id":"list-1","title":"Milk"}`))
+			rec := httptest.NewRecorder()
+			deps.handlers().SyncBatch(rec, familyRequest(http.MethodPost, tc.target, body))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			familytest.Golden(t, tc.name, rec.Body.Bytes())
+		})
+	}
+}
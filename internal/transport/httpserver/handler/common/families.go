@@ -2,13 +2,16 @@ package common
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"family-app-go/internal/devseed"
+	auditdomain "family-app-go/internal/domain/audit"
 	familydomain "family-app-go/internal/domain/family"
 	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -17,47 +20,38 @@ type createFamilyRequest struct {
 }
 
 type joinFamilyRequest struct {
-	Code string `json:"code"`
+	Token string `json:"token"`
+}
+
+type setSpendingLimitRequest struct {
+	Amount *float64 `json:"amount"`
+}
+
+type updateFamilyMemberRequest struct {
+	Role string `json:"role"`
 }
 
 type updateFamilyRequest struct {
-	Name            *string `json:"name"`
-	DefaultCurrency *string `json:"default_currency"`
+	Name              *string   `json:"name"`
+	DefaultCurrency   *string   `json:"default_currency"`
+	AllowedCurrencies *[]string `json:"allowed_currencies"`
 }
 
 func (h *Handlers) GetFamilyMe(w http.ResponseWriter, r *http.Request) {
-	user, ok := middleware.UserFromContext(r.Context())
+	family, ok := requireFamily(w, r)
 	if !ok {
-		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
 		return
 	}
 
-	result, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("families.get_me: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("families.get_me: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
-		return
-	}
-
-	writeJSON(w, http.StatusOK, toFamilyResponse(result))
+	writeJSON(w, http.StatusOK, toFamilyResponse(family))
 }
 
 func (h *Handlers) CreateFamily(w http.ResponseWriter, r *http.Request) {
 	var req createFamilyRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 	req.Name = strings.TrimSpace(req.Name)
-	if req.Name == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
-		return
-	}
 
 	user, ok := middleware.UserFromContext(r.Context())
 	if !ok {
@@ -69,10 +63,10 @@ func (h *Handlers) CreateFamily(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch {
 		case errors.Is(err, familydomain.ErrAlreadyInFamily):
-			h.log.BusinessError("families.create: user already in family", err, "user_id", user.ID)
+			RequestLog(r, h.log).BusinessError("families.create: user already in family", err, "user_id", user.ID)
 			writeError(w, http.StatusConflict, "already_in_family", "already in family")
 		default:
-			h.log.InternalError("families.create: create family failed", err, "user_id", user.ID)
+			RequestLog(r, h.log).InternalError("families.create: create family failed", err, "user_id", user.ID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
@@ -84,9 +78,9 @@ func (h *Handlers) CreateFamily(w http.ResponseWriter, r *http.Request) {
 			UserID:   user.ID,
 		})
 		if err != nil {
-			h.log.InternalError("families.create: seed mock data failed", err, "user_id", user.ID, "family_id", result.ID)
+			RequestLog(r, h.log).InternalError("families.create: seed mock data failed", err, "user_id", user.ID, "family_id", result.ID)
 		} else if seedResult.CategoriesCreated > 0 || seedResult.ExpensesCreated > 0 {
-			h.log.Info(
+			RequestLog(r, h.log).Info(
 				"families.create: seeded mock data",
 				"user_id", user.ID,
 				"family_id", result.ID,
@@ -103,15 +97,10 @@ func (h *Handlers) CreateFamily(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) JoinFamily(w http.ResponseWriter, r *http.Request) {
 	var req joinFamilyRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
-		return
-	}
-	req.Code = strings.TrimSpace(req.Code)
-	if req.Code == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "code is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
+	req.Token = strings.TrimSpace(req.Token)
 
 	user, ok := middleware.UserFromContext(r.Context())
 	if !ok {
@@ -119,22 +108,35 @@ func (h *Handlers) JoinFamily(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.Families.JoinFamily(r.Context(), user.ID, req.Code)
+	result, err := h.Families.JoinFamily(r.Context(), user.ID, req.Token)
 	if err != nil {
 		switch {
-		case errors.Is(err, familydomain.ErrFamilyCodeNotFound):
-			h.log.BusinessError("families.join: family code not found", err, "user_id", user.ID, "code", req.Code)
-			writeError(w, http.StatusNotFound, "family_code_not_found", "family code not found")
+		case errors.Is(err, familydomain.ErrInviteNotFound):
+			RequestLog(r, h.log).BusinessError("families.join: invite not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "invite_not_found", "invite not found")
+		case errors.Is(err, familydomain.ErrInviteExpired):
+			RequestLog(r, h.log).BusinessError("families.join: invite expired", err, "user_id", user.ID)
+			writeError(w, http.StatusGone, "invite_expired", "invite expired")
+		case errors.Is(err, familydomain.ErrInviteUsedUp):
+			RequestLog(r, h.log).BusinessError("families.join: invite used up", err, "user_id", user.ID)
+			writeError(w, http.StatusGone, "invite_used_up", "invite has no uses left")
+		case errors.Is(err, familydomain.ErrInviteRevoked):
+			RequestLog(r, h.log).BusinessError("families.join: invite revoked", err, "user_id", user.ID)
+			writeError(w, http.StatusGone, "invite_revoked", "invite revoked")
 		case errors.Is(err, familydomain.ErrAlreadyInFamily):
-			h.log.BusinessError("families.join: user already in family", err, "user_id", user.ID)
+			RequestLog(r, h.log).BusinessError("families.join: user already in family", err, "user_id", user.ID)
 			writeError(w, http.StatusConflict, "already_in_family", "already in family")
 		default:
-			h.log.InternalError("families.join: join family failed", err, "user_id", user.ID, "code", req.Code)
+			RequestLog(r, h.log).InternalError("families.join: join family failed", err, "user_id", user.ID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
 
+	if _, err := h.Activity.RecordMemberJoined(r.Context(), result.ID, ActorFromUser(user)); err != nil {
+		RequestLog(r, h.log).InternalError("families.join: record activity failed", err, "user_id", user.ID, "family_id", result.ID)
+	}
+
 	writeJSON(w, http.StatusOK, toFamilyResponse(result))
 }
 
@@ -145,25 +147,32 @@ func (h *Handlers) LeaveFamily(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.Families.LeaveFamily(r.Context(), user.ID); err != nil {
+	result, err := h.Families.LeaveFamily(r.Context(), user.ID)
+	if err != nil {
 		switch {
 		case errors.Is(err, familydomain.ErrFamilyNotFound):
-			h.log.BusinessError("families.leave: family not found", err, "user_id", user.ID)
+			RequestLog(r, h.log).BusinessError("families.leave: family not found", err, "user_id", user.ID)
 			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
 		default:
-			h.log.InternalError("families.leave: leave family failed", err, "user_id", user.ID)
+			RequestLog(r, h.log).InternalError("families.leave: leave family failed", err, "user_id", user.ID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
+	if result.NewOwnerID != "" {
+		RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventOwnershipTransferred, func(input *auditdomain.Input) {
+			input.FamilyID = result.FamilyID
+			input.TargetID = result.NewOwnerID
+			input.Details = map[string]string{"reason": "owner_left"}
+		})
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (h *Handlers) UpdateFamily(w http.ResponseWriter, r *http.Request) {
 	var req updateFamilyRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -174,33 +183,38 @@ func (h *Handlers) UpdateFamily(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := h.Families.UpdateFamily(r.Context(), user.ID, familydomain.UpdateFamilyInput{
-		Name:            req.Name,
-		DefaultCurrency: req.DefaultCurrency,
+		Name:              req.Name,
+		DefaultCurrency:   req.DefaultCurrency,
+		AllowedCurrencies: req.AllowedCurrencies,
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, familydomain.ErrFamilyNotFound):
-			h.log.BusinessError("families.update: family not found", err, "user_id", user.ID)
+			RequestLog(r, h.log).BusinessError("families.update: family not found", err, "user_id", user.ID)
 			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
 			return
 		case errors.Is(err, familydomain.ErrInvalidFamilyName):
-			h.log.BusinessError("families.update: invalid name", err, "user_id", user.ID)
-			writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+			RequestLog(r, h.log).BusinessError("families.update: invalid name", err, "user_id", user.ID)
+			writeValidationError(w, validation.FieldErr("name", validation.CodeRequired, "name is required"))
 			return
 		case errors.Is(err, familydomain.ErrInvalidCurrency):
-			h.log.BusinessError("families.update: invalid currency", err, "user_id", user.ID)
-			writeError(w, http.StatusBadRequest, "invalid_request", "default_currency must be a 3-letter code")
+			RequestLog(r, h.log).BusinessError("families.update: invalid currency", err, "user_id", user.ID)
+			writeValidationError(w, validation.FieldErr("default_currency", validation.CodeFormat, "default_currency must be a 3-letter code"))
+			return
+		case errors.Is(err, familydomain.ErrInvalidAllowedCurrencies):
+			RequestLog(r, h.log).BusinessError("families.update: invalid allowed currencies", err, "user_id", user.ID)
+			writeValidationError(w, validation.FieldErr("allowed_currencies", validation.CodeCurrency, fmt.Sprintf("allowed_currencies must list at most %d ISO 4217 codes", familydomain.MaxAllowedCurrencies)))
 			return
 		case errors.Is(err, familydomain.ErrDefaultCurrencyLocked):
-			h.log.BusinessError("families.update: default currency locked", err, "user_id", user.ID)
+			RequestLog(r, h.log).BusinessError("families.update: default currency locked", err, "user_id", user.ID)
 			writeError(w, http.StatusConflict, "base_currency_locked", "default_currency cannot be changed")
 			return
 		case errors.Is(err, familydomain.ErrNoFieldsToUpdate):
-			h.log.BusinessError("families.update: no fields to update", err, "user_id", user.ID)
-			writeError(w, http.StatusBadRequest, "invalid_request", "at least one field is required")
+			RequestLog(r, h.log).BusinessError("families.update: no fields to update", err, "user_id", user.ID)
+			writeValidationError(w, validation.FieldErr("", validation.CodeEmpty, "at least one field is required"))
 			return
 		}
-		h.log.InternalError("families.update: update family failed", err, "user_id", user.ID)
+		RequestLog(r, h.log).InternalError("families.update: update family failed", err, "user_id", user.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -218,11 +232,11 @@ func (h *Handlers) ListFamilyMembers(w http.ResponseWriter, r *http.Request) {
 	members, err := h.Families.ListMembersWithProfiles(r.Context(), user.ID)
 	if err != nil {
 		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("families.list_members: family not found", err, "user_id", user.ID)
+			RequestLog(r, h.log).BusinessError("families.list_members: family not found", err, "user_id", user.ID)
 			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
 			return
 		}
-		h.log.InternalError("families.list_members: list members failed", err, "user_id", user.ID)
+		RequestLog(r, h.log).InternalError("families.list_members: list members failed", err, "user_id", user.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -230,17 +244,120 @@ func (h *Handlers) ListFamilyMembers(w http.ResponseWriter, r *http.Request) {
 	response := make([]familyMemberResponse, 0, len(members))
 	for _, member := range members {
 		response = append(response, familyMemberResponse{
-			UserID:    member.UserID,
-			Role:      member.Role,
-			JoinedAt:  member.JoinedAt,
-			Email:     member.Email,
-			AvatarURL: member.AvatarURL,
+			UserID:        member.UserID,
+			Role:          member.Role,
+			JoinedAt:      member.JoinedAt,
+			Email:         member.Email,
+			AvatarURL:     member.AvatarURL,
+			SpendingLimit: member.SpendingLimit,
 		})
 	}
 
 	writeJSON(w, http.StatusOK, response)
 }
 
+func (h *Handlers) UpdateFamilyMember(w http.ResponseWriter, r *http.Request) {
+	var req updateFamilyMemberRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	memberID := strings.TrimSpace(chi.URLParam(r, "user_id"))
+	if memberID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "user_id is required")
+		return
+	}
+
+	member, err := h.Families.UpdateMemberRole(r.Context(), user.ID, memberID, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			RequestLog(r, h.log).BusinessError("families.update_member: family not found", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, familydomain.ErrMemberNotFound):
+			RequestLog(r, h.log).BusinessError("families.update_member: member not found", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusNotFound, "member_not_found", "member not found")
+		case errors.Is(err, familydomain.ErrNotOwner):
+			RequestLog(r, h.log).BusinessError("families.update_member: actor is not owner", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusForbidden, "not_owner", "only owner can change member roles")
+		case errors.Is(err, familydomain.ErrCannotChangeOwnerRole):
+			RequestLog(r, h.log).BusinessError("families.update_member: cannot change owner role", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusConflict, "cannot_change_owner_role", "cannot change owner role")
+		case errors.Is(err, familydomain.ErrInvalidRole):
+			writeValidationError(w, validation.FieldErr("role", validation.CodeEnum, "role must be member or child"))
+		default:
+			RequestLog(r, h.log).InternalError("families.update_member: update member role failed", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, familyMemberResponse{
+		UserID:        member.UserID,
+		Role:          member.Role,
+		JoinedAt:      member.JoinedAt,
+		SpendingLimit: member.SpendingLimit,
+	})
+}
+
+// SetFamilyMemberSpendingLimit sets a member's spending limit; a null
+// amount removes it.
+func (h *Handlers) SetFamilyMemberSpendingLimit(w http.ResponseWriter, r *http.Request) {
+	var req setSpendingLimitRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	memberID := strings.TrimSpace(chi.URLParam(r, "user_id"))
+	if memberID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "user_id is required")
+		return
+	}
+
+	member, err := h.Families.SetMemberSpendingLimit(r.Context(), user.ID, memberID, req.Amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			RequestLog(r, h.log).BusinessError("families.spending_limit: family not found", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, familydomain.ErrMemberNotFound):
+			RequestLog(r, h.log).BusinessError("families.spending_limit: member not found", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusNotFound, "member_not_found", "member not found")
+		case errors.Is(err, familydomain.ErrNotOwner):
+			RequestLog(r, h.log).BusinessError("families.spending_limit: actor is not owner", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusForbidden, "not_owner", "only owner can set spending limits")
+		case errors.Is(err, familydomain.ErrCannotLimitOwner):
+			RequestLog(r, h.log).BusinessError("families.spending_limit: cannot limit owner", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusConflict, "cannot_limit_owner", "owner cannot have a spending limit")
+		case errors.Is(err, familydomain.ErrInvalidSpendingLimit):
+			writeValidationError(w, validation.FieldErr("amount", validation.CodeRange, "amount must be between 0 and 9999999999.99"))
+		default:
+			RequestLog(r, h.log).InternalError("families.spending_limit: set spending limit failed", err, "actor_id", user.ID, "member_id", memberID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, familyMemberResponse{
+		UserID:        member.UserID,
+		Role:          member.Role,
+		JoinedAt:      member.JoinedAt,
+		SpendingLimit: member.SpendingLimit,
+	})
+}
+
 func (h *Handlers) RemoveFamilyMember(w http.ResponseWriter, r *http.Request) {
 	user, ok := middleware.UserFromContext(r.Context())
 	if !ok {
@@ -257,23 +374,29 @@ func (h *Handlers) RemoveFamilyMember(w http.ResponseWriter, r *http.Request) {
 	if err := h.Families.RemoveMember(r.Context(), user.ID, memberID); err != nil {
 		switch {
 		case errors.Is(err, familydomain.ErrFamilyNotFound):
-			h.log.BusinessError("families.remove_member: family not found", err, "actor_id", user.ID, "member_id", memberID)
+			RequestLog(r, h.log).BusinessError("families.remove_member: family not found", err, "actor_id", user.ID, "member_id", memberID)
 			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
 		case errors.Is(err, familydomain.ErrMemberNotFound):
-			h.log.BusinessError("families.remove_member: member not found", err, "actor_id", user.ID, "member_id", memberID)
+			RequestLog(r, h.log).BusinessError("families.remove_member: member not found", err, "actor_id", user.ID, "member_id", memberID)
 			writeError(w, http.StatusNotFound, "member_not_found", "member not found")
 		case errors.Is(err, familydomain.ErrNotOwner):
-			h.log.BusinessError("families.remove_member: actor is not owner", err, "actor_id", user.ID, "member_id", memberID)
+			RequestLog(r, h.log).BusinessError("families.remove_member: actor is not owner", err, "actor_id", user.ID, "member_id", memberID)
 			writeError(w, http.StatusForbidden, "not_owner", "only owner can remove members")
 		case errors.Is(err, familydomain.ErrCannotRemoveOwner):
-			h.log.BusinessError("families.remove_member: cannot remove owner", err, "actor_id", user.ID, "member_id", memberID)
+			RequestLog(r, h.log).BusinessError("families.remove_member: cannot remove owner", err, "actor_id", user.ID, "member_id", memberID)
 			writeError(w, http.StatusConflict, "cannot_remove_owner", "cannot remove owner")
 		default:
-			h.log.InternalError("families.remove_member: remove member failed", err, "actor_id", user.ID, "member_id", memberID)
+			RequestLog(r, h.log).InternalError("families.remove_member: remove member failed", err, "actor_id", user.ID, "member_id", memberID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
+	RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventMemberRemoved, func(input *auditdomain.Input) {
+		if family, ok := middleware.FamilyFromContext(r.Context()); ok {
+			input.FamilyID = family.ID
+		}
+		input.TargetID = memberID
+	})
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -285,10 +408,12 @@ func notImplemented(w http.ResponseWriter) {
 type familyResponse struct {
 	ID              string    `json:"id"`
 	Name            string    `json:"name"`
-	Code            string    `json:"code"`
 	OwnerID         string    `json:"owner_id"`
 	DefaultCurrency string    `json:"default_currency"`
 	CreatedAt       time.Time `json:"created_at"`
+	// AllowedCurrencies lists the currencies expenses may use besides the
+	// default one; empty means any.
+	AllowedCurrencies []string `json:"allowed_currencies"`
 }
 
 type familyMemberResponse struct {
@@ -297,15 +422,23 @@ type familyMemberResponse struct {
 	JoinedAt  time.Time `json:"joined_at"`
 	Email     *string   `json:"email"`
 	AvatarURL *string   `json:"avatar_url"`
+	// SpendingLimit is the amount in the family currency above which the
+	// member's expenses wait for the owner's approval.
+	SpendingLimit *float64 `json:"spending_limit"`
 }
 
 func toFamilyResponse(familyModel *familydomain.Family) familyResponse {
+	allowedCurrencies := familyModel.Currencies()
+	if allowedCurrencies == nil {
+		allowedCurrencies = []string{}
+	}
 	return familyResponse{
 		ID:              familyModel.ID,
 		Name:            familyModel.Name,
-		Code:            familyModel.Code,
 		OwnerID:         familyModel.OwnerID,
 		DefaultCurrency: familyModel.DefaultCurrency,
 		CreatedAt:       familyModel.CreatedAt,
+
+		AllowedCurrencies: allowedCurrencies,
 	}
 }
@@ -0,0 +1,362 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auditdomain "family-app-go/internal/domain/audit"
+	familydomain "family-app-go/internal/domain/family"
+)
+
+type mockFamilies struct {
+	FamilyService
+	err     error
+	leave   familydomain.LeaveResult
+	update  familydomain.UpdateFamilyInput
+	limit   *float64
+	removed string
+}
+
+func (m *mockFamilies) family() (*familydomain.Family, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &familydomain.Family{
+		ID:                testFamilyID,
+		Name:              "Smiths",
+		OwnerID:           testUserID,
+		DefaultCurrency:   "EUR",
+		CreatedAt:         time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC),
+		AllowedCurrencies: []byte(`[]`),
+	}, nil
+}
+
+func (m *mockFamilies) CreateFamily(context.Context, string, string) (*familydomain.Family, error) {
+	return m.family()
+}
+
+func (m *mockFamilies) JoinFamily(context.Context, string, string) (*familydomain.Family, error) {
+	return m.family()
+}
+
+func (m *mockFamilies) UpdateFamily(_ context.Context, _ string, input familydomain.UpdateFamilyInput) (*familydomain.Family, error) {
+	m.update = input
+	return m.family()
+}
+
+func (m *mockFamilies) LeaveFamily(context.Context, string) (*familydomain.LeaveResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &m.leave, nil
+}
+
+func (m *mockFamilies) UpdateMemberRole(_ context.Context, _, memberID, role string) (*familydomain.FamilyMember, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &familydomain.FamilyMember{FamilyID: testFamilyID, UserID: memberID, Role: role}, nil
+}
+
+func (m *mockFamilies) SetMemberSpendingLimit(_ context.Context, _, memberID string, limit *float64) (*familydomain.FamilyMember, error) {
+	m.limit = limit
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &familydomain.FamilyMember{FamilyID: testFamilyID, UserID: memberID, Role: familydomain.RoleMember, SpendingLimit: limit}, nil
+}
+
+func (m *mockFamilies) RemoveMember(_ context.Context, _, memberID string) error {
+	m.removed = memberID
+	return m.err
+}
+
+func TestCreateFamily(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "malformed", body: `{"name":1}`, status: http.StatusBadRequest, code: "invalid_json"},
+		{name: "missing name", body: `{"name":"  "}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "already in family", body: `{"name":"Smiths"}`, err: familydomain.ErrAlreadyInFamily, status: http.StatusConflict, code: "already_in_family"},
+		{name: "internal", body: `{"name":"Smiths"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "created", body: `{"name":"Smiths"}`, status: http.StatusCreated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.families.err = tc.err
+			rec := httptest.NewRecorder()
+			deps.handlers().CreateFamily(rec, userRequest(http.MethodPost, "/api/families", tc.body))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := decodeError(t, rec).Code; code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			for _, key := range []string{"id", "name", "owner_id", "default_currency", "created_at", "allowed_currencies"} {
+				if _, ok := body[key]; !ok {
+					t.Fatalf("expected %q in %s", key, rec.Body.String())
+				}
+			}
+			if string(body["allowed_currencies"]) != "[]" {
+				t.Fatalf("expected allowed_currencies to be an empty array, got %s", body["allowed_currencies"])
+			}
+		})
+	}
+}
+
+func TestJoinFamilyMapsErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{familydomain.ErrInviteNotFound, http.StatusNotFound, "invite_not_found"},
+		{familydomain.ErrInviteExpired, http.StatusGone, "invite_expired"},
+		{familydomain.ErrInviteUsedUp, http.StatusGone, "invite_used_up"},
+		{fmt.Errorf("join: %w", familydomain.ErrInviteRevoked), http.StatusGone, "invite_revoked"},
+		{familydomain.ErrAlreadyInFamily, http.StatusConflict, "already_in_family"},
+		{errDatabase, http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.families.err = tc.err
+			rec := httptest.NewRecorder()
+			deps.handlers().JoinFamily(rec, userRequest(http.MethodPost, "/api/families/join", `{"token":"abc"}`))
+
+			if rec.Code != tc.status || decodeError(t, rec).Code != tc.code {
+				t.Fatalf("expected %d %s, got %d: %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+			if len(deps.activity.joined) != 0 {
+				t.Fatalf("expected no activity, got %v", deps.activity.joined)
+			}
+		})
+	}
+}
+
+func TestJoinFamily(t *testing.T) {
+	deps := newTestDeps()
+	rec := httptest.NewRecorder()
+	deps.handlers().JoinFamily(rec, userRequest(http.MethodPost, "/api/families/join", `{"token":" abc "}`))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(deps.activity.joined) != 1 || deps.activity.joined[0] != testFamilyID {
+		t.Fatalf("expected the join in the activity feed, got %v", deps.activity.joined)
+	}
+
+	rec = httptest.NewRecorder()
+	deps.handlers().JoinFamily(rec, userRequest(http.MethodPost, "/api/families/join", `{}`))
+	if rec.Code != http.StatusBadRequest || decodeError(t, rec).Code != "invalid_request" {
+		t.Fatalf("expected 400 invalid_request without a token, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLeaveFamily(t *testing.T) {
+	deps := newTestDeps()
+	deps.families.err = familydomain.ErrFamilyNotFound
+	rec := httptest.NewRecorder()
+	deps.handlers().LeaveFamily(rec, userRequest(http.MethodPost, "/api/families/leave", ""))
+	if rec.Code != http.StatusNotFound || decodeError(t, rec).Code != "family_not_found" {
+		t.Fatalf("expected 404 family_not_found, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, newOwner := range []string{"", testMemberID} {
+		deps = newTestDeps()
+		deps.families.leave = familydomain.LeaveResult{FamilyID: testFamilyID, NewOwnerID: newOwner}
+		rec = httptest.NewRecorder()
+		deps.handlers().LeaveFamily(rec, userRequest(http.MethodPost, "/api/families/leave", ""))
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if newOwner == "" && len(deps.audit.events) != 0 {
+			t.Fatalf("expected no audit event, got %+v", deps.audit.events)
+		}
+		if newOwner != "" && (len(deps.audit.events) != 1 || deps.audit.events[0].Type != auditdomain.EventOwnershipTransferred || deps.audit.events[0].TargetID != newOwner) {
+			t.Fatalf("expected the ownership transfer audited, got %+v", deps.audit.events)
+		}
+	}
+}
+
+func TestUpdateFamily(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+		field  string
+	}{
+		{name: "no fields", body: `{}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "blank name", body: `{"name":" "}`, status: http.StatusBadRequest, code: "invalid_request", field: "name"},
+		{name: "bad currency", body: `{"default_currency":"euro"}`, status: http.StatusBadRequest, code: "invalid_request", field: "default_currency"},
+		{name: "not found", body: `{"name":"Smiths"}`, err: familydomain.ErrFamilyNotFound, status: http.StatusNotFound, code: "family_not_found"},
+		{name: "name rejected", body: `{"name":"Smiths"}`, err: familydomain.ErrInvalidFamilyName, status: http.StatusBadRequest, code: "invalid_request", field: "name"},
+		{name: "currency rejected", body: `{"default_currency":"EUR"}`, err: familydomain.ErrInvalidCurrency, status: http.StatusBadRequest, code: "invalid_request", field: "default_currency"},
+		{name: "allowed rejected", body: `{"allowed_currencies":["USD"]}`, err: familydomain.ErrInvalidAllowedCurrencies, status: http.StatusBadRequest, code: "invalid_request", field: "allowed_currencies"},
+		{name: "currency locked", body: `{"default_currency":"USD"}`, err: familydomain.ErrDefaultCurrencyLocked, status: http.StatusConflict, code: "base_currency_locked"},
+		{name: "nothing changed", body: `{"name":"Smiths"}`, err: familydomain.ErrNoFieldsToUpdate, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "internal", body: `{"name":"Smiths"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "updated", body: `{"name":"Smiths","allowed_currencies":["USD","GBP"]}`, status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.families.err = tc.err
+			rec := httptest.NewRecorder()
+			deps.handlers().UpdateFamily(rec, userRequest(http.MethodPatch, "/api/families/me", tc.body))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code == "" {
+				if deps.families.update.Name == nil || deps.families.update.AllowedCurrencies == nil || len(*deps.families.update.AllowedCurrencies) != 2 {
+					t.Fatalf("unexpected input: %+v", deps.families.update)
+				}
+				return
+			}
+			body := decodeError(t, rec)
+			if body.Code != tc.code {
+				t.Fatalf("expected %s, got %s", tc.code, body.Code)
+			}
+			if tc.field != "" && (len(body.Fields) == 0 || body.Fields[0].Field != tc.field) {
+				t.Fatalf("expected a %q field error, got %+v", tc.field, body.Fields)
+			}
+		})
+	}
+}
+
+func TestUpdateFamilyMemberMapsErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{familydomain.ErrFamilyNotFound, http.StatusNotFound, "family_not_found"},
+		{familydomain.ErrMemberNotFound, http.StatusNotFound, "member_not_found"},
+		{familydomain.ErrNotOwner, http.StatusForbidden, "not_owner"},
+		{familydomain.ErrCannotChangeOwnerRole, http.StatusConflict, "cannot_change_owner_role"},
+		{familydomain.ErrInvalidRole, http.StatusBadRequest, "invalid_request"},
+		{errDatabase, http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.families.err = tc.err
+			req := withURLParam(userRequest(http.MethodPatch, "/api/families/me/members/"+testMemberID, `{"role":"child"}`), "user_id", testMemberID)
+			rec := httptest.NewRecorder()
+			deps.handlers().UpdateFamilyMember(rec, req)
+
+			if rec.Code != tc.status || decodeError(t, rec).Code != tc.code {
+				t.Fatalf("expected %d %s, got %d: %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSetFamilyMemberSpendingLimit(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "not owner", body: `{"amount":100}`, err: familydomain.ErrNotOwner, status: http.StatusForbidden, code: "not_owner"},
+		{name: "owner", body: `{"amount":100}`, err: familydomain.ErrCannotLimitOwner, status: http.StatusConflict, code: "cannot_limit_owner"},
+		{name: "out of range", body: `{"amount":-1}`, err: familydomain.ErrInvalidSpendingLimit, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "member not found", body: `{"amount":100}`, err: familydomain.ErrMemberNotFound, status: http.StatusNotFound, code: "member_not_found"},
+		{name: "set", body: `{"amount":100}`, status: http.StatusOK},
+		{name: "removed", body: `{"amount":null}`, status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.families.err = tc.err
+			req := withURLParam(userRequest(http.MethodPut, "/api/families/me/members/"+testMemberID+"/spending-limit", tc.body), "user_id", testMemberID)
+			rec := httptest.NewRecorder()
+			deps.handlers().SetFamilyMemberSpendingLimit(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := decodeError(t, rec).Code; code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			want := "100"
+			if deps.families.limit == nil {
+				want = "null"
+			}
+			if string(body["spending_limit"]) != want {
+				t.Fatalf("expected spending_limit %s, got %s", want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRemoveFamilyMember(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{familydomain.ErrNotOwner, http.StatusForbidden, "not_owner"},
+		{familydomain.ErrCannotRemoveOwner, http.StatusConflict, "cannot_remove_owner"},
+		{familydomain.ErrMemberNotFound, http.StatusNotFound, "member_not_found"},
+		{errDatabase, http.StatusInternalServerError, "internal_error"},
+		{nil, http.StatusNoContent, ""},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.status, tc.code), func(t *testing.T) {
+			deps := newTestDeps()
+			deps.families.err = tc.err
+			req := withURLParam(familyRequest(http.MethodDelete, "/api/families/me/members/"+testMemberID, ""), "user_id", testMemberID)
+			rec := httptest.NewRecorder()
+			deps.handlers().RemoveFamilyMember(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := decodeError(t, rec).Code; code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				if len(deps.audit.events) != 0 {
+					t.Fatalf("expected no audit event, got %+v", deps.audit.events)
+				}
+				return
+			}
+			events := deps.audit.events
+			if len(events) != 1 || events[0].Type != auditdomain.EventMemberRemoved || events[0].FamilyID != testFamilyID || events[0].TargetID != testMemberID {
+				t.Fatalf("expected the removal audited, got %+v", events)
+			}
+		})
+	}
+}
@@ -2,10 +2,9 @@ package common
 
 import (
 	"context"
+	"net/http"
 
 	"family-app-go/internal/devseed"
-	familydomain "family-app-go/internal/domain/family"
-	syncdomain "family-app-go/internal/domain/sync"
 	"family-app-go/pkg/logger"
 )
 
@@ -14,21 +13,36 @@ type FamilySeeder interface {
 }
 
 type Handlers struct {
-	Families     *familydomain.Service
-	Sync         *syncdomain.Service
+	Families     FamilyService
+	Users        UserService
+	Sync         SyncService
+	Activity     ActivityService
+	HealthChecks HealthService
+	Flags        FeatureFlagsService
+	Audit        AuditRecorder
 	FamilySeeder FamilySeeder
 	log          logger.Logger
 }
 
-func New(families *familydomain.Service, sync *syncdomain.Service, log logger.Logger, seeders ...FamilySeeder) *Handlers {
+func New(families FamilyService, users UserService, sync SyncService, activity ActivityService, health HealthService, flags FeatureFlagsService, audit AuditRecorder, log logger.Logger, seeders ...FamilySeeder) *Handlers {
 	var familySeeder FamilySeeder
 	if len(seeders) > 0 {
 		familySeeder = seeders[0]
 	}
 	return &Handlers{
 		Families:     families,
+		Users:        users,
 		Sync:         sync,
+		Activity:     activity,
+		HealthChecks: health,
+		Flags:        flags,
+		Audit:        audit,
 		FamilySeeder: familySeeder,
 		log:          log,
 	}
 }
+
+// RequestLog returns log carrying the request and trace IDs of r.
+func RequestLog(r *http.Request, log logger.Logger) logger.Logger {
+	return logger.FromContext(r.Context(), log)
+}
@@ -0,0 +1,107 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	auditdomain "family-app-go/internal/domain/audit"
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testUserID   = "22222222-2222-2222-2222-222222222222"
+	testMemberID = "33333333-3333-3333-3333-333333333333"
+)
+
+var errDatabase = errors.New("database is down")
+
+// The mocks embed the service interface: a call the test did not expect
+// hits the nil interface and panics.
+
+type mockActivity struct {
+	ActivityService
+	joined []string
+}
+
+func (m *mockActivity) RecordMemberJoined(_ context.Context, familyID string, _ activitydomain.Actor) (*activitydomain.Event, error) {
+	m.joined = append(m.joined, familyID)
+	return &activitydomain.Event{}, nil
+}
+
+type mockAudit struct {
+	events []auditdomain.Input
+}
+
+func (m *mockAudit) Record(_ context.Context, input auditdomain.Input) error {
+	m.events = append(m.events, input)
+	return nil
+}
+
+type mockFlags struct {
+	disabled bool
+	err      error
+}
+
+func (m mockFlags) Enabled(context.Context, string, string) (bool, error) {
+	return !m.disabled, m.err
+}
+
+type testDeps struct {
+	families *mockFamilies
+	sync     *mockSync
+	activity *mockActivity
+	flags    mockFlags
+	audit    *mockAudit
+}
+
+func newTestDeps() *testDeps {
+	return &testDeps{
+		families: &mockFamilies{},
+		sync:     &mockSync{},
+		activity: &mockActivity{},
+		audit:    &mockAudit{},
+	}
+}
+
+func (d *testDeps) handlers() *Handlers {
+	return New(d.families, nil, d.sync, d.activity, nil, d.flags, d.audit, logger.New(io.Discard, slog.LevelError, "text"))
+}
+
+func userRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(middleware.WithUser(req.Context(), middleware.User{ID: testUserID, Name: "Alex"}))
+}
+
+// familyRequest is a userRequest from a member of testFamilyID.
+func familyRequest(method, target, body string) *http.Request {
+	req := userRequest(method, target, body)
+	family := &familydomain.Family{ID: testFamilyID, DefaultCurrency: "EUR", AllowedCurrencies: []byte(`["USD"]`)}
+	return req.WithContext(middleware.WithFamily(req.Context(), family))
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) errorBody {
+	t.Helper()
+	var payload errorEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error response %q: %v", rec.Body.String(), err)
+	}
+	return payload.Error
+}
@@ -1,9 +1,70 @@
 package common
 
-import "net/http"
+import (
+	"net/http"
+	"time"
 
+	healthdomain "family-app-go/internal/domain/health"
+)
+
+type healthResponse struct {
+	Status     string                             `json:"status"`
+	CheckedAt  *time.Time                         `json:"checked_at,omitempty"`
+	Components map[string]healthComponentResponse `json:"components,omitempty"`
+}
+
+type healthComponentResponse struct {
+	Status    string         `json:"status"`
+	Critical  bool           `json:"critical"`
+	LatencyMS int64          `json:"latency_ms"`
+	Error     string         `json:"error,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// Health is the legacy plain-text probe; prefer /health/live and
+// /health/ready.
 func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("ok"))
 }
+
+// HealthLive answers as long as the process serves HTTP. It never touches
+// dependencies so a database outage does not restart the pod.
+func (h *Handlers) HealthLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	WriteJSON(w, http.StatusOK, healthResponse{Status: healthdomain.StatusOK})
+}
+
+// HealthReady reports per-component status and answers 503 when a critical
+// component is down.
+func (h *Handlers) HealthReady(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if h.HealthChecks == nil {
+		WriteJSON(w, http.StatusOK, healthResponse{Status: healthdomain.StatusOK})
+		return
+	}
+
+	report := h.HealthChecks.Readiness(r.Context())
+	components := make(map[string]healthComponentResponse, len(report.Components))
+	for name, component := range report.Components {
+		components[name] = healthComponentResponse{
+			Status:    component.Status,
+			Critical:  component.Critical,
+			LatencyMS: component.LatencyMS,
+			Error:     component.Error,
+			Details:   component.Details,
+		}
+	}
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+		RequestLog(r, h.log).Warn("health.ready: not ready", "components", components)
+	}
+	WriteJSON(w, status, healthResponse{
+		Status:     report.Status,
+		CheckedAt:  &report.CheckedAt,
+		Components: components,
+	})
+}
@@ -0,0 +1,176 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type createInviteRequest struct {
+	ExpiresInHours *int   `json:"expires_in_hours"`
+	MaxUses        *int   `json:"max_uses"`
+	Role           string `json:"role"`
+}
+
+type inviteResponse struct {
+	ID        string     `json:"id"`
+	Role      string     `json:"role"`
+	Status    string     `json:"status"`
+	MaxUses   *int       `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+type createdInviteResponse struct {
+	inviteResponse
+	Token string `json:"token"`
+}
+
+func (h *Handlers) CreateFamilyInvite(w http.ResponseWriter, r *http.Request) {
+	var req createInviteRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	input := familydomain.CreateInviteInput{
+		MaxUses: req.MaxUses,
+		Role:    req.Role,
+	}
+	if req.ExpiresInHours != nil {
+		input.TTL = time.Duration(*req.ExpiresInHours) * time.Hour
+	}
+
+	created, err := h.Families.CreateInvite(r.Context(), user.ID, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			RequestLog(r, h.log).BusinessError("families.create_invite: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, familydomain.ErrNotOwner):
+			RequestLog(r, h.log).BusinessError("families.create_invite: actor is not owner", err, "user_id", user.ID)
+			writeError(w, http.StatusForbidden, "not_owner", "only owner can manage invites")
+		case errors.Is(err, familydomain.ErrInvalidInviteTTL):
+			writeInviteTTLError(w)
+		case errors.Is(err, familydomain.ErrInvalidInviteMaxUses):
+			writeValidationError(w, validation.FieldErr("max_uses", validation.CodeRange, fmt.Sprintf(
+				"max_uses must be between 1 and %d",
+				familydomain.MaxInviteUses,
+			)))
+		case errors.Is(err, familydomain.ErrInvalidRole):
+			writeValidationError(w, validation.FieldErr("role", validation.CodeEnum, "role must be member or child"))
+		default:
+			RequestLog(r, h.log).InternalError("families.create_invite: create invite failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdInviteResponse{
+		inviteResponse: toInviteResponse(created.Invite, time.Now()),
+		Token:          created.Token,
+	})
+}
+
+func (h *Handlers) ListFamilyInvites(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	invites, err := h.Families.ListInvites(r.Context(), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			RequestLog(r, h.log).BusinessError("families.list_invites: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, familydomain.ErrNotOwner):
+			RequestLog(r, h.log).BusinessError("families.list_invites: actor is not owner", err, "user_id", user.ID)
+			writeError(w, http.StatusForbidden, "not_owner", "only owner can manage invites")
+		default:
+			RequestLog(r, h.log).InternalError("families.list_invites: list invites failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	now := time.Now()
+	response := make([]inviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		response = append(response, toInviteResponse(invite, now))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) RevokeFamilyInvite(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	inviteID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if inviteID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	if err := h.Families.RevokeInvite(r.Context(), user.ID, inviteID); err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			RequestLog(r, h.log).BusinessError("families.revoke_invite: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, familydomain.ErrNotOwner):
+			RequestLog(r, h.log).BusinessError("families.revoke_invite: actor is not owner", err, "user_id", user.ID)
+			writeError(w, http.StatusForbidden, "not_owner", "only owner can manage invites")
+		case errors.Is(err, familydomain.ErrInviteNotFound):
+			RequestLog(r, h.log).BusinessError("families.revoke_invite: invite not found", err, "user_id", user.ID, "invite_id", inviteID)
+			writeError(w, http.StatusNotFound, "invite_not_found", "invite not found")
+		default:
+			RequestLog(r, h.log).InternalError("families.revoke_invite: revoke invite failed", err, "user_id", user.ID, "invite_id", inviteID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeInviteTTLError(w http.ResponseWriter) {
+	writeValidationError(w, validation.FieldErr("expires_in_hours", validation.CodeRange, fmt.Sprintf(
+		"expires_in_hours must be between %d and %d",
+		int(familydomain.MinInviteTTL/time.Hour),
+		int(familydomain.MaxInviteTTL/time.Hour),
+	)))
+}
+
+func toInviteResponse(invite familydomain.Invite, now time.Time) inviteResponse {
+	return inviteResponse{
+		ID:        invite.ID,
+		Role:      invite.Role,
+		Status:    invite.Status(now),
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		ExpiresAt: invite.ExpiresAt,
+		CreatedBy: invite.CreatedBy,
+		CreatedAt: invite.CreatedAt,
+		RevokedAt: invite.RevokedAt,
+	}
+}
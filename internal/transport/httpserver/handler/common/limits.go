@@ -0,0 +1,53 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"family-app-go/internal/config"
+)
+
+// ListLimit is the page size of a list endpoint: Default when the request
+// sends no limit, Max as the most rows one page returns. A larger limit, or
+// limit=0, is clamped to Max instead of reading the whole table.
+type ListLimit struct {
+	Default int
+	Max     int
+}
+
+// ListLimits holds the page sizes of the list endpoints that take ?limit=.
+type ListLimits struct {
+	Expenses     ListLimit
+	TodoLists    ListLimit
+	GymEntries   ListLimit
+	Workouts     ListLimit
+	AnalyticsTop ListLimit
+}
+
+func NewListLimits(cfg config.ListLimitsConfig) ListLimits {
+	return ListLimits{
+		Expenses:     ListLimit(cfg.Expenses),
+		TodoLists:    ListLimit(cfg.TodoLists),
+		GymEntries:   ListLimit(cfg.GymEntries),
+		Workouts:     ListLimit(cfg.Workouts),
+		AnalyticsTop: ListLimit(cfg.AnalyticsTop),
+	}
+}
+
+// Parse reads a limit query value, clamped to Max. Negative and non-numeric
+// values are rejected. A zero Max leaves the limit unbounded.
+func (l ListLimit) Parse(value string) (int, error) {
+	limit := l.Default
+	if value = strings.TrimSpace(value); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, fmt.Errorf("invalid int")
+		}
+		limit = parsed
+	}
+	if l.Max > 0 && (limit == 0 || limit > l.Max) {
+		limit = l.Max
+	}
+	return limit, nil
+}
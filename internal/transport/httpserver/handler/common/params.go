@@ -5,34 +5,42 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"family-app-go/pkg/dateparse"
 )
 
 func parseDateRequired(value string) (time.Time, error) {
-	value = strings.TrimSpace(value)
-	if value == "" {
-		return time.Time{}, fmt.Errorf("date is required")
-	}
-	return time.Parse("2006-01-02", value)
+	return dateparse.Date(value)
 }
 
 func parseDateParam(value string) (*time.Time, error) {
+	return dateparse.OptionalDate(value)
+}
+
+// parseTimestampParam parses an optional RFC 3339 timestamp.
+func parseTimestampParam(value string) (*time.Time, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
 		return nil, nil
 	}
-	parsed, err := time.Parse("2006-01-02", value)
+	parsed, err := time.Parse(time.RFC3339, value)
 	if err != nil {
 		return nil, err
 	}
 	return &parsed, nil
 }
 
-func parseMonthRequired(value string) (time.Time, error) {
+// parseBoolParam parses an optional boolean; blank means false.
+func parseBoolParam(value string) (bool, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
-		return time.Time{}, fmt.Errorf("month is required")
+		return false, nil
 	}
-	return time.Parse("2006-01", value)
+	return strconv.ParseBool(value)
+}
+
+func parseMonthRequired(value string) (time.Time, error) {
+	return dateparse.Month(value)
 }
 
 func parseCSV(value string) []string {
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+
+	quotadomain "family-app-go/internal/domain/quota"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+// quotaExceededEnvelope is the body for writes refused by a family quota.
+type quotaExceededEnvelope struct {
+	Error errorBody         `json:"error"`
+	Quota quotaExceededBody `json:"quota"`
+}
+
+type quotaExceededBody struct {
+	Name  string `json:"name"`
+	Limit int    `json:"limit"`
+	// WindowSeconds is set for rolling quotas, which free up as older writes
+	// leave the window.
+	WindowSeconds int64 `json:"window_seconds,omitempty"`
+}
+
+// writeQuotaExceeded answers quota_exceeded with the quota that was hit:
+// 429 for rolling quotas the client can wait out, 409 for capacity quotas
+// that only free up when something is deleted.
+func writeQuotaExceeded(w http.ResponseWriter, err error) {
+	const message = "family quota exceeded"
+	var exceeded *quotadomain.ExceededError
+	if !errors.As(err, &exceeded) {
+		writeError(w, http.StatusTooManyRequests, "quota_exceeded", message)
+		return
+	}
+
+	status := http.StatusConflict
+	if exceeded.Window > 0 {
+		status = http.StatusTooManyRequests
+	}
+	body := quotaExceededBody{
+		Name:          exceeded.Quota,
+		Limit:         exceeded.Limit,
+		WindowSeconds: int64(exceeded.Window.Seconds()),
+	}
+	if middleware.WriteQuotaProblem(w, status, "quota_exceeded", message, body) {
+		return
+	}
+	writeJSON(w, status, quotaExceededEnvelope{
+		Error: errorBody{Code: "quota_exceeded", Message: message},
+		Quota: body,
+	})
+}
+
+func WriteQuotaExceeded(w http.ResponseWriter, err error) {
+	writeQuotaExceeded(w, err)
+}
@@ -0,0 +1,49 @@
+package common
+
+import "encoding/json"
+
+// OptionalNullableString tells a missing field apart from an explicit null
+// in PATCH-style request bodies: Set is false when the field was omitted, and
+// Value is nil when it was null.
+type OptionalNullableString struct {
+	Set   bool
+	Value *string
+}
+
+func (o *OptionalNullableString) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	o.Value = &value
+	return nil
+}
+
+// OptionalNullableFloat is OptionalNullableString for numbers.
+type OptionalNullableFloat struct {
+	Set   bool
+	Value *float64
+}
+
+func (o *OptionalNullableFloat) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var value float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	o.Value = &value
+	return nil
+}
@@ -2,22 +2,61 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
 )
 
+// databaseRetryAfter is the Retry-After, in seconds, sent with 503s caused by
+// database query timeouts.
+const databaseRetryAfter = "5"
+
 type errorEnvelope struct {
 	Error errorBody `json:"error"`
 }
 
 type errorBody struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string                  `json:"code"`
+	Message string                  `json:"message"`
+	Fields  []validation.FieldError `json:"fields,omitempty"`
 }
 
+// writeError answers with the {"error":{...}} envelope, or with problem+json
+// when the client asked for it. Internal errors caused by a database query
+// timeout become 503 database_unavailable, so clients retry instead.
 func writeError(w http.ResponseWriter, status int, code, message string) {
+	if status == http.StatusInternalServerError && middleware.DatabaseTimedOut(w) {
+		status, code, message = http.StatusServiceUnavailable, "database_unavailable", "database is not responding, try again later"
+		w.Header().Set("Retry-After", databaseRetryAfter)
+	}
+	if middleware.WriteProblem(w, status, code, message, nil) {
+		return
+	}
 	writeJSON(w, status, errorEnvelope{Error: errorBody{Code: code, Message: message}})
 }
 
+// writeValidationError answers 400 invalid_request with every failed field.
+// The message repeats the first failure for clients that only read it.
+func writeValidationError(w http.ResponseWriter, err error) {
+	var fields validation.Errors
+	if !errors.As(err, &fields) || len(fields) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if middleware.WriteProblem(w, http.StatusBadRequest, "invalid_request", fields[0].Message, fields) {
+		return
+	}
+	writeJSON(w, http.StatusBadRequest, errorEnvelope{Error: errorBody{
+		Code:    "invalid_request",
+		Message: fields[0].Message,
+		Fields:  fields,
+	}})
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
@@ -30,6 +69,37 @@ func decodeJSON(r *http.Request, dst interface{}) error {
 	return dec.Decode(dst)
 }
 
+// decodeRequest decodes the JSON body into dst and runs its validation rules.
+// It writes the 400 response and returns false when either step fails.
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := decodeJSON(r, dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request_body_too_large", fmt.Sprintf("request body must be at most %d bytes", maxBytesErr.Limit))
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+		return false
+	}
+	if err := validation.Run(dst); err != nil {
+		writeValidationError(w, err)
+		return false
+	}
+	return true
+}
+
+// requireFamily returns the caller's family, resolved once per request by
+// middleware.FamilyAccess, and answers 404 family_not_found when the caller
+// has none.
+func requireFamily(w http.ResponseWriter, r *http.Request) (*familydomain.Family, bool) {
+	family, ok := middleware.FamilyFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		return nil, false
+	}
+	return family, true
+}
+
 func WriteError(w http.ResponseWriter, status int, code, message string) {
 	writeError(w, status, code, message)
 }
@@ -41,3 +111,15 @@ func WriteJSON(w http.ResponseWriter, status int, payload interface{}) {
 func DecodeJSON(r *http.Request, dst interface{}) error {
 	return decodeJSON(r, dst)
 }
+
+func WriteValidationError(w http.ResponseWriter, err error) {
+	writeValidationError(w, err)
+}
+
+func DecodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return decodeRequest(w, r, dst)
+}
+
+func RequireFamily(w http.ResponseWriter, r *http.Request) (*familydomain.Family, bool) {
+	return requireFamily(w, r)
+}
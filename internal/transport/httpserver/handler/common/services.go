@@ -0,0 +1,59 @@
+package common
+
+import (
+	"context"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	familydomain "family-app-go/internal/domain/family"
+	healthdomain "family-app-go/internal/domain/health"
+	syncdomain "family-app-go/internal/domain/sync"
+	userdomain "family-app-go/internal/domain/user"
+)
+
+// ActivityService is implemented by *activitydomain.Service.
+type ActivityService interface {
+	ListEvents(ctx context.Context, familyID string, filter activitydomain.ListFilter) ([]activitydomain.Event, int64, error)
+	RecordMemberJoined(ctx context.Context, familyID string, actor activitydomain.Actor) (*activitydomain.Event, error)
+}
+
+// FamilyService is implemented by *familydomain.Service.
+type FamilyService interface {
+	CreateFamily(ctx context.Context, userID, name string) (*familydomain.Family, error)
+	CreateInvite(ctx context.Context, actorID string, input familydomain.CreateInviteInput) (*familydomain.CreatedInvite, error)
+	JoinFamily(ctx context.Context, userID, token string) (*familydomain.Family, error)
+	LeaveFamily(ctx context.Context, userID string) (*familydomain.LeaveResult, error)
+	ListInvites(ctx context.Context, actorID string) ([]familydomain.Invite, error)
+	ListMembersWithProfiles(ctx context.Context, userID string) ([]familydomain.FamilyMemberProfile, error)
+	RemoveMember(ctx context.Context, actorID, memberID string) error
+	RevokeInvite(ctx context.Context, actorID, inviteID string) error
+	SetMemberSpendingLimit(ctx context.Context, actorID, memberID string, limit *float64) (*familydomain.FamilyMember, error)
+	UpdateFamily(ctx context.Context, userID string, input familydomain.UpdateFamilyInput) (*familydomain.Family, error)
+	UpdateMemberRole(ctx context.Context, actorID, memberID, role string) (*familydomain.FamilyMember, error)
+}
+
+// FeatureFlagsService is implemented by *featureflagsdomain.Service.
+type FeatureFlagsService interface {
+	Enabled(ctx context.Context, key, familyID string) (bool, error)
+}
+
+// HealthService is implemented by *healthdomain.Service.
+type HealthService interface {
+	Readiness(ctx context.Context) healthdomain.Report
+}
+
+// SyncService is implemented by *syncdomain.Service.
+type SyncService interface {
+	Events(ctx context.Context, familyID, cursor string, limit int) (*syncdomain.EventsPage, error)
+	ProcessBatch(ctx context.Context, input syncdomain.BatchInput) (*syncdomain.BatchResponse, error)
+	ResolveMappings(ctx context.Context, familyID, userID string, entity syncdomain.Entity, localIDs []string) (*syncdomain.MappingsResult, error)
+	Snapshot(ctx context.Context, familyID, userID string, writer syncdomain.SnapshotWriter) error
+	ValidateBatch(ctx context.Context, input syncdomain.BatchInput) (*syncdomain.BatchResponse, error)
+}
+
+// UserService is implemented by *userdomain.Service.
+type UserService interface {
+	GetPreferences(ctx context.Context, userID string) (userdomain.Preferences, error)
+	LoadAvatar(ctx context.Context, userID, file string) ([]byte, error)
+	UpdatePreferences(ctx context.Context, userID string, update userdomain.PreferencesUpdate) (userdomain.Preferences, error)
+	UploadAvatar(ctx context.Context, userID string, data []byte, baseURL string) (*userdomain.Profile, error)
+}
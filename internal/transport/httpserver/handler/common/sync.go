@@ -6,24 +6,25 @@ import (
 	"errors"
 	"io"
 	"net/http"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
-	familydomain "family-app-go/internal/domain/family"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	quotadomain "family-app-go/internal/domain/quota"
 	syncdomain "family-app-go/internal/domain/sync"
 	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
 )
 
 const (
 	minIdempotencyKeyLength = 8
 	maxIdempotencyKeyLength = 128
+	maxCategoryNameLength   = 50
 )
 
-var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-5][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`)
-
 type syncBatchRequest struct {
+	ClientTime string                 `json:"client_time"`
 	Operations []syncOperationRequest `json:"operations"`
 }
 
@@ -31,6 +32,7 @@ type syncOperationRequest struct {
 	OperationID string          `json:"operation_id"`
 	Type        string          `json:"type"`
 	LocalID     string          `json:"local_id"`
+	OccurredAt  string          `json:"occurred_at"`
 	Payload     json.RawMessage `json:"payload"`
 }
 
@@ -45,6 +47,14 @@ type syncCreateExpensePayloadRequest struct {
 	Currency    string   `json:"currency"`
 	Title       string   `json:"title"`
 	CategoryIDs []string `json:"category_ids"`
+	// CategoryLocalIDs name categories created by create_category operations.
+	CategoryLocalIDs []string `json:"category_local_ids"`
+}
+
+type syncCreateCategoryPayloadRequest struct {
+	Name  string  `json:"name"`
+	Color *string `json:"color"`
+	Emoji *string `json:"emoji"`
 }
 
 type syncSetTodoCompletedPayloadRequest struct {
@@ -57,13 +67,7 @@ func (h *Handlers) SyncBatch(w http.ResponseWriter, r *http.Request) {
 	startedAt := time.Now()
 
 	var req syncBatchRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
-		return
-	}
-
-	if len(req.Operations) == 0 {
-		writeError(w, http.StatusBadRequest, "invalid_request", "operations are required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 	if len(req.Operations) > syncdomain.MaxBatchOperations {
@@ -81,40 +85,70 @@ func (h *Handlers) SyncBatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	validateOnly, err := parseBoolParam(r.URL.Query().Get("validate"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid validate")
+		return
+	}
+	atomic, err := parseBoolParam(r.URL.Query().Get("atomic"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid atomic")
+		return
+	}
+
 	user, ok := middleware.UserFromContext(r.Context())
 	if !ok {
 		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.Flags.Enabled(r.Context(), featureflagsdomain.OfflineSync, family.ID)
 	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("sync.batch: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("sync.batch: get family failed", err, "user_id", user.ID)
+		RequestLog(r, h.log).InternalError("sync.batch: evaluate feature flag failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if !enabled {
+		RequestLog(r, h.log).BusinessError("sync.batch: offline sync disabled for family", featureflagsdomain.ErrFeatureDisabled, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusForbidden, "feature_disabled", "offline sync is disabled for this family")
+		return
+	}
+
+	clientTime, err := parseTimestampParam(req.ClientTime)
+	if err != nil {
+		writeValidationError(w, validation.FieldErr("client_time", validation.CodeFormat, "client_time must be RFC3339"))
+		return
+	}
 
 	operations := make([]syncdomain.OperationInput, 0, len(req.Operations))
 	for i, operation := range req.Operations {
 		parsed, err := parseSyncOperation(operation)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid_request", "invalid operation at index "+strconv.Itoa(i))
+			writeValidationError(w, validation.FieldErr("operations["+strconv.Itoa(i)+"]", validation.CodeInvalid, "invalid operation at index "+strconv.Itoa(i)))
 			return
 		}
 		operations = append(operations, parsed)
 	}
 
-	response, err := h.Sync.ProcessBatch(r.Context(), syncdomain.BatchInput{
+	process := h.Sync.ProcessBatch
+	if validateOnly {
+		process = h.Sync.ValidateBatch
+	}
+	response, err := process(r.Context(), syncdomain.BatchInput{
 		FamilyID:       family.ID,
 		BaseCurrency:   family.DefaultCurrency,
 		User:           syncdomain.UserSnapshot{ID: user.ID, Name: user.Name, Email: user.Email, AvatarURL: user.AvatarURL},
 		IdempotencyKey: idempotencyKey,
+		ClientTime:     clientTime,
+		Atomic:         atomic,
 		Operations:     operations,
+
+		AllowedCurrencies: family.Currencies(),
 	})
 	if err != nil {
 		logAttrs := []any{
@@ -122,27 +156,48 @@ func (h *Handlers) SyncBatch(w http.ResponseWriter, r *http.Request) {
 			"family_id", family.ID,
 			"operations", len(operations),
 			"has_idempotency_key", idempotencyKey != "",
+			"validate", validateOnly,
+			"atomic", atomic,
 			"duration_ms", time.Since(startedAt).Milliseconds(),
 		}
 
 		switch {
 		case errors.Is(err, syncdomain.ErrBatchTooLarge):
-			h.log.BusinessError("sync.batch: batch too large", err, logAttrs...)
+			RequestLog(r, h.log).BusinessError("sync.batch: batch too large", err, logAttrs...)
 			writeError(w, http.StatusRequestEntityTooLarge, "sync_batch_too_large", "too many operations in one batch")
 		case errors.Is(err, syncdomain.ErrIdempotencyKeyPayloadMismatch):
-			h.log.BusinessError("sync.batch: idempotency key payload mismatch", err, logAttrs...)
+			RequestLog(r, h.log).BusinessError("sync.batch: idempotency key payload mismatch", err, logAttrs...)
 			writeError(w, http.StatusConflict, "idempotency_key_payload_mismatch", "Idempotency-Key was already used with different payload")
 		case errors.Is(err, syncdomain.ErrBatchInProgress):
-			h.log.BusinessError("sync.batch: batch in progress", err, logAttrs...)
+			RequestLog(r, h.log).BusinessError("sync.batch: batch in progress", err, logAttrs...)
 			writeError(w, http.StatusConflict, "batch_in_progress", "sync batch is already in progress")
+		case errors.Is(err, quotadomain.ErrExceeded):
+			RequestLog(r, h.log).BusinessError("sync.batch: quota exceeded", err, logAttrs...)
+			writeQuotaExceeded(w, err)
 		default:
-			h.log.InternalError("sync.batch: process batch failed", err, logAttrs...)
+			RequestLog(r, h.log).InternalError("sync.batch: process batch failed", err, logAttrs...)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
 
-	h.log.Info(
+	if validateOnly {
+		RequestLog(r, h.log).Info(
+			"sync: validated",
+			"user_id", user.ID,
+			"family_id", family.ID,
+			"status", response.Status,
+			"total", response.Summary.Total,
+			"valid", response.Summary.Valid,
+			"duplicate", response.Summary.Duplicate,
+			"failed", response.Summary.Failed,
+			"duration_ms", time.Since(startedAt).Milliseconds(),
+		)
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	RequestLog(r, h.log).Info(
 		"sync: completed",
 		"sync_id",
 		response.SyncID,
@@ -160,6 +215,8 @@ func (h *Handlers) SyncBatch(w http.ResponseWriter, r *http.Request) {
 		response.Summary.Duplicate,
 		"failed",
 		response.Summary.Failed,
+		"atomic",
+		atomic,
 		"has_idempotency_key",
 		idempotencyKey != "",
 		"duration_ms",
@@ -169,27 +226,84 @@ func (h *Handlers) SyncBatch(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, response)
 }
 
+// SyncMappings resolves local IDs the caller uploaded earlier to server IDs,
+// e.g. GET /sync/mappings?entity=todo_item&local_ids=a,b.
+func (h *Handlers) SyncMappings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	entity := syncdomain.Entity(strings.TrimSpace(query.Get("entity")))
+	if entity == "" {
+		writeValidationError(w, validation.FieldErr("entity", validation.CodeRequired, "entity is required"))
+		return
+	}
+	switch entity {
+	case syncdomain.EntityExpense, syncdomain.EntityTodoItem, syncdomain.EntityCategory:
+	default:
+		writeValidationError(w, validation.FieldErr("entity", validation.CodeEnum, "entity must be one of expense, todo_item, category"))
+		return
+	}
+	localIDs := parseCSV(query.Get("local_ids"))
+	if len(localIDs) == 0 {
+		writeValidationError(w, validation.FieldErr("local_ids", validation.CodeRequired, "local_ids is required"))
+		return
+	}
+	if len(localIDs) > syncdomain.MaxMappingLocalIDs {
+		writeValidationError(w, validation.FieldErr("local_ids", validation.CodeRange, "local_ids must hold at most "+strconv.Itoa(syncdomain.MaxMappingLocalIDs)+" ids"))
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.Flags.Enabled(r.Context(), featureflagsdomain.OfflineSync, family.ID)
+	if err != nil {
+		RequestLog(r, h.log).InternalError("sync.mappings: evaluate feature flag failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	if !enabled {
+		RequestLog(r, h.log).BusinessError("sync.mappings: offline sync disabled for family", featureflagsdomain.ErrFeatureDisabled, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusForbidden, "feature_disabled", "offline sync is disabled for this family")
+		return
+	}
+
+	result, err := h.Sync.ResolveMappings(r.Context(), family.ID, user.ID, entity, localIDs)
+	if err != nil {
+		RequestLog(r, h.log).InternalError("sync.mappings: resolve failed", err, "user_id", user.ID, "family_id", family.ID, "entity", entity, "local_ids", len(localIDs))
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// parseSyncOperation maps an operation that already passed validation.
 func parseSyncOperation(operation syncOperationRequest) (syncdomain.OperationInput, error) {
 	operationID := strings.TrimSpace(operation.OperationID)
-	if !isUUID(operationID) {
-		return syncdomain.OperationInput{}, errors.New("invalid operation_id")
-	}
 
 	operationType := syncdomain.OperationType(strings.TrimSpace(operation.Type))
 	localID := strings.TrimSpace(operation.LocalID)
+	occurredAt, err := parseTimestampParam(operation.OccurredAt)
+	if err != nil {
+		return syncdomain.OperationInput{}, err
+	}
 
 	result := syncdomain.OperationInput{
 		OperationID: operationID,
 		Type:        operationType,
 		LocalID:     localID,
+		OccurredAt:  occurredAt,
 	}
 
 	switch operationType {
 	case syncdomain.OperationTypeCreateExpense:
-		if localID == "" {
-			return syncdomain.OperationInput{}, errors.New("local_id is required")
-		}
-
 		var payload syncCreateExpensePayloadRequest
 		if err := decodePayload(operation.Payload, &payload); err != nil {
 			return syncdomain.OperationInput{}, err
@@ -199,40 +313,35 @@ func parseSyncOperation(operation syncOperationRequest) (syncdomain.OperationInp
 		if err != nil {
 			return syncdomain.OperationInput{}, err
 		}
-		if payload.Amount <= 0 {
-			return syncdomain.OperationInput{}, errors.New("amount must be positive")
-		}
-		if strings.TrimSpace(payload.Currency) == "" {
-			return syncdomain.OperationInput{}, errors.New("currency is required")
-		}
-		if strings.TrimSpace(payload.Title) == "" {
-			return syncdomain.OperationInput{}, errors.New("title is required")
-		}
 
 		result.CreateExpense = &syncdomain.CreateExpensePayload{
-			Date:        date,
-			Amount:      payload.Amount,
-			Currency:    payload.Currency,
-			Title:       payload.Title,
-			CategoryIDs: payload.CategoryIDs,
+			Date:             date,
+			Amount:           payload.Amount,
+			Currency:         payload.Currency,
+			Title:            payload.Title,
+			CategoryIDs:      payload.CategoryIDs,
+			CategoryLocalIDs: payload.CategoryLocalIDs,
 		}
 		return result, nil
 
-	case syncdomain.OperationTypeCreateTodo:
-		if localID == "" {
-			return syncdomain.OperationInput{}, errors.New("local_id is required")
+	case syncdomain.OperationTypeCreateCategory:
+		var payload syncCreateCategoryPayloadRequest
+		if err := decodePayload(operation.Payload, &payload); err != nil {
+			return syncdomain.OperationInput{}, err
 		}
 
+		result.CreateCategory = &syncdomain.CreateCategoryPayload{
+			Name:  payload.Name,
+			Color: payload.Color,
+			Emoji: payload.Emoji,
+		}
+		return result, nil
+
+	case syncdomain.OperationTypeCreateTodo:
 		var payload syncCreateTodoPayloadRequest
 		if err := decodePayload(operation.Payload, &payload); err != nil {
 			return syncdomain.OperationInput{}, err
 		}
-		if strings.TrimSpace(payload.ListID) == "" {
-			return syncdomain.OperationInput{}, errors.New("list_id is required")
-		}
-		if strings.TrimSpace(payload.Title) == "" {
-			return syncdomain.OperationInput{}, errors.New("title is required")
-		}
 
 		result.CreateTodo = &syncdomain.CreateTodoPayload{
 			ListID: payload.ListID,
@@ -245,15 +354,8 @@ func parseSyncOperation(operation syncOperationRequest) (syncdomain.OperationInp
 		if err := decodePayload(operation.Payload, &payload); err != nil {
 			return syncdomain.OperationInput{}, err
 		}
-		if payload.IsCompleted == nil {
-			return syncdomain.OperationInput{}, errors.New("is_completed is required")
-		}
-
 		todoID := normalizeStringPtr(payload.TodoID)
 		todoLocalID := normalizeStringPtr(payload.TodoLocalID)
-		if todoID == nil && todoLocalID == nil {
-			return syncdomain.OperationInput{}, errors.New("todo_id or todo_local_id is required")
-		}
 
 		result.SetTodoCompleted = &syncdomain.SetTodoCompletedPayload{
 			TodoID:      valueOrEmptyPtr(todoID),
@@ -279,10 +381,6 @@ func decodePayload(raw json.RawMessage, dst interface{}) error {
 	return nil
 }
 
-func isUUID(value string) bool {
-	return uuidRegex.MatchString(strings.TrimSpace(value))
-}
-
 func normalizeStringPtr(value *string) *string {
 	if value == nil {
 		return nil
@@ -0,0 +1,240 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	familydomain "family-app-go/internal/domain/family"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	syncdomain "family-app-go/internal/domain/sync"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+// snapshotFlushEvery is how many records a JSON snapshot sends per chunk.
+// NDJSON snapshots flush after every record.
+const snapshotFlushEvery = 500
+
+// ndjsonContentType is the newline-delimited JSON snapshot format.
+const ndjsonContentType = "application/x-ndjson"
+
+type snapshotRecordResponse struct {
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data"`
+}
+
+type snapshotHeadResponse struct {
+	Cursor string `json:"cursor"`
+}
+
+type snapshotEndResponse struct {
+	End     bool `json:"end"`
+	Records int  `json:"records"`
+}
+
+// SyncEvents replays the family's change log after a cursor, oldest first.
+// A cursor older than the retention window answers 410 resync_required; the
+// client then bootstraps again from GET /sync/snapshot.
+func (h *Handlers) SyncEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cursor := query.Get("cursor")
+	if cursor == "" {
+		writeValidationError(w, validation.FieldErr("cursor", validation.CodeRequired, "cursor is required"))
+		return
+	}
+	limit, err := parseIntParam(query.Get("limit"), syncdomain.DefaultEventLimit)
+	if err != nil || limit <= 0 || limit > syncdomain.MaxEventLimit {
+		writeValidationError(w, validation.FieldErr("limit", validation.CodeRange, "limit must be between 1 and "+strconv.Itoa(syncdomain.MaxEventLimit)))
+		return
+	}
+
+	user, family, ok := h.offlineSyncFamily(w, r, "sync.events")
+	if !ok {
+		return
+	}
+
+	page, err := h.Sync.Events(r.Context(), family.ID, cursor, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, syncdomain.ErrInvalidCursor):
+			writeValidationError(w, validation.FieldErr("cursor", validation.CodeInvalid, "cursor is not a cursor of this family's change feed"))
+		case errors.Is(err, syncdomain.ErrCursorExpired):
+			RequestLog(r, h.log).BusinessError("sync.events: cursor expired", err, "user_id", user.ID, "family_id", family.ID, "cursor", cursor)
+			writeError(w, http.StatusGone, "resync_required", "events after this cursor were purged; fetch a new snapshot")
+		default:
+			RequestLog(r, h.log).InternalError("sync.events: list events failed", err, "user_id", user.ID, "family_id", family.ID, "cursor", cursor)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	if page.Events == nil {
+		page.Events = []syncdomain.ChangeEvent{}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// SyncSnapshot streams everything a new device needs in one response, with
+// the cursor to replay events from afterwards. The body is written while the
+// database is read, flushed every snapshotFlushEvery records; a failure after
+// the first byte aborts the response, so clients never see a truncated body
+// as complete JSON.
+//
+// With ?format=ndjson or Accept: application/x-ndjson the snapshot is sent as
+// one JSON object per line instead: the cursor, the records, then an end line
+// with the record count. Each line is flushed as it is written, through the
+// gzip writer when the client accepts it.
+func (h *Handlers) SyncSnapshot(w http.ResponseWriter, r *http.Request) {
+	ndjson, ok := snapshotNDJSON(r)
+	if !ok {
+		writeValidationError(w, validation.FieldErr("format", validation.CodeEnum, "format must be one of: json, ndjson"))
+		return
+	}
+
+	user, family, ok := h.offlineSyncFamily(w, r, "sync.snapshot")
+	if !ok {
+		return
+	}
+
+	writer := &snapshotWriter{w: w, ndjson: ndjson}
+	if err := h.Sync.Snapshot(r.Context(), family.ID, user.ID, writer); err != nil {
+		RequestLog(r, h.log).InternalError("sync.snapshot: read snapshot failed", err, "user_id", user.ID, "family_id", family.ID, "records", writer.records)
+		if !writer.started {
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+		panic(http.ErrAbortHandler)
+	}
+	if err := writer.End(); err != nil {
+		RequestLog(r, h.log).BusinessError("sync.snapshot: write failed", err, "user_id", user.ID, "family_id", family.ID)
+	}
+}
+
+// snapshotNDJSON reports whether the caller asked for an NDJSON snapshot. An
+// explicit format parameter wins over the Accept header; false in the second
+// result means the format is unknown.
+func snapshotNDJSON(r *http.Request) (bool, bool) {
+	switch r.URL.Query().Get("format") {
+	case "ndjson":
+		return true, true
+	case "json":
+		return false, true
+	case "":
+	default:
+		return false, false
+	}
+	for _, value := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(value, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err == nil && mediaType == ndjsonContentType {
+				return true, true
+			}
+		}
+	}
+	return false, true
+}
+
+// offlineSyncFamily resolves the caller and their family and checks that
+// offline sync is enabled for it, answering the request otherwise.
+func (h *Handlers) offlineSyncFamily(w http.ResponseWriter, r *http.Request, operation string) (middleware.User, *familydomain.Family, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, false
+	}
+
+	enabled, err := h.Flags.Enabled(r.Context(), featureflagsdomain.OfflineSync, family.ID)
+	if err != nil {
+		RequestLog(r, h.log).InternalError(operation+": evaluate feature flag failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return middleware.User{}, nil, false
+	}
+	if !enabled {
+		RequestLog(r, h.log).BusinessError(operation+": offline sync disabled for family", featureflagsdomain.ErrFeatureDisabled, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusForbidden, "feature_disabled", "offline sync is disabled for this family")
+		return middleware.User{}, nil, false
+	}
+	return user, family, true
+}
+
+// snapshotWriter writes a snapshot as {"cursor": ..., "records": [...]}, or
+// as NDJSON lines. SyncSnapshot calls End once every record is written.
+type snapshotWriter struct {
+	w       http.ResponseWriter
+	ndjson  bool
+	started bool
+	records int
+}
+
+func (s *snapshotWriter) Begin(cursor string) error {
+	var payload []byte
+	if s.ndjson {
+		line, err := json.Marshal(snapshotHeadResponse{Cursor: cursor})
+		if err != nil {
+			return err
+		}
+		payload = append(line, '\n')
+		s.w.Header().Set("Content-Type", ndjsonContentType)
+	} else {
+		head, err := json.Marshal(cursor)
+		if err != nil {
+			return err
+		}
+		payload = append(append([]byte(`{"cursor":`), head...), `,"records":[`...)
+		s.w.Header().Set("Content-Type", "application/json")
+	}
+	s.w.WriteHeader(http.StatusOK)
+	s.started = true
+	return s.write(payload, s.ndjson)
+}
+
+func (s *snapshotWriter) Write(record syncdomain.SnapshotRecord) error {
+	payload, err := json.Marshal(snapshotRecordResponse{Type: string(record.Entity), Data: record.Data})
+	if err != nil {
+		return err
+	}
+	switch {
+	case s.ndjson:
+		payload = append(payload, '\n')
+	case s.records > 0:
+		payload = append([]byte(",\n"), payload...)
+	default:
+		payload = append([]byte("\n"), payload...)
+	}
+	s.records++
+	return s.write(payload, s.ndjson || s.records%snapshotFlushEvery == 0)
+}
+
+// End finishes the body: the closing brackets of the JSON object, or the end
+// line of an NDJSON stream.
+func (s *snapshotWriter) End() error {
+	if !s.ndjson {
+		return s.write([]byte("]}\n"), false)
+	}
+	line, err := json.Marshal(snapshotEndResponse{End: true, Records: s.records})
+	if err != nil {
+		return err
+	}
+	return s.write(append(line, '\n'), true)
+}
+
+func (s *snapshotWriter) write(payload []byte, flush bool) error {
+	if _, err := s.w.Write(payload); err != nil {
+		return err
+	}
+	if flush {
+		if flusher, ok := s.w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,501 @@
+package common
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	quotadomain "family-app-go/internal/domain/quota"
+	syncdomain "family-app-go/internal/domain/sync"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	testOperationID = "44444444-4444-4444-4444-444444444444"
+	testTodoID      = "55555555-5555-5555-5555-555555555555"
+)
+
+type mockSync struct {
+	SyncService
+	err       error
+	batch     *syncdomain.BatchResponse
+	validated bool
+	input     syncdomain.BatchInput
+	entity    syncdomain.Entity
+	localIDs  []string
+	// snapshot is nil when Snapshot fails before writing anything.
+	snapshot *snapshotFixture
+}
+
+type snapshotFixture struct {
+	cursor  string
+	records []syncdomain.SnapshotRecord
+}
+
+func (m *mockSync) ProcessBatch(_ context.Context, input syncdomain.BatchInput) (*syncdomain.BatchResponse, error) {
+	m.input = input
+	return m.response()
+}
+
+func (m *mockSync) ValidateBatch(_ context.Context, input syncdomain.BatchInput) (*syncdomain.BatchResponse, error) {
+	m.input = input
+	m.validated = true
+	return m.response()
+}
+
+func (m *mockSync) response() (*syncdomain.BatchResponse, error) {
+	if m.err != nil || m.batch != nil {
+		return m.batch, m.err
+	}
+	entity := syncdomain.EntityTodoItem
+	serverID := testTodoID
+	return &syncdomain.BatchResponse{
+		SyncID:  "sync-1",
+		Status:  syncdomain.BatchStatusSuccess,
+		Summary: syncdomain.BatchSummary{Total: 1, Applied: 1},
+		Results: []syncdomain.OperationResult{{
+			OperationID: testOperationID,
+			Type:        syncdomain.OperationTypeCreateTodo,
+			Status:      syncdomain.ResultStatusApplied,
+			Entity:      &entity,
+			ServerID:    &serverID,
+		}},
+		Mappings:   []syncdomain.EntityMapping{{Entity: entity, LocalID: "todo-1", ServerID: serverID}},
+		ServerTime: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *mockSync) ResolveMappings(_ context.Context, _, _ string, entity syncdomain.Entity, localIDs []string) (*syncdomain.MappingsResult, error) {
+	m.entity = entity
+	m.localIDs = localIDs
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &syncdomain.MappingsResult{
+		Entity:   entity,
+		Mappings: []syncdomain.EntityMapping{{Entity: entity, LocalID: localIDs[0], ServerID: testTodoID}},
+		Missing:  localIDs[1:],
+	}, nil
+}
+
+func (m *mockSync) Snapshot(_ context.Context, _, _ string, writer syncdomain.SnapshotWriter) error {
+	if m.snapshot == nil {
+		return m.err
+	}
+	if err := writer.Begin(m.snapshot.cursor); err != nil {
+		return err
+	}
+	for _, record := range m.snapshot.records {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+func syncOperation(operationID, opType, payload string) string {
+	return fmt.Sprintf(`{"operation_id":%q,"type":%q,"local_id":"todo-1","occurred_at":"2026-03-01T11:59:00Z","payload":%s}`, operationID, opType, payload)
+}
+
+func syncBody(operations ...string) string {
+	return `{"client_time":"2026-03-01T12:00:00Z","operations":[` + strings.Join(operations, ",") + `]}`
+}
+
+func TestSyncBatchValidation(t *testing.T) {
+	createTodo := syncOperation(testOperationID, "create_todo", `{"list_id":"list-1","title":"Milk"}`)
+	tooMany := make([]string, syncdomain.MaxBatchOperations+1)
+	for i := range tooMany {
+		tooMany[i] = createTodo
+	}
+
+	cases := []struct {
+		name   string
+		target string
+		key    string
+		body   string
+		status int
+		code   string
+		field  string
+	}{
+		{name: "no operations", body: syncBody(), status: http.StatusBadRequest, code: "invalid_request", field: "operations"},
+		{name: "bad client time", body: `{"client_time":"yesterday","operations":[` + createTodo + `]}`, status: http.StatusBadRequest, code: "invalid_request", field: "client_time"},
+		{name: "bad operation id", body: syncBody(syncOperation("op-1", "create_todo", `{"list_id":"list-1","title":"Milk"}`)), status: http.StatusBadRequest, code: "invalid_request", field: "operations[0].operation_id"},
+		{name: "bad payload", body: syncBody(syncOperation(testOperationID, "create_todo", `[]`)), status: http.StatusBadRequest, code: "invalid_request", field: "operations[0].payload"},
+		{name: "payload field", body: syncBody(syncOperation(testOperationID, "create_todo", `{"list_id":"list-1"}`)), status: http.StatusBadRequest, code: "invalid_request", field: "operations[0].payload.title"},
+		{name: "too many operations", body: syncBody(tooMany...), status: http.StatusRequestEntityTooLarge, code: "sync_batch_too_large"},
+		{name: "short idempotency key", key: "abc", body: syncBody(createTodo), status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "long idempotency key", key: strings.Repeat("k", maxIdempotencyKeyLength+1), body: syncBody(createTodo), status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "bad validate", target: "/api/sync/batch?validate=maybe", body: syncBody(createTodo), status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "bad atomic", target: "/api/sync/batch?atomic=maybe", body: syncBody(createTodo), status: http.StatusBadRequest, code: "invalid_request"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := tc.target
+			if target == "" {
+				target = "/api/sync/batch"
+			}
+			req := familyRequest(http.MethodPost, target, tc.body)
+			if tc.key != "" {
+				req.Header.Set("Idempotency-Key", tc.key)
+			}
+			deps := newTestDeps()
+			rec := httptest.NewRecorder()
+			deps.handlers().SyncBatch(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			body := decodeError(t, rec)
+			if body.Code != tc.code {
+				t.Fatalf("expected %s, got %s", tc.code, body.Code)
+			}
+			if tc.field != "" && (len(body.Fields) == 0 || body.Fields[0].Field != tc.field) {
+				t.Fatalf("expected a %q field error, got %+v", tc.field, body.Fields)
+			}
+		})
+	}
+}
+
+func TestSyncBatchRequiresFamilyAndFlag(t *testing.T) {
+	body := syncBody(syncOperation(testOperationID, "create_todo", `{"list_id":"list-1","title":"Milk"}`))
+
+	deps := newTestDeps()
+	rec := httptest.NewRecorder()
+	deps.handlers().SyncBatch(rec, userRequest(http.MethodPost, "/api/sync/batch", body))
+	if rec.Code != http.StatusNotFound || decodeError(t, rec).Code != "family_not_found" {
+		t.Fatalf("expected 404 family_not_found, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	deps = newTestDeps()
+	deps.flags.disabled = true
+	rec = httptest.NewRecorder()
+	deps.handlers().SyncBatch(rec, familyRequest(http.MethodPost, "/api/sync/batch", body))
+	if rec.Code != http.StatusForbidden || decodeError(t, rec).Code != "feature_disabled" {
+		t.Fatalf("expected 403 feature_disabled, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	deps = newTestDeps()
+	deps.flags.err = errDatabase
+	rec = httptest.NewRecorder()
+	deps.handlers().SyncBatch(rec, familyRequest(http.MethodPost, "/api/sync/batch", body))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 when the flag cannot be read, got %d", rec.Code)
+	}
+}
+
+func TestSyncBatchMapsErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{syncdomain.ErrBatchTooLarge, http.StatusRequestEntityTooLarge, "sync_batch_too_large"},
+		{syncdomain.ErrIdempotencyKeyPayloadMismatch, http.StatusConflict, "idempotency_key_payload_mismatch"},
+		{syncdomain.ErrBatchInProgress, http.StatusConflict, "batch_in_progress"},
+		{&quotadomain.ExceededError{Quota: quotadomain.SyncOperationsPerHour, Limit: 100, Window: quotadomain.SyncOperationsWindow}, http.StatusTooManyRequests, "quota_exceeded"},
+		{errDatabase, http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.sync.err = tc.err
+			body := syncBody(syncOperation(testOperationID, "create_todo", `{"list_id":"list-1","title":"Milk"}`))
+			rec := httptest.NewRecorder()
+			deps.handlers().SyncBatch(rec, familyRequest(http.MethodPost, "/api/sync/batch", body))
+
+			if rec.Code != tc.status || decodeError(t, rec).Code != tc.code {
+				t.Fatalf("expected %d %s, got %d: %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSyncBatch(t *testing.T) {
+	body := syncBody(
+		syncOperation(testOperationID, "create_todo", `{"list_id":"list-1","title":"Milk"}`),
+		syncOperation("66666666-6666-6666-6666-666666666666", "set_todo_completed", `{"todo_local_id":"todo-1","is_completed":true}`),
+	)
+	req := familyRequest(http.MethodPost, "/api/sync/batch?atomic=true", body)
+	req.Header.Set("Idempotency-Key", "batch-key-1")
+	deps := newTestDeps()
+	rec := httptest.NewRecorder()
+	deps.handlers().SyncBatch(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if deps.sync.validated {
+		t.Fatal("expected ProcessBatch, got ValidateBatch")
+	}
+	input := deps.sync.input
+	if input.FamilyID != testFamilyID || input.BaseCurrency != "EUR" || input.User.ID != testUserID || input.User.Name != "Alex" {
+		t.Fatalf("unexpected batch owner: %+v", input)
+	}
+	if input.IdempotencyKey != "batch-key-1" || !input.Atomic || input.ClientTime == nil {
+		t.Fatalf("unexpected batch options: %+v", input)
+	}
+	if len(input.AllowedCurrencies) != 1 || input.AllowedCurrencies[0] != "USD" {
+		t.Fatalf("expected the family allowed currencies, got %v", input.AllowedCurrencies)
+	}
+	if len(input.Operations) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(input.Operations))
+	}
+	createTodo := input.Operations[0]
+	if createTodo.Type != syncdomain.OperationTypeCreateTodo || createTodo.LocalID != "todo-1" || createTodo.OccurredAt == nil || createTodo.CreateTodo == nil || createTodo.CreateTodo.Title != "Milk" {
+		t.Fatalf("unexpected create_todo operation: %+v", createTodo)
+	}
+	completed := input.Operations[1].SetTodoCompleted
+	if completed == nil || completed.TodoLocalID != "todo-1" || !completed.IsCompleted {
+		t.Fatalf("unexpected set_todo_completed payload: %+v", completed)
+	}
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	for _, key := range []string{"sync_id", "status", "summary", "results", "mappings", "server_time"} {
+		if _, ok := response[key]; !ok {
+			t.Fatalf("expected %q in %s", key, rec.Body.String())
+		}
+	}
+}
+
+func TestSyncBatchValidateOnly(t *testing.T) {
+	deps := newTestDeps()
+	body := syncBody(syncOperation(testOperationID, "create_todo", `{"list_id":"list-1","title":"Milk"}`))
+	rec := httptest.NewRecorder()
+	deps.handlers().SyncBatch(rec, familyRequest(http.MethodPost, "/api/sync/batch?validate=true", body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !deps.sync.validated {
+		t.Fatal("expected ValidateBatch, got ProcessBatch")
+	}
+}
+
+func TestSyncMappings(t *testing.T) {
+	tooMany := make([]string, syncdomain.MaxMappingLocalIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("todo-%d", i)
+	}
+
+	cases := []struct {
+		name   string
+		query  string
+		status int
+		field  string
+	}{
+		{name: "missing entity", query: "local_ids=a", status: http.StatusBadRequest, field: "entity"},
+		{name: "unknown entity", query: "entity=workout&local_ids=a", status: http.StatusBadRequest, field: "entity"},
+		{name: "missing local ids", query: "entity=todo_item&local_ids=,", status: http.StatusBadRequest, field: "local_ids"},
+		{name: "too many local ids", query: "entity=todo_item&local_ids=" + strings.Join(tooMany, ","), status: http.StatusBadRequest, field: "local_ids"},
+		{name: "resolved", query: "entity=todo_item&local_ids=todo-1,todo-2", status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			rec := httptest.NewRecorder()
+			deps.handlers().SyncMappings(rec, familyRequest(http.MethodGet, "/api/sync/mappings?"+tc.query, ""))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.field != "" {
+				body := decodeError(t, rec)
+				if body.Code != "invalid_request" || len(body.Fields) == 0 || body.Fields[0].Field != tc.field {
+					t.Fatalf("expected a %q field error, got %+v", tc.field, body)
+				}
+				return
+			}
+			var result syncdomain.MappingsResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if deps.sync.entity != syncdomain.EntityTodoItem || len(result.Mappings) != 1 || len(result.Missing) != 1 || result.Missing[0] != "todo-2" {
+				t.Fatalf("unexpected result: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSyncMappingsFeatureDisabled(t *testing.T) {
+	deps := newTestDeps()
+	deps.flags.disabled = true
+	rec := httptest.NewRecorder()
+	deps.handlers().SyncMappings(rec, familyRequest(http.MethodGet, "/api/sync/mappings?entity=expense&local_ids=a", ""))
+
+	if rec.Code != http.StatusForbidden || decodeError(t, rec).Code != "feature_disabled" {
+		t.Fatalf("expected 403 feature_disabled, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestSyncSnapshot(t *testing.T) {
+	deps := newTestDeps()
+	deps.sync.snapshot = &snapshotFixture{cursor: "7", records: []syncdomain.SnapshotRecord{
+		{Entity: syncdomain.EntityCategory, Data: map[string]interface{}{"id": "cat-1", "name": "Groceries"}},
+		{Entity: syncdomain.EntityExpense, Data: map[string]interface{}{"id": "exp-1"}},
+	}}
+	rec := httptest.NewRecorder()
+	deps.handlers().SyncSnapshot(rec, familyRequest(http.MethodGet, "/api/sync/snapshot", ""))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected 200 JSON, got %d %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	var body struct {
+		Cursor  string `json:"cursor"`
+		Records []struct {
+			Type string                 `json:"type"`
+			Data map[string]interface{} `json:"data"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", rec.Body.String(), err)
+	}
+	// The cursor is where GET /sync/events picks up after the snapshot.
+	if body.Cursor != "7" || len(body.Records) != 2 || body.Records[0].Type != "category" || body.Records[1].Data["id"] != "exp-1" {
+		t.Fatalf("unexpected snapshot: %s", rec.Body.String())
+	}
+}
+
+func TestSyncSnapshotFailures(t *testing.T) {
+	t.Run("before the first byte", func(t *testing.T) {
+		deps := newTestDeps()
+		deps.sync.err = errDatabase
+		rec := httptest.NewRecorder()
+		deps.handlers().SyncSnapshot(rec, familyRequest(http.MethodGet, "/api/sync/snapshot", ""))
+
+		if rec.Code != http.StatusInternalServerError || decodeError(t, rec).Code != "internal_error" {
+			t.Fatalf("expected 500 internal_error, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("midway", func(t *testing.T) {
+		deps := newTestDeps()
+		deps.sync.snapshot = &snapshotFixture{cursor: "7", records: []syncdomain.SnapshotRecord{{Entity: syncdomain.EntityExpense, Data: map[string]interface{}{"id": "exp-1"}}}}
+		deps.sync.err = errDatabase
+		defer func() {
+			if recovered := recover(); recovered != http.ErrAbortHandler {
+				t.Fatalf("expected the response aborted, got %v", recovered)
+			}
+		}()
+		deps.handlers().SyncSnapshot(httptest.NewRecorder(), familyRequest(http.MethodGet, "/api/sync/snapshot", ""))
+	})
+
+	t.Run("feature disabled", func(t *testing.T) {
+		deps := newTestDeps()
+		deps.flags.disabled = true
+		rec := httptest.NewRecorder()
+		deps.handlers().SyncSnapshot(rec, familyRequest(http.MethodGet, "/api/sync/snapshot", ""))
+
+		if rec.Code != http.StatusForbidden || decodeError(t, rec).Code != "feature_disabled" {
+			t.Fatalf("expected 403 feature_disabled, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+// flushRecorder counts the flushes a streamed response asks for.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func ndjsonLines(t *testing.T, body string) []map[string]json.RawMessage {
+	t.Helper()
+	var lines []map[string]json.RawMessage
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var line map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestSyncSnapshotNDJSON(t *testing.T) {
+	records := []syncdomain.SnapshotRecord{
+		{Entity: syncdomain.EntityCategory, Data: map[string]interface{}{"id": "cat-1"}},
+		{Entity: syncdomain.EntityExpense, Data: map[string]interface{}{"id": "exp-1"}},
+	}
+	cases := []struct {
+		name   string
+		target string
+		accept string
+	}{
+		{name: "format parameter", target: "/api/sync/snapshot?format=ndjson", accept: "application/json"},
+		{name: "accept header", target: "/api/sync/snapshot", accept: "application/json;q=0.5, application/x-ndjson"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.sync.snapshot = &snapshotFixture{cursor: "7", records: records}
+			req := familyRequest(http.MethodGet, tc.target, "")
+			req.Header.Set("Accept", tc.accept)
+			rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+			deps.handlers().SyncSnapshot(rec, req)
+
+			if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-ndjson" {
+				t.Fatalf("expected 200 NDJSON, got %d %q: %s", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+			}
+			lines := ndjsonLines(t, rec.Body.String())
+			if len(lines) != 4 || string(lines[0]["cursor"]) != `"7"` || string(lines[2]["type"]) != `"expense"` || string(lines[3]["records"]) != "2" {
+				t.Fatalf("unexpected stream: %s", rec.Body.String())
+			}
+			// Head, each record and the end line are flushed as written.
+			if rec.flushes != 4 {
+				t.Fatalf("expected 4 flushes, got %d", rec.flushes)
+			}
+		})
+	}
+}
+
+func TestSyncSnapshotNDJSONIsGzipped(t *testing.T) {
+	deps := newTestDeps()
+	deps.sync.snapshot = &snapshotFixture{cursor: "7", records: []syncdomain.SnapshotRecord{{Entity: syncdomain.EntityExpense, Data: map[string]interface{}{"id": "exp-1"}}}}
+	req := familyRequest(http.MethodGet, "/api/sync/snapshot?format=ndjson", "")
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	chimw.Compress(5, "application/x-ndjson")(http.HandlerFunc(deps.handlers().SyncSnapshot)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzipped 200, got %d %q", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	var body strings.Builder
+	if _, err := io.Copy(&body, reader); err != nil {
+		t.Fatalf("gunzip: %v", err)
+	}
+	if lines := ndjsonLines(t, body.String()); len(lines) != 3 || string(lines[2]["end"]) != "true" {
+		t.Fatalf("unexpected stream: %s", body.String())
+	}
+}
+
+func TestSyncSnapshotRejectsUnknownFormat(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestDeps().handlers().SyncSnapshot(rec, familyRequest(http.MethodGet, "/api/sync/snapshot?format=csv", ""))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body := decodeError(t, rec); len(body.Fields) == 0 || body.Fields[0].Field != "format" {
+		t.Fatalf("expected a format field error, got %+v", body)
+	}
+}
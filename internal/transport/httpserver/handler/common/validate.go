@@ -0,0 +1,125 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	syncdomain "family-app-go/internal/domain/sync"
+	"family-app-go/internal/transport/httpserver/validation"
+	"family-app-go/pkg/id"
+)
+
+func (req createFamilyRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+}
+
+func (req joinFamilyRequest) Validate(v *validation.Validator) {
+	v.Required("token", req.Token)
+}
+
+func (req updateFamilyRequest) Validate(v *validation.Validator) {
+	if req.Name == nil && req.DefaultCurrency == nil && req.AllowedCurrencies == nil {
+		v.Add("", validation.CodeEmpty, "at least one field is required")
+		return
+	}
+	v.NotBlank("name", req.Name)
+	v.CurrencyCode("default_currency", req.DefaultCurrency)
+	if req.AllowedCurrencies != nil {
+		v.Check(len(*req.AllowedCurrencies) <= familydomain.MaxAllowedCurrencies, "allowed_currencies", validation.CodeTooLong, fmt.Sprintf("allowed_currencies must list at most %d currencies", familydomain.MaxAllowedCurrencies))
+		for i, currency := range *req.AllowedCurrencies {
+			field := fmt.Sprintf("allowed_currencies[%d]", i)
+			v.Required(field, currency)
+			v.Currency(field, currency)
+		}
+	}
+}
+
+func (req updateFamilyMemberRequest) Validate(v *validation.Validator) {
+	v.Required("role", req.Role)
+}
+
+func (req createInviteRequest) Validate(v *validation.Validator) {
+	v.Between("expires_in_hours", req.ExpiresInHours, int(familydomain.MinInviteTTL/time.Hour), int(familydomain.MaxInviteTTL/time.Hour))
+	v.Between("max_uses", req.MaxUses, 1, familydomain.MaxInviteUses)
+}
+
+func (req updatePreferencesRequest) Validate(v *validation.Validator) {
+	notifications := req.Notifications != nil &&
+		(req.Notifications.TodoReminders != nil || req.Notifications.GymNudges != nil || req.Notifications.FamilyActivity != nil)
+	if req.Theme == nil && req.Language == nil && req.WeightUnit == nil && !notifications {
+		v.Add("", validation.CodeEmpty, "no fields to update")
+	}
+}
+
+func (req syncBatchRequest) Validate(v *validation.Validator) {
+	v.Timestamp("client_time", &req.ClientTime)
+	if len(req.Operations) == 0 {
+		v.Add("operations", validation.CodeRequired, "operations are required")
+		return
+	}
+	// Oversized batches are rejected with 413 by the handler.
+	if len(req.Operations) > syncdomain.MaxBatchOperations {
+		return
+	}
+	for i, operation := range req.Operations {
+		v.Nested(fmt.Sprintf("operations[%d]", i), operation)
+	}
+}
+
+func (req syncOperationRequest) Validate(v *validation.Validator) {
+	v.Required("operation_id", req.OperationID)
+	v.Check(req.OperationID == "" || id.IsUUID(strings.TrimSpace(req.OperationID)), "operation_id", validation.CodeFormat, "operation_id must be a uuid")
+	v.Timestamp("occurred_at", &req.OccurredAt)
+
+	var payload validation.Validatable
+	switch syncdomain.OperationType(strings.TrimSpace(req.Type)) {
+	case syncdomain.OperationTypeCreateExpense:
+		v.Required("local_id", req.LocalID)
+		payload = &syncCreateExpensePayloadRequest{}
+	case syncdomain.OperationTypeCreateTodo:
+		v.Required("local_id", req.LocalID)
+		payload = &syncCreateTodoPayloadRequest{}
+	case syncdomain.OperationTypeSetTodoCompleted:
+		payload = &syncSetTodoCompletedPayloadRequest{}
+	case syncdomain.OperationTypeCreateCategory:
+		v.Required("local_id", req.LocalID)
+		payload = &syncCreateCategoryPayloadRequest{}
+	default:
+		// Unknown types are reported per operation by the sync service.
+		return
+	}
+	if err := decodePayload(req.Payload, payload); err != nil {
+		v.Add("payload", validation.CodeInvalid, "payload is invalid")
+		return
+	}
+	v.Nested("payload", payload)
+}
+
+func (req *syncCreateExpensePayloadRequest) Validate(v *validation.Validator) {
+	v.Required("date", req.Date)
+	v.Date("date", &req.Date)
+	v.Positive("amount", req.Amount)
+	v.Precision("amount", req.Amount, req.Currency)
+	v.Required("currency", req.Currency)
+	v.Currency("currency", req.Currency)
+	v.Required("title", req.Title)
+}
+
+func (req *syncCreateCategoryPayloadRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, maxCategoryNameLength)
+}
+
+func (req *syncCreateTodoPayloadRequest) Validate(v *validation.Validator) {
+	v.Required("list_id", req.ListID)
+	v.Required("title", req.Title)
+}
+
+func (req *syncSetTodoCompletedPayloadRequest) Validate(v *validation.Validator) {
+	v.Check(req.IsCompleted != nil, "is_completed", validation.CodeRequired, "is_completed is required")
+	if normalizeStringPtr(req.TodoID) == nil && normalizeStringPtr(req.TodoLocalID) == nil {
+		v.Add("todo_id", validation.CodeRequired, "todo_id or todo_local_id is required")
+	}
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+// versionConflictEnvelope is the 409 body for stale If-Match preconditions.
+type versionConflictEnvelope struct {
+	Error   errorBody   `json:"error"`
+	Current interface{} `json:"current"`
+}
+
+// parseIfMatch returns the version the client based its change on: 0 when
+// If-Match is absent or "*", otherwise the number inside a single "N" or
+// W/"N" entity tag. Bare numbers are accepted for clients that drop quotes.
+func parseIfMatch(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	value = strings.TrimPrefix(value, "W/")
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match")
+	}
+	return version, nil
+}
+
+// setETag advertises the entity version for a later If-Match.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// writeVersionConflict answers 409 version_conflict with the stored entity so
+// the client can reapply its change without another GET.
+func writeVersionConflict(w http.ResponseWriter, version int64, current interface{}) {
+	const message = "resource was modified by someone else"
+	setETag(w, version)
+	if middleware.WriteConflictProblem(w, "version_conflict", message, current) {
+		return
+	}
+	writeJSON(w, http.StatusConflict, versionConflictEnvelope{
+		Error:   errorBody{Code: "version_conflict", Message: message},
+		Current: current,
+	})
+}
+
+// ParseIfMatch reads the If-Match precondition and writes 400 when it is not
+// a version ETag. ok is false when the response has been written.
+func ParseIfMatch(w http.ResponseWriter, r *http.Request) (version int64, ok bool) {
+	version, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_if_match", `If-Match must be a version ETag such as "3"`)
+		return 0, false
+	}
+	return version, true
+}
+
+func SetETag(w http.ResponseWriter, version int64) {
+	setETag(w, version)
+}
+
+func WriteVersionConflict(w http.ResponseWriter, version int64, current interface{}) {
+	writeVersionConflict(w, version, current)
+}
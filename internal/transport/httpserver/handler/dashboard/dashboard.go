@@ -0,0 +1,279 @@
+package dashboard
+
+import (
+	"net/http"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	dashboarddomain "family-app-go/internal/domain/dashboard"
+	gymdomain "family-app-go/internal/domain/gym"
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+type budgetResponse struct {
+	BaseCurrency  string  `json:"base_currency"`
+	SpentThisWeek float64 `json:"spent_this_week"`
+	SpentLastWeek float64 `json:"spent_last_week"`
+	Unconverted   int64   `json:"unconverted"`
+}
+
+type dueTodoResponse struct {
+	ID         string  `json:"id"`
+	ListID     string  `json:"list_id"`
+	ListTitle  string  `json:"list_title"`
+	Title      string  `json:"title"`
+	DueDate    string  `json:"due_date"`
+	AssigneeID *string `json:"assignee_id"`
+	Overdue    bool    `json:"overdue"`
+}
+
+type dueTodosResponse struct {
+	Items   []dueTodoResponse `json:"items"`
+	Total   int               `json:"total"`
+	Overdue int               `json:"overdue"`
+}
+
+type eventResponse struct {
+	Kind     string    `json:"kind"`
+	PetID    string    `json:"pet_id"`
+	PetName  string    `json:"pet_name"`
+	SourceID string    `json:"source_id"`
+	Title    string    `json:"title"`
+	DueAt    time.Time `json:"due_at"`
+	Overdue  bool      `json:"overdue"`
+}
+
+type streakResponse struct {
+	WorkoutsPerWeek    *int `json:"workouts_per_week"`
+	WorkoutsThisWeek   int  `json:"workouts_this_week"`
+	Remaining          int  `json:"remaining"`
+	CurrentStreakWeeks int  `json:"current_streak_weeks"`
+	LongestStreakWeeks int  `json:"longest_streak_weeks"`
+}
+
+type notificationResponse struct {
+	ID        string    `json:"id"`
+	Action    string    `json:"action"`
+	Summary   string    `json:"summary"`
+	ActorID   string    `json:"actor_id"`
+	ActorName string    `json:"actor_name"`
+	Link      string    `json:"link"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type notificationsResponse struct {
+	Unread int                    `json:"unread"`
+	Latest []notificationResponse `json:"latest"`
+}
+
+type milestoneCountdownResponse struct {
+	Days   int  `json:"days"`
+	Weeks  int  `json:"weeks"`
+	Months int  `json:"months"`
+	Passed bool `json:"passed"`
+}
+
+type milestoneSavingsResponse struct {
+	Amount    float64  `json:"amount"`
+	Currency  string   `json:"currency"`
+	Saved     float64  `json:"saved"`
+	Remaining float64  `json:"remaining"`
+	Share     float64  `json:"share"`
+	PerMonth  *float64 `json:"per_month"`
+}
+
+type milestoneResponse struct {
+	ID          string                     `json:"id"`
+	Title       string                     `json:"title"`
+	Kind        string                     `json:"kind"`
+	Date        string                     `json:"date"`
+	Countdown   milestoneCountdownResponse `json:"countdown"`
+	SavingsGoal *milestoneSavingsResponse  `json:"savings_goal"`
+}
+
+type sectionErrorResponse struct {
+	Section string `json:"section"`
+	Code    string `json:"code"`
+}
+
+type dashboardResponse struct {
+	WeekStart     string                 `json:"week_start"`
+	WeekEnd       string                 `json:"week_end"`
+	Budget        *budgetResponse        `json:"budget"`
+	DueTodos      *dueTodosResponse      `json:"due_todos"`
+	Events        []eventResponse        `json:"upcoming_events"`
+	Streak        *streakResponse        `json:"gym_streak"`
+	Notifications *notificationsResponse `json:"notifications"`
+	Milestones    []milestoneResponse    `json:"milestones"`
+	Errors        []sectionErrorResponse `json:"errors"`
+}
+
+// GetDashboard returns the caller's home screen for the current week in one
+// payload. Children get null budget and notifications, and milestones
+// without savings goals. A section that failed or timed out is null and
+// listed in errors; the rest are still returned.
+func (h *Handlers) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	dashboard, err := h.Dashboard.Build(r.Context(), dashboarddomain.Query{
+		FamilyID:     family.ID,
+		UserID:       user.ID,
+		BaseCurrency: family.DefaultCurrency,
+		Child:        middleware.IsChild(r.Context()),
+	})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("dashboard: build failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	for _, sectionErr := range dashboard.Errors {
+		commonhandler.RequestLog(r, h.log).InternalError("dashboard: section failed", sectionErr.Err, "user_id", user.ID, "family_id", family.ID, "section", string(sectionErr.Section))
+	}
+
+	writeJSON(w, http.StatusOK, toDashboardResponse(dashboard, time.Now().UTC()))
+}
+
+func toDashboardResponse(dashboard *dashboarddomain.Dashboard, now time.Time) dashboardResponse {
+	today := now.Truncate(24 * time.Hour)
+	response := dashboardResponse{
+		WeekStart: dashboard.WeekStart.Format("2006-01-02"),
+		WeekEnd:   dashboard.WeekEnd.Format("2006-01-02"),
+		Errors:    make([]sectionErrorResponse, 0, len(dashboard.Errors)),
+	}
+	for _, sectionErr := range dashboard.Errors {
+		code := "unavailable"
+		if sectionErr.Timeout() {
+			code = "timeout"
+		}
+		response.Errors = append(response.Errors, sectionErrorResponse{Section: string(sectionErr.Section), Code: code})
+	}
+
+	if budget := dashboard.Budget; budget != nil {
+		response.Budget = &budgetResponse{
+			BaseCurrency:  budget.BaseCurrency,
+			SpentThisWeek: budget.SpentThisWeek,
+			SpentLastWeek: budget.SpentLastWeek,
+			Unconverted:   budget.Unconverted,
+		}
+	}
+	if due := dashboard.DueTodos; due != nil {
+		response.DueTodos = toDueTodosResponse(due, today)
+	}
+	if !dashboard.Failed(dashboarddomain.SectionEvents) {
+		response.Events = make([]eventResponse, 0, len(dashboard.Events))
+	}
+	for _, event := range dashboard.Events {
+		response.Events = append(response.Events, eventResponse{
+			Kind:     event.Kind,
+			PetID:    event.PetID,
+			PetName:  event.PetName,
+			SourceID: event.SourceID,
+			Title:    event.Title,
+			DueAt:    event.DueAt,
+			Overdue:  event.DueAt.Before(now),
+		})
+	}
+	if streak := dashboard.Streak; streak != nil {
+		response.Streak = toStreakResponse(streak)
+	}
+	if notifications := dashboard.Notifications; notifications != nil {
+		response.Notifications = &notificationsResponse{
+			Unread: notifications.Unread,
+			Latest: make([]notificationResponse, 0, len(notifications.Latest)),
+		}
+		for _, event := range notifications.Latest {
+			response.Notifications.Latest = append(response.Notifications.Latest, toNotificationResponse(event))
+		}
+	}
+	if !dashboard.Failed(dashboarddomain.SectionMilestones) {
+		response.Milestones = make([]milestoneResponse, 0, len(dashboard.Milestones))
+	}
+	for _, milestone := range dashboard.Milestones {
+		response.Milestones = append(response.Milestones, toMilestoneResponse(milestone, now))
+	}
+	return response
+}
+
+func toMilestoneResponse(milestone milestonesdomain.Milestone, now time.Time) milestoneResponse {
+	countdown := milestone.Countdown(now)
+	response := milestoneResponse{
+		ID:    milestone.ID,
+		Title: milestone.Title,
+		Kind:  milestone.Kind,
+		Date:  milestone.Date.Format("2006-01-02"),
+		Countdown: milestoneCountdownResponse{
+			Days:   countdown.Days,
+			Weeks:  countdown.Weeks,
+			Months: countdown.Months,
+			Passed: countdown.Passed,
+		},
+	}
+	if progress := milestone.Progress(now); progress != nil {
+		response.SavingsGoal = &milestoneSavingsResponse{
+			Amount:    progress.Goal,
+			Currency:  progress.Currency,
+			Saved:     progress.Saved,
+			Remaining: progress.Remaining,
+			Share:     progress.Share,
+			PerMonth:  progress.PerMonth,
+		}
+	}
+	return response
+}
+
+func toDueTodosResponse(due *dashboarddomain.DueTodos, today time.Time) *dueTodosResponse {
+	response := &dueTodosResponse{
+		Items:   make([]dueTodoResponse, 0, len(due.Items)),
+		Total:   due.Total,
+		Overdue: due.Overdue,
+	}
+	for _, entry := range due.Items {
+		item := entry.Item
+		response.Items = append(response.Items, dueTodoResponse{
+			ID:         item.ID,
+			ListID:     item.ListID,
+			ListTitle:  entry.ListTitle,
+			Title:      item.Title,
+			DueDate:    item.DueDate.Format("2006-01-02"),
+			AssigneeID: item.AssigneeID,
+			Overdue:    item.DueDate.Before(today),
+		})
+	}
+	return response
+}
+
+func toStreakResponse(streak *gymdomain.Streak) *streakResponse {
+	response := &streakResponse{
+		WorkoutsThisWeek:   streak.WorkoutsThisWeek,
+		Remaining:          streak.Remaining(),
+		CurrentStreakWeeks: streak.CurrentStreakWeeks,
+		LongestStreakWeeks: streak.LongestStreakWeeks,
+	}
+	if streak.Goal != nil {
+		response.WorkoutsPerWeek = &streak.Goal.WorkoutsPerWeek
+	}
+	return response
+}
+
+func toNotificationResponse(event activitydomain.Event) notificationResponse {
+	return notificationResponse{
+		ID:        event.ID,
+		Action:    event.Action,
+		Summary:   event.Summary,
+		ActorID:   event.ActorID,
+		ActorName: event.ActorName,
+		Link:      event.Link(),
+		CreatedAt: event.CreatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package dashboard
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Dashboard DashboardService
+	log       logger.Logger
+}
+
+func New(dashboard DashboardService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Dashboard: dashboard,
+		log:       log,
+	}
+}
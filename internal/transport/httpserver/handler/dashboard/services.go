@@ -0,0 +1,12 @@
+package dashboard
+
+import (
+	"context"
+
+	dashboarddomain "family-app-go/internal/domain/dashboard"
+)
+
+// DashboardService is implemented by *dashboarddomain.Service.
+type DashboardService interface {
+	Build(ctx context.Context, query dashboarddomain.Query) (*dashboarddomain.Dashboard, error)
+}
@@ -0,0 +1,103 @@
+package erasure
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	erasuredomain "family-app-go/internal/domain/erasure"
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+type deletionResponse struct {
+	ID           string     `json:"id"`
+	Subject      string     `json:"subject"`
+	Status       string     `json:"status"`
+	ScheduledFor time.Time  `json:"scheduled_for"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+}
+
+type deletionCall func(r *http.Request, userID string) (*erasuredomain.DeletionRequest, error)
+
+// DeleteAccount schedules the caller's account for erasure. The account keeps
+// working during the grace period so the request can be cancelled.
+func (h *Handlers) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "erasure.delete_account", http.StatusAccepted, func(r *http.Request, userID string) (*erasuredomain.DeletionRequest, error) {
+		return h.Erasure.ScheduleAccountDeletion(r.Context(), userID)
+	})
+}
+
+func (h *Handlers) GetAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "erasure.get_account_deletion", http.StatusOK, func(r *http.Request, userID string) (*erasuredomain.DeletionRequest, error) {
+		return h.Erasure.GetAccountDeletion(r.Context(), userID)
+	})
+}
+
+func (h *Handlers) CancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "erasure.cancel_account_deletion", http.StatusOK, func(r *http.Request, userID string) (*erasuredomain.DeletionRequest, error) {
+		return h.Erasure.CancelAccountDeletion(r.Context(), userID)
+	})
+}
+
+// DeleteFamily schedules the caller's family for erasure. Owner only.
+func (h *Handlers) DeleteFamily(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "erasure.delete_family", http.StatusAccepted, func(r *http.Request, userID string) (*erasuredomain.DeletionRequest, error) {
+		return h.Erasure.ScheduleFamilyDeletion(r.Context(), userID)
+	})
+}
+
+func (h *Handlers) GetFamilyDeletion(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "erasure.get_family_deletion", http.StatusOK, func(r *http.Request, userID string) (*erasuredomain.DeletionRequest, error) {
+		return h.Erasure.GetFamilyDeletion(r.Context(), userID)
+	})
+}
+
+func (h *Handlers) CancelFamilyDeletion(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "erasure.cancel_family_deletion", http.StatusOK, func(r *http.Request, userID string) (*erasuredomain.DeletionRequest, error) {
+		return h.Erasure.CancelFamilyDeletion(r.Context(), userID)
+	})
+}
+
+func (h *Handlers) serve(w http.ResponseWriter, r *http.Request, op string, status int, call deletionCall) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	request, err := call(r, user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError(op+": family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, familydomain.ErrNotOwner):
+			commonhandler.RequestLog(r, h.log).BusinessError(op+": not owner", err, "user_id", user.ID)
+			writeError(w, http.StatusForbidden, "not_owner", "only owner can delete the family")
+		case errors.Is(err, erasuredomain.ErrDeletionNotFound):
+			writeError(w, http.StatusNotFound, "deletion_not_found", "no deletion is scheduled")
+		case errors.Is(err, erasuredomain.ErrDeletionAlreadyScheduled):
+			writeError(w, http.StatusConflict, "deletion_already_scheduled", "deletion is already scheduled")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError(op+": failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, status, toDeletionResponse(request))
+}
+
+func toDeletionResponse(request *erasuredomain.DeletionRequest) deletionResponse {
+	return deletionResponse{
+		ID:           request.ID,
+		Subject:      request.Subject,
+		Status:       request.Status,
+		ScheduledFor: request.ScheduledFor,
+		CreatedAt:    request.CreatedAt,
+		CancelledAt:  request.CancelledAt,
+	}
+}
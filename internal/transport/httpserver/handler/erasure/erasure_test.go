@@ -0,0 +1,142 @@
+package erasure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	erasuredomain "family-app-go/internal/domain/erasure"
+	familydomain "family-app-go/internal/domain/family"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+)
+
+const testUserID = "22222222-2222-2222-2222-222222222222"
+
+// mockErasure answers every call with request and err and records which
+// method was called.
+type mockErasure struct {
+	request *erasuredomain.DeletionRequest
+	err     error
+	called  string
+}
+
+func (m *mockErasure) answer(method string) (*erasuredomain.DeletionRequest, error) {
+	m.called = method
+	return m.request, m.err
+}
+
+func (m *mockErasure) CancelAccountDeletion(context.Context, string) (*erasuredomain.DeletionRequest, error) {
+	return m.answer("CancelAccountDeletion")
+}
+
+func (m *mockErasure) CancelFamilyDeletion(context.Context, string) (*erasuredomain.DeletionRequest, error) {
+	return m.answer("CancelFamilyDeletion")
+}
+
+func (m *mockErasure) GetAccountDeletion(context.Context, string) (*erasuredomain.DeletionRequest, error) {
+	return m.answer("GetAccountDeletion")
+}
+
+func (m *mockErasure) GetFamilyDeletion(context.Context, string) (*erasuredomain.DeletionRequest, error) {
+	return m.answer("GetFamilyDeletion")
+}
+
+func (m *mockErasure) ScheduleAccountDeletion(context.Context, string) (*erasuredomain.DeletionRequest, error) {
+	return m.answer("ScheduleAccountDeletion")
+}
+
+func (m *mockErasure) ScheduleFamilyDeletion(context.Context, string) (*erasuredomain.DeletionRequest, error) {
+	return m.answer("ScheduleFamilyDeletion")
+}
+
+func userRequest(method string) *http.Request {
+	req := httptest.NewRequest(method, "/api/me/deletion", nil)
+	return req.WithContext(middleware.WithUser(req.Context(), middleware.User{ID: testUserID}))
+}
+
+func TestErasureHandlersCallTheirServiceMethod(t *testing.T) {
+	scheduled := time.Date(2026, 11, 17, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		method string
+		serve  func(h *Handlers) http.HandlerFunc
+		status int
+	}{
+		{"ScheduleAccountDeletion", func(h *Handlers) http.HandlerFunc { return h.DeleteAccount }, http.StatusAccepted},
+		{"GetAccountDeletion", func(h *Handlers) http.HandlerFunc { return h.GetAccountDeletion }, http.StatusOK},
+		{"CancelAccountDeletion", func(h *Handlers) http.HandlerFunc { return h.CancelAccountDeletion }, http.StatusOK},
+		{"ScheduleFamilyDeletion", func(h *Handlers) http.HandlerFunc { return h.DeleteFamily }, http.StatusAccepted},
+		{"GetFamilyDeletion", func(h *Handlers) http.HandlerFunc { return h.GetFamilyDeletion }, http.StatusOK},
+		{"CancelFamilyDeletion", func(h *Handlers) http.HandlerFunc { return h.CancelFamilyDeletion }, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.method, func(t *testing.T) {
+			service := &mockErasure{request: &erasuredomain.DeletionRequest{ID: "request-1", Subject: "account", Status: "scheduled", ScheduledFor: scheduled}}
+			h := New(service, logger.New(io.Discard, slog.LevelError, "text"))
+			rec := httptest.NewRecorder()
+			tc.serve(h)(rec, userRequest(http.MethodPost))
+
+			if rec.Code != tc.status || service.called != tc.method {
+				t.Fatalf("expected %d from %s, got %d from %s: %s", tc.status, tc.method, rec.Code, service.called, rec.Body.String())
+			}
+			var body deletionResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if body.ID != "request-1" || !body.ScheduledFor.Equal(scheduled) {
+				t.Fatalf("unexpected response: %+v", body)
+			}
+		})
+	}
+}
+
+func TestErasureHandlersMapErrors(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{familydomain.ErrFamilyNotFound, http.StatusNotFound, "family_not_found"},
+		{familydomain.ErrNotOwner, http.StatusForbidden, "not_owner"},
+		{erasuredomain.ErrDeletionNotFound, http.StatusNotFound, "deletion_not_found"},
+		{fmt.Errorf("schedule: %w", erasuredomain.ErrDeletionAlreadyScheduled), http.StatusConflict, "deletion_already_scheduled"},
+		{errors.New("database is down"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.code, func(t *testing.T) {
+			h := New(&mockErasure{err: tc.err}, logger.New(io.Discard, slog.LevelError, "text"))
+			rec := httptest.NewRecorder()
+			h.DeleteFamily(rec, userRequest(http.MethodDelete))
+
+			var payload struct {
+				Error struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if rec.Code != tc.status || payload.Error.Code != tc.code {
+				t.Fatalf("expected %d %s, got %d: %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestErasureHandlersRequireUser(t *testing.T) {
+	service := &mockErasure{}
+	h := New(service, logger.New(io.Discard, slog.LevelError, "text"))
+	rec := httptest.NewRecorder()
+	h.DeleteAccount(rec, httptest.NewRequest(http.MethodDelete, "/api/me", nil))
+
+	if rec.Code != http.StatusUnauthorized || service.called != "" {
+		t.Fatalf("expected 401 without calling the service, got %d after %q", rec.Code, service.called)
+	}
+}
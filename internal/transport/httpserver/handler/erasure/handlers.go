@@ -0,0 +1,17 @@
+package erasure
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Erasure ErasureService
+	log     logger.Logger
+}
+
+func New(erasure ErasureService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Erasure: erasure,
+		log:     log,
+	}
+}
@@ -0,0 +1,17 @@
+package erasure
+
+import (
+	"context"
+
+	erasuredomain "family-app-go/internal/domain/erasure"
+)
+
+// ErasureService is implemented by *erasuredomain.Service.
+type ErasureService interface {
+	CancelAccountDeletion(ctx context.Context, userID string) (*erasuredomain.DeletionRequest, error)
+	CancelFamilyDeletion(ctx context.Context, userID string) (*erasuredomain.DeletionRequest, error)
+	GetAccountDeletion(ctx context.Context, userID string) (*erasuredomain.DeletionRequest, error)
+	GetFamilyDeletion(ctx context.Context, userID string) (*erasuredomain.DeletionRequest, error)
+	ScheduleAccountDeletion(ctx context.Context, userID string) (*erasuredomain.DeletionRequest, error)
+	ScheduleFamilyDeletion(ctx context.Context, userID string) (*erasuredomain.DeletionRequest, error)
+}
@@ -1,13 +1,13 @@
 package expenses
 
 import (
-	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	analyticsdomain "family-app-go/internal/domain/analytics"
-	familydomain "family-app-go/internal/domain/family"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/internal/transport/httpserver/middleware"
 )
 
@@ -18,15 +18,8 @@ func (h *Handlers) AnalyticsSummary(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("analytics.summary: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("analytics.summary: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -62,7 +55,7 @@ func (h *Handlers) AnalyticsSummary(w http.ResponseWriter, r *http.Request) {
 		CategoryIDs:   categoryIDs,
 	})
 	if err != nil {
-		h.log.InternalError("analytics.summary: build summary failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("analytics.summary: build summary failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -84,15 +77,8 @@ func (h *Handlers) AnalyticsTimeseries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("analytics.timeseries: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("analytics.timeseries: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -136,7 +122,7 @@ func (h *Handlers) AnalyticsTimeseries(w http.ResponseWriter, r *http.Request) {
 		Timezone:      tz,
 	})
 	if err != nil {
-		h.log.InternalError("analytics.timeseries: build timeseries failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("analytics.timeseries: build timeseries failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -151,15 +137,8 @@ func (h *Handlers) AnalyticsByCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("analytics.by_category: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("analytics.by_category: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -179,8 +158,8 @@ func (h *Handlers) AnalyticsByCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, err := parseIntParam(query.Get("limit"), 20)
-	if err != nil || limit <= 0 {
+	limit, err := h.limits.AnalyticsTop.Parse(query.Get("limit"))
+	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
 		return
 	}
@@ -197,7 +176,7 @@ func (h *Handlers) AnalyticsByCategory(w http.ResponseWriter, r *http.Request) {
 		Limit:         limit,
 	})
 	if err != nil {
-		h.log.InternalError("analytics.by_category: build report failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("analytics.by_category: build report failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -212,21 +191,25 @@ func (h *Handlers) TopCategories(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.Flags.Enabled(r.Context(), featureflagsdomain.TopCategories, family.ID)
 	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("analytics.top_categories: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("analytics.top_categories: get family failed", err, "user_id", user.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("analytics.top_categories: evaluate feature flag failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if !enabled {
+		writeJSON(w, http.StatusOK, analyticsdomain.DisabledTopCategories())
+		return
+	}
 
 	result, err := h.Analytics.TopCategories(r.Context(), family.ID)
 	if err != nil {
-		h.log.InternalError("analytics.top_categories: build report failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("analytics.top_categories: build report failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -241,15 +224,8 @@ func (h *Handlers) ReportsMonthly(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("reports.monthly: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("reports.monthly: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -283,7 +259,7 @@ func (h *Handlers) ReportsMonthly(w http.ResponseWriter, r *http.Request) {
 		CategoryIDs:   categoryIDs,
 	})
 	if err != nil {
-		h.log.InternalError("reports.monthly: build report failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("reports.monthly: build report failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -291,25 +267,65 @@ func (h *Handlers) ReportsMonthly(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, rows)
 }
 
-func (h *Handlers) ReportsCompare(w http.ResponseWriter, r *http.Request) {
+func (h *Handlers) AnalyticsCategoryTrends(w http.ResponseWriter, r *http.Request) {
 	user, ok := middleware.UserFromContext(r.Context())
 	if !ok {
 		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("reports.compare: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	months, err := parseIntParam(query.Get("months"), analyticsdomain.DefaultCategoryTrendMonths)
+	if err != nil || months <= 0 || months > analyticsdomain.MaxCategoryTrendMonths {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid months")
+		return
+	}
+
+	var toMonth time.Time
+	if value := strings.TrimSpace(query.Get("to_month")); value != "" {
+		toMonth, err = parseMonthRequired(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid to_month")
 			return
 		}
-		h.log.InternalError("reports.compare: get family failed", err, "user_id", user.ID)
+	}
+
+	currency, useBaseAmount := resolveAnalyticsCurrency(query.Get("currency"), family.DefaultCurrency)
+	categoryIDs := parseCSV(query.Get("category_ids"))
+
+	result, err := h.Analytics.CategoryTrends(r.Context(), family.ID, analyticsdomain.CategoryTrendsFilter{
+		ToMonth:       toMonth,
+		Months:        months,
+		Currency:      currency,
+		UseBaseAmount: useBaseAmount,
+		CategoryIDs:   categoryIDs,
+	})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("analytics.category_trends: build report failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handlers) ReportsCompare(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
 	query := r.URL.Query()
 	fromA, err := parseDateRequired(query.Get("from_a"))
 	if err != nil {
@@ -350,7 +366,7 @@ func (h *Handlers) ReportsCompare(w http.ResponseWriter, r *http.Request) {
 		CategoryIDs:   categoryIDs,
 	})
 	if err != nil {
-		h.log.InternalError("reports.compare: build report failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("reports.compare: build report failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
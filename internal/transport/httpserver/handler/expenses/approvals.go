@@ -0,0 +1,194 @@
+package expenses
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type expenseApprovalResponse struct {
+	ID               string     `json:"id"`
+	FamilyID         string     `json:"family_id"`
+	UserID           string     `json:"user_id"`
+	Date             string     `json:"date"`
+	Amount           float64    `json:"amount"`
+	Currency         string     `json:"currency"`
+	BaseCurrency     *string    `json:"base_currency,omitempty"`
+	AmountInBase     *float64   `json:"amount_in_base,omitempty"`
+	Title            string     `json:"title"`
+	CategoryIDs      []string   `json:"category_ids"`
+	SpendingLimit    float64    `json:"spending_limit"`
+	Status           string     `json:"status"`
+	ExpenseID        *string    `json:"expense_id,omitempty"`
+	UpdatesExpenseID *string    `json:"updates_expense_id,omitempty"`
+	DecidedBy        *string    `json:"decided_by,omitempty"`
+	DecidedAt        *time.Time `json:"decided_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+
+	Location *locationResponse `json:"location"`
+}
+
+type expenseApprovalListResponse struct {
+	Items []expenseApprovalResponse `json:"items"`
+}
+
+type approveExpenseResponse struct {
+	Approval expenseApprovalResponse `json:"approval"`
+	Expense  expenseResponse         `json:"expense"`
+}
+
+// ListExpenseApprovals lists the family's approvals to the owner and only
+// their own to everyone else.
+func (h *Handlers) ListExpenseApprovals(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	var filter expensesdomain.ApprovalFilter
+	switch status := expensesdomain.ApprovalStatus(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))); status {
+	case "":
+	case expensesdomain.ApprovalPending, expensesdomain.ApprovalApproved, expensesdomain.ApprovalRejected:
+		filter.Status = status
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid status")
+		return
+	}
+	if role, _ := middleware.FamilyRoleFromContext(r.Context()); role != familydomain.RoleOwner {
+		filter.UserID = user.ID
+	}
+
+	items, err := h.Expenses.ListExpenseApprovals(r.Context(), family.ID, filter)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.approvals: list approvals failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := make([]expenseApprovalResponse, 0, len(items))
+	for _, item := range items {
+		response = append(response, toExpenseApprovalResponse(item))
+	}
+	writeJSON(w, http.StatusOK, expenseApprovalListResponse{Items: response})
+}
+
+func (h *Handlers) ApproveExpense(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	approvalID := chi.URLParam(r, "id")
+	approval, created, err := h.Expenses.ApproveExpense(r.Context(), family.ID, approvalID, user.ID)
+	if err != nil {
+		if h.writeApprovalError(w, r, "expenses.approve", err, user.ID, family.ID, approvalID) {
+			return
+		}
+		if errors.Is(err, expensesdomain.ErrCategoryNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.approve: category not found", err, "user_id", user.ID, "family_id", family.ID, "approval_id", approvalID)
+			writeError(w, http.StatusConflict, "category_not_found", "a category of the expense was deleted")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.approve: approve expense failed", err, "user_id", user.ID, "family_id", family.ID, "approval_id", approvalID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	if _, err := h.Activity.RecordExpenseCreated(r.Context(), family.ID, actorFromUser(user), created.ID, created.Title); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.approve: record activity failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", created.ID)
+	}
+
+	writeJSON(w, http.StatusOK, approveExpenseResponse{
+		Approval: toExpenseApprovalResponse(*approval),
+		Expense:  toExpenseResponse(*created),
+	})
+}
+
+func (h *Handlers) RejectExpense(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	approvalID := chi.URLParam(r, "id")
+	approval, err := h.Expenses.RejectExpense(r.Context(), family.ID, approvalID, user.ID)
+	if err != nil {
+		if h.writeApprovalError(w, r, "expenses.reject", err, user.ID, family.ID, approvalID) {
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.reject: reject expense failed", err, "user_id", user.ID, "family_id", family.ID, "approval_id", approvalID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toExpenseApprovalResponse(*approval))
+}
+
+// writeApprovalError writes the response for errors shared by approve and
+// reject and reports whether err was one of them.
+func (h *Handlers) writeApprovalError(w http.ResponseWriter, r *http.Request, op string, err error, userID, familyID, approvalID string) bool {
+	switch {
+	case errors.Is(err, expensesdomain.ErrApprovalNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": approval not found", err, "user_id", userID, "family_id", familyID, "approval_id", approvalID)
+		writeError(w, http.StatusNotFound, "approval_not_found", "approval not found")
+	case errors.Is(err, expensesdomain.ErrApprovalDecided):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": approval already decided", err, "user_id", userID, "family_id", familyID, "approval_id", approvalID)
+		writeError(w, http.StatusConflict, "approval_decided", "approval is already decided")
+	default:
+		return false
+	}
+	return true
+}
+
+func toExpenseApprovalResponse(approval expensesdomain.ExpenseApproval) expenseApprovalResponse {
+	categoryIDs := []string{}
+	_ = json.Unmarshal(approval.CategoryIDs, &categoryIDs)
+
+	return expenseApprovalResponse{
+		ID:               approval.ID,
+		FamilyID:         approval.FamilyID,
+		UserID:           approval.UserID,
+		Date:             approval.Date.Format("2006-01-02"),
+		Amount:           approval.Amount,
+		Currency:         approval.Currency,
+		BaseCurrency:     approval.BaseCurrency,
+		AmountInBase:     approval.AmountInBase,
+		Title:            approval.Title,
+		CategoryIDs:      categoryIDs,
+		SpendingLimit:    approval.SpendingLimit,
+		Status:           string(approval.Status),
+		ExpenseID:        approval.ExpenseID,
+		UpdatesExpenseID: approval.UpdatesExpenseID,
+		DecidedBy:        approval.DecidedBy,
+		DecidedAt:        approval.DecidedAt,
+		CreatedAt:        approval.CreatedAt,
+
+		Location: toLocationResponse(approval.Latitude, approval.Longitude, approval.PlaceName),
+	}
+}
@@ -1,15 +1,17 @@
 package expenses
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	expensesdomain "family-app-go/internal/domain/expenses"
-	familydomain "family-app-go/internal/domain/family"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -25,27 +27,6 @@ type updateCategoryRequest struct {
 	Emoji optionalNullableString `json:"emoji"`
 }
 
-type optionalNullableString struct {
-	Set   bool
-	Value *string
-}
-
-func (o *optionalNullableString) UnmarshalJSON(data []byte) error {
-	o.Set = true
-	if string(data) == "null" {
-		o.Value = nil
-		return nil
-	}
-
-	var value string
-	if err := json.Unmarshal(data, &value); err != nil {
-		return err
-	}
-
-	o.Value = &value
-	return nil
-}
-
 func (h *Handlers) ListCategories(w http.ResponseWriter, r *http.Request) {
 	user, ok := middleware.UserFromContext(r.Context())
 	if !ok {
@@ -53,34 +34,69 @@ func (h *Handlers) ListCategories(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("categories.list: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+	favoritesFirst := false
+	if value := strings.TrimSpace(r.URL.Query().Get("favorites_first")); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid favorites_first")
 			return
 		}
-		h.log.InternalError("categories.list: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		favoritesFirst = parsed
+	}
+	includeUsage := false
+	if value := strings.TrimSpace(r.URL.Query().Get("include_usage")); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid include_usage")
+			return
+		}
+		includeUsage = parsed
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
 	categories, err := h.Expenses.ListCategories(r.Context(), family.ID)
 	if err != nil {
-		h.log.InternalError("categories.list: list categories failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("categories.list: list categories failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	favorites, err := h.Favorites.IDs(r.Context(), user.ID, favoritesdomain.EntityCategory)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("categories.list: list favorites failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if favoritesFirst {
+		favoritesdomain.SortFirst(categories, favorites, func(category expensesdomain.Category) string { return category.ID })
+	}
+
+	var usage map[string]expensesdomain.CategoryUsage
+	if includeUsage {
+		usage, err = h.Expenses.ListCategoryUsage(r.Context(), family.ID)
+		if err != nil {
+			commonhandler.RequestLog(r, h.log).InternalError("categories.list: list category usage failed", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+	}
 
 	response := make([]categoryResponse, 0, len(categories))
 	for _, category := range categories {
-		response = append(response, categoryResponse{
-			ID:        category.ID,
-			Name:      category.Name,
-			Color:     category.Color,
-			Emoji:     category.Emoji,
-			CreatedAt: category.CreatedAt,
-		})
+		item := toCategoryResponse(category)
+		item.IsFavorite = favorites[category.ID]
+		if includeUsage {
+			categoryUsage := usage[category.ID]
+			item.Usage = &categoryUsageResponse{
+				ExpenseCount: categoryUsage.ExpenseCount,
+				LastUsedAt:   categoryUsage.LastUsedAt,
+			}
+		}
+		response = append(response, item)
 	}
 
 	writeJSON(w, http.StatusOK, response)
@@ -88,17 +104,7 @@ func (h *Handlers) ListCategories(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	var req createCategoryRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
-		return
-	}
-
-	if strings.TrimSpace(req.Name) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
-		return
-	}
-	if len([]rune(strings.TrimSpace(req.Name))) > 50 {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name must be at most 50 characters")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -108,15 +114,8 @@ func (h *Handlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("categories.create: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("categories.create: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -128,21 +127,16 @@ func (h *Handlers) CreateCategory(w http.ResponseWriter, r *http.Request) {
 	})
 	if err != nil {
 		if writeCategoryValidationError(w, err) {
-			h.log.BusinessError("categories.create: validation failed", err, "user_id", user.ID, "family_id", family.ID)
+			commonhandler.RequestLog(r, h.log).BusinessError("categories.create: validation failed", err, "user_id", user.ID, "family_id", family.ID)
 			return
 		}
-		h.log.InternalError("categories.create: create category failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("categories.create: create category failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, categoryResponse{
-		ID:        created.ID,
-		Name:      created.Name,
-		Color:     created.Color,
-		Emoji:     created.Emoji,
-		CreatedAt: created.CreatedAt,
-	})
+	setETag(w, created.Version)
+	writeJSON(w, http.StatusCreated, toCategoryResponse(*created))
 }
 
 func (h *Handlers) DeleteCategory(w http.ResponseWriter, r *http.Request) {
@@ -158,33 +152,29 @@ func (h *Handlers) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("categories.delete: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("categories.delete: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
 	if err := h.Expenses.DeleteCategory(r.Context(), family.ID, categoryID); err != nil {
 		if errors.Is(err, expensesdomain.ErrCategoryNotFound) {
-			h.log.BusinessError("categories.delete: category not found", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			commonhandler.RequestLog(r, h.log).BusinessError("categories.delete: category not found", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
 			writeError(w, http.StatusNotFound, "category_not_found", "category not found")
 			return
 		}
 		if errors.Is(err, expensesdomain.ErrCategoryInUse) {
-			h.log.BusinessError("categories.delete: category is in use", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			commonhandler.RequestLog(r, h.log).BusinessError("categories.delete: category is in use", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
 			writeError(w, http.StatusConflict, "category_in_use", "Category is used by expenses")
 			return
 		}
-		h.log.InternalError("categories.delete: delete category failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+		commonhandler.RequestLog(r, h.log).InternalError("categories.delete: delete category failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if err := h.Favorites.Clear(r.Context(), favoritesdomain.EntityCategory, categoryID); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("categories.delete: clear favorites failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -196,17 +186,13 @@ func (h *Handlers) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req updateCategoryRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
-		return
-	}
-	if strings.TrimSpace(req.Name) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+	expectedVersion, ok := parseIfMatch(w, r)
+	if !ok {
 		return
 	}
-	if len([]rune(strings.TrimSpace(req.Name))) > 50 {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name must be at most 50 characters")
+
+	var req updateCategoryRequest
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -216,15 +202,8 @@ func (h *Handlers) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("categories.update: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("categories.update: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -240,48 +219,77 @@ func (h *Handlers) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 			Set:   req.Emoji.Set,
 			Value: req.Emoji.Value,
 		},
+		ExpectedVersion: expectedVersion,
 	})
 	if err != nil {
+		var conflict *expensesdomain.CategoryConflictError
 		switch {
+		case errors.As(err, &conflict):
+			commonhandler.RequestLog(r, h.log).BusinessError("categories.update: version conflict", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			writeVersionConflict(w, conflict.Current.Version, toCategoryResponse(conflict.Current))
 		case errors.Is(err, expensesdomain.ErrCategoryNotFound):
-			h.log.BusinessError("categories.update: category not found", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			commonhandler.RequestLog(r, h.log).BusinessError("categories.update: category not found", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
 			writeError(w, http.StatusNotFound, "category_not_found", "category not found")
 		case errors.Is(err, expensesdomain.ErrCategoryNameTaken):
-			h.log.BusinessError("categories.update: category name already exists", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			commonhandler.RequestLog(r, h.log).BusinessError("categories.update: category name already exists", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
 			writeError(w, http.StatusConflict, "category_name_taken", "Category name already exists")
 		case writeCategoryValidationError(w, err):
-			h.log.BusinessError("categories.update: validation failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			commonhandler.RequestLog(r, h.log).BusinessError("categories.update: validation failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
 		default:
-			h.log.InternalError("categories.update: update category failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			commonhandler.RequestLog(r, h.log).InternalError("categories.update: update category failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
 
-	writeJSON(w, http.StatusOK, categoryResponse{
-		ID:        updated.ID,
-		Name:      updated.Name,
-		Color:     updated.Color,
-		Emoji:     updated.Emoji,
-		CreatedAt: updated.CreatedAt,
-	})
+	favorites, err := h.Favorites.IDs(r.Context(), user.ID, favoritesdomain.EntityCategory)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("categories.update: list favorites failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := toCategoryResponse(*updated)
+	response.IsFavorite = favorites[updated.ID]
+	setETag(w, updated.Version)
+	writeJSON(w, http.StatusOK, response)
 }
 
 type categoryResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Color     *string   `json:"color"`
-	Emoji     *string   `json:"emoji"`
-	CreatedAt time.Time `json:"created_at"`
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Color      *string   `json:"color"`
+	Emoji      *string   `json:"emoji"`
+	Version    int64     `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	IsFavorite bool      `json:"is_favorite"`
+	// Usage is only set with include_usage=true.
+	Usage *categoryUsageResponse `json:"usage,omitempty"`
+}
+
+type categoryUsageResponse struct {
+	ExpenseCount int64      `json:"expense_count"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+}
+
+func toCategoryResponse(category expensesdomain.Category) categoryResponse {
+	return categoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		Color:     category.Color,
+		Emoji:     category.Emoji,
+		Version:   category.Version,
+		CreatedAt: category.CreatedAt,
+	}
 }
 
 func writeCategoryValidationError(w http.ResponseWriter, err error) bool {
 	switch {
 	case errors.Is(err, expensesdomain.ErrInvalidCategoryColor):
-		writeError(w, http.StatusBadRequest, "invalid_request", "color must be null or #RRGGBB")
+		writeValidationError(w, validation.FieldErr("color", validation.CodeFormat, "color must be null or #RRGGBB"))
 		return true
 	case errors.Is(err, expensesdomain.ErrInvalidCategoryEmoji):
-		writeError(w, http.StatusBadRequest, "invalid_request", "emoji must be a single emoji grapheme")
+		writeValidationError(w, validation.FieldErr("emoji", validation.CodeFormat, "emoji must be a single emoji grapheme"))
 		return true
 	default:
 		return false
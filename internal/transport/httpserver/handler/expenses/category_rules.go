@@ -0,0 +1,190 @@
+package expenses
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type createCategoryRuleRequest struct {
+	Pattern    string `json:"pattern"`
+	MatchType  string `json:"match_type"`
+	CategoryID string `json:"category_id"`
+	Priority   int    `json:"priority"`
+}
+
+type updateCategoryRuleRequest struct {
+	Pattern    *string `json:"pattern"`
+	MatchType  *string `json:"match_type"`
+	CategoryID *string `json:"category_id"`
+	Priority   *int    `json:"priority"`
+}
+
+type categoryRuleResponse struct {
+	ID         string    `json:"id"`
+	Pattern    string    `json:"pattern"`
+	MatchType  string    `json:"match_type"`
+	CategoryID string    `json:"category_id"`
+	Priority   int       `json:"priority"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type categoryRuleListResponse struct {
+	Items []categoryRuleResponse `json:"items"`
+}
+
+func (h *Handlers) ListCategoryRules(w http.ResponseWriter, r *http.Request) {
+	user, family, ok := h.categoryRulesFamily(w, r, "category_rules.list")
+	if !ok {
+		return
+	}
+
+	rules, err := h.Expenses.ListCategoryRules(r.Context(), family.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("category_rules.list: list rules failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := categoryRuleListResponse{Items: make([]categoryRuleResponse, 0, len(rules))}
+	for _, rule := range rules {
+		response.Items = append(response.Items, toCategoryRuleResponse(rule))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) CreateCategoryRule(w http.ResponseWriter, r *http.Request) {
+	var req createCategoryRuleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, ok := h.categoryRulesFamily(w, r, "category_rules.create")
+	if !ok {
+		return
+	}
+
+	created, err := h.Expenses.CreateCategoryRule(r.Context(), expensesdomain.CreateCategoryRuleInput{
+		FamilyID:   family.ID,
+		Pattern:    req.Pattern,
+		MatchType:  expensesdomain.RuleMatchType(strings.ToLower(strings.TrimSpace(req.MatchType))),
+		CategoryID: req.CategoryID,
+		Priority:   req.Priority,
+	})
+	if err != nil {
+		h.writeCategoryRuleError(w, r, "category_rules.create", err, user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toCategoryRuleResponse(*created))
+}
+
+func (h *Handlers) UpdateCategoryRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if ruleID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	var req updateCategoryRuleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, ok := h.categoryRulesFamily(w, r, "category_rules.update")
+	if !ok {
+		return
+	}
+
+	input := expensesdomain.UpdateCategoryRuleInput{
+		FamilyID:   family.ID,
+		RuleID:     ruleID,
+		Pattern:    req.Pattern,
+		CategoryID: req.CategoryID,
+		Priority:   req.Priority,
+	}
+	if req.MatchType != nil {
+		matchType := expensesdomain.RuleMatchType(strings.ToLower(strings.TrimSpace(*req.MatchType)))
+		input.MatchType = &matchType
+	}
+
+	updated, err := h.Expenses.UpdateCategoryRule(r.Context(), input)
+	if err != nil {
+		h.writeCategoryRuleError(w, r, "category_rules.update", err, user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCategoryRuleResponse(*updated))
+}
+
+func (h *Handlers) DeleteCategoryRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if ruleID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, family, ok := h.categoryRulesFamily(w, r, "category_rules.delete")
+	if !ok {
+		return
+	}
+
+	if err := h.Expenses.DeleteCategoryRule(r.Context(), family.ID, ruleID); err != nil {
+		h.writeCategoryRuleError(w, r, "category_rules.delete", err, user.ID, family.ID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) categoryRulesFamily(w http.ResponseWriter, r *http.Request, op string) (middleware.User, *familydomain.Family, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, false
+	}
+	return user, family, true
+}
+
+func (h *Handlers) writeCategoryRuleError(w http.ResponseWriter, r *http.Request, op string, err error, userID, familyID string) {
+	switch {
+	case errors.Is(err, expensesdomain.ErrCategoryRuleNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": rule not found", err, "user_id", userID, "family_id", familyID)
+		writeError(w, http.StatusNotFound, "category_rule_not_found", "category rule not found")
+	case errors.Is(err, expensesdomain.ErrCategoryNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": category not found", err, "user_id", userID, "family_id", familyID)
+		writeError(w, http.StatusNotFound, "category_not_found", "category not found")
+	case errors.Is(err, expensesdomain.ErrInvalidCategoryRule):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": invalid rule", err, "user_id", userID, "family_id", familyID)
+		writeValidationError(w, validation.FieldErr("pattern", validation.CodeInvalid, "invalid category rule"))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(op+": failed", err, "user_id", userID, "family_id", familyID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+func toCategoryRuleResponse(rule expensesdomain.CategoryRule) categoryRuleResponse {
+	return categoryRuleResponse{
+		ID:         rule.ID,
+		Pattern:    rule.Pattern,
+		MatchType:  string(rule.MatchType),
+		CategoryID: rule.CategoryID,
+		Priority:   rule.Priority,
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,235 @@
+package expenses
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	commentsdomain "family-app-go/internal/domain/comments"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type commentRequest struct {
+	Body string `json:"body"`
+}
+
+func (req commentRequest) Validate(v *validation.Validator) {
+	v.Required("body", req.Body)
+	v.MaxLength("body", req.Body, commentsdomain.MaxBodyLength)
+}
+
+type commentAuthorResponse struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Email     string  `json:"email"`
+	AvatarURL *string `json:"avatar_url"`
+}
+
+type commentResponse struct {
+	ID        string                `json:"id"`
+	ExpenseID string                `json:"expense_id"`
+	Author    commentAuthorResponse `json:"author"`
+	Body      string                `json:"body"`
+	Edited    bool                  `json:"edited"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+type commentListResponse struct {
+	Items []commentResponse `json:"items"`
+	Total int64             `json:"total"`
+}
+
+// commentCountsResponse tells the caller how many comments an expense has
+// and how many of them they have not read yet.
+type commentCountsResponse struct {
+	Total  int64 `json:"total"`
+	Unread int64 `json:"unread"`
+}
+
+// ListExpenseComments returns an expense's thread, oldest first, and marks it
+// read for the caller.
+func (h *Handlers) ListExpenseComments(w http.ResponseWriter, r *http.Request) {
+	user, family, expenseID, ok := h.commentedExpense(w, r, "expenses.comments.list")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), commentsdomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > commentsdomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+
+	items, total, err := h.Comments.List(r.Context(), expenseThread(family.ID, expenseID), user.ID, commentsdomain.ListFilter{Limit: limit, Offset: offset})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.comments.list: list comments failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := commentListResponse{Items: make([]commentResponse, 0, len(items)), Total: total}
+	for _, comment := range items {
+		response.Items = append(response.Items, toCommentResponse(comment))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateExpenseComment adds the caller's comment to an expense. Members it
+// mentions with @handle find it in their mention inbox.
+func (h *Handlers) CreateExpenseComment(w http.ResponseWriter, r *http.Request) {
+	var req commentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, expenseID, ok := h.commentedExpense(w, r, "expenses.comments.create")
+	if !ok {
+		return
+	}
+
+	actor := actorFromUser(user)
+	comment, err := h.Comments.Create(r.Context(), commentsdomain.CreateInput{
+		FamilyID:   family.ID,
+		EntityType: commentsdomain.EntityExpense,
+		EntityID:   expenseID,
+		Author: commentsdomain.Author{
+			ID:        actor.ID,
+			Name:      actor.Name,
+			Email:     actor.Email,
+			AvatarURL: actor.AvatarURL,
+		},
+		Body: req.Body,
+	})
+	if err != nil {
+		h.writeCommentError(w, r, "expenses.comments.create", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toCommentResponse(*comment))
+}
+
+// UpdateExpenseComment edits the caller's own comment.
+func (h *Handlers) UpdateExpenseComment(w http.ResponseWriter, r *http.Request) {
+	var req commentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, expenseID, ok := h.commentedExpense(w, r, "expenses.comments.update")
+	if !ok {
+		return
+	}
+	commentID := strings.TrimSpace(chi.URLParam(r, "comment_id"))
+
+	comment, err := h.Comments.Update(r.Context(), commentsdomain.UpdateInput{
+		Thread:    expenseThread(family.ID, expenseID),
+		CommentID: commentID,
+		AuthorID:  user.ID,
+		Body:      req.Body,
+	})
+	if err != nil {
+		h.writeCommentError(w, r, "expenses.comments.update", err, "user_id", user.ID, "family_id", family.ID, "comment_id", commentID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCommentResponse(*comment))
+}
+
+// DeleteExpenseComment removes the caller's own comment.
+func (h *Handlers) DeleteExpenseComment(w http.ResponseWriter, r *http.Request) {
+	user, family, expenseID, ok := h.commentedExpense(w, r, "expenses.comments.delete")
+	if !ok {
+		return
+	}
+	commentID := strings.TrimSpace(chi.URLParam(r, "comment_id"))
+
+	if err := h.Comments.Delete(r.Context(), expenseThread(family.ID, expenseID), commentID, user.ID); err != nil {
+		h.writeCommentError(w, r, "expenses.comments.delete", err, "user_id", user.ID, "family_id", family.ID, "comment_id", commentID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commentedExpense resolves the caller and checks that the expense in the
+// path belongs to their family.
+func (h *Handlers) commentedExpense(w http.ResponseWriter, r *http.Request, operation string) (middleware.User, *familydomain.Family, string, bool) {
+	expenseID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if expenseID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return middleware.User{}, nil, "", false
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, "", false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, "", false
+	}
+
+	if _, err := h.Expenses.GetExpense(r.Context(), family.ID, expenseID); err != nil {
+		if errors.Is(err, expensesdomain.ErrExpenseNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError(operation+": expense not found", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			writeError(w, http.StatusNotFound, "expense_not_found", "expense not found")
+			return middleware.User{}, nil, "", false
+		}
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": get expense failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return middleware.User{}, nil, "", false
+	}
+	return user, family, expenseID, true
+}
+
+func expenseThread(familyID, expenseID string) commentsdomain.Thread {
+	return commentsdomain.Thread{FamilyID: familyID, EntityType: commentsdomain.EntityExpense, EntityID: expenseID}
+}
+
+func (h *Handlers) writeCommentError(w http.ResponseWriter, r *http.Request, operation string, err error, args ...any) {
+	switch {
+	case errors.Is(err, commentsdomain.ErrCommentNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": comment not found", err, args...)
+		writeError(w, http.StatusNotFound, "comment_not_found", "comment not found")
+	case errors.Is(err, commentsdomain.ErrNotAuthor):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": not the author", err, args...)
+		writeError(w, http.StatusForbidden, "not_comment_author", "only the author can change a comment")
+	case errors.Is(err, commentsdomain.ErrInvalidBody):
+		writeValidationError(w, validation.FieldErr("body", validation.CodeInvalid, "body must be 1 to 2000 characters"))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": failed", err, args...)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+func toCommentResponse(comment commentsdomain.Comment) commentResponse {
+	return commentResponse{
+		ID:        comment.ID,
+		ExpenseID: comment.EntityID,
+		Author: commentAuthorResponse{
+			ID:        comment.AuthorID,
+			Name:      comment.AuthorName,
+			Email:     comment.AuthorEmail,
+			AvatarURL: comment.AuthorAvatarURL,
+		},
+		Body:      comment.Body,
+		Edited:    comment.UpdatedAt.After(comment.CreatedAt),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
@@ -0,0 +1,145 @@
+package expenses
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	analyticsdomain "family-app-go/internal/domain/analytics"
+	commentsdomain "family-app-go/internal/domain/comments"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	"family-app-go/pkg/familytest"
+)
+
+// The contract tests snapshot the JSON the mobile clients read. The
+// fixtures set every field, so that omitempty fields are in the golden
+// files too.
+
+type mockAnalytics struct {
+	AnalyticsService
+}
+
+func (mockAnalytics) Summary(context.Context, string, analyticsdomain.SummaryFilter) (analyticsdomain.SummaryResult, error) {
+	return analyticsdomain.SummaryResult{TotalAmount: 310.5, Count: 7, AvgPerDay: 10.02}, nil
+}
+
+func (mockAnalytics) Timeseries(context.Context, string, analyticsdomain.TimeseriesFilter) ([]analyticsdomain.TimeseriesPoint, error) {
+	return []analyticsdomain.TimeseriesPoint{{Period: "2026-10-01", Total: 42.5, Count: 2}}, nil
+}
+
+func (mockAnalytics) ByCategory(context.Context, string, analyticsdomain.ByCategoryFilter) ([]analyticsdomain.ByCategoryRow, error) {
+	return []analyticsdomain.ByCategoryRow{testCategoryRow()}, nil
+}
+
+func (mockAnalytics) TopCategories(context.Context, string) (analyticsdomain.TopCategoriesResult, error) {
+	return analyticsdomain.TopCategoriesResult{Status: analyticsdomain.TopCategoriesStatusOK, Items: []analyticsdomain.ByCategoryRow{testCategoryRow()}}, nil
+}
+
+func (mockAnalytics) Monthly(context.Context, string, analyticsdomain.MonthlyFilter) ([]analyticsdomain.MonthlyRow, error) {
+	return []analyticsdomain.MonthlyRow{{Month: "2026-10", Total: 310.5, Count: 7}}, nil
+}
+
+func (mockAnalytics) CategoryTrends(context.Context, string, analyticsdomain.CategoryTrendsFilter) (analyticsdomain.CategoryTrendsResult, error) {
+	change := 12.5
+	return analyticsdomain.CategoryTrendsResult{
+		FromMonth: "2026-09",
+		ToMonth:   "2026-10",
+		Items: []analyticsdomain.CategoryTrend{{
+			CategoryID:    testCategoryID,
+			CategoryName:  "Food",
+			Months:        []analyticsdomain.MonthlyRow{{Month: "2026-09", Total: 100, Count: 3}, {Month: "2026-10", Total: 125, Count: 4}},
+			Total:         225,
+			PriorTotal:    200,
+			ChangePercent: &change,
+		}},
+	}, nil
+}
+
+func (mockAnalytics) Compare(context.Context, string, analyticsdomain.CompareFilter) (analyticsdomain.CompareResult, error) {
+	return analyticsdomain.CompareResult{
+		PeriodA: analyticsdomain.PeriodSummary{From: "2026-09-01", To: "2026-09-30", Total: 200, Count: 5},
+		PeriodB: analyticsdomain.PeriodSummary{From: "2026-10-01", To: "2026-10-31", Total: 250, Count: 6},
+		Delta:   analyticsdomain.DeltaResult{Amount: 50, Percent: 25},
+	}, nil
+}
+
+type mockFlags struct{}
+
+func (mockFlags) Enabled(context.Context, string, string) (bool, error) {
+	return true, nil
+}
+
+const testCategoryID = "44444444-4444-4444-4444-444444444444"
+
+func testCategoryRow() analyticsdomain.ByCategoryRow {
+	return analyticsdomain.ByCategoryRow{CategoryID: testCategoryID, CategoryName: "Food", Total: 125, Count: 4}
+}
+
+// contractExpense is testExpense with the conversion and location set.
+func contractExpense() expensesdomain.ExpenseWithCategories {
+	baseCurrency, rate, inBase, source := "EUR", 0.92, 11.5, "ecb"
+	rateDate := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+	latitude, longitude, place := 52.52, 13.405, "Market"
+
+	expense := testExpense()
+	expense.Currency = "USD"
+	expense.BaseCurrency = &baseCurrency
+	expense.ExchangeRate = &rate
+	expense.AmountInBase = &inBase
+	expense.RateDate = &rateDate
+	expense.RateSource = &source
+	expense.Latitude = &latitude
+	expense.Longitude = &longitude
+	expense.PlaceName = &place
+	expense.AutoCategorized = true
+	expense.CategoryIDs = []string{testCategoryID}
+	return expense
+}
+
+func TestExpenseListContract(t *testing.T) {
+	deps := newTestDeps()
+	deps.expenses.items = []expensesdomain.ExpenseWithCategories{contractExpense()}
+	deps.expenses.totals = expensesdomain.ExpenseTotals{
+		ByCurrency:   []expensesdomain.CurrencyTotal{{Currency: "USD", Amount: 12.5, Count: 1, AmountInBase: 11.5}},
+		BaseCurrency: "EUR",
+		TotalInBase:  11.5,
+	}
+	deps.comments.counts = map[string]commentsdomain.Counts{testExpenseID: {Total: 3, Unread: 1}}
+	rec := httptest.NewRecorder()
+	deps.handlers().ListExpenses(rec, familyRequest(http.MethodGet, "/api/expenses", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	familytest.Golden(t, "expense_list", rec.Body.Bytes())
+}
+
+func TestAnalyticsContract(t *testing.T) {
+	h := newTestDeps().handlers()
+	cases := []struct {
+		name    string
+		target  string
+		handler http.HandlerFunc
+	}{
+		{"analytics_summary", "/api/analytics/summary?from=2026-10-01&to=2026-10-31", h.AnalyticsSummary},
+		{"analytics_timeseries", "/api/analytics/timeseries?from=2026-10-01&to=2026-10-31&group_by=day", h.AnalyticsTimeseries},
+		{"analytics_by_category", "/api/analytics/by-category?from=2026-10-01&to=2026-10-31", h.AnalyticsByCategory},
+		{"analytics_top_categories", "/api/analytics/top-categories", h.TopCategories},
+		{"analytics_category_trends", "/api/analytics/category-trends?months=2&to_month=2026-10", h.AnalyticsCategoryTrends},
+		{"reports_monthly", "/api/reports/monthly?from_month=2026-10&to_month=2026-10", h.ReportsMonthly},
+		{"reports_compare", "/api/reports/compare?from_a=2026-09-01&to_a=2026-09-30&from_b=2026-10-01&to_b=2026-10-31", h.ReportsCompare},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tc.handler(rec, familyRequest(http.MethodGet, tc.target, ""))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			familytest.Golden(t, tc.name, rec.Body.Bytes())
+		})
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"strings"
 	"time"
 
+	commentsdomain "family-app-go/internal/domain/comments"
 	expensesdomain "family-app-go/internal/domain/expenses"
-	familydomain "family-app-go/internal/domain/family"
+	quotadomain "family-app-go/internal/domain/quota"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"family-app-go/pkg/money"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -18,6 +22,8 @@ type createExpenseRequest struct {
 	Currency    string   `json:"currency"`
 	Title       string   `json:"title"`
 	CategoryIDs []string `json:"category_ids"`
+
+	Location *locationRequest `json:"location"`
 }
 
 type updateExpenseRequest struct {
@@ -26,6 +32,30 @@ type updateExpenseRequest struct {
 	Currency    string   `json:"currency"`
 	Title       string   `json:"title"`
 	CategoryIDs []string `json:"category_ids"`
+	IsArchived  *bool    `json:"is_archived"`
+
+	// Location replaces the stored location and {} clears it. An absent or
+	// null location keeps it.
+	Location *locationRequest `json:"location"`
+}
+
+// locationRequest carries coordinates, which come in pairs, and an optional
+// place name.
+type locationRequest struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	PlaceName *string  `json:"place_name"`
+}
+
+func (req *locationRequest) toDomain() *expensesdomain.Location {
+	if req == nil {
+		return nil
+	}
+	return &expensesdomain.Location{Latitude: req.Latitude, Longitude: req.Longitude, PlaceName: req.PlaceName}
+}
+
+type archiveExpensesResponse struct {
+	Archived int64 `json:"archived"`
 }
 
 func (h *Handlers) ListExpenses(w http.ResponseWriter, r *http.Request) {
@@ -35,15 +65,8 @@ func (h *Handlers) ListExpenses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("expenses.list: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("expenses.list: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -59,7 +82,7 @@ func (h *Handlers) ListExpenses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, err := parseIntParam(query.Get("limit"), 50)
+	limit, err := h.limits.Expenses.Parse(query.Get("limit"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
 		return
@@ -70,11 +93,18 @@ func (h *Handlers) ListExpenses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	archived, err := parseArchivedFilter(query.Get("archived"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid archived")
+		return
+	}
+
 	filter := expensesdomain.ListFilter{
-		From:   from,
-		To:     to,
-		Limit:  limit,
-		Offset: offset,
+		From:     from,
+		To:       to,
+		Archived: archived,
+		Limit:    limit,
+		Offset:   offset,
 	}
 	currency := strings.ToUpper(strings.TrimSpace(query.Get("currency")))
 	if currency != "" {
@@ -92,26 +122,47 @@ func (h *Handlers) ListExpenses(w http.ResponseWriter, r *http.Request) {
 
 	items, total, err := h.Expenses.ListExpenses(r.Context(), family.ID, filter)
 	if err != nil {
-		h.log.InternalError("expenses.list: list expenses failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.list: list expenses failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	expenseIDs := make([]string, 0, len(items))
+	for _, expense := range items {
+		expenseIDs = append(expenseIDs, expense.ID)
+	}
+	comments, err := h.Comments.Counts(r.Context(), family.ID, commentsdomain.EntityExpense, expenseIDs, user.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.list: count comments failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
 	response := make([]expenseResponse, 0, len(items))
 	for _, expense := range items {
-		response = append(response, toExpenseResponse(expense))
+		item := toExpenseResponse(expense)
+		counts := comments[expense.ID]
+		item.Comments = &commentCountsResponse{Total: counts.Total, Unread: counts.Unread}
+		response = append(response, item)
+	}
+
+	totals, err := h.Expenses.SummarizeExpenses(r.Context(), family.ID, family.DefaultCurrency, filter)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.list: summarize expenses failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
 	}
 
 	writeJSON(w, http.StatusOK, expenseListResponse{
-		Items: response,
-		Total: total,
+		Items:     response,
+		Total:     total,
+		Aggregate: toExpenseAggregateResponse(totals),
 	})
 }
 
 func (h *Handlers) CreateExpense(w http.ResponseWriter, r *http.Request) {
 	var req createExpenseRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -121,35 +172,12 @@ func (h *Handlers) CreateExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("expenses.create: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("expenses.create: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
-	date, err := parseDateRequired(req.Date)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
-		return
-	}
-	if req.Amount <= 0 {
-		writeError(w, http.StatusBadRequest, "invalid_request", "amount must be positive")
-		return
-	}
-	if strings.TrimSpace(req.Title) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "title is required")
-		return
-	}
-	if strings.TrimSpace(req.Currency) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "currency is required")
-		return
-	}
+	date, _ := parseDateRequired(req.Date)
 
 	input := expensesdomain.CreateExpenseInput{
 		FamilyID:     family.ID,
@@ -160,32 +188,67 @@ func (h *Handlers) CreateExpense(w http.ResponseWriter, r *http.Request) {
 		BaseCurrency: family.DefaultCurrency,
 		Title:        req.Title,
 		CategoryIDs:  req.CategoryIDs,
+		Location:     req.Location.toDomain(),
+
+		AllowedCurrencies: family.Currencies(),
 	}
 
 	created, err := h.Expenses.CreateExpense(r.Context(), input)
+	var approvalErr *expensesdomain.ApprovalRequiredError
+	if errors.As(err, &approvalErr) {
+		writeJSON(w, http.StatusAccepted, toExpenseApprovalResponse(approvalErr.Approval))
+		return
+	}
 	if err != nil {
-		if errors.Is(err, expensesdomain.ErrCategoryNotFound) {
-			h.log.BusinessError("expenses.create: category not found", err, "user_id", user.ID, "family_id", family.ID)
-			writeError(w, http.StatusNotFound, "category_not_found", "category not found")
-			return
-		}
-		if errors.Is(err, expensesdomain.ErrRateNotAvailable) {
-			h.log.BusinessError("expenses.create: rate not available", err, "user_id", user.ID, "family_id", family.ID)
-			writeError(w, http.StatusUnprocessableEntity, "rate_not_available", "rate is not available for selected date")
-			return
-		}
-		h.log.InternalError("expenses.create: create expense failed", err, "user_id", user.ID, "family_id", family.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		h.writeCreateExpenseError(w, r, "expenses.create", err, "user_id", user.ID, "family_id", family.ID)
 		return
 	}
 
+	if _, err := h.Activity.RecordExpenseCreated(r.Context(), family.ID, actorFromUser(user), created.ID, created.Title); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.create: record activity failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", created.ID)
+	}
+
+	setETag(w, created.Version)
 	writeJSON(w, http.StatusCreated, toExpenseResponse(*created))
 }
 
+// writeCreateExpenseError answers a failed expense creation; operation
+// prefixes the log messages.
+func (h *Handlers) writeCreateExpenseError(w http.ResponseWriter, r *http.Request, operation string, err error, args ...any) {
+	var precisionErr *money.PrecisionError
+	switch {
+	case errors.As(err, &precisionErr):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": invalid precision", err, args...)
+		writeValidationError(w, validation.PrecisionErr("amount", precisionErr))
+	case errors.Is(err, expensesdomain.ErrInvalidCurrency):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": invalid currency", err, args...)
+		writeValidationError(w, validation.FieldErr("currency", validation.CodeCurrency, err.Error()))
+	case errors.Is(err, expensesdomain.ErrInvalidLocation):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": invalid location", err, args...)
+		writeValidationError(w, validation.FieldErr("location", validation.CodeInvalid, "location must have both coordinates in range"))
+	case errors.Is(err, expensesdomain.ErrCategoryNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": category not found", err, args...)
+		writeError(w, http.StatusNotFound, "category_not_found", "category not found")
+	case errors.Is(err, expensesdomain.ErrRateNotAvailable):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": rate not available", err, args...)
+		writeError(w, http.StatusUnprocessableEntity, "rate_not_available", "rate is not available for selected date")
+	case errors.Is(err, quotadomain.ErrExceeded):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": quota exceeded", err, args...)
+		writeQuotaExceeded(w, err)
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": create expense failed", err, args...)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
 func (h *Handlers) UpdateExpense(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	var req updateExpenseRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -201,66 +264,69 @@ func (h *Handlers) UpdateExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("expenses.update: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("expenses.update: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
-	date, err := parseDateRequired(req.Date)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
-		return
-	}
-	if req.Amount <= 0 {
-		writeError(w, http.StatusBadRequest, "invalid_request", "amount must be positive")
-		return
-	}
-	if strings.TrimSpace(req.Title) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "title is required")
-		return
-	}
-	if strings.TrimSpace(req.Currency) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "currency is required")
-		return
-	}
+	date, _ := parseDateRequired(req.Date)
 
 	input := expensesdomain.UpdateExpenseInput{
-		ID:           expenseID,
-		FamilyID:     family.ID,
-		Date:         date,
-		Amount:       req.Amount,
-		Currency:     req.Currency,
-		BaseCurrency: family.DefaultCurrency,
-		Title:        req.Title,
-		CategoryIDs:  req.CategoryIDs,
+		ID:              expenseID,
+		FamilyID:        family.ID,
+		UserID:          user.ID,
+		Date:            date,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		BaseCurrency:    family.DefaultCurrency,
+		Title:           req.Title,
+		CategoryIDs:     req.CategoryIDs,
+		IsArchived:      req.IsArchived,
+		ExpectedVersion: expectedVersion,
+		Location:        req.Location.toDomain(),
+
+		AllowedCurrencies: family.Currencies(),
 	}
 
 	updated, err := h.Expenses.UpdateExpense(r.Context(), input)
+	var approvalErr *expensesdomain.ApprovalRequiredError
+	if errors.As(err, &approvalErr) {
+		writeJSON(w, http.StatusAccepted, toExpenseApprovalResponse(approvalErr.Approval))
+		return
+	}
 	if err != nil {
+		var conflict *expensesdomain.ExpenseConflictError
+		var precisionErr *money.PrecisionError
 		switch {
+		case errors.As(err, &conflict):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.update: version conflict", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			writeVersionConflict(w, conflict.Current.Version, toExpenseResponse(conflict.Current))
 		case errors.Is(err, expensesdomain.ErrExpenseNotFound):
-			h.log.BusinessError("expenses.update: expense not found", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.update: expense not found", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
 			writeError(w, http.StatusNotFound, "expense_not_found", "expense not found")
 		case errors.Is(err, expensesdomain.ErrCategoryNotFound):
-			h.log.BusinessError("expenses.update: category not found", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.update: category not found", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
 			writeError(w, http.StatusNotFound, "category_not_found", "category not found")
 		case errors.Is(err, expensesdomain.ErrRateNotAvailable):
-			h.log.BusinessError("expenses.update: rate not available", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.update: rate not available", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
 			writeError(w, http.StatusUnprocessableEntity, "rate_not_available", "rate is not available for selected date")
+		case errors.As(err, &precisionErr):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.update: invalid precision", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			writeValidationError(w, validation.PrecisionErr("amount", precisionErr))
+		case errors.Is(err, expensesdomain.ErrInvalidCurrency):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.update: invalid currency", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			writeValidationError(w, validation.FieldErr("currency", validation.CodeCurrency, err.Error()))
+		case errors.Is(err, expensesdomain.ErrInvalidLocation):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.update: invalid location", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			writeValidationError(w, validation.FieldErr("location", validation.CodeInvalid, "location must have both coordinates in range"))
 		default:
-			h.log.InternalError("expenses.update: update expense failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			commonhandler.RequestLog(r, h.log).InternalError("expenses.update: update expense failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
 
+	setETag(w, updated.Version)
 	writeJSON(w, http.StatusOK, toExpenseResponse(*updated))
 }
 
@@ -277,53 +343,136 @@ func (h *Handlers) DeleteExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("expenses.delete: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("expenses.delete: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
 	if err := h.Expenses.DeleteExpense(r.Context(), family.ID, expenseID); err != nil {
 		if errors.Is(err, expensesdomain.ErrExpenseNotFound) {
-			h.log.BusinessError("expenses.delete: expense not found", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.delete: expense not found", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
 			writeError(w, http.StatusNotFound, "expense_not_found", "expense not found")
 			return
 		}
-		h.log.InternalError("expenses.delete: delete expense failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.delete: delete expense failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if err := h.Comments.Clear(r.Context(), commentsdomain.EntityExpense, expenseID); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.delete: clear comments failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", expenseID)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ArchiveExpensesOlderThan archives the family's expenses dated before the
+// date query parameter, hiding them from the default list.
+func (h *Handlers) ArchiveExpensesOlderThan(w http.ResponseWriter, r *http.Request) {
+	before, err := parseDateRequired(r.URL.Query().Get("date"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "date is required in YYYY-MM-DD format")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	archived, err := h.Expenses.ArchiveExpensesBefore(r.Context(), family.ID, before)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.archive: archive expenses failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	commonhandler.RequestLog(r, h.log).Info("expenses.archive: archived expenses", "user_id", user.ID, "family_id", family.ID, "before", before.Format("2006-01-02"), "archived", archived)
+	writeJSON(w, http.StatusOK, archiveExpensesResponse{Archived: archived})
+}
+
 type expenseResponse struct {
-	ID           string    `json:"id"`
-	FamilyID     string    `json:"family_id"`
-	UserID       string    `json:"user_id"`
-	Date         string    `json:"date"`
-	Amount       float64   `json:"amount"`
-	Currency     string    `json:"currency"`
-	BaseCurrency *string   `json:"base_currency,omitempty"`
-	ExchangeRate *float64  `json:"exchange_rate,omitempty"`
-	AmountInBase *float64  `json:"amount_in_base,omitempty"`
-	RateDate     *string   `json:"rate_date,omitempty"`
-	RateSource   *string   `json:"rate_source,omitempty"`
-	Title        string    `json:"title"`
-	CategoryIDs  []string  `json:"category_ids"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              string    `json:"id"`
+	FamilyID        string    `json:"family_id"`
+	UserID          string    `json:"user_id"`
+	Date            string    `json:"date"`
+	Amount          float64   `json:"amount"`
+	Currency        string    `json:"currency"`
+	BaseCurrency    *string   `json:"base_currency,omitempty"`
+	ExchangeRate    *float64  `json:"exchange_rate,omitempty"`
+	AmountInBase    *float64  `json:"amount_in_base,omitempty"`
+	RateDate        *string   `json:"rate_date,omitempty"`
+	RateSource      *string   `json:"rate_source,omitempty"`
+	Title           string    `json:"title"`
+	CategoryIDs     []string  `json:"category_ids"`
+	AutoCategorized bool      `json:"auto_categorized"`
+	IsArchived      bool      `json:"is_archived"`
+	Version         int64     `json:"version"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	Location *locationResponse `json:"location"`
+	// Comments is only filled in lists.
+	Comments *commentCountsResponse `json:"comments,omitempty"`
+}
+
+type locationResponse struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	PlaceName *string  `json:"place_name"`
+}
+
+// toLocationResponse returns nil for an expense without a location.
+func toLocationResponse(latitude, longitude *float64, placeName *string) *locationResponse {
+	if latitude == nil && placeName == nil {
+		return nil
+	}
+	return &locationResponse{Latitude: latitude, Longitude: longitude, PlaceName: placeName}
 }
 
 type expenseListResponse struct {
-	Items []expenseResponse `json:"items"`
-	Total int64             `json:"total"`
+	Items     []expenseResponse        `json:"items"`
+	Total     int64                    `json:"total"`
+	Aggregate expenseAggregateResponse `json:"aggregate"`
+}
+
+type expenseAggregateResponse struct {
+	ByCurrency       []currencyTotalResponse `json:"by_currency"`
+	BaseCurrency     string                  `json:"base_currency"`
+	TotalInBase      float64                 `json:"total_in_base"`
+	UnconvertedCount int64                   `json:"unconverted_count"`
+}
+
+type currencyTotalResponse struct {
+	Currency     string  `json:"currency"`
+	Amount       float64 `json:"amount"`
+	Count        int64   `json:"count"`
+	AmountInBase float64 `json:"amount_in_base"`
+	Unconverted  int64   `json:"unconverted"`
+}
+
+func toExpenseAggregateResponse(totals expensesdomain.ExpenseTotals) expenseAggregateResponse {
+	byCurrency := make([]currencyTotalResponse, 0, len(totals.ByCurrency))
+	for _, total := range totals.ByCurrency {
+		byCurrency = append(byCurrency, currencyTotalResponse{
+			Currency:     total.Currency,
+			Amount:       total.Amount,
+			Count:        total.Count,
+			AmountInBase: total.AmountInBase,
+			Unconverted:  total.Unconverted,
+		})
+	}
+	return expenseAggregateResponse{
+		ByCurrency:       byCurrency,
+		BaseCurrency:     totals.BaseCurrency,
+		TotalInBase:      totals.TotalInBase,
+		UnconvertedCount: totals.Unconverted,
+	}
 }
 
 func toExpenseResponse(expense expensesdomain.ExpenseWithCategories) expenseResponse {
@@ -334,20 +483,25 @@ func toExpenseResponse(expense expensesdomain.ExpenseWithCategories) expenseResp
 	}
 
 	return expenseResponse{
-		ID:           expense.ID,
-		FamilyID:     expense.FamilyID,
-		UserID:       expense.UserID,
-		Date:         expense.Date.Format("2006-01-02"),
-		Amount:       expense.Amount,
-		Currency:     expense.Currency,
-		BaseCurrency: expense.BaseCurrency,
-		ExchangeRate: expense.ExchangeRate,
-		AmountInBase: expense.AmountInBase,
-		RateDate:     rateDate,
-		RateSource:   expense.RateSource,
-		Title:        expense.Title,
-		CategoryIDs:  expense.CategoryIDs,
-		CreatedAt:    expense.CreatedAt,
-		UpdatedAt:    expense.UpdatedAt,
+		ID:              expense.ID,
+		FamilyID:        expense.FamilyID,
+		UserID:          expense.UserID,
+		Date:            expense.Date.Format("2006-01-02"),
+		Amount:          expense.Amount,
+		Currency:        expense.Currency,
+		BaseCurrency:    expense.BaseCurrency,
+		ExchangeRate:    expense.ExchangeRate,
+		AmountInBase:    expense.AmountInBase,
+		RateDate:        rateDate,
+		RateSource:      expense.RateSource,
+		Title:           expense.Title,
+		CategoryIDs:     expense.CategoryIDs,
+		AutoCategorized: expense.AutoCategorized,
+		IsArchived:      expense.IsArchived,
+		Version:         expense.Version,
+		CreatedAt:       expense.CreatedAt,
+		UpdatedAt:       expense.UpdatedAt,
+
+		Location: toLocationResponse(expense.Latitude, expense.Longitude, expense.PlaceName),
 	}
 }
@@ -0,0 +1,516 @@
+package expenses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	commentsdomain "family-app-go/internal/domain/comments"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	quotadomain "family-app-go/internal/domain/quota"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"family-app-go/pkg/money"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	testFamilyID  = "11111111-1111-1111-1111-111111111111"
+	testUserID    = "22222222-2222-2222-2222-222222222222"
+	testExpenseID = "33333333-3333-3333-3333-333333333333"
+)
+
+var errDatabase = errors.New("database is down")
+
+// The mocks embed the service interface: a call the test did not expect
+// hits the nil interface and panics.
+
+type mockExpenses struct {
+	ExpensesService
+	items   []expensesdomain.ExpenseWithCategories
+	totals  expensesdomain.ExpenseTotals
+	err     error
+	filter  expensesdomain.ListFilter
+	created expensesdomain.CreateExpenseInput
+	updated expensesdomain.UpdateExpenseInput
+	deleted string
+}
+
+func (m *mockExpenses) ListExpenses(_ context.Context, _ string, filter expensesdomain.ListFilter) ([]expensesdomain.ExpenseWithCategories, int64, error) {
+	m.filter = filter
+	return m.items, int64(len(m.items)), m.err
+}
+
+func (m *mockExpenses) SummarizeExpenses(context.Context, string, string, expensesdomain.ListFilter) (expensesdomain.ExpenseTotals, error) {
+	return m.totals, nil
+}
+
+func (m *mockExpenses) CreateExpense(_ context.Context, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error) {
+	m.created = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	expense := testExpense()
+	expense.Title = input.Title
+	return &expense, nil
+}
+
+func (m *mockExpenses) UpdateExpense(_ context.Context, input expensesdomain.UpdateExpenseInput) (*expensesdomain.ExpenseWithCategories, error) {
+	m.updated = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	expense := testExpense()
+	expense.Version = input.ExpectedVersion + 1
+	return &expense, nil
+}
+
+func (m *mockExpenses) DeleteExpense(_ context.Context, _ string, expenseID string) error {
+	m.deleted = expenseID
+	return m.err
+}
+
+type mockActivity struct {
+	ActivityService
+	recorded []string
+}
+
+func (m *mockActivity) RecordExpenseCreated(_ context.Context, _ string, _ activitydomain.Actor, expenseID, _ string) (*activitydomain.Event, error) {
+	m.recorded = append(m.recorded, expenseID)
+	return &activitydomain.Event{}, nil
+}
+
+type mockComments struct {
+	CommentsService
+	counts  map[string]commentsdomain.Counts
+	cleared []string
+}
+
+func (m *mockComments) Counts(context.Context, string, commentsdomain.EntityType, []string, string) (map[string]commentsdomain.Counts, error) {
+	return m.counts, nil
+}
+
+func (m *mockComments) Clear(_ context.Context, _ commentsdomain.EntityType, entityID string) error {
+	m.cleared = append(m.cleared, entityID)
+	return nil
+}
+
+type testDeps struct {
+	analytics *mockAnalytics
+	expenses  *mockExpenses
+	activity  *mockActivity
+	flags     mockFlags
+	comments  *mockComments
+}
+
+func newTestDeps() *testDeps {
+	return &testDeps{
+		analytics: &mockAnalytics{},
+		expenses:  &mockExpenses{},
+		activity:  &mockActivity{},
+		comments:  &mockComments{},
+	}
+}
+
+func (d *testDeps) handlers() *Handlers {
+	limits := commonhandler.ListLimits{Expenses: commonhandler.ListLimit{Default: 50, Max: 200}}
+	return New(d.analytics, d.expenses, nil, d.activity, nil, d.flags, nil, d.comments, limits, logger.New(io.Discard, slog.LevelError, "text"))
+}
+
+func testExpense() expensesdomain.ExpenseWithCategories {
+	created := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	return expensesdomain.ExpenseWithCategories{
+		Expense: expensesdomain.Expense{
+			ID:        testExpenseID,
+			FamilyID:  testFamilyID,
+			UserID:    testUserID,
+			Date:      time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+			Amount:    12.5,
+			Currency:  "EUR",
+			Title:     "Groceries",
+			Version:   1,
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+		CategoryIDs: []string{},
+	}
+}
+
+func testApproval() expensesdomain.ExpenseApproval {
+	return expensesdomain.ExpenseApproval{ID: "approval-1", FamilyID: testFamilyID, UserID: testUserID, Amount: 12.5, Currency: "EUR", SpendingLimit: 10, Status: expensesdomain.ApprovalPending}
+}
+
+// familyRequest is a request from testUserID, a member of testFamilyID.
+func familyRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	ctx := middleware.WithUser(req.Context(), middleware.User{ID: testUserID})
+	ctx = middleware.WithFamily(ctx, &familydomain.Family{ID: testFamilyID, DefaultCurrency: "EUR"})
+	return req.WithContext(ctx)
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
+
+type errorResponse struct {
+	Error struct {
+		Code   string `json:"code"`
+		Fields []struct {
+			Field string `json:"field"`
+			Code  string `json:"code"`
+		} `json:"fields"`
+	} `json:"error"`
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) errorResponse {
+	t.Helper()
+	var payload errorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error response %q: %v", rec.Body.String(), err)
+	}
+	return payload
+}
+
+func TestListExpensesRequiresUserAndFamily(t *testing.T) {
+	h := newTestDeps().handlers()
+
+	rec := httptest.NewRecorder()
+	h.ListExpenses(rec, httptest.NewRequest(http.MethodGet, "/api/expenses", nil))
+	if rec.Code != http.StatusUnauthorized || decodeError(t, rec).Error.Code != "invalid_token" {
+		t.Fatalf("expected 401 invalid_token, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/expenses", nil)
+	req = req.WithContext(middleware.WithUser(req.Context(), middleware.User{ID: testUserID}))
+	rec = httptest.NewRecorder()
+	h.ListExpenses(rec, req)
+	if rec.Code != http.StatusNotFound || decodeError(t, rec).Error.Code != "family_not_found" {
+		t.Fatalf("expected 404 family_not_found, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestListExpensesRejectsInvalidQuery(t *testing.T) {
+	for _, query := range []string{
+		"from=2026-13-01",
+		"to=yesterday",
+		"limit=-1",
+		"limit=ten",
+		"offset=x",
+		"archived=maybe",
+	} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestDeps().handlers().ListExpenses(rec, familyRequest(http.MethodGet, "/api/expenses?"+query, ""))
+			if rec.Code != http.StatusBadRequest || decodeError(t, rec).Error.Code != "invalid_request" {
+				t.Fatalf("expected 400 invalid_request, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestListExpensesResponse(t *testing.T) {
+	deps := newTestDeps()
+	deps.expenses.items = []expensesdomain.ExpenseWithCategories{testExpense()}
+	deps.expenses.totals = expensesdomain.ExpenseTotals{
+		ByCurrency:   []expensesdomain.CurrencyTotal{{Currency: "EUR", Amount: 12.5, Count: 1, AmountInBase: 12.5}},
+		BaseCurrency: "EUR",
+		TotalInBase:  12.5,
+	}
+	deps.comments.counts = map[string]commentsdomain.Counts{testExpenseID: {Total: 3, Unread: 1}}
+
+	rec := httptest.NewRecorder()
+	deps.handlers().ListExpenses(rec, familyRequest(http.MethodGet, "/api/expenses?limit=1000&archived=all&currency=eur&category_ids=a,b", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	filter := deps.expenses.filter
+	if filter.Limit != 200 || filter.Archived != expensesdomain.ArchivedAll || filter.Currency != "EUR" || len(filter.CategoryIDs) != 2 {
+		t.Fatalf("unexpected filter: %+v", filter)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	for _, key := range []string{"items", "total", "aggregate"} {
+		if _, ok := body[key]; !ok {
+			t.Fatalf("expected %q in %s", key, rec.Body.String())
+		}
+	}
+	var list expenseListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if list.Total != 1 || len(list.Items) != 1 || list.Items[0].Date != "2026-10-01" {
+		t.Fatalf("unexpected items: %+v", list)
+	}
+	if comments := list.Items[0].Comments; comments == nil || comments.Total != 3 || comments.Unread != 1 {
+		t.Fatalf("expected comment counts on the item, got %+v", comments)
+	}
+	if list.Aggregate.BaseCurrency != "EUR" || len(list.Aggregate.ByCurrency) != 1 {
+		t.Fatalf("unexpected aggregate: %+v", list.Aggregate)
+	}
+}
+
+func TestListExpensesInternalError(t *testing.T) {
+	deps := newTestDeps()
+	deps.expenses.err = errDatabase
+
+	rec := httptest.NewRecorder()
+	deps.handlers().ListExpenses(rec, familyRequest(http.MethodGet, "/api/expenses", ""))
+	if rec.Code != http.StatusInternalServerError || decodeError(t, rec).Error.Code != "internal_error" {
+		t.Fatalf("expected 500 internal_error, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateExpenseValidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		code   string
+		fields []string
+	}{
+		{name: "malformed", body: `{"date":`, code: "invalid_json"},
+		{name: "unknown field", body: `{"date":"2026-10-01","amount":1,"currency":"EUR","title":"x","note":"y"}`, code: "invalid_json"},
+		{name: "empty", body: `{}`, code: "invalid_request", fields: []string{"date", "amount", "title", "currency"}},
+		{name: "bad date", body: `{"date":"01.10.2026","amount":1,"currency":"EUR","title":"x"}`, code: "invalid_request", fields: []string{"date"}},
+		{name: "precision", body: `{"date":"2026-10-01","amount":1.5,"currency":"JPY","title":"x"}`, code: "invalid_request", fields: []string{"amount"}},
+		{name: "half a location", body: `{"date":"2026-10-01","amount":1,"currency":"EUR","title":"x","location":{"latitude":10}}`, code: "invalid_request", fields: []string{"location"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			rec := httptest.NewRecorder()
+			deps.handlers().CreateExpense(rec, familyRequest(http.MethodPost, "/api/expenses", tc.body))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			payload := decodeError(t, rec)
+			if payload.Error.Code != tc.code {
+				t.Fatalf("expected %s, got %s", tc.code, payload.Error.Code)
+			}
+			got := make(map[string]bool, len(payload.Error.Fields))
+			for _, field := range payload.Error.Fields {
+				got[field.Field] = true
+			}
+			for _, field := range tc.fields {
+				if !got[field] {
+					t.Fatalf("expected a %q field error, got %+v", field, payload.Error.Fields)
+				}
+			}
+			if deps.expenses.created.Title != "" {
+				t.Fatalf("service called for an invalid request: %+v", deps.expenses.created)
+			}
+		})
+	}
+}
+
+func TestCreateExpenseMapsErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"precision", &money.PrecisionError{Currency: "JPY"}, http.StatusBadRequest, "invalid_request"},
+		{"currency", expensesdomain.ErrInvalidCurrency, http.StatusBadRequest, "invalid_request"},
+		{"location", expensesdomain.ErrInvalidLocation, http.StatusBadRequest, "invalid_request"},
+		{"category", expensesdomain.ErrCategoryNotFound, http.StatusNotFound, "category_not_found"},
+		{"rate", expensesdomain.ErrRateNotAvailable, http.StatusUnprocessableEntity, "rate_not_available"},
+		{"capacity quota", &quotadomain.ExceededError{Quota: "expenses", Limit: 10}, http.StatusConflict, "quota_exceeded"},
+		{"rolling quota", &quotadomain.ExceededError{Quota: "expenses_per_day", Limit: 10, Window: time.Hour}, http.StatusTooManyRequests, "quota_exceeded"},
+		{"internal", errDatabase, http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.expenses.err = tc.err
+			rec := httptest.NewRecorder()
+			deps.handlers().CreateExpense(rec, familyRequest(http.MethodPost, "/api/expenses", `{"date":"2026-10-01","amount":12.5,"currency":"EUR","title":"Groceries"}`))
+
+			if rec.Code != tc.status || decodeError(t, rec).Error.Code != tc.code {
+				t.Fatalf("expected %d %s, got %d: %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+			if len(deps.activity.recorded) != 0 {
+				t.Fatalf("expected no activity, got %v", deps.activity.recorded)
+			}
+		})
+	}
+}
+
+func TestCreateExpense(t *testing.T) {
+	deps := newTestDeps()
+	rec := httptest.NewRecorder()
+	deps.handlers().CreateExpense(rec, familyRequest(http.MethodPost, "/api/expenses", `{"date":"2026-10-01","amount":12.5,"currency":"EUR","title":"Groceries","category_ids":[]}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Fatalf("expected ETag \"1\", got %q", etag)
+	}
+	input := deps.expenses.created
+	if input.FamilyID != testFamilyID || input.UserID != testUserID || input.BaseCurrency != "EUR" || !input.Date.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+	var body expenseResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ID != testExpenseID || body.Title != "Groceries" || body.Location != nil || body.Comments != nil {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+	if len(deps.activity.recorded) != 1 || deps.activity.recorded[0] != testExpenseID {
+		t.Fatalf("expected the creation in the activity feed, got %v", deps.activity.recorded)
+	}
+}
+
+func TestCreateExpenseOverLimitNeedsApproval(t *testing.T) {
+	deps := newTestDeps()
+	deps.expenses.err = &expensesdomain.ApprovalRequiredError{Approval: testApproval()}
+
+	rec := httptest.NewRecorder()
+	deps.handlers().CreateExpense(rec, familyRequest(http.MethodPost, "/api/expenses", `{"date":"2026-10-01","amount":12.5,"currency":"EUR","title":"Groceries"}`))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body expenseApprovalResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ID != "approval-1" || len(deps.activity.recorded) != 0 {
+		t.Fatalf("unexpected approval %+v with activity %v", body, deps.activity.recorded)
+	}
+}
+
+func TestUpdateExpenseOverLimitNeedsApproval(t *testing.T) {
+	deps := newTestDeps()
+	deps.expenses.err = &expensesdomain.ApprovalRequiredError{Approval: testApproval()}
+
+	rec := httptest.NewRecorder()
+	req := withURLParam(familyRequest(http.MethodPut, "/api/expenses/"+testExpenseID, `{"date":"2026-10-01","amount":12.5,"currency":"EUR","title":"Groceries"}`), "id", testExpenseID)
+	deps.handlers().UpdateExpense(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body expenseApprovalResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ID != "approval-1" || deps.expenses.updated.UserID != testUserID {
+		t.Fatalf("unexpected approval %+v for update %+v", body, deps.expenses.updated)
+	}
+}
+
+func TestUpdateExpense(t *testing.T) {
+	const body = `{"date":"2026-10-01","amount":12.5,"currency":"EUR","title":"Groceries"}`
+	current := testExpense()
+	current.Version = 4
+	cases := []struct {
+		name    string
+		ifMatch string
+		err     error
+		status  int
+		code    string
+	}{
+		{name: "bad if-match", ifMatch: "latest", status: http.StatusBadRequest, code: "invalid_if_match"},
+		{name: "conflict", ifMatch: `"3"`, err: &expensesdomain.ExpenseConflictError{Current: current}, status: http.StatusConflict, code: "version_conflict"},
+		{name: "not found", ifMatch: `"3"`, err: expensesdomain.ErrExpenseNotFound, status: http.StatusNotFound, code: "expense_not_found"},
+		{name: "category", err: expensesdomain.ErrCategoryNotFound, status: http.StatusNotFound, code: "category_not_found"},
+		{name: "rate", err: expensesdomain.ErrRateNotAvailable, status: http.StatusUnprocessableEntity, code: "rate_not_available"},
+		{name: "currency", err: expensesdomain.ErrInvalidCurrency, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "internal", err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "updated", ifMatch: `W/"3"`, status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.expenses.err = tc.err
+			req := withURLParam(familyRequest(http.MethodPut, "/api/expenses/"+testExpenseID, body), "id", testExpenseID)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			deps.handlers().UpdateExpense(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := decodeError(t, rec).Error.Code; code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+			}
+			switch tc.status {
+			case http.StatusConflict:
+				var conflict struct {
+					Current expenseResponse `json:"current"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &conflict); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if conflict.Current.Version != 4 || rec.Header().Get("ETag") != `"4"` {
+					t.Fatalf("expected the current expense at version 4, got %+v etag %q", conflict.Current, rec.Header().Get("ETag"))
+				}
+			case http.StatusOK:
+				if deps.expenses.updated.ExpectedVersion != 3 || rec.Header().Get("ETag") != `"4"` {
+					t.Fatalf("expected version 3 to become 4, got %d etag %q", deps.expenses.updated.ExpectedVersion, rec.Header().Get("ETag"))
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteExpense(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "not found", err: expensesdomain.ErrExpenseNotFound, status: http.StatusNotFound, code: "expense_not_found"},
+		{name: "internal", err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "deleted", status: http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.expenses.err = tc.err
+			req := withURLParam(familyRequest(http.MethodDelete, "/api/expenses/"+testExpenseID, ""), "id", testExpenseID)
+			rec := httptest.NewRecorder()
+			deps.handlers().DeleteExpense(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := decodeError(t, rec).Error.Code; code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				if len(deps.comments.cleared) != 0 {
+					t.Fatalf("comments cleared for a failed delete: %v", deps.comments.cleared)
+				}
+				return
+			}
+			if deps.expenses.deleted != testExpenseID || len(deps.comments.cleared) != 1 {
+				t.Fatalf("expected the expense and its comments deleted, got %q %v", deps.expenses.deleted, deps.comments.cleared)
+			}
+		})
+	}
+}
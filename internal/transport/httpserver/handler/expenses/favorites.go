@@ -0,0 +1,74 @@
+package expenses
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// FavoriteCategory pins a category for the caller, so it can be listed
+// first when picking categories for an expense.
+func (h *Handlers) FavoriteCategory(w http.ResponseWriter, r *http.Request) {
+	h.setCategoryFavorite(w, r, "categories.favorite", true)
+}
+
+func (h *Handlers) UnfavoriteCategory(w http.ResponseWriter, r *http.Request) {
+	h.setCategoryFavorite(w, r, "categories.unfavorite", false)
+}
+
+func (h *Handlers) setCategoryFavorite(w http.ResponseWriter, r *http.Request, op string, favorite bool) {
+	categoryID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if categoryID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	if favorite {
+		if _, err := h.Expenses.GetCategory(r.Context(), family.ID, categoryID); err != nil {
+			if errors.Is(err, expensesdomain.ErrCategoryNotFound) {
+				commonhandler.RequestLog(r, h.log).BusinessError(op+": category not found", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+				writeError(w, http.StatusNotFound, "category_not_found", "category not found")
+				return
+			}
+			commonhandler.RequestLog(r, h.log).InternalError(op+": get category failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+		err = h.Favorites.Add(r.Context(), user.ID, favoritesdomain.EntityCategory, categoryID)
+	} else {
+		err = h.Favorites.Remove(r.Context(), user.ID, favoritesdomain.EntityCategory, categoryID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, favoritesdomain.ErrInvalidEntityID):
+			writeError(w, http.StatusNotFound, "category_not_found", "category not found")
+		case errors.Is(err, favoritesdomain.ErrTooManyFavorites):
+			commonhandler.RequestLog(r, h.log).BusinessError(op+": too many favorites", err, "user_id", user.ID, "category_id", categoryID)
+			writeError(w, http.StatusConflict, "too_many_favorites", "too many favorite categories")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError(op+": update favorite failed", err, "user_id", user.ID, "family_id", family.ID, "category_id", categoryID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,104 @@
+package expenses
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+type geoClusterResponse struct {
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	Count        int64   `json:"count"`
+	AmountInBase float64 `json:"amount_in_base"`
+	ExpenseID    string  `json:"expense_id,omitempty"`
+}
+
+type geoClusterListResponse struct {
+	Items        []geoClusterResponse `json:"items"`
+	Grid         int                  `json:"grid"`
+	BaseCurrency string               `json:"base_currency"`
+}
+
+// ExpensesGeo clusters located expenses inside a bounding box for the map
+// view.
+func (h *Handlers) ExpensesGeo(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	var filter expensesdomain.GeoFilter
+	for _, param := range []struct {
+		name  string
+		value *float64
+	}{
+		{"south", &filter.South},
+		{"west", &filter.West},
+		{"north", &filter.North},
+		{"east", &filter.East},
+	} {
+		value, err := strconv.ParseFloat(strings.TrimSpace(query.Get(param.name)), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			writeError(w, http.StatusBadRequest, "invalid_request", param.name+" is required and must be a number")
+			return
+		}
+		*param.value = value
+	}
+	grid, err := parseIntParam(query.Get("grid"), expensesdomain.DefaultGeoGrid)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid grid")
+		return
+	}
+	filter.Grid = grid
+	if filter.From, err = parseDateParam(query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid from")
+		return
+	}
+	if filter.To, err = parseDateParam(query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid to")
+		return
+	}
+	filter.CategoryIDs = parseCSV(query.Get("category_ids"))
+
+	clusters, err := h.Expenses.ClusterExpenseLocations(r.Context(), family.ID, filter)
+	if err != nil {
+		if errors.Is(err, expensesdomain.ErrInvalidBoundingBox) {
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.geo: invalid bounding box", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusBadRequest, "invalid_request", "south must be below north, west below east, and grid between 1 and 64")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.geo: cluster locations failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := geoClusterListResponse{
+		Items:        make([]geoClusterResponse, 0, len(clusters)),
+		Grid:         filter.Grid,
+		BaseCurrency: family.DefaultCurrency,
+	}
+	for _, cluster := range clusters {
+		response.Items = append(response.Items, geoClusterResponse{
+			Latitude:     cluster.Latitude,
+			Longitude:    cluster.Longitude,
+			Count:        cluster.Count,
+			AmountInBase: cluster.AmountInBase,
+			ExpenseID:    cluster.ExpenseID,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -1,27 +1,34 @@
 package expenses
 
 import (
-	analyticsdomain "family-app-go/internal/domain/analytics"
-	expensesdomain "family-app-go/internal/domain/expenses"
-	familydomain "family-app-go/internal/domain/family"
-	ratesdomain "family-app-go/internal/domain/rates"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/pkg/logger"
 )
 
 type Handlers struct {
-	Analytics *analyticsdomain.Service
-	Families  *familydomain.Service
-	Expenses  *expensesdomain.Service
-	Rates     *ratesdomain.Service
+	Analytics AnalyticsService
+	Expenses  ExpensesService
+	Rates     RatesService
+	Activity  ActivityService
+	Favorites FavoritesService
+	Flags     FeatureFlagsService
+	Todos     TodosService
+	Comments  CommentsService
+	limits    commonhandler.ListLimits
 	log       logger.Logger
 }
 
-func New(analytics *analyticsdomain.Service, families *familydomain.Service, expenses *expensesdomain.Service, rates *ratesdomain.Service, log logger.Logger) *Handlers {
+func New(analytics AnalyticsService, expenses ExpensesService, rates RatesService, activity ActivityService, favorites FavoritesService, flags FeatureFlagsService, todos TodosService, comments CommentsService, limits commonhandler.ListLimits, log logger.Logger) *Handlers {
 	return &Handlers{
 		Analytics: analytics,
-		Families:  families,
 		Expenses:  expenses,
 		Rates:     rates,
+		Activity:  activity,
+		Favorites: favorites,
+		Flags:     flags,
+		Todos:     todos,
+		Comments:  comments,
+		limits:    limits,
 		log:       log,
 	}
 }
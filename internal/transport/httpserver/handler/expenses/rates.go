@@ -4,8 +4,10 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	ratesdomain "family-app-go/internal/domain/rates"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 )
 
 type currencyResponse struct {
@@ -23,10 +25,17 @@ type exchangeRateResponse struct {
 	Source string  `json:"source"`
 }
 
+type rateTableResponse struct {
+	Base   string             `json:"base"`
+	Date   string             `json:"date"`
+	Source string             `json:"source"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
 func (h *Handlers) ListCurrencies(w http.ResponseWriter, r *http.Request) {
 	currencies, err := h.Rates.ListCurrencies(r.Context())
 	if err != nil {
-		h.log.InternalError("rates.list_currencies: list currencies failed", err)
+		commonhandler.RequestLog(r, h.log).InternalError("rates.list_currencies: list currencies failed", err)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -62,7 +71,7 @@ func (h *Handlers) GetExchangeRate(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, ratesdomain.ErrRateNotAvailable):
 			writeError(w, http.StatusNotFound, "rate_not_available", "rate is not available for selected date")
 		default:
-			h.log.InternalError("rates.get_exchange_rate: get rate failed", err, "from", from, "to", to, "date", date.Format("2006-01-02"))
+			commonhandler.RequestLog(r, h.log).InternalError("rates.get_exchange_rate: get rate failed", err, "from", from, "to", to, "date", date.Format("2006-01-02"))
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
@@ -76,3 +85,39 @@ func (h *Handlers) GetExchangeRate(w http.ResponseWriter, r *http.Request) {
 		Source: quote.Source,
 	})
 }
+
+func (h *Handlers) GetFXRates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	base := strings.TrimSpace(query.Get("base"))
+	if base == "" {
+		base = "EUR"
+	}
+	date := time.Now().UTC()
+	if parsed, err := parseDateParam(query.Get("date")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
+		return
+	} else if parsed != nil {
+		date = *parsed
+	}
+
+	table, err := h.Rates.GetRates(r.Context(), base, date)
+	if err != nil {
+		switch {
+		case errors.Is(err, ratesdomain.ErrInvalidCurrency):
+			writeError(w, http.StatusBadRequest, "invalid_request", "base must be a 3-letter currency code")
+		case errors.Is(err, ratesdomain.ErrRateNotAvailable):
+			writeError(w, http.StatusNotFound, "rate_not_available", "rates are not available for selected date")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("rates.get_fx_rates: get rates failed", err, "base", base, "date", date.Format("2006-01-02"))
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rateTableResponse{
+		Base:   table.Base,
+		Date:   table.Date.Format("2006-01-02"),
+		Source: table.Source,
+		Rates:  table.Rates,
+	})
+}
@@ -0,0 +1,92 @@
+package expenses
+
+import (
+	"context"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	analyticsdomain "family-app-go/internal/domain/analytics"
+	commentsdomain "family-app-go/internal/domain/comments"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	ratesdomain "family-app-go/internal/domain/rates"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+// ActivityService is implemented by *activitydomain.Service.
+type ActivityService interface {
+	RecordExpenseCreated(ctx context.Context, familyID string, actor activitydomain.Actor, expenseID, title string) (*activitydomain.Event, error)
+}
+
+// AnalyticsService is implemented by *analyticsdomain.Service.
+type AnalyticsService interface {
+	ByCategory(ctx context.Context, familyID string, filter analyticsdomain.ByCategoryFilter) ([]analyticsdomain.ByCategoryRow, error)
+	CategoryTrends(ctx context.Context, familyID string, filter analyticsdomain.CategoryTrendsFilter) (analyticsdomain.CategoryTrendsResult, error)
+	Compare(ctx context.Context, familyID string, filter analyticsdomain.CompareFilter) (analyticsdomain.CompareResult, error)
+	Monthly(ctx context.Context, familyID string, filter analyticsdomain.MonthlyFilter) ([]analyticsdomain.MonthlyRow, error)
+	Summary(ctx context.Context, familyID string, filter analyticsdomain.SummaryFilter) (analyticsdomain.SummaryResult, error)
+	Timeseries(ctx context.Context, familyID string, filter analyticsdomain.TimeseriesFilter) ([]analyticsdomain.TimeseriesPoint, error)
+	TopCategories(ctx context.Context, familyID string) (analyticsdomain.TopCategoriesResult, error)
+}
+
+// CommentsService is implemented by *commentsdomain.Service.
+type CommentsService interface {
+	Clear(ctx context.Context, entityType commentsdomain.EntityType, entityID string) error
+	Counts(ctx context.Context, familyID string, entityType commentsdomain.EntityType, entityIDs []string, userID string) (map[string]commentsdomain.Counts, error)
+	Create(ctx context.Context, input commentsdomain.CreateInput) (*commentsdomain.Comment, error)
+	Delete(ctx context.Context, thread commentsdomain.Thread, commentID, authorID string) error
+	List(ctx context.Context, thread commentsdomain.Thread, readerID string, filter commentsdomain.ListFilter) ([]commentsdomain.Comment, int64, error)
+	Update(ctx context.Context, input commentsdomain.UpdateInput) (*commentsdomain.Comment, error)
+}
+
+// ExpensesService is implemented by *expensesdomain.Service.
+type ExpensesService interface {
+	ApproveExpense(ctx context.Context, familyID, approvalID, deciderID string) (*expensesdomain.ExpenseApproval, *expensesdomain.ExpenseWithCategories, error)
+	ArchiveExpensesBefore(ctx context.Context, familyID string, before time.Time) (int64, error)
+	ClusterExpenseLocations(ctx context.Context, familyID string, filter expensesdomain.GeoFilter) ([]expensesdomain.GeoCluster, error)
+	CreateCategory(ctx context.Context, input expensesdomain.CreateCategoryInput) (*expensesdomain.Category, error)
+	CreateCategoryRule(ctx context.Context, input expensesdomain.CreateCategoryRuleInput) (*expensesdomain.CategoryRule, error)
+	CreateExpense(ctx context.Context, input expensesdomain.CreateExpenseInput) (*expensesdomain.ExpenseWithCategories, error)
+	DeleteCategory(ctx context.Context, familyID, categoryID string) error
+	DeleteCategoryRule(ctx context.Context, familyID, ruleID string) error
+	DeleteExpense(ctx context.Context, familyID, expenseID string) error
+	GetCategory(ctx context.Context, familyID, categoryID string) (*expensesdomain.Category, error)
+	GetExpense(ctx context.Context, familyID, expenseID string) (*expensesdomain.Expense, error)
+	ImportStatement(ctx context.Context, input expensesdomain.ImportStatementInput) (*expensesdomain.StatementImportResult, error)
+	ListCategories(ctx context.Context, familyID string) ([]expensesdomain.Category, error)
+	ListCategoryRules(ctx context.Context, familyID string) ([]expensesdomain.CategoryRule, error)
+	ListCategoryUsage(ctx context.Context, familyID string) (map[string]expensesdomain.CategoryUsage, error)
+	ListExpenseApprovals(ctx context.Context, familyID string, filter expensesdomain.ApprovalFilter) ([]expensesdomain.ExpenseApproval, error)
+	ListExpenses(ctx context.Context, familyID string, filter expensesdomain.ListFilter) ([]expensesdomain.ExpenseWithCategories, int64, error)
+	RejectExpense(ctx context.Context, familyID, approvalID, deciderID string) (*expensesdomain.ExpenseApproval, error)
+	SummarizeExpenses(ctx context.Context, familyID, baseCurrency string, filter expensesdomain.ListFilter) (expensesdomain.ExpenseTotals, error)
+	UpdateCategory(ctx context.Context, input expensesdomain.UpdateCategoryInput) (*expensesdomain.Category, error)
+	UpdateCategoryRule(ctx context.Context, input expensesdomain.UpdateCategoryRuleInput) (*expensesdomain.CategoryRule, error)
+	UpdateExpense(ctx context.Context, input expensesdomain.UpdateExpenseInput) (*expensesdomain.ExpenseWithCategories, error)
+}
+
+// FavoritesService is implemented by *favoritesdomain.Service.
+type FavoritesService interface {
+	Add(ctx context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error
+	Clear(ctx context.Context, entityType favoritesdomain.EntityType, entityID string) error
+	IDs(ctx context.Context, userID string, entityType favoritesdomain.EntityType) (map[string]bool, error)
+	Remove(ctx context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error
+}
+
+// FeatureFlagsService is implemented by *featureflagsdomain.Service.
+type FeatureFlagsService interface {
+	Enabled(ctx context.Context, key, familyID string) (bool, error)
+}
+
+// RatesService is implemented by *ratesdomain.Service.
+type RatesService interface {
+	GetRate(ctx context.Context, from, to string, onDate time.Time) (ratesdomain.Quote, error)
+	GetRates(ctx context.Context, base string, onDate time.Time) (ratesdomain.RateTable, error)
+	ListCurrencies(ctx context.Context) ([]ratesdomain.Currency, error)
+}
+
+// TodosService is implemented by *todosdomain.Service.
+type TodosService interface {
+	ItemForExpense(ctx context.Context, familyID, itemID, viewerID string) (*todosdomain.TodoItem, error)
+	UpdateTodoItem(ctx context.Context, input todosdomain.UpdateTodoItemInput) (*todosdomain.TodoItem, error)
+}
@@ -0,0 +1,178 @@
+package expenses
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	quotadomain "family-app-go/internal/domain/quota"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+const maxStatementFileSizeBytes = 10 * 1024 * 1024
+
+type statementRowErrorResponse struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type statementItemResponse struct {
+	Row                 int     `json:"row"`
+	Date                string  `json:"date"`
+	Amount              float64 `json:"amount"`
+	Currency            string  `json:"currency"`
+	Title               string  `json:"title"`
+	Status              string  `json:"status"`
+	ExpenseID           *string `json:"expense_id"`
+	ApprovalID          *string `json:"approval_id"`
+	MatchedExpenseID    *string `json:"matched_expense_id"`
+	SuggestedCategoryID *string `json:"suggested_category_id"`
+}
+
+type statementImportResponse struct {
+	DryRun          bool                        `json:"dry_run"`
+	Format          string                      `json:"format"`
+	Rows            int                         `json:"rows"`
+	Created         int                         `json:"created"`
+	Duplicates      int                         `json:"duplicates"`
+	Incoming        int                         `json:"incoming"`
+	PendingApproval int                         `json:"pending_approval"`
+	Items           []statementItemResponse     `json:"items"`
+	Errors          []statementRowErrorResponse `json:"errors"`
+}
+
+func (h *Handlers) ImportStatement(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementFileSizeBytes+1024*1024)
+	if err := r.ParseMultipartForm(maxStatementFileSizeBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "import_file_too_large", "import file is too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_request", "multipart form with file is required")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "file is required")
+		return
+	}
+	defer file.Close()
+
+	dryRun := false
+	if value := strings.TrimSpace(r.FormValue("dry_run")); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid dry_run")
+			return
+		}
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(r.FormValue("currency")))
+	if currency == "" {
+		currency = family.DefaultCurrency
+	}
+	v := validation.New()
+	v.Currency("currency", currency)
+	if err := v.Err(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	result, err := h.Expenses.ImportStatement(r.Context(), expensesdomain.ImportStatementInput{
+		FamilyID:     family.ID,
+		UserID:       user.ID,
+		Format:       expensesdomain.StatementFormat(strings.ToLower(strings.TrimSpace(r.FormValue("format")))),
+		Currency:     currency,
+		BaseCurrency: family.DefaultCurrency,
+		DryRun:       dryRun,
+		Data:         file,
+
+		AllowedCurrencies: family.Currencies(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, expensesdomain.ErrUnsupportedStatementFormat):
+			writeError(w, http.StatusBadRequest, "invalid_request", "format must be auto, ofx, camt or csv")
+		case errors.Is(err, expensesdomain.ErrInvalidStatementFile):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.import_statement: invalid statement file", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusUnprocessableEntity, "invalid_import_file", "file is not a supported bank statement")
+		case errors.Is(err, expensesdomain.ErrStatementTooLarge):
+			writeError(w, http.StatusRequestEntityTooLarge, "import_file_too_large", fmt.Sprintf("import is limited to %d rows", expensesdomain.MaxStatementRows))
+		case errors.Is(err, expensesdomain.ErrRateNotAvailable):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.import_statement: rate not available", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusUnprocessableEntity, "rate_not_available", "rate is not available for a statement date")
+		case errors.Is(err, quotadomain.ErrExceeded):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.import_statement: quota exceeded", err, "user_id", user.ID, "family_id", family.ID)
+			writeQuotaExceeded(w, err)
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("expenses.import_statement: import failed", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	items := make([]statementItemResponse, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, statementItemResponse{
+			Row:                 item.Row,
+			Date:                item.Date.Format("2006-01-02"),
+			Amount:              item.Amount,
+			Currency:            item.Currency,
+			Title:               item.Title,
+			Status:              string(item.Status),
+			ExpenseID:           optionalString(item.ExpenseID),
+			ApprovalID:          optionalString(item.ApprovalID),
+			MatchedExpenseID:    optionalString(item.MatchedExpenseID),
+			SuggestedCategoryID: optionalString(item.SuggestedCategoryID),
+		})
+	}
+	rowErrors := make([]statementRowErrorResponse, 0, len(result.Errors))
+	for _, rowErr := range result.Errors {
+		rowErrors = append(rowErrors, statementRowErrorResponse{Row: rowErr.Row, Message: rowErr.Message})
+	}
+
+	status := http.StatusCreated
+	if result.DryRun {
+		status = http.StatusOK
+	} else {
+		commonhandler.RequestLog(r, h.log).Info("expenses.import_statement: statement imported", "user_id", user.ID, "family_id", family.ID, "format", result.Format, "created", result.Created, "duplicates", result.Duplicates)
+	}
+
+	writeJSON(w, status, statementImportResponse{
+		DryRun:          result.DryRun,
+		Format:          string(result.Format),
+		Rows:            result.Rows,
+		Created:         result.Created,
+		Duplicates:      result.Duplicates,
+		Incoming:        result.Incoming,
+		PendingApproval: result.PendingApproval,
+		Items:           items,
+		Errors:          rowErrors,
+	})
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,155 @@
+package expenses
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+// createExpenseFromTodoItemRequest overrides what the expense is pre-filled
+// with; every field is optional.
+type createExpenseFromTodoItemRequest struct {
+	Date        *string  `json:"date"`
+	Amount      *float64 `json:"amount"`
+	Currency    *string  `json:"currency"`
+	CategoryIDs []string `json:"category_ids"`
+}
+
+func (req createExpenseFromTodoItemRequest) Validate(v *validation.Validator) {
+	v.Date("date", req.Date)
+	if req.Amount != nil {
+		v.Positive("amount", *req.Amount)
+	}
+	if req.Currency != nil {
+		v.Currency("currency", *req.Currency)
+	}
+}
+
+// CreateExpenseFromTodoItem files a completed todo item as an expense and
+// links the two. The expense takes the item's title, today's date and its
+// estimated price unless the body says otherwise; without categories one
+// is suggested from the family's history. Above the caller's spending limit
+// the expense waits for approval and the item stays unlinked.
+func (h *Handlers) CreateExpenseFromTodoItem(w http.ResponseWriter, r *http.Request) {
+	var req createExpenseFromTodoItemRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	itemID := strings.TrimSpace(chi.URLParam(r, "item_id"))
+	if itemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "item_id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.Todos.ItemForExpense(r.Context(), family.ID, itemID, user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, todosdomain.ErrTodoItemNotFound), errors.Is(err, todosdomain.ErrTodoListNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.from_todo: todo item not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusNotFound, "todo_item_not_found", "todo item not found")
+		case errors.Is(err, todosdomain.ErrTodoItemNotCompleted):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.from_todo: item not completed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusConflict, "todo_item_not_completed", "only completed todo items can be filed as expenses")
+		case errors.Is(err, todosdomain.ErrTodoItemLinked):
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.from_todo: item already linked", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusConflict, "todo_item_linked", "todo item is already linked to an expense")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("expenses.from_todo: get todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	currency := family.DefaultCurrency
+	if req.Currency != nil {
+		currency = strings.ToUpper(strings.TrimSpace(*req.Currency))
+	}
+	amount := req.Amount
+	// The estimate is in the family currency, so it only stands in for the
+	// amount there.
+	if amount == nil && currency == family.DefaultCurrency && item.EstimatedPrice != nil && *item.EstimatedPrice > 0 {
+		amount = item.EstimatedPrice
+	}
+	if amount == nil {
+		writeValidationError(w, validation.FieldErr("amount", validation.CodeRequired, "amount is required when the item has no estimated price in the family currency"))
+		return
+	}
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.Date != nil {
+		if parsed, _ := parseDateParam(*req.Date); parsed != nil {
+			date = *parsed
+		}
+	}
+
+	created, err := h.Expenses.CreateExpense(r.Context(), expensesdomain.CreateExpenseInput{
+		FamilyID:     family.ID,
+		UserID:       user.ID,
+		Date:         date,
+		Amount:       *amount,
+		Currency:     currency,
+		BaseCurrency: family.DefaultCurrency,
+		Title:        item.Title,
+		CategoryIDs:  req.CategoryIDs,
+
+		AllowedCurrencies: family.Currencies(),
+	})
+	var approvalErr *expensesdomain.ApprovalRequiredError
+	if errors.As(err, &approvalErr) {
+		writeJSON(w, http.StatusAccepted, toExpenseApprovalResponse(approvalErr.Approval))
+		return
+	}
+	if err != nil {
+		h.writeCreateExpenseError(w, r, "expenses.from_todo", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+		return
+	}
+
+	_, err = h.Todos.UpdateTodoItem(r.Context(), todosdomain.UpdateTodoItemInput{
+		ID:              item.ID,
+		FamilyID:        family.ID,
+		ViewerID:        user.ID,
+		ExpectedVersion: item.Version,
+		ExpenseID:       todosdomain.OptionalNullableString{Set: true, Value: &created.ID},
+	})
+	if err != nil {
+		// Without the link the expense would be a silent duplicate of
+		// whatever the client retries with.
+		if deleteErr := h.Expenses.DeleteExpense(r.Context(), family.ID, created.ID); deleteErr != nil {
+			commonhandler.RequestLog(r, h.log).InternalError("expenses.from_todo: delete unlinked expense failed", deleteErr, "user_id", user.ID, "family_id", family.ID, "expense_id", created.ID)
+		}
+		if errors.Is(err, todosdomain.ErrVersionConflict) {
+			commonhandler.RequestLog(r, h.log).BusinessError("expenses.from_todo: item changed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusConflict, "version_conflict", "todo item changed, retry")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.from_todo: link todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	if _, err := h.Activity.RecordExpenseCreated(r.Context(), family.ID, actorFromUser(user), created.ID, created.Title); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("expenses.from_todo: record activity failed", err, "user_id", user.ID, "family_id", family.ID, "expense_id", created.ID)
+	}
+
+	setETag(w, created.Version)
+	writeJSON(w, http.StatusCreated, toExpenseResponse(*created))
+}
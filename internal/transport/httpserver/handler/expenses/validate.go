@@ -0,0 +1,88 @@
+package expenses
+
+import (
+	"strings"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+const (
+	maxCategoryNameLength        = 50
+	maxCategoryRulePatternLength = 200
+)
+
+var categoryRuleMatchTypes = []string{"contains", "prefix", "exact"}
+
+func (req createExpenseRequest) Validate(v *validation.Validator) {
+	validateExpense(v, req.Date, req.Amount, req.Title, req.Currency)
+	if req.Location != nil {
+		v.Nested("location", req.Location)
+	}
+}
+
+func (req updateExpenseRequest) Validate(v *validation.Validator) {
+	validateExpense(v, req.Date, req.Amount, req.Title, req.Currency)
+	if req.Location != nil {
+		v.Nested("location", req.Location)
+	}
+}
+
+func (req *locationRequest) Validate(v *validation.Validator) {
+	v.Check((req.Latitude == nil) == (req.Longitude == nil), "", validation.CodeInvalid, "location must have both latitude and longitude or neither")
+	if req.Latitude != nil {
+		v.Check(*req.Latitude >= -90 && *req.Latitude <= 90, "latitude", validation.CodeRange, "location.latitude must be between -90 and 90")
+	}
+	if req.Longitude != nil {
+		v.Check(*req.Longitude >= -180 && *req.Longitude <= 180, "longitude", validation.CodeRange, "location.longitude must be between -180 and 180")
+	}
+	if req.PlaceName != nil {
+		v.MaxLength("place_name", *req.PlaceName, expensesdomain.MaxPlaceNameLength)
+	}
+}
+
+func validateExpense(v *validation.Validator, date string, amount float64, title, currency string) {
+	v.Required("date", date)
+	v.Date("date", &date)
+	v.Positive("amount", amount)
+	v.Precision("amount", amount, currency)
+	v.Required("title", title)
+	v.Required("currency", currency)
+	v.Currency("currency", currency)
+}
+
+func (req createCategoryRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, maxCategoryNameLength)
+}
+
+func (req updateCategoryRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, maxCategoryNameLength)
+}
+
+func (req createCategoryRuleRequest) Validate(v *validation.Validator) {
+	v.Required("pattern", req.Pattern)
+	v.MaxLength("pattern", req.Pattern, maxCategoryRulePatternLength)
+	v.OneOf("match_type", strings.ToLower(req.MatchType), categoryRuleMatchTypes...)
+	v.Required("category_id", req.CategoryID)
+	v.UUID("category_id", req.CategoryID)
+}
+
+func (req updateCategoryRuleRequest) Validate(v *validation.Validator) {
+	if req.Pattern == nil && req.MatchType == nil && req.CategoryID == nil && req.Priority == nil {
+		v.Add("", validation.CodeEmpty, "no fields to update")
+	}
+	v.NotBlank("pattern", req.Pattern)
+	if req.Pattern != nil {
+		v.MaxLength("pattern", *req.Pattern, maxCategoryRulePatternLength)
+	}
+	if req.MatchType != nil {
+		v.Required("match_type", *req.MatchType)
+		v.OneOf("match_type", strings.ToLower(*req.MatchType), categoryRuleMatchTypes...)
+	}
+	if req.CategoryID != nil {
+		v.Required("category_id", *req.CategoryID)
+		v.UUID("category_id", *req.CategoryID)
+	}
+}
@@ -0,0 +1,185 @@
+package exports
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	auditdomain "family-app-go/internal/domain/audit"
+	exportsdomain "family-app-go/internal/domain/exports"
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+const downloadPath = "/api/exports/download"
+
+type exportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	SizeBytes   int64      `json:"size_bytes"`
+	Error       *string    `json:"error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	DownloadURL *string    `json:"download_url"`
+}
+
+func (h *Handlers) RequestExport(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	export, err := h.Exports.Request(r.Context(), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, exportsdomain.ErrExportsDisabled):
+			writeError(w, http.StatusNotFound, "exports_disabled", "family exports are disabled")
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError("exports.request: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, exportsdomain.ErrExportInProgress):
+			writeError(w, http.StatusConflict, "export_in_progress", "an export is already in progress")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("exports.request: create export failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventExportRequested, func(input *auditdomain.Input) {
+		input.FamilyID = export.FamilyID
+		input.TargetID = export.ID
+	})
+
+	w.Header().Set("Location", "/api/families/me/exports/"+export.ID)
+	writeJSON(w, http.StatusAccepted, h.toExportResponse(r, export))
+}
+
+func (h *Handlers) GetExport(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	exportID := strings.TrimSpace(chi.URLParam(r, "id"))
+	v := validation.New()
+	v.UUID("id", exportID)
+	if err := v.Err(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	export, err := h.Exports.Get(r.Context(), user.ID, exportID)
+	if err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError("exports.get: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, exportsdomain.ErrExportNotFound):
+			writeError(w, http.StatusNotFound, "export_not_found", "export not found")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("exports.get: get export failed", err, "user_id", user.ID, "export_id", exportID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.toExportResponse(r, export))
+}
+
+// Download serves a ready archive. It is mounted outside of the auth group so
+// the link can be opened directly in a browser; the signed token in the query
+// string is the only credential.
+func (h *Handlers) Download(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSpace(r.URL.Query().Get("token"))
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "token is required")
+		return
+	}
+
+	download, err := h.Exports.Open(r.Context(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, exportsdomain.ErrExportsDisabled):
+			writeError(w, http.StatusNotFound, "exports_disabled", "family exports are disabled")
+		case errors.Is(err, exportsdomain.ErrInvalidDownloadToken):
+			writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		case errors.Is(err, exportsdomain.ErrExportExpired):
+			writeError(w, http.StatusGone, "export_expired", "export has expired")
+		case errors.Is(err, exportsdomain.ErrExportNotFound):
+			writeError(w, http.StatusNotFound, "export_not_found", "export not found")
+		case errors.Is(err, exportsdomain.ErrExportNotReady):
+			writeError(w, http.StatusConflict, "export_not_ready", "export is not ready")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("exports.download: open archive failed", err)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventExportDownloaded, func(input *auditdomain.Input) {
+		input.FamilyID = download.FamilyID
+		input.TargetID = download.ExportID
+	})
+
+	defer download.Body.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+download.FileName+`"`)
+	w.Header().Set("Content-Length", strconv.FormatInt(download.Size, 10))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, download.Body); err != nil {
+		commonhandler.RequestLog(r, h.log).BusinessError("exports.download: write archive failed", err, "export_id", download.ExportID)
+	}
+}
+
+func (h *Handlers) toExportResponse(r *http.Request, export *exportsdomain.Export) exportResponse {
+	response := exportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		SizeBytes:   export.SizeBytes,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == exportsdomain.StatusReady {
+		token, err := h.Exports.IssueDownloadToken(*export)
+		if err != nil {
+			commonhandler.RequestLog(r, h.log).Warn("exports: issue download token failed", "export_id", export.ID, "error", err)
+			return response
+		}
+		link := downloadURL(r, token)
+		response.DownloadURL = &link
+	}
+	return response
+}
+
+func downloadURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")); forwarded != "" {
+		scheme = forwarded
+	}
+
+	target := url.URL{
+		Scheme:   scheme,
+		Host:     r.Host,
+		Path:     downloadPath,
+		RawQuery: url.Values{"token": []string{token}}.Encode(),
+	}
+	return target.String()
+}
@@ -0,0 +1,20 @@
+package exports
+
+import (
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Exports ExportsService
+	Audit   commonhandler.AuditRecorder
+	log     logger.Logger
+}
+
+func New(exports ExportsService, audit commonhandler.AuditRecorder, log logger.Logger) *Handlers {
+	return &Handlers{
+		Exports: exports,
+		Audit:   audit,
+		log:     log,
+	}
+}
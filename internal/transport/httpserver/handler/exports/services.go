@@ -0,0 +1,15 @@
+package exports
+
+import (
+	"context"
+
+	exportsdomain "family-app-go/internal/domain/exports"
+)
+
+// ExportsService is implemented by *exportsdomain.Service.
+type ExportsService interface {
+	Get(ctx context.Context, userID, exportID string) (*exportsdomain.Export, error)
+	IssueDownloadToken(export exportsdomain.Export) (string, error)
+	Open(ctx context.Context, token string) (*exportsdomain.Download, error)
+	Request(ctx context.Context, userID string) (*exportsdomain.Export, error)
+}
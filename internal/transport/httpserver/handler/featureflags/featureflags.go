@@ -0,0 +1,161 @@
+package featureflags
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/id"
+	"github.com/go-chi/chi/v5"
+)
+
+type setFlagRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type familyFlagsResponse struct {
+	Flags map[string]bool `json:"flags"`
+}
+
+type overrideResponse struct {
+	FamilyID  string    `json:"family_id"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type flagResponse struct {
+	Key         string             `json:"key"`
+	Description string             `json:"description"`
+	Default     bool               `json:"default"`
+	Enabled     bool               `json:"enabled"`
+	UpdatedAt   *time.Time         `json:"updated_at"`
+	Overrides   []overrideResponse `json:"overrides"`
+}
+
+type flagListResponse struct {
+	Items []flagResponse `json:"items"`
+}
+
+// GetFamilyFlags returns every flag as evaluated for the caller's family, so
+// clients can hide features the server would refuse.
+func (h *Handlers) GetFamilyFlags(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	flags, err := h.Flags.Evaluate(r.Context(), family.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("feature_flags.get: evaluate flags failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, familyFlagsResponse{Flags: flags})
+}
+
+func (h *Handlers) ListFlags(w http.ResponseWriter, r *http.Request) {
+	states, err := h.Flags.List(r.Context())
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("admin.feature_flags: list flags failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := flagListResponse{Items: make([]flagResponse, 0, len(states))}
+	for _, state := range states {
+		item := flagResponse{
+			Key:         state.Key,
+			Description: state.Description,
+			Default:     state.Default,
+			Enabled:     state.Enabled,
+			UpdatedAt:   state.UpdatedAt,
+			Overrides:   make([]overrideResponse, 0, len(state.Overrides)),
+		}
+		for _, override := range state.Overrides {
+			item.Overrides = append(item.Overrides, overrideResponse{
+				FamilyID:  override.FamilyID,
+				Enabled:   override.Enabled,
+				UpdatedAt: override.UpdatedAt,
+			})
+		}
+		response.Items = append(response.Items, item)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) SetGlobalFlag(w http.ResponseWriter, r *http.Request) {
+	var req setFlagRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+	if err := h.Flags.SetGlobal(r.Context(), key, *req.Enabled); err != nil {
+		h.writeFlagError(w, r, "admin.feature_flags.set", err, "flag", key)
+		return
+	}
+
+	commonhandler.RequestLog(r, h.log).Info("admin.feature_flags.set: flag changed", "flag", key, "enabled", *req.Enabled)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) SetFamilyFlag(w http.ResponseWriter, r *http.Request) {
+	var req setFlagRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	key := chi.URLParam(r, "key")
+	familyID := chi.URLParam(r, "family_id")
+	if !id.IsUUID(familyID) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid family id")
+		return
+	}
+	if err := h.Flags.SetFamily(r.Context(), key, familyID, *req.Enabled); err != nil {
+		h.writeFlagError(w, r, "admin.feature_flags.set_family", err, "flag", key, "family_id", familyID)
+		return
+	}
+
+	commonhandler.RequestLog(r, h.log).Info("admin.feature_flags.set_family: flag changed", "flag", key, "family_id", familyID, "enabled", *req.Enabled)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ClearFamilyFlag(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	familyID := chi.URLParam(r, "family_id")
+	if !id.IsUUID(familyID) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid family id")
+		return
+	}
+	if err := h.Flags.ClearFamily(r.Context(), key, familyID); err != nil {
+		h.writeFlagError(w, r, "admin.feature_flags.clear_family", err, "flag", key, "family_id", familyID)
+		return
+	}
+
+	commonhandler.RequestLog(r, h.log).Info("admin.feature_flags.clear_family: override removed", "flag", key, "family_id", familyID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) writeFlagError(w http.ResponseWriter, r *http.Request, op string, err error, kv ...interface{}) {
+	switch {
+	case errors.Is(err, featureflagsdomain.ErrUnknownFlag):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": unknown flag", err, kv...)
+		writeError(w, http.StatusNotFound, "feature_flag_not_found", "feature flag not found")
+	case errors.Is(err, featureflagsdomain.ErrFamilyNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": family not found", err, kv...)
+		writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(op+": update flag failed", err, kv...)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
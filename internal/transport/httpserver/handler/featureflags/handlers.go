@@ -0,0 +1,17 @@
+package featureflags
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Flags FeatureFlagsService
+	log   logger.Logger
+}
+
+func New(flags FeatureFlagsService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Flags: flags,
+		log:   log,
+	}
+}
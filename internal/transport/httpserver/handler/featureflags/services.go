@@ -0,0 +1,16 @@
+package featureflags
+
+import (
+	"context"
+
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+)
+
+// FeatureFlagsService is implemented by *featureflagsdomain.Service.
+type FeatureFlagsService interface {
+	ClearFamily(ctx context.Context, key, familyID string) error
+	Evaluate(ctx context.Context, familyID string) (map[string]bool, error)
+	List(ctx context.Context) ([]featureflagsdomain.State, error)
+	SetFamily(ctx context.Context, key, familyID string, enabled bool) error
+	SetGlobal(ctx context.Context, key string, enabled bool) error
+}
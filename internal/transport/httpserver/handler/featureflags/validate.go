@@ -0,0 +1,7 @@
+package featureflags
+
+import "family-app-go/internal/transport/httpserver/validation"
+
+func (req setFlagRequest) Validate(v *validation.Validator) {
+	v.Check(req.Enabled != nil, "enabled", validation.CodeRequired, "enabled is required")
+}
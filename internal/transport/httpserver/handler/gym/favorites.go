@@ -0,0 +1,52 @@
+package gym
+
+import (
+	"errors"
+	"net/http"
+
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+// FavoriteExercise pins an exercise, given by the name query parameter,
+// for the caller. Exercises are plain names, so any name can be pinned
+// before it is first logged.
+func (h *Handlers) FavoriteExercise(w http.ResponseWriter, r *http.Request) {
+	h.setExerciseFavorite(w, r, "gym.favorite_exercise", true)
+}
+
+func (h *Handlers) UnfavoriteExercise(w http.ResponseWriter, r *http.Request) {
+	h.setExerciseFavorite(w, r, "gym.unfavorite_exercise", false)
+}
+
+func (h *Handlers) setExerciseFavorite(w http.ResponseWriter, r *http.Request, op string, favorite bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	var err error
+	if favorite {
+		err = h.Favorites.Add(r.Context(), user.ID, favoritesdomain.EntityExercise, name)
+	} else {
+		err = h.Favorites.Remove(r.Context(), user.ID, favoritesdomain.EntityExercise, name)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, favoritesdomain.ErrInvalidEntityID):
+			writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+		case errors.Is(err, favoritesdomain.ErrTooManyFavorites):
+			commonhandler.RequestLog(r, h.log).BusinessError(op+": too many favorites", err, "user_id", user.ID)
+			writeError(w, http.StatusConflict, "too_many_favorites", "too many favorite exercises")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError(op+": update favorite failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
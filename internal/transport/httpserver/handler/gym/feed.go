@@ -0,0 +1,67 @@
+package gym
+
+import (
+	"errors"
+	"net/http"
+
+	gymdomain "family-app-go/internal/domain/gym"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+// FamilyFeed lists recent workouts that family members shared with the family.
+func (h *Handlers) FamilyFeed(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), 20)
+	if err != nil || limit <= 0 || limit > 100 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "limit must be between 1 and 100")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	scope := gymdomain.Scope{UserID: user.ID, FamilyID: family.ID, Kind: gymdomain.ScopeFamily}
+	items, total, err := h.Gym.ListFamilyFeed(r.Context(), scope, gymdomain.ListFilter{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		if errors.Is(err, gymdomain.ErrFamilyRequired) {
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.family_feed: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.family_feed: list feed failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := make([]workoutResponse, 0, len(items))
+	for _, workout := range items {
+		response = append(response, toWorkoutResponse(workout))
+	}
+	if err := h.fillLabels(r, response); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.family_feed: list labels failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, workoutListResponse{
+		Items: response,
+		Total: total,
+	})
+}
@@ -0,0 +1,153 @@
+package gym
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	gymdomain "family-app-go/internal/domain/gym"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+type setGoalRequest struct {
+	WorkoutsPerWeek int `json:"workouts_per_week"`
+}
+
+type goalResponse struct {
+	WorkoutsPerWeek int       `json:"workouts_per_week"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type goalNudgeResponse struct {
+	WorkoutsDone int       `json:"workouts_done"`
+	Target       int       `json:"target"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type streakResponse struct {
+	Goal               *goalResponse      `json:"goal"`
+	WeekStart          string             `json:"week_start"`
+	WeekEnd            string             `json:"week_end"`
+	WorkoutsThisWeek   int                `json:"workouts_this_week"`
+	Remaining          int                `json:"remaining"`
+	CurrentStreakWeeks int                `json:"current_streak_weeks"`
+	LongestStreakWeeks int                `json:"longest_streak_weeks"`
+	Nudge              *goalNudgeResponse `json:"nudge"`
+}
+
+func (h *Handlers) GetGoal(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	goal, err := h.Gym.GetGoal(r.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, gymdomain.ErrGoalNotFound) {
+			writeError(w, http.StatusNotFound, "goal_not_found", "goal not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.get_goal: get goal failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toGoalResponse(goal))
+}
+
+func (h *Handlers) SetGoal(w http.ResponseWriter, r *http.Request) {
+	var req setGoalRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	goal, err := h.Gym.SetGoal(r.Context(), user.ID, req.WorkoutsPerWeek)
+	if err != nil {
+		if errors.Is(err, gymdomain.ErrInvalidGoal) {
+			writeValidationError(w, validation.FieldErr("workouts_per_week", validation.CodeRange, fmt.Sprintf(
+				"workouts_per_week must be between 1 and %d",
+				gymdomain.MaxWorkoutsPerWeek,
+			)))
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.set_goal: set goal failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toGoalResponse(goal))
+}
+
+func (h *Handlers) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	if err := h.Gym.DeleteGoal(r.Context(), user.ID); err != nil {
+		if errors.Is(err, gymdomain.ErrGoalNotFound) {
+			writeError(w, http.StatusNotFound, "goal_not_found", "goal not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.delete_goal: delete goal failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) GetStreak(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	streak, err := h.Gym.GetStreak(r.Context(), user.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.get_streak: get streak failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := streakResponse{
+		WeekStart:          streak.WeekStart.Format("2006-01-02"),
+		WeekEnd:            streak.WeekEnd.Format("2006-01-02"),
+		WorkoutsThisWeek:   streak.WorkoutsThisWeek,
+		Remaining:          streak.Remaining(),
+		CurrentStreakWeeks: streak.CurrentStreakWeeks,
+		LongestStreakWeeks: streak.LongestStreakWeeks,
+	}
+	if streak.Goal != nil {
+		goal := toGoalResponse(streak.Goal)
+		response.Goal = &goal
+	}
+	if streak.Nudge != nil {
+		response.Nudge = &goalNudgeResponse{
+			WorkoutsDone: streak.Nudge.WorkoutsDone,
+			Target:       streak.Nudge.Target,
+			CreatedAt:    streak.Nudge.CreatedAt,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func toGoalResponse(goal *gymdomain.Goal) goalResponse {
+	return goalResponse{
+		WorkoutsPerWeek: goal.WorkoutsPerWeek,
+		UpdatedAt:       goal.UpdatedAt,
+	}
+}
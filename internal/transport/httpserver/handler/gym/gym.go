@@ -3,10 +3,14 @@ package gym
 import (
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	favoritesdomain "family-app-go/internal/domain/favorites"
 	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/internal/transport/httpserver/middleware"
 	"github.com/go-chi/chi/v5"
 )
@@ -14,17 +18,19 @@ import (
 // GymEntry handlers
 
 type createGymEntryRequest struct {
-	Date     string  `json:"date"`
-	Exercise string  `json:"exercise"`
-	WeightKg float64 `json:"weight_kg"`
-	Reps     int     `json:"reps"`
+	Date       string  `json:"date"`
+	Exercise   string  `json:"exercise"`
+	WeightKg   float64 `json:"weight_kg"`
+	Reps       int     `json:"reps"`
+	Visibility string  `json:"visibility"`
 }
 
 type updateGymEntryRequest struct {
-	Date     string  `json:"date"`
-	Exercise string  `json:"exercise"`
-	WeightKg float64 `json:"weight_kg"`
-	Reps     int     `json:"reps"`
+	Date       string  `json:"date"`
+	Exercise   string  `json:"exercise"`
+	WeightKg   float64 `json:"weight_kg"`
+	Reps       int     `json:"reps"`
+	Visibility string  `json:"visibility"`
 }
 
 func (h *Handlers) ListGymEntries(w http.ResponseWriter, r *http.Request) {
@@ -46,7 +52,7 @@ func (h *Handlers) ListGymEntries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, err := parseIntParam(query.Get("limit"), 100)
+	limit, err := h.limits.GymEntries.Parse(query.Get("limit"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
 		return
@@ -64,9 +70,19 @@ func (h *Handlers) ListGymEntries(w http.ResponseWriter, r *http.Request) {
 		Offset: offset,
 	}
 
-	items, total, err := h.Gym.ListGymEntries(r.Context(), user.ID, filter)
+	scope, ok := h.resolveScope(w, r, user.ID, "gym.list_entries")
+	if !ok {
+		return
+	}
+
+	items, total, err := h.Gym.ListGymEntries(r.Context(), scope, filter)
 	if err != nil {
-		h.log.InternalError("gym.list_entries: list gym entries failed", err, "user_id", user.ID)
+		if errors.Is(err, gymdomain.ErrFamilyRequired) {
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.list_entries: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_entries: list gym entries failed", err, "user_id", user.ID, "scope", scope.Kind)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -84,8 +100,7 @@ func (h *Handlers) ListGymEntries(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) CreateGymEntry(w http.ResponseWriter, r *http.Request) {
 	var req createGymEntryRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -100,22 +115,24 @@ func (h *Handlers) CreateGymEntry(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
 		return
 	}
-	if strings.TrimSpace(req.Exercise) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "exercise is required")
+	visibility, ok := parseVisibility(req.Visibility)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request", "visibility must be private or family")
 		return
 	}
 
 	input := gymdomain.CreateGymEntryInput{
-		UserID:   user.ID,
-		Date:     date,
-		Exercise: req.Exercise,
-		WeightKg: req.WeightKg,
-		Reps:     req.Reps,
+		UserID:     user.ID,
+		Date:       date,
+		Exercise:   req.Exercise,
+		WeightKg:   req.WeightKg,
+		Reps:       req.Reps,
+		Visibility: visibility,
 	}
 
 	created, err := h.Gym.CreateGymEntry(r.Context(), input)
 	if err != nil {
-		h.log.InternalError("gym.create_entry: create gym entry failed", err, "user_id", user.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.create_entry: create gym entry failed", err, "user_id", user.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -125,8 +142,7 @@ func (h *Handlers) CreateGymEntry(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) UpdateGymEntry(w http.ResponseWriter, r *http.Request) {
 	var req updateGymEntryRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -147,28 +163,30 @@ func (h *Handlers) UpdateGymEntry(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
 		return
 	}
-	if strings.TrimSpace(req.Exercise) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "exercise is required")
+	visibility, ok := parseVisibility(req.Visibility)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request", "visibility must be private or family")
 		return
 	}
 
 	input := gymdomain.UpdateGymEntryInput{
-		ID:       entryID,
-		UserID:   user.ID,
-		Date:     date,
-		Exercise: req.Exercise,
-		WeightKg: req.WeightKg,
-		Reps:     req.Reps,
+		ID:         entryID,
+		UserID:     user.ID,
+		Date:       date,
+		Exercise:   req.Exercise,
+		WeightKg:   req.WeightKg,
+		Reps:       req.Reps,
+		Visibility: visibility,
 	}
 
 	updated, err := h.Gym.UpdateGymEntry(r.Context(), input)
 	if err != nil {
 		if errors.Is(err, gymdomain.ErrGymEntryNotFound) {
-			h.log.BusinessError("gym.update_entry: gym entry not found", err, "user_id", user.ID, "entry_id", entryID)
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.update_entry: gym entry not found", err, "user_id", user.ID, "entry_id", entryID)
 			writeError(w, http.StatusNotFound, "gym_entry_not_found", "gym entry not found")
 			return
 		}
-		h.log.InternalError("gym.update_entry: update gym entry failed", err, "user_id", user.ID, "entry_id", entryID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.update_entry: update gym entry failed", err, "user_id", user.ID, "entry_id", entryID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -191,11 +209,11 @@ func (h *Handlers) DeleteGymEntry(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.Gym.DeleteGymEntry(r.Context(), user.ID, entryID); err != nil {
 		if errors.Is(err, gymdomain.ErrGymEntryNotFound) {
-			h.log.BusinessError("gym.delete_entry: gym entry not found", err, "user_id", user.ID, "entry_id", entryID)
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.delete_entry: gym entry not found", err, "user_id", user.ID, "entry_id", entryID)
 			writeError(w, http.StatusNotFound, "gym_entry_not_found", "gym entry not found")
 			return
 		}
-		h.log.InternalError("gym.delete_entry: delete gym entry failed", err, "user_id", user.ID, "entry_id", entryID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.delete_entry: delete gym entry failed", err, "user_id", user.ID, "entry_id", entryID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -216,12 +234,14 @@ type createWorkoutRequest struct {
 	Name       string                    `json:"name"`
 	Sets       []createWorkoutSetRequest `json:"sets"`
 	TemplateID string                    `json:"template_id"`
+	Visibility string                    `json:"visibility"`
 }
 
 type updateWorkoutRequest struct {
-	Date string                    `json:"date"`
-	Name string                    `json:"name"`
-	Sets []createWorkoutSetRequest `json:"sets"`
+	Date       string                    `json:"date"`
+	Name       string                    `json:"name"`
+	Sets       []createWorkoutSetRequest `json:"sets"`
+	Visibility string                    `json:"visibility"`
 }
 
 func (h *Handlers) ListWorkouts(w http.ResponseWriter, r *http.Request) {
@@ -243,7 +263,7 @@ func (h *Handlers) ListWorkouts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, err := parseIntParam(query.Get("limit"), 100)
+	limit, err := h.limits.Workouts.Parse(query.Get("limit"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
 		return
@@ -254,16 +274,33 @@ func (h *Handlers) ListWorkouts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	labels, err := parseLabelsParam(query.Get("labels"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid labels")
+		return
+	}
+
 	filter := gymdomain.ListFilter{
 		From:   from,
 		To:     to,
 		Limit:  limit,
 		Offset: offset,
+		Labels: labels,
 	}
 
-	items, total, err := h.Gym.ListWorkouts(r.Context(), user.ID, filter)
+	scope, ok := h.resolveScope(w, r, user.ID, "gym.list_workouts")
+	if !ok {
+		return
+	}
+
+	items, total, err := h.Gym.ListWorkouts(r.Context(), scope, filter)
 	if err != nil {
-		h.log.InternalError("gym.list_workouts: list workouts failed", err, "user_id", user.ID)
+		if errors.Is(err, gymdomain.ErrFamilyRequired) {
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.list_workouts: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_workouts: list workouts failed", err, "user_id", user.ID, "scope", scope.Kind)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -272,6 +309,11 @@ func (h *Handlers) ListWorkouts(w http.ResponseWriter, r *http.Request) {
 	for _, workout := range items {
 		response = append(response, toWorkoutResponse(workout))
 	}
+	if err := h.fillLabels(r, response); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_workouts: list labels failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
 
 	writeJSON(w, http.StatusOK, workoutListResponse{
 		Items: response,
@@ -295,22 +337,28 @@ func (h *Handlers) GetWorkout(w http.ResponseWriter, r *http.Request) {
 	workout, err := h.Gym.GetWorkoutByID(r.Context(), user.ID, workoutID)
 	if err != nil {
 		if errors.Is(err, gymdomain.ErrWorkoutNotFound) {
-			h.log.BusinessError("gym.get_workout: workout not found", err, "user_id", user.ID, "workout_id", workoutID)
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.get_workout: workout not found", err, "user_id", user.ID, "workout_id", workoutID)
 			writeError(w, http.StatusNotFound, "workout_not_found", "workout not found")
 			return
 		}
-		h.log.InternalError("gym.get_workout: get workout failed", err, "user_id", user.ID, "workout_id", workoutID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.get_workout: get workout failed", err, "user_id", user.ID, "workout_id", workoutID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := []workoutResponse{toWorkoutResponse(*workout)}
+	if err := h.fillLabels(r, response); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.get_workout: list labels failed", err, "user_id", user.ID, "workout_id", workoutID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toWorkoutResponse(*workout))
+	writeJSON(w, http.StatusOK, response[0])
 }
 
 func (h *Handlers) CreateWorkout(w http.ResponseWriter, r *http.Request) {
 	var req createWorkoutRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -325,8 +373,9 @@ func (h *Handlers) CreateWorkout(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
 		return
 	}
-	if strings.TrimSpace(req.Name) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+	visibility, ok := parseVisibility(req.Visibility)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request", "visibility must be private or family")
 		return
 	}
 
@@ -345,11 +394,17 @@ func (h *Handlers) CreateWorkout(w http.ResponseWriter, r *http.Request) {
 		Name:       req.Name,
 		Sets:       sets,
 		TemplateID: strings.TrimSpace(req.TemplateID),
+		Visibility: visibility,
 	}
 
 	created, err := h.Gym.CreateWorkout(r.Context(), input)
 	if err != nil {
-		h.log.InternalError("gym.create_workout: create workout failed", err, "user_id", user.ID)
+		if errors.Is(err, gymdomain.ErrTemplateNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.create_workout: template not found", err, "user_id", user.ID, "template_id", input.TemplateID)
+			writeError(w, http.StatusNotFound, "template_not_found", "template not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.create_workout: create workout failed", err, "user_id", user.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -359,8 +414,7 @@ func (h *Handlers) CreateWorkout(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
 	var req updateWorkoutRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -381,8 +435,9 @@ func (h *Handlers) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
 		return
 	}
-	if strings.TrimSpace(req.Name) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+	visibility, ok := parseVisibility(req.Visibility)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request", "visibility must be private or family")
 		return
 	}
 
@@ -396,26 +451,34 @@ func (h *Handlers) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := gymdomain.UpdateWorkoutInput{
-		ID:     workoutID,
-		UserID: user.ID,
-		Date:   date,
-		Name:   req.Name,
-		Sets:   sets,
+		ID:         workoutID,
+		UserID:     user.ID,
+		Date:       date,
+		Name:       req.Name,
+		Sets:       sets,
+		Visibility: visibility,
 	}
 
 	updated, err := h.Gym.UpdateWorkout(r.Context(), input)
 	if err != nil {
 		if errors.Is(err, gymdomain.ErrWorkoutNotFound) {
-			h.log.BusinessError("gym.update_workout: workout not found", err, "user_id", user.ID, "workout_id", workoutID)
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.update_workout: workout not found", err, "user_id", user.ID, "workout_id", workoutID)
 			writeError(w, http.StatusNotFound, "workout_not_found", "workout not found")
 			return
 		}
-		h.log.InternalError("gym.update_workout: update workout failed", err, "user_id", user.ID, "workout_id", workoutID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.update_workout: update workout failed", err, "user_id", user.ID, "workout_id", workoutID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toWorkoutResponse(*updated))
+	response := []workoutResponse{toWorkoutResponse(*updated)}
+	if err := h.fillLabels(r, response); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.update_workout: list labels failed", err, "user_id", user.ID, "workout_id", workoutID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response[0])
 }
 
 func (h *Handlers) DeleteWorkout(w http.ResponseWriter, r *http.Request) {
@@ -432,14 +495,18 @@ func (h *Handlers) DeleteWorkout(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := h.Gym.DeleteWorkout(r.Context(), user.ID, workoutID); err != nil {
 		if errors.Is(err, gymdomain.ErrWorkoutNotFound) {
-			h.log.BusinessError("gym.delete_workout: workout not found", err, "user_id", user.ID, "workout_id", workoutID)
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.delete_workout: workout not found", err, "user_id", user.ID, "workout_id", workoutID)
 			writeError(w, http.StatusNotFound, "workout_not_found", "workout not found")
 			return
 		}
-		h.log.InternalError("gym.delete_workout: delete workout failed", err, "user_id", user.ID, "workout_id", workoutID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.delete_workout: delete workout failed", err, "user_id", user.ID, "workout_id", workoutID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	// The workout is gone either way; leftover labels only skew suggestions.
+	if err := h.Labels.Clear(r.Context(), labelsdomain.EntityWorkout, workoutID); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.delete_workout: clear labels failed", err, "user_id", user.ID, "workout_id", workoutID)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -453,13 +520,15 @@ type createTemplateSetRequest struct {
 }
 
 type createTemplateRequest struct {
-	Name string                     `json:"name"`
-	Sets []createTemplateSetRequest `json:"sets"`
+	Name       string                     `json:"name"`
+	Sets       []createTemplateSetRequest `json:"sets"`
+	Visibility string                     `json:"visibility"`
 }
 
 type updateTemplateRequest struct {
-	Name string                     `json:"name"`
-	Sets []createTemplateSetRequest `json:"sets"`
+	Name       string                     `json:"name"`
+	Sets       []createTemplateSetRequest `json:"sets"`
+	Visibility string                     `json:"visibility"`
 }
 
 func (h *Handlers) ListTemplates(w http.ResponseWriter, r *http.Request) {
@@ -469,9 +538,19 @@ func (h *Handlers) ListTemplates(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items, err := h.Gym.ListTemplates(r.Context(), user.ID)
+	scope, ok := h.resolveScope(w, r, user.ID, "gym.list_templates")
+	if !ok {
+		return
+	}
+
+	items, err := h.Gym.ListTemplates(r.Context(), scope)
 	if err != nil {
-		h.log.InternalError("gym.list_templates: list templates failed", err, "user_id", user.ID)
+		if errors.Is(err, gymdomain.ErrFamilyRequired) {
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.list_templates: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_templates: list templates failed", err, "user_id", user.ID, "scope", scope.Kind)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -486,8 +565,7 @@ func (h *Handlers) ListTemplates(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) CreateTemplate(w http.ResponseWriter, r *http.Request) {
 	var req createTemplateRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -497,8 +575,9 @@ func (h *Handlers) CreateTemplate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.TrimSpace(req.Name) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+	visibility, ok := parseVisibility(req.Visibility)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request", "visibility must be private or family")
 		return
 	}
 
@@ -512,14 +591,15 @@ func (h *Handlers) CreateTemplate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := gymdomain.CreateTemplateInput{
-		UserID: user.ID,
-		Name:   req.Name,
-		Sets:   sets,
+		UserID:     user.ID,
+		Name:       req.Name,
+		Sets:       sets,
+		Visibility: visibility,
 	}
 
 	created, err := h.Gym.CreateTemplate(r.Context(), input)
 	if err != nil {
-		h.log.InternalError("gym.create_template: create template failed", err, "user_id", user.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.create_template: create template failed", err, "user_id", user.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -529,8 +609,7 @@ func (h *Handlers) CreateTemplate(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
 	var req updateTemplateRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -546,8 +625,9 @@ func (h *Handlers) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.TrimSpace(req.Name) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "name is required")
+	visibility, ok := parseVisibility(req.Visibility)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request", "visibility must be private or family")
 		return
 	}
 
@@ -561,20 +641,21 @@ func (h *Handlers) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := gymdomain.UpdateTemplateInput{
-		ID:     templateID,
-		UserID: user.ID,
-		Name:   req.Name,
-		Sets:   sets,
+		ID:         templateID,
+		UserID:     user.ID,
+		Name:       req.Name,
+		Sets:       sets,
+		Visibility: visibility,
 	}
 
 	updated, err := h.Gym.UpdateTemplate(r.Context(), input)
 	if err != nil {
 		if errors.Is(err, gymdomain.ErrTemplateNotFound) {
-			h.log.BusinessError("gym.update_template: template not found", err, "user_id", user.ID, "template_id", templateID)
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.update_template: template not found", err, "user_id", user.ID, "template_id", templateID)
 			writeError(w, http.StatusNotFound, "template_not_found", "template not found")
 			return
 		}
-		h.log.InternalError("gym.update_template: update template failed", err, "user_id", user.ID, "template_id", templateID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.update_template: update template failed", err, "user_id", user.ID, "template_id", templateID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -597,11 +678,11 @@ func (h *Handlers) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.Gym.DeleteTemplate(r.Context(), user.ID, templateID); err != nil {
 		if errors.Is(err, gymdomain.ErrTemplateNotFound) {
-			h.log.BusinessError("gym.delete_template: template not found", err, "user_id", user.ID, "template_id", templateID)
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.delete_template: template not found", err, "user_id", user.ID, "template_id", templateID)
 			writeError(w, http.StatusNotFound, "template_not_found", "template not found")
 			return
 		}
-		h.log.InternalError("gym.delete_template: delete template failed", err, "user_id", user.ID, "template_id", templateID)
+		commonhandler.RequestLog(r, h.log).InternalError("gym.delete_template: delete template failed", err, "user_id", user.ID, "template_id", templateID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -618,27 +699,94 @@ func (h *Handlers) ListExercises(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	exercises, err := h.Gym.ListExercises(r.Context(), user.ID)
+	scope, ok := h.resolveScope(w, r, user.ID, "gym.list_exercises")
+	if !ok {
+		return
+	}
+	favoritesFirst := false
+	if value := strings.TrimSpace(r.URL.Query().Get("favorites_first")); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid favorites_first")
+			return
+		}
+		favoritesFirst = parsed
+	}
+
+	exercises, err := h.Gym.ListExercises(r.Context(), scope)
 	if err != nil {
-		h.log.InternalError("gym.list_exercises: list exercises failed", err, "user_id", user.ID)
+		if errors.Is(err, gymdomain.ErrFamilyRequired) {
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.list_exercises: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_exercises: list exercises failed", err, "user_id", user.ID, "scope", scope.Kind)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	favorites, err := h.Favorites.IDs(r.Context(), user.ID, favoritesdomain.EntityExercise)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_exercises: list favorites failed", err, "user_id", user.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if favoritesFirst {
+		favoritesdomain.SortFirst(exercises, favorites, func(name string) string { return name })
+	}
+
+	items := make([]exerciseItemResponse, 0, len(exercises))
+	for _, name := range exercises {
+		items = append(items, exerciseItemResponse{Name: name, IsFavorite: favorites[name]})
+	}
+	writeJSON(w, http.StatusOK, exerciseListResponse{Exercises: exercises, Items: items})
+}
+
+// parseVisibility accepts an empty value so updates from older clients keep
+// the current visibility.
+func parseVisibility(value string) (gymdomain.Visibility, bool) {
+	visibility := gymdomain.Visibility(strings.ToLower(strings.TrimSpace(value)))
+	switch visibility {
+	case "", gymdomain.VisibilityPrivate, gymdomain.VisibilityFamily:
+		return visibility, true
+	default:
+		return "", false
+	}
+}
+
+// resolveScope reads the scope query parameter of list endpoints. The family
+// is only looked up for scope=family, so users without a family keep using
+// their personal gym data.
+func (h *Handlers) resolveScope(w http.ResponseWriter, r *http.Request, userID, operation string) (gymdomain.Scope, bool) {
+	kind, ok := gymdomain.ParseScopeKind(r.URL.Query().Get("scope"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_request", "scope must be me or family")
+		return gymdomain.Scope{}, false
+	}
+	if kind == gymdomain.ScopeMe {
+		return gymdomain.Scope{UserID: userID, Kind: gymdomain.ScopeMe}, true
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return gymdomain.Scope{}, false
+	}
 
-	writeJSON(w, http.StatusOK, exerciseListResponse{Exercises: exercises})
+	return gymdomain.Scope{UserID: userID, FamilyID: family.ID, Kind: gymdomain.ScopeFamily}, true
 }
 
 // Response types
 
 type gymEntryResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Date      string    `json:"date"`
-	Exercise  string    `json:"exercise"`
-	WeightKg  float64   `json:"weight_kg"`
-	Reps      int       `json:"reps"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	Date       string    `json:"date"`
+	Exercise   string    `json:"exercise"`
+	WeightKg   float64   `json:"weight_kg"`
+	Reps       int       `json:"reps"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 type gymEntryListResponse struct {
@@ -654,13 +802,15 @@ type workoutSetResponse struct {
 }
 
 type workoutResponse struct {
-	ID        string               `json:"id"`
-	UserID    string               `json:"user_id"`
-	Date      string               `json:"date"`
-	Name      string               `json:"name"`
-	Sets      []workoutSetResponse `json:"sets"`
-	CreatedAt time.Time            `json:"created_at"`
-	UpdatedAt time.Time            `json:"updated_at"`
+	ID         string               `json:"id"`
+	UserID     string               `json:"user_id"`
+	Date       string               `json:"date"`
+	Name       string               `json:"name"`
+	Visibility string               `json:"visibility"`
+	Sets       []workoutSetResponse `json:"sets"`
+	Labels     []string             `json:"labels"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
 }
 
 type workoutListResponse struct {
@@ -676,12 +826,13 @@ type templateSetResponse struct {
 }
 
 type templateResponse struct {
-	ID        string                `json:"id"`
-	UserID    string                `json:"user_id"`
-	Name      string                `json:"name"`
-	Sets      []templateSetResponse `json:"sets"`
-	CreatedAt time.Time             `json:"created_at"`
-	UpdatedAt time.Time             `json:"updated_at"`
+	ID         string                `json:"id"`
+	UserID     string                `json:"user_id"`
+	Name       string                `json:"name"`
+	Visibility string                `json:"visibility"`
+	Sets       []templateSetResponse `json:"sets"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
 }
 
 type templateListResponse struct {
@@ -689,21 +840,28 @@ type templateListResponse struct {
 }
 
 type exerciseListResponse struct {
-	Exercises []string `json:"exercises"`
+	Exercises []string               `json:"exercises"`
+	Items     []exerciseItemResponse `json:"items"`
+}
+
+type exerciseItemResponse struct {
+	Name       string `json:"name"`
+	IsFavorite bool   `json:"is_favorite"`
 }
 
 // Response mappers
 
 func toGymEntryResponse(entry gymdomain.GymEntry) gymEntryResponse {
 	return gymEntryResponse{
-		ID:        entry.ID,
-		UserID:    entry.UserID,
-		Date:      entry.Date.Format("2006-01-02"),
-		Exercise:  entry.Exercise,
-		WeightKg:  entry.WeightKg,
-		Reps:      entry.Reps,
-		CreatedAt: entry.CreatedAt,
-		UpdatedAt: entry.UpdatedAt,
+		ID:         entry.ID,
+		UserID:     entry.UserID,
+		Date:       entry.Date.Format("2006-01-02"),
+		Exercise:   entry.Exercise,
+		WeightKg:   entry.WeightKg,
+		Reps:       entry.Reps,
+		Visibility: string(entry.Visibility),
+		CreatedAt:  entry.CreatedAt,
+		UpdatedAt:  entry.UpdatedAt,
 	}
 }
 
@@ -719,13 +877,15 @@ func toWorkoutResponse(workout gymdomain.WorkoutWithSets) workoutResponse {
 	}
 
 	return workoutResponse{
-		ID:        workout.ID,
-		UserID:    workout.UserID,
-		Date:      workout.Date.Format("2006-01-02"),
-		Name:      workout.Name,
-		Sets:      sets,
-		CreatedAt: workout.CreatedAt,
-		UpdatedAt: workout.UpdatedAt,
+		ID:         workout.ID,
+		UserID:     workout.UserID,
+		Date:       workout.Date.Format("2006-01-02"),
+		Name:       workout.Name,
+		Visibility: string(workout.Visibility),
+		Sets:       sets,
+		Labels:     []string{},
+		CreatedAt:  workout.CreatedAt,
+		UpdatedAt:  workout.UpdatedAt,
 	}
 }
 
@@ -741,11 +901,12 @@ func toTemplateResponse(template gymdomain.TemplateWithSets) templateResponse {
 	}
 
 	return templateResponse{
-		ID:        template.ID,
-		UserID:    template.UserID,
-		Name:      template.Name,
-		Sets:      sets,
-		CreatedAt: template.CreatedAt,
-		UpdatedAt: template.UpdatedAt,
+		ID:         template.ID,
+		UserID:     template.UserID,
+		Name:       template.Name,
+		Visibility: string(template.Visibility),
+		Sets:       sets,
+		CreatedAt:  template.CreatedAt,
+		UpdatedAt:  template.UpdatedAt,
 	}
 }
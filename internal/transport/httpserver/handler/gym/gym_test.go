@@ -0,0 +1,359 @@
+package gym
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	testFamilyID  = "11111111-1111-1111-1111-111111111111"
+	testUserID    = "22222222-2222-2222-2222-222222222222"
+	testWorkoutID = "66666666-6666-6666-6666-666666666666"
+)
+
+var errDatabase = errors.New("database is down")
+
+// The mocks embed the service interface: a call the test did not expect
+// hits the nil interface and panics.
+
+type mockGym struct {
+	GymService
+	entries  []gymdomain.GymEntry
+	workouts []gymdomain.WorkoutWithSets
+	err      error
+	scope    gymdomain.Scope
+	filter   gymdomain.ListFilter
+	entry    gymdomain.CreateGymEntryInput
+	workout  gymdomain.CreateWorkoutInput
+	deleted  string
+}
+
+func (m *mockGym) ListGymEntries(_ context.Context, scope gymdomain.Scope, filter gymdomain.ListFilter) ([]gymdomain.GymEntry, int64, error) {
+	m.scope, m.filter = scope, filter
+	return m.entries, int64(len(m.entries)), m.err
+}
+
+func (m *mockGym) CreateGymEntry(_ context.Context, input gymdomain.CreateGymEntryInput) (*gymdomain.GymEntry, error) {
+	m.entry = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &gymdomain.GymEntry{ID: "entry-1", UserID: input.UserID, Date: input.Date, Exercise: input.Exercise, WeightKg: input.WeightKg, Reps: input.Reps}, nil
+}
+
+func (m *mockGym) ListWorkouts(_ context.Context, scope gymdomain.Scope, filter gymdomain.ListFilter) ([]gymdomain.WorkoutWithSets, int64, error) {
+	m.scope, m.filter = scope, filter
+	return m.workouts, int64(len(m.workouts)), m.err
+}
+
+func (m *mockGym) GetWorkoutByID(context.Context, string, string) (*gymdomain.WorkoutWithSets, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	workout := testWorkout()
+	return &workout, nil
+}
+
+func (m *mockGym) CreateWorkout(_ context.Context, input gymdomain.CreateWorkoutInput) (*gymdomain.WorkoutWithSets, error) {
+	m.workout = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	workout := testWorkout()
+	workout.Visibility = input.Visibility
+	return &workout, nil
+}
+
+func (m *mockGym) UpdateWorkout(context.Context, gymdomain.UpdateWorkoutInput) (*gymdomain.WorkoutWithSets, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	workout := testWorkout()
+	return &workout, nil
+}
+
+func (m *mockGym) DeleteWorkout(_ context.Context, _ string, workoutID string) error {
+	m.deleted = workoutID
+	return m.err
+}
+
+type mockLabels struct {
+	LabelsService
+	labels  map[string][]string
+	cleared []string
+}
+
+func (m *mockLabels) ListByEntities(context.Context, labelsdomain.EntityType, []string) (map[string][]string, error) {
+	return m.labels, nil
+}
+
+func (m *mockLabels) Clear(_ context.Context, _ labelsdomain.EntityType, entityID string) error {
+	m.cleared = append(m.cleared, entityID)
+	return nil
+}
+
+func newTestHandlers(gym *mockGym, labels *mockLabels) *Handlers {
+	limits := commonhandler.ListLimits{
+		GymEntries: commonhandler.ListLimit{Default: 50, Max: 200},
+		Workouts:   commonhandler.ListLimit{Default: 20, Max: 100},
+	}
+	return New(gym, labels, nil, limits, logger.New(io.Discard, slog.LevelError, "text"))
+}
+
+func testWorkout() gymdomain.WorkoutWithSets {
+	created := time.Date(2026, 10, 2, 18, 0, 0, 0, time.UTC)
+	return gymdomain.WorkoutWithSets{
+		Workout: gymdomain.Workout{
+			ID:         testWorkoutID,
+			UserID:     testUserID,
+			Date:       time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC),
+			Name:       "Legs",
+			Visibility: gymdomain.VisibilityPrivate,
+			CreatedAt:  created,
+			UpdatedAt:  created,
+		},
+		Sets: []gymdomain.WorkoutSet{{ID: "set-1", WorkoutID: testWorkoutID, Exercise: "Squat", WeightKg: 100, Reps: 5}},
+	}
+}
+
+// userRequest is a request from testUserID, who has no family.
+func userRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(middleware.WithUser(req.Context(), middleware.User{ID: testUserID}))
+}
+
+func withFamily(req *http.Request) *http.Request {
+	return req.WithContext(middleware.WithFamily(req.Context(), &familydomain.Family{ID: testFamilyID}))
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var payload struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error response %q: %v", rec.Body.String(), err)
+	}
+	return payload.Error.Code
+}
+
+func TestListGymEntriesScopes(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		family    bool
+		err       error
+		status    int
+		code      string
+		wantScope gymdomain.Scope
+	}{
+		{name: "default", status: http.StatusOK, wantScope: gymdomain.Scope{UserID: testUserID, Kind: gymdomain.ScopeMe}},
+		{name: "me without family", query: "scope=ME", status: http.StatusOK, wantScope: gymdomain.Scope{UserID: testUserID, Kind: gymdomain.ScopeMe}},
+		{name: "family", query: "scope=family", family: true, status: http.StatusOK, wantScope: gymdomain.Scope{UserID: testUserID, FamilyID: testFamilyID, Kind: gymdomain.ScopeFamily}},
+		{name: "family without family", query: "scope=family", status: http.StatusNotFound, code: "family_not_found"},
+		{name: "family required", query: "scope=family", family: true, err: gymdomain.ErrFamilyRequired, status: http.StatusNotFound, code: "family_not_found"},
+		{name: "unknown scope", query: "scope=everyone", status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "bad from", query: "from=2026-02-30", status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "bad limit", query: "limit=many", status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "bad offset", query: "offset=-", status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "internal", err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gym := &mockGym{err: tc.err}
+			req := userRequest(http.MethodGet, "/api/gym/entries?"+tc.query, "")
+			if tc.family {
+				req = withFamily(req)
+			}
+			rec := httptest.NewRecorder()
+			newTestHandlers(gym, &mockLabels{}).ListGymEntries(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			if gym.scope != tc.wantScope || gym.filter.Limit != 50 {
+				t.Fatalf("unexpected scope %+v filter %+v", gym.scope, gym.filter)
+			}
+			var body gymEntryListResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if body.Items == nil {
+				t.Fatalf("expected items to be an empty array, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateGymEntry(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "malformed", body: `[]`, status: http.StatusBadRequest, code: "invalid_json"},
+		{name: "missing fields", body: `{"weight_kg":60}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "bad date", body: `{"date":"2026/10/02","exercise":"Bench"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "bad visibility", body: `{"date":"2026-10-02","exercise":"Bench","visibility":"public"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "internal", body: `{"date":"2026-10-02","exercise":"Bench"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "created", body: `{"date":"2026-10-02","exercise":"Bench","weight_kg":60.5,"reps":8}`, status: http.StatusCreated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gym := &mockGym{err: tc.err}
+			rec := httptest.NewRecorder()
+			newTestHandlers(gym, &mockLabels{}).CreateGymEntry(rec, userRequest(http.MethodPost, "/api/gym/entries", tc.body))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			var body gymEntryResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if body.Date != "2026-10-02" || body.WeightKg != 60.5 || body.Reps != 8 || body.UserID != testUserID {
+				t.Fatalf("unexpected response: %+v", body)
+			}
+		})
+	}
+}
+
+func TestListWorkoutsFillsLabels(t *testing.T) {
+	gym := &mockGym{workouts: []gymdomain.WorkoutWithSets{testWorkout()}}
+	labels := &mockLabels{labels: map[string][]string{testWorkoutID: {"legs"}}}
+	rec := httptest.NewRecorder()
+	newTestHandlers(gym, labels).ListWorkouts(rec, userRequest(http.MethodGet, "/api/gym/workouts?labels=legs,push&limit=0", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gym.filter.Limit != 100 || len(gym.filter.Labels) != 2 {
+		t.Fatalf("unexpected filter: %+v", gym.filter)
+	}
+	var body workoutListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Total != 1 || len(body.Items) != 1 {
+		t.Fatalf("unexpected response: %s", rec.Body.String())
+	}
+	workout := body.Items[0]
+	if workout.Date != "2026-10-02" || workout.Visibility != "private" || len(workout.Sets) != 1 || len(workout.Labels) != 1 {
+		t.Fatalf("unexpected workout: %+v", workout)
+	}
+}
+
+func TestCreateWorkout(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "bad visibility", body: `{"date":"2026-10-02","name":"Legs","visibility":"public"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "missing name", body: `{"date":"2026-10-02"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "template not found", body: `{"date":"2026-10-02","name":"Legs","template_id":"t"}`, err: gymdomain.ErrTemplateNotFound, status: http.StatusNotFound, code: "template_not_found"},
+		{name: "internal", body: `{"date":"2026-10-02","name":"Legs"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "created", body: `{"date":"2026-10-02","name":"Legs","visibility":" Family ","sets":[{"exercise":"Squat","weight_kg":100,"reps":5}]}`, status: http.StatusCreated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gym := &mockGym{err: tc.err}
+			rec := httptest.NewRecorder()
+			newTestHandlers(gym, &mockLabels{}).CreateWorkout(rec, userRequest(http.MethodPost, "/api/gym/workouts", tc.body))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			if gym.workout.Visibility != gymdomain.VisibilityFamily || len(gym.workout.Sets) != 1 {
+				t.Fatalf("unexpected input: %+v", gym.workout)
+			}
+			var body workoutResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if body.Visibility != "family" || body.Labels == nil {
+				t.Fatalf("unexpected response: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWorkoutNotFound(t *testing.T) {
+	h := newTestHandlers(&mockGym{err: gymdomain.ErrWorkoutNotFound}, &mockLabels{})
+	serve := map[string]func(w http.ResponseWriter, r *http.Request){
+		"get":    h.GetWorkout,
+		"update": h.UpdateWorkout,
+		"delete": h.DeleteWorkout,
+	}
+	for name, handler := range serve {
+		t.Run(name, func(t *testing.T) {
+			req := withURLParam(userRequest(http.MethodPut, "/api/gym/workouts/"+testWorkoutID, `{"date":"2026-10-02","name":"Legs"}`), "id", testWorkoutID)
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			if rec.Code != http.StatusNotFound || errorCode(t, rec) != "workout_not_found" {
+				t.Fatalf("expected 404 workout_not_found, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteWorkoutClearsLabels(t *testing.T) {
+	gym := &mockGym{}
+	labels := &mockLabels{}
+	req := withURLParam(userRequest(http.MethodDelete, "/api/gym/workouts/"+testWorkoutID, ""), "id", testWorkoutID)
+	rec := httptest.NewRecorder()
+	newTestHandlers(gym, labels).DeleteWorkout(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gym.deleted != testWorkoutID || len(labels.cleared) != 1 || labels.cleared[0] != testWorkoutID {
+		t.Fatalf("expected the workout and its labels deleted, got %q %v", gym.deleted, labels.cleared)
+	}
+}
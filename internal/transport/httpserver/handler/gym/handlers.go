@@ -1,18 +1,24 @@
 package gym
 
 import (
-	gymdomain "family-app-go/internal/domain/gym"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/pkg/logger"
 )
 
 type Handlers struct {
-	Gym *gymdomain.Service
-	log logger.Logger
+	Gym       GymService
+	Labels    LabelsService
+	Favorites FavoritesService
+	limits    commonhandler.ListLimits
+	log       logger.Logger
 }
 
-func New(gym *gymdomain.Service, log logger.Logger) *Handlers {
+func New(gym GymService, labels LabelsService, favorites FavoritesService, limits commonhandler.ListLimits, log logger.Logger) *Handlers {
 	return &Handlers{
-		Gym: gym,
-		log: log,
+		Gym:       gym,
+		Labels:    labels,
+		Favorites: favorites,
+		limits:    limits,
+		log:       log,
 	}
 }
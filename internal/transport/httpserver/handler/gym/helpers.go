@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	familydomain "family-app-go/internal/domain/family"
 	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 )
 
@@ -11,12 +12,16 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 	commonhandler.WriteError(w, status, code, message)
 }
 
+func requireFamily(w http.ResponseWriter, r *http.Request) (*familydomain.Family, bool) {
+	return commonhandler.RequireFamily(w, r)
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 	commonhandler.WriteJSON(w, status, payload)
 }
 
-func decodeJSON(r *http.Request, dst interface{}) error {
-	return commonhandler.DecodeJSON(r, dst)
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return commonhandler.DecodeRequest(w, r, dst)
 }
 
 func parseDateRequired(value string) (time.Time, error) {
@@ -27,6 +32,14 @@ func parseDateParam(value string) (*time.Time, error) {
 	return commonhandler.ParseDateParam(value)
 }
 
+func parseCSV(value string) []string {
+	return commonhandler.ParseCSV(value)
+}
+
 func parseIntParam(value string, fallback int) (int, error) {
 	return commonhandler.ParseIntParam(value, fallback)
 }
+
+func writeValidationError(w http.ResponseWriter, err error) {
+	commonhandler.WriteValidationError(w, err)
+}
@@ -0,0 +1,117 @@
+package gym
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gymdomain "family-app-go/internal/domain/gym"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+const maxImportFileSizeBytes = 10 * 1024 * 1024
+
+type importRowErrorResponse struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type importResponse struct {
+	DryRun            bool                     `json:"dry_run"`
+	Format            string                   `json:"format"`
+	Rows              int                      `json:"rows"`
+	SkippedRows       int                      `json:"skipped_rows"`
+	Workouts          int                      `json:"workouts"`
+	WorkoutSets       int                      `json:"workout_sets"`
+	Entries           int                      `json:"entries"`
+	DuplicateWorkouts int                      `json:"duplicate_workouts"`
+	Errors            []importRowErrorResponse `json:"errors"`
+}
+
+func (h *Handlers) Import(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSizeBytes+1024*1024)
+	if err := r.ParseMultipartForm(maxImportFileSizeBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "import_file_too_large", "import file is too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_request", "multipart form with file is required")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "file is required")
+		return
+	}
+	defer file.Close()
+
+	dryRun := false
+	if value := strings.TrimSpace(r.FormValue("dry_run")); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid dry_run")
+			return
+		}
+	}
+
+	result, err := h.Gym.Import(r.Context(), gymdomain.ImportInput{
+		UserID:     user.ID,
+		Format:     gymdomain.ImportFormat(strings.ToLower(strings.TrimSpace(r.FormValue("format")))),
+		WeightUnit: gymdomain.WeightUnit(strings.ToLower(strings.TrimSpace(r.FormValue("weight_unit")))),
+		DryRun:     dryRun,
+		Data:       file,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gymdomain.ErrUnsupportedImportFormat):
+			writeError(w, http.StatusBadRequest, "invalid_request", "format must be auto, strong, hevy or generic")
+		case errors.Is(err, gymdomain.ErrInvalidWeightUnit):
+			writeError(w, http.StatusBadRequest, "invalid_request", "weight_unit must be kg or lbs")
+		case errors.Is(err, gymdomain.ErrInvalidImportFile):
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.import: invalid import file", err, "user_id", user.ID)
+			writeError(w, http.StatusUnprocessableEntity, "invalid_import_file", "file is not a supported csv export")
+		case errors.Is(err, gymdomain.ErrImportTooLarge):
+			writeError(w, http.StatusRequestEntityTooLarge, "import_file_too_large", fmt.Sprintf("import is limited to %d rows", gymdomain.MaxImportRows))
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("gym.import: import failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	rowErrors := make([]importRowErrorResponse, 0, len(result.Errors))
+	for _, rowErr := range result.Errors {
+		rowErrors = append(rowErrors, importRowErrorResponse{Row: rowErr.Row, Message: rowErr.Message})
+	}
+
+	status := http.StatusCreated
+	if result.DryRun {
+		status = http.StatusOK
+	} else {
+		commonhandler.RequestLog(r, h.log).Info("gym.import: history imported", "user_id", user.ID, "format", result.Format, "workouts", result.Workouts, "entries", result.Entries)
+	}
+
+	writeJSON(w, status, importResponse{
+		DryRun:            result.DryRun,
+		Format:            string(result.Format),
+		Rows:              result.Rows,
+		SkippedRows:       result.SkippedRows,
+		Workouts:          result.Workouts,
+		WorkoutSets:       result.WorkoutSets,
+		Entries:           result.Entries,
+		DuplicateWorkouts: result.DuplicateWorkouts,
+		Errors:            rowErrors,
+	})
+}
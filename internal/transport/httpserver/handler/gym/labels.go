@@ -0,0 +1,125 @@
+package gym
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type setLabelsRequest struct {
+	Labels []string `json:"labels"`
+}
+
+type labelsResponse struct {
+	Labels []string `json:"labels"`
+}
+
+type labelUsageResponse struct {
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+type labelUsageListResponse struct {
+	Items []labelUsageResponse `json:"items"`
+}
+
+// SetWorkoutLabels replaces the labels of one of the caller's workouts.
+func (h *Handlers) SetWorkoutLabels(w http.ResponseWriter, r *http.Request) {
+	workoutID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if workoutID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	var req setLabelsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	workout, err := h.Gym.GetWorkoutByID(r.Context(), user.ID, workoutID)
+	if err != nil {
+		if errors.Is(err, gymdomain.ErrWorkoutNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("gym.set_workout_labels: workout not found", err, "user_id", user.ID, "workout_id", workoutID)
+			writeError(w, http.StatusNotFound, "workout_not_found", "workout not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("gym.set_workout_labels: get workout failed", err, "user_id", user.ID, "workout_id", workoutID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	labels, err := h.Labels.Set(r.Context(), labelsdomain.SetInput{
+		EntityType: labelsdomain.EntityWorkout,
+		EntityID:   workout.ID,
+		OwnerID:    user.ID,
+		Labels:     req.Labels,
+	})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.set_workout_labels: set labels failed", err, "user_id", user.ID, "workout_id", workoutID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, labelsResponse{Labels: labels})
+}
+
+// ListWorkoutLabels returns the labels the caller uses on their workouts.
+func (h *Handlers) ListWorkoutLabels(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	usage, err := h.Labels.ListUsage(r.Context(), labelsdomain.EntityWorkout, user.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_workout_labels: list labels failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := labelUsageListResponse{Items: make([]labelUsageResponse, 0, len(usage))}
+	for _, item := range usage {
+		response.Items = append(response.Items, labelUsageResponse{Label: item.Label, Count: item.Count})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// fillLabels sets the labels of workout responses with one lookup.
+func (h *Handlers) fillLabels(r *http.Request, workouts []workoutResponse) error {
+	ids := make([]string, 0, len(workouts))
+	for _, workout := range workouts {
+		ids = append(ids, workout.ID)
+	}
+	labels, err := h.Labels.ListByEntities(r.Context(), labelsdomain.EntityWorkout, ids)
+	if err != nil {
+		return err
+	}
+	for i := range workouts {
+		if workoutLabels, ok := labels[workouts[i].ID]; ok {
+			workouts[i].Labels = workoutLabels
+		}
+	}
+	return nil
+}
+
+// parseLabelsParam reads a comma-separated labels filter.
+func parseLabelsParam(value string) ([]string, error) {
+	values := parseCSV(value)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return labelsdomain.Normalize(values)
+}
@@ -0,0 +1,125 @@
+package gym
+
+import (
+	"net/http"
+	"time"
+
+	gymdomain "family-app-go/internal/domain/gym"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+type personalRecordResponse struct {
+	Exercise           string                      `json:"exercise"`
+	MaxWeightKg        float64                     `json:"max_weight_kg"`
+	MaxWeightReps      int                         `json:"max_weight_reps"`
+	MaxWeightDate      string                      `json:"max_weight_date"`
+	MaxReps            int                         `json:"max_reps"`
+	MaxRepsDate        string                      `json:"max_reps_date"`
+	EstimatedOneRepMax *estimatedOneRepMaxResponse `json:"estimated_1rm"`
+}
+
+type estimatedOneRepMaxResponse struct {
+	EpleyKg   float64  `json:"epley_kg"`
+	BrzyckiKg *float64 `json:"brzycki_kg"`
+	Date      string   `json:"date"`
+}
+
+type personalRecordListResponse struct {
+	Items []personalRecordResponse `json:"items"`
+}
+
+type personalRecordEventResponse struct {
+	ID            string    `json:"id"`
+	Exercise      string    `json:"exercise"`
+	Kind          string    `json:"kind"`
+	Value         float64   `json:"value"`
+	PreviousValue float64   `json:"previous_value"`
+	AchievedOn    string    `json:"achieved_on"`
+	SourceType    string    `json:"source_type"`
+	SourceID      string    `json:"source_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type personalRecordEventListResponse struct {
+	Items []personalRecordEventResponse `json:"items"`
+}
+
+func (h *Handlers) ListRecords(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	records, err := h.Gym.ListRecords(r.Context(), user.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_records: list records failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	items := make([]personalRecordResponse, 0, len(records))
+	for _, record := range records {
+		items = append(items, toPersonalRecordResponse(record))
+	}
+
+	writeJSON(w, http.StatusOK, personalRecordListResponse{Items: items})
+}
+
+func (h *Handlers) ListRecordEvents(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	limit, err := parseIntParam(r.URL.Query().Get("limit"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
+		return
+	}
+
+	events, err := h.Gym.ListRecordEvents(r.Context(), user.ID, limit)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("gym.list_record_events: list record events failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	items := make([]personalRecordEventResponse, 0, len(events))
+	for _, event := range events {
+		items = append(items, personalRecordEventResponse{
+			ID:            event.ID,
+			Exercise:      event.Exercise,
+			Kind:          event.Kind,
+			Value:         event.Value,
+			PreviousValue: event.PreviousValue,
+			AchievedOn:    event.AchievedOn.Format("2006-01-02"),
+			SourceType:    event.SourceType,
+			SourceID:      event.SourceID,
+			CreatedAt:     event.CreatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, personalRecordEventListResponse{Items: items})
+}
+
+func toPersonalRecordResponse(record gymdomain.PersonalRecord) personalRecordResponse {
+	response := personalRecordResponse{
+		Exercise:      record.Exercise,
+		MaxWeightKg:   record.MaxWeightKg,
+		MaxWeightReps: record.MaxWeightReps,
+		MaxWeightDate: record.MaxWeightDate.Format("2006-01-02"),
+		MaxReps:       record.MaxReps,
+		MaxRepsDate:   record.MaxRepsDate.Format("2006-01-02"),
+	}
+	if record.EpleyOneRepMax != nil && record.OneRepMaxDate != nil {
+		response.EstimatedOneRepMax = &estimatedOneRepMaxResponse{
+			EpleyKg:   *record.EpleyOneRepMax,
+			BrzyckiKg: record.BrzyckiOneRepMax,
+			Date:      record.OneRepMaxDate.Format("2006-01-02"),
+		}
+	}
+	return response
+}
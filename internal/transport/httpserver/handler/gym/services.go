@@ -0,0 +1,56 @@
+package gym
+
+import (
+	"context"
+
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+)
+
+// FavoritesService is implemented by *favoritesdomain.Service.
+type FavoritesService interface {
+	Add(ctx context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error
+	IDs(ctx context.Context, userID string, entityType favoritesdomain.EntityType) (map[string]bool, error)
+	Remove(ctx context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error
+}
+
+// GymService is implemented by *gymdomain.Service.
+type GymService interface {
+	CancelSession(ctx context.Context, userID, sessionID string) error
+	CreateGymEntry(ctx context.Context, input gymdomain.CreateGymEntryInput) (*gymdomain.GymEntry, error)
+	CreateTemplate(ctx context.Context, input gymdomain.CreateTemplateInput) (*gymdomain.TemplateWithSets, error)
+	CreateWorkout(ctx context.Context, input gymdomain.CreateWorkoutInput) (*gymdomain.WorkoutWithSets, error)
+	DeleteGoal(ctx context.Context, userID string) error
+	DeleteGymEntry(ctx context.Context, userID, entryID string) error
+	DeleteTemplate(ctx context.Context, userID, templateID string) error
+	DeleteWorkout(ctx context.Context, userID, workoutID string) error
+	FinishSession(ctx context.Context, userID, sessionID string) (*gymdomain.SessionWithSets, *gymdomain.WorkoutWithSets, error)
+	GetActiveSession(ctx context.Context, userID string) (*gymdomain.SessionWithSets, error)
+	GetGoal(ctx context.Context, userID string) (*gymdomain.Goal, error)
+	GetSession(ctx context.Context, userID, sessionID string) (*gymdomain.SessionWithSets, error)
+	GetStreak(ctx context.Context, userID string) (*gymdomain.Streak, error)
+	GetWorkoutByID(ctx context.Context, userID, workoutID string) (*gymdomain.WorkoutWithSets, error)
+	Import(ctx context.Context, input gymdomain.ImportInput) (*gymdomain.ImportResult, error)
+	ListExercises(ctx context.Context, scope gymdomain.Scope) ([]string, error)
+	ListFamilyFeed(ctx context.Context, scope gymdomain.Scope, filter gymdomain.ListFilter) ([]gymdomain.WorkoutWithSets, int64, error)
+	ListGymEntries(ctx context.Context, scope gymdomain.Scope, filter gymdomain.ListFilter) ([]gymdomain.GymEntry, int64, error)
+	ListRecordEvents(ctx context.Context, userID string, limit int) ([]gymdomain.PersonalRecordEvent, error)
+	ListRecords(ctx context.Context, userID string) ([]gymdomain.PersonalRecord, error)
+	ListTemplates(ctx context.Context, scope gymdomain.Scope) ([]gymdomain.TemplateWithSets, error)
+	ListWorkouts(ctx context.Context, scope gymdomain.Scope, filter gymdomain.ListFilter) ([]gymdomain.WorkoutWithSets, int64, error)
+	SetGoal(ctx context.Context, userID string, workoutsPerWeek int) (*gymdomain.Goal, error)
+	StartSession(ctx context.Context, input gymdomain.StartSessionInput) (*gymdomain.SessionWithSets, error)
+	UpdateGymEntry(ctx context.Context, input gymdomain.UpdateGymEntryInput) (*gymdomain.GymEntry, error)
+	UpdateSession(ctx context.Context, input gymdomain.UpdateSessionInput) (*gymdomain.SessionWithSets, error)
+	UpdateTemplate(ctx context.Context, input gymdomain.UpdateTemplateInput) (*gymdomain.TemplateWithSets, error)
+	UpdateWorkout(ctx context.Context, input gymdomain.UpdateWorkoutInput) (*gymdomain.WorkoutWithSets, error)
+}
+
+// LabelsService is implemented by *labelsdomain.Service.
+type LabelsService interface {
+	Clear(ctx context.Context, entityType labelsdomain.EntityType, entityID string) error
+	ListByEntities(ctx context.Context, entityType labelsdomain.EntityType, entityIDs []string) (map[string][]string, error)
+	ListUsage(ctx context.Context, entityType labelsdomain.EntityType, ownerID string) ([]labelsdomain.Usage, error)
+	Set(ctx context.Context, input labelsdomain.SetInput) ([]string, error)
+}
@@ -0,0 +1,271 @@
+package gym
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gymdomain "family-app-go/internal/domain/gym"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type startSessionRequest struct {
+	Name        string `json:"name"`
+	Date        string `json:"date"`
+	RestSeconds int    `json:"rest_seconds"`
+}
+
+type updateSessionRequest struct {
+	Name        *string                   `json:"name"`
+	RestSeconds *int                      `json:"rest_seconds"`
+	Sets        []createWorkoutSetRequest `json:"sets"`
+}
+
+type sessionSetResponse struct {
+	ID          string    `json:"id"`
+	Exercise    string    `json:"exercise"`
+	WeightKg    float64   `json:"weight_kg"`
+	Reps        int       `json:"reps"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+type sessionRestResponse struct {
+	StartedAt        time.Time `json:"started_at"`
+	EndsAt           time.Time `json:"ends_at"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+}
+
+type sessionResponse struct {
+	ID             string               `json:"id"`
+	UserID         string               `json:"user_id"`
+	Name           string               `json:"name"`
+	Date           string               `json:"date"`
+	Status         string               `json:"status"`
+	RestSeconds    int                  `json:"rest_seconds"`
+	Rest           *sessionRestResponse `json:"rest"`
+	ElapsedSeconds int64                `json:"elapsed_seconds"`
+	StartedAt      time.Time            `json:"started_at"`
+	FinishedAt     *time.Time           `json:"finished_at"`
+	WorkoutID      *string              `json:"workout_id"`
+	Sets           []sessionSetResponse `json:"sets"`
+}
+
+type finishSessionResponse struct {
+	Session sessionResponse `json:"session"`
+	Workout workoutResponse `json:"workout"`
+}
+
+func (h *Handlers) StartSession(w http.ResponseWriter, r *http.Request) {
+	var req startSessionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	date, _ := parseDateParam(req.Date)
+
+	session, err := h.Gym.StartSession(r.Context(), gymdomain.StartSessionInput{
+		UserID:      user.ID,
+		Name:        req.Name,
+		Date:        date,
+		RestSeconds: req.RestSeconds,
+	})
+	if err != nil {
+		h.writeSessionError(w, r, err, "gym.start_session", user.ID, "")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toSessionResponse(*session, time.Now()))
+}
+
+func (h *Handlers) GetActiveSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	session, err := h.Gym.GetActiveSession(r.Context(), user.ID)
+	if err != nil {
+		h.writeSessionError(w, r, err, "gym.get_active_session", user.ID, "")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSessionResponse(*session, time.Now()))
+}
+
+func (h *Handlers) GetSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if sessionID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	session, err := h.Gym.GetSession(r.Context(), user.ID, sessionID)
+	if err != nil {
+		h.writeSessionError(w, r, err, "gym.get_session", user.ID, sessionID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSessionResponse(*session, time.Now()))
+}
+
+func (h *Handlers) UpdateSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if sessionID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	var req updateSessionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	sets := make([]gymdomain.CreateWorkoutSetInput, 0, len(req.Sets))
+	for _, setReq := range req.Sets {
+		sets = append(sets, gymdomain.CreateWorkoutSetInput{
+			Exercise: setReq.Exercise,
+			WeightKg: setReq.WeightKg,
+			Reps:     setReq.Reps,
+		})
+	}
+
+	session, err := h.Gym.UpdateSession(r.Context(), gymdomain.UpdateSessionInput{
+		ID:          sessionID,
+		UserID:      user.ID,
+		Name:        req.Name,
+		RestSeconds: req.RestSeconds,
+		Sets:        sets,
+	})
+	if err != nil {
+		h.writeSessionError(w, r, err, "gym.update_session", user.ID, sessionID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSessionResponse(*session, time.Now()))
+}
+
+func (h *Handlers) FinishSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if sessionID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	session, workout, err := h.Gym.FinishSession(r.Context(), user.ID, sessionID)
+	if err != nil {
+		h.writeSessionError(w, r, err, "gym.finish_session", user.ID, sessionID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, finishSessionResponse{
+		Session: toSessionResponse(*session, time.Now()),
+		Workout: toWorkoutResponse(*workout),
+	})
+}
+
+func (h *Handlers) CancelSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if sessionID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	if err := h.Gym.CancelSession(r.Context(), user.ID, sessionID); err != nil {
+		h.writeSessionError(w, r, err, "gym.cancel_session", user.ID, sessionID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) writeSessionError(w http.ResponseWriter, r *http.Request, err error, operation, userID, sessionID string) {
+	switch {
+	case errors.Is(err, gymdomain.ErrSessionNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": session not found", err, "user_id", userID, "session_id", sessionID)
+		writeError(w, http.StatusNotFound, "session_not_found", "workout session not found")
+	case errors.Is(err, gymdomain.ErrSessionAlreadyActive):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": session already active", err, "user_id", userID)
+		writeError(w, http.StatusConflict, "session_already_active", "another workout session is already active")
+	case errors.Is(err, gymdomain.ErrSessionNotActive):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": session not active", err, "user_id", userID, "session_id", sessionID)
+		writeError(w, http.StatusConflict, "session_not_active", "workout session is not active")
+	case errors.Is(err, gymdomain.ErrSessionEmpty):
+		writeValidationError(w, validation.FieldErr("sets", validation.CodeRequired, "workout session has no sets"))
+	case errors.Is(err, gymdomain.ErrInvalidRestSeconds):
+		writeValidationError(w, validation.FieldErr("rest_seconds", validation.CodeRange, fmt.Sprintf("rest_seconds must be between 0 and %d", gymdomain.MaxRestSeconds)))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": failed", err, "user_id", userID, "session_id", sessionID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+func toSessionResponse(session gymdomain.SessionWithSets, now time.Time) sessionResponse {
+	sets := make([]sessionSetResponse, 0, len(session.Sets))
+	for _, set := range session.Sets {
+		sets = append(sets, sessionSetResponse{
+			ID:          set.ID,
+			Exercise:    set.Exercise,
+			WeightKg:    set.WeightKg,
+			Reps:        set.Reps,
+			CompletedAt: set.CompletedAt,
+		})
+	}
+
+	response := sessionResponse{
+		ID:             session.ID,
+		UserID:         session.UserID,
+		Name:           session.Name,
+		Date:           session.Date.Format("2006-01-02"),
+		Status:         string(session.Status),
+		RestSeconds:    session.RestSeconds,
+		ElapsedSeconds: session.ElapsedSeconds(now),
+		StartedAt:      session.StartedAt,
+		FinishedAt:     session.FinishedAt,
+		WorkoutID:      session.WorkoutID,
+		Sets:           sets,
+	}
+	if session.RestStartedAt != nil && session.RestEndsAt != nil && session.Status == gymdomain.SessionStatusActive {
+		response.Rest = &sessionRestResponse{
+			StartedAt:        *session.RestStartedAt,
+			EndsAt:           *session.RestEndsAt,
+			RemainingSeconds: session.RestRemainingSeconds(now),
+		}
+	}
+	return response
+}
@@ -0,0 +1,90 @@
+package gym
+
+import (
+	"fmt"
+
+	gymdomain "family-app-go/internal/domain/gym"
+	labelsdomain "family-app-go/internal/domain/labels"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req createGymEntryRequest) Validate(v *validation.Validator) {
+	validateEntry(v, req.Date, req.Exercise, req.Visibility)
+}
+
+func (req updateGymEntryRequest) Validate(v *validation.Validator) {
+	validateEntry(v, req.Date, req.Exercise, req.Visibility)
+}
+
+func validateEntry(v *validation.Validator, date, exercise, visibility string) {
+	v.Required("date", date)
+	v.Date("date", &date)
+	v.Required("exercise", exercise)
+	validateVisibility(v, visibility)
+}
+
+func (req createWorkoutRequest) Validate(v *validation.Validator) {
+	validateWorkout(v, req.Date, req.Name, req.Visibility)
+}
+
+func (req updateWorkoutRequest) Validate(v *validation.Validator) {
+	validateWorkout(v, req.Date, req.Name, req.Visibility)
+}
+
+func validateWorkout(v *validation.Validator, date, name, visibility string) {
+	v.Required("date", date)
+	v.Date("date", &date)
+	v.Required("name", name)
+	validateVisibility(v, visibility)
+}
+
+func (req createTemplateRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	validateVisibility(v, req.Visibility)
+}
+
+func (req updateTemplateRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	validateVisibility(v, req.Visibility)
+}
+
+func validateVisibility(v *validation.Validator, visibility string) {
+	_, ok := parseVisibility(visibility)
+	v.Check(ok, "visibility", validation.CodeEnum, "visibility must be private or family")
+}
+
+func (req setGoalRequest) Validate(v *validation.Validator) {
+	v.Between("workouts_per_week", &req.WorkoutsPerWeek, 1, gymdomain.MaxWorkoutsPerWeek)
+}
+
+func (req startSessionRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.Date("date", &req.Date)
+	v.Between("rest_seconds", &req.RestSeconds, 0, gymdomain.MaxRestSeconds)
+}
+
+func (req updateSessionRequest) Validate(v *validation.Validator) {
+	v.NotBlank("name", req.Name)
+	v.Between("rest_seconds", req.RestSeconds, 0, gymdomain.MaxRestSeconds)
+	for i, set := range req.Sets {
+		v.Nested(fmt.Sprintf("sets[%d]", i), set)
+	}
+}
+
+func (req createWorkoutSetRequest) Validate(v *validation.Validator) {
+	v.Required("exercise", req.Exercise)
+}
+
+func (req setLabelsRequest) Validate(v *validation.Validator) {
+	if req.Labels == nil {
+		v.Add("labels", validation.CodeRequired, "labels is required")
+		return
+	}
+	if len(req.Labels) > labelsdomain.MaxLabelsPerEntity {
+		v.Add("labels", validation.CodeTooLong, fmt.Sprintf("at most %d labels are allowed", labelsdomain.MaxLabelsPerEntity))
+		return
+	}
+	if _, err := labelsdomain.Normalize(req.Labels); err != nil {
+		v.Add("labels", validation.CodeInvalid, fmt.Sprintf("labels must be 1 to %d characters", labelsdomain.MaxLabelLength))
+	}
+}
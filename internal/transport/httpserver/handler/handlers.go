@@ -1,36 +1,95 @@
 package handler
 
 import (
-	analyticsdomain "family-app-go/internal/domain/analytics"
-	expensesdomain "family-app-go/internal/domain/expenses"
-	familydomain "family-app-go/internal/domain/family"
-	gymdomain "family-app-go/internal/domain/gym"
-	ratesdomain "family-app-go/internal/domain/rates"
-	receiptsdomain "family-app-go/internal/domain/receipts"
-	syncdomain "family-app-go/internal/domain/sync"
-	todosdomain "family-app-go/internal/domain/todos"
+	adminhandler "family-app-go/internal/transport/httpserver/handler/admin"
+	apikeyshandler "family-app-go/internal/transport/httpserver/handler/apikeys"
+	authhandler "family-app-go/internal/transport/httpserver/handler/auth"
+	batchhandler "family-app-go/internal/transport/httpserver/handler/batch"
+	budgetshandler "family-app-go/internal/transport/httpserver/handler/budgets"
+	calendarhandler "family-app-go/internal/transport/httpserver/handler/calendar"
 	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	dashboardhandler "family-app-go/internal/transport/httpserver/handler/dashboard"
+	erasurehandler "family-app-go/internal/transport/httpserver/handler/erasure"
 	expenseshandler "family-app-go/internal/transport/httpserver/handler/expenses"
+	exportshandler "family-app-go/internal/transport/httpserver/handler/exports"
+	featureflagshandler "family-app-go/internal/transport/httpserver/handler/featureflags"
 	gymhandler "family-app-go/internal/transport/httpserver/handler/gym"
+	mentionshandler "family-app-go/internal/transport/httpserver/handler/mentions"
+	milestoneshandler "family-app-go/internal/transport/httpserver/handler/milestones"
+	notificationshandler "family-app-go/internal/transport/httpserver/handler/notifications"
+	petshandler "family-app-go/internal/transport/httpserver/handler/pets"
+	pollshandler "family-app-go/internal/transport/httpserver/handler/polls"
 	receiptshandler "family-app-go/internal/transport/httpserver/handler/receipts"
+	retentionhandler "family-app-go/internal/transport/httpserver/handler/retention"
+	searchhandler "family-app-go/internal/transport/httpserver/handler/search"
+	sessionshandler "family-app-go/internal/transport/httpserver/handler/sessions"
 	todoshandler "family-app-go/internal/transport/httpserver/handler/todos"
+	viewshandler "family-app-go/internal/transport/httpserver/handler/views"
+	wishlisthandler "family-app-go/internal/transport/httpserver/handler/wishlist"
+	yearreviewhandler "family-app-go/internal/transport/httpserver/handler/yearreview"
 	"family-app-go/pkg/logger"
 )
 
 type Handlers struct {
-	Common   *commonhandler.Handlers
-	Expenses *expenseshandler.Handlers
-	Todos    *todoshandler.Handlers
-	Gym      *gymhandler.Handlers
-	Receipts *receiptshandler.Handlers
+	Auth      *authhandler.Handlers
+	APIKeys   *apikeyshandler.Handlers
+	Sessions  *sessionshandler.Handlers
+	Common    *commonhandler.Handlers
+	Expenses  *expenseshandler.Handlers
+	Todos     *todoshandler.Handlers
+	Gym       *gymhandler.Handlers
+	Receipts  *receiptshandler.Handlers
+	Retention *retentionhandler.Handlers
+	Calendar  *calendarhandler.Handlers
+	Wishlist  *wishlisthandler.Handlers
+	Pets      *petshandler.Handlers
+	Admin     *adminhandler.Handlers
+	Exports   *exportshandler.Handlers
+	Erasure   *erasurehandler.Handlers
+	Views     *viewshandler.Handlers
+	Search    *searchhandler.Handlers
+	Flags     *featureflagshandler.Handlers
+	Dashboard *dashboardhandler.Handlers
+
+	YearReview *yearreviewhandler.Handlers
+	Mentions   *mentionshandler.Handlers
+	Polls      *pollshandler.Handlers
+	Milestones *milestoneshandler.Handlers
+	Batch      *batchhandler.Handlers
+
+	Budgets       *budgetshandler.Handlers
+	Notifications *notificationshandler.Handlers
 }
 
-func New(analytics *analyticsdomain.Service, families *familydomain.Service, expenses *expensesdomain.Service, rates *ratesdomain.Service, todos *todosdomain.Service, sync *syncdomain.Service, gym *gymdomain.Service, receipts *receiptsdomain.Service, log logger.Logger, seeders ...commonhandler.FamilySeeder) *Handlers {
+func New(activity ActivityService, analytics AnalyticsService, auth AuthService, apiKeys APIKeysService, sessions SessionsService, families FamilyService, users UserService, expenses ExpensesService, rates RatesService, todos TodosService, sync SyncService, gym GymService, labels LabelsService, receipts ReceiptsService, retention RetentionService, calendar CalendarService, wishlist WishlistService, pets PetsService, health HealthService, admin AdminService, exports ExportsService, erasure ErasureService, views ViewsService, search SearchService, dashboard DashboardService, yearReview YearReviewService, comments CommentsService, polls PollsService, milestones MilestonesService, budgets BudgetsService, notifications NotificationsService, batch BatchService, favorites FavoritesService, flags FeatureFlagsService, audit AuditService, usage UsageService, limits commonhandler.ListLimits, log logger.Logger, seeders ...commonhandler.FamilySeeder) *Handlers {
 	return &Handlers{
-		Common:   commonhandler.New(families, sync, log, seeders...),
-		Expenses: expenseshandler.New(analytics, families, expenses, rates, log),
-		Todos:    todoshandler.New(families, todos, log),
-		Gym:      gymhandler.New(gym, log),
-		Receipts: receiptshandler.New(families, receipts, log),
+		Auth:      authhandler.New(auth, audit, log),
+		APIKeys:   apikeyshandler.New(apiKeys, audit, log),
+		Sessions:  sessionshandler.New(sessions, audit, log),
+		Common:    commonhandler.New(families, users, sync, activity, health, flags, audit, log, seeders...),
+		Expenses:  expenseshandler.New(analytics, expenses, rates, activity, favorites, flags, todos, comments, limits, log),
+		Todos:     todoshandler.New(families, todos, activity, labels, favorites, comments, limits, log),
+		Gym:       gymhandler.New(gym, labels, favorites, limits, log),
+		Receipts:  receiptshandler.New(receipts, log),
+		Retention: retentionhandler.New(retention, log),
+		Calendar:  calendarhandler.New(calendar, log),
+		Wishlist:  wishlisthandler.New(wishlist, log),
+		Pets:      petshandler.New(pets, log),
+		Admin:     adminhandler.New(admin, audit, usage, log),
+		Exports:   exportshandler.New(exports, audit, log),
+		Erasure:   erasurehandler.New(erasure, log),
+		Views:     viewshandler.New(views, log),
+		Search:    searchhandler.New(search, log),
+		Flags:     featureflagshandler.New(flags, log),
+		Dashboard: dashboardhandler.New(dashboard, log),
+
+		YearReview: yearreviewhandler.New(yearReview, log),
+		Mentions:   mentionshandler.New(comments, log),
+		Polls:      pollshandler.New(polls, log),
+		Milestones: milestoneshandler.New(milestones, log),
+		Batch:      batchhandler.New(batch, log),
+
+		Budgets:       budgetshandler.New(budgets, log),
+		Notifications: notificationshandler.New(notifications, log),
 	}
 }
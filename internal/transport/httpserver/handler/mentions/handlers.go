@@ -0,0 +1,17 @@
+package mentions
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Comments CommentsService
+	log      logger.Logger
+}
+
+func New(comments CommentsService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Comments: comments,
+		log:      log,
+	}
+}
@@ -0,0 +1,159 @@
+package mentions
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	commentsdomain "family-app-go/internal/domain/comments"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+type markReadRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func (req markReadRequest) Validate(v *validation.Validator) {
+	v.Check(len(req.IDs) <= commentsdomain.MaxMentionLimit, "ids", validation.CodeInvalid, fmt.Sprintf("at most %d ids", commentsdomain.MaxMentionLimit))
+	for i, id := range req.IDs {
+		v.UUID(fmt.Sprintf("ids[%d]", i), id)
+	}
+}
+
+type commentAuthorResponse struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	AvatarURL *string `json:"avatar_url"`
+}
+
+type mentionCommentResponse struct {
+	ID        string                `json:"id"`
+	Author    commentAuthorResponse `json:"author"`
+	Body      string                `json:"body"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+type mentionResponse struct {
+	ID         string                 `json:"id"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Comment    mentionCommentResponse `json:"comment"`
+	CreatedAt  time.Time              `json:"created_at"`
+	ReadAt     *time.Time             `json:"read_at"`
+}
+
+type mentionListResponse struct {
+	Items  []mentionResponse `json:"items"`
+	Total  int64             `json:"total"`
+	Unread int64             `json:"unread"`
+}
+
+type markReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// ListMentions returns the caller's mention inbox, newest first.
+func (h *Handlers) ListMentions(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	var filter commentsdomain.MentionFilter
+	if value := strings.TrimSpace(query.Get("unread")); value != "" {
+		unread, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid unread")
+			return
+		}
+		filter.UnreadOnly = unread
+	}
+	limit, err := parseIntParam(query.Get("limit"), commentsdomain.DefaultMentionLimit)
+	if err != nil || limit <= 0 || limit > commentsdomain.MaxMentionLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+	filter.Limit, filter.Offset = limit, offset
+
+	page, err := h.Comments.Mentions(r.Context(), family.ID, user.ID, filter)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("mentions.list: list mentions failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := mentionListResponse{
+		Items:  make([]mentionResponse, 0, len(page.Items)),
+		Total:  page.Total,
+		Unread: page.Unread,
+	}
+	for _, mention := range page.Items {
+		response.Items = append(response.Items, toMentionResponse(mention))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// MarkMentionsRead marks the listed mentions of the caller read, or every
+// one of them without ids.
+func (h *Handlers) MarkMentionsRead(w http.ResponseWriter, r *http.Request) {
+	var req markReadRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Comments.MarkMentionsRead(r.Context(), family.ID, user.ID, req.IDs)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("mentions.mark_read: mark mentions read failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, markReadResponse{Updated: updated})
+}
+
+func toMentionResponse(mention commentsdomain.Mention) mentionResponse {
+	return mentionResponse{
+		ID:         mention.ID,
+		EntityType: string(mention.EntityType),
+		EntityID:   mention.EntityID,
+		Comment: mentionCommentResponse{
+			ID: mention.Comment.ID,
+			Author: commentAuthorResponse{
+				ID:        mention.Comment.AuthorID,
+				Name:      mention.Comment.AuthorName,
+				AvatarURL: mention.Comment.AuthorAvatarURL,
+			},
+			Body:      mention.Comment.Body,
+			CreatedAt: mention.Comment.CreatedAt,
+		},
+		CreatedAt: mention.CreatedAt,
+		ReadAt:    mention.ReadAt,
+	}
+}
@@ -0,0 +1,13 @@
+package mentions
+
+import (
+	"context"
+
+	commentsdomain "family-app-go/internal/domain/comments"
+)
+
+// CommentsService is implemented by *commentsdomain.Service.
+type CommentsService interface {
+	MarkMentionsRead(ctx context.Context, familyID, userID string, mentionIDs []string) (int64, error)
+	Mentions(ctx context.Context, familyID, userID string, filter commentsdomain.MentionFilter) (*commentsdomain.MentionPage, error)
+}
@@ -0,0 +1,17 @@
+package milestones
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Milestones MilestonesService
+	log        logger.Logger
+}
+
+func New(milestones MilestonesService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Milestones: milestones,
+		log:        log,
+	}
+}
@@ -0,0 +1,298 @@
+package milestones
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type milestoneRequest struct {
+	Title       string              `json:"title"`
+	Kind        string              `json:"kind"`
+	Date        string              `json:"date"`
+	Note        *string             `json:"note"`
+	SavingsGoal *savingsGoalRequest `json:"savings_goal"`
+}
+
+type savingsGoalRequest struct {
+	Amount   float64  `json:"amount"`
+	Currency *string  `json:"currency"`
+	Saved    *float64 `json:"saved"`
+}
+
+type savingsRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+type countdownResponse struct {
+	Days   int  `json:"days"`
+	Weeks  int  `json:"weeks"`
+	Months int  `json:"months"`
+	Passed bool `json:"passed"`
+}
+
+type savingsGoalResponse struct {
+	Amount    float64  `json:"amount"`
+	Currency  string   `json:"currency"`
+	Saved     float64  `json:"saved"`
+	Remaining float64  `json:"remaining"`
+	Share     float64  `json:"share"`
+	PerMonth  *float64 `json:"per_month"`
+}
+
+type milestoneResponse struct {
+	ID          string               `json:"id"`
+	Title       string               `json:"title"`
+	Kind        string               `json:"kind"`
+	Date        string               `json:"date"`
+	Note        *string              `json:"note"`
+	Countdown   countdownResponse    `json:"countdown"`
+	SavingsGoal *savingsGoalResponse `json:"savings_goal"`
+	CreatedBy   string               `json:"created_by"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+type milestoneListResponse struct {
+	Items []milestoneResponse `json:"items"`
+}
+
+// ListMilestones returns the family's milestones from today on, soonest
+// first; include_past adds those whose date has passed. Children get them
+// without savings goals.
+func (h *Handlers) ListMilestones(w http.ResponseWriter, r *http.Request) {
+	user, family, ok := h.currentUserFamily(w, r)
+	if !ok {
+		return
+	}
+
+	var filter milestonesdomain.ListFilter
+	if value := strings.TrimSpace(r.URL.Query().Get("include_past")); value != "" {
+		includePast, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid include_past")
+			return
+		}
+		filter.IncludePast = includePast
+	}
+
+	milestones, err := h.Milestones.List(r.Context(), family.ID, filter)
+	if err != nil {
+		h.writeMilestoneError(w, r, "milestones.list", err, "user_id", user.ID, "family_id", family.ID)
+		return
+	}
+
+	today := time.Now()
+	child := middleware.IsChild(r.Context())
+	response := milestoneListResponse{Items: make([]milestoneResponse, 0, len(milestones))}
+	for _, milestone := range milestones {
+		response.Items = append(response.Items, toMilestoneResponse(milestone, today, child))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) GetMilestone(w http.ResponseWriter, r *http.Request) {
+	user, family, milestoneID, ok := h.milestoneFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	milestone, err := h.Milestones.Get(r.Context(), family.ID, milestoneID)
+	if err != nil {
+		h.writeMilestoneError(w, r, "milestones.get", err, "user_id", user.ID, "family_id", family.ID, "milestone_id", milestoneID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toMilestoneResponse(*milestone, time.Now(), middleware.IsChild(r.Context())))
+}
+
+func (h *Handlers) CreateMilestone(w http.ResponseWriter, r *http.Request) {
+	var req milestoneRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r)
+	if !ok {
+		return
+	}
+
+	milestone, err := h.Milestones.Create(r.Context(), family.ID, user.ID, toMilestoneInput(req, family))
+	if err != nil {
+		h.writeMilestoneError(w, r, "milestones.create", err, "user_id", user.ID, "family_id", family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toMilestoneResponse(*milestone, time.Now(), false))
+}
+
+// UpdateMilestone replaces a milestone's fields; leaving out savings_goal
+// removes the goal.
+func (h *Handlers) UpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	var req milestoneRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, milestoneID, ok := h.milestoneFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	milestone, err := h.Milestones.Update(r.Context(), family.ID, milestoneID, toMilestoneInput(req, family))
+	if err != nil {
+		h.writeMilestoneError(w, r, "milestones.update", err, "user_id", user.ID, "family_id", family.ID, "milestone_id", milestoneID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toMilestoneResponse(*milestone, time.Now(), false))
+}
+
+func (h *Handlers) DeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	user, family, milestoneID, ok := h.milestoneFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Milestones.Delete(r.Context(), family.ID, milestoneID); err != nil {
+		h.writeMilestoneError(w, r, "milestones.delete", err, "user_id", user.ID, "family_id", family.ID, "milestone_id", milestoneID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddMilestoneSavings adds money put aside for a milestone's savings goal;
+// a negative amount takes it back.
+func (h *Handlers) AddMilestoneSavings(w http.ResponseWriter, r *http.Request) {
+	var req savingsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, milestoneID, ok := h.milestoneFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	milestone, err := h.Milestones.AddSavings(r.Context(), family.ID, milestoneID, req.Amount)
+	if err != nil {
+		h.writeMilestoneError(w, r, "milestones.savings", err, "user_id", user.ID, "family_id", family.ID, "milestone_id", milestoneID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toMilestoneResponse(*milestone, time.Now(), false))
+}
+
+func (h *Handlers) currentUserFamily(w http.ResponseWriter, r *http.Request) (middleware.User, *familydomain.Family, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, false
+	}
+	return user, family, true
+}
+
+func (h *Handlers) milestoneFromPath(w http.ResponseWriter, r *http.Request) (middleware.User, *familydomain.Family, string, bool) {
+	milestoneID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if milestoneID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return middleware.User{}, nil, "", false
+	}
+
+	user, family, ok := h.currentUserFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, "", false
+	}
+	return user, family, milestoneID, true
+}
+
+func (h *Handlers) writeMilestoneError(w http.ResponseWriter, r *http.Request, operation string, err error, args ...any) {
+	switch {
+	case errors.Is(err, milestonesdomain.ErrMilestoneNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": milestone not found", err, args...)
+		writeError(w, http.StatusNotFound, "milestone_not_found", "milestone not found")
+	case errors.Is(err, milestonesdomain.ErrNoSavingsGoal):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": no savings goal", err, args...)
+		writeError(w, http.StatusConflict, "no_savings_goal", "milestone has no savings goal")
+	case errors.Is(err, milestonesdomain.ErrInvalidTitle):
+		writeValidationError(w, validation.FieldErr("title", validation.CodeInvalid, "title must be 1 to 120 characters"))
+	case errors.Is(err, milestonesdomain.ErrInvalidKind):
+		writeValidationError(w, validation.FieldErr("kind", validation.CodeEnum, "kind must be one of "+strings.Join(milestonesdomain.Kinds, ", ")))
+	case errors.Is(err, milestonesdomain.ErrInvalidGoal):
+		writeValidationError(w, validation.FieldErr("savings_goal", validation.CodeInvalid, "savings_goal needs a positive amount, a currency and a non-negative saved amount"))
+	case errors.Is(err, milestonesdomain.ErrInvalidAmount):
+		writeValidationError(w, validation.FieldErr("amount", validation.CodeInvalid, "amount must be non-zero and fit the goal's currency"))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": failed", err, args...)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+// toMilestoneInput reads a request the validation already accepted. A goal
+// without currency is kept in the family currency.
+func toMilestoneInput(req milestoneRequest, family *familydomain.Family) milestonesdomain.Input {
+	date, _ := parseDateRequired(req.Date)
+	input := milestonesdomain.Input{
+		Title: req.Title,
+		Kind:  req.Kind,
+		Date:  date,
+		Note:  req.Note,
+	}
+	if goal := req.SavingsGoal; goal != nil {
+		currency := family.DefaultCurrency
+		if goal.Currency != nil && strings.TrimSpace(*goal.Currency) != "" {
+			currency = *goal.Currency
+		}
+		input.Goal = &milestonesdomain.GoalInput{Amount: goal.Amount, Currency: currency}
+		if goal.Saved != nil {
+			input.Goal.Saved = *goal.Saved
+		}
+	}
+	return input
+}
+
+func toMilestoneResponse(milestone milestonesdomain.Milestone, today time.Time, child bool) milestoneResponse {
+	countdown := milestone.Countdown(today)
+	response := milestoneResponse{
+		ID:    milestone.ID,
+		Title: milestone.Title,
+		Kind:  milestone.Kind,
+		Date:  milestone.Date.Format("2006-01-02"),
+		Note:  milestone.Note,
+		Countdown: countdownResponse{
+			Days:   countdown.Days,
+			Weeks:  countdown.Weeks,
+			Months: countdown.Months,
+			Passed: countdown.Passed,
+		},
+		CreatedBy: milestone.CreatedBy,
+		CreatedAt: milestone.CreatedAt,
+		UpdatedAt: milestone.UpdatedAt,
+	}
+	if progress := milestone.Progress(today); progress != nil && !child {
+		response.SavingsGoal = &savingsGoalResponse{
+			Amount:    progress.Goal,
+			Currency:  progress.Currency,
+			Saved:     progress.Saved,
+			Remaining: progress.Remaining,
+			Share:     progress.Share,
+			PerMonth:  progress.PerMonth,
+		}
+	}
+	return response
+}
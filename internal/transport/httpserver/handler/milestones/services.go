@@ -0,0 +1,17 @@
+package milestones
+
+import (
+	"context"
+
+	milestonesdomain "family-app-go/internal/domain/milestones"
+)
+
+// MilestonesService is implemented by *milestonesdomain.Service.
+type MilestonesService interface {
+	AddSavings(ctx context.Context, familyID, milestoneID string, amount float64) (*milestonesdomain.Milestone, error)
+	Create(ctx context.Context, familyID, createdBy string, input milestonesdomain.Input) (*milestonesdomain.Milestone, error)
+	Delete(ctx context.Context, familyID, milestoneID string) error
+	Get(ctx context.Context, familyID, milestoneID string) (*milestonesdomain.Milestone, error)
+	List(ctx context.Context, familyID string, filter milestonesdomain.ListFilter) ([]milestonesdomain.Milestone, error)
+	Update(ctx context.Context, familyID, milestoneID string, input milestonesdomain.Input) (*milestonesdomain.Milestone, error)
+}
@@ -0,0 +1,37 @@
+package milestones
+
+import (
+	"strings"
+
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req milestoneRequest) Validate(v *validation.Validator) {
+	v.Required("title", req.Title)
+	v.MaxLength("title", req.Title, milestonesdomain.MaxTitleLength)
+	if kind := strings.TrimSpace(req.Kind); kind != "" {
+		v.OneOf("kind", strings.ToLower(kind), milestonesdomain.Kinds...)
+	}
+	v.Required("date", req.Date)
+	v.Date("date", &req.Date)
+	if req.SavingsGoal != nil {
+		v.Nested("savings_goal", *req.SavingsGoal)
+	}
+}
+
+func (req savingsGoalRequest) Validate(v *validation.Validator) {
+	v.Positive("amount", req.Amount)
+	v.NonNegativeFloat("saved", req.Saved)
+	if req.Currency != nil && strings.TrimSpace(*req.Currency) != "" {
+		v.Currency("currency", *req.Currency)
+		v.Precision("amount", req.Amount, *req.Currency)
+		if req.Saved != nil {
+			v.Precision("saved", *req.Saved, *req.Currency)
+		}
+	}
+}
+
+func (req savingsRequest) Validate(v *validation.Validator) {
+	v.Check(req.Amount != 0, "amount", validation.CodeInvalid, "amount must not be zero")
+}
@@ -0,0 +1,17 @@
+package notifications
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Notifications NotificationsService
+	log           logger.Logger
+}
+
+func New(notifications NotificationsService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Notifications: notifications,
+		log:           log,
+	}
+}
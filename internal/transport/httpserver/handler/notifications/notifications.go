@@ -0,0 +1,139 @@
+package notifications
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	notificationsdomain "family-app-go/internal/domain/notifications"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+type markReadRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func (req markReadRequest) Validate(v *validation.Validator) {
+	v.Check(len(req.IDs) <= notificationsdomain.MaxLimit, "ids", validation.CodeInvalid, fmt.Sprintf("at most %d ids", notificationsdomain.MaxLimit))
+	for i, id := range req.IDs {
+		v.UUID(fmt.Sprintf("ids[%d]", i), id)
+	}
+}
+
+type notificationResponse struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Link      string     `json:"link"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+type notificationListResponse struct {
+	Items  []notificationResponse `json:"items"`
+	Total  int64                  `json:"total"`
+	Unread int64                  `json:"unread"`
+}
+
+type markReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// ListNotifications returns the caller's notification inbox, newest first.
+func (h *Handlers) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	var filter notificationsdomain.ListFilter
+	if value := strings.TrimSpace(query.Get("unread")); value != "" {
+		unread, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid unread")
+			return
+		}
+		filter.UnreadOnly = unread
+	}
+	limit, err := parseIntParam(query.Get("limit"), notificationsdomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > notificationsdomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+	filter.Limit, filter.Offset = limit, offset
+
+	page, err := h.Notifications.List(r.Context(), family.ID, user.ID, filter)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("notifications.list: list notifications failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := notificationListResponse{
+		Items:  make([]notificationResponse, 0, len(page.Items)),
+		Total:  page.Total,
+		Unread: page.Unread,
+	}
+	for _, notification := range page.Items {
+		response.Items = append(response.Items, toNotificationResponse(notification))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// MarkNotificationsRead marks the listed notifications of the caller read,
+// or every one of them without ids.
+func (h *Handlers) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	var req markReadRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.Notifications.MarkRead(r.Context(), family.ID, user.ID, req.IDs)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("notifications.mark_read: mark notifications read failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, markReadResponse{Updated: updated})
+}
+
+func toNotificationResponse(notification notificationsdomain.Notification) notificationResponse {
+	return notificationResponse{
+		ID:        notification.ID,
+		Kind:      notification.Kind,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Link:      notification.Link,
+		CreatedAt: notification.CreatedAt,
+		ReadAt:    notification.ReadAt,
+	}
+}
@@ -0,0 +1,13 @@
+package notifications
+
+import (
+	"context"
+
+	notificationsdomain "family-app-go/internal/domain/notifications"
+)
+
+// NotificationsService is implemented by *notificationsdomain.Service.
+type NotificationsService interface {
+	List(ctx context.Context, familyID, userID string, filter notificationsdomain.ListFilter) (*notificationsdomain.Page, error)
+	MarkRead(ctx context.Context, familyID, userID string, notificationIDs []string) (int64, error)
+}
@@ -0,0 +1,17 @@
+package pets
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Pets PetsService
+	log  logger.Logger
+}
+
+func New(pets PetsService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Pets: pets,
+		log:  log,
+	}
+}
@@ -0,0 +1,45 @@
+package pets
+
+import (
+	"net/http"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+)
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	commonhandler.WriteError(w, status, code, message)
+}
+
+func writeQuotaExceeded(w http.ResponseWriter, err error) {
+	commonhandler.WriteQuotaExceeded(w, err)
+}
+
+func requireFamily(w http.ResponseWriter, r *http.Request) (*familydomain.Family, bool) {
+	return commonhandler.RequireFamily(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	commonhandler.WriteJSON(w, status, payload)
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return commonhandler.DecodeRequest(w, r, dst)
+}
+
+func parseDateRequired(value string) (time.Time, error) {
+	return commonhandler.ParseDateRequired(value)
+}
+
+func parseDateParam(value string) (*time.Time, error) {
+	return commonhandler.ParseDateParam(value)
+}
+
+func parseIntParam(value string, fallback int) (int, error) {
+	return commonhandler.ParseIntParam(value, fallback)
+}
+
+func writeValidationError(w http.ResponseWriter, err error) {
+	commonhandler.WriteValidationError(w, err)
+}
@@ -0,0 +1,624 @@
+package pets
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	petsdomain "family-app-go/internal/domain/pets"
+	quotadomain "family-app-go/internal/domain/quota"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type petRequest struct {
+	Name      string  `json:"name"`
+	Species   *string `json:"species"`
+	Breed     *string `json:"breed"`
+	BirthDate *string `json:"birth_date"`
+	Notes     *string `json:"notes"`
+}
+
+type createVaccinationRequest struct {
+	Name           string  `json:"name"`
+	AdministeredOn string  `json:"administered_on"`
+	NextDueOn      *string `json:"next_due_on"`
+	Notes          *string `json:"notes"`
+}
+
+type createVetVisitRequest struct {
+	VisitDate string   `json:"visit_date"`
+	Reason    string   `json:"reason"`
+	Clinic    *string  `json:"clinic"`
+	Notes     *string  `json:"notes"`
+	Cost      *float64 `json:"cost"`
+	Currency  *string  `json:"currency"`
+}
+
+type createScheduleRequest struct {
+	Kind          string     `json:"kind"`
+	Title         string     `json:"title"`
+	Dosage        *string    `json:"dosage"`
+	IntervalHours int        `json:"interval_hours"`
+	StartsAt      *time.Time `json:"starts_at"`
+}
+
+type petResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Species   *string   `json:"species"`
+	Breed     *string   `json:"breed"`
+	BirthDate *string   `json:"birth_date"`
+	Notes     *string   `json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type vaccinationResponse struct {
+	ID             string    `json:"id"`
+	PetID          string    `json:"pet_id"`
+	Name           string    `json:"name"`
+	AdministeredOn string    `json:"administered_on"`
+	NextDueOn      *string   `json:"next_due_on"`
+	Notes          *string   `json:"notes"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type vetVisitResponse struct {
+	ID        string    `json:"id"`
+	PetID     string    `json:"pet_id"`
+	VisitDate string    `json:"visit_date"`
+	Reason    string    `json:"reason"`
+	Clinic    *string   `json:"clinic"`
+	Notes     *string   `json:"notes"`
+	Cost      *float64  `json:"cost"`
+	Currency  *string   `json:"currency"`
+	ExpenseID *string   `json:"expense_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type scheduleResponse struct {
+	ID            string     `json:"id"`
+	PetID         string     `json:"pet_id"`
+	Kind          string     `json:"kind"`
+	Title         string     `json:"title"`
+	Dosage        *string    `json:"dosage"`
+	IntervalHours int        `json:"interval_hours"`
+	NextDueAt     time.Time  `json:"next_due_at"`
+	LastDoneAt    *time.Time `json:"last_done_at"`
+}
+
+type reminderResponse struct {
+	Kind     string    `json:"kind"`
+	PetID    string    `json:"pet_id"`
+	PetName  string    `json:"pet_name"`
+	SourceID string    `json:"source_id"`
+	Title    string    `json:"title"`
+	DueAt    time.Time `json:"due_at"`
+	Overdue  bool      `json:"overdue"`
+}
+
+type listResponse[T any] struct {
+	Items []T `json:"items"`
+}
+
+func (h *Handlers) ListPets(w http.ResponseWriter, r *http.Request) {
+	user, family, ok := h.currentUserFamily(w, r, "pets.list")
+	if !ok {
+		return
+	}
+
+	pets, err := h.Pets.ListPets(r.Context(), family.ID)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.list", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, listResponse[petResponse]{Items: mapSlice(pets, toPetResponse)})
+}
+
+func (h *Handlers) CreatePet(w http.ResponseWriter, r *http.Request) {
+	var req petRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	input := toPetInput(req)
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.create")
+	if !ok {
+		return
+	}
+
+	pet, err := h.Pets.CreatePet(r.Context(), family.ID, input)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.create", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toPetResponse(*pet))
+}
+
+func (h *Handlers) UpdatePet(w http.ResponseWriter, r *http.Request) {
+	var req petRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	input := toPetInput(req)
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.update")
+	if !ok {
+		return
+	}
+
+	pet, err := h.Pets.UpdatePet(r.Context(), family.ID, petID, input)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.update", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPetResponse(*pet))
+}
+
+func (h *Handlers) DeletePet(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.delete")
+	if !ok {
+		return
+	}
+
+	if err := h.Pets.DeletePet(r.Context(), family.ID, petID); err != nil {
+		h.writeServiceError(w, r, err, "pets.delete", user.ID, family.ID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ListVaccinations(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.list_vaccinations")
+	if !ok {
+		return
+	}
+
+	vaccinations, err := h.Pets.ListVaccinations(r.Context(), family.ID, petID)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.list_vaccinations", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, listResponse[vaccinationResponse]{Items: mapSlice(vaccinations, toVaccinationResponse)})
+}
+
+func (h *Handlers) CreateVaccination(w http.ResponseWriter, r *http.Request) {
+	var req createVaccinationRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	administeredOn, _ := parseDateRequired(req.AdministeredOn)
+	nextDueOn := parseOptionalDate(req.NextDueOn)
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.create_vaccination")
+	if !ok {
+		return
+	}
+
+	vaccination, err := h.Pets.CreateVaccination(r.Context(), family.ID, petID, petsdomain.CreateVaccinationInput{
+		Name:           req.Name,
+		AdministeredOn: administeredOn,
+		NextDueOn:      nextDueOn,
+		Notes:          req.Notes,
+	})
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.create_vaccination", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toVaccinationResponse(*vaccination))
+}
+
+func (h *Handlers) DeleteVaccination(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+	vaccinationID, ok := urlParam(w, r, "vaccination_id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.delete_vaccination")
+	if !ok {
+		return
+	}
+
+	if err := h.Pets.DeleteVaccination(r.Context(), family.ID, petID, vaccinationID); err != nil {
+		h.writeServiceError(w, r, err, "pets.delete_vaccination", user.ID, family.ID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ListVetVisits(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.list_vet_visits")
+	if !ok {
+		return
+	}
+
+	visits, err := h.Pets.ListVetVisits(r.Context(), family.ID, petID)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.list_vet_visits", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, listResponse[vetVisitResponse]{Items: mapSlice(visits, toVetVisitResponse)})
+}
+
+func (h *Handlers) CreateVetVisit(w http.ResponseWriter, r *http.Request) {
+	var req createVetVisitRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	visitDate, _ := parseDateRequired(req.VisitDate)
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.create_vet_visit")
+	if !ok {
+		return
+	}
+
+	visit, err := h.Pets.CreateVetVisit(r.Context(), family.ID, petID, petsdomain.CreateVetVisitInput{
+		UserID:       user.ID,
+		BaseCurrency: family.DefaultCurrency,
+		VisitDate:    visitDate,
+		Reason:       req.Reason,
+		Clinic:       req.Clinic,
+		Notes:        req.Notes,
+		Cost:         req.Cost,
+		Currency:     req.Currency,
+
+		AllowedCurrencies: family.Currencies(),
+	})
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.create_vet_visit", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toVetVisitResponse(*visit))
+}
+
+func (h *Handlers) DeleteVetVisit(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+	visitID, ok := urlParam(w, r, "visit_id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.delete_vet_visit")
+	if !ok {
+		return
+	}
+
+	if err := h.Pets.DeleteVetVisit(r.Context(), family.ID, petID, visitID); err != nil {
+		h.writeServiceError(w, r, err, "pets.delete_vet_visit", user.ID, family.ID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.list_schedules")
+	if !ok {
+		return
+	}
+
+	schedules, err := h.Pets.ListSchedules(r.Context(), family.ID, petID)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.list_schedules", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, listResponse[scheduleResponse]{Items: mapSlice(schedules, toScheduleResponse)})
+}
+
+func (h *Handlers) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req createScheduleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.create_schedule")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.Pets.CreateSchedule(r.Context(), family.ID, petID, petsdomain.CreateScheduleInput{
+		Kind:          req.Kind,
+		Title:         req.Title,
+		Dosage:        req.Dosage,
+		IntervalHours: req.IntervalHours,
+		StartsAt:      req.StartsAt,
+	})
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.create_schedule", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toScheduleResponse(*schedule))
+}
+
+func (h *Handlers) MarkScheduleDone(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+	scheduleID, ok := urlParam(w, r, "schedule_id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.mark_schedule_done")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.Pets.MarkScheduleDone(r.Context(), family.ID, petID, scheduleID)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.mark_schedule_done", user.ID, family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toScheduleResponse(*schedule))
+}
+
+func (h *Handlers) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	petID, ok := urlParam(w, r, "id")
+	if !ok {
+		return
+	}
+	scheduleID, ok := urlParam(w, r, "schedule_id")
+	if !ok {
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.delete_schedule")
+	if !ok {
+		return
+	}
+
+	if err := h.Pets.DeleteSchedule(r.Context(), family.ID, petID, scheduleID); err != nil {
+		h.writeServiceError(w, r, err, "pets.delete_schedule", user.ID, family.ID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ListReminders(w http.ResponseWriter, r *http.Request) {
+	days, err := parseIntParam(r.URL.Query().Get("days"), petsdomain.DefaultReminderDays)
+	if err != nil || days < 1 || days > petsdomain.MaxReminderWindowDays {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid days")
+		return
+	}
+
+	user, family, ok := h.currentUserFamily(w, r, "pets.list_reminders")
+	if !ok {
+		return
+	}
+
+	reminders, err := h.Pets.ListReminders(r.Context(), family.ID, days)
+	if err != nil {
+		h.writeServiceError(w, r, err, "pets.list_reminders", user.ID, family.ID)
+		return
+	}
+
+	now := time.Now().UTC()
+	response := make([]reminderResponse, 0, len(reminders))
+	for _, reminder := range reminders {
+		response = append(response, reminderResponse{
+			Kind:     reminder.Kind,
+			PetID:    reminder.PetID,
+			PetName:  reminder.PetName,
+			SourceID: reminder.SourceID,
+			Title:    reminder.Title,
+			DueAt:    reminder.DueAt,
+			Overdue:  reminder.DueAt.Before(now),
+		})
+	}
+	writeJSON(w, http.StatusOK, listResponse[reminderResponse]{Items: response})
+}
+
+func (h *Handlers) currentUserFamily(w http.ResponseWriter, r *http.Request, operation string) (middleware.User, *familydomain.Family, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, false
+	}
+
+	return user, family, true
+}
+
+func (h *Handlers) writeServiceError(w http.ResponseWriter, r *http.Request, err error, operation, userID, familyID string) {
+	switch {
+	case errors.Is(err, petsdomain.ErrPetNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": pet not found", err, "user_id", userID, "family_id", familyID)
+		writeError(w, http.StatusNotFound, "pet_not_found", "pet not found")
+	case errors.Is(err, petsdomain.ErrVaccinationNotFound):
+		writeError(w, http.StatusNotFound, "vaccination_not_found", "vaccination not found")
+	case errors.Is(err, petsdomain.ErrVetVisitNotFound):
+		writeError(w, http.StatusNotFound, "vet_visit_not_found", "vet visit not found")
+	case errors.Is(err, petsdomain.ErrScheduleNotFound):
+		writeError(w, http.StatusNotFound, "care_schedule_not_found", "care schedule not found")
+	case errors.Is(err, petsdomain.ErrNameRequired):
+		writeValidationError(w, validation.FieldErr("name", validation.CodeRequired, "name is required"))
+	case errors.Is(err, petsdomain.ErrReasonRequired):
+		writeValidationError(w, validation.FieldErr("reason", validation.CodeRequired, "reason is required"))
+	case errors.Is(err, petsdomain.ErrTitleRequired):
+		writeValidationError(w, validation.FieldErr("title", validation.CodeRequired, "title is required"))
+	case errors.Is(err, petsdomain.ErrInvalidScheduleKind):
+		writeValidationError(w, validation.FieldErr("kind", validation.CodeEnum, "kind must be feeding or medication"))
+	case errors.Is(err, petsdomain.ErrInvalidInterval):
+		writeValidationError(w, validation.FieldErr("interval_hours", validation.CodeRange, "invalid interval_hours"))
+	case errors.Is(err, petsdomain.ErrInvalidCost):
+		writeValidationError(w, validation.FieldErr("cost", validation.CodeInvalid, "cost must be positive and requires currency"))
+	case errors.Is(err, petsdomain.ErrInvalidNextDue):
+		writeValidationError(w, validation.FieldErr("next_due_on", validation.CodeInvalid, "next_due_on must not be before administered_on"))
+	case errors.Is(err, expensesdomain.ErrRateNotAvailable):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": rate not available", err, "user_id", userID, "family_id", familyID)
+		writeError(w, http.StatusUnprocessableEntity, "rate_not_available", "rate is not available for selected date")
+	case errors.Is(err, expensesdomain.ErrInvalidCurrency):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": invalid currency", err, "user_id", userID, "family_id", familyID)
+		writeValidationError(w, validation.FieldErr("currency", validation.CodeCurrency, err.Error()))
+	case errors.Is(err, quotadomain.ErrExceeded):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": quota exceeded", err, "user_id", userID, "family_id", familyID)
+		writeQuotaExceeded(w, err)
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": request failed", err, "user_id", userID, "family_id", familyID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+func toPetInput(req petRequest) petsdomain.PetInput {
+	return petsdomain.PetInput{
+		Name:      req.Name,
+		Species:   req.Species,
+		Breed:     req.Breed,
+		BirthDate: parseOptionalDate(req.BirthDate),
+		Notes:     req.Notes,
+	}
+}
+
+// parseOptionalDate reads a date the request validation already accepted.
+func parseOptionalDate(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+	parsed, _ := parseDateParam(*value)
+	return parsed
+}
+
+func urlParam(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	value := strings.TrimSpace(chi.URLParam(r, name))
+	if value == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", name+" is required")
+		return "", false
+	}
+	return value, true
+}
+
+func mapSlice[T, R any](items []T, convert func(T) R) []R {
+	result := make([]R, 0, len(items))
+	for _, item := range items {
+		result = append(result, convert(item))
+	}
+	return result
+}
+
+func formatDate(value *time.Time) *string {
+	if value == nil {
+		return nil
+	}
+	formatted := value.Format("2006-01-02")
+	return &formatted
+}
+
+func toPetResponse(pet petsdomain.Pet) petResponse {
+	return petResponse{
+		ID:        pet.ID,
+		Name:      pet.Name,
+		Species:   pet.Species,
+		Breed:     pet.Breed,
+		BirthDate: formatDate(pet.BirthDate),
+		Notes:     pet.Notes,
+		CreatedAt: pet.CreatedAt,
+		UpdatedAt: pet.UpdatedAt,
+	}
+}
+
+func toVaccinationResponse(vaccination petsdomain.Vaccination) vaccinationResponse {
+	return vaccinationResponse{
+		ID:             vaccination.ID,
+		PetID:          vaccination.PetID,
+		Name:           vaccination.Name,
+		AdministeredOn: vaccination.AdministeredOn.Format("2006-01-02"),
+		NextDueOn:      formatDate(vaccination.NextDueOn),
+		Notes:          vaccination.Notes,
+		CreatedAt:      vaccination.CreatedAt,
+	}
+}
+
+func toVetVisitResponse(visit petsdomain.VetVisit) vetVisitResponse {
+	return vetVisitResponse{
+		ID:        visit.ID,
+		PetID:     visit.PetID,
+		VisitDate: visit.VisitDate.Format("2006-01-02"),
+		Reason:    visit.Reason,
+		Clinic:    visit.Clinic,
+		Notes:     visit.Notes,
+		Cost:      visit.Cost,
+		Currency:  visit.Currency,
+		ExpenseID: visit.ExpenseID,
+		CreatedAt: visit.CreatedAt,
+	}
+}
+
+func toScheduleResponse(schedule petsdomain.CareSchedule) scheduleResponse {
+	return scheduleResponse{
+		ID:            schedule.ID,
+		PetID:         schedule.PetID,
+		Kind:          schedule.Kind,
+		Title:         schedule.Title,
+		Dosage:        schedule.Dosage,
+		IntervalHours: schedule.IntervalHours,
+		NextDueAt:     schedule.NextDueAt,
+		LastDoneAt:    schedule.LastDoneAt,
+	}
+}
@@ -0,0 +1,26 @@
+package pets
+
+import (
+	"context"
+
+	petsdomain "family-app-go/internal/domain/pets"
+)
+
+// PetsService is implemented by *petsdomain.Service.
+type PetsService interface {
+	CreatePet(ctx context.Context, familyID string, input petsdomain.PetInput) (*petsdomain.Pet, error)
+	CreateSchedule(ctx context.Context, familyID, petID string, input petsdomain.CreateScheduleInput) (*petsdomain.CareSchedule, error)
+	CreateVaccination(ctx context.Context, familyID, petID string, input petsdomain.CreateVaccinationInput) (*petsdomain.Vaccination, error)
+	CreateVetVisit(ctx context.Context, familyID, petID string, input petsdomain.CreateVetVisitInput) (*petsdomain.VetVisit, error)
+	DeletePet(ctx context.Context, familyID, petID string) error
+	DeleteSchedule(ctx context.Context, familyID, petID, scheduleID string) error
+	DeleteVaccination(ctx context.Context, familyID, petID, vaccinationID string) error
+	DeleteVetVisit(ctx context.Context, familyID, petID, visitID string) error
+	ListPets(ctx context.Context, familyID string) ([]petsdomain.Pet, error)
+	ListReminders(ctx context.Context, familyID string, windowDays int) ([]petsdomain.Reminder, error)
+	ListSchedules(ctx context.Context, familyID, petID string) ([]petsdomain.CareSchedule, error)
+	ListVaccinations(ctx context.Context, familyID, petID string) ([]petsdomain.Vaccination, error)
+	ListVetVisits(ctx context.Context, familyID, petID string) ([]petsdomain.VetVisit, error)
+	MarkScheduleDone(ctx context.Context, familyID, petID, scheduleID string) (*petsdomain.CareSchedule, error)
+	UpdatePet(ctx context.Context, familyID, petID string, input petsdomain.PetInput) (*petsdomain.Pet, error)
+}
@@ -0,0 +1,41 @@
+package pets
+
+import (
+	"strings"
+
+	petsdomain "family-app-go/internal/domain/pets"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req petRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.Date("birth_date", req.BirthDate)
+}
+
+func (req createVaccinationRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.Required("administered_on", req.AdministeredOn)
+	v.Date("administered_on", &req.AdministeredOn)
+	v.Date("next_due_on", req.NextDueOn)
+}
+
+func (req createVetVisitRequest) Validate(v *validation.Validator) {
+	v.Required("visit_date", req.VisitDate)
+	v.Date("visit_date", &req.VisitDate)
+	v.Required("reason", req.Reason)
+	if req.Cost != nil {
+		v.Positive("cost", *req.Cost)
+		v.Check(req.Currency != nil && len(strings.TrimSpace(*req.Currency)) == 3, "currency", validation.CodeFormat, "currency must be a 3-letter code when cost is set")
+		if req.Currency != nil {
+			v.Precision("cost", *req.Cost, *req.Currency)
+			v.Currency("currency", *req.Currency)
+		}
+	}
+}
+
+func (req createScheduleRequest) Validate(v *validation.Validator) {
+	v.Required("kind", req.Kind)
+	v.OneOf("kind", strings.ToLower(req.Kind), petsdomain.ScheduleKindFeeding, petsdomain.ScheduleKindMedication)
+	v.Required("title", req.Title)
+	v.Between("interval_hours", &req.IntervalHours, 1, petsdomain.MaxIntervalHours)
+}
@@ -0,0 +1,17 @@
+package polls
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Polls PollsService
+	log   logger.Logger
+}
+
+func New(polls PollsService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Polls: polls,
+		log:   log,
+	}
+}
@@ -0,0 +1,354 @@
+package polls
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	pollsdomain "family-app-go/internal/domain/polls"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type createPollRequest struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options"`
+	Deadline *string  `json:"deadline"`
+}
+
+type voteRequest struct {
+	OptionID string `json:"option_id"`
+}
+
+type pollCreatorResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type pollOptionResponse struct {
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+	Votes int64  `json:"votes"`
+}
+
+type pollResponse struct {
+	ID         string               `json:"id"`
+	Question   string               `json:"question"`
+	Options    []pollOptionResponse `json:"options"`
+	CreatedBy  pollCreatorResponse  `json:"created_by"`
+	Deadline   *time.Time           `json:"deadline"`
+	ClosedAt   *time.Time           `json:"closed_at"`
+	IsOpen     bool                 `json:"is_open"`
+	TotalVotes int64                `json:"total_votes"`
+	Winners    []string             `json:"winners"`
+	MyVote     *string              `json:"my_vote"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
+
+type pollListResponse struct {
+	Items []pollResponse `json:"items"`
+	Total int64          `json:"total"`
+}
+
+type optionResultResponse struct {
+	ID       string   `json:"id"`
+	Text     string   `json:"text"`
+	Votes    int64    `json:"votes"`
+	Share    float64  `json:"share"`
+	VoterIDs []string `json:"voter_ids"`
+}
+
+type resultsResponse struct {
+	PollID     string                 `json:"poll_id"`
+	IsOpen     bool                   `json:"is_open"`
+	TotalVotes int64                  `json:"total_votes"`
+	Options    []optionResultResponse `json:"options"`
+	Winners    []string               `json:"winners"`
+}
+
+// ListPolls returns the family's polls, newest first. status narrows them
+// to open or closed ones.
+func (h *Handlers) ListPolls(w http.ResponseWriter, r *http.Request) {
+	user, family, ok := h.pollsFamily(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	status := pollsdomain.Status(strings.TrimSpace(query.Get("status")))
+	switch status {
+	case "", pollsdomain.StatusOpen, pollsdomain.StatusClosed, pollsdomain.StatusAll:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_request", "status must be open, closed or all")
+		return
+	}
+	limit, err := parseIntParam(query.Get("limit"), pollsdomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > pollsdomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+
+	summaries, total, err := h.Polls.List(r.Context(), family.ID, user.ID, pollsdomain.ListFilter{Status: status, Limit: limit, Offset: offset})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("polls.list: list polls failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	now := time.Now()
+	response := pollListResponse{Items: make([]pollResponse, 0, len(summaries)), Total: total}
+	for _, summary := range summaries {
+		response.Items = append(response.Items, toPollResponse(summary, now))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) CreatePoll(w http.ResponseWriter, r *http.Request) {
+	var req createPollRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, ok := h.pollsFamily(w, r)
+	if !ok {
+		return
+	}
+
+	var deadline *time.Time
+	if req.Deadline != nil && strings.TrimSpace(*req.Deadline) != "" {
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(*req.Deadline))
+		if err != nil {
+			writeValidationError(w, validation.FieldErr("deadline", validation.CodeFormat, "deadline must be RFC3339"))
+			return
+		}
+		deadline = &parsed
+	}
+
+	actor := actorFromUser(user)
+	summary, err := h.Polls.Create(r.Context(), pollsdomain.CreateInput{
+		FamilyID: family.ID,
+		Creator:  pollsdomain.Creator{ID: actor.ID, Name: actor.Name},
+		Question: req.Question,
+		Options:  req.Options,
+		Deadline: deadline,
+	})
+	if err != nil {
+		h.writePollError(w, r, "polls.create", err, "user_id", user.ID, "family_id", family.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toPollResponse(*summary, time.Now()))
+}
+
+func (h *Handlers) GetPoll(w http.ResponseWriter, r *http.Request) {
+	user, family, pollID, ok := h.pollFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.Polls.Get(r.Context(), family.ID, pollID, user.ID)
+	if err != nil {
+		h.writePollError(w, r, "polls.get", err, "user_id", user.ID, "family_id", family.ID, "poll_id", pollID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPollResponse(*summary, time.Now()))
+}
+
+// GetPollResults returns the tally of a poll with who voted for what.
+func (h *Handlers) GetPollResults(w http.ResponseWriter, r *http.Request) {
+	user, family, pollID, ok := h.pollFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.Polls.Get(r.Context(), family.ID, pollID, user.ID)
+	if err != nil {
+		h.writePollError(w, r, "polls.results", err, "user_id", user.ID, "family_id", family.ID, "poll_id", pollID)
+		return
+	}
+
+	results := summary.Results
+	response := resultsResponse{
+		PollID:     summary.Poll.ID,
+		IsOpen:     summary.Poll.Open(time.Now()),
+		TotalVotes: results.TotalVotes,
+		Options:    make([]optionResultResponse, 0, len(results.Options)),
+		Winners:    results.Winners,
+	}
+	for _, option := range results.Options {
+		var share float64
+		if results.TotalVotes > 0 {
+			share = float64(option.Votes) / float64(results.TotalVotes)
+		}
+		response.Options = append(response.Options, optionResultResponse{
+			ID:       option.Option.ID,
+			Text:     option.Option.Text,
+			Votes:    option.Votes,
+			Share:    share,
+			VoterIDs: option.VoterIDs,
+		})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// VotePoll sets the caller's vote, replacing an earlier one.
+func (h *Handlers) VotePoll(w http.ResponseWriter, r *http.Request) {
+	var req voteRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, pollID, ok := h.pollFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.Polls.Vote(r.Context(), family.ID, pollID, user.ID, strings.TrimSpace(req.OptionID))
+	if err != nil {
+		h.writePollError(w, r, "polls.vote", err, "user_id", user.ID, "family_id", family.ID, "poll_id", pollID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPollResponse(*summary, time.Now()))
+}
+
+// UnvotePoll withdraws the caller's vote.
+func (h *Handlers) UnvotePoll(w http.ResponseWriter, r *http.Request) {
+	user, family, pollID, ok := h.pollFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.Polls.Unvote(r.Context(), family.ID, pollID, user.ID)
+	if err != nil {
+		h.writePollError(w, r, "polls.unvote", err, "user_id", user.ID, "family_id", family.ID, "poll_id", pollID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPollResponse(*summary, time.Now()))
+}
+
+// ClosePoll ends the voting and posts the outcome to the family activity
+// feed. Only the creator and the family owner may close a poll.
+func (h *Handlers) ClosePoll(w http.ResponseWriter, r *http.Request) {
+	user, family, pollID, ok := h.pollFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	summary, err := h.Polls.Close(r.Context(), family.ID, pollID, closerFromRequest(r, user))
+	if err != nil {
+		h.writePollError(w, r, "polls.close", err, "user_id", user.ID, "family_id", family.ID, "poll_id", pollID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPollResponse(*summary, time.Now()))
+}
+
+// DeletePoll removes a poll; only the creator and the family owner may.
+func (h *Handlers) DeletePoll(w http.ResponseWriter, r *http.Request) {
+	user, family, pollID, ok := h.pollFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Polls.Delete(r.Context(), family.ID, pollID, closerFromRequest(r, user)); err != nil {
+		h.writePollError(w, r, "polls.delete", err, "user_id", user.ID, "family_id", family.ID, "poll_id", pollID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) pollsFamily(w http.ResponseWriter, r *http.Request) (middleware.User, *familydomain.Family, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, false
+	}
+	return user, family, true
+}
+
+func (h *Handlers) pollFromPath(w http.ResponseWriter, r *http.Request) (middleware.User, *familydomain.Family, string, bool) {
+	pollID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if pollID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return middleware.User{}, nil, "", false
+	}
+
+	user, family, ok := h.pollsFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, "", false
+	}
+	return user, family, pollID, true
+}
+
+func closerFromRequest(r *http.Request, user middleware.User) pollsdomain.Closer {
+	actor := actorFromUser(user)
+	role, _ := middleware.FamilyRoleFromContext(r.Context())
+	return pollsdomain.Closer{ID: actor.ID, Name: actor.Name, Email: actor.Email, IsOwner: role == familydomain.RoleOwner}
+}
+
+func (h *Handlers) writePollError(w http.ResponseWriter, r *http.Request, operation string, err error, args ...any) {
+	switch {
+	case errors.Is(err, pollsdomain.ErrPollNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": poll not found", err, args...)
+		writeError(w, http.StatusNotFound, "poll_not_found", "poll not found")
+	case errors.Is(err, pollsdomain.ErrPollClosed):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": poll closed", err, args...)
+		writeError(w, http.StatusConflict, "poll_closed", "poll is closed")
+	case errors.Is(err, pollsdomain.ErrNotAllowed):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": not allowed", err, args...)
+		writeError(w, http.StatusForbidden, "not_poll_creator", "only the poll creator or the family owner can do this")
+	case errors.Is(err, pollsdomain.ErrOptionNotFound):
+		writeValidationError(w, validation.FieldErr("option_id", validation.CodeInvalid, "option_id is not an option of this poll"))
+	case errors.Is(err, pollsdomain.ErrInvalidQuestion):
+		writeValidationError(w, validation.FieldErr("question", validation.CodeInvalid, "question must be 1 to 200 characters"))
+	case errors.Is(err, pollsdomain.ErrInvalidOptions):
+		writeValidationError(w, validation.FieldErr("options", validation.CodeInvalid, "options must be 2 to 10 distinct texts of at most 100 characters"))
+	case errors.Is(err, pollsdomain.ErrInvalidDeadline):
+		writeValidationError(w, validation.FieldErr("deadline", validation.CodeInvalid, "deadline must be in the future"))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": failed", err, args...)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+func toPollResponse(summary pollsdomain.Summary, now time.Time) pollResponse {
+	poll := summary.Poll
+	response := pollResponse{
+		ID:         poll.ID,
+		Question:   poll.Question,
+		Options:    make([]pollOptionResponse, 0, len(summary.Results.Options)),
+		CreatedBy:  pollCreatorResponse{ID: poll.CreatedBy, Name: poll.CreatorName},
+		Deadline:   poll.Deadline,
+		ClosedAt:   poll.ClosedAt,
+		IsOpen:     poll.Open(now),
+		TotalVotes: summary.Results.TotalVotes,
+		Winners:    summary.Results.Winners,
+		CreatedAt:  poll.CreatedAt,
+	}
+	for _, option := range summary.Results.Options {
+		response.Options = append(response.Options, pollOptionResponse{ID: option.Option.ID, Text: option.Option.Text, Votes: option.Votes})
+	}
+	if summary.MyVote != "" {
+		myVote := summary.MyVote
+		response.MyVote = &myVote
+	}
+	return response
+}
@@ -0,0 +1,18 @@
+package polls
+
+import (
+	"context"
+
+	pollsdomain "family-app-go/internal/domain/polls"
+)
+
+// PollsService is implemented by *pollsdomain.Service.
+type PollsService interface {
+	Close(ctx context.Context, familyID, pollID string, closer pollsdomain.Closer) (*pollsdomain.Summary, error)
+	Create(ctx context.Context, input pollsdomain.CreateInput) (*pollsdomain.Summary, error)
+	Delete(ctx context.Context, familyID, pollID string, closer pollsdomain.Closer) error
+	Get(ctx context.Context, familyID, pollID, viewerID string) (*pollsdomain.Summary, error)
+	List(ctx context.Context, familyID, viewerID string, filter pollsdomain.ListFilter) ([]pollsdomain.Summary, int64, error)
+	Unvote(ctx context.Context, familyID, pollID, userID string) (*pollsdomain.Summary, error)
+	Vote(ctx context.Context, familyID, pollID, userID, optionID string) (*pollsdomain.Summary, error)
+}
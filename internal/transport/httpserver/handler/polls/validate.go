@@ -0,0 +1,25 @@
+package polls
+
+import (
+	"fmt"
+
+	pollsdomain "family-app-go/internal/domain/polls"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req createPollRequest) Validate(v *validation.Validator) {
+	v.Required("question", req.Question)
+	v.MaxLength("question", req.Question, pollsdomain.MaxQuestionLength)
+	v.Check(len(req.Options) >= pollsdomain.MinOptions && len(req.Options) <= pollsdomain.MaxOptions,
+		"options", validation.CodeInvalid, fmt.Sprintf("a poll needs %d to %d options", pollsdomain.MinOptions, pollsdomain.MaxOptions))
+	for i, option := range req.Options {
+		field := fmt.Sprintf("options[%d]", i)
+		v.Required(field, option)
+		v.MaxLength(field, option, pollsdomain.MaxOptionLength)
+	}
+	v.Timestamp("deadline", req.Deadline)
+}
+
+func (req voteRequest) Validate(v *validation.Validator) {
+	v.Required("option_id", req.OptionID)
+}
@@ -14,8 +14,11 @@ import (
 
 	expensesdomain "family-app-go/internal/domain/expenses"
 	familydomain "family-app-go/internal/domain/family"
+	quotadomain "family-app-go/internal/domain/quota"
 	receiptsdomain "family-app-go/internal/domain/receipts"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -113,6 +116,9 @@ type approveExpenseRequest struct {
 type approveParseResponse struct {
 	Status   receiptsdomain.ParseStatus `json:"status"`
 	Expenses []expenseResponse          `json:"expenses"`
+	// ApprovalIDs are the expenses above the member's spending limit, queued
+	// for the owner's approval instead of created.
+	ApprovalIDs []string `json:"approval_ids"`
 }
 
 type expenseResponse struct {
@@ -147,7 +153,7 @@ func (h *Handlers) CreateParse(w http.ResponseWriter, r *http.Request) {
 
 	job, err := h.Receipts.CreateParse(r.Context(), input)
 	if err != nil {
-		h.writeServiceError(w, err, "receipt_parses.create", user.ID, family.ID, "")
+		h.writeServiceError(w, r, err, "receipt_parses.create", user.ID, family.ID, "")
 		return
 	}
 
@@ -167,7 +173,7 @@ func (h *Handlers) GetActiveParse(w http.ResponseWriter, r *http.Request) {
 
 	job, err := h.Receipts.GetActiveParse(r.Context(), family.ID)
 	if err != nil {
-		h.log.InternalError("receipt_parses.active: get active parse failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("receipt_parses.active: get active parse failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -199,7 +205,7 @@ func (h *Handlers) GetParse(w http.ResponseWriter, r *http.Request) {
 
 	job, err := h.Receipts.GetParse(r.Context(), family.ID, jobID)
 	if err != nil {
-		h.writeServiceError(w, err, "receipt_parses.get", user.ID, family.ID, jobID)
+		h.writeServiceError(w, r, err, "receipt_parses.get", user.ID, family.ID, jobID)
 		return
 	}
 
@@ -208,8 +214,7 @@ func (h *Handlers) GetParse(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) ApproveParse(w http.ResponseWriter, r *http.Request) {
 	var req approveParseRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -225,11 +230,7 @@ func (h *Handlers) ApproveParse(w http.ResponseWriter, r *http.Request) {
 
 	inputs := make([]receiptsdomain.ApproveExpenseInput, 0, len(req.Expenses))
 	for _, item := range req.Expenses {
-		date, err := parseDateRequired(item.Date)
-		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid_request", "invalid date")
-			return
-		}
+		date, _ := parseDateRequired(item.Date)
 		inputs = append(inputs, receiptsdomain.ApproveExpenseInput{
 			DraftID:     strings.TrimSpace(item.DraftID),
 			Date:        date,
@@ -240,15 +241,17 @@ func (h *Handlers) ApproveParse(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	created, err := h.Receipts.ApproveParse(r.Context(), receiptsdomain.ApproveInput{
+	created, approvals, err := h.Receipts.ApproveParse(r.Context(), receiptsdomain.ApproveInput{
 		FamilyID:     family.ID,
 		UserID:       user.ID,
 		BaseCurrency: family.DefaultCurrency,
 		JobID:        jobID,
 		Expenses:     inputs,
+
+		AllowedCurrencies: family.Currencies(),
 	})
 	if err != nil {
-		h.writeServiceError(w, err, "receipt_parses.approve", user.ID, family.ID, jobID)
+		h.writeServiceError(w, r, err, "receipt_parses.approve", user.ID, family.ID, jobID)
 		return
 	}
 
@@ -256,16 +259,20 @@ func (h *Handlers) ApproveParse(w http.ResponseWriter, r *http.Request) {
 	for _, expense := range created {
 		expenses = append(expenses, toExpenseResponse(expense))
 	}
+	approvalIDs := make([]string, 0, len(approvals))
+	for _, approval := range approvals {
+		approvalIDs = append(approvalIDs, approval.ID)
+	}
 	writeJSON(w, http.StatusOK, approveParseResponse{
-		Status:   receiptsdomain.StatusApproved,
-		Expenses: expenses,
+		Status:      receiptsdomain.StatusApproved,
+		Expenses:    expenses,
+		ApprovalIDs: approvalIDs,
 	})
 }
 
 func (h *Handlers) UpdateItems(w http.ResponseWriter, r *http.Request) {
 	var req updateItemsRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -294,7 +301,7 @@ func (h *Handlers) UpdateItems(w http.ResponseWriter, r *http.Request) {
 		Items:    items,
 	})
 	if err != nil {
-		h.writeServiceError(w, err, "receipt_parses.update_items", user.ID, family.ID, jobID)
+		h.writeServiceError(w, r, err, "receipt_parses.update_items", user.ID, family.ID, jobID)
 		return
 	}
 
@@ -314,7 +321,7 @@ func (h *Handlers) CancelParse(w http.ResponseWriter, r *http.Request) {
 
 	job, err := h.Receipts.CancelParse(r.Context(), family.ID, jobID)
 	if err != nil {
-		h.writeServiceError(w, err, "receipt_parses.cancel", user.ID, family.ID, jobID)
+		h.writeServiceError(w, r, err, "receipt_parses.cancel", user.ID, family.ID, jobID)
 		return
 	}
 
@@ -333,61 +340,60 @@ func (h *Handlers) currentUserFamily(w http.ResponseWriter, r *http.Request, ope
 		return middleware.User{}, nil, false
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError(operation+": family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return middleware.User{}, nil, false
-		}
-		h.log.InternalError(operation+": get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return middleware.User{}, nil, false
 	}
 
 	return user, family, true
 }
 
-func (h *Handlers) writeServiceError(w http.ResponseWriter, err error, operation, userID, familyID, jobID string) {
+func (h *Handlers) writeServiceError(w http.ResponseWriter, r *http.Request, err error, operation, userID, familyID, jobID string) {
 	switch {
 	case errors.Is(err, receiptsdomain.ErrReceiptParserDisabled):
-		h.log.BusinessError(operation+": parser disabled", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": parser disabled", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusServiceUnavailable, "receipt_parser_disabled", "receipt parser is disabled")
 	case errors.Is(err, receiptsdomain.ErrActiveReceiptParseExists):
-		h.log.BusinessError(operation+": active parse exists", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": active parse exists", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusConflict, "active_receipt_parse_exists", "active receipt parse already exists")
 	case errors.Is(err, receiptsdomain.ErrReceiptParseNotFound):
-		h.log.BusinessError(operation+": parse not found", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": parse not found", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusNotFound, "receipt_parse_not_found", "receipt parse not found")
 	case errors.Is(err, receiptsdomain.ErrReceiptParseInvalidStatus):
-		h.log.BusinessError(operation+": invalid status", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": invalid status", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusConflict, "receipt_parse_invalid_status", "receipt parse has invalid status")
 	case errors.Is(err, receiptsdomain.ErrInvalidReceiptFile):
-		h.log.BusinessError(operation+": invalid file", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": invalid file", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusBadRequest, "invalid_receipt_file", "invalid receipt file")
 	case errors.Is(err, receiptsdomain.ErrReceiptFileTooLarge):
-		h.log.BusinessError(operation+": file too large", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": file too large", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusRequestEntityTooLarge, "receipt_file_too_large", "receipt file is too large")
 	case errors.Is(err, receiptsdomain.ErrTooManyReceiptFiles):
-		h.log.BusinessError(operation+": too many files", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": too many files", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusBadRequest, "too_many_receipt_files", "too many receipt files")
 	case errors.Is(err, receiptsdomain.ErrCategorySelectionRequired):
-		h.log.BusinessError(operation+": category selection required", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": category selection required", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusBadRequest, "category_selection_required", "category selection is required")
 	case errors.Is(err, receiptsdomain.ErrCategoryNotFound):
-		h.log.BusinessError(operation+": category not found", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": category not found", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusNotFound, "category_not_found", "category not found")
 	case errors.Is(err, receiptsdomain.ErrReceiptParseEmpty):
-		h.log.BusinessError(operation+": parse empty", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": parse empty", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusUnprocessableEntity, "receipt_parse_empty", "receipt parse produced no draft expenses")
 	case errors.Is(err, receiptsdomain.ErrReceiptParseUnresolvedItems):
-		h.log.BusinessError(operation+": unresolved items", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": unresolved items", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusConflict, "receipt_parse_unresolved_items", "receipt parse has unresolved items")
 	case errors.Is(err, expensesdomain.ErrRateNotAvailable):
-		h.log.BusinessError(operation+": rate not available", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": rate not available", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusUnprocessableEntity, "rate_not_available", "rate is not available for selected date")
+	case errors.Is(err, expensesdomain.ErrInvalidCurrency):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": invalid currency", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		writeValidationError(w, validation.FieldErr("currency", validation.CodeCurrency, err.Error()))
+	case errors.Is(err, quotadomain.ErrExceeded):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": quota exceeded", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		writeQuotaExceeded(w, err)
 	default:
-		h.log.InternalError(operation+": request failed", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": request failed", err, "user_id", userID, "family_id", familyID, "job_id", jobID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 	}
 }
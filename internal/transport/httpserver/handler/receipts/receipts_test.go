@@ -276,27 +276,63 @@ func TestUpdateItemsReturnsUpdatedParse(t *testing.T) {
 	}
 }
 
+func TestUpdateItemsRejectsInvalidItemsWithFieldErrors(t *testing.T) {
+	h := newTestHandlers(newHandlerReceiptRepo())
+	req := authenticatedRequest(http.MethodPatch, "/api/receipt-parses/"+handlerJobID+"/items", strings.NewReader(`{"items":[{"id":"item-1","amount":1},{"id":" ","amount":-2}]}`))
+	req = withURLParam(req, "id", handlerJobID)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	h.UpdateItems(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Fields  []struct {
+				Field string `json:"field"`
+				Code  string `json:"code"`
+			} `json:"fields"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if payload.Error.Code != "invalid_request" || payload.Error.Message != "items[1].id is required" {
+		t.Fatalf("unexpected error: %#v", payload.Error)
+	}
+	if len(payload.Error.Fields) != 2 || payload.Error.Fields[0].Field != "items[1].id" || payload.Error.Fields[1].Field != "items[1].amount" {
+		t.Fatalf("unexpected fields: %#v", payload.Error.Fields)
+	}
+}
+
 func newTestHandlers(repo *handlerReceiptRepo) *Handlers {
-	families := familydomain.NewService(&handlerFamilyRepo{
-		family: &familydomain.Family{
-			ID:              handlerFamilyID,
-			Name:            "Family",
-			Code:            "ABC123",
-			OwnerID:         handlerUserID,
-			DefaultCurrency: "BYN",
-		},
-	})
 	receipts := receiptsdomain.NewServiceWithOptions(repo, receiptsdomain.NewMockParser(), handlerCategoryProvider{}, handlerExpenseBatchCreator{}, receiptsdomain.ServiceOptions{
 		FileStore:     newHandlerMemoryFileStore(),
 		WorkerEnabled: false,
 	})
-	return New(families, receipts, logger.New(io.Discard, slog.LevelError, "text"))
+	return New(receipts, logger.New(io.Discard, slog.LevelError, "text"))
 }
 
+func handlerFamily() *familydomain.Family {
+	return &familydomain.Family{
+		ID:              handlerFamilyID,
+		Name:            "Family",
+		OwnerID:         handlerUserID,
+		DefaultCurrency: "BYN",
+	}
+}
+
+// authenticatedRequest carries the user and family the auth and family
+// access middleware would put in the context.
 func authenticatedRequest(method, target string, body io.Reader) *http.Request {
 	req := httptest.NewRequest(method, target, body)
 	user := middleware.User{ID: handlerUserID, Email: "test@example.com"}
-	return req.WithContext(middleware.WithUser(req.Context(), user))
+	ctx := middleware.WithUser(req.Context(), user)
+	return req.WithContext(middleware.WithFamily(ctx, handlerFamily()))
 }
 
 func multipartReceiptBody(t *testing.T) (io.Reader, string) {
@@ -344,87 +380,6 @@ func withURLParam(req *http.Request, key, value string) *http.Request {
 	return req.WithContext(ctx)
 }
 
-type handlerFamilyRepo struct {
-	family *familydomain.Family
-}
-
-func (r *handlerFamilyRepo) Transaction(ctx context.Context, fn func(familydomain.Repository) error) error {
-	return fn(r)
-}
-
-func (r *handlerFamilyRepo) GetFamilyByUser(context.Context, string) (*familydomain.Family, error) {
-	family := *r.family
-	return &family, nil
-}
-
-func (r *handlerFamilyRepo) GetFamilyByCode(context.Context, string) (*familydomain.Family, error) {
-	return nil, familydomain.ErrFamilyNotFound
-}
-
-func (r *handlerFamilyRepo) GetMemberByUser(context.Context, string) (*familydomain.FamilyMember, error) {
-	return nil, familydomain.ErrFamilyNotFound
-}
-
-func (r *handlerFamilyRepo) GetMember(context.Context, string, string) (*familydomain.FamilyMember, error) {
-	return nil, familydomain.ErrFamilyNotFound
-}
-
-func (r *handlerFamilyRepo) ListMembers(context.Context, string) ([]familydomain.FamilyMember, error) {
-	return nil, nil
-}
-
-func (r *handlerFamilyRepo) ListMembersWithProfiles(context.Context, string) ([]familydomain.FamilyMemberProfile, error) {
-	return nil, nil
-}
-
-func (r *handlerFamilyRepo) CreateFamily(context.Context, *familydomain.Family) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) AddMember(context.Context, *familydomain.FamilyMember) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) UpdateFamilyName(context.Context, string, string) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) UpdateFamilyDefaultCurrency(context.Context, string, string) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) UpdateFamilyOwner(context.Context, string, string) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) UpdateMemberRole(context.Context, string, string, string) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) DeleteFamily(context.Context, string) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) DeleteMember(context.Context, string, string) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) DeleteMembersByFamily(context.Context, string) error {
-	return nil
-}
-
-func (r *handlerFamilyRepo) CountMembers(context.Context, string) (int64, error) {
-	return 1, nil
-}
-
-func (r *handlerFamilyRepo) IsUserInFamily(context.Context, string) (bool, error) {
-	return true, nil
-}
-
-func (r *handlerFamilyRepo) IsCodeTaken(context.Context, string) (bool, error) {
-	return false, nil
-}
-
 type handlerCategoryProvider struct{}
 
 func (handlerCategoryProvider) ListCategories(context.Context, string) ([]expensesdomain.Category, error) {
@@ -433,12 +388,12 @@ func (handlerCategoryProvider) ListCategories(context.Context, string) ([]expens
 
 type handlerExpenseBatchCreator struct{}
 
-func (handlerExpenseBatchCreator) CreateExpensesBatch(context.Context, []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, error) {
-	return nil, nil
+func (handlerExpenseBatchCreator) CreateExpensesBatch(context.Context, []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error) {
+	return nil, nil, nil
 }
 
-func (handlerExpenseBatchCreator) CreateExpensesBatchWithRepository(context.Context, expensesdomain.Repository, []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, error) {
-	return nil, nil
+func (handlerExpenseBatchCreator) CreateExpensesBatchWithRepository(context.Context, expensesdomain.Repository, []expensesdomain.CreateExpenseInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error) {
+	return nil, nil, nil
 }
 
 type handlerMemoryFileStore struct {
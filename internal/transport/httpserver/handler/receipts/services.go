@@ -0,0 +1,18 @@
+package receipts
+
+import (
+	"context"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	receiptsdomain "family-app-go/internal/domain/receipts"
+)
+
+// ReceiptsService is implemented by *receiptsdomain.Service.
+type ReceiptsService interface {
+	ApproveParse(ctx context.Context, input receiptsdomain.ApproveInput) ([]expensesdomain.ExpenseWithCategories, []expensesdomain.ExpenseApproval, error)
+	CancelParse(ctx context.Context, familyID, jobID string) (*receiptsdomain.Job, error)
+	CreateParse(ctx context.Context, input receiptsdomain.CreateParseInput) (*receiptsdomain.Job, error)
+	GetActiveParse(ctx context.Context, familyID string) (*receiptsdomain.Job, error)
+	GetParse(ctx context.Context, familyID, jobID string) (*receiptsdomain.JobWithDrafts, error)
+	UpdateItems(ctx context.Context, input receiptsdomain.UpdateItemsInput) (*receiptsdomain.JobWithDrafts, error)
+}
@@ -0,0 +1,33 @@
+package receipts
+
+import (
+	"fmt"
+
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req approveParseRequest) Validate(v *validation.Validator) {
+	for i, expense := range req.Expenses {
+		v.Nested(fmt.Sprintf("expenses[%d]", i), expense)
+	}
+}
+
+func (req approveExpenseRequest) Validate(v *validation.Validator) {
+	v.Required("date", req.Date)
+	v.Date("date", &req.Date)
+	v.Precision("amount", req.Amount, req.Currency)
+	v.Currency("currency", req.Currency)
+}
+
+func (req updateItemsRequest) Validate(v *validation.Validator) {
+	for i, item := range req.Items {
+		v.Nested(fmt.Sprintf("items[%d]", i), item)
+	}
+}
+
+func (req updateItemRequest) Validate(v *validation.Validator) {
+	v.Required("id", req.ID)
+	if req.Amount != nil {
+		v.Positive("amount", *req.Amount)
+	}
+}
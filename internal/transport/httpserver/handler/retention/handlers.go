@@ -0,0 +1,17 @@
+package retention
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Retention RetentionService
+	log       logger.Logger
+}
+
+func New(retention RetentionService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Retention: retention,
+		log:       log,
+	}
+}
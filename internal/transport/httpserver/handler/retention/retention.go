@@ -0,0 +1,151 @@
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	retentiondomain "family-app-go/internal/domain/retention"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+type updateRetentionPolicyRequest struct {
+	ExpensesMaxAgeDays    *int `json:"expenses_max_age_days"`
+	TodosArchiveAfterDays *int `json:"todos_archive_after_days"`
+}
+
+type retentionPolicyResponse struct {
+	ExpensesMaxAgeDays    *int       `json:"expenses_max_age_days"`
+	TodosArchiveAfterDays *int       `json:"todos_archive_after_days"`
+	LastRunAt             *time.Time `json:"last_run_at"`
+	UpdatedAt             *time.Time `json:"updated_at"`
+}
+
+type retentionPreviewResponse struct {
+	Expenses retentionPreviewRuleResponse `json:"expenses"`
+	Todos    retentionPreviewRuleResponse `json:"todos"`
+}
+
+type retentionPreviewRuleResponse struct {
+	Enabled  bool       `json:"enabled"`
+	Cutoff   *time.Time `json:"cutoff"`
+	Affected int64      `json:"affected"`
+}
+
+func (h *Handlers) GetRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	policy, err := h.Retention.GetPolicy(r.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, familydomain.ErrFamilyNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("retention.get: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("retention.get: get policy failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toRetentionPolicyResponse(policy))
+}
+
+func (h *Handlers) UpdateRetentionPolicy(w http.ResponseWriter, r *http.Request) {
+	var req updateRetentionPolicyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	policy, err := h.Retention.UpdatePolicy(r.Context(), user.ID, retentiondomain.UpdatePolicyInput{
+		ExpensesMaxAgeDays:    req.ExpensesMaxAgeDays,
+		TodosArchiveAfterDays: req.TodosArchiveAfterDays,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, familydomain.ErrFamilyNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError("retention.update: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+		case errors.Is(err, retentiondomain.ErrNotOwner):
+			commonhandler.RequestLog(r, h.log).BusinessError("retention.update: actor is not owner", err, "user_id", user.ID)
+			writeError(w, http.StatusForbidden, "not_owner", "only owner can change retention policy")
+		case errors.Is(err, retentiondomain.ErrInvalidExpensesMaxAge):
+			writeValidationError(w, validation.FieldErr("expenses_max_age_days", validation.CodeRange, fmt.Sprintf(
+				"expenses_max_age_days must be between %d and %d",
+				retentiondomain.MinExpensesMaxAgeDays,
+				retentiondomain.MaxExpensesMaxAgeDays,
+			)))
+		case errors.Is(err, retentiondomain.ErrInvalidTodosArchiveAfter):
+			writeValidationError(w, validation.FieldErr("todos_archive_after_days", validation.CodeRange, fmt.Sprintf(
+				"todos_archive_after_days must be between %d and %d",
+				retentiondomain.MinTodosArchiveAfterDays,
+				retentiondomain.MaxTodosArchiveAfterDays,
+			)))
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("retention.update: update policy failed", err, "user_id", user.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toRetentionPolicyResponse(policy))
+}
+
+func (h *Handlers) PreviewRetention(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	preview, err := h.Retention.Preview(r.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, familydomain.ErrFamilyNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("retention.preview: family not found", err, "user_id", user.ID)
+			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("retention.preview: preview failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, retentionPreviewResponse{
+		Expenses: retentionPreviewRuleResponse{
+			Enabled:  preview.ExpensesCutoff != nil,
+			Cutoff:   preview.ExpensesCutoff,
+			Affected: preview.ExpensesToDelete,
+		},
+		Todos: retentionPreviewRuleResponse{
+			Enabled:  preview.TodosCutoff != nil,
+			Cutoff:   preview.TodosCutoff,
+			Affected: preview.TodosToArchive,
+		},
+	})
+}
+
+func toRetentionPolicyResponse(policy *retentiondomain.Policy) retentionPolicyResponse {
+	response := retentionPolicyResponse{
+		ExpensesMaxAgeDays:    policy.ExpensesMaxAgeDays,
+		TodosArchiveAfterDays: policy.TodosArchiveAfterDays,
+		LastRunAt:             policy.LastRunAt,
+	}
+	if !policy.UpdatedAt.IsZero() {
+		updatedAt := policy.UpdatedAt
+		response.UpdatedAt = &updatedAt
+	}
+	return response
+}
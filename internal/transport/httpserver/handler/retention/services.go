@@ -0,0 +1,14 @@
+package retention
+
+import (
+	"context"
+
+	retentiondomain "family-app-go/internal/domain/retention"
+)
+
+// RetentionService is implemented by *retentiondomain.Service.
+type RetentionService interface {
+	GetPolicy(ctx context.Context, userID string) (*retentiondomain.Policy, error)
+	Preview(ctx context.Context, userID string) (*retentiondomain.Preview, error)
+	UpdatePolicy(ctx context.Context, userID string, input retentiondomain.UpdatePolicyInput) (*retentiondomain.Policy, error)
+}
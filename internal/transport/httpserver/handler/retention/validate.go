@@ -0,0 +1,11 @@
+package retention
+
+import (
+	retentiondomain "family-app-go/internal/domain/retention"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req updateRetentionPolicyRequest) Validate(v *validation.Validator) {
+	v.Between("expenses_max_age_days", req.ExpensesMaxAgeDays, retentiondomain.MinExpensesMaxAgeDays, retentiondomain.MaxExpensesMaxAgeDays)
+	v.Between("todos_archive_after_days", req.TodosArchiveAfterDays, retentiondomain.MinTodosArchiveAfterDays, retentiondomain.MaxTodosArchiveAfterDays)
+}
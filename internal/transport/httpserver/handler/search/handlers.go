@@ -0,0 +1,17 @@
+package search
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Search SearchService
+	log    logger.Logger
+}
+
+func New(search SearchService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Search: search,
+		log:    log,
+	}
+}
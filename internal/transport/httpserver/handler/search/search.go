@@ -0,0 +1,115 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	searchdomain "family-app-go/internal/domain/search"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+)
+
+type highlightResponse struct {
+	Field  string `json:"field"`
+	Text   string `json:"text"`
+	Start  int    `json:"start"`
+	Length int    `json:"length"`
+}
+
+type resultResponse struct {
+	Type       string              `json:"type"`
+	ID         string              `json:"id"`
+	Title      string              `json:"title"`
+	Date       *string             `json:"date,omitempty"`
+	ListID     *string             `json:"list_id,omitempty"`
+	Score      float64             `json:"score"`
+	Highlights []highlightResponse `json:"highlights"`
+}
+
+type searchResponse struct {
+	Query string           `json:"query"`
+	Items []resultResponse `json:"items"`
+}
+
+// Find looks the query up in the family's expenses, todo lists and items,
+// and workouts, and returns the matches ranked together.
+func (h *Handlers) Find(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	query := r.URL.Query()
+	text := strings.TrimSpace(query.Get("q"))
+	limit, err := parseIntParam(query.Get("limit"), searchdomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > searchdomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("limit must be between 1 and %d", searchdomain.MaxLimit))
+		return
+	}
+	var types []searchdomain.ResultType
+	for _, value := range parseCSV(query.Get("types")) {
+		types = append(types, searchdomain.ResultType(strings.ToLower(value)))
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	results, err := h.Search.Search(r.Context(), searchdomain.Query{
+		Text:     text,
+		UserID:   user.ID,
+		FamilyID: family.ID,
+		Child:    middleware.IsChild(r.Context()),
+		Types:    types,
+		Limit:    limit,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, searchdomain.ErrInvalidQuery):
+			writeError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("q must be %d to %d characters", searchdomain.MinQueryLength, searchdomain.MaxQueryLength))
+		case errors.Is(err, searchdomain.ErrInvalidType):
+			writeError(w, http.StatusBadRequest, "invalid_request", "types must be among expense, todo_list, todo_item, workout")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("search: search failed", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	response := searchResponse{Query: text, Items: make([]resultResponse, 0, len(results))}
+	for _, result := range results {
+		response.Items = append(response.Items, toResultResponse(result))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func toResultResponse(result searchdomain.Result) resultResponse {
+	response := resultResponse{
+		Type:       string(result.Type),
+		ID:         result.ID,
+		Title:      result.Title,
+		Score:      result.Score,
+		Highlights: make([]highlightResponse, 0, len(result.Highlights)),
+	}
+	if result.Date != nil {
+		date := result.Date.Format("2006-01-02")
+		response.Date = &date
+	}
+	if result.ListID != "" {
+		listID := result.ListID
+		response.ListID = &listID
+	}
+	for _, highlight := range result.Highlights {
+		response.Highlights = append(response.Highlights, highlightResponse{
+			Field:  highlight.Field,
+			Text:   highlight.Text,
+			Start:  highlight.Start,
+			Length: highlight.Length,
+		})
+	}
+	return response
+}
@@ -0,0 +1,12 @@
+package search
+
+import (
+	"context"
+
+	searchdomain "family-app-go/internal/domain/search"
+)
+
+// SearchService is implemented by *searchdomain.Service.
+type SearchService interface {
+	Search(ctx context.Context, query searchdomain.Query) ([]searchdomain.Result, error)
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	activitydomain "family-app-go/internal/domain/activity"
+	admindomain "family-app-go/internal/domain/admin"
+	analyticsdomain "family-app-go/internal/domain/analytics"
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	auditdomain "family-app-go/internal/domain/audit"
+	authdomain "family-app-go/internal/domain/auth"
+	batchdomain "family-app-go/internal/domain/batch"
+	budgetsdomain "family-app-go/internal/domain/budgets"
+	calendardomain "family-app-go/internal/domain/calendar"
+	commentsdomain "family-app-go/internal/domain/comments"
+	dashboarddomain "family-app-go/internal/domain/dashboard"
+	erasuredomain "family-app-go/internal/domain/erasure"
+	expensesdomain "family-app-go/internal/domain/expenses"
+	exportsdomain "family-app-go/internal/domain/exports"
+	familydomain "family-app-go/internal/domain/family"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	gymdomain "family-app-go/internal/domain/gym"
+	healthdomain "family-app-go/internal/domain/health"
+	labelsdomain "family-app-go/internal/domain/labels"
+	milestonesdomain "family-app-go/internal/domain/milestones"
+	notificationsdomain "family-app-go/internal/domain/notifications"
+	petsdomain "family-app-go/internal/domain/pets"
+	pollsdomain "family-app-go/internal/domain/polls"
+	ratesdomain "family-app-go/internal/domain/rates"
+	receiptsdomain "family-app-go/internal/domain/receipts"
+	retentiondomain "family-app-go/internal/domain/retention"
+	searchdomain "family-app-go/internal/domain/search"
+	sessionsdomain "family-app-go/internal/domain/sessions"
+	syncdomain "family-app-go/internal/domain/sync"
+	todosdomain "family-app-go/internal/domain/todos"
+	usagedomain "family-app-go/internal/domain/usage"
+	userdomain "family-app-go/internal/domain/user"
+	viewsdomain "family-app-go/internal/domain/views"
+	wishlistdomain "family-app-go/internal/domain/wishlist"
+	yearreviewdomain "family-app-go/internal/domain/yearreview"
+	adminhandler "family-app-go/internal/transport/httpserver/handler/admin"
+	apikeyshandler "family-app-go/internal/transport/httpserver/handler/apikeys"
+	authhandler "family-app-go/internal/transport/httpserver/handler/auth"
+	batchhandler "family-app-go/internal/transport/httpserver/handler/batch"
+	budgetshandler "family-app-go/internal/transport/httpserver/handler/budgets"
+	calendarhandler "family-app-go/internal/transport/httpserver/handler/calendar"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	dashboardhandler "family-app-go/internal/transport/httpserver/handler/dashboard"
+	erasurehandler "family-app-go/internal/transport/httpserver/handler/erasure"
+	expenseshandler "family-app-go/internal/transport/httpserver/handler/expenses"
+	exportshandler "family-app-go/internal/transport/httpserver/handler/exports"
+	featureflagshandler "family-app-go/internal/transport/httpserver/handler/featureflags"
+	gymhandler "family-app-go/internal/transport/httpserver/handler/gym"
+	mentionshandler "family-app-go/internal/transport/httpserver/handler/mentions"
+	milestoneshandler "family-app-go/internal/transport/httpserver/handler/milestones"
+	notificationshandler "family-app-go/internal/transport/httpserver/handler/notifications"
+	petshandler "family-app-go/internal/transport/httpserver/handler/pets"
+	pollshandler "family-app-go/internal/transport/httpserver/handler/polls"
+	receiptshandler "family-app-go/internal/transport/httpserver/handler/receipts"
+	retentionhandler "family-app-go/internal/transport/httpserver/handler/retention"
+	searchhandler "family-app-go/internal/transport/httpserver/handler/search"
+	sessionshandler "family-app-go/internal/transport/httpserver/handler/sessions"
+	todoshandler "family-app-go/internal/transport/httpserver/handler/todos"
+	viewshandler "family-app-go/internal/transport/httpserver/handler/views"
+	wishlisthandler "family-app-go/internal/transport/httpserver/handler/wishlist"
+	yearreviewhandler "family-app-go/internal/transport/httpserver/handler/yearreview"
+)
+
+// APIKeysService is everything the handlers use of *apikeysdomain.Service.
+type APIKeysService interface {
+	apikeyshandler.APIKeysService
+}
+
+// ActivityService is everything the handlers use of *activitydomain.Service.
+type ActivityService interface {
+	commonhandler.ActivityService
+	expenseshandler.ActivityService
+	todoshandler.ActivityService
+}
+
+// AdminService is everything the handlers use of *admindomain.Service.
+type AdminService interface {
+	adminhandler.AdminService
+}
+
+// AnalyticsService is everything the handlers use of *analyticsdomain.Service.
+type AnalyticsService interface {
+	expenseshandler.AnalyticsService
+}
+
+// AuditService is everything the handlers use of *auditdomain.Service.
+type AuditService interface {
+	adminhandler.AuditService
+	commonhandler.AuditRecorder
+}
+
+// AuthService is everything the handlers use of *authdomain.Service.
+type AuthService interface {
+	authhandler.AuthService
+}
+
+// BatchService is everything the handlers use of *batchdomain.Service.
+type BatchService interface {
+	batchhandler.BatchService
+}
+
+// BudgetsService is everything the handlers use of *budgetsdomain.Service.
+type BudgetsService interface {
+	budgetshandler.BudgetsService
+}
+
+// CalendarService is everything the handlers use of *calendardomain.Service.
+type CalendarService interface {
+	calendarhandler.CalendarService
+}
+
+// CommentsService is everything the handlers use of *commentsdomain.Service.
+type CommentsService interface {
+	expenseshandler.CommentsService
+	mentionshandler.CommentsService
+	todoshandler.CommentsService
+}
+
+// DashboardService is everything the handlers use of *dashboarddomain.Service.
+type DashboardService interface {
+	dashboardhandler.DashboardService
+}
+
+// ErasureService is everything the handlers use of *erasuredomain.Service.
+type ErasureService interface {
+	erasurehandler.ErasureService
+}
+
+// ExpensesService is everything the handlers use of *expensesdomain.Service.
+type ExpensesService interface {
+	expenseshandler.ExpensesService
+}
+
+// ExportsService is everything the handlers use of *exportsdomain.Service.
+type ExportsService interface {
+	exportshandler.ExportsService
+}
+
+// FamilyService is everything the handlers use of *familydomain.Service.
+type FamilyService interface {
+	commonhandler.FamilyService
+	todoshandler.FamilyService
+}
+
+// FavoritesService is everything the handlers use of *favoritesdomain.Service.
+type FavoritesService interface {
+	expenseshandler.FavoritesService
+	gymhandler.FavoritesService
+	todoshandler.FavoritesService
+}
+
+// FeatureFlagsService is everything the handlers use of *featureflagsdomain.Service.
+type FeatureFlagsService interface {
+	commonhandler.FeatureFlagsService
+	expenseshandler.FeatureFlagsService
+	featureflagshandler.FeatureFlagsService
+}
+
+// GymService is everything the handlers use of *gymdomain.Service.
+type GymService interface {
+	gymhandler.GymService
+}
+
+// HealthService is everything the handlers use of *healthdomain.Service.
+type HealthService interface {
+	commonhandler.HealthService
+}
+
+// LabelsService is everything the handlers use of *labelsdomain.Service.
+type LabelsService interface {
+	gymhandler.LabelsService
+	todoshandler.LabelsService
+}
+
+// MilestonesService is everything the handlers use of *milestonesdomain.Service.
+type MilestonesService interface {
+	milestoneshandler.MilestonesService
+}
+
+// NotificationsService is everything the handlers use of *notificationsdomain.Service.
+type NotificationsService interface {
+	notificationshandler.NotificationsService
+}
+
+// PetsService is everything the handlers use of *petsdomain.Service.
+type PetsService interface {
+	petshandler.PetsService
+}
+
+// PollsService is everything the handlers use of *pollsdomain.Service.
+type PollsService interface {
+	pollshandler.PollsService
+}
+
+// RatesService is everything the handlers use of *ratesdomain.Service.
+type RatesService interface {
+	expenseshandler.RatesService
+}
+
+// ReceiptsService is everything the handlers use of *receiptsdomain.Service.
+type ReceiptsService interface {
+	receiptshandler.ReceiptsService
+}
+
+// RetentionService is everything the handlers use of *retentiondomain.Service.
+type RetentionService interface {
+	retentionhandler.RetentionService
+}
+
+// SearchService is everything the handlers use of *searchdomain.Service.
+type SearchService interface {
+	searchhandler.SearchService
+}
+
+// SessionsService is everything the handlers use of *sessionsdomain.Service.
+type SessionsService interface {
+	sessionshandler.SessionsService
+}
+
+// SyncService is everything the handlers use of *syncdomain.Service.
+type SyncService interface {
+	commonhandler.SyncService
+}
+
+// TodosService is everything the handlers use of *todosdomain.Service.
+type TodosService interface {
+	expenseshandler.TodosService
+	todoshandler.TodosService
+}
+
+// UsageService is everything the handlers use of *usagedomain.Service.
+type UsageService interface {
+	adminhandler.UsageService
+}
+
+// UserService is everything the handlers use of *userdomain.Service.
+type UserService interface {
+	commonhandler.UserService
+}
+
+// ViewsService is everything the handlers use of *viewsdomain.Service.
+type ViewsService interface {
+	viewshandler.ViewsService
+}
+
+// WishlistService is everything the handlers use of *wishlistdomain.Service.
+type WishlistService interface {
+	wishlisthandler.WishlistService
+}
+
+// YearReviewService is everything the handlers use of *yearreviewdomain.Service.
+type YearReviewService interface {
+	yearreviewhandler.YearReviewService
+}
+
+var (
+	_ APIKeysService       = (*apikeysdomain.Service)(nil)
+	_ ActivityService      = (*activitydomain.Service)(nil)
+	_ AdminService         = (*admindomain.Service)(nil)
+	_ AnalyticsService     = (*analyticsdomain.Service)(nil)
+	_ AuditService         = (*auditdomain.Service)(nil)
+	_ AuthService          = (*authdomain.Service)(nil)
+	_ BatchService         = (*batchdomain.Service)(nil)
+	_ BudgetsService       = (*budgetsdomain.Service)(nil)
+	_ CalendarService      = (*calendardomain.Service)(nil)
+	_ CommentsService      = (*commentsdomain.Service)(nil)
+	_ DashboardService     = (*dashboarddomain.Service)(nil)
+	_ ErasureService       = (*erasuredomain.Service)(nil)
+	_ ExpensesService      = (*expensesdomain.Service)(nil)
+	_ ExportsService       = (*exportsdomain.Service)(nil)
+	_ FamilyService        = (*familydomain.Service)(nil)
+	_ FavoritesService     = (*favoritesdomain.Service)(nil)
+	_ FeatureFlagsService  = (*featureflagsdomain.Service)(nil)
+	_ GymService           = (*gymdomain.Service)(nil)
+	_ HealthService        = (*healthdomain.Service)(nil)
+	_ LabelsService        = (*labelsdomain.Service)(nil)
+	_ MilestonesService    = (*milestonesdomain.Service)(nil)
+	_ NotificationsService = (*notificationsdomain.Service)(nil)
+	_ PetsService          = (*petsdomain.Service)(nil)
+	_ PollsService         = (*pollsdomain.Service)(nil)
+	_ RatesService         = (*ratesdomain.Service)(nil)
+	_ ReceiptsService      = (*receiptsdomain.Service)(nil)
+	_ RetentionService     = (*retentiondomain.Service)(nil)
+	_ SearchService        = (*searchdomain.Service)(nil)
+	_ SessionsService      = (*sessionsdomain.Service)(nil)
+	_ SyncService          = (*syncdomain.Service)(nil)
+	_ TodosService         = (*todosdomain.Service)(nil)
+	_ UsageService         = (*usagedomain.Service)(nil)
+	_ UserService          = (*userdomain.Service)(nil)
+	_ ViewsService         = (*viewsdomain.Service)(nil)
+	_ WishlistService      = (*wishlistdomain.Service)(nil)
+	_ YearReviewService    = (*yearreviewdomain.Service)(nil)
+)
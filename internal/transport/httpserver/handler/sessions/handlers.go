@@ -0,0 +1,20 @@
+package sessions
+
+import (
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Sessions SessionsService
+	Audit    commonhandler.AuditRecorder
+	log      logger.Logger
+}
+
+func New(sessions SessionsService, audit commonhandler.AuditRecorder, log logger.Logger) *Handlers {
+	return &Handlers{
+		Sessions: sessions,
+		Audit:    audit,
+		log:      log,
+	}
+}
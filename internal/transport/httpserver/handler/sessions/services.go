@@ -0,0 +1,14 @@
+package sessions
+
+import (
+	"context"
+
+	sessionsdomain "family-app-go/internal/domain/sessions"
+)
+
+// SessionsService is implemented by *sessionsdomain.Service.
+type SessionsService interface {
+	ListSessions(ctx context.Context, userID string) ([]sessionsdomain.Session, error)
+	RevokeOtherSessions(ctx context.Context, userID, keepID string) (int, error)
+	RevokeSession(ctx context.Context, userID, id string) error
+}
@@ -0,0 +1,142 @@
+package sessions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	auditdomain "family-app-go/internal/domain/audit"
+	sessionsdomain "family-app-go/internal/domain/sessions"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/id"
+	"github.com/go-chi/chi/v5"
+)
+
+type sessionResponse struct {
+	ID         string     `json:"id"`
+	DeviceName *string    `json:"device_name"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	Current    bool       `json:"current"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+type sessionsListResponse struct {
+	Items []sessionResponse `json:"items"`
+}
+
+type revokeOtherSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+func (h *Handlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.Sessions.ListSessions(r.Context(), user.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("sessions.list: list sessions failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	currentID, _ := middleware.SessionIDFromContext(r.Context())
+	items := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, toSessionResponse(session, currentID))
+	}
+	writeJSON(w, http.StatusOK, sessionsListResponse{Items: items})
+}
+
+// RevokeSession logs a device out. Revoking the current session logs the
+// caller out.
+func (h *Handlers) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	sessionID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if !id.IsUUID(sessionID) {
+		writeError(w, http.StatusNotFound, "session_not_found", "session not found")
+		return
+	}
+
+	if err := h.Sessions.RevokeSession(r.Context(), user.ID, sessionID); err != nil {
+		if errors.Is(err, sessionsdomain.ErrSessionNotFound) {
+			writeError(w, http.StatusNotFound, "session_not_found", "session not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("sessions.revoke: revoke session failed", err, "user_id", user.ID, "session_id", sessionID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventSessionRevoked, func(input *auditdomain.Input) {
+		input.TargetID = sessionID
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOtherSessions logs out every device but the caller's.
+func (h *Handlers) RevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.sessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	currentID, ok := middleware.SessionIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusConflict, "session_unknown", "the current session is not tracked; sign in again and retry")
+		return
+	}
+
+	revoked, err := h.Sessions.RevokeOtherSessions(r.Context(), user.ID, currentID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("sessions.revoke_others: revoke sessions failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	if revoked > 0 {
+		commonhandler.RecordSecurityEvent(r, h.Audit, h.log, auditdomain.EventSessionRevoked, func(input *auditdomain.Input) {
+			input.Details = map[string]string{"kept_session_id": currentID, "revoked": strconv.Itoa(revoked)}
+		})
+	}
+
+	writeJSON(w, http.StatusOK, revokeOtherSessionsResponse{Revoked: revoked})
+}
+
+// sessionUser rejects requests made with an API key: sessions belong to
+// access tokens, and a key must not be able to log its owner out.
+func (h *Handlers) sessionUser(w http.ResponseWriter, r *http.Request) (middleware.User, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, false
+	}
+	if _, viaKey := middleware.APIKeyFromContext(r.Context()); viaKey {
+		writeError(w, http.StatusForbidden, "api_key_not_allowed", "api keys cannot manage sessions")
+		return middleware.User{}, false
+	}
+	return user, true
+}
+
+func toSessionResponse(session sessionsdomain.Session, currentID string) sessionResponse {
+	return sessionResponse{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		Current:    session.ID == currentID,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
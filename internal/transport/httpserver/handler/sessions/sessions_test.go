@@ -0,0 +1,201 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auditdomain "family-app-go/internal/domain/audit"
+	sessionsdomain "family-app-go/internal/domain/sessions"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	testUserID    = "22222222-2222-2222-2222-222222222222"
+	testSessionID = "77777777-7777-7777-7777-777777777777"
+	otherSession  = "88888888-8888-8888-8888-888888888888"
+)
+
+var errDatabase = errors.New("database is down")
+
+type mockSessions struct {
+	sessions []sessionsdomain.Session
+	revoked  int
+	err      error
+	kept     string
+}
+
+func (m *mockSessions) ListSessions(context.Context, string) ([]sessionsdomain.Session, error) {
+	return m.sessions, m.err
+}
+
+func (m *mockSessions) RevokeOtherSessions(_ context.Context, _ string, keepID string) (int, error) {
+	m.kept = keepID
+	return m.revoked, m.err
+}
+
+func (m *mockSessions) RevokeSession(context.Context, string, string) error {
+	return m.err
+}
+
+type mockAudit struct {
+	events []auditdomain.Input
+}
+
+func (m *mockAudit) Record(_ context.Context, input auditdomain.Input) error {
+	m.events = append(m.events, input)
+	return nil
+}
+
+func newTestHandlers(sessions *mockSessions, audit *mockAudit) *Handlers {
+	return New(sessions, audit, logger.New(io.Discard, slog.LevelError, "text"))
+}
+
+// sessionRequest is a request made with an access token of testSessionID.
+func sessionRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	ctx := middleware.WithUser(req.Context(), middleware.User{ID: testUserID})
+	return req.WithContext(middleware.WithSessionID(ctx, testSessionID))
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var payload struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error response %q: %v", rec.Body.String(), err)
+	}
+	return payload.Error.Code
+}
+
+func TestListSessionsMarksCurrent(t *testing.T) {
+	now := time.Now().UTC()
+	sessions := &mockSessions{sessions: []sessionsdomain.Session{
+		{ID: testSessionID, UserAgent: "phone", CreatedAt: now, LastSeenAt: now},
+		{ID: otherSession, UserAgent: "laptop", CreatedAt: now, LastSeenAt: now},
+	}}
+	rec := httptest.NewRecorder()
+	newTestHandlers(sessions, &mockAudit{}).ListSessions(rec, sessionRequest(http.MethodGet, "/api/me/sessions"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var body sessionsListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Items) != 2 || !body.Items[0].Current || body.Items[1].Current {
+		t.Fatalf("expected only the first session current, got %+v", body.Items)
+	}
+}
+
+func TestListSessionsErrors(t *testing.T) {
+	h := newTestHandlers(&mockSessions{err: errDatabase}, &mockAudit{})
+
+	rec := httptest.NewRecorder()
+	h.ListSessions(rec, httptest.NewRequest(http.MethodGet, "/api/me/sessions", nil))
+	if rec.Code != http.StatusUnauthorized || errorCode(t, rec) != "invalid_token" {
+		t.Fatalf("expected 401 invalid_token, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req := sessionRequest(http.MethodGet, "/api/me/sessions")
+	rec = httptest.NewRecorder()
+	h.ListSessions(rec, req.WithContext(middleware.WithAPIKey(req.Context(), middleware.APIKey{ID: "key"})))
+	if rec.Code != http.StatusForbidden || errorCode(t, rec) != "api_key_not_allowed" {
+		t.Fatalf("expected 403 api_key_not_allowed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ListSessions(rec, sessionRequest(http.MethodGet, "/api/me/sessions"))
+	if rec.Code != http.StatusInternalServerError || errorCode(t, rec) != "internal_error" {
+		t.Fatalf("expected 500 internal_error, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	cases := []struct {
+		name      string
+		sessionID string
+		err       error
+		status    int
+		code      string
+	}{
+		{name: "malformed id", sessionID: "current", status: http.StatusNotFound, code: "session_not_found"},
+		{name: "not found", sessionID: otherSession, err: sessionsdomain.ErrSessionNotFound, status: http.StatusNotFound, code: "session_not_found"},
+		{name: "internal", sessionID: otherSession, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "revoked", sessionID: otherSession, status: http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			audit := &mockAudit{}
+			req := withURLParam(sessionRequest(http.MethodPost, "/api/me/sessions/"+tc.sessionID+"/revoke"), "id", tc.sessionID)
+			rec := httptest.NewRecorder()
+			newTestHandlers(&mockSessions{err: tc.err}, audit).RevokeSession(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			if len(audit.events) != 1 || audit.events[0].Type != auditdomain.EventSessionRevoked || audit.events[0].TargetID != otherSession {
+				t.Fatalf("unexpected audit events: %+v", audit.events)
+			}
+		})
+	}
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/me/sessions/revoke-others", nil)
+	req = req.WithContext(middleware.WithUser(req.Context(), middleware.User{ID: testUserID}))
+	rec := httptest.NewRecorder()
+	newTestHandlers(&mockSessions{}, &mockAudit{}).RevokeOtherSessions(rec, req)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != "session_unknown" {
+		t.Fatalf("expected 409 session_unknown without a tracked session, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	newTestHandlers(&mockSessions{err: errDatabase}, &mockAudit{}).RevokeOtherSessions(rec, sessionRequest(http.MethodPost, "/api/me/sessions/revoke-others"))
+	if rec.Code != http.StatusInternalServerError || errorCode(t, rec) != "internal_error" {
+		t.Fatalf("expected 500 internal_error, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, revoked := range []int{0, 2} {
+		sessions := &mockSessions{revoked: revoked}
+		audit := &mockAudit{}
+		rec = httptest.NewRecorder()
+		newTestHandlers(sessions, audit).RevokeOtherSessions(rec, sessionRequest(http.MethodPost, "/api/me/sessions/revoke-others"))
+
+		var body revokeOtherSessionsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if rec.Code != http.StatusOK || body.Revoked != revoked || sessions.kept != testSessionID {
+			t.Fatalf("expected %d revoked keeping the current session, got %d %+v kept %q", revoked, rec.Code, body, sessions.kept)
+		}
+		// Nothing revoked is not worth an audit event.
+		if wantEvents := min(revoked, 1); len(audit.events) != wantEvents {
+			t.Fatalf("expected %d audit events, got %+v", wantEvents, audit.events)
+		}
+	}
+}
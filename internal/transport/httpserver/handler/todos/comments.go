@@ -0,0 +1,241 @@
+package todos
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	commentsdomain "family-app-go/internal/domain/comments"
+	familydomain "family-app-go/internal/domain/family"
+	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type commentRequest struct {
+	Body string `json:"body"`
+}
+
+func (req commentRequest) Validate(v *validation.Validator) {
+	v.Required("body", req.Body)
+	v.MaxLength("body", req.Body, commentsdomain.MaxBodyLength)
+}
+
+type commentAuthorResponse struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Email     string  `json:"email"`
+	AvatarURL *string `json:"avatar_url"`
+}
+
+type commentResponse struct {
+	ID        string                `json:"id"`
+	ItemID    string                `json:"item_id"`
+	Author    commentAuthorResponse `json:"author"`
+	Body      string                `json:"body"`
+	Edited    bool                  `json:"edited"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+type commentListResponse struct {
+	Items []commentResponse `json:"items"`
+	Total int64             `json:"total"`
+}
+
+// commentedItem is a todo item the caller sees, with the members of its
+// list's audience; see todosdomain.Service.ItemAudience.
+type commentedItem struct {
+	user     middleware.User
+	family   *familydomain.Family
+	item     *todosdomain.TodoItem
+	audience []string
+}
+
+func (c commentedItem) thread() commentsdomain.Thread {
+	return commentsdomain.Thread{FamilyID: c.family.ID, EntityType: commentsdomain.EntityTodoItem, EntityID: c.item.ID}
+}
+
+// ListTodoItemComments returns an item's thread, oldest first, and marks it
+// read for the caller.
+func (h *Handlers) ListTodoItemComments(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.commentedItem(w, r, "todos.comments.list")
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := parseIntParam(query.Get("limit"), commentsdomain.DefaultLimit)
+	if err != nil || limit <= 0 || limit > commentsdomain.MaxLimit {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid offset")
+		return
+	}
+
+	items, total, err := h.Comments.List(r.Context(), target.thread(), target.user.ID, commentsdomain.ListFilter{Limit: limit, Offset: offset})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.comments.list: list comments failed", err, "user_id", target.user.ID, "family_id", target.family.ID, "item_id", target.item.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := commentListResponse{Items: make([]commentResponse, 0, len(items)), Total: total}
+	for _, comment := range items {
+		response.Items = append(response.Items, toCommentResponse(comment))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// CreateTodoItemComment adds the caller's comment to a todo item. Members
+// it mentions with @handle find it in their mention inbox if they can see
+// the item's list.
+func (h *Handlers) CreateTodoItemComment(w http.ResponseWriter, r *http.Request) {
+	var req commentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	target, ok := h.commentedItem(w, r, "todos.comments.create")
+	if !ok {
+		return
+	}
+
+	actor := actorFromUser(target.user)
+	comment, err := h.Comments.Create(r.Context(), commentsdomain.CreateInput{
+		FamilyID:   target.family.ID,
+		EntityType: commentsdomain.EntityTodoItem,
+		EntityID:   target.item.ID,
+		Author: commentsdomain.Author{
+			ID:        actor.ID,
+			Name:      actor.Name,
+			Email:     actor.Email,
+			AvatarURL: actor.AvatarURL,
+		},
+		Body:     req.Body,
+		Audience: target.audience,
+	})
+	if err != nil {
+		h.writeCommentError(w, r, "todos.comments.create", err, "user_id", target.user.ID, "family_id", target.family.ID, "item_id", target.item.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toCommentResponse(*comment))
+}
+
+// UpdateTodoItemComment edits the caller's own comment.
+func (h *Handlers) UpdateTodoItemComment(w http.ResponseWriter, r *http.Request) {
+	var req commentRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	target, ok := h.commentedItem(w, r, "todos.comments.update")
+	if !ok {
+		return
+	}
+	commentID := strings.TrimSpace(chi.URLParam(r, "comment_id"))
+
+	comment, err := h.Comments.Update(r.Context(), commentsdomain.UpdateInput{
+		Thread:    target.thread(),
+		CommentID: commentID,
+		AuthorID:  target.user.ID,
+		Body:      req.Body,
+		Audience:  target.audience,
+	})
+	if err != nil {
+		h.writeCommentError(w, r, "todos.comments.update", err, "user_id", target.user.ID, "family_id", target.family.ID, "comment_id", commentID)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCommentResponse(*comment))
+}
+
+// DeleteTodoItemComment removes the caller's own comment.
+func (h *Handlers) DeleteTodoItemComment(w http.ResponseWriter, r *http.Request) {
+	target, ok := h.commentedItem(w, r, "todos.comments.delete")
+	if !ok {
+		return
+	}
+	commentID := strings.TrimSpace(chi.URLParam(r, "comment_id"))
+
+	if err := h.Comments.Delete(r.Context(), target.thread(), commentID, target.user.ID); err != nil {
+		h.writeCommentError(w, r, "todos.comments.delete", err, "user_id", target.user.ID, "family_id", target.family.ID, "comment_id", commentID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// commentedItem resolves the caller and the todo item in the path, which
+// must be on a list they see.
+func (h *Handlers) commentedItem(w http.ResponseWriter, r *http.Request, operation string) (commentedItem, bool) {
+	itemID := strings.TrimSpace(chi.URLParam(r, "item_id"))
+	if itemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "item_id is required")
+		return commentedItem{}, false
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return commentedItem{}, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return commentedItem{}, false
+	}
+
+	item, audience, err := h.Todos.ItemAudience(r.Context(), family.ID, itemID, user.ID)
+	if err != nil {
+		if errors.Is(err, todosdomain.ErrTodoItemNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError(operation+": todo item not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusNotFound, "todo_item_not_found", "todo item not found")
+			return commentedItem{}, false
+		}
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": get todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return commentedItem{}, false
+	}
+	return commentedItem{user: user, family: family, item: item, audience: audience}, true
+}
+
+func (h *Handlers) writeCommentError(w http.ResponseWriter, r *http.Request, operation string, err error, args ...any) {
+	switch {
+	case errors.Is(err, commentsdomain.ErrCommentNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": comment not found", err, args...)
+		writeError(w, http.StatusNotFound, "comment_not_found", "comment not found")
+	case errors.Is(err, commentsdomain.ErrNotAuthor):
+		commonhandler.RequestLog(r, h.log).BusinessError(operation+": not the author", err, args...)
+		writeError(w, http.StatusForbidden, "not_comment_author", "only the author can change a comment")
+	case errors.Is(err, commentsdomain.ErrInvalidBody):
+		writeValidationError(w, validation.FieldErr("body", validation.CodeInvalid, "body must be 1 to 2000 characters"))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": failed", err, args...)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+func toCommentResponse(comment commentsdomain.Comment) commentResponse {
+	return commentResponse{
+		ID:     comment.ID,
+		ItemID: comment.EntityID,
+		Author: commentAuthorResponse{
+			ID:        comment.AuthorID,
+			Name:      comment.AuthorName,
+			Email:     comment.AuthorEmail,
+			AvatarURL: comment.AuthorAvatarURL,
+		},
+		Body:      comment.Body,
+		Edited:    comment.UpdatedAt.After(comment.CreatedAt),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
@@ -0,0 +1,73 @@
+package todos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	todosdomain "family-app-go/internal/domain/todos"
+	"family-app-go/pkg/familytest"
+)
+
+// The contract tests snapshot the JSON the mobile clients read. The
+// fixtures set every field, so that omitempty fields are in the golden
+// files too.
+
+// contractItem is testItem completed, assigned, priced and paid.
+func contractItem() todosdomain.TodoItem {
+	completedAt := time.Date(2026, 10, 2, 18, 0, 0, 0, time.UTC)
+	dueDate := time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)
+	assignee, name, email, avatar := testMemberID, "Sam", "sam@example.com", "https://example.com/sam.png"
+	price, expenseID := 2.5, "66666666-6666-6666-6666-666666666666"
+
+	item := testItem()
+	item.IsCompleted = true
+	item.CompletedAt = &completedAt
+	item.DueDate = &dueDate
+	item.AssigneeID = &assignee
+	item.CompletedByID = &assignee
+	item.CompletedByName = &name
+	item.CompletedByEmail = &email
+	item.CompletedByAvatarURL = &avatar
+	item.EstimatedPrice = &price
+	item.ExpenseID = &expenseID
+	return item
+}
+
+func TestTodoListListContract(t *testing.T) {
+	deps := newTestDeps()
+	deps.todos.lists = []todosdomain.ListWithItems{{
+		List: todosdomain.TodoList{
+			ID:               testListID,
+			FamilyID:         testFamilyID,
+			Title:            "Groceries",
+			ArchiveCompleted: true,
+			IsCollapsed:      true,
+			IsPrivate:        true,
+			Order:            2,
+			Version:          3,
+			CreatedAt:        time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		},
+		Counts: todosdomain.ListItemCounts{
+			ItemsTotal:       4,
+			ItemsCompleted:   1,
+			ItemsArchived:    1,
+			PlannedTotal:     10,
+			PlannedPurchased: 2.5,
+			ActualTotal:      2.4,
+			ItemsLinked:      1,
+		},
+		Items:     []todosdomain.TodoItem{contractItem()},
+		ViewerIDs: []string{testUserID, testMemberID},
+	}}
+	deps.favorites.ids = map[string]bool{testListID: true}
+	deps.labels.labels = map[string][]string{testItemID: {"urgent"}}
+	rec := httptest.NewRecorder()
+	deps.handlers().ListTodoLists(rec, familyRequest(http.MethodGet, "/api/todo-lists?include_items=true", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	familytest.Golden(t, "todo_list_list", rec.Body.Bytes())
+}
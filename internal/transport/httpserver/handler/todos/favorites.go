@@ -0,0 +1,83 @@
+package todos
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// FavoriteTodoList pins a list for the caller. Pinning is personal and does
+// not change the list for the rest of the family.
+func (h *Handlers) FavoriteTodoList(w http.ResponseWriter, r *http.Request) {
+	h.setTodoListFavorite(w, r, "todos.favorite_list", true)
+}
+
+func (h *Handlers) UnfavoriteTodoList(w http.ResponseWriter, r *http.Request) {
+	h.setTodoListFavorite(w, r, "todos.unfavorite_list", false)
+}
+
+func (h *Handlers) setTodoListFavorite(w http.ResponseWriter, r *http.Request, op string, favorite bool) {
+	listID := strings.TrimSpace(chi.URLParam(r, "list_id"))
+	if listID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "list_id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	if favorite {
+		if _, err := h.Todos.GetTodoList(r.Context(), family.ID, listID, user.ID); err != nil {
+			if errors.Is(err, todosdomain.ErrTodoListNotFound) {
+				commonhandler.RequestLog(r, h.log).BusinessError(op+": todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+				writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
+				return
+			}
+			commonhandler.RequestLog(r, h.log).InternalError(op+": get todo list failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+		err = h.Favorites.Add(r.Context(), user.ID, favoritesdomain.EntityTodoList, listID)
+	} else {
+		err = h.Favorites.Remove(r.Context(), user.ID, favoritesdomain.EntityTodoList, listID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, favoritesdomain.ErrInvalidEntityID):
+			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
+		case errors.Is(err, favoritesdomain.ErrTooManyFavorites):
+			commonhandler.RequestLog(r, h.log).BusinessError(op+": too many favorites", err, "user_id", user.ID, "list_id", listID)
+			writeError(w, http.StatusConflict, "too_many_favorites", "too many favorite todo lists")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError(op+": update favorite failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// isFavoriteList reports whether the user pinned the list.
+func (h *Handlers) isFavoriteList(r *http.Request, userID, listID string) (bool, error) {
+	ids, err := h.Favorites.IDs(r.Context(), userID, favoritesdomain.EntityTodoList)
+	if err != nil {
+		return false, err
+	}
+	return ids[listID], nil
+}
@@ -1,21 +1,30 @@
 package todos
 
 import (
-	familydomain "family-app-go/internal/domain/family"
-	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/pkg/logger"
 )
 
 type Handlers struct {
-	Families *familydomain.Service
-	Todos    *todosdomain.Service
-	log      logger.Logger
+	Families  FamilyService
+	Todos     TodosService
+	Activity  ActivityService
+	Labels    LabelsService
+	Favorites FavoritesService
+	Comments  CommentsService
+	limits    commonhandler.ListLimits
+	log       logger.Logger
 }
 
-func New(families *familydomain.Service, todos *todosdomain.Service, log logger.Logger) *Handlers {
+func New(families FamilyService, todos TodosService, activity ActivityService, labels LabelsService, favorites FavoritesService, comments CommentsService, limits commonhandler.ListLimits, log logger.Logger) *Handlers {
 	return &Handlers{
-		Families: families,
-		Todos:    todos,
-		log:      log,
+		Families:  families,
+		Todos:     todos,
+		Activity:  activity,
+		Labels:    labels,
+		Favorites: favorites,
+		Comments:  comments,
+		limits:    limits,
+		log:       log,
 	}
 }
@@ -4,19 +4,36 @@ import (
 	"net/http"
 	"time"
 
+	activitydomain "family-app-go/internal/domain/activity"
+	familydomain "family-app-go/internal/domain/family"
 	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
 )
 
+// optionalNullableString is shared with the other handlers that accept
+// PATCH-style bodies.
+type optionalNullableString = commonhandler.OptionalNullableString
+
+type optionalNullableFloat = commonhandler.OptionalNullableFloat
+
 func writeError(w http.ResponseWriter, status int, code, message string) {
 	commonhandler.WriteError(w, status, code, message)
 }
 
+func writeQuotaExceeded(w http.ResponseWriter, err error) {
+	commonhandler.WriteQuotaExceeded(w, err)
+}
+
+func requireFamily(w http.ResponseWriter, r *http.Request) (*familydomain.Family, bool) {
+	return commonhandler.RequireFamily(w, r)
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 	commonhandler.WriteJSON(w, status, payload)
 }
 
-func decodeJSON(r *http.Request, dst interface{}) error {
-	return commonhandler.DecodeJSON(r, dst)
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	return commonhandler.DecodeRequest(w, r, dst)
 }
 
 func parseDateParam(value string) (*time.Time, error) {
@@ -30,3 +47,23 @@ func parseCSV(value string) []string {
 func parseIntParam(value string, fallback int) (int, error) {
 	return commonhandler.ParseIntParam(value, fallback)
 }
+
+func actorFromUser(user middleware.User) activitydomain.Actor {
+	return commonhandler.ActorFromUser(user)
+}
+
+func parseIfMatch(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	return commonhandler.ParseIfMatch(w, r)
+}
+
+func setETag(w http.ResponseWriter, version int64) {
+	commonhandler.SetETag(w, version)
+}
+
+func writeVersionConflict(w http.ResponseWriter, version int64, current interface{}) {
+	commonhandler.WriteVersionConflict(w, version, current)
+}
+
+func writeValidationError(w http.ResponseWriter, err error) {
+	commonhandler.WriteValidationError(w, err)
+}
@@ -0,0 +1,142 @@
+package todos
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	familydomain "family-app-go/internal/domain/family"
+	labelsdomain "family-app-go/internal/domain/labels"
+	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type setLabelsRequest struct {
+	Labels []string `json:"labels"`
+}
+
+type labelsResponse struct {
+	Labels []string `json:"labels"`
+}
+
+type labelUsageResponse struct {
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+type labelUsageListResponse struct {
+	Items []labelUsageResponse `json:"items"`
+}
+
+// SetTodoItemLabels replaces the labels of a todo item.
+func (h *Handlers) SetTodoItemLabels(w http.ResponseWriter, r *http.Request) {
+	itemID := strings.TrimSpace(chi.URLParam(r, "item_id"))
+	if itemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "item_id is required")
+		return
+	}
+
+	var req setLabelsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, family, ok := h.labelsFamily(w, r, "todos.set_item_labels")
+	if !ok {
+		return
+	}
+
+	item, err := h.Todos.GetTodoItem(r.Context(), family.ID, itemID, user.ID)
+	if err != nil {
+		if errors.Is(err, todosdomain.ErrTodoItemNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.set_item_labels: todo item not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusNotFound, "todo_item_not_found", "todo item not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("todos.set_item_labels: get todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	labels, err := h.Labels.Set(r.Context(), labelsdomain.SetInput{
+		EntityType: labelsdomain.EntityTodoItem,
+		EntityID:   item.ID,
+		OwnerID:    family.ID,
+		Labels:     req.Labels,
+	})
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.set_item_labels: set labels failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, labelsResponse{Labels: labels})
+}
+
+// ListTodoLabels returns the labels used on the family's todo items.
+func (h *Handlers) ListTodoLabels(w http.ResponseWriter, r *http.Request) {
+	user, family, ok := h.labelsFamily(w, r, "todos.list_labels")
+	if !ok {
+		return
+	}
+
+	usage, err := h.Labels.ListUsage(r.Context(), labelsdomain.EntityTodoItem, family.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.list_labels: list labels failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := labelUsageListResponse{Items: make([]labelUsageResponse, 0, len(usage))}
+	for _, item := range usage {
+		response.Items = append(response.Items, labelUsageResponse{Label: item.Label, Count: item.Count})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) labelsFamily(w http.ResponseWriter, r *http.Request, op string) (middleware.User, *familydomain.Family, bool) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return middleware.User{}, nil, false
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return middleware.User{}, nil, false
+	}
+	return user, family, true
+}
+
+// fillLabels sets the labels of todo item responses with one lookup.
+func (h *Handlers) fillLabels(r *http.Request, groups ...[]todoItemResponse) error {
+	var ids []string
+	for _, items := range groups {
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+	}
+	labels, err := h.Labels.ListByEntities(r.Context(), labelsdomain.EntityTodoItem, ids)
+	if err != nil {
+		return err
+	}
+	for _, items := range groups {
+		for i := range items {
+			if itemLabels, ok := labels[items[i].ID]; ok {
+				items[i].Labels = itemLabels
+			}
+		}
+	}
+	return nil
+}
+
+// parseLabelsParam reads a comma-separated labels filter.
+func parseLabelsParam(value string) ([]string, error) {
+	values := parseCSV(value)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return labelsdomain.Normalize(values)
+}
@@ -0,0 +1,67 @@
+package todos
+
+import (
+	"context"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	commentsdomain "family-app-go/internal/domain/comments"
+	familydomain "family-app-go/internal/domain/family"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	labelsdomain "family-app-go/internal/domain/labels"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+// ActivityService is implemented by *activitydomain.Service.
+type ActivityService interface {
+	RecordTodoCompleted(ctx context.Context, familyID string, actor activitydomain.Actor, listID, itemID, title string) (*activitydomain.Event, error)
+}
+
+// CommentsService is implemented by *commentsdomain.Service.
+type CommentsService interface {
+	Clear(ctx context.Context, entityType commentsdomain.EntityType, entityID string) error
+	Create(ctx context.Context, input commentsdomain.CreateInput) (*commentsdomain.Comment, error)
+	Delete(ctx context.Context, thread commentsdomain.Thread, commentID, authorID string) error
+	List(ctx context.Context, thread commentsdomain.Thread, readerID string, filter commentsdomain.ListFilter) ([]commentsdomain.Comment, int64, error)
+	Update(ctx context.Context, input commentsdomain.UpdateInput) (*commentsdomain.Comment, error)
+}
+
+// FamilyService is implemented by *familydomain.Service.
+type FamilyService interface {
+	ListMembers(ctx context.Context, userID string) ([]familydomain.FamilyMember, error)
+}
+
+// FavoritesService is implemented by *favoritesdomain.Service.
+type FavoritesService interface {
+	Add(ctx context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error
+	Clear(ctx context.Context, entityType favoritesdomain.EntityType, entityID string) error
+	IDs(ctx context.Context, userID string, entityType favoritesdomain.EntityType) (map[string]bool, error)
+	Remove(ctx context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error
+}
+
+// LabelsService is implemented by *labelsdomain.Service.
+type LabelsService interface {
+	ListByEntities(ctx context.Context, entityType labelsdomain.EntityType, entityIDs []string) (map[string][]string, error)
+	ListUsage(ctx context.Context, entityType labelsdomain.EntityType, ownerID string) ([]labelsdomain.Usage, error)
+	Set(ctx context.Context, input labelsdomain.SetInput) ([]string, error)
+}
+
+// TodosService is implemented by *todosdomain.Service.
+type TodosService interface {
+	CountItemsByListID(ctx context.Context, listID string) (todosdomain.ListItemCounts, error)
+	CreateListShare(ctx context.Context, input todosdomain.CreateListShareInput) (*todosdomain.ListShare, string, error)
+	CreateTodoItem(ctx context.Context, familyID string, input todosdomain.CreateTodoItemInput) (*todosdomain.TodoItem, error)
+	CreateTodoList(ctx context.Context, input todosdomain.CreateTodoListInput) (*todosdomain.ListWithItems, error)
+	DeleteTodoItem(ctx context.Context, familyID, itemID, viewerID string) error
+	DeleteTodoList(ctx context.Context, familyID, listID, viewerID string) error
+	GetSharedList(ctx context.Context, token string) (*todosdomain.SharedList, error)
+	GetTodoItem(ctx context.Context, familyID, itemID, viewerID string) (*todosdomain.TodoItem, error)
+	GetTodoList(ctx context.Context, familyID, listID, viewerID string) (*todosdomain.TodoList, error)
+	ItemAudience(ctx context.Context, familyID, itemID, viewerID string) (*todosdomain.TodoItem, []string, error)
+	ListListShares(ctx context.Context, familyID, listID, viewerID string) ([]todosdomain.ListShare, error)
+	ListTodoItems(ctx context.Context, familyID, listID, viewerID string, archived todosdomain.ArchivedFilter, assigneeID string, labels []string) ([]todosdomain.TodoItem, int64, error)
+	ListTodoLists(ctx context.Context, familyID string, filter todosdomain.ListFilter, includeItems bool, itemsArchived todosdomain.ArchivedFilter) ([]todosdomain.ListWithItems, int64, error)
+	RevokeListShare(ctx context.Context, familyID, listID, shareID, viewerID string) error
+	SetListVisibility(ctx context.Context, input todosdomain.SetListVisibilityInput) (*todosdomain.TodoList, []string, error)
+	UpdateTodoItem(ctx context.Context, input todosdomain.UpdateTodoItemInput) (*todosdomain.TodoItem, error)
+	UpdateTodoList(ctx context.Context, input todosdomain.UpdateTodoListInput) (*todosdomain.ListWithItems, error)
+}
@@ -0,0 +1,243 @@
+package todos
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+const sharedListPath = "/api/shared/lists/"
+
+type createListShareRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type listShareResponse struct {
+	ID        string     `json:"id"`
+	ListID    string     `json:"list_id"`
+	CreatedBy string     `json:"created_by"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type createdListShareResponse struct {
+	listShareResponse
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+type listSharesResponse struct {
+	Items []listShareResponse `json:"items"`
+}
+
+type sharedListResponse struct {
+	Title     string               `json:"title"`
+	Items     []sharedItemResponse `json:"items"`
+	ExpiresAt *time.Time           `json:"expires_at"`
+}
+
+type sharedItemResponse struct {
+	Title       string  `json:"title"`
+	IsCompleted bool    `json:"is_completed"`
+	DueDate     *string `json:"due_date"`
+}
+
+func (h *Handlers) CreateListShare(w http.ResponseWriter, r *http.Request) {
+	listID := strings.TrimSpace(chi.URLParam(r, "list_id"))
+	if listID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "list_id is required")
+		return
+	}
+
+	var req createListShareRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	share, token, err := h.Todos.CreateListShare(r.Context(), todosdomain.CreateListShareInput{
+		FamilyID:  family.ID,
+		ListID:    listID,
+		CreatedBy: user.ID,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, todosdomain.ErrTodoListNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.create_share: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
+		case errors.Is(err, todosdomain.ErrInvalidShareExpiry):
+			writeError(w, http.StatusBadRequest, "invalid_request", "expires_at must be in the future")
+		case errors.Is(err, todosdomain.ErrTooManyShares):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.create_share: too many shares", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusConflict, "too_many_shares", "too many active share links for this list")
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("todos.create_share: create share failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdListShareResponse{
+		listShareResponse: toListShareResponse(*share, time.Now().UTC()),
+		Token:             token,
+		URL:               sharedListURL(r, token),
+	})
+}
+
+func (h *Handlers) ListListShares(w http.ResponseWriter, r *http.Request) {
+	listID := strings.TrimSpace(chi.URLParam(r, "list_id"))
+	if listID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "list_id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	shares, err := h.Todos.ListListShares(r.Context(), family.ID, listID, user.ID)
+	if err != nil {
+		if errors.Is(err, todosdomain.ErrTodoListNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.list_shares: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("todos.list_shares: list shares failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	now := time.Now().UTC()
+	response := make([]listShareResponse, 0, len(shares))
+	for _, share := range shares {
+		response = append(response, toListShareResponse(share, now))
+	}
+	writeJSON(w, http.StatusOK, listSharesResponse{Items: response})
+}
+
+func (h *Handlers) RevokeListShare(w http.ResponseWriter, r *http.Request) {
+	listID := strings.TrimSpace(chi.URLParam(r, "list_id"))
+	shareID := strings.TrimSpace(chi.URLParam(r, "share_id"))
+	if listID == "" || shareID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "list_id and share_id are required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Todos.RevokeListShare(r.Context(), family.ID, listID, shareID, user.ID); err != nil {
+		if errors.Is(err, todosdomain.ErrShareNotFound) {
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.revoke_share: share not found", err, "user_id", user.ID, "family_id", family.ID, "share_id", shareID)
+			writeError(w, http.StatusNotFound, "share_not_found", "share link not found")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("todos.revoke_share: revoke share failed", err, "user_id", user.ID, "family_id", family.ID, "share_id", shareID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSharedList serves a shared list to people without an account. It is
+// mounted outside of the auth group; the token in the path is the only
+// credential, and only titles, completion and due dates are exposed.
+func (h *Handlers) GetSharedList(w http.ResponseWriter, r *http.Request) {
+	shared, err := h.Todos.GetSharedList(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, todosdomain.ErrShareNotFound) {
+			writeError(w, http.StatusNotFound, "share_not_found", "share link not found or expired")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("todos.shared_list: load shared list failed", err)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	items := make([]sharedItemResponse, 0, len(shared.Items))
+	for _, item := range shared.Items {
+		var dueDate *string
+		if item.DueDate != nil {
+			value := item.DueDate.Format("2006-01-02")
+			dueDate = &value
+		}
+		items = append(items, sharedItemResponse{
+			Title:       item.Title,
+			IsCompleted: item.IsCompleted,
+			DueDate:     dueDate,
+		})
+	}
+
+	// Revoking has to take effect right away.
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, sharedListResponse{
+		Title:     shared.List.Title,
+		Items:     items,
+		ExpiresAt: shared.ExpiresAt,
+	})
+}
+
+func toListShareResponse(share todosdomain.ListShare, now time.Time) listShareResponse {
+	return listShareResponse{
+		ID:        share.ID,
+		ListID:    share.ListID,
+		CreatedBy: share.CreatedBy,
+		ExpiresAt: share.ExpiresAt,
+		RevokedAt: share.RevokedAt,
+		Active:    share.Active(now),
+		CreatedAt: share.CreatedAt,
+	}
+}
+
+func sharedListURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")); forwarded != "" {
+		scheme = forwarded
+	}
+
+	target := url.URL{
+		Scheme: scheme,
+		Host:   r.Host,
+		Path:   sharedListPath + token,
+	}
+	return target.String()
+}
@@ -6,9 +6,13 @@ import (
 	"strings"
 	"time"
 
-	familydomain "family-app-go/internal/domain/family"
+	commentsdomain "family-app-go/internal/domain/comments"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	quotadomain "family-app-go/internal/domain/quota"
 	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
 	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -30,12 +34,21 @@ type updateTodoListRequest struct {
 }
 
 type createTodoItemRequest struct {
-	Title string `json:"title"`
+	Title      string  `json:"title"`
+	DueDate    *string `json:"due_date"`
+	AssigneeID *string `json:"assignee_id"`
+	// EstimatedPrice is the planned cost in the family currency.
+	EstimatedPrice *float64 `json:"estimated_price"`
 }
 
 type updateTodoItemRequest struct {
-	Title       *string `json:"title"`
-	IsCompleted *bool   `json:"is_completed"`
+	Title       *string                `json:"title"`
+	IsCompleted *bool                  `json:"is_completed"`
+	DueDate     optionalNullableString `json:"due_date"`
+	AssigneeID  optionalNullableString `json:"assignee_id"`
+
+	EstimatedPrice optionalNullableFloat  `json:"estimated_price"`
+	ExpenseID      optionalNullableString `json:"expense_id"`
 }
 
 type todoListSettingsResponse struct {
@@ -48,12 +61,23 @@ type todoListResponse struct {
 	Title          string                   `json:"title"`
 	IsCollapsed    bool                     `json:"is_collapsed"`
 	Order          int                      `json:"order"`
+	Version        int64                    `json:"version"`
 	CreatedAt      time.Time                `json:"created_at"`
 	Settings       todoListSettingsResponse `json:"settings"`
 	ItemsTotal     int64                    `json:"items_total"`
 	ItemsCompleted int64                    `json:"items_completed"`
 	ItemsArchived  int64                    `json:"items_archived"`
 	Items          *[]todoItemResponse      `json:"items,omitempty"`
+	IsPrivate      bool                     `json:"is_private"`
+	IsFavorite     bool                     `json:"is_favorite"`
+	// VisibleTo lists the viewers of a private list.
+	VisibleTo []string `json:"visible_to,omitempty"`
+
+	// Planned and actual spend, in the family currency.
+	PlannedTotal     float64 `json:"planned_total"`
+	PlannedPurchased float64 `json:"planned_purchased"`
+	ActualTotal      float64 `json:"actual_total"`
+	ItemsLinked      int64   `json:"items_linked"`
 }
 
 type todoListListResponse struct {
@@ -70,6 +94,13 @@ type todoItemResponse struct {
 	CreatedAt   time.Time                `json:"created_at"`
 	CompletedAt *time.Time               `json:"completed_at"`
 	CompletedBy *todoCompletedByResponse `json:"completed_by"`
+	DueDate     *string                  `json:"due_date"`
+	AssigneeID  *string                  `json:"assignee_id"`
+	Labels      []string                 `json:"labels"`
+	Version     int64                    `json:"version"`
+
+	EstimatedPrice *float64 `json:"estimated_price"`
+	ExpenseID      *string  `json:"expense_id"`
 }
 
 type todoCompletedByResponse struct {
@@ -91,20 +122,13 @@ func (h *Handlers) ListTodoLists(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.list_lists: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.list_lists: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
 	query := r.URL.Query()
-	limit, err := parseIntParam(query.Get("limit"), 50)
+	limit, err := h.limits.TodoLists.Parse(query.Get("limit"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid limit")
 		return
@@ -119,6 +143,11 @@ func (h *Handlers) ListTodoLists(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid include_items")
 		return
 	}
+	favoritesFirst, err := parseBoolParam(query.Get("favorites_first"), false)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid favorites_first")
+		return
+	}
 
 	itemsArchived, err := parseArchivedFilter(query.Get("items_archived"), todosdomain.ArchivedExclude)
 	if err != nil {
@@ -127,21 +156,45 @@ func (h *Handlers) ListTodoLists(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filter := todosdomain.ListFilter{
-		Query:  strings.TrimSpace(query.Get("q")),
-		Limit:  limit,
-		Offset: offset,
+		Query:      strings.TrimSpace(query.Get("q")),
+		Limit:      limit,
+		Offset:     offset,
+		AssigneeID: middleware.AssigneeRestriction(r.Context()),
+		ViewerID:   user.ID,
+	}
+	if favoritesFirst {
+		filter.FavoritesOf = user.ID
 	}
 
 	items, total, err := h.Todos.ListTodoLists(r.Context(), family.ID, filter, includeItems, itemsArchived)
 	if err != nil {
-		h.log.InternalError("todos.list_lists: list todo lists failed", err, "user_id", user.ID, "family_id", family.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("todos.list_lists: list todo lists failed", err, "user_id", user.ID, "family_id", family.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	favorites, err := h.Favorites.IDs(r.Context(), user.ID, favoritesdomain.EntityTodoList)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.list_lists: list favorites failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
 	response := make([]todoListResponse, 0, len(items))
+	var listItems [][]todoItemResponse
 	for _, item := range items {
-		response = append(response, toTodoListResponse(item, includeItems))
+		list := toTodoListResponse(item, includeItems)
+		list.IsFavorite = favorites[item.List.ID]
+		if list.Items != nil {
+			listItems = append(listItems, *list.Items)
+		}
+		response = append(response, list)
+	}
+	if len(listItems) > 0 {
+		if err := h.fillLabels(r, listItems...); err != nil {
+			commonhandler.RequestLog(r, h.log).InternalError("todos.list_lists: list labels failed", err, "user_id", user.ID, "family_id", family.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
 	}
 
 	writeJSON(w, http.StatusOK, todoListListResponse{
@@ -152,16 +205,7 @@ func (h *Handlers) ListTodoLists(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) CreateTodoList(w http.ResponseWriter, r *http.Request) {
 	var req createTodoListRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
-		return
-	}
-	if strings.TrimSpace(req.Title) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "title is required")
-		return
-	}
-	if req.Order != nil && *req.Order < 0 {
-		writeError(w, http.StatusBadRequest, "invalid_request", "order must be non-negative")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -171,15 +215,8 @@ func (h *Handlers) CreateTodoList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.create_list: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.create_list: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -195,36 +232,28 @@ func (h *Handlers) CreateTodoList(w http.ResponseWriter, r *http.Request) {
 		Order:            req.Order,
 	})
 	if err != nil {
-		h.log.InternalError("todos.create_list: create todo list failed", err, "user_id", user.ID, "family_id", family.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
-		return
-	}
-
-	counts, err := h.Todos.CountItemsByListID(r.Context(), list.ID)
-	if err != nil {
-		h.log.InternalError("todos.create_list: count items failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", list.ID)
+		if errors.Is(err, quotadomain.ErrExceeded) {
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.create_list: quota exceeded", err, "user_id", user.ID, "family_id", family.ID)
+			writeQuotaExceeded(w, err)
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("todos.create_list: create todo list failed", err, "user_id", user.ID, "family_id", family.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, todoListResponse{
-		ID:             list.ID,
-		FamilyID:       list.FamilyID,
-		Title:          list.Title,
-		IsCollapsed:    list.IsCollapsed,
-		Order:          list.Order,
-		CreatedAt:      list.CreatedAt,
-		Settings:       todoListSettingsResponse{ArchiveCompleted: list.ArchiveCompleted},
-		ItemsTotal:     counts.ItemsTotal,
-		ItemsCompleted: counts.ItemsCompleted,
-		ItemsArchived:  counts.ItemsArchived,
-	})
+	setETag(w, list.List.Version)
+	writeJSON(w, http.StatusCreated, toTodoListResponse(*list, false))
 }
 
 func (h *Handlers) UpdateTodoList(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	var req updateTodoListRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -240,15 +269,8 @@ func (h *Handlers) UpdateTodoList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.update_list: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.update_list: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -256,18 +278,6 @@ func (h *Handlers) UpdateTodoList(w http.ResponseWriter, r *http.Request) {
 	if req.Settings != nil {
 		archiveCompleted = req.Settings.ArchiveCompleted
 	}
-	if req.Title == nil && archiveCompleted == nil && req.IsCollapsed == nil && req.Order == nil {
-		writeError(w, http.StatusBadRequest, "invalid_request", "no fields to update")
-		return
-	}
-	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "title is required")
-		return
-	}
-	if req.Order != nil && *req.Order < 0 {
-		writeError(w, http.StatusBadRequest, "invalid_request", "order must be non-negative")
-		return
-	}
 
 	list, err := h.Todos.UpdateTodoList(r.Context(), todosdomain.UpdateTodoListInput{
 		ID:               listID,
@@ -276,38 +286,42 @@ func (h *Handlers) UpdateTodoList(w http.ResponseWriter, r *http.Request) {
 		ArchiveCompleted: archiveCompleted,
 		IsCollapsed:      req.IsCollapsed,
 		Order:            req.Order,
+		ViewerID:         user.ID,
+		ExpectedVersion:  expectedVersion,
 	})
 	if err != nil {
+		var conflict *todosdomain.TodoListConflictError
 		switch {
+		case errors.As(err, &conflict):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_list: version conflict", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			counts, err := h.Todos.CountItemsByListID(r.Context(), listID)
+			if err != nil {
+				commonhandler.RequestLog(r, h.log).InternalError("todos.update_list: count items failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+				writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+				return
+			}
+			writeVersionConflict(w, conflict.Current.Version, toTodoListResponse(todosdomain.ListWithItems{List: conflict.Current, Counts: counts}, false))
 		case errors.Is(err, todosdomain.ErrTodoListNotFound):
-			h.log.BusinessError("todos.update_list: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_list: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
 		default:
-			h.log.InternalError("todos.update_list: update todo list failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			commonhandler.RequestLog(r, h.log).InternalError("todos.update_list: update todo list failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
 
-	counts, err := h.Todos.CountItemsByListID(r.Context(), list.ID)
+	isFavorite, err := h.isFavoriteList(r, user.ID, list.List.ID)
 	if err != nil {
-		h.log.InternalError("todos.update_list: count items failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", list.ID)
+		commonhandler.RequestLog(r, h.log).InternalError("todos.update_list: list favorites failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", list.List.ID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, todoListResponse{
-		ID:             list.ID,
-		FamilyID:       list.FamilyID,
-		Title:          list.Title,
-		IsCollapsed:    list.IsCollapsed,
-		Order:          list.Order,
-		CreatedAt:      list.CreatedAt,
-		Settings:       todoListSettingsResponse{ArchiveCompleted: list.ArchiveCompleted},
-		ItemsTotal:     counts.ItemsTotal,
-		ItemsCompleted: counts.ItemsCompleted,
-		ItemsArchived:  counts.ItemsArchived,
-	})
+	response := toTodoListResponse(*list, false)
+	response.IsFavorite = isFavorite
+	setETag(w, list.List.Version)
+	writeJSON(w, http.StatusOK, response)
 }
 
 func (h *Handlers) DeleteTodoList(w http.ResponseWriter, r *http.Request) {
@@ -323,28 +337,24 @@ func (h *Handlers) DeleteTodoList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.delete_list: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.delete_list: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
-	if err := h.Todos.DeleteTodoList(r.Context(), family.ID, listID); err != nil {
+	if err := h.Todos.DeleteTodoList(r.Context(), family.ID, listID, user.ID); err != nil {
 		if errors.Is(err, todosdomain.ErrTodoListNotFound) {
-			h.log.BusinessError("todos.delete_list: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.delete_list: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
 			return
 		}
-		h.log.InternalError("todos.delete_list: delete todo list failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+		commonhandler.RequestLog(r, h.log).InternalError("todos.delete_list: delete todo list failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if err := h.Favorites.Clear(r.Context(), favoritesdomain.EntityTodoList, listID); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.delete_list: clear favorites failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -362,15 +372,8 @@ func (h *Handlers) ListTodoItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.list_items: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.list_items: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
@@ -379,15 +382,20 @@ func (h *Handlers) ListTodoItems(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request", "invalid archived")
 		return
 	}
+	labels, err := parseLabelsParam(r.URL.Query().Get("labels"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "invalid labels")
+		return
+	}
 
-	items, total, err := h.Todos.ListTodoItems(r.Context(), family.ID, listID, archived)
+	items, total, err := h.Todos.ListTodoItems(r.Context(), family.ID, listID, user.ID, archived, middleware.AssigneeRestriction(r.Context()), labels)
 	if err != nil {
 		if errors.Is(err, todosdomain.ErrTodoListNotFound) {
-			h.log.BusinessError("todos.list_items: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.list_items: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
 			return
 		}
-		h.log.InternalError("todos.list_items: list todo items failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+		commonhandler.RequestLog(r, h.log).InternalError("todos.list_items: list todo items failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
@@ -396,6 +404,11 @@ func (h *Handlers) ListTodoItems(w http.ResponseWriter, r *http.Request) {
 	for _, item := range items {
 		response = append(response, toTodoItemResponse(item))
 	}
+	if err := h.fillLabels(r, response); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.list_items: list labels failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
 
 	writeJSON(w, http.StatusOK, todoItemListResponse{
 		Items: response,
@@ -405,13 +418,12 @@ func (h *Handlers) ListTodoItems(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) CreateTodoItem(w http.ResponseWriter, r *http.Request) {
 	var req createTodoItemRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
-	if strings.TrimSpace(req.Title) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "title is required")
-		return
+	var dueDate *time.Time
+	if req.DueDate != nil {
+		dueDate, _ = parseDateParam(*req.DueDate)
 	}
 
 	listID := strings.TrimSpace(chi.URLParam(r, "list_id"))
@@ -426,40 +438,57 @@ func (h *Handlers) CreateTodoItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.create_item: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.create_item: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.validAssignee(w, r, "todos.create_item", user.ID, req.AssigneeID) {
 		return
 	}
 
 	item, err := h.Todos.CreateTodoItem(r.Context(), family.ID, todosdomain.CreateTodoItemInput{
-		ListID: listID,
-		Title:  req.Title,
+		ListID:     listID,
+		Title:      req.Title,
+		DueDate:    dueDate,
+		AssigneeID: req.AssigneeID,
+		ViewerID:   user.ID,
+
+		EstimatedPrice: req.EstimatedPrice,
 	})
 	if err != nil {
 		if errors.Is(err, todosdomain.ErrTodoListNotFound) {
-			h.log.BusinessError("todos.create_item: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.create_item: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
 			return
 		}
-		h.log.InternalError("todos.create_item: create todo item failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+		if errors.Is(err, todosdomain.ErrInvalidEstimatedPrice) {
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.create_item: invalid estimated price", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeValidationError(w, validation.FieldErr("estimated_price", validation.CodeRange, "estimated_price must be non-negative"))
+			return
+		}
+		if errors.Is(err, quotadomain.ErrExceeded) {
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.create_item: quota exceeded", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeQuotaExceeded(w, err)
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("todos.create_item: create todo item failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
 
+	setETag(w, item.Version)
 	writeJSON(w, http.StatusCreated, toTodoItemResponse(*item))
 }
 
 func (h *Handlers) UpdateTodoItem(w http.ResponseWriter, r *http.Request) {
+	expectedVersion, ok := parseIfMatch(w, r)
+	if !ok {
+		return
+	}
+
 	var req updateTodoItemRequest
-	if err := decodeJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid_json", "invalid json body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -475,23 +504,15 @@ func (h *Handlers) UpdateTodoItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.update_item: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.update_item: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
-	if req.Title == nil && req.IsCompleted == nil {
-		writeError(w, http.StatusBadRequest, "invalid_request", "no fields to update")
-		return
+	dueDate := todosdomain.OptionalNullableDate{Set: req.DueDate.Set}
+	if req.DueDate.Value != nil {
+		dueDate.Value, _ = parseDateParam(*req.DueDate.Value)
 	}
-	if req.Title != nil && strings.TrimSpace(*req.Title) == "" {
-		writeError(w, http.StatusBadRequest, "invalid_request", "title is required")
+	if req.AssigneeID.Set && !h.validAssignee(w, r, "todos.update_item", user.ID, req.AssigneeID.Value) {
 		return
 	}
 
@@ -510,21 +531,63 @@ func (h *Handlers) UpdateTodoItem(w http.ResponseWriter, r *http.Request) {
 		FamilyID:    family.ID,
 		Title:       req.Title,
 		IsCompleted: req.IsCompleted,
-		CompletedBy: completedBy,
+		DueDate:     dueDate,
+		AssigneeID: todosdomain.OptionalNullableString{
+			Set:   req.AssigneeID.Set,
+			Value: req.AssigneeID.Value,
+		},
+		CompletedBy:        completedBy,
+		RestrictToAssignee: middleware.AssigneeRestriction(r.Context()),
+		ViewerID:           user.ID,
+		ExpectedVersion:    expectedVersion,
+
+		EstimatedPrice: todosdomain.OptionalNullableFloat{Set: req.EstimatedPrice.Set, Value: req.EstimatedPrice.Value},
+		ExpenseID:      todosdomain.OptionalNullableString{Set: req.ExpenseID.Set, Value: req.ExpenseID.Value},
 	})
 	if err != nil {
+		var conflict *todosdomain.TodoItemConflictError
 		switch {
+		case errors.As(err, &conflict):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_item: version conflict", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeVersionConflict(w, conflict.Current.Version, toTodoItemResponse(conflict.Current))
 		case errors.Is(err, todosdomain.ErrTodoItemNotFound):
-			h.log.BusinessError("todos.update_item: todo item not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_item: todo item not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
 			writeError(w, http.StatusNotFound, "todo_item_not_found", "todo item not found")
+		case errors.Is(err, todosdomain.ErrAssigneeLocked), errors.Is(err, todosdomain.ErrPurchaseLocked):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_item: change denied", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusForbidden, "child_restricted", "not available for child members")
+		case errors.Is(err, todosdomain.ErrExpenseNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_item: expense not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusNotFound, "expense_not_found", "expense not found")
+		case errors.Is(err, todosdomain.ErrTodoItemNotCompleted):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_item: item not completed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeError(w, http.StatusConflict, "todo_item_not_completed", "only completed todo items can be linked to an expense")
+		case errors.Is(err, todosdomain.ErrInvalidEstimatedPrice):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.update_item: invalid estimated price", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			writeValidationError(w, validation.FieldErr("estimated_price", validation.CodeRange, "estimated_price must be non-negative"))
 		default:
-			h.log.InternalError("todos.update_item: update todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			commonhandler.RequestLog(r, h.log).InternalError("todos.update_item: update todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
 			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		}
 		return
 	}
 
-	writeJSON(w, http.StatusOK, toTodoItemResponse(*item))
+	if completedBy != nil {
+		actor := actorFromUser(user)
+		if _, err := h.Activity.RecordTodoCompleted(r.Context(), family.ID, actor, item.ListID, item.ID, item.Title); err != nil {
+			commonhandler.RequestLog(r, h.log).InternalError("todos.update_item: record activity failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", item.ID)
+		}
+	}
+
+	response := []todoItemResponse{toTodoItemResponse(*item)}
+	if err := h.fillLabels(r, response); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.update_item: list labels failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", item.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	setETag(w, item.Version)
+	writeJSON(w, http.StatusOK, response[0])
 }
 
 func (h *Handlers) DeleteTodoItem(w http.ResponseWriter, r *http.Request) {
@@ -540,28 +603,24 @@ func (h *Handlers) DeleteTodoItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	family, err := h.Families.GetFamilyByUser(r.Context(), user.ID)
-	if err != nil {
-		if errors.Is(err, familydomain.ErrFamilyNotFound) {
-			h.log.BusinessError("todos.delete_item: family not found", err, "user_id", user.ID)
-			writeError(w, http.StatusNotFound, "family_not_found", "family not found")
-			return
-		}
-		h.log.InternalError("todos.delete_item: get family failed", err, "user_id", user.ID)
-		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	family, ok := requireFamily(w, r)
+	if !ok {
 		return
 	}
 
-	if err := h.Todos.DeleteTodoItem(r.Context(), family.ID, itemID); err != nil {
+	if err := h.Todos.DeleteTodoItem(r.Context(), family.ID, itemID, user.ID); err != nil {
 		if errors.Is(err, todosdomain.ErrTodoItemNotFound) {
-			h.log.BusinessError("todos.delete_item: todo item not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.delete_item: todo item not found", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
 			writeError(w, http.StatusNotFound, "todo_item_not_found", "todo item not found")
 			return
 		}
-		h.log.InternalError("todos.delete_item: delete todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+		commonhandler.RequestLog(r, h.log).InternalError("todos.delete_item: delete todo item failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
 		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 		return
 	}
+	if err := h.Comments.Clear(r.Context(), commentsdomain.EntityTodoItem, itemID); err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.delete_item: clear comments failed", err, "user_id", user.ID, "family_id", family.ID, "item_id", itemID)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -605,11 +664,19 @@ func toTodoListResponse(item todosdomain.ListWithItems, includeItems bool) todoL
 		Title:          item.List.Title,
 		IsCollapsed:    item.List.IsCollapsed,
 		Order:          item.List.Order,
+		Version:        item.List.Version,
 		CreatedAt:      item.List.CreatedAt,
 		Settings:       todoListSettingsResponse{ArchiveCompleted: item.List.ArchiveCompleted},
 		ItemsTotal:     item.Counts.ItemsTotal,
 		ItemsCompleted: item.Counts.ItemsCompleted,
 		ItemsArchived:  item.Counts.ItemsArchived,
+		IsPrivate:      item.List.IsPrivate,
+		VisibleTo:      item.ViewerIDs,
+
+		PlannedTotal:     item.Counts.PlannedTotal,
+		PlannedPurchased: item.Counts.PlannedPurchased,
+		ActualTotal:      item.Counts.ActualTotal,
+		ItemsLinked:      item.Counts.ItemsLinked,
 	}
 
 	if includeItems {
@@ -634,6 +701,12 @@ func toTodoItemResponse(item todosdomain.TodoItem) todoItemResponse {
 		}
 	}
 
+	var dueDate *string
+	if item.DueDate != nil {
+		value := item.DueDate.Format("2006-01-02")
+		dueDate = &value
+	}
+
 	return todoItemResponse{
 		ID:          item.ID,
 		ListID:      item.ListID,
@@ -643,7 +716,37 @@ func toTodoItemResponse(item todosdomain.TodoItem) todoItemResponse {
 		CreatedAt:   item.CreatedAt,
 		CompletedAt: item.CompletedAt,
 		CompletedBy: completedBy,
+		Version:     item.Version,
+		DueDate:     dueDate,
+		AssigneeID:  item.AssigneeID,
+		Labels:      []string{},
+
+		EstimatedPrice: item.EstimatedPrice,
+		ExpenseID:      item.ExpenseID,
+	}
+}
+
+// validAssignee checks that an assignee belongs to the caller's family and
+// writes the error response otherwise.
+func (h *Handlers) validAssignee(w http.ResponseWriter, r *http.Request, operation, userID string, assigneeID *string) bool {
+	if assigneeID == nil || strings.TrimSpace(*assigneeID) == "" {
+		return true
 	}
+
+	members, err := h.Families.ListMembers(r.Context(), userID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError(operation+": list members failed", err, "user_id", userID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return false
+	}
+	for _, member := range members {
+		if member.UserID == strings.TrimSpace(*assigneeID) {
+			return true
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, "invalid_request", "assignee_id must be a family member")
+	return false
 }
 
 func valueOrEmpty(value *string) string {
@@ -0,0 +1,520 @@
+package todos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	commentsdomain "family-app-go/internal/domain/comments"
+	familydomain "family-app-go/internal/domain/family"
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	labelsdomain "family-app-go/internal/domain/labels"
+	quotadomain "family-app-go/internal/domain/quota"
+	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	testFamilyID = "11111111-1111-1111-1111-111111111111"
+	testUserID   = "22222222-2222-2222-2222-222222222222"
+	testMemberID = "33333333-3333-3333-3333-333333333333"
+	testListID   = "44444444-4444-4444-4444-444444444444"
+	testItemID   = "55555555-5555-5555-5555-555555555555"
+)
+
+var errDatabase = errors.New("database is down")
+
+// The mocks embed the service interface: a call the test did not expect
+// hits the nil interface and panics.
+
+type mockTodos struct {
+	TodosService
+	lists      []todosdomain.ListWithItems
+	counts     todosdomain.ListItemCounts
+	err        error
+	filter     todosdomain.ListFilter
+	listUpdate todosdomain.UpdateTodoListInput
+	itemsInput todosdomain.CreateTodoItemInput
+	update     todosdomain.UpdateTodoItemInput
+	deleted    string
+}
+
+func (m *mockTodos) ListTodoLists(_ context.Context, _ string, filter todosdomain.ListFilter, _ bool, _ todosdomain.ArchivedFilter) ([]todosdomain.ListWithItems, int64, error) {
+	m.filter = filter
+	return m.lists, int64(len(m.lists)), m.err
+}
+
+func (m *mockTodos) CreateTodoList(_ context.Context, input todosdomain.CreateTodoListInput) (*todosdomain.ListWithItems, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &todosdomain.ListWithItems{List: todosdomain.TodoList{ID: testListID, FamilyID: input.FamilyID, Title: input.Title, Version: 1}}, nil
+}
+
+func (m *mockTodos) UpdateTodoList(_ context.Context, input todosdomain.UpdateTodoListInput) (*todosdomain.ListWithItems, error) {
+	m.listUpdate = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &todosdomain.ListWithItems{List: todosdomain.TodoList{ID: input.ID, FamilyID: input.FamilyID, Title: *input.Title, Version: 4}, Counts: m.counts}, nil
+}
+
+func (m *mockTodos) CreateTodoItem(_ context.Context, _ string, input todosdomain.CreateTodoItemInput) (*todosdomain.TodoItem, error) {
+	m.itemsInput = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	item := testItem()
+	item.Title = input.Title
+	return &item, nil
+}
+
+func (m *mockTodos) UpdateTodoItem(_ context.Context, input todosdomain.UpdateTodoItemInput) (*todosdomain.TodoItem, error) {
+	m.update = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	item := testItem()
+	if input.IsCompleted != nil && *input.IsCompleted {
+		item.IsCompleted = true
+		item.CompletedByID = &input.CompletedBy.ID
+		item.CompletedByName = &input.CompletedBy.Name
+	}
+	item.Version = 2
+	return &item, nil
+}
+
+func (m *mockTodos) DeleteTodoItem(_ context.Context, _ string, itemID, _ string) error {
+	m.deleted = itemID
+	return m.err
+}
+
+type mockFamilies struct {
+	FamilyService
+}
+
+func (mockFamilies) ListMembers(context.Context, string) ([]familydomain.FamilyMember, error) {
+	return []familydomain.FamilyMember{{FamilyID: testFamilyID, UserID: testUserID}, {FamilyID: testFamilyID, UserID: testMemberID}}, nil
+}
+
+type mockActivity struct {
+	ActivityService
+	completed []string
+}
+
+func (m *mockActivity) RecordTodoCompleted(_ context.Context, _ string, _ activitydomain.Actor, _, itemID, _ string) (*activitydomain.Event, error) {
+	m.completed = append(m.completed, itemID)
+	return &activitydomain.Event{}, nil
+}
+
+type mockLabels struct {
+	LabelsService
+	labels map[string][]string
+}
+
+func (m *mockLabels) ListByEntities(context.Context, labelsdomain.EntityType, []string) (map[string][]string, error) {
+	return m.labels, nil
+}
+
+type mockFavorites struct {
+	FavoritesService
+	ids map[string]bool
+}
+
+func (m *mockFavorites) IDs(context.Context, string, favoritesdomain.EntityType) (map[string]bool, error) {
+	return m.ids, nil
+}
+
+type mockComments struct {
+	CommentsService
+	cleared []string
+}
+
+func (m *mockComments) Clear(_ context.Context, _ commentsdomain.EntityType, entityID string) error {
+	m.cleared = append(m.cleared, entityID)
+	return nil
+}
+
+type testDeps struct {
+	todos     *mockTodos
+	activity  *mockActivity
+	labels    *mockLabels
+	favorites *mockFavorites
+	comments  *mockComments
+}
+
+func newTestDeps() *testDeps {
+	return &testDeps{
+		todos:     &mockTodos{},
+		activity:  &mockActivity{},
+		labels:    &mockLabels{},
+		favorites: &mockFavorites{},
+		comments:  &mockComments{},
+	}
+}
+
+func (d *testDeps) handlers() *Handlers {
+	limits := commonhandler.ListLimits{TodoLists: commonhandler.ListLimit{Default: 20, Max: 100}}
+	return New(mockFamilies{}, d.todos, d.activity, d.labels, d.favorites, d.comments, limits, logger.New(io.Discard, slog.LevelError, "text"))
+}
+
+func testItem() todosdomain.TodoItem {
+	return todosdomain.TodoItem{
+		ID:        testItemID,
+		ListID:    testListID,
+		Title:     "Milk",
+		CreatedAt: time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC),
+		Version:   1,
+	}
+}
+
+// familyRequest is a request from testUserID, a member of testFamilyID.
+func familyRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	ctx := middleware.WithUser(req.Context(), middleware.User{ID: testUserID, Name: "Alex", Email: "alex@example.com"})
+	ctx = middleware.WithFamily(ctx, &familydomain.Family{ID: testFamilyID, DefaultCurrency: "EUR"})
+	return req.WithContext(ctx)
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var payload struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error response %q: %v", rec.Body.String(), err)
+	}
+	return payload.Error.Code
+}
+
+func TestListTodoListsRejectsInvalidQuery(t *testing.T) {
+	for _, query := range []string{
+		"limit=-5",
+		"offset=first",
+		"include_items=yes",
+		"favorites_first=maybe",
+		"items_archived=some",
+	} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			newTestDeps().handlers().ListTodoLists(rec, familyRequest(http.MethodGet, "/api/todo-lists?"+query, ""))
+			if rec.Code != http.StatusBadRequest || errorCode(t, rec) != "invalid_request" {
+				t.Fatalf("expected 400 invalid_request, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestListTodoListsResponse(t *testing.T) {
+	deps := newTestDeps()
+	deps.todos.lists = []todosdomain.ListWithItems{{
+		List:   todosdomain.TodoList{ID: testListID, FamilyID: testFamilyID, Title: "Shopping", Version: 3},
+		Counts: todosdomain.ListItemCounts{ItemsTotal: 1},
+		Items:  []todosdomain.TodoItem{testItem()},
+	}}
+	deps.favorites.ids = map[string]bool{testListID: true}
+	deps.labels.labels = map[string][]string{testItemID: {"dairy"}}
+
+	rec := httptest.NewRecorder()
+	deps.handlers().ListTodoLists(rec, familyRequest(http.MethodGet, "/api/todo-lists?include_items=true&favorites_first=1&limit=500&q=+shop+", ""))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	filter := deps.todos.filter
+	if filter.Limit != 100 || filter.Query != "shop" || filter.FavoritesOf != testUserID || filter.ViewerID != testUserID || filter.AssigneeID != "" {
+		t.Fatalf("unexpected filter: %+v", filter)
+	}
+	var body todoListListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Total != 1 || len(body.Items) != 1 || !body.Items[0].IsFavorite || body.Items[0].Items == nil {
+		t.Fatalf("unexpected response: %s", rec.Body.String())
+	}
+	items := *body.Items[0].Items
+	if len(items) != 1 || len(items[0].Labels) != 1 || items[0].Labels[0] != "dairy" {
+		t.Fatalf("expected the item with its labels, got %+v", items)
+	}
+}
+
+func TestListTodoListsOmitsItemsUnlessAsked(t *testing.T) {
+	deps := newTestDeps()
+	deps.todos.lists = []todosdomain.ListWithItems{{List: todosdomain.TodoList{ID: testListID, FamilyID: testFamilyID, Title: "Shopping"}}}
+
+	rec := httptest.NewRecorder()
+	deps.handlers().ListTodoLists(rec, familyRequest(http.MethodGet, "/api/todo-lists", ""))
+
+	var body struct {
+		Items []map[string]json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Items) != 1 {
+		t.Fatalf("unexpected response: %s", rec.Body.String())
+	}
+	if _, ok := body.Items[0]["items"]; ok {
+		t.Fatalf("expected no items key, got %s", rec.Body.String())
+	}
+}
+
+func TestListTodoListsRestrictsChildren(t *testing.T) {
+	deps := newTestDeps()
+	req := familyRequest(http.MethodGet, "/api/todo-lists", "")
+	req = req.WithContext(middleware.WithFamilyRole(req.Context(), familydomain.RoleChild))
+
+	rec := httptest.NewRecorder()
+	deps.handlers().ListTodoLists(rec, req)
+
+	if rec.Code != http.StatusOK || deps.todos.filter.AssigneeID != testUserID {
+		t.Fatalf("expected lists restricted to the child's items, got %d filter %+v", rec.Code, deps.todos.filter)
+	}
+}
+
+func TestCreateTodoList(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "malformed", body: `{"title":`, status: http.StatusBadRequest, code: "invalid_json"},
+		{name: "missing title", body: `{"title":" "}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "negative order", body: `{"title":"Shopping","order":-1}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "quota", body: `{"title":"Shopping"}`, err: &quotadomain.ExceededError{Quota: "todo_lists", Limit: 50}, status: http.StatusConflict, code: "quota_exceeded"},
+		{name: "internal", body: `{"title":"Shopping"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "created", body: `{"title":"Shopping","settings":{"archive_completed":true}}`, status: http.StatusCreated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.todos.err = tc.err
+			rec := httptest.NewRecorder()
+			deps.handlers().CreateTodoList(rec, familyRequest(http.MethodPost, "/api/todo-lists", tc.body))
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			var body todoListResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if body.ID != testListID || body.Title != "Shopping" || body.Items != nil || rec.Header().Get("ETag") != `"1"` {
+				t.Fatalf("unexpected response %+v etag %q", body, rec.Header().Get("ETag"))
+			}
+			if body.ItemsTotal != 0 || body.ItemsCompleted != 0 || body.ItemsArchived != 0 || body.PlannedTotal != 0 || body.ActualTotal != 0 || body.ItemsLinked != 0 {
+				t.Fatalf("expected zero counts for a new list, got %+v", body)
+			}
+		})
+	}
+}
+
+func TestUpdateTodoListReturnsCounts(t *testing.T) {
+	deps := newTestDeps()
+	deps.todos.counts = todosdomain.ListItemCounts{ItemsTotal: 4, ItemsCompleted: 2, ItemsArchived: 1, PlannedTotal: 12.5, PlannedPurchased: 5, ActualTotal: 4.8, ItemsLinked: 1}
+	req := familyRequest(http.MethodPatch, "/api/todo-lists/"+testListID, `{"title":"Weekend"}`)
+	req.Header.Set("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+	deps.handlers().UpdateTodoList(rec, withURLParam(req, "list_id", testListID))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	input := deps.todos.listUpdate
+	if input.ID != testListID || input.ExpectedVersion != 3 || input.ViewerID != testUserID {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+	var body todoListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.ID != testListID || body.Title != "Weekend" || rec.Header().Get("ETag") != `"4"` {
+		t.Fatalf("unexpected response %+v etag %q", body, rec.Header().Get("ETag"))
+	}
+	if body.ItemsTotal != 4 || body.ItemsCompleted != 2 || body.ItemsArchived != 1 || body.PlannedTotal != 12.5 || body.PlannedPurchased != 5 || body.ActualTotal != 4.8 || body.ItemsLinked != 1 {
+		t.Fatalf("expected the list counts, got %+v", body)
+	}
+}
+
+func TestCreateTodoItem(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "bad due date", body: `{"title":"Milk","due_date":"tomorrow"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "negative price", body: `{"title":"Milk","estimated_price":-1}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "assignee outside family", body: `{"title":"Milk","assignee_id":"99999999-9999-9999-9999-999999999999"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "list not found", body: `{"title":"Milk"}`, err: todosdomain.ErrTodoListNotFound, status: http.StatusNotFound, code: "todo_list_not_found"},
+		{name: "price rejected", body: `{"title":"Milk"}`, err: todosdomain.ErrInvalidEstimatedPrice, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "quota", body: `{"title":"Milk"}`, err: &quotadomain.ExceededError{Quota: "todo_items", Limit: 500}, status: http.StatusConflict, code: "quota_exceeded"},
+		{name: "internal", body: `{"title":"Milk"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "created", body: `{"title":"Milk","due_date":"2026-10-20","assignee_id":"` + testMemberID + `"}`, status: http.StatusCreated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.todos.err = tc.err
+			req := withURLParam(familyRequest(http.MethodPost, "/api/todo-lists/"+testListID+"/items", tc.body), "list_id", testListID)
+			rec := httptest.NewRecorder()
+			deps.handlers().CreateTodoItem(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			input := deps.todos.itemsInput
+			if input.ListID != testListID || input.DueDate == nil || input.DueDate.Format("2006-01-02") != "2026-10-20" || input.ViewerID != testUserID {
+				t.Fatalf("unexpected input: %+v", input)
+			}
+			var body todoItemResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if body.ID != testItemID || body.Labels == nil || body.CompletedBy != nil {
+				t.Fatalf("unexpected response: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdateTodoItemMapsErrors(t *testing.T) {
+	current := testItem()
+	current.Version = 5
+	cases := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "no fields", body: `{}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "blank title", body: `{"title":""}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "expense id", body: `{"expense_id":"receipt"}`, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "conflict", body: `{"title":"Oat milk"}`, err: &todosdomain.TodoItemConflictError{Current: current}, status: http.StatusConflict, code: "version_conflict"},
+		{name: "not found", body: `{"title":"Oat milk"}`, err: todosdomain.ErrTodoItemNotFound, status: http.StatusNotFound, code: "todo_item_not_found"},
+		{name: "assignee locked", body: `{"assignee_id":null}`, err: todosdomain.ErrAssigneeLocked, status: http.StatusForbidden, code: "child_restricted"},
+		{name: "purchase locked", body: `{"estimated_price":3}`, err: todosdomain.ErrPurchaseLocked, status: http.StatusForbidden, code: "child_restricted"},
+		{name: "expense not found", body: `{"expense_id":"` + testMemberID + `"}`, err: todosdomain.ErrExpenseNotFound, status: http.StatusNotFound, code: "expense_not_found"},
+		{name: "not completed", body: `{"expense_id":"` + testMemberID + `"}`, err: todosdomain.ErrTodoItemNotCompleted, status: http.StatusConflict, code: "todo_item_not_completed"},
+		{name: "price rejected", body: `{"estimated_price":3}`, err: todosdomain.ErrInvalidEstimatedPrice, status: http.StatusBadRequest, code: "invalid_request"},
+		{name: "internal", body: `{"title":"Oat milk"}`, err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.todos.err = tc.err
+			req := withURLParam(familyRequest(http.MethodPatch, "/api/todo-items/"+testItemID, tc.body), "item_id", testItemID)
+			rec := httptest.NewRecorder()
+			deps.handlers().UpdateTodoItem(rec, req)
+
+			if rec.Code != tc.status || errorCode(t, rec) != tc.code {
+				t.Fatalf("expected %d %s, got %d: %s", tc.status, tc.code, rec.Code, rec.Body.String())
+			}
+			if tc.code == "version_conflict" && rec.Header().Get("ETag") != `"5"` {
+				t.Fatalf("expected the current version in ETag, got %q", rec.Header().Get("ETag"))
+			}
+			if len(deps.activity.completed) != 0 {
+				t.Fatalf("expected no activity, got %v", deps.activity.completed)
+			}
+		})
+	}
+}
+
+func TestUpdateTodoItemCompletes(t *testing.T) {
+	deps := newTestDeps()
+	req := withURLParam(familyRequest(http.MethodPatch, "/api/todo-items/"+testItemID, `{"is_completed":true,"due_date":null}`), "item_id", testItemID)
+	req.Header.Set("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+	deps.handlers().UpdateTodoItem(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	input := deps.todos.update
+	if input.ExpectedVersion != 1 || !input.DueDate.Set || input.DueDate.Value != nil || input.AssigneeID.Set {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+	if input.CompletedBy == nil || input.CompletedBy.ID != testUserID || input.CompletedBy.Name != "Alex" {
+		t.Fatalf("expected the caller as completer, got %+v", input.CompletedBy)
+	}
+	var body todoItemResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !body.IsCompleted || body.CompletedBy == nil || body.CompletedBy.Name != "Alex" || rec.Header().Get("ETag") != `"2"` {
+		t.Fatalf("unexpected response %s etag %q", rec.Body.String(), rec.Header().Get("ETag"))
+	}
+	if len(deps.activity.completed) != 1 || deps.activity.completed[0] != testItemID {
+		t.Fatalf("expected the completion in the activity feed, got %v", deps.activity.completed)
+	}
+}
+
+func TestDeleteTodoItem(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "not found", err: todosdomain.ErrTodoItemNotFound, status: http.StatusNotFound, code: "todo_item_not_found"},
+		{name: "internal", err: errDatabase, status: http.StatusInternalServerError, code: "internal_error"},
+		{name: "deleted", status: http.StatusNoContent},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := newTestDeps()
+			deps.todos.err = tc.err
+			req := withURLParam(familyRequest(http.MethodDelete, "/api/todo-items/"+testItemID, ""), "item_id", testItemID)
+			rec := httptest.NewRecorder()
+			deps.handlers().DeleteTodoItem(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.code != "" {
+				if code := errorCode(t, rec); code != tc.code {
+					t.Fatalf("expected %s, got %s", tc.code, code)
+				}
+				return
+			}
+			if deps.todos.deleted != testItemID || len(deps.comments.cleared) != 1 {
+				t.Fatalf("expected the item and its comments deleted, got %q %v", deps.todos.deleted, deps.comments.cleared)
+			}
+		})
+	}
+}
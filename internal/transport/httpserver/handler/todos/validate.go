@@ -0,0 +1,57 @@
+package todos
+
+import (
+	"fmt"
+
+	labelsdomain "family-app-go/internal/domain/labels"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+func (req createTodoListRequest) Validate(v *validation.Validator) {
+	v.Required("title", req.Title)
+	v.NonNegative("order", req.Order)
+}
+
+func (req updateTodoListRequest) Validate(v *validation.Validator) {
+	hasSettings := req.Settings != nil && req.Settings.ArchiveCompleted != nil
+	if req.Title == nil && !hasSettings && req.IsCollapsed == nil && req.Order == nil {
+		v.Add("", validation.CodeEmpty, "no fields to update")
+		return
+	}
+	v.NotBlank("title", req.Title)
+	v.NonNegative("order", req.Order)
+}
+
+func (req createTodoItemRequest) Validate(v *validation.Validator) {
+	v.Required("title", req.Title)
+	v.Date("due_date", req.DueDate)
+	v.NonNegativeFloat("estimated_price", req.EstimatedPrice)
+}
+
+func (req updateTodoItemRequest) Validate(v *validation.Validator) {
+	if req.Title == nil && req.IsCompleted == nil && !req.DueDate.Set && !req.AssigneeID.Set &&
+		!req.EstimatedPrice.Set && !req.ExpenseID.Set {
+		v.Add("", validation.CodeEmpty, "no fields to update")
+		return
+	}
+	v.NotBlank("title", req.Title)
+	v.Date("due_date", req.DueDate.Value)
+	v.NonNegativeFloat("estimated_price", req.EstimatedPrice.Value)
+	if req.ExpenseID.Value != nil {
+		v.UUID("expense_id", *req.ExpenseID.Value)
+	}
+}
+
+func (req setLabelsRequest) Validate(v *validation.Validator) {
+	if req.Labels == nil {
+		v.Add("labels", validation.CodeRequired, "labels is required")
+		return
+	}
+	if len(req.Labels) > labelsdomain.MaxLabelsPerEntity {
+		v.Add("labels", validation.CodeTooLong, fmt.Sprintf("at most %d labels are allowed", labelsdomain.MaxLabelsPerEntity))
+		return
+	}
+	if _, err := labelsdomain.Normalize(req.Labels); err != nil {
+		v.Add("labels", validation.CodeInvalid, fmt.Sprintf("labels must be 1 to %d characters", labelsdomain.MaxLabelLength))
+	}
+}
@@ -0,0 +1,82 @@
+package todos
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	todosdomain "family-app-go/internal/domain/todos"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type setListVisibilityRequest struct {
+	MemberIDs []string `json:"member_ids"`
+}
+
+// SetTodoListVisibility makes a list private to the given members, the
+// caller always included, or visible to the whole family with no members.
+func (h *Handlers) SetTodoListVisibility(w http.ResponseWriter, r *http.Request) {
+	var req setListVisibilityRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	listID := strings.TrimSpace(chi.URLParam(r, "list_id"))
+	if listID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "list_id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	list, viewerIDs, err := h.Todos.SetListVisibility(r.Context(), todosdomain.SetListVisibilityInput{
+		FamilyID:  family.ID,
+		ListID:    listID,
+		ActorID:   user.ID,
+		ViewerIDs: req.MemberIDs,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, todosdomain.ErrTodoListNotFound):
+			commonhandler.RequestLog(r, h.log).BusinessError("todos.set_visibility: todo list not found", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusNotFound, "todo_list_not_found", "todo list not found")
+		case errors.Is(err, todosdomain.ErrInvalidListViewers):
+			writeValidationError(w, validation.FieldErr("member_ids", validation.CodeInvalid, "member_ids must be members of the family"))
+		default:
+			commonhandler.RequestLog(r, h.log).InternalError("todos.set_visibility: set visibility failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", listID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		}
+		return
+	}
+
+	counts, err := h.Todos.CountItemsByListID(r.Context(), list.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.set_visibility: count items failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", list.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	isFavorite, err := h.isFavoriteList(r, user.ID, list.ID)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError("todos.set_visibility: list favorites failed", err, "user_id", user.ID, "family_id", family.ID, "list_id", list.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := toTodoListResponse(todosdomain.ListWithItems{List: *list, Counts: counts, ViewerIDs: viewerIDs}, false)
+	response.IsFavorite = isFavorite
+	setETag(w, list.Version)
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,17 @@
+package views
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Views ViewsService
+	log   logger.Logger
+}
+
+func New(views ViewsService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Views: views,
+		log:   log,
+	}
+}
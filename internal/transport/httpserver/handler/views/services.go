@@ -0,0 +1,17 @@
+package views
+
+import (
+	"context"
+
+	viewsdomain "family-app-go/internal/domain/views"
+)
+
+// ViewsService is implemented by *viewsdomain.Service.
+type ViewsService interface {
+	CreateView(ctx context.Context, userID string, input viewsdomain.CreateInput) (*viewsdomain.SavedView, error)
+	DeleteView(ctx context.Context, userID, id string) error
+	GetView(ctx context.Context, userID, id string) (*viewsdomain.SavedView, error)
+	ListViews(ctx context.Context, userID string, entityType viewsdomain.EntityType) ([]viewsdomain.SavedView, error)
+	ResolveFilters(view viewsdomain.SavedView) (map[string]string, error)
+	UpdateView(ctx context.Context, userID, id string, input viewsdomain.UpdateInput) (*viewsdomain.SavedView, error)
+}
@@ -0,0 +1,36 @@
+package views
+
+import (
+	viewsdomain "family-app-go/internal/domain/views"
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+const filtersFormatMessage = "filter values must be strings, numbers, booleans or arrays of strings"
+
+func (req createViewRequest) Validate(v *validation.Validator) {
+	v.Required("name", req.Name)
+	v.MaxLength("name", req.Name, viewsdomain.MaxNameLength)
+	v.OneOf("entity_type", req.EntityType,
+		string(viewsdomain.EntityExpenses),
+		string(viewsdomain.EntityTodoLists),
+		string(viewsdomain.EntityTodoItems),
+		string(viewsdomain.EntityWorkouts),
+	)
+	if _, ok := filterStrings(req.Filters); !ok {
+		v.Add("filters", validation.CodeInvalid, filtersFormatMessage)
+	}
+}
+
+func (req updateViewRequest) Validate(v *validation.Validator) {
+	if req.Name == nil && req.Filters == nil {
+		v.Add("", validation.CodeEmpty, "no fields to update")
+		return
+	}
+	if req.Name != nil {
+		v.NotBlank("name", req.Name)
+		v.MaxLength("name", *req.Name, viewsdomain.MaxNameLength)
+	}
+	if _, ok := filterStrings(req.Filters); !ok {
+		v.Add("filters", validation.CodeInvalid, filtersFormatMessage)
+	}
+}
@@ -0,0 +1,306 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	viewsdomain "family-app-go/internal/domain/views"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"family-app-go/pkg/id"
+	"github.com/go-chi/chi/v5"
+)
+
+type createViewRequest struct {
+	Name       string                 `json:"name"`
+	EntityType string                 `json:"entity_type"`
+	Filters    map[string]interface{} `json:"filters"`
+}
+
+type updateViewRequest struct {
+	Name    *string                `json:"name"`
+	Filters map[string]interface{} `json:"filters"`
+}
+
+type viewResponse struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	EntityType string            `json:"entity_type"`
+	Filters    map[string]string `json:"filters"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+type viewListResponse struct {
+	Items []viewResponse `json:"items"`
+}
+
+func (h *Handlers) ListViews(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	entityType := viewsdomain.EntityType(strings.TrimSpace(r.URL.Query().Get("entity_type")))
+	views, err := h.Views.ListViews(r.Context(), user.ID, entityType)
+	if err != nil {
+		if errors.Is(err, viewsdomain.ErrInvalidEntityType) {
+			writeError(w, http.StatusBadRequest, "invalid_request", "invalid entity_type")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("views.list: list views failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := viewListResponse{Items: make([]viewResponse, 0, len(views))}
+	for _, view := range views {
+		item, err := toViewResponse(view)
+		if err != nil {
+			commonhandler.RequestLog(r, h.log).InternalError("views.list: decode filters failed", err, "user_id", user.ID, "view_id", view.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+		response.Items = append(response.Items, item)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handlers) CreateView(w http.ResponseWriter, r *http.Request) {
+	var req createViewRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	filters, _ := filterStrings(req.Filters)
+	view, err := h.Views.CreateView(r.Context(), user.ID, viewsdomain.CreateInput{
+		Name:       req.Name,
+		EntityType: viewsdomain.EntityType(strings.TrimSpace(req.EntityType)),
+		Filters:    filters,
+	})
+	if err != nil {
+		h.writeViewError(w, r, "views.create", err, user.ID)
+		return
+	}
+
+	h.writeView(w, r, http.StatusCreated, "views.create", *view)
+}
+
+func (h *Handlers) GetView(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	view, ok := h.loadView(w, r, "views.get", user.ID)
+	if !ok {
+		return
+	}
+	h.writeView(w, r, http.StatusOK, "views.get", *view)
+}
+
+func (h *Handlers) UpdateView(w http.ResponseWriter, r *http.Request) {
+	var req updateViewRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	viewID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if !id.IsUUID(viewID) {
+		writeError(w, http.StatusNotFound, "view_not_found", "view not found")
+		return
+	}
+
+	input := viewsdomain.UpdateInput{Name: req.Name}
+	if req.Filters != nil {
+		input.Filters, _ = filterStrings(req.Filters)
+	}
+	view, err := h.Views.UpdateView(r.Context(), user.ID, viewID, input)
+	if err != nil {
+		h.writeViewError(w, r, "views.update", err, user.ID)
+		return
+	}
+
+	h.writeView(w, r, http.StatusOK, "views.update", *view)
+}
+
+func (h *Handlers) DeleteView(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	viewID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if !id.IsUUID(viewID) {
+		writeError(w, http.StatusNotFound, "view_not_found", "view not found")
+		return
+	}
+
+	if err := h.Views.DeleteView(r.Context(), user.ID, viewID); err != nil {
+		h.writeViewError(w, r, "views.delete", err, user.ID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Results runs a saved view by replaying its filters against the list
+// endpoint of its entity type. targets must carry the same access checks as
+// the routes they stand for. limit and offset on the request page through
+// the results.
+func (h *Handlers) Results(targets map[viewsdomain.EntityType]http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := middleware.UserFromContext(r.Context())
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+			return
+		}
+
+		view, ok := h.loadView(w, r, "views.results", user.ID)
+		if !ok {
+			return
+		}
+		target, ok := targets[view.EntityType]
+		if !ok {
+			commonhandler.RequestLog(r, h.log).InternalError("views.results: no list endpoint", viewsdomain.ErrInvalidEntityType, "user_id", user.ID, "view_id", view.ID, "entity_type", view.EntityType)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+
+		filters, err := h.Views.ResolveFilters(*view)
+		if err != nil {
+			commonhandler.RequestLog(r, h.log).InternalError("views.results: resolve filters failed", err, "user_id", user.ID, "view_id", view.ID)
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+
+		query := url.Values{}
+		for key, value := range filters {
+			query.Set(key, value)
+		}
+		for _, key := range []string{"limit", "offset"} {
+			if value := r.URL.Query().Get(key); value != "" {
+				query.Set(key, value)
+			}
+		}
+
+		// Path parameters of the list endpoint travel as filters.
+		routeCtx := chi.NewRouteContext()
+		if listID := query.Get("list_id"); listID != "" {
+			routeCtx.URLParams.Add("list_id", listID)
+			query.Del("list_id")
+		}
+
+		proxied := r.Clone(context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx))
+		proxied.URL.RawQuery = query.Encode()
+		target.ServeHTTP(w, proxied)
+	}
+}
+
+func (h *Handlers) loadView(w http.ResponseWriter, r *http.Request, op, userID string) (*viewsdomain.SavedView, bool) {
+	viewID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if !id.IsUUID(viewID) {
+		writeError(w, http.StatusNotFound, "view_not_found", "view not found")
+		return nil, false
+	}
+
+	view, err := h.Views.GetView(r.Context(), userID, viewID)
+	if err != nil {
+		h.writeViewError(w, r, op, err, userID)
+		return nil, false
+	}
+	return view, true
+}
+
+func (h *Handlers) writeView(w http.ResponseWriter, r *http.Request, status int, op string, view viewsdomain.SavedView) {
+	response, err := toViewResponse(view)
+	if err != nil {
+		commonhandler.RequestLog(r, h.log).InternalError(op+": decode filters failed", err, "user_id", view.UserID, "view_id", view.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+	writeJSON(w, status, response)
+}
+
+func (h *Handlers) writeViewError(w http.ResponseWriter, r *http.Request, op string, err error, userID string) {
+	switch {
+	case errors.Is(err, viewsdomain.ErrViewNotFound):
+		writeError(w, http.StatusNotFound, "view_not_found", "view not found")
+	case errors.Is(err, viewsdomain.ErrInvalidName):
+		writeValidationError(w, validation.FieldErr("name", validation.CodeInvalid, fmt.Sprintf("name is required and must be at most %d characters", viewsdomain.MaxNameLength)))
+	case errors.Is(err, viewsdomain.ErrInvalidEntityType):
+		writeValidationError(w, validation.FieldErr("entity_type", validation.CodeEnum, "entity_type must be one of expenses, todo_lists, todo_items, workouts"))
+	case errors.Is(err, viewsdomain.ErrInvalidFilters):
+		writeValidationError(w, validation.FieldErr("filters", validation.CodeInvalid, err.Error()))
+	case errors.Is(err, viewsdomain.ErrTooManyViews):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": too many views", err, "user_id", userID)
+		writeError(w, http.StatusConflict, "too_many_views", fmt.Sprintf("at most %d saved views are allowed", viewsdomain.MaxViewsPerUser))
+	default:
+		commonhandler.RequestLog(r, h.log).InternalError(op+": failed", err, "user_id", userID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+	}
+}
+
+// filterStrings turns filter values into query parameter values. Arrays are
+// joined with commas, as the list endpoints take them.
+func filterStrings(values map[string]interface{}) (map[string]string, bool) {
+	filters := make(map[string]string, len(values))
+	for key, value := range values {
+		switch typed := value.(type) {
+		case string:
+			filters[key] = typed
+		case float64, bool:
+			filters[key] = fmt.Sprint(typed)
+		case nil:
+		case []interface{}:
+			parts := make([]string, 0, len(typed))
+			for _, item := range typed {
+				part, ok := item.(string)
+				if !ok {
+					return nil, false
+				}
+				parts = append(parts, part)
+			}
+			filters[key] = strings.Join(parts, ",")
+		default:
+			return nil, false
+		}
+	}
+	return filters, true
+}
+
+func toViewResponse(view viewsdomain.SavedView) (viewResponse, error) {
+	filters, err := viewsdomain.Filters(view)
+	if err != nil {
+		return viewResponse{}, err
+	}
+	return viewResponse{
+		ID:         view.ID,
+		Name:       view.Name,
+		EntityType: string(view.EntityType),
+		Filters:    filters,
+		CreatedAt:  view.CreatedAt,
+		UpdatedAt:  view.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,17 @@
+package wishlist
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	Wishlist WishlistService
+	log      logger.Logger
+}
+
+func New(wishlist WishlistService, log logger.Logger) *Handlers {
+	return &Handlers{
+		Wishlist: wishlist,
+		log:      log,
+	}
+}
@@ -0,0 +1,17 @@
+package wishlist
+
+import (
+	"context"
+
+	wishlistdomain "family-app-go/internal/domain/wishlist"
+)
+
+// WishlistService is implemented by *wishlistdomain.Service.
+type WishlistService interface {
+	ClaimItem(ctx context.Context, userID, itemID string) (*wishlistdomain.Item, error)
+	CreateItem(ctx context.Context, userID string, input wishlistdomain.ItemInput) (*wishlistdomain.Item, error)
+	DeleteItem(ctx context.Context, userID, itemID string) error
+	ListItems(ctx context.Context, viewerID, ownerID string) ([]wishlistdomain.Item, error)
+	UnclaimItem(ctx context.Context, userID, itemID string) (*wishlistdomain.Item, error)
+	UpdateItem(ctx context.Context, userID, itemID string, input wishlistdomain.ItemInput) (*wishlistdomain.Item, error)
+}
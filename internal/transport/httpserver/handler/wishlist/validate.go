@@ -0,0 +1,16 @@
+package wishlist
+
+import "family-app-go/internal/transport/httpserver/validation"
+
+func (req wishlistItemRequest) Validate(v *validation.Validator) {
+	v.Required("title", req.Title)
+	v.NonNegativeFloat("price", req.Price)
+	v.CurrencyCode("currency", req.Currency)
+	if req.Price != nil {
+		currency := ""
+		if req.Currency != nil {
+			currency = *req.Currency
+		}
+		v.Precision("price", *req.Price, currency)
+	}
+}
@@ -0,0 +1,241 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	wishlistdomain "family-app-go/internal/domain/wishlist"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"family-app-go/internal/transport/httpserver/validation"
+	"github.com/go-chi/chi/v5"
+)
+
+type wishlistItemRequest struct {
+	Title    string   `json:"title"`
+	URL      *string  `json:"url"`
+	Price    *float64 `json:"price"`
+	Currency *string  `json:"currency"`
+	Note     *string  `json:"note"`
+}
+
+type wishlistItemResponse struct {
+	ID          string     `json:"id"`
+	OwnerID     string     `json:"owner_id"`
+	Title       string     `json:"title"`
+	URL         *string    `json:"url"`
+	Price       *float64   `json:"price"`
+	Currency    *string    `json:"currency"`
+	Note        *string    `json:"note"`
+	IsClaimed   bool       `json:"is_claimed"`
+	ClaimedByID *string    `json:"claimed_by_id"`
+	ClaimedAt   *time.Time `json:"claimed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type wishlistItemListResponse struct {
+	Items []wishlistItemResponse `json:"items"`
+}
+
+func (h *Handlers) ListWishlistItems(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	ownerID := strings.TrimSpace(chi.URLParam(r, "user_id"))
+	if ownerID == "" || ownerID == "me" {
+		ownerID = user.ID
+	}
+
+	items, err := h.Wishlist.ListItems(r.Context(), user.ID, ownerID)
+	if err != nil {
+		if h.writeDomainError(w, r, "wishlist.list_items", err, user.ID) {
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("wishlist.list_items: list items failed", err, "user_id", user.ID, "owner_id", ownerID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	response := make([]wishlistItemResponse, 0, len(items))
+	for _, item := range items {
+		response = append(response, toWishlistItemResponse(item))
+	}
+	writeJSON(w, http.StatusOK, wishlistItemListResponse{Items: response})
+}
+
+func (h *Handlers) CreateWishlistItem(w http.ResponseWriter, r *http.Request) {
+	var req wishlistItemRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	item, err := h.Wishlist.CreateItem(r.Context(), user.ID, toItemInput(req))
+	if err != nil {
+		if h.writeDomainError(w, r, "wishlist.create_item", err, user.ID) {
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("wishlist.create_item: create item failed", err, "user_id", user.ID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toWishlistItemResponse(*item))
+}
+
+func (h *Handlers) UpdateWishlistItem(w http.ResponseWriter, r *http.Request) {
+	var req wishlistItemRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	itemID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if itemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	item, err := h.Wishlist.UpdateItem(r.Context(), user.ID, itemID, toItemInput(req))
+	if err != nil {
+		if h.writeDomainError(w, r, "wishlist.update_item", err, user.ID) {
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("wishlist.update_item: update item failed", err, "user_id", user.ID, "item_id", itemID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toWishlistItemResponse(*item))
+}
+
+func (h *Handlers) DeleteWishlistItem(w http.ResponseWriter, r *http.Request) {
+	itemID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if itemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	if err := h.Wishlist.DeleteItem(r.Context(), user.ID, itemID); err != nil {
+		if h.writeDomainError(w, r, "wishlist.delete_item", err, user.ID) {
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("wishlist.delete_item: delete item failed", err, "user_id", user.ID, "item_id", itemID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handlers) ClaimWishlistItem(w http.ResponseWriter, r *http.Request) {
+	h.changeClaim(w, r, "wishlist.claim_item", h.Wishlist.ClaimItem)
+}
+
+func (h *Handlers) UnclaimWishlistItem(w http.ResponseWriter, r *http.Request) {
+	h.changeClaim(w, r, "wishlist.unclaim_item", h.Wishlist.UnclaimItem)
+}
+
+func (h *Handlers) changeClaim(w http.ResponseWriter, r *http.Request, op string, change func(ctx context.Context, userID, itemID string) (*wishlistdomain.Item, error)) {
+	itemID := strings.TrimSpace(chi.URLParam(r, "id"))
+	if itemID == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", "id is required")
+		return
+	}
+
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	item, err := change(r.Context(), user.ID, itemID)
+	if err != nil {
+		if h.writeDomainError(w, r, op, err, user.ID) {
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError(op+": change claim failed", err, "user_id", user.ID, "item_id", itemID)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toWishlistItemResponse(*item))
+}
+
+// writeDomainError maps expected wishlist errors to responses and reports
+// whether the error was handled.
+func (h *Handlers) writeDomainError(w http.ResponseWriter, r *http.Request, op string, err error, userID string) bool {
+	switch {
+	case errors.Is(err, familydomain.ErrFamilyNotFound):
+		commonhandler.RequestLog(r, h.log).BusinessError(op+": family not found", err, "user_id", userID)
+		writeError(w, http.StatusNotFound, "family_not_found", "family not found")
+	case errors.Is(err, wishlistdomain.ErrMemberNotFound):
+		writeError(w, http.StatusNotFound, "member_not_found", "member not found")
+	case errors.Is(err, wishlistdomain.ErrItemNotFound):
+		writeError(w, http.StatusNotFound, "wishlist_item_not_found", "wishlist item not found")
+	case errors.Is(err, wishlistdomain.ErrTitleRequired):
+		writeValidationError(w, validation.FieldErr("title", validation.CodeRequired, "title is required"))
+	case errors.Is(err, wishlistdomain.ErrNotItemOwner):
+		writeError(w, http.StatusForbidden, "not_item_owner", "only the wishlist owner can change this item")
+	case errors.Is(err, wishlistdomain.ErrCannotClaimOwnItem):
+		writeError(w, http.StatusForbidden, "cannot_claim_own_item", "cannot claim own wishlist item")
+	case errors.Is(err, wishlistdomain.ErrItemAlreadyClaimed):
+		writeError(w, http.StatusConflict, "item_already_claimed", "wishlist item already claimed")
+	case errors.Is(err, wishlistdomain.ErrNotClaimer):
+		writeError(w, http.StatusForbidden, "not_claimer", "wishlist item claimed by another member")
+	default:
+		return false
+	}
+	return true
+}
+
+func toItemInput(req wishlistItemRequest) wishlistdomain.ItemInput {
+	return wishlistdomain.ItemInput{
+		Title:    req.Title,
+		URL:      req.URL,
+		Price:    req.Price,
+		Currency: req.Currency,
+		Note:     req.Note,
+	}
+}
+
+func toWishlistItemResponse(item wishlistdomain.Item) wishlistItemResponse {
+	return wishlistItemResponse{
+		ID:          item.ID,
+		OwnerID:     item.OwnerID,
+		Title:       item.Title,
+		URL:         item.URL,
+		Price:       item.Price,
+		Currency:    item.Currency,
+		Note:        item.Note,
+		IsClaimed:   item.ClaimedByID != nil,
+		ClaimedByID: item.ClaimedByID,
+		ClaimedAt:   item.ClaimedAt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package yearreview
+
+import (
+	"family-app-go/pkg/logger"
+)
+
+type Handlers struct {
+	YearReview YearReviewService
+	log        logger.Logger
+}
+
+func New(yearReview YearReviewService, log logger.Logger) *Handlers {
+	return &Handlers{
+		YearReview: yearReview,
+		log:        log,
+	}
+}
@@ -0,0 +1,13 @@
+package yearreview
+
+import (
+	"context"
+	"time"
+
+	yearreviewdomain "family-app-go/internal/domain/yearreview"
+)
+
+// YearReviewService is implemented by *yearreviewdomain.Service.
+type YearReviewService interface {
+	YearInReview(ctx context.Context, familyID string, year int, baseCurrency string) (yearreviewdomain.Review, time.Time, error)
+}
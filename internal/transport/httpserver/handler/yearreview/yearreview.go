@@ -0,0 +1,134 @@
+package yearreview
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	yearreviewdomain "family-app-go/internal/domain/yearreview"
+	commonhandler "family-app-go/internal/transport/httpserver/handler/common"
+	"family-app-go/internal/transport/httpserver/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type merchantResponse struct {
+	Name         string  `json:"name"`
+	Expenses     int64   `json:"expenses"`
+	AmountInBase float64 `json:"amount_in_base"`
+}
+
+type todoMemberResponse struct {
+	UserID         string  `json:"user_id"`
+	Name           *string `json:"name"`
+	ItemsCompleted int64   `json:"items_completed"`
+}
+
+type liftResponse struct {
+	UserID   string  `json:"user_id"`
+	Exercise string  `json:"exercise"`
+	WeightKg float64 `json:"weight_kg"`
+	Reps     int     `json:"reps"`
+	Date     string  `json:"date"`
+}
+
+type streakResponse struct {
+	UserID string `json:"user_id"`
+	Days   int    `json:"days"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+type yearInReviewResponse struct {
+	Year          int                 `json:"year"`
+	BaseCurrency  string              `json:"base_currency"`
+	TotalSpent    float64             `json:"total_spent"`
+	Expenses      int64               `json:"expenses"`
+	Unconverted   int64               `json:"unconverted"`
+	TopMerchant   *merchantResponse   `json:"top_merchant"`
+	TopTodoMember *todoMemberResponse `json:"top_todo_member"`
+	HeaviestLift  *liftResponse       `json:"heaviest_lift"`
+	LongestStreak *streakResponse     `json:"longest_streak"`
+	GeneratedAt   time.Time           `json:"generated_at"`
+}
+
+// YearInReview returns the family's wrap-up of a year. The review is cached,
+// a finished year far longer than the running one, and Cache-Control tells
+// clients how long they may keep it.
+func (h *Handlers) YearInReview(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
+		return
+	}
+
+	year, err := strconv.Atoi(chi.URLParam(r, "year"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "year must be a number")
+		return
+	}
+
+	family, ok := requireFamily(w, r)
+	if !ok {
+		return
+	}
+
+	review, expiresAt, err := h.YearReview.YearInReview(r.Context(), family.ID, year, family.DefaultCurrency)
+	if err != nil {
+		if errors.Is(err, yearreviewdomain.ErrInvalidYear) {
+			writeError(w, http.StatusBadRequest, "invalid_request", "year must be between "+strconv.Itoa(yearreviewdomain.MinYear)+" and the current year")
+			return
+		}
+		commonhandler.RequestLog(r, h.log).InternalError("reports.year_in_review: build failed", err, "user_id", user.ID, "family_id", family.ID, "year", year)
+		writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+		return
+	}
+
+	if maxAge := int(time.Until(expiresAt).Seconds()); maxAge > 0 {
+		w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(maxAge))
+	}
+	writeJSON(w, http.StatusOK, toYearInReviewResponse(review))
+}
+
+func toYearInReviewResponse(review yearreviewdomain.Review) yearInReviewResponse {
+	response := yearInReviewResponse{
+		Year:         review.Year,
+		BaseCurrency: review.BaseCurrency,
+		TotalSpent:   review.TotalSpent,
+		Expenses:     review.Expenses,
+		Unconverted:  review.Unconverted,
+		GeneratedAt:  review.GeneratedAt,
+	}
+	if merchant := review.TopMerchant; merchant != nil {
+		response.TopMerchant = &merchantResponse{
+			Name:         merchant.Name,
+			Expenses:     merchant.Expenses,
+			AmountInBase: merchant.AmountInBase,
+		}
+	}
+	if member := review.TopTodoMember; member != nil {
+		response.TopTodoMember = &todoMemberResponse{
+			UserID:         member.UserID,
+			Name:           member.Name,
+			ItemsCompleted: member.ItemsCompleted,
+		}
+	}
+	if lift := review.HeaviestLift; lift != nil {
+		response.HeaviestLift = &liftResponse{
+			UserID:   lift.UserID,
+			Exercise: lift.Exercise,
+			WeightKg: lift.WeightKg,
+			Reps:     lift.Reps,
+			Date:     lift.Date.Format("2006-01-02"),
+		}
+	}
+	if streak := review.LongestStreak; streak != nil {
+		response.LongestStreak = &streakResponse{
+			UserID: streak.UserID,
+			Days:   streak.Days,
+			From:   streak.From.Format("2006-01-02"),
+			To:     streak.To.Format("2006-01-02"),
+		}
+	}
+	return response
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	familydomain "family-app-go/internal/domain/family"
+	gymdomain "family-app-go/internal/domain/gym"
+	"family-app-go/pkg/logger"
+)
+
+// MemberRoleProvider resolves the caller's membership together with the
+// family it is in.
+type MemberRoleProvider interface {
+	GetMembership(ctx context.Context, userID string) (*familydomain.FamilyMember, error)
+}
+
+// FamilyAccess resolves the caller's family and role once per request so
+// handlers read them from the context and route groups can be restricted
+// without each handler checking it.
+type FamilyAccess struct {
+	roles MemberRoleProvider
+	log   logger.Logger
+}
+
+func NewFamilyAccess(roles MemberRoleProvider, log logger.Logger) *FamilyAccess {
+	return &FamilyAccess{roles: roles, log: log}
+}
+
+// Middleware stores the caller's family and role in the request context and
+// tags its logger with the family ID. Users without a family get neither.
+func (a *FamilyAccess) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := a.WithRole(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// WithRole returns ctx carrying the family and role of the authenticated
+// user, or ctx unchanged when there is no user or the user has no family.
+func (a *FamilyAccess) WithRole(ctx context.Context) (context.Context, error) {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	member, err := a.roles.GetMembership(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, familydomain.ErrFamilyNotFound) {
+			return ctx, nil
+		}
+		if a.log != nil {
+			logger.FromContext(ctx, a.log).InternalError("access: get membership failed", err, "user_id", user.ID)
+		}
+		return nil, err
+	}
+	family := &member.Family
+	ctx = logger.WithFamilyID(ctx, family.ID)
+	ctx = WithFamily(ctx, family)
+	return WithFamilyRole(ctx, member.Role), nil
+}
+
+// DenyChild rejects child members.
+func DenyChild(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsChild(r.Context()) {
+			childRestricted(w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// DenyChildFamilyScope rejects child members asking for other members' data
+// through scope=family. The scope is read the way the gym handlers read it,
+// so no spelling of it gets past.
+func DenyChildFamilyScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if kind, _ := gymdomain.ParseScopeKind(r.URL.Query().Get("scope")); IsChild(r.Context()) && kind == gymdomain.ScopeFamily {
+			childRestricted(w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireOwner rejects everyone but the family owner.
+func RequireOwner(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if role, ok := FamilyRoleFromContext(r.Context()); !ok || role != familydomain.RoleOwner {
+			writeError(w, http.StatusForbidden, "not_owner", "only the family owner can do this")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func WithFamily(ctx context.Context, family *familydomain.Family) context.Context {
+	return context.WithValue(ctx, familyKey, family)
+}
+
+// FamilyFromContext returns the caller's family resolved by FamilyAccess.
+// Handlers must not modify it.
+func FamilyFromContext(ctx context.Context) (*familydomain.Family, bool) {
+	family, ok := ctx.Value(familyKey).(*familydomain.Family)
+	return family, ok && family != nil
+}
+
+func WithFamilyRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, familyRoleKey, role)
+}
+
+func FamilyRoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(familyRoleKey).(string)
+	if !ok || role == "" {
+		return "", false
+	}
+	return role, true
+}
+
+func IsChild(ctx context.Context) bool {
+	role, ok := FamilyRoleFromContext(ctx)
+	return ok && role == familydomain.RoleChild
+}
+
+// AssigneeRestriction returns the caller's user ID when they may only see
+// items assigned to them, and an empty string otherwise.
+func AssigneeRestriction(ctx context.Context) string {
+	if !IsChild(ctx) {
+		return ""
+	}
+	userID, _ := UserIDFromContext(ctx)
+	return userID
+}
+
+func childRestricted(w http.ResponseWriter) {
+	writeError(w, http.StatusForbidden, "child_restricted", "not available for child members")
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	familydomain "family-app-go/internal/domain/family"
+)
+
+func TestDenyChildFamilyScope(t *testing.T) {
+	cases := []struct {
+		name   string
+		role   string
+		target string
+		status int
+	}{
+		{name: "child family", role: familydomain.RoleChild, target: "/api/gym/workouts?scope=family", status: http.StatusForbidden},
+		{name: "child mixed case", role: familydomain.RoleChild, target: "/api/gym/workouts?scope=Family", status: http.StatusForbidden},
+		{name: "child padded", role: familydomain.RoleChild, target: "/api/gym/workouts?scope=%20FAMILY%20", status: http.StatusForbidden},
+		{name: "child me", role: familydomain.RoleChild, target: "/api/gym/workouts?scope=me", status: http.StatusOK},
+		{name: "child default", role: familydomain.RoleChild, target: "/api/gym/workouts", status: http.StatusOK},
+		{name: "member family", role: familydomain.RoleMember, target: "/api/gym/workouts?scope=FAMILY", status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req = req.WithContext(WithFamilyRole(req.Context(), tc.role))
+			rec := httptest.NewRecorder()
+			DenyChildFamilyScope(next).ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+type countingRoles struct {
+	member *familydomain.FamilyMember
+	calls  int
+}
+
+func (r *countingRoles) GetMembership(context.Context, string) (*familydomain.FamilyMember, error) {
+	r.calls++
+	if r.member == nil {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	return r.member, nil
+}
+
+func TestWithRoleResolvesFamilyAndRoleInOneCall(t *testing.T) {
+	roles := &countingRoles{member: &familydomain.FamilyMember{
+		FamilyID: "family-1",
+		UserID:   "user-1",
+		Role:     familydomain.RoleChild,
+		Family:   familydomain.Family{ID: "family-1", DefaultCurrency: "EUR"},
+	}}
+	ctx, err := NewFamilyAccess(roles, nil).WithRole(WithUser(context.Background(), User{ID: "user-1"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if roles.calls != 1 {
+		t.Fatalf("expected one membership lookup, got %d", roles.calls)
+	}
+	family, ok := FamilyFromContext(ctx)
+	if !ok || family.ID != "family-1" || family.DefaultCurrency != "EUR" {
+		t.Fatalf("expected family-1 in the context, got %+v", family)
+	}
+	if !IsChild(ctx) {
+		t.Fatal("expected the child role in the context")
+	}
+}
+
+func TestWithRoleLeavesUsersWithoutFamily(t *testing.T) {
+	ctx, err := NewFamilyAccess(&countingRoles{}, nil).WithRole(WithUser(context.Background(), User{ID: "user-1"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := FamilyFromContext(ctx); ok {
+		t.Fatal("expected no family in the context")
+	}
+	if _, ok := FamilyRoleFromContext(ctx); ok {
+		t.Fatal("expected no role in the context")
+	}
+}
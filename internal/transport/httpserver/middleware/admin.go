@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminToken guards the operator API with a static bearer token. With an
+// empty token the routes answer 404 as if they did not exist.
+func AdminToken(token string) func(http.Handler) http.Handler {
+	expected := sha256.Sum256([]byte(token))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				writeError(w, http.StatusNotFound, "not_found", "not found")
+				return
+			}
+
+			provided, ok := bearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				writeError(w, http.StatusUnauthorized, "invalid_admin_token", "invalid admin token")
+				return
+			}
+			// Comparing digests keeps the check constant-time regardless of
+			// the provided token's length.
+			actual := sha256.Sum256([]byte(provided))
+			if subtle.ConstantTimeCompare(actual[:], expected[:]) != 1 {
+				writeError(w, http.StatusUnauthorized, "invalid_admin_token", "invalid admin token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	auditdomain "family-app-go/internal/domain/audit"
+)
+
+// SecurityAuditor records security events for incident investigation.
+type SecurityAuditor interface {
+	Record(ctx context.Context, input auditdomain.Input) error
+}
+
+// AuditInput starts a security event for the request: the client address,
+// user agent, request ID and, once authenticated, the caller and the API key
+// they used. Callers add the family, target and details.
+func AuditInput(r *http.Request, eventType string) auditdomain.Input {
+	input := auditdomain.Input{
+		Type:      eventType,
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
+		RequestID: RequestIDFromContext(r.Context()),
+	}
+	if userID, ok := UserIDFromContext(r.Context()); ok {
+		input.ActorID = userID
+	}
+	if key, ok := APIKeyFromContext(r.Context()); ok {
+		input.APIKeyID = key.ID
+	}
+	return input
+}
+
+// clientIP is RemoteAddr without the port. chi's RealIP has already
+// replaced it with the forwarded address when there was one.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
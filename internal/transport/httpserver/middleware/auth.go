@@ -2,41 +2,79 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	"family-app-go/internal/config"
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	auditdomain "family-app-go/internal/domain/audit"
+	sessionsdomain "family-app-go/internal/domain/sessions"
+	userdomain "family-app-go/internal/domain/user"
 	"family-app-go/pkg/logger"
 )
 
-type SupabaseAuth struct {
-	baseURL  string
-	apiKey   string
-	client   *http.Client
+// Auth authenticates requests with the configured provider, caches the
+// resolved users and keeps their profiles in sync.
+type Auth struct {
+	provider AuthProvider
+	apiKeys  APIKeyVerifier
+	sessions SessionTracker
 	log      logger.Logger
 	profiles ProfileSaver
+	cache    AuthCache
+	audit    SecurityAuditor
+	cacheTTL time.Duration
 	skipAuth bool
 	mockUser User
 }
 
+// AuthProvider turns a bearer token into a user.
+type AuthProvider interface {
+	Name() string
+	Configured() bool
+	Authenticate(r *http.Request, token string) (User, bool)
+}
+
+// APIKeyVerifier resolves per-user API keys. Keys are recognised by their
+// prefix and never cached, so revocation applies to the next request.
+type APIKeyVerifier interface {
+	Authenticate(ctx context.Context, key string) (*apikeysdomain.Key, error)
+}
+
+// SessionTracker records the devices behind access tokens so users can list
+// and revoke them. It runs when a token is not in the auth cache.
+type SessionTracker interface {
+	Track(ctx context.Context, input sessionsdomain.TrackInput) (*sessionsdomain.Session, error)
+}
+
+// DeviceNameHeader lets clients label their session, e.g. "Anna's iPhone".
+// Without it the session list shows the User-Agent.
+const DeviceNameHeader = "X-Device-Name"
+
 type contextKey int
 
 const (
 	userIDKey contextKey = iota
 	userKey
+	familyRoleKey
+	apiKeyKey
+	familyKey
+	sessionIDKey
 )
 
-type userResponse struct {
-	ID           string                 `json:"id"`
-	Email        string                 `json:"email"`
-	Sub          string                 `json:"sub"`
-	UserMetadata map[string]interface{} `json:"user_metadata"`
-	User         struct {
-		ID  string `json:"id"`
-		Sub string `json:"sub"`
-	} `json:"user"`
+// APIKey describes the key a request was authenticated with.
+type APIKey struct {
+	ID    string
+	Scope string
+}
+
+func (k APIKey) ReadOnly() bool {
+	return k.Scope == apikeysdomain.ScopeReadOnly
 }
 
 type User struct {
@@ -46,23 +84,29 @@ type User struct {
 	AvatarURL string
 }
 
-type ProfileSaver interface {
-	UpsertProfile(ctx context.Context, userID, email, avatarURL string) error
+// AuthCache keeps resolved users by token hash so repeated requests with the
+// same token skip verification.
+type AuthCache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
 }
 
-func NewSupabaseAuth(cfg config.SupabaseConfig, profiles ProfileSaver, log logger.Logger) *SupabaseAuth {
-	baseURL := strings.TrimRight(cfg.URL, "/")
-	timeout := cfg.AuthTimeout
-	if timeout == 0 {
-		timeout = 5 * time.Second
-	}
+type ProfileSaver interface {
+	UpsertProfile(ctx context.Context, userID, email, avatarURL string) (*userdomain.Profile, error)
+}
 
-	return &SupabaseAuth{
-		baseURL: baseURL,
-		apiKey:  cfg.PublishableKey,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+// NewAuth builds the authenticator. audit may be nil, which skips recording
+// rejected tokens and API key use, and so may sessions, which skips session
+// tracking.
+func NewAuth(cfg config.SupabaseConfig, provider AuthProvider, apiKeys APIKeyVerifier, sessions SessionTracker, profiles ProfileSaver, cache AuthCache, audit SecurityAuditor, log logger.Logger) *Auth {
+	return &Auth{
+		provider: provider,
+		apiKeys:  apiKeys,
+		sessions: sessions,
+		cache:    cache,
+		audit:    audit,
+		cacheTTL: cfg.CacheTTL,
 		log:      log,
 		profiles: profiles,
 		skipAuth: cfg.SkipAuth,
@@ -75,117 +119,310 @@ func NewSupabaseAuth(cfg config.SupabaseConfig, profiles ProfileSaver, log logge
 	}
 }
 
-func (a *SupabaseAuth) Middleware(next http.Handler) http.Handler {
+var (
+	ErrUnauthenticated   = errors.New("unauthenticated")
+	ErrAuthNotConfigured = errors.New("auth not configured")
+)
+
+func (a *Auth) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestMethod := r.Method
-		requestPath := r.URL.Path
-
-		if a.skipAuth {
-			user := a.mockUser
-			if user.ID == "" {
-				a.log.Error("auth: mock auth user id not configured", "method", requestMethod, "path", requestPath)
-				writeError(w, http.StatusInternalServerError, "auth_not_configured", "auth mock user id not configured")
-				return
-			}
-			if a.profiles != nil {
-				if err := a.profiles.UpsertProfile(r.Context(), user.ID, user.Email, user.AvatarURL); err != nil {
-					a.log.Warn("auth: upsert profile failed", "user_id", user.ID, "err", err)
+		token, ok := bearerToken(r.Header.Get("Authorization"))
+		if !ok {
+			token = strings.TrimSpace(r.Header.Get("X-API-Key"))
+		}
+
+		ctx, err := a.Authenticate(r, token)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrUnauthenticated):
+				unauthorized(w)
+			case errors.Is(err, ErrAuthNotConfigured):
+				message := "auth not configured"
+				if a.skipAuth {
+					message = "auth mock user id not configured"
 				}
+				writeError(w, http.StatusInternalServerError, "auth_not_configured", message)
+			default:
+				writeError(w, http.StatusInternalServerError, "internal_error", "internal error")
 			}
-			ctx := WithUser(r.Context(), user)
-			next.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
-
-		if a.baseURL == "" || a.apiKey == "" {
-			a.log.Error(
-				"auth: provider not configured",
-				"method",
-				requestMethod,
-				"path",
-				requestPath,
-				"has_base_url",
-				a.baseURL != "",
-				"has_api_key",
-				a.apiKey != "",
-			)
-			writeError(w, http.StatusInternalServerError, "auth_not_configured", "auth not configured")
+		if key, ok := APIKeyFromContext(ctx); ok && key.ReadOnly() && !isSafeMethod(r.Method) {
+			writeError(w, http.StatusForbidden, "insufficient_scope", "api key is read-only")
 			return
 		}
 
-		authorizationHeader := r.Header.Get("Authorization")
-		token, ok := bearerToken(authorizationHeader)
-		if !ok {
-			a.log.Warn(
-				"auth: missing or invalid bearer token",
-				"method",
-				requestMethod,
-				"path",
-				requestPath,
-				"has_authorization_header",
-				strings.TrimSpace(authorizationHeader) != "",
-			)
-			unauthorized(w)
-			return
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Authenticate resolves the caller from a bearer token or API key and returns
+// the request context carrying the user. token is empty when the client sent
+// none. It is shared by the HTTP middleware and the gRPC interceptors; scope
+// checks on API keys are left to the caller.
+func (a *Auth) Authenticate(r *http.Request, token string) (context.Context, error) {
+	requestMethod := r.Method
+	requestPath := r.URL.Path
+
+	if a.skipAuth {
+		user := a.mockUser
+		if user.ID == "" {
+			logger.FromContext(r.Context(), a.log).Error("auth: mock auth user id not configured", "method", requestMethod, "path", requestPath)
+			return nil, ErrAuthNotConfigured
 		}
+		user = a.syncProfile(r.Context(), user)
+		return WithUser(r.Context(), user), nil
+	}
 
-		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, a.baseURL+"/auth/v1/user", nil)
-		if err != nil {
-			a.log.Error("auth: build supabase auth request failed", "method", requestMethod, "path", requestPath, "err", err)
-			unauthorized(w)
-			return
+	if a.provider == nil || !a.provider.Configured() {
+		provider := ""
+		if a.provider != nil {
+			provider = a.provider.Name()
 		}
-		req.Header.Set("Authorization", "Bearer "+token)
-		req.Header.Set("apikey", a.apiKey)
+		logger.FromContext(r.Context(), a.log).Error("auth: provider not configured", "method", requestMethod, "path", requestPath, "provider", provider)
+		return nil, ErrAuthNotConfigured
+	}
+
+	if token == "" {
+		logger.FromContext(r.Context(), a.log).Warn(
+			"auth: missing or invalid bearer token",
+			"method",
+			requestMethod,
+			"path",
+			requestPath,
+			"has_authorization_header",
+			strings.TrimSpace(r.Header.Get("Authorization")) != "",
+		)
+		return nil, ErrUnauthenticated
+	}
+
+	if strings.HasPrefix(token, apikeysdomain.KeyPrefix) {
+		return a.authenticateAPIKey(r, token)
+	}
 
-		resp, err := a.client.Do(req)
+	cacheKey := tokenCacheKey(token)
+	identity, ok := a.cachedIdentity(r.Context(), cacheKey)
+	if !ok {
+		user, ok := a.provider.Authenticate(r, token)
+		if !ok {
+			a.recordRejected(r, "bearer")
+			return nil, ErrUnauthenticated
+		}
+		sessionID, err := a.trackSession(r, token, cacheKey, user.ID)
 		if err != nil {
-			a.log.Error("auth: request to supabase failed", "method", requestMethod, "path", requestPath, "err", err)
-			unauthorized(w)
-			return
+			return nil, err
 		}
-		defer resp.Body.Close()
+		identity = cachedIdentity{User: user, SessionID: sessionID}
+		a.cacheIdentity(r.Context(), cacheKey, token, identity)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			if resp.StatusCode >= http.StatusInternalServerError {
-				a.log.Error("auth: supabase auth endpoint error", "method", requestMethod, "path", requestPath, "status_code", resp.StatusCode)
-			} else {
-				a.log.Warn("auth: supabase rejected token", "method", requestMethod, "path", requestPath, "status_code", resp.StatusCode)
-			}
-			unauthorized(w)
-			return
+	user := a.syncProfile(r.Context(), identity.User)
+	ctx := WithUser(r.Context(), user)
+	if identity.SessionID != "" {
+		ctx = WithSessionID(ctx, identity.SessionID)
+	}
+	return ctx, nil
+}
+
+// trackSession records the device behind a verified token and returns its
+// session ID. Tokens of a revoked session are rejected; other tracking
+// failures only leave the request without a session.
+func (a *Auth) trackSession(r *http.Request, token, tokenHash, userID string) (string, error) {
+	if a.sessions == nil {
+		return "", nil
+	}
+
+	input := sessionsdomain.TrackInput{
+		UserID:     userID,
+		TokenHash:  tokenHash,
+		DeviceName: r.Header.Get(DeviceNameHeader),
+		UserAgent:  r.UserAgent(),
+		IP:         clientIP(r),
+	}
+	if sessionID := tokenSessionID(token); sessionID != "" {
+		input.Key = a.provider.Name() + ":" + sessionID
+	} else {
+		input.Key = "token:" + tokenHash
+		if expiresAt, ok := tokenExpiry(token); ok {
+			input.ExpiresAt = &expiresAt
 		}
+	}
 
-		var payload userResponse
-		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-			a.log.Error("auth: decode supabase auth response failed", "method", requestMethod, "path", requestPath, "err", err)
-			unauthorized(w)
-			return
+	session, err := a.sessions.Track(r.Context(), input)
+	if err != nil {
+		if errors.Is(err, sessionsdomain.ErrSessionRevoked) {
+			logger.FromContext(r.Context(), a.log).Warn("auth: session revoked", "method", r.Method, "path", r.URL.Path, "user_id", userID)
+			a.recordRejected(r, "revoked_session")
+			return "", ErrUnauthenticated
 		}
+		logger.FromContext(r.Context(), a.log).Warn("auth: track session failed", "user_id", userID, "err", err)
+		return "", nil
+	}
+	return session.ID, nil
+}
 
-		userID := firstNonEmpty(payload.ID, payload.Sub, payload.User.ID, payload.User.Sub)
-		if userID == "" {
-			a.log.Warn("auth: supabase response missing user id", "method", requestMethod, "path", requestPath)
-			unauthorized(w)
-			return
+func (a *Auth) authenticateAPIKey(r *http.Request, token string) (context.Context, error) {
+	if a.apiKeys == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	key, err := a.apiKeys.Authenticate(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, apikeysdomain.ErrInvalidKey) {
+			logger.FromContext(r.Context(), a.log).Warn("auth: api key rejected", "method", r.Method, "path", r.URL.Path)
+			a.recordRejected(r, "api_key")
+			return nil, ErrUnauthenticated
 		}
+		logger.FromContext(r.Context(), a.log).Error("auth: api key lookup failed", "method", r.Method, "path", r.URL.Path, "err", err)
+		return nil, err
+	}
+
+	user := a.syncProfile(r.Context(), User{ID: key.UserID})
+	ctx := WithUser(r.Context(), user)
+	ctx = WithAPIKey(ctx, APIKey{ID: key.ID, Scope: key.Scope})
+	a.record(r.WithContext(ctx), auditdomain.EventAPIKeyUsed, map[string]string{"scope": key.Scope})
+	return ctx, nil
+}
+
+// recordRejected records a presented token that did not authenticate.
+// Requests without any token are not security events.
+func (a *Auth) recordRejected(r *http.Request, kind string) {
+	a.record(r, auditdomain.EventTokenRejected, map[string]string{
+		"kind":   kind,
+		"method": r.Method,
+		"path":   r.URL.Path,
+	})
+}
+
+func (a *Auth) record(r *http.Request, eventType string, details map[string]string) {
+	if a.audit == nil {
+		return
+	}
+	input := AuditInput(r, eventType)
+	input.Details = details
+	if err := a.audit.Record(r.Context(), input); err != nil {
+		logger.FromContext(r.Context(), a.log).Warn("auth: record security event failed", "type", eventType, "err", err)
+	}
+}
 
-		user := User{
-			ID:        userID,
-			Email:     payload.Email,
-			Name:      firstNonEmpty(stringFromMap(payload.UserMetadata, "name"), stringFromMap(payload.UserMetadata, "full_name")),
-			AvatarURL: stringFromMap(payload.UserMetadata, "avatar_url"),
+// Logout drops the caller's token from the auth cache so the next request
+// with it is verified again.
+func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := bearerToken(r.Header.Get("Authorization"))
+	if ok && a.cache != nil {
+		if err := a.cache.Delete(r.Context(), tokenCacheKey(token)); err != nil {
+			logger.FromContext(r.Context(), a.log).Warn("auth: invalidate cached token failed", "err", err)
 		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
-		if a.profiles != nil {
-			if err := a.profiles.UpsertProfile(r.Context(), user.ID, user.Email, user.AvatarURL); err != nil {
-				a.log.Warn("auth: upsert profile failed", "user_id", user.ID, "err", err)
-			}
+// cachedIdentity is what the auth cache keeps per token. It embeds User so
+// entries written before sessions were tracked still decode.
+type cachedIdentity struct {
+	User
+	SessionID string `json:",omitempty"`
+}
+
+func (a *Auth) cachedIdentity(ctx context.Context, key string) (cachedIdentity, bool) {
+	if a.cache == nil {
+		return cachedIdentity{}, false
+	}
+	data, ok, err := a.cache.Get(ctx, key)
+	if err != nil {
+		logger.FromContext(ctx, a.log).Warn("auth: read auth cache failed", "err", err)
+		return cachedIdentity{}, false
+	}
+	if !ok {
+		return cachedIdentity{}, false
+	}
+	var identity cachedIdentity
+	if err := json.Unmarshal(data, &identity); err != nil || identity.ID == "" {
+		return cachedIdentity{}, false
+	}
+	return identity, true
+}
+
+// cacheIdentity stores the resolved user for the cache TTL, or until the
+// token expires if that comes first.
+func (a *Auth) cacheIdentity(ctx context.Context, key, token string, identity cachedIdentity) {
+	if a.cache == nil || a.cacheTTL <= 0 {
+		return
+	}
+	ttl := a.cacheTTL
+	if expiresAt, ok := tokenExpiry(token); ok {
+		if remaining := time.Until(expiresAt); remaining < ttl {
+			ttl = remaining
 		}
+	}
+	if ttl <= 0 {
+		return
+	}
 
-		ctx := WithUser(r.Context(), user)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	data, err := json.Marshal(identity)
+	if err != nil {
+		return
+	}
+	if err := a.cache.Set(ctx, key, data, ttl); err != nil {
+		logger.FromContext(ctx, a.log).Warn("auth: write auth cache failed", "user_id", identity.ID, "err", err)
+	}
+}
+
+// syncProfile stores the provider profile and swaps in the avatar the user
+// uploaded, so snapshots taken from the request user pick it up.
+func (a *Auth) syncProfile(ctx context.Context, user User) User {
+	if a.profiles == nil {
+		return user
+	}
+	profile, err := a.profiles.UpsertProfile(ctx, user.ID, user.Email, user.AvatarURL)
+	if err != nil {
+		logger.FromContext(ctx, a.log).Warn("auth: upsert profile failed", "user_id", user.ID, "err", err)
+		return user
+	}
+	if avatarURL := profile.EffectiveAvatarURL(); avatarURL != "" {
+		user.AvatarURL = avatarURL
+	}
+	if user.Email == "" && profile.Email != nil {
+		user.Email = *profile.Email
+	}
+	return user
+}
+
+// tokenCacheKey hashes the token so raw credentials never reach the cache.
+func tokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// tokenExpiry reads exp from a token that has already been verified.
+func tokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.ExpiresAt, 0), true
+}
+
+// tokenSessionID reads the provider's session_id claim, which Supabase puts
+// in every access token of a sign-in.
+func tokenSessionID(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	var claims struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(claims.SessionID)
 }
 
 func bearerToken(value string) (string, bool) {
@@ -199,11 +436,20 @@ func bearerToken(value string) (string, bool) {
 	return parts[1], true
 }
 
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
 func unauthorized(w http.ResponseWriter) {
 	writeError(w, http.StatusUnauthorized, "invalid_token", "invalid token")
 }
 
 func WithUser(ctx context.Context, user User) context.Context {
+	ctx = logger.WithUserID(ctx, user.ID)
 	ctx = context.WithValue(ctx, userKey, user)
 	return context.WithValue(ctx, userIDKey, user.ID)
 }
@@ -221,6 +467,29 @@ func UserFromContext(ctx context.Context) (User, bool) {
 	return user, true
 }
 
+func WithAPIKey(ctx context.Context, key APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// APIKeyFromContext reports whether the request was authenticated with an
+// API key rather than a user session.
+func APIKeyFromContext(ctx context.Context) (APIKey, bool) {
+	key, ok := ctx.Value(apiKeyKey).(APIKey)
+	return key, ok && key.ID != ""
+}
+
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// SessionIDFromContext returns the session the request's access token
+// belongs to. Requests made with an API key, or before the session could be
+// recorded, have none.
+func SessionIDFromContext(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(sessionIDKey).(string)
+	return sessionID, ok && sessionID != ""
+}
+
 func UserIDFromContext(ctx context.Context) (string, bool) {
 	value := ctx.Value(userIDKey)
 	userID, ok := value.(string)
@@ -240,6 +509,9 @@ func firstNonEmpty(values ...string) string {
 }
 
 func writeError(w http.ResponseWriter, status int, code, message string) {
+	if WriteProblem(w, status, code, message, nil) {
+		return
+	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type originalBodyKey struct{}
+
+// BodyLimit caps the request body at limit bytes. Reads past the limit fail
+// with *http.MaxBytesError, which handlers answer with 413.
+//
+// The limit always wraps the original body rather than an earlier limit, so
+// the innermost BodyLimit wins: routers set a default with Use and raise it
+// for individual routes with With.
+func BodyLimit(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, ok := r.Context().Value(originalBodyKey{}).(io.ReadCloser)
+			if !ok {
+				body = r.Body
+				r = r.WithContext(context.WithValue(r.Context(), originalBodyKey{}, body))
+			}
+			if body != nil && body != http.NoBody {
+				r.Body = http.MaxBytesReader(w, body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
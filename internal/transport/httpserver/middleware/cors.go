@@ -23,7 +23,8 @@ func NewCORS(allowedOrigins []string) func(http.Handler) http.Handler {
 					w.Header().Add("Vary", "Origin")
 					w.Header().Set("Access-Control-Allow-Origin", origin)
 					w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
-					w.Header().Set("Access-Control-Allow-Headers", "Authorization,Content-Type")
+					w.Header().Set("Access-Control-Allow-Headers", "Authorization,Content-Type,If-Match,X-Request-ID,traceparent,tracestate")
+					w.Header().Set("Access-Control-Expose-Headers", "ETag,Retry-After,X-Request-ID")
 					w.Header().Set("Access-Control-Max-Age", "86400")
 				}
 			}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"family-app-go/internal/db"
+)
+
+// databaseWriter remembers whether a statement of the request hit the
+// database query timeout.
+type databaseWriter struct {
+	http.ResponseWriter
+	timedOut func() bool
+}
+
+func (w *databaseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *databaseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// DatabaseTimeouts tracks query timeouts per request so handlers that fail
+// with an internal error answer 503 instead; see DatabaseTimedOut. It also
+// counts the request's statements and their time for RequestLogger.
+func DatabaseTimeouts(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, timedOut := db.TrackTimeouts(db.TrackQueries(r.Context()))
+		next.ServeHTTP(&databaseWriter{ResponseWriter: w, timedOut: timedOut}, r.WithContext(ctx))
+	})
+}
+
+// DatabaseTimedOut reports whether a query of the request behind w ran into
+// the database query timeout.
+func DatabaseTimedOut(w http.ResponseWriter) bool {
+	for {
+		switch current := w.(type) {
+		case *databaseWriter:
+			return current.timedOut()
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = current.Unwrap()
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"family-app-go/internal/config"
+	"family-app-go/pkg/logger"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	debugLogHeader = "X-Debug-Log"
+	redacted       = "[REDACTED]"
+)
+
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	debugLogHeader:        true,
+}
+
+// redactedFields are JSON keys and query parameters holding credentials or
+// personal data. Keys containing "password", "token" or "secret" are
+// redacted as well.
+var redactedFields = map[string]bool{
+	"key":          true,
+	"api_key":      true,
+	"sig":          true,
+	"signature":    true,
+	"email":        true,
+	"phone":        true,
+	"display_name": true,
+	"full_name":    true,
+	"first_name":   true,
+	"last_name":    true,
+	"avatar_url":   true,
+	"address":      true,
+	"birth_date":   true,
+}
+
+// DebugLog logs the request and response of matching requests, with
+// credentials and personal data redacted, to reproduce production issues. A
+// request matches when its path starts with one of cfg.DebugLogRoutes or it
+// sends cfg.DebugLogToken in X-Debug-Log. With neither configured it is a
+// no-op. Bodies are captured up to cfg.DebugLogMaxBodyBytes as the handler
+// reads and writes them; longer or non-JSON bodies are summarized only.
+func DebugLog(cfg config.HTTPConfig, log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(cfg.DebugLogRoutes) == 0 && cfg.DebugLogToken == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !debugLogMatches(cfg, r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestBody := &captureBuffer{limit: cfg.DebugLogMaxBodyBytes}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = &teeBody{ReadCloser: r.Body, capture: requestBody}
+			}
+			responseBody := &captureBuffer{limit: cfg.DebugLogMaxBodyBytes}
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(responseBody)
+
+			startedAt := time.Now()
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			logger.FromContext(r.Context(), log).Info(
+				"http.debug: request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"query", redactQuery(r.URL.RawQuery),
+				"status", status,
+				"duration_ms", time.Since(startedAt).Milliseconds(),
+				"request_headers", redactHeaders(r.Header),
+				"request_body", sanitizeBody(r.Header.Get("Content-Type"), requestBody),
+				"response_headers", redactHeaders(ww.Header()),
+				"response_body", sanitizeBody(ww.Header().Get("Content-Type"), responseBody),
+			)
+		})
+	}
+}
+
+func debugLogMatches(cfg config.HTTPConfig, r *http.Request) bool {
+	for _, prefix := range cfg.DebugLogRoutes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	if cfg.DebugLogToken == "" {
+		return false
+	}
+	token := r.Header.Get(debugLogHeader)
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(cfg.DebugLogToken)) == 1
+}
+
+// captureBuffer keeps the first limit bytes written to it and counts the
+// rest.
+type captureBuffer struct {
+	limit int
+	data  []byte
+	total int
+}
+
+func (b *captureBuffer) Write(p []byte) (int, error) {
+	b.total += len(p)
+	if room := b.limit - len(b.data); room > 0 {
+		b.data = append(b.data, p[:min(len(p), room)]...)
+	}
+	return len(p), nil
+}
+
+func (b *captureBuffer) truncated() bool {
+	return b.total > len(b.data)
+}
+
+type teeBody struct {
+	io.ReadCloser
+	capture *captureBuffer
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = b.capture.Write(p[:n])
+	}
+	return n, err
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	values := make(map[string]string, len(header))
+	for name, value := range header {
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			values[name] = redacted
+			continue
+		}
+		values[name] = strings.Join(value, ", ")
+	}
+	return values
+}
+
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redacted
+	}
+	for name := range query {
+		if isRedactedField(name) {
+			query[name] = []string{redacted}
+		}
+	}
+	return query.Encode()
+}
+
+// sanitizeBody returns the JSON body with sensitive fields redacted, or a
+// summary when it cannot be parsed in full.
+func sanitizeBody(contentType string, body *captureBuffer) string {
+	if body.total == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	isJSON := mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+	if !isJSON {
+		return fmt.Sprintf("[%s body, %d bytes]", orUnknown(mediaType), body.total)
+	}
+	if body.truncated() {
+		return fmt.Sprintf("[json body, %d bytes, over the debug log limit]", body.total)
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body.data, &payload); err != nil {
+		return fmt.Sprintf("[invalid json body, %d bytes]", body.total)
+	}
+	sanitized, err := json.Marshal(redactJSON(payload))
+	if err != nil {
+		return redacted
+	}
+	return string(sanitized)
+}
+
+func redactJSON(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			if isRedactedField(key) {
+				typed[key] = redacted
+				continue
+			}
+			typed[key] = redactJSON(item)
+		}
+		return typed
+	case []interface{}:
+		for i, item := range typed {
+			typed[i] = redactJSON(item)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+func isRedactedField(name string) bool {
+	name = strings.ToLower(name)
+	if redactedFields[name] {
+		return true
+	}
+	return strings.Contains(name, "password") || strings.Contains(name, "token") || strings.Contains(name, "secret")
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,316 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	jwksPath = "/auth/v1/.well-known/jwks.json"
+
+	// jwksRefreshCooldown stops tokens with unknown key IDs from hammering
+	// the JWKS endpoint.
+	jwksRefreshCooldown = 30 * time.Second
+	jwtClockSkew        = 30 * time.Second
+	jwtAudience         = "authenticated"
+)
+
+var (
+	// errJWKSUnavailable and errUnsupportedToken make the caller fall back to
+	// the remote user endpoint; any other verification error rejects the token.
+	errJWKSUnavailable  = errors.New("jwks unavailable")
+	errUnsupportedToken = errors.New("unsupported token algorithm")
+	errInvalidJWT       = errors.New("invalid jwt")
+)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtAudienceClaim []string
+
+func (a *jwtAudienceClaim) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = jwtAudienceClaim{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+type jwtClaims struct {
+	Sub          string                 `json:"sub"`
+	Email        string                 `json:"email"`
+	Issuer       string                 `json:"iss"`
+	Audience     jwtAudienceClaim       `json:"aud"`
+	ExpiresAt    int64                  `json:"exp"`
+	NotBefore    int64                  `json:"nbf"`
+	UserMetadata map[string]interface{} `json:"user_metadata"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwksVerifier validates Supabase access tokens locally against the
+// project's signing keys. Keys are cached for cacheTTL and refetched early
+// when a token names a key ID the cache does not know, which covers rotation.
+type jwksVerifier struct {
+	url      string
+	apiKey   string
+	issuer   string
+	client   *http.Client
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func newJWKSVerifier(baseURL, apiKey string, client *http.Client, cacheTTL time.Duration) *jwksVerifier {
+	return &jwksVerifier{
+		url:      baseURL + jwksPath,
+		apiKey:   apiKey,
+		issuer:   baseURL + "/auth/v1",
+		client:   client,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+	}
+}
+
+func (v *jwksVerifier) Verify(ctx context.Context, token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errInvalidJWT
+	}
+
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, errInvalidJWT
+	}
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return nil, errUnsupportedToken
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errInvalidJWT
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verifySignature(header.Alg, key, digest[:], signature) {
+		return nil, errInvalidJWT
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, errInvalidJWT
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (v *jwksVerifier) validateClaims(claims jwtClaims) error {
+	now := v.now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(jwtClockSkew)) {
+		return fmt.Errorf("%w: expired", errInvalidJWT)
+	}
+	if claims.NotBefore != 0 && now.Add(jwtClockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return fmt.Errorf("%w: not yet valid", errInvalidJWT)
+	}
+	if claims.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", errInvalidJWT)
+	}
+	if !containsString(claims.Audience, jwtAudience) {
+		return fmt.Errorf("%w: unexpected audience", errInvalidJWT)
+	}
+	if strings.TrimSpace(claims.Sub) == "" {
+		return fmt.Errorf("%w: missing subject", errInvalidJWT)
+	}
+	return nil
+}
+
+func (v *jwksVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	fresh := v.keys != nil && now.Sub(v.fetchedAt) < v.cacheTTL
+	if key, ok := v.keys[kid]; ok && fresh {
+		return key, nil
+	}
+
+	if now.Sub(v.lastAttempt) >= jwksRefreshCooldown {
+		v.lastAttempt = now
+		keys, err := v.fetch(ctx)
+		if err == nil {
+			v.keys = keys
+			v.fetchedAt = now
+		} else if v.keys == nil {
+			return nil, fmt.Errorf("%w: %v", errJWKSUnavailable, err)
+		}
+		// On a failed refresh the previous keys stay in use.
+	}
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if len(v.keys) == 0 {
+		return nil, errJWKSUnavailable
+	}
+	return nil, fmt.Errorf("%w: unknown key id", errInvalidJWT)
+}
+
+func (v *jwksVerifier) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if v.apiKey != "" {
+		req.Header.Set("apikey", v.apiKey)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks endpoint returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(payload.Keys))
+	for _, key := range payload.Keys {
+		publicKey, err := key.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[key.Kid] = publicKey
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("rsa exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !key.Curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point not on curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func verifySignature(alg string, key crypto.PublicKey, digest, signature []byte) bool {
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest, signature) == nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		return ecdsa.Verify(ecKey, digest, r, s)
+	default:
+		return false
+	}
+}
+
+func decodeJWTPart(part string, dst interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
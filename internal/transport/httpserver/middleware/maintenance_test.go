@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	"family-app-go/pkg/logger"
+)
+
+type fakeFlags struct {
+	enabled map[string]bool
+	err     error
+}
+
+func (f fakeFlags) Enabled(_ context.Context, key, familyID string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return familyID == "family-1" && f.enabled[key], nil
+}
+
+func TestMaintenanceGuard(t *testing.T) {
+	todosOn := fakeFlags{enabled: map[string]bool{featureflagsdomain.MaintenanceTodos: true}}
+	cases := []struct {
+		name   string
+		flags  fakeFlags
+		method string
+		guard  []string
+		status int
+	}{
+		{name: "write in maintenance", flags: todosOn, method: http.MethodPost, guard: []string{featureflagsdomain.MaintenanceTodos}, status: http.StatusServiceUnavailable},
+		{name: "read in maintenance", flags: todosOn, method: http.MethodGet, guard: []string{featureflagsdomain.MaintenanceTodos}, status: http.StatusOK},
+		{name: "other domain", flags: todosOn, method: http.MethodPost, guard: []string{featureflagsdomain.MaintenanceExpenses}, status: http.StatusOK},
+		{name: "any of several domains", flags: todosOn, method: http.MethodPost, guard: []string{featureflagsdomain.MaintenanceExpenses, featureflagsdomain.MaintenanceTodos}, status: http.StatusServiceUnavailable},
+		{name: "lookup failure", flags: fakeFlags{err: errors.New("db down")}, method: http.MethodPost, guard: []string{featureflagsdomain.MaintenanceTodos}, status: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			maintenance := NewMaintenance(tc.flags, 90*time.Second, logger.New(io.Discard, slog.LevelError, "text"))
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			req := httptest.NewRequest(tc.method, "/api/todo-items/item-1/create-expense", nil)
+			req = req.WithContext(WithFamily(req.Context(), &familydomain.Family{ID: "family-1"}))
+			rec := httptest.NewRecorder()
+			maintenance.Guard(tc.guard...)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.status == http.StatusServiceUnavailable && rec.Header().Get("Retry-After") != "90" {
+				t.Fatalf("expected Retry-After 90, got %q", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
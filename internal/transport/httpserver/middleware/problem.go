@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"family-app-go/internal/transport/httpserver/validation"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+
+	// problemTypeBase prefixes error codes to form RFC 7807 type URIs. The
+	// reference is relative so it resolves against whatever host serves the API.
+	problemTypeBase = "/problems/"
+)
+
+// Problem is an RFC 7807 problem details body. Code and Fields extend the
+// standard members so clients can keep switching on the same error codes;
+// Current carries the stored entity on version conflicts and Quota the limit
+// a write would exceed.
+type Problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	Code      string                  `json:"code"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Fields    []validation.FieldError `json:"fields,omitempty"`
+	Current   interface{}             `json:"current,omitempty"`
+	Quota     interface{}             `json:"quota,omitempty"`
+}
+
+// problemWriter marks a response whose client asked for problem+json.
+type problemWriter struct {
+	http.ResponseWriter
+	requestID string
+}
+
+func (w *problemWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *problemWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// ProblemDetails opts clients that send Accept: application/problem+json into
+// RFC 7807 error bodies. Everyone else keeps the {"error":{...}} envelope.
+// Must run after RequestID so problems can point at the request.
+func ProblemDetails(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		if acceptsProblem(r.Header.Values("Accept")) {
+			w = &problemWriter{ResponseWriter: w, requestID: RequestIDFromContext(r.Context())}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteProblem writes the error as problem+json when the client negotiated it
+// and reports whether it did; otherwise the caller writes its usual envelope.
+func WriteProblem(w http.ResponseWriter, status int, code, message string, fields []validation.FieldError) bool {
+	return writeProblem(w, status, code, message, fields, nil, nil)
+}
+
+// WriteConflictProblem is WriteProblem for 409s that return the stored entity.
+func WriteConflictProblem(w http.ResponseWriter, code, message string, current interface{}) bool {
+	return writeProblem(w, http.StatusConflict, code, message, nil, current, nil)
+}
+
+// WriteQuotaProblem is WriteProblem for writes refused by a quota.
+func WriteQuotaProblem(w http.ResponseWriter, status int, code, message string, quota interface{}) bool {
+	return writeProblem(w, status, code, message, nil, nil, quota)
+}
+
+func writeProblem(w http.ResponseWriter, status int, code, message string, fields []validation.FieldError, current, quota interface{}) bool {
+	pw, ok := findProblemWriter(w)
+	if !ok {
+		return false
+	}
+
+	problem := Problem{
+		Type:      problemTypeBase + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    message,
+		Code:      code,
+		RequestID: pw.requestID,
+		Fields:    fields,
+		Current:   current,
+		Quota:     quota,
+	}
+	if pw.requestID != "" {
+		problem.Instance = "urn:request-id:" + pw.requestID
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem)
+	return true
+}
+
+func findProblemWriter(w http.ResponseWriter) (*problemWriter, bool) {
+	for {
+		switch current := w.(type) {
+		case *problemWriter:
+			return current, true
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = current.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
+func acceptsProblem(values []string) bool {
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil || mediaType != ProblemContentType {
+				continue
+			}
+			if q := strings.TrimSpace(params["q"]); q == "0" || q == "0.0" || q == "0.00" || q == "0.000" {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
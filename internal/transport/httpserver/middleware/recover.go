@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"family-app-go/pkg/logger"
+)
+
+// panicsRecovered counts recovered panics per transport. It is published
+// with the other expvars at GET /api/admin/debug/vars.
+var panicsRecovered = expvar.NewMap("panics_recovered")
+
+// RecordPanic counts a recovered panic; transport is "http" or "grpc".
+func RecordPanic(transport string) {
+	panicsRecovered.Add(transport, 1)
+}
+
+// recoverWriter remembers whether the handler already started its response,
+// in which case a panic can no longer be answered with an error body.
+type recoverWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *recoverWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recoverWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *recoverWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *recoverWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Recoverer keeps the server answering when a handler panics: it logs the
+// panic with its stack trace and the request context, which also reports it
+// to the error tracker, counts it, and answers 500 internal_error in the
+// usual error envelope with the request ID. It must run after RequestID and
+// ProblemDetails.
+func Recoverer(log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &recoverWriter{ResponseWriter: w}
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				RecordPanic("http")
+				logger.FromContext(r.Context(), log).InternalError(
+					"http: handler panicked",
+					panicError(recovered),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"stack", string(debug.Stack()),
+				)
+				if rw.wroteHeader || r.Header.Get("Connection") == "Upgrade" {
+					return
+				}
+				writeInternalError(rw, RequestIDFromContext(r.Context()))
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+func writeInternalError(w http.ResponseWriter, requestID string) {
+	if WriteProblem(w, http.StatusInternalServerError, "internal_error", "internal error", nil) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{
+			"code":       "internal_error",
+			"message":    "internal error",
+			"request_id": requestID,
+		},
+	})
+}
+
+func panicError(recovered any) error {
+	if err, ok := recovered.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", recovered)
+}
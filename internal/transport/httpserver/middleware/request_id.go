@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID reuses a sane incoming X-Request-ID or assigns a new one, echoes
+// it in the response and stores it where chi's logger looks for it.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := ResolveRequestID(r.Header.Get(RequestIDHeader))
+
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// ResolveRequestID returns the incoming ID when it is usable and a fresh one
+// otherwise.
+func ResolveRequestID(incoming string) string {
+	requestID := strings.TrimSpace(incoming)
+	if !validRequestID(requestID) {
+		requestID = newRequestID()
+	}
+	return requestID
+}
+
+// WithRequestID stores the request ID for transports that do not go through
+// RequestID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, chimw.RequestIDKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	return chimw.GetReqID(ctx)
+}
+
+func validRequestID(value string) bool {
+	if value == "" || len(value) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range value {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b[:])
+}
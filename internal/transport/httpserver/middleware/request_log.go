@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	stdlog "log"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"family-app-go/internal/db"
+	"family-app-go/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestLogger writes chi's request log line with the request's database
+// time and statement count appended, as in `... 200 512B in 40ms db=31ms/4q`.
+// A request that spends more than budget in the database is marked
+// db_over_budget and logged as a warning with its route, so endpoints
+// missing an index stand out. A zero budget only appends the time. It must
+// run after DatabaseTimeouts, which starts the count.
+func RequestLogger(budget time.Duration, log logger.Logger) func(http.Handler) http.Handler {
+	return chimw.RequestLogger(&requestLogFormatter{
+		out:     stdlog.New(os.Stdout, "", stdlog.LstdFlags),
+		noColor: runtime.GOOS == "windows",
+		budget:  budget,
+		log:     log,
+	})
+}
+
+type requestLogFormatter struct {
+	out     chimw.LoggerInterface
+	noColor bool
+	budget  time.Duration
+	log     logger.Logger
+}
+
+func (f *requestLogFormatter) NewLogEntry(r *http.Request) chimw.LogEntry {
+	printer := &databaseTimePrinter{formatter: f, ctx: r.Context(), method: r.Method}
+	return (&chimw.DefaultLogFormatter{Logger: printer, NoColor: f.noColor}).NewLogEntry(r)
+}
+
+// databaseTimePrinter receives the finished line from chi's log entry, once
+// the request is done and its database time is final.
+type databaseTimePrinter struct {
+	formatter *requestLogFormatter
+	ctx       context.Context
+	method    string
+}
+
+func (p *databaseTimePrinter) Print(v ...interface{}) {
+	line := fmt.Sprint(v...)
+	stats, ok := db.QueryStatsFromContext(p.ctx)
+	if !ok {
+		p.formatter.out.Print(line)
+		return
+	}
+
+	line += fmt.Sprintf(" db=%s/%dq", stats.Elapsed.Round(time.Microsecond), stats.Queries)
+	budget := p.formatter.budget
+	if budget > 0 && stats.Elapsed > budget {
+		line += " db_over_budget"
+		route := ""
+		if rctx := chi.RouteContext(p.ctx); rctx != nil {
+			route = rctx.RoutePattern()
+		}
+		logger.FromContext(p.ctx, p.formatter.log).Warn(
+			"http: request over database budget",
+			"method", p.method,
+			"route", route,
+			"db_ms", stats.Elapsed.Milliseconds(),
+			"db_queries", stats.Queries,
+			"budget_ms", budget.Milliseconds(),
+		)
+	}
+	p.formatter.out.Print(line)
+}
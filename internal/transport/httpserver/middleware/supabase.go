@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"family-app-go/internal/config"
+	"family-app-go/pkg/logger"
+)
+
+// SupabaseProvider verifies Supabase access tokens locally against the
+// project JWKS and falls back to the Supabase user endpoint.
+type SupabaseProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+	jwks    *jwksVerifier
+	log     logger.Logger
+}
+
+type userResponse struct {
+	ID           string                 `json:"id"`
+	Email        string                 `json:"email"`
+	Sub          string                 `json:"sub"`
+	UserMetadata map[string]interface{} `json:"user_metadata"`
+	User         struct {
+		ID  string `json:"id"`
+		Sub string `json:"sub"`
+	} `json:"user"`
+}
+
+func NewSupabaseProvider(cfg config.SupabaseConfig, log logger.Logger) *SupabaseProvider {
+	baseURL := strings.TrimRight(cfg.URL, "/")
+	timeout := cfg.AuthTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	var jwks *jwksVerifier
+	if cfg.JWKSEnabled && baseURL != "" {
+		cacheTTL := cfg.JWKSCacheTTL
+		if cacheTTL <= 0 {
+			cacheTTL = 10 * time.Minute
+		}
+		jwks = newJWKSVerifier(baseURL, cfg.PublishableKey, client, cacheTTL)
+	}
+
+	return &SupabaseProvider{
+		baseURL: baseURL,
+		apiKey:  cfg.PublishableKey,
+		client:  client,
+		jwks:    jwks,
+		log:     log,
+	}
+}
+
+func (p *SupabaseProvider) Name() string {
+	return "supabase"
+}
+
+func (p *SupabaseProvider) Configured() bool {
+	return p.baseURL != "" && p.apiKey != ""
+}
+
+func (p *SupabaseProvider) Authenticate(r *http.Request, token string) (User, bool) {
+	user, handled, ok := p.verifyLocally(r, token)
+	if handled {
+		return user, ok
+	}
+	return p.fetchRemoteUser(r, token)
+}
+
+// verifyLocally checks the token against the cached JWKS. handled is false
+// when the remote user endpoint must decide instead: local verification is
+// off, the signing keys are unavailable, the token uses a legacy algorithm,
+// or the token carries no profile data.
+func (p *SupabaseProvider) verifyLocally(r *http.Request, token string) (user User, handled bool, ok bool) {
+	if p.jwks == nil {
+		return User{}, false, false
+	}
+
+	claims, err := p.jwks.Verify(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, errJWKSUnavailable) || errors.Is(err, errUnsupportedToken) {
+			logger.FromContext(r.Context(), p.log).Warn("auth: local jwt verification unavailable, falling back", "method", r.Method, "path", r.URL.Path, "err", err)
+			return User{}, false, false
+		}
+		logger.FromContext(r.Context(), p.log).Warn("auth: jwt rejected", "method", r.Method, "path", r.URL.Path, "err", err)
+		return User{}, true, false
+	}
+	if claims.Email == "" {
+		return User{}, false, false
+	}
+
+	return User{
+		ID:        claims.Sub,
+		Email:     claims.Email,
+		Name:      firstNonEmpty(stringFromMap(claims.UserMetadata, "name"), stringFromMap(claims.UserMetadata, "full_name")),
+		AvatarURL: stringFromMap(claims.UserMetadata, "avatar_url"),
+	}, true, true
+}
+
+// fetchRemoteUser resolves the token through Supabase's user endpoint.
+func (p *SupabaseProvider) fetchRemoteUser(r *http.Request, token string) (User, bool) {
+	requestMethod := r.Method
+	requestPath := r.URL.Path
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, p.baseURL+"/auth/v1/user", nil)
+	if err != nil {
+		logger.FromContext(r.Context(), p.log).Error("auth: build supabase auth request failed", "method", requestMethod, "path", requestPath, "err", err)
+		return User{}, false
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("apikey", p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		logger.FromContext(r.Context(), p.log).Error("auth: request to supabase failed", "method", requestMethod, "path", requestPath, "err", err)
+		return User{}, false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			logger.FromContext(r.Context(), p.log).Error("auth: supabase auth endpoint error", "method", requestMethod, "path", requestPath, "status_code", resp.StatusCode)
+		} else {
+			logger.FromContext(r.Context(), p.log).Warn("auth: supabase rejected token", "method", requestMethod, "path", requestPath, "status_code", resp.StatusCode)
+		}
+		return User{}, false
+	}
+
+	var payload userResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		logger.FromContext(r.Context(), p.log).Error("auth: decode supabase auth response failed", "method", requestMethod, "path", requestPath, "err", err)
+		return User{}, false
+	}
+
+	userID := firstNonEmpty(payload.ID, payload.Sub, payload.User.ID, payload.User.Sub)
+	if userID == "" {
+		logger.FromContext(r.Context(), p.log).Warn("auth: supabase response missing user id", "method", requestMethod, "path", requestPath)
+		return User{}, false
+	}
+
+	return User{
+		ID:        userID,
+		Email:     payload.Email,
+		Name:      firstNonEmpty(stringFromMap(payload.UserMetadata, "name"), stringFromMap(payload.UserMetadata, "full_name")),
+		AvatarURL: stringFromMap(payload.UserMetadata, "avatar_url"),
+	}, true
+}
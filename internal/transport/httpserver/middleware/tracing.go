@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"family-app-go/pkg/logger"
+	"family-app-go/pkg/tracing"
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracing opens a server span per request, continuing any incoming W3C
+// trace context, and stores a logger tagged with the request and trace IDs
+// in the request context. It must run after RequestID.
+func NewTracing(log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			requestID := RequestIDFromContext(ctx)
+
+			ctx, span := tracing.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", r.Method),
+					attribute.String("url.path", r.URL.Path),
+					attribute.String("request.id", requestID),
+				),
+			)
+			defer span.End()
+
+			requestLog := log
+			if traceID := tracing.TraceID(ctx); traceID != "" {
+				requestLog = requestLog.With("trace_id", traceID)
+			}
+			ctx = logger.WithContext(logger.WithRequestID(ctx, requestID), requestLog)
+
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if routeContext := chi.RouteContext(ctx); routeContext != nil {
+				if pattern := routeContext.RoutePattern(); pattern != "" {
+					span.SetName(r.Method + " " + pattern)
+					span.SetAttributes(attribute.String("http.route", pattern))
+				}
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, fmt.Sprintf("status %d", status))
+			}
+		})
+	}
+}
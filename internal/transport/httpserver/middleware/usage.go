@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UsageRecorder counts requests per family and endpoint.
+type UsageRecorder interface {
+	Record(familyID, method, route string)
+}
+
+// Usage counts each request of a family against its route pattern once the
+// handler is done, so IDs in the path do not split the counters. A nil
+// recorder turns counting off.
+func Usage(recorder UsageRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if recorder == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			family, ok := FamilyFromContext(r.Context())
+			if !ok {
+				return
+			}
+			routeContext := chi.RouteContext(r.Context())
+			if routeContext == nil {
+				return
+			}
+			recorder.Record(family.ID, r.Method, routeContext.RoutePattern())
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package httpserver
 
 import (
+	"expvar"
 	"net/http"
 	"time"
 
 	"family-app-go/internal/config"
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+	viewsdomain "family-app-go/internal/domain/views"
 	"family-app-go/internal/transport/httpserver/handler"
 	authmw "family-app-go/internal/transport/httpserver/middleware"
 	"family-app-go/pkg/logger"
@@ -12,88 +15,352 @@ import (
 	chimw "github.com/go-chi/chi/v5/middleware"
 )
 
-func NewRouter(cfg config.Config, handlers *handler.Handlers, profiles authmw.ProfileSaver, log logger.Logger) http.Handler {
+// compressibleTypes are the response types gzip/deflate compression applies
+// to; images and other binary responses are sent as is.
+var compressibleTypes = []string{
+	"application/json",
+	"application/x-ndjson",
+	authmw.ProblemContentType,
+	"text/plain",
+	"text/calendar",
+}
+
+func NewRouter(cfg config.Config, handlers *handler.Handlers, provider authmw.AuthProvider, apiKeys authmw.APIKeyVerifier, sessions authmw.SessionTracker, profiles authmw.ProfileSaver, roles authmw.MemberRoleProvider, flags authmw.FlagEvaluator, usage authmw.UsageRecorder, authCache authmw.AuthCache, audit authmw.SecurityAuditor, log logger.Logger) http.Handler {
 	r := chi.NewRouter()
-	r.Use(chimw.RequestID)
+	r.Use(authmw.RequestID)
+	r.Use(authmw.NewTracing(log))
+	r.Use(authmw.ProblemDetails)
+	r.Use(authmw.DatabaseTimeouts)
 	r.Use(chimw.RealIP)
-	r.Use(chimw.Logger)
-	r.Use(chimw.Recoverer)
+	r.Use(authmw.RequestLogger(cfg.DB.RequestBudget, log))
+	r.Use(authmw.Recoverer(log))
 	r.Use(chimw.Timeout(30 * time.Second))
 	r.Use(authmw.NewCORS([]string{"http://localhost:5173"}))
+	if cfg.HTTP.CompressionLevel > 0 {
+		r.Use(chimw.Compress(cfg.HTTP.CompressionLevel, compressibleTypes...))
+	}
+	r.Use(authmw.BodyLimit(cfg.HTTP.MaxBodyBytes))
+	r.Use(authmw.DebugLog(cfg.HTTP, log))
+	upload := authmw.BodyLimit(cfg.HTTP.UploadMaxBodyBytes)
 
 	r.Route("/api", func(r chi.Router) {
 		r.Get("/health", handlers.Common.Health)
+		r.Get("/health/live", handlers.Common.HealthLive)
+		r.Get("/health/ready", handlers.Common.HealthReady)
+		r.Get("/calendar/feed.ics", handlers.Calendar.Feed)
+		r.Get("/shared/lists/{token}", handlers.Todos.GetSharedList)
+		r.Get("/exports/download", handlers.Exports.Download)
+		r.Get("/avatars/{user_id}/{file}", handlers.Common.GetAvatar)
+
+		// Self-hosted auth; these answer 404 unless AUTH_PROVIDER=local.
+		r.Post("/auth/register", handlers.Auth.Register)
+		r.Post("/auth/login", handlers.Auth.Login)
+		r.Post("/auth/refresh", handlers.Auth.Refresh)
+		r.Post("/auth/revoke", handlers.Auth.Revoke)
 
-		auth := authmw.NewSupabaseAuth(cfg.Supabase, profiles, log)
+		// Operator API; answers 404 unless ADMIN_TOKEN is set.
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(authmw.AdminToken(cfg.Admin.Token))
+			r.Get("/families", handlers.Admin.ListFamilies)
+			r.Get("/sync/batches", handlers.Admin.ListSyncBatches)
+			r.Get("/sync/batches/{id}", handlers.Admin.GetSyncBatch)
+			r.Post("/sync/batches/{id}/release", handlers.Admin.ReleaseSyncBatch)
+			r.Post("/purge", handlers.Admin.PurgeSoftDeleted)
+			r.Get("/jobs", handlers.Admin.ListJobs)
+			r.Get("/jobs/runs", handlers.Admin.ListJobRuns)
+			r.Post("/jobs/{name}/run", handlers.Admin.RunJob)
+			r.Get("/backups", handlers.Admin.ListBackups)
+			r.Post("/backups/restore", handlers.Admin.RestoreBackup)
+			r.Post("/encryption/rotate", handlers.Admin.RotateEncryption)
+			r.Get("/indexes", handlers.Admin.IndexReport)
+			r.Get("/security-events", handlers.Admin.ListSecurityEvents)
+			r.Get("/usage", handlers.Admin.ListUsage)
+			r.Get("/usage/modules", handlers.Admin.ListModuleUsage)
+			r.Handle("/debug/vars", expvar.Handler())
+			r.Get("/feature-flags", handlers.Flags.ListFlags)
+			r.Put("/feature-flags/{key}", handlers.Flags.SetGlobalFlag)
+			r.Put("/feature-flags/{key}/families/{family_id}", handlers.Flags.SetFamilyFlag)
+			r.Delete("/feature-flags/{key}/families/{family_id}", handlers.Flags.ClearFamilyFlag)
+		})
+
+		if provider == nil {
+			provider = authmw.NewSupabaseProvider(cfg.Supabase, log)
+		}
+		auth := authmw.NewAuth(cfg.Supabase, provider, apiKeys, sessions, profiles, authCache, audit, log)
+		access := authmw.NewFamilyAccess(roles, log)
+		// Routes guard their writes with the maintenance flags of every
+		// domain they touch; reads stay available.
+		maintenance := authmw.NewMaintenance(flags, cfg.Maintenance.RetryAfter, log)
 		r.Group(func(r chi.Router) {
 			r.Use(auth.Middleware)
+			r.Use(access.Middleware)
+			r.Use(authmw.Usage(usage))
 
 			r.Get("/auth/me", handlers.Common.AuthMe)
-			if cfg.OfflineSyncEnabled {
-				r.Post("/sync", handlers.Common.SyncBatch)
-			}
-
-			r.Get("/analytics/summary", handlers.Expenses.AnalyticsSummary)
-			r.Get("/analytics/timeseries", handlers.Expenses.AnalyticsTimeseries)
-			r.Get("/analytics/by-category", handlers.Expenses.AnalyticsByCategory)
-			r.Get("/top_categories", handlers.Expenses.TopCategories)
-			r.Get("/reports/monthly", handlers.Expenses.ReportsMonthly)
-			r.Get("/reports/compare", handlers.Expenses.ReportsCompare)
+			r.Delete("/me", handlers.Erasure.DeleteAccount)
+			r.Get("/me/deletion", handlers.Erasure.GetAccountDeletion)
+			r.Delete("/me/deletion", handlers.Erasure.CancelAccountDeletion)
+			r.Post("/auth/logout", auth.Logout)
+			r.Get("/me/preferences", handlers.Common.GetPreferences)
+			r.Patch("/me/preferences", handlers.Common.UpdatePreferences)
+			r.With(upload).Post("/me/avatar", handlers.Common.UploadAvatar)
+			r.Get("/me/api-keys", handlers.APIKeys.ListAPIKeys)
+			r.Post("/me/api-keys", handlers.APIKeys.CreateAPIKey)
+			r.Delete("/me/api-keys/{id}", handlers.APIKeys.RevokeAPIKey)
+			r.Get("/me/sessions", handlers.Sessions.ListSessions)
+			r.Post("/me/sessions/revoke-others", handlers.Sessions.RevokeOtherSessions)
+			r.Post("/me/sessions/{id}/revoke", handlers.Sessions.RevokeSession)
+			r.Get("/me/mentions", handlers.Mentions.ListMentions)
+			r.Post("/me/mentions/read", handlers.Mentions.MarkMentionsRead)
+			r.Get("/me/notifications", handlers.Notifications.ListNotifications)
+			r.Post("/me/notifications/read", handlers.Notifications.MarkNotificationsRead)
 
 			r.Get("/families/me", handlers.Common.GetFamilyMe)
 			r.Post("/families", handlers.Common.CreateFamily)
 			r.Post("/families/join", handlers.Common.JoinFamily)
 			r.Post("/families/leave", handlers.Common.LeaveFamily)
-			r.Patch("/families/me", handlers.Common.UpdateFamily)
 			r.Get("/families/me/members", handlers.Common.ListFamilyMembers)
-			r.Delete("/families/me/members/{user_id}", handlers.Common.RemoveFamilyMember)
-
-			r.Get("/currencies", handlers.Expenses.ListCurrencies)
-			r.Get("/exchange-rates", handlers.Expenses.GetExchangeRate)
-
-			r.Get("/expenses", handlers.Expenses.ListExpenses)
-			r.Post("/expenses", handlers.Expenses.CreateExpense)
-			r.Put("/expenses/{id}", handlers.Expenses.UpdateExpense)
-			r.Delete("/expenses/{id}", handlers.Expenses.DeleteExpense)
-
-			r.Get("/categories", handlers.Expenses.ListCategories)
-			r.Post("/categories", handlers.Expenses.CreateCategory)
-			r.Patch("/categories/{id}", handlers.Expenses.UpdateCategory)
-			r.Delete("/categories/{id}", handlers.Expenses.DeleteCategory)
-
-			r.Post("/receipt-parses", handlers.Receipts.CreateParse)
-			r.Get("/receipt-parses/active", handlers.Receipts.GetActiveParse)
-			r.Get("/receipt-parses/{id}", handlers.Receipts.GetParse)
-			r.Patch("/receipt-parses/{id}/items", handlers.Receipts.UpdateItems)
-			r.Post("/receipt-parses/{id}/approve", handlers.Receipts.ApproveParse)
-			r.Post("/receipt-parses/{id}/cancel", handlers.Receipts.CancelParse)
-
-			r.Get("/todo-lists", handlers.Todos.ListTodoLists)
-			r.Post("/todo-lists", handlers.Todos.CreateTodoList)
-			r.Patch("/todo-lists/{list_id}", handlers.Todos.UpdateTodoList)
-			r.Delete("/todo-lists/{list_id}", handlers.Todos.DeleteTodoList)
-			r.Get("/todo-lists/{list_id}/items", handlers.Todos.ListTodoItems)
-			r.Post("/todo-lists/{list_id}/items", handlers.Todos.CreateTodoItem)
-			r.Patch("/todo-items/{item_id}", handlers.Todos.UpdateTodoItem)
-			r.Delete("/todo-items/{item_id}", handlers.Todos.DeleteTodoItem)
-
-			r.Get("/gym/entries", handlers.Gym.ListGymEntries)
-			r.Post("/gym/entries", handlers.Gym.CreateGymEntry)
-			r.Put("/gym/entries/{id}", handlers.Gym.UpdateGymEntry)
-			r.Delete("/gym/entries/{id}", handlers.Gym.DeleteGymEntry)
-
-			r.Get("/gym/workouts", handlers.Gym.ListWorkouts)
-			r.Get("/gym/workouts/{id}", handlers.Gym.GetWorkout)
-			r.Post("/gym/workouts", handlers.Gym.CreateWorkout)
-			r.Put("/gym/workouts/{id}", handlers.Gym.UpdateWorkout)
-			r.Delete("/gym/workouts/{id}", handlers.Gym.DeleteWorkout)
-
-			r.Get("/gym/templates", handlers.Gym.ListTemplates)
-			r.Post("/gym/templates", handlers.Gym.CreateTemplate)
-			r.Put("/gym/templates/{id}", handlers.Gym.UpdateTemplate)
-			r.Delete("/gym/templates/{id}", handlers.Gym.DeleteTemplate)
-
-			r.Get("/gym/exercises", handlers.Gym.ListExercises)
+			r.Get("/feature-flags", handlers.Flags.GetFamilyFlags)
+
+			r.Get("/polls", handlers.Polls.ListPolls)
+			r.Post("/polls", handlers.Polls.CreatePoll)
+			r.Get("/polls/{id}", handlers.Polls.GetPoll)
+			r.Delete("/polls/{id}", handlers.Polls.DeletePoll)
+			r.Get("/polls/{id}/results", handlers.Polls.GetPollResults)
+			r.Put("/polls/{id}/vote", handlers.Polls.VotePoll)
+			r.Delete("/polls/{id}/vote", handlers.Polls.UnvotePoll)
+			r.Post("/polls/{id}/close", handlers.Polls.ClosePoll)
+
+			r.Get("/milestones", handlers.Milestones.ListMilestones)
+			r.Get("/milestones/{id}", handlers.Milestones.GetMilestone)
+
+			// Child members only see their own data and todo items assigned
+			// to them. Everything touching family finances or family settings
+			// is grouped here.
+			r.Group(func(r chi.Router) {
+				r.Use(authmw.DenyChild)
+
+				if cfg.OfflineSyncEnabled {
+					r.With(authmw.BodyLimit(cfg.HTTP.SyncMaxBodyBytes), maintenance.Guard(featureflagsdomain.MaintenanceSync)).Post("/sync", handlers.Common.SyncBatch)
+					r.Get("/sync/mappings", handlers.Common.SyncMappings)
+					r.Get("/sync/events", handlers.Common.SyncEvents)
+					r.Get("/sync/snapshot", handlers.Common.SyncSnapshot)
+				}
+
+				r.Get("/analytics/summary", handlers.Expenses.AnalyticsSummary)
+				r.Get("/analytics/timeseries", handlers.Expenses.AnalyticsTimeseries)
+				r.Get("/analytics/by-category", handlers.Expenses.AnalyticsByCategory)
+				r.Get("/analytics/category-trends", handlers.Expenses.AnalyticsCategoryTrends)
+				r.Get("/top_categories", handlers.Expenses.TopCategories)
+				r.Get("/reports/monthly", handlers.Expenses.ReportsMonthly)
+				r.Get("/reports/compare", handlers.Expenses.ReportsCompare)
+				r.Get("/reports/year-in-review/{year}", handlers.YearReview.YearInReview)
+
+				r.Patch("/families/me", handlers.Common.UpdateFamily)
+				r.Delete("/families/me", handlers.Erasure.DeleteFamily)
+				r.Get("/families/me/deletion", handlers.Erasure.GetFamilyDeletion)
+				r.Delete("/families/me/deletion", handlers.Erasure.CancelFamilyDeletion)
+				r.Get("/families/me/activity", handlers.Common.ListFamilyActivity)
+				r.Patch("/families/me/members/{user_id}", handlers.Common.UpdateFamilyMember)
+				r.Put("/families/me/members/{user_id}/spending-limit", handlers.Common.SetFamilyMemberSpendingLimit)
+				r.Delete("/families/me/members/{user_id}", handlers.Common.RemoveFamilyMember)
+				r.Post("/families/me/invites", handlers.Common.CreateFamilyInvite)
+				r.Get("/families/me/invites", handlers.Common.ListFamilyInvites)
+				r.Delete("/families/me/invites/{id}", handlers.Common.RevokeFamilyInvite)
+				r.Get("/families/me/retention", handlers.Retention.GetRetentionPolicy)
+				r.Put("/families/me/retention", handlers.Retention.UpdateRetentionPolicy)
+				r.Get("/families/me/retention/preview", handlers.Retention.PreviewRetention)
+				r.Post("/families/me/export", handlers.Exports.RequestExport)
+				r.Get("/families/me/exports/{id}", handlers.Exports.GetExport)
+
+				r.Post("/milestones", handlers.Milestones.CreateMilestone)
+				r.Put("/milestones/{id}", handlers.Milestones.UpdateMilestone)
+				r.Delete("/milestones/{id}", handlers.Milestones.DeleteMilestone)
+				r.Post("/milestones/{id}/savings", handlers.Milestones.AddMilestoneSavings)
+
+				r.Get("/currencies", handlers.Expenses.ListCurrencies)
+				r.Get("/exchange-rates", handlers.Expenses.GetExchangeRate)
+				r.Get("/fx/rates", handlers.Expenses.GetFXRates)
+
+				r.Group(func(r chi.Router) {
+					r.Use(maintenance.Guard(featureflagsdomain.MaintenanceExpenses))
+
+					r.Get("/expenses", handlers.Expenses.ListExpenses)
+					r.Post("/expenses", handlers.Expenses.CreateExpense)
+					r.Get("/expenses/geo", handlers.Expenses.ExpensesGeo)
+					r.With(maintenance.Guard(featureflagsdomain.MaintenanceTodos)).Post("/todo-items/{item_id}/create-expense", handlers.Expenses.CreateExpenseFromTodoItem)
+					r.Get("/expenses/approvals", handlers.Expenses.ListExpenseApprovals)
+					r.With(authmw.RequireOwner).Post("/expenses/approvals/{id}/approve", handlers.Expenses.ApproveExpense)
+					r.With(authmw.RequireOwner).Post("/expenses/approvals/{id}/reject", handlers.Expenses.RejectExpense)
+					r.Put("/expenses/{id}", handlers.Expenses.UpdateExpense)
+					r.Delete("/expenses/{id}", handlers.Expenses.DeleteExpense)
+					r.Get("/expenses/{id}/comments", handlers.Expenses.ListExpenseComments)
+					r.Post("/expenses/{id}/comments", handlers.Expenses.CreateExpenseComment)
+					r.Put("/expenses/{id}/comments/{comment_id}", handlers.Expenses.UpdateExpenseComment)
+					r.Delete("/expenses/{id}/comments/{comment_id}", handlers.Expenses.DeleteExpenseComment)
+					r.Post("/expenses/archive-older-than", handlers.Expenses.ArchiveExpensesOlderThan)
+					r.With(upload).Post("/expenses/import/statement", handlers.Expenses.ImportStatement)
+
+					r.Get("/categories", handlers.Expenses.ListCategories)
+					r.Post("/categories", handlers.Expenses.CreateCategory)
+					r.Patch("/categories/{id}", handlers.Expenses.UpdateCategory)
+					r.Delete("/categories/{id}", handlers.Expenses.DeleteCategory)
+					r.Put("/categories/{id}/favorite", handlers.Expenses.FavoriteCategory)
+					r.Delete("/categories/{id}/favorite", handlers.Expenses.UnfavoriteCategory)
+
+					r.Get("/category-rules", handlers.Expenses.ListCategoryRules)
+					r.Post("/category-rules", handlers.Expenses.CreateCategoryRule)
+					r.Patch("/category-rules/{id}", handlers.Expenses.UpdateCategoryRule)
+					r.Delete("/category-rules/{id}", handlers.Expenses.DeleteCategoryRule)
+
+					r.Get("/budgets", handlers.Budgets.ListBudgets)
+					r.Post("/budgets", handlers.Budgets.CreateBudget)
+					r.Get("/budgets/{id}", handlers.Budgets.GetBudget)
+					r.Put("/budgets/{id}", handlers.Budgets.UpdateBudget)
+					r.Delete("/budgets/{id}", handlers.Budgets.DeleteBudget)
+
+					r.With(upload).Post("/receipt-parses", handlers.Receipts.CreateParse)
+					r.Get("/receipt-parses/active", handlers.Receipts.GetActiveParse)
+					r.Get("/receipt-parses/{id}", handlers.Receipts.GetParse)
+					r.Patch("/receipt-parses/{id}/items", handlers.Receipts.UpdateItems)
+					r.Post("/receipt-parses/{id}/approve", handlers.Receipts.ApproveParse)
+					r.Post("/receipt-parses/{id}/cancel", handlers.Receipts.CancelParse)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(maintenance.Guard(featureflagsdomain.MaintenanceTodos))
+
+					r.Post("/todo-lists", handlers.Todos.CreateTodoList)
+					r.Patch("/todo-lists/{list_id}", handlers.Todos.UpdateTodoList)
+					r.Delete("/todo-lists/{list_id}", handlers.Todos.DeleteTodoList)
+					r.Put("/todo-lists/{list_id}/visibility", handlers.Todos.SetTodoListVisibility)
+					r.Post("/todo-lists/{list_id}/shares", handlers.Todos.CreateListShare)
+					r.Get("/todo-lists/{list_id}/shares", handlers.Todos.ListListShares)
+					r.Delete("/todo-lists/{list_id}/shares/{share_id}", handlers.Todos.RevokeListShare)
+					r.Post("/todo-lists/{list_id}/items", handlers.Todos.CreateTodoItem)
+					r.Delete("/todo-items/{item_id}", handlers.Todos.DeleteTodoItem)
+					r.Put("/todo-items/{item_id}/labels", handlers.Todos.SetTodoItemLabels)
+					r.Get("/todo-items/{item_id}/comments", handlers.Todos.ListTodoItemComments)
+					r.Post("/todo-items/{item_id}/comments", handlers.Todos.CreateTodoItemComment)
+					r.Put("/todo-items/{item_id}/comments/{comment_id}", handlers.Todos.UpdateTodoItemComment)
+					r.Delete("/todo-items/{item_id}/comments/{comment_id}", handlers.Todos.DeleteTodoItemComment)
+				})
+
+				// Vet visits carry costs and file them as expenses.
+				r.Get("/pets/{id}/vet-visits", handlers.Pets.ListVetVisits)
+				r.With(maintenance.Guard(featureflagsdomain.MaintenancePets, featureflagsdomain.MaintenanceExpenses)).Post("/pets/{id}/vet-visits", handlers.Pets.CreateVetVisit)
+				r.With(maintenance.Guard(featureflagsdomain.MaintenancePets)).Delete("/pets/{id}/vet-visits/{visit_id}", handlers.Pets.DeleteVetVisit)
+
+				r.Get("/calendar/feed-url", handlers.Calendar.GetFeedURL)
+				r.Post("/calendar/feed-url/rotate", handlers.Calendar.RotateFeedURL)
+
+				r.Get("/gym/family-feed", handlers.Gym.FamilyFeed)
+			})
+
+			// Children get these filtered to items assigned to them.
+			r.Group(func(r chi.Router) {
+				r.Use(maintenance.Guard(featureflagsdomain.MaintenanceTodos))
+
+				r.Get("/todo-lists", handlers.Todos.ListTodoLists)
+				r.Put("/todo-lists/{list_id}/favorite", handlers.Todos.FavoriteTodoList)
+				r.Delete("/todo-lists/{list_id}/favorite", handlers.Todos.UnfavoriteTodoList)
+				r.Get("/todo-lists/{list_id}/items", handlers.Todos.ListTodoItems)
+				r.Patch("/todo-items/{item_id}", handlers.Todos.UpdateTodoItem)
+				r.Get("/todo-items/labels", handlers.Todos.ListTodoLabels)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(maintenance.Guard(featureflagsdomain.MaintenanceWishlist))
+
+				r.Get("/wishlists/{user_id}/items", handlers.Wishlist.ListWishlistItems)
+				r.Post("/wishlist-items", handlers.Wishlist.CreateWishlistItem)
+				r.Put("/wishlist-items/{id}", handlers.Wishlist.UpdateWishlistItem)
+				r.Delete("/wishlist-items/{id}", handlers.Wishlist.DeleteWishlistItem)
+				r.Post("/wishlist-items/{id}/claim", handlers.Wishlist.ClaimWishlistItem)
+				r.Delete("/wishlist-items/{id}/claim", handlers.Wishlist.UnclaimWishlistItem)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(maintenance.Guard(featureflagsdomain.MaintenancePets))
+
+				r.Get("/pets", handlers.Pets.ListPets)
+				r.Post("/pets", handlers.Pets.CreatePet)
+				r.Get("/pets/reminders", handlers.Pets.ListReminders)
+				r.Put("/pets/{id}", handlers.Pets.UpdatePet)
+				r.Delete("/pets/{id}", handlers.Pets.DeletePet)
+				r.Get("/pets/{id}/vaccinations", handlers.Pets.ListVaccinations)
+				r.Post("/pets/{id}/vaccinations", handlers.Pets.CreateVaccination)
+				r.Delete("/pets/{id}/vaccinations/{vaccination_id}", handlers.Pets.DeleteVaccination)
+				r.Get("/pets/{id}/schedules", handlers.Pets.ListSchedules)
+				r.Post("/pets/{id}/schedules", handlers.Pets.CreateSchedule)
+				r.Post("/pets/{id}/schedules/{schedule_id}/done", handlers.Pets.MarkScheduleDone)
+				r.Delete("/pets/{id}/schedules/{schedule_id}", handlers.Pets.DeleteSchedule)
+			})
+
+			// Search leaves out what children may not see.
+			r.Get("/search", handlers.Search.Find)
+			// So does the dashboard, which drops budget and notifications.
+			r.Get("/dashboard", handlers.Dashboard.GetDashboard)
+			// Batch reads report records a child may not see as forbidden.
+			r.Post("/batch/get", handlers.Batch.GetSnapshots)
+
+			r.Get("/views", handlers.Views.ListViews)
+			r.Post("/views", handlers.Views.CreateView)
+			r.Get("/views/{id}", handlers.Views.GetView)
+			r.Patch("/views/{id}", handlers.Views.UpdateView)
+			r.Delete("/views/{id}", handlers.Views.DeleteView)
+			// Views replay their filters against the list endpoints, each
+			// behind the same access checks as its own route.
+			r.Get("/views/{id}/results", handlers.Views.Results(map[viewsdomain.EntityType]http.Handler{
+				viewsdomain.EntityExpenses:  authmw.DenyChild(http.HandlerFunc(handlers.Expenses.ListExpenses)),
+				viewsdomain.EntityTodoLists: http.HandlerFunc(handlers.Todos.ListTodoLists),
+				viewsdomain.EntityTodoItems: http.HandlerFunc(handlers.Todos.ListTodoItems),
+				viewsdomain.EntityWorkouts:  authmw.DenyChildFamilyScope(http.HandlerFunc(handlers.Gym.ListWorkouts)),
+			}))
+
+			// Children only see their own gym data.
+			r.Group(func(r chi.Router) {
+				r.Use(authmw.DenyChildFamilyScope)
+				r.Use(maintenance.Guard(featureflagsdomain.MaintenanceGym))
+
+				r.Get("/gym/entries", handlers.Gym.ListGymEntries)
+				r.Post("/gym/entries", handlers.Gym.CreateGymEntry)
+				r.Put("/gym/entries/{id}", handlers.Gym.UpdateGymEntry)
+				r.Delete("/gym/entries/{id}", handlers.Gym.DeleteGymEntry)
+
+				r.Get("/gym/workouts", handlers.Gym.ListWorkouts)
+				r.Get("/gym/workouts/{id}", handlers.Gym.GetWorkout)
+				r.Post("/gym/workouts", handlers.Gym.CreateWorkout)
+				r.Put("/gym/workouts/{id}", handlers.Gym.UpdateWorkout)
+				r.Delete("/gym/workouts/{id}", handlers.Gym.DeleteWorkout)
+				r.Get("/gym/workouts/labels", handlers.Gym.ListWorkoutLabels)
+				r.Put("/gym/workouts/{id}/labels", handlers.Gym.SetWorkoutLabels)
+
+				r.Get("/gym/templates", handlers.Gym.ListTemplates)
+				r.Post("/gym/templates", handlers.Gym.CreateTemplate)
+				r.Put("/gym/templates/{id}", handlers.Gym.UpdateTemplate)
+				r.Delete("/gym/templates/{id}", handlers.Gym.DeleteTemplate)
+
+				r.Post("/gym/sessions", handlers.Gym.StartSession)
+				r.Get("/gym/sessions/active", handlers.Gym.GetActiveSession)
+				r.Get("/gym/sessions/{id}", handlers.Gym.GetSession)
+				r.Patch("/gym/sessions/{id}", handlers.Gym.UpdateSession)
+				r.Post("/gym/sessions/{id}/finish", handlers.Gym.FinishSession)
+				r.Delete("/gym/sessions/{id}", handlers.Gym.CancelSession)
+
+				r.Get("/gym/exercises", handlers.Gym.ListExercises)
+				r.Put("/gym/exercises/favorite", handlers.Gym.FavoriteExercise)
+				r.Delete("/gym/exercises/favorite", handlers.Gym.UnfavoriteExercise)
+				r.With(upload).Post("/gym/import", handlers.Gym.Import)
+
+				r.Get("/gym/records", handlers.Gym.ListRecords)
+				r.Get("/gym/records/events", handlers.Gym.ListRecordEvents)
+
+				r.Get("/gym/goal", handlers.Gym.GetGoal)
+				r.Put("/gym/goal", handlers.Gym.SetGoal)
+				r.Delete("/gym/goal", handlers.Gym.DeleteGoal)
+				r.Get("/gym/streak", handlers.Gym.GetStreak)
+			})
 		})
 	})
 
@@ -0,0 +1,286 @@
+// Package validation collects field-level errors for decoded request bodies
+// so every handler reports them in the same shape.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"family-app-go/pkg/id"
+	"family-app-go/pkg/money"
+)
+
+const (
+	CodeRequired = "required"
+	CodeInvalid  = "invalid"
+	CodeTooLong  = "too_long"
+	CodeTooShort = "too_short"
+	CodeRange    = "out_of_range"
+	CodeEnum     = "not_allowed"
+	CodeFormat   = "invalid_format"
+	CodeEmpty    = "empty_update"
+	// CodePrecision marks amounts with more decimals than their currency
+	// has, e.g. 10.5 JPY or 12.345 EUR.
+	CodePrecision = "invalid_precision"
+	// CodeCurrency marks currencies that are not ISO 4217 codes or that the
+	// family does not accept.
+	CodeCurrency = "invalid_currency"
+)
+
+// FieldError describes one rejected field. Field is the JSON path of the
+// value and is empty for errors about the body as a whole.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors is returned when at least one rule failed.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FieldErr builds a single-field error for rules the domain enforces.
+func FieldErr(field, code, message string) error {
+	return Errors{{Field: field, Code: code, Message: message}}
+}
+
+// Validatable is implemented by request bodies that carry their own rules.
+type Validatable interface {
+	Validate(v *Validator)
+}
+
+// Validator accumulates errors; rules never stop at the first failure so the
+// client sees every problem at once.
+type Validator struct {
+	prefix string
+	errs   Errors
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Run validates value when it implements Validatable.
+func Run(value interface{}) error {
+	target, ok := value.(Validatable)
+	if !ok {
+		return nil
+	}
+	v := New()
+	target.Validate(v)
+	return v.Err()
+}
+
+// Err returns the collected errors or nil.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func (v *Validator) Valid() bool {
+	return len(v.errs) == 0
+}
+
+// Add records an error for field under the validator's prefix. The message
+// is used as is.
+func (v *Validator) Add(field, code, message string) {
+	v.errs = append(v.errs, FieldError{Field: v.path(field), Code: code, Message: message})
+}
+
+// Check adds the error when ok is false.
+func (v *Validator) Check(ok bool, field, code, message string) {
+	if !ok {
+		v.Add(field, code, message)
+	}
+}
+
+// Required rejects blank strings.
+func (v *Validator) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(field, CodeRequired, "%s is required")
+	}
+}
+
+// NotBlank rejects a present but blank value; nil means "not sent".
+func (v *Validator) NotBlank(field string, value *string) {
+	if value != nil {
+		v.Required(field, *value)
+	}
+}
+
+// MaxLength limits the trimmed value to max characters.
+func (v *Validator) MaxLength(field, value string, max int) {
+	if utf8.RuneCountInString(strings.TrimSpace(value)) > max {
+		v.fail(field, CodeTooLong, "%s must be at most %d characters", max)
+	}
+}
+
+// MinLength requires at least min characters.
+func (v *Validator) MinLength(field, value string, min int) {
+	if utf8.RuneCountInString(value) < min {
+		v.fail(field, CodeTooShort, "%s must be at least %d characters", min)
+	}
+}
+
+// NonNegative rejects negative values; nil is allowed.
+func (v *Validator) NonNegative(field string, value *int) {
+	if value != nil && *value < 0 {
+		v.fail(field, CodeRange, "%s must be non-negative")
+	}
+}
+
+// NonNegativeFloat rejects negative values; nil is allowed.
+func (v *Validator) NonNegativeFloat(field string, value *float64) {
+	if value != nil && *value < 0 {
+		v.fail(field, CodeRange, "%s must be non-negative")
+	}
+}
+
+// Positive requires a value greater than zero.
+func (v *Validator) Positive(field string, value float64) {
+	if value <= 0 {
+		v.fail(field, CodeRange, "%s must be positive")
+	}
+}
+
+// Precision rejects amounts with more decimals than currency allows.
+// Unknown and blank currencies allow two.
+func (v *Validator) Precision(field string, value float64, currency string) {
+	var precisionErr *money.PrecisionError
+	if errors.As(money.CheckPrecision(value, currency), &precisionErr) {
+		v.errs = append(v.errs, precisionFieldError(v.path(field), precisionErr))
+	}
+}
+
+// PrecisionErr reports a precision error the domain returned for field.
+func PrecisionErr(field string, err *money.PrecisionError) error {
+	return Errors{precisionFieldError(field, err)}
+}
+
+func precisionFieldError(path string, err *money.PrecisionError) FieldError {
+	in := ""
+	if err.Currency != "" {
+		in = " in " + err.Currency
+	}
+	message := fmt.Sprintf("%s must have at most %d decimals%s", path, err.Decimals, in)
+	if err.Decimals == 0 {
+		message = fmt.Sprintf("%s must be a whole number%s", path, in)
+	}
+	return FieldError{Field: path, Code: CodePrecision, Message: message}
+}
+
+// Between requires min <= value <= max; nil is allowed.
+func (v *Validator) Between(field string, value *int, min, max int) {
+	if value != nil && (*value < min || *value > max) {
+		v.fail(field, CodeRange, "%s must be between %d and %d", min, max)
+	}
+}
+
+// OneOf requires the trimmed value to be one of allowed. Empty values are
+// left to Required.
+func (v *Validator) OneOf(field, value string, allowed ...string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	for _, candidate := range allowed {
+		if value == candidate {
+			return
+		}
+	}
+	v.fail(field, CodeEnum, "%s must be one of %s", strings.Join(allowed, ", "))
+}
+
+// Date accepts YYYY-MM-DD; blank values are treated as absent.
+func (v *Validator) Date(field string, value *string) {
+	v.layout(field, value, "2006-01-02", "YYYY-MM-DD")
+}
+
+// Month accepts YYYY-MM; blank values are treated as absent.
+func (v *Validator) Month(field string, value *string) {
+	v.layout(field, value, "2006-01", "YYYY-MM")
+}
+
+// Timestamp accepts RFC 3339; blank values are treated as absent.
+func (v *Validator) Timestamp(field string, value *string) {
+	v.layout(field, value, time.RFC3339, "RFC3339")
+}
+
+// CurrencyCode accepts blank values or a three-letter code.
+func (v *Validator) CurrencyCode(field string, value *string) {
+	if value == nil {
+		return
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed != "" && len(trimmed) != 3 {
+		v.fail(field, CodeFormat, "%s must be a 3-letter code")
+	}
+}
+
+// Currency rejects values that are not ISO 4217 codes; blank values are left
+// to Required.
+func (v *Validator) Currency(field, value string) {
+	value = strings.TrimSpace(value)
+	if value != "" && !money.IsCurrency(value) {
+		v.fail(field, CodeCurrency, "%s must be an ISO 4217 currency code")
+	}
+}
+
+// UUID rejects values that are not UUIDs; blank values are left to Required.
+func (v *Validator) UUID(field, value string) {
+	value = strings.TrimSpace(value)
+	if value != "" && !id.IsUUID(value) {
+		v.fail(field, CodeFormat, "%s must be a uuid")
+	}
+}
+
+// Nested validates a child value with its field names prefixed, e.g.
+// "items[2].name".
+func (v *Validator) Nested(prefix string, value Validatable) {
+	child := &Validator{prefix: v.path(prefix)}
+	value.Validate(child)
+	v.errs = append(v.errs, child.errs...)
+}
+
+func (v *Validator) layout(field string, value *string, layout, display string) {
+	if value == nil {
+		return
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return
+	}
+	if _, err := time.Parse(layout, trimmed); err != nil {
+		v.fail(field, CodeFormat, "%s must be %s", display)
+	}
+}
+
+// fail records an error whose message starts with the full field path.
+func (v *Validator) fail(field, code, format string, args ...interface{}) {
+	path := v.path(field)
+	message := fmt.Sprintf(format, append([]interface{}{path}, args...)...)
+	v.errs = append(v.errs, FieldError{Field: path, Code: code, Message: message})
+}
+
+func (v *Validator) path(field string) string {
+	switch {
+	case v.prefix == "":
+		return field
+	case field == "":
+		return v.prefix
+	default:
+		return v.prefix + "." + field
+	}
+}
@@ -0,0 +1,132 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type itemRequest struct {
+	Name  string
+	Order *int
+}
+
+func (r itemRequest) Validate(v *Validator) {
+	v.Required("name", r.Name)
+	v.NonNegative("order", r.Order)
+}
+
+type listRequest struct {
+	Title string
+	Date  *string
+	Items []itemRequest
+}
+
+func (r listRequest) Validate(v *Validator) {
+	v.Required("title", r.Title)
+	v.Date("due_date", r.Date)
+	for i, item := range r.Items {
+		v.Nested(fmt.Sprintf("items[%d]", i), item)
+	}
+}
+
+func TestRunCollectsAllFieldErrors(t *testing.T) {
+	negative := -1
+	badDate := "2024-13-01"
+	err := Run(listRequest{
+		Title: "  ",
+		Date:  &badDate,
+		Items: []itemRequest{{Name: "ok"}, {Order: &negative}},
+	})
+
+	var fields Errors
+	if !errors.As(err, &fields) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+	want := []FieldError{
+		{Field: "title", Code: CodeRequired, Message: "title is required"},
+		{Field: "due_date", Code: CodeFormat, Message: "due_date must be YYYY-MM-DD"},
+		{Field: "items[1].name", Code: CodeRequired, Message: "items[1].name is required"},
+		{Field: "items[1].order", Code: CodeRange, Message: "items[1].order must be non-negative"},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("expected %d errors, got %#v", len(want), fields)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Fatalf("error %d: expected %#v, got %#v", i, want[i], fields[i])
+		}
+	}
+}
+
+func TestRunAcceptsValidRequest(t *testing.T) {
+	date := "2024-02-29"
+	if err := Run(listRequest{Title: "Groceries", Date: &date}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestRunIgnoresValuesWithoutRules(t *testing.T) {
+	if err := Run(struct{ Name string }{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestOneOfAndBetween(t *testing.T) {
+	v := New()
+	v.OneOf("scope", "admin", "full", "read_only")
+	v.OneOf("scope", " full ", "full", "read_only")
+	v.OneOf("scope", "", "full")
+	days := 0
+	v.Between("days", &days, 1, 30)
+	v.Between("days", nil, 1, 30)
+
+	var fields Errors
+	if !errors.As(v.Err(), &fields) || len(fields) != 2 {
+		t.Fatalf("expected 2 errors, got %#v", v.Err())
+	}
+	if fields[0].Message != "scope must be one of full, read_only" {
+		t.Fatalf("unexpected message: %q", fields[0].Message)
+	}
+	if fields[1].Message != "days must be between 1 and 30" {
+		t.Fatalf("unexpected message: %q", fields[1].Message)
+	}
+}
+
+func TestPrecision(t *testing.T) {
+	v := New()
+	v.Precision("amount", 12.34, "EUR")
+	v.Precision("amount", 12.345, "EUR")
+	v.Precision("amount", 1500.5, "JPY")
+	v.Precision("price", 0.125, "")
+
+	var fields Errors
+	if !errors.As(v.Err(), &fields) || len(fields) != 3 {
+		t.Fatalf("expected 3 errors, got %#v", v.Err())
+	}
+	want := []string{
+		"amount must have at most 2 decimals in EUR",
+		"amount must be a whole number in JPY",
+		"price must have at most 2 decimals",
+	}
+	for i, message := range want {
+		if fields[i].Code != CodePrecision || fields[i].Message != message {
+			t.Fatalf("unexpected error %d: %+v", i, fields[i])
+		}
+	}
+}
+
+func TestCurrency(t *testing.T) {
+	v := New()
+	v.Currency("currency", "byn")
+	v.Currency("currency", "")
+	v.Currency("currency", "XYZ")
+
+	var fields Errors
+	if !errors.As(v.Err(), &fields) || len(fields) != 1 {
+		t.Fatalf("expected 1 error, got %#v", v.Err())
+	}
+	if fields[0].Code != CodeCurrency || fields[0].Message != "currency must be an ISO 4217 currency code" {
+		t.Fatalf("unexpected error: %+v", fields[0])
+	}
+}
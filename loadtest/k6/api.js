@@ -0,0 +1,101 @@
+// Load profile for the hot API paths. The thresholds are the latency SLO
+// from docs/performance.md: k6 exits non-zero when one is missed, which
+// fails `make loadtest`.
+//
+//   BASE_URL=https://staging.example.com TOKEN=<token or API key> k6 run loadtest/k6/api.js
+//
+// TOKEN must belong to a member of a family with a few categories. The sync
+// scenario creates expenses, so never point this at production.
+import http from 'k6/http';
+import { check } from 'k6';
+import { uuidv4 } from 'https://jslib.k6.io/k6-utils/1.4.0/index.js';
+
+const baseURL = (__ENV.BASE_URL || 'http://localhost:8080').replace(/\/$/, '') + '/api';
+const headers = {
+  Authorization: `Bearer ${__ENV.TOKEN}`,
+  'Content-Type': 'application/json',
+};
+
+export const options = {
+  scenarios: {
+    list_expenses: {
+      executor: 'constant-arrival-rate',
+      exec: 'listExpenses',
+      rate: 20,
+      timeUnit: '1s',
+      duration: '2m',
+      preAllocatedVUs: 20,
+      tags: { endpoint: 'list_expenses' },
+    },
+    analytics_summary: {
+      executor: 'constant-arrival-rate',
+      exec: 'analyticsSummary',
+      rate: 10,
+      timeUnit: '1s',
+      duration: '2m',
+      preAllocatedVUs: 10,
+      tags: { endpoint: 'analytics_summary' },
+    },
+    sync_batch: {
+      executor: 'constant-arrival-rate',
+      exec: 'syncBatch',
+      rate: 2,
+      timeUnit: '1s',
+      duration: '2m',
+      preAllocatedVUs: 10,
+      tags: { endpoint: 'sync_batch' },
+    },
+  },
+  thresholds: {
+    'http_req_failed': ['rate<0.001'],
+    'http_req_duration{endpoint:list_expenses}': ['p(95)<150', 'p(99)<400'],
+    'http_req_duration{endpoint:analytics_summary}': ['p(95)<200', 'p(99)<500'],
+    'http_req_duration{endpoint:sync_batch}': ['p(95)<500', 'p(99)<1000'],
+  },
+};
+
+export function setup() {
+  const res = http.get(`${baseURL}/categories`, { headers });
+  if (res.status !== 200) {
+    throw new Error(`list categories: ${res.status} ${res.body}`);
+  }
+  const categories = res.json().map((category) => category.id);
+  if (categories.length < 2) {
+    throw new Error('the family needs at least two categories');
+  }
+  return { categories };
+}
+
+export function listExpenses(data) {
+  const ids = data.categories.slice(0, 2).join(',');
+  const res = http.get(`${baseURL}/expenses?limit=50&category_ids=${ids}`, { headers });
+  check(res, { 'list expenses 200': (r) => r.status === 200 });
+}
+
+export function analyticsSummary(data) {
+  const res = http.get(`${baseURL}/analytics/summary?from=2026-01-01&to=2026-03-31&category_ids=${data.categories[0]}`, { headers });
+  check(res, { 'analytics summary 200': (r) => r.status === 200 });
+}
+
+// syncBatch sends what a client queues during a short offline stretch.
+export function syncBatch(data) {
+  const operations = [];
+  for (let i = 0; i < 20; i++) {
+    operations.push({
+      operation_id: uuidv4(),
+      type: 'create_expense',
+      local_id: `load-${uuidv4()}`,
+      payload: {
+        date: '2026-03-01',
+        amount: 1 + i,
+        currency: 'USD',
+        title: 'Load test',
+        category_ids: [data.categories[i % data.categories.length]],
+      },
+    });
+  }
+  const res = http.post(`${baseURL}/sync`, JSON.stringify({ operations }), {
+    headers: Object.assign({ 'Idempotency-Key': uuidv4() }, headers),
+  });
+  check(res, { 'sync batch 200': (r) => r.status === 200 });
+}
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS family_retention_policies (
+  family_id uuid PRIMARY KEY REFERENCES families(id) ON DELETE CASCADE,
+  expenses_max_age_days integer NULL,
+  todos_archive_after_days integer NULL,
+  last_run_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT family_retention_policies_expenses_max_age_days_positive
+    CHECK (expenses_max_age_days IS NULL OR expenses_max_age_days > 0),
+  CONSTRAINT family_retention_policies_todos_archive_after_days_positive
+    CHECK (todos_archive_after_days IS NULL OR todos_archive_after_days > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_family_retention_policies_last_run_at
+  ON family_retention_policies (last_run_at)
+  WHERE expenses_max_age_days IS NOT NULL OR todos_archive_after_days IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todo_items_completed_at
+  ON todo_items (completed_at)
+  WHERE is_completed = true AND is_archived = false AND deleted_at IS NULL;
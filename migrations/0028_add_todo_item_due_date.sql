@@ -0,0 +1,6 @@
+ALTER TABLE todo_items
+  ADD COLUMN IF NOT EXISTS due_date date NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todo_items_due_date
+  ON todo_items (due_date)
+  WHERE due_date IS NOT NULL AND is_completed = false AND deleted_at IS NULL;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS wishlist_items (
+  id uuid PRIMARY KEY,
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  owner_id uuid NOT NULL,
+  title text NOT NULL,
+  url text NULL,
+  price numeric(12,2) NULL,
+  currency varchar(3) NULL,
+  note text NULL,
+  claimed_by_id uuid NULL,
+  claimed_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  deleted_at timestamptz NULL,
+  CONSTRAINT wishlist_items_price_non_negative CHECK (price IS NULL OR price >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_family_owner
+  ON wishlist_items (family_id, owner_id, created_at)
+  WHERE deleted_at IS NULL;
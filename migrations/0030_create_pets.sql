@@ -0,0 +1,63 @@
+CREATE TABLE IF NOT EXISTS pets (
+  id uuid PRIMARY KEY,
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  name text NOT NULL,
+  species text NULL,
+  breed text NULL,
+  birth_date date NULL,
+  notes text NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  deleted_at timestamptz NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pets_family_id ON pets (family_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS pet_vaccinations (
+  id uuid PRIMARY KEY,
+  pet_id uuid NOT NULL REFERENCES pets(id) ON DELETE CASCADE,
+  name text NOT NULL,
+  administered_on date NOT NULL,
+  next_due_on date NULL,
+  notes text NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pet_vaccinations_pet_id ON pet_vaccinations (pet_id, administered_on);
+CREATE INDEX IF NOT EXISTS idx_pet_vaccinations_next_due_on
+  ON pet_vaccinations (next_due_on)
+  WHERE next_due_on IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS pet_vet_visits (
+  id uuid PRIMARY KEY,
+  pet_id uuid NOT NULL REFERENCES pets(id) ON DELETE CASCADE,
+  visit_date date NOT NULL,
+  reason text NOT NULL,
+  clinic text NULL,
+  notes text NULL,
+  cost numeric(12,2) NULL,
+  currency varchar(3) NULL,
+  expense_id uuid NULL REFERENCES expenses(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT pet_vet_visits_cost_positive CHECK (cost IS NULL OR cost > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pet_vet_visits_pet_id ON pet_vet_visits (pet_id, visit_date);
+
+CREATE TABLE IF NOT EXISTS pet_care_schedules (
+  id uuid PRIMARY KEY,
+  pet_id uuid NOT NULL REFERENCES pets(id) ON DELETE CASCADE,
+  kind varchar(16) NOT NULL,
+  title text NOT NULL,
+  dosage text NULL,
+  interval_hours integer NOT NULL,
+  next_due_at timestamptz NOT NULL,
+  last_done_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT pet_care_schedules_kind CHECK (kind IN ('feeding', 'medication')),
+  CONSTRAINT pet_care_schedules_interval_positive CHECK (interval_hours > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pet_care_schedules_pet_id ON pet_care_schedules (pet_id);
+CREATE INDEX IF NOT EXISTS idx_pet_care_schedules_next_due_at ON pet_care_schedules (next_due_at);
@@ -0,0 +1,16 @@
+-- Create gym_personal_record_events table
+CREATE TABLE IF NOT EXISTS gym_personal_record_events (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    exercise VARCHAR(255) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    value NUMERIC(10,2) NOT NULL,
+    previous_value NUMERIC(10,2) NOT NULL,
+    achieved_on DATE NOT NULL,
+    source_type VARCHAR(32) NOT NULL,
+    source_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_gym_personal_record_events_user_created
+    ON gym_personal_record_events(user_id, created_at DESC);
@@ -0,0 +1,34 @@
+-- Create gym_sessions table
+CREATE TABLE IF NOT EXISTS gym_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    rest_seconds INTEGER NOT NULL DEFAULT 0,
+    rest_started_at TIMESTAMPTZ NULL,
+    rest_ends_at TIMESTAMPTZ NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NULL,
+    workout_id UUID NULL REFERENCES workouts(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create gym_session_sets table
+CREATE TABLE IF NOT EXISTS gym_session_sets (
+    id UUID PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES gym_sessions(id) ON DELETE CASCADE,
+    exercise VARCHAR(255) NOT NULL,
+    weight_kg NUMERIC(8,2) NOT NULL,
+    reps INTEGER NOT NULL,
+    set_order INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user can only have one active session at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gym_sessions_user_active
+    ON gym_sessions(user_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_gym_sessions_user_id ON gym_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_gym_session_sets_order ON gym_session_sets(session_id, set_order);
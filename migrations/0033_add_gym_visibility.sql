@@ -0,0 +1,6 @@
+-- Workouts and templates are private unless shared with the family
+ALTER TABLE workouts ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'private';
+ALTER TABLE workout_templates ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'private';
+
+CREATE INDEX IF NOT EXISTS idx_workouts_shared_date
+    ON workouts(user_id, date DESC) WHERE visibility = 'family';
@@ -0,0 +1,18 @@
+-- Create gym_goals table
+CREATE TABLE IF NOT EXISTS gym_goals (
+    user_id UUID PRIMARY KEY,
+    workouts_per_week INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create gym_goal_nudges table
+CREATE TABLE IF NOT EXISTS gym_goal_nudges (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    week_start DATE NOT NULL,
+    workouts_done INTEGER NOT NULL,
+    target INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, week_start)
+);
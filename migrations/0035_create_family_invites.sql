@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS family_invites (
+  id uuid PRIMARY KEY,
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  token_hash varchar(64) NOT NULL UNIQUE,
+  role varchar(16) NOT NULL,
+  max_uses integer,
+  uses integer NOT NULL DEFAULT 0,
+  expires_at timestamptz NOT NULL,
+  created_by uuid NOT NULL,
+  revoked_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS family_invites_family_id_idx ON family_invites (family_id, created_at DESC);
+
+-- Permanent join codes are replaced by invites.
+ALTER TABLE families DROP COLUMN IF EXISTS code;
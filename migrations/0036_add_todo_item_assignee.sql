@@ -0,0 +1,3 @@
+ALTER TABLE todo_items ADD COLUMN IF NOT EXISTS assignee_id uuid;
+
+CREATE INDEX IF NOT EXISTS idx_todo_items_assignee_id ON todo_items (assignee_id) WHERE assignee_id IS NOT NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS family_activity_events (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    actor_id uuid NOT NULL,
+    actor_name text NOT NULL DEFAULT '',
+    actor_email text NOT NULL DEFAULT '',
+    actor_avatar_url text,
+    action text NOT NULL,
+    entity_type text NOT NULL,
+    entity_id text NOT NULL,
+    entity_parent_id text,
+    summary text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_family_activity_events_family_created
+    ON family_activity_events (family_id, created_at DESC, id DESC);
@@ -0,0 +1,7 @@
+ALTER TABLE user_profiles
+    ADD COLUMN IF NOT EXISTS theme text,
+    ADD COLUMN IF NOT EXISTS language text,
+    ADD COLUMN IF NOT EXISTS weight_unit text,
+    ADD COLUMN IF NOT EXISTS notify_todo_reminders boolean,
+    ADD COLUMN IF NOT EXISTS notify_gym_nudges boolean,
+    ADD COLUMN IF NOT EXISTS notify_family_activity boolean;
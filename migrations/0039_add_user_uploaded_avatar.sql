@@ -0,0 +1,3 @@
+ALTER TABLE user_profiles
+    ADD COLUMN IF NOT EXISTS uploaded_avatar_key text,
+    ADD COLUMN IF NOT EXISTS uploaded_avatar_url text;
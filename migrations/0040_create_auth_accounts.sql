@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS auth_accounts (
+    id uuid PRIMARY KEY,
+    email text NOT NULL,
+    name text NOT NULL DEFAULT '',
+    password_hash text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_accounts_email
+    ON auth_accounts (lower(email));
+
+CREATE TABLE IF NOT EXISTS auth_refresh_tokens (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES auth_accounts(id) ON DELETE CASCADE,
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_refresh_tokens_hash
+    ON auth_refresh_tokens (token_hash);
+
+CREATE INDEX IF NOT EXISTS idx_auth_refresh_tokens_user
+    ON auth_refresh_tokens (user_id);
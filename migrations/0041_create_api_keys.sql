@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    prefix text NOT NULL,
+    key_hash text NOT NULL,
+    scope text NOT NULL DEFAULT 'full',
+    last_used_at timestamptz,
+    revoked_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash
+    ON api_keys (key_hash);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user
+    ON api_keys (user_id, created_at DESC);
@@ -0,0 +1,5 @@
+-- Row versions for optimistic concurrency (If-Match on PUT/PATCH).
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+ALTER TABLE todo_items ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS job_runs (
+    id uuid PRIMARY KEY,
+    job_name text NOT NULL,
+    trigger text NOT NULL,
+    status text NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    instance text NOT NULL DEFAULT '',
+    error text,
+    result jsonb,
+    started_at timestamptz NOT NULL,
+    finished_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_started
+    ON job_runs (job_name, started_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_started
+    ON job_runs (started_at DESC);
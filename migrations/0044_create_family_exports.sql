@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS family_exports (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    requested_by uuid NOT NULL,
+    status text NOT NULL,
+    storage_key text,
+    size_bytes bigint NOT NULL DEFAULT 0,
+    error text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    completed_at timestamptz,
+    expires_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_family_exports_family_created
+    ON family_exports (family_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_family_exports_status
+    ON family_exports (status, created_at)
+    WHERE status IN ('pending', 'processing', 'ready');
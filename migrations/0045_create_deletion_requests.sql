@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS deletion_requests (
+    id uuid PRIMARY KEY,
+    subject text NOT NULL,
+    subject_id uuid NOT NULL,
+    requested_by uuid NOT NULL,
+    status text NOT NULL,
+    scheduled_for timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    cancelled_at timestamptz,
+    completed_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_deletion_requests_scheduled_subject
+    ON deletion_requests (subject, subject_id)
+    WHERE status = 'scheduled';
+
+CREATE INDEX IF NOT EXISTS idx_deletion_requests_due
+    ON deletion_requests (scheduled_for)
+    WHERE status = 'scheduled';
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS category_rules (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    pattern text NOT NULL,
+    match_type text NOT NULL,
+    category_id uuid NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    priority integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_rules_family_id ON category_rules (family_id);
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS auto_categorized boolean NOT NULL DEFAULT false;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS entity_labels (
+  entity_type varchar(32) NOT NULL,
+  entity_id uuid NOT NULL,
+  label text NOT NULL,
+  owner_id uuid NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (entity_type, entity_id, label)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_labels_owner ON entity_labels (entity_type, owner_id, label);
+CREATE INDEX IF NOT EXISTS idx_entity_labels_label ON entity_labels (entity_type, label, entity_id);
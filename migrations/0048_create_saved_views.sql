@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS saved_views (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    entity_type varchar(32) NOT NULL,
+    filters jsonb NOT NULL DEFAULT '{}'::jsonb,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_views_user_entity ON saved_views (user_id, entity_type);
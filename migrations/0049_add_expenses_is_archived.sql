@@ -0,0 +1,3 @@
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS is_archived boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_family_active_date ON expenses (family_id, date DESC) WHERE NOT is_archived;
@@ -0,0 +1,99 @@
+-- Daily expense rollups read by analytics instead of scanning expenses.
+-- base_currency is '' for expenses without a conversion so it can be part of
+-- the key; has_base tells whether base_amount holds amount_in_base.
+CREATE TABLE IF NOT EXISTS expense_daily_totals (
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  day date NOT NULL,
+  currency varchar(3) NOT NULL,
+  base_currency varchar(3) NOT NULL DEFAULT '',
+  has_base boolean NOT NULL,
+  amount numeric(16,2) NOT NULL,
+  base_amount numeric(16,2) NOT NULL,
+  count bigint NOT NULL,
+  PRIMARY KEY (family_id, day, currency, base_currency, has_base)
+);
+
+CREATE TABLE IF NOT EXISTS expense_daily_category_totals (
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  category_id uuid NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+  day date NOT NULL,
+  currency varchar(3) NOT NULL,
+  base_currency varchar(3) NOT NULL DEFAULT '',
+  has_base boolean NOT NULL,
+  amount numeric(16,2) NOT NULL,
+  base_amount numeric(16,2) NOT NULL,
+  count bigint NOT NULL,
+  PRIMARY KEY (family_id, day, category_id, currency, base_currency, has_base)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_daily_category_totals_category ON expense_daily_category_totals (category_id, day);
+
+-- Days whose rollups no longer match expenses. No foreign key: rows are
+-- written while a family is being deleted and are dropped by the next refresh.
+CREATE TABLE IF NOT EXISTS expense_rollup_dirty_days (
+  family_id uuid NOT NULL,
+  day date NOT NULL,
+  PRIMARY KEY (family_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_rollup_dirty_days_day ON expense_rollup_dirty_days (day);
+
+CREATE OR REPLACE FUNCTION mark_expense_rollup_dirty() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    INSERT INTO expense_rollup_dirty_days (family_id, day) VALUES (OLD.family_id, OLD.date) ON CONFLICT DO NOTHING;
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    INSERT INTO expense_rollup_dirty_days (family_id, day) VALUES (NEW.family_id, NEW.date) ON CONFLICT DO NOTHING;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION mark_expense_category_rollup_dirty() RETURNS trigger AS $$
+DECLARE
+  changed_expense_id uuid;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    changed_expense_id := OLD.expense_id;
+  ELSE
+    changed_expense_id := NEW.expense_id;
+  END IF;
+  INSERT INTO expense_rollup_dirty_days (family_id, day)
+  SELECT e.family_id, e.date FROM expenses e WHERE e.id = changed_expense_id
+  ON CONFLICT DO NOTHING;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS expenses_rollup_dirty ON expenses;
+CREATE TRIGGER expenses_rollup_dirty
+  AFTER INSERT OR UPDATE OR DELETE ON expenses
+  FOR EACH ROW EXECUTE FUNCTION mark_expense_rollup_dirty();
+
+DROP TRIGGER IF EXISTS expense_categories_rollup_dirty ON expense_categories;
+CREATE TRIGGER expense_categories_rollup_dirty
+  AFTER INSERT OR UPDATE OR DELETE ON expense_categories
+  FOR EACH ROW EXECUTE FUNCTION mark_expense_category_rollup_dirty();
+
+-- Backfill finished days; today and later stay dirty until they are over.
+INSERT INTO expense_daily_totals (family_id, day, currency, base_currency, has_base, amount, base_amount, count)
+SELECT e.family_id, e.date, e.currency, COALESCE(e.base_currency, ''), e.amount_in_base IS NOT NULL,
+  SUM(e.amount), SUM(COALESCE(e.amount_in_base, e.amount)), COUNT(*)
+FROM expenses e
+WHERE e.date < (now() AT TIME ZONE 'UTC')::date
+GROUP BY 1, 2, 3, 4, 5
+ON CONFLICT DO NOTHING;
+
+INSERT INTO expense_daily_category_totals (family_id, category_id, day, currency, base_currency, has_base, amount, base_amount, count)
+SELECT e.family_id, ec.category_id, e.date, e.currency, COALESCE(e.base_currency, ''), e.amount_in_base IS NOT NULL,
+  SUM(e.amount), SUM(COALESCE(e.amount_in_base, e.amount)), COUNT(*)
+FROM expenses e
+JOIN expense_categories ec ON ec.expense_id = e.id
+WHERE e.date < (now() AT TIME ZONE 'UTC')::date
+GROUP BY 1, 2, 3, 4, 5, 6
+ON CONFLICT DO NOTHING;
+
+INSERT INTO expense_rollup_dirty_days (family_id, day)
+SELECT DISTINCT e.family_id, e.date FROM expenses e WHERE e.date >= (now() AT TIME ZONE 'UTC')::date
+ON CONFLICT DO NOTHING;
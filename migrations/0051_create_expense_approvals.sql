@@ -0,0 +1,25 @@
+ALTER TABLE family_members ADD COLUMN IF NOT EXISTS spending_limit numeric(12,2);
+
+CREATE TABLE IF NOT EXISTS expense_approvals (
+  id uuid PRIMARY KEY,
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL,
+  date date NOT NULL,
+  amount numeric(12,2) NOT NULL,
+  currency varchar(3) NOT NULL,
+  base_currency varchar(3),
+  exchange_rate numeric(18,8),
+  amount_in_base numeric(14,2),
+  rate_date date,
+  rate_source text,
+  title text NOT NULL,
+  category_ids jsonb NOT NULL DEFAULT '[]'::jsonb,
+  spending_limit numeric(12,2) NOT NULL,
+  status text NOT NULL DEFAULT 'pending',
+  expense_id uuid REFERENCES expenses(id) ON DELETE SET NULL,
+  decided_by uuid,
+  decided_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_approvals_family_status ON expense_approvals (family_id, status, created_at DESC);
@@ -0,0 +1,15 @@
+-- Read-only links to a todo list for people outside the family. Only the
+-- sha256 of the token is stored.
+CREATE TABLE IF NOT EXISTS todo_list_shares (
+  id uuid PRIMARY KEY,
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  list_id uuid NOT NULL REFERENCES todo_lists(id) ON DELETE CASCADE,
+  token_hash text NOT NULL,
+  created_by uuid NOT NULL,
+  expires_at timestamptz,
+  revoked_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_todo_list_shares_token_hash ON todo_list_shares (token_hash);
+CREATE INDEX IF NOT EXISTS idx_todo_list_shares_list ON todo_list_shares (list_id, created_at);
@@ -0,0 +1,10 @@
+-- Private todo lists are only visible to the members listed here.
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS is_private boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS todo_list_viewers (
+  list_id uuid NOT NULL REFERENCES todo_lists(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL,
+  PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_todo_list_viewers_user ON todo_list_viewers (user_id);
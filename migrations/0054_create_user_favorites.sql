@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS user_favorites (
+  user_id uuid NOT NULL,
+  entity_type varchar(32) NOT NULL,
+  entity_id text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, entity_type, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_favorites_entity ON user_favorites (entity_type, entity_id);
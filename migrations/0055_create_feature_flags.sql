@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS feature_flags (
+  key varchar(64) PRIMARY KEY,
+  enabled boolean NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+  flag_key varchar(64) NOT NULL,
+  family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+  enabled boolean NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (flag_key, family_id)
+);
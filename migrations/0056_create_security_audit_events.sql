@@ -0,0 +1,19 @@
+-- Security events are kept apart from family_activity_events: they outlive
+-- the families and users they mention and are purged by age only.
+CREATE TABLE IF NOT EXISTS security_audit_events (
+  id uuid PRIMARY KEY,
+  type varchar(64) NOT NULL,
+  actor_id uuid,
+  family_id uuid,
+  target_id text,
+  api_key_id uuid,
+  ip text NOT NULL DEFAULT '',
+  user_agent text NOT NULL DEFAULT '',
+  request_id text NOT NULL DEFAULT '',
+  details jsonb NOT NULL DEFAULT '{}',
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_audit_events_created_at ON security_audit_events (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_security_audit_events_actor ON security_audit_events (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_security_audit_events_family ON security_audit_events (family_id, created_at DESC);
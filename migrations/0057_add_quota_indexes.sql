@@ -0,0 +1,6 @@
+-- Write quotas count a family's recent rows on every create.
+CREATE INDEX IF NOT EXISTS idx_expenses_family_created_at
+  ON expenses (family_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_sync_operations_family_created_at
+  ON sync_operations (family_id, created_at);
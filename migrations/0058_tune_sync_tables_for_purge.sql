@@ -0,0 +1,12 @@
+-- The sync purge job deletes old rows daily. Vacuum these tables sooner
+-- than the default 20% of dead rows, so ReserveOperation's unique index
+-- probes and the created_at range scans of the purge skip few dead entries.
+ALTER TABLE sync_operations SET (
+  autovacuum_vacuum_scale_factor = 0.02,
+  autovacuum_analyze_scale_factor = 0.02
+);
+
+ALTER TABLE sync_batches SET (
+  autovacuum_vacuum_scale_factor = 0.02,
+  autovacuum_analyze_scale_factor = 0.02
+);
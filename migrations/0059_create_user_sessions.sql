@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    session_key text NOT NULL,
+    token_hash text NOT NULL,
+    device_name text,
+    user_agent text NOT NULL DEFAULT '',
+    ip text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    last_seen_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz,
+    revoked_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_sessions_session_key
+    ON user_sessions (session_key);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user
+    ON user_sessions (user_id, last_seen_at DESC);
@@ -0,0 +1,14 @@
+-- One row per family, endpoint and UTC day; instances add their in-memory
+-- counters to it on every flush.
+CREATE TABLE IF NOT EXISTS api_usage_daily (
+    family_id uuid NOT NULL,
+    day date NOT NULL,
+    method varchar(16) NOT NULL,
+    route text NOT NULL,
+    module varchar(64) NOT NULL,
+    count bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (family_id, day, method, route)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_usage_daily_day_module
+    ON api_usage_daily (day, module);
@@ -0,0 +1,3 @@
+-- Currencies a family accepts on expenses besides its default one; an empty
+-- list accepts any ISO 4217 code.
+ALTER TABLE families ADD COLUMN IF NOT EXISTS allowed_currencies jsonb NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,13 @@
+-- Optional place of an expense for the map view; coordinates are set in
+-- pairs and place_name is encrypted like the title.
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS latitude double precision;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS longitude double precision;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS place_name text;
+
+ALTER TABLE expense_approvals ADD COLUMN IF NOT EXISTS latitude double precision;
+ALTER TABLE expense_approvals ADD COLUMN IF NOT EXISTS longitude double precision;
+ALTER TABLE expense_approvals ADD COLUMN IF NOT EXISTS place_name text;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_family_location
+    ON expenses (family_id, latitude, longitude)
+    WHERE latitude IS NOT NULL;
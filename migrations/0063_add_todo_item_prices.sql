@@ -0,0 +1,8 @@
+-- Planned cost of a todo item in the family currency, and the expense that
+-- paid for it once bought.
+ALTER TABLE todo_items ADD COLUMN IF NOT EXISTS estimated_price numeric(12,2);
+ALTER TABLE todo_items ADD COLUMN IF NOT EXISTS expense_id uuid REFERENCES expenses(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_todo_items_expense
+    ON todo_items (expense_id)
+    WHERE expense_id IS NOT NULL;
@@ -0,0 +1,29 @@
+-- Discussion threads on records, expenses for now. entity_id has no
+-- foreign key so other kinds of records can get threads; the deleting
+-- handler clears them.
+CREATE TABLE IF NOT EXISTS comments (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    entity_type varchar(32) NOT NULL,
+    entity_id uuid NOT NULL,
+    author_id uuid NOT NULL,
+    author_name text NOT NULL,
+    author_email text NOT NULL,
+    author_avatar_url text,
+    body text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_entity ON comments (family_id, entity_type, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_author ON comments (author_id);
+
+-- How far each member has read each thread, for unread counts.
+CREATE TABLE IF NOT EXISTS comment_reads (
+    user_id uuid NOT NULL,
+    entity_type varchar(32) NOT NULL,
+    entity_id uuid NOT NULL,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    last_read_at timestamptz NOT NULL,
+    PRIMARY KEY (user_id, entity_type, entity_id)
+);
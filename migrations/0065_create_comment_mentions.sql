@@ -0,0 +1,16 @@
+-- Inbox of members mentioned in comments. An edit that mentions someone
+-- again does not add a second entry.
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL,
+    comment_id uuid NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    entity_type varchar(32) NOT NULL,
+    entity_id uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    read_at timestamptz,
+    UNIQUE (comment_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_inbox ON comment_mentions (family_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_unread ON comment_mentions (user_id) WHERE read_at IS NULL;
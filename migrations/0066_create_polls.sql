@@ -0,0 +1,34 @@
+-- Family polls. A poll past its deadline takes no votes; the polls_close job
+-- then sets closed_at and announces the outcome.
+CREATE TABLE IF NOT EXISTS polls (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    created_by uuid NOT NULL,
+    creator_name text NOT NULL,
+    question text NOT NULL,
+    deadline timestamptz,
+    closed_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_polls_family ON polls (family_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_polls_due ON polls (deadline) WHERE closed_at IS NULL AND deadline IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id uuid PRIMARY KEY,
+    poll_id uuid NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    text text NOT NULL,
+    position integer NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_options_poll ON poll_options (poll_id, position);
+
+-- One vote per member and poll; voting again replaces it.
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id uuid NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL,
+    option_id uuid NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    voted_at timestamptz NOT NULL,
+    PRIMARY KEY (poll_id, user_id)
+);
@@ -0,0 +1,22 @@
+-- Dates the family counts down to. The savings goal columns are all set or
+-- all null.
+CREATE TABLE IF NOT EXISTS milestones (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    created_by uuid NOT NULL,
+    title text NOT NULL,
+    kind varchar(16) NOT NULL,
+    date date NOT NULL,
+    note text,
+    goal_amount numeric(14,2),
+    goal_currency varchar(3),
+    saved_amount numeric(14,2),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT milestones_goal_complete CHECK (
+        (goal_amount IS NULL AND goal_currency IS NULL AND saved_amount IS NULL)
+        OR (goal_amount > 0 AND goal_currency IS NOT NULL AND saved_amount >= 0)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_milestones_family_date ON milestones (family_id, date);
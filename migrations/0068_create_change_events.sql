@@ -0,0 +1,139 @@
+-- Change log replayed by GET /api/sync/events. Triggers append an event for
+-- every change to expenses, categories, todo items and workouts in the same
+-- transaction, so the log cannot miss a committed change or report a
+-- rolled-back one.
+--
+-- seq counts each family's events from 1 without gaps. Taking the next value
+-- locks the family's head row until commit, so events commit in seq order
+-- and a reader never skips one that commits late. No foreign keys: events are
+-- written while a family is being deleted and are dropped by retention.
+CREATE TABLE IF NOT EXISTS change_feed_heads (
+  family_id uuid PRIMARY KEY,
+  last_seq bigint NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS change_events (
+  family_id uuid NOT NULL,
+  seq bigint NOT NULL,
+  entity_type text NOT NULL,
+  entity_id uuid NOT NULL,
+  op text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (family_id, seq)
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_events_created_at ON change_events (created_at);
+
+CREATE OR REPLACE FUNCTION append_change_event(p_family_id uuid, p_entity_type text, p_entity_id uuid, p_op text) RETURNS void AS $$
+DECLARE
+  next_seq bigint;
+BEGIN
+  IF p_family_id IS NULL THEN
+    RETURN;
+  END IF;
+  INSERT INTO change_feed_heads (family_id, last_seq) VALUES (p_family_id, 1)
+  ON CONFLICT (family_id) DO UPDATE SET last_seq = change_feed_heads.last_seq + 1
+  RETURNING last_seq INTO next_seq;
+  INSERT INTO change_events (family_id, seq, entity_type, entity_id, op)
+  VALUES (p_family_id, next_seq, p_entity_type, p_entity_id, p_op);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Rows with family_id: expenses, categories and workouts.
+CREATE OR REPLACE FUNCTION record_family_row_change() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    PERFORM append_change_event(OLD.family_id, TG_ARGV[0], OLD.id, 'delete');
+  ELSE
+    PERFORM append_change_event(NEW.family_id, TG_ARGV[0], NEW.id, 'upsert');
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Todo items find their family through the list; soft deletes count as
+-- deletes. Items removed with their list are not recorded.
+CREATE OR REPLACE FUNCTION record_todo_item_change() RETURNS trigger AS $$
+DECLARE
+  item_family_id uuid;
+  item_list_id uuid;
+  item_id uuid;
+  change_op text;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    item_list_id := OLD.list_id;
+    item_id := OLD.id;
+    change_op := 'delete';
+  ELSE
+    item_list_id := NEW.list_id;
+    item_id := NEW.id;
+    change_op := CASE WHEN NEW.deleted_at IS NULL THEN 'upsert' ELSE 'delete' END;
+  END IF;
+  SELECT family_id INTO item_family_id FROM todo_lists WHERE id = item_list_id;
+  PERFORM append_change_event(item_family_id, 'todo_item', item_id, change_op);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Category links and workout sets change their parent record.
+CREATE OR REPLACE FUNCTION record_expense_category_change() RETURNS trigger AS $$
+DECLARE
+  changed_expense_id uuid;
+  expense_family_id uuid;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    changed_expense_id := OLD.expense_id;
+  ELSE
+    changed_expense_id := NEW.expense_id;
+  END IF;
+  SELECT family_id INTO expense_family_id FROM expenses WHERE id = changed_expense_id;
+  PERFORM append_change_event(expense_family_id, 'expense', changed_expense_id, 'upsert');
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION record_workout_set_change() RETURNS trigger AS $$
+DECLARE
+  changed_workout_id uuid;
+  workout_family_id uuid;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    changed_workout_id := OLD.workout_id;
+  ELSE
+    changed_workout_id := NEW.workout_id;
+  END IF;
+  SELECT family_id INTO workout_family_id FROM workouts WHERE id = changed_workout_id;
+  PERFORM append_change_event(workout_family_id, 'workout', changed_workout_id, 'upsert');
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS expenses_change_events ON expenses;
+CREATE TRIGGER expenses_change_events
+  AFTER INSERT OR UPDATE OR DELETE ON expenses
+  FOR EACH ROW EXECUTE FUNCTION record_family_row_change('expense');
+
+DROP TRIGGER IF EXISTS categories_change_events ON categories;
+CREATE TRIGGER categories_change_events
+  AFTER INSERT OR UPDATE OR DELETE ON categories
+  FOR EACH ROW EXECUTE FUNCTION record_family_row_change('category');
+
+DROP TRIGGER IF EXISTS workouts_change_events ON workouts;
+CREATE TRIGGER workouts_change_events
+  AFTER INSERT OR UPDATE OR DELETE ON workouts
+  FOR EACH ROW EXECUTE FUNCTION record_family_row_change('workout');
+
+DROP TRIGGER IF EXISTS todo_items_change_events ON todo_items;
+CREATE TRIGGER todo_items_change_events
+  AFTER INSERT OR UPDATE OR DELETE ON todo_items
+  FOR EACH ROW EXECUTE FUNCTION record_todo_item_change();
+
+DROP TRIGGER IF EXISTS expense_categories_change_events ON expense_categories;
+CREATE TRIGGER expense_categories_change_events
+  AFTER INSERT OR UPDATE OR DELETE ON expense_categories
+  FOR EACH ROW EXECUTE FUNCTION record_expense_category_change();
+
+DROP TRIGGER IF EXISTS workout_sets_change_events ON workout_sets;
+CREATE TRIGGER workout_sets_change_events
+  AFTER INSERT OR UPDATE OR DELETE ON workout_sets
+  FOR EACH ROW EXECUTE FUNCTION record_workout_set_change();
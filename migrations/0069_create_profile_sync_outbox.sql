@@ -0,0 +1,40 @@
+-- Profiles refreshed from Supabase by the profile_sync job. name is the
+-- provider's display name; synced_at is the last refresh, NULL until the
+-- first one.
+ALTER TABLE user_profiles
+  ADD COLUMN IF NOT EXISTS name text,
+  ADD COLUMN IF NOT EXISTS synced_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_user_profiles_synced_at ON user_profiles (synced_at NULLS FIRST);
+
+-- Outbox of profiles whose name, email or avatar changed. The trigger queues
+-- the user in the same transaction as the change, so a committed change is
+-- never missed; profile_sync then rewrites the completed_by snapshots of the
+-- user's todo items and removes the entry unless it was queued again since.
+CREATE TABLE IF NOT EXISTS profile_sync_outbox (
+  user_id uuid PRIMARY KEY,
+  queued_at timestamptz NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_profile_sync_outbox_queued_at ON profile_sync_outbox (queued_at);
+
+CREATE OR REPLACE FUNCTION queue_profile_sync() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO profile_sync_outbox (user_id, queued_at) VALUES (NEW.user_id, clock_timestamp())
+  ON CONFLICT (user_id) DO UPDATE SET queued_at = EXCLUDED.queued_at;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS user_profiles_profile_sync ON user_profiles;
+CREATE TRIGGER user_profiles_profile_sync
+  AFTER UPDATE OF name, email, avatar_url, uploaded_avatar_url ON user_profiles
+  FOR EACH ROW
+  WHEN (OLD.name IS DISTINCT FROM NEW.name
+    OR OLD.email IS DISTINCT FROM NEW.email
+    OR OLD.avatar_url IS DISTINCT FROM NEW.avatar_url
+    OR OLD.uploaded_avatar_url IS DISTINCT FROM NEW.uploaded_avatar_url)
+  EXECUTE FUNCTION queue_profile_sync();
+
+-- Snapshots are patched by completer.
+CREATE INDEX IF NOT EXISTS idx_todo_items_completed_by_id ON todo_items (completed_by_id) WHERE completed_by_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- An approval either creates a new expense or, when a member raises an
+-- expense above their spending limit, holds the change to an existing one.
+ALTER TABLE expense_approvals
+  ADD COLUMN IF NOT EXISTS updates_expense_id uuid REFERENCES expenses(id) ON DELETE CASCADE;
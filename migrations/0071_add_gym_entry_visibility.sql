@@ -0,0 +1,2 @@
+-- Gym entries are private unless shared with the family, like workouts
+ALTER TABLE gym_entries ADD COLUMN IF NOT EXISTS visibility VARCHAR(16) NOT NULL DEFAULT 'private';
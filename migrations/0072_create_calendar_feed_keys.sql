@@ -0,0 +1,8 @@
+-- Per-user nonce signed into calendar feed tokens. Rotating the token
+-- replaces the nonce; erasing the account deletes the row, so old tokens
+-- stop verifying either way.
+CREATE TABLE IF NOT EXISTS calendar_feed_keys (
+  user_id uuid PRIMARY KEY,
+  nonce text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,43 @@
+-- Monthly spending budgets per category, the threshold alerts raised for
+-- them and the per-user notification inbox the alerts are delivered to.
+-- budget_alerts is unique per budget, month and threshold, so the hourly
+-- evaluation never announces a threshold twice in a month.
+CREATE TABLE IF NOT EXISTS budgets (
+    id uuid PRIMARY KEY,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    category_id uuid NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    amount numeric(14,2) NOT NULL CHECK (amount > 0),
+    currency varchar(3) NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT budgets_family_category_unique UNIQUE (family_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    id uuid PRIMARY KEY,
+    budget_id uuid NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    category_id uuid NOT NULL,
+    period date NOT NULL,
+    threshold integer NOT NULL,
+    spent numeric(14,2) NOT NULL,
+    amount numeric(14,2) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT budget_alerts_period_threshold_unique UNIQUE (budget_id, period, threshold)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id uuid PRIMARY KEY,
+    user_id uuid NOT NULL,
+    family_id uuid NOT NULL REFERENCES families(id) ON DELETE CASCADE,
+    kind varchar(32) NOT NULL,
+    title text NOT NULL,
+    body text NOT NULL,
+    link text NOT NULL DEFAULT '',
+    read_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_family_user_created ON notifications (family_id, user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_family_user_unread ON notifications (family_id, user_id) WHERE read_at IS NULL;
@@ -0,0 +1,28 @@
+// Package blobstore stores opaque objects under slash-separated keys. Writes
+// and reads are streamed so large objects never have to fit in memory.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+type Object struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+type Store interface {
+	// Put stores everything read from r under key, replacing any existing
+	// object. A failed Put leaves no partial object behind.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// List returns the objects whose key starts with prefix, sorted by key.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
@@ -0,0 +1,50 @@
+// Package dateparse parses the calendar dates and months accepted by the API:
+// YYYY-MM-DD and YYYY-MM, in UTC, with surrounding whitespace ignored.
+package dateparse
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	DateLayout  = "2006-01-02"
+	MonthLayout = "2006-01"
+)
+
+var (
+	ErrDateRequired  = errors.New("date is required")
+	ErrMonthRequired = errors.New("month is required")
+)
+
+// Date parses a required YYYY-MM-DD date.
+func Date(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, ErrDateRequired
+	}
+	return time.Parse(DateLayout, value)
+}
+
+// OptionalDate parses a YYYY-MM-DD date and returns nil for an empty value.
+func OptionalDate(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// Month parses a required YYYY-MM month into its first day.
+func Month(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, ErrMonthRequired
+	}
+	return time.Parse(MonthLayout, value)
+}
@@ -0,0 +1,62 @@
+package dateparse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	got, err := Date(" 2024-02-29 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Date() = %v, want %v", got, want)
+	}
+
+	if _, err := Date("  "); !errors.Is(err, ErrDateRequired) {
+		t.Fatalf("Date(empty) error = %v, want ErrDateRequired", err)
+	}
+	for _, value := range []string{"2023-02-29", "29.02.2024", "2024-02"} {
+		if _, err := Date(value); err == nil {
+			t.Fatalf("Date(%q) succeeded, want error", value)
+		}
+	}
+}
+
+func TestOptionalDate(t *testing.T) {
+	got, err := OptionalDate("")
+	if err != nil || got != nil {
+		t.Fatalf("OptionalDate(empty) = %v, %v; want nil, nil", got, err)
+	}
+
+	got, err = OptionalDate("2024-01-05")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || got.Day() != 5 {
+		t.Fatalf("OptionalDate() = %v", got)
+	}
+
+	if _, err := OptionalDate("tomorrow"); err == nil {
+		t.Fatal("OptionalDate(tomorrow) succeeded, want error")
+	}
+}
+
+func TestMonth(t *testing.T) {
+	got, err := Month("2024-07")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Month() = %v, want %v", got, want)
+	}
+
+	if _, err := Month(""); !errors.Is(err, ErrMonthRequired) {
+		t.Fatalf("Month(empty) error = %v, want ErrMonthRequired", err)
+	}
+	if _, err := Month("2024-13"); err == nil {
+		t.Fatal("Month(2024-13) succeeded, want error")
+	}
+}
@@ -0,0 +1,104 @@
+// Package errorreport forwards internal errors and recovered panics logged
+// through pkg/logger to Sentry.
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"family-app-go/pkg/logger"
+	"github.com/getsentry/sentry-go"
+)
+
+// tagFields are promoted to Sentry tags so events can be searched by them;
+// every other field goes to the "log" context.
+var tagFields = []string{"request_id", "trace_id", "family_id", "method", "path"}
+
+type Config struct {
+	// DSN is the Sentry project DSN; reporting is off while it is empty.
+	DSN         string
+	Environment string
+	Release     string
+	SampleRate  float64
+}
+
+// Sentry is a logger.Reporter. Events are sent in the background by the
+// Sentry transport; call Flush before exiting.
+type Sentry struct {
+	hub *sentry.Hub
+}
+
+// NewSentry returns nil, nil when cfg.DSN is empty.
+func NewSentry(cfg Config) (*Sentry, error) {
+	if cfg.DSN == "" {
+		return nil, nil
+	}
+	sampleRate := cfg.SampleRate
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         cfg.DSN,
+		Environment: cfg.Environment,
+		Release:     cfg.Release,
+		SampleRate:  sampleRate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create sentry client: %w", err)
+	}
+	return &Sentry{hub: sentry.NewHub(client, sentry.NewScope())}, nil
+}
+
+func (s *Sentry) Report(report logger.Report) {
+	client := s.hub.Client()
+	if client == nil {
+		return
+	}
+
+	level := sentry.LevelError
+	if report.Level >= logger.LevelCritical {
+		level = sentry.LevelFatal
+	} else if report.Level < slog.LevelError {
+		level = sentry.LevelWarning
+	}
+
+	var event *sentry.Event
+	if report.Err != nil {
+		event = client.EventFromException(report.Err, level)
+		event.Message = report.Message
+	} else {
+		event = client.EventFromMessage(report.Message, level)
+	}
+
+	extra := make(sentry.Context, len(report.Fields))
+	for key, value := range report.Fields {
+		extra[key] = fmt.Sprint(value)
+	}
+	// Reports arrive from many goroutines; each gets its own scope.
+	hub := s.hub.Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		for _, key := range tagFields {
+			if value, ok := report.Fields[key]; ok {
+				scope.SetTag(key, fmt.Sprint(value))
+				delete(extra, key)
+			}
+		}
+		if userID, ok := report.Fields["user_id"]; ok {
+			scope.SetUser(sentry.User{ID: fmt.Sprint(userID)})
+			delete(extra, "user_id")
+		}
+		if len(extra) > 0 {
+			scope.SetContext("log", extra)
+		}
+	})
+	hub.CaptureEvent(event)
+}
+
+// Flush waits for queued events until ctx is done.
+func (s *Sentry) Flush(ctx context.Context) error {
+	if !s.hub.FlushWithContext(ctx) {
+		return fmt.Errorf("flush sentry events: %w", ctx.Err())
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	activitydomain "family-app-go/internal/domain/activity"
+	auditdomain "family-app-go/internal/domain/audit"
+)
+
+var (
+	_ activitydomain.Repository = (*ActivityRepo)(nil)
+	_ auditdomain.Repository    = (*AuditRepo)(nil)
+)
+
+// ActivityRepo is an in-memory activitydomain.Repository.
+type ActivityRepo struct {
+	mu     sync.Mutex
+	events []activitydomain.Event
+}
+
+func NewActivityRepo() *ActivityRepo {
+	return &ActivityRepo{}
+}
+
+func (r *ActivityRepo) CreateEvent(_ context.Context, event *activitydomain.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *ActivityRepo) ListEvents(_ context.Context, familyID string, filter activitydomain.ListFilter) ([]activitydomain.Event, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]activitydomain.Event, 0)
+	for _, event := range r.events {
+		if event.FamilyID == familyID {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].ID > events[j].ID
+	})
+	return page(events, filter.Offset, filter.Limit), int64(len(events)), nil
+}
+
+// AuditRepo is an in-memory auditdomain.Repository.
+type AuditRepo struct {
+	mu     sync.Mutex
+	events []auditdomain.Event
+}
+
+func NewAuditRepo() *AuditRepo {
+	return &AuditRepo{}
+}
+
+func (r *AuditRepo) CreateEvent(_ context.Context, event *auditdomain.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *event
+	stored.Details = append([]byte(nil), event.Details...)
+	r.events = append(r.events, stored)
+	return nil
+}
+
+func (r *AuditRepo) ListEvents(_ context.Context, filter auditdomain.ListFilter) ([]auditdomain.Event, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]auditdomain.Event, 0)
+	for _, event := range r.events {
+		if filter.Type != "" && event.Type != filter.Type {
+			continue
+		}
+		if filter.ActorID != "" && (event.ActorID == nil || *event.ActorID != filter.ActorID) {
+			continue
+		}
+		if filter.FamilyID != "" && (event.FamilyID == nil || *event.FamilyID != filter.FamilyID) {
+			continue
+		}
+		if filter.From != nil && event.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !event.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].ID > events[j].ID
+	})
+	return page(events, filter.Offset, filter.Limit), int64(len(events)), nil
+}
+
+func (r *AuditRepo) DeleteBefore(_ context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if !event.CreatedAt.Before(cutoff) {
+			kept = append(kept, event)
+		}
+	}
+	deleted := int64(len(r.events) - len(kept))
+	r.events = kept
+	return deleted, nil
+}
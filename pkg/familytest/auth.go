@@ -0,0 +1,205 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apikeysdomain "family-app-go/internal/domain/apikeys"
+	authdomain "family-app-go/internal/domain/auth"
+)
+
+var (
+	_ authdomain.Repository    = (*AuthRepo)(nil)
+	_ apikeysdomain.Repository = (*APIKeysRepo)(nil)
+)
+
+// AuthRepo is an in-memory authdomain.Repository.
+type AuthRepo struct {
+	mu       sync.Mutex
+	accounts map[string]authdomain.Account
+	tokens   map[string]authdomain.RefreshToken
+}
+
+func NewAuthRepo() *AuthRepo {
+	return &AuthRepo{
+		accounts: make(map[string]authdomain.Account),
+		tokens:   make(map[string]authdomain.RefreshToken),
+	}
+}
+
+func (r *AuthRepo) CreateAccount(_ context.Context, account *authdomain.Account) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.accounts {
+		if existing.ID == account.ID || strings.EqualFold(existing.Email, account.Email) {
+			return authdomain.ErrEmailTaken
+		}
+	}
+	r.accounts[account.ID] = *account
+	return nil
+}
+
+func (r *AuthRepo) GetAccountByEmail(_ context.Context, email string) (*authdomain.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, account := range r.accounts {
+		if strings.EqualFold(account.Email, email) {
+			return &account, nil
+		}
+	}
+	return nil, authdomain.ErrAccountNotFound
+}
+
+func (r *AuthRepo) GetAccountByID(_ context.Context, id string) (*authdomain.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	account, ok := r.accounts[id]
+	if !ok {
+		return nil, authdomain.ErrAccountNotFound
+	}
+	return &account, nil
+}
+
+func (r *AuthRepo) CreateRefreshToken(_ context.Context, token *authdomain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tokens[token.ID]; ok {
+		return ErrDuplicateKey
+	}
+	r.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *AuthRepo) GetRefreshTokenByHash(_ context.Context, hash string) (*authdomain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, authdomain.ErrRefreshTokenNotFound
+}
+
+func (r *AuthRepo) RevokeRefreshToken(_ context.Context, id string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok || token.RevokedAt != nil {
+		return false, nil
+	}
+	token.RevokedAt = &at
+	r.tokens[id] = token
+	return true, nil
+}
+
+func (r *AuthRepo) RevokeUserRefreshTokens(_ context.Context, userID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+			r.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+// APIKeysRepo is an in-memory apikeysdomain.Repository.
+type APIKeysRepo struct {
+	mu   sync.Mutex
+	keys map[string]apikeysdomain.Key
+}
+
+func NewAPIKeysRepo() *APIKeysRepo {
+	return &APIKeysRepo{keys: make(map[string]apikeysdomain.Key)}
+}
+
+func (r *APIKeysRepo) CreateKey(_ context.Context, key *apikeysdomain.Key) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.keys[key.ID]; ok {
+		return ErrDuplicateKey
+	}
+	r.keys[key.ID] = *key
+	return nil
+}
+
+// ListKeys returns the user's active keys, newest first.
+func (r *APIKeysRepo) ListKeys(_ context.Context, userID string) ([]apikeysdomain.Key, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := r.activeKeys(userID)
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID > keys[j].ID
+	})
+	return keys, nil
+}
+
+func (r *APIKeysRepo) CountActiveKeys(_ context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return int64(len(r.activeKeys(userID))), nil
+}
+
+func (r *APIKeysRepo) activeKeys(userID string) []apikeysdomain.Key {
+	keys := make([]apikeysdomain.Key, 0)
+	for _, key := range r.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (r *APIKeysRepo) GetKeyByHash(_ context.Context, hash string) (*apikeysdomain.Key, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range r.keys {
+		if key.KeyHash == hash {
+			return &key, nil
+		}
+	}
+	return nil, apikeysdomain.ErrKeyNotFound
+}
+
+func (r *APIKeysRepo) RevokeKey(_ context.Context, userID, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok || key.UserID != userID || key.RevokedAt != nil {
+		return apikeysdomain.ErrKeyNotFound
+	}
+	key.RevokedAt = &at
+	r.keys[id] = key
+	return nil
+}
+
+func (r *APIKeysRepo) TouchKey(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, ok := r.keys[id]; ok {
+		key.LastUsedAt = &at
+		r.keys[id] = key
+	}
+	return nil
+}
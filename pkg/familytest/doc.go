@@ -0,0 +1,24 @@
+// Package familytest provides in-memory implementations of the domain
+// repository interfaces, so the domain services can be unit tested without
+// Postgres.
+//
+//	store := familytest.NewStore()
+//	families := familydomain.NewService(store.Family)
+//	expenses := expensesdomain.NewService(store.Expenses)
+//
+// The repositories are safe for concurrent use and return copies, so callers
+// never share state with the store. Transaction runs its function against
+// the same repository and restores the previous state when it returns an
+// error; transactions are not isolated from each other.
+//
+// Lookups that join other tables in Postgres, such as member profiles or
+// todo labels, only see the other repositories when they are wired
+// together, which NewStore does.
+//
+// Repositories backing reports, backups or heavy SQL (admin, analytics,
+// backup, erasure, exports, gym, pets, receipts and retention) have no
+// in-memory version; test those services with a fake of their own.
+//
+// Golden compares JSON responses with golden files, for the contract tests
+// of the HTTP handlers.
+package familytest
@@ -0,0 +1,665 @@
+package familytest
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+)
+
+var _ expensesdomain.Repository = (*ExpensesRepo)(nil)
+
+// ExpensesRepo is an in-memory expensesdomain.Repository.
+type ExpensesRepo struct {
+	mu    sync.Mutex
+	state expensesState
+}
+
+type expensesState struct {
+	expenses map[string]expensesdomain.Expense
+	// expenseCategories holds the category IDs of each expense.
+	expenseCategories map[string][]string
+	categories        map[string]expensesdomain.Category
+	rules             map[string]expensesdomain.CategoryRule
+	approvals         map[string]expensesdomain.ExpenseApproval
+}
+
+func NewExpensesRepo() *ExpensesRepo {
+	return &ExpensesRepo{state: expensesState{
+		expenses:          make(map[string]expensesdomain.Expense),
+		expenseCategories: make(map[string][]string),
+		categories:        make(map[string]expensesdomain.Category),
+		rules:             make(map[string]expensesdomain.CategoryRule),
+		approvals:         make(map[string]expensesdomain.ExpenseApproval),
+	}}
+}
+
+func (s expensesState) clone() expensesState {
+	return expensesState{
+		expenses:          maps.Clone(s.expenses),
+		expenseCategories: maps.Clone(s.expenseCategories),
+		categories:        maps.Clone(s.categories),
+		rules:             maps.Clone(s.rules),
+		approvals:         maps.Clone(s.approvals),
+	}
+}
+
+func (r *ExpensesRepo) Transaction(_ context.Context, fn func(expensesdomain.Repository) error) error {
+	r.mu.Lock()
+	saved := r.state.clone()
+	r.mu.Unlock()
+
+	if err := fn(r); err != nil {
+		r.mu.Lock()
+		r.state = saved
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (r *ExpensesRepo) ListExpenses(_ context.Context, familyID string, filter expensesdomain.ListFilter) ([]expensesdomain.Expense, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := r.filterExpenses(familyID, filter)
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Date.Equal(items[j].Date) {
+			return items[i].Date.After(items[j].Date)
+		}
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return page(items, filter.Offset, filter.Limit), int64(len(items)), nil
+}
+
+func (r *ExpensesRepo) SummarizeExpenses(_ context.Context, familyID, baseCurrency string, filter expensesdomain.ListFilter) ([]expensesdomain.CurrencyTotal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byCurrency := make(map[string]*expensesdomain.CurrencyTotal)
+	for _, expense := range r.filterExpenses(familyID, filter) {
+		total, ok := byCurrency[expense.Currency]
+		if !ok {
+			total = &expensesdomain.CurrencyTotal{Currency: expense.Currency}
+			byCurrency[expense.Currency] = total
+		}
+		total.Amount += expense.Amount
+		total.Count++
+		if expense.AmountInBase != nil && expense.BaseCurrency != nil && *expense.BaseCurrency == baseCurrency {
+			total.AmountInBase += *expense.AmountInBase
+		} else {
+			total.Unconverted++
+		}
+	}
+
+	totals := make([]expensesdomain.CurrencyTotal, 0, len(byCurrency))
+	for _, total := range byCurrency {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Currency < totals[j].Currency
+	})
+	return totals, nil
+}
+
+func (r *ExpensesRepo) filterExpenses(familyID string, filter expensesdomain.ListFilter) []expensesdomain.Expense {
+	search := strings.TrimSpace(filter.Query)
+	items := make([]expensesdomain.Expense, 0)
+	for _, expense := range r.state.expenses {
+		if expense.FamilyID != familyID {
+			continue
+		}
+		if filter.From != nil && expense.Date.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && expense.Date.After(*filter.To) {
+			continue
+		}
+		if filter.Currency != "" && expense.Currency != filter.Currency {
+			continue
+		}
+		if filter.Archived == expensesdomain.ArchivedExclude && expense.IsArchived ||
+			filter.Archived == expensesdomain.ArchivedOnly && !expense.IsArchived {
+			continue
+		}
+		if search != "" && !containsFold(expense.Title, search) {
+			continue
+		}
+		if len(filter.CategoryIDs) > 0 && !r.hasAnyCategory(expense.ID, filter.CategoryIDs) {
+			continue
+		}
+		items = append(items, expense)
+	}
+	return items
+}
+
+// exists reports whether the family has the expense.
+func (r *ExpensesRepo) exists(familyID, expenseID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expense, ok := r.state.expenses[expenseID]
+	return ok && expense.FamilyID == familyID
+}
+
+// amountInBase returns an expense's amount in the family currency, zero
+// when it is unconverted.
+func (r *ExpensesRepo) amountInBase(expenseID string) (float64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expense, ok := r.state.expenses[expenseID]
+	if !ok {
+		return 0, false
+	}
+	if expense.AmountInBase == nil {
+		return 0, true
+	}
+	return *expense.AmountInBase, true
+}
+
+func (r *ExpensesRepo) hasAnyCategory(expenseID string, categoryIDs []string) bool {
+	for _, categoryID := range r.state.expenseCategories[expenseID] {
+		if contains(categoryIDs, categoryID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ExpensesRepo) GetExpenseByID(_ context.Context, familyID, expenseID string) (*expensesdomain.Expense, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expense, ok := r.state.expenses[expenseID]
+	if !ok || expense.FamilyID != familyID {
+		return nil, expensesdomain.ErrExpenseNotFound
+	}
+	return &expense, nil
+}
+
+func (r *ExpensesRepo) CreateExpense(_ context.Context, expense *expensesdomain.Expense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.expenses[expense.ID]; ok {
+		return ErrDuplicateKey
+	}
+	if expense.Version == 0 {
+		expense.Version = 1
+	}
+	now := time.Now().UTC()
+	if expense.CreatedAt.IsZero() {
+		expense.CreatedAt = now
+	}
+	if expense.UpdatedAt.IsZero() {
+		expense.UpdatedAt = now
+	}
+	r.state.expenses[expense.ID] = *expense
+	return nil
+}
+
+func (r *ExpensesRepo) UpdateExpense(_ context.Context, expense *expensesdomain.Expense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.state.expenses[expense.ID]
+	if !ok || stored.FamilyID != expense.FamilyID || stored.Version != expense.Version {
+		return expensesdomain.ErrVersionConflict
+	}
+	expense.Version++
+	expense.CreatedAt = stored.CreatedAt
+	r.state.expenses[expense.ID] = *expense
+	return nil
+}
+
+func (r *ExpensesRepo) CountExpensesCreatedSince(_ context.Context, familyID string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, expense := range r.state.expenses {
+		if expense.FamilyID == familyID && !expense.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ExpensesRepo) DeleteExpense(_ context.Context, familyID, expenseID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expense, ok := r.state.expenses[expenseID]
+	if !ok || expense.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.state.expenses, expenseID)
+	delete(r.state.expenseCategories, expenseID)
+	return true, nil
+}
+
+func (r *ExpensesRepo) ArchiveExpensesBefore(_ context.Context, familyID string, before, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var archived int64
+	for id, expense := range r.state.expenses {
+		if expense.FamilyID != familyID || expense.IsArchived || !expense.Date.Before(before) {
+			continue
+		}
+		expense.IsArchived = true
+		expense.UpdatedAt = at
+		expense.Version++
+		r.state.expenses[id] = expense
+		archived++
+	}
+	return archived, nil
+}
+
+func (r *ExpensesRepo) ReplaceExpenseCategories(_ context.Context, expenseID string, categoryIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(categoryIDs) == 0 {
+		delete(r.state.expenseCategories, expenseID)
+		return nil
+	}
+	r.state.expenseCategories[expenseID] = append([]string{}, categoryIDs...)
+	return nil
+}
+
+func (r *ExpensesRepo) GetCategoryIDsByExpenseIDs(_ context.Context, expenseIDs []string) (map[string][]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[string][]string, len(expenseIDs))
+	for _, expenseID := range expenseIDs {
+		if categoryIDs, ok := r.state.expenseCategories[expenseID]; ok {
+			result[expenseID] = append([]string{}, categoryIDs...)
+		}
+	}
+	return result, nil
+}
+
+func (r *ExpensesRepo) CountCategoriesByIDs(_ context.Context, familyID string, categoryIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, categoryID := range dedupe(categoryIDs) {
+		if category, ok := r.state.categories[categoryID]; ok && category.FamilyID == familyID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ExpensesRepo) ListCategories(_ context.Context, familyID string) ([]expensesdomain.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]expensesdomain.Category, 0)
+	for _, category := range r.state.categories {
+		if category.FamilyID == familyID {
+			result = append(result, category)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *ExpensesRepo) CreateCategory(_ context.Context, category *expensesdomain.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.categories[category.ID]; ok {
+		return ErrDuplicateKey
+	}
+	if category.Version == 0 {
+		category.Version = 1
+	}
+	if category.CreatedAt.IsZero() {
+		category.CreatedAt = time.Now().UTC()
+	}
+	r.state.categories[category.ID] = *category
+	return nil
+}
+
+func (r *ExpensesRepo) GetCategoryByID(_ context.Context, familyID, categoryID string) (*expensesdomain.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	category, ok := r.state.categories[categoryID]
+	if !ok || category.FamilyID != familyID {
+		return nil, expensesdomain.ErrCategoryNotFound
+	}
+	return &category, nil
+}
+
+func (r *ExpensesRepo) UpdateCategory(_ context.Context, category *expensesdomain.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.state.categories[category.ID]
+	if !ok || stored.FamilyID != category.FamilyID || stored.Version != category.Version {
+		return expensesdomain.ErrVersionConflict
+	}
+	category.Version++
+	category.CreatedAt = stored.CreatedAt
+	r.state.categories[category.ID] = *category
+	return nil
+}
+
+func (r *ExpensesRepo) CountCategoriesByName(_ context.Context, familyID, name, excludeID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, category := range r.state.categories {
+		if category.FamilyID != familyID || (excludeID != "" && category.ID == excludeID) {
+			continue
+		}
+		if strings.EqualFold(category.Name, name) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteCategory also unlinks the category from expenses and drops its
+// rules, as the foreign keys cascade in Postgres.
+func (r *ExpensesRepo) DeleteCategory(_ context.Context, familyID, categoryID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	category, ok := r.state.categories[categoryID]
+	if !ok || category.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.state.categories, categoryID)
+	for expenseID, categoryIDs := range r.state.expenseCategories {
+		kept := make([]string, 0, len(categoryIDs))
+		for _, id := range categoryIDs {
+			if id != categoryID {
+				kept = append(kept, id)
+			}
+		}
+		r.state.expenseCategories[expenseID] = kept
+	}
+	for id, rule := range r.state.rules {
+		if rule.CategoryID == categoryID {
+			delete(r.state.rules, id)
+		}
+	}
+	return true, nil
+}
+
+func (r *ExpensesRepo) CountExpenseCategoriesByCategoryID(_ context.Context, categoryID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, categoryIDs := range r.state.expenseCategories {
+		if contains(categoryIDs, categoryID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ExpensesRepo) ListCategoryUsage(_ context.Context, familyID string) ([]expensesdomain.CategoryUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byCategory := make(map[string]*expensesdomain.CategoryUsage)
+	for expenseID, categoryIDs := range r.state.expenseCategories {
+		expense, ok := r.state.expenses[expenseID]
+		if !ok || expense.FamilyID != familyID {
+			continue
+		}
+		for _, categoryID := range categoryIDs {
+			usage, ok := byCategory[categoryID]
+			if !ok {
+				usage = &expensesdomain.CategoryUsage{CategoryID: categoryID}
+				byCategory[categoryID] = usage
+			}
+			usage.ExpenseCount++
+			if usage.LastUsedAt == nil || expense.CreatedAt.After(*usage.LastUsedAt) {
+				lastUsedAt := expense.CreatedAt
+				usage.LastUsedAt = &lastUsedAt
+			}
+		}
+	}
+
+	result := make([]expensesdomain.CategoryUsage, 0, len(byCategory))
+	for _, usage := range byCategory {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CategoryID < result[j].CategoryID
+	})
+	return result, nil
+}
+
+func (r *ExpensesRepo) ListCategoryRules(_ context.Context, familyID string) ([]expensesdomain.CategoryRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]expensesdomain.CategoryRule, 0)
+	for _, rule := range r.state.rules {
+		if rule.FamilyID == familyID {
+			result = append(result, rule)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority > result[j].Priority
+		}
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *ExpensesRepo) GetCategoryRuleByID(_ context.Context, familyID, ruleID string) (*expensesdomain.CategoryRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.state.rules[ruleID]
+	if !ok || rule.FamilyID != familyID {
+		return nil, expensesdomain.ErrCategoryRuleNotFound
+	}
+	return &rule, nil
+}
+
+func (r *ExpensesRepo) CreateCategoryRule(_ context.Context, rule *expensesdomain.CategoryRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.rules[rule.ID]; ok {
+		return ErrDuplicateKey
+	}
+	now := time.Now().UTC()
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = now
+	}
+	if rule.UpdatedAt.IsZero() {
+		rule.UpdatedAt = now
+	}
+	r.state.rules[rule.ID] = *rule
+	return nil
+}
+
+func (r *ExpensesRepo) UpdateCategoryRule(_ context.Context, rule *expensesdomain.CategoryRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.state.rules[rule.ID]
+	if !ok || stored.FamilyID != rule.FamilyID {
+		return expensesdomain.ErrCategoryRuleNotFound
+	}
+	rule.CreatedAt = stored.CreatedAt
+	r.state.rules[rule.ID] = *rule
+	return nil
+}
+
+func (r *ExpensesRepo) DeleteCategoryRule(_ context.Context, familyID, ruleID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.state.rules[ruleID]
+	if !ok || rule.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.state.rules, ruleID)
+	return true, nil
+}
+
+func (r *ExpensesRepo) ListCategorizedTitles(_ context.Context, familyID string, since time.Time, limit int) ([]expensesdomain.CategorizedTitle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expenses := make([]expensesdomain.Expense, 0)
+	for _, expense := range r.state.expenses {
+		if expense.FamilyID == familyID && !expense.Date.Before(since) {
+			expenses = append(expenses, expense)
+		}
+	}
+	sort.Slice(expenses, func(i, j int) bool {
+		if !expenses[i].Date.Equal(expenses[j].Date) {
+			return expenses[i].Date.After(expenses[j].Date)
+		}
+		return expenses[i].CreatedAt.After(expenses[j].CreatedAt)
+	})
+
+	result := make([]expensesdomain.CategorizedTitle, 0)
+	for _, expense := range expenses {
+		for _, categoryID := range r.state.expenseCategories[expense.ID] {
+			result = append(result, expensesdomain.CategorizedTitle{Title: expense.Title, CategoryID: categoryID})
+		}
+	}
+	return page(result, 0, limit), nil
+}
+
+func (r *ExpensesRepo) CreateExpenseApproval(_ context.Context, approval *expensesdomain.ExpenseApproval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.approvals[approval.ID]; ok {
+		return ErrDuplicateKey
+	}
+	if approval.CreatedAt.IsZero() {
+		approval.CreatedAt = time.Now().UTC()
+	}
+	stored := *approval
+	stored.CategoryIDs = append([]byte{}, approval.CategoryIDs...)
+	r.state.approvals[approval.ID] = stored
+	return nil
+}
+
+func (r *ExpensesRepo) ListExpenseApprovals(_ context.Context, familyID string, filter expensesdomain.ApprovalFilter) ([]expensesdomain.ExpenseApproval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]expensesdomain.ExpenseApproval, 0)
+	for _, approval := range r.state.approvals {
+		if approval.FamilyID != familyID {
+			continue
+		}
+		if filter.Status != "" && approval.Status != filter.Status {
+			continue
+		}
+		if filter.UserID != "" && approval.UserID != filter.UserID {
+			continue
+		}
+		result = append(result, approval)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+func (r *ExpensesRepo) LockExpenseApproval(_ context.Context, familyID, approvalID string) (*expensesdomain.ExpenseApproval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	approval, ok := r.state.approvals[approvalID]
+	if !ok || approval.FamilyID != familyID {
+		return nil, expensesdomain.ErrApprovalNotFound
+	}
+	return &approval, nil
+}
+
+func (r *ExpensesRepo) UpdateExpenseApproval(_ context.Context, approval *expensesdomain.ExpenseApproval) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.state.approvals[approval.ID]
+	if !ok || stored.FamilyID != approval.FamilyID {
+		return nil
+	}
+	stored.Status = approval.Status
+	stored.ExpenseID = approval.ExpenseID
+	stored.DecidedBy = approval.DecidedBy
+	stored.DecidedAt = approval.DecidedAt
+	r.state.approvals[approval.ID] = stored
+	return nil
+}
+
+func (r *ExpensesRepo) ClusterExpenseLocations(_ context.Context, familyID string, filter expensesdomain.GeoFilter) ([]expensesdomain.GeoCluster, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type cell struct{ row, col int }
+	type sums struct {
+		latitude, longitude float64
+		cluster             expensesdomain.GeoCluster
+	}
+	byCell := make(map[cell]*sums)
+	for _, expense := range r.filterExpenses(familyID, expensesdomain.ListFilter{From: filter.From, To: filter.To, CategoryIDs: filter.CategoryIDs}) {
+		if expense.Latitude == nil || expense.Longitude == nil {
+			continue
+		}
+		latitude, longitude := *expense.Latitude, *expense.Longitude
+		if latitude < filter.South || latitude > filter.North || longitude < filter.West || longitude > filter.East {
+			continue
+		}
+		row, col := filter.Cell(latitude, longitude)
+		current, ok := byCell[cell{row, col}]
+		if !ok {
+			current = &sums{cluster: expensesdomain.GeoCluster{ExpenseID: expense.ID}}
+			byCell[cell{row, col}] = current
+		}
+		current.latitude += latitude
+		current.longitude += longitude
+		current.cluster.Count++
+		if expense.AmountInBase != nil {
+			current.cluster.AmountInBase += *expense.AmountInBase
+		}
+		if expense.ID < current.cluster.ExpenseID {
+			current.cluster.ExpenseID = expense.ID
+		}
+	}
+
+	clusters := make([]expensesdomain.GeoCluster, 0, len(byCell))
+	for _, current := range byCell {
+		cluster := current.cluster
+		cluster.Latitude = current.latitude / float64(cluster.Count)
+		cluster.Longitude = current.longitude / float64(cluster.Count)
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
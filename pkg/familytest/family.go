@@ -0,0 +1,383 @@
+package familytest
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	familydomain "family-app-go/internal/domain/family"
+)
+
+var _ familydomain.Repository = (*FamilyRepo)(nil)
+
+// FamilyRepo is an in-memory familydomain.Repository.
+type FamilyRepo struct {
+	mu       sync.Mutex
+	families map[string]familydomain.Family
+	// members are keyed by user ID; a user belongs to one family.
+	members map[string]familydomain.FamilyMember
+	invites map[string]familydomain.Invite
+
+	// users provides emails and avatars for ListMembersWithProfiles.
+	users *UserRepo
+	// labels loses the family's labels in DeleteFamily.
+	labels *LabelsRepo
+}
+
+func NewFamilyRepo() *FamilyRepo {
+	return &FamilyRepo{
+		families: make(map[string]familydomain.Family),
+		members:  make(map[string]familydomain.FamilyMember),
+		invites:  make(map[string]familydomain.Invite),
+	}
+}
+
+func (r *FamilyRepo) Transaction(_ context.Context, fn func(familydomain.Repository) error) error {
+	r.mu.Lock()
+	families, members, invites := maps.Clone(r.families), maps.Clone(r.members), maps.Clone(r.invites)
+	r.mu.Unlock()
+
+	if err := fn(r); err != nil {
+		r.mu.Lock()
+		r.families, r.members, r.invites = families, members, invites
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (r *FamilyRepo) GetFamilyByUser(_ context.Context, userID string) (*familydomain.Family, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[userID]
+	if !ok {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	family, ok := r.families[member.FamilyID]
+	if !ok {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	return &family, nil
+}
+
+func (r *FamilyRepo) GetMemberByUser(_ context.Context, userID string) (*familydomain.FamilyMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[userID]
+	if !ok {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	return &member, nil
+}
+
+func (r *FamilyRepo) GetMemberWithFamilyByUser(_ context.Context, userID string) (*familydomain.FamilyMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[userID]
+	if !ok {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	family, ok := r.families[member.FamilyID]
+	if !ok {
+		return nil, familydomain.ErrFamilyNotFound
+	}
+	member.Family = family
+	return &member, nil
+}
+
+func (r *FamilyRepo) GetMember(_ context.Context, familyID, userID string) (*familydomain.FamilyMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[userID]
+	if !ok || member.FamilyID != familyID {
+		return nil, familydomain.ErrMemberNotFound
+	}
+	return &member, nil
+}
+
+func (r *FamilyRepo) ListMembers(_ context.Context, familyID string) ([]familydomain.FamilyMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.listMembers(familyID), nil
+}
+
+func (r *FamilyRepo) ListMembersWithProfiles(ctx context.Context, familyID string) ([]familydomain.FamilyMemberProfile, error) {
+	r.mu.Lock()
+	members := r.listMembers(familyID)
+	r.mu.Unlock()
+
+	result := make([]familydomain.FamilyMemberProfile, 0, len(members))
+	for _, member := range members {
+		profile := familydomain.FamilyMemberProfile{
+			UserID:        member.UserID,
+			Role:          member.Role,
+			SpendingLimit: member.SpendingLimit,
+			JoinedAt:      member.JoinedAt,
+		}
+		if r.users != nil {
+			if stored, err := r.users.GetProfile(ctx, member.UserID); err == nil {
+				profile.Email = stored.Email
+				profile.AvatarURL = stored.AvatarURL
+			}
+		}
+		result = append(result, profile)
+	}
+	return result, nil
+}
+
+func (r *FamilyRepo) listMembers(familyID string) []familydomain.FamilyMember {
+	result := make([]familydomain.FamilyMember, 0)
+	for _, member := range r.members {
+		if member.FamilyID == familyID {
+			result = append(result, member)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].JoinedAt.Equal(result[j].JoinedAt) {
+			return result[i].JoinedAt.Before(result[j].JoinedAt)
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	return result
+}
+
+func (r *FamilyRepo) CreateFamily(_ context.Context, family *familydomain.Family) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.families[family.ID]; ok {
+		return ErrDuplicateKey
+	}
+	now := time.Now().UTC()
+	if family.CreatedAt.IsZero() {
+		family.CreatedAt = now
+	}
+	family.UpdatedAt = now
+	r.families[family.ID] = *family
+	return nil
+}
+
+func (r *FamilyRepo) AddMember(_ context.Context, member *familydomain.FamilyMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.members[member.UserID]; ok {
+		return ErrDuplicateKey
+	}
+	if member.JoinedAt.IsZero() {
+		member.JoinedAt = time.Now().UTC()
+	}
+	r.members[member.UserID] = *member
+	return nil
+}
+
+func (r *FamilyRepo) UpdateFamilyName(_ context.Context, familyID, name string) error {
+	return r.updateFamily(familyID, func(family *familydomain.Family) { family.Name = name })
+}
+
+func (r *FamilyRepo) UpdateFamilyDefaultCurrency(_ context.Context, familyID, currency string) error {
+	return r.updateFamily(familyID, func(family *familydomain.Family) { family.DefaultCurrency = currency })
+}
+
+func (r *FamilyRepo) UpdateFamilyAllowedCurrencies(_ context.Context, familyID string, currencies []byte) error {
+	return r.updateFamily(familyID, func(family *familydomain.Family) { family.AllowedCurrencies = currencies })
+}
+
+func (r *FamilyRepo) UpdateFamilyOwner(_ context.Context, familyID, ownerID string) error {
+	return r.updateFamily(familyID, func(family *familydomain.Family) { family.OwnerID = ownerID })
+}
+
+// updateFamily ignores unknown families, like an UPDATE matching no rows.
+func (r *FamilyRepo) updateFamily(familyID string, apply func(*familydomain.Family)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	family, ok := r.families[familyID]
+	if !ok {
+		return nil
+	}
+	apply(&family)
+	family.UpdatedAt = time.Now().UTC()
+	r.families[familyID] = family
+	return nil
+}
+
+func (r *FamilyRepo) UpdateMemberRole(_ context.Context, familyID, userID, role string) error {
+	return r.updateMember(familyID, userID, func(member *familydomain.FamilyMember) { member.Role = role })
+}
+
+func (r *FamilyRepo) UpdateMemberSpendingLimit(_ context.Context, familyID, userID string, limit *float64) error {
+	return r.updateMember(familyID, userID, func(member *familydomain.FamilyMember) {
+		member.SpendingLimit = cloneFloat(limit)
+	})
+}
+
+func (r *FamilyRepo) updateMember(familyID, userID string, apply func(*familydomain.FamilyMember)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[userID]
+	if !ok || member.FamilyID != familyID {
+		return nil
+	}
+	apply(&member)
+	r.members[userID] = member
+	return nil
+}
+
+// DeleteFamily also removes its members and invites, as the foreign keys
+// cascade in Postgres, and the labels the family owns.
+func (r *FamilyRepo) DeleteFamily(_ context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.families, familyID)
+	r.deleteMembers(familyID)
+	for id, invite := range r.invites {
+		if invite.FamilyID == familyID {
+			delete(r.invites, id)
+		}
+	}
+	if r.labels != nil {
+		r.labels.deleteByOwner(familyID)
+	}
+	return nil
+}
+
+func (r *FamilyRepo) DeleteMember(_ context.Context, familyID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if member, ok := r.members[userID]; ok && member.FamilyID == familyID {
+		delete(r.members, userID)
+	}
+	return nil
+}
+
+func (r *FamilyRepo) DeleteMembersByFamily(_ context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteMembers(familyID)
+	return nil
+}
+
+func (r *FamilyRepo) deleteMembers(familyID string) {
+	for userID, member := range r.members {
+		if member.FamilyID == familyID {
+			delete(r.members, userID)
+		}
+	}
+}
+
+func (r *FamilyRepo) CountMembers(_ context.Context, familyID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return int64(len(r.listMembers(familyID))), nil
+}
+
+func (r *FamilyRepo) IsUserInFamily(_ context.Context, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.members[userID]
+	return ok, nil
+}
+
+func (r *FamilyRepo) exists(familyID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.families[familyID]
+	return ok
+}
+
+// countMembers counts which of userIDs belong to the family.
+func (r *FamilyRepo) countMembers(familyID string, userIDs []string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, userID := range dedupe(userIDs) {
+		if member, ok := r.members[userID]; ok && member.FamilyID == familyID {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *FamilyRepo) CreateInvite(_ context.Context, invite *familydomain.Invite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.invites {
+		if existing.ID == invite.ID || existing.TokenHash == invite.TokenHash {
+			return ErrDuplicateKey
+		}
+	}
+	if invite.CreatedAt.IsZero() {
+		invite.CreatedAt = time.Now().UTC()
+	}
+	r.invites[invite.ID] = *invite
+	return nil
+}
+
+func (r *FamilyRepo) ListInvites(_ context.Context, familyID string) ([]familydomain.Invite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]familydomain.Invite, 0)
+	for _, invite := range r.invites {
+		if invite.FamilyID == familyID {
+			result = append(result, invite)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+func (r *FamilyRepo) LockInviteByTokenHash(_ context.Context, tokenHash string) (*familydomain.Invite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, invite := range r.invites {
+		if invite.TokenHash == tokenHash {
+			return &invite, nil
+		}
+	}
+	return nil, familydomain.ErrInviteNotFound
+}
+
+func (r *FamilyRepo) IncrementInviteUses(_ context.Context, inviteID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if invite, ok := r.invites[inviteID]; ok {
+		invite.Uses++
+		r.invites[inviteID] = invite
+	}
+	return nil
+}
+
+func (r *FamilyRepo) RevokeInvite(_ context.Context, familyID, inviteID string, revokedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invite, ok := r.invites[inviteID]
+	if !ok || invite.FamilyID != familyID || invite.RevokedAt != nil {
+		return false, nil
+	}
+	invite.RevokedAt = &revokedAt
+	r.invites[inviteID] = invite
+	return true, nil
+}
@@ -0,0 +1,402 @@
+package familytest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	familydomain "family-app-go/internal/domain/family"
+	syncdomain "family-app-go/internal/domain/sync"
+	todosdomain "family-app-go/internal/domain/todos"
+	userdomain "family-app-go/internal/domain/user"
+	"family-app-go/pkg/familytest"
+)
+
+func TestFamilyServiceWithProfiles(t *testing.T) {
+	ctx := context.Background()
+	store := familytest.NewStore()
+	families := familydomain.NewService(store.Family)
+
+	email := "owner@example.com"
+	if err := store.User.UpsertProfile(ctx, &userdomain.Profile{UserID: "user-1", Email: &email}); err != nil {
+		t.Fatalf("upsert profile: %v", err)
+	}
+	family, err := families.CreateFamily(ctx, "user-1", "Smiths")
+	if err != nil {
+		t.Fatalf("create family: %v", err)
+	}
+
+	if _, err := families.CreateFamily(ctx, "user-1", "Again"); !errors.Is(err, familydomain.ErrAlreadyInFamily) {
+		t.Fatalf("expected ErrAlreadyInFamily, got %v", err)
+	}
+	members, err := families.ListMembersWithProfiles(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("list members: %v", err)
+	}
+	if len(members) != 1 || members[0].Role != familydomain.RoleOwner || members[0].Email == nil || *members[0].Email != email {
+		t.Fatalf("unexpected members: %+v", members)
+	}
+
+	if err := families.DeleteFamily(ctx, family.ID); err != nil {
+		t.Fatalf("delete family: %v", err)
+	}
+	if _, err := families.GetFamilyByUser(ctx, "user-1"); !errors.Is(err, familydomain.ErrFamilyNotFound) {
+		t.Fatalf("expected ErrFamilyNotFound after delete, got %v", err)
+	}
+}
+
+func TestSyncBatchAppliesToExpensesAndTodos(t *testing.T) {
+	ctx := context.Background()
+	store := familytest.NewStore()
+	families := familydomain.NewService(store.Family)
+	expenses := expensesdomain.NewService(store.Expenses)
+	todos := todosdomain.NewService(store.Todos)
+	syncs := syncdomain.NewService(store.Sync, expenses, todos)
+
+	family, err := families.CreateFamily(ctx, "user-1", "Smiths")
+	if err != nil {
+		t.Fatalf("create family: %v", err)
+	}
+	category, err := expenses.CreateCategory(ctx, expensesdomain.CreateCategoryInput{FamilyID: family.ID, Name: "Food"})
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	list, err := todos.CreateTodoList(ctx, todosdomain.CreateTodoListInput{FamilyID: family.ID, Title: "Groceries"})
+	if err != nil {
+		t.Fatalf("create list: %v", err)
+	}
+
+	input := syncdomain.BatchInput{
+		FamilyID:       family.ID,
+		BaseCurrency:   family.DefaultCurrency,
+		User:           syncdomain.UserSnapshot{ID: "user-1", Name: "Owner"},
+		IdempotencyKey: "batch-key-123456",
+		Operations: []syncdomain.OperationInput{
+			{
+				OperationID: "11111111-1111-4111-8111-111111111111",
+				Type:        syncdomain.OperationTypeCreateExpense,
+				LocalID:     "expense-local-1",
+				CreateExpense: &syncdomain.CreateExpensePayload{
+					Date:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+					Amount:      12.5,
+					Currency:    family.DefaultCurrency,
+					Title:       "Milk",
+					CategoryIDs: []string{category.ID},
+				},
+			},
+			{
+				OperationID: "22222222-2222-4222-8222-222222222222",
+				Type:        syncdomain.OperationTypeCreateTodo,
+				LocalID:     "todo-local-1",
+				CreateTodo:  &syncdomain.CreateTodoPayload{ListID: list.List.ID, Title: "Buy bread"},
+			},
+			{
+				OperationID:      "33333333-3333-4333-8333-333333333333",
+				Type:             syncdomain.OperationTypeSetTodoCompleted,
+				SetTodoCompleted: &syncdomain.SetTodoCompletedPayload{TodoLocalID: "todo-local-1", IsCompleted: true},
+			},
+		},
+	}
+
+	response, err := syncs.ProcessBatch(ctx, input)
+	if err != nil {
+		t.Fatalf("process batch: %v", err)
+	}
+	if response.Status != syncdomain.BatchStatusSuccess || response.Summary.Applied != 3 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+
+	listed, total, err := expenses.ListExpenses(ctx, family.ID, expensesdomain.ListFilter{CategoryIDs: []string{category.ID}})
+	if err != nil {
+		t.Fatalf("list expenses: %v", err)
+	}
+	if total != 1 || listed[0].Title != "Milk" {
+		t.Fatalf("unexpected expenses: %+v", listed)
+	}
+	lists, _, err := todos.ListTodoLists(ctx, family.ID, todosdomain.ListFilter{}, true, todosdomain.ArchivedAll)
+	if err != nil {
+		t.Fatalf("list todo lists: %v", err)
+	}
+	if len(lists) != 1 || lists[0].Counts.ItemsTotal != 1 || lists[0].Counts.ItemsCompleted != 1 {
+		t.Fatalf("unexpected todo lists: %+v", lists)
+	}
+
+	repeated, err := syncs.ProcessBatch(ctx, input)
+	if err != nil {
+		t.Fatalf("repeat batch: %v", err)
+	}
+	if repeated.SyncID != response.SyncID {
+		t.Fatalf("expected the stored response, got sync id %s", repeated.SyncID)
+	}
+}
+
+func TestAtomicSyncBatchRollsBackExpenses(t *testing.T) {
+	ctx := context.Background()
+	store := familytest.NewStore()
+	expenses := expensesdomain.NewService(store.Expenses)
+	syncs := syncdomain.NewService(store.Sync, expenses, todosdomain.NewService(store.Todos))
+
+	family, err := familydomain.NewService(store.Family).CreateFamily(ctx, "user-1", "Smiths")
+	if err != nil {
+		t.Fatalf("create family: %v", err)
+	}
+	expense := func(operationID string, categoryIDs ...string) syncdomain.OperationInput {
+		return syncdomain.OperationInput{
+			OperationID: operationID,
+			Type:        syncdomain.OperationTypeCreateExpense,
+			LocalID:     "expense-" + operationID,
+			CreateExpense: &syncdomain.CreateExpensePayload{
+				Date:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				Amount:      12.5,
+				Currency:    family.DefaultCurrency,
+				Title:       "Milk",
+				CategoryIDs: categoryIDs,
+			},
+		}
+	}
+
+	response, err := syncs.ProcessBatch(ctx, syncdomain.BatchInput{
+		FamilyID:     family.ID,
+		BaseCurrency: family.DefaultCurrency,
+		User:         syncdomain.UserSnapshot{ID: "user-1", Name: "Owner"},
+		Atomic:       true,
+		Operations: []syncdomain.OperationInput{
+			expense("44444444-4444-4444-8444-444444444441"),
+			expense("44444444-4444-4444-8444-444444444442", "55555555-5555-4555-8555-555555555555"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("process batch: %v", err)
+	}
+	if response.Status != syncdomain.BatchStatusFailed || response.Results[1].Error.Code != syncdomain.ErrorCodeCategoryNotFound {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+
+	_, total, err := expenses.ListExpenses(ctx, family.ID, expensesdomain.ListFilter{})
+	if err != nil {
+		t.Fatalf("list expenses: %v", err)
+	}
+	if total != 0 {
+		t.Fatalf("expected the first expense rolled back, got %d expenses", total)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	ctx := context.Background()
+	repo := familytest.NewExpensesRepo()
+	failure := errors.New("boom")
+
+	err := repo.Transaction(ctx, func(tx expensesdomain.Repository) error {
+		if err := tx.CreateCategory(ctx, &expensesdomain.Category{ID: "category-1", FamilyID: "family-1", Name: "Food"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected transaction error, got %v", err)
+	}
+	if _, err := repo.GetCategoryByID(ctx, "family-1", "category-1"); !errors.Is(err, expensesdomain.ErrCategoryNotFound) {
+		t.Fatalf("expected category to be rolled back, got %v", err)
+	}
+}
+
+func TestUpdateRejectsStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := familytest.NewTodosRepo()
+
+	list := &todosdomain.TodoList{ID: "list-1", FamilyID: "family-1", Title: "Chores"}
+	if err := repo.CreateTodoList(ctx, list); err != nil {
+		t.Fatalf("create list: %v", err)
+	}
+	stale := *list
+	list.Title = "House chores"
+	if err := repo.UpdateTodoList(ctx, list); err != nil {
+		t.Fatalf("update list: %v", err)
+	}
+	stale.Title = "Garden"
+	if err := repo.UpdateTodoList(ctx, &stale); !errors.Is(err, todosdomain.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+}
+
+func TestTodoListPlannedVersusActualSpend(t *testing.T) {
+	ctx := context.Background()
+	store := familytest.NewStore()
+	families := familydomain.NewService(store.Family)
+	expenses := expensesdomain.NewService(store.Expenses)
+	todos := todosdomain.NewService(store.Todos)
+
+	family, err := families.CreateFamily(ctx, "user-1", "Smiths")
+	if err != nil {
+		t.Fatalf("create family: %v", err)
+	}
+	list, err := todos.CreateTodoList(ctx, todosdomain.CreateTodoListInput{FamilyID: family.ID, Title: "Groceries"})
+	if err != nil {
+		t.Fatalf("create list: %v", err)
+	}
+	owner := &todosdomain.UserSnapshot{ID: "user-1", Name: "Owner"}
+	completed := true
+	var itemIDs []string
+	for _, price := range []float64{3.5, 2.004} {
+		item, err := todos.CreateTodoItem(ctx, family.ID, todosdomain.CreateTodoItemInput{ListID: list.List.ID, Title: "Item", EstimatedPrice: &price})
+		if err != nil {
+			t.Fatalf("create item: %v", err)
+		}
+		itemIDs = append(itemIDs, item.ID)
+	}
+	expense, err := expenses.CreateExpense(ctx, expensesdomain.CreateExpenseInput{
+		FamilyID:     family.ID,
+		UserID:       "user-1",
+		Date:         time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Amount:       6,
+		Currency:     family.DefaultCurrency,
+		BaseCurrency: family.DefaultCurrency,
+		Title:        "Supermarket",
+	})
+	if err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+
+	link := todosdomain.OptionalNullableString{Set: true, Value: &expense.ID}
+	_, err = todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{ID: itemIDs[0], FamilyID: family.ID, ExpenseID: link})
+	if !errors.Is(err, todosdomain.ErrTodoItemNotCompleted) {
+		t.Fatalf("expected ErrTodoItemNotCompleted for an open item, got %v", err)
+	}
+	missing := "00000000-0000-4000-8000-000000000000"
+	_, err = todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{ID: itemIDs[0], FamilyID: family.ID, IsCompleted: &completed, CompletedBy: owner, ExpenseID: todosdomain.OptionalNullableString{Set: true, Value: &missing}})
+	if !errors.Is(err, todosdomain.ErrExpenseNotFound) {
+		t.Fatalf("expected ErrExpenseNotFound, got %v", err)
+	}
+	for _, itemID := range itemIDs {
+		if _, err := todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{ID: itemID, FamilyID: family.ID, IsCompleted: &completed, CompletedBy: owner, ExpenseID: link}); err != nil {
+			t.Fatalf("link item: %v", err)
+		}
+	}
+
+	lists, _, err := todos.ListTodoLists(ctx, family.ID, todosdomain.ListFilter{}, false, todosdomain.ArchivedAll)
+	if err != nil {
+		t.Fatalf("list todo lists: %v", err)
+	}
+	counts := lists[0].Counts
+	if counts.PlannedTotal != 5.5 || counts.PlannedPurchased != 5.5 || counts.ItemsLinked != 2 || counts.ActualTotal != 6 {
+		t.Fatalf("expected 5.5 planned and one 6.00 expense counted once, got %+v", counts)
+	}
+
+	reopened := false
+	item, err := todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{ID: itemIDs[1], FamilyID: family.ID, IsCompleted: &reopened})
+	if err != nil {
+		t.Fatalf("reopen item: %v", err)
+	}
+	if item.ExpenseID != nil || item.EstimatedPrice == nil || *item.EstimatedPrice != 2 {
+		t.Fatalf("expected a reopened item to keep its price and lose its expense, got %+v", item)
+	}
+}
+
+func TestItemForExpenseNeedsCompletedUnlinkedItem(t *testing.T) {
+	ctx := context.Background()
+	store := familytest.NewStore()
+	families := familydomain.NewService(store.Family)
+	expenses := expensesdomain.NewService(store.Expenses)
+	todos := todosdomain.NewService(store.Todos)
+
+	family, err := families.CreateFamily(ctx, "user-1", "Smiths")
+	if err != nil {
+		t.Fatalf("create family: %v", err)
+	}
+	list, err := todos.CreateTodoList(ctx, todosdomain.CreateTodoListInput{FamilyID: family.ID, Title: "Groceries"})
+	if err != nil {
+		t.Fatalf("create list: %v", err)
+	}
+	item, err := todos.CreateTodoItem(ctx, family.ID, todosdomain.CreateTodoItemInput{ListID: list.List.ID, Title: "Bread"})
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	if _, err := todos.ItemForExpense(ctx, family.ID, item.ID, "user-1"); !errors.Is(err, todosdomain.ErrTodoItemNotCompleted) {
+		t.Fatalf("expected ErrTodoItemNotCompleted, got %v", err)
+	}
+
+	completed := true
+	owner := &todosdomain.UserSnapshot{ID: "user-1", Name: "Owner"}
+	if _, err := todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{ID: item.ID, FamilyID: family.ID, IsCompleted: &completed, CompletedBy: owner}); err != nil {
+		t.Fatalf("complete item: %v", err)
+	}
+	if _, err := todos.ItemForExpense(ctx, family.ID, item.ID, "user-1"); err != nil {
+		t.Fatalf("expected a completed item to be accepted, got %v", err)
+	}
+
+	expense, err := expenses.CreateExpense(ctx, expensesdomain.CreateExpenseInput{
+		FamilyID:     family.ID,
+		UserID:       "user-1",
+		Date:         time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Amount:       2,
+		Currency:     family.DefaultCurrency,
+		BaseCurrency: family.DefaultCurrency,
+		Title:        "Bread",
+	})
+	if err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	link := todosdomain.OptionalNullableString{Set: true, Value: &expense.ID}
+	if _, err := todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{ID: item.ID, FamilyID: family.ID, ExpenseID: link}); err != nil {
+		t.Fatalf("link item: %v", err)
+	}
+	if _, err := todos.ItemForExpense(ctx, family.ID, item.ID, "user-1"); !errors.Is(err, todosdomain.ErrTodoItemLinked) {
+		t.Fatalf("expected ErrTodoItemLinked, got %v", err)
+	}
+}
+
+func TestProfileSyncPatchesCompletedBySnapshots(t *testing.T) {
+	ctx := context.Background()
+	store := familytest.NewStore()
+	families := familydomain.NewService(store.Family)
+	todos := todosdomain.NewService(store.Todos)
+	users := userdomain.NewService(store.User)
+
+	if _, err := users.UpsertProfile(ctx, "user-1", "owner@example.com", "https://provider.example.com/old.png"); err != nil {
+		t.Fatalf("upsert profile: %v", err)
+	}
+	family, err := families.CreateFamily(ctx, "user-1", "Smiths")
+	if err != nil {
+		t.Fatalf("create family: %v", err)
+	}
+	list, err := todos.CreateTodoList(ctx, todosdomain.CreateTodoListInput{FamilyID: family.ID, Title: "Groceries"})
+	if err != nil {
+		t.Fatalf("create list: %v", err)
+	}
+	item, err := todos.CreateTodoItem(ctx, family.ID, todosdomain.CreateTodoItemInput{ListID: list.List.ID, Title: "Milk"})
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	completed := true
+	owner := &todosdomain.UserSnapshot{ID: "user-1", Name: "Owner", Email: "owner@example.com", AvatarURL: "https://provider.example.com/old.png"}
+	if _, err := todos.UpdateTodoItem(ctx, todosdomain.UpdateTodoItemInput{ID: item.ID, FamilyID: family.ID, IsCompleted: &completed, CompletedBy: owner}); err != nil {
+		t.Fatalf("complete item: %v", err)
+	}
+
+	if _, err := users.UpsertProfile(ctx, "user-1", "owner@example.com", "https://provider.example.com/new.png"); err != nil {
+		t.Fatalf("upsert changed profile: %v", err)
+	}
+	result, err := users.SyncProfiles(ctx)
+	if err != nil {
+		t.Fatalf("sync profiles: %v", err)
+	}
+	if result.Drained != 1 || result.SnapshotsPatched != 1 {
+		t.Fatalf("expected one snapshot patched, got %+v", result)
+	}
+
+	patched, err := todos.GetTodoItem(ctx, family.ID, item.ID, "user-1")
+	if err != nil {
+		t.Fatalf("get item: %v", err)
+	}
+	if patched.CompletedByAvatarURL == nil || *patched.CompletedByAvatarURL != "https://provider.example.com/new.png" {
+		t.Fatalf("expected the new avatar, got %v", patched.CompletedByAvatarURL)
+	}
+	if patched.CompletedByName == nil || *patched.CompletedByName != "Owner" {
+		t.Fatalf("expected the unknown name kept, got %v", patched.CompletedByName)
+	}
+	if result, err := users.SyncProfiles(ctx); err != nil || result.Drained != 0 {
+		t.Fatalf("expected an empty outbox, got %+v, %v", result, err)
+	}
+}
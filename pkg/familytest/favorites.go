@@ -0,0 +1,103 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	favoritesdomain "family-app-go/internal/domain/favorites"
+)
+
+var _ favoritesdomain.Repository = (*FavoritesRepo)(nil)
+
+// FavoritesRepo is an in-memory favoritesdomain.Repository.
+type FavoritesRepo struct {
+	mu        sync.Mutex
+	favorites map[favoriteKey]favoritesdomain.Favorite
+}
+
+type favoriteKey struct {
+	userID     string
+	entityType favoritesdomain.EntityType
+	entityID   string
+}
+
+func NewFavoritesRepo() *FavoritesRepo {
+	return &FavoritesRepo{favorites: make(map[favoriteKey]favoritesdomain.Favorite)}
+}
+
+func (r *FavoritesRepo) AddFavorite(_ context.Context, favorite *favoritesdomain.Favorite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := favoriteKey{userID: favorite.UserID, entityType: favorite.EntityType, entityID: favorite.EntityID}
+	if _, ok := r.favorites[key]; ok {
+		return nil
+	}
+	if favorite.CreatedAt.IsZero() {
+		favorite.CreatedAt = time.Now().UTC()
+	}
+	r.favorites[key] = *favorite
+	return nil
+}
+
+func (r *FavoritesRepo) RemoveFavorite(_ context.Context, userID string, entityType favoritesdomain.EntityType, entityID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.favorites, favoriteKey{userID: userID, entityType: entityType, entityID: entityID})
+	return nil
+}
+
+func (r *FavoritesRepo) ListFavorites(_ context.Context, userID string, entityType favoritesdomain.EntityType) ([]favoritesdomain.Favorite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]favoritesdomain.Favorite, 0)
+	for key, favorite := range r.favorites {
+		if key.userID == userID && key.entityType == entityType {
+			result = append(result, favorite)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].EntityID < result[j].EntityID
+	})
+	return result, nil
+}
+
+func (r *FavoritesRepo) CountFavorites(_ context.Context, userID string, entityType favoritesdomain.EntityType) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for key := range r.favorites {
+		if key.userID == userID && key.entityType == entityType {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *FavoritesRepo) DeleteByEntity(_ context.Context, entityType favoritesdomain.EntityType, entityID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.favorites {
+		if key.entityType == entityType && key.entityID == entityID {
+			delete(r.favorites, key)
+		}
+	}
+	return nil
+}
+
+func (r *FavoritesRepo) isFavorite(userID string, entityType favoritesdomain.EntityType, entityID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.favorites[favoriteKey{userID: userID, entityType: entityType, entityID: entityID}]
+	return ok
+}
@@ -0,0 +1,96 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	featureflagsdomain "family-app-go/internal/domain/featureflags"
+)
+
+var _ featureflagsdomain.Repository = (*FeatureFlagsRepo)(nil)
+
+// FeatureFlagsRepo is an in-memory featureflagsdomain.Repository.
+type FeatureFlagsRepo struct {
+	mu        sync.Mutex
+	flags     map[string]featureflagsdomain.Flag
+	overrides map[overrideKey]featureflagsdomain.FamilyOverride
+
+	// family rejects overrides for unknown families; without it any
+	// family is accepted.
+	family *FamilyRepo
+}
+
+type overrideKey struct {
+	flagKey  string
+	familyID string
+}
+
+func NewFeatureFlagsRepo() *FeatureFlagsRepo {
+	return &FeatureFlagsRepo{
+		flags:     make(map[string]featureflagsdomain.Flag),
+		overrides: make(map[overrideKey]featureflagsdomain.FamilyOverride),
+	}
+}
+
+func (r *FeatureFlagsRepo) ListFlags(context.Context) ([]featureflagsdomain.Flag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	flags := make([]featureflagsdomain.Flag, 0, len(r.flags))
+	for _, flag := range r.flags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Key < flags[j].Key
+	})
+	return flags, nil
+}
+
+func (r *FeatureFlagsRepo) ListOverrides(context.Context) ([]featureflagsdomain.FamilyOverride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	overrides := make([]featureflagsdomain.FamilyOverride, 0, len(r.overrides))
+	for _, override := range r.overrides {
+		overrides = append(overrides, override)
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		if overrides[i].FlagKey != overrides[j].FlagKey {
+			return overrides[i].FlagKey < overrides[j].FlagKey
+		}
+		return overrides[i].FamilyID < overrides[j].FamilyID
+	})
+	return overrides, nil
+}
+
+func (r *FeatureFlagsRepo) UpsertFlag(_ context.Context, flag *featureflagsdomain.Flag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	flag.UpdatedAt = time.Now().UTC()
+	r.flags[flag.Key] = *flag
+	return nil
+}
+
+func (r *FeatureFlagsRepo) UpsertOverride(_ context.Context, override *featureflagsdomain.FamilyOverride) error {
+	if r.family != nil && !r.family.exists(override.FamilyID) {
+		return featureflagsdomain.ErrFamilyNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	override.UpdatedAt = time.Now().UTC()
+	r.overrides[overrideKey{flagKey: override.FlagKey, familyID: override.FamilyID}] = *override
+	return nil
+}
+
+func (r *FeatureFlagsRepo) DeleteOverride(_ context.Context, key, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.overrides, overrideKey{flagKey: key, familyID: familyID})
+	return nil
+}
@@ -0,0 +1,46 @@
+package familytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGoldenEnv names the environment variable that makes Golden rewrite
+// the golden files instead of comparing against them.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// Golden compares a JSON response body with testdata/<name>.golden in the
+// package under test. Both are compared indented, so a renamed, removed or
+// added field shows up as a line in the failure. Run the tests with
+// UPDATE_GOLDEN=1 to accept a deliberate change to the contract.
+func Golden(t testing.TB, name string, body []byte) {
+	t.Helper()
+
+	var got bytes.Buffer
+	if err := json.Indent(&got, body, "", "  "); err != nil {
+		t.Fatalf("golden %s: response is not JSON: %v: %s", name, err, body)
+	}
+	got.WriteByte('\n')
+
+	path := filepath.Join("testdata", name+".golden")
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden %s: %v; run with %s=1 to create it", name, err, UpdateGoldenEnv)
+	}
+	if !bytes.Equal(want, got.Bytes()) {
+		t.Errorf("golden %s: response differs from %s; if the change is intended, run with %s=1\n--- want\n%s--- got\n%s", name, path, UpdateGoldenEnv, want, got.Bytes())
+	}
+}
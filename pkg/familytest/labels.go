@@ -0,0 +1,140 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	labelsdomain "family-app-go/internal/domain/labels"
+)
+
+var _ labelsdomain.Repository = (*LabelsRepo)(nil)
+
+// LabelsRepo is an in-memory labelsdomain.Repository.
+type LabelsRepo struct {
+	mu     sync.Mutex
+	labels map[labelKey][]labelsdomain.Label
+}
+
+type labelKey struct {
+	entityType labelsdomain.EntityType
+	entityID   string
+}
+
+func NewLabelsRepo() *LabelsRepo {
+	return &LabelsRepo{labels: make(map[labelKey][]labelsdomain.Label)}
+}
+
+func (r *LabelsRepo) ReplaceLabels(_ context.Context, entityType labelsdomain.EntityType, entityID, ownerID string, labels []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := labelKey{entityType: entityType, entityID: entityID}
+	if len(labels) == 0 {
+		delete(r.labels, key)
+		return nil
+	}
+	if len(dedupe(labels)) != len(labels) {
+		return ErrDuplicateKey
+	}
+	now := time.Now().UTC()
+	rows := make([]labelsdomain.Label, 0, len(labels))
+	for _, label := range labels {
+		rows = append(rows, labelsdomain.Label{
+			EntityType: entityType,
+			EntityID:   entityID,
+			Label:      label,
+			OwnerID:    ownerID,
+			CreatedAt:  now,
+		})
+	}
+	r.labels[key] = rows
+	return nil
+}
+
+func (r *LabelsRepo) ListByEntities(_ context.Context, entityType labelsdomain.EntityType, entityIDs []string) (map[string][]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[string][]string)
+	for _, entityID := range entityIDs {
+		if labels := r.entityLabels(entityType, entityID); len(labels) > 0 {
+			result[entityID] = labels
+		}
+	}
+	return result, nil
+}
+
+// hasAny reports whether the record carries one of labels.
+func (r *LabelsRepo) hasAny(entityType labelsdomain.EntityType, entityID string, labels []string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, label := range r.entityLabels(entityType, entityID) {
+		if contains(labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *LabelsRepo) entityLabels(entityType labelsdomain.EntityType, entityID string) []string {
+	rows := r.labels[labelKey{entityType: entityType, entityID: entityID}]
+	labels := make([]string, 0, len(rows))
+	for _, row := range rows {
+		labels = append(labels, row.Label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+func (r *LabelsRepo) ListUsage(_ context.Context, entityType labelsdomain.EntityType, ownerID string) ([]labelsdomain.Usage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[string]int64)
+	for key, rows := range r.labels {
+		if key.entityType != entityType {
+			continue
+		}
+		for _, row := range rows {
+			if row.OwnerID == ownerID {
+				counts[row.Label]++
+			}
+		}
+	}
+
+	usage := make([]labelsdomain.Usage, 0, len(counts))
+	for label, count := range counts {
+		usage = append(usage, labelsdomain.Usage{Label: label, Count: count})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Count != usage[j].Count {
+			return usage[i].Count > usage[j].Count
+		}
+		return usage[i].Label < usage[j].Label
+	})
+	return usage, nil
+}
+
+func (r *LabelsRepo) DeleteByEntity(_ context.Context, entityType labelsdomain.EntityType, entityID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.labels, labelKey{entityType: entityType, entityID: entityID})
+	return nil
+}
+
+// deleteByOwner drops every label of the owner, like DeleteFamily does in
+// Postgres.
+func (r *LabelsRepo) deleteByOwner(ownerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, rows := range r.labels {
+		if len(rows) > 0 && rows[0].OwnerID == ownerID {
+			delete(r.labels, key)
+		}
+	}
+}
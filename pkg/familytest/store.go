@@ -0,0 +1,108 @@
+package familytest
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrDuplicateKey is returned where Postgres would reject a row violating a
+// primary key or unique constraint.
+var ErrDuplicateKey = errors.New("familytest: duplicate key")
+
+// Store holds one repository per domain, wired together so lookups across
+// them behave like the joins they replace.
+type Store struct {
+	Family       *FamilyRepo
+	User         *UserRepo
+	Expenses     *ExpensesRepo
+	Todos        *TodosRepo
+	Sync         *SyncRepo
+	Labels       *LabelsRepo
+	Favorites    *FavoritesRepo
+	Activity     *ActivityRepo
+	Audit        *AuditRepo
+	Auth         *AuthRepo
+	APIKeys      *APIKeysRepo
+	FeatureFlags *FeatureFlagsRepo
+	Views        *ViewsRepo
+	Wishlist     *WishlistRepo
+}
+
+func NewStore() *Store {
+	store := &Store{
+		Family:       NewFamilyRepo(),
+		User:         NewUserRepo(),
+		Expenses:     NewExpensesRepo(),
+		Todos:        NewTodosRepo(),
+		Sync:         NewSyncRepo(),
+		Labels:       NewLabelsRepo(),
+		Favorites:    NewFavoritesRepo(),
+		Activity:     NewActivityRepo(),
+		Audit:        NewAuditRepo(),
+		Auth:         NewAuthRepo(),
+		APIKeys:      NewAPIKeysRepo(),
+		FeatureFlags: NewFeatureFlagsRepo(),
+		Views:        NewViewsRepo(),
+		Wishlist:     NewWishlistRepo(),
+	}
+	store.Family.users = store.User
+	store.User.todos = store.Todos
+	store.Family.labels = store.Labels
+	store.Todos.family = store.Family
+	store.Todos.labels = store.Labels
+	store.Todos.favorites = store.Favorites
+	store.Todos.expenses = store.Expenses
+	store.FeatureFlags.family = store.Family
+	store.Sync.expenses = store.Expenses
+	store.Sync.todos = store.Todos
+	return store
+}
+
+func cloneFloat(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold matches like ILIKE '%search%'.
+func containsFold(value, search string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(search))
+}
+
+// page applies offset and limit, where zero means no limit.
+func page[T any](items []T, offset, limit int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return []T{}
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,336 @@
+package familytest
+
+import (
+	"context"
+	"maps"
+	stdsync "sync"
+	"time"
+
+	expensesdomain "family-app-go/internal/domain/expenses"
+	syncdomain "family-app-go/internal/domain/sync"
+	todosdomain "family-app-go/internal/domain/todos"
+)
+
+var _ syncdomain.Repository = (*SyncRepo)(nil)
+
+// SyncRepo is an in-memory syncdomain.Repository.
+type SyncRepo struct {
+	mu         stdsync.Mutex
+	batches    map[string]syncdomain.BatchRecord
+	operations map[string]syncdomain.OperationRecord
+	events     []syncdomain.ChangeEvent
+	heads      map[string]int64
+
+	// expenses and todos join Transaction; NewStore sets them.
+	expenses *ExpensesRepo
+	todos    *TodosRepo
+}
+
+func NewSyncRepo() *SyncRepo {
+	return &SyncRepo{
+		batches:    make(map[string]syncdomain.BatchRecord),
+		operations: make(map[string]syncdomain.OperationRecord),
+		heads:      make(map[string]int64),
+	}
+}
+
+// RecordChange appends a change event as the database triggers do; the
+// in-memory repositories do not record changes themselves.
+func (r *SyncRepo) RecordChange(familyID string, entity syncdomain.Entity, entityID string, op syncdomain.ChangeOp) syncdomain.ChangeEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.heads[familyID]++
+	event := syncdomain.ChangeEvent{
+		FamilyID:   familyID,
+		Seq:        r.heads[familyID],
+		Entity:     entity,
+		EntityID:   entityID,
+		Op:         op,
+		OccurredAt: time.Now().UTC(),
+	}
+	r.events = append(r.events, event)
+	return event
+}
+
+// Transaction rolls back the sync records and, when the repository belongs
+// to a Store, its expenses and todos when fn fails. Like the other in-memory
+// transactions it does not isolate concurrent callers.
+func (r *SyncRepo) Transaction(ctx context.Context, fn func(syncdomain.Repository, expensesdomain.Repository, todosdomain.Repository) error) error {
+	r.mu.Lock()
+	batches := maps.Clone(r.batches)
+	operations := maps.Clone(r.operations)
+	r.mu.Unlock()
+
+	rollback := func() {
+		r.mu.Lock()
+		r.batches, r.operations = batches, operations
+		r.mu.Unlock()
+	}
+	if r.expenses == nil || r.todos == nil {
+		if err := fn(r, nil, nil); err != nil {
+			rollback()
+			return err
+		}
+		return nil
+	}
+
+	return r.expenses.Transaction(ctx, func(expensesTx expensesdomain.Repository) error {
+		return r.todos.Transaction(ctx, func(todosTx todosdomain.Repository) error {
+			if err := fn(r, expensesTx, todosTx); err != nil {
+				rollback()
+				return err
+			}
+			return nil
+		})
+	})
+}
+
+// BeginBatch stores the batch unless the user already sent one with the same
+// idempotency key, which it returns instead.
+func (r *SyncRepo) BeginBatch(_ context.Context, batch *syncdomain.BatchRecord) (bool, *syncdomain.BatchRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.batches[batch.ID]; ok {
+		return false, nil, nil
+	}
+	if batch.IdempotencyKey != nil {
+		for _, existing := range r.batches {
+			if existing.FamilyID == batch.FamilyID && existing.UserID == batch.UserID &&
+				existing.IdempotencyKey != nil && *existing.IdempotencyKey == *batch.IdempotencyKey {
+				existing.ResponseJSON = append([]byte(nil), existing.ResponseJSON...)
+				return false, &existing, nil
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	batch.CreatedAt, batch.UpdatedAt = now, now
+	stored := *batch
+	stored.ResponseJSON = append([]byte(nil), batch.ResponseJSON...)
+	r.batches[batch.ID] = stored
+	return true, nil, nil
+}
+
+func (r *SyncRepo) CompleteBatch(_ context.Context, batchID string, status syncdomain.BatchState, responseJSON []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	batch, ok := r.batches[batchID]
+	if !ok {
+		return nil
+	}
+	batch.Status = status
+	batch.ResponseJSON = append([]byte(nil), responseJSON...)
+	batch.UpdatedAt = time.Now().UTC()
+	r.batches[batchID] = batch
+	return nil
+}
+
+// ReserveOperation stores the operation unless the user already sent one
+// with the same operation ID, which it returns instead.
+func (r *SyncRepo) ReserveOperation(_ context.Context, operation *syncdomain.OperationRecord) (bool, *syncdomain.OperationRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.operations {
+		if existing.FamilyID == operation.FamilyID && existing.UserID == operation.UserID &&
+			existing.OperationID == operation.OperationID {
+			return false, &existing, nil
+		}
+	}
+	if _, ok := r.operations[operation.ID]; ok {
+		return false, nil, ErrDuplicateKey
+	}
+
+	now := time.Now().UTC()
+	operation.CreatedAt, operation.UpdatedAt = now, now
+	r.operations[operation.ID] = *operation
+	return true, nil, nil
+}
+
+func (r *SyncRepo) UpdateOperation(_ context.Context, operation *syncdomain.OperationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.operations[operation.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status = operation.Status
+	stored.LocalID = operation.LocalID
+	stored.Entity = operation.Entity
+	stored.ServerID = operation.ServerID
+	stored.ErrorCode = operation.ErrorCode
+	stored.ErrorMessage = operation.ErrorMessage
+	stored.Retryable = operation.Retryable
+	stored.UpdatedAt = time.Now().UTC()
+	r.operations[operation.ID] = stored
+	return nil
+}
+
+func (r *SyncRepo) FindOperation(_ context.Context, familyID, userID, operationID string) (*syncdomain.OperationRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.operations {
+		if existing.FamilyID == familyID && existing.UserID == userID && existing.OperationID == operationID {
+			return &existing, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *SyncRepo) CountOperationsSince(_ context.Context, familyID string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, operation := range r.operations {
+		if operation.FamilyID == familyID && !operation.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// FindServerIDByLocalID returns the server ID of the latest applied
+// operation that created the entity with localID.
+func (r *SyncRepo) FindServerIDByLocalID(_ context.Context, familyID, userID string, entity syncdomain.Entity, localID string) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var latest *syncdomain.OperationRecord
+	for _, operation := range r.operations {
+		if operation.FamilyID != familyID || operation.UserID != userID ||
+			operation.Status != syncdomain.OperationStateApplied ||
+			operation.Entity == nil || *operation.Entity != entity ||
+			operation.LocalID == nil || *operation.LocalID != localID ||
+			operation.ServerID == nil {
+			continue
+		}
+		if latest == nil || operation.CreatedAt.After(latest.CreatedAt) {
+			latest = &operation
+		}
+	}
+	if latest == nil {
+		return "", false, nil
+	}
+	return *latest.ServerID, true, nil
+}
+
+func (r *SyncRepo) FindServerIDsByLocalIDs(ctx context.Context, familyID, userID string, entity syncdomain.Entity, localIDs []string) (map[string]string, error) {
+	result := make(map[string]string, len(localIDs))
+	for _, localID := range localIDs {
+		serverID, found, err := r.FindServerIDByLocalID(ctx, familyID, userID, entity, localID)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			result[localID] = serverID
+		}
+	}
+	return result, nil
+}
+
+func (r *SyncRepo) DeleteOperationsBefore(_ context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, operation := range r.operations {
+		if deleted >= int64(limit) {
+			break
+		}
+		if operation.CreatedAt.Before(cutoff) {
+			delete(r.operations, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *SyncRepo) DeleteBatchesBefore(_ context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, batch := range r.batches {
+		if deleted >= int64(limit) {
+			break
+		}
+		if batch.CreatedAt.Before(cutoff) {
+			delete(r.batches, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *SyncRepo) ChangeFeedBounds(_ context.Context, familyID string) (syncdomain.ChangeFeedBounds, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bounds := syncdomain.ChangeFeedBounds{LastSeq: r.heads[familyID]}
+	for _, event := range r.events {
+		if event.FamilyID == familyID && (bounds.OldestSeq == 0 || event.Seq < bounds.OldestSeq) {
+			bounds.OldestSeq = event.Seq
+		}
+	}
+	return bounds, nil
+}
+
+func (r *SyncRepo) ListChangeEvents(_ context.Context, familyID string, afterSeq int64, limit int) ([]syncdomain.ChangeEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []syncdomain.ChangeEvent
+	for _, event := range r.events {
+		if len(events) >= limit {
+			break
+		}
+		if event.FamilyID == familyID && event.Seq > afterSeq {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *SyncRepo) DeleteChangeEventsBefore(_ context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if deleted < int64(limit) && event.OccurredAt.Before(cutoff) {
+			deleted++
+			continue
+		}
+		kept = append(kept, event)
+	}
+	r.events = kept
+	return deleted, nil
+}
+
+// StreamSnapshot reports the family's change feed head and no records: the
+// snapshot queries only exist for Postgres.
+func (r *SyncRepo) StreamSnapshot(_ context.Context, familyID, _ string, begin func(lastSeq int64) error, _ func(syncdomain.SnapshotRecord) error) error {
+	r.mu.Lock()
+	lastSeq := r.heads[familyID]
+	r.mu.Unlock()
+
+	return begin(lastSeq)
+}
+
+// StorageStats counts the stored rows; the in-memory tables have no size.
+func (r *SyncRepo) StorageStats(context.Context) (syncdomain.StorageStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return syncdomain.StorageStats{
+		OperationRows: int64(len(r.operations)),
+		BatchRows:     int64(len(r.batches)),
+	}, nil
+}
@@ -0,0 +1,631 @@
+package familytest
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	favoritesdomain "family-app-go/internal/domain/favorites"
+	labelsdomain "family-app-go/internal/domain/labels"
+	todosdomain "family-app-go/internal/domain/todos"
+	userdomain "family-app-go/internal/domain/user"
+)
+
+var _ todosdomain.Repository = (*TodosRepo)(nil)
+
+// TodosRepo is an in-memory todosdomain.Repository. Deleted lists and items
+// are dropped instead of soft deleted, which reads the same.
+type TodosRepo struct {
+	mu    sync.Mutex
+	state todosState
+
+	// family answers CountFamilyMembers; without it nobody is a member.
+	family *FamilyRepo
+	// labels filters ListTodoItems by label; without it nothing matches.
+	labels *LabelsRepo
+	// favorites orders ListTodoLists for FavoritesOf.
+	favorites *FavoritesRepo
+	// expenses answers ExpenseExists and prices linked purchases; without it
+	// no expense exists.
+	expenses *ExpensesRepo
+}
+
+type todosState struct {
+	lists  map[string]todosdomain.TodoList
+	items  map[string]todosdomain.TodoItem
+	shares map[string]todosdomain.ListShare
+	// viewers holds the viewer IDs of each private list.
+	viewers map[string][]string
+}
+
+func NewTodosRepo() *TodosRepo {
+	return &TodosRepo{state: todosState{
+		lists:   make(map[string]todosdomain.TodoList),
+		items:   make(map[string]todosdomain.TodoItem),
+		shares:  make(map[string]todosdomain.ListShare),
+		viewers: make(map[string][]string),
+	}}
+}
+
+func (s todosState) clone() todosState {
+	return todosState{
+		lists:   maps.Clone(s.lists),
+		items:   maps.Clone(s.items),
+		shares:  maps.Clone(s.shares),
+		viewers: maps.Clone(s.viewers),
+	}
+}
+
+func (r *TodosRepo) Transaction(_ context.Context, fn func(todosdomain.Repository) error) error {
+	r.mu.Lock()
+	saved := r.state.clone()
+	r.mu.Unlock()
+
+	if err := fn(r); err != nil {
+		r.mu.Lock()
+		r.state = saved
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// LockFamilyOrders is a no-op: every call already holds the repository lock.
+func (r *TodosRepo) LockFamilyOrders(context.Context, string) error {
+	return nil
+}
+
+// LockListItems is a no-op, like LockFamilyOrders.
+func (r *TodosRepo) LockListItems(context.Context, string) error {
+	return nil
+}
+
+// LockShoppingList is a no-op, like LockFamilyOrders.
+func (r *TodosRepo) LockShoppingList(context.Context, string) error {
+	return nil
+}
+
+func (r *TodosRepo) ListTodoLists(_ context.Context, familyID string, filter todosdomain.ListFilter) ([]todosdomain.TodoList, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	search := strings.TrimSpace(filter.Query)
+	lists := make([]todosdomain.TodoList, 0)
+	for _, list := range r.state.lists {
+		if list.FamilyID != familyID {
+			continue
+		}
+		if search != "" && !containsFold(list.Title, search) {
+			continue
+		}
+		if filter.AssigneeID != "" && !r.hasItemAssignedTo(list.ID, filter.AssigneeID) {
+			continue
+		}
+		if filter.ViewerID != "" && !r.visible(list, filter.ViewerID) {
+			continue
+		}
+		lists = append(lists, list)
+	}
+
+	favorite := func(list todosdomain.TodoList) bool {
+		return filter.FavoritesOf != "" && r.favorites != nil &&
+			r.favorites.isFavorite(filter.FavoritesOf, favoritesdomain.EntityTodoList, list.ID)
+	}
+	sort.SliceStable(lists, func(i, j int) bool {
+		if fi, fj := favorite(lists[i]), favorite(lists[j]); fi != fj {
+			return fi
+		}
+		if lists[i].Order != lists[j].Order {
+			return lists[i].Order < lists[j].Order
+		}
+		return lists[i].CreatedAt.Before(lists[j].CreatedAt)
+	})
+	return page(lists, filter.Offset, filter.Limit), int64(len(lists)), nil
+}
+
+func (r *TodosRepo) hasItemAssignedTo(listID, assigneeID string) bool {
+	for _, item := range r.state.items {
+		if item.ListID == listID && isAssignedTo(item, assigneeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// visible reports whether the viewer sees the list: it is not private or
+// they are one of its viewers.
+func (r *TodosRepo) visible(list todosdomain.TodoList, viewerID string) bool {
+	return !list.IsPrivate || contains(r.state.viewers[list.ID], viewerID)
+}
+
+func isAssignedTo(item todosdomain.TodoItem, assigneeID string) bool {
+	return item.AssigneeID != nil && *item.AssigneeID == assigneeID
+}
+
+func (r *TodosRepo) GetTodoListByID(_ context.Context, familyID, listID string) (*todosdomain.TodoList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list, ok := r.state.lists[listID]
+	if !ok || list.FamilyID != familyID {
+		return nil, todosdomain.ErrTodoListNotFound
+	}
+	return &list, nil
+}
+
+func (r *TodosRepo) CreateTodoList(_ context.Context, list *todosdomain.TodoList) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.lists[list.ID]; ok {
+		return ErrDuplicateKey
+	}
+	if list.Version == 0 {
+		list.Version = 1
+	}
+	if list.CreatedAt.IsZero() {
+		list.CreatedAt = time.Now().UTC()
+	}
+	r.state.lists[list.ID] = *list
+	return nil
+}
+
+func (r *TodosRepo) UpdateTodoList(_ context.Context, list *todosdomain.TodoList) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.state.lists[list.ID]
+	if !ok || stored.FamilyID != list.FamilyID || stored.Version != list.Version {
+		return todosdomain.ErrVersionConflict
+	}
+	stored.Title = list.Title
+	stored.ArchiveCompleted = list.ArchiveCompleted
+	stored.IsCollapsed = list.IsCollapsed
+	stored.Order = list.Order
+	stored.IsPrivate = list.IsPrivate
+	stored.Version++
+	r.state.lists[list.ID] = stored
+	list.Version++
+	return nil
+}
+
+func (r *TodosRepo) SoftDeleteTodoList(_ context.Context, familyID, listID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list, ok := r.state.lists[listID]
+	if !ok || list.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.state.lists, listID)
+	return true, nil
+}
+
+func (r *TodosRepo) CountTodoLists(_ context.Context, familyID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, list := range r.state.lists {
+		if list.FamilyID == familyID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *TodosRepo) GetMaxOrder(_ context.Context, familyID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	maxOrder := -1
+	for _, list := range r.state.lists {
+		if list.FamilyID == familyID && list.Order > maxOrder {
+			maxOrder = list.Order
+		}
+	}
+	return maxOrder, nil
+}
+
+func (r *TodosRepo) ShiftOrderRange(_ context.Context, familyID string, from, to, delta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if from > to || delta == 0 {
+		return nil
+	}
+	for id, list := range r.state.lists {
+		if list.FamilyID == familyID && list.Order >= from && list.Order <= to {
+			list.Order += delta
+			r.state.lists[id] = list
+		}
+	}
+	return nil
+}
+
+func (r *TodosRepo) SetCompletedItemsArchived(_ context.Context, listID string, archived bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, item := range r.state.items {
+		if item.ListID == listID && item.IsCompleted && item.IsArchived != archived {
+			item.IsArchived = archived
+			item.Version++
+			r.state.items[id] = item
+		}
+	}
+	return nil
+}
+
+func (r *TodosRepo) SoftDeleteItemsByList(_ context.Context, listID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, item := range r.state.items {
+		if item.ListID == listID {
+			delete(r.state.items, id)
+		}
+	}
+	return nil
+}
+
+func (r *TodosRepo) CountItemsByListIDs(_ context.Context, listIDs []string, assigneeID string) (map[string]todosdomain.ListItemCounts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[string]todosdomain.ListItemCounts, len(listIDs))
+	for _, item := range r.state.items {
+		if !contains(listIDs, item.ListID) || (assigneeID != "" && !isAssignedTo(item, assigneeID)) {
+			continue
+		}
+		counts := result[item.ListID]
+		counts.ItemsTotal++
+		if item.IsCompleted {
+			counts.ItemsCompleted++
+		}
+		if item.IsArchived {
+			counts.ItemsArchived++
+		}
+		if item.EstimatedPrice != nil {
+			counts.PlannedTotal += *item.EstimatedPrice
+		}
+		if item.ExpenseID != nil {
+			counts.ItemsLinked++
+			if item.EstimatedPrice != nil {
+				counts.PlannedPurchased += *item.EstimatedPrice
+			}
+		}
+		result[item.ListID] = counts
+	}
+
+	if r.expenses != nil {
+		priced := map[string]bool{}
+		for _, item := range r.state.items {
+			if item.ExpenseID == nil || !contains(listIDs, item.ListID) || (assigneeID != "" && !isAssignedTo(item, assigneeID)) {
+				continue
+			}
+			key := item.ListID + "/" + *item.ExpenseID
+			if priced[key] {
+				continue
+			}
+			priced[key] = true
+			if amount, ok := r.expenses.amountInBase(*item.ExpenseID); ok {
+				counts := result[item.ListID]
+				counts.ActualTotal += amount
+				result[item.ListID] = counts
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *TodosRepo) ListItemsByListIDs(_ context.Context, listIDs []string, archived todosdomain.ArchivedFilter, assigneeID string) ([]todosdomain.TodoItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]todosdomain.TodoItem, 0)
+	for _, item := range r.state.items {
+		if !contains(listIDs, item.ListID) || !matchesItem(item, archived, assigneeID) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ListID != items[j].ListID {
+			return items[i].ListID < items[j].ListID
+		}
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+func matchesItem(item todosdomain.TodoItem, archived todosdomain.ArchivedFilter, assigneeID string) bool {
+	if assigneeID != "" && !isAssignedTo(item, assigneeID) {
+		return false
+	}
+	switch archived {
+	case todosdomain.ArchivedOnly:
+		return item.IsArchived
+	case todosdomain.ArchivedExclude:
+		return !item.IsArchived
+	}
+	return true
+}
+
+func (r *TodosRepo) SearchTodoItems(_ context.Context, familyID, query, assigneeID, viewerID string, limit int) ([]todosdomain.TodoItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	search := strings.TrimSpace(query)
+	items := make([]todosdomain.TodoItem, 0)
+	for _, item := range r.state.items {
+		list, ok := r.state.lists[item.ListID]
+		if !ok || list.FamilyID != familyID || !containsFold(item.Title, search) {
+			continue
+		}
+		if assigneeID != "" && !isAssignedTo(item, assigneeID) {
+			continue
+		}
+		if viewerID != "" && !r.visible(list, viewerID) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].IsCompleted != items[j].IsCompleted {
+			return !items[i].IsCompleted
+		}
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return page(items, 0, limit), nil
+}
+
+func (r *TodosRepo) ListTodoItems(_ context.Context, listID string, archived todosdomain.ArchivedFilter, assigneeID string, labels []string) ([]todosdomain.TodoItem, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]todosdomain.TodoItem, 0)
+	for _, item := range r.state.items {
+		if item.ListID != listID || !matchesItem(item, archived, assigneeID) {
+			continue
+		}
+		if len(labels) > 0 && (r.labels == nil || !r.labels.hasAny(labelsdomain.EntityTodoItem, item.ID, labels)) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items, int64(len(items)), nil
+}
+
+func (r *TodosRepo) CreateTodoItem(_ context.Context, item *todosdomain.TodoItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.items[item.ID]; ok {
+		return ErrDuplicateKey
+	}
+	if item.Version == 0 {
+		item.Version = 1
+	}
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now().UTC()
+	}
+	r.state.items[item.ID] = *item
+	return nil
+}
+
+func (r *TodosRepo) GetTodoItemWithListArchive(_ context.Context, familyID, itemID string) (*todosdomain.TodoItem, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.state.items[itemID]
+	if !ok {
+		return nil, false, todosdomain.ErrTodoItemNotFound
+	}
+	list, ok := r.state.lists[item.ListID]
+	if !ok || list.FamilyID != familyID {
+		return nil, false, todosdomain.ErrTodoItemNotFound
+	}
+	return &item, list.ArchiveCompleted, nil
+}
+
+func (r *TodosRepo) UpdateTodoItem(_ context.Context, item *todosdomain.TodoItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.state.items[item.ID]
+	if !ok || stored.ListID != item.ListID || stored.Version != item.Version {
+		return todosdomain.ErrVersionConflict
+	}
+	item.Version++
+	item.CreatedAt = stored.CreatedAt
+	r.state.items[item.ID] = *item
+	return nil
+}
+
+func (r *TodosRepo) SoftDeleteTodoItem(_ context.Context, itemID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.items[itemID]; !ok {
+		return false, nil
+	}
+	delete(r.state.items, itemID)
+	return true, nil
+}
+
+func (r *TodosRepo) ListDueTodoItems(_ context.Context, familyID, viewerID string) ([]todosdomain.DueTodoItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]todosdomain.DueTodoItem, 0)
+	for _, item := range r.state.items {
+		if item.DueDate == nil || item.IsCompleted || item.IsArchived {
+			continue
+		}
+		list, ok := r.state.lists[item.ListID]
+		if !ok || list.FamilyID != familyID {
+			continue
+		}
+		if viewerID != "" && !r.visible(list, viewerID) {
+			continue
+		}
+		result = append(result, todosdomain.DueTodoItem{Item: item, ListTitle: list.Title})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Item.DueDate.Equal(*result[j].Item.DueDate) {
+			return result[i].Item.DueDate.Before(*result[j].Item.DueDate)
+		}
+		return result[i].Item.CreatedAt.Before(result[j].Item.CreatedAt)
+	})
+	return result, nil
+}
+
+func (r *TodosRepo) ListListViewers(_ context.Context, listIDs []string) (map[string][]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[string][]string, len(listIDs))
+	for _, listID := range listIDs {
+		if viewers := r.state.viewers[listID]; len(viewers) > 0 {
+			result[listID] = append([]string{}, viewers...)
+		}
+	}
+	return result, nil
+}
+
+func (r *TodosRepo) ReplaceListViewers(_ context.Context, listID string, userIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(userIDs) == 0 {
+		delete(r.state.viewers, listID)
+		return nil
+	}
+	viewers := dedupe(userIDs)
+	if len(viewers) != len(userIDs) {
+		return ErrDuplicateKey
+	}
+	sort.Strings(viewers)
+	r.state.viewers[listID] = viewers
+	return nil
+}
+
+func (r *TodosRepo) CountFamilyMembers(_ context.Context, familyID string, userIDs []string) (int64, error) {
+	if r.family == nil {
+		return 0, nil
+	}
+	return r.family.countMembers(familyID, userIDs), nil
+}
+
+func (r *TodosRepo) ExpenseExists(_ context.Context, familyID, expenseID string) (bool, error) {
+	if r.expenses == nil {
+		return false, nil
+	}
+	return r.expenses.exists(familyID, expenseID), nil
+}
+
+func (r *TodosRepo) CreateListShare(_ context.Context, share *todosdomain.ListShare) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.shares[share.ID]; ok {
+		return ErrDuplicateKey
+	}
+	r.state.shares[share.ID] = *share
+	return nil
+}
+
+func (r *TodosRepo) ListListShares(_ context.Context, familyID, listID string) ([]todosdomain.ListShare, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shares := make([]todosdomain.ListShare, 0)
+	for _, share := range r.state.shares {
+		if share.FamilyID == familyID && share.ListID == listID {
+			shares = append(shares, share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.After(shares[j].CreatedAt)
+	})
+	return shares, nil
+}
+
+func (r *TodosRepo) CountActiveListShares(_ context.Context, listID string, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, share := range r.state.shares {
+		if share.ListID == listID && share.RevokedAt == nil && (share.ExpiresAt == nil || share.ExpiresAt.After(now)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *TodosRepo) GetListShareByHash(_ context.Context, hash string) (*todosdomain.ListShare, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, share := range r.state.shares {
+		if share.TokenHash == hash {
+			return &share, nil
+		}
+	}
+	return nil, todosdomain.ErrShareNotFound
+}
+
+func (r *TodosRepo) RevokeListShare(_ context.Context, familyID, listID, shareID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	share, ok := r.state.shares[shareID]
+	if !ok || share.FamilyID != familyID || share.ListID != listID || share.RevokedAt != nil {
+		return todosdomain.ErrShareNotFound
+	}
+	share.RevokedAt = &at
+	r.state.shares[shareID] = share
+	return nil
+}
+
+// patchCompletedBy rewrites the completed_by snapshots of userID's items
+// from their profile, as the user repository's Postgres version does.
+func (r *TodosRepo) patchCompletedBy(userID string, profile userdomain.Profile) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var avatarURL *string
+	if effective := profile.EffectiveAvatarURL(); effective != "" {
+		avatarURL = &effective
+	}
+	var patched int64
+	for id, item := range r.state.items {
+		if item.CompletedByID == nil || *item.CompletedByID != userID {
+			continue
+		}
+		updated := item
+		if profile.Name != nil && *profile.Name != "" {
+			updated.CompletedByName = profile.Name
+		}
+		if profile.Email != nil && *profile.Email != "" {
+			updated.CompletedByEmail = profile.Email
+		}
+		updated.CompletedByAvatarURL = avatarURL
+		if sameString(item.CompletedByName, updated.CompletedByName) &&
+			sameString(item.CompletedByEmail, updated.CompletedByEmail) &&
+			sameString(item.CompletedByAvatarURL, updated.CompletedByAvatarURL) {
+			continue
+		}
+		r.state.items[id] = updated
+		patched++
+	}
+	return patched
+}
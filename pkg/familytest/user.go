@@ -0,0 +1,245 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	userdomain "family-app-go/internal/domain/user"
+)
+
+var _ userdomain.Repository = (*UserRepo)(nil)
+
+// UserRepo is an in-memory userdomain.Repository. Updates that change a
+// profile's name, email or avatar queue it in the sync outbox, as the
+// Postgres trigger does.
+type UserRepo struct {
+	mu       sync.Mutex
+	profiles map[string]userdomain.Profile
+	outbox   map[string]time.Time
+
+	// todos holds the snapshots PatchCompletedBySnapshots rewrites; without
+	// it there are none.
+	todos *TodosRepo
+}
+
+func NewUserRepo() *UserRepo {
+	return &UserRepo{
+		profiles: make(map[string]userdomain.Profile),
+		outbox:   make(map[string]time.Time),
+	}
+}
+
+// UpsertProfile creates the profile or refreshes the email and avatar the
+// identity provider reported, leaving nil fields alone. The stored profile is
+// copied back into profile.
+func (r *UserRepo) UpsertProfile(_ context.Context, profile *userdomain.Profile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	stored, ok := r.profiles[profile.UserID]
+	if !ok {
+		stored = *profile
+		stored.CreatedAt = now
+	}
+	if profile.Email != nil {
+		stored.Email = profile.Email
+	}
+	if profile.AvatarURL != nil {
+		stored.AvatarURL = profile.AvatarURL
+	}
+	stored.UpdatedAt = now
+	if ok {
+		r.queueIfChanged(r.profiles[profile.UserID], stored)
+	}
+	r.profiles[profile.UserID] = stored
+	*profile = stored
+	return nil
+}
+
+func (r *UserRepo) GetProfile(_ context.Context, userID string) (*userdomain.Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil, userdomain.ErrProfileNotFound
+	}
+	return &profile, nil
+}
+
+func (r *UserRepo) UpdatePreferences(_ context.Context, userID string, update userdomain.PreferencesUpdate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profile := r.profile(userID)
+	if update.Theme != nil {
+		profile.Theme = update.Theme
+	}
+	if update.Language != nil {
+		profile.Language = update.Language
+	}
+	if update.WeightUnit != nil {
+		profile.WeightUnit = update.WeightUnit
+	}
+	if update.NotifyTodoReminders != nil {
+		profile.NotifyTodoReminders = update.NotifyTodoReminders
+	}
+	if update.NotifyGymNudges != nil {
+		profile.NotifyGymNudges = update.NotifyGymNudges
+	}
+	if update.NotifyFamilyActivity != nil {
+		profile.NotifyFamilyActivity = update.NotifyFamilyActivity
+	}
+	r.profiles[userID] = profile
+	return nil
+}
+
+func (r *UserRepo) SetUploadedAvatar(_ context.Context, userID, key, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profile := r.profile(userID)
+	profile.UploadedAvatarKey = &key
+	profile.UploadedAvatarURL = &url
+	r.update(profile)
+	return nil
+}
+
+func (r *UserRepo) ListProfilesToSync(_ context.Context, syncedBefore time.Time, limit int) ([]userdomain.Profile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []userdomain.Profile
+	for _, profile := range r.profiles {
+		if profile.SyncedAt == nil || profile.SyncedAt.Before(syncedBefore) {
+			due = append(due, profile)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		a, b := due[i].SyncedAt, due[j].SyncedAt
+		if (a == nil) != (b == nil) {
+			return a == nil
+		}
+		if a != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return due[i].UserID < due[j].UserID
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (r *UserRepo) ApplyRemoteProfile(_ context.Context, userID string, remote userdomain.RemoteProfile, syncedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profile, ok := r.profiles[userID]
+	if !ok {
+		return nil
+	}
+	if remote.Name != "" {
+		profile.Name = &remote.Name
+	}
+	if remote.Email != "" {
+		profile.Email = &remote.Email
+	}
+	if remote.AvatarURL != "" {
+		profile.AvatarURL = &remote.AvatarURL
+	}
+	profile.SyncedAt = &syncedAt
+	profile.UpdatedAt = time.Now().UTC()
+	r.update(profile)
+	return nil
+}
+
+func (r *UserRepo) MarkProfileSynced(_ context.Context, userID string, syncedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if profile, ok := r.profiles[userID]; ok {
+		profile.SyncedAt = &syncedAt
+		r.profiles[userID] = profile
+	}
+	return nil
+}
+
+func (r *UserRepo) ListSyncOutbox(_ context.Context, limit int) ([]userdomain.SyncOutboxEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]userdomain.SyncOutboxEntry, 0, len(r.outbox))
+	for userID, queuedAt := range r.outbox {
+		entries = append(entries, userdomain.SyncOutboxEntry{UserID: userID, QueuedAt: queuedAt})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].QueuedAt.Equal(entries[j].QueuedAt) {
+			return entries[i].QueuedAt.Before(entries[j].QueuedAt)
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// PatchCompletedBySnapshots releases the lock while it rewrites the todo
+// items, so the repositories never wait on each other.
+func (r *UserRepo) PatchCompletedBySnapshots(_ context.Context, entry userdomain.SyncOutboxEntry) (int64, error) {
+	r.mu.Lock()
+	profile, ok := r.profiles[entry.UserID]
+	r.mu.Unlock()
+
+	var patched int64
+	if ok && r.todos != nil {
+		patched = r.todos.patchCompletedBy(entry.UserID, profile)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if queuedAt, ok := r.outbox[entry.UserID]; ok && queuedAt.Equal(entry.QueuedAt) {
+		delete(r.outbox, entry.UserID)
+	}
+	return patched, nil
+}
+
+// update stores profile, queueing it when it changed an existing one.
+func (r *UserRepo) update(profile userdomain.Profile) {
+	if stored, ok := r.profiles[profile.UserID]; ok {
+		r.queueIfChanged(stored, profile)
+	}
+	r.profiles[profile.UserID] = profile
+}
+
+func (r *UserRepo) queueIfChanged(before, after userdomain.Profile) {
+	if sameString(before.Name, after.Name) &&
+		sameString(before.Email, after.Email) &&
+		sameString(before.AvatarURL, after.AvatarURL) &&
+		sameString(before.UploadedAvatarURL, after.UploadedAvatarURL) {
+		return
+	}
+	r.outbox[after.UserID] = time.Now()
+}
+
+func sameString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// profile returns the user's profile, or a new one, touched for an update.
+func (r *UserRepo) profile(userID string) userdomain.Profile {
+	now := time.Now().UTC()
+	profile, ok := r.profiles[userID]
+	if !ok {
+		profile = userdomain.Profile{UserID: userID, CreatedAt: now}
+	}
+	profile.UpdatedAt = now
+	return profile
+}
@@ -0,0 +1,109 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	viewsdomain "family-app-go/internal/domain/views"
+)
+
+var _ viewsdomain.Repository = (*ViewsRepo)(nil)
+
+// ViewsRepo is an in-memory viewsdomain.Repository.
+type ViewsRepo struct {
+	mu    sync.Mutex
+	views map[string]viewsdomain.SavedView
+}
+
+func NewViewsRepo() *ViewsRepo {
+	return &ViewsRepo{views: make(map[string]viewsdomain.SavedView)}
+}
+
+func (r *ViewsRepo) CreateView(_ context.Context, view *viewsdomain.SavedView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.views[view.ID]; ok {
+		return ErrDuplicateKey
+	}
+	r.views[view.ID] = cloneView(*view)
+	return nil
+}
+
+func (r *ViewsRepo) UpdateView(_ context.Context, view *viewsdomain.SavedView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.views[view.ID]
+	if !ok || stored.UserID != view.UserID {
+		return viewsdomain.ErrViewNotFound
+	}
+	stored.Name = view.Name
+	stored.Filters = view.Filters
+	stored.UpdatedAt = view.UpdatedAt
+	r.views[view.ID] = cloneView(stored)
+	return nil
+}
+
+func (r *ViewsRepo) GetView(_ context.Context, userID, id string) (*viewsdomain.SavedView, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	view, ok := r.views[id]
+	if !ok || view.UserID != userID {
+		return nil, viewsdomain.ErrViewNotFound
+	}
+	view = cloneView(view)
+	return &view, nil
+}
+
+func (r *ViewsRepo) ListViews(_ context.Context, userID string, entityType viewsdomain.EntityType) ([]viewsdomain.SavedView, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	views := make([]viewsdomain.SavedView, 0)
+	for _, view := range r.views {
+		if view.UserID != userID || (entityType != "" && view.EntityType != entityType) {
+			continue
+		}
+		views = append(views, cloneView(view))
+	}
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Name != views[j].Name {
+			return views[i].Name < views[j].Name
+		}
+		return views[i].ID < views[j].ID
+	})
+	return views, nil
+}
+
+func (r *ViewsRepo) CountViews(_ context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, view := range r.views {
+		if view.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ViewsRepo) DeleteView(_ context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	view, ok := r.views[id]
+	if !ok || view.UserID != userID {
+		return viewsdomain.ErrViewNotFound
+	}
+	delete(r.views, id)
+	return nil
+}
+
+func cloneView(view viewsdomain.SavedView) viewsdomain.SavedView {
+	view.Filters = append([]byte(nil), view.Filters...)
+	return view
+}
@@ -0,0 +1,123 @@
+package familytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	wishlistdomain "family-app-go/internal/domain/wishlist"
+)
+
+var _ wishlistdomain.Repository = (*WishlistRepo)(nil)
+
+// WishlistRepo is an in-memory wishlistdomain.Repository.
+type WishlistRepo struct {
+	mu    sync.Mutex
+	items map[string]wishlistdomain.Item
+}
+
+func NewWishlistRepo() *WishlistRepo {
+	return &WishlistRepo{items: make(map[string]wishlistdomain.Item)}
+}
+
+func (r *WishlistRepo) ListItemsByOwner(_ context.Context, familyID, ownerID string) ([]wishlistdomain.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]wishlistdomain.Item, 0)
+	for _, item := range r.items {
+		if item.FamilyID == familyID && item.OwnerID == ownerID {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+func (r *WishlistRepo) GetItem(_ context.Context, familyID, itemID string) (*wishlistdomain.Item, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[itemID]
+	if !ok || item.FamilyID != familyID {
+		return nil, wishlistdomain.ErrItemNotFound
+	}
+	return &item, nil
+}
+
+func (r *WishlistRepo) CreateItem(_ context.Context, item *wishlistdomain.Item) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[item.ID]; ok {
+		return ErrDuplicateKey
+	}
+	now := time.Now().UTC()
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = now
+	}
+	item.UpdatedAt = now
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *WishlistRepo) UpdateItem(_ context.Context, item *wishlistdomain.Item) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.items[item.ID]
+	if !ok || stored.FamilyID != item.FamilyID {
+		return nil
+	}
+	stored.Title = item.Title
+	stored.URL = item.URL
+	stored.Price = cloneFloat(item.Price)
+	stored.Currency = item.Currency
+	stored.Note = item.Note
+	stored.UpdatedAt = time.Now().UTC()
+	r.items[item.ID] = stored
+	return nil
+}
+
+func (r *WishlistRepo) DeleteItem(_ context.Context, familyID, itemID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[itemID]
+	if !ok || item.FamilyID != familyID {
+		return false, nil
+	}
+	delete(r.items, itemID)
+	return true, nil
+}
+
+func (r *WishlistRepo) ClaimItem(_ context.Context, itemID, userID string, claimedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[itemID]
+	if !ok || item.ClaimedByID != nil {
+		return false, nil
+	}
+	item.ClaimedByID = &userID
+	item.ClaimedAt = &claimedAt
+	r.items[itemID] = item
+	return true, nil
+}
+
+func (r *WishlistRepo) UnclaimItem(_ context.Context, itemID, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[itemID]
+	if !ok || item.ClaimedByID == nil || *item.ClaimedByID != userID {
+		return false, nil
+	}
+	item.ClaimedByID = nil
+	item.ClaimedAt = nil
+	r.items[itemID] = item
+	return true, nil
+}
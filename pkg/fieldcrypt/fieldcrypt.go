@@ -0,0 +1,219 @@
+// Package fieldcrypt encrypts sensitive columns at rest with AES-256-GCM.
+//
+// Model fields opt in with the `serializer:encrypted` GORM tag. Values are
+// stored as "enc:v1:<key id>:<base64 nonce and ciphertext>", so every value
+// names the key that sealed it and old keys can stay in the keyring until
+// their rows are rotated. Values without that prefix are read back as they
+// are, which lets encryption be enabled on a database with plaintext rows.
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	prefix  = "enc:v1:"
+	keySize = 32
+)
+
+// EncryptedLike is a SQL LIKE pattern matching encrypted values only.
+const EncryptedLike = prefix + "%"
+
+var (
+	ErrNoKeyring    = errors.New("fieldcrypt: value is encrypted but no keys are configured")
+	ErrUnknownKey   = errors.New("fieldcrypt: value is encrypted with an unknown key")
+	ErrMalformed    = errors.New("fieldcrypt: malformed encrypted value")
+	ErrInvalidKeys  = errors.New("fieldcrypt: invalid keys")
+	ErrNoPrimaryKey = errors.New("fieldcrypt: no keys given")
+)
+
+// Key is one AES-256 key. ID is stored next to every value it encrypts and
+// must not contain ':' or ','.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeyProvider supplies the keys, primary first. The environment is one
+// source; a KMS client that unwraps data keys is another.
+type KeyProvider interface {
+	Keys(ctx context.Context) ([]Key, error)
+}
+
+// EnvProvider reads keys from a comma-separated list of id:base64 pairs, as
+// in FIELD_ENCRYPTION_KEYS=2026-10:...,2026-01:...
+type EnvProvider struct {
+	Value string
+}
+
+func (p EnvProvider) Keys(context.Context) ([]Key, error) {
+	return ParseKeys(p.Value)
+}
+
+// ParseKeys parses a comma-separated list of id:base64 pairs. An empty value
+// yields no keys.
+func ParseKeys(value string) ([]Key, error) {
+	var keys []Key
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(id) == "" {
+			return nil, fmt.Errorf("%w: %q is not id:base64", ErrInvalidKeys, entry)
+		}
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %s is not base64", ErrInvalidKeys, id)
+		}
+		keys = append(keys, Key{ID: strings.TrimSpace(id), Secret: secret})
+	}
+	return keys, nil
+}
+
+// Keyring encrypts with its primary key and decrypts with any of its keys.
+type Keyring struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring builds a keyring whose primary key is the first one.
+func NewKeyring(keys []Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoPrimaryKey
+	}
+
+	keyring := &Keyring{primary: keys[0].ID, aeads: make(map[string]cipher.AEAD, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" || strings.ContainsAny(key.ID, ":,") {
+			return nil, fmt.Errorf("%w: key id %q", ErrInvalidKeys, key.ID)
+		}
+		if len(key.Secret) != keySize {
+			return nil, fmt.Errorf("%w: key %s must be %d bytes", ErrInvalidKeys, key.ID, keySize)
+		}
+		if _, ok := keyring.aeads[key.ID]; ok {
+			return nil, fmt.Errorf("%w: duplicate key id %s", ErrInvalidKeys, key.ID)
+		}
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keyring.aeads[key.ID] = aead
+	}
+	return keyring, nil
+}
+
+// LoadKeyring builds a keyring from provider. It returns nil, and no error,
+// when the provider has no keys: encryption is then disabled.
+func LoadKeyring(ctx context.Context, provider KeyProvider) (*Keyring, error) {
+	keys, err := provider.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return NewKeyring(keys)
+}
+
+// PrimaryKeyID names the key new values are encrypted with.
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext with the primary key.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.aeads[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + k.primary + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens an encrypted value. Values that are not encrypted are
+// returned unchanged.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	aead, ok := k.aeads[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether value is plaintext or sealed with a key
+// other than the primary one.
+func (k *Keyring) NeedsRotation(value string) bool {
+	return !strings.HasPrefix(value, prefix+k.primary+":")
+}
+
+// IsEncrypted reports whether value was written by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+var active atomic.Pointer[Keyring]
+
+// Use installs the keyring the GORM serializer and the package-level
+// helpers work with. A nil keyring disables encryption: new values are
+// stored in plaintext and encrypted ones fail to decrypt.
+func Use(keyring *Keyring) {
+	active.Store(keyring)
+}
+
+// Active returns the keyring installed by Use, or nil.
+func Active() *Keyring {
+	return active.Load()
+}
+
+// Encrypt seals plaintext with the active keyring, or returns it unchanged
+// when encryption is disabled.
+func Encrypt(plaintext string) (string, error) {
+	keyring := Active()
+	if keyring == nil {
+		return plaintext, nil
+	}
+	return keyring.Encrypt(plaintext)
+}
+
+// Decrypt opens value with the active keyring. Plaintext passes through.
+func Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	keyring := Active()
+	if keyring == nil {
+		return "", ErrNoKeyring
+	}
+	return keyring.Decrypt(value)
+}
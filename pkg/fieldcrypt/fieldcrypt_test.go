@@ -0,0 +1,74 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(id string, fill byte) Key {
+	return Key{ID: id, Secret: bytes.Repeat([]byte{fill}, keySize)}
+}
+
+func TestKeyringRoundTripsAndRotates(t *testing.T) {
+	old, err := NewKeyring([]Key{testKey("k1", 1)})
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	sealed, err := old.Encrypt("Groceries at the corner shop")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if !IsEncrypted(sealed) || strings.Contains(sealed, "Groceries") {
+		t.Fatalf("expected an opaque encrypted value, got %q", sealed)
+	}
+
+	rotated, err := NewKeyring([]Key{testKey("k2", 2), testKey("k1", 1)})
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	if !rotated.NeedsRotation(sealed) || !rotated.NeedsRotation("plain") {
+		t.Fatalf("expected old-key and plaintext values to need rotation")
+	}
+	plaintext, err := rotated.Decrypt(sealed)
+	if err != nil || plaintext != "Groceries at the corner shop" {
+		t.Fatalf("decrypt with old key: %q, %v", plaintext, err)
+	}
+	resealed, err := rotated.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if rotated.NeedsRotation(resealed) {
+		t.Fatalf("expected %q to be sealed with the primary key", resealed)
+	}
+
+	if _, err := old.Decrypt(resealed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected unknown key, got %v", err)
+	}
+	if plaintext, err := old.Decrypt("plain"); err != nil || plaintext != "plain" {
+		t.Fatalf("expected plaintext to pass through, got %q, %v", plaintext, err)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, keySize))
+	keys, err := ParseKeys(" 2026-10:" + secret + ", 2026-01:" + secret + " ")
+	if err != nil {
+		t.Fatalf("parse keys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != "2026-10" || keys[1].ID != "2026-01" {
+		t.Fatalf("unexpected keys %#v", keys)
+	}
+
+	if keys, err := ParseKeys(""); err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys, got %#v, %v", keys, err)
+	}
+	if _, err := ParseKeys("no-secret"); !errors.Is(err, ErrInvalidKeys) {
+		t.Fatalf("expected invalid keys, got %v", err)
+	}
+	if _, err := NewKeyring([]Key{{ID: "short", Secret: []byte("too short")}}); !errors.Is(err, ErrInvalidKeys) {
+		t.Fatalf("expected invalid keys for a short secret, got %v", err)
+	}
+}
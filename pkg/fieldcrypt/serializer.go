@@ -0,0 +1,87 @@
+package fieldcrypt
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the GORM serializer that encrypts string and *string
+// fields, e.g. `gorm:"serializer:encrypted"`.
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Serializer encrypts fields on write and decrypts them on read with the
+// keyring installed by Use.
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value := reflect.New(field.FieldType).Elem()
+	if dbValue != nil {
+		var stored string
+		switch v := dbValue.(type) {
+		case string:
+			stored = v
+		case []byte:
+			stored = string(v)
+		default:
+			return fmt.Errorf("fieldcrypt: cannot scan %T into %s", dbValue, field.Name)
+		}
+
+		plaintext, err := Decrypt(stored)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		if field.FieldType.Kind() == reflect.Ptr {
+			value.Set(reflect.New(field.FieldType.Elem()))
+			value.Elem().SetString(plaintext)
+		} else {
+			value.SetString(plaintext)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(value)
+	return nil
+}
+
+func (Serializer) Value(_ context.Context, _ *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	return encryptValue(fieldValue)
+}
+
+// Value wraps a string or *string so it is encrypted when written. GORM
+// does not run serializers for map updates, so those pass encrypted
+// columns through Value, e.g. Updates(map[string]interface{}{"title":
+// fieldcrypt.Value(expense.Title)}).
+func Value(value interface{}) driver.Valuer {
+	return valuer{value: value}
+}
+
+type valuer struct {
+	value interface{}
+}
+
+func (v valuer) Value() (driver.Value, error) {
+	return encryptValue(v.value)
+}
+
+func encryptValue(value interface{}) (driver.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return Encrypt(v)
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		return Encrypt(*v)
+	default:
+		return nil, fmt.Errorf("fieldcrypt: cannot encrypt %T", value)
+	}
+}
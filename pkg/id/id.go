@@ -0,0 +1,58 @@
+// Package id generates and checks the UUIDs used as record IDs, and the
+// random hex strings used in file names and key prefixes.
+package id
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// New returns a random (version 4) UUID in its canonical lowercase form.
+func New() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// Hex returns n random bytes as 2n lowercase hex characters.
+func Hex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IsUUID reports whether value is a UUID in the 8-4-4-4-12 hex layout that
+// postgres accepts for uuid columns. Version and variant bits are not
+// checked, and surrounding whitespace is not allowed.
+func IsUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if ch != '-' {
+				return false
+			}
+			continue
+		}
+		if !isHex(ch) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(ch byte) bool {
+	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
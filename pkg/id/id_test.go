@@ -0,0 +1,62 @@
+package id
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewReturnsVersion4UUIDs(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		value, err := New()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !IsUUID(value) {
+			t.Fatalf("New() = %q, not a uuid", value)
+		}
+		if value[14] != '4' || (value[19] != '8' && value[19] != '9' && value[19] != 'a' && value[19] != 'b') {
+			t.Fatalf("New() = %q, want version 4 with RFC 4122 variant", value)
+		}
+		if seen[value] {
+			t.Fatalf("New() repeated %q", value)
+		}
+		seen[value] = true
+	}
+}
+
+func TestHex(t *testing.T) {
+	first, err := Hex(8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := Hex(8)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first) != 16 || strings.ToLower(first) != first || strings.Trim(first, "0123456789abcdef") != "" {
+		t.Fatalf("Hex(8) = %q, want 16 lowercase hex characters", first)
+	}
+	if first == second {
+		t.Fatalf("Hex(8) repeated %q", first)
+	}
+}
+
+func TestIsUUID(t *testing.T) {
+	cases := map[string]bool{
+		"11111111-1111-1111-1111-111111111111":  true,
+		"A1B2C3D4-E5F6-4A7B-8C9D-0E1F2A3B4C5D":  true,
+		"":                                      false,
+		"not-a-uuid":                            false,
+		"11111111111111111111111111111111":      false,
+		"11111111-1111-1111-1111-11111111111g":  false,
+		" 11111111-1111-1111-1111-111111111111": false,
+		"11111111-1111-1111-1111-1111111111111": false,
+		"11111111_1111-1111-1111-111111111111":  false,
+	}
+	for value, want := range cases {
+		if got := IsUUID(value); got != want {
+			t.Fatalf("IsUUID(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
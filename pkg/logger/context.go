@@ -0,0 +1,88 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// scope is what a context carries: the request-scoped logger, if any, and
+// the fields added by WithFields so a fallback logger gets them too.
+type scope struct {
+	log    Logger
+	fields []any
+}
+
+// WithContext stores a request-scoped logger, typically one carrying the
+// request and trace IDs. Fields already added with WithFields are attached
+// to it.
+func WithContext(ctx context.Context, log Logger) context.Context {
+	current := scopeFrom(ctx)
+	if log != nil && len(current.fields) > 0 {
+		log = addFields(log, current.fields)
+	}
+	return context.WithValue(ctx, contextKey{}, scope{log: log, fields: current.fields})
+}
+
+// FromContext returns the logger stored by WithContext, or fallback with the
+// fields added by WithFields.
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if ctx == nil {
+		return fallback
+	}
+	current := scopeFrom(ctx)
+	if current.log != nil {
+		return current.log
+	}
+	if fallback != nil && len(current.fields) > 0 {
+		return addFields(fallback, current.fields)
+	}
+	return fallback
+}
+
+// WithFields returns ctx whose logger carries args as well.
+func WithFields(ctx context.Context, args ...any) context.Context {
+	if len(args) == 0 {
+		return ctx
+	}
+	current := scopeFrom(ctx)
+	fields := make([]any, 0, len(current.fields)+len(args))
+	fields = append(append(fields, current.fields...), args...)
+	log := current.log
+	if log != nil {
+		log = addFields(log, args)
+	}
+	return context.WithValue(ctx, contextKey{}, scope{log: log, fields: fields})
+}
+
+// WithRequestID, WithUserID and WithFamilyID attach the usual identifiers to
+// every line logged through FromContext. Empty IDs are skipped.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return withID(ctx, "request_id", requestID)
+}
+
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return withID(ctx, "user_id", userID)
+}
+
+func WithFamilyID(ctx context.Context, familyID string) context.Context {
+	return withID(ctx, "family_id", familyID)
+}
+
+func withID(ctx context.Context, key, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	return WithFields(ctx, key, value)
+}
+
+// addFields attaches fields that a line's own arguments may override.
+func addFields(log Logger, fields []any) Logger {
+	if base, ok := log.(*slogLogger); ok {
+		return base.withDefaults(fields)
+	}
+	return log.With(fields...)
+}
+
+func scopeFrom(ctx context.Context) scope {
+	current, _ := ctx.Value(contextKey{}).(scope)
+	return current
+}
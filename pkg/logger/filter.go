@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// filterHandler applies per-module levels and sampling before handing
+// records to the JSON or text handler.
+type filterHandler struct {
+	inner   slog.Handler
+	level   slog.Level
+	modules map[string]slog.Level
+	sampler *sampler
+}
+
+func (h *filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *filterHandler) Handle(ctx context.Context, record slog.Record) error {
+	level := h.level
+	if moduleLevel, ok := h.modules[moduleOf(record.Message)]; ok {
+		level = moduleLevel
+	}
+	if record.Level < level {
+		return nil
+	}
+	if record.Level < slog.LevelWarn && !h.sampler.allow(record.Message, record.Time) {
+		return nil
+	}
+	return h.inner.Handle(ctx, record)
+}
+
+func (h *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.inner = h.inner.WithAttrs(attrs)
+	return &clone
+}
+
+func (h *filterHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.inner = h.inner.WithGroup(name)
+	return &clone
+}
+
+func moduleOf(message string) string {
+	end := strings.IndexAny(message, ".: ")
+	if end <= 0 {
+		return ""
+	}
+	return strings.ToLower(message[:end])
+}
+
+// sampler counts messages per one-second window. It is shared by every
+// logger derived with With, so request-scoped loggers sample together.
+type sampler struct {
+	initial    int
+	thereafter int
+
+	mu     sync.Mutex
+	window time.Time
+	counts map[string]int
+}
+
+func newSampler(options Sampling) *sampler {
+	if options.Initial <= 0 {
+		return nil
+	}
+	return &sampler{
+		initial:    options.Initial,
+		thereafter: options.Thereafter,
+		counts:     make(map[string]int),
+	}
+}
+
+func (s *sampler) allow(message string, at time.Time) bool {
+	if s == nil {
+		return true
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	window := at.Truncate(time.Second)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !window.Equal(s.window) {
+		s.window = window
+		clear(s.counts)
+	}
+	s.counts[message]++
+	count := s.counts[message]
+	if count <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (count-s.initial)%s.thereafter == 0
+}
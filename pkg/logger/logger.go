@@ -5,6 +5,7 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -25,50 +26,118 @@ type Logger interface {
 
 type slogLogger struct {
 	base *slog.Logger
+	// fields are attached only when a line does not set the same key, so a
+	// handler passing "user_id" itself does not log it twice.
+	fields []any
+	// with repeats the arguments given to With for the reporter, which
+	// cannot read them back from the slog handler.
+	with     []any
+	reporter Reporter
+	policy   piiPolicy
 }
 
+// Options configures NewWithOptions.
+type Options struct {
+	Level  slog.Level
+	Format string
+	// ModuleLevels overrides Level for messages of a module. The module is
+	// the message prefix before the first "." or ":", so "sync: batch
+	// applied" and "sync.apply: failed" both belong to "sync".
+	ModuleLevels map[string]slog.Level
+	Sampling     Sampling
+	// PII selects how emails, names and avatar URLs are scrubbed; the zero
+	// value masks them.
+	PII PIIMode
+}
+
+// Sampling thins out repeated Debug and Info lines. Within each second the
+// first Initial lines with the same message are written, then every
+// Thereafter-th one. Warnings and errors are never sampled. A zero Initial
+// disables sampling.
+type Sampling struct {
+	Initial    int
+	Thereafter int
+}
+
+// NewFromEnv reads LOG_LEVEL, LOG_FORMAT, LOG_LEVEL_<MODULE> overrides such
+// as LOG_LEVEL_SYNC=debug, LOG_SAMPLE_INITIAL/LOG_SAMPLE_THEREAFTER and
+// LOG_PII_STRICT.
 func NewFromEnv() Logger {
 	env := normalizeValue(os.Getenv("ENV"))
-	level := parseLevel(os.Getenv("LOG_LEVEL"), env)
-	format := parseFormat(os.Getenv("LOG_FORMAT"))
-	return New(os.Stdout, level, format)
+	pii := PIIMask
+	if envBool("LOG_PII_STRICT") {
+		pii = PIIStrict
+	}
+	return NewWithOptions(os.Stdout, Options{
+		Level:        parseLevel(os.Getenv("LOG_LEVEL"), env),
+		Format:       parseFormat(os.Getenv("LOG_FORMAT")),
+		ModuleLevels: moduleLevelsFromEnv(os.Environ()),
+		Sampling: Sampling{
+			Initial:    envInt("LOG_SAMPLE_INITIAL"),
+			Thereafter: envInt("LOG_SAMPLE_THEREAFTER"),
+		},
+		PII: pii,
+	})
 }
 
 func New(output io.Writer, level slog.Level, format string) Logger {
-	options := &slog.HandlerOptions{
-		Level:       level,
-		ReplaceAttr: replaceAttr,
+	return NewWithOptions(output, Options{Level: level, Format: format})
+}
+
+func NewWithOptions(output io.Writer, options Options) Logger {
+	minLevel := options.Level
+	for _, level := range options.ModuleLevels {
+		if level < minLevel {
+			minLevel = level
+		}
+	}
+	policy := piiPolicy{mode: options.PII}
+	handlerOptions := &slog.HandlerOptions{
+		Level: minLevel,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			return policy.scrubAttr(replaceAttr(groups, attr))
+		},
 	}
 
 	var handler slog.Handler
-	switch normalizeValue(format) {
+	switch normalizeValue(options.Format) {
 	case "json":
-		handler = slog.NewJSONHandler(output, options)
+		handler = slog.NewJSONHandler(output, handlerOptions)
 	default:
-		handler = slog.NewTextHandler(output, options)
+		handler = slog.NewTextHandler(output, handlerOptions)
+	}
+	if len(options.ModuleLevels) > 0 || options.Sampling.Initial > 0 {
+		handler = &filterHandler{
+			inner:   handler,
+			level:   options.Level,
+			modules: options.ModuleLevels,
+			sampler: newSampler(options.Sampling),
+		}
 	}
 
-	return &slogLogger{base: slog.New(handler)}
+	return &slogLogger{base: slog.New(handler), policy: policy}
 }
 
 func (l *slogLogger) Debug(message string, args ...any) {
-	l.base.Debug(message, args...)
+	l.base.Debug(message, l.withFields(args)...)
 }
 
 func (l *slogLogger) Info(message string, args ...any) {
-	l.base.Info(message, args...)
+	l.base.Info(message, l.withFields(args)...)
 }
 
 func (l *slogLogger) Warn(message string, args ...any) {
-	l.base.Warn(message, args...)
+	l.base.Warn(message, l.withFields(args)...)
 }
 
 func (l *slogLogger) Error(message string, args ...any) {
-	l.base.Error(message, args...)
+	l.base.Error(message, l.withFields(args)...)
 }
 
 func (l *slogLogger) Critical(message string, args ...any) {
+	args = l.withFields(args)
 	l.base.Log(context.Background(), LevelCritical, message, args...)
+	l.report(LevelCritical, message, nil, args)
 }
 
 func (l *slogLogger) BusinessError(message string, err error, args ...any) {
@@ -77,7 +146,7 @@ func (l *slogLogger) BusinessError(message string, err error, args ...any) {
 	}
 
 	attrs := append([]any{"err", err}, args...)
-	l.base.Warn(message, attrs...)
+	l.base.Warn(message, l.withFields(attrs)...)
 }
 
 func (l *slogLogger) InternalError(message string, err error, args ...any) {
@@ -86,11 +155,57 @@ func (l *slogLogger) InternalError(message string, err error, args ...any) {
 	}
 
 	attrs := append([]any{"err", err}, args...)
-	l.base.Error(message, attrs...)
+	l.base.Error(message, l.withFields(attrs)...)
+	l.report(slog.LevelError, message, err, l.withFields(args))
 }
 
 func (l *slogLogger) With(args ...any) Logger {
-	return &slogLogger{base: l.base.With(args...)}
+	with := make([]any, 0, len(l.with)+len(args))
+	with = append(append(with, l.with...), args...)
+	return &slogLogger{base: l.base.With(args...), fields: l.fields, with: with, reporter: l.reporter, policy: l.policy}
+}
+
+// withDefaults returns a logger attaching args to lines that do not set the
+// same keys themselves.
+func (l *slogLogger) withDefaults(args []any) *slogLogger {
+	fields := make([]any, 0, len(l.fields)+len(args))
+	fields = append(append(fields, l.fields...), args...)
+	return &slogLogger{base: l.base, fields: fields, with: l.with, reporter: l.reporter, policy: l.policy}
+}
+
+func (l *slogLogger) withFields(args []any) []any {
+	if len(l.fields) == 0 {
+		return args
+	}
+	merged := args
+	for i := 0; i+1 < len(l.fields); i += 2 {
+		key, _ := l.fields[i].(string)
+		if key == "" || hasKey(args, key) {
+			continue
+		}
+		if len(merged) == len(args) {
+			merged = append(make([]any, 0, len(args)+len(l.fields)), args...)
+		}
+		merged = append(merged, key, l.fields[i+1])
+	}
+	return merged
+}
+
+func hasKey(args []any, key string) bool {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i].(type) {
+		case slog.Attr:
+			if arg.Key == key {
+				return true
+			}
+		case string:
+			if arg == key {
+				return true
+			}
+			i++
+		}
+	}
+	return false
 }
 
 func parseLevel(value string, env string) slog.Level {
@@ -116,6 +231,51 @@ func parseLevel(value string, env string) slog.Level {
 	}
 }
 
+func parseModuleLevel(value string) (slog.Level, bool) {
+	switch normalizeValue(value) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	case "critical", "fatal":
+		return LevelCritical, true
+	default:
+		return 0, false
+	}
+}
+
+func moduleLevelsFromEnv(environ []string) map[string]slog.Level {
+	const prefix = "LOG_LEVEL_"
+	levels := make(map[string]slog.Level)
+	for _, entry := range environ {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(key, prefix) || len(key) == len(prefix) {
+			continue
+		}
+		if level, ok := parseModuleLevel(value); ok {
+			levels[normalizeValue(key[len(prefix):])] = level
+		}
+	}
+	return levels
+}
+
+func envInt(key string) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
+
+func envBool(key string) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(key)))
+	return err == nil && value
+}
+
 func parseFormat(value string) string {
 	switch normalizeValue(value) {
 	case "json", "text":
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModuleLevelsOverrideDefault(t *testing.T) {
+	var out bytes.Buffer
+	log := NewWithOptions(&out, Options{
+		Level:        slog.LevelInfo,
+		Format:       "text",
+		ModuleLevels: map[string]slog.Level{"sync": slog.LevelDebug, "rates": slog.LevelWarn},
+	})
+
+	log.Debug("sync: operation applied")
+	log.Debug("expenses: listed")
+	log.Info("rates.refresh: stored")
+	log.Info("app: starting")
+
+	got := out.String()
+	if !strings.Contains(got, "sync: operation applied") {
+		t.Fatalf("expected sync debug line, got %q", got)
+	}
+	if strings.Contains(got, "expenses: listed") || strings.Contains(got, "rates.refresh: stored") {
+		t.Fatalf("expected filtered lines to be dropped, got %q", got)
+	}
+	if !strings.Contains(got, "app: starting") {
+		t.Fatalf("expected default level line, got %q", got)
+	}
+}
+
+func TestSamplingThinsRepeatedInfoLines(t *testing.T) {
+	s := newSampler(Sampling{Initial: 2, Thereafter: 3})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	var passed []int
+	for i := 1; i <= 8; i++ {
+		if s.allow("http: request", now) {
+			passed = append(passed, i)
+		}
+	}
+	if fmt.Sprint(passed) != "[1 2 5 8]" {
+		t.Fatalf("unexpected sampled lines: %v", passed)
+	}
+	if !s.allow("http: request", now.Add(time.Second)) {
+		t.Fatal("expected a new window to reset the count")
+	}
+}
+
+func TestSamplingSkipsWarnings(t *testing.T) {
+	var out bytes.Buffer
+	log := NewWithOptions(&out, Options{
+		Level:    slog.LevelInfo,
+		Format:   "text",
+		Sampling: Sampling{Initial: 1},
+	})
+
+	for i := 0; i < 3; i++ {
+		log.Warn("http: slow request")
+	}
+	if got := strings.Count(out.String(), "http: slow request"); got != 3 {
+		t.Fatalf("expected 3 warnings, got %d", got)
+	}
+}
+
+func TestModuleLevelsFromEnv(t *testing.T) {
+	levels := moduleLevelsFromEnv([]string{"LOG_LEVEL=info", "LOG_LEVEL_SYNC=debug", "LOG_LEVEL_AUTH=bogus", "LOG_LEVEL_=debug"})
+	if len(levels) != 1 || levels["sync"] != slog.LevelDebug {
+		t.Fatalf("unexpected levels: %v", levels)
+	}
+}
+
+func TestContextFieldsAreAttachedOnce(t *testing.T) {
+	var out bytes.Buffer
+	base := New(&out, slog.LevelInfo, "text")
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithContext(ctx, base.With("trace_id", "trace-1"))
+	ctx = WithUserID(ctx, "user-1")
+	ctx = WithFamilyID(ctx, "")
+
+	FromContext(ctx, base).Info("todos: list created", "user_id", "user-1")
+
+	got := out.String()
+	for _, want := range []string{"request_id=req-1", "trace_id=trace-1", "user_id=user-1"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in %q", want, got)
+		}
+	}
+	if strings.Count(got, "user_id=") != 1 {
+		t.Fatalf("expected user_id once, got %q", got)
+	}
+	if strings.Contains(got, "family_id") {
+		t.Fatalf("expected empty family_id to be skipped, got %q", got)
+	}
+}
+
+func TestFromContextFallbackGetsFields(t *testing.T) {
+	var out bytes.Buffer
+	base := New(&out, slog.LevelInfo, "text")
+
+	ctx := WithFamilyID(context.Background(), "family-1")
+	FromContext(ctx, base).Info("sync: batch applied")
+
+	if !strings.Contains(out.String(), "family_id=family-1") {
+		t.Fatalf("expected family_id on fallback logger, got %q", out.String())
+	}
+}
+
+type recordingReporter struct {
+	reports []Report
+}
+
+func (r *recordingReporter) Report(report Report) {
+	r.reports = append(r.reports, report)
+}
+
+func TestReporterReceivesInternalErrorsWithContext(t *testing.T) {
+	reporter := &recordingReporter{}
+	base := WithReporter(New(io.Discard, slog.LevelInfo, "text"), reporter)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	log := FromContext(ctx, base).With("component", "sync")
+	log.BusinessError("sync: conflict", errors.New("conflict"))
+	log.InternalError("sync: apply failed", errors.New("boom"), "batch_id", "batch-1")
+	log.Critical("app: init failed", "err", errors.New("no db"))
+
+	if len(reporter.reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reporter.reports))
+	}
+	report := reporter.reports[0]
+	if report.Message != "sync: apply failed" || report.Err == nil || report.Err.Error() != "boom" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for key, want := range map[string]string{"request_id": "req-1", "component": "sync", "batch_id": "batch-1"} {
+		if report.Fields[key] != want {
+			t.Fatalf("expected %s=%s, got %v", key, want, report.Fields)
+		}
+	}
+	if critical := reporter.reports[1]; critical.Level != LevelCritical || critical.Err == nil || critical.Err.Error() != "no db" {
+		t.Fatalf("unexpected critical report: %+v", critical)
+	}
+}
+
+type testSnapshot struct {
+	ID        string
+	Name      string
+	Email     string
+	AvatarURL *string
+}
+
+func TestPIIMaskTruncatesPersonalData(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out, slog.LevelInfo, "json")
+
+	avatar := "https://cdn.example.com/avatars/jane.png"
+	log.Info("auth: login", "email", "jane.doe@example.com", "user", testSnapshot{ID: "user-1", Name: "Jane Doe", Email: "jane.doe@example.com", AvatarURL: &avatar})
+
+	got := out.String()
+	for _, leaked := range []string{"jane.doe", "Jane Doe", "avatars/jane.png"} {
+		if strings.Contains(got, leaked) {
+			t.Fatalf("expected %q to be scrubbed, got %s", leaked, got)
+		}
+	}
+	for _, kept := range []string{`"email":"j***@example.com"`, `"name":"J***"`, `"avatar_url":"https://cdn.example.com/***"`, `"id":"user-1"`} {
+		if !strings.Contains(got, kept) {
+			t.Fatalf("expected %s in %s", kept, got)
+		}
+	}
+}
+
+func TestPIIStrictHashesAndScrubsFreeText(t *testing.T) {
+	var out bytes.Buffer
+	reporter := &recordingReporter{}
+	log := WithReporter(NewWithOptions(&out, Options{Level: slog.LevelInfo, Format: "json", PII: PIIStrict}), reporter)
+
+	log.InternalError("auth: invite failed", errors.New("no account for jane@example.com"), "email", "jane@example.com", "name", "Jane")
+
+	got := out.String()
+	if strings.Contains(got, "jane") || strings.Contains(got, "Jane") {
+		t.Fatalf("expected personal data to be hashed, got %s", got)
+	}
+	hash := piiHash("jane@example.com")
+	if strings.Count(got, hash) != 2 {
+		t.Fatalf("expected the email hashed alike in the field and the error, got %s", got)
+	}
+	if len(reporter.reports) != 1 || strings.Contains(reporter.reports[0].Err.Error(), "jane") || reporter.reports[0].Fields["email"] != hash {
+		t.Fatalf("expected a scrubbed report, got %+v", reporter.reports)
+	}
+}
@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PIIMode selects how personal data is scrubbed from log lines and reports.
+// Attributes are classified by key: "email" and keys ending in "_email",
+// names such as "name" or "display_name", and keys containing "avatar".
+// Structs with such fields, like user snapshots, are logged field by field
+// and scrubbed the same way.
+type PIIMode int
+
+const (
+	// PIIMask truncates personal data while keeping it recognizable when
+	// debugging: "j***@example.com", "J***" and the avatar URL's host.
+	PIIMask PIIMode = iota
+	// PIIStrict replaces personal data with a short stable hash, so lines
+	// can still be correlated, and hashes email addresses found in any
+	// string, messages and error texts included. Meant for regulated
+	// deployments.
+	PIIStrict
+)
+
+type piiKind int
+
+const (
+	piiNone piiKind = iota
+	piiEmail
+	piiName
+	piiAvatar
+)
+
+const maxScrubDepth = 3
+
+var (
+	nameKeys = map[string]bool{
+		"name":         true,
+		"user_name":    true,
+		"member_name":  true,
+		"display_name": true,
+		"full_name":    true,
+		"first_name":   true,
+		"last_name":    true,
+	}
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+type piiPolicy struct {
+	mode PIIMode
+}
+
+func piiKindOf(key string) piiKind {
+	key = strings.ToLower(key)
+	switch {
+	case key == "email" || strings.HasSuffix(key, "_email"):
+		return piiEmail
+	case nameKeys[key]:
+		return piiName
+	case strings.Contains(key, "avatar"):
+		return piiAvatar
+	default:
+		return piiNone
+	}
+}
+
+// scrubAttr is applied to every attribute through the handler's
+// ReplaceAttr, including those attached with With.
+func (p piiPolicy) scrubAttr(attr slog.Attr) slog.Attr {
+	switch attr.Key {
+	case slog.TimeKey, slog.LevelKey, slog.SourceKey:
+		return attr
+	}
+	attr.Value = p.scrubValue(attr.Key, attr.Value, 0)
+	return attr
+}
+
+func (p piiPolicy) scrubValue(key string, value slog.Value, depth int) slog.Value {
+	value = value.Resolve()
+	kind := piiKindOf(key)
+
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.StringValue(p.scrubString(kind, value.String()))
+	case slog.KindGroup:
+		attrs := value.Group()
+		scrubbed := make([]slog.Attr, len(attrs))
+		for i, attr := range attrs {
+			scrubbed[i] = slog.Attr{Key: attr.Key, Value: p.scrubValue(attr.Key, attr.Value, depth+1)}
+		}
+		return slog.GroupValue(scrubbed...)
+	case slog.KindAny:
+	default:
+		return value
+	}
+
+	switch v := value.Any().(type) {
+	case error:
+		if p.mode == PIIStrict || kind != piiNone {
+			return slog.StringValue(p.scrubString(kind, v.Error()))
+		}
+		return value
+	case *string:
+		if v != nil && kind != piiNone {
+			return slog.StringValue(p.scrubString(kind, *v))
+		}
+		return value
+	}
+
+	if group, ok := p.scrubStruct(value.Any(), depth); ok {
+		return group
+	}
+	if kind != piiNone {
+		return slog.StringValue(p.scrubString(kind, fmt.Sprint(value.Any())))
+	}
+	return value
+}
+
+// scrubStruct logs a struct carrying personal data as a group of its
+// exported fields. Other values are left to the handler.
+func (p piiPolicy) scrubStruct(value any, depth int) (slog.Value, bool) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return slog.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || rv.Type() == timeType || depth >= maxScrubDepth || !hasPIIFields(rv.Type()) {
+		return slog.Value{}, false
+	}
+
+	attrs := make([]slog.Attr, 0, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := snakeCase(field.Name)
+		attrs = append(attrs, slog.Attr{Key: key, Value: p.scrubValue(key, slog.AnyValue(rv.Field(i).Interface()), depth+1)})
+	}
+	return slog.GroupValue(attrs...), true
+}
+
+func hasPIIFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && piiKindOf(snakeCase(field.Name)) != piiNone {
+			return true
+		}
+	}
+	return false
+}
+
+func (p piiPolicy) scrubString(kind piiKind, value string) string {
+	if value == "" {
+		return value
+	}
+	if p.mode == PIIStrict {
+		if kind != piiNone {
+			return piiHash(value)
+		}
+		return emailPattern.ReplaceAllStringFunc(value, piiHash)
+	}
+
+	switch kind {
+	case piiEmail:
+		local, domain, ok := strings.Cut(value, "@")
+		if !ok {
+			return truncate(value)
+		}
+		return truncate(local) + "@" + domain
+	case piiName:
+		return truncate(value)
+	case piiAvatar:
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Host == "" {
+			return "***"
+		}
+		return parsed.Scheme + "://" + parsed.Host + "/***"
+	default:
+		return value
+	}
+}
+
+// scrubReportField applies the policy to a field handed to a Reporter, so
+// error trackers receive the same scrubbed values as the log output.
+func (p piiPolicy) scrubReportField(key string, value any) any {
+	return reportValue(p.scrubValue(key, slog.AnyValue(value), 0))
+}
+
+// scrubError hides email addresses in err's text from reporters in strict
+// mode; errors.Is and errors.As still see err.
+func (p piiPolicy) scrubError(err error) error {
+	if err == nil || p.mode != PIIStrict {
+		return err
+	}
+	text := p.scrubString(piiNone, err.Error())
+	if text == err.Error() {
+		return err
+	}
+	return scrubbedError{text: text, err: err}
+}
+
+type scrubbedError struct {
+	text string
+	err  error
+}
+
+func (e scrubbedError) Error() string { return e.text }
+func (e scrubbedError) Unwrap() error { return e.err }
+
+func reportValue(value slog.Value) any {
+	if value.Kind() != slog.KindGroup {
+		return value.Any()
+	}
+	fields := make(map[string]any, len(value.Group()))
+	for _, attr := range value.Group() {
+		fields[attr.Key] = reportValue(attr.Value)
+	}
+	return fields
+}
+
+func truncate(value string) string {
+	first, _ := utf8.DecodeRuneInString(value)
+	return string(first) + "***"
+}
+
+func piiHash(value string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a word at a lower-to-upper change and before the last
+			// capital of an acronym, so AvatarURL becomes avatar_url.
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package logger
+
+import "log/slog"
+
+// Report is an internal error or critical line handed to a Reporter.
+type Report struct {
+	Level   slog.Level
+	Message string
+	// Err is nil for Critical lines that do not pass an "err" argument.
+	Err error
+	// Fields holds the line's arguments and those of With and WithFields,
+	// such as request_id, user_id and a recovered panic's stack.
+	Fields map[string]any
+}
+
+// Reporter forwards internal errors to an error tracker such as Sentry.
+// Report is called synchronously from InternalError and Critical, so it
+// must not block on the network.
+type Reporter interface {
+	Report(report Report)
+}
+
+// WithReporter returns log calling reporter for every InternalError and
+// Critical line, including those of loggers derived from it. Loggers not
+// built by this package are returned unchanged.
+func WithReporter(log Logger, reporter Reporter) Logger {
+	base, ok := log.(*slogLogger)
+	if !ok || reporter == nil {
+		return log
+	}
+	clone := *base
+	clone.reporter = reporter
+	return &clone
+}
+
+func (l *slogLogger) report(level slog.Level, message string, err error, args []any) {
+	if l.reporter == nil {
+		return
+	}
+	fields := make(map[string]any, (len(l.with)+len(args))/2)
+	addReportFields(fields, l.with)
+	addReportFields(fields, args)
+	if err == nil {
+		err, _ = fields["err"].(error)
+	}
+	delete(fields, "err")
+	for key, value := range fields {
+		fields[key] = l.policy.scrubReportField(key, value)
+	}
+	message = l.policy.scrubString(piiNone, message)
+	l.reporter.Report(Report{Level: level, Message: message, Err: l.policy.scrubError(err), Fields: fields})
+}
+
+func addReportFields(fields map[string]any, args []any) {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i].(type) {
+		case slog.Attr:
+			fields[arg.Key] = arg.Value.Any()
+		case string:
+			if i+1 < len(args) {
+				fields[arg] = args[i+1]
+			}
+			i++
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package money knows how many decimals each currency has, so amounts are
+// validated and rounded the same way wherever they enter the system.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// StoredDecimals is the scale of the amount columns. Currencies with more
+// minor units (BHD, KWD, ...) are kept to it.
+const StoredDecimals = 2
+
+// precisionTolerance absorbs float error in scaled amounts up to the
+// numeric(12,2) range, about 1e-4 at 1e12.
+const precisionTolerance = 1e-3
+
+// ErrInvalidPrecision is matched by every *PrecisionError.
+var ErrInvalidPrecision = errors.New("invalid amount precision")
+
+// PrecisionError reports an amount with more decimals than its currency
+// allows.
+type PrecisionError struct {
+	Currency string
+	Decimals int
+}
+
+func (e *PrecisionError) Error() string {
+	if e.Decimals == 0 {
+		return fmt.Sprintf("%s amounts must be whole numbers", e.Currency)
+	}
+	return fmt.Sprintf("%s amounts must have at most %d decimals", e.Currency, e.Decimals)
+}
+
+func (e *PrecisionError) Unwrap() error { return ErrInvalidPrecision }
+
+// minorUnits lists the ISO 4217 currencies whose minor unit is not 1/100.
+// Every other code has two decimals.
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// MinorUnits returns the ISO 4217 number of decimals of a currency code.
+func MinorUnits(currency string) int {
+	if units, ok := minorUnits[strings.ToUpper(strings.TrimSpace(currency))]; ok {
+		return units
+	}
+	return 2
+}
+
+// Decimals returns how many decimals amounts in currency may be stored with.
+func Decimals(currency string) int {
+	units := MinorUnits(currency)
+	if units > StoredDecimals {
+		return StoredDecimals
+	}
+	return units
+}
+
+// Round rounds amount half away from zero to the decimals of currency.
+func Round(amount float64, currency string) float64 {
+	scale := math.Pow10(Decimals(currency))
+	return math.Round(amount*scale) / scale
+}
+
+// CheckPrecision returns a *PrecisionError when amount has more decimals
+// than currency allows. Binary float noise, such as 0.1+0.2, is tolerated.
+func CheckPrecision(amount float64, currency string) error {
+	decimals := Decimals(currency)
+	scaled := amount * math.Pow10(decimals)
+	if math.Abs(scaled-math.Round(scaled)) > precisionTolerance {
+		return &PrecisionError{Currency: strings.ToUpper(strings.TrimSpace(currency)), Decimals: decimals}
+	}
+	return nil
+}
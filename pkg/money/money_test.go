@@ -0,0 +1,70 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecimals(t *testing.T) {
+	cases := map[string]int{"JPY": 0, "jpy": 0, "USD": 2, "BYN": 2, "KWD": 2, "XYZ": 2}
+	for currency, want := range cases {
+		if got := Decimals(currency); got != want {
+			t.Fatalf("Decimals(%q) = %d, want %d", currency, got, want)
+		}
+	}
+	if got := MinorUnits("KWD"); got != 3 {
+		t.Fatalf("MinorUnits(KWD) = %d, want 3", got)
+	}
+}
+
+func TestRound(t *testing.T) {
+	if got := Round(12.345, "EUR"); got != 12.35 {
+		t.Fatalf("Round(12.345, EUR) = %v, want 12.35", got)
+	}
+	if got := Round(1234.5, "JPY"); got != 1235 {
+		t.Fatalf("Round(1234.5, JPY) = %v, want 1235", got)
+	}
+}
+
+func TestCheckPrecision(t *testing.T) {
+	for _, valid := range []struct {
+		amount   float64
+		currency string
+	}{
+		{12.34, "EUR"},
+		{0.1 + 0.2, "EUR"},
+		{99999999.99, "USD"},
+		{1500, "JPY"},
+	} {
+		if err := CheckPrecision(valid.amount, valid.currency); err != nil {
+			t.Fatalf("CheckPrecision(%v, %s) = %v, want nil", valid.amount, valid.currency, err)
+		}
+	}
+
+	err := CheckPrecision(12.345, "EUR")
+	var precisionErr *PrecisionError
+	if !errors.As(err, &precisionErr) || precisionErr.Decimals != 2 || !errors.Is(err, ErrInvalidPrecision) {
+		t.Fatalf("CheckPrecision(12.345, EUR) = %v, want a precision error", err)
+	}
+	if err := CheckPrecision(10.5, "jpy"); err == nil || err.Error() != "JPY amounts must be whole numbers" {
+		t.Fatalf("CheckPrecision(10.5, jpy) = %v", err)
+	}
+}
+
+func TestIsCurrency(t *testing.T) {
+	for _, code := range []string{"USD", "byn", " eur ", "JPY", "XOF"} {
+		if !IsCurrency(code) {
+			t.Fatalf("IsCurrency(%q) = false, want true", code)
+		}
+	}
+	for _, code := range []string{"", "XYZ", "ABC", "US", "XAU", "EURO"} {
+		if IsCurrency(code) {
+			t.Fatalf("IsCurrency(%q) = true, want false", code)
+		}
+	}
+	for code := range minorUnits {
+		if !IsCurrency(code) {
+			t.Fatalf("minor units listed for unknown currency %s", code)
+		}
+	}
+}
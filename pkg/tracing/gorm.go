@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// InstrumentGORM registers callbacks that wrap GORM statements in client
+// spans named after the operation. Statements outside of a trace, such as
+// migrations and worker polls, are not traced.
+func InstrumentGORM(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startGORMSpan("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endGORMSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startGORMSpan("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endGORMSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startGORMSpan("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endGORMSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startGORMSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endGORMSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startGORMSpan("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endGORMSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startGORMSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endGORMSpan),
+	)
+}
+
+func startGORMSpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if tx.Statement == nil || tx.Statement.Context == nil {
+			return
+		}
+		if !trace.SpanContextFromContext(tx.Statement.Context).IsValid() {
+			return
+		}
+		ctx, span := Start(tx.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system.name", "postgresql"),
+				attribute.String("db.operation.name", operation),
+			),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endGORMSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if table := tx.Statement.Table; table != "" {
+		span.SetAttributes(attribute.String("db.collection.name", table))
+	}
+	// The statement keeps placeholders; bound values never reach the span.
+	if query := tx.Statement.SQL.String(); query != "" {
+		span.SetAttributes(attribute.String("db.query.text", query))
+	}
+	span.SetAttributes(attribute.Int64("db.response.returned_rows", tx.Statement.RowsAffected))
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		RecordError(span, tx.Error)
+	}
+}
@@ -0,0 +1,121 @@
+// Package validate holds value checks shared by the API and by tools that
+// need to accept exactly the same input, such as category colors and emoji.
+package validate
+
+import (
+	"regexp"
+	"strings"
+)
+
+var hexColorRegex = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// HexColor trims and lowercases value and reports whether it is a #rrggbb
+// color.
+func HexColor(value string) (string, bool) {
+	color := strings.ToLower(strings.TrimSpace(value))
+	return color, hexColorRegex.MatchString(color)
+}
+
+// Emoji trims value and reports whether it is exactly one emoji: a single
+// pictograph with optional variation selector and skin tone, a ZWJ sequence
+// of those, a flag or a keycap.
+func Emoji(value string) (string, bool) {
+	emoji := strings.TrimSpace(value)
+	return emoji, emoji != "" && isSingleEmojiGrapheme(emoji)
+}
+
+const (
+	variationSelector16    rune = 0xFE0F
+	zeroWidthJoiner        rune = 0x200D
+	combiningEnclosingMark rune = 0x20E3
+)
+
+func isSingleEmojiGrapheme(value string) bool {
+	runes := []rune(value)
+	if len(runes) == 0 {
+		return false
+	}
+
+	if isKeycapEmoji(runes) {
+		return true
+	}
+	if len(runes) == 2 && isRegionalIndicator(runes[0]) && isRegionalIndicator(runes[1]) {
+		return true
+	}
+
+	index, ok := consumeEmojiComponent(runes, 0)
+	if !ok {
+		return false
+	}
+	for index < len(runes) {
+		if runes[index] != zeroWidthJoiner {
+			return false
+		}
+		index++
+
+		next, ok := consumeEmojiComponent(runes, index)
+		if !ok {
+			return false
+		}
+		index = next
+	}
+
+	return true
+}
+
+func consumeEmojiComponent(runes []rune, index int) (int, bool) {
+	if index >= len(runes) {
+		return index, false
+	}
+	if !isEmojiBase(runes[index]) {
+		return index, false
+	}
+	index++
+
+	if index < len(runes) && runes[index] == variationSelector16 {
+		index++
+	}
+	if index < len(runes) && isEmojiModifier(runes[index]) {
+		index++
+	}
+
+	return index, true
+}
+
+func isEmojiBase(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	case r >= 0x2600 && r <= 0x27BF:
+		return true
+	case r >= 0x2300 && r <= 0x23FF:
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	case r == 0x00A9 || r == 0x00AE || r == 0x3030 || r == 0x303D || r == 0x3297 || r == 0x3299:
+		return true
+	}
+	return false
+}
+
+func isEmojiModifier(r rune) bool {
+	return r >= 0x1F3FB && r <= 0x1F3FF
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isKeycapEmoji(runes []rune) bool {
+	if len(runes) == 2 && isKeycapBase(runes[0]) && runes[1] == combiningEnclosingMark {
+		return true
+	}
+	if len(runes) == 3 && isKeycapBase(runes[0]) && runes[1] == variationSelector16 && runes[2] == combiningEnclosingMark {
+		return true
+	}
+	return false
+}
+
+func isKeycapBase(r rune) bool {
+	return r == '#' || r == '*' || (r >= '0' && r <= '9')
+}
@@ -0,0 +1,40 @@
+package validate
+
+import "testing"
+
+func TestHexColor(t *testing.T) {
+	color, ok := HexColor(" #A1B2C3 ")
+	if !ok || color != "#a1b2c3" {
+		t.Fatalf("HexColor() = %q, %v; want #a1b2c3, true", color, ok)
+	}
+	for _, value := range []string{"", "a1b2c3", "#a1b2c", "#a1b2c3d4", "#g1b2c3", "red"} {
+		if _, ok := HexColor(value); ok {
+			t.Fatalf("HexColor(%q) accepted, want rejected", value)
+		}
+	}
+}
+
+func TestEmoji(t *testing.T) {
+	valid := []string{
+		"🙂",
+		" 🍕 ",
+		"❤️",
+		"👍🏽",
+		"👨‍👩‍👧‍👦",
+		"🇩🇪",
+		"1️⃣",
+		"#⃣",
+	}
+	for _, value := range valid {
+		if _, ok := Emoji(value); !ok {
+			t.Fatalf("Emoji(%q) rejected, want accepted", value)
+		}
+	}
+
+	invalid := []string{"", "  ", "a", "🙂🙂", "🙂a", "‍🙂"}
+	for _, value := range invalid {
+		if _, ok := Emoji(value); ok {
+			t.Fatalf("Emoji(%q) accepted, want rejected", value)
+		}
+	}
+}